@@ -374,4 +374,5 @@ type TokenCreateRequest struct {
 	Renewable       *bool             `json:"renewable,omitempty"`
 	Type            string            `json:"type"`
 	EntityAlias     string            `json:"entity_alias"`
+	BindClientCert  bool              `json:"bind_client_cert,omitempty"`
 }
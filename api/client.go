@@ -134,6 +134,20 @@ type Config struct {
 	// The CheckRetry function to use; a default is used if not provided
 	CheckRetry retryablehttp.CheckRetry
 
+	// RetryPolicy, if set, takes precedence over CheckRetry and decides
+	// which failed requests are retried, per status code, within an
+	// optional time budget per request.
+	RetryPolicy *RetryPolicy
+
+	// CircuitBreaker, if set, makes the client fail fast with
+	// ErrCircuitOpen after a number of consecutive failed requests, until
+	// the server recovers.
+	CircuitBreaker *CircuitBreakerConfig
+
+	// ResponseCache, if set, enables caching of selected read responses on
+	// the client. See ResponseCacheConfig.
+	ResponseCache *ResponseCacheConfig
+
 	// Logger is the leveled logger to provide to the retryable HTTP client.
 	Logger retryablehttp.LeveledLogger
 
@@ -560,6 +574,8 @@ type Client struct {
 	policyOverride     bool
 	requestCallbacks   []RequestCallback
 	responseCallbacks  []ResponseCallback
+	circuitBreaker     *circuitBreaker
+	responseCache      *responseCache
 }
 
 // NewClient returns a new client for the given configuration.
@@ -612,9 +628,11 @@ func NewClient(c *Config) (*Client, error) {
 	}
 
 	client := &Client{
-		addr:    u,
-		config:  c,
-		headers: make(http.Header),
+		addr:           u,
+		config:         c,
+		headers:        make(http.Header),
+		circuitBreaker: newCircuitBreaker(c.CircuitBreaker),
+		responseCache:  newResponseCache(c.ResponseCache),
 	}
 
 	// Add the VaultRequest SSRF protection header
@@ -644,6 +662,9 @@ func (c *Client) CloneConfig() *Config {
 	newConfig.Timeout = c.config.Timeout
 	newConfig.Backoff = c.config.Backoff
 	newConfig.CheckRetry = c.config.CheckRetry
+	newConfig.RetryPolicy = c.config.RetryPolicy
+	newConfig.CircuitBreaker = c.config.CircuitBreaker
+	newConfig.ResponseCache = c.config.ResponseCache
 	newConfig.Logger = c.config.Logger
 	newConfig.Limiter = c.config.Limiter
 	newConfig.SRVLookup = c.config.SRVLookup
@@ -843,6 +864,72 @@ func (c *Client) CheckRetry() retryablehttp.CheckRetry {
 	return c.config.CheckRetry
 }
 
+// SetRetryPolicy sets the RetryPolicy to be used for future requests. A nil
+// policy restores the use of the CheckRetry function.
+func (c *Client) SetRetryPolicy(policy *RetryPolicy) {
+	c.modifyLock.RLock()
+	defer c.modifyLock.RUnlock()
+	c.config.modifyLock.Lock()
+	defer c.config.modifyLock.Unlock()
+
+	c.config.RetryPolicy = policy
+}
+
+func (c *Client) RetryPolicy() *RetryPolicy {
+	c.modifyLock.RLock()
+	defer c.modifyLock.RUnlock()
+	c.config.modifyLock.RLock()
+	defer c.config.modifyLock.RUnlock()
+
+	return c.config.RetryPolicy
+}
+
+// SetCircuitBreaker configures the circuit breaker of this client, resetting
+// its state. A nil config disables circuit breaking.
+func (c *Client) SetCircuitBreaker(config *CircuitBreakerConfig) {
+	c.modifyLock.Lock()
+	defer c.modifyLock.Unlock()
+	c.config.modifyLock.Lock()
+	defer c.config.modifyLock.Unlock()
+
+	c.config.CircuitBreaker = config
+	c.circuitBreaker = newCircuitBreaker(config)
+}
+
+// CircuitState returns the current state of the client's circuit breaker.
+// It is always CircuitClosed if no circuit breaker is configured.
+func (c *Client) CircuitState() CircuitState {
+	c.modifyLock.RLock()
+	breaker := c.circuitBreaker
+	c.modifyLock.RUnlock()
+
+	if breaker == nil {
+		return CircuitClosed
+	}
+	return breaker.State()
+}
+
+// SetResponseCache configures the response cache of this client, dropping
+// all previously cached responses. A nil config disables caching.
+func (c *Client) SetResponseCache(config *ResponseCacheConfig) {
+	c.modifyLock.Lock()
+	defer c.modifyLock.Unlock()
+	c.config.modifyLock.Lock()
+	defer c.config.modifyLock.Unlock()
+
+	c.config.ResponseCache = config
+	c.responseCache = newResponseCache(config)
+}
+
+// PurgeResponseCache drops all responses cached by this client.
+func (c *Client) PurgeResponseCache() {
+	c.modifyLock.RLock()
+	cache := c.responseCache
+	c.modifyLock.RUnlock()
+
+	cache.purge()
+}
+
 // SetClientTimeout sets the client request timeout
 func (c *Client) SetClientTimeout(timeout time.Duration) {
 	c.modifyLock.RLock()
@@ -1121,20 +1208,23 @@ func (c *Client) clone(cloneHeaders bool) (*Client, error) {
 	defer config.modifyLock.RUnlock()
 
 	newConfig := &Config{
-		Address:      config.Address,
-		HttpClient:   config.HttpClient,
-		MinRetryWait: config.MinRetryWait,
-		MaxRetryWait: config.MaxRetryWait,
-		MaxRetries:   config.MaxRetries,
-		Timeout:      config.Timeout,
-		Backoff:      config.Backoff,
-		CheckRetry:   config.CheckRetry,
-		Logger:       config.Logger,
-		Limiter:      config.Limiter,
-		AgentAddress: config.AgentAddress,
-		SRVLookup:    config.SRVLookup,
-		CloneHeaders: config.CloneHeaders,
-		CloneToken:   config.CloneToken,
+		Address:        config.Address,
+		HttpClient:     config.HttpClient,
+		MinRetryWait:   config.MinRetryWait,
+		MaxRetryWait:   config.MaxRetryWait,
+		MaxRetries:     config.MaxRetries,
+		Timeout:        config.Timeout,
+		Backoff:        config.Backoff,
+		CheckRetry:     config.CheckRetry,
+		RetryPolicy:    config.RetryPolicy,
+		CircuitBreaker: config.CircuitBreaker,
+		ResponseCache:  config.ResponseCache,
+		Logger:         config.Logger,
+		Limiter:        config.Limiter,
+		AgentAddress:   config.AgentAddress,
+		SRVLookup:      config.SRVLookup,
+		CloneHeaders:   config.CloneHeaders,
+		CloneToken:     config.CloneToken,
 	}
 	client, err := NewClient(newConfig)
 	if err != nil {
@@ -1258,6 +1348,7 @@ func (c *Client) rawRequestWithContext(ctx context.Context, r *Request) (*Respon
 	maxRetryWait := c.config.MaxRetryWait
 	maxRetries := c.config.MaxRetries
 	checkRetry := c.config.CheckRetry
+	retryPolicy := c.config.RetryPolicy
 	backoff := c.config.Backoff
 	httpClient := c.config.HttpClient
 	ns := c.headers.Get(NamespaceHeaderName)
@@ -1267,6 +1358,8 @@ func (c *Client) rawRequestWithContext(ctx context.Context, r *Request) (*Respon
 	disableRedirects := c.config.DisableRedirects
 	c.config.modifyLock.RUnlock()
 
+	breaker := c.circuitBreaker
+	cache := c.responseCache
 	c.modifyLock.RUnlock()
 
 	// ensure that the most current namespace setting is used at the time of the call
@@ -1291,6 +1384,15 @@ func (c *Client) rawRequestWithContext(ctx context.Context, r *Request) (*Respon
 		return nil, err
 	}
 
+	cacheKey, cacheRule := cache.key(r, token, ns)
+	if cached := cache.get(cacheKey); cached != nil {
+		return cached, nil
+	}
+	if r.Method != http.MethodGet {
+		cache.invalidate(requestPath(r))
+	}
+
+	start := time.Now()
 	redirectCount := 0
 START:
 	req, err := r.toRetryableHTTP()
@@ -1332,6 +1434,14 @@ START:
 		checkRetry = DefaultRetryPolicy
 	}
 
+	if retryPolicy != nil {
+		checkRetry = retryPolicy.checkRetry(start)
+	}
+
+	if err := breaker.allow(); err != nil {
+		return nil, err
+	}
+
 	client := &retryablehttp.Client{
 		HTTPClient:   httpClient,
 		RetryWaitMin: minRetryWait,
@@ -1348,6 +1458,7 @@ START:
 	if resp != nil {
 		result = &Response{Response: resp}
 	}
+	breaker.record(result, err)
 	if err != nil {
 		if strings.Contains(err.Error(), "tls: oversized") {
 			err = errwrap.Wrapf("{{err}}\n\n"+TLSErrorString, err)
@@ -1390,6 +1501,12 @@ START:
 		return result, err
 	}
 
+	if cacheRule >= 0 {
+		if err := cache.put(cacheKey, cacheRule, result); err != nil {
+			return result, err
+		}
+	}
+
 	return result, nil
 }
 
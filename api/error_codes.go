@@ -0,0 +1,39 @@
+package api
+
+import "errors"
+
+// Error codes returned by the server in the error_code field of error
+// responses, and available as ResponseError.ErrorCode. Codes are stable:
+// clients can branch on them instead of matching error messages.
+const (
+	ErrorCodeInternal                     = "internal_error"
+	ErrorCodeInvalidRequest               = "invalid_request"
+	ErrorCodePermissionDenied             = "permission_denied"
+	ErrorCodeInvalidCredentials           = "invalid_credentials"
+	ErrorCodeNotFound                     = "not_found"
+	ErrorCodeUnsupportedOperation         = "unsupported_operation"
+	ErrorCodeUnsupportedPath              = "unsupported_path"
+	ErrorCodeInvalidWrappingToken         = "invalid_wrapping_token"
+	ErrorCodeUpstreamRateLimited          = "upstream_rate_limited"
+	ErrorCodeRateLimitQuotaExceeded       = "rate_limit_quota_exceeded"
+	ErrorCodeLeaseCountQuotaExceeded      = "lease_count_quota_exceeded"
+	ErrorCodeConcurrencyLimitExceeded     = "concurrency_limit_exceeded"
+	ErrorCodePathFunctionalityRemoved     = "path_functionality_removed"
+	ErrorCodeDeleteConfirmationRequired   = "delete_confirmation_required"
+	ErrorCodeBannerAcknowledgmentRequired = "banner_acknowledgment_required"
+	ErrorCodeRequestTooLarge              = "request_too_large"
+	ErrorCodeRequestTimeout               = "request_timeout"
+	ErrorCodeSealed                       = "sealed"
+	ErrorCodeAPILocked                    = "api_locked"
+	ErrorCodeUnavailable                  = "unavailable"
+)
+
+// ErrorCode returns the error code of err if it is, or wraps, a
+// ResponseError, and an empty string otherwise.
+func ErrorCode(err error) string {
+	var respErr *ResponseError
+	if errors.As(err, &respErr) {
+		return respErr.ErrorCode
+	}
+	return ""
+}
@@ -39,33 +39,65 @@ const (
 // path matches that path or not (useful specifically for the paths that
 // contain templated fields.)
 var sudoPaths = map[string]*regexp.Regexp{
-	"/auth/token/accessors":                         regexp.MustCompile(`^/auth/token/accessors/?$`),
-	"/pki/root":                                     regexp.MustCompile(`^/pki/root$`),
-	"/pki/root/sign-self-issued":                    regexp.MustCompile(`^/pki/root/sign-self-issued$`),
-	"/sys/audit":                                    regexp.MustCompile(`^/sys/audit$`),
-	"/sys/audit/{path}":                             regexp.MustCompile(`^/sys/audit/.+$`),
-	"/sys/auth/{path}":                              regexp.MustCompile(`^/sys/auth/.+$`),
-	"/sys/auth/{path}/tune":                         regexp.MustCompile(`^/sys/auth/.+/tune$`),
-	"/sys/config/auditing/request-headers":          regexp.MustCompile(`^/sys/config/auditing/request-headers$`),
-	"/sys/config/auditing/request-headers/{header}": regexp.MustCompile(`^/sys/config/auditing/request-headers/.+$`),
-	"/sys/config/cors":                              regexp.MustCompile(`^/sys/config/cors$`),
-	"/sys/config/ui/headers":                        regexp.MustCompile(`^/sys/config/ui/headers/?$`),
-	"/sys/config/ui/headers/{header}":               regexp.MustCompile(`^/sys/config/ui/headers/.+$`),
-	"/sys/leases":                                   regexp.MustCompile(`^/sys/leases$`),
-	"/sys/leases/lookup/":                           regexp.MustCompile(`^/sys/leases/lookup/?$`),
-	"/sys/leases/lookup/{prefix}":                   regexp.MustCompile(`^/sys/leases/lookup/.+$`),
-	"/sys/leases/revoke-force/{prefix}":             regexp.MustCompile(`^/sys/leases/revoke-force/.+$`),
-	"/sys/leases/revoke-prefix/{prefix}":            regexp.MustCompile(`^/sys/leases/revoke-prefix/.+$`),
-	"/sys/plugins/catalog/{name}":                   regexp.MustCompile(`^/sys/plugins/catalog/[^/]+$`),
-	"/sys/plugins/catalog/{type}":                   regexp.MustCompile(`^/sys/plugins/catalog/[\w-]+$`),
-	"/sys/plugins/catalog/{type}/{name}":            regexp.MustCompile(`^/sys/plugins/catalog/[\w-]+/[^/]+$`),
-	"/sys/raw":                                      regexp.MustCompile(`^/sys/raw$`),
-	"/sys/raw/{path}":                               regexp.MustCompile(`^/sys/raw/.+$`),
-	"/sys/remount":                                  regexp.MustCompile(`^/sys/remount$`),
-	"/sys/revoke-force/{prefix}":                    regexp.MustCompile(`^/sys/revoke-force/.+$`),
-	"/sys/revoke-prefix/{prefix}":                   regexp.MustCompile(`^/sys/revoke-prefix/.+$`),
-	"/sys/rotate":                                   regexp.MustCompile(`^/sys/rotate$`),
-	"/sys/internal/inspect/router/{tag}":            regexp.MustCompile(`^/sys/internal/inspect/router/.+$`),
+	"/auth/token/accessors":                             regexp.MustCompile(`^/auth/token/accessors/?$`),
+	"/pki/root":                                         regexp.MustCompile(`^/pki/root$`),
+	"/pki/root/sign-self-issued":                        regexp.MustCompile(`^/pki/root/sign-self-issued$`),
+	"/sys/audit":                                        regexp.MustCompile(`^/sys/audit$`),
+	"/sys/audit/{path}":                                 regexp.MustCompile(`^/sys/audit/.+$`),
+	"/sys/auth/{path}":                                  regexp.MustCompile(`^/sys/auth/.+$`),
+	"/sys/auth/{path}/rollback":                         regexp.MustCompile(`^/sys/auth/.+/rollback$`),
+	"/sys/auth/{path}/tune":                             regexp.MustCompile(`^/sys/auth/.+/tune$`),
+	"/sys/auth/{path}/undelete":                         regexp.MustCompile(`^/sys/auth/.+/undelete$`),
+	"/sys/auth/{path}/versions":                         regexp.MustCompile(`^/sys/auth/.+/versions/?$`),
+	"/sys/auth/{path}/versions/{version}":               regexp.MustCompile(`^/sys/auth/.+/versions/\d+$`),
+	"/sys/config/auditing/request-headers":              regexp.MustCompile(`^/sys/config/auditing/request-headers$`),
+	"/sys/config/auditing/request-headers/{header}":     regexp.MustCompile(`^/sys/config/auditing/request-headers/.+$`),
+	"/sys/config/backup-encryption":                     regexp.MustCompile(`^/sys/config/backup-encryption$`),
+	"/sys/config/cache":                                 regexp.MustCompile(`^/sys/config/cache$`),
+	"/sys/config/cors":                                  regexp.MustCompile(`^/sys/config/cors$`),
+	"/sys/config/export":                                regexp.MustCompile(`^/sys/config/export$`),
+	"/sys/config/import":                                regexp.MustCompile(`^/sys/config/import$`),
+	"/sys/config/reload/{subsystem}":                    regexp.MustCompile(`^/sys/config/reload/.+$`),
+	"/sys/config/state/apply":                           regexp.MustCompile(`^/sys/config/state/apply$`),
+	"/sys/config/ttl-policies":                          regexp.MustCompile(`^/sys/config/ttl-policies/?$`),
+	"/sys/config/ttl-policies/{name}":                   regexp.MustCompile(`^/sys/config/ttl-policies/.+$`),
+	"/sys/config/ui/banner-acknowledgments/{entity_id}": regexp.MustCompile(`^/sys/config/ui/banner-acknowledgments/.+$`),
+	"/sys/config/ui/banners":                            regexp.MustCompile(`^/sys/config/ui/banners/?$`),
+	"/sys/config/ui/banners/{name}":                     regexp.MustCompile(`^/sys/config/ui/banners/.+$`),
+	"/sys/config/ui/headers":                            regexp.MustCompile(`^/sys/config/ui/headers/?$`),
+	"/sys/config/ui/headers/{header}":                   regexp.MustCompile(`^/sys/config/ui/headers/.+$`),
+	"/sys/federation/clusters":                          regexp.MustCompile(`^/sys/federation/clusters/?$`),
+	"/sys/federation/clusters/{cluster_id}":             regexp.MustCompile(`^/sys/federation/clusters/.+$`),
+	"/sys/federation/config":                            regexp.MustCompile(`^/sys/federation/config$`),
+	"/sys/federation/tokens":                            regexp.MustCompile(`^/sys/federation/tokens/?$`),
+	"/sys/federation/tokens/{name}":                     regexp.MustCompile(`^/sys/federation/tokens/.+$`),
+	"/sys/generate-root/history/":                       regexp.MustCompile(`^/sys/generate-root/history/?$`),
+	"/sys/import":                                       regexp.MustCompile(`^/sys/import/?$`),
+	"/sys/import/{id}":                                  regexp.MustCompile(`^/sys/import/.+$`),
+	"/sys/leases":                                       regexp.MustCompile(`^/sys/leases$`),
+	"/sys/leases/irrevocable":                           regexp.MustCompile(`^/sys/leases/irrevocable$`),
+	"/sys/leases/irrevocable/revoke-force":              regexp.MustCompile(`^/sys/leases/irrevocable/revoke-force$`),
+	"/sys/leases/lookup/":                               regexp.MustCompile(`^/sys/leases/lookup/?$`),
+	"/sys/leases/lookup/{prefix}":                       regexp.MustCompile(`^/sys/leases/lookup/.+$`),
+	"/sys/leases/revoke-force/{prefix}":                 regexp.MustCompile(`^/sys/leases/revoke-force/.+$`),
+	"/sys/leases/revoke-prefix/{prefix}":                regexp.MustCompile(`^/sys/leases/revoke-prefix/.+$`),
+	"/sys/network-policy":                               regexp.MustCompile(`^/sys/network-policy/?$`),
+	"/sys/network-policy/{name}":                        regexp.MustCompile(`^/sys/network-policy/.+$`),
+	"/sys/payload-encryption/rotate":                    regexp.MustCompile(`^/sys/payload-encryption/rotate$`),
+	"/sys/plugins/catalog/{name}":                       regexp.MustCompile(`^/sys/plugins/catalog/[^/]+$`),
+	"/sys/plugins/catalog/{type}":                       regexp.MustCompile(`^/sys/plugins/catalog/[\w-]+$`),
+	"/sys/plugins/catalog/{type}/{name}":                regexp.MustCompile(`^/sys/plugins/catalog/[\w-]+/[^/]+$`),
+	"/sys/raw":                                          regexp.MustCompile(`^/sys/raw$`),
+	"/sys/raw/{path}":                                   regexp.MustCompile(`^/sys/raw/.+$`),
+	"/sys/remount":                                      regexp.MustCompile(`^/sys/remount$`),
+	"/sys/restricted-crypto/report":                     regexp.MustCompile(`^/sys/restricted-crypto/report$`),
+	"/sys/revoke-force/{prefix}":                        regexp.MustCompile(`^/sys/revoke-force/.+$`),
+	"/sys/revoke-prefix/{prefix}":                       regexp.MustCompile(`^/sys/revoke-prefix/.+$`),
+	"/sys/rotate":                                       regexp.MustCompile(`^/sys/rotate$`),
+	"/sys/rotate/roots":                                 regexp.MustCompile(`^/sys/rotate/roots/?$`),
+	"/sys/rotate/roots/{name}":                          regexp.MustCompile(`^/sys/rotate/roots/.+$`),
+	"/sys/rotate/roots/{name}/rotate":                   regexp.MustCompile(`^/sys/rotate/roots/.+/rotate$`),
+	"/sys/internal/inspect/router/{tag}":                regexp.MustCompile(`^/sys/internal/inspect/router/.+$`),
 }
 
 // PluginAPIClientMeta is a helper that plugins can use to configure TLS connections
@@ -9,6 +9,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 
 	retryablehttp "github.com/hashicorp/go-retryablehttp"
 )
@@ -38,6 +39,19 @@ type Request struct {
 	// EGPs). If set, the override flag will take effect for all policies
 	// evaluated during the request.
 	PolicyOverride bool
+
+	// IdempotencyKey identifies a write request across its retries, so that
+	// the server handles it only once and returns the same response to the
+	// retries.
+	IdempotencyKey string
+
+	// DryRun requests the write request to be validated and reported on
+	// without being persisted, on the endpoints supporting it.
+	DryRun bool
+
+	// AcknowledgedBanners are the names of the login banners acknowledged by
+	// the user, required by login requests subject to them.
+	AcknowledgedBanners []string
 }
 
 // SetJSONBody is used to set a request body that is a JSON-encoded value.
@@ -144,5 +158,17 @@ func (r *Request) toRetryableHTTP() (*retryablehttp.Request, error) {
 		req.Header.Set("X-Vault-Policy-Override", "true")
 	}
 
+	if r.IdempotencyKey != "" {
+		req.Header.Set("X-OpenBao-Idempotency-Key", r.IdempotencyKey)
+	}
+
+	if r.DryRun {
+		req.Header.Set("X-OpenBao-Dry-Run", "true")
+	}
+
+	if len(r.AcknowledgedBanners) > 0 {
+		req.Header.Set("X-OpenBao-Acknowledge-Banners", strings.Join(r.AcknowledgedBanners, ","))
+	}
+
 	return req, nil
 }
@@ -0,0 +1,269 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ResponseCacheConfig enables an in-memory cache of read responses on the
+// client. Only successful GET requests matching one of the Rules are cached;
+// any other request made through the same client, or a shallow copy of it
+// such as one returned by WithNamespace, invalidates the entries of the
+// rules it touches. Cached responses are keyed by path, query, token and
+// namespace, so they are never shared between identities.
+//
+// The cache cannot observe changes made by other clients, so rule TTLs
+// should be chosen with that staleness in mind.
+type ResponseCacheConfig struct {
+	// Rules selects which paths are cached and for how long. If nil,
+	// DefaultResponseCacheRules is used.
+	Rules []ResponseCacheRule
+
+	// MaxEntries bounds the number of cached responses. When full, the entry
+	// closest to expiry is evicted. Zero means 1024.
+	MaxEntries int
+}
+
+// ResponseCacheRule describes a path whose read responses may be cached.
+type ResponseCacheRule struct {
+	// Path is the request path relative to /v1/, e.g. "sys/health". A
+	// trailing "*" matches any path with the preceding prefix.
+	Path string
+
+	// TTL is how long a cached response is served.
+	TTL time.Duration
+
+	// InvalidatedBy lists path prefixes, relative to /v1/, whose non-GET
+	// requests drop the cached entries of this rule. Writes to paths
+	// matching the rule itself always invalidate it.
+	InvalidatedBy []string
+}
+
+// DefaultResponseCacheRules caches the health endpoint and the token
+// self-lookup, the two reads most frequently repeated by sidecars.
+var DefaultResponseCacheRules = []ResponseCacheRule{
+	{
+		Path: "sys/health",
+		TTL:  5 * time.Second,
+	},
+	{
+		Path:          "auth/token/lookup-self",
+		TTL:           30 * time.Second,
+		InvalidatedBy: []string{"auth/token/"},
+	},
+}
+
+// KVResponseCacheRule returns a rule caching reads of the KV secrets engine
+// mounted at mount. For version 2 mounts, writes to the metadata and delete
+// endpoints are covered as well since they share the mount prefix.
+func KVResponseCacheRule(mount string, ttl time.Duration) ResponseCacheRule {
+	return ResponseCacheRule{
+		Path: strings.Trim(mount, "/") + "/*",
+		TTL:  ttl,
+	}
+}
+
+const defaultResponseCacheMaxEntries = 1024
+
+type responseCache struct {
+	rules      []ResponseCacheRule
+	maxEntries int
+
+	lock    sync.Mutex
+	entries map[string]*responseCacheEntry
+}
+
+type responseCacheEntry struct {
+	rule       int
+	statusCode int
+	header     http.Header
+	body       []byte
+	expires    time.Time
+}
+
+func newResponseCache(config *ResponseCacheConfig) *responseCache {
+	if config == nil {
+		return nil
+	}
+
+	rules := config.Rules
+	if rules == nil {
+		rules = DefaultResponseCacheRules
+	}
+	maxEntries := config.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = defaultResponseCacheMaxEntries
+	}
+
+	return &responseCache{
+		rules:      rules,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*responseCacheEntry),
+	}
+}
+
+// key returns the cache key of r and the index of the rule it matches, or
+// -1 if the request is not cacheable.
+func (c *responseCache) key(r *Request, token, ns string) (string, int) {
+	if c == nil || r.Method != http.MethodGet || r.WrapTTL != "" || len(r.MFAHeaderVals) != 0 {
+		return "", -1
+	}
+
+	rule := c.match(requestPath(r))
+	if rule < 0 {
+		return "", -1
+	}
+
+	h := sha256.New()
+	for _, part := range []string{r.URL.Path, r.Params.Encode(), token, ns} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)), rule
+}
+
+func (c *responseCache) match(path string) int {
+	for i, rule := range c.rules {
+		if strings.HasSuffix(rule.Path, "*") {
+			if strings.HasPrefix(path, strings.TrimSuffix(rule.Path, "*")) {
+				return i
+			}
+		} else if path == rule.Path {
+			return i
+		}
+	}
+	return -1
+}
+
+// get returns a copy of the cached response for key, if any.
+func (c *responseCache) get(key string) *Response {
+	if c == nil || key == "" {
+		return nil
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	if time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		return nil
+	}
+
+	return &Response{Response: &http.Response{
+		StatusCode:    entry.statusCode,
+		Status:        http.StatusText(entry.statusCode),
+		Header:        entry.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(entry.body)),
+		ContentLength: int64(len(entry.body)),
+	}}
+}
+
+// put caches resp under key. The response body is read in full and
+// replaced so that the caller can still consume it.
+func (c *responseCache) put(key string, rule int, resp *Response) error {
+	if c == nil || key == "" || resp == nil || resp.Response == nil {
+		return nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 || resp.StatusCode == http.StatusNoContent {
+		return nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		c.evictLocked()
+	}
+	c.entries[key] = &responseCacheEntry{
+		rule:       rule,
+		statusCode: resp.StatusCode,
+		header:     resp.Header.Clone(),
+		body:       body,
+		expires:    time.Now().Add(c.rules[rule].TTL),
+	}
+
+	return nil
+}
+
+// evictLocked drops expired entries, or the entry closest to expiry if none
+// have expired.
+func (c *responseCache) evictLocked() {
+	now := time.Now()
+	var oldestKey string
+	var oldest time.Time
+	for k, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, k)
+			continue
+		}
+		if oldestKey == "" || entry.expires.Before(oldest) {
+			oldestKey, oldest = k, entry.expires
+		}
+	}
+	if len(c.entries) >= c.maxEntries {
+		delete(c.entries, oldestKey)
+	}
+}
+
+// invalidate drops the entries of all rules affected by a non-GET request
+// to the given path.
+func (c *responseCache) invalidate(path string) {
+	if c == nil {
+		return
+	}
+
+	affected := make(map[int]struct{})
+	if rule := c.match(path); rule >= 0 {
+		affected[rule] = struct{}{}
+	}
+	for i, rule := range c.rules {
+		for _, prefix := range rule.InvalidatedBy {
+			if strings.HasPrefix(path, strings.TrimPrefix(prefix, "/")) {
+				affected[i] = struct{}{}
+			}
+		}
+	}
+	if len(affected) == 0 {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for k, entry := range c.entries {
+		if _, ok := affected[entry.rule]; ok {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// purge drops all cached entries.
+func (c *responseCache) purge() {
+	if c == nil {
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.entries = make(map[string]*responseCacheEntry)
+}
+
+func requestPath(r *Request) string {
+	return strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/"), "v1/")
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient_ResponseCache(t *testing.T) {
+	var lock sync.Mutex
+	calls := make(map[string]int)
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		lock.Lock()
+		calls[req.Method+" "+req.URL.Path]++
+		lock.Unlock()
+
+		switch req.URL.Path {
+		case "/v1/sys/health":
+			w.Write([]byte(`{"initialized":true,"sealed":false}`))
+		case "/v1/auth/token/lookup-self":
+			w.Write([]byte(`{"data":{"id":"` + req.Header.Get(AuthHeaderName) + `"}}`))
+		default:
+			if req.Method == http.MethodGet {
+				w.Write([]byte(`{"data":{"foo":"bar"}}`))
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}
+
+	config, ln := testHTTPServer(t, http.HandlerFunc(handler))
+	defer ln.Close()
+
+	config.ResponseCache = &ResponseCacheConfig{
+		Rules: append([]ResponseCacheRule{KVResponseCacheRule("secret", time.Minute)}, DefaultResponseCacheRules...),
+	}
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.SetToken("token-a")
+
+	count := func(key string) int {
+		lock.Lock()
+		defer lock.Unlock()
+		return calls[key]
+	}
+
+	for i := 0; i < 3; i++ {
+		health, err := client.Sys().Health()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !health.Initialized {
+			t.Fatalf("bad health response: %#v", health)
+		}
+
+		secret, err := client.Logical().Read("secret/foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if secret.Data["foo"] != "bar" {
+			t.Fatalf("bad secret: %#v", secret)
+		}
+
+		secret, err = client.Auth().Token().LookupSelf()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if secret.Data["id"] != "token-a" {
+			t.Fatalf("bad lookup: %#v", secret)
+		}
+	}
+	for _, key := range []string{"GET /v1/sys/health", "GET /v1/secret/foo", "GET /v1/auth/token/lookup-self"} {
+		if n := count(key); n != 1 {
+			t.Fatalf("expected 1 call to %s, got %d", key, n)
+		}
+	}
+
+	// Responses are not shared between tokens.
+	other, err := client.Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client2 := client.WithNamespace("")
+	client2.SetToken("token-b")
+	secret, err := client2.Auth().Token().LookupSelf()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret.Data["id"] != "token-b" {
+		t.Fatalf("bad lookup: %#v", secret)
+	}
+	if n := count("GET /v1/auth/token/lookup-self"); n != 2 {
+		t.Fatalf("expected 2 lookups, got %d", n)
+	}
+
+	// Writes through the client invalidate the affected rules only.
+	if _, err := client.Logical().Write("secret/bar", map[string]interface{}{"a": "b"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Logical().Read("secret/foo"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Sys().Health(); err != nil {
+		t.Fatal(err)
+	}
+	if n := count("GET /v1/secret/foo"); n != 2 {
+		t.Fatalf("expected 2 reads after write, got %d", n)
+	}
+	if n := count("GET /v1/sys/health"); n != 1 {
+		t.Fatalf("expected health to stay cached, got %d calls", n)
+	}
+
+	// Renewing the token invalidates its lookup.
+	if _, err := client.Auth().Token().RenewSelf(0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Auth().Token().LookupSelf(); err != nil {
+		t.Fatal(err)
+	}
+	if n := count("GET /v1/auth/token/lookup-self"); n != 3 {
+		t.Fatalf("expected lookup after renewal, got %d", n)
+	}
+
+	// A client created with Clone has its own cache.
+	if _, err := other.Sys().Health(); err != nil {
+		t.Fatal(err)
+	}
+	if n := count("GET /v1/sys/health"); n != 2 {
+		t.Fatalf("expected separate cache for clone, got %d calls", n)
+	}
+
+	client.PurgeResponseCache()
+	if _, err := client.Sys().Health(); err != nil {
+		t.Fatal(err)
+	}
+	if n := count("GET /v1/sys/health"); n != 3 {
+		t.Fatalf("expected health call after purge, got %d", n)
+	}
+}
+
+func TestResponseCache_Expiry(t *testing.T) {
+	cache := newResponseCache(&ResponseCacheConfig{
+		Rules:      []ResponseCacheRule{{Path: "sys/health", TTL: time.Millisecond}},
+		MaxEntries: 1,
+	})
+
+	newReq := func(path string) *Request {
+		c, err := NewClient(DefaultConfig())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c.NewRequest(http.MethodGet, path)
+	}
+
+	if key, rule := cache.key(newReq("/v1/sys/seal-status"), "", ""); key != "" || rule != -1 {
+		t.Fatalf("unexpected cacheable request")
+	}
+	wrapped := newReq("/v1/sys/health")
+	wrapped.WrapTTL = "1m"
+	if key, _ := cache.key(wrapped, "", ""); key != "" {
+		t.Fatalf("wrapped requests must not be cached")
+	}
+
+	keyA, rule := cache.key(newReq("/v1/sys/health"), "a", "")
+	keyB, _ := cache.key(newReq("/v1/sys/health"), "b", "")
+	if keyA == keyB {
+		t.Fatalf("keys must differ by token")
+	}
+
+	put := func(key string) {
+		resp := &Response{Response: &http.Response{StatusCode: 200, Header: http.Header{}, Body: http.NoBody}}
+		if err := cache.put(key, rule, resp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	put(keyA)
+	put(keyB)
+	if len(cache.entries) != 1 {
+		t.Fatalf("expected eviction, got %d entries", len(cache.entries))
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	if cache.get(keyB) != nil {
+		t.Fatalf("expected expired entry")
+	}
+}
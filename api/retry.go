@@ -0,0 +1,287 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// DefaultRetryStatusCodes are the response status codes retried by a
+// RetryPolicy that does not specify its own.
+var DefaultRetryStatusCodes = map[int]int{
+	http.StatusPreconditionFailed:  0,
+	http.StatusTooManyRequests:     0,
+	http.StatusInternalServerError: 0,
+	http.StatusBadGateway:          0,
+	http.StatusServiceUnavailable:  0,
+	http.StatusGatewayTimeout:      0,
+}
+
+// RetryPolicy configures how requests are retried. When set on the client
+// configuration, it replaces the CheckRetry function. The number of retries
+// is still bounded by MaxRetries and the wait between attempts is still
+// computed by the configured Backoff function.
+type RetryPolicy struct {
+	// StatusCodes maps the response status codes that should be retried to
+	// the maximum number of retries for that code within a single request.
+	// A limit of 0 means the code is retried up to MaxRetries times. If nil,
+	// DefaultRetryStatusCodes is used.
+	StatusCodes map[int]int
+
+	// RetryConnectionErrors controls whether requests failing without a
+	// response, e.g. because the connection was refused or reset, are
+	// retried.
+	RetryConnectionErrors bool
+
+	// Budget bounds the total time spent on a single request, including all
+	// retries and the waits between them. Once exhausted, the last response
+	// or error is returned. Zero means no budget beyond the request's
+	// context deadline.
+	Budget time.Duration
+
+	// OnRetry, if set, is called before every retry. It is intended for
+	// metrics and logging and must not block.
+	OnRetry func(RetryEvent)
+}
+
+// RetryEvent describes a retry about to be performed.
+type RetryEvent struct {
+	// Attempt is the number of attempts made so far, starting at 1.
+	Attempt int
+
+	// StatusCode is the status code of the failed attempt, or 0 if it
+	// failed without a response.
+	StatusCode int
+
+	// Err is the error of the failed attempt, if any.
+	Err error
+}
+
+// checkRetry returns a retryablehttp.CheckRetry implementing the policy for
+// a single request started at the given time.
+func (p *RetryPolicy) checkRetry(start time.Time) retryablehttp.CheckRetry {
+	statusCodes := p.StatusCodes
+	if statusCodes == nil {
+		statusCodes = DefaultRetryStatusCodes
+	}
+
+	var lock sync.Mutex
+	attempts := 0
+	perStatus := make(map[int]int)
+
+	return func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		// Never retry once the context is done.
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+
+		lock.Lock()
+		defer lock.Unlock()
+		attempts++
+
+		if p.Budget > 0 && time.Since(start) >= p.Budget {
+			return false, nil
+		}
+
+		var statusCode int
+		if err != nil {
+			if !p.RetryConnectionErrors {
+				return false, nil
+			}
+			// Let the default policy weed out errors that will never
+			// succeed, such as TLS verification failures.
+			retry, checkErr := retryablehttp.DefaultRetryPolicy(ctx, nil, err)
+			if !retry || checkErr != nil {
+				return false, checkErr
+			}
+		} else {
+			statusCode = resp.StatusCode
+			limit, ok := statusCodes[statusCode]
+			if !ok {
+				return false, nil
+			}
+			perStatus[statusCode]++
+			if limit > 0 && perStatus[statusCode] > limit {
+				return false, nil
+			}
+		}
+
+		if p.OnRetry != nil {
+			p.OnRetry(RetryEvent{
+				Attempt:    attempts,
+				StatusCode: statusCode,
+				Err:        err,
+			})
+		}
+		return true, nil
+	}
+}
+
+// ExponentialJitterBackoff is a Backoff that waits a random duration
+// between min and an exponentially growing cap ("full jitter"), which avoids
+// synchronized retries from many clients. For 429 and 503 responses carrying
+// a Retry-After header, the server-provided value is honored instead.
+func ExponentialJitterBackoff(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if s, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && s >= 0 {
+			return time.Duration(s) * time.Second
+		}
+	}
+
+	if max <= min {
+		return min
+	}
+
+	upper := float64(min) * math.Pow(2, float64(attemptNum))
+	if upper > float64(max) || math.IsInf(upper, 0) {
+		upper = float64(max)
+	}
+	if upper <= float64(min) {
+		return min
+	}
+
+	return min + time.Duration(rand.Int63n(int64(upper)-int64(min)))
+}
+
+// ErrCircuitOpen is returned, without contacting the server, for requests
+// made while the client's circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open: too many recent requests to the server failed")
+
+// CircuitState is the state of a client's circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed is the normal state: requests are sent to the server.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects all requests with ErrCircuitOpen.
+	CircuitOpen
+	// CircuitHalfOpen lets a single trial request through to decide
+	// whether to close or re-open the circuit.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerConfig configures the client's circuit breaker. A request
+// counts as failed if it could not be sent, or if its final response, after
+// retries and redirects, has a 5xx status code.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failed requests after
+	// which the circuit opens.
+	FailureThreshold int
+
+	// OpenDuration is how long the circuit stays open before a trial
+	// request is let through.
+	OpenDuration time.Duration
+
+	// OnStateChange, if set, is called on every state transition. It is
+	// intended for metrics and logging and must not block.
+	OnStateChange func(from, to CircuitState)
+}
+
+type circuitBreaker struct {
+	config *CircuitBreakerConfig
+
+	lock     sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	trialing bool
+}
+
+func newCircuitBreaker(config *CircuitBreakerConfig) *circuitBreaker {
+	if config == nil || config.FailureThreshold <= 0 {
+		return nil
+	}
+	return &circuitBreaker{config: config}
+}
+
+// allow reports whether a request may be sent.
+func (b *circuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	switch b.state {
+	case CircuitOpen:
+		if time.Since(b.openedAt) < b.config.OpenDuration {
+			return ErrCircuitOpen
+		}
+		b.transition(CircuitHalfOpen)
+		fallthrough
+	case CircuitHalfOpen:
+		if b.trialing {
+			return ErrCircuitOpen
+		}
+		b.trialing = true
+	}
+
+	return nil
+}
+
+// record records the outcome of a request let through by allow.
+func (b *circuitBreaker) record(resp *Response, err error) {
+	if b == nil {
+		return
+	}
+
+	failed := resp == nil && err != nil && !errors.Is(err, context.Canceled)
+	if resp != nil && resp.Response != nil && resp.StatusCode >= 500 {
+		failed = true
+	}
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.trialing = false
+	if !failed {
+		b.failures = 0
+		b.transition(CircuitClosed)
+		return
+	}
+
+	b.failures++
+	if b.state == CircuitHalfOpen || b.failures >= b.config.FailureThreshold {
+		b.openedAt = time.Now()
+		b.transition(CircuitOpen)
+	}
+}
+
+// State returns the current state of the circuit breaker.
+func (b *circuitBreaker) State() CircuitState {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.state
+}
+
+func (b *circuitBreaker) transition(to CircuitState) {
+	from := b.state
+	if from == to {
+		return
+	}
+	b.state = to
+	if b.config.OnStateChange != nil {
+		b.config.OnStateChange(from, to)
+	}
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_RetryPolicy(t *testing.T) {
+	var calls int32
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		switch n {
+		case 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.Write([]byte(`{"data":{}}`))
+		}
+	}
+
+	config, ln := testHTTPServer(t, http.HandlerFunc(handler))
+	defer ln.Close()
+
+	var events []RetryEvent
+	config.MaxRetries = 5
+	config.MinRetryWait = time.Millisecond
+	config.MaxRetryWait = 5 * time.Millisecond
+	config.Backoff = ExponentialJitterBackoff
+	config.RetryPolicy = &RetryPolicy{
+		OnRetry: func(e RetryEvent) { events = append(events, e) },
+	}
+
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Logical().Read("secret/foo"); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 3 {
+		t.Fatalf("expected 3 calls, got %d", calls)
+	}
+	if len(events) != 2 || events[0].StatusCode != 429 || events[1].StatusCode != 503 || events[1].Attempt != 2 {
+		t.Fatalf("unexpected retry events: %#v", events)
+	}
+
+	// Limit the number of retries of 429 responses.
+	atomic.StoreInt32(&calls, 0)
+	client.SetRetryPolicy(&RetryPolicy{
+		StatusCodes: map[int]int{http.StatusTooManyRequests: 0, http.StatusServiceUnavailable: 0},
+		Budget:      time.Nanosecond,
+	})
+	_, err = client.Logical().Read("secret/foo")
+	var respErr *ResponseError
+	if !errors.As(err, &respErr) || respErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected budget to prevent retries, got %v", err)
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+
+	// Status codes not in the policy are not retried.
+	atomic.StoreInt32(&calls, 0)
+	client.SetRetryPolicy(&RetryPolicy{
+		StatusCodes: map[int]int{http.StatusBadGateway: 1},
+	})
+	if _, err := client.Logical().Read("secret/foo"); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 {
+		t.Fatalf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	var healthy int32
+	handler := func(w http.ResponseWriter, req *http.Request) {
+		if atomic.LoadInt32(&healthy) == 0 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"data":{}}`))
+	}
+
+	config, ln := testHTTPServer(t, http.HandlerFunc(handler))
+	defer ln.Close()
+
+	var transitions []CircuitState
+	config.MaxRetries = 0
+	config.CircuitBreaker = &CircuitBreakerConfig{
+		FailureThreshold: 2,
+		OpenDuration:     50 * time.Millisecond,
+		OnStateChange: func(from, to CircuitState) {
+			transitions = append(transitions, to)
+		},
+	}
+
+	client, err := NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Logical().ReadWithContext(context.Background(), "secret/foo"); err == nil {
+			t.Fatal("expected error")
+		}
+	}
+	if client.CircuitState() != CircuitOpen {
+		t.Fatalf("expected open circuit, got %s", client.CircuitState())
+	}
+
+	atomic.StoreInt32(&healthy, 1)
+	if _, err := client.Logical().Read("secret/foo"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, err := client.Logical().Read("secret/foo"); err != nil {
+		t.Fatal(err)
+	}
+	if client.CircuitState() != CircuitClosed {
+		t.Fatalf("expected closed circuit, got %s", client.CircuitState())
+	}
+
+	expected := []CircuitState{CircuitOpen, CircuitHalfOpen, CircuitClosed}
+	if len(transitions) != len(expected) {
+		t.Fatalf("unexpected transitions: %v", transitions)
+	}
+	for i := range expected {
+		if transitions[i] != expected[i] {
+			t.Fatalf("unexpected transitions: %v", transitions)
+		}
+	}
+}
+
+func TestExponentialJitterBackoff(t *testing.T) {
+	for attempt := 0; attempt < 10; attempt++ {
+		wait := ExponentialJitterBackoff(10*time.Millisecond, 100*time.Millisecond, attempt, nil)
+		if wait < 10*time.Millisecond || wait > 100*time.Millisecond {
+			t.Fatalf("wait %s out of bounds", wait)
+		}
+	}
+
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{"3"}}}
+	if wait := ExponentialJitterBackoff(time.Millisecond, time.Second, 1, resp); wait != 3*time.Second {
+		t.Fatalf("expected Retry-After to be honored, got %s", wait)
+	}
+}
@@ -355,10 +355,15 @@ func ParseSecret(r io.Reader) (*Secret, error) {
 			return nil, err
 		}
 		errRaw, errPresent := data["errors"]
+		errFields := 1
+		if _, ok := data["error_code"]; ok && errPresent {
+			errFields++
+		}
 
-		// if only errors are present in the resp.Body return nil
-		// to return value not found as it does not have any raw data
-		if len(data) == 1 && errPresent {
+		// if only errors, and their code, are present in the resp.Body
+		// return nil to return value not found as it does not have any
+		// raw data
+		if len(data) == errFields && errPresent {
 			return nil, nil
 		}
 
@@ -78,3 +78,87 @@ func (c *Sys) CapabilitiesWithContext(ctx context.Context, token, path string) (
 
 	return res, nil
 }
+
+// CapabilitiesBatchInput is the input to CapabilitiesBatch. Either the token
+// or its accessor is used, the latter through sys/capabilities-accessor.
+type CapabilitiesBatchInput struct {
+	Token    string   `json:"token,omitempty"`
+	Accessor string   `json:"accessor,omitempty"`
+	Paths    []string `json:"paths"`
+	Explain  bool     `json:"explain,omitempty"`
+}
+
+// CapabilitiesBatchOutput is the output of CapabilitiesBatch.
+type CapabilitiesBatchOutput struct {
+	// Capabilities are the capabilities of the token on each path.
+	Capabilities map[string][]string
+
+	// Explain lists, for each path, the policies of the token granting or
+	// denying capabilities on it, if requested.
+	Explain map[string][]*PolicyCapabilities
+}
+
+// PolicyCapabilities are the capabilities a single policy grants on a path.
+type PolicyCapabilities struct {
+	Policy       string   `mapstructure:"policy"`
+	Namespace    string   `mapstructure:"namespace"`
+	Capabilities []string `mapstructure:"capabilities"`
+}
+
+func (c *Sys) CapabilitiesBatch(input *CapabilitiesBatchInput) (*CapabilitiesBatchOutput, error) {
+	return c.CapabilitiesBatchWithContext(context.Background(), input)
+}
+
+func (c *Sys) CapabilitiesBatchWithContext(ctx context.Context, input *CapabilitiesBatchInput) (*CapabilitiesBatchOutput, error) {
+	ctx, cancelFunc := c.c.withConfiguredTimeout(ctx)
+	defer cancelFunc()
+
+	path := "/v1/sys/capabilities"
+	if input.Accessor != "" {
+		path = "/v1/sys/capabilities-accessor"
+	}
+
+	r := c.c.NewRequest(http.MethodPost, path)
+	if err := r.SetJSONBody(input); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.c.rawRequestWithContext(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	secret, err := ParseSecret(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, errors.New("data from server response is empty")
+	}
+
+	output := &CapabilitiesBatchOutput{
+		Capabilities: make(map[string][]string, len(input.Paths)),
+	}
+
+	// With explain, the capabilities are nested under their own key
+	if input.Explain {
+		if err := mapstructure.Decode(secret.Data["capabilities"], &output.Capabilities); err != nil {
+			return nil, err
+		}
+		if err := mapstructure.Decode(secret.Data["explain"], &output.Explain); err != nil {
+			return nil, err
+		}
+		return output, nil
+	}
+
+	for _, path := range input.Paths {
+		var res []string
+		if err := mapstructure.Decode(secret.Data[path], &res); err != nil {
+			return nil, err
+		}
+		output.Capabilities[path] = res
+	}
+
+	return output, nil
+}
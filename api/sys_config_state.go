@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// ConfigStateApplyInput is a declarative bundle for sys/config/state/apply.
+// Mounts and Auth are keyed by path and take the parameters of sys/mounts
+// and sys/auth. Resources are keyed by API path; a nil value deletes the
+// path.
+type ConfigStateApplyInput struct {
+	Mounts    map[string]map[string]interface{} `json:"mounts,omitempty"`
+	Auth      map[string]map[string]interface{} `json:"auth,omitempty"`
+	Policies  map[string]string                 `json:"policies,omitempty"`
+	Resources map[string]map[string]interface{} `json:"resources,omitempty"`
+	Prune     bool                              `json:"prune,omitempty"`
+	DryRun    bool                              `json:"dry_run,omitempty"`
+}
+
+type ConfigStateApplyOutput struct {
+	Changes []*ConfigStateChange `json:"changes" mapstructure:"changes"`
+	DryRun  bool                 `json:"dry_run" mapstructure:"dry_run"`
+}
+
+type ConfigStateChange struct {
+	Kind   string   `json:"kind" mapstructure:"kind"`
+	Path   string   `json:"path" mapstructure:"path"`
+	Action string   `json:"action" mapstructure:"action"`
+	Fields []string `json:"fields,omitempty" mapstructure:"fields"`
+}
+
+func (c *Sys) ApplyConfigState(input *ConfigStateApplyInput) (*ConfigStateApplyOutput, error) {
+	return c.ApplyConfigStateWithContext(context.Background(), input)
+}
+
+func (c *Sys) ApplyConfigStateWithContext(ctx context.Context, input *ConfigStateApplyInput) (*ConfigStateApplyOutput, error) {
+	ctx, cancelFunc := c.c.withConfiguredTimeout(ctx)
+	defer cancelFunc()
+
+	r := c.c.NewRequest(http.MethodPost, "/v1/sys/config/state/apply")
+	if err := r.SetJSONBody(input); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.c.rawRequestWithContext(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	secret, err := ParseSecret(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, errors.New("data from server response is empty")
+	}
+
+	var result ConfigStateApplyOutput
+	err = mapstructure.Decode(secret.Data, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, err
+}
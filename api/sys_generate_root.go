@@ -120,14 +120,32 @@ func (c *Sys) GenerateRecoveryOperationTokenUpdate(shard, nonce string) (*Genera
 }
 
 func (c *Sys) GenerateRootUpdateWithContext(ctx context.Context, shard, nonce string) (*GenerateRootStatusResponse, error) {
-	return c.generateRootUpdateCommonWithContext(ctx, "/v1/sys/generate-root/update", shard, nonce)
+	return c.generateRootUpdateCommonWithContext(ctx, "/v1/sys/generate-root/update", shard, "", nonce)
 }
 
 func (c *Sys) GenerateRecoveryOperationTokenUpdateWithContext(ctx context.Context, shard, nonce string) (*GenerateRootStatusResponse, error) {
-	return c.generateRootUpdateCommonWithContext(ctx, "/v1/sys/generate-recovery-token/update", shard, nonce)
+	return c.generateRootUpdateCommonWithContext(ctx, "/v1/sys/generate-recovery-token/update", shard, "", nonce)
 }
 
-func (c *Sys) generateRootUpdateCommonWithContext(ctx context.Context, path, shard, nonce string) (*GenerateRootStatusResponse, error) {
+// GenerateRootUpdateWithTOTP provides an unseal key share enrolled for TOTP,
+// along with its current TOTP code.
+func (c *Sys) GenerateRootUpdateWithTOTP(shard, totp, nonce string) (*GenerateRootStatusResponse, error) {
+	return c.GenerateRootUpdateWithTOTPWithContext(context.Background(), shard, totp, nonce)
+}
+
+func (c *Sys) GenerateRecoveryOperationTokenUpdateWithTOTP(shard, totp, nonce string) (*GenerateRootStatusResponse, error) {
+	return c.GenerateRecoveryOperationTokenUpdateWithTOTPWithContext(context.Background(), shard, totp, nonce)
+}
+
+func (c *Sys) GenerateRootUpdateWithTOTPWithContext(ctx context.Context, shard, totp, nonce string) (*GenerateRootStatusResponse, error) {
+	return c.generateRootUpdateCommonWithContext(ctx, "/v1/sys/generate-root/update", shard, totp, nonce)
+}
+
+func (c *Sys) GenerateRecoveryOperationTokenUpdateWithTOTPWithContext(ctx context.Context, shard, totp, nonce string) (*GenerateRootStatusResponse, error) {
+	return c.generateRootUpdateCommonWithContext(ctx, "/v1/sys/generate-recovery-token/update", shard, totp, nonce)
+}
+
+func (c *Sys) generateRootUpdateCommonWithContext(ctx context.Context, path, shard, totp, nonce string) (*GenerateRootStatusResponse, error) {
 	ctx, cancelFunc := c.c.withConfiguredTimeout(ctx)
 	defer cancelFunc()
 
@@ -135,6 +153,9 @@ func (c *Sys) generateRootUpdateCommonWithContext(ctx context.Context, path, sha
 		"key":   shard,
 		"nonce": nonce,
 	}
+	if totp != "" {
+		body["totp"] = totp
+	}
 
 	r := c.c.NewRequest(http.MethodPut, path)
 	if err := r.SetJSONBody(body); err != nil {
@@ -44,4 +44,6 @@ type HealthResponse struct {
 	ClusterName   string `json:"cluster_name,omitempty"`
 	ClusterID     string `json:"cluster_id,omitempty"`
 	LastWAL       uint64 `json:"last_wal,omitempty"`
+
+	RestrictedCrypto bool `json:"restricted_crypto,omitempty"`
 }
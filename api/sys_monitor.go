@@ -8,23 +8,57 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strings"
+	"time"
 )
 
 // Monitor returns a channel that outputs strings containing the log messages
 // coming from the server.
 func (c *Sys) Monitor(ctx context.Context, logLevel string, logFormat string) (chan string, error) {
+	return c.MonitorWithInput(ctx, &MonitorInput{
+		LogLevel:  logLevel,
+		LogFormat: logFormat,
+	})
+}
+
+// MonitorInput is used as input to the MonitorWithInput function.
+type MonitorInput struct {
+	LogLevel          string
+	LogFormat         string
+	Subsystems        []string
+	ExcludeSubsystems []string
+	Regex             string
+	HeartbeatInterval time.Duration
+}
+
+// MonitorWithInput returns a channel that outputs strings containing the log
+// messages coming from the server, filtered server-side as set by input.
+func (c *Sys) MonitorWithInput(ctx context.Context, input *MonitorInput) (chan string, error) {
 	r := c.c.NewRequest(http.MethodGet, "/v1/sys/monitor")
 
-	if logLevel == "" {
+	if input.LogLevel == "" {
 		r.Params.Add("log_level", "info")
 	} else {
-		r.Params.Add("log_level", logLevel)
+		r.Params.Add("log_level", input.LogLevel)
 	}
 
-	if logFormat == "" {
+	if input.LogFormat == "" {
 		r.Params.Add("log_format", "standard")
 	} else {
-		r.Params.Add("log_format", logFormat)
+		r.Params.Add("log_format", input.LogFormat)
+	}
+
+	if len(input.Subsystems) > 0 {
+		r.Params.Add("subsystems", strings.Join(input.Subsystems, ","))
+	}
+	if len(input.ExcludeSubsystems) > 0 {
+		r.Params.Add("exclude_subsystems", strings.Join(input.ExcludeSubsystems, ","))
+	}
+	if input.Regex != "" {
+		r.Params.Add("regex", input.Regex)
+	}
+	if input.HeartbeatInterval > 0 {
+		r.Params.Add("heartbeat_interval", input.HeartbeatInterval.String())
 	}
 
 	resp, err := c.c.RawRequestWithContext(ctx, r)
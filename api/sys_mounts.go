@@ -209,6 +209,25 @@ func (c *Sys) TuneMountWithContext(ctx context.Context, path string, config Moun
 	return err
 }
 
+// WarmUpMount loads the backend of a secrets engine enabled with lazy_load
+// ahead of the first request routed to it.
+func (c *Sys) WarmUpMount(path string) error {
+	return c.WarmUpMountWithContext(context.Background(), path)
+}
+
+func (c *Sys) WarmUpMountWithContext(ctx context.Context, path string) error {
+	ctx, cancelFunc := c.c.withConfiguredTimeout(ctx)
+	defer cancelFunc()
+
+	r := c.c.NewRequest(http.MethodPost, fmt.Sprintf("/v1/sys/mounts/%s/warm-up", path))
+
+	resp, err := c.c.rawRequestWithContext(ctx, r)
+	if err == nil {
+		defer resp.Body.Close()
+	}
+	return err
+}
+
 func (c *Sys) MountConfig(path string) (*MountConfigOutput, error) {
 	return c.MountConfigWithContext(context.Background(), path)
 }
@@ -271,6 +290,12 @@ type MountConfigInput struct {
 	AllowedManagedKeys        []string                `json:"allowed_managed_keys,omitempty" mapstructure:"allowed_managed_keys"`
 	PluginVersion             string                  `json:"plugin_version,omitempty"`
 	UserLockoutConfig         *UserLockoutConfigInput `json:"user_lockout_config,omitempty"`
+	Protected                 *bool                   `json:"protected,omitempty" mapstructure:"protected"`
+	DefaultRequestTimeout     string                  `json:"default_request_timeout,omitempty" mapstructure:"default_request_timeout"`
+	MaxRequestSize            *int64                  `json:"max_request_size,omitempty" mapstructure:"max_request_size"`
+	MaxConcurrentRequests     *int                    `json:"max_concurrent_requests,omitempty" mapstructure:"max_concurrent_requests"`
+	LazyLoad                  *bool                   `json:"lazy_load,omitempty" mapstructure:"lazy_load"`
+	TokenBindClientCert       *bool                   `json:"token_bind_client_cert,omitempty" mapstructure:"token_bind_client_cert"`
 	// Deprecated: This field will always be blank for newer server responses.
 	PluginName string `json:"plugin_name,omitempty" mapstructure:"plugin_name"`
 }
@@ -303,6 +328,12 @@ type MountConfigOutput struct {
 	TokenType                 string                   `json:"token_type,omitempty" mapstructure:"token_type"`
 	AllowedManagedKeys        []string                 `json:"allowed_managed_keys,omitempty" mapstructure:"allowed_managed_keys"`
 	UserLockoutConfig         *UserLockoutConfigOutput `json:"user_lockout_config,omitempty"`
+	Protected                 bool                     `json:"protected,omitempty" mapstructure:"protected"`
+	DefaultRequestTimeout     int                      `json:"default_request_timeout,omitempty" mapstructure:"default_request_timeout"`
+	MaxRequestSize            int64                    `json:"max_request_size,omitempty" mapstructure:"max_request_size"`
+	MaxConcurrentRequests     int                      `json:"max_concurrent_requests,omitempty" mapstructure:"max_concurrent_requests"`
+	LazyLoad                  bool                     `json:"lazy_load,omitempty" mapstructure:"lazy_load"`
+	TokenBindClientCert       bool                     `json:"token_bind_client_cert,omitempty" mapstructure:"token_bind_client_cert"`
 	// Deprecated: This field will always be blank for newer server responses.
 	PluginName string `json:"plugin_name,omitempty" mapstructure:"plugin_name"`
 }
@@ -334,4 +365,10 @@ type MountMigrationStatusInfo struct {
 	SourceMount     string `mapstructure:"source_mount"`
 	TargetMount     string `mapstructure:"target_mount"`
 	MigrationStatus string `mapstructure:"status"`
+	Stage           string `mapstructure:"stage"`
+	LeasesTotal     int    `mapstructure:"leases_total"`
+	LeasesRevoked   int    `mapstructure:"leases_revoked"`
+	StartTime       string `mapstructure:"start_time"`
+	EndTime         string `mapstructure:"end_time"`
+	Error           string `mapstructure:"error"`
 }
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// EncryptedPayloadContentType is the content type of request bodies
+	// encrypted to the payload encryption key of the server.
+	EncryptedPayloadContentType = "application/vnd.openbao.encrypted-payload+json"
+
+	// PayloadEncryptionAlgorithm is the only payload encryption scheme
+	// supported by the server.
+	PayloadEncryptionAlgorithm = "x25519-hkdf-sha256-aes256gcm"
+
+	payloadEncryptionInfo = "openbao payload encryption"
+)
+
+// PayloadEncryptionKey is the public key request payloads are encrypted to.
+type PayloadEncryptionKey struct {
+	KeyID        string    `json:"key_id" mapstructure:"key_id"`
+	Algorithm    string    `json:"algorithm" mapstructure:"algorithm"`
+	PublicKey    string    `json:"public_key" mapstructure:"public_key"`
+	CreationTime time.Time `json:"creation_time" mapstructure:"-"`
+}
+
+func (c *Sys) PayloadEncryptionKey() (*PayloadEncryptionKey, error) {
+	return c.PayloadEncryptionKeyWithContext(context.Background())
+}
+
+func (c *Sys) PayloadEncryptionKeyWithContext(ctx context.Context) (*PayloadEncryptionKey, error) {
+	r := c.c.NewRequest(http.MethodGet, "/v1/sys/payload-encryption/key")
+	return c.payloadEncryptionKey(ctx, r)
+}
+
+func (c *Sys) RotatePayloadEncryptionKey() (*PayloadEncryptionKey, error) {
+	return c.RotatePayloadEncryptionKeyWithContext(context.Background())
+}
+
+func (c *Sys) RotatePayloadEncryptionKeyWithContext(ctx context.Context) (*PayloadEncryptionKey, error) {
+	r := c.c.NewRequest(http.MethodPost, "/v1/sys/payload-encryption/rotate")
+	return c.payloadEncryptionKey(ctx, r)
+}
+
+func (c *Sys) payloadEncryptionKey(ctx context.Context, r *Request) (*PayloadEncryptionKey, error) {
+	ctx, cancelFunc := c.c.withConfiguredTimeout(ctx)
+	defer cancelFunc()
+
+	resp, err := c.c.rawRequestWithContext(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	secret, err := ParseSecret(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, errors.New("data from server response is empty")
+	}
+
+	var result PayloadEncryptionKey
+	if err := mapstructure.Decode(secret.Data, &result); err != nil {
+		return nil, err
+	}
+	if creationTime, ok := secret.Data["creation_time"].(string); ok {
+		result.CreationTime, err = time.Parse(time.RFC3339Nano, creationTime)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &result, nil
+}
+
+// SetEncryptedJSONBody encodes val as JSON and encrypts it to the payload
+// encryption key, binding it to the path of the request. The URL of the
+// request must not change after the body is set.
+func (r *Request) SetEncryptedJSONBody(key *PayloadEncryptionKey, val interface{}) error {
+	if key == nil {
+		return errors.New("payload encryption key is nil")
+	}
+	if key.Algorithm != PayloadEncryptionAlgorithm {
+		return errors.New("unsupported payload encryption algorithm: " + key.Algorithm)
+	}
+	publicKey, err := base64.StdEncoding.DecodeString(key.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+
+	ephemeralPrivateKey := make([]byte, curve25519.ScalarSize)
+	if _, err := rand.Read(ephemeralPrivateKey); err != nil {
+		return err
+	}
+	ephemeralPublicKey, err := curve25519.X25519(ephemeralPrivateKey, curve25519.Basepoint)
+	if err != nil {
+		return err
+	}
+	shared, err := curve25519.X25519(ephemeralPrivateKey, publicKey)
+	if err != nil {
+		return err
+	}
+
+	salt := append(append([]byte{}, ephemeralPublicKey...), publicKey...)
+	cipherKey := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, shared, salt, []byte(payloadEncryptionInfo)), cipherKey); err != nil {
+		return err
+	}
+	block, err := aes.NewCipher(cipherKey)
+	if err != nil {
+		return err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	if err := r.SetJSONBody(map[string]interface{}{
+		"key_id":               key.KeyID,
+		"ephemeral_public_key": base64.StdEncoding.EncodeToString(ephemeralPublicKey),
+		"nonce":                base64.StdEncoding.EncodeToString(nonce),
+		"ciphertext":           base64.StdEncoding.EncodeToString(aead.Seal(nil, nonce, plaintext, []byte(r.URL.Path))),
+	}); err != nil {
+		return err
+	}
+	r.Headers.Set("Content-Type", EncryptedPayloadContentType)
+	return nil
+}
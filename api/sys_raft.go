@@ -174,7 +174,25 @@ func (c *Sys) RaftSnapshot(snapWriter io.Writer) error {
 // RaftSnapshotWithContext invokes the API that takes the snapshot of the raft cluster and
 // writes it to the supplied io.Writer.
 func (c *Sys) RaftSnapshotWithContext(ctx context.Context, snapWriter io.Writer) error {
+	return c.RaftSnapshotWithOptions(ctx, snapWriter, nil)
+}
+
+// RaftSnapshotOptions are the options of RaftSnapshotWithOptions.
+type RaftSnapshotOptions struct {
+	// Unencrypted requests a snapshot which is not encrypted to the backup
+	// encryption recipients of the cluster, if any.
+	Unencrypted bool
+}
+
+// RaftSnapshotWithOptions invokes the API that takes the snapshot of the raft
+// cluster with the given options and writes it to the supplied io.Writer.
+// Snapshots encrypted to the backup encryption recipients of the cluster are
+// written as is, without being verified.
+func (c *Sys) RaftSnapshotWithOptions(ctx context.Context, snapWriter io.Writer, opts *RaftSnapshotOptions) error {
 	r := c.c.NewRequest(http.MethodGet, "/v1/sys/storage/raft/snapshot")
+	if opts != nil && opts.Unencrypted {
+		r.Params.Set("encrypt", "false")
+	}
 	r.URL.RawQuery = r.Params.Encode()
 
 	resp, err := c.c.httpRequestWithContext(ctx, r)
@@ -183,6 +201,11 @@ func (c *Sys) RaftSnapshotWithContext(ctx context.Context, snapWriter io.Writer)
 	}
 	defer resp.Body.Close()
 
+	if resp.Header.Get("Content-Type") == "application/octet-stream" {
+		_, err := io.Copy(snapWriter, resp.Body)
+		return err
+	}
+
 	// Make sure that the last file in the archive, SHA256SUMS.sealed, is present
 	// and non-empty.  This is to catch cases where the snapshot failed midstream,
 	// e.g. due to a problem with the seal that prevented encryption of that file.
@@ -265,6 +288,56 @@ func (c *Sys) RaftSnapshotRestoreWithContext(ctx context.Context, snapReader io.
 	return nil
 }
 
+// RaftSnapshotLoadMountResult is the result of loading a single mount from a
+// snapshot.
+type RaftSnapshotLoadMountResult struct {
+	SourceMount string `mapstructure:"source_mount"`
+	TargetMount string `mapstructure:"target_mount"`
+	Type        string `mapstructure:"type"`
+	Accessor    string `mapstructure:"accessor"`
+	Keys        int    `mapstructure:"keys"`
+}
+
+// RaftSnapshotLoadMount wraps RaftSnapshotLoadMountWithContext using context.Background.
+func (c *Sys) RaftSnapshotLoadMount(snapReader io.Reader, sourceMount, targetMount string) (*RaftSnapshotLoadMountResult, error) {
+	return c.RaftSnapshotLoadMountWithContext(context.Background(), snapReader, sourceMount, targetMount)
+}
+
+// RaftSnapshotLoadMountWithContext reads the snapshot from the io.Reader and
+// loads the data of the secrets engine mounted at sourceMount in the snapshot
+// into a new secrets engine mounted at targetMount, leaving the rest of the
+// cluster unchanged.
+func (c *Sys) RaftSnapshotLoadMountWithContext(ctx context.Context, snapReader io.Reader, sourceMount, targetMount string) (*RaftSnapshotLoadMountResult, error) {
+	r := c.c.NewRequest(http.MethodPost, "/v1/sys/storage/raft/snapshot-load")
+	// The snapshot is the body, so parameters are passed in the query.
+	query := r.URL.Query()
+	query.Set("scope", "mount")
+	query.Set("source_mount", sourceMount)
+	query.Set("target_mount", targetMount)
+	r.URL.RawQuery = query.Encode()
+	r.Body = snapReader
+
+	resp, err := c.c.httpRequestWithContext(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	secret, err := ParseSecret(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil || secret.Data == nil {
+		return nil, errors.New("data from server response is empty")
+	}
+
+	var result RaftSnapshotLoadMountResult
+	if err := mapstructure.WeakDecode(secret.Data, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 // RaftAutopilotState wraps RaftAutopilotStateWithContext using context.Background.
 func (c *Sys) RaftAutopilotState() (*AutopilotState, error) {
 	return c.RaftAutopilotStateWithContext(context.Background())
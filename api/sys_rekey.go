@@ -216,6 +216,16 @@ func (c *Sys) RekeyUpdate(shard, nonce string) (*RekeyUpdateResponse, error) {
 }
 
 func (c *Sys) RekeyUpdateWithContext(ctx context.Context, shard, nonce string) (*RekeyUpdateResponse, error) {
+	return c.RekeyUpdateWithTOTPWithContext(ctx, shard, "", nonce)
+}
+
+// RekeyUpdateWithTOTP provides an unseal key share enrolled for TOTP, along
+// with its current TOTP code.
+func (c *Sys) RekeyUpdateWithTOTP(shard, totp, nonce string) (*RekeyUpdateResponse, error) {
+	return c.RekeyUpdateWithTOTPWithContext(context.Background(), shard, totp, nonce)
+}
+
+func (c *Sys) RekeyUpdateWithTOTPWithContext(ctx context.Context, shard, totp, nonce string) (*RekeyUpdateResponse, error) {
 	ctx, cancelFunc := c.c.withConfiguredTimeout(ctx)
 	defer cancelFunc()
 
@@ -223,6 +233,9 @@ func (c *Sys) RekeyUpdateWithContext(ctx context.Context, shard, nonce string) (
 		"key":   shard,
 		"nonce": nonce,
 	}
+	if totp != "" {
+		body["totp"] = totp
+	}
 
 	r := c.c.NewRequest(http.MethodPut, "/v1/sys/rekey/update")
 	if err := r.SetJSONBody(body); err != nil {
@@ -436,6 +449,7 @@ type RekeyInitRequest struct {
 	PGPKeys             []string `json:"pgp_keys"`
 	Backup              bool
 	RequireVerification bool `json:"require_verification"`
+	TOTPShares          bool `json:"totp_shares"`
 }
 
 type RekeyStatusResponse struct {
@@ -449,6 +463,7 @@ type RekeyStatusResponse struct {
 	Backup               bool     `json:"backup"`
 	VerificationRequired bool     `json:"verification_required"`
 	VerificationNonce    string   `json:"verification_nonce"`
+	TOTPShares           bool     `json:"totp_shares"`
 }
 
 type RekeyUpdateResponse struct {
@@ -460,6 +475,7 @@ type RekeyUpdateResponse struct {
 	Backup               bool     `json:"backup"`
 	VerificationRequired bool     `json:"verification_required"`
 	VerificationNonce    string   `json:"verification_nonce,omitempty"`
+	TOTPURLs             []string `json:"totp_urls,omitempty"`
 }
 
 type RekeyRetrieveResponse struct {
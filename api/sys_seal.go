@@ -117,4 +117,5 @@ type UnsealOpts struct {
 	Key     string `json:"key"`
 	Reset   bool   `json:"reset"`
 	Migrate bool   `json:"migrate"`
+	TOTP    string `json:"totp,omitempty"`
 }
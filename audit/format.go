@@ -113,6 +113,7 @@ func (f *AuditFormatter) FormatRequest(ctx context.Context, w io.Writer, config
 
 		Request: &AuditRequest{
 			ID:                    req.ID,
+			CorrelationID:         req.CorrelationID,
 			ClientID:              req.ClientID,
 			ClientToken:           req.ClientToken,
 			ClientTokenAccessor:   req.ClientTokenAccessor,
@@ -131,6 +132,8 @@ func (f *AuditFormatter) FormatRequest(ctx context.Context, w io.Writer, config
 			Path:                          req.Path,
 			Data:                          req.Data,
 			PolicyOverride:                req.PolicyOverride,
+			DryRun:                        req.DryRun,
+			AcknowledgedBanners:           req.AcknowledgedBanners,
 			RemoteAddr:                    getRemoteAddr(req),
 			RemotePort:                    getRemotePort(req),
 			ReplicationCluster:            req.ReplicationCluster,
@@ -319,6 +322,7 @@ func (f *AuditFormatter) FormatResponse(ctx context.Context, w io.Writer, config
 
 		Request: &AuditRequest{
 			ID:                    req.ID,
+			CorrelationID:         req.CorrelationID,
 			ClientToken:           req.ClientToken,
 			ClientTokenAccessor:   req.ClientTokenAccessor,
 			ClientID:              req.ClientID,
@@ -337,6 +341,8 @@ func (f *AuditFormatter) FormatResponse(ctx context.Context, w io.Writer, config
 			Path:                          req.Path,
 			Data:                          req.Data,
 			PolicyOverride:                req.PolicyOverride,
+			DryRun:                        req.DryRun,
+			AcknowledgedBanners:           req.AcknowledgedBanners,
 			RemoteAddr:                    getRemoteAddr(req),
 			RemotePort:                    getRemotePort(req),
 			ClientCertificateSerialNumber: getClientCertificateSerialNumber(connState),
@@ -414,6 +420,7 @@ type AuditResponseEntry struct {
 
 type AuditRequest struct {
 	ID                            string                 `json:"id,omitempty"`
+	CorrelationID                 string                 `json:"correlation_id,omitempty"`
 	ClientID                      string                 `json:"client_id,omitempty"`
 	ReplicationCluster            string                 `json:"replication_cluster,omitempty"`
 	Operation                     logical.Operation      `json:"operation,omitempty"`
@@ -430,6 +437,8 @@ type AuditRequest struct {
 	Path                          string                 `json:"path,omitempty"`
 	Data                          map[string]interface{} `json:"data,omitempty"`
 	PolicyOverride                bool                   `json:"policy_override,omitempty"`
+	DryRun                        bool                   `json:"dry_run,omitempty"`
+	AcknowledgedBanners           []string               `json:"acknowledged_banners,omitempty"`
 	RemoteAddr                    string                 `json:"remote_address,omitempty"`
 	RemotePort                    int                    `json:"remote_port,omitempty"`
 	WrapTTL                       int                    `json:"wrap_ttl,omitempty"`
@@ -53,8 +53,9 @@ func TestFormatJSON_formatRequest(t *testing.T) {
 				},
 			},
 			&logical.Request{
-				Operation: logical.UpdateOperation,
-				Path:      "/foo",
+				Operation:     logical.UpdateOperation,
+				Path:          "/foo",
+				CorrelationID: "corr-1234",
 				Connection: &logical.Connection{
 					RemoteAddr: "127.0.0.1",
 				},
@@ -84,8 +85,9 @@ func TestFormatJSON_formatRequest(t *testing.T) {
 				},
 			},
 			&logical.Request{
-				Operation: logical.UpdateOperation,
-				Path:      "/foo",
+				Operation:     logical.UpdateOperation,
+				Path:          "/foo",
+				CorrelationID: "corr-1234",
 				Connection: &logical.Connection{
 					RemoteAddr: "127.0.0.1",
 				},
@@ -153,5 +155,5 @@ func TestFormatJSON_formatRequest(t *testing.T) {
 	}
 }
 
-const testFormatJSONReqBasicStrFmt = `{"time":"2015-08-05T13:45:46Z","type":"request","auth":{"client_token":"%s","accessor":"bar","display_name":"testtoken","policies":["root"],"no_default_policy":true,"metadata":null,"entity_id":"foobarentity","token_type":"service", "token_ttl": 14400, "token_issue_time": "2020-05-28T13:40:18-05:00"},"request":{"operation":"update","path":"/foo","data":null,"wrap_ttl":60,"remote_address":"127.0.0.1","headers":{"foo":["bar"]}},"error":"this is an error"}
+const testFormatJSONReqBasicStrFmt = `{"time":"2015-08-05T13:45:46Z","type":"request","auth":{"client_token":"%s","accessor":"bar","display_name":"testtoken","policies":["root"],"no_default_policy":true,"metadata":null,"entity_id":"foobarentity","token_type":"service", "token_ttl": 14400, "token_issue_time": "2020-05-28T13:40:18-05:00"},"request":{"correlation_id":"corr-1234","operation":"update","path":"/foo","data":null,"wrap_ttl":60,"remote_address":"127.0.0.1","headers":{"foo":["bar"]}},"error":"this is an error"}
 `
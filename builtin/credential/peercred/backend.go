@@ -0,0 +1,58 @@
+package peercred
+
+import (
+	"context"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const operationPrefixPeerCred = "peer-cred"
+
+func Factory(ctx context.Context, conf *logical.BackendConfig) (logical.Backend, error) {
+	b := Backend()
+	if err := b.Setup(ctx, conf); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func Backend() *backend {
+	var b backend
+	b.Backend = &framework.Backend{
+		Help: backendHelp,
+
+		PathsSpecial: &logical.Paths{
+			Unauthenticated: []string{
+				"login",
+			},
+		},
+
+		Paths: []*framework.Path{
+			pathRoles(&b),
+			pathRolesList(&b),
+			pathLogin(&b),
+		},
+
+		AuthRenew:   b.pathLoginRenew,
+		BackendType: logical.TypeCredential,
+	}
+
+	return &b
+}
+
+type backend struct {
+	*framework.Backend
+}
+
+const backendHelp = `
+The "peercred" credential provider allows processes on the same host to
+authenticate using the credentials of the connecting process, as reported by
+the kernel for connections made over a Unix domain socket listener.
+
+Roles bind a set of user and group IDs to token parameters. A process may
+log in to a role without presenting any secret if it matches all the
+bindings set on the role: its user ID must be one of the role's bound_uids
+and its primary group ID one of its bound_gids, for whichever of the two are
+set. Peer credentials are currently only available on Linux.
+`
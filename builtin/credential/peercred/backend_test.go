@@ -0,0 +1,123 @@
+package peercred
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/stretchr/testify/require"
+)
+
+func testBackend(t *testing.T) (*backend, logical.Storage) {
+	t.Helper()
+
+	storage := &logical.InmemStorage{}
+	config := logical.TestBackendConfig()
+	config.StorageView = storage
+
+	b, err := Factory(context.Background(), config)
+	require.NoError(t, err)
+
+	return b.(*backend), storage
+}
+
+func TestPeerCred_Roles(t *testing.T) {
+	b, storage := testBackend(t)
+	ctx := context.Background()
+
+	// A role without any binding is rejected.
+	resp, err := b.HandleRequest(ctx, &logical.Request{
+		Path:      "role/agent",
+		Operation: logical.CreateOperation,
+		Storage:   storage,
+		Data: map[string]interface{}{
+			"token_policies": "default",
+		},
+	})
+	require.ErrorIs(t, err, logical.ErrInvalidRequest)
+	require.True(t, resp.IsError())
+
+	resp, err = b.HandleRequest(ctx, &logical.Request{
+		Path:      "role/agent",
+		Operation: logical.CreateOperation,
+		Storage:   storage,
+		Data: map[string]interface{}{
+			"bound_uids":     "1000,1001",
+			"token_policies": "agent",
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, resp)
+
+	resp, err = b.HandleRequest(ctx, &logical.Request{
+		Path:      "role/agent",
+		Operation: logical.ReadOperation,
+		Storage:   storage,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{1000, 1001}, resp.Data["bound_uids"])
+	require.Equal(t, []string{"agent"}, resp.Data["token_policies"])
+
+	resp, err = b.HandleRequest(ctx, &logical.Request{
+		Path:      "role/",
+		Operation: logical.ListOperation,
+		Storage:   storage,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"agent"}, resp.Data["keys"])
+
+	resp, err = b.HandleRequest(ctx, &logical.Request{
+		Path:      "role/agent",
+		Operation: logical.DeleteOperation,
+		Storage:   storage,
+	})
+	require.NoError(t, err)
+	require.Nil(t, resp)
+}
+
+func TestPeerCred_Login(t *testing.T) {
+	b, storage := testBackend(t)
+	ctx := context.Background()
+
+	_, err := b.HandleRequest(ctx, &logical.Request{
+		Path:      "role/agent",
+		Operation: logical.CreateOperation,
+		Storage:   storage,
+		Data: map[string]interface{}{
+			"bound_uids":     "1000",
+			"bound_gids":     "100",
+			"token_policies": "agent",
+		},
+	})
+	require.NoError(t, err)
+
+	login := func(creds *logical.PeerCredentials) (*logical.Response, error) {
+		return b.HandleRequest(ctx, &logical.Request{
+			Path:       "login",
+			Operation:  logical.UpdateOperation,
+			Storage:    storage,
+			Connection: &logical.Connection{PeerCredentials: creds},
+			Data: map[string]interface{}{
+				"role": "agent",
+			},
+		})
+	}
+
+	resp, err := login(&logical.PeerCredentials{PID: 42, UID: 1000, GID: 100})
+	require.NoError(t, err)
+	require.NotNil(t, resp.Auth)
+	require.Equal(t, "1000", resp.Auth.Alias.Name)
+	require.Equal(t, []string{"agent"}, resp.Auth.Policies)
+	require.Equal(t, "42", resp.Auth.Metadata["pid"])
+
+	// Both bindings must match.
+	_, err = login(&logical.PeerCredentials{UID: 1000, GID: 0})
+	require.ErrorIs(t, err, logical.ErrPermissionDenied)
+
+	_, err = login(&logical.PeerCredentials{UID: 0, GID: 100})
+	require.ErrorIs(t, err, logical.ErrPermissionDenied)
+
+	// Requests not received over a unix socket carry no peer credentials.
+	_, err = login(nil)
+	require.ErrorIs(t, err, logical.ErrPermissionDenied)
+}
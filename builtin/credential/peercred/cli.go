@@ -0,0 +1,66 @@
+package peercred
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/openbao/openbao/api/v2"
+)
+
+type CLIHandler struct{}
+
+func (h *CLIHandler) Auth(c *api.Client, m map[string]string, nonInteractive bool) (*api.Secret, error) {
+	var data struct {
+		Role  string `mapstructure:"role"`
+		Mount string `mapstructure:"mount"`
+	}
+	if err := mapstructure.WeakDecode(m, &data); err != nil {
+		return nil, err
+	}
+
+	if data.Role == "" {
+		return nil, fmt.Errorf("'role' must be specified")
+	}
+	if data.Mount == "" {
+		data.Mount = "peercred"
+	}
+
+	path := fmt.Sprintf("auth/%s/login", data.Mount)
+	secret, err := c.Logical().Write(path, map[string]interface{}{
+		"role": data.Role,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("empty response from credential provider")
+	}
+
+	return secret, nil
+}
+
+func (h *CLIHandler) Help() string {
+	help := `
+Usage: bao login -method=peercred [CONFIG K=V...]
+
+  The peercred auth method allows processes on the same host as the server
+  to authenticate using their user and group IDs. The client must connect
+  to the server over a "unix" listener, e.g. by setting BAO_ADDR to
+  "unix:///run/openbao.sock".
+
+  Authenticate against the "agent" role:
+
+      $ bao login -method=peercred role=agent
+
+Configuration:
+
+  mount=<string>
+      Path where the peercred auth method is mounted. Defaults to "peercred".
+
+  role=<string>
+      Name of the role to log in to.
+`
+
+	return strings.TrimSpace(help)
+}
@@ -0,0 +1,139 @@
+package peercred
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/policyutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+func pathLogin(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "login$",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPeerCred,
+			OperationVerb:   "login",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"role": {
+				Type:        framework.TypeString,
+				Description: "Name of the role to log in to.",
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.UpdateOperation:         b.pathLogin,
+			logical.AliasLookaheadOperation: b.pathLoginAliasLookahead,
+		},
+
+		HelpSynopsis:    pathLoginSyn,
+		HelpDescription: pathLoginDesc,
+	}
+}
+
+func peerCredentials(req *logical.Request) (*logical.PeerCredentials, error) {
+	if req.Connection == nil || req.Connection.PeerCredentials == nil {
+		return nil, errors.New("no peer credentials available; requests must be made over a unix listener")
+	}
+	return req.Connection.PeerCredentials, nil
+}
+
+func (b *backend) pathLoginAliasLookahead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	creds, err := peerCredentials(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Auth: &logical.Auth{
+			Alias: &logical.Alias{
+				Name: strconv.FormatUint(uint64(creds.UID), 10),
+			},
+		},
+	}, nil
+}
+
+func (b *backend) pathLogin(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	creds, err := peerCredentials(req)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrPermissionDenied
+	}
+
+	roleName := d.Get("role").(string)
+	if roleName == "" {
+		return logical.ErrorResponse("missing role"), logical.ErrInvalidRequest
+	}
+
+	role, err := b.role(ctx, req.Storage, roleName)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil || !role.matches(creds) {
+		return logical.ErrorResponse("peer is not allowed to log in to this role"), logical.ErrPermissionDenied
+	}
+
+	uid := strconv.FormatUint(uint64(creds.UID), 10)
+	auth := &logical.Auth{
+		Metadata: map[string]string{
+			"role": roleName,
+			"uid":  uid,
+			"gid":  strconv.FormatUint(uint64(creds.GID), 10),
+			"pid":  strconv.FormatInt(int64(creds.PID), 10),
+		},
+		InternalData: map[string]interface{}{
+			"role": roleName,
+		},
+		DisplayName: fmt.Sprintf("%s-%s", roleName, uid),
+		Alias: &logical.Alias{
+			Name: uid,
+			Metadata: map[string]string{
+				"role": roleName,
+			},
+		},
+	}
+	if err := role.PopulateTokenAuth(auth, req); err != nil {
+		return nil, fmt.Errorf("failed to populate auth information: %w", err)
+	}
+
+	return &logical.Response{
+		Auth: auth,
+	}, nil
+}
+
+func (b *backend) pathLoginRenew(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	roleName, _ := req.Auth.InternalData["role"].(string)
+	role, err := b.role(ctx, req.Storage, roleName)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		// Role no longer exists, do not renew
+		return nil, nil
+	}
+
+	if !policyutil.EquivalentPolicies(role.TokenPolicies, req.Auth.TokenPolicies) {
+		return nil, fmt.Errorf("policies have changed, not renewing")
+	}
+
+	resp := &logical.Response{Auth: req.Auth}
+	resp.Auth.Period = role.TokenPeriod
+	resp.Auth.TTL = role.TokenTTL
+	resp.Auth.MaxTTL = role.TokenMaxTTL
+	return resp, nil
+}
+
+const pathLoginSyn = `
+Log in using the credentials of the calling process.
+`
+
+const pathLoginDesc = `
+This endpoint authenticates the calling process against the given role using
+the user and group IDs reported by the kernel for the Unix domain socket
+connection the request was received on. No secret needs to be supplied.
+`
@@ -0,0 +1,241 @@
+package peercred
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/tokenutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const rolePrefix = "role/"
+
+func pathRolesList(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "role/?",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPeerCred,
+			OperationSuffix: "roles",
+			Navigation:      true,
+			ItemType:        "Role",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"after": {
+				Type:        framework.TypeString,
+				Description: `Optional entry to list begin listing after, not required to exist.`,
+			},
+			"limit": {
+				Type:        framework.TypeInt,
+				Description: `Optional number of entries to return; defaults to all entries.`,
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ListOperation: b.pathRoleList,
+		},
+
+		HelpSynopsis:    pathRoleHelpSyn,
+		HelpDescription: pathRoleHelpDesc,
+	}
+}
+
+func pathRoles(b *backend) *framework.Path {
+	p := &framework.Path{
+		Pattern: "role/" + framework.GenericNameRegex("name"),
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPeerCred,
+			OperationSuffix: "role",
+			Action:          "Create",
+			ItemType:        "Role",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Name of the role.",
+			},
+
+			"bound_uids": {
+				Type:        framework.TypeCommaIntSlice,
+				Description: "User IDs allowed to log in to this role. If bound_gids is also set, the primary group ID must match too.",
+			},
+
+			"bound_gids": {
+				Type:        framework.TypeCommaIntSlice,
+				Description: "Primary group IDs allowed to log in to this role. If bound_uids is also set, the user ID must match too.",
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.DeleteOperation: b.pathRoleDelete,
+			logical.ReadOperation:   b.pathRoleRead,
+			logical.UpdateOperation: b.pathRoleWrite,
+			logical.CreateOperation: b.pathRoleWrite,
+		},
+
+		ExistenceCheck: b.roleExistenceCheck,
+
+		HelpSynopsis:    pathRoleHelpSyn,
+		HelpDescription: pathRoleHelpDesc,
+	}
+
+	tokenutil.AddTokenFields(p.Fields)
+	return p
+}
+
+type roleEntry struct {
+	tokenutil.TokenParams
+
+	BoundUIDs []int `json:"bound_uids"`
+	BoundGIDs []int `json:"bound_gids"`
+}
+
+// matches reports whether the given peer is allowed to log in to the role.
+// Every configured binding must match.
+func (r *roleEntry) matches(creds *logical.PeerCredentials) bool {
+	if len(r.BoundUIDs) > 0 && !containsID(r.BoundUIDs, creds.UID) {
+		return false
+	}
+	if len(r.BoundGIDs) > 0 && !containsID(r.BoundGIDs, creds.GID) {
+		return false
+	}
+	return true
+}
+
+func containsID(ids []int, id uint32) bool {
+	for _, v := range ids {
+		if v >= 0 && uint32(v) == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *backend) roleExistenceCheck(ctx context.Context, req *logical.Request, d *framework.FieldData) (bool, error) {
+	role, err := b.role(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return false, err
+	}
+
+	return role != nil, nil
+}
+
+func (b *backend) role(ctx context.Context, s logical.Storage, name string) (*roleEntry, error) {
+	if name == "" {
+		return nil, errors.New("missing role name")
+	}
+
+	entry, err := s.Get(ctx, rolePrefix+strings.ToLower(name))
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var result roleEntry
+	if err := entry.DecodeJSON(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (b *backend) pathRoleList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	after := d.Get("after").(string)
+	limit := d.Get("limit").(int)
+	if limit <= 0 {
+		limit = -1
+	}
+
+	roles, err := req.Storage.ListPage(ctx, rolePrefix, after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return logical.ListResponse(roles), nil
+}
+
+func (b *backend) pathRoleDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if err := req.Storage.Delete(ctx, rolePrefix+strings.ToLower(d.Get("name").(string))); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (b *backend) pathRoleRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	role, err := b.role(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, nil
+	}
+
+	data := map[string]interface{}{
+		"bound_uids": role.BoundUIDs,
+		"bound_gids": role.BoundGIDs,
+	}
+	role.PopulateTokenData(data)
+
+	return &logical.Response{
+		Data: data,
+	}, nil
+}
+
+func (b *backend) pathRoleWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := strings.ToLower(d.Get("name").(string))
+	role, err := b.role(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	// Due to existence check, role will only be nil if it's a create operation
+	if role == nil {
+		role = &roleEntry{}
+	}
+
+	if err := role.ParseTokenFields(req, d); err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	if raw, ok := d.GetOk("bound_uids"); ok {
+		role.BoundUIDs = raw.([]int)
+	}
+	if raw, ok := d.GetOk("bound_gids"); ok {
+		role.BoundGIDs = raw.([]int)
+	}
+
+	if len(role.BoundUIDs) == 0 && len(role.BoundGIDs) == 0 {
+		return logical.ErrorResponse("at least one of bound_uids or bound_gids must be set"), logical.ErrInvalidRequest
+	}
+	for _, id := range append(append([]int{}, role.BoundUIDs...), role.BoundGIDs...) {
+		if id < 0 {
+			return logical.ErrorResponse(fmt.Sprintf("invalid negative id %d", id)), logical.ErrInvalidRequest
+		}
+	}
+
+	entry, err := logical.StorageEntryJSON(rolePrefix+name, role)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, req.Storage.Put(ctx, entry)
+}
+
+const pathRoleHelpSyn = `
+Manage roles that local processes can log in to.
+`
+
+const pathRoleHelpDesc = `
+This endpoint allows you to create, read, update, and delete roles binding
+the user and group IDs of local processes to token parameters. A process
+must match all the bindings set: when both bound_uids and bound_gids are
+set, both its user ID and its primary group ID must be listed.
+`
@@ -0,0 +1,64 @@
+package spiffe
+
+import (
+	"context"
+	"sync"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const operationPrefixSPIFFE = "spiffe"
+
+func Factory(ctx context.Context, conf *logical.BackendConfig) (logical.Backend, error) {
+	b := Backend()
+	if err := b.Setup(ctx, conf); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func Backend() *backend {
+	var b backend
+	b.Backend = &framework.Backend{
+		Help: backendHelp,
+
+		PathsSpecial: &logical.Paths{
+			Unauthenticated: []string{
+				"login",
+			},
+		},
+
+		Paths: []*framework.Path{
+			pathTrustDomainRefresh(&b),
+			pathTrustDomains(&b),
+			pathTrustDomainsList(&b),
+			pathRoles(&b),
+			pathRolesList(&b),
+			pathLogin(&b),
+		},
+
+		AuthRenew:    b.pathLoginRenew,
+		PeriodicFunc: b.refreshBundles,
+		BackendType:  logical.TypeCredential,
+	}
+
+	return &b
+}
+
+type backend struct {
+	*framework.Backend
+
+	// refreshLock serializes bundle endpoint refreshes of trust domains.
+	refreshLock sync.Mutex
+}
+
+const backendHelp = `
+The "spiffe" credential provider allows workloads to authenticate using an
+X.509 SPIFFE Verifiable Identity Document (SVID) presented as the TLS client
+certificate of the connection.
+
+Trust domains hold the trust bundles SVIDs are verified against. Bundles
+may be configured statically or fetched from a SPIFFE bundle endpoint to
+federate with other trust domains. Roles map SPIFFE IDs to token parameters.
+`
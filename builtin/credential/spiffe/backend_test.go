@@ -0,0 +1,258 @@
+package spiffe
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/stretchr/testify/require"
+)
+
+func testBackend(t *testing.T) (*backend, logical.Storage) {
+	t.Helper()
+
+	storage := &logical.InmemStorage{}
+	config := logical.TestBackendConfig()
+	config.StorageView = storage
+
+	b, err := Factory(context.Background(), config)
+	require.NoError(t, err)
+
+	return b.(*backend), storage
+}
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "SPIFFE CA"},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{cert: cert, key: key}
+}
+
+func (ca *testCA) pem() string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}))
+}
+
+func (ca *testCA) svid(t *testing.T, id string) *x509.Certificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	u, err := url.Parse(id)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		URIs:         []*url.URL{u},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return cert
+}
+
+func testLogin(b *backend, storage logical.Storage, role string, certs ...*x509.Certificate) (*logical.Response, error) {
+	return b.HandleRequest(context.Background(), &logical.Request{
+		Path:      "login",
+		Operation: logical.UpdateOperation,
+		Storage:   storage,
+		Connection: &logical.Connection{
+			ConnState: &tls.ConnectionState{PeerCertificates: certs},
+		},
+		Data: map[string]interface{}{
+			"role": role,
+		},
+	})
+}
+
+func TestSPIFFE_Login(t *testing.T) {
+	b, storage := testBackend(t)
+	ctx := context.Background()
+	ca := newTestCA(t)
+
+	write := func(path string, data map[string]interface{}) {
+		t.Helper()
+		resp, err := b.HandleRequest(ctx, &logical.Request{
+			Path:      path,
+			Operation: logical.CreateOperation,
+			Storage:   storage,
+			Data:      data,
+		})
+		require.NoError(t, err)
+		require.Nil(t, resp)
+	}
+
+	write("trust-domain/example.org", map[string]interface{}{
+		"trust_bundle_pem": ca.pem(),
+	})
+	write("role/prod", map[string]interface{}{
+		"bound_spiffe_ids": "spiffe://example.org/ns/prod/*",
+		"token_policies":   "prod",
+	})
+	write("role/web", map[string]interface{}{
+		"bound_spiffe_ids": "spiffe://example.org/ns/prod/sa/web",
+		"token_policies":   "web",
+	})
+
+	// Without a role, the first matching role is used.
+	resp, err := testLogin(b, storage, "", ca.svid(t, "spiffe://example.org/ns/prod/sa/web"))
+	require.NoError(t, err)
+	require.Equal(t, "spiffe://example.org/ns/prod/sa/web", resp.Auth.Alias.Name)
+	require.Equal(t, "prod", resp.Auth.Metadata["role"])
+	require.Equal(t, []string{"prod"}, resp.Auth.Policies)
+
+	resp, err = testLogin(b, storage, "web", ca.svid(t, "spiffe://example.org/ns/prod/sa/web"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"web"}, resp.Auth.Policies)
+
+	// The SPIFFE ID must match the role.
+	_, err = testLogin(b, storage, "web", ca.svid(t, "spiffe://example.org/ns/prod/sa/db"))
+	require.ErrorIs(t, err, logical.ErrPermissionDenied)
+
+	// SVIDs of unknown trust domains are rejected.
+	_, err = testLogin(b, storage, "", ca.svid(t, "spiffe://other.org/ns/prod/sa/web"))
+	require.ErrorIs(t, err, logical.ErrPermissionDenied)
+
+	// SVIDs must be signed by the trust bundle of their trust domain.
+	_, err = testLogin(b, storage, "", newTestCA(t).svid(t, "spiffe://example.org/ns/prod/sa/web"))
+	require.ErrorIs(t, err, logical.ErrPermissionDenied)
+
+	// A client certificate is required.
+	_, err = testLogin(b, storage, "")
+	require.ErrorIs(t, err, logical.ErrPermissionDenied)
+}
+
+func TestSPIFFE_BundleEndpoint(t *testing.T) {
+	b, storage := testBackend(t)
+	ctx := context.Background()
+	ca := newTestCA(t)
+
+	var fail atomic.Bool
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]interface{}{
+				{
+					"use": "x509-svid",
+					"kty": "EC",
+					"x5c": []string{base64.StdEncoding.EncodeToString(ca.cert.Raw)},
+				},
+			},
+			"spiffe_refresh_hint": 60,
+		})
+	}))
+	defer server.Close()
+	serverCA := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	resp, err := b.HandleRequest(ctx, &logical.Request{
+		Path:      "trust-domain/federated.org",
+		Operation: logical.CreateOperation,
+		Storage:   storage,
+		Data: map[string]interface{}{
+			"bundle_endpoint_url":    server.URL,
+			"bundle_endpoint_ca_pem": serverCA,
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, resp)
+
+	resp, err = b.HandleRequest(ctx, &logical.Request{
+		Path:      "role/federated",
+		Operation: logical.CreateOperation,
+		Storage:   storage,
+		Data: map[string]interface{}{
+			"bound_spiffe_ids": "spiffe://federated.org/*",
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, resp)
+
+	resp, err = testLogin(b, storage, "federated", ca.svid(t, "spiffe://federated.org/workload"))
+	require.NoError(t, err)
+	require.NotNil(t, resp.Auth)
+
+	// Refreshes are only attempted once due, and failures keep the
+	// previous bundle.
+	td, err := b.trustDomain(ctx, storage, "federated.org")
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, td.RefreshHint)
+	td.LastRefresh = time.Now().Add(-2 * time.Minute)
+	require.NoError(t, b.putTrustDomain(ctx, storage, "federated.org", td))
+
+	fail.Store(true)
+	require.NoError(t, b.refreshBundles(ctx, &logical.Request{Storage: storage}))
+	td, err = b.trustDomain(ctx, storage, "federated.org")
+	require.NoError(t, err)
+	require.Contains(t, td.LastRefreshError, "500")
+	require.Len(t, td.FetchedCertificates, 1)
+
+	resp, err = b.HandleRequest(ctx, &logical.Request{
+		Path:      "trust-domain/federated.org/refresh",
+		Operation: logical.UpdateOperation,
+		Storage:   storage,
+	})
+	require.NoError(t, err)
+	require.True(t, resp.IsError())
+
+	fail.Store(false)
+	resp, err = b.HandleRequest(ctx, &logical.Request{
+		Path:      "trust-domain/federated.org/refresh",
+		Operation: logical.UpdateOperation,
+		Storage:   storage,
+	})
+	require.NoError(t, err)
+	require.Nil(t, resp)
+
+	resp, err = b.HandleRequest(ctx, &logical.Request{
+		Path:      "trust-domain/federated.org",
+		Operation: logical.ReadOperation,
+		Storage:   storage,
+	})
+	require.NoError(t, err)
+	require.Equal(t, ca.pem(), resp.Data["fetched_bundle_pem"])
+	require.Empty(t, resp.Data["last_refresh_error"])
+}
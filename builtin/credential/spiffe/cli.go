@@ -0,0 +1,64 @@
+package spiffe
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/openbao/openbao/api/v2"
+)
+
+type CLIHandler struct{}
+
+func (h *CLIHandler) Auth(c *api.Client, m map[string]string, nonInteractive bool) (*api.Secret, error) {
+	var data struct {
+		Role  string `mapstructure:"role"`
+		Mount string `mapstructure:"mount"`
+	}
+	if err := mapstructure.WeakDecode(m, &data); err != nil {
+		return nil, err
+	}
+
+	if data.Mount == "" {
+		data.Mount = "spiffe"
+	}
+
+	path := fmt.Sprintf("auth/%s/login", data.Mount)
+	secret, err := c.Logical().Write(path, map[string]interface{}{
+		"role": data.Role,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("empty response from credential provider")
+	}
+
+	return secret, nil
+}
+
+func (h *CLIHandler) Help() string {
+	help := `
+Usage: bao login -method=spiffe [CONFIG K=V...]
+
+  The spiffe auth method allows workloads to authenticate using an X.509
+  SVID passed as the TLS client certificate of the request. The -client-cert
+  and -client-key flags are included with the "bao login" command, NOT as
+  configuration to the auth method.
+
+  Authenticate using the SVID issued to the workload:
+
+      $ bao login -method=spiffe -client-cert=svid.pem -client-key=svid_key.pem
+
+Configuration:
+
+  mount=<string>
+      Path where the spiffe auth method is mounted. Defaults to "spiffe".
+
+  role=<string>
+      Name of the role to log in to. Defaults to the first role matching
+      the SPIFFE ID of the SVID.
+`
+
+	return strings.TrimSpace(help)
+}
@@ -0,0 +1,232 @@
+package spiffe
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/policyutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const spiffeScheme = "spiffe://"
+
+func pathLogin(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "login$",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixSPIFFE,
+			OperationVerb:   "login",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"role": {
+				Type:        framework.TypeString,
+				Description: "Name of the role to log in to. Defaults to the first role, in lexical order, matching the SPIFFE ID.",
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.UpdateOperation:         b.pathLogin,
+			logical.AliasLookaheadOperation: b.pathLoginAliasLookahead,
+		},
+
+		HelpSynopsis:    pathLoginSyn,
+		HelpDescription: pathLoginDesc,
+	}
+}
+
+func peerCertificates(req *logical.Request) ([]*x509.Certificate, error) {
+	if req.Connection == nil || req.Connection.ConnState == nil || len(req.Connection.ConnState.PeerCertificates) == 0 {
+		return nil, errors.New("no client certificate presented; an X.509 SVID must be used as the TLS client certificate")
+	}
+	return req.Connection.ConnState.PeerCertificates, nil
+}
+
+// svidID returns the SPIFFE ID and trust domain name of an X.509 SVID, as
+// defined by the X.509-SVID specification.
+func svidID(cert *x509.Certificate) (string, string, error) {
+	if len(cert.URIs) != 1 {
+		return "", "", errors.New("SVID must contain exactly one URI SAN")
+	}
+	u := cert.URIs[0]
+	if u.Scheme != "spiffe" || u.Host == "" || u.User != nil || u.Port() != "" || u.RawQuery != "" || u.Fragment != "" {
+		return "", "", fmt.Errorf("invalid SPIFFE ID %q", u.String())
+	}
+	if cert.IsCA {
+		return "", "", errors.New("leaf SVID must not be a CA certificate")
+	}
+	return u.String(), strings.ToLower(u.Host), nil
+}
+
+// verifySVID verifies the given certificate chain against the trust bundle
+// of the trust domain of its leaf and returns its SPIFFE ID and trust domain
+// name.
+func (b *backend) verifySVID(ctx context.Context, s logical.Storage, certs []*x509.Certificate) (string, string, error) {
+	leaf := certs[0]
+	id, tdName, err := svidID(leaf)
+	if err != nil {
+		return "", "", err
+	}
+
+	td, err := b.trustDomain(ctx, s, tdName)
+	if err != nil {
+		return "", "", err
+	}
+	if td == nil {
+		return "", "", fmt.Errorf("trust domain %q is not configured", tdName)
+	}
+
+	roots, err := td.roots()
+	if err != nil {
+		return "", "", err
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return "", "", fmt.Errorf("failed to verify SVID: %w", err)
+	}
+
+	return id, tdName, nil
+}
+
+// matchingRole returns the name of the role the given SPIFFE ID may log in
+// to. If no role name is given, the first matching role is used.
+func (b *backend) matchingRole(ctx context.Context, s logical.Storage, roleName, id string) (string, *roleEntry, error) {
+	if roleName != "" {
+		role, err := b.role(ctx, s, roleName)
+		if err != nil || role == nil || !role.matches(id) {
+			return "", nil, err
+		}
+		return roleName, role, nil
+	}
+
+	names, err := s.List(ctx, rolePrefix)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, name := range names {
+		role, err := b.role(ctx, s, name)
+		if err != nil {
+			return "", nil, err
+		}
+		if role != nil && role.matches(id) {
+			return name, role, nil
+		}
+	}
+	return "", nil, nil
+}
+
+func (b *backend) pathLoginAliasLookahead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	certs, err := peerCertificates(req)
+	if err != nil {
+		return nil, err
+	}
+	id, _, err := svidID(certs[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Auth: &logical.Auth{
+			Alias: &logical.Alias{
+				Name: id,
+			},
+		},
+	}, nil
+}
+
+func (b *backend) pathLogin(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	certs, err := peerCertificates(req)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrPermissionDenied
+	}
+
+	id, tdName, err := b.verifySVID(ctx, req.Storage, certs)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrPermissionDenied
+	}
+
+	roleName, role, err := b.matchingRole(ctx, req.Storage, d.Get("role").(string), id)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return logical.ErrorResponse("SPIFFE ID %q is not allowed to log in to any matching role", id), logical.ErrPermissionDenied
+	}
+
+	auth := &logical.Auth{
+		Metadata: map[string]string{
+			"role":         roleName,
+			"spiffe_id":    id,
+			"trust_domain": tdName,
+		},
+		InternalData: map[string]interface{}{
+			"role":      roleName,
+			"spiffe_id": id,
+		},
+		DisplayName: id,
+		Alias: &logical.Alias{
+			Name: id,
+			Metadata: map[string]string{
+				"role":         roleName,
+				"trust_domain": tdName,
+			},
+		},
+	}
+	if err := role.PopulateTokenAuth(auth, req); err != nil {
+		return nil, fmt.Errorf("failed to populate auth information: %w", err)
+	}
+
+	return &logical.Response{
+		Auth: auth,
+	}, nil
+}
+
+func (b *backend) pathLoginRenew(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	roleName, _ := req.Auth.InternalData["role"].(string)
+	role, err := b.role(ctx, req.Storage, roleName)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		// Role no longer exists, do not renew
+		return nil, nil
+	}
+
+	id, _ := req.Auth.InternalData["spiffe_id"].(string)
+	if !role.matches(id) {
+		return nil, fmt.Errorf("SPIFFE ID is no longer bound to the role, not renewing")
+	}
+	if !policyutil.EquivalentPolicies(role.TokenPolicies, req.Auth.TokenPolicies) {
+		return nil, fmt.Errorf("policies have changed, not renewing")
+	}
+
+	resp := &logical.Response{Auth: req.Auth}
+	resp.Auth.Period = role.TokenPeriod
+	resp.Auth.TTL = role.TokenTTL
+	resp.Auth.MaxTTL = role.TokenMaxTTL
+	return resp, nil
+}
+
+const pathLoginSyn = `
+Log in using an X.509 SVID.
+`
+
+const pathLoginDesc = `
+This endpoint authenticates the X.509 SVID presented as the TLS client
+certificate of the request. The SVID is verified against the trust bundle of
+its trust domain, and its SPIFFE ID is matched against the bindings of the
+given role, or of all roles if none is given.
+`
@@ -0,0 +1,216 @@
+package spiffe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/strutil"
+	"github.com/openbao/openbao/sdk/v2/helper/tokenutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const rolePrefix = "role/"
+
+func pathRolesList(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "role/?",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixSPIFFE,
+			OperationSuffix: "roles",
+			Navigation:      true,
+			ItemType:        "Role",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"after": {
+				Type:        framework.TypeString,
+				Description: `Optional entry to list begin listing after, not required to exist.`,
+			},
+			"limit": {
+				Type:        framework.TypeInt,
+				Description: `Optional number of entries to return; defaults to all entries.`,
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ListOperation: b.pathRoleList,
+		},
+
+		HelpSynopsis:    pathRoleHelpSyn,
+		HelpDescription: pathRoleHelpDesc,
+	}
+}
+
+func pathRoles(b *backend) *framework.Path {
+	p := &framework.Path{
+		Pattern: "role/" + framework.GenericNameRegex("name"),
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixSPIFFE,
+			OperationSuffix: "role",
+			Action:          "Create",
+			ItemType:        "Role",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Name of the role.",
+			},
+
+			"bound_spiffe_ids": {
+				Type:        framework.TypeCommaStringSlice,
+				Description: `SPIFFE IDs allowed to log in to this role. Supports globbing, e.g. "spiffe://example.org/ns/prod/*".`,
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.DeleteOperation: b.pathRoleDelete,
+			logical.ReadOperation:   b.pathRoleRead,
+			logical.UpdateOperation: b.pathRoleWrite,
+			logical.CreateOperation: b.pathRoleWrite,
+		},
+
+		ExistenceCheck: b.roleExistenceCheck,
+
+		HelpSynopsis:    pathRoleHelpSyn,
+		HelpDescription: pathRoleHelpDesc,
+	}
+
+	tokenutil.AddTokenFields(p.Fields)
+	return p
+}
+
+type roleEntry struct {
+	tokenutil.TokenParams
+
+	BoundSPIFFEIDs []string `json:"bound_spiffe_ids"`
+}
+
+// matches reports whether the given SPIFFE ID is allowed to log in to the
+// role.
+func (r *roleEntry) matches(id string) bool {
+	return strutil.StrListContainsGlob(r.BoundSPIFFEIDs, id)
+}
+
+func (b *backend) roleExistenceCheck(ctx context.Context, req *logical.Request, d *framework.FieldData) (bool, error) {
+	role, err := b.role(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return false, err
+	}
+
+	return role != nil, nil
+}
+
+func (b *backend) role(ctx context.Context, s logical.Storage, name string) (*roleEntry, error) {
+	if name == "" {
+		return nil, errors.New("missing role name")
+	}
+
+	entry, err := s.Get(ctx, rolePrefix+strings.ToLower(name))
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var result roleEntry
+	if err := entry.DecodeJSON(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (b *backend) pathRoleList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	after := d.Get("after").(string)
+	limit := d.Get("limit").(int)
+	if limit <= 0 {
+		limit = -1
+	}
+
+	roles, err := req.Storage.ListPage(ctx, rolePrefix, after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return logical.ListResponse(roles), nil
+}
+
+func (b *backend) pathRoleDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if err := req.Storage.Delete(ctx, rolePrefix+strings.ToLower(d.Get("name").(string))); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (b *backend) pathRoleRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	role, err := b.role(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, nil
+	}
+
+	data := map[string]interface{}{
+		"bound_spiffe_ids": role.BoundSPIFFEIDs,
+	}
+	role.PopulateTokenData(data)
+
+	return &logical.Response{
+		Data: data,
+	}, nil
+}
+
+func (b *backend) pathRoleWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := strings.ToLower(d.Get("name").(string))
+	role, err := b.role(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	// Due to existence check, role will only be nil if it's a create operation
+	if role == nil {
+		role = &roleEntry{}
+	}
+
+	if err := role.ParseTokenFields(req, d); err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	if raw, ok := d.GetOk("bound_spiffe_ids"); ok {
+		role.BoundSPIFFEIDs = raw.([]string)
+	}
+
+	if len(role.BoundSPIFFEIDs) == 0 {
+		return logical.ErrorResponse("bound_spiffe_ids must be set"), logical.ErrInvalidRequest
+	}
+	for _, id := range role.BoundSPIFFEIDs {
+		if !strings.HasPrefix(id, spiffeScheme) {
+			return logical.ErrorResponse(fmt.Sprintf("invalid SPIFFE ID %q", id)), logical.ErrInvalidRequest
+		}
+	}
+
+	entry, err := logical.StorageEntryJSON(rolePrefix+name, role)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, req.Storage.Put(ctx, entry)
+}
+
+const pathRoleHelpSyn = `
+Manage roles that workloads can log in to.
+`
+
+const pathRoleHelpDesc = `
+This endpoint allows you to create, read, update, and delete roles mapping
+SPIFFE IDs to token parameters. A workload presenting a valid SVID whose
+SPIFFE ID matches one of bound_spiffe_ids may log in to the role.
+`
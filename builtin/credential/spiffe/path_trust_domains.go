@@ -0,0 +1,517 @@
+package spiffe
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	cleanhttp "github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/go-multierror"
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const (
+	trustDomainPrefix = "trust-domain/"
+
+	// defaultRefreshInterval is used for bundle endpoints that do not
+	// provide a refresh hint when no refresh interval is configured.
+	defaultRefreshInterval = 5 * time.Minute
+
+	// maxBundleSize limits the size of bundles fetched from bundle
+	// endpoints.
+	maxBundleSize = 1 << 20
+)
+
+// trustDomainNameRegex matches the characters allowed in a trust domain
+// name by the SPIFFE ID specification.
+var trustDomainNameRegex = regexp.MustCompile(`^[a-z0-9._-]+$`)
+
+func pathTrustDomainsList(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "trust-domain/?",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixSPIFFE,
+			OperationSuffix: "trust-domains",
+			Navigation:      true,
+			ItemType:        "Trust Domain",
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ListOperation: b.pathTrustDomainList,
+		},
+
+		HelpSynopsis:    pathTrustDomainHelpSyn,
+		HelpDescription: pathTrustDomainHelpDesc,
+	}
+}
+
+func pathTrustDomains(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "trust-domain/" + framework.GenericNameRegex("name"),
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixSPIFFE,
+			OperationSuffix: "trust-domain",
+			Action:          "Create",
+			ItemType:        "Trust Domain",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Name of the trust domain, e.g. 'example.org'.",
+			},
+
+			"trust_bundle_pem": {
+				Type:        framework.TypeString,
+				Description: "PEM encoded X.509 authorities of the trust domain.",
+			},
+
+			"bundle_endpoint_url": {
+				Type:        framework.TypeString,
+				Description: "HTTPS URL of the SPIFFE bundle endpoint of the trust domain, using the https_web profile.",
+			},
+
+			"bundle_endpoint_ca_pem": {
+				Type:        framework.TypeString,
+				Description: "PEM encoded CA certificates used to verify the bundle endpoint. Defaults to the system roots.",
+			},
+
+			"refresh_interval": {
+				Type:        framework.TypeDurationSecond,
+				Description: "Interval at which the bundle is refreshed from the bundle endpoint. Defaults to the refresh hint of the bundle, or 5 minutes.",
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.DeleteOperation: b.pathTrustDomainDelete,
+			logical.ReadOperation:   b.pathTrustDomainRead,
+			logical.UpdateOperation: b.pathTrustDomainWrite,
+			logical.CreateOperation: b.pathTrustDomainWrite,
+		},
+
+		ExistenceCheck: b.trustDomainExistenceCheck,
+
+		HelpSynopsis:    pathTrustDomainHelpSyn,
+		HelpDescription: pathTrustDomainHelpDesc,
+	}
+}
+
+func pathTrustDomainRefresh(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "trust-domain/" + framework.GenericNameRegex("name") + "/refresh",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixSPIFFE,
+			OperationVerb:   "refresh",
+			OperationSuffix: "trust-domain",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Name of the trust domain.",
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.UpdateOperation: b.pathTrustDomainRefreshWrite,
+		},
+
+		HelpSynopsis:    pathTrustDomainRefreshHelpSyn,
+		HelpDescription: pathTrustDomainRefreshHelpDesc,
+	}
+}
+
+type trustDomainEntry struct {
+	TrustBundlePEM      string        `json:"trust_bundle_pem"`
+	BundleEndpointURL   string        `json:"bundle_endpoint_url"`
+	BundleEndpointCAPEM string        `json:"bundle_endpoint_ca_pem"`
+	RefreshInterval     time.Duration `json:"refresh_interval"`
+
+	// The following fields hold the bundle last fetched from the bundle
+	// endpoint. A failed refresh keeps the previous bundle.
+	FetchedCertificates [][]byte      `json:"fetched_certificates"`
+	RefreshHint         time.Duration `json:"refresh_hint"`
+	LastRefresh         time.Time     `json:"last_refresh"`
+	LastRefreshError    string        `json:"last_refresh_error"`
+}
+
+// nextRefresh returns the time at which the bundle should be fetched again
+// from the bundle endpoint.
+func (e *trustDomainEntry) nextRefresh() time.Time {
+	interval := e.RefreshInterval
+	if interval == 0 {
+		interval = e.RefreshHint
+	}
+	if interval == 0 {
+		interval = defaultRefreshInterval
+	}
+	return e.LastRefresh.Add(interval)
+}
+
+// roots returns the X.509 authorities of the trust domain, combining the
+// static and fetched bundles.
+func (e *trustDomainEntry) roots() (*x509.CertPool, error) {
+	pool := x509.NewCertPool()
+	if e.TrustBundlePEM != "" && !pool.AppendCertsFromPEM([]byte(e.TrustBundlePEM)) {
+		return nil, errors.New("failed to parse trust bundle")
+	}
+	for _, der := range e.FetchedCertificates {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse fetched bundle: %w", err)
+		}
+		pool.AddCert(cert)
+	}
+	return pool, nil
+}
+
+func (e *trustDomainEntry) fetchedBundlePEM() string {
+	var sb strings.Builder
+	for _, der := range e.FetchedCertificates {
+		sb.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	}
+	return sb.String()
+}
+
+func (b *backend) trustDomainExistenceCheck(ctx context.Context, req *logical.Request, d *framework.FieldData) (bool, error) {
+	entry, err := b.trustDomain(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return false, err
+	}
+
+	return entry != nil, nil
+}
+
+func (b *backend) trustDomain(ctx context.Context, s logical.Storage, name string) (*trustDomainEntry, error) {
+	if name == "" {
+		return nil, errors.New("missing trust domain name")
+	}
+
+	entry, err := s.Get(ctx, trustDomainPrefix+strings.ToLower(name))
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var result trustDomainEntry
+	if err := entry.DecodeJSON(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (b *backend) putTrustDomain(ctx context.Context, s logical.Storage, name string, td *trustDomainEntry) error {
+	entry, err := logical.StorageEntryJSON(trustDomainPrefix+strings.ToLower(name), td)
+	if err != nil {
+		return err
+	}
+
+	return s.Put(ctx, entry)
+}
+
+func (b *backend) pathTrustDomainList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	names, err := req.Storage.List(ctx, trustDomainPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return logical.ListResponse(names), nil
+}
+
+func (b *backend) pathTrustDomainDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if err := req.Storage.Delete(ctx, trustDomainPrefix+strings.ToLower(d.Get("name").(string))); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (b *backend) pathTrustDomainRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	td, err := b.trustDomain(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return nil, err
+	}
+	if td == nil {
+		return nil, nil
+	}
+
+	data := map[string]interface{}{
+		"trust_bundle_pem":       td.TrustBundlePEM,
+		"bundle_endpoint_url":    td.BundleEndpointURL,
+		"bundle_endpoint_ca_pem": td.BundleEndpointCAPEM,
+		"refresh_interval":       int64(td.RefreshInterval.Seconds()),
+	}
+	if td.BundleEndpointURL != "" {
+		data["fetched_bundle_pem"] = td.fetchedBundlePEM()
+		data["last_refresh"] = td.LastRefresh
+		data["next_refresh"] = td.nextRefresh()
+		data["last_refresh_error"] = td.LastRefreshError
+	}
+
+	return &logical.Response{
+		Data: data,
+	}, nil
+}
+
+func (b *backend) pathTrustDomainWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := strings.ToLower(d.Get("name").(string))
+	if !trustDomainNameRegex.MatchString(name) {
+		return logical.ErrorResponse("invalid trust domain name %q", name), logical.ErrInvalidRequest
+	}
+
+	b.refreshLock.Lock()
+	defer b.refreshLock.Unlock()
+
+	td, err := b.trustDomain(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	// Due to existence check, the entry will only be nil if it's a create
+	// operation
+	if td == nil {
+		td = &trustDomainEntry{}
+	}
+	previous := *td
+
+	if raw, ok := d.GetOk("trust_bundle_pem"); ok {
+		td.TrustBundlePEM = raw.(string)
+	}
+	if raw, ok := d.GetOk("bundle_endpoint_url"); ok {
+		td.BundleEndpointURL = raw.(string)
+	}
+	if raw, ok := d.GetOk("bundle_endpoint_ca_pem"); ok {
+		td.BundleEndpointCAPEM = raw.(string)
+	}
+	if raw, ok := d.GetOk("refresh_interval"); ok {
+		td.RefreshInterval = time.Duration(raw.(int)) * time.Second
+	}
+
+	if td.TrustBundlePEM == "" && td.BundleEndpointURL == "" {
+		return logical.ErrorResponse("at least one of trust_bundle_pem or bundle_endpoint_url must be set"), logical.ErrInvalidRequest
+	}
+	if td.TrustBundlePEM != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(td.TrustBundlePEM)) {
+		return logical.ErrorResponse("failed to parse trust_bundle_pem"), logical.ErrInvalidRequest
+	}
+	if td.BundleEndpointCAPEM != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(td.BundleEndpointCAPEM)) {
+		return logical.ErrorResponse("failed to parse bundle_endpoint_ca_pem"), logical.ErrInvalidRequest
+	}
+	if td.RefreshInterval < 0 {
+		return logical.ErrorResponse("refresh_interval must not be negative"), logical.ErrInvalidRequest
+	}
+
+	switch {
+	case td.BundleEndpointURL == "":
+		td.FetchedCertificates = nil
+		td.RefreshHint = 0
+		td.LastRefresh = time.Time{}
+		td.LastRefreshError = ""
+	case td.BundleEndpointURL != previous.BundleEndpointURL || td.BundleEndpointCAPEM != previous.BundleEndpointCAPEM:
+		u, err := url.Parse(td.BundleEndpointURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return logical.ErrorResponse("bundle_endpoint_url must be an https URL"), logical.ErrInvalidRequest
+		}
+
+		// Fetch the bundle right away, so that configuration errors are
+		// reported to the operator rather than in the logs.
+		if err := b.refreshTrustDomain(ctx, td); err != nil {
+			return logical.ErrorResponse("failed to fetch bundle: %s", err), logical.ErrInvalidRequest
+		}
+	}
+
+	return nil, b.putTrustDomain(ctx, req.Storage, name, td)
+}
+
+func (b *backend) pathTrustDomainRefreshWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := strings.ToLower(d.Get("name").(string))
+
+	b.refreshLock.Lock()
+	defer b.refreshLock.Unlock()
+
+	td, err := b.trustDomain(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if td == nil {
+		return logical.ErrorResponse("trust domain %q not found", name), logical.ErrInvalidRequest
+	}
+	if td.BundleEndpointURL == "" {
+		return logical.ErrorResponse("trust domain %q has no bundle endpoint", name), logical.ErrInvalidRequest
+	}
+
+	refreshErr := b.refreshTrustDomain(ctx, td)
+	if err := b.putTrustDomain(ctx, req.Storage, name, td); err != nil {
+		return nil, err
+	}
+	if refreshErr != nil {
+		return logical.ErrorResponse("failed to fetch bundle: %s", refreshErr), nil
+	}
+
+	return nil, nil
+}
+
+// refreshBundles refreshes the bundles of trust domains with a bundle
+// endpoint once they are due. It is invoked periodically.
+func (b *backend) refreshBundles(ctx context.Context, req *logical.Request) error {
+	b.refreshLock.Lock()
+	defer b.refreshLock.Unlock()
+
+	names, err := req.Storage.List(ctx, trustDomainPrefix)
+	if err != nil {
+		return err
+	}
+
+	var errs *multierror.Error
+	now := time.Now()
+	for _, name := range names {
+		td, err := b.trustDomain(ctx, req.Storage, name)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		if td == nil || td.BundleEndpointURL == "" || now.Before(td.nextRefresh()) {
+			continue
+		}
+
+		if err := b.refreshTrustDomain(ctx, td); err != nil {
+			b.Logger().Warn("failed to refresh trust bundle", "trust_domain", name, "error", err)
+		}
+		if err := b.putTrustDomain(ctx, req.Storage, name, td); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// refreshTrustDomain fetches the bundle of the given trust domain from its
+// bundle endpoint and updates the entry. On failure, the previous bundle is
+// kept and the error is recorded. The caller must hold the refresh lock and
+// persist the entry.
+func (b *backend) refreshTrustDomain(ctx context.Context, td *trustDomainEntry) error {
+	certs, hint, err := fetchBundle(ctx, td.BundleEndpointURL, td.BundleEndpointCAPEM)
+	if err != nil {
+		td.LastRefreshError = err.Error()
+		return err
+	}
+
+	td.FetchedCertificates = certs
+	td.RefreshHint = hint
+	td.LastRefresh = time.Now().UTC()
+	td.LastRefreshError = ""
+	return nil
+}
+
+// bundleDocument is the subset of a SPIFFE bundle, a JWK set, that is used
+// to verify X.509 SVIDs.
+type bundleDocument struct {
+	Keys []struct {
+		Use string   `json:"use"`
+		X5C []string `json:"x5c"`
+	} `json:"keys"`
+	RefreshHint int64 `json:"spiffe_refresh_hint"`
+}
+
+// fetchBundle fetches a SPIFFE bundle from the given bundle endpoint and
+// returns its X.509 authorities and refresh hint.
+func fetchBundle(ctx context.Context, endpoint, caPEM string) ([][]byte, time.Duration, error) {
+	transport := cleanhttp.DefaultTransport()
+	if caPEM != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(caPEM)) {
+			return nil, 0, errors.New("failed to parse bundle endpoint CA certificates")
+		}
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   30 * time.Second,
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("unexpected response code %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBundleSize))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var doc bundleDocument
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode bundle: %w", err)
+	}
+
+	var certs [][]byte
+	for _, key := range doc.Keys {
+		if key.Use != "x509-svid" {
+			continue
+		}
+		if len(key.X5C) != 1 {
+			return nil, 0, errors.New("x509-svid keys must contain exactly one certificate")
+		}
+		der, err := base64.StdEncoding.DecodeString(key.X5C[0])
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to decode x509-svid key: %w", err)
+		}
+		if _, err := x509.ParseCertificate(der); err != nil {
+			return nil, 0, fmt.Errorf("failed to parse x509-svid key: %w", err)
+		}
+		certs = append(certs, der)
+	}
+	if len(certs) == 0 {
+		return nil, 0, errors.New("bundle contains no X.509 authorities")
+	}
+
+	return certs, time.Duration(doc.RefreshHint) * time.Second, nil
+}
+
+const pathTrustDomainHelpSyn = `
+Manage the trust domains and trust bundles SVIDs are verified against.
+`
+
+const pathTrustDomainHelpDesc = `
+This endpoint allows you to create, read, update, and delete trust domains.
+The X.509 authorities of a trust domain may be configured statically using
+trust_bundle_pem, or fetched from a SPIFFE bundle endpoint to federate with
+another trust domain. Fetched bundles are refreshed periodically.
+`
+
+const pathTrustDomainRefreshHelpSyn = `
+Refresh the bundle of a trust domain from its bundle endpoint.
+`
+
+const pathTrustDomainRefreshHelpDesc = `
+This endpoint fetches the bundle of the trust domain from its bundle endpoint
+immediately, rather than waiting for the next periodic refresh.
+`
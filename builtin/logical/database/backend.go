@@ -101,6 +101,7 @@ func Backend(conf *logical.BackendConfig) *databaseBackend {
 				pathListPluginConnection(&b),
 				pathConfigurePluginConnection(&b),
 				pathResetConnection(&b),
+				pathConnectionHealth(&b),
 			},
 			pathListRoles(&b),
 			pathRoles(&b),
@@ -113,6 +114,7 @@ func Backend(conf *logical.BackendConfig) *databaseBackend {
 		},
 		Clean:             b.clean,
 		Invalidate:        b.invalidate,
+		PeriodicFunc:      b.periodicHealthCheck,
 		WALRollback:       b.walRollback,
 		WALRollbackMinAge: minRootCredRollbackAge,
 		BackendType:       logical.TypeLogical,
@@ -120,6 +122,7 @@ func Backend(conf *logical.BackendConfig) *databaseBackend {
 
 	b.logger = conf.Logger
 	b.connections = make(map[string]*dbPluginInstance)
+	b.health = make(map[string]*connectionHealth)
 	b.queueCtx, b.cancelQueueCtx = context.WithCancel(context.Background())
 	b.roleLocks = locksutil.CreateLocks()
 	return &b
@@ -178,6 +181,11 @@ type databaseBackend struct {
 	// issues with the priority queue.
 	roleLocks []*locksutil.LockEntry
 
+	// healthLock is used to synchronize access to the health map
+	healthLock sync.RWMutex
+	// health holds the status of the last health check by config name
+	health map[string]*connectionHealth
+
 	// the running gauge collection process
 	gaugeCollectionProcess     *metricsutil.GaugeCollectionProcess
 	gaugeCollectionProcessStop sync.Once
@@ -317,6 +325,7 @@ func (b *databaseBackend) invalidate(ctx context.Context, key string) {
 	case strings.HasPrefix(key, databaseConfigPath):
 		name := strings.TrimPrefix(key, databaseConfigPath)
 		b.ClearConnection(name)
+		b.healthDelete(name)
 	}
 }
 
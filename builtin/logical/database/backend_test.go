@@ -195,6 +195,8 @@ func TestBackend_config_connection(t *testing.T) {
 			"allowed_roles":                      []string{"*"},
 			"root_credentials_rotate_statements": []string{},
 			"password_policy":                    "",
+			"health_check_interval":              int64(0),
+			"verify_credentials":                 false,
 			"plugin_version":                     "",
 		}
 		configReq.Operation = logical.ReadOperation
@@ -249,6 +251,8 @@ func TestBackend_config_connection(t *testing.T) {
 			"allowed_roles":                      []string{"*"},
 			"root_credentials_rotate_statements": []string{},
 			"password_policy":                    "",
+			"health_check_interval":              int64(0),
+			"verify_credentials":                 false,
 			"plugin_version":                     "",
 		}
 		configReq.Operation = logical.ReadOperation
@@ -292,6 +296,8 @@ func TestBackend_config_connection(t *testing.T) {
 			"allowed_roles":                      []string{"flu", "barre"},
 			"root_credentials_rotate_statements": []string{},
 			"password_policy":                    "",
+			"health_check_interval":              int64(0),
+			"verify_credentials":                 false,
 			"plugin_version":                     "",
 		}
 		configReq.Operation = logical.ReadOperation
@@ -690,6 +696,8 @@ func TestBackend_connectionCrud(t *testing.T) {
 		"allowed_roles":                      []string{"plugin-role-test"},
 		"root_credentials_rotate_statements": []string(nil),
 		"password_policy":                    "",
+		"health_check_interval":              int64(0),
+		"verify_credentials":                 false,
 		"plugin_version":                     "",
 	}
 	req.Operation = logical.ReadOperation
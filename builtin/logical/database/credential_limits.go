@@ -0,0 +1,119 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	v5 "github.com/openbao/openbao/sdk/v2/database/dbplugin/v5"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const (
+	// databaseRoleCredsPath is the storage prefix under which the outstanding
+	// credentials of the roles limiting their number are tracked.
+	databaseRoleCredsPath = "role-creds/"
+
+	maxCredentialsBehaviorDeny         = "deny"
+	maxCredentialsBehaviorRevokeOldest = "revoke_oldest"
+)
+
+// trackedCredential is an outstanding credential of a dynamic role.
+type trackedCredential struct {
+	Username  string    `json:"username"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func roleCredsPrefix(roleName string) string {
+	return databaseRoleCredsPath + roleName + "/"
+}
+
+// trackCredential records a credential issued for the given role.
+func trackCredential(ctx context.Context, s logical.Storage, roleName, username string) error {
+	entry, err := logical.StorageEntryJSON(roleCredsPrefix(roleName)+username, &trackedCredential{
+		Username:  username,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	return s.Put(ctx, entry)
+}
+
+// untrackCredential removes a credential of the given role from the tracked
+// ones, if it was tracked.
+func untrackCredential(ctx context.Context, s logical.Storage, roleName, username string) error {
+	return s.Delete(ctx, roleCredsPrefix(roleName)+username)
+}
+
+// trackedCredentials returns the tracked credentials of the given role,
+// oldest first.
+func trackedCredentials(ctx context.Context, s logical.Storage, roleName string) ([]*trackedCredential, error) {
+	keys, err := s.List(ctx, roleCredsPrefix(roleName))
+	if err != nil {
+		return nil, err
+	}
+
+	creds := make([]*trackedCredential, 0, len(keys))
+	for _, key := range keys {
+		entry, err := s.Get(ctx, roleCredsPrefix(roleName)+key)
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			continue
+		}
+
+		var cred trackedCredential
+		if err := entry.DecodeJSON(&cred); err != nil {
+			return nil, err
+		}
+		creds = append(creds, &cred)
+	}
+
+	sort.SliceStable(creds, func(i, j int) bool {
+		return creds[i].CreatedAt.Before(creds[j].CreatedAt)
+	})
+	return creds, nil
+}
+
+// enforceMaxCredentials makes room for a new credential of the given role,
+// either by refusing it or by revoking its oldest credentials. It returns an
+// error response when the credential is refused. The caller must hold the
+// lock of the role.
+func (b *databaseBackend) enforceMaxCredentials(ctx context.Context, s logical.Storage, dbi *dbPluginInstance, roleName string, role *roleEntry) (*logical.Response, error) {
+	creds, err := trackedCredentials(ctx, s, roleName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outstanding credentials: %w", err)
+	}
+	if len(creds) < role.MaxCredentials {
+		return nil, nil
+	}
+
+	if role.maxCredentialsBehavior() == maxCredentialsBehaviorDeny {
+		return logical.ErrorResponse("role %q has reached its maximum of %d outstanding credentials", roleName, role.MaxCredentials), nil
+	}
+
+	for _, cred := range creds[:len(creds)-role.MaxCredentials+1] {
+		// The lease of the credential is left in place: revoking it later
+		// deletes a user which no longer exists, which plugins ignore.
+		_, err := dbi.database.DeleteUser(ctx, v5.DeleteUserRequest{
+			Username: cred.Username,
+			Statements: v5.Statements{
+				Commands: role.Statements.Revocation,
+			},
+		})
+		if err != nil {
+			b.CloseIfShutdown(dbi, err)
+			return nil, fmt.Errorf("failed to revoke the oldest credential of the role: %w", err)
+		}
+		if err := untrackCredential(ctx, s, roleName, cred.Username); err != nil {
+			return nil, err
+		}
+
+		b.Logger().Info("revoked oldest credential of role at its maximum of outstanding credentials", "role", roleName, "username", cred.Username)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,126 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	v5 "github.com/openbao/openbao/sdk/v2/database/dbplugin/v5"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackend_MaxCredentials(t *testing.T) {
+	ctx := context.Background()
+
+	setup := func(t *testing.T, behavior string) (*databaseBackend, logical.Storage, *mockNewDatabase) {
+		b, storage, mockDB := getBackend(t)
+		t.Cleanup(func() { b.Cleanup(ctx) })
+		configureDBMount(t, storage)
+
+		resp, err := b.HandleRequest(ctx, &logical.Request{
+			Operation: logical.CreateOperation,
+			Path:      "roles/limited",
+			Storage:   storage,
+			Data: map[string]interface{}{
+				"db_name":                  "mockv5",
+				"max_credentials":          2,
+				"max_credentials_behavior": behavior,
+			},
+		})
+		require.NoError(t, err)
+		require.Nil(t, resp)
+
+		for i := 1; i <= 3; i++ {
+			mockDB.On("NewUser", mock.Anything, mock.Anything).
+				Return(v5.NewUserResponse{Username: fmt.Sprintf("user-%d", i)}, nil).
+				Once()
+		}
+		mockDB.On("DeleteUser", mock.Anything, mock.Anything).
+			Return(v5.DeleteUserResponse{}, nil)
+
+		return b, storage, mockDB
+	}
+
+	issue := func(t *testing.T, b *databaseBackend, storage logical.Storage) *logical.Response {
+		t.Helper()
+		resp, err := b.HandleRequest(ctx, &logical.Request{
+			Operation: logical.ReadOperation,
+			Path:      "creds/limited",
+			Storage:   storage,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+		return resp
+	}
+
+	tracked := func(t *testing.T, storage logical.Storage) []string {
+		t.Helper()
+		creds, err := trackedCredentials(ctx, storage, "limited")
+		require.NoError(t, err)
+		var usernames []string
+		for _, cred := range creds {
+			usernames = append(usernames, cred.Username)
+		}
+		return usernames
+	}
+
+	t.Run("deny", func(t *testing.T) {
+		b, storage, mockDB := setup(t, maxCredentialsBehaviorDeny)
+
+		first := issue(t, b, storage)
+		require.False(t, first.IsError())
+		require.False(t, issue(t, b, storage).IsError())
+
+		resp := issue(t, b, storage)
+		require.True(t, resp.IsError())
+		require.Contains(t, resp.Error().Error(), "maximum of 2 outstanding credentials")
+		require.Equal(t, []string{"user-1", "user-2"}, tracked(t, storage))
+
+		// Revoking a credential makes room for a new one.
+		_, err := b.HandleRequest(ctx, &logical.Request{
+			Operation: logical.RevokeOperation,
+			Storage:   storage,
+			Secret:    first.Secret,
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{"user-2"}, tracked(t, storage))
+
+		require.False(t, issue(t, b, storage).IsError())
+		require.Equal(t, []string{"user-2", "user-3"}, tracked(t, storage))
+		mockDB.AssertNumberOfCalls(t, "DeleteUser", 1)
+	})
+
+	t.Run("revoke_oldest", func(t *testing.T) {
+		b, storage, mockDB := setup(t, maxCredentialsBehaviorRevokeOldest)
+
+		for i := 0; i < 3; i++ {
+			require.False(t, issue(t, b, storage).IsError())
+		}
+		require.Equal(t, []string{"user-2", "user-3"}, tracked(t, storage))
+		mockDB.AssertCalled(t, "DeleteUser", mock.Anything, mock.MatchedBy(func(req v5.DeleteUserRequest) bool {
+			return req.Username == "user-1"
+		}))
+	})
+}
+
+func TestBackend_MaxCredentials_Validation(t *testing.T) {
+	ctx := context.Background()
+	b, storage, _ := getBackend(t)
+	defer b.Cleanup(ctx)
+
+	for _, data := range []map[string]interface{}{
+		{"db_name": "mockv5", "max_credentials": -1},
+		{"db_name": "mockv5", "max_credentials": 1, "max_credentials_behavior": "queue"},
+	} {
+		resp, err := b.HandleRequest(ctx, &logical.Request{
+			Operation: logical.CreateOperation,
+			Path:      "roles/limited",
+			Storage:   storage,
+			Data:      data,
+		})
+		require.NoError(t, err)
+		require.True(t, resp.IsError(), "expected error for %v", data)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net/url"
 	"sort"
+	"time"
 
 	"github.com/fatih/structs"
 	"github.com/hashicorp/go-uuid"
@@ -40,6 +41,14 @@ type DatabaseConfig struct {
 	RootCredentialsRotateStatements []string `json:"root_credentials_rotate_statements" structs:"root_credentials_rotate_statements" mapstructure:"root_credentials_rotate_statements"`
 
 	PasswordPolicy string `json:"password_policy" structs:"password_policy" mapstructure:"password_policy"`
+
+	// HealthCheckInterval is the interval at which the connection is checked
+	// in the background. Zero disables the health checks.
+	HealthCheckInterval time.Duration `json:"health_check_interval" structs:"-" mapstructure:"health_check_interval"`
+
+	// VerifyCredentials enables a test login with the dynamic credentials
+	// before they are returned.
+	VerifyCredentials bool `json:"verify_credentials" structs:"verify_credentials" mapstructure:"verify_credentials"`
 }
 
 func (c *DatabaseConfig) SupportsCredentialType(credentialType v5.CredentialType) bool {
@@ -161,6 +170,19 @@ func pathConfigurePluginConnection(b *databaseBackend) *framework.Path {
 				Type:        framework.TypeString,
 				Description: `Password policy to use when generating passwords.`,
 			},
+			"health_check_interval": {
+				Type: framework.TypeDurationSecond,
+				Description: `Interval at which the connection to the database
+				is checked in the background. The status of the last check can
+				be read at the "health/<name>" endpoint. Defaults to 0, which
+				disables the health checks.`,
+			},
+			"verify_credentials": {
+				Type: framework.TypeBool,
+				Description: `If true, the dynamic credentials are verified by
+				logging in to the database with them before they are returned.
+				Only password credentials are verified. Defaults to false.`,
+			},
 		},
 
 		ExistenceCheck: b.connectionExistenceCheck(),
@@ -283,8 +305,11 @@ func (b *databaseBackend) connectionReadHandler() framework.OperationFunc {
 		delete(config.ConnectionDetails, "password")
 		delete(config.ConnectionDetails, "private_key")
 
+		respData := structs.New(config).Map()
+		respData["health_check_interval"] = int64(config.HealthCheckInterval.Seconds())
+
 		return &logical.Response{
-			Data: structs.New(config).Map(),
+			Data: respData,
 		}, nil
 	}
 }
@@ -305,6 +330,7 @@ func (b *databaseBackend) connectionDeleteHandler() framework.OperationFunc {
 		if err := b.ClearConnection(name); err != nil {
 			return nil, err
 		}
+		b.healthDelete(name)
 
 		return nil, nil
 	}
@@ -413,6 +439,17 @@ func (b *databaseBackend) connectionWriteHandler() framework.OperationFunc {
 			config.PasswordPolicy = passwordPolicyRaw.(string)
 		}
 
+		if healthCheckIntervalRaw, ok := data.GetOk("health_check_interval"); ok {
+			config.HealthCheckInterval = time.Duration(healthCheckIntervalRaw.(int)) * time.Second
+			if config.HealthCheckInterval < 0 {
+				return logical.ErrorResponse("health_check_interval must not be negative"), nil
+			}
+		}
+
+		if verifyCredentialsRaw, ok := data.GetOk("verify_credentials"); ok {
+			config.VerifyCredentials = verifyCredentialsRaw.(bool)
+		}
+
 		// Remove these entries from the data before we store it keyed under
 		// ConnectionDetails.
 		delete(data.Raw, "name")
@@ -422,6 +459,8 @@ func (b *databaseBackend) connectionWriteHandler() framework.OperationFunc {
 		delete(data.Raw, "verify_connection")
 		delete(data.Raw, "root_rotation_statements")
 		delete(data.Raw, "password_policy")
+		delete(data.Raw, "health_check_interval")
+		delete(data.Raw, "verify_credentials")
 
 		id, err := uuid.GenerateUUID()
 		if err != nil {
@@ -470,6 +509,7 @@ func (b *databaseBackend) connectionWriteHandler() framework.OperationFunc {
 		if oldConn != nil {
 			oldConn.Close()
 		}
+		b.healthDelete(name)
 
 		// 1.12.0 and 1.12.1 stored builtin plugins in storage, but 1.12.2 reverted
 		// that, so clean up any pre-existing stored builtin versions on write.
@@ -12,6 +12,7 @@ import (
 	"github.com/openbao/openbao/sdk/v2/database/dbplugin/v5"
 	v5 "github.com/openbao/openbao/sdk/v2/database/dbplugin/v5"
 	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/locksutil"
 	"github.com/openbao/openbao/sdk/v2/logical"
 )
 
@@ -76,7 +77,7 @@ func (b *databaseBackend) pathCredsCreateRead() framework.OperationFunc {
 			return nil, err
 		}
 		if role == nil {
-			return logical.ErrorResponse(fmt.Sprintf("unknown role: %s", name)), nil
+			return logical.CodedErrorResponse(logical.ErrorCodeNotFound, "unknown role: %s", name), nil
 		}
 
 		dbConfig, err := b.DatabaseConfig(ctx, req.Storage, role.DBName)
@@ -96,6 +97,15 @@ func (b *databaseBackend) pathCredsCreateRead() framework.OperationFunc {
 				role.CredentialType.String()), nil
 		}
 
+		if role.MaxCredentials > 0 {
+			// Hold the lock of the role until the new credential is tracked,
+			// so that concurrent requests cannot exceed the maximum. It is
+			// taken before the lock of the connection, like during rotations.
+			lock := locksutil.LockForKey(b.roleLocks, name)
+			lock.Lock()
+			defer lock.Unlock()
+		}
+
 		// Get the Database object
 		dbi, err := b.GetConnection(ctx, req.Storage, role.DBName)
 		if err != nil {
@@ -105,6 +115,13 @@ func (b *databaseBackend) pathCredsCreateRead() framework.OperationFunc {
 		dbi.RLock()
 		defer dbi.RUnlock()
 
+		if role.MaxCredentials > 0 {
+			resp, err := b.enforceMaxCredentials(ctx, req.Storage, dbi, name, role)
+			if err != nil || resp != nil {
+				return resp, err
+			}
+		}
+
 		ttl, _, err := framework.CalculateTTL(b.System(), 0, role.DefaultTTL, 0, role.MaxTTL, 0, time.Time{})
 		if err != nil {
 			return nil, err
@@ -208,6 +225,37 @@ func (b *databaseBackend) pathCredsCreateRead() framework.OperationFunc {
 		// Set the password response to what is returned by the NewUser request.
 		if role.CredentialType == v5.CredentialTypePassword {
 			respData["password"] = password
+
+			if dbConfig.VerifyCredentials {
+				if err := b.verifyCredentials(ctx, dbConfig, newUserResp.Username, password); err != nil {
+					// Do not leave behind a user that was never handed out.
+					_, delErr := dbi.database.DeleteUser(ctx, v5.DeleteUserRequest{
+						Username: newUserResp.Username,
+						Statements: v5.Statements{
+							Commands: role.Statements.Revocation,
+						},
+					})
+					if delErr != nil {
+						b.Logger().Warn("failed to delete user after credential verification failure", "role", name, "error", delErr)
+					}
+					return nil, fmt.Errorf("failed to verify the generated credentials: %w", err)
+				}
+			}
+		}
+
+		if role.MaxCredentials > 0 {
+			if err := trackCredential(ctx, req.Storage, name, newUserResp.Username); err != nil {
+				_, delErr := dbi.database.DeleteUser(ctx, v5.DeleteUserRequest{
+					Username: newUserResp.Username,
+					Statements: v5.Statements{
+						Commands: role.Statements.Revocation,
+					},
+				})
+				if delErr != nil {
+					b.Logger().Warn("failed to delete user after credential tracking failure", "role", name, "error", delErr)
+				}
+				return nil, fmt.Errorf("failed to track the generated credentials: %w", err)
+			}
 		}
 
 		internal := map[string]interface{}{
@@ -223,6 +271,32 @@ func (b *databaseBackend) pathCredsCreateRead() framework.OperationFunc {
 	}
 }
 
+// verifyCredentials logs in to the database of the given connection with the
+// given credentials, using a dedicated plugin instance.
+func (b *databaseBackend) verifyCredentials(ctx context.Context, config *DatabaseConfig, username, password string) error {
+	details := make(map[string]interface{}, len(config.ConnectionDetails))
+	for k, v := range config.ConnectionDetails {
+		details[k] = v
+	}
+	details["username"] = username
+	details["password"] = password
+
+	dbw, err := newDatabaseWrapper(ctx, config.PluginName, config.PluginVersion, b.System(), b.logger)
+	if err != nil {
+		return fmt.Errorf("unable to create database instance: %w", err)
+	}
+	defer dbw.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	_, err = dbw.Initialize(ctx, v5.InitializeRequest{
+		Config:           details,
+		VerifyConnection: true,
+	})
+	return err
+}
+
 func (b *databaseBackend) pathStaticCredsRead() framework.OperationFunc {
 	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
 		name := data.Get("name").(string)
@@ -232,7 +306,7 @@ func (b *databaseBackend) pathStaticCredsRead() framework.OperationFunc {
 			return nil, err
 		}
 		if role == nil {
-			return logical.ErrorResponse("unknown role: %s", name), nil
+			return logical.CodedErrorResponse(logical.ErrorCodeNotFound, "unknown role: %s", name), nil
 		}
 
 		dbConfig, err := b.DatabaseConfig(ctx, req.Storage, role.DBName)
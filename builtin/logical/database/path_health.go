@@ -0,0 +1,256 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/armon/go-metrics"
+	v5 "github.com/openbao/openbao/sdk/v2/database/dbplugin/v5"
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const (
+	// healthCheckTimeout bounds the duration of a single health check.
+	healthCheckTimeout = 30 * time.Second
+
+	healthStatusHealthy   = "healthy"
+	healthStatusUnhealthy = "unhealthy"
+	healthStatusUnknown   = "unknown"
+)
+
+// connectionHealth is the result of the last health check of a database
+// connection. It is only held in memory by the node running the checks.
+type connectionHealth struct {
+	Healthy             bool
+	LastChecked         time.Time
+	LastError           string
+	ConsecutiveFailures int
+}
+
+func (b *databaseBackend) healthGet(name string) *connectionHealth {
+	b.healthLock.RLock()
+	defer b.healthLock.RUnlock()
+
+	health, ok := b.health[name]
+	if !ok {
+		return nil
+	}
+	healthCopy := *health
+	return &healthCopy
+}
+
+func (b *databaseBackend) healthDelete(name string) {
+	b.healthLock.Lock()
+	defer b.healthLock.Unlock()
+	delete(b.health, name)
+}
+
+func (b *databaseBackend) healthRecord(name string, checkErr error) *connectionHealth {
+	b.healthLock.Lock()
+	defer b.healthLock.Unlock()
+
+	health, ok := b.health[name]
+	if !ok {
+		health = &connectionHealth{}
+		b.health[name] = health
+	}
+
+	health.LastChecked = time.Now()
+	if checkErr != nil {
+		health.Healthy = false
+		health.LastError = checkErr.Error()
+		health.ConsecutiveFailures++
+	} else {
+		health.Healthy = true
+		health.LastError = ""
+		health.ConsecutiveFailures = 0
+	}
+
+	healthCopy := *health
+	return &healthCopy
+}
+
+// checkConnectionHealth verifies that the database of the given connection
+// can be reached, and records the result.
+func (b *databaseBackend) checkConnectionHealth(ctx context.Context, name string, config *DatabaseConfig) *connectionHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	var err error
+	if dbi := b.connGet(name); dbi != nil {
+		// Initializing the plugin again with the same configuration makes it
+		// verify its connection to the database.
+		dbi.Lock()
+		_, err = dbi.database.Initialize(ctx, v5.InitializeRequest{
+			Config:           config.ConnectionDetails,
+			VerifyConnection: true,
+		})
+		dbi.Unlock()
+		if err != nil {
+			b.CloseIfShutdown(dbi, err)
+		}
+	} else {
+		// A new connection is verified when it is created.
+		_, err = b.GetConnectionWithConfig(ctx, name, config)
+	}
+
+	labels := []metrics.Label{{Name: "name", Value: name}}
+	if err != nil {
+		b.Logger().Warn("database connection health check failed", "name", name, "error", err)
+		metrics.IncrCounterWithLabels([]string{"secrets", "database", "connection", "health_check", "failure"}, 1, labels)
+		metrics.SetGaugeWithLabels([]string{"secrets", "database", "connection", "healthy"}, 0, labels)
+	} else {
+		metrics.SetGaugeWithLabels([]string{"secrets", "database", "connection", "healthy"}, 1, labels)
+	}
+
+	return b.healthRecord(name, err)
+}
+
+// periodicHealthCheck checks the connections whose health check interval
+// has elapsed since their last check.
+func (b *databaseBackend) periodicHealthCheck(ctx context.Context, req *logical.Request) error {
+	names, err := req.Storage.List(ctx, databaseConfigPath)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		config, err := b.DatabaseConfig(ctx, req.Storage, name)
+		if err != nil {
+			b.Logger().Warn("unable to read database connection configuration for health check", "name", name, "error", err)
+			continue
+		}
+		if config.HealthCheckInterval <= 0 {
+			continue
+		}
+
+		if health := b.healthGet(name); health != nil && time.Since(health.LastChecked) < config.HealthCheckInterval {
+			continue
+		}
+
+		b.checkConnectionHealth(ctx, name, config)
+	}
+
+	return nil
+}
+
+func pathConnectionHealth(b *databaseBackend) *framework.Path {
+	return &framework.Path{
+		Pattern: fmt.Sprintf("health/%s", framework.GenericNameRegex("name")),
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixDatabase,
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Name of this database connection",
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathConnectionHealthRead,
+				DisplayAttrs: &framework.DisplayAttributes{
+					OperationVerb:   "read",
+					OperationSuffix: "connection-health",
+				},
+			},
+			logical.UpdateOperation: &framework.PathOperation{
+				Callback: b.pathConnectionHealthCheck,
+				DisplayAttrs: &framework.DisplayAttributes{
+					OperationVerb:   "check",
+					OperationSuffix: "connection-health",
+				},
+			},
+		},
+
+		HelpSynopsis:    pathConnectionHealthHelpSyn,
+		HelpDescription: pathConnectionHealthHelpDesc,
+	}
+}
+
+// pathConnectionHealthRead returns the result of the last health check of a
+// connection.
+func (b *databaseBackend) pathConnectionHealthRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	name := data.Get("name").(string)
+	if name == "" {
+		return logical.ErrorResponse(respErrEmptyName), nil
+	}
+
+	entry, err := req.Storage.Get(ctx, databaseConfigPath+name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read connection configuration: %w", err)
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var config DatabaseConfig
+	if err := entry.DecodeJSON(&config); err != nil {
+		return nil, err
+	}
+
+	return connectionHealthResponse(&config, b.healthGet(name)), nil
+}
+
+// pathConnectionHealthCheck checks the health of a connection immediately.
+func (b *databaseBackend) pathConnectionHealthCheck(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	name := data.Get("name").(string)
+	if name == "" {
+		return logical.ErrorResponse(respErrEmptyName), nil
+	}
+
+	entry, err := req.Storage.Get(ctx, databaseConfigPath+name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read connection configuration: %w", err)
+	}
+	if entry == nil {
+		return logical.ErrorResponse("unknown database connection: %s", name), nil
+	}
+
+	var config DatabaseConfig
+	if err := entry.DecodeJSON(&config); err != nil {
+		return nil, err
+	}
+
+	return connectionHealthResponse(&config, b.checkConnectionHealth(ctx, name, &config)), nil
+}
+
+func connectionHealthResponse(config *DatabaseConfig, health *connectionHealth) *logical.Response {
+	respData := map[string]interface{}{
+		"health_check_interval": int64(config.HealthCheckInterval.Seconds()),
+		"status":                healthStatusUnknown,
+		"last_checked":          "",
+		"last_error":            "",
+		"consecutive_failures":  0,
+	}
+
+	if health != nil {
+		respData["status"] = healthStatusHealthy
+		if !health.Healthy {
+			respData["status"] = healthStatusUnhealthy
+		}
+		respData["last_checked"] = health.LastChecked.Format(time.RFC3339)
+		respData["last_error"] = health.LastError
+		respData["consecutive_failures"] = health.ConsecutiveFailures
+	}
+
+	return &logical.Response{
+		Data: respData,
+	}
+}
+
+const pathConnectionHealthHelpSyn = `
+Check the health of a database connection.
+`
+
+const pathConnectionHealthHelpDesc = `
+Reading this path returns the result of the last health check of the
+connection. Health checks run in the background at the interval set by the
+"health_check_interval" parameter of the connection configuration. Writing to
+this path checks the connection immediately and returns the result.
+`
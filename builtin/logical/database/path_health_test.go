@@ -0,0 +1,97 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/openbao/openbao/helper/namespace"
+	v5 "github.com/openbao/openbao/sdk/v2/database/dbplugin/v5"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackend_ConnectionHealth(t *testing.T) {
+	b, storage, _ := getBackend(t)
+	defer b.Cleanup(context.Background())
+
+	entry, err := logical.StorageEntryJSON("config/mockv5", &DatabaseConfig{
+		AllowedRoles:        []string{"*"},
+		HealthCheckInterval: time.Hour,
+	})
+	require.NoError(t, err)
+	require.NoError(t, storage.Put(context.Background(), entry))
+
+	readHealth := func() map[string]interface{} {
+		t.Helper()
+		resp, err := b.HandleRequest(namespace.RootContext(nil), &logical.Request{
+			Operation: logical.ReadOperation,
+			Path:      "health/mockv5",
+			Storage:   storage,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, resp)
+		require.False(t, resp.IsError(), "unexpected error: %v", resp.Error())
+		return resp.Data
+	}
+
+	// No check has run yet.
+	data := readHealth()
+	require.Equal(t, healthStatusUnknown, data["status"])
+	require.Equal(t, int64(3600), data["health_check_interval"])
+
+	// The periodic function checks the connection.
+	require.NoError(t, b.periodicHealthCheck(context.Background(), &logical.Request{Storage: storage}))
+	data = readHealth()
+	require.Equal(t, healthStatusHealthy, data["status"])
+	require.NotEmpty(t, data["last_checked"])
+	require.Equal(t, 0, data["consecutive_failures"])
+
+	// Make the database unreachable.
+	failingDB := &mockNewDatabase{}
+	failingDB.On("Initialize", mock.Anything, mock.Anything).
+		Return(v5.InitializeResponse{}, errors.New("connection refused"))
+	failingDB.On("Close").Return(nil)
+	b.connections["mockv5"].database = databaseVersionWrapper{v5: failingDB}
+
+	// The interval has not elapsed, so the periodic function skips the check.
+	require.NoError(t, b.periodicHealthCheck(context.Background(), &logical.Request{Storage: storage}))
+	require.Equal(t, healthStatusHealthy, readHealth()["status"])
+	failingDB.AssertNotCalled(t, "Initialize", mock.Anything, mock.Anything)
+
+	// Writing to the endpoint checks the connection immediately.
+	for i := 1; i <= 2; i++ {
+		resp, err := b.HandleRequest(namespace.RootContext(nil), &logical.Request{
+			Operation: logical.UpdateOperation,
+			Path:      "health/mockv5",
+			Storage:   storage,
+		})
+		require.NoError(t, err)
+		require.Equal(t, healthStatusUnhealthy, resp.Data["status"])
+		require.Equal(t, i, resp.Data["consecutive_failures"])
+		require.Contains(t, resp.Data["last_error"], "connection refused")
+	}
+	require.Equal(t, healthStatusUnhealthy, readHealth()["status"])
+
+	// Unknown connections are not found.
+	resp, err := b.HandleRequest(namespace.RootContext(nil), &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "health/unknown",
+		Storage:   storage,
+	})
+	require.NoError(t, err)
+	require.Nil(t, resp)
+}
+
+func TestBackend_ConnectionHealth_Disabled(t *testing.T) {
+	b, storage, mockDB := getBackend(t)
+	defer b.Cleanup(context.Background())
+
+	configureDBMount(t, storage)
+
+	require.NoError(t, b.periodicHealthCheck(context.Background(), &logical.Request{Storage: storage}))
+	mockDB.AssertNotCalled(t, "Initialize", mock.Anything, mock.Anything)
+	require.Nil(t, b.healthGet("mockv5"))
+}
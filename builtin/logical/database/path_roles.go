@@ -94,6 +94,7 @@ func pathRoles(b *databaseBackend) []*framework.Path {
 				logical.UpdateOperation: b.pathRoleCreateUpdate,
 				logical.DeleteOperation: b.pathRoleDelete,
 			},
+			DryRun: true,
 
 			HelpSynopsis:    pathRoleHelpSyn,
 			HelpDescription: pathRoleHelpDesc,
@@ -113,6 +114,7 @@ func pathRoles(b *databaseBackend) []*framework.Path {
 				logical.UpdateOperation: b.pathStaticRoleCreateUpdate,
 				logical.DeleteOperation: b.pathStaticRoleDelete,
 			},
+			DryRun: true,
 
 			HelpSynopsis:    pathStaticRoleHelpSyn,
 			HelpDescription: pathStaticRoleHelpDesc,
@@ -201,6 +203,20 @@ func dynamicFields() map[string]*framework.FieldSchema {
 	type will support this functionality. See the plugin's API page for
 	more information on support and formatting for this parameter.`,
 		},
+		"max_credentials": {
+			Type: framework.TypeInt,
+			Description: `Maximum number of outstanding credentials of the
+	role. Defaults to 0, which does not limit the number of credentials.`,
+		},
+		"max_credentials_behavior": {
+			Type:          framework.TypeString,
+			Default:       maxCredentialsBehaviorDeny,
+			AllowedValues: []interface{}{maxCredentialsBehaviorDeny, maxCredentialsBehaviorRevokeOldest},
+			Description: `Behavior when a credential is requested while the role
+	has reached "max_credentials". "deny" refuses the request, while
+	"revoke_oldest" revokes the oldest outstanding credential of the role.
+	Defaults to "deny".`,
+		},
 	}
 	return fields
 }
@@ -264,6 +280,11 @@ func (b *databaseBackend) pathStaticRoleDelete(ctx context.Context, req *logical
 	lock.Lock()
 	defer lock.Unlock()
 
+	// Dry runs leave the rotation queue and the WALs of the role untouched
+	if req.DryRun {
+		return nil, nil
+	}
+
 	// Remove the item from the queue
 	_, _ = b.popFromRotationQueueByKey(name)
 
@@ -351,6 +372,10 @@ func (b *databaseBackend) pathRoleRead(ctx context.Context, req *logical.Request
 		"default_ttl":           role.DefaultTTL.Seconds(),
 		"max_ttl":               role.MaxTTL.Seconds(),
 		"credential_type":       role.CredentialType.String(),
+		"max_credentials":       role.MaxCredentials,
+	}
+	if role.MaxCredentials > 0 {
+		data["max_credentials_behavior"] = role.maxCredentialsBehavior()
 	}
 	if len(role.CredentialConfig) > 0 {
 		data["credential_config"] = role.CredentialConfig
@@ -495,6 +520,25 @@ func (b *databaseBackend) pathRoleCreateUpdate(ctx context.Context, req *logical
 		}
 	}
 
+	// Credential limits
+	{
+		if maxCredentialsRaw, ok := data.GetOk("max_credentials"); ok {
+			role.MaxCredentials = maxCredentialsRaw.(int)
+		}
+		if role.MaxCredentials < 0 {
+			return logical.ErrorResponse("max_credentials must not be negative"), nil
+		}
+
+		if behaviorRaw, ok := data.GetOk("max_credentials_behavior"); ok {
+			role.MaxCredentialsBehavior = behaviorRaw.(string)
+		}
+		switch role.MaxCredentialsBehavior {
+		case "", maxCredentialsBehaviorDeny, maxCredentialsBehaviorRevokeOldest:
+		default:
+			return logical.ErrorResponse("invalid max_credentials_behavior %q", role.MaxCredentialsBehavior), nil
+		}
+	}
+
 	// Store it
 	entry, err := logical.StorageEntryJSON(databaseRolePath+name, role)
 	if err != nil {
@@ -604,6 +648,16 @@ func (b *databaseBackend) pathStaticRoleCreateUpdate(ctx context.Context, req *l
 		return logical.ErrorResponse("credential_config validation failed: %s", err), nil
 	}
 
+	// Dry runs stop once the role is validated, as storing it rotates the
+	// password of new static accounts and schedules the next rotation
+	if req.DryRun {
+		resp := &logical.Response{}
+		if req.Operation == logical.CreateOperation {
+			resp.AddWarning(fmt.Sprintf("the credentials of static account %q would be rotated when creating the role", role.StaticAccount.Username))
+		}
+		return resp, nil
+	}
+
 	// lvr represents the roles' LastVaultRotation
 	lvr := role.StaticAccount.LastVaultRotation
 
@@ -670,6 +724,20 @@ type roleEntry struct {
 	CredentialType   v5.CredentialType      `json:"credential_type"`
 	CredentialConfig map[string]interface{} `json:"credential_config"`
 	StaticAccount    *staticAccount         `json:"static_account" mapstructure:"static_account"`
+
+	// MaxCredentials limits the number of outstanding credentials of a
+	// dynamic role. Zero means no limit.
+	MaxCredentials         int    `json:"max_credentials,omitempty"`
+	MaxCredentialsBehavior string `json:"max_credentials_behavior,omitempty"`
+}
+
+// maxCredentialsBehavior returns the behavior of the role when it has reached
+// its maximum number of outstanding credentials.
+func (r *roleEntry) maxCredentialsBehavior() string {
+	if r.MaxCredentialsBehavior == "" {
+		return maxCredentialsBehaviorDeny
+	}
+	return r.MaxCredentialsBehavior
 }
 
 // setCredentialType sets the credential type for the role given its string form.
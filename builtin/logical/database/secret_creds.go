@@ -162,6 +162,10 @@ func (b *databaseBackend) secretCredsRevoke() framework.OperationFunc {
 			b.CloseIfShutdown(dbi, err)
 			return nil, err
 		}
+
+		if err := untrackCredential(ctx, req.Storage, roleNameRaw.(string), username); err != nil {
+			return nil, err
+		}
 		return resp, nil
 	}
 }
@@ -0,0 +1,85 @@
+package keymgmt
+
+import (
+	"context"
+	"strings"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/locksutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const operationPrefixKeyManagement = "key-management"
+
+func Factory(ctx context.Context, conf *logical.BackendConfig) (logical.Backend, error) {
+	b := Backend(conf)
+	if err := b.Setup(ctx, conf); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func Backend(conf *logical.BackendConfig) *backend {
+	b := &backend{
+		keyLocks: locksutil.CreateLocks(),
+	}
+	b.Backend = &framework.Backend{
+		Help: strings.TrimSpace(backendHelp),
+
+		PathsSpecial: &logical.Paths{
+			SealWrapStorage: []string{
+				kmsPrefix,
+			},
+		},
+
+		Paths: []*framework.Path{
+			pathKMSList(b),
+			pathKMS(b),
+			pathRolesList(b),
+			pathRoles(b),
+			pathKeysList(b),
+			pathKeys(b),
+			pathKeyRotate(b),
+		},
+
+		PeriodicFunc: b.periodicFunc,
+		BackendType:  logical.TypeLogical,
+	}
+
+	return b
+}
+
+type backend struct {
+	*framework.Backend
+
+	// keyLocks serializes the operations on each key, so that a key is not
+	// rotated concurrently or while its deletion is scheduled.
+	keyLocks []*locksutil.LockEntry
+}
+
+// periodicFunc enforces the rotation periods of the roles, and forgets the
+// keys past their deletion date.
+func (b *backend) periodicFunc(ctx context.Context, req *logical.Request) error {
+	names, err := req.Storage.List(ctx, keyPrefix)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if err := b.tidyKey(ctx, req.Storage, name); err != nil {
+			b.Logger().Error("failed to tidy key", "key", name, "error", err)
+		}
+	}
+
+	return nil
+}
+
+const backendHelp = `
+The key management backend creates, rotates and schedules the deletion of
+keys in cloud KMS providers: AWS KMS, Azure Key Vault and GCP Cloud KMS.
+
+Roles set the type, purpose, rotation period and deletion window of the keys
+created from them, and the KMS provider they are created in. The backend
+keeps the inventory of the keys with their versions and rotation status, and
+rotates the keys whose rotation period elapsed.
+`
@@ -0,0 +1,270 @@
+package keymgmt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/stretchr/testify/require"
+)
+
+const providerFake = "fake"
+
+// fakeKMS records the operations of the engine on its keys.
+type fakeKMS struct {
+	sync.Mutex
+
+	keys      map[string][]string
+	deleted   map[string]bool
+	rotateErr error
+}
+
+func (f *fakeKMS) CreateKey(_ context.Context, name string, spec keySpec) (string, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	if _, ok := f.keys[name]; ok {
+		return "", fmt.Errorf("key %q already exists", name)
+	}
+	id := fmt.Sprintf("%s/%s/1", name, spec.Type)
+	f.keys[name] = []string{id}
+	return id, nil
+}
+
+func (f *fakeKMS) RotateKey(_ context.Context, name string, spec keySpec) (string, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.rotateErr != nil {
+		return "", f.rotateErr
+	}
+	id := fmt.Sprintf("%s/%s/%d", name, spec.Type, len(f.keys[name])+1)
+	f.keys[name] = append(f.keys[name], id)
+	return id, nil
+}
+
+func (f *fakeKMS) ScheduleDeletion(_ context.Context, name string, versionIDs []string, spec keySpec) (time.Time, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	for _, id := range versionIDs {
+		f.deleted[id] = true
+	}
+	return time.Now().Add(spec.DeletionWindow), nil
+}
+
+func testBackend(t *testing.T) (*backend, logical.Storage, *fakeKMS) {
+	t.Helper()
+
+	fake := &fakeKMS{
+		keys:    map[string][]string{},
+		deleted: map[string]bool{},
+	}
+	kmsProviders[providerFake] = kmsProvider{
+		newClient: func(context.Context, string, map[string]string) (kmsClient, error) {
+			return fake, nil
+		},
+		keyTypes: []string{keyTypeAES256, keyTypeECDSAP256},
+	}
+	t.Cleanup(func() { delete(kmsProviders, providerFake) })
+
+	storage := &logical.InmemStorage{}
+	config := logical.TestBackendConfig()
+	config.StorageView = storage
+
+	b, err := Factory(context.Background(), config)
+	require.NoError(t, err)
+
+	return b.(*backend), storage, fake
+}
+
+func testRequest(t *testing.T, b *backend, storage logical.Storage, op logical.Operation, path string, data map[string]interface{}) (*logical.Response, error) {
+	t.Helper()
+
+	return b.HandleRequest(context.Background(), &logical.Request{
+		Operation: op,
+		Path:      path,
+		Storage:   storage,
+		Data:      data,
+	})
+}
+
+func setupRole(t *testing.T, b *backend, storage logical.Storage, data map[string]interface{}) {
+	t.Helper()
+
+	_, err := testRequest(t, b, storage, logical.CreateOperation, "kms/test", map[string]interface{}{
+		"provider":       providerFake,
+		"key_collection": "collection",
+		"credentials":    map[string]interface{}{"secret": "s3cr3t"},
+	})
+	require.NoError(t, err)
+
+	data["kms"] = "test"
+	_, err = testRequest(t, b, storage, logical.CreateOperation, "role/encrypt", data)
+	require.NoError(t, err)
+}
+
+func TestKeyManagement_KMS(t *testing.T) {
+	b, storage, _ := testBackend(t)
+	setupRole(t, b, storage, map[string]interface{}{"key_type": keyTypeAES256})
+
+	// Credentials are never read back
+	resp, err := testRequest(t, b, storage, logical.ReadOperation, "kms/test", nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"provider":       providerFake,
+		"key_collection": "collection",
+		"credentials":    []string{"secret"},
+	}, resp.Data)
+
+	// The key collection cannot be changed
+	_, err = testRequest(t, b, storage, logical.UpdateOperation, "kms/test", map[string]interface{}{
+		"key_collection": "other",
+	})
+	require.ErrorIs(t, err, logical.ErrInvalidRequest)
+
+	_, err = testRequest(t, b, storage, logical.CreateOperation, "kms/bad", map[string]interface{}{
+		"provider":       "somecloud",
+		"key_collection": "collection",
+	})
+	require.ErrorIs(t, err, logical.ErrInvalidRequest)
+
+	// KMS providers managing keys cannot be deleted
+	_, err = testRequest(t, b, storage, logical.CreateOperation, "key/data", map[string]interface{}{"role": "encrypt"})
+	require.NoError(t, err)
+	_, err = testRequest(t, b, storage, logical.DeleteOperation, "kms/test", nil)
+	require.ErrorIs(t, err, logical.ErrInvalidRequest)
+}
+
+func TestKeyManagement_Role(t *testing.T) {
+	b, storage, _ := testBackend(t)
+	setupRole(t, b, storage, map[string]interface{}{
+		"key_type":        keyTypeAES256,
+		"rotation_period": "720h",
+	})
+
+	resp, err := testRequest(t, b, storage, logical.ReadOperation, "role/encrypt", nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"kms":             "test",
+		"key_type":        keyTypeAES256,
+		"purpose":         purposeEncrypt,
+		"rotation_period": int64(720 * 3600),
+		"deletion_window": int64(30 * 24 * 3600),
+	}, resp.Data)
+
+	for _, data := range []map[string]interface{}{
+		{"purpose": purposeSign},
+		{"key_type": keyTypeRSA2048},
+		{"key_type": "des"},
+		{"rotation_period": "1m"},
+		{"deletion_window": "24h"},
+		{"kms": "missing"},
+	} {
+		_, err = testRequest(t, b, storage, logical.UpdateOperation, "role/encrypt", data)
+		require.ErrorIs(t, err, logical.ErrInvalidRequest, "data: %v", data)
+	}
+}
+
+func TestKeyManagement_KeyLifecycle(t *testing.T) {
+	b, storage, fake := testBackend(t)
+	setupRole(t, b, storage, map[string]interface{}{
+		"key_type":        keyTypeAES256,
+		"rotation_period": "24h",
+		"deletion_window": "168h",
+	})
+
+	_, err := testRequest(t, b, storage, logical.CreateOperation, "key/data", map[string]interface{}{"role": "encrypt"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"data/aes256/1"}, fake.keys["data"])
+
+	// Keys cannot be recreated
+	_, err = testRequest(t, b, storage, logical.UpdateOperation, "key/data", map[string]interface{}{"role": "encrypt"})
+	require.ErrorIs(t, err, logical.ErrInvalidRequest)
+
+	resp, err := testRequest(t, b, storage, logical.ReadOperation, "key/data", nil)
+	require.NoError(t, err)
+	require.Equal(t, keyStatusActive, resp.Data["status"])
+	require.Equal(t, 1, resp.Data["latest_version"])
+	require.Equal(t, false, resp.Data["rotation_overdue"])
+	require.NotEmpty(t, resp.Data["next_rotation"])
+
+	resp, err = testRequest(t, b, storage, logical.UpdateOperation, "key/data/rotate", nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, resp.Data["latest_version"])
+	require.Equal(t, []string{"data/aes256/1", "data/aes256/2"}, fake.keys["data"])
+
+	// Deleting a key schedules the deletion of all its versions
+	resp, err = testRequest(t, b, storage, logical.DeleteOperation, "key/data", nil)
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.Data["deletion_date"])
+	require.True(t, fake.deleted["data/aes256/1"])
+	require.True(t, fake.deleted["data/aes256/2"])
+
+	resp, err = testRequest(t, b, storage, logical.ReadOperation, "key/data", nil)
+	require.NoError(t, err)
+	require.Equal(t, keyStatusPendingDeletion, resp.Data["status"])
+	require.NotContains(t, resp.Data, "next_rotation")
+
+	_, err = testRequest(t, b, storage, logical.UpdateOperation, "key/data/rotate", nil)
+	require.ErrorIs(t, err, logical.ErrInvalidRequest)
+
+	// Keys are forgotten once past their deletion date
+	key, err := b.key(context.Background(), storage, "data")
+	require.NoError(t, err)
+	key.DeletionDate = time.Now().Add(-time.Minute)
+	require.NoError(t, b.putKey(context.Background(), storage, "data", key))
+	require.NoError(t, b.periodicFunc(context.Background(), &logical.Request{Storage: storage}))
+
+	resp, err = testRequest(t, b, storage, logical.ListOperation, "key/", nil)
+	require.NoError(t, err)
+	require.Empty(t, resp.Data["keys"])
+}
+
+func TestKeyManagement_PeriodicRotation(t *testing.T) {
+	b, storage, fake := testBackend(t)
+	setupRole(t, b, storage, map[string]interface{}{
+		"key_type":        keyTypeAES256,
+		"rotation_period": "24h",
+	})
+
+	_, err := testRequest(t, b, storage, logical.CreateOperation, "key/data", map[string]interface{}{"role": "encrypt"})
+	require.NoError(t, err)
+
+	// Keys are only rotated once due
+	require.NoError(t, b.periodicFunc(context.Background(), &logical.Request{Storage: storage}))
+	require.Len(t, fake.keys["data"], 1)
+
+	key, err := b.key(context.Background(), storage, "data")
+	require.NoError(t, err)
+	key.LastRotated = time.Now().Add(-25 * time.Hour)
+	require.NoError(t, b.putKey(context.Background(), storage, "data", key))
+
+	// Failed rotations are reported in the key status
+	fake.rotateErr = errors.New("throttled")
+	require.NoError(t, b.periodicFunc(context.Background(), &logical.Request{Storage: storage}))
+	resp, err := testRequest(t, b, storage, logical.ReadOperation, "key/data", nil)
+	require.NoError(t, err)
+	require.Equal(t, true, resp.Data["rotation_overdue"])
+	require.Equal(t, "throttled", resp.Data["last_rotation_error"])
+
+	fake.rotateErr = nil
+	require.NoError(t, b.periodicFunc(context.Background(), &logical.Request{Storage: storage}))
+	require.Len(t, fake.keys["data"], 2)
+	resp, err = testRequest(t, b, storage, logical.ReadOperation, "key/data", nil)
+	require.NoError(t, err)
+	require.Equal(t, false, resp.Data["rotation_overdue"])
+	require.Empty(t, resp.Data["last_rotation_error"])
+}
+
+func TestValidateKeySpec(t *testing.T) {
+	require.NoError(t, validateKeySpec(providerAWSKMS, keyTypeAES256, purposeEncrypt))
+	require.NoError(t, validateKeySpec(providerGCPCKMS, keyTypeRSA4096, purposeSign))
+	require.Error(t, validateKeySpec(providerAzureKeyVault, keyTypeAES256, purposeEncrypt))
+	require.Error(t, validateKeySpec(providerAWSKMS, keyTypeECDSAP256, purposeEncrypt))
+	require.Error(t, validateKeySpec(providerAWSKMS, "des", purposeEncrypt))
+}
@@ -0,0 +1,539 @@
+package keymgmt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/locksutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const (
+	keyPrefix = "key/"
+
+	keyStatusActive          = "active"
+	keyStatusPendingDeletion = "pending_deletion"
+)
+
+func pathKeysList(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "key/?",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixKeyManagement,
+			OperationSuffix: "keys",
+			Navigation:      true,
+			ItemType:        "Key",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"after": {
+				Type:        framework.TypeString,
+				Description: `Optional entry to list begin listing after, not required to exist.`,
+			},
+			"limit": {
+				Type:        framework.TypeInt,
+				Description: `Optional number of entries to return; defaults to all entries.`,
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ListOperation: b.pathKeyList,
+		},
+
+		HelpSynopsis:    pathKeyHelpSyn,
+		HelpDescription: pathKeyHelpDesc,
+	}
+}
+
+func pathKeys(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "key/" + framework.GenericNameRegex("name"),
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixKeyManagement,
+			OperationSuffix: "key",
+			Action:          "Create",
+			ItemType:        "Key",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Name of the key, also used as its name in the KMS.",
+			},
+
+			"role": {
+				Type:        framework.TypeString,
+				Description: "Name of the role to create the key from.",
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.DeleteOperation: b.pathKeyDelete,
+			logical.ReadOperation:   b.pathKeyRead,
+			logical.UpdateOperation: b.pathKeyUpdate,
+			logical.CreateOperation: b.pathKeyCreate,
+		},
+
+		ExistenceCheck: b.keyExistenceCheck,
+
+		HelpSynopsis:    pathKeyHelpSyn,
+		HelpDescription: pathKeyHelpDesc,
+	}
+}
+
+func pathKeyRotate(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "key/" + framework.GenericNameRegex("name") + "/rotate",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixKeyManagement,
+			OperationVerb:   "rotate",
+			OperationSuffix: "key",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Name of the key.",
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.UpdateOperation: &framework.PathOperation{
+				Callback: b.pathKeyRotateWrite,
+			},
+		},
+
+		HelpSynopsis:    pathKeyRotateHelpSyn,
+		HelpDescription: pathKeyRotateHelpDesc,
+	}
+}
+
+type keyVersion struct {
+	// ID identifies the version in the KMS: the key ID for AWS KMS, the
+	// crypto key version name for GCP Cloud KMS, and the key identifier URL
+	// for Azure Key Vault.
+	ID           string    `json:"id"`
+	CreationTime time.Time `json:"creation_time"`
+}
+
+type keyEntry struct {
+	Role     string       `json:"role"`
+	KMS      string       `json:"kms"`
+	Provider string       `json:"provider"`
+	KeyType  string       `json:"key_type"`
+	Purpose  string       `json:"purpose"`
+	Versions []keyVersion `json:"versions"`
+
+	LastRotated       time.Time `json:"last_rotated"`
+	LastRotationError string    `json:"last_rotation_error"`
+
+	Status       string    `json:"status"`
+	DeletionDate time.Time `json:"deletion_date"`
+}
+
+func (k *keyEntry) spec(role *roleEntry) keySpec {
+	spec := keySpec{
+		Type:           k.KeyType,
+		Purpose:        k.Purpose,
+		DeletionWindow: defaultDeletionWindow,
+	}
+	if role != nil {
+		spec.DeletionWindow = role.DeletionWindow
+	}
+	return spec
+}
+
+// nextRotation returns when the key is due for rotation under the role, or
+// the zero time if it is not rotated periodically.
+func (k *keyEntry) nextRotation(role *roleEntry) time.Time {
+	if k.Status != keyStatusActive || role == nil || role.RotationPeriod == 0 {
+		return time.Time{}
+	}
+	return k.LastRotated.Add(role.RotationPeriod)
+}
+
+func (b *backend) keyExistenceCheck(ctx context.Context, req *logical.Request, d *framework.FieldData) (bool, error) {
+	key, err := b.key(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return false, err
+	}
+
+	return key != nil, nil
+}
+
+func (b *backend) key(ctx context.Context, s logical.Storage, name string) (*keyEntry, error) {
+	if name == "" {
+		return nil, errors.New("missing key name")
+	}
+
+	entry, err := s.Get(ctx, keyPrefix+strings.ToLower(name))
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var result keyEntry
+	if err := entry.DecodeJSON(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (b *backend) putKey(ctx context.Context, s logical.Storage, name string, key *keyEntry) error {
+	entry, err := logical.StorageEntryJSON(keyPrefix+name, key)
+	if err != nil {
+		return err
+	}
+
+	return s.Put(ctx, entry)
+}
+
+// keysOf returns the names of the keys matching the filter.
+func (b *backend) keysOf(ctx context.Context, s logical.Storage, filter func(*keyEntry) bool) ([]string, error) {
+	names, err := s.List(ctx, keyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []string
+	for _, name := range names {
+		key, err := b.key(ctx, s, name)
+		if err != nil {
+			return nil, err
+		}
+		if key != nil && filter(key) {
+			matches = append(matches, name)
+		}
+	}
+
+	return matches, nil
+}
+
+func (b *backend) pathKeyList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	after := d.Get("after").(string)
+	limit := d.Get("limit").(int)
+	if limit <= 0 {
+		limit = -1
+	}
+
+	keys, err := req.Storage.ListPage(ctx, keyPrefix, after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return logical.ListResponse(keys), nil
+}
+
+func (b *backend) pathKeyRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	key, err := b.key(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, nil
+	}
+
+	role, err := b.role(ctx, req.Storage, key.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make(map[string]interface{}, len(key.Versions))
+	for i, version := range key.Versions {
+		versions[strconv.Itoa(i+1)] = map[string]interface{}{
+			"id":            version.ID,
+			"creation_time": version.CreationTime.Format(time.RFC3339),
+		}
+	}
+
+	data := map[string]interface{}{
+		"role":                key.Role,
+		"kms":                 key.KMS,
+		"provider":            key.Provider,
+		"key_type":            key.KeyType,
+		"purpose":             key.Purpose,
+		"status":              key.Status,
+		"versions":            versions,
+		"latest_version":      len(key.Versions),
+		"last_rotated":        key.LastRotated.Format(time.RFC3339),
+		"last_rotation_error": key.LastRotationError,
+		"rotation_overdue":    false,
+	}
+	if next := key.nextRotation(role); !next.IsZero() {
+		data["next_rotation"] = next.Format(time.RFC3339)
+		data["rotation_overdue"] = time.Now().After(next)
+	}
+	if key.Status == keyStatusPendingDeletion {
+		data["deletion_date"] = key.DeletionDate.Format(time.RFC3339)
+	}
+
+	resp := &logical.Response{
+		Data: data,
+	}
+	if role == nil && key.Status == keyStatusActive {
+		resp.AddWarning(fmt.Sprintf("Role %q no longer exists, the key is not rotated periodically.", key.Role))
+	}
+
+	return resp, nil
+}
+
+func (b *backend) pathKeyCreate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := strings.ToLower(d.Get("name").(string))
+
+	roleName := strings.ToLower(d.Get("role").(string))
+	if roleName == "" {
+		return logical.ErrorResponse("role must be set"), logical.ErrInvalidRequest
+	}
+	role, err := b.role(ctx, req.Storage, roleName)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return logical.CodedErrorResponse(logical.ErrorCodeNotFound, "unknown role %q", roleName), logical.ErrInvalidRequest
+	}
+	kms, err := b.kms(ctx, req.Storage, role.KMS)
+	if err != nil {
+		return nil, err
+	}
+	if kms == nil {
+		return logical.ErrorResponse(fmt.Sprintf("unknown KMS provider %q", role.KMS)), logical.ErrInvalidRequest
+	}
+
+	lock := locksutil.LockForKey(b.keyLocks, name)
+	lock.Lock()
+	defer lock.Unlock()
+
+	existing, err := b.key(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return logical.ErrorResponse(fmt.Sprintf("key %q already exists", name)), logical.ErrInvalidRequest
+	}
+
+	key := &keyEntry{
+		Role:     roleName,
+		KMS:      role.KMS,
+		Provider: kms.Provider,
+		KeyType:  role.KeyType,
+		Purpose:  role.Purpose,
+		Status:   keyStatusActive,
+	}
+
+	client, err := kms.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id, err := client.CreateKey(ctx, name, key.spec(role))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	key.Versions = []keyVersion{{ID: id, CreationTime: now}}
+	key.LastRotated = now
+	if err := b.putKey(ctx, req.Storage, name, key); err != nil {
+		b.Logger().Error("failed to store created key, the key exists in the KMS but is not tracked", "key", name, "id", id, "error", err)
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (b *backend) pathKeyUpdate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	return logical.ErrorResponse("keys cannot be updated, rotate them at key/:name/rotate or change their role"), logical.ErrInvalidRequest
+}
+
+func (b *backend) pathKeyDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := strings.ToLower(d.Get("name").(string))
+
+	lock := locksutil.LockForKey(b.keyLocks, name)
+	lock.Lock()
+	defer lock.Unlock()
+
+	key, err := b.key(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil || key.Status == keyStatusPendingDeletion {
+		return nil, nil
+	}
+
+	role, err := b.role(ctx, req.Storage, key.Role)
+	if err != nil {
+		return nil, err
+	}
+	client, err := b.keyClient(ctx, req.Storage, key)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(key.Versions))
+	for _, version := range key.Versions {
+		ids = append(ids, version.ID)
+	}
+	deletionDate, err := client.ScheduleDeletion(ctx, name, ids, key.spec(role))
+	if err != nil {
+		return nil, err
+	}
+
+	// The key stays in the inventory until its deletion date
+	key.Status = keyStatusPendingDeletion
+	key.DeletionDate = deletionDate
+	if err := b.putKey(ctx, req.Storage, name, key); err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"deletion_date": deletionDate.Format(time.RFC3339),
+		},
+	}, nil
+}
+
+func (b *backend) pathKeyRotateWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := strings.ToLower(d.Get("name").(string))
+
+	lock := locksutil.LockForKey(b.keyLocks, name)
+	lock.Lock()
+	defer lock.Unlock()
+
+	key, err := b.key(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return logical.ErrorResponse(fmt.Sprintf("unknown key %q", name)), logical.ErrInvalidRequest
+	}
+	if key.Status != keyStatusActive {
+		return logical.ErrorResponse(fmt.Sprintf("key %q is pending deletion", name)), logical.ErrInvalidRequest
+	}
+
+	if err := b.rotateKey(ctx, req.Storage, name, key); err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"latest_version": len(key.Versions),
+		},
+	}, nil
+}
+
+// keyClient returns a client for the KMS of the key.
+func (b *backend) keyClient(ctx context.Context, s logical.Storage, key *keyEntry) (kmsClient, error) {
+	kms, err := b.kms(ctx, s, key.KMS)
+	if err != nil {
+		return nil, err
+	}
+	if kms == nil {
+		return nil, fmt.Errorf("KMS provider %q no longer exists", key.KMS)
+	}
+	return kms.client(ctx)
+}
+
+// rotateKey rotates the key in its KMS and stores the outcome, whether the
+// rotation succeeded or not. Callers must hold the lock of the key.
+func (b *backend) rotateKey(ctx context.Context, s logical.Storage, name string, key *keyEntry) error {
+	role, err := b.role(ctx, s, key.Role)
+	if err != nil {
+		return err
+	}
+
+	var id string
+	client, err := b.keyClient(ctx, s, key)
+	if err == nil {
+		id, err = client.RotateKey(ctx, name, key.spec(role))
+	}
+	if err != nil {
+		key.LastRotationError = err.Error()
+		if putErr := b.putKey(ctx, s, name, key); putErr != nil {
+			b.Logger().Error("failed to store rotation error", "key", name, "error", putErr)
+		}
+		return err
+	}
+
+	now := time.Now().UTC()
+	key.Versions = append(key.Versions, keyVersion{ID: id, CreationTime: now})
+	key.LastRotated = now
+	key.LastRotationError = ""
+	if err := b.putKey(ctx, s, name, key); err != nil {
+		b.Logger().Error("failed to store rotated key, the new version exists in the KMS but is not tracked", "key", name, "id", id, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// tidyKey rotates the key if it is due for rotation, and forgets it once it
+// is past its deletion date.
+func (b *backend) tidyKey(ctx context.Context, s logical.Storage, name string) error {
+	lock := locksutil.LockForKey(b.keyLocks, name)
+	lock.Lock()
+	defer lock.Unlock()
+
+	key, err := b.key(ctx, s, name)
+	if err != nil {
+		return err
+	}
+	if key == nil {
+		return nil
+	}
+
+	if key.Status == keyStatusPendingDeletion {
+		if time.Now().After(key.DeletionDate) {
+			b.Logger().Info("forgetting deleted key", "key", name)
+			return s.Delete(ctx, keyPrefix+name)
+		}
+		return nil
+	}
+
+	role, err := b.role(ctx, s, key.Role)
+	if err != nil {
+		return err
+	}
+	next := key.nextRotation(role)
+	if next.IsZero() || time.Now().Before(next) {
+		return nil
+	}
+
+	b.Logger().Info("rotating key", "key", name)
+	return b.rotateKey(ctx, s, name, key)
+}
+
+const pathKeyHelpSyn = `
+Manage the keys created in the KMS providers.
+`
+
+const pathKeyHelpDesc = `
+This endpoint creates a key in the KMS provider of the given role, reads the
+metadata and rotation status of a key, and schedules its deletion.
+
+Scheduling the deletion of a key schedules the deletion of all its versions.
+The key stays listed, with its deletion date, until it is deleted by the
+KMS.
+`
+
+const pathKeyRotateHelpSyn = `
+Rotate a key.
+`
+
+const pathKeyRotateHelpDesc = `
+This endpoint creates a new version of the key in its KMS, and makes it the
+primary one. Keys are also rotated when the rotation period of their role
+elapses.
+
+In AWS KMS, keys cannot be rotated on demand: a rotation creates a new KMS
+key and points the alias/openbao/<name> alias at it.
+`
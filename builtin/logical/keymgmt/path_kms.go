@@ -0,0 +1,264 @@
+package keymgmt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const kmsPrefix = "kms/"
+
+func pathKMSList(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "kms/?",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixKeyManagement,
+			OperationSuffix: "kms-providers",
+			Navigation:      true,
+			ItemType:        "KMS Provider",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"after": {
+				Type:        framework.TypeString,
+				Description: `Optional entry to list begin listing after, not required to exist.`,
+			},
+			"limit": {
+				Type:        framework.TypeInt,
+				Description: `Optional number of entries to return; defaults to all entries.`,
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ListOperation: b.pathKMSList,
+		},
+
+		HelpSynopsis:    pathKMSHelpSyn,
+		HelpDescription: pathKMSHelpDesc,
+	}
+}
+
+func pathKMS(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "kms/" + framework.GenericNameRegex("name"),
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixKeyManagement,
+			OperationSuffix: "kms-provider",
+			Action:          "Create",
+			ItemType:        "KMS Provider",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Name of the KMS provider.",
+			},
+
+			"provider": {
+				Type:          framework.TypeString,
+				Description:   `Type of the KMS: "awskms", "azurekeyvault" or "gcpckms". Cannot be changed once set.`,
+				AllowedValues: []interface{}{providerAWSKMS, providerAzureKeyVault, providerGCPCKMS},
+			},
+
+			"key_collection": {
+				Type:        framework.TypeString,
+				Description: "Location of the keys in the KMS: the AWS region, the URI of the Azure key vault, or the resource name of the GCP key ring. Cannot be changed once set.",
+			},
+
+			"credentials": {
+				Type:        framework.TypeKVPairs,
+				Description: `Credentials of the KMS. "access_key", "secret_key" and "session_token" for AWS KMS; "tenant_id", "client_id" and "client_secret" for Azure Key Vault; "credentials", the JSON service account key, for GCP Cloud KMS. Without credentials, the default credentials of the environment are used.`,
+				DisplayAttrs: &framework.DisplayAttributes{
+					Sensitive: true,
+				},
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.DeleteOperation: b.pathKMSDelete,
+			logical.ReadOperation:   b.pathKMSRead,
+			logical.UpdateOperation: b.pathKMSWrite,
+			logical.CreateOperation: b.pathKMSWrite,
+		},
+
+		ExistenceCheck: b.kmsExistenceCheck,
+
+		HelpSynopsis:    pathKMSHelpSyn,
+		HelpDescription: pathKMSHelpDesc,
+	}
+}
+
+type kmsEntry struct {
+	Provider      string            `json:"provider"`
+	KeyCollection string            `json:"key_collection"`
+	Credentials   map[string]string `json:"credentials"`
+}
+
+// client returns a client for the KMS.
+func (k *kmsEntry) client(ctx context.Context) (kmsClient, error) {
+	provider, ok := kmsProviders[k.Provider]
+	if !ok {
+		return nil, fmt.Errorf("unsupported provider %q", k.Provider)
+	}
+	return provider.newClient(ctx, k.KeyCollection, k.Credentials)
+}
+
+func (b *backend) kmsExistenceCheck(ctx context.Context, req *logical.Request, d *framework.FieldData) (bool, error) {
+	kms, err := b.kms(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return false, err
+	}
+
+	return kms != nil, nil
+}
+
+func (b *backend) kms(ctx context.Context, s logical.Storage, name string) (*kmsEntry, error) {
+	if name == "" {
+		return nil, errors.New("missing KMS provider name")
+	}
+
+	entry, err := s.Get(ctx, kmsPrefix+strings.ToLower(name))
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var result kmsEntry
+	if err := entry.DecodeJSON(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (b *backend) pathKMSList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	after := d.Get("after").(string)
+	limit := d.Get("limit").(int)
+	if limit <= 0 {
+		limit = -1
+	}
+
+	names, err := req.Storage.ListPage(ctx, kmsPrefix, after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return logical.ListResponse(names), nil
+}
+
+func (b *backend) pathKMSDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := strings.ToLower(d.Get("name").(string))
+
+	// The keys of a KMS provider can no longer be rotated or deleted once it
+	// is gone
+	keys, err := b.keysOf(ctx, req.Storage, func(key *keyEntry) bool {
+		return key.KMS == name
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) > 0 {
+		return logical.ErrorResponse(fmt.Sprintf("KMS provider %q still manages keys: %s", name, strings.Join(keys, ", "))), logical.ErrInvalidRequest
+	}
+
+	if err := req.Storage.Delete(ctx, kmsPrefix+name); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (b *backend) pathKMSRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	kms, err := b.kms(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return nil, err
+	}
+	if kms == nil {
+		return nil, nil
+	}
+
+	// Credentials are never returned, only which ones are set
+	credentials := make([]string, 0, len(kms.Credentials))
+	for k := range kms.Credentials {
+		credentials = append(credentials, k)
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"provider":       kms.Provider,
+			"key_collection": kms.KeyCollection,
+			"credentials":    credentials,
+		},
+	}, nil
+}
+
+func (b *backend) pathKMSWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := strings.ToLower(d.Get("name").(string))
+	kms, err := b.kms(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := d.Get("provider").(string)
+	keyCollection := d.Get("key_collection").(string)
+
+	// Due to existence check, kms will only be nil if it's a create operation
+	if kms == nil {
+		if provider == "" {
+			return logical.ErrorResponse("provider must be set"), logical.ErrInvalidRequest
+		}
+		if _, ok := kmsProviders[provider]; !ok {
+			return logical.ErrorResponse(fmt.Sprintf("unsupported provider %q, must be one of %s", provider, strings.Join(providerNames(), ", "))), logical.ErrInvalidRequest
+		}
+		if keyCollection == "" {
+			return logical.ErrorResponse("key_collection must be set"), logical.ErrInvalidRequest
+		}
+		kms = &kmsEntry{
+			Provider:      provider,
+			KeyCollection: keyCollection,
+		}
+	} else {
+		// The keys are tracked in the key collection they were created in
+		if provider != "" && provider != kms.Provider {
+			return logical.ErrorResponse("provider cannot be changed"), logical.ErrInvalidRequest
+		}
+		if keyCollection != "" && keyCollection != kms.KeyCollection {
+			return logical.ErrorResponse("key_collection cannot be changed"), logical.ErrInvalidRequest
+		}
+	}
+
+	if raw, ok := d.GetOk("credentials"); ok {
+		kms.Credentials = raw.(map[string]string)
+	}
+
+	// Check the configuration by creating a client
+	if _, err := kms.client(ctx); err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	entry, err := logical.StorageEntryJSON(kmsPrefix+name, kms)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, req.Storage.Put(ctx, entry)
+}
+
+const pathKMSHelpSyn = `
+Manage the KMS providers keys are created in.
+`
+
+const pathKMSHelpDesc = `
+This endpoint allows you to create, read, update, and delete KMS providers.
+A KMS provider is a key collection of a cloud KMS: an AWS KMS region, an
+Azure key vault or a GCP Cloud KMS key ring, with the credentials to manage
+its keys.
+`
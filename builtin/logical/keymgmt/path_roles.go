@@ -0,0 +1,266 @@
+package keymgmt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const (
+	rolePrefix = "role/"
+
+	minDeletionWindow     = 7 * 24 * time.Hour
+	maxDeletionWindow     = 30 * 24 * time.Hour
+	defaultDeletionWindow = maxDeletionWindow
+
+	// minRotationPeriod keeps the periodic rotations from hammering the KMS
+	minRotationPeriod = time.Hour
+)
+
+func pathRolesList(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "role/?",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixKeyManagement,
+			OperationSuffix: "roles",
+			Navigation:      true,
+			ItemType:        "Role",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"after": {
+				Type:        framework.TypeString,
+				Description: `Optional entry to list begin listing after, not required to exist.`,
+			},
+			"limit": {
+				Type:        framework.TypeInt,
+				Description: `Optional number of entries to return; defaults to all entries.`,
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ListOperation: b.pathRoleList,
+		},
+
+		HelpSynopsis:    pathRoleHelpSyn,
+		HelpDescription: pathRoleHelpDesc,
+	}
+}
+
+func pathRoles(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "role/" + framework.GenericNameRegex("name"),
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixKeyManagement,
+			OperationSuffix: "role",
+			Action:          "Create",
+			ItemType:        "Role",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Name of the role.",
+			},
+
+			"kms": {
+				Type:        framework.TypeString,
+				Description: "Name of the KMS provider the keys of the role are created in.",
+			},
+
+			"key_type": {
+				Type:        framework.TypeString,
+				Description: `Type of the keys: "aes256", "rsa-2048", "rsa-3072", "rsa-4096", "ecdsa-p256" or "ecdsa-p384". Azure Key Vault does not support "aes256".`,
+			},
+
+			"purpose": {
+				Type:          framework.TypeString,
+				Description:   `Purpose of the keys: "encrypt" or "sign". AES keys can only encrypt, and ECDSA keys can only sign. Defaults to "encrypt".`,
+				AllowedValues: []interface{}{purposeEncrypt, purposeSign},
+			},
+
+			"rotation_period": {
+				Type:        framework.TypeDurationSecond,
+				Description: "Period after which the keys are rotated. If unset, keys are only rotated on demand.",
+			},
+
+			"deletion_window": {
+				Type:        framework.TypeDurationSecond,
+				Description: "Time between the scheduling of the deletion of a key and its deletion by the KMS, between 7 and 30 days. Not supported by Azure Key Vault, where it is set on the vault. Defaults to 30 days.",
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.DeleteOperation: b.pathRoleDelete,
+			logical.ReadOperation:   b.pathRoleRead,
+			logical.UpdateOperation: b.pathRoleWrite,
+			logical.CreateOperation: b.pathRoleWrite,
+		},
+
+		ExistenceCheck: b.roleExistenceCheck,
+
+		HelpSynopsis:    pathRoleHelpSyn,
+		HelpDescription: pathRoleHelpDesc,
+	}
+}
+
+type roleEntry struct {
+	KMS            string        `json:"kms"`
+	KeyType        string        `json:"key_type"`
+	Purpose        string        `json:"purpose"`
+	RotationPeriod time.Duration `json:"rotation_period"`
+	DeletionWindow time.Duration `json:"deletion_window"`
+}
+
+func (b *backend) roleExistenceCheck(ctx context.Context, req *logical.Request, d *framework.FieldData) (bool, error) {
+	role, err := b.role(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return false, err
+	}
+
+	return role != nil, nil
+}
+
+func (b *backend) role(ctx context.Context, s logical.Storage, name string) (*roleEntry, error) {
+	if name == "" {
+		return nil, errors.New("missing role name")
+	}
+
+	entry, err := s.Get(ctx, rolePrefix+strings.ToLower(name))
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var result roleEntry
+	if err := entry.DecodeJSON(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (b *backend) pathRoleList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	after := d.Get("after").(string)
+	limit := d.Get("limit").(int)
+	if limit <= 0 {
+		limit = -1
+	}
+
+	roles, err := req.Storage.ListPage(ctx, rolePrefix, after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return logical.ListResponse(roles), nil
+}
+
+func (b *backend) pathRoleDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if err := req.Storage.Delete(ctx, rolePrefix+strings.ToLower(d.Get("name").(string))); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (b *backend) pathRoleRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	role, err := b.role(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, nil
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"kms":             role.KMS,
+			"key_type":        role.KeyType,
+			"purpose":         role.Purpose,
+			"rotation_period": int64(role.RotationPeriod.Seconds()),
+			"deletion_window": int64(role.DeletionWindow.Seconds()),
+		},
+	}, nil
+}
+
+func (b *backend) pathRoleWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := strings.ToLower(d.Get("name").(string))
+	role, err := b.role(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	// Due to existence check, role will only be nil if it's a create operation
+	if role == nil {
+		role = &roleEntry{
+			Purpose:        purposeEncrypt,
+			DeletionWindow: defaultDeletionWindow,
+		}
+	}
+
+	if raw, ok := d.GetOk("kms"); ok {
+		role.KMS = strings.ToLower(raw.(string))
+	}
+	if raw, ok := d.GetOk("key_type"); ok {
+		role.KeyType = raw.(string)
+	}
+	if raw, ok := d.GetOk("purpose"); ok {
+		role.Purpose = raw.(string)
+	}
+	if raw, ok := d.GetOk("rotation_period"); ok {
+		role.RotationPeriod = time.Duration(raw.(int)) * time.Second
+	}
+	if raw, ok := d.GetOk("deletion_window"); ok {
+		role.DeletionWindow = time.Duration(raw.(int)) * time.Second
+	}
+
+	if role.KMS == "" {
+		return logical.ErrorResponse("kms must be set"), logical.ErrInvalidRequest
+	}
+	kms, err := b.kms(ctx, req.Storage, role.KMS)
+	if err != nil {
+		return nil, err
+	}
+	if kms == nil {
+		return logical.ErrorResponse(fmt.Sprintf("unknown KMS provider %q", role.KMS)), logical.ErrInvalidRequest
+	}
+	if role.KeyType == "" {
+		return logical.ErrorResponse("key_type must be set"), logical.ErrInvalidRequest
+	}
+	if err := validateKeySpec(kms.Provider, role.KeyType, role.Purpose); err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+	if role.RotationPeriod != 0 && role.RotationPeriod < minRotationPeriod {
+		return logical.ErrorResponse(fmt.Sprintf("rotation_period must be at least %s", minRotationPeriod)), logical.ErrInvalidRequest
+	}
+	if role.DeletionWindow < minDeletionWindow || role.DeletionWindow > maxDeletionWindow {
+		return logical.ErrorResponse("deletion_window must be between 7 and 30 days"), logical.ErrInvalidRequest
+	}
+
+	entry, err := logical.StorageEntryJSON(rolePrefix+name, role)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, req.Storage.Put(ctx, entry)
+}
+
+const pathRoleHelpSyn = `
+Manage the roles keys are created from.
+`
+
+const pathRoleHelpDesc = `
+This endpoint allows you to create, read, update, and delete roles. A role
+sets the KMS provider, type and purpose of the keys created from it, how
+often they are rotated, and how long their deletion is delayed. Changes to
+the rotation period and deletion window apply to the existing keys of the
+role.
+`
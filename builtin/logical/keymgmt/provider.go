@@ -0,0 +1,121 @@
+package keymgmt
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/helper/strutil"
+)
+
+const (
+	providerAWSKMS        = "awskms"
+	providerAzureKeyVault = "azurekeyvault"
+	providerGCPCKMS       = "gcpckms"
+)
+
+// The types of the keys managed by the engine.
+const (
+	keyTypeAES256    = "aes256"
+	keyTypeRSA2048   = "rsa-2048"
+	keyTypeRSA3072   = "rsa-3072"
+	keyTypeRSA4096   = "rsa-4096"
+	keyTypeECDSAP256 = "ecdsa-p256"
+	keyTypeECDSAP384 = "ecdsa-p384"
+)
+
+// The purposes of the keys managed by the engine.
+const (
+	purposeEncrypt = "encrypt"
+	purposeSign    = "sign"
+)
+
+// keyTypePurposes holds the purposes each key type can be created for.
+var keyTypePurposes = map[string][]string{
+	keyTypeAES256:    {purposeEncrypt},
+	keyTypeRSA2048:   {purposeEncrypt, purposeSign},
+	keyTypeRSA3072:   {purposeEncrypt, purposeSign},
+	keyTypeRSA4096:   {purposeEncrypt, purposeSign},
+	keyTypeECDSAP256: {purposeSign},
+	keyTypeECDSAP384: {purposeSign},
+}
+
+// keySpec describes a key to create in a KMS.
+type keySpec struct {
+	Type    string
+	Purpose string
+
+	// DeletionWindow is the time between the scheduling of the deletion of
+	// the key and its actual deletion by the KMS, where the KMS allows
+	// setting it per key.
+	DeletionWindow time.Duration
+}
+
+// kmsClient manages keys in a cloud KMS. Keys are identified in the KMS by
+// the IDs returned on creation and rotation, each identifying a version of
+// the key.
+type kmsClient interface {
+	// CreateKey creates the key of the given name, returning the ID of its
+	// first version.
+	CreateKey(ctx context.Context, name string, spec keySpec) (string, error)
+
+	// RotateKey creates a new version of the key and makes it the primary
+	// one, returning its ID.
+	RotateKey(ctx context.Context, name string, spec keySpec) (string, error)
+
+	// ScheduleDeletion schedules the deletion of the given versions of the
+	// key, returning the time the key will be deleted at.
+	ScheduleDeletion(ctx context.Context, name string, versionIDs []string, spec keySpec) (time.Time, error)
+}
+
+type kmsProvider struct {
+	// newClient returns a client for the key collection of the KMS, that is
+	// the AWS region, the GCP key ring or the Azure key vault.
+	newClient func(ctx context.Context, keyCollection string, credentials map[string]string) (kmsClient, error)
+
+	// keyTypes are the key types the KMS supports.
+	keyTypes []string
+}
+
+var kmsProviders = map[string]kmsProvider{
+	providerAWSKMS: {
+		newClient: newAWSKMSClient,
+		keyTypes:  []string{keyTypeAES256, keyTypeRSA2048, keyTypeRSA3072, keyTypeRSA4096, keyTypeECDSAP256, keyTypeECDSAP384},
+	},
+	providerAzureKeyVault: {
+		newClient: newAzureKeyVaultClient,
+		keyTypes:  []string{keyTypeRSA2048, keyTypeRSA3072, keyTypeRSA4096, keyTypeECDSAP256, keyTypeECDSAP384},
+	},
+	providerGCPCKMS: {
+		newClient: newGCPCKMSClient,
+		keyTypes:  []string{keyTypeAES256, keyTypeRSA2048, keyTypeRSA3072, keyTypeRSA4096, keyTypeECDSAP256, keyTypeECDSAP384},
+	},
+}
+
+// providerNames returns the names of the supported providers, sorted.
+func providerNames() []string {
+	names := make([]string, 0, len(kmsProviders))
+	for name := range kmsProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateKeySpec checks that keys of the given type and purpose can be
+// created in the KMS of the given provider.
+func validateKeySpec(provider, keyType, purpose string) error {
+	purposes, ok := keyTypePurposes[keyType]
+	if !ok {
+		return fmt.Errorf("unsupported key type %q", keyType)
+	}
+	if !strutil.StrListContains(purposes, purpose) {
+		return fmt.Errorf("keys of type %q can only be used to %s", keyType, strings.Join(purposes, " or "))
+	}
+	if !strutil.StrListContains(kmsProviders[provider].keyTypes, keyType) {
+		return fmt.Errorf("%s does not support keys of type %q", provider, keyType)
+	}
+	return nil
+}
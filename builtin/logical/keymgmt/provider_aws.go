@@ -0,0 +1,134 @@
+package keymgmt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/hashicorp/go-cleanhttp"
+)
+
+// awsKMSClient manages keys in AWS KMS. AWS KMS keys cannot be rotated on
+// demand, so a key is the alias alias/openbao/<name>: a rotation creates a new
+// KMS key and points the alias at it. Without explicit credentials, the
+// default credential chain of the SDK is used.
+type awsKMSClient struct {
+	client *kms.KMS
+}
+
+func newAWSKMSClient(_ context.Context, region string, creds map[string]string) (kmsClient, error) {
+	awsConfig := aws.NewConfig().WithHTTPClient(cleanhttp.DefaultClient()).WithRegion(region)
+	if endpoint := creds["endpoint"]; endpoint != "" {
+		awsConfig = awsConfig.WithEndpoint(endpoint)
+	}
+
+	accessKey, secretKey := creds["access_key"], creds["secret_key"]
+	switch {
+	case accessKey != "" && secretKey != "":
+		awsConfig = awsConfig.WithCredentials(credentials.NewStaticCredentials(accessKey, secretKey, creds["session_token"]))
+	case accessKey != "" || secretKey != "":
+		return nil, errors.New("access_key and secret_key must be given together")
+	}
+
+	sess, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS session: %w", err)
+	}
+
+	return &awsKMSClient{
+		client: kms.New(sess),
+	}, nil
+}
+
+func awsKMSAlias(name string) string {
+	return "alias/openbao/" + name
+}
+
+func (c *awsKMSClient) createKMSKey(ctx context.Context, name string, spec keySpec) (string, error) {
+	keySpec := map[string]string{
+		keyTypeAES256:    kms.KeySpecSymmetricDefault,
+		keyTypeRSA2048:   kms.KeySpecRsa2048,
+		keyTypeRSA3072:   kms.KeySpecRsa3072,
+		keyTypeRSA4096:   kms.KeySpecRsa4096,
+		keyTypeECDSAP256: kms.KeySpecEccNistP256,
+		keyTypeECDSAP384: kms.KeySpecEccNistP384,
+	}[spec.Type]
+	keyUsage := kms.KeyUsageTypeEncryptDecrypt
+	if spec.Purpose == purposeSign {
+		keyUsage = kms.KeyUsageTypeSignVerify
+	}
+
+	out, err := c.client.CreateKeyWithContext(ctx, &kms.CreateKeyInput{
+		Description: aws.String(fmt.Sprintf("OpenBao managed key %q", name)),
+		KeySpec:     aws.String(keySpec),
+		KeyUsage:    aws.String(keyUsage),
+		Tags: []*kms.Tag{
+			{TagKey: aws.String("openbao-key"), TagValue: aws.String(name)},
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create AWS KMS key: %w", err)
+	}
+	return aws.StringValue(out.KeyMetadata.KeyId), nil
+}
+
+func (c *awsKMSClient) CreateKey(ctx context.Context, name string, spec keySpec) (string, error) {
+	id, err := c.createKMSKey(ctx, name, spec)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = c.client.CreateAliasWithContext(ctx, &kms.CreateAliasInput{
+		AliasName:   aws.String(awsKMSAlias(name)),
+		TargetKeyId: aws.String(id),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create AWS KMS alias: %w", err)
+	}
+	return id, nil
+}
+
+func (c *awsKMSClient) RotateKey(ctx context.Context, name string, spec keySpec) (string, error) {
+	id, err := c.createKMSKey(ctx, name, spec)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = c.client.UpdateAliasWithContext(ctx, &kms.UpdateAliasInput{
+		AliasName:   aws.String(awsKMSAlias(name)),
+		TargetKeyId: aws.String(id),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to update AWS KMS alias: %w", err)
+	}
+	return id, nil
+}
+
+func (c *awsKMSClient) ScheduleDeletion(ctx context.Context, name string, versionIDs []string, spec keySpec) (time.Time, error) {
+	_, err := c.client.DeleteAliasWithContext(ctx, &kms.DeleteAliasInput{
+		AliasName: aws.String(awsKMSAlias(name)),
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to delete AWS KMS alias: %w", err)
+	}
+
+	var deletionDate time.Time
+	for _, id := range versionIDs {
+		out, err := c.client.ScheduleKeyDeletionWithContext(ctx, &kms.ScheduleKeyDeletionInput{
+			KeyId:               aws.String(id),
+			PendingWindowInDays: aws.Int64(int64(spec.DeletionWindow / (24 * time.Hour))),
+		})
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to schedule the deletion of AWS KMS key %q: %w", id, err)
+		}
+		if date := aws.TimeValue(out.DeletionDate); date.After(deletionDate) {
+			deletionDate = date
+		}
+	}
+	return deletionDate, nil
+}
@@ -0,0 +1,171 @@
+package keymgmt
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/hashicorp/go-cleanhttp"
+)
+
+const (
+	azureKeyVaultAPIVersion = "7.4"
+	azureKeyVaultScope      = "https://vault.azure.net/.default"
+)
+
+// azureKeyVaultClient manages the keys of an Azure Key Vault, given by its
+// URI, through its REST API. Without explicit client credentials, the default
+// Azure credential chain is used.
+type azureKeyVaultClient struct {
+	vaultURI   string
+	credential azcore.TokenCredential
+	client     *http.Client
+}
+
+// azureKeyBundle is the subset of the key bundles returned by the Key Vault
+// API used by the client.
+type azureKeyBundle struct {
+	Key struct {
+		KID string `json:"kid"`
+	} `json:"key"`
+
+	// ScheduledPurgeDate is only set on deleted keys, as a Unix time
+	ScheduledPurgeDate int64 `json:"scheduledPurgeDate"`
+}
+
+func newAzureKeyVaultClient(_ context.Context, vaultURI string, creds map[string]string) (kmsClient, error) {
+	var credential azcore.TokenCredential
+	var err error
+	tenantID, clientID, clientSecret := creds["tenant_id"], creds["client_id"], creds["client_secret"]
+	switch {
+	case tenantID != "" && clientID != "" && clientSecret != "":
+		credential, err = azidentity.NewClientSecretCredential(tenantID, clientID, clientSecret, nil)
+	case tenantID != "" || clientID != "" || clientSecret != "":
+		return nil, errors.New("tenant_id, client_id and client_secret must be given together")
+	default:
+		credential, err = azidentity.NewDefaultAzureCredential(nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Azure credential: %w", err)
+	}
+
+	return &azureKeyVaultClient{
+		vaultURI:   strings.TrimSuffix(vaultURI, "/"),
+		credential: credential,
+		client:     cleanhttp.DefaultClient(),
+	}, nil
+}
+
+func (c *azureKeyVaultClient) do(ctx context.Context, method, path string, body interface{}) (*azureKeyBundle, error) {
+	token, err := c.credential.GetToken(ctx, policy.TokenRequestOptions{
+		Scopes: []string{azureKeyVaultScope},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Azure token: %w", err)
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(encoded)
+	}
+
+	reqURL := fmt.Sprintf("%s%s?api-version=%s", c.vaultURI, path, azureKeyVaultAPIVersion)
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.Token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, respBody)
+	}
+
+	var bundle azureKeyBundle
+	if err := json.NewDecoder(resp.Body).Decode(&bundle); err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+func (c *azureKeyVaultClient) CreateKey(ctx context.Context, name string, spec keySpec) (string, error) {
+	params := map[string]interface{}{
+		"tags": map[string]string{
+			"openbao-managed": "true",
+		},
+	}
+	switch spec.Type {
+	case keyTypeRSA2048, keyTypeRSA3072, keyTypeRSA4096:
+		params["kty"] = "RSA"
+		params["key_size"] = map[string]int{
+			keyTypeRSA2048: 2048,
+			keyTypeRSA3072: 3072,
+			keyTypeRSA4096: 4096,
+		}[spec.Type]
+	case keyTypeECDSAP256:
+		params["kty"] = "EC"
+		params["crv"] = "P-256"
+	case keyTypeECDSAP384:
+		params["kty"] = "EC"
+		params["crv"] = "P-384"
+	default:
+		return "", fmt.Errorf("unsupported key type %q", spec.Type)
+	}
+	if spec.Purpose == purposeSign {
+		params["key_ops"] = []string{"sign", "verify"}
+	} else {
+		params["key_ops"] = []string{"encrypt", "decrypt", "wrapKey", "unwrapKey"}
+	}
+
+	bundle, err := c.do(ctx, http.MethodPost, "/keys/"+url.PathEscape(name)+"/create", params)
+	if err != nil {
+		return "", fmt.Errorf("failed to create Azure Key Vault key: %w", err)
+	}
+	return bundle.Key.KID, nil
+}
+
+func (c *azureKeyVaultClient) RotateKey(ctx context.Context, name string, _ keySpec) (string, error) {
+	bundle, err := c.do(ctx, http.MethodPost, "/keys/"+url.PathEscape(name)+"/rotate", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to rotate Azure Key Vault key: %w", err)
+	}
+	return bundle.Key.KID, nil
+}
+
+func (c *azureKeyVaultClient) ScheduleDeletion(ctx context.Context, name string, _ []string, _ keySpec) (time.Time, error) {
+	// Deleting a key deletes all its versions. The retention period of the
+	// deleted keys is set on the vault, not per key.
+	bundle, err := c.do(ctx, http.MethodDelete, "/keys/"+url.PathEscape(name), nil)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to delete Azure Key Vault key: %w", err)
+	}
+
+	// Vaults without soft-delete delete keys immediately
+	if bundle.ScheduledPurgeDate == 0 {
+		return time.Now().UTC(), nil
+	}
+	return time.Unix(bundle.ScheduledPurgeDate, 0).UTC(), nil
+}
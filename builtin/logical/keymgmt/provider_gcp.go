@@ -0,0 +1,131 @@
+package keymgmt
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	cloudkms "google.golang.org/api/cloudkms/v1"
+	"google.golang.org/api/option"
+)
+
+// gcpCKMSClient manages the crypto keys of a GCP Cloud KMS key ring, given as
+// projects/<project>/locations/<location>/keyRings/<key ring>. Without
+// explicit credentials, the application default credentials are used.
+type gcpCKMSClient struct {
+	keyRing string
+	service *cloudkms.Service
+}
+
+func newGCPCKMSClient(ctx context.Context, keyRing string, creds map[string]string) (kmsClient, error) {
+	var opts []option.ClientOption
+	if credentials := creds["credentials"]; credentials != "" {
+		opts = append(opts, option.WithCredentialsJSON([]byte(credentials)))
+	}
+	if endpoint := creds["endpoint"]; endpoint != "" {
+		opts = append(opts, option.WithEndpoint(endpoint))
+	}
+
+	service, err := cloudkms.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCP Cloud KMS client: %w", err)
+	}
+
+	return &gcpCKMSClient{
+		keyRing: keyRing,
+		service: service,
+	}, nil
+}
+
+func (c *gcpCKMSClient) cryptoKeyName(name string) string {
+	return c.keyRing + "/cryptoKeys/" + name
+}
+
+func gcpCKMSAlgorithm(spec keySpec) string {
+	if spec.Purpose == purposeSign {
+		return map[string]string{
+			keyTypeRSA2048:   "RSA_SIGN_PKCS1_2048_SHA256",
+			keyTypeRSA3072:   "RSA_SIGN_PKCS1_3072_SHA256",
+			keyTypeRSA4096:   "RSA_SIGN_PKCS1_4096_SHA512",
+			keyTypeECDSAP256: "EC_SIGN_P256_SHA256",
+			keyTypeECDSAP384: "EC_SIGN_P384_SHA384",
+		}[spec.Type]
+	}
+	return map[string]string{
+		keyTypeAES256:  "GOOGLE_SYMMETRIC_ENCRYPTION",
+		keyTypeRSA2048: "RSA_DECRYPT_OAEP_2048_SHA256",
+		keyTypeRSA3072: "RSA_DECRYPT_OAEP_3072_SHA256",
+		keyTypeRSA4096: "RSA_DECRYPT_OAEP_4096_SHA512",
+	}[spec.Type]
+}
+
+func gcpCKMSPurpose(spec keySpec) string {
+	switch {
+	case spec.Purpose == purposeSign:
+		return "ASYMMETRIC_SIGN"
+	case spec.Type == keyTypeAES256:
+		return "ENCRYPT_DECRYPT"
+	default:
+		return "ASYMMETRIC_DECRYPT"
+	}
+}
+
+func (c *gcpCKMSClient) CreateKey(ctx context.Context, name string, spec keySpec) (string, error) {
+	cryptoKey := &cloudkms.CryptoKey{
+		Purpose: gcpCKMSPurpose(spec),
+		VersionTemplate: &cloudkms.CryptoKeyVersionTemplate{
+			Algorithm: gcpCKMSAlgorithm(spec),
+		},
+		DestroyScheduledDuration: fmt.Sprintf("%ds", int64(spec.DeletionWindow.Seconds())),
+		Labels: map[string]string{
+			"openbao-managed": "true",
+		},
+	}
+	_, err := c.service.Projects.Locations.KeyRings.CryptoKeys.Create(c.keyRing, cryptoKey).CryptoKeyId(name).Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("failed to create GCP Cloud KMS crypto key: %w", err)
+	}
+
+	// The initial version of a crypto key is always the first one
+	return c.cryptoKeyName(name) + "/cryptoKeyVersions/1", nil
+}
+
+func (c *gcpCKMSClient) RotateKey(ctx context.Context, name string, spec keySpec) (string, error) {
+	version, err := c.service.Projects.Locations.KeyRings.CryptoKeys.CryptoKeyVersions.Create(c.cryptoKeyName(name), &cloudkms.CryptoKeyVersion{}).Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("failed to create GCP Cloud KMS crypto key version: %w", err)
+	}
+
+	// Only symmetric keys have a primary version, asymmetric keys are used
+	// by version
+	if spec.Type == keyTypeAES256 {
+		_, err = c.service.Projects.Locations.KeyRings.CryptoKeys.UpdatePrimaryVersion(c.cryptoKeyName(name), &cloudkms.UpdateCryptoKeyPrimaryVersionRequest{
+			CryptoKeyVersionId: path.Base(version.Name),
+		}).Context(ctx).Do()
+		if err != nil {
+			return "", fmt.Errorf("failed to update the primary version of GCP Cloud KMS crypto key: %w", err)
+		}
+	}
+
+	return version.Name, nil
+}
+
+func (c *gcpCKMSClient) ScheduleDeletion(ctx context.Context, name string, versionIDs []string, spec keySpec) (time.Time, error) {
+	// Crypto keys cannot be deleted, only their versions can be destroyed
+	var deletionDate time.Time
+	for _, id := range versionIDs {
+		version, err := c.service.Projects.Locations.KeyRings.CryptoKeys.CryptoKeyVersions.Destroy(id, &cloudkms.DestroyCryptoKeyVersionRequest{}).Context(ctx).Do()
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to destroy GCP Cloud KMS crypto key version %q: %w", id, err)
+		}
+		date, err := time.Parse(time.RFC3339, version.DestroyTime)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid destroy time of GCP Cloud KMS crypto key version %q: %w", id, err)
+		}
+		if date.After(deletionDate) {
+			deletionDate = date
+		}
+	}
+	return deletionDate, nil
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"path"
+	"strings"
 	"sync"
 
 	"github.com/golang/protobuf/proto"
@@ -65,6 +66,20 @@ type versionedKVBackend struct {
 	globalConfig     *Configuration
 	globalConfigLock *sync.RWMutex
 
+	// validationConf is a cached value of the validation config, which is
+	// nil if validationConfLoaded and no validation config is set
+	validationConf       *validationConfig
+	validationConfLoaded bool
+	validationConfigLock sync.RWMutex
+
+	// syncDests is a cached value of the sync destinations by name, which is
+	// nil until loaded
+	syncDests            map[string]*syncDestination
+	syncDestinationsLock sync.RWMutex
+
+	// syncLocks serializes the pushes to each sync destination
+	syncLocks sync.Map
+
 	// upgradeCancelFunc is used to be able to shut down the upgrade checking
 	// goroutine from cleanup
 	upgradeCancelFunc context.CancelFunc
@@ -111,8 +126,9 @@ func VersionedKVFactory(ctx context.Context, conf *logical.BackendConfig) (logic
 		BackendType:    logical.TypeLogical,
 		RunningVersion: ReportedVersion,
 
-		Help:       backendHelp,
-		Invalidate: b.Invalidate,
+		Help:         backendHelp,
+		Invalidate:   b.Invalidate,
+		PeriodicFunc: b.syncPeriodic,
 
 		PathsSpecial: &logical.Paths{
 			SealWrapStorage: []string{
@@ -124,18 +140,23 @@ func VersionedKVFactory(ctx context.Context, conf *logical.BackendConfig) (logic
 
 				// Seal wrap the archived key policy
 				path.Join(b.storagePrefix, "archive") + "/",
+
+				// Seal wrap the sync destinations, holding their tokens
+				path.Join(b.storagePrefix, syncDestinationsPath) + "/",
 			},
 		},
 
 		Paths: framework.PathAppend(
 			[]*framework.Path{
 				pathConfig(b),
+				pathConfigValidation(b),
 				pathData(b),
 				pathMetadata(b),
 				pathDestroy(b),
 				pathSubkeys(b),
 			},
 			pathsDelete(b),
+			pathSyncDestinations(b),
 
 			// Make sure this stays at the end so that the valid paths are
 			// processed first.
@@ -229,6 +250,17 @@ func (b *versionedKVBackend) Invalidate(ctx context.Context, key string) {
 		b.globalConfigLock.Lock()
 		b.globalConfig = nil
 		b.globalConfigLock.Unlock()
+	case path.Join(b.storagePrefix, validationConfigPath):
+		b.validationConfigLock.Lock()
+		b.validationConf = nil
+		b.validationConfLoaded = false
+		b.validationConfigLock.Unlock()
+	default:
+		if strings.HasPrefix(key, path.Join(b.storagePrefix, syncDestinationsPath)+"/") {
+			b.syncDestinationsLock.Lock()
+			b.syncDests = nil
+			b.syncDestinationsLock.Unlock()
+		}
 	}
 }
 
@@ -253,7 +285,9 @@ func (b *versionedKVBackend) Salt(ctx context.Context, s logical.Storage) (*salt
 	if err != nil {
 		return nil, err
 	}
-	b.salt = salt
+	if !isDryRun(ctx) {
+		b.salt = salt
+	}
 	return salt, nil
 }
 
@@ -315,10 +349,30 @@ func (b *versionedKVBackend) getKeyEncryptor(ctx context.Context, s logical.Stor
 		return nil, err
 	}
 
-	// Cache the value
-	b.keyEncryptedWrapper = e
+	// Cache the value, unless the policy may only exist in the storage of a
+	// dry run
+	if !isDryRun(ctx) {
+		b.keyEncryptedWrapper = e
+	}
 
-	return b.keyEncryptedWrapper, nil
+	return e, nil
+}
+
+// dryRunKey is the context key marking the requests handled as dry runs.
+// The salt and key policy created by dry runs are not cached, as their
+// storage entries are discarded.
+type dryRunKey struct{}
+
+func dryRunContext(ctx context.Context, req *logical.Request) context.Context {
+	if !req.DryRun {
+		return ctx
+	}
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+func isDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
 }
 
 // config takes a storage object and returns a configuration object
@@ -376,6 +430,15 @@ func (b *versionedKVBackend) getVersionKey(ctx context.Context, key string, vers
 	return path.Join(b.storagePrefix, versionPrefix, salted[0:3], salted[3:]), nil
 }
 
+// checkDeletionProtection returns an error if the key is protected against
+// deletion and the deletion was not confirmed by the client.
+func checkDeletionProtection(req *logical.Request, meta *KeyMetadata) (*logical.Response, error) {
+	if !meta.Protected || req.DeleteConfirmed {
+		return nil, nil
+	}
+	return logical.ErrorResponse("secret %q is protected against deletion", meta.Key), logical.ErrDeleteConfirmationRequired
+}
+
 // getKeyMetadata returns the metadata object for the provided key, if no object
 // exits it will return nil.
 func (b *versionedKVBackend) getKeyMetadata(ctx context.Context, s logical.Storage, key string) (*KeyMetadata, error) {
@@ -0,0 +1,436 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+const (
+	// validationConfigPath is the location where the validation config is
+	// stored
+	validationConfigPath string = "config/validation"
+
+	// defaultValidationWebhookTimeout is the time allowed to the validation
+	// webhook to answer, unless set by the validation config.
+	defaultValidationWebhookTimeout = 5 * time.Second
+
+	// maxValidationWebhookResponseSize is the maximum size of the response
+	// read from the validation webhook.
+	maxValidationWebhookResponseSize = 1024 * 1024
+)
+
+// validationConfig holds the rules applied to the data of each write, which is
+// rejected if any rule reports a violation.
+type validationConfig struct {
+	// DenyPatterns are regular expressions, by name, which no string value of
+	// the data may match.
+	DenyPatterns map[string]string `json:"deny_patterns,omitempty"`
+
+	// JSONSchema is a JSON schema the data must conform to.
+	JSONSchema string `json:"json_schema,omitempty"`
+
+	// WebhookURL is the URL of an HTTP service called with each write, which
+	// returns the violations found.
+	WebhookURL     string        `json:"webhook_url,omitempty"`
+	WebhookTimeout time.Duration `json:"webhook_timeout,omitempty"`
+
+	denyPatterns map[string]*regexp.Regexp
+	schema       *gojsonschema.Schema
+}
+
+// validationViolation is a rule violated by the data of a write.
+type validationViolation struct {
+	Rule    string `json:"rule"`
+	Key     string `json:"key,omitempty"`
+	Message string `json:"message"`
+}
+
+// validationWebhookRequest is the body sent to the validation webhook.
+type validationWebhookRequest struct {
+	MountPoint string                 `json:"mount_point"`
+	Path       string                 `json:"path"`
+	Operation  logical.Operation      `json:"operation"`
+	EntityID   string                 `json:"entity_id,omitempty"`
+	Data       map[string]interface{} `json:"data"`
+}
+
+// validationWebhookResponse is the body expected from the validation
+// webhook. The write is rejected if it is not allowed.
+type validationWebhookResponse struct {
+	Allowed    bool                   `json:"allowed"`
+	Violations []*validationViolation `json:"violations"`
+}
+
+// pathConfigValidation returns the path configuration for CRUD operations on
+// the validation rules applied to writes.
+func pathConfigValidation(b *versionedKVBackend) *framework.Path {
+	return &framework.Path{
+		Pattern: "config/validation$",
+		Fields: map[string]*framework.FieldSchema{
+			"deny_patterns": {
+				Type:        framework.TypeKVPairs,
+				Description: "Regular expressions, by name, which no string value of the written data may match.",
+			},
+			"json_schema": {
+				Type:        framework.TypeString,
+				Description: "JSON schema the written data must conform to.",
+			},
+			"webhook_url": {
+				Type:        framework.TypeString,
+				Description: "URL of an HTTP service called with the data of each write, which may reject it.",
+			},
+			"webhook_timeout": {
+				Type:        framework.TypeDurationSecond,
+				Description: "Time allowed to the webhook to answer. Defaults to 5s",
+			},
+		},
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.UpdateOperation: &framework.PathOperation{
+				Callback: b.upgradeCheck(b.pathConfigValidationWrite()),
+				Summary:  "Configure the validation rules applied to every write in the key-value store.",
+			},
+			logical.CreateOperation: &framework.PathOperation{
+				Callback: b.upgradeCheck(b.pathConfigValidationWrite()),
+			},
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.upgradeCheck(b.pathConfigValidationRead()),
+				Summary:  "Read the validation rules.",
+			},
+			logical.DeleteOperation: &framework.PathOperation{
+				Callback: b.upgradeCheck(b.pathConfigValidationDelete()),
+				Summary:  "Remove the validation rules.",
+			},
+		},
+
+		HelpSynopsis:    confValidationHelpSyn,
+		HelpDescription: confValidationHelpDesc,
+	}
+}
+
+func (b *versionedKVBackend) pathConfigValidationRead() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		config, err := b.validationConfig(ctx, req.Storage)
+		if err != nil {
+			return nil, err
+		}
+		if config == nil {
+			return nil, nil
+		}
+
+		denyPatterns := config.DenyPatterns
+		if denyPatterns == nil {
+			denyPatterns = map[string]string{}
+		}
+		return &logical.Response{
+			Data: map[string]interface{}{
+				"deny_patterns":   denyPatterns,
+				"json_schema":     config.JSONSchema,
+				"webhook_url":     config.WebhookURL,
+				"webhook_timeout": int64(config.WebhookTimeout.Seconds()),
+			},
+		}, nil
+	}
+}
+
+func (b *versionedKVBackend) pathConfigValidationWrite() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		existing, err := b.validationConfig(ctx, req.Storage)
+		if err != nil {
+			return nil, err
+		}
+
+		config := &validationConfig{}
+		if existing != nil {
+			config.DenyPatterns = existing.DenyPatterns
+			config.JSONSchema = existing.JSONSchema
+			config.WebhookURL = existing.WebhookURL
+			config.WebhookTimeout = existing.WebhookTimeout
+		}
+
+		if denyPatternsRaw, ok := data.GetOk("deny_patterns"); ok {
+			config.DenyPatterns = denyPatternsRaw.(map[string]string)
+		}
+		if jsonSchemaRaw, ok := data.GetOk("json_schema"); ok {
+			config.JSONSchema = jsonSchemaRaw.(string)
+		}
+		if webhookURLRaw, ok := data.GetOk("webhook_url"); ok {
+			config.WebhookURL = webhookURLRaw.(string)
+		}
+		if webhookTimeoutRaw, ok := data.GetOk("webhook_timeout"); ok {
+			config.WebhookTimeout = time.Duration(webhookTimeoutRaw.(int)) * time.Second
+		}
+
+		if err := config.init(); err != nil {
+			return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+		}
+
+		entry, err := logical.StorageEntryJSON(path.Join(b.storagePrefix, validationConfigPath), config)
+		if err != nil {
+			return nil, err
+		}
+		if err := req.Storage.Put(ctx, entry); err != nil {
+			return nil, err
+		}
+
+		b.validationConfigLock.Lock()
+		defer b.validationConfigLock.Unlock()
+
+		b.validationConf = config
+
+		return nil, nil
+	}
+}
+
+func (b *versionedKVBackend) pathConfigValidationDelete() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		if err := req.Storage.Delete(ctx, path.Join(b.storagePrefix, validationConfigPath)); err != nil {
+			return nil, err
+		}
+
+		b.validationConfigLock.Lock()
+		defer b.validationConfigLock.Unlock()
+
+		b.validationConf = nil
+
+		return nil, nil
+	}
+}
+
+// init compiles the rules of the config.
+func (c *validationConfig) init() error {
+	c.denyPatterns = make(map[string]*regexp.Regexp, len(c.DenyPatterns))
+	for name, pattern := range c.DenyPatterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid deny pattern %q: %w", name, err)
+		}
+		c.denyPatterns[name] = re
+	}
+
+	c.schema = nil
+	if c.JSONSchema != "" {
+		schema, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(c.JSONSchema))
+		if err != nil {
+			return fmt.Errorf("invalid json_schema: %w", err)
+		}
+		c.schema = schema
+	}
+
+	if c.WebhookTimeout < 0 {
+		return fmt.Errorf("webhook_timeout must not be negative")
+	}
+	return nil
+}
+
+// validationConfig returns the validation config of the backend, or nil if
+// none is set.
+func (b *versionedKVBackend) validationConfig(ctx context.Context, s logical.Storage) (*validationConfig, error) {
+	b.validationConfigLock.RLock()
+	if b.validationConfLoaded {
+		defer b.validationConfigLock.RUnlock()
+		return b.validationConf, nil
+	}
+	b.validationConfigLock.RUnlock()
+
+	b.validationConfigLock.Lock()
+	defer b.validationConfigLock.Unlock()
+
+	// Verify this hasn't already changed
+	if b.validationConfLoaded {
+		return b.validationConf, nil
+	}
+
+	raw, err := s.Get(ctx, path.Join(b.storagePrefix, validationConfigPath))
+	if err != nil {
+		return nil, err
+	}
+
+	var config *validationConfig
+	if raw != nil {
+		config = &validationConfig{}
+		if err := raw.DecodeJSON(config); err != nil {
+			return nil, err
+		}
+		if err := config.init(); err != nil {
+			return nil, err
+		}
+	}
+
+	b.validationConf = config
+	b.validationConfLoaded = true
+
+	return config, nil
+}
+
+// validateData applies the validation rules to the data written at key. It
+// returns an error response listing the violations found, if any.
+func (b *versionedKVBackend) validateData(ctx context.Context, req *logical.Request, key string, data map[string]interface{}) (*logical.Response, error) {
+	config, err := b.validationConfig(ctx, req.Storage)
+	if err != nil || config == nil {
+		return nil, err
+	}
+
+	violations := config.checkDenyPatterns("", data)
+
+	if config.schema != nil {
+		result, err := config.schema.Validate(gojsonschema.NewGoLoader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to validate data against json_schema: %w", err)
+		}
+		for _, e := range result.Errors() {
+			violations = append(violations, &validationViolation{
+				Rule:    "json_schema",
+				Key:     e.Field(),
+				Message: e.Description(),
+			})
+		}
+	}
+
+	if config.WebhookURL != "" {
+		webhookViolations, err := b.callValidationWebhook(ctx, config, &validationWebhookRequest{
+			MountPoint: req.MountPoint,
+			Path:       key,
+			Operation:  req.Operation,
+			EntityID:   req.EntityID,
+			Data:       data,
+		})
+		if err != nil {
+			return nil, err
+		}
+		violations = append(violations, webhookViolations...)
+	}
+
+	if len(violations) == 0 {
+		return nil, nil
+	}
+
+	resp := logical.ErrorResponse("data rejected by %d validation violation(s)", len(violations))
+	resp.Data["data"] = map[string]interface{}{
+		"violations": violations,
+	}
+	return resp, nil
+}
+
+// checkDenyPatterns returns the violations of the deny patterns by the string
+// values found in data, at the given key prefix. The values themselves are not
+// reported.
+func (c *validationConfig) checkDenyPatterns(prefix string, data interface{}) []*validationViolation {
+	if len(c.denyPatterns) == 0 {
+		return nil
+	}
+
+	var violations []*validationViolation
+	switch v := data.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			violations = append(violations, c.checkDenyPatterns(path.Join(prefix, k), v[k])...)
+		}
+	case []interface{}:
+		for i, elem := range v {
+			violations = append(violations, c.checkDenyPatterns(path.Join(prefix, strconv.Itoa(i)), elem)...)
+		}
+	case string:
+		names := make([]string, 0, len(c.denyPatterns))
+		for name := range c.denyPatterns {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if c.denyPatterns[name].MatchString(v) {
+				violations = append(violations, &validationViolation{
+					Rule:    name,
+					Key:     prefix,
+					Message: fmt.Sprintf("value matches the denied pattern %q", name),
+				})
+			}
+		}
+	}
+	return violations
+}
+
+// callValidationWebhook sends the write to the validation webhook and returns
+// the violations it reports. Writes are rejected if the webhook cannot be
+// reached.
+func (b *versionedKVBackend) callValidationWebhook(ctx context.Context, config *validationConfig, webhookReq *validationWebhookRequest) ([]*validationViolation, error) {
+	timeout := config.WebhookTimeout
+	if timeout == 0 {
+		timeout = defaultValidationWebhookTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	body, err := json.Marshal(webhookReq)
+	if err != nil {
+		return nil, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, config.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	httpResp, err := http.DefaultClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call validation webhook: %w", err)
+	}
+	defer httpResp.Body.Close()
+
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("validation webhook returned status %d", httpResp.StatusCode)
+	}
+
+	var webhookResp validationWebhookResponse
+	if err := json.NewDecoder(io.LimitReader(httpResp.Body, maxValidationWebhookResponseSize)).Decode(&webhookResp); err != nil {
+		return nil, fmt.Errorf("failed to decode validation webhook response: %w", err)
+	}
+
+	if webhookResp.Allowed {
+		return nil, nil
+	}
+	if len(webhookResp.Violations) == 0 {
+		return []*validationViolation{{
+			Rule:    "webhook",
+			Message: "rejected by the validation webhook",
+		}}, nil
+	}
+	return webhookResp.Violations, nil
+}
+
+const (
+	confValidationHelpSyn  = `Configures the validation rules applied to writes`
+	confValidationHelpDesc = `
+This path configures rules applied to the data of every write and patch in the
+key-value store. A write is rejected with the list of violations found if any
+rule reports one. This parameter accepts:
+
+	* deny_patterns (map) - Regular expressions, by name, which no string value
+	  of the data may match, such as private keys or cloud access keys.
+
+	* json_schema (string) - JSON schema the data must conform to.
+
+	* webhook_url (string) - URL of an HTTP service receiving each write as a
+	  JSON POST request, which answers with whether the write is allowed and
+	  the violations found.
+
+	* webhook_timeout (duration) - Time allowed to the webhook to answer.
+	  Defaults to 5s.
+`
+)
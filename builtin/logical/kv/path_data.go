@@ -53,6 +53,7 @@ version matches the version specified in the cas parameter.`,
 			logical.DeleteOperation: b.upgradeCheck(b.pathDataDelete()),
 			logical.PatchOperation:  b.upgradeCheck(b.pathDataPatch()),
 		},
+		DryRun: true,
 
 		ExistenceCheck: b.dataExistenceCheck(),
 
@@ -65,7 +66,7 @@ func (b *versionedKVBackend) dataExistenceCheck() framework.ExistenceFunc {
 	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (bool, error) {
 		key := data.Get("path").(string)
 
-		meta, err := b.getKeyMetadata(ctx, req.Storage, key)
+		meta, err := b.getKeyMetadata(dryRunContext(ctx, req), req.Storage, key)
 		if err != nil {
 			// If we are returning a readonly error it means we are attempting
 			// to write the policy for the first time. This means no data exists
@@ -267,6 +268,14 @@ func (b *versionedKVBackend) pathDataWrite() framework.OperationFunc {
 			if err != nil {
 				return nil, err
 			}
+
+			validationResp, err := b.validateData(ctx, req, key, dataRaw.(map[string]interface{}))
+			if err != nil {
+				return nil, err
+			}
+			if validationResp != nil {
+				return validationResp, logical.ErrInvalidRequest
+			}
 		}
 
 		lock := locksutil.LockForKey(b.locks, key)
@@ -337,6 +346,10 @@ func (b *versionedKVBackend) pathDataWrite() framework.OperationFunc {
 			return nil, err
 		}
 
+		if err := b.queueSync(ctx, req.Storage, key); err != nil {
+			return nil, err
+		}
+
 		resp := &logical.Response{
 			Data: map[string]interface{}{
 				"version":         meta.CurrentVersion,
@@ -483,6 +496,18 @@ func (b *versionedKVBackend) pathDataPatch() framework.OperationFunc {
 			return nil, err
 		}
 
+		var patchedData map[string]interface{}
+		if err := json.Unmarshal(patchedBytes, &patchedData); err != nil {
+			return nil, err
+		}
+		validationResp, err := b.validateData(ctx, req, key, patchedData)
+		if err != nil {
+			return nil, err
+		}
+		if validationResp != nil {
+			return validationResp, logical.ErrInvalidRequest
+		}
+
 		newVersion := &Version{
 			Data:        patchedBytes,
 			CreatedTime: ptypes.TimestampNow(),
@@ -532,6 +557,10 @@ func (b *versionedKVBackend) pathDataPatch() framework.OperationFunc {
 			return nil, err
 		}
 
+		if err := b.queueSync(ctx, req.Storage, key); err != nil {
+			return nil, err
+		}
+
 		resp := &logical.Response{
 			Data: map[string]interface{}{
 				"version":         meta.CurrentVersion,
@@ -568,6 +597,9 @@ func (b *versionedKVBackend) pathDataDelete() framework.OperationFunc {
 		if meta == nil {
 			return nil, nil
 		}
+		if resp, err := checkDeletionProtection(req, meta); resp != nil || err != nil {
+			return resp, err
+		}
 
 		// If there is no latest version, or the latest version is already
 		// deleted or destroyed return
@@ -594,6 +626,10 @@ func (b *versionedKVBackend) pathDataDelete() framework.OperationFunc {
 			return nil, err
 		}
 
+		if err := b.queueSync(ctx, req.Storage, key); err != nil {
+			return nil, err
+		}
+
 		return nil, nil
 	}
 }
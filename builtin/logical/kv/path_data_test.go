@@ -1159,3 +1159,45 @@ func TestVersionedKV_Patch_CurrentVersionDestroyed(t *testing.T) {
 		t.Fatalf("Expected 404 status code for destroyed version: resp:%#v\n", resp)
 	}
 }
+
+func TestVersionedKV_Data_DryRun(t *testing.T) {
+	b, storage := getBackend(t)
+
+	// The storage of dry runs is discarded, so the salt and key policy
+	// created by the first write to the mount must not be cached
+	resp, err := b.HandleRequest(context.Background(), &logical.Request{
+		Operation: logical.CreateOperation,
+		Path:      "data/foo",
+		Storage:   &logical.InmemStorage{},
+		Data: map[string]interface{}{
+			"data": map[string]interface{}{"bar": "baz"},
+		},
+		DryRun: true,
+	})
+	if err != nil || resp.IsError() {
+		t.Fatalf("dry run failed, err: %s, resp %#v", err, resp)
+	}
+	if resp.Data["version"] != uint64(1) {
+		t.Fatalf("bad response: %#v", resp)
+	}
+
+	kv := b.(*versionedKVBackend)
+	kv.l.RLock()
+	cached := kv.salt != nil || kv.keyEncryptedWrapper != nil
+	kv.l.RUnlock()
+	if cached {
+		t.Fatal("dry run cached the salt or key policy")
+	}
+
+	resp, err = b.HandleRequest(context.Background(), &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "data/foo",
+		Storage:   storage,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp != nil {
+		t.Fatalf("bad response: %#v", resp)
+	}
+}
@@ -29,6 +29,7 @@ func pathsDelete(b *versionedKVBackend) []*framework.Path {
 				logical.UpdateOperation: b.upgradeCheck(b.pathDeleteWrite()),
 				logical.CreateOperation: b.upgradeCheck(b.pathDeleteWrite()),
 			},
+			DryRun: true,
 
 			HelpSynopsis:    deleteHelpSyn,
 			HelpDescription: deleteHelpDesc,
@@ -49,6 +50,7 @@ func pathsDelete(b *versionedKVBackend) []*framework.Path {
 				logical.UpdateOperation: b.upgradeCheck(b.pathUndeleteWrite()),
 				logical.CreateOperation: b.upgradeCheck(b.pathUndeleteWrite()),
 			},
+			DryRun: true,
 
 			HelpSynopsis:    undeleteHelpSyn,
 			HelpDescription: undeleteHelpDesc,
@@ -106,6 +108,10 @@ func (b *versionedKVBackend) pathUndeleteWrite() framework.OperationFunc {
 			return nil, err
 		}
 
+		if err := b.queueSync(ctx, req.Storage, key); err != nil {
+			return nil, err
+		}
+
 		return nil, nil
 	}
 }
@@ -131,6 +137,9 @@ func (b *versionedKVBackend) pathDeleteWrite() framework.OperationFunc {
 		if meta == nil {
 			return nil, nil
 		}
+		if resp, err := checkDeletionProtection(req, meta); resp != nil || err != nil {
+			return resp, err
+		}
 
 		for _, verNum := range versions {
 			// If there is no latest version, or the latest version is already
@@ -159,6 +168,10 @@ func (b *versionedKVBackend) pathDeleteWrite() framework.OperationFunc {
 			return nil, err
 		}
 
+		if err := b.queueSync(ctx, req.Storage, key); err != nil {
+			return nil, err
+		}
+
 		return nil, nil
 	}
 }
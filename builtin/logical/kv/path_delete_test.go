@@ -206,3 +206,62 @@ func TestVersionedKV_Undelete_Put(t *testing.T) {
 		t.Fatalf("Bad response: %#v", resp)
 	}
 }
+
+func TestVersionedKV_Delete_Protected(t *testing.T) {
+	b, storage := getBackend(t)
+
+	request := func(op logical.Operation, path string, data map[string]interface{}, confirmed bool) (*logical.Response, error) {
+		t.Helper()
+		return b.HandleRequest(context.Background(), &logical.Request{
+			Operation:       op,
+			Path:            path,
+			Storage:         storage,
+			Data:            data,
+			DeleteConfirmed: confirmed,
+		})
+	}
+
+	resp, err := request(logical.CreateOperation, "data/foo", map[string]interface{}{
+		"data": map[string]interface{}{"bar": "baz"},
+	}, false)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%s resp:%#v\n", err, resp)
+	}
+	resp, err = request(logical.UpdateOperation, "metadata/foo", map[string]interface{}{"protected": true}, false)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%s resp:%#v\n", err, resp)
+	}
+
+	resp, err = request(logical.ReadOperation, "metadata/foo", nil, false)
+	if err != nil || resp == nil || resp.IsError() {
+		t.Fatalf("err:%s resp:%#v\n", err, resp)
+	}
+	if resp.Data["protected"] != true {
+		t.Fatalf("Bad response: %#v", resp)
+	}
+
+	for _, tc := range []struct {
+		op   logical.Operation
+		path string
+		data map[string]interface{}
+	}{
+		{logical.DeleteOperation, "data/foo", nil},
+		{logical.UpdateOperation, "delete/foo", map[string]interface{}{"versions": "1"}},
+		{logical.UpdateOperation, "destroy/foo", map[string]interface{}{"versions": "1"}},
+		{logical.DeleteOperation, "metadata/foo", nil},
+	} {
+		resp, err = request(tc.op, tc.path, tc.data, false)
+		if err != logical.ErrDeleteConfirmationRequired || !resp.IsError() {
+			t.Fatalf("expected %s to require confirmation, err:%v resp:%#v", tc.path, err, resp)
+		}
+	}
+
+	resp, err = request(logical.DeleteOperation, "metadata/foo", nil, true)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%s resp:%#v\n", err, resp)
+	}
+	resp, err = request(logical.ReadOperation, "metadata/foo", nil, false)
+	if err != nil || resp != nil {
+		t.Fatalf("err:%s resp:%#v\n", err, resp)
+	}
+}
@@ -26,6 +26,7 @@ func pathDestroy(b *versionedKVBackend) *framework.Path {
 			logical.UpdateOperation: b.upgradeCheck(b.pathDestroyWrite()),
 			logical.CreateOperation: b.upgradeCheck(b.pathDestroyWrite()),
 		},
+		DryRun: true,
 
 		HelpSynopsis:    destroyHelpSyn,
 		HelpDescription: destroyHelpDesc,
@@ -52,6 +53,9 @@ func (b *versionedKVBackend) pathDestroyWrite() framework.OperationFunc {
 		if meta == nil {
 			return nil, nil
 		}
+		if resp, err := checkDeletionProtection(req, meta); resp != nil || err != nil {
+			return resp, err
+		}
 
 		for _, verNum := range versions {
 			// If there is no version, or the version is already destroyed,
@@ -83,6 +87,10 @@ func (b *versionedKVBackend) pathDestroyWrite() framework.OperationFunc {
 			}
 		}
 
+		if err := b.queueSync(ctx, req.Storage, key); err != nil {
+			return nil, err
+		}
+
 		return nil, nil
 	}
 }
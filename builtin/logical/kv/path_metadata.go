@@ -55,6 +55,12 @@ A negative duration will cause an error.
 User-provided key-value pairs that are used to describe arbitrary and
 version-agnostic information about a secret.
 `,
+			},
+			"protected": {
+				Type: framework.TypeBool,
+				Description: `
+If true, deleting or destroying versions of the secret, or deleting its
+metadata, requires a confirmation.`,
 			},
 			"after": {
 				Type:        framework.TypeString,
@@ -73,6 +79,7 @@ version-agnostic information about a secret.
 			logical.ListOperation:   b.upgradeCheck(b.pathMetadataList()),
 			logical.PatchOperation:  b.upgradeCheck(b.pathMetadataPatch()),
 		},
+		DryRun: true,
 
 		ExistenceCheck: b.metadataExistenceCheck(),
 
@@ -85,7 +92,7 @@ func (b *versionedKVBackend) metadataExistenceCheck() framework.ExistenceFunc {
 	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (bool, error) {
 		key := data.Get("path").(string)
 
-		meta, err := b.getKeyMetadata(ctx, req.Storage, key)
+		meta, err := b.getKeyMetadata(dryRunContext(ctx, req), req.Storage, key)
 		if err != nil {
 			// If we are returning a readonly error it means we are attempting
 			// to write the policy for the first time. This means no data exists
@@ -165,6 +172,7 @@ func (b *versionedKVBackend) pathMetadataRead() framework.OperationFunc {
 				"cas_required":         meta.CasRequired,
 				"delete_version_after": deleteVersionAfter.String(),
 				"custom_metadata":      meta.CustomMetadata,
+				"protected":            meta.Protected,
 			},
 		}, nil
 	}
@@ -270,9 +278,10 @@ func (b *versionedKVBackend) pathMetadataWrite() framework.OperationFunc {
 		casRaw, cOk := data.GetOk("cas_required")
 		deleteVersionAfterRaw, dvaOk := data.GetOk("delete_version_after")
 		customMetadataRaw, cmOk := data.GetOk("custom_metadata")
+		protectedRaw, pOk := data.GetOk("protected")
 
 		// Fast path validation
-		if !mOk && !cOk && !dvaOk && !cmOk {
+		if !mOk && !cOk && !dvaOk && !cmOk && !pOk {
 			return nil, nil
 		}
 
@@ -332,6 +341,9 @@ func (b *versionedKVBackend) pathMetadataWrite() framework.OperationFunc {
 		if cmOk {
 			meta.CustomMetadata = customMetadataMap
 		}
+		if pOk {
+			meta.Protected = protectedRaw.(bool)
+		}
 
 		err = b.writeKeyMetadata(ctx, req.Storage, meta)
 		return resp, err
@@ -344,7 +356,7 @@ func (b *versionedKVBackend) pathMetadataWrite() framework.OperationFunc {
 // and ensuring appropriate handling of data types not supported directly by FieldType.
 func metadataPatchPreprocessor() framework.PatchPreprocessorFunc {
 	return func(input map[string]interface{}) (map[string]interface{}, error) {
-		patchableKeys := []string{"max_versions", "cas_required", "delete_version_after", "custom_metadata"}
+		patchableKeys := []string{"max_versions", "cas_required", "delete_version_after", "custom_metadata", "protected"}
 		patchData := map[string]interface{}{}
 
 		for _, k := range patchableKeys {
@@ -455,6 +467,9 @@ func (b *versionedKVBackend) pathMetadataDelete() framework.OperationFunc {
 		if meta == nil {
 			return nil, nil
 		}
+		if resp, err := checkDeletionProtection(req, meta); resp != nil || err != nil {
+			return resp, err
+		}
 
 		// Delete each version.
 		for id := range meta.Versions {
@@ -478,8 +493,15 @@ func (b *versionedKVBackend) pathMetadataDelete() framework.OperationFunc {
 		es := wrapper.Wrap(req.Storage)
 
 		// Use encrypted key storage to delete the key
-		err = es.Delete(ctx, key)
-		return nil, err
+		if err := es.Delete(ctx, key); err != nil {
+			return nil, err
+		}
+
+		if err := b.queueSync(ctx, req.Storage, key); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
 	}
 }
 
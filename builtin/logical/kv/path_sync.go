@@ -0,0 +1,895 @@
+package kv
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/openbao/openbao/api/v2"
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/locksutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const (
+	// syncDestinationsPath is the location where the sync destinations are
+	// stored.
+	syncDestinationsPath string = "sync/destinations/"
+
+	// syncPendingPath is the location of the keys waiting to be pushed to
+	// each sync destination.
+	syncPendingPath string = "sync/pending/"
+
+	// syncStatePath is the location of the sync state of each key pushed to
+	// each sync destination.
+	syncStatePath string = "sync/state/"
+
+	// syncStatusPath is the location of the status of each sync destination.
+	syncStatusPath string = "sync/status/"
+
+	syncTypeOpenBao = "openbao"
+
+	syncConflictOverwrite = "overwrite"
+	syncConflictFail      = "fail"
+)
+
+// errSyncConflict is returned by a sync target when the destination key was
+// changed since it was last pushed.
+var errSyncConflict = errors.New("destination key was changed since it was last synced")
+
+// syncDestination is a remote store the keys under a prefix of the mount are
+// pushed to whenever they change.
+type syncDestination struct {
+	Type string `json:"type"`
+
+	// Prefix restricts the synced keys to those under it.
+	Prefix string `json:"prefix"`
+
+	// ConflictPolicy is either overwrite, replacing the destination keys, or
+	// fail, refusing to replace destination keys changed since they were last
+	// pushed.
+	ConflictPolicy string `json:"conflict_policy"`
+
+	// Address, Token, Namespace, Mount and CACert locate the K/V v2 mount of
+	// the remote OpenBao cluster.
+	Address   string `json:"address"`
+	Token     string `json:"token"`
+	Namespace string `json:"namespace,omitempty"`
+	Mount     string `json:"mount"`
+	CACert    string `json:"ca_cert,omitempty"`
+}
+
+// syncKeyState is the sync state of a key at a destination.
+type syncKeyState struct {
+	// RemoteVersion is the version of the destination key written by the
+	// last push, used as the check-and-set value of the next one.
+	RemoteVersion uint64    `json:"remote_version"`
+	LastSynced    time.Time `json:"last_synced,omitempty"`
+	Error         string    `json:"error,omitempty"`
+}
+
+// syncStatus is the status of the last pushes to a destination.
+type syncStatus struct {
+	LastRun     time.Time `json:"last_run,omitempty"`
+	LastSuccess time.Time `json:"last_success,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+	Synced      uint64    `json:"synced"`
+	Conflicts   uint64    `json:"conflicts"`
+}
+
+// syncTarget writes keys to a sync destination.
+type syncTarget interface {
+	// Write writes data at key and returns the new version of the key. If
+	// cas is set, the write fails with errSyncConflict unless the key is at
+	// that version.
+	Write(ctx context.Context, key string, data map[string]interface{}, cas *uint64) (uint64, error)
+
+	// Delete deletes the latest version of key, or removes the key and all
+	// its versions if destroy is set.
+	Delete(ctx context.Context, key string, destroy bool) error
+}
+
+func pathSyncDestinations(b *versionedKVBackend) []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "sync/destinations/?$",
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback: b.upgradeCheck(b.pathSyncDestinationsList()),
+					Summary:  "List the sync destinations.",
+				},
+			},
+
+			HelpSynopsis:    syncHelpSyn,
+			HelpDescription: syncHelpDesc,
+		},
+		{
+			Pattern: "sync/destinations/" + framework.GenericNameRegex("name") + "$",
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the sync destination.",
+				},
+				"type": {
+					Type:        framework.TypeString,
+					Description: `Type of the sync destination. Only "openbao", a K/V v2 mount of another cluster, is supported.`,
+					Default:     syncTypeOpenBao,
+				},
+				"prefix": {
+					Type:        framework.TypeString,
+					Description: "Only the keys under this prefix are synced. Defaults to all the keys of the mount.",
+				},
+				"conflict_policy": {
+					Type:        framework.TypeString,
+					Description: `Either "overwrite", replacing the destination keys, or "fail", refusing to replace destination keys changed since they were last synced. Defaults to "overwrite".`,
+				},
+				"address": {
+					Type:        framework.TypeString,
+					Description: "Address of the destination cluster.",
+				},
+				"token": {
+					Type:        framework.TypeString,
+					Description: "Token used to write to the destination cluster.",
+				},
+				"namespace": {
+					Type:        framework.TypeString,
+					Description: "Namespace of the destination mount.",
+				},
+				"mount": {
+					Type:        framework.TypeString,
+					Description: `Path of the K/V v2 mount of the destination cluster. Defaults to "secret".`,
+				},
+				"ca_cert": {
+					Type:        framework.TypeString,
+					Description: "PEM-encoded CA certificate used to verify the destination cluster.",
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.upgradeCheck(b.pathSyncDestinationWrite()),
+					Summary:  "Configure a destination the keys of the mount are pushed to.",
+				},
+				logical.CreateOperation: &framework.PathOperation{
+					Callback: b.upgradeCheck(b.pathSyncDestinationWrite()),
+				},
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.upgradeCheck(b.pathSyncDestinationRead()),
+					Summary:  "Read a sync destination.",
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.upgradeCheck(b.pathSyncDestinationDelete()),
+					Summary:  "Remove a sync destination.",
+				},
+			},
+
+			HelpSynopsis:    syncHelpSyn,
+			HelpDescription: syncHelpDesc,
+		},
+		{
+			Pattern: "sync/destinations/" + framework.GenericNameRegex("name") + "/sync$",
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the sync destination.",
+				},
+				"reconcile": {
+					Type:        framework.TypeBool,
+					Description: "Push all the keys under the prefix of the destination, rather than only the changed ones.",
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.upgradeCheck(b.pathSyncDestinationSync()),
+					Summary:  "Push the pending changes to a sync destination now.",
+				},
+			},
+
+			HelpSynopsis:    syncHelpSyn,
+			HelpDescription: syncHelpDesc,
+		},
+		{
+			Pattern: "sync/destinations/" + framework.GenericNameRegex("name") + "/status$",
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the sync destination.",
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.upgradeCheck(b.pathSyncDestinationStatus()),
+					Summary:  "Read the sync status of a destination.",
+				},
+			},
+
+			HelpSynopsis:    syncHelpSyn,
+			HelpDescription: syncHelpDesc,
+		},
+	}
+}
+
+func (b *versionedKVBackend) pathSyncDestinationsList() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		names, err := req.Storage.List(ctx, path.Join(b.storagePrefix, syncDestinationsPath)+"/")
+		if err != nil {
+			return nil, err
+		}
+
+		return logical.ListResponse(names), nil
+	}
+}
+
+func (b *versionedKVBackend) pathSyncDestinationRead() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		dest, err := b.syncDestination(ctx, req.Storage, data.Get("name").(string))
+		if err != nil {
+			return nil, err
+		}
+		if dest == nil {
+			return nil, nil
+		}
+
+		// The token is never returned.
+		return &logical.Response{
+			Data: map[string]interface{}{
+				"type":            dest.Type,
+				"prefix":          dest.Prefix,
+				"conflict_policy": dest.ConflictPolicy,
+				"address":         dest.Address,
+				"namespace":       dest.Namespace,
+				"mount":           dest.Mount,
+				"ca_cert":         dest.CACert,
+			},
+		}, nil
+	}
+}
+
+func (b *versionedKVBackend) pathSyncDestinationWrite() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		name := data.Get("name").(string)
+
+		dest, err := b.syncDestination(ctx, req.Storage, name)
+		if err != nil {
+			return nil, err
+		}
+		if dest == nil {
+			dest = &syncDestination{
+				Type:           syncTypeOpenBao,
+				ConflictPolicy: syncConflictOverwrite,
+				Mount:          "secret",
+			}
+		}
+
+		if typeRaw, ok := data.GetOk("type"); ok {
+			dest.Type = typeRaw.(string)
+		}
+		if prefixRaw, ok := data.GetOk("prefix"); ok {
+			dest.Prefix = prefixRaw.(string)
+		}
+		if conflictPolicyRaw, ok := data.GetOk("conflict_policy"); ok {
+			dest.ConflictPolicy = conflictPolicyRaw.(string)
+		}
+		if addressRaw, ok := data.GetOk("address"); ok {
+			dest.Address = addressRaw.(string)
+		}
+		if tokenRaw, ok := data.GetOk("token"); ok {
+			dest.Token = tokenRaw.(string)
+		}
+		if namespaceRaw, ok := data.GetOk("namespace"); ok {
+			dest.Namespace = namespaceRaw.(string)
+		}
+		if mountRaw, ok := data.GetOk("mount"); ok {
+			dest.Mount = strings.Trim(mountRaw.(string), "/")
+		}
+		if caCertRaw, ok := data.GetOk("ca_cert"); ok {
+			dest.CACert = caCertRaw.(string)
+		}
+
+		switch {
+		case dest.Type != syncTypeOpenBao:
+			return logical.ErrorResponse("unsupported sync destination type %q", dest.Type), logical.ErrInvalidRequest
+		case dest.ConflictPolicy != syncConflictOverwrite && dest.ConflictPolicy != syncConflictFail:
+			return logical.ErrorResponse("conflict_policy must be %q or %q", syncConflictOverwrite, syncConflictFail), logical.ErrInvalidRequest
+		case dest.Address == "":
+			return logical.ErrorResponse("missing address"), logical.ErrInvalidRequest
+		case dest.Token == "":
+			return logical.ErrorResponse("missing token"), logical.ErrInvalidRequest
+		case dest.Mount == "":
+			return logical.ErrorResponse("missing mount"), logical.ErrInvalidRequest
+		}
+		if _, err := dest.target(); err != nil {
+			return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+		}
+
+		entry, err := logical.StorageEntryJSON(path.Join(b.storagePrefix, syncDestinationsPath, name), dest)
+		if err != nil {
+			return nil, err
+		}
+		if err := req.Storage.Put(ctx, entry); err != nil {
+			return nil, err
+		}
+
+		b.syncDestinationsLock.Lock()
+		defer b.syncDestinationsLock.Unlock()
+
+		b.syncDests = nil
+
+		return nil, nil
+	}
+}
+
+func (b *versionedKVBackend) pathSyncDestinationDelete() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		name := data.Get("name").(string)
+
+		lock := b.syncLock(name)
+		lock.Lock()
+		defer lock.Unlock()
+
+		if err := req.Storage.Delete(ctx, path.Join(b.storagePrefix, syncDestinationsPath, name)); err != nil {
+			return nil, err
+		}
+
+		b.syncDestinationsLock.Lock()
+		b.syncDests = nil
+		b.syncDestinationsLock.Unlock()
+
+		for _, prefix := range []string{syncPendingPath, syncStatePath} {
+			if err := logical.ClearView(ctx, logical.NewStorageView(req.Storage, path.Join(b.storagePrefix, prefix, name)+"/")); err != nil {
+				return nil, err
+			}
+		}
+		if err := req.Storage.Delete(ctx, path.Join(b.storagePrefix, syncStatusPath, name)); err != nil {
+			return nil, err
+		}
+
+		return nil, nil
+	}
+}
+
+func (b *versionedKVBackend) pathSyncDestinationSync() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		name := data.Get("name").(string)
+
+		dest, err := b.syncDestination(ctx, req.Storage, name)
+		if err != nil {
+			return nil, err
+		}
+		if dest == nil {
+			return logical.ErrorResponse("no sync destination named %s", name), logical.ErrInvalidRequest
+		}
+
+		if data.Get("reconcile").(bool) {
+			if err := b.queueSyncPrefix(ctx, req.Storage, name, dest.Prefix); err != nil {
+				return nil, err
+			}
+		}
+
+		status, err := b.runSync(ctx, req.Storage, name, dest)
+		if err != nil {
+			return nil, err
+		}
+
+		return b.syncStatusResponse(ctx, req.Storage, name, status)
+	}
+}
+
+func (b *versionedKVBackend) pathSyncDestinationStatus() framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		name := data.Get("name").(string)
+
+		dest, err := b.syncDestination(ctx, req.Storage, name)
+		if err != nil {
+			return nil, err
+		}
+		if dest == nil {
+			return nil, nil
+		}
+
+		status, err := b.syncStatus(ctx, req.Storage, name)
+		if err != nil {
+			return nil, err
+		}
+
+		return b.syncStatusResponse(ctx, req.Storage, name, status)
+	}
+}
+
+func (b *versionedKVBackend) syncStatusResponse(ctx context.Context, s logical.Storage, name string, status *syncStatus) (*logical.Response, error) {
+	pending, err := s.List(ctx, path.Join(b.storagePrefix, syncPendingPath, name)+"/")
+	if err != nil {
+		return nil, err
+	}
+
+	states, err := s.List(ctx, path.Join(b.storagePrefix, syncStatePath, name)+"/")
+	if err != nil {
+		return nil, err
+	}
+	failedKeys := map[string]string{}
+	for _, encoded := range states {
+		state, err := b.syncKeyState(ctx, s, name, encoded)
+		if err != nil {
+			return nil, err
+		}
+		if state != nil && state.Error != "" {
+			key, err := decodeSyncKey(encoded)
+			if err != nil {
+				return nil, err
+			}
+			failedKeys[key] = state.Error
+		}
+	}
+
+	resp := &logical.Response{
+		Data: map[string]interface{}{
+			"pending":     len(pending),
+			"failed_keys": failedKeys,
+			"synced":      status.Synced,
+			"conflicts":   status.Conflicts,
+			"last_error":  status.LastError,
+		},
+	}
+	if !status.LastRun.IsZero() {
+		resp.Data["last_run"] = status.LastRun.Format(time.RFC3339Nano)
+	}
+	if !status.LastSuccess.IsZero() {
+		resp.Data["last_success"] = status.LastSuccess.Format(time.RFC3339Nano)
+	}
+
+	return resp, nil
+}
+
+// syncDestination returns the named sync destination, or nil if it does not
+// exist.
+func (b *versionedKVBackend) syncDestination(ctx context.Context, s logical.Storage, name string) (*syncDestination, error) {
+	raw, err := s.Get(ctx, path.Join(b.storagePrefix, syncDestinationsPath, name))
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, nil
+	}
+
+	dest := &syncDestination{}
+	if err := raw.DecodeJSON(dest); err != nil {
+		return nil, err
+	}
+
+	return dest, nil
+}
+
+// syncDestinations returns the sync destinations of the mount by name.
+func (b *versionedKVBackend) syncDestinations(ctx context.Context, s logical.Storage) (map[string]*syncDestination, error) {
+	b.syncDestinationsLock.RLock()
+	if b.syncDests != nil {
+		defer b.syncDestinationsLock.RUnlock()
+		return b.syncDests, nil
+	}
+	b.syncDestinationsLock.RUnlock()
+
+	b.syncDestinationsLock.Lock()
+	defer b.syncDestinationsLock.Unlock()
+
+	// Verify this hasn't already changed
+	if b.syncDests != nil {
+		return b.syncDests, nil
+	}
+
+	names, err := s.List(ctx, path.Join(b.storagePrefix, syncDestinationsPath)+"/")
+	if err != nil {
+		return nil, err
+	}
+
+	dests := make(map[string]*syncDestination, len(names))
+	for _, name := range names {
+		dest, err := b.syncDestination(ctx, s, name)
+		if err != nil {
+			return nil, err
+		}
+		if dest != nil {
+			dests[name] = dest
+		}
+	}
+
+	b.syncDests = dests
+
+	return dests, nil
+}
+
+// queueSync queues key to be pushed to the sync destinations whose prefix
+// it is under. It is called with the lock of the key held after each change.
+func (b *versionedKVBackend) queueSync(ctx context.Context, s logical.Storage, key string) error {
+	dests, err := b.syncDestinations(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	for name, dest := range dests {
+		if !strings.HasPrefix(key, dest.Prefix) {
+			continue
+		}
+		if err := s.Put(ctx, &logical.StorageEntry{
+			Key:   path.Join(b.storagePrefix, syncPendingPath, name, encodeSyncKey(key)),
+			Value: []byte(key),
+		}); err != nil {
+			return fmt.Errorf("failed to queue sync to %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// queueSyncPrefix queues all the keys under prefix to be pushed to the named
+// destination.
+func (b *versionedKVBackend) queueSyncPrefix(ctx context.Context, s logical.Storage, name, prefix string) error {
+	wrapper, err := b.getKeyEncryptor(ctx, s)
+	if err != nil {
+		return err
+	}
+	es := wrapper.Wrap(s)
+
+	// Keys are listed by folder, so walk up to the last folder of the prefix.
+	folder := prefix[:strings.LastIndex(prefix, "/")+1]
+
+	var putErr error
+	err = logical.ScanView(ctx, logical.NewStorageView(es, folder), func(key string) {
+		key = folder + key
+		if putErr != nil || !strings.HasPrefix(key, prefix) {
+			return
+		}
+		putErr = s.Put(ctx, &logical.StorageEntry{
+			Key:   path.Join(b.storagePrefix, syncPendingPath, name, encodeSyncKey(key)),
+			Value: []byte(key),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	return putErr
+}
+
+// syncPeriodic pushes the pending changes to every sync destination.
+func (b *versionedKVBackend) syncPeriodic(ctx context.Context, req *logical.Request) error {
+	if b.perfSecondaryCheck() {
+		return nil
+	}
+
+	dests, err := b.syncDestinations(ctx, req.Storage)
+	if err != nil {
+		return err
+	}
+
+	for name, dest := range dests {
+		if _, err := b.runSync(ctx, req.Storage, name, dest); err != nil {
+			b.Logger().Error("failed to run sync", "destination", name, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// runSync pushes the pending keys to the named destination and returns the
+// updated status of the destination. Failures to push keys are recorded in
+// the status rather than returned.
+func (b *versionedKVBackend) runSync(ctx context.Context, s logical.Storage, name string, dest *syncDestination) (*syncStatus, error) {
+	lock := b.syncLock(name)
+	lock.Lock()
+	defer lock.Unlock()
+
+	status, err := b.syncStatus(ctx, s, name)
+	if err != nil {
+		return nil, err
+	}
+
+	pendingPrefix := path.Join(b.storagePrefix, syncPendingPath, name) + "/"
+	pending, err := s.List(ctx, pendingPrefix)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(pending)
+
+	target, err := dest.target()
+	if err != nil {
+		return nil, err
+	}
+
+	status.LastRun = time.Now()
+	var lastErr error
+	for _, encoded := range pending {
+		key, err := decodeSyncKey(encoded)
+		if err != nil {
+			return nil, err
+		}
+
+		state, err := b.syncKeyState(ctx, s, name, encoded)
+		if err != nil {
+			return nil, err
+		}
+		if state == nil {
+			state = &syncKeyState{}
+		}
+
+		pushErr := b.pushKey(ctx, s, key, dest, target, state)
+		switch {
+		case pushErr == nil:
+			state.Error = ""
+			state.LastSynced = time.Now()
+			status.Synced++
+		case errors.Is(pushErr, errSyncConflict):
+			// Conflicts are not retried until the key changes again or the
+			// destination is reconciled.
+			state.Error = pushErr.Error()
+			status.Conflicts++
+			lastErr = fmt.Errorf("%s: %w", key, pushErr)
+		default:
+			state.Error = pushErr.Error()
+			lastErr = fmt.Errorf("%s: %w", key, pushErr)
+		}
+
+		entry, err := logical.StorageEntryJSON(path.Join(b.storagePrefix, syncStatePath, name, encoded), state)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.Put(ctx, entry); err != nil {
+			return nil, err
+		}
+
+		if pushErr == nil || errors.Is(pushErr, errSyncConflict) {
+			if err := s.Delete(ctx, pendingPrefix+encoded); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if lastErr != nil {
+		status.LastError = lastErr.Error()
+	} else {
+		status.LastError = ""
+		status.LastSuccess = status.LastRun
+	}
+
+	entry, err := logical.StorageEntryJSON(path.Join(b.storagePrefix, syncStatusPath, name), status)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.Put(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return status, nil
+}
+
+// pushKey makes the destination key match the latest version of key:
+// the data is written if it is readable, the latest destination version is
+// deleted if the latest version was deleted or destroyed, and the destination
+// key is removed if the key no longer exists.
+func (b *versionedKVBackend) pushKey(ctx context.Context, s logical.Storage, key string, dest *syncDestination, target syncTarget, state *syncKeyState) error {
+	data, exists, err := b.latestData(ctx, s, key)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case !exists:
+		state.RemoteVersion = 0
+		return target.Delete(ctx, key, true)
+	case data == nil:
+		return target.Delete(ctx, key, false)
+	}
+
+	var cas *uint64
+	if dest.ConflictPolicy == syncConflictFail {
+		cas = &state.RemoteVersion
+	}
+	version, err := target.Write(ctx, key, data, cas)
+	if err != nil {
+		return err
+	}
+	state.RemoteVersion = version
+
+	return nil
+}
+
+// latestData returns the data of the latest version of key, or nil if it was
+// deleted or destroyed. exists is false if the key does not exist.
+func (b *versionedKVBackend) latestData(ctx context.Context, s logical.Storage, key string) (map[string]interface{}, bool, error) {
+	lock := locksutil.LockForKey(b.locks, key)
+	lock.RLock()
+	defer lock.RUnlock()
+
+	meta, err := b.getKeyMetadata(ctx, s, key)
+	if err != nil {
+		return nil, false, err
+	}
+	if meta == nil {
+		return nil, false, nil
+	}
+
+	vm := meta.Versions[meta.CurrentVersion]
+	if vm == nil || vm.Destroyed {
+		return nil, true, nil
+	}
+	if vm.DeletionTime != nil {
+		deletionTime, err := ptypes.Timestamp(vm.DeletionTime)
+		if err != nil {
+			return nil, false, err
+		}
+		if deletionTime.Before(time.Now()) {
+			return nil, true, nil
+		}
+	}
+
+	versionKey, err := b.getVersionKey(ctx, key, meta.CurrentVersion, s)
+	if err != nil {
+		return nil, false, err
+	}
+	raw, err := s.Get(ctx, versionKey)
+	if err != nil {
+		return nil, false, err
+	}
+	if raw == nil {
+		return nil, false, errors.New("could not find version data")
+	}
+
+	version := &Version{}
+	if err := proto.Unmarshal(raw.Value, version); err != nil {
+		return nil, false, err
+	}
+
+	data := map[string]interface{}{}
+	if err := json.Unmarshal(version.Data, &data); err != nil {
+		return nil, false, err
+	}
+
+	return data, true, nil
+}
+
+func (b *versionedKVBackend) syncKeyState(ctx context.Context, s logical.Storage, name, encoded string) (*syncKeyState, error) {
+	raw, err := s.Get(ctx, path.Join(b.storagePrefix, syncStatePath, name, encoded))
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, nil
+	}
+
+	state := &syncKeyState{}
+	if err := raw.DecodeJSON(state); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+func (b *versionedKVBackend) syncStatus(ctx context.Context, s logical.Storage, name string) (*syncStatus, error) {
+	status := &syncStatus{}
+
+	raw, err := s.Get(ctx, path.Join(b.storagePrefix, syncStatusPath, name))
+	if err != nil {
+		return nil, err
+	}
+	if raw != nil {
+		if err := raw.DecodeJSON(status); err != nil {
+			return nil, err
+		}
+	}
+
+	return status, nil
+}
+
+// syncLock returns the lock serializing the pushes to the named destination.
+func (b *versionedKVBackend) syncLock(name string) *sync.Mutex {
+	lock, _ := b.syncLocks.LoadOrStore(name, &sync.Mutex{})
+	return lock.(*sync.Mutex)
+}
+
+// Keys are encoded in the storage paths of the sync queue so that they are
+// listed at a single level.
+func encodeSyncKey(key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key))
+}
+
+func decodeSyncKey(encoded string) (string, error) {
+	key, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", fmt.Errorf("invalid sync queue entry %q: %w", encoded, err)
+	}
+	return string(key), nil
+}
+
+// target returns the sync target writing to the destination.
+func (d *syncDestination) target() (syncTarget, error) {
+	config := api.DefaultConfig()
+	if config.Error != nil {
+		return nil, config.Error
+	}
+	config.Address = d.Address
+	if d.CACert != "" {
+		if err := config.ConfigureTLS(&api.TLSConfig{CACertBytes: []byte(d.CACert)}); err != nil {
+			return nil, fmt.Errorf("invalid ca_cert: %w", err)
+		}
+	}
+
+	client, err := api.NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+	client.SetToken(d.Token)
+	if d.Namespace != "" {
+		client.SetNamespace(d.Namespace)
+	} else {
+		client.ClearNamespace()
+	}
+
+	return &openbaoSyncTarget{client: client, mount: d.Mount}, nil
+}
+
+// openbaoSyncTarget writes keys to a K/V v2 mount of another cluster.
+type openbaoSyncTarget struct {
+	client *api.Client
+	mount  string
+}
+
+func (t *openbaoSyncTarget) Write(ctx context.Context, key string, data map[string]interface{}, cas *uint64) (uint64, error) {
+	body := map[string]interface{}{
+		"data": data,
+	}
+	if cas != nil {
+		body["options"] = map[string]interface{}{
+			"cas": *cas,
+		}
+	}
+
+	secret, err := t.client.Logical().WriteWithContext(ctx, path.Join(t.mount, "data", key), body)
+	if err != nil {
+		var respErr *api.ResponseError
+		if cas != nil && errors.As(err, &respErr) && respErr.StatusCode == http.StatusBadRequest && strings.Contains(strings.Join(respErr.Errors, " "), "check-and-set") {
+			return 0, errSyncConflict
+		}
+		return 0, err
+	}
+	if secret == nil || secret.Data["version"] == nil {
+		return 0, errors.New("no version returned by the destination")
+	}
+
+	version, err := strconv.ParseUint(fmt.Sprint(secret.Data["version"]), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid version returned by the destination: %w", err)
+	}
+
+	return version, nil
+}
+
+func (t *openbaoSyncTarget) Delete(ctx context.Context, key string, destroy bool) error {
+	endpoint := "data"
+	if destroy {
+		endpoint = "metadata"
+	}
+
+	_, err := t.client.Logical().DeleteWithContext(ctx, path.Join(t.mount, endpoint, key))
+	return err
+}
+
+const (
+	syncHelpSyn  = `Pushes the keys of the K/V store to other clusters.`
+	syncHelpDesc = `
+Sync destinations are K/V v2 mounts of other clusters the keys of this mount
+are pushed to. Changes to the keys under the prefix of a destination are
+queued and pushed periodically, or immediately through the sync endpoint of
+the destination. With the "fail" conflict policy, destination keys changed
+since they were last pushed are not replaced, and are reported in the status
+of the destination.
+`
+)
@@ -0,0 +1,206 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+// testSyncDestination serves a K/V v2 backend over HTTP at /v1/secret/, as
+// the mount of a destination cluster.
+func testSyncDestination(t *testing.T) (*httptest.Server, logical.Backend, logical.Storage) {
+	b, storage := getBackend(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := &logical.Request{
+			Path:    strings.TrimPrefix(r.URL.Path, "/v1/secret/"),
+			Storage: storage,
+		}
+		switch r.Method {
+		case http.MethodPut, http.MethodPost:
+			req.Operation = logical.UpdateOperation
+			if err := json.NewDecoder(r.Body).Decode(&req.Data); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		case http.MethodDelete:
+			req.Operation = logical.DeleteOperation
+		default:
+			req.Operation = logical.ReadOperation
+		}
+
+		resp, err := b.HandleRequest(context.Background(), req)
+		switch {
+		case resp != nil && resp.IsError():
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{resp.Error().Error()}})
+		case err != nil:
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{err.Error()}})
+		case resp == nil:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{"data": resp.Data})
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, b, storage
+}
+
+func TestVersionedKV_Sync(t *testing.T) {
+	b, storage := getBackend(t)
+	srv, remote, remoteStorage := testSyncDestination(t)
+
+	request := func(b logical.Backend, s logical.Storage, op logical.Operation, path string, data map[string]interface{}) *logical.Response {
+		t.Helper()
+		resp, err := b.HandleRequest(context.Background(), &logical.Request{
+			Operation: op,
+			Path:      path,
+			Storage:   s,
+			Data:      data,
+		})
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("%s %s: err:%v resp:%#v", op, path, err, resp)
+		}
+		return resp
+	}
+	remoteData := func(key string) map[string]interface{} {
+		t.Helper()
+		resp, err := remote.HandleRequest(context.Background(), &logical.Request{
+			Operation: logical.ReadOperation,
+			Path:      "data/" + key,
+			Storage:   remoteStorage,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp == nil || resp.Data["data"] == nil {
+			return nil
+		}
+		return resp.Data["data"].(map[string]interface{})
+	}
+	sync := func() *logical.Response {
+		t.Helper()
+		return request(b, storage, logical.UpdateOperation, "sync/destinations/edge/sync", nil)
+	}
+
+	// Keys written before the destination exists are only pushed when it is
+	// reconciled.
+	request(b, storage, logical.UpdateOperation, "data/edge/existing", map[string]interface{}{"data": map[string]interface{}{"a": "1"}})
+
+	request(b, storage, logical.UpdateOperation, "sync/destinations/edge", map[string]interface{}{
+		"address":         srv.URL,
+		"token":           "remote-token",
+		"prefix":          "edge/",
+		"conflict_policy": "fail",
+	})
+	resp := request(b, storage, logical.ReadOperation, "sync/destinations/edge", nil)
+	if _, ok := resp.Data["token"]; ok || resp.Data["mount"] != "secret" {
+		t.Fatalf("bad destination: %#v", resp.Data)
+	}
+
+	request(b, storage, logical.UpdateOperation, "data/edge/app", map[string]interface{}{"data": map[string]interface{}{"password": "hunter2"}})
+	request(b, storage, logical.UpdateOperation, "data/core/app", map[string]interface{}{"data": map[string]interface{}{"password": "secret"}})
+
+	resp = request(b, storage, logical.ReadOperation, "sync/destinations/edge/status", nil)
+	if resp.Data["pending"] != 1 {
+		t.Fatalf("expected a pending key: %#v", resp.Data)
+	}
+
+	resp = sync()
+	if resp.Data["pending"] != 0 || resp.Data["synced"] != uint64(1) || resp.Data["last_error"] != "" {
+		t.Fatalf("bad status: %#v", resp.Data)
+	}
+	if data := remoteData("edge/app"); data["password"] != "hunter2" {
+		t.Fatalf("bad remote data: %#v", data)
+	}
+	if remoteData("core/app") != nil || remoteData("edge/existing") != nil {
+		t.Fatal("keys outside of the prefix or unchanged were synced")
+	}
+
+	request(b, storage, logical.UpdateOperation, "sync/destinations/edge/sync", map[string]interface{}{"reconcile": true})
+	if data := remoteData("edge/existing"); data["a"] != "1" {
+		t.Fatalf("bad remote data after reconcile: %#v", data)
+	}
+
+	// A key changed at the destination is not replaced with the fail policy.
+	request(remote, remoteStorage, logical.UpdateOperation, "data/edge/app", map[string]interface{}{"data": map[string]interface{}{"password": "local"}})
+	request(b, storage, logical.UpdateOperation, "data/edge/app", map[string]interface{}{"data": map[string]interface{}{"password": "hunter3"}})
+	resp = sync()
+	if resp.Data["conflicts"] != uint64(1) || resp.Data["failed_keys"].(map[string]string)["edge/app"] == "" {
+		t.Fatalf("expected a conflict: %#v", resp.Data)
+	}
+	if data := remoteData("edge/app"); data["password"] != "local" {
+		t.Fatalf("conflicting key was replaced: %#v", data)
+	}
+
+	// It is replaced with the overwrite policy.
+	request(b, storage, logical.UpdateOperation, "sync/destinations/edge", map[string]interface{}{"conflict_policy": "overwrite"})
+	request(b, storage, logical.UpdateOperation, "data/edge/app", map[string]interface{}{"data": map[string]interface{}{"password": "hunter4"}})
+	resp = sync()
+	if len(resp.Data["failed_keys"].(map[string]string)) != 0 {
+		t.Fatalf("unexpected failures: %#v", resp.Data)
+	}
+	if data := remoteData("edge/app"); data["password"] != "hunter4" {
+		t.Fatalf("bad remote data: %#v", data)
+	}
+
+	// Deletions are pushed.
+	request(b, storage, logical.DeleteOperation, "data/edge/app", nil)
+	sync()
+	if remoteData("edge/app") != nil {
+		t.Fatal("deleted key is still readable at the destination")
+	}
+	request(b, storage, logical.DeleteOperation, "metadata/edge/existing", nil)
+	sync()
+	resp, err := remote.HandleRequest(context.Background(), &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "metadata/edge/existing",
+		Storage:   remoteStorage,
+	})
+	if err != nil || resp != nil {
+		t.Fatalf("expected the destination key to be removed, err:%v resp:%#v", err, resp)
+	}
+
+	// Failures to reach the destination are kept pending.
+	srv.Close()
+	request(b, storage, logical.UpdateOperation, "data/edge/app", map[string]interface{}{"data": map[string]interface{}{"password": "hunter5"}})
+	resp = sync()
+	if resp.Data["pending"] != 1 || resp.Data["last_error"] == "" {
+		t.Fatalf("expected the key to stay pending: %#v", resp.Data)
+	}
+
+	request(b, storage, logical.DeleteOperation, "sync/destinations/edge", nil)
+	resp = request(b, storage, logical.ListOperation, "sync/destinations/", nil)
+	if len(resp.Data) != 0 {
+		t.Fatalf("expected no destinations: %#v", resp.Data)
+	}
+}
+
+func TestVersionedKV_Sync_InvalidDestination(t *testing.T) {
+	b, storage := getBackend(t)
+
+	for _, data := range []map[string]interface{}{
+		{"token": "t"},
+		{"address": "http://127.0.0.1:8200"},
+		{"address": "http://127.0.0.1:8200", "token": "t", "type": "aws"},
+		{"address": "http://127.0.0.1:8200", "token": "t", "conflict_policy": "merge"},
+		{"address": "http://127.0.0.1:8200", "token": "t", "ca_cert": "not a certificate"},
+	} {
+		resp, err := b.HandleRequest(context.Background(), &logical.Request{
+			Operation: logical.UpdateOperation,
+			Path:      "sync/destinations/bad",
+			Storage:   storage,
+			Data:      data,
+		})
+		if err == nil && (resp == nil || !resp.IsError()) {
+			t.Fatalf("expected %v to be refused, resp:%#v", data, resp)
+		}
+	}
+}
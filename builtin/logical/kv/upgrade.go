@@ -45,7 +45,7 @@ func (b *versionedKVBackend) upgradeCheck(next framework.OperationFunc) framewor
 			}
 		}
 
-		return next(ctx, req, data)
+		return next(dryRunContext(ctx, req), req, data)
 	}
 }
 
@@ -75,8 +75,9 @@ func (b *backend) CheckOut(ctx context.Context, storage logical.Storage, service
 
 // CheckIn attempts to check in a service account. If an error occurs, the account remains checked out
 // and can either be retried by the caller, or eventually may be checked in if it has a ttl
-// that ends.
-func (b *backend) CheckIn(ctx context.Context, storage logical.Storage, serviceAccountName string) error {
+// that ends. The new password is generated with the given password policy of the library set,
+// if any.
+func (b *backend) CheckIn(ctx context.Context, storage logical.Storage, serviceAccountName, passwordPolicy string) error {
 	if ctx == nil {
 		return errors.New("ctx must be provided")
 	}
@@ -96,7 +97,7 @@ func (b *backend) CheckIn(ctx context.Context, storage logical.Storage, serviceA
 		return errors.New("the config is currently unset")
 	}
 
-	newPassword, err := b.GeneratePassword(ctx, config)
+	newPassword, err := b.GeneratePassword(ctx, config, passwordPolicy)
 	if err != nil {
 		return err
 	}
@@ -35,7 +35,7 @@ func TestCheckOutHandlerStorageLayer(t *testing.T) {
 	}
 
 	// Service accounts must initially be checked in to the library
-	if err := b.CheckIn(ctx, s, serviceAccountName); err != nil {
+	if err := b.CheckIn(ctx, s, serviceAccountName, ""); err != nil {
 		t.Fatal(err)
 	}
 
@@ -65,7 +65,7 @@ func TestCheckOutHandlerStorageLayer(t *testing.T) {
 	}
 
 	// If we try to check something in, it should succeed.
-	if err := b.CheckIn(ctx, s, serviceAccountName); err != nil {
+	if err := b.CheckIn(ctx, s, serviceAccountName, ""); err != nil {
 		t.Fatal(err)
 	}
 
@@ -79,7 +79,7 @@ func TestCheckOutHandlerStorageLayer(t *testing.T) {
 	}
 
 	// If we try to check it in again, it should have the same behavior.
-	if err := b.CheckIn(ctx, s, serviceAccountName); err != nil {
+	if err := b.CheckIn(ctx, s, serviceAccountName, ""); err != nil {
 		t.Fatal(err)
 	}
 
@@ -112,7 +112,7 @@ func TestPasswordHandlerInterfaceFulfillment(t *testing.T) {
 	}
 
 	// We must always start managing a service account by checking it in.
-	if err := b.CheckIn(ctx, s, serviceAccountName); err != nil {
+	if err := b.CheckIn(ctx, s, serviceAccountName, ""); err != nil {
 		t.Fatal(err)
 	}
 
@@ -126,7 +126,7 @@ func TestPasswordHandlerInterfaceFulfillment(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if err := b.CheckIn(ctx, s, serviceAccountName); err != nil {
+	if err := b.CheckIn(ctx, s, serviceAccountName, ""); err != nil {
 		t.Fatal(err)
 	}
 	currPassword, err := retrievePassword(ctx, s, serviceAccountName)
@@ -23,6 +23,7 @@ type dynamicRole struct {
 	UsernameTemplate string        `json:"username_template,omitempty" mapstructure:"username_template,omitempty"`
 	DefaultTTL       time.Duration `json:"default_ttl,omitempty"       mapstructure:"default_ttl,omitempty"`
 	MaxTTL           time.Duration `json:"max_ttl,omitempty"           mapstructure:"max_ttl,omitempty"`
+	PasswordPolicy   string        `json:"password_policy,omitempty"   mapstructure:"password_policy,omitempty"`
 }
 
 func retrieveDynamicRole(ctx context.Context, s logical.Storage, roleName string) (*dynamicRole, error) {
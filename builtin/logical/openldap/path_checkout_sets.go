@@ -21,6 +21,7 @@ type librarySet struct {
 	TTL                       time.Duration `json:"ttl"`
 	MaxTTL                    time.Duration `json:"max_ttl"`
 	DisableCheckInEnforcement bool          `json:"disable_check_in_enforcement"`
+	PasswordPolicy            string        `json:"password_policy,omitempty"`
 }
 
 // Validate ensures that a set meets our code assumptions that TTLs are set in
@@ -101,6 +102,10 @@ func (b *backend) pathSets() []*framework.Path {
 					Description: "Disable the default behavior of requiring that check-ins are performed by the entity that checked them out.",
 					Default:     false,
 				},
+				"password_policy": {
+					Type:        framework.TypeString,
+					Description: "Password policy to use to generate the passwords of the service accounts on check-in, instead of the one of the config.",
+				},
 			},
 			Operations: map[logical.Operation]framework.OperationHandler{
 				logical.CreateOperation: &framework.PathOperation{
@@ -178,6 +183,7 @@ func (b *backend) operationSetCreate(ctx context.Context, req *logical.Request,
 	ttl := time.Duration(fieldData.Get("ttl").(int)) * time.Second
 	maxTTL := time.Duration(fieldData.Get("max_ttl").(int)) * time.Second
 	disableCheckInEnforcement := fieldData.Get("disable_check_in_enforcement").(bool)
+	passwordPolicy := fieldData.Get("password_policy").(string)
 
 	if len(serviceAccountNames) == 0 {
 		return logical.ErrorResponse(`"service_account_names" must be provided`), nil
@@ -200,12 +206,13 @@ func (b *backend) operationSetCreate(ctx context.Context, req *logical.Request,
 		TTL:                       ttl,
 		MaxTTL:                    maxTTL,
 		DisableCheckInEnforcement: disableCheckInEnforcement,
+		PasswordPolicy:            passwordPolicy,
 	}
 	if err := set.Validate(); err != nil {
 		return logical.ErrorResponse(err.Error()), nil
 	}
 	for _, serviceAccountName := range serviceAccountNames {
-		if err := b.CheckIn(ctx, req.Storage, serviceAccountName); err != nil {
+		if err := b.CheckIn(ctx, req.Storage, serviceAccountName, set.PasswordPolicy); err != nil {
 			return nil, err
 		}
 	}
@@ -310,6 +317,9 @@ func (b *backend) operationSetUpdate(ctx context.Context, req *logical.Request,
 	if enforcementSent {
 		set.DisableCheckInEnforcement = disableCheckInEnforcement
 	}
+	if passwordPolicyRaw, ok := fieldData.GetOk("password_policy"); ok {
+		set.PasswordPolicy = passwordPolicyRaw.(string)
+	}
 
 	if err := set.Validate(); err != nil {
 		return logical.ErrorResponse(err.Error()), nil
@@ -317,7 +327,7 @@ func (b *backend) operationSetUpdate(ctx context.Context, req *logical.Request,
 
 	// Now that we know we can take all these actions, let's take them.
 	for _, newServiceAccountName := range beingAdded {
-		if err := b.CheckIn(ctx, req.Storage, newServiceAccountName); err != nil {
+		if err := b.CheckIn(ctx, req.Storage, newServiceAccountName, set.PasswordPolicy); err != nil {
 			return nil, err
 		}
 	}
@@ -363,6 +373,7 @@ func (b *backend) operationSetRead(ctx context.Context, req *logical.Request, fi
 			"ttl":                          int64(set.TTL.Seconds()),
 			"max_ttl":                      int64(set.MaxTTL.Seconds()),
 			"disable_check_in_enforcement": set.DisableCheckInEnforcement,
+			"password_policy":              set.PasswordPolicy,
 		},
 	}, nil
 }
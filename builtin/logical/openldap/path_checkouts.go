@@ -173,8 +173,18 @@ func (b *backend) endCheckOut(ctx context.Context, req *logical.Request, _ *fram
 	lock.Lock()
 	defer lock.Unlock()
 
+	// The set may have been deleted since the check-out.
+	set, err := readSet(ctx, req.Storage, setName)
+	if err != nil {
+		return nil, err
+	}
+	var passwordPolicy string
+	if set != nil {
+		passwordPolicy = set.PasswordPolicy
+	}
+
 	serviceAccountName := req.Secret.InternalData["service_account_name"].(string)
-	if err := b.CheckIn(ctx, req.Storage, serviceAccountName); err != nil {
+	if err := b.CheckIn(ctx, req.Storage, serviceAccountName, passwordPolicy); err != nil {
 		return nil, err
 	}
 	return nil, nil
@@ -313,7 +323,7 @@ func (b *backend) operationCheckIn(overrideCheckInEnforcement bool) framework.Op
 			}
 		}
 		for _, serviceAccountName := range toCheckIn {
-			if err := b.CheckIn(ctx, req.Storage, serviceAccountName); err != nil {
+			if err := b.CheckIn(ctx, req.Storage, serviceAccountName, set.PasswordPolicy); err != nil {
 				return nil, err
 			}
 		}
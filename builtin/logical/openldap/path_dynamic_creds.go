@@ -73,7 +73,7 @@ func (b *backend) pathDynamicCredsRead(ctx context.Context, req *logical.Request
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate username: %w", err)
 	}
-	password, err := b.GeneratePassword(ctx, config)
+	password, err := b.GeneratePassword(ctx, config, dRole.PasswordPolicy)
 	if err != nil {
 		return nil, err
 	}
@@ -210,6 +210,8 @@ func TestDynamicCredsRead_success(t *testing.T) {
 	roleName := "testrole"
 	passwordPolicyName := "testpolicy"
 	fakePassword := "fake password"
+	rolePasswordPolicyName := "rolepolicy"
+	fakeRolePassword := "fake role password"
 
 	type testCase struct {
 		reqDisplayName string
@@ -259,6 +261,25 @@ func TestDynamicCredsRead_success(t *testing.T) {
 			expectedTTL:           0,
 			expectedMaxTTL:        0,
 		},
+		"role password policy": {
+			reqDisplayName: "token-dispname",
+
+			role: dynamicRole{
+				Name:           roleName,
+				CreationLDIF:   ldifCreationTemplate,
+				PasswordPolicy: rolePasswordPolicyName,
+			},
+
+			config: config{
+				PasswordPolicy: passwordPolicyName,
+			},
+
+			expectedDNRegex:       "^cn=v_token-dispname_testrole_[a-zA-Z0-9]{10}_[0-9]{10},ou=users,dc=hashicorp,dc=com$",
+			expectedUsernameRegex: "^v_token-dispname_testrole_[a-zA-Z0-9]{10}_[0-9]{10}$",
+			expectedPasswordRegex: "^" + fakeRolePassword + "$",
+			expectedTTL:           0,
+			expectedMaxTTL:        0,
+		},
 		"custom username": {
 			reqDisplayName: "token-dispname",
 
@@ -315,6 +336,9 @@ func TestDynamicCredsRead_success(t *testing.T) {
 						passwordPolicyName: func() (pass string, err error) {
 							return fakePassword, nil
 						},
+						rolePasswordPolicyName: func() (pass string, err error) {
+							return fakeRolePassword, nil
+						},
 					},
 				},
 				StorageView: &logical.InmemStorage{},
@@ -64,6 +64,10 @@ func (b *backend) pathDynamicRoles() []*framework.Path {
 					Type:        framework.TypeDurationSecond,
 					Description: "Max TTL a dynamic credential can be extended to",
 				},
+				"password_policy": {
+					Type:        framework.TypeString,
+					Description: "Password policy to use to generate passwords, instead of the one of the config",
+				},
 			},
 			ExistenceCheck: b.pathDynamicRoleExistenceCheck,
 			Operations: map[logical.Operation]framework.OperationHandler{
@@ -280,6 +284,7 @@ func (b *backend) pathDynamicRoleRead(ctx context.Context, req *logical.Request,
 			"username_template": dRole.UsernameTemplate,
 			"default_ttl":       dRole.DefaultTTL.Seconds(),
 			"max_ttl":           dRole.MaxTTL.Seconds(),
+			"password_policy":   dRole.PasswordPolicy,
 		},
 	}
 	return resp, nil
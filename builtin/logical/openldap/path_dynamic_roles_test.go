@@ -899,6 +899,7 @@ func TestDynamicRoleRead(t *testing.T) {
 					"username_template": "v-foo-{{.RoleName}}-{{rand 20}}-{{unix_seconds}}",
 					"default_ttl":       (24 * time.Hour).Seconds(),
 					"max_ttl":           (5 * 24 * time.Hour).Seconds(),
+					"password_policy":   "",
 				},
 			},
 			expectErr: false,
@@ -82,7 +82,7 @@ func (b *backend) pathRotateRootCredentialsUpdate(ctx context.Context, req *logi
 		return nil, errors.New("the config is currently unset")
 	}
 
-	newPassword, err := b.GeneratePassword(ctx, config)
+	newPassword, err := b.GeneratePassword(ctx, config, "")
 	if err != nil {
 		return nil, err
 	}
@@ -46,7 +46,7 @@ func (b *backend) pathStaticCredsRead(ctx context.Context, req *logical.Request,
 		return nil, err
 	}
 	if role == nil {
-		return logical.ErrorResponse("unknown role: %s", name), nil
+		return logical.CodedErrorResponse(logical.ErrorCodeNotFound, "unknown role: %s", name), nil
 	}
 
 	return &logical.Response{
@@ -101,6 +101,76 @@ func TestCreds(t *testing.T) {
 		}
 	})
 
+	t.Run("creds with role password policy", func(t *testing.T) {
+		b, storage := getBackend(false)
+		defer b.Cleanup(context.Background())
+
+		req := &logical.Request{
+			Operation: logical.CreateOperation,
+			Path:      configPath,
+			Storage:   storage,
+			Data: map[string]interface{}{
+				"binddn":          "tester",
+				"bindpass":        "pa$$w0rd",
+				"url":             "ldap://138.91.247.105",
+				"certificate":     validCertificate,
+				"password_policy": testPasswordPolicy1,
+			},
+		}
+
+		resp, err := b.HandleRequest(context.Background(), req)
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("err:%s resp:%#v\n", err, resp)
+		}
+
+		req = &logical.Request{
+			Operation: logical.CreateOperation,
+			Path:      staticRolePath + "hashicorp",
+			Storage:   storage,
+			Data: map[string]interface{}{
+				"username":        "hashicorp",
+				"dn":              "uid=hashicorp,ou=users,dc=hashicorp,dc=com",
+				"rotation_period": "60s",
+				"password_policy": testPasswordPolicy2,
+			},
+		}
+
+		resp, err = b.HandleRequest(context.Background(), req)
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("err:%s resp:%#v\n", err, resp)
+		}
+
+		req = &logical.Request{
+			Operation: logical.ReadOperation,
+			Path:      staticRolePath + "hashicorp",
+			Storage:   storage,
+		}
+
+		resp, err = b.HandleRequest(context.Background(), req)
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("err:%s resp:%#v\n", err, resp)
+		}
+
+		if resp.Data["password_policy"] != testPasswordPolicy2 {
+			t.Fatalf("expected password_policy to be %s, got %s", testPasswordPolicy2, resp.Data["password_policy"])
+		}
+
+		req = &logical.Request{
+			Operation: logical.ReadOperation,
+			Path:      staticCredPath + "hashicorp",
+			Storage:   storage,
+		}
+
+		resp, err = b.HandleRequest(context.Background(), req)
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("err:%s resp:%#v\n", err, resp)
+		}
+
+		if resp.Data["password"] != testPasswordFromPolicy2 {
+			t.Fatalf("expected password to be %s, got %s", testPasswordFromPolicy2, resp.Data["password"])
+		}
+	})
+
 	t.Run("cred doesn't exist", func(t *testing.T) {
 		b, storage := getBackend(false)
 		defer b.Cleanup(context.Background())
@@ -123,6 +123,10 @@ func staticFields() map[string]*framework.FieldSchema {
 			Type:        framework.TypeBool,
 			Description: "Skip the initial pasword rotation on import (has no effect on updates)",
 		},
+		"password_policy": {
+			Type:        framework.TypeString,
+			Description: "Password policy to use to generate passwords, instead of the one of the config.",
+		},
 	}
 	return fields
 }
@@ -207,6 +211,7 @@ func (b *backend) pathStaticRoleRead(ctx context.Context, req *logical.Request,
 	}
 
 	data["rotation_period"] = role.StaticAccount.RotationPeriod.Seconds()
+	data["password_policy"] = role.StaticAccount.PasswordPolicy
 	if !role.StaticAccount.LastVaultRotation.IsZero() {
 		data["last_vault_rotation"] = role.StaticAccount.LastVaultRotation
 	}
@@ -285,6 +290,10 @@ func (b *backend) pathStaticRoleCreateUpdate(ctx context.Context, req *logical.R
 		role.StaticAccount.RotationPeriod = time.Duration(rotationPeriodSeconds) * time.Second
 	}
 
+	if passwordPolicyRaw, ok := data.GetOk("password_policy"); ok {
+		role.StaticAccount.PasswordPolicy = passwordPolicyRaw.(string)
+	}
+
 	skipRotation := false
 	skipRotationRaw, ok := data.GetOk("skip_import_rotation")
 	if ok {
@@ -412,6 +421,10 @@ type staticAccount struct {
 	// "time to live". This value is compared to the LastVaultRotation to
 	// determine if a password needs to be rotated
 	RotationPeriod time.Duration `json:"rotation_period"`
+
+	// PasswordPolicy is the name of the password policy used to generate the
+	// passwords of the account, instead of the one of the config
+	PasswordPolicy string `json:"password_policy,omitempty"`
 }
 
 // NextRotationTime calculates the next rotation by adding the Rotation Period
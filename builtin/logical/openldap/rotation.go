@@ -327,6 +327,12 @@ func (b *backend) setStaticAccountPassword(ctx context.Context, s logical.Storag
 		return output, errors.New("the config is currently unset")
 	}
 
+	// Passwords of WAL entries generated with another policy are not reused.
+	passwordPolicy := input.Role.StaticAccount.PasswordPolicy
+	if passwordPolicy == "" {
+		passwordPolicy = config.PasswordPolicy
+	}
+
 	var newPassword string
 	if output.WALID != "" {
 		wal, err := b.findStaticWAL(ctx, s, output.WALID)
@@ -340,7 +346,7 @@ func (b *backend) setStaticAccountPassword(ctx context.Context, s logical.Storag
 
 			// Generate a new WAL entry and credential
 			output.WALID = ""
-		case wal.NewPassword != "" && wal.PasswordPolicy != config.PasswordPolicy:
+		case wal.NewPassword != "" && wal.PasswordPolicy != passwordPolicy:
 			b.Logger().Debug("password policy changed, generating new password", "role", input.RoleName, "WAL ID", output.WALID)
 			if err := framework.DeleteWAL(ctx, s, output.WALID); err != nil {
 				b.Logger().Warn("failed to delete WAL", "error", err, "WAL ID", output.WALID)
@@ -355,7 +361,7 @@ func (b *backend) setStaticAccountPassword(ctx context.Context, s logical.Storag
 	}
 
 	if output.WALID == "" {
-		newPassword, err = b.GeneratePassword(ctx, config)
+		newPassword, err = b.GeneratePassword(ctx, config, input.Role.StaticAccount.PasswordPolicy)
 		if err != nil {
 			return output, err
 		}
@@ -365,7 +371,7 @@ func (b *backend) setStaticAccountPassword(ctx context.Context, s logical.Storag
 			DN:                input.Role.StaticAccount.DN,
 			NewPassword:       newPassword,
 			LastVaultRotation: input.Role.StaticAccount.LastVaultRotation,
-			PasswordPolicy:    config.PasswordPolicy,
+			PasswordPolicy:    passwordPolicy,
 		})
 		b.Logger().Debug("wrote WAL", "role", input.RoleName, "WAL ID", output.WALID)
 		if err != nil {
@@ -375,7 +381,7 @@ func (b *backend) setStaticAccountPassword(ctx context.Context, s logical.Storag
 
 	if newPassword == "" {
 		b.Logger().Error("newPassword was empty, re-generating based on the password policy")
-		newPassword, err = b.GeneratePassword(ctx, config)
+		newPassword, err = b.GeneratePassword(ctx, config, input.Role.StaticAccount.PasswordPolicy)
 		if err != nil {
 			return output, err
 		}
@@ -421,15 +427,21 @@ func (b *backend) setStaticAccountPassword(ctx context.Context, s logical.Storag
 	return &setStaticAccountOutput{RotationTime: lvr}, nil
 }
 
-func (b *backend) GeneratePassword(ctx context.Context, cfg *config) (string, error) {
-	if cfg.PasswordPolicy == "" {
+// GeneratePassword generates a password with the given password policy of a
+// role, or with the password policy or length of the config when the role has
+// none.
+func (b *backend) GeneratePassword(ctx context.Context, cfg *config, passwordPolicy string) (string, error) {
+	if passwordPolicy == "" {
+		passwordPolicy = cfg.PasswordPolicy
+	}
+	if passwordPolicy == "" {
 		if cfg.PasswordLength == 0 {
 			return base62.Random(defaultPasswordLength)
 		}
 		return base62.Random(cfg.PasswordLength)
 	}
 
-	password, err := b.System().GeneratePasswordFromPolicy(ctx, cfg.PasswordPolicy)
+	password, err := b.System().GeneratePasswordFromPolicy(ctx, passwordPolicy)
 	if err != nil {
 		return "", fmt.Errorf("unable to generate password: %w", err)
 	}
@@ -0,0 +1,129 @@
+package pairing
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/salt"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	cache "github.com/patrickmn/go-cache"
+)
+
+const operationPrefixPairing = "pairing"
+
+func Factory(ctx context.Context, conf *logical.BackendConfig) (logical.Backend, error) {
+	b := Backend(conf)
+	if err := b.Setup(ctx, conf); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func Backend(conf *logical.BackendConfig) *backend {
+	b := &backend{
+		view:              conf.StorageView,
+		failedRedemptions: cache.New(0, time.Minute),
+		codeFailureBases:  make(map[string]uint64),
+	}
+	b.Backend = &framework.Backend{
+		Help: strings.TrimSpace(backendHelp),
+
+		PathsSpecial: &logical.Paths{
+			Unauthenticated: []string{
+				"redeem",
+			},
+			SealWrapStorage: []string{
+				codePrefix,
+			},
+		},
+
+		Paths: []*framework.Path{
+			pathConfig(b),
+			pathRolesList(b),
+			pathRoles(b),
+			pathIssue(b),
+			pathCodesList(b),
+			pathCodes(b),
+			pathRedeem(b),
+		},
+
+		PeriodicFunc: b.tidyCodes,
+		Invalidate:   b.invalidate,
+		BackendType:  logical.TypeLogical,
+	}
+
+	return b
+}
+
+type backend struct {
+	*framework.Backend
+
+	// The salt used to derive the storage keys of the codes, so that codes
+	// are never stored in the clear.
+	salt      *salt.Salt
+	saltMutex sync.RWMutex
+
+	// The view to use when creating the salt
+	view logical.Storage
+
+	// codeLock serializes the redemptions and revocations of codes, so that
+	// each code is redeemed at most once.
+	codeLock sync.Mutex
+
+	// failedRedemptions counts the failed redemptions of each client
+	// address over the configured window, for rate limiting.
+	failedRedemptions *cache.Cache
+
+	// failedRedemptionCount counts all the failed redemptions, and
+	// codeFailureBases holds its value when each code was issued or first
+	// seen, so that a code is invalidated once too many redemptions failed
+	// while it was outstanding, whatever their client addresses. Both are
+	// guarded by codeLock.
+	failedRedemptionCount uint64
+	codeFailureBases      map[string]uint64
+}
+
+func (b *backend) Salt(ctx context.Context) (*salt.Salt, error) {
+	b.saltMutex.RLock()
+	if b.salt != nil {
+		defer b.saltMutex.RUnlock()
+		return b.salt, nil
+	}
+	b.saltMutex.RUnlock()
+	b.saltMutex.Lock()
+	defer b.saltMutex.Unlock()
+	if b.salt != nil {
+		return b.salt, nil
+	}
+	salt, err := salt.NewSalt(ctx, b.view, &salt.Config{
+		HashFunc: salt.SHA256Hash,
+		Location: salt.DefaultLocation,
+	})
+	if err != nil {
+		return nil, err
+	}
+	b.salt = salt
+	return salt, nil
+}
+
+func (b *backend) invalidate(_ context.Context, key string) {
+	switch key {
+	case salt.DefaultLocation:
+		b.saltMutex.Lock()
+		defer b.saltMutex.Unlock()
+		b.salt = nil
+	}
+}
+
+const backendHelp = `
+The pairing backend issues single-use, short-lived codes for device
+onboarding.
+
+A code is issued against a role, along with the secret it unlocks. A human
+relays the short code to the device, which redeems it once, without a token,
+to receive the secret response-wrapped. Roles constrain the format and
+lifetime of the codes and the networks they may be redeemed from.
+`
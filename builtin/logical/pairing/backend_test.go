@@ -0,0 +1,270 @@
+package pairing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/stretchr/testify/require"
+)
+
+func testBackend(t *testing.T) (*backend, logical.Storage) {
+	t.Helper()
+
+	storage := &logical.InmemStorage{}
+	config := logical.TestBackendConfig()
+	config.StorageView = storage
+
+	b, err := Factory(context.Background(), config)
+	require.NoError(t, err)
+
+	return b.(*backend), storage
+}
+
+func testRequest(b *backend, storage logical.Storage, op logical.Operation, path string, data map[string]interface{}) (*logical.Response, error) {
+	return b.HandleRequest(context.Background(), &logical.Request{
+		Operation:  op,
+		Path:       path,
+		Storage:    storage,
+		Data:       data,
+		Connection: &logical.Connection{RemoteAddr: "127.0.0.1"},
+	})
+}
+
+func TestPairing_Role(t *testing.T) {
+	b, storage := testBackend(t)
+
+	resp, err := testRequest(b, storage, logical.CreateOperation, "role/device", nil)
+	require.NoError(t, err)
+	require.Nil(t, resp)
+
+	resp, err = testRequest(b, storage, logical.ReadOperation, "role/device", nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"code_length":       defaultCodeLength,
+		"code_charset":      charsetAlphanumeric,
+		"code_max_failures": defaultCodeMaxFailures,
+		"ttl":               int64(300),
+		"wrap_ttl":          int64(300),
+		"bound_cidrs":       []string{},
+	}, resp.Data)
+
+	for _, data := range []map[string]interface{}{
+		{"code_length": 4},
+		{"code_charset": "emoji"},
+		{"code_max_failures": 0},
+		{"ttl": 0},
+		{"bound_cidrs": "not a cidr"},
+	} {
+		resp, err = testRequest(b, storage, logical.UpdateOperation, "role/device", data)
+		require.ErrorIs(t, err, logical.ErrInvalidRequest, "data: %v", data)
+		require.True(t, resp.IsError())
+	}
+
+	resp, err = testRequest(b, storage, logical.ListOperation, "role/", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"device"}, resp.Data["keys"])
+}
+
+func TestPairing_IssueAndRedeem(t *testing.T) {
+	b, storage := testBackend(t)
+
+	_, err := testRequest(b, storage, logical.CreateOperation, "role/device", map[string]interface{}{
+		"code_charset": charsetAlphanumeric,
+		"code_length":  10,
+		"wrap_ttl":     "2m",
+	})
+	require.NoError(t, err)
+
+	resp, err := testRequest(b, storage, logical.UpdateOperation, "issue/device", map[string]interface{}{
+		"data": map[string]interface{}{"secret_id": "s3cr3t"},
+		"ttl":  "1m",
+	})
+	require.NoError(t, err)
+	code := resp.Data["code"].(string)
+	codeID := resp.Data["code_id"].(string)
+	require.Len(t, code, 10)
+	require.NotContains(t, codeID, code)
+
+	// The data of a code is never read back
+	resp, err = testRequest(b, storage, logical.ReadOperation, "codes/"+codeID, nil)
+	require.NoError(t, err)
+	require.Equal(t, "device", resp.Data["role"])
+	require.NotContains(t, resp.Data, "data")
+
+	// Relayed codes may be reformatted by the human
+	relayed := strings.ToLower(code[:5] + "-" + code[5:])
+	resp, err = testRequest(b, storage, logical.UpdateOperation, "redeem", map[string]interface{}{"code": relayed})
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"secret_id": "s3cr3t"}, resp.Data)
+	require.NotNil(t, resp.WrapInfo)
+	require.Equal(t, 2*time.Minute, resp.WrapInfo.TTL)
+
+	// Codes are single-use
+	resp, err = testRequest(b, storage, logical.UpdateOperation, "redeem", map[string]interface{}{"code": code})
+	require.ErrorIs(t, err, logical.ErrPermissionDenied)
+	require.True(t, resp.IsError())
+
+	// The TTL of a code cannot exceed the one of its role
+	_, err = testRequest(b, storage, logical.UpdateOperation, "issue/device", map[string]interface{}{
+		"data": map[string]interface{}{"secret_id": "s3cr3t"},
+		"ttl":  "1h",
+	})
+	require.ErrorIs(t, err, logical.ErrInvalidRequest)
+}
+
+func TestPairing_ExpiredAndRevokedCodes(t *testing.T) {
+	b, storage := testBackend(t)
+
+	_, err := testRequest(b, storage, logical.CreateOperation, "role/device", nil)
+	require.NoError(t, err)
+
+	issue := func() (string, string) {
+		resp, err := testRequest(b, storage, logical.UpdateOperation, "issue/device", map[string]interface{}{
+			"data": map[string]interface{}{"token": "wrapped"},
+		})
+		require.NoError(t, err)
+		return resp.Data["code"].(string), resp.Data["code_id"].(string)
+	}
+
+	// Revoked codes cannot be redeemed
+	code, codeID := issue()
+	_, err = testRequest(b, storage, logical.DeleteOperation, "codes/"+codeID, nil)
+	require.NoError(t, err)
+	_, err = testRequest(b, storage, logical.UpdateOperation, "redeem", map[string]interface{}{"code": code})
+	require.ErrorIs(t, err, logical.ErrPermissionDenied)
+
+	// Expired codes cannot be redeemed, and are tidied
+	code, codeID = issue()
+	entry, err := b.code(context.Background(), storage, codeID)
+	require.NoError(t, err)
+	entry.Expiration = time.Now().Add(-time.Second)
+	storageEntry, err := logical.StorageEntryJSON(codePrefix+codeID, entry)
+	require.NoError(t, err)
+	require.NoError(t, storage.Put(context.Background(), storageEntry))
+
+	require.NoError(t, b.tidyCodes(context.Background(), &logical.Request{Storage: storage}))
+	resp, err := testRequest(b, storage, logical.ListOperation, "codes/", nil)
+	require.NoError(t, err)
+	require.Empty(t, resp.Data["keys"])
+	_, err = testRequest(b, storage, logical.UpdateOperation, "redeem", map[string]interface{}{"code": code})
+	require.ErrorIs(t, err, logical.ErrPermissionDenied)
+}
+
+func TestPairing_BoundCIDRs(t *testing.T) {
+	b, storage := testBackend(t)
+
+	_, err := testRequest(b, storage, logical.CreateOperation, "role/device", map[string]interface{}{
+		"bound_cidrs": "10.0.0.0/8",
+	})
+	require.NoError(t, err)
+
+	resp, err := testRequest(b, storage, logical.UpdateOperation, "issue/device", map[string]interface{}{
+		"data": map[string]interface{}{"token": "wrapped"},
+	})
+	require.NoError(t, err)
+	code := resp.Data["code"].(string)
+
+	_, err = testRequest(b, storage, logical.UpdateOperation, "redeem", map[string]interface{}{"code": code})
+	require.ErrorIs(t, err, logical.ErrPermissionDenied)
+
+	// The code was not consumed by the denied redemption
+	resp, err = b.HandleRequest(context.Background(), &logical.Request{
+		Operation:  logical.UpdateOperation,
+		Path:       "redeem",
+		Storage:    storage,
+		Data:       map[string]interface{}{"code": code},
+		Connection: &logical.Connection{RemoteAddr: "10.1.2.3"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "wrapped", resp.Data["token"])
+}
+
+func TestPairing_RateLimit(t *testing.T) {
+	b, storage := testBackend(t)
+
+	_, err := testRequest(b, storage, logical.UpdateOperation, "config", map[string]interface{}{
+		"max_failed_redemptions": 2,
+	})
+	require.NoError(t, err)
+	_, err = testRequest(b, storage, logical.CreateOperation, "role/device", nil)
+	require.NoError(t, err)
+
+	resp, err := testRequest(b, storage, logical.UpdateOperation, "issue/device", map[string]interface{}{
+		"data": map[string]interface{}{"token": "wrapped"},
+	})
+	require.NoError(t, err)
+	code := resp.Data["code"].(string)
+
+	for i := 0; i < 2; i++ {
+		_, err = testRequest(b, storage, logical.UpdateOperation, "redeem", map[string]interface{}{"code": "not-a-code"})
+		require.ErrorIs(t, err, logical.ErrPermissionDenied)
+	}
+
+	// Even valid codes are denied once the limit is reached
+	_, err = testRequest(b, storage, logical.UpdateOperation, "redeem", map[string]interface{}{"code": code})
+	require.ErrorIs(t, err, logical.ErrRateLimitQuotaExceeded)
+
+	resp, err = testRequest(b, storage, logical.ReadOperation, "config", nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, resp.Data["max_failed_redemptions"])
+	require.Equal(t, int64(900), resp.Data["failed_redemption_window"])
+}
+
+func TestPairing_CodeMaxFailures(t *testing.T) {
+	b, storage := testBackend(t)
+
+	_, err := testRequest(b, storage, logical.CreateOperation, "role/device", map[string]interface{}{
+		"code_max_failures": 3,
+	})
+	require.NoError(t, err)
+
+	resp, err := testRequest(b, storage, logical.UpdateOperation, "issue/device", map[string]interface{}{
+		"data": map[string]interface{}{"token": "wrapped"},
+	})
+	require.NoError(t, err)
+	code := resp.Data["code"].(string)
+
+	// Failed redemptions from many addresses stay below the limit of each
+	// address, but invalidate the outstanding codes
+	for i := 0; i < 3; i++ {
+		_, err = b.HandleRequest(context.Background(), &logical.Request{
+			Operation:  logical.UpdateOperation,
+			Path:       "redeem",
+			Storage:    storage,
+			Data:       map[string]interface{}{"code": "not-a-code"},
+			Connection: &logical.Connection{RemoteAddr: fmt.Sprintf("10.0.0.%d", i+1)},
+		})
+		require.ErrorIs(t, err, logical.ErrPermissionDenied)
+	}
+
+	_, err = testRequest(b, storage, logical.UpdateOperation, "redeem", map[string]interface{}{"code": code})
+	require.ErrorIs(t, err, logical.ErrPermissionDenied)
+	resp, err = testRequest(b, storage, logical.ListOperation, "codes/", nil)
+	require.NoError(t, err)
+	require.Empty(t, resp.Data["keys"])
+
+	// Codes issued afterwards are not affected by the earlier failures
+	resp, err = testRequest(b, storage, logical.UpdateOperation, "issue/device", map[string]interface{}{
+		"data": map[string]interface{}{"token": "wrapped"},
+	})
+	require.NoError(t, err)
+	resp, err = testRequest(b, storage, logical.UpdateOperation, "redeem", map[string]interface{}{"code": resp.Data["code"]})
+	require.NoError(t, err)
+	require.Equal(t, "wrapped", resp.Data["token"])
+}
+
+func TestPairing_GenerateCode(t *testing.T) {
+	for charset, chars := range codeCharsets {
+		code, err := generateCode(32, chars)
+		require.NoError(t, err)
+		require.Len(t, code, 32)
+		for _, c := range code {
+			require.Contains(t, chars, string(c), "charset %s", charset)
+		}
+		require.Equal(t, code, normalizeCode(code))
+	}
+}
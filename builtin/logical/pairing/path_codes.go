@@ -0,0 +1,363 @@
+package pairing
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const (
+	codePrefix = "code/"
+
+	// maxCodeCollisions is the number of times a new code is drawn when it
+	// collides with an outstanding one before giving up.
+	maxCodeCollisions = 10
+)
+
+type codeEntry struct {
+	Role       string                 `json:"role"`
+	Data       map[string]interface{} `json:"data"`
+	IssueTime  time.Time              `json:"issue_time"`
+	Expiration time.Time              `json:"expiration"`
+}
+
+func (c *codeEntry) expired() bool {
+	return time.Now().After(c.Expiration)
+}
+
+func pathIssue(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "issue/" + framework.GenericNameRegex("role"),
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPairing,
+			OperationVerb:   "issue",
+			OperationSuffix: "code",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"role": {
+				Type:        framework.TypeString,
+				Description: "Name of the role to issue the code against.",
+			},
+			"data": {
+				Type:        framework.TypeMap,
+				Description: "Secret returned, response-wrapped, on redemption of the code.",
+				Required:    true,
+			},
+			"ttl": {
+				Type:        framework.TypeDurationSecond,
+				Description: "Time the code can be redeemed for. Cannot exceed the TTL of the role, which is the default.",
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.UpdateOperation: &framework.PathOperation{
+				Callback: b.pathIssueWrite,
+			},
+		},
+
+		HelpSynopsis:    pathIssueHelpSyn,
+		HelpDescription: pathIssueHelpDesc,
+	}
+}
+
+func pathCodesList(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "codes/?",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPairing,
+			OperationSuffix: "codes",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"after": {
+				Type:        framework.TypeString,
+				Description: `Optional entry to list begin listing after, not required to exist.`,
+			},
+			"limit": {
+				Type:        framework.TypeInt,
+				Description: `Optional number of entries to return; defaults to all entries.`,
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ListOperation: b.pathCodeList,
+		},
+
+		HelpSynopsis:    pathCodesHelpSyn,
+		HelpDescription: pathCodesHelpDesc,
+	}
+}
+
+func pathCodes(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "codes/" + framework.GenericNameRegex("code_id"),
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPairing,
+			OperationSuffix: "code",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"code_id": {
+				Type:        framework.TypeString,
+				Description: "Identifier of the code, as returned on issuance.",
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation:   b.pathCodeRead,
+			logical.DeleteOperation: b.pathCodeDelete,
+		},
+
+		HelpSynopsis:    pathCodesHelpSyn,
+		HelpDescription: pathCodesHelpDesc,
+	}
+}
+
+// generateCode draws a random code of the given length from the charset.
+func generateCode(length int, charset string) (string, error) {
+	max := big.NewInt(int64(len(charset)))
+	code := make([]byte, length)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = charset[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// normalizeCode undoes the formatting a human may add when relaying a code.
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
+
+// codeID returns the identifier of the code, under which it is stored.
+func (b *backend) codeID(ctx context.Context, code string) (string, error) {
+	salt, err := b.Salt(ctx)
+	if err != nil {
+		return "", err
+	}
+	return salt.SaltID(code), nil
+}
+
+func (b *backend) code(ctx context.Context, s logical.Storage, id string) (*codeEntry, error) {
+	if id == "" {
+		return nil, errors.New("missing code ID")
+	}
+
+	entry, err := s.Get(ctx, codePrefix+id)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var result codeEntry
+	if err := entry.DecodeJSON(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (b *backend) pathIssueWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	roleName := strings.ToLower(d.Get("role").(string))
+	role, err := b.role(ctx, req.Storage, roleName)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return logical.CodedErrorResponse(logical.ErrorCodeNotFound, "unknown role %q", roleName), logical.ErrInvalidRequest
+	}
+
+	data := d.Get("data").(map[string]interface{})
+	if len(data) == 0 {
+		return logical.ErrorResponse("data must be set"), logical.ErrInvalidRequest
+	}
+
+	ttl := role.TTL
+	if raw, ok := d.GetOk("ttl"); ok {
+		ttl = time.Duration(raw.(int)) * time.Second
+		if ttl <= 0 {
+			return logical.ErrorResponse("ttl must be positive"), logical.ErrInvalidRequest
+		}
+		if ttl > role.TTL {
+			return logical.ErrorResponse(fmt.Sprintf("ttl cannot exceed the TTL of the role (%s)", role.TTL)), logical.ErrInvalidRequest
+		}
+	}
+
+	b.codeLock.Lock()
+	defer b.codeLock.Unlock()
+
+	var code, id string
+	for i := 0; ; i++ {
+		if i == maxCodeCollisions {
+			return nil, errors.New("failed to generate a unique code, consider increasing the code_length of the role")
+		}
+
+		code, err = generateCode(role.CodeLength, codeCharsets[role.CodeCharset])
+		if err != nil {
+			return nil, err
+		}
+		id, err = b.codeID(ctx, code)
+		if err != nil {
+			return nil, err
+		}
+
+		existing, err := b.code(ctx, req.Storage, id)
+		if err != nil {
+			return nil, err
+		}
+		if existing == nil || existing.expired() {
+			break
+		}
+	}
+
+	now := time.Now()
+	codeEntry := &codeEntry{
+		Role:       roleName,
+		Data:       data,
+		IssueTime:  now,
+		Expiration: now.Add(ttl),
+	}
+	entry, err := logical.StorageEntryJSON(codePrefix+id, codeEntry)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Storage.Put(ctx, entry); err != nil {
+		return nil, err
+	}
+	b.codeFailureBases[id] = b.failedRedemptionCount
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"code":       code,
+			"code_id":    id,
+			"role":       roleName,
+			"expiration": codeEntry.Expiration.Format(time.RFC3339),
+		},
+	}, nil
+}
+
+func (b *backend) pathCodeList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	after := d.Get("after").(string)
+	limit := d.Get("limit").(int)
+	if limit <= 0 {
+		limit = -1
+	}
+
+	codes, err := req.Storage.ListPage(ctx, codePrefix, after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return logical.ListResponse(codes), nil
+}
+
+func (b *backend) pathCodeRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	code, err := b.code(ctx, req.Storage, d.Get("code_id").(string))
+	if err != nil {
+		return nil, err
+	}
+	if code == nil || code.expired() {
+		return nil, nil
+	}
+
+	// The secret of the code is only ever returned on redemption
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"role":       code.Role,
+			"issue_time": code.IssueTime.Format(time.RFC3339),
+			"expiration": code.Expiration.Format(time.RFC3339),
+		},
+	}, nil
+}
+
+func (b *backend) pathCodeDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	b.codeLock.Lock()
+	defer b.codeLock.Unlock()
+
+	if err := b.deleteCode(ctx, req.Storage, d.Get("code_id").(string)); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// deleteCode deletes the code with the given identifier. Callers must hold
+// codeLock.
+func (b *backend) deleteCode(ctx context.Context, s logical.Storage, id string) error {
+	if err := s.Delete(ctx, codePrefix+id); err != nil {
+		return err
+	}
+	delete(b.codeFailureBases, id)
+	return nil
+}
+
+// tidyCodes deletes the expired codes.
+func (b *backend) tidyCodes(ctx context.Context, req *logical.Request) error {
+	ids, err := req.Storage.List(ctx, codePrefix)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := b.tidyCode(ctx, req.Storage, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *backend) tidyCode(ctx context.Context, s logical.Storage, id string) error {
+	b.codeLock.Lock()
+	defer b.codeLock.Unlock()
+
+	code, err := b.code(ctx, s, id)
+	if err != nil {
+		return err
+	}
+	if code == nil || !code.expired() {
+		return nil
+	}
+
+	b.Logger().Debug("deleting expired code", "code_id", id)
+	return b.deleteCode(ctx, s, id)
+}
+
+const pathIssueHelpSyn = `
+Issue a single-use code unlocking a secret.
+`
+
+const pathIssueHelpDesc = `
+This endpoint issues a short code against a role, storing the given data
+until the code is redeemed or expires. The code itself is only returned
+here; it is stored salted, and identified by the returned code_id.
+
+Secrets engines cannot create tokens, so to onboard a device with a token,
+create a use-limited or response-wrapped token and pass it in the data.
+`
+
+const pathCodesHelpSyn = `
+Inspect and revoke outstanding codes.
+`
+
+const pathCodesHelpDesc = `
+This endpoint lists the identifiers of the outstanding codes, reads the
+metadata of a code, and revokes a code before it is redeemed. The data of a
+code is only ever returned on redemption.
+`
@@ -0,0 +1,139 @@
+package pairing
+
+import (
+	"context"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const (
+	configPath = "config"
+
+	defaultMaxFailedRedemptions   = 10
+	defaultFailedRedemptionWindow = 15 * time.Minute
+)
+
+type configEntry struct {
+	MaxFailedRedemptions   int           `json:"max_failed_redemptions"`
+	FailedRedemptionWindow time.Duration `json:"failed_redemption_window"`
+}
+
+func pathConfig(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "config",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPairing,
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"max_failed_redemptions": {
+				Type:        framework.TypeInt,
+				Description: "Number of failed redemptions after which a client address is denied further redemptions for the rest of failed_redemption_window. Defaults to 10.",
+			},
+			"failed_redemption_window": {
+				Type:        framework.TypeDurationSecond,
+				Description: "Window over which the failed redemptions of a client address are counted. Defaults to 15m.",
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathConfigRead,
+				DisplayAttrs: &framework.DisplayAttributes{
+					OperationSuffix: "configuration",
+				},
+			},
+			logical.UpdateOperation: &framework.PathOperation{
+				Callback: b.pathConfigWrite,
+				DisplayAttrs: &framework.DisplayAttributes{
+					OperationVerb: "configure",
+				},
+			},
+		},
+
+		HelpSynopsis:    pathConfigHelpSyn,
+		HelpDescription: pathConfigHelpDesc,
+	}
+}
+
+// config returns the configuration of the backend, with the defaults set.
+func (b *backend) config(ctx context.Context, s logical.Storage) (*configEntry, error) {
+	config := &configEntry{
+		MaxFailedRedemptions:   defaultMaxFailedRedemptions,
+		FailedRedemptionWindow: defaultFailedRedemptionWindow,
+	}
+
+	entry, err := s.Get(ctx, configPath)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil {
+		if err := entry.DecodeJSON(config); err != nil {
+			return nil, err
+		}
+	}
+
+	return config, nil
+}
+
+func (b *backend) pathConfigRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	config, err := b.config(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"max_failed_redemptions":   config.MaxFailedRedemptions,
+			"failed_redemption_window": int64(config.FailedRedemptionWindow.Seconds()),
+		},
+	}, nil
+}
+
+func (b *backend) pathConfigWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	config, err := b.config(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+
+	if raw, ok := d.GetOk("max_failed_redemptions"); ok {
+		config.MaxFailedRedemptions = raw.(int)
+	}
+	if raw, ok := d.GetOk("failed_redemption_window"); ok {
+		config.FailedRedemptionWindow = time.Duration(raw.(int)) * time.Second
+	}
+
+	if config.MaxFailedRedemptions < 1 {
+		return logical.ErrorResponse("max_failed_redemptions must be at least 1"), logical.ErrInvalidRequest
+	}
+	if config.FailedRedemptionWindow <= 0 {
+		return logical.ErrorResponse("failed_redemption_window must be positive"), logical.ErrInvalidRequest
+	}
+
+	entry, err := logical.StorageEntryJSON(configPath, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, req.Storage.Put(ctx, entry)
+}
+
+const pathConfigHelpSyn = `
+Configure the rate limiting of code redemptions.
+`
+
+const pathConfigHelpDesc = `
+Codes are short, so the redemptions of each client address are rate limited:
+once a client address failed max_failed_redemptions redemptions within
+failed_redemption_window, its redemptions are denied until the window
+elapses. Failed redemptions are counted by each node separately.
+
+Clients sharing an address share its limit: behind a proxy, authorize the
+proxy in the x_forwarded_for settings of the listener so that the addresses
+of the clients are used. The codes of a role are also invalidated after
+code_max_failures failed redemptions from any address, see the role
+endpoint.
+`
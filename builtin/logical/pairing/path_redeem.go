@@ -0,0 +1,153 @@
+package pairing
+
+import (
+	"context"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/cidrutil"
+	"github.com/openbao/openbao/sdk/v2/helper/wrapping"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+func pathRedeem(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "redeem",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPairing,
+			OperationVerb:   "redeem",
+			OperationSuffix: "code",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"code": {
+				Type:        framework.TypeString,
+				Description: "The code to redeem. Dashes and spaces are ignored, and alphanumeric codes are case-insensitive.",
+				Required:    true,
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.UpdateOperation: &framework.PathOperation{
+				Callback: b.pathRedeemWrite,
+			},
+		},
+
+		HelpSynopsis:    pathRedeemHelpSyn,
+		HelpDescription: pathRedeemHelpDesc,
+	}
+}
+
+func (b *backend) pathRedeemWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	code := normalizeCode(d.Get("code").(string))
+	if code == "" {
+		return logical.ErrorResponse("missing code"), logical.ErrInvalidRequest
+	}
+
+	var remoteAddr string
+	if req.Connection != nil {
+		remoteAddr = req.Connection.RemoteAddr
+	}
+
+	config, err := b.config(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+
+	b.codeLock.Lock()
+	defer b.codeLock.Unlock()
+
+	if failed, ok := b.failedRedemptions.Get(remoteAddr); ok && failed.(int) >= config.MaxFailedRedemptions {
+		return logical.ErrorResponse("too many failed redemptions, try again later"), logical.ErrRateLimitQuotaExceeded
+	}
+
+	id, err := b.codeID(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := b.code(ctx, req.Storage, id)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil && entry.expired() {
+		if err := b.deleteCode(ctx, req.Storage, id); err != nil {
+			return nil, err
+		}
+		entry = nil
+	}
+	if entry == nil {
+		b.recordFailedRedemption(remoteAddr, config)
+		return logical.ErrorResponse("invalid or expired code"), logical.ErrPermissionDenied
+	}
+
+	role, err := b.role(ctx, req.Storage, entry.Role)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		// Codes do not outlive their role
+		if err := b.deleteCode(ctx, req.Storage, id); err != nil {
+			return nil, err
+		}
+		b.recordFailedRedemption(remoteAddr, config)
+		return logical.ErrorResponse("invalid or expired code"), logical.ErrPermissionDenied
+	}
+
+	// Codes only withstand a number of failed redemptions, so that they
+	// cannot be guessed by spreading attempts over many client addresses.
+	base, ok := b.codeFailureBases[id]
+	if !ok {
+		base = b.failedRedemptionCount
+		b.codeFailureBases[id] = base
+	}
+	if b.failedRedemptionCount-base >= uint64(role.CodeMaxFailures) {
+		b.Logger().Warn("invalidating code after too many failed redemptions", "code_id", id, "role", entry.Role)
+		if err := b.deleteCode(ctx, req.Storage, id); err != nil {
+			return nil, err
+		}
+		b.recordFailedRedemption(remoteAddr, config)
+		return logical.ErrorResponse("invalid or expired code"), logical.ErrPermissionDenied
+	}
+
+	// A redemption from outside the bound networks does not consume the
+	// code, so that it cannot be burnt from elsewhere.
+	if len(role.BoundCIDRs) > 0 && !cidrutil.RemoteAddrIsOk(remoteAddr, role.BoundCIDRs) {
+		b.recordFailedRedemption(remoteAddr, config)
+		return logical.ErrorResponse("code cannot be redeemed from this address"), logical.ErrPermissionDenied
+	}
+
+	if err := b.deleteCode(ctx, req.Storage, id); err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: entry.Data,
+		WrapInfo: &wrapping.ResponseWrapInfo{
+			TTL: role.WrapTTL,
+		},
+	}, nil
+}
+
+// recordFailedRedemption counts a failed redemption from the address.
+// Callers must hold codeLock.
+func (b *backend) recordFailedRedemption(remoteAddr string, config *configEntry) {
+	b.failedRedemptionCount++
+	if _, err := b.failedRedemptions.IncrementInt(remoteAddr, 1); err != nil {
+		b.failedRedemptions.Set(remoteAddr, 1, config.FailedRedemptionWindow)
+	}
+}
+
+const pathRedeemHelpSyn = `
+Redeem a code for the secret it unlocks.
+`
+
+const pathRedeemHelpDesc = `
+This endpoint does not require a token. It consumes the given code and
+returns the secret it unlocks, response-wrapped with the wrap_ttl of its
+role: unwrap the returned wrapping token to read the secret.
+
+Client addresses failing too many redemptions are denied further
+redemptions for a while, see the config endpoint. Outstanding codes are
+invalidated once code_max_failures redemptions failed on the mount since
+they were issued, whatever the client addresses.
+`
@@ -0,0 +1,298 @@
+package pairing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	sockaddr "github.com/hashicorp/go-sockaddr"
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/parseutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const (
+	rolePrefix = "role/"
+
+	defaultCodeLength      = 10
+	minCodeLength          = 6
+	maxCodeLength          = 32
+	defaultCodeMaxFailures = 100
+	defaultCodeTTL         = 5 * time.Minute
+	defaultWrapTTL         = 5 * time.Minute
+)
+
+// The character sets codes are generated from. The alphanumeric set leaves out
+// the characters easily confused when relayed by a human (I, L, O and U).
+const (
+	charsetNumeric      = "numeric"
+	charsetAlphanumeric = "alphanumeric"
+)
+
+var codeCharsets = map[string]string{
+	charsetNumeric:      "0123456789",
+	charsetAlphanumeric: "0123456789ABCDEFGHJKMNPQRSTVWXYZ",
+}
+
+func pathRolesList(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "role/?",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPairing,
+			OperationSuffix: "roles",
+			Navigation:      true,
+			ItemType:        "Role",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"after": {
+				Type:        framework.TypeString,
+				Description: `Optional entry to list begin listing after, not required to exist.`,
+			},
+			"limit": {
+				Type:        framework.TypeInt,
+				Description: `Optional number of entries to return; defaults to all entries.`,
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ListOperation: b.pathRoleList,
+		},
+
+		HelpSynopsis:    pathRoleHelpSyn,
+		HelpDescription: pathRoleHelpDesc,
+	}
+}
+
+func pathRoles(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "role/" + framework.GenericNameRegex("name"),
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPairing,
+			OperationSuffix: "role",
+			Action:          "Create",
+			ItemType:        "Role",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Name of the role.",
+			},
+
+			"code_length": {
+				Type:        framework.TypeInt,
+				Description: fmt.Sprintf("Number of characters of the codes, between %d and %d. Defaults to %d.", minCodeLength, maxCodeLength, defaultCodeLength),
+			},
+
+			"code_charset": {
+				Type:          framework.TypeString,
+				Description:   `Characters the codes are made of: "numeric" or "alphanumeric". Alphanumeric codes are upper-case and leave out the characters I, L, O and U. Defaults to "alphanumeric".`,
+				AllowedValues: []interface{}{charsetNumeric, charsetAlphanumeric},
+			},
+
+			"code_max_failures": {
+				Type:        framework.TypeInt,
+				Description: fmt.Sprintf("Number of failed redemptions on the mount, from any client address, after which an outstanding code of the role is invalidated. Defaults to %d.", defaultCodeMaxFailures),
+			},
+
+			"ttl": {
+				Type:        framework.TypeDurationSecond,
+				Description: "Maximum time a code can be redeemed for after being issued. Defaults to 5m.",
+			},
+
+			"wrap_ttl": {
+				Type:        framework.TypeDurationSecond,
+				Description: "TTL of the wrapping token returned on redemption. Defaults to 5m.",
+			},
+
+			"bound_cidrs": {
+				Type:        framework.TypeCommaStringSlice,
+				Description: "Comma-separated list of CIDR blocks. If set, the codes of the role can only be redeemed from these networks.",
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.DeleteOperation: b.pathRoleDelete,
+			logical.ReadOperation:   b.pathRoleRead,
+			logical.UpdateOperation: b.pathRoleWrite,
+			logical.CreateOperation: b.pathRoleWrite,
+		},
+
+		ExistenceCheck: b.roleExistenceCheck,
+
+		HelpSynopsis:    pathRoleHelpSyn,
+		HelpDescription: pathRoleHelpDesc,
+	}
+}
+
+type roleEntry struct {
+	CodeLength      int                           `json:"code_length"`
+	CodeCharset     string                        `json:"code_charset"`
+	CodeMaxFailures int                           `json:"code_max_failures"`
+	TTL             time.Duration                 `json:"ttl"`
+	WrapTTL         time.Duration                 `json:"wrap_ttl"`
+	BoundCIDRs      []*sockaddr.SockAddrMarshaler `json:"bound_cidrs"`
+}
+
+func (b *backend) roleExistenceCheck(ctx context.Context, req *logical.Request, d *framework.FieldData) (bool, error) {
+	role, err := b.role(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return false, err
+	}
+
+	return role != nil, nil
+}
+
+func (b *backend) role(ctx context.Context, s logical.Storage, name string) (*roleEntry, error) {
+	if name == "" {
+		return nil, errors.New("missing role name")
+	}
+
+	entry, err := s.Get(ctx, rolePrefix+strings.ToLower(name))
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var result roleEntry
+	if err := entry.DecodeJSON(&result); err != nil {
+		return nil, err
+	}
+
+	// Roles created before the option was added get the default
+	if result.CodeMaxFailures == 0 {
+		result.CodeMaxFailures = defaultCodeMaxFailures
+	}
+
+	return &result, nil
+}
+
+func (b *backend) pathRoleList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	after := d.Get("after").(string)
+	limit := d.Get("limit").(int)
+	if limit <= 0 {
+		limit = -1
+	}
+
+	roles, err := req.Storage.ListPage(ctx, rolePrefix, after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return logical.ListResponse(roles), nil
+}
+
+func (b *backend) pathRoleDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if err := req.Storage.Delete(ctx, rolePrefix+strings.ToLower(d.Get("name").(string))); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (b *backend) pathRoleRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	role, err := b.role(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, nil
+	}
+
+	boundCIDRs := make([]string, 0, len(role.BoundCIDRs))
+	for _, cidr := range role.BoundCIDRs {
+		boundCIDRs = append(boundCIDRs, cidr.String())
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"code_length":       role.CodeLength,
+			"code_charset":      role.CodeCharset,
+			"code_max_failures": role.CodeMaxFailures,
+			"ttl":               int64(role.TTL.Seconds()),
+			"wrap_ttl":          int64(role.WrapTTL.Seconds()),
+			"bound_cidrs":       boundCIDRs,
+		},
+	}, nil
+}
+
+func (b *backend) pathRoleWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := strings.ToLower(d.Get("name").(string))
+	role, err := b.role(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	// Due to existence check, role will only be nil if it's a create operation
+	if role == nil {
+		role = &roleEntry{
+			CodeLength:      defaultCodeLength,
+			CodeCharset:     charsetAlphanumeric,
+			CodeMaxFailures: defaultCodeMaxFailures,
+			TTL:             defaultCodeTTL,
+			WrapTTL:         defaultWrapTTL,
+		}
+	}
+
+	if raw, ok := d.GetOk("code_length"); ok {
+		role.CodeLength = raw.(int)
+	}
+	if raw, ok := d.GetOk("code_charset"); ok {
+		role.CodeCharset = raw.(string)
+	}
+	if raw, ok := d.GetOk("code_max_failures"); ok {
+		role.CodeMaxFailures = raw.(int)
+	}
+	if raw, ok := d.GetOk("ttl"); ok {
+		role.TTL = time.Duration(raw.(int)) * time.Second
+	}
+	if raw, ok := d.GetOk("wrap_ttl"); ok {
+		role.WrapTTL = time.Duration(raw.(int)) * time.Second
+	}
+	if raw, ok := d.GetOk("bound_cidrs"); ok {
+		role.BoundCIDRs, err = parseutil.ParseAddrs(raw.([]string))
+		if err != nil {
+			return logical.ErrorResponse(fmt.Sprintf("invalid bound_cidrs: %v", err)), logical.ErrInvalidRequest
+		}
+	}
+
+	if role.CodeLength < minCodeLength || role.CodeLength > maxCodeLength {
+		return logical.ErrorResponse(fmt.Sprintf("code_length must be between %d and %d", minCodeLength, maxCodeLength)), logical.ErrInvalidRequest
+	}
+	if _, ok := codeCharsets[role.CodeCharset]; !ok {
+		return logical.ErrorResponse(fmt.Sprintf("invalid code_charset %q", role.CodeCharset)), logical.ErrInvalidRequest
+	}
+	if role.CodeMaxFailures < 1 {
+		return logical.ErrorResponse("code_max_failures must be at least 1"), logical.ErrInvalidRequest
+	}
+	if role.TTL <= 0 {
+		return logical.ErrorResponse("ttl must be positive"), logical.ErrInvalidRequest
+	}
+	if role.WrapTTL <= 0 {
+		return logical.ErrorResponse("wrap_ttl must be positive"), logical.ErrInvalidRequest
+	}
+
+	entry, err := logical.StorageEntryJSON(rolePrefix+name, role)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, req.Storage.Put(ctx, entry)
+}
+
+const pathRoleHelpSyn = `
+Manage roles that codes are issued against.
+`
+
+const pathRoleHelpDesc = `
+This endpoint allows you to create, read, update, and delete roles. A role
+sets the format and lifetime of the codes issued against it, the number of
+failed redemptions they withstand, the TTL of the wrapping token returned on
+redemption, and the networks its codes may be redeemed from.
+`
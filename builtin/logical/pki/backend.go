@@ -107,6 +107,7 @@ func Backend(conf *logical.BackendConfig) *backend {
 				clusterConfigPath,
 				"crls/",
 				"certs/",
+				certMetadataPath,
 				acmePathPrefix,
 			},
 
@@ -166,6 +167,7 @@ func Backend(conf *logical.BackendConfig) *backend {
 			pathConfigIssuers(&b),
 			pathReplaceRoot(&b),
 			pathRevokeIssuer(&b),
+			pathKubernetesBootstrap(&b),
 
 			// Key APIs
 			pathListKeys(&b),
@@ -182,6 +184,8 @@ func Backend(conf *logical.BackendConfig) *backend {
 			pathFetchValidRaw(&b),
 			pathFetchValid(&b),
 			pathFetchListCerts(&b),
+			pathListCertMetadata(&b),
+			pathCertMetadata(&b),
 
 			// OCSP APIs
 			buildPathOcspGet(&b),
@@ -355,7 +359,7 @@ func (b *backend) metricsWrap(callType string, roleMode int, ofunc roleOperation
 				return nil, err
 			}
 			if role == nil && (roleMode == roleRequired || len(roleName) > 0) {
-				return logical.ErrorResponse(fmt.Sprintf("unknown role: %s", roleName)), nil
+				return logical.CodedErrorResponse(logical.ErrorCodeNotFound, "unknown role: %s", roleName), nil
 			}
 			labels = []metrics.Label{{"role", roleName}}
 		}
@@ -2589,7 +2589,7 @@ func TestBackend_Root_Idempotency(t *testing.T) {
 	require.NotNil(t, resp, "expected ca info")
 	keyId1 := resp.Data["key_id"]
 	issuerId1 := resp.Data["issuer_id"]
-	cert := parseCert(t, resp.Data["certificate"].(string))
+	cert := ToCertificate(t, resp.Data["certificate"].(string))
 	certSkid := certutil.GetHexFormatted(cert.SubjectKeyId, ":")
 
 	//  -> Validate the SKID matches between the root cert and the key
@@ -2611,7 +2611,7 @@ func TestBackend_Root_Idempotency(t *testing.T) {
 	require.NotNil(t, resp, "expected ca info")
 	keyId2 := resp.Data["key_id"]
 	issuerId2 := resp.Data["issuer_id"]
-	cert = parseCert(t, resp.Data["certificate"].(string))
+	cert = ToCertificate(t, resp.Data["certificate"].(string))
 	certSkid = certutil.GetHexFormatted(cert.SubjectKeyId, ":")
 
 	//  -> Validate the SKID matches between the root cert and the key
@@ -2795,7 +2795,7 @@ func TestBackend_SignIntermediate_AllowedPastCAValidity(t *testing.T) {
 		t.Fatalf("expected warnings, got %#v", *resp)
 	}
 
-	cert := parseCert(t, resp.Data["certificate"].(string))
+	cert := ToCertificate(t, resp.Data["certificate"].(string))
 	certSkid := certutil.GetHexFormatted(cert.SubjectKeyId, ":")
 	require.Equal(t, intSkid, certSkid)
 
@@ -3796,6 +3796,121 @@ func TestBackend_AllowedDomainsTemplate(t *testing.T) {
 	}
 }
 
+func TestBackend_SubjectTemplate(t *testing.T) {
+	t.Parallel()
+	coreConfig := &vault.CoreConfig{
+		CredentialBackends: map[string]logical.Factory{
+			"userpass": userpass.Factory,
+		},
+		LogicalBackends: map[string]logical.Factory{
+			"pki": Factory,
+		},
+	}
+	cluster := vault.NewTestCluster(t, coreConfig, &vault.TestClusterOptions{
+		HandlerFunc: vaulthttp.Handler,
+	})
+	cluster.Start()
+	defer cluster.Cleanup()
+	client := cluster.Cores[0].Client
+
+	err := client.Sys().PutPolicy("test", `
+   path "pki/*" {
+     capabilities = ["update"]
+   }`)
+	require.NoError(t, err)
+	require.NoError(t, client.Sys().EnableAuth("userpass", "userpass", ""))
+	_, err = client.Logical().Write("auth/userpass/users/machine", map[string]interface{}{
+		"password": "test",
+		"policies": "test",
+	})
+	require.NoError(t, err)
+
+	auths, err := client.Sys().ListAuth()
+	require.NoError(t, err)
+	userpassAccessor := auths["userpass/"].Accessor
+
+	err = client.Sys().Mount("pki", &api.MountInput{Type: "pki"})
+	require.NoError(t, err)
+	_, err = client.Logical().Write("pki/root/generate/internal", map[string]interface{}{
+		"ttl":         "40h",
+		"common_name": "myvault.com",
+	})
+	require.NoError(t, err)
+
+	// Templates are validated when the role is written
+	_, err = client.Logical().Write("pki/roles/test", map[string]interface{}{
+		"allow_any_name":   true,
+		"ou":               "{{identity.entity.metadata.team",
+		"subject_template": true,
+	})
+	require.ErrorContains(t, err, "invalid subject template")
+
+	_, err = client.Logical().Write("pki/roles/test", map[string]interface{}{
+		"allow_any_name":        true,
+		"ttl":                   "1h",
+		"ou":                    "{{identity.entity.metadata.team}},machines",
+		"organization":          "{{identity.entity.metadata.environment}}",
+		"subject_serial_number": "{{identity.entity.aliases." + userpassAccessor + ".name}}",
+		"subject_template":      true,
+	})
+	require.NoError(t, err)
+
+	// The certificate of a token without an entity cannot be templated
+	_, err = client.Logical().Write("pki/issue/test", map[string]interface{}{"common_name": "machine.example.com"})
+	require.ErrorContains(t, err, "requires the request to be made by an entity")
+
+	rootToken := client.Token()
+	userpassAuth, err := auth.NewUserpassAuth("machine", &auth.Password{FromString: "test"})
+	require.NoError(t, err)
+	secret, err := client.Auth().Login(context.TODO(), userpassAuth)
+	require.NoError(t, err)
+	entityID := secret.Auth.EntityID
+	userToken := client.Token()
+
+	// Templates referencing missing metadata fail the issuance
+	_, err = client.Logical().Write("pki/issue/test", map[string]interface{}{"common_name": "machine.example.com"})
+	require.ErrorContains(t, err, "unable to populate subject template")
+
+	client.SetToken(rootToken)
+	_, err = client.Logical().Write("identity/entity/id/"+entityID, map[string]interface{}{
+		"metadata": map[string]string{
+			"team":        "payments",
+			"environment": "production",
+		},
+	})
+	require.NoError(t, err)
+	client.SetToken(userToken)
+
+	resp, err := client.Logical().Write("pki/issue/test", map[string]interface{}{"common_name": "machine.example.com"})
+	require.NoError(t, err)
+	cert := ToCertificate(t, resp.Data["certificate"].(string))
+	require.ElementsMatch(t, []string{"payments", "machines"}, cert.Subject.OrganizationalUnit)
+	require.Equal(t, []string{"production"}, cert.Subject.Organization)
+	require.Equal(t, "machine", cert.Subject.SerialNumber)
+
+	// The serial number set by the role cannot be overridden
+	_, err = client.Logical().Write("pki/issue/test", map[string]interface{}{
+		"common_name":   "machine.example.com",
+		"serial_number": "other",
+	})
+	require.ErrorContains(t, err, "serial_number cannot be set")
+
+	// Without subject_template, the values are used as-is
+	client.SetToken(rootToken)
+	_, err = client.Logical().Write("pki/roles/test", map[string]interface{}{
+		"allow_any_name":        true,
+		"ttl":                   "1h",
+		"ou":                    "{{identity.entity.metadata.team}}",
+		"subject_serial_number": "",
+	})
+	require.NoError(t, err)
+	client.SetToken(userToken)
+	resp, err = client.Logical().Write("pki/issue/test", map[string]interface{}{"common_name": "machine.example.com"})
+	require.NoError(t, err)
+	cert = ToCertificate(t, resp.Data["certificate"].(string))
+	require.Equal(t, []string{"{{identity.entity.metadata.team}}"}, cert.Subject.OrganizationalUnit)
+}
+
 func TestReadWriteDeleteRoles(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
@@ -3893,9 +4008,12 @@ func TestReadWriteDeleteRoles(t *testing.T) {
 		"key_bits":                           json.Number("2048"),
 		"max_ttl":                            json.Number("0"),
 		"no_store":                           false,
+		"sign_only":                          false,
 		"organization":                       []interface{}{},
 		"province":                           []interface{}{},
 		"street_address":                     []interface{}{},
+		"subject_serial_number":              "",
+		"subject_template":                   false,
 		"code_signing_flag":                  false,
 		"issuer_ref":                         "default",
 		"cn_validations":                     []interface{}{"email", "hostname"},
@@ -6405,7 +6523,7 @@ func TestPKI_TemplatedAIAs(t *testing.T) {
 	requireSuccessNonNilResponse(t, resp, err)
 
 	// Validate the AIA info is correctly templated.
-	cert := parseCert(t, resp.Data["certificate"].(string))
+	cert := ToCertificate(t, resp.Data["certificate"].(string))
 	require.Equal(t, cert.OCSPServer, []string{"http://localhost:8200/v1/pki/ocsp"})
 	require.Equal(t, cert.IssuingCertificateURL, []string{"http://localhost:8200/cdn/pki/issuer/" + issuerId + "/der"})
 	require.Equal(t, cert.CRLDistributionPoints, []string{"http://localhost:8200/v1/pki/issuer/" + issuerId + "/crl/der"})
@@ -6444,7 +6562,7 @@ func TestPKI_TemplatedAIAs(t *testing.T) {
 	requireSuccessNonNilResponse(t, resp, err)
 
 	// Validate the AIA info is correctly templated.
-	cert = parseCert(t, resp.Data["certificate"].(string))
+	cert = ToCertificate(t, resp.Data["certificate"].(string))
 	require.Equal(t, cert.OCSPServer, []string{"http://localhost/c"})
 	require.Equal(t, cert.IssuingCertificateURL, []string{"http://localhost/b"})
 	require.Equal(t, cert.CRLDistributionPoints, []string{"http://localhost/a"})
@@ -7014,6 +7132,8 @@ func TestProperAuthing(t *testing.T) {
 		"cert/delta-crl":                         shouldBeUnauthedReadList,
 		"cert/delta-crl/raw":                     shouldBeUnauthedReadList,
 		"cert/delta-crl/raw/pem":                 shouldBeUnauthedReadList,
+		"cert-metadata":                          shouldBeAuthed,
+		"cert-metadata/" + serial:                shouldBeAuthed,
 		"certs":                                  shouldBeAuthed,
 		"certs/revoked":                          shouldBeAuthed,
 		"config/acme":                            shouldBeAuthed,
@@ -7094,6 +7214,7 @@ func TestProperAuthing(t *testing.T) {
 		"sign-verbatim":                          shouldBeAuthed,
 		"sign-verbatim/test":                     shouldBeAuthed,
 		"sign/test":                              shouldBeAuthed,
+		"kubernetes/bootstrap/test":              shouldBeAuthed,
 		"tidy":                                   shouldBeAuthed,
 		"tidy-cancel":                            shouldBeAuthed,
 		"tidy-status":                            shouldBeAuthed,
@@ -7145,6 +7266,9 @@ func TestProperAuthing(t *testing.T) {
 		if strings.Contains(raw_path, "roles/") && strings.Contains(raw_path, "{name}") {
 			raw_path = strings.ReplaceAll(raw_path, "{name}", "test")
 		}
+		if strings.Contains(raw_path, "{cluster}") {
+			raw_path = strings.ReplaceAll(raw_path, "{cluster}", "test")
+		}
 		if strings.Contains(raw_path, "{role}") {
 			raw_path = strings.ReplaceAll(raw_path, "{role}", "test")
 		}
@@ -7369,7 +7493,7 @@ func TestGenerateRootCAWithAIA(t *testing.T) {
 	// Read the root and ensure it does not have AIA information. This is
 	// because roots do not have much use for AIA as it would be self
 	// referential.
-	cert := parseCert(t, resp.Data["certificate"].(string))
+	cert := ToCertificate(t, resp.Data["certificate"].(string))
 	require.Equal(t, len(cert.OCSPServer), 0)
 	require.Equal(t, len(cert.IssuingCertificateURL), 0)
 	require.Equal(t, len(cert.CRLDistributionPoints), 0)
@@ -7530,3 +7654,58 @@ var (
 	edCAKey   string
 	edCACert  string
 )
+
+func TestPki_IssueDryRun(t *testing.T) {
+	t.Parallel()
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root.example.com",
+		"key_type":    "ec",
+		"ttl":         "72h",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"key_type":         "ec",
+		"ttl":              "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+
+	issue := func(data map[string]interface{}) (*logical.Response, error) {
+		return b.HandleRequest(context.Background(), &logical.Request{
+			Operation:  logical.UpdateOperation,
+			Path:       "issue/example",
+			Data:       data,
+			Storage:    s,
+			MountPoint: "pki/",
+			DryRun:     true,
+		})
+	}
+
+	resp, err = issue(map[string]interface{}{
+		"common_name": "host.example.com",
+		"alt_names":   "alt.example.com",
+		"ip_sans":     "10.0.0.1",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	require.Equal(t, "host.example.com", resp.Data["common_name"])
+	require.Equal(t, []string{"host.example.com", "alt.example.com"}, resp.Data["alt_names"])
+	require.Equal(t, []string{"10.0.0.1"}, resp.Data["ip_sans"])
+	require.Equal(t, "ec", resp.Data["key_type"])
+	require.NotContains(t, resp.Data, "certificate")
+	require.NotContains(t, resp.Data, "private_key")
+
+	// The role constraints are checked
+	resp, err = issue(map[string]interface{}{
+		"common_name": "host.example.org",
+	})
+	require.NoError(t, err)
+	require.True(t, resp.IsError())
+
+	// No certificate was stored
+	resp, err = CBList(b, s, "certs")
+	requireSuccessNonNilResponse(t, resp, err)
+	require.Len(t, resp.Data["keys"], 1)
+}
@@ -287,6 +287,39 @@ func validateURISAN(b *backend, data *inputBundle, uri string) bool {
 	return valid
 }
 
+// populateSubjectTemplate populates the identity template of a subject
+// field of the role from the entity of the requester. Values are used as-is
+// unless the role has subject_template set.
+func populateSubjectTemplate(b *backend, data *inputBundle, tpl string) (string, error) {
+	if !data.role.SubjectTemplate {
+		return tpl, nil
+	}
+	isTemplate, _ := framework.ValidateIdentityTemplate(tpl)
+	if !isTemplate {
+		return tpl, nil
+	}
+	if data.req == nil || data.req.EntityID == "" {
+		return "", errutil.UserError{Err: fmt.Sprintf("subject template %q requires the request to be made by an entity", tpl)}
+	}
+	out, err := framework.PopulateIdentityTemplate(tpl, data.req.EntityID, b.System())
+	if err != nil {
+		return "", errutil.UserError{Err: fmt.Sprintf("unable to populate subject template %q: %v", tpl, err)}
+	}
+	return out, nil
+}
+
+func populateSubjectTemplates(b *backend, data *inputBundle, tpls []string) ([]string, error) {
+	out := make([]string, 0, len(tpls))
+	for _, tpl := range tpls {
+		value, err := populateSubjectTemplate(b, data, tpl)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, value)
+	}
+	return out, nil
+}
+
 // Validates a given common name, ensuring it's either an email or a hostname
 // after validating it according to the role parameters, or disables
 // validation altogether.
@@ -740,23 +773,16 @@ func generateCert(sc *storageContext,
 	randomSource io.Reader) (*certutil.ParsedCertBundle, []string, error,
 ) {
 	ctx := sc.Context
-	b := sc.Backend
-
-	if input.role == nil {
-		return nil, nil, errutil.InternalError{Err: "no role found in data bundle"}
-	}
 
-	if input.role.KeyType == "rsa" && input.role.KeyBits < 2048 {
-		return nil, nil, errutil.UserError{Err: "RSA keys < 2048 bits are unsafe and not supported"}
+	data, warnings, err := validateCertInput(sc.Backend, input, caSign)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	data, warnings, err := generateCreationBundle(b, input, caSign, nil)
+	data.Params.SerialNumber, err = sc.generateSerialNumber()
 	if err != nil {
 		return nil, nil, err
 	}
-	if data.Params == nil {
-		return nil, nil, errutil.InternalError{Err: "nil parameters received from parameter bundle generation"}
-	}
 
 	if isCA {
 		data.Params.IsCA = isCA
@@ -829,6 +855,10 @@ func generateCert(sc *storageContext,
 func generateIntermediateCSR(sc *storageContext, input *inputBundle, randomSource io.Reader) (*certutil.ParsedCSRBundle, []string, error) {
 	b := sc.Backend
 
+	if err := b.checkRestrictedCrypto(certutil.PrivateKeyType(input.role.KeyType)); err != nil {
+		return nil, nil, err
+	}
+
 	creation, warnings, err := generateCreationBundle(b, input, nil, nil)
 	if err != nil {
 		return nil, nil, err
@@ -846,12 +876,14 @@ func generateIntermediateCSR(sc *storageContext, input *inputBundle, randomSourc
 	return parsedBundle, warnings, nil
 }
 
-func signCert(b *backend,
+func signCert(sc *storageContext,
 	data *inputBundle,
 	caSign *certutil.CAInfoBundle,
 	isCA bool,
 	useCSRValues bool) (*certutil.ParsedCertBundle, []string, error,
 ) {
+	b := sc.Backend
+
 	if data.role == nil {
 		return nil, nil, errutil.InternalError{Err: "no role found in data bundle"}
 	}
@@ -1019,6 +1051,13 @@ func signCert(b *backend,
 		}
 	}
 
+	if err := b.checkRestrictedCrypto(certutil.PrivateKeyType(actualKeyType)); err != nil {
+		return nil, nil, err
+	}
+	if err := b.checkRestrictedCrypto(caSign.PrivateKeyType); err != nil {
+		return nil, nil, err
+	}
+
 	creation, warnings, err := generateCreationBundle(b, data, caSign, csr)
 	if err != nil {
 		return nil, nil, err
@@ -1030,6 +1069,11 @@ func signCert(b *backend,
 	creation.Params.IsCA = isCA
 	creation.Params.UseCSRValues = useCSRValues
 
+	creation.Params.SerialNumber, err = sc.generateSerialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+
 	if isCA {
 		creation.Params.PermittedDNSDomains = data.apiData.Get("permitted_dns_domains").([]string)
 
@@ -1186,6 +1230,36 @@ func dedupCommonNames(cnRaw string) []string {
 // generateCreationBundle is a shared function that reads parameters supplied
 // from the various endpoints and generates a CreationParameters with the
 // parameters that can be used to issue or sign
+// validateCertInput checks the request of a certificate to generate against
+// its role, returning the parameters of the certificate.
+func validateCertInput(b *backend, input *inputBundle, caSign *certutil.CAInfoBundle) (*certutil.CreationBundle, []string, error) {
+	if input.role == nil {
+		return nil, nil, errutil.InternalError{Err: "no role found in data bundle"}
+	}
+
+	if input.role.KeyType == "rsa" && input.role.KeyBits < 2048 {
+		return nil, nil, errutil.UserError{Err: "RSA keys < 2048 bits are unsafe and not supported"}
+	}
+
+	if err := b.checkRestrictedCrypto(certutil.PrivateKeyType(input.role.KeyType)); err != nil {
+		return nil, nil, err
+	}
+	if caSign != nil {
+		if err := b.checkRestrictedCrypto(caSign.PrivateKeyType); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	data, warnings, err := generateCreationBundle(b, input, caSign, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if data.Params == nil {
+		return nil, nil, errutil.InternalError{Err: "nil parameters received from parameter bundle generation"}
+	}
+	return data, warnings, nil
+}
+
 func generateCreationBundle(b *backend, data *inputBundle, caSign *certutil.CAInfoBundle, csr *x509.CertificateRequest) (*certutil.CreationBundle, []string, error) {
 	// Read in names -- CN, DNS and email addresses
 	var cn string
@@ -1211,6 +1285,19 @@ func generateCreationBundle(b *backend, data *inputBundle, caSign *certutil.CAIn
 			ridSerialNumber = csr.Subject.SerialNumber
 		}
 
+		// The serial number set by the role takes precedence over the one
+		// of the CSR
+		if data.role.SubjectSerialNumber != "" {
+			if data.apiData.Get("serial_number").(string) != "" {
+				return nil, nil, errutil.UserError{Err: "serial_number cannot be set, as this role sets the subject serial number"}
+			}
+			serialNumber, err := populateSubjectTemplate(b, data, data.role.SubjectSerialNumber)
+			if err != nil {
+				return nil, nil, err
+			}
+			ridSerialNumber = serialNumber
+		}
+
 		if csr != nil && data.role.UseCSRSANs {
 			dnsNames = csr.DNSNames
 			emailAddresses = csr.EmailAddresses
@@ -1277,7 +1364,7 @@ func generateCreationBundle(b *backend, data *inputBundle, caSign *certutil.CAIn
 			}
 		}
 
-		if ridSerialNumber != "" {
+		if ridSerialNumber != "" && data.role.SubjectSerialNumber == "" {
 			badName := validateSerialNumber(data, ridSerialNumber)
 			if len(badName) != 0 {
 				return nil, nil, errutil.UserError{Err: fmt.Sprintf(
@@ -1420,14 +1507,26 @@ func generateCreationBundle(b *backend, data *inputBundle, caSign *certutil.CAIn
 		}
 	}
 
+	organization := data.role.Organization
+	ou := data.role.OU
+	if data.role.SubjectTemplate {
+		var err error
+		if organization, err = populateSubjectTemplates(b, data, organization); err != nil {
+			return nil, nil, err
+		}
+		if ou, err = populateSubjectTemplates(b, data, ou); err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// Most of these could also be RemoveDuplicateStable, or even
 	// leave duplicates in, but OU is the one most likely to be duplicated.
 	subject := pkix.Name{
 		CommonName:         cn,
 		SerialNumber:       ridSerialNumber,
 		Country:            strutil.RemoveDuplicatesStable(data.role.Country, false),
-		Organization:       strutil.RemoveDuplicatesStable(data.role.Organization, false),
-		OrganizationalUnit: strutil.RemoveDuplicatesStable(data.role.OU, false),
+		Organization:       strutil.RemoveDuplicatesStable(organization, false),
+		OrganizationalUnit: strutil.RemoveDuplicatesStable(ou, false),
 		Locality:           strutil.RemoveDuplicatesStable(data.role.Locality, false),
 		Province:           strutil.RemoveDuplicatesStable(data.role.Province, false),
 		StreetAddress:      strutil.RemoveDuplicatesStable(data.role.StreetAddress, false),
@@ -74,6 +74,9 @@ func importKeyFromBytes(sc *storageContext, keyValue string, keyName string) (*k
 	if privateKeyType == certutil.UnknownPrivateKey {
 		return nil, false, errors.New("unsupported private key type within pem bundle")
 	}
+	if err := sc.Backend.checkRestrictedCrypto(privateKeyType); err != nil {
+		return nil, false, err
+	}
 
 	key, existed, err := sc.importKey(keyValue, keyName, privateKeyType)
 	if err != nil {
@@ -0,0 +1,163 @@
+package pki
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+// certMetadataPath is the prefix where the metadata recorded with issued
+// certificates is stored, by serial number.
+const certMetadataPath = "cert-metadata/"
+
+// certMetadata is recorded with a certificate signed from a CSR carrying
+// requester-supplied key attestation.
+type certMetadata struct {
+	SerialNumber   string    `json:"serial_number"`
+	Role           string    `json:"role"`
+	KeyAttestation string    `json:"key_attestation"`
+	IssuedTime     time.Time `json:"issued_time"`
+}
+
+func pathListCertMetadata(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "cert-metadata/?$",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationSuffix: "cert-metadata",
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ListOperation: &framework.PathOperation{
+				Callback: b.pathListCertMetadata,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"keys": {
+								Type:        framework.TypeStringSlice,
+								Description: `A list of serial numbers`,
+								Required:    true,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathCertMetadataHelpSyn,
+		HelpDescription: pathCertMetadataHelpDesc,
+	}
+}
+
+func pathCertMetadata(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: `cert-metadata/(?P<serial>[0-9A-Fa-f-:]+)`,
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationSuffix: "cert-metadata",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"serial": {
+				Type: framework.TypeString,
+				Description: `Certificate serial number, in colon- or
+hyphen-separated octal`,
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathReadCertMetadata,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"serial_number": {
+								Type:        framework.TypeString,
+								Description: `Serial Number`,
+								Required:    true,
+							},
+							"role": {
+								Type:        framework.TypeString,
+								Description: `Role the certificate was signed with`,
+								Required:    true,
+							},
+							"key_attestation": {
+								Type:        framework.TypeString,
+								Description: `Key attestation supplied with the CSR`,
+								Required:    true,
+							},
+							"issued_time": {
+								Type:        framework.TypeTime,
+								Description: `Time the certificate was signed`,
+								Required:    true,
+							},
+						},
+					}},
+				},
+			},
+		},
+
+		HelpSynopsis:    pathCertMetadataHelpSyn,
+		HelpDescription: pathCertMetadataHelpDesc,
+	}
+}
+
+func (b *backend) pathListCertMetadata(ctx context.Context, req *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
+	entries, err := req.Storage.List(ctx, certMetadataPath)
+	if err != nil {
+		return nil, err
+	}
+	return logical.ListResponse(entries), nil
+}
+
+func (b *backend) pathReadCertMetadata(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	serial := normalizeSerial(data.Get("serial").(string))
+	entry, err := req.Storage.Get(ctx, certMetadataPath+serial)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var metadata certMetadata
+	if err := entry.DecodeJSON(&metadata); err != nil {
+		return nil, fmt.Errorf("error decoding metadata of certificate %q: %w", serial, err)
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"serial_number":   metadata.SerialNumber,
+			"role":            metadata.Role,
+			"key_attestation": metadata.KeyAttestation,
+			"issued_time":     metadata.IssuedTime,
+		},
+	}, nil
+}
+
+// storeCertMetadata records the metadata of a newly signed certificate.
+func storeCertMetadata(ctx context.Context, s logical.Storage, metadata *certMetadata) error {
+	entry, err := logical.StorageEntryJSON(certMetadataPath+normalizeSerial(metadata.SerialNumber), metadata)
+	if err != nil {
+		return err
+	}
+	return s.Put(ctx, entry)
+}
+
+const pathCertMetadataHelpSyn = `
+Fetch the metadata recorded with certificates signed from attested keys.
+`
+
+const pathCertMetadataHelpDesc = `
+This allows certificate metadata to be fetched by serial number, or listed.
+Metadata is recorded when a key_attestation is supplied along with a CSR to the
+sign endpoints, and is removed by tidy along with the certificate.
+`
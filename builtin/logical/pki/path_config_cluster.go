@@ -5,14 +5,35 @@ package pki
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
+	"math/big"
 	"net/http"
+	"time"
 
 	"github.com/asaskevich/govalidator"
 	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/errutil"
 	"github.com/openbao/openbao/sdk/v2/logical"
 )
 
+const (
+	serialNumberFormatRandom            = "random"
+	serialNumberFormatTimestampPrefixed = "timestamp_prefixed"
+
+	// minSerialNumberEntropyBits is the least entropy accepted in serial
+	// numbers, as required by the CA/Browser Forum Baseline Requirements.
+	minSerialNumberEntropyBits = 64
+
+	// maxSerialNumberEntropyBits keeps random serial numbers within the 20
+	// octets allowed by RFC 5280, Section 4.1.2.2, as positive integers.
+	maxSerialNumberEntropyBits = 159
+
+	// maxTimestampedSerialNumberEntropyBits leaves room for the issuance
+	// timestamp in timestamp prefixed serial numbers.
+	maxTimestampedSerialNumberEntropyBits = 112
+)
+
 func pathConfigCluster(b *backend) *framework.Path {
 	return &framework.Path{
 		Pattern: "config/cluster",
@@ -45,6 +66,19 @@ be an insecure transit mechanism (like HTTP without TLS).
 
 For example: http://cdn.example.com/pr1/pki`,
 			},
+			"serial_number_format": {
+				Type: framework.TypeString,
+				Description: `Format of the serial numbers of issued certificates;
+either "random" (the default), for serial numbers made of random bits only, or
+"timestamp_prefixed", for random bits prefixed with the issuance time in
+seconds since the Unix epoch.`,
+			},
+			"serial_number_entropy_bits": {
+				Type: framework.TypeInt,
+				Description: `Number of random bits in the serial numbers of
+issued certificates; at least 64, and at most 159 for random serial numbers or
+112 for timestamp prefixed ones. Defaults to the maximum.`,
+			},
 		},
 
 		Operations: map[logical.Operation]framework.OperationHandler{
@@ -81,6 +115,14 @@ be an insecure transit mechanism (like HTTP without TLS).
 
 For example: http://cdn.example.com/pr1/pki`,
 							},
+							"serial_number_format": {
+								Type:        framework.TypeString,
+								Description: `Format of the serial numbers of issued certificates`,
+							},
+							"serial_number_entropy_bits": {
+								Type:        framework.TypeInt,
+								Description: `Number of random bits in the serial numbers of issued certificates`,
+							},
 						},
 					}},
 				},
@@ -118,6 +160,14 @@ be an insecure transit mechanism (like HTTP without TLS).
 
 For example: http://cdn.example.com/pr1/pki`,
 							},
+							"serial_number_format": {
+								Type:        framework.TypeString,
+								Description: `Format of the serial numbers of issued certificates`,
+							},
+							"serial_number_entropy_bits": {
+								Type:        framework.TypeInt,
+								Description: `Number of random bits in the serial numbers of issued certificates`,
+							},
 						},
 					}},
 				},
@@ -137,10 +187,7 @@ func (b *backend) pathReadCluster(ctx context.Context, req *logical.Request, _ *
 	}
 
 	resp := &logical.Response{
-		Data: map[string]interface{}{
-			"path":     cfg.Path,
-			"aia_path": cfg.AIAPath,
-		},
+		Data: cfg.toResponseData(),
 	}
 
 	return resp, nil
@@ -170,20 +217,104 @@ func (b *backend) pathWriteCluster(ctx context.Context, req *logical.Request, da
 		}
 	}
 
+	if value, ok := data.GetOk("serial_number_format"); ok {
+		cfg.SerialNumberFormat = value.(string)
+	}
+
+	if value, ok := data.GetOk("serial_number_entropy_bits"); ok {
+		cfg.SerialNumberEntropyBits = value.(int)
+	}
+
+	if err := cfg.validateSerialNumberConfig(); err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+
 	if err := sc.writeClusterConfig(cfg); err != nil {
 		return nil, err
 	}
 
 	resp := &logical.Response{
-		Data: map[string]interface{}{
-			"path":     cfg.Path,
-			"aia_path": cfg.AIAPath,
-		},
+		Data: cfg.toResponseData(),
 	}
 
 	return resp, nil
 }
 
+func (c *clusterConfigEntry) toResponseData() map[string]interface{} {
+	format, bits := c.serialNumberConfig()
+	return map[string]interface{}{
+		"path":                       c.Path,
+		"aia_path":                   c.AIAPath,
+		"serial_number_format":       format,
+		"serial_number_entropy_bits": bits,
+	}
+}
+
+// serialNumberConfig returns the effective serial number format and entropy,
+// applying the defaults to unset values.
+func (c *clusterConfigEntry) serialNumberConfig() (string, int) {
+	format := c.SerialNumberFormat
+	if format == "" {
+		format = serialNumberFormatRandom
+	}
+
+	bits := c.SerialNumberEntropyBits
+	if bits == 0 {
+		bits = maxSerialNumberEntropyBits
+		if format == serialNumberFormatTimestampPrefixed {
+			bits = maxTimestampedSerialNumberEntropyBits
+		}
+	}
+
+	return format, bits
+}
+
+func (c *clusterConfigEntry) validateSerialNumberConfig() error {
+	maxBits := maxSerialNumberEntropyBits
+	switch c.SerialNumberFormat {
+	case "", serialNumberFormatRandom:
+	case serialNumberFormatTimestampPrefixed:
+		maxBits = maxTimestampedSerialNumberEntropyBits
+	default:
+		return fmt.Errorf("invalid serial_number_format %q: must be %q or %q", c.SerialNumberFormat, serialNumberFormatRandom, serialNumberFormatTimestampPrefixed)
+	}
+
+	if c.SerialNumberEntropyBits != 0 && (c.SerialNumberEntropyBits < minSerialNumberEntropyBits || c.SerialNumberEntropyBits > maxBits) {
+		return fmt.Errorf("invalid serial_number_entropy_bits %d: must be between %d and %d", c.SerialNumberEntropyBits, minSerialNumberEntropyBits, maxBits)
+	}
+
+	return nil
+}
+
+// generateSerialNumber generates a serial number for a new certificate
+// according to the cluster configuration. A nil serial number is returned
+// when the defaults are in use, letting certutil generate one.
+func (sc *storageContext) generateSerialNumber() (*big.Int, error) {
+	cfg, err := sc.getClusterConfig()
+	if err != nil {
+		return nil, errutil.InternalError{Err: fmt.Sprintf("unable to fetch cluster configuration: %v", err)}
+	}
+	if cfg.SerialNumberFormat == "" && cfg.SerialNumberEntropyBits == 0 {
+		return nil, nil
+	}
+
+	format, bits := cfg.serialNumberConfig()
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), uint(bits)))
+	if err != nil {
+		return nil, errutil.InternalError{Err: fmt.Sprintf("error generating serial number: %v", err)}
+	}
+
+	if format == serialNumberFormatTimestampPrefixed {
+		// Align the timestamp on a byte boundary so that it reads as the
+		// leading octets of the serial number.
+		shift := uint((bits + 7) / 8 * 8)
+		prefix := new(big.Int).Lsh(big.NewInt(time.Now().Unix()), shift)
+		serial.Or(serial, prefix)
+	}
+
+	return serial, nil
+}
+
 const pathConfigClusterHelpSyn = `
 Set cluster-local configuration, including address to this PR cluster.
 `
@@ -195,4 +326,7 @@ templated AIA URLs with /config/urls and /issuer/:issuer_ref, setting the
 reference to the cluster's URI.
 
 Only one address can be specified for any given cluster.
+
+The format and entropy of the serial numbers of issued certificates can also
+be set here.
 `
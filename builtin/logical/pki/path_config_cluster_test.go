@@ -0,0 +1,79 @@
+package pki
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPki_ConfigClusterSerialNumbers(t *testing.T) {
+	t.Parallel()
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBRead(b, s, "config/cluster")
+	requireSuccessNonNilResponse(t, resp, err)
+	require.Equal(t, serialNumberFormatRandom, resp.Data["serial_number_format"])
+	require.Equal(t, maxSerialNumberEntropyBits, resp.Data["serial_number_entropy_bits"])
+
+	// Invalid formats and entropy are refused.
+	for _, data := range []map[string]interface{}{
+		{"serial_number_format": "sequential"},
+		{"serial_number_entropy_bits": 32},
+		{"serial_number_entropy_bits": 160},
+		{"serial_number_format": serialNumberFormatTimestampPrefixed, "serial_number_entropy_bits": 128},
+	} {
+		_, err = CBWrite(b, s, "config/cluster", data)
+		require.Error(t, err, "expected %v to be refused", data)
+	}
+
+	resp, err = CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root.example.com",
+		"key_type":    "ec",
+		"ttl":         "72h",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	resp, err = CBWrite(b, s, "roles/example", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"key_type":         "ec",
+		"ttl":              "1h",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+
+	issueSerial := func() *big.Int {
+		resp, err := CBWrite(b, s, "issue/example", map[string]interface{}{
+			"common_name": "host.example.com",
+		})
+		requireSuccessNonNilResponse(t, resp, err)
+		return parseCert(t, resp.Data["certificate"].(string)).SerialNumber
+	}
+
+	// Random serial numbers are limited to the configured entropy.
+	resp, err = CBWrite(b, s, "config/cluster", map[string]interface{}{
+		"serial_number_entropy_bits": 64,
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	require.Equal(t, serialNumberFormatRandom, resp.Data["serial_number_format"])
+	require.Equal(t, 64, resp.Data["serial_number_entropy_bits"])
+	for i := 0; i < 5; i++ {
+		require.LessOrEqual(t, issueSerial().BitLen(), 64)
+	}
+
+	// Timestamp prefixed serial numbers carry the issuance time above the
+	// random bits.
+	resp, err = CBWrite(b, s, "config/cluster", map[string]interface{}{
+		"serial_number_format":       serialNumberFormatTimestampPrefixed,
+		"serial_number_entropy_bits": 0,
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	require.Equal(t, maxTimestampedSerialNumberEntropyBits, resp.Data["serial_number_entropy_bits"])
+
+	before := time.Now().Unix()
+	serial := issueSerial()
+	after := time.Now().Unix()
+	timestamp := new(big.Int).Rsh(serial, maxTimestampedSerialNumberEntropyBits).Int64()
+	require.GreaterOrEqual(t, timestamp, before)
+	require.LessOrEqual(t, timestamp, after)
+}
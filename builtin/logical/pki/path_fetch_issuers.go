@@ -221,11 +221,12 @@ for the OCSP servers attribute. See also RFC 5280 Section 4.2.2.1.`,
 		Type: framework.TypeBool,
 		Description: `Whether or not to enabling templating of the
 above AIA fields. When templating is enabled the special values '{{issuer_id}}',
-'{{cluster_path}}', '{{cluster_aia_path}}' are available, but the addresses are
-not checked for URL validity until issuance time. Using '{{cluster_path}}'
-requires /config/cluster's 'path' member to be set on all PR Secondary clusters
-and using '{{cluster_aia_path}}' requires /config/cluster's 'aia_path' member
-to be set on all PR secondary clusters.`,
+'{{cluster_path}}', '{{cluster_aia_path}}', '{{cluster_address}}', and
+'{{mount_path}}' are available, but the addresses are not checked for URL
+validity until issuance time. Using '{{cluster_path}}', '{{cluster_address}}'
+or '{{mount_path}}' requires /config/cluster's 'path' member to be set on all
+PR Secondary clusters and using '{{cluster_aia_path}}' requires
+/config/cluster's 'aia_path' member to be set on all PR secondary clusters.`,
 		Default: false,
 	}
 
@@ -6,7 +6,6 @@ package pki
 import (
 	"bytes"
 	"context"
-	"crypto/rand"
 	"encoding/base64"
 	"encoding/pem"
 	"fmt"
@@ -53,6 +52,7 @@ func buildPathIssue(b *backend, pattern string, displayAttrs *framework.DisplayA
 		Operations: map[logical.Operation]framework.OperationHandler{
 			logical.UpdateOperation: &framework.PathOperation{
 				Callback: b.metricsWrap("issue", roleRequired, b.pathIssue),
+				DryRun:   true,
 				Responses: map[int][]framework.Response{
 					http.StatusOK: {{
 						Description: "OK",
@@ -220,6 +220,13 @@ func buildPathSign(b *backend, pattern string, displayAttrs *framework.DisplayAt
 		Description: `PEM-format CSR to be signed.`,
 	}
 
+	ret.Fields["key_attestation"] = &framework.FieldSchema{
+		Type:    framework.TypeString,
+		Default: "",
+		Description: `Attestation of the key of the CSR, such as a hardware
+key attestation statement, recorded with the metadata of the certificate.`,
+	}
+
 	return ret
 }
 
@@ -331,6 +338,10 @@ See the API documentation for more information about required parameters.
 // pathIssue issues a certificate and private key from given parameters,
 // subject to role restrictions
 func (b *backend) pathIssue(ctx context.Context, req *logical.Request, data *framework.FieldData, role *roleEntry) (*logical.Response, error) {
+	if role.SignOnly {
+		return logical.ErrorResponse("role %q is sign-only: private keys cannot be generated with it, submit a CSR to the sign endpoint instead", role.Name), nil
+	}
+
 	keyTypeRaw, keyTypePresent := data.GetOk("key_type")
 	keyBitsRaw, keyBitsPresent := data.GetOk("key_bits")
 
@@ -367,6 +378,46 @@ func (b *backend) pathIssue(ctx context.Context, req *logical.Request, data *fra
 	return resp, err
 }
 
+// pathIssueDryRun validates a request to issue a certificate against its role
+// and returns the details of the certificate it would issue, without
+// generating a key nor signing anything.
+func (b *backend) pathIssueDryRun(input *inputBundle, issuerName string, signingBundle *certutil.CAInfoBundle) (*logical.Response, error) {
+	creation, warnings, err := validateCertInput(b, input, signingBundle)
+	if err != nil {
+		switch err.(type) {
+		case errutil.UserError:
+			return logical.ErrorResponse(err.Error()), nil
+		default:
+			return nil, err
+		}
+	}
+	params := creation.Params
+
+	ipSANs := make([]string, 0, len(params.IPAddresses))
+	for _, ip := range params.IPAddresses {
+		ipSANs = append(ipSANs, ip.String())
+	}
+	uriSANs := make([]string, 0, len(params.URIs))
+	for _, uri := range params.URIs {
+		uriSANs = append(uriSANs, uri.String())
+	}
+
+	resp := &logical.Response{
+		Data: map[string]interface{}{
+			"common_name": params.Subject.CommonName,
+			"alt_names":   append(append([]string{}, params.DNSNames...), params.EmailAddresses...),
+			"ip_sans":     ipSANs,
+			"uri_sans":    uriSANs,
+			"key_type":    params.KeyType,
+			"key_bits":    params.KeyBits,
+			"expiration":  params.NotAfter.Unix(),
+			"issuer_ref":  issuerName,
+			"no_store":    input.role.NoStore,
+		},
+	}
+	return addWarnings(resp, warnings), nil
+}
+
 // pathSign issues a certificate from a submitted CSR, subject to role
 // restrictions
 func (b *backend) pathSign(ctx context.Context, req *logical.Request, data *framework.FieldData, role *roleEntry) (*logical.Response, error) {
@@ -438,13 +489,19 @@ func (b *backend) pathIssueSignCert(ctx context.Context, req *logical.Request, d
 		apiData: data,
 		role:    role,
 	}
+
+	// Only the issue paths support dry runs
+	if req.DryRun {
+		return b.pathIssueDryRun(input, issuerName, signingBundle)
+	}
+
 	var parsedBundle *certutil.ParsedCertBundle
 	var err error
 	var warnings []string
 	if useCSR {
-		parsedBundle, warnings, err = signCert(b, input, signingBundle, false, useCSRValues)
+		parsedBundle, warnings, err = signCert(sc, input, signingBundle, false, useCSRValues)
 	} else {
-		parsedBundle, warnings, err = generateCert(sc, input, signingBundle, false, rand.Reader)
+		parsedBundle, warnings, err = generateCert(sc, input, signingBundle, false, b.Backend.GetRandomReader())
 	}
 	if err != nil {
 		switch err.(type) {
@@ -552,6 +609,22 @@ func (b *backend) pathIssueSignCert(ctx context.Context, req *logical.Request, d
 		b.ifCountEnabledIncrementTotalCertificatesCount(certsCounted, key)
 	}
 
+	if keyAttestation, ok := data.GetOk("key_attestation"); ok && keyAttestation.(string) != "" {
+		if role.NoStore {
+			resp.AddWarning("the key_attestation field was provided but the role is set with \"no_store\" set to true; it was not recorded")
+		} else {
+			err = storeCertMetadata(ctx, req.Storage, &certMetadata{
+				SerialNumber:   cb.SerialNumber,
+				Role:           role.Name,
+				KeyAttestation: keyAttestation.(string),
+				IssuedTime:     time.Now(),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("unable to store certificate metadata: %w", err)
+			}
+		}
+	}
+
 	if useCSR {
 		if role.UseCSRCommonName && data.Get("common_name").(string) != "" {
 			resp.AddWarning("the common_name field was provided but the role is set with \"use_csr_common_name\" set to true")
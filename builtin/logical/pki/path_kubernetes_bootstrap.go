@@ -0,0 +1,392 @@
+package pki
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/certutil"
+	"github.com/openbao/openbao/sdk/v2/helper/errutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const (
+	// kubernetesBootstrapPrefix prefixes the names of the issuer, key and
+	// role provisioned for a Kubernetes cluster.
+	kubernetesBootstrapPrefix = "kubernetes-"
+
+	defaultKubernetesIntermediateTTL = 365 * 24 * time.Hour
+)
+
+func pathKubernetesBootstrap(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "kubernetes/bootstrap/" + framework.GenericNameRegex("cluster"),
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixPKI,
+			OperationVerb:   "bootstrap",
+			OperationSuffix: "kubernetes-cluster",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"cluster": {
+				Type:        framework.TypeString,
+				Description: `Name of the Kubernetes cluster to provision an intermediate CA for.`,
+				Required:    true,
+			},
+			issuerRefParam: {
+				Type:        framework.TypeString,
+				Default:     defaultRef,
+				Description: `Reference to the issuer signing the intermediate CA of the cluster; defaults to the default issuer.`,
+			},
+			"allowed_domains": {
+				Type: framework.TypeCommaStringSlice,
+				Description: `DNS domains the certificates of the cluster are issued for. They are
+encoded as permitted DNS name constraints of the intermediate CA, and set
+as the allowed domains of the role. They must be permitted by the name
+constraints of the parent issuer, if any.`,
+				Required: true,
+			},
+			"common_name": {
+				Type:        framework.TypeString,
+				Description: `Common name of the intermediate CA; defaults to "<cluster> Kubernetes Intermediate CA".`,
+			},
+			"ttl": {
+				Type: framework.TypeDurationSecond,
+				Description: `Lifetime of the intermediate CA; defaults to one year. It is truncated
+to the expiration of the parent issuer.`,
+			},
+			keyTypeParam: {
+				Type:          framework.TypeString,
+				Default:       "ec",
+				Description:   `The type of key of the intermediate CA; defaults to "ec".`,
+				AllowedValues: []interface{}{"rsa", "ec", "ed25519"},
+			},
+			keyBitsParam: {
+				Type:        framework.TypeInt,
+				Default:     0,
+				Description: `The number of bits of the key of the intermediate CA; defaults to the universal default of the key type.`,
+			},
+			"max_ttl": {
+				Type:        framework.TypeDurationSecond,
+				Description: `The maximum lifetime of the certificates signed through the role; defaults to the mount's maximum TTL.`,
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.UpdateOperation: &framework.PathOperation{
+				Callback: b.pathKubernetesBootstrap,
+				Responses: map[int][]framework.Response{
+					http.StatusOK: {{
+						Description: "OK",
+						Fields: map[string]*framework.FieldSchema{
+							"issuer_id": {
+								Type:        framework.TypeString,
+								Description: `Issuer ID of the intermediate CA`,
+								Required:    true,
+							},
+							"issuer_name": {
+								Type:        framework.TypeString,
+								Description: `Issuer name of the intermediate CA`,
+								Required:    true,
+							},
+							"key_id": {
+								Type:        framework.TypeString,
+								Description: `Key ID of the intermediate CA`,
+								Required:    true,
+							},
+							"role": {
+								Type:        framework.TypeString,
+								Description: `Role signing the certificates of the cluster`,
+								Required:    true,
+							},
+							"sign_path": {
+								Type:        framework.TypeString,
+								Description: `Path the certificates of the cluster are signed on, for the cert-manager Vault issuer`,
+								Required:    true,
+							},
+							"certificate": {
+								Type:        framework.TypeString,
+								Description: `Certificate of the intermediate CA`,
+								Required:    true,
+							},
+							"issuing_ca": {
+								Type:        framework.TypeString,
+								Description: `Issuing CA`,
+								Required:    true,
+							},
+							"ca_chain": {
+								Type:        framework.TypeStringSlice,
+								Description: `CA Chain`,
+								Required:    true,
+							},
+							"ca_bundle": {
+								Type:        framework.TypeString,
+								Description: `PEM bundle of the CA chain, for the trust stores of the cluster`,
+								Required:    true,
+							},
+							"serial_number": {
+								Type:        framework.TypeString,
+								Description: `Serial Number`,
+								Required:    true,
+							},
+							"expiration": {
+								Type:        framework.TypeInt64,
+								Description: `Expiration Time`,
+								Required:    true,
+							},
+						},
+					}},
+				},
+				// Read more about why these flags are set in backend.go
+				ForwardPerformanceStandby:   true,
+				ForwardPerformanceSecondary: true,
+			},
+		},
+
+		HelpSynopsis:    pathKubernetesBootstrapHelpSyn,
+		HelpDescription: pathKubernetesBootstrapHelpDesc,
+	}
+}
+
+func (b *backend) pathKubernetesBootstrap(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	// Since we're planning on updating issuers here, grab the lock so we've
+	// got a consistent view.
+	b.issuersLock.Lock()
+	defer b.issuersLock.Unlock()
+
+	if b.useLegacyBundleCaStorage() {
+		return logical.ErrorResponse("Can not bootstrap a cluster until migration has completed"), nil
+	}
+
+	cluster := data.Get("cluster").(string)
+	name := kubernetesBootstrapPrefix + cluster
+	if !nameMatcher.MatchString(name) {
+		return logical.ErrorResponse("cluster name contained invalid characters"), nil
+	}
+
+	domains := data.Get("allowed_domains").([]string)
+	if len(domains) == 0 {
+		return logical.ErrorResponse("at least one allowed domain is required"), nil
+	}
+	for i, domain := range domains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain == "" || strings.Contains(domain, "*") {
+			return logical.ErrorResponse("allowed domain %q is not a DNS domain", domain), nil
+		}
+		domains[i] = domain
+	}
+
+	sc := b.makeStorageContext(ctx, req.Storage)
+
+	// The issuer, key and role are all named after the cluster; refuse to
+	// provision the cluster again rather than replacing them.
+	if issuerId, err := sc.resolveIssuerReference(name); err == nil {
+		return logical.ErrorResponse("cluster %q is already provisioned with issuer %v", cluster, issuerId), nil
+	} else if issuerId != IssuerRefNotFound {
+		return nil, err
+	}
+	if keyId, err := sc.resolveKeyReference(name); err == nil {
+		return logical.ErrorResponse("cluster %q is already provisioned with key %v", cluster, keyId), nil
+	} else if keyId != KeyRefNotFound {
+		return nil, err
+	}
+	role, err := b.getRole(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if role != nil {
+		return logical.ErrorResponse("cluster %q is already provisioned with role %q", cluster, name), nil
+	}
+
+	signingBundle, err := sc.fetchCAInfo(getIssuerRef(data), IssuanceUsage)
+	if err != nil {
+		switch err.(type) {
+		case errutil.UserError:
+			return logical.ErrorResponse("could not fetch the parent CA certificate: %s", err), nil
+		default:
+			return nil, fmt.Errorf("error fetching parent CA certificate: %w", err)
+		}
+	}
+
+	// The intermediate inherits the constraints of its parent: its domains
+	// must be permitted by the parent, and it may not outlive it.
+	parent := signingBundle.Certificate
+	for _, domain := range domains {
+		if len(parent.PermittedDNSDomains) > 0 && !dnsNameWithinConstraints(domain, parent.PermittedDNSDomains) {
+			return logical.ErrorResponse("allowed domain %q is not permitted by the parent issuer", domain), nil
+		}
+		if dnsNameWithinConstraints(domain, parent.ExcludedDNSDomains) {
+			return logical.ErrorResponse("allowed domain %q is excluded by the parent issuer", domain), nil
+		}
+	}
+
+	ttl := time.Duration(data.Get("ttl").(int)) * time.Second
+	if ttl == 0 {
+		ttl = defaultKubernetesIntermediateTTL
+	}
+	notAfter := time.Now().Add(ttl)
+	if notAfter.After(parent.NotAfter) {
+		notAfter = parent.NotAfter
+	}
+
+	commonName := data.Get("common_name").(string)
+	if commonName == "" {
+		commonName = cluster + " Kubernetes Intermediate CA"
+	}
+
+	keyType := data.Get(keyTypeParam).(string)
+	keyBits, _, err := certutil.ValidateDefaultOrValueKeyTypeSignatureLength(keyType, data.Get(keyBitsParam).(int), 0)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+
+	csrBundle, err := certutil.CreateCSRWithRandomSource(&certutil.CreationBundle{
+		Params: &certutil.CreationParameters{
+			Subject: pkix.Name{CommonName: commonName},
+			KeyType: keyType,
+			KeyBits: keyBits,
+		},
+	}, true, b.Backend.GetRandomReader())
+	if err != nil {
+		return nil, fmt.Errorf("error generating intermediate key: %w", err)
+	}
+
+	parsedBundle, err := certutil.SignCertificateWithRandomSource(&certutil.CreationBundle{
+		Params: &certutil.CreationParameters{
+			Subject:             pkix.Name{CommonName: commonName},
+			NotAfter:            notAfter,
+			IsCA:                true,
+			MaxPathLength:       0,
+			PermittedDNSDomains: domains,
+			URLs:                signingBundle.URLs,
+		},
+		SigningBundle: signingBundle,
+		CSR:           csrBundle.CSR,
+	}, b.Backend.GetRandomReader())
+	if err != nil {
+		switch err.(type) {
+		case errutil.UserError:
+			return logical.ErrorResponse(err.Error()), nil
+		default:
+			return nil, fmt.Errorf("error signing intermediate: %w", err)
+		}
+	}
+
+	csrb, err := csrBundle.ToCSRBundle()
+	if err != nil {
+		return nil, fmt.Errorf("error converting raw CSR bundle to CSR bundle: %w", err)
+	}
+	cb, err := parsedBundle.ToCertBundle()
+	if err != nil {
+		return nil, fmt.Errorf("error converting raw cert bundle to cert bundle: %w", err)
+	}
+
+	myKey, _, err := sc.importKey(csrb.PrivateKey, name, csrb.PrivateKeyType)
+	if err != nil {
+		return nil, err
+	}
+	myIssuer, _, err := sc.importIssuer(cb.Certificate, name)
+	if err != nil {
+		return nil, err
+	}
+
+	key := "certs/" + normalizeSerial(cb.SerialNumber)
+	certsCounted := b.certsCounted.Load()
+	err = req.Storage.Put(ctx, &logical.StorageEntry{
+		Key:   key,
+		Value: parsedBundle.CertificateBytes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to store certificate locally: %w", err)
+	}
+	b.ifCountEnabledIncrementTotalCertificatesCount(certsCounted, key)
+
+	// The role signs the CSRs of cert-manager, whatever their key type, for
+	// the domains of the cluster only.
+	roleResp, err := b.pathRoleCreate(ctx, req, &framework.FieldData{
+		Raw: map[string]interface{}{
+			"name":                        name,
+			issuerRefParam:                string(myIssuer.ID),
+			"allowed_domains":             domains,
+			"allow_bare_domains":          true,
+			"allow_subdomains":            true,
+			"allow_localhost":             false,
+			"allow_ip_sans":               false,
+			"allow_wildcard_certificates": true,
+			"key_type":                    "any",
+			"max_ttl":                     data.Get("max_ttl").(int),
+		},
+		Schema: pathRoles(b).Fields,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if roleResp.IsError() {
+		return roleResp, nil
+	}
+
+	caChain := append([]string{cb.Certificate}, cb.CAChain...)
+
+	resp := &logical.Response{
+		Data: map[string]interface{}{
+			"issuer_id":     myIssuer.ID,
+			"issuer_name":   myIssuer.Name,
+			"key_id":        myKey.ID,
+			"role":          name,
+			"sign_path":     req.MountPoint + "sign/" + name,
+			"certificate":   cb.Certificate,
+			"issuing_ca":    cb.IssuingCA,
+			"ca_chain":      caChain,
+			"ca_bundle":     strings.Join(caChain, "\n") + "\n",
+			"serial_number": cb.SerialNumber,
+			"expiration":    parsedBundle.Certificate.NotAfter.Unix(),
+		},
+	}
+
+	if notAfter.Equal(parent.NotAfter) {
+		resp.AddWarning("The lifetime of the intermediate CA was truncated to the expiration of the parent issuer.")
+	}
+	for _, warning := range roleResp.Warnings {
+		resp.AddWarning(warning)
+	}
+
+	return resp, nil
+}
+
+// dnsNameWithinConstraints returns whether the given DNS name is within one of
+// the given DNS name constraints, following the semantics of RFC 5280: a
+// constraint with a leading period only matches subdomains.
+func dnsNameWithinConstraints(name string, constraints []string) bool {
+	for _, constraint := range constraints {
+		constraint = strings.ToLower(constraint)
+		if strings.HasPrefix(constraint, ".") {
+			if strings.HasSuffix(name, constraint) {
+				return true
+			}
+			continue
+		}
+		if name == constraint || strings.HasSuffix(name, "."+constraint) {
+			return true
+		}
+	}
+	return false
+}
+
+const pathKubernetesBootstrapHelpSyn = `
+Provision an intermediate CA and a role for a Kubernetes cluster.
+`
+
+const pathKubernetesBootstrapHelpDesc = `
+This endpoint generates an intermediate CA for the given Kubernetes cluster,
+signed by the given issuer and constrained to the allowed domains of the
+cluster, along with a role signing certificates for these domains with it.
+The response holds the path of the role and the CA chain, as required to
+configure a cert-manager issuer for the cluster.
+
+See the API documentation for more information.
+`
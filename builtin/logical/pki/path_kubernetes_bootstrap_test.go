@@ -0,0 +1,73 @@
+package pki
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPki_KubernetesBootstrap(t *testing.T) {
+	t.Parallel()
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name":           "Root CA",
+		"key_type":              "ec",
+		"ttl":                   "8760h",
+		"permitted_dns_domains": "example.com",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	root := parseCert(t, resp.Data["certificate"].(string))
+
+	// Domains outside of the name constraints of the parent are refused.
+	_, err = CBWrite(b, s, "kubernetes/bootstrap/prod", map[string]interface{}{
+		"allowed_domains": "prod.example.com,example.org",
+	})
+	require.Error(t, err)
+
+	resp, err = CBWrite(b, s, "kubernetes/bootstrap/prod", map[string]interface{}{
+		"allowed_domains": "prod.example.com",
+		"ttl":             "87600h",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	requireFieldsSetInResp(t, resp, "issuer_id", "key_id", "certificate", "ca_chain", "ca_bundle")
+	require.Equal(t, "kubernetes-prod", resp.Data["issuer_name"])
+	require.Equal(t, "kubernetes-prod", resp.Data["role"])
+	require.Equal(t, "pki/sign/kubernetes-prod", resp.Data["sign_path"])
+	require.NotEmpty(t, resp.Warnings, "expected truncation warning")
+
+	intermediate := parseCert(t, resp.Data["certificate"].(string))
+	requireSignedBy(t, intermediate, root)
+	require.True(t, intermediate.IsCA)
+	require.True(t, intermediate.MaxPathLenZero)
+	require.Equal(t, []string{"prod.example.com"}, intermediate.PermittedDNSDomains)
+	require.Equal(t, root.NotAfter, intermediate.NotAfter)
+	require.Len(t, resp.Data["ca_chain"], 2)
+
+	// The role signs certificates within the domains of the cluster only,
+	// with the intermediate.
+	_, _, csr := generateCSR(t, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: "app.prod.example.com"},
+	}, "rsa", 2048)
+	resp, err = CBWrite(b, s, "sign/kubernetes-prod", map[string]interface{}{
+		"csr": csr,
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	requireSignedBy(t, parseCert(t, resp.Data["certificate"].(string)), intermediate)
+
+	_, _, csr = generateCSR(t, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: "app.staging.example.com"},
+	}, "rsa", 2048)
+	_, err = CBWrite(b, s, "sign/kubernetes-prod", map[string]interface{}{
+		"csr": csr,
+	})
+	require.Error(t, err)
+
+	// A cluster is only provisioned once.
+	_, err = CBWrite(b, s, "kubernetes/bootstrap/prod", map[string]interface{}{
+		"allowed_domains": "prod.example.com",
+	})
+	require.Error(t, err)
+}
@@ -128,6 +128,9 @@ func (b *backend) pathGenerateKeyHandler(ctx context.Context, req *logical.Reque
 		if err != nil {
 			return logical.ErrorResponse("Validation for key_type, key_bits failed: %s", err.Error()), nil
 		}
+		if err := b.checkRestrictedCrypto(certutil.PrivateKeyType(keyType)); err != nil {
+			return logical.ErrorResponse(err.Error()), nil
+		}
 
 		// Internal key generation, stored in storage
 		keyBundle, err = certutil.CreateKeyBundle(keyType, keyBits, b.GetRandomReader())
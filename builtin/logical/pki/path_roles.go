@@ -313,6 +313,20 @@ this value in certificates issued by this role.`,
 this value in certificates issued by this role.`,
 		},
 
+		"subject_serial_number": {
+			Type: framework.TypeString,
+			Description: `If set, the Subject's SerialNumber will be set to
+this value in certificates issued by this role.`,
+		},
+
+		"subject_template": {
+			Type:     framework.TypeBool,
+			Required: true,
+			Description: `If set, ou, organization and subject_serial_number
+can be specified using identity templates, populated from the entity of the
+requester.`,
+		},
+
 		"country": {
 			Type: framework.TypeCommaStringSlice,
 			Description: `If set, Country will be set to
@@ -367,6 +381,14 @@ non-sensitive, or extremely short-lived. This option implies a value of "false"
 for "generate_lease".`,
 		},
 
+		"sign_only": {
+			Type: framework.TypeBool,
+			Description: `
+If set, certificates can only be signed from CSRs with this role, and the issue
+endpoints refuse to generate private keys, so that they never transit the
+server. Defaults to "false".`,
+		},
+
 		"require_cn": {
 			Type:        framework.TypeBool,
 			Description: `If set to false, makes the 'common_name' field optional while generating a certificate.`,
@@ -712,6 +734,25 @@ this value in certificates issued by this role.`,
 this value in certificates issued by this role.`,
 			},
 
+			"subject_serial_number": {
+				Type: framework.TypeString,
+				Description: `If set, the Subject's SerialNumber will be set to
+this value in certificates issued by this role. Requests cannot then set
+serial_number.`,
+				DisplayAttrs: &framework.DisplayAttributes{
+					Name: "Subject Serial Number",
+				},
+			},
+
+			"subject_template": {
+				Type: framework.TypeBool,
+				Description: `If set, ou, organization and subject_serial_number
+can be specified using identity templates, such as
+{{identity.entity.metadata.team}}, populated from the entity of the requester.
+Issuance fails when a template cannot be populated.`,
+				Default: false,
+			},
+
 			"country": {
 				Type: framework.TypeCommaStringSlice,
 				Description: `If set, Country will be set to
@@ -772,6 +813,15 @@ non-sensitive, or extremely short-lived. This option implies a value of "false"
 for "generate_lease".`,
 			},
 
+			"sign_only": {
+				Type: framework.TypeBool,
+				Description: `
+If set, certificates can only be signed from CSRs with this role, and the issue
+endpoints refuse to generate private keys, so that they never transit the
+server.`,
+				Default: false,
+			},
+
 			"require_cn": {
 				Type:        framework.TypeBool,
 				Default:     true,
@@ -1105,6 +1155,8 @@ func (b *backend) pathRoleCreate(ctx context.Context, req *logical.Request, data
 		ExtKeyUsageOIDs:               data.Get("ext_key_usage_oids").([]string),
 		OU:                            data.Get("ou").([]string),
 		Organization:                  data.Get("organization").([]string),
+		SubjectSerialNumber:           data.Get("subject_serial_number").(string),
+		SubjectTemplate:               data.Get("subject_template").(bool),
 		Country:                       data.Get("country").([]string),
 		Locality:                      data.Get("locality").([]string),
 		Province:                      data.Get("province").([]string),
@@ -1112,6 +1164,7 @@ func (b *backend) pathRoleCreate(ctx context.Context, req *logical.Request, data
 		PostalCode:                    data.Get("postal_code").([]string),
 		GenerateLease:                 new(bool),
 		NoStore:                       data.Get("no_store").(bool),
+		SignOnly:                      data.Get("sign_only").(bool),
 		RequireCN:                     data.Get("require_cn").(bool),
 		CNValidations:                 data.Get("cn_validations").([]string),
 		AllowedSerialNumbers:          data.Get("allowed_serial_numbers").([]string),
@@ -1195,6 +1248,9 @@ func validateRole(b *backend, entry *roleEntry, ctx context.Context, s logical.S
 	if entry.KeyBits, entry.SignatureBits, err = certutil.ValidateDefaultOrValueKeyTypeSignatureLength(entry.KeyType, entry.KeyBits, entry.SignatureBits); err != nil {
 		return logical.ErrorResponse(err.Error()), nil
 	}
+	if err := b.checkRestrictedCrypto(certutil.PrivateKeyType(entry.KeyType)); err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
 
 	if len(entry.ExtKeyUsageOIDs) > 0 {
 		for _, oidstr := range entry.ExtKeyUsageOIDs {
@@ -1212,6 +1268,14 @@ func validateRole(b *backend, entry *roleEntry, ctx context.Context, s logical.S
 		}
 	}
 
+	if entry.SubjectTemplate {
+		for _, tpl := range entry.subjectTemplates() {
+			if _, err := framework.ValidateIdentityTemplate(tpl); err != nil {
+				return logical.ErrorResponse(fmt.Sprintf("invalid subject template %q: %v", tpl, err)), nil
+			}
+		}
+	}
+
 	// Ensure issuers ref is set to a non-empty value. Note that we never
 	// resolve the reference (to an issuerId) at role creation time; instead,
 	// resolve it at use time. This allows values such as `default` or other
@@ -1305,6 +1369,8 @@ func (b *backend) pathRolePatch(ctx context.Context, req *logical.Request, data
 		ExtKeyUsageOIDs:               getWithExplicitDefault(data, "ext_key_usage_oids", oldEntry.ExtKeyUsageOIDs).([]string),
 		OU:                            getWithExplicitDefault(data, "ou", oldEntry.OU).([]string),
 		Organization:                  getWithExplicitDefault(data, "organization", oldEntry.Organization).([]string),
+		SubjectSerialNumber:           getWithExplicitDefault(data, "subject_serial_number", oldEntry.SubjectSerialNumber).(string),
+		SubjectTemplate:               getWithExplicitDefault(data, "subject_template", oldEntry.SubjectTemplate).(bool),
 		Country:                       getWithExplicitDefault(data, "country", oldEntry.Country).([]string),
 		Locality:                      getWithExplicitDefault(data, "locality", oldEntry.Locality).([]string),
 		Province:                      getWithExplicitDefault(data, "province", oldEntry.Province).([]string),
@@ -1312,6 +1378,7 @@ func (b *backend) pathRolePatch(ctx context.Context, req *logical.Request, data
 		PostalCode:                    getWithExplicitDefault(data, "postal_code", oldEntry.PostalCode).([]string),
 		GenerateLease:                 new(bool),
 		NoStore:                       getWithExplicitDefault(data, "no_store", oldEntry.NoStore).(bool),
+		SignOnly:                      getWithExplicitDefault(data, "sign_only", oldEntry.SignOnly).(bool),
 		RequireCN:                     getWithExplicitDefault(data, "require_cn", oldEntry.RequireCN).(bool),
 		CNValidations:                 getWithExplicitDefault(data, "cn_validations", oldEntry.CNValidations).([]string),
 		AllowedSerialNumbers:          getWithExplicitDefault(data, "allowed_serial_numbers", oldEntry.AllowedSerialNumbers).([]string),
@@ -1511,6 +1578,8 @@ type roleEntry struct {
 	OU                            []string      `json:"ou_list"`
 	OrganizationOld               string        `json:"organization,omitempty"`
 	Organization                  []string      `json:"organization_list"`
+	SubjectSerialNumber           string        `json:"subject_serial_number"`
+	SubjectTemplate               bool          `json:"subject_template"`
 	Country                       []string      `json:"country"`
 	Locality                      []string      `json:"locality"`
 	Province                      []string      `json:"province"`
@@ -1518,6 +1587,7 @@ type roleEntry struct {
 	PostalCode                    []string      `json:"postal_code"`
 	GenerateLease                 *bool         `json:"generate_lease,omitempty"`
 	NoStore                       bool          `json:"no_store"`
+	SignOnly                      bool          `json:"sign_only"`
 	RequireCN                     bool          `json:"require_cn"`
 	CNValidations                 []string      `json:"cn_validations"`
 	AllowedOtherSANs              []string      `json:"allowed_other_sans"`
@@ -1566,12 +1636,15 @@ func (r *roleEntry) ToResponseData() map[string]interface{} {
 		"ext_key_usage_oids":                 r.ExtKeyUsageOIDs,
 		"ou":                                 r.OU,
 		"organization":                       r.Organization,
+		"subject_serial_number":              r.SubjectSerialNumber,
+		"subject_template":                   r.SubjectTemplate,
 		"country":                            r.Country,
 		"locality":                           r.Locality,
 		"province":                           r.Province,
 		"street_address":                     r.StreetAddress,
 		"postal_code":                        r.PostalCode,
 		"no_store":                           r.NoStore,
+		"sign_only":                          r.SignOnly,
 		"allowed_other_sans":                 r.AllowedOtherSANs,
 		"allowed_serial_numbers":             r.AllowedSerialNumbers,
 		"allowed_user_ids":                   r.AllowedUserIDs,
@@ -1593,6 +1666,16 @@ func (r *roleEntry) ToResponseData() map[string]interface{} {
 	return responseData
 }
 
+// subjectTemplates returns the subject fields of the role which may be
+// identity templates when SubjectTemplate is set.
+func (r *roleEntry) subjectTemplates() []string {
+	templates := append(append([]string{}, r.OU...), r.Organization...)
+	if r.SubjectSerialNumber != "" {
+		templates = append(templates, r.SubjectSerialNumber)
+	}
+	return templates
+}
+
 func checkCNValidations(validations []string) ([]string, error) {
 	var haveDisabled bool
 	var haveEmail bool
@@ -5,7 +5,11 @@ package pki
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/asn1"
 	"encoding/base64"
 	"encoding/pem"
@@ -1160,3 +1164,58 @@ func getPolicyInformationExtensionOffCertificate(resp logical.Response) ([]byte,
 	}
 	return *new([]byte), errors.New("No Policy Information Extension Found")
 }
+
+func TestPki_RoleSignOnly(t *testing.T) {
+	t.Parallel()
+	b, s := CreateBackendWithStorage(t)
+
+	resp, err := CBWrite(b, s, "root/generate/internal", map[string]interface{}{
+		"common_name": "root.example.com",
+		"key_type":    "ec",
+		"ttl":         "72h",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+
+	resp, err = CBWrite(b, s, "roles/external", map[string]interface{}{
+		"allowed_domains":  "example.com",
+		"allow_subdomains": true,
+		"key_type":         "ec",
+		"ttl":              "1h",
+		"sign_only":        true,
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	resp, err = CBRead(b, s, "roles/external")
+	requireSuccessNonNilResponse(t, resp, err)
+	require.Equal(t, true, resp.Data["sign_only"])
+
+	// Private keys cannot be generated with the role.
+	_, err = CBWrite(b, s, "issue/external", map[string]interface{}{
+		"common_name": "host.example.com",
+	})
+	require.ErrorContains(t, err, "sign-only")
+
+	// CSRs are signed, recording the key attestation.
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: "host.example.com"},
+	}, key)
+	require.NoError(t, err)
+	resp, err = CBWrite(b, s, "sign/external", map[string]interface{}{
+		"csr":             string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr})),
+		"key_attestation": "attestation-statement",
+	})
+	requireSuccessNonNilResponse(t, resp, err)
+	require.NotContains(t, resp.Data, "private_key")
+	serial := resp.Data["serial_number"].(string)
+
+	resp, err = CBRead(b, s, "cert-metadata/"+serial)
+	requireSuccessNonNilResponse(t, resp, err)
+	require.Equal(t, "external", resp.Data["role"])
+	require.Equal(t, "attestation-statement", resp.Data["key_attestation"])
+	require.Equal(t, serial, resp.Data["serial_number"])
+
+	resp, err = CBList(b, s, "cert-metadata")
+	requireSuccessNonNilResponse(t, resp, err)
+	require.Equal(t, []string{normalizeSerial(serial)}, resp.Data["keys"])
+}
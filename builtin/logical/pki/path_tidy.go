@@ -879,6 +879,9 @@ func (b *backend) doTidyCertStore(ctx context.Context, req *logical.Request, log
 			if err := req.Storage.Delete(ctx, "certs/"+serial); err != nil {
 				return fmt.Errorf("error deleting serial %q from storage: %w", serial, err)
 			}
+			if err := req.Storage.Delete(ctx, certMetadataPath+serial); err != nil {
+				return fmt.Errorf("error deleting metadata of serial %q from storage: %w", serial, err)
+			}
 			b.tidyStatusIncCertStoreCount()
 		}
 	}
@@ -9,6 +9,7 @@ import (
 	"crypto/x509"
 	"errors"
 	"fmt"
+	"net/url"
 	"sort"
 	"strings"
 	"time"
@@ -192,8 +193,10 @@ type issuerConfigEntry struct {
 }
 
 type clusterConfigEntry struct {
-	Path    string `json:"path"`
-	AIAPath string `json:"aia_path"`
+	Path                    string `json:"path"`
+	AIAPath                 string `json:"aia_path"`
+	SerialNumberFormat      string `json:"serial_number_format,omitempty"`
+	SerialNumberEntropyBits int    `json:"serial_number_entropy_bits,omitempty"`
 }
 
 type aiaConfigEntry struct {
@@ -240,6 +243,15 @@ func (c *aiaConfigEntry) toURLEntries(sc *storageContext, issuer issuerID) (*cer
 					// Elide issuer AIA info as we lack an issuer_id.
 					return nil, fmt.Errorf("unable to template AIA URLs as we lack an issuer_id for this operation")
 				}
+				if strings.Contains(uri, "{{cluster_address}}") || strings.Contains(uri, "{{mount_path}}") {
+					address, mountPath, err := splitClusterPath(cfg.Path)
+					if err != nil {
+						return nil, fmt.Errorf("unable to template AIA URLs: %w", err)
+					}
+
+					uri = strings.ReplaceAll(uri, "{{cluster_address}}", address)
+					uri = strings.ReplaceAll(uri, "{{mount_path}}", mountPath)
+				}
 
 				uri = strings.ReplaceAll(uri, "{{cluster_path}}", cfg.Path)
 				uri = strings.ReplaceAll(uri, "{{cluster_aia_path}}", cfg.AIAPath)
@@ -258,6 +270,28 @@ func (c *aiaConfigEntry) toURLEntries(sc *storageContext, issuer issuerID) (*cer
 	return &result, nil
 }
 
+// splitClusterPath splits the cluster-local path to this mount into the
+// address of the cluster, such as https://pr1.example.com:8200, and the path
+// of the mount including any namespaces, such as ns1/pki.
+func splitClusterPath(clusterPath string) (string, string, error) {
+	if len(clusterPath) == 0 {
+		return "", "", fmt.Errorf("we lack local cluster address information (path)")
+	}
+
+	parsed, err := url.Parse(clusterPath)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to parse local cluster path: %w", err)
+	}
+
+	mountPath, ok := strings.CutPrefix(parsed.Path, "/v1/")
+	if !ok || len(strings.Trim(mountPath, "/")) == 0 {
+		return "", "", fmt.Errorf("local cluster path %q does not refer to a mount under /v1/", clusterPath)
+	}
+
+	address := parsed.Scheme + "://" + parsed.Host
+	return address, strings.Trim(mountPath, "/"), nil
+}
+
 type storageContext struct {
 	Context context.Context
 	Storage logical.Storage
@@ -277,3 +277,33 @@ func writeLegacyBundle(t *testing.T, b *backend, s logical.Storage, bundle *cert
 	err = s.Put(context.Background(), entry)
 	require.NoError(t, err)
 }
+
+func Test_AIATemplatingClusterAddress(t *testing.T) {
+	t.Parallel()
+	b, s := CreateBackendWithStorage(t)
+	sc := b.makeStorageContext(ctx, s)
+
+	aia := &aiaConfigEntry{
+		IssuingCertificates:   []string{"{{cluster_address}}/v1/{{mount_path}}/issuer/{{issuer_id}}/der"},
+		CRLDistributionPoints: []string{"http://crl.example.com/{{mount_path}}/{{issuer_id}}.crl"},
+		EnableTemplating:      true,
+	}
+
+	// Templating fails without the cluster-local path.
+	_, err := aia.toURLEntries(sc, issuerID("my-issuer"))
+	require.Error(t, err)
+
+	err = sc.writeClusterConfig(&clusterConfigEntry{Path: "https://pr1.example.com:8200/v1/ns1/pki/"})
+	require.NoError(t, err)
+
+	entries, err := aia.toURLEntries(sc, issuerID("my-issuer"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"https://pr1.example.com:8200/v1/ns1/pki/issuer/my-issuer/der"}, entries.IssuingCertificates)
+	require.Equal(t, []string{"http://crl.example.com/ns1/pki/my-issuer.crl"}, entries.CRLDistributionPoints)
+
+	// The path must refer to a mount.
+	err = sc.writeClusterConfig(&clusterConfigEntry{Path: "https://pr1.example.com:8200/pki"})
+	require.NoError(t, err)
+	_, err = aia.toURLEntries(sc, issuerID("my-issuer"))
+	require.Error(t, err)
+}
@@ -280,6 +280,15 @@ func (sc *storageContext) isIfModifiedSinceBeforeLastModified(helper *IfModified
 	return false, nil
 }
 
+// checkRestrictedCrypto refuses keys of types which are not approved when the
+// server runs in restricted crypto mode.
+func (b *backend) checkRestrictedCrypto(keyType certutil.PrivateKeyType) error {
+	if b.RestrictedCrypto() && !keyType.RestrictedCryptoApproved() {
+		return errutil.UserError{Err: fmt.Sprintf("key type %s is not allowed in restricted crypto mode", keyType)}
+	}
+	return nil
+}
+
 func addWarnings(resp *logical.Response, warnings []string) *logical.Response {
 	for _, warning := range warnings {
 		resp.AddWarning(warning)
@@ -11,6 +11,7 @@ import (
 	cleanhttp "github.com/hashicorp/go-cleanhttp"
 	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
 	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/locksutil"
 	"github.com/openbao/openbao/sdk/v2/logical"
 )
 
@@ -34,6 +35,7 @@ func Backend() *backend {
 		PathsSpecial: &logical.Paths{
 			SealWrapStorage: []string{
 				"config/connection",
+				staticRolePath,
 			},
 		},
 
@@ -43,17 +45,24 @@ func Backend() *backend {
 			pathListRoles(&b),
 			pathCreds(&b),
 			pathRoles(&b),
+			pathListStaticRoles(&b),
+			pathStaticRoles(&b),
+			pathStaticCreds(&b),
+			pathRotateStaticRole(&b),
 		},
 
 		Secrets: []*framework.Secret{
 			secretCreds(&b),
 		},
 
-		Clean:       b.resetClient,
-		Invalidate:  b.invalidate,
-		BackendType: logical.TypeLogical,
+		Clean:        b.resetClient,
+		Invalidate:   b.invalidate,
+		PeriodicFunc: b.periodicFunc,
+		BackendType:  logical.TypeLogical,
 	}
 
+	b.roleLocks = locksutil.CreateLocks()
+
 	return &b
 }
 
@@ -62,6 +71,9 @@ type backend struct {
 
 	client *rabbithole.Client
 	lock   sync.RWMutex
+
+	// roleLocks serializes the rotations of the static roles.
+	roleLocks []*locksutil.LockEntry
 }
 
 // DB returns the database connection.
@@ -9,6 +9,15 @@ import (
 	"github.com/hashicorp/go-secure-stdlib/base62"
 )
 
+// passwordPolicy returns the password policy of a role, falling back to the
+// password policy of the connection configuration.
+func passwordPolicy(rolePolicy string, config connectionConfig) string {
+	if rolePolicy != "" {
+		return rolePolicy
+	}
+	return config.PasswordPolicy
+}
+
 func (b *backend) generatePassword(ctx context.Context, policyName string) (password string, err error) {
 	if policyName != "" {
 		return b.System().GeneratePasswordFromPolicy(ctx, policyName)
@@ -57,7 +57,7 @@ func (b *backend) pathCredsRead(ctx context.Context, req *logical.Request, d *fr
 		return nil, err
 	}
 	if role == nil {
-		return logical.ErrorResponse(fmt.Sprintf("unknown role: %s", name)), nil
+		return logical.CodedErrorResponse(logical.ErrorCodeNotFound, "unknown role: %s", name), nil
 	}
 
 	config, err := readConfig(ctx, req.Storage)
@@ -85,7 +85,7 @@ func (b *backend) pathCredsRead(ctx context.Context, req *logical.Request, d *fr
 		return nil, fmt.Errorf("failed to generate username: %w", err)
 	}
 
-	password, err := b.generatePassword(ctx, config.PasswordPolicy)
+	password, err := b.generatePassword(ctx, passwordPolicy(role.PasswordPolicy, config))
 	if err != nil {
 		return nil, err
 	}
@@ -65,6 +65,10 @@ func pathRoles(b *backend) *framework.Path {
 				Type:        framework.TypeString,
 				Description: "A nested map of virtual hosts and exchanges to topic permissions.",
 			},
+			"password_policy": {
+				Type:        framework.TypeString,
+				Description: "Name of the password policy used to generate the passwords of the credentials. Defaults to the password policy of the connection configuration.",
+			},
 		},
 		Callbacks: map[logical.Operation]framework.OperationFunc{
 			logical.ReadOperation:   b.pathRoleRead,
@@ -143,6 +147,7 @@ func (b *backend) pathRoleRead(ctx context.Context, req *logical.Request, d *fra
 		respVHostTopics[key] = respVHostTopic
 	}
 	resp["vhost_topics"] = respVHostTopics
+	resp["password_policy"] = role.PasswordPolicy
 
 	return &logical.Response{
 		Data: resp,
@@ -175,6 +180,7 @@ func (b *backend) pathRoleUpdate(ctx context.Context, req *logical.Request, d *f
 	tags := d.Get("tags").(string)
 	rawVHosts := d.Get("vhosts").(string)
 	rawVHostTopics := d.Get("vhost_topics").(string)
+	passwordPolicy := d.Get("password_policy").(string)
 
 	// Either tags or VHost permissions are always required, but topic permissions are always optional.
 	if tags == "" && rawVHosts == "" {
@@ -197,9 +203,10 @@ func (b *backend) pathRoleUpdate(ctx context.Context, req *logical.Request, d *f
 
 	// Store it
 	entry, err := logical.StorageEntryJSON("role/"+name, &roleEntry{
-		Tags:        tags,
-		VHosts:      vhosts,
-		VHostTopics: vhostTopics,
+		Tags:           tags,
+		VHosts:         vhosts,
+		VHostTopics:    vhostTopics,
+		PasswordPolicy: passwordPolicy,
 	})
 	if err != nil {
 		return nil, err
@@ -220,6 +227,9 @@ type roleEntry struct {
 	Tags        string                                     `json:"tags" structs:"tags" mapstructure:"tags"`
 	VHosts      map[string]vhostPermission                 `json:"vhosts" structs:"vhosts" mapstructure:"vhosts"`
 	VHostTopics map[string]map[string]vhostTopicPermission `json:"vhost_topics" structs:"vhost_topics" mapstructure:"vhost_topics"`
+	// PasswordPolicy overrides the password policy of the connection
+	// configuration.
+	PasswordPolicy string `json:"password_policy" structs:"password_policy" mapstructure:"password_policy"`
 }
 
 // Structure representing the permissions of a vhost
@@ -0,0 +1,399 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/locksutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const (
+	staticRolePath = "static-role/"
+
+	// minRotationPeriod is the minimum rotation period of the static roles.
+	// Due rotations are performed by the periodic function of the backend,
+	// which runs about every minute.
+	minRotationPeriod = time.Minute
+)
+
+func pathListStaticRoles(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "static-roles/?$",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixRabbitMQ,
+			OperationSuffix: "static-roles",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"after": {
+				Type:        framework.TypeString,
+				Description: `Optional entry to list begin listing after, not required to exist.`,
+			},
+			"limit": {
+				Type:        framework.TypeInt,
+				Description: `Optional number of entries to return; defaults to all entries.`,
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ListOperation: b.pathStaticRoleList,
+		},
+
+		HelpSynopsis:    pathStaticRoleHelpSyn,
+		HelpDescription: pathStaticRoleHelpDesc,
+	}
+}
+
+func pathStaticRoles(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "static-roles/" + framework.GenericNameRegex("name"),
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixRabbitMQ,
+			OperationSuffix: "static-role",
+		},
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Name of the static role.",
+			},
+			"username": {
+				Type:        framework.TypeString,
+				Description: "Name of the existing RabbitMQ user whose password is managed by the static role.",
+			},
+			"rotation_period": {
+				Type:        framework.TypeDurationSecond,
+				Description: "Period for automatic rotation of the password of the user. Must be at least one minute.",
+			},
+			"password_policy": {
+				Type:        framework.TypeString,
+				Description: "Name of the password policy used to generate the password of the user. Defaults to the password policy of the connection configuration.",
+			},
+		},
+		ExistenceCheck: b.pathStaticRoleExistenceCheck,
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation:   b.pathStaticRoleRead,
+			logical.CreateOperation: b.pathStaticRoleCreateUpdate,
+			logical.UpdateOperation: b.pathStaticRoleCreateUpdate,
+			logical.DeleteOperation: b.pathStaticRoleDelete,
+		},
+		HelpSynopsis:    pathStaticRoleHelpSyn,
+		HelpDescription: pathStaticRoleHelpDesc,
+	}
+}
+
+func pathStaticCreds(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "static-creds/" + framework.GenericNameRegex("name"),
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixRabbitMQ,
+			OperationVerb:   "request",
+			OperationSuffix: "static-role-credentials",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Name of the static role.",
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ReadOperation: b.pathStaticCredsRead,
+		},
+
+		HelpSynopsis:    pathStaticCredsHelpSyn,
+		HelpDescription: pathStaticCredsHelpDesc,
+	}
+}
+
+func pathRotateStaticRole(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "rotate-role/" + framework.GenericNameRegex("name"),
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixRabbitMQ,
+			OperationVerb:   "rotate",
+			OperationSuffix: "static-role-credentials",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Name of the static role.",
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.UpdateOperation: b.pathRotateStaticRoleUpdate,
+		},
+
+		HelpSynopsis:    pathRotateStaticRoleHelpSyn,
+		HelpDescription: pathRotateStaticRoleHelpDesc,
+	}
+}
+
+// StaticRole reads the static role of the given name from the storage.
+func (b *backend) StaticRole(ctx context.Context, s logical.Storage, n string) (*staticRoleEntry, error) {
+	entry, err := s.Get(ctx, staticRolePath+n)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var result staticRoleEntry
+	if err := entry.DecodeJSON(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func storeStaticRole(ctx context.Context, s logical.Storage, name string, role *staticRoleEntry) error {
+	entry, err := logical.StorageEntryJSON(staticRolePath+name, role)
+	if err != nil {
+		return err
+	}
+	return s.Put(ctx, entry)
+}
+
+func (b *backend) pathStaticRoleExistenceCheck(ctx context.Context, req *logical.Request, d *framework.FieldData) (bool, error) {
+	role, err := b.StaticRole(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return false, err
+	}
+	return role != nil, nil
+}
+
+// Lists the static roles registered with the backend
+func (b *backend) pathStaticRoleList(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	after := data.Get("after").(string)
+	limit := data.Get("limit").(int)
+	if limit <= 0 {
+		limit = -1
+	}
+
+	roles, err := req.Storage.ListPage(ctx, staticRolePath, after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return logical.ListResponse(roles), nil
+}
+
+// Reads an existing static role
+func (b *backend) pathStaticRoleRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+	if name == "" {
+		return logical.ErrorResponse("missing name"), nil
+	}
+
+	role, err := b.StaticRole(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, nil
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"username":            role.Username,
+			"rotation_period":     role.RotationPeriod.Seconds(),
+			"last_vault_rotation": role.LastVaultRotation,
+			"password_policy":     role.PasswordPolicy,
+		},
+	}, nil
+}
+
+// Creates or updates a static role. The password of the user is rotated when
+// the role is created.
+func (b *backend) pathStaticRoleCreateUpdate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+	if name == "" {
+		return logical.ErrorResponse("missing name"), nil
+	}
+
+	lock := locksutil.LockForKey(b.roleLocks, name)
+	lock.Lock()
+	defer lock.Unlock()
+
+	role, err := b.StaticRole(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	createRole := role == nil
+	if createRole {
+		role = &staticRoleEntry{}
+	}
+
+	if usernameRaw, ok := d.GetOk("username"); ok {
+		username := usernameRaw.(string)
+		if !createRole && username != role.Username {
+			return logical.ErrorResponse("cannot update the username of a static role"), nil
+		}
+		role.Username = username
+	}
+	if role.Username == "" {
+		return logical.ErrorResponse("missing username"), nil
+	}
+
+	if rotationPeriodRaw, ok := d.GetOk("rotation_period"); ok {
+		role.RotationPeriod = time.Duration(rotationPeriodRaw.(int)) * time.Second
+	} else if createRole {
+		return logical.ErrorResponse("missing rotation_period"), nil
+	}
+	if role.RotationPeriod < minRotationPeriod {
+		return logical.ErrorResponse("rotation_period must be %s or more", minRotationPeriod), nil
+	}
+
+	if passwordPolicyRaw, ok := d.GetOk("password_policy"); ok {
+		role.PasswordPolicy = passwordPolicyRaw.(string)
+	}
+
+	if !createRole {
+		if err := storeStaticRole(ctx, req.Storage, name, role); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+
+	config, err := readConfig(ctx, req.Storage)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read configuration: %w", err)
+	}
+	if role.Username == config.Username {
+		return logical.ErrorResponse("the user of the connection configuration cannot be managed by a static role"), nil
+	}
+
+	if err := b.rotateStaticRole(ctx, req.Storage, name, role); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// Deletes an existing static role. The user is left untouched.
+func (b *backend) pathStaticRoleDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+	if name == "" {
+		return logical.ErrorResponse("missing name"), nil
+	}
+
+	lock := locksutil.LockForKey(b.roleLocks, name)
+	lock.Lock()
+	defer lock.Unlock()
+
+	return nil, req.Storage.Delete(ctx, staticRolePath+name)
+}
+
+// Reads the current credentials of a static role
+func (b *backend) pathStaticCredsRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+	if name == "" {
+		return logical.ErrorResponse("missing name"), nil
+	}
+
+	role, err := b.StaticRole(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return logical.ErrorResponse("unknown static role: %s", name), nil
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"username":            role.Username,
+			"password":            role.Password,
+			"ttl":                 role.credentialTTL().Seconds(),
+			"rotation_period":     role.RotationPeriod.Seconds(),
+			"last_vault_rotation": role.LastVaultRotation,
+		},
+	}, nil
+}
+
+// Rotates the password of a static role immediately
+func (b *backend) pathRotateStaticRoleUpdate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+	if name == "" {
+		return logical.ErrorResponse("missing name"), nil
+	}
+
+	lock := locksutil.LockForKey(b.roleLocks, name)
+	lock.Lock()
+	defer lock.Unlock()
+
+	role, err := b.StaticRole(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return logical.ErrorResponse("unknown static role: %s", name), nil
+	}
+
+	if err := b.rotateStaticRole(ctx, req.Storage, name, role); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+// staticRoleEntry is a static role, managing the password of an existing
+// RabbitMQ user.
+type staticRoleEntry struct {
+	Username          string        `json:"username"`
+	RotationPeriod    time.Duration `json:"rotation_period"`
+	Password          string        `json:"password"`
+	LastVaultRotation time.Time     `json:"last_vault_rotation"`
+	PasswordPolicy    string        `json:"password_policy"`
+}
+
+// nextRotation returns the time at which the password is due for rotation.
+func (r *staticRoleEntry) nextRotation() time.Time {
+	return r.LastVaultRotation.Add(r.RotationPeriod)
+}
+
+// credentialTTL returns the remaining time until the next rotation.
+func (r *staticRoleEntry) credentialTTL() time.Duration {
+	ttl := time.Until(r.nextRotation())
+	if ttl < 0 {
+		return 0
+	}
+	return ttl.Truncate(time.Second)
+}
+
+const pathStaticRoleHelpSyn = `
+Manage the static roles of this backend.
+`
+
+const pathStaticRoleHelpDesc = `
+This path lets you manage the static roles of this backend. A static role
+manages the password of an existing RabbitMQ user, which is rotated when the
+role is created and then every "rotation_period". The tags and permissions of
+the user are left untouched.
+`
+
+const pathStaticCredsHelpSyn = `
+Request the credentials of a static role.
+`
+
+const pathStaticCredsHelpDesc = `
+This path reads the current credentials of a static role. The same password is
+returned until it is rotated.
+`
+
+const pathRotateStaticRoleHelpSyn = `
+Rotate the password of a static role.
+`
+
+const pathRotateStaticRoleHelpDesc = `
+This path rotates the password of a static role immediately. The next rotation
+is scheduled one "rotation_period" later.
+`
@@ -0,0 +1,187 @@
+package rabbitmq
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeManagementAPI emulates the user endpoints of the RabbitMQ management
+// HTTP API.
+type fakeManagementAPI struct {
+	sync.Mutex
+	users map[string]map[string]interface{}
+}
+
+func (f *fakeManagementAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+
+	name := strings.TrimPrefix(r.URL.Path, "/api/users/")
+	switch r.Method {
+	case http.MethodGet:
+		user, ok := f.users[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"Object Not Found","reason":"Not Found"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"name": name,
+			"tags": user["tags"],
+		})
+	case http.MethodPut:
+		var settings map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.users[name] = settings
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakeManagementAPI) user(name string) map[string]interface{} {
+	f.Lock()
+	defer f.Unlock()
+	return f.users[name]
+}
+
+func TestBackend_StaticRoles(t *testing.T) {
+	api := &fakeManagementAPI{
+		users: map[string]map[string]interface{}{
+			"app": {"tags": "management", "password": "initial"},
+		},
+	}
+	srv := httptest.NewServer(api)
+	defer srv.Close()
+
+	ctx := context.Background()
+	config := logical.TestBackendConfig()
+	config.StorageView = &logical.InmemStorage{}
+	b := Backend()
+	require.NoError(t, b.Setup(ctx, config))
+
+	resp, err := b.HandleRequest(ctx, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "config/connection",
+		Storage:   config.StorageView,
+		Data: map[string]interface{}{
+			"connection_uri":    srv.URL,
+			"username":          "admin",
+			"password":          "admin",
+			"verify_connection": false,
+		},
+	})
+	require.NoError(t, err)
+	require.Nil(t, resp)
+
+	writeRole := func(name string, data map[string]interface{}) *logical.Response {
+		t.Helper()
+		resp, err := b.HandleRequest(ctx, &logical.Request{
+			Operation: logical.CreateOperation,
+			Path:      "static-roles/" + name,
+			Storage:   config.StorageView,
+			Data:      data,
+		})
+		require.NoError(t, err)
+		return resp
+	}
+
+	readCreds := func() map[string]interface{} {
+		t.Helper()
+		resp, err := b.HandleRequest(ctx, &logical.Request{
+			Operation: logical.ReadOperation,
+			Path:      "static-creds/app",
+			Storage:   config.StorageView,
+		})
+		require.NoError(t, err)
+		require.False(t, resp.IsError())
+		return resp.Data
+	}
+
+	// Invalid roles are refused.
+	require.True(t, writeRole("bad", map[string]interface{}{"username": "app"}).IsError())
+	require.True(t, writeRole("bad", map[string]interface{}{"username": "app", "rotation_period": "10s"}).IsError())
+	require.True(t, writeRole("bad", map[string]interface{}{"username": "admin", "rotation_period": "1h"}).IsError())
+
+	// Creating the role rotates the password and keeps the tags of the user.
+	require.Nil(t, writeRole("app", map[string]interface{}{"username": "app", "rotation_period": "1h"}))
+	creds := readCreds()
+	require.Equal(t, "app", creds["username"])
+	password := creds["password"].(string)
+	require.NotEmpty(t, password)
+	require.Equal(t, password, api.user("app")["password"])
+	require.Equal(t, "management", api.user("app")["tags"])
+	require.InDelta(t, 3600, creds["ttl"], 5)
+
+	// The periodic function does not rotate passwords before they are due.
+	require.NoError(t, b.periodicFunc(ctx, &logical.Request{Storage: config.StorageView}))
+	require.Equal(t, password, readCreds()["password"])
+
+	// Manual rotation.
+	resp, err = b.HandleRequest(ctx, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "rotate-role/app",
+		Storage:   config.StorageView,
+	})
+	require.NoError(t, err)
+	require.Nil(t, resp)
+	rotated := readCreds()["password"].(string)
+	require.NotEqual(t, password, rotated)
+	require.Equal(t, rotated, api.user("app")["password"])
+
+	// The periodic function rotates passwords which are due.
+	role, err := b.StaticRole(ctx, config.StorageView, "app")
+	require.NoError(t, err)
+	role.LastVaultRotation = time.Now().Add(-2 * time.Hour)
+	require.NoError(t, storeStaticRole(ctx, config.StorageView, "app", role))
+	require.NoError(t, b.periodicFunc(ctx, &logical.Request{Storage: config.StorageView}))
+	require.NotEqual(t, rotated, readCreds()["password"])
+
+	// The username cannot be changed.
+	resp, err = b.HandleRequest(ctx, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "static-roles/app",
+		Storage:   config.StorageView,
+		Data:      map[string]interface{}{"username": "other"},
+	})
+	require.NoError(t, err)
+	require.True(t, resp.IsError())
+
+	// Roles for unknown users cannot be created.
+	_, err = b.HandleRequest(ctx, &logical.Request{
+		Operation: logical.CreateOperation,
+		Path:      "static-roles/missing",
+		Storage:   config.StorageView,
+		Data:      map[string]interface{}{"username": "missing", "rotation_period": "1h"},
+	})
+	require.Error(t, err)
+
+	resp, err = b.HandleRequest(ctx, &logical.Request{
+		Operation: logical.ListOperation,
+		Path:      "static-roles/",
+		Storage:   config.StorageView,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"app"}, resp.Data["keys"])
+
+	// Deleting the role leaves the user in place.
+	_, err = b.HandleRequest(ctx, &logical.Request{
+		Operation: logical.DeleteOperation,
+		Path:      "static-roles/app",
+		Storage:   config.StorageView,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, api.user("app"))
+}
@@ -0,0 +1,95 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	rabbithole "github.com/michaelklishin/rabbit-hole/v2"
+	"github.com/openbao/openbao/sdk/v2/helper/locksutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+// rotateStaticRole sets a new password on the user of the static role and
+// stores it. The caller must hold the lock of the role.
+func (b *backend) rotateStaticRole(ctx context.Context, s logical.Storage, name string, role *staticRoleEntry) error {
+	config, err := readConfig(ctx, s)
+	if err != nil {
+		return fmt.Errorf("unable to read configuration: %w", err)
+	}
+
+	client, err := b.Client(ctx, s)
+	if err != nil {
+		return err
+	}
+
+	// The user is updated as a whole, so read it to keep its tags.
+	user, err := client.GetUser(role.Username)
+	if err != nil {
+		return fmt.Errorf("unable to read user %q: %w", role.Username, err)
+	}
+
+	password, err := b.generatePassword(ctx, passwordPolicy(role.PasswordPolicy, config))
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.PutUser(role.Username, rabbithole.UserSettings{
+		Password: password,
+		Tags:     user.Tags,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update the password of user %q: %w", role.Username, err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			b.Logger().Error(fmt.Sprintf("unable to close response body: %s", err))
+		}
+	}()
+	if !isIn200s(resp.StatusCode) {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("error updating the password of user %s - %d: %s", role.Username, resp.StatusCode, body)
+	}
+
+	role.Password = password
+	role.LastVaultRotation = time.Now()
+	if err := storeStaticRole(ctx, s, name, role); err != nil {
+		return fmt.Errorf("password of user %q was rotated but could not be stored: %w", role.Username, err)
+	}
+
+	return nil
+}
+
+// periodicFunc rotates the passwords of the static roles which are due for
+// rotation.
+func (b *backend) periodicFunc(ctx context.Context, req *logical.Request) error {
+	names, err := req.Storage.List(ctx, staticRolePath)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if err := b.rotateStaticRoleIfDue(ctx, req.Storage, name); err != nil {
+			b.Logger().Error("unable to rotate the password of static role", "role", name, "error", err)
+		}
+	}
+
+	return nil
+}
+
+func (b *backend) rotateStaticRoleIfDue(ctx context.Context, s logical.Storage, name string) error {
+	lock := locksutil.LockForKey(b.roleLocks, name)
+	lock.Lock()
+	defer lock.Unlock()
+
+	role, err := b.StaticRole(ctx, s, name)
+	if err != nil {
+		return err
+	}
+	if role == nil || time.Now().Before(role.nextRotation()) {
+		return nil
+	}
+
+	return b.rotateStaticRole(ctx, s, name, role)
+}
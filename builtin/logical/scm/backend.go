@@ -0,0 +1,69 @@
+package scm
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const operationPrefixSCM = "scm"
+
+func Factory(ctx context.Context, conf *logical.BackendConfig) (logical.Backend, error) {
+	b := Backend(conf)
+	if err := b.Setup(ctx, conf); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func Backend(conf *logical.BackendConfig) *backend {
+	b := &backend{
+		client: cleanhttp.DefaultClient(),
+	}
+	b.Backend = &framework.Backend{
+		Help: strings.TrimSpace(backendHelp),
+
+		PathsSpecial: &logical.Paths{
+			SealWrapStorage: []string{
+				"config/",
+			},
+		},
+
+		Paths: []*framework.Path{
+			pathConfigGitHub(b),
+			pathConfigGitLab(b),
+			pathRolesList(b),
+			pathRoles(b),
+			pathCreds(b),
+		},
+
+		Secrets: []*framework.Secret{
+			secretToken(b),
+		},
+
+		BackendType: logical.TypeLogical,
+	}
+
+	return b
+}
+
+type backend struct {
+	*framework.Backend
+
+	// client is the HTTP client used to call the GitHub and GitLab APIs.
+	client *http.Client
+}
+
+const backendHelp = `
+The SCM backend vends short-lived GitHub App installation tokens and GitLab
+project and group access tokens, so that CI pipelines do not need long-lived
+tokens.
+
+Configure the GitHub App or the GitLab token the tokens are created with,
+then create roles setting the scope of the tokens, and read tokens from
+creds/<role>. Tokens are revoked when their lease expires or is revoked.
+`
@@ -0,0 +1,262 @@
+package scm
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/stretchr/testify/require"
+)
+
+func testBackend(t *testing.T) (*backend, logical.Storage) {
+	t.Helper()
+
+	storage := &logical.InmemStorage{}
+	config := logical.TestBackendConfig()
+	config.StorageView = storage
+
+	b, err := Factory(context.Background(), config)
+	require.NoError(t, err)
+
+	return b.(*backend), storage
+}
+
+func testRequest(t *testing.T, b *backend, storage logical.Storage, op logical.Operation, path string, data map[string]interface{}) (*logical.Response, error) {
+	t.Helper()
+
+	return b.HandleRequest(context.Background(), &logical.Request{
+		Operation: op,
+		Path:      path,
+		Storage:   storage,
+		Data:      data,
+	})
+}
+
+// revoke revokes the secret of the response as the expiration manager would,
+// with the internal data round-tripped through JSON.
+func revoke(t *testing.T, b *backend, storage logical.Storage, resp *logical.Response) error {
+	t.Helper()
+
+	encoded, err := json.Marshal(resp.Secret)
+	require.NoError(t, err)
+	var secret logical.Secret
+	require.NoError(t, json.Unmarshal(encoded, &secret))
+
+	_, err = b.HandleRequest(context.Background(), &logical.Request{
+		Operation: logical.RevokeOperation,
+		Storage:   storage,
+		Secret:    &secret,
+	})
+	return err
+}
+
+func TestSCM_GitHub(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	var mu sync.Mutex
+	revoked := map[string]bool{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/app/installations/42/access_tokens":
+			// The request is authenticated as the app
+			appJWT := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			var claims jwt.RegisteredClaims
+			_, err := jwt.ParseWithClaims(appJWT, &claims, func(*jwt.Token) (interface{}, error) {
+				return &key.PublicKey, nil
+			})
+			require.NoError(t, err)
+			require.Equal(t, "1234", claims.Issuer)
+
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.Equal(t, []interface{}{"infra"}, body["repositories"])
+			require.Equal(t, map[string]interface{}{"contents": "read"}, body["permissions"])
+
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"token":      "ghs_installation",
+				"expires_at": time.Now().Add(time.Hour).Format(time.RFC3339),
+			})
+		case r.Method == http.MethodDelete && r.URL.Path == "/installation/token":
+			token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if revoked[token] {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			revoked[token] = true
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	b, storage := testBackend(t)
+
+	_, err = testRequest(t, b, storage, logical.UpdateOperation, "config/github", map[string]interface{}{
+		"app_id":      1234,
+		"private_key": "not a key",
+		"base_url":    srv.URL,
+	})
+	require.ErrorIs(t, err, logical.ErrInvalidRequest)
+	_, err = testRequest(t, b, storage, logical.UpdateOperation, "config/github", map[string]interface{}{
+		"app_id":      1234,
+		"private_key": string(keyPEM),
+		"base_url":    srv.URL,
+	})
+	require.NoError(t, err)
+
+	// The private key is never read back
+	resp, err := testRequest(t, b, storage, logical.ReadOperation, "config/github", nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"app_id": int64(1234), "base_url": srv.URL}, resp.Data)
+
+	_, err = testRequest(t, b, storage, logical.CreateOperation, "role/ci", map[string]interface{}{
+		"platform":        platformGitHub,
+		"installation_id": 42,
+		"repositories":    "infra",
+		"permissions":     map[string]interface{}{"contents": "read"},
+		"ttl":             "15m",
+	})
+	require.NoError(t, err)
+
+	resp, err = testRequest(t, b, storage, logical.ReadOperation, "creds/ci", nil)
+	require.NoError(t, err)
+	require.Equal(t, "ghs_installation", resp.Data["token"])
+	require.Equal(t, 15*time.Minute, resp.Secret.TTL)
+	require.False(t, resp.Secret.Renewable)
+
+	require.NoError(t, revoke(t, b, storage, resp))
+	require.True(t, revoked["ghs_installation"])
+
+	// Revoking an expired or revoked token succeeds
+	require.NoError(t, revoke(t, b, storage, resp))
+}
+
+func TestSCM_GitLab(t *testing.T) {
+	var mu sync.Mutex
+	tokens := map[string]bool{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		require.Equal(t, "glpat-admin", r.Header.Get("Private-Token"))
+		switch {
+		case r.Method == http.MethodPost && r.URL.EscapedPath() == "/api/v4/projects/acme%2Finfra/access_tokens":
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.True(t, strings.HasPrefix(body["name"].(string), "openbao-ci-"))
+			require.Equal(t, []interface{}{"read_repository"}, body["scopes"])
+			require.Equal(t, float64(20), body["access_level"])
+			require.Equal(t, time.Now().UTC().AddDate(0, 0, 1).Format(time.DateOnly), body["expires_at"])
+
+			tokens["7"] = true
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"id":    7,
+				"token": "glpat-project",
+			})
+		case r.Method == http.MethodDelete && r.URL.EscapedPath() == "/api/v4/projects/acme%2Finfra/access_tokens/7":
+			if !tokens["7"] {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			delete(tokens, "7")
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	b, storage := testBackend(t)
+
+	_, err := testRequest(t, b, storage, logical.UpdateOperation, "config/gitlab", map[string]interface{}{
+		"token":    "glpat-admin",
+		"base_url": srv.URL,
+	})
+	require.NoError(t, err)
+
+	_, err = testRequest(t, b, storage, logical.CreateOperation, "role/ci", map[string]interface{}{
+		"platform":     platformGitLab,
+		"project":      "acme/infra",
+		"scopes":       "read_repository",
+		"access_level": 20,
+		"ttl":          "10m",
+	})
+	require.NoError(t, err)
+
+	resp, err := testRequest(t, b, storage, logical.ReadOperation, "creds/ci", nil)
+	require.NoError(t, err)
+	require.Equal(t, "glpat-project", resp.Data["token"])
+	require.Equal(t, 10*time.Minute, resp.Secret.TTL)
+	require.True(t, tokens["7"])
+
+	require.NoError(t, revoke(t, b, storage, resp))
+	require.False(t, tokens["7"])
+	require.NoError(t, revoke(t, b, storage, resp))
+}
+
+func TestSCM_Role(t *testing.T) {
+	b, storage := testBackend(t)
+
+	for _, data := range []map[string]interface{}{
+		{},
+		{"platform": "bitbucket"},
+		{"platform": platformGitHub},
+		{"platform": platformGitHub, "installation_id": 1, "ttl": "2h"},
+		{"platform": platformGitHub, "installation_id": 1, "project": "acme/infra"},
+		{"platform": platformGitHub, "installation_id": 1, "permissions": map[string]interface{}{"contents": "all"}},
+		{"platform": platformGitLab, "scopes": "api"},
+		{"platform": platformGitLab, "project": "1", "group": "2", "scopes": "api"},
+		{"platform": platformGitLab, "project": "1"},
+		{"platform": platformGitLab, "project": "1", "scopes": "api", "access_level": 35},
+	} {
+		_, err := testRequest(t, b, storage, logical.CreateOperation, "role/bad", data)
+		require.ErrorIs(t, err, logical.ErrInvalidRequest, "data: %v", data)
+	}
+
+	_, err := testRequest(t, b, storage, logical.CreateOperation, "role/gl", map[string]interface{}{
+		"platform": platformGitLab,
+		"group":    "acme",
+		"scopes":   "read_api",
+	})
+	require.NoError(t, err)
+
+	resp, err := testRequest(t, b, storage, logical.ReadOperation, "role/gl", nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"platform":     platformGitLab,
+		"ttl":          int64(3600),
+		"project":      "",
+		"group":        "acme",
+		"scopes":       []string{"read_api"},
+		"access_level": defaultGitLabAccessLevel,
+	}, resp.Data)
+
+	// The platform of a role cannot change
+	_, err = testRequest(t, b, storage, logical.UpdateOperation, "role/gl", map[string]interface{}{
+		"platform": platformGitHub,
+	})
+	require.ErrorIs(t, err, logical.ErrInvalidRequest)
+
+	// Creating tokens requires the platform to be configured
+	_, err = testRequest(t, b, storage, logical.ReadOperation, "creds/gl", nil)
+	require.ErrorIs(t, err, logical.ErrInvalidRequest)
+}
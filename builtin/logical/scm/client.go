@@ -0,0 +1,179 @@
+package scm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+)
+
+// errStatus is returned by do on an unexpected response status.
+type errStatus struct {
+	status int
+	body   string
+}
+
+func (e *errStatus) Error() string {
+	return fmt.Sprintf("unexpected status %d: %s", e.status, e.body)
+}
+
+// do sends a JSON request and decodes the JSON response into out, if not nil.
+// Responses with another status than the expected one are returned as
+// *errStatus.
+func (b *backend) do(ctx context.Context, method, reqURL string, header http.Header, body interface{}, expected int, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, reqURL, reqBody)
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expected {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return &errStatus{status: resp.StatusCode, body: string(respBody)}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// gitHubHeader returns the headers of the GitHub API requests authenticated
+// with the given bearer token.
+func gitHubHeader(token string) http.Header {
+	return http.Header{
+		"Accept":               []string{"application/vnd.github+json"},
+		"Authorization":        []string{"Bearer " + token},
+		"X-Github-Api-Version": []string{"2022-11-28"},
+	}
+}
+
+// gitHubAppJWT returns a JWT authenticating as the GitHub App, valid for
+// a few minutes. The issue time is backdated to allow for clock drift.
+func gitHubAppJWT(config *gitHubConfig) (string, error) {
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(config.PrivateKey))
+	if err != nil {
+		return "", fmt.Errorf("invalid GitHub App private key: %w", err)
+	}
+
+	now := time.Now()
+	return jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.RegisteredClaims{
+		Issuer:    strconv.FormatInt(config.AppID, 10),
+		IssuedAt:  jwt.NewNumericDate(now.Add(-time.Minute)),
+		ExpiresAt: jwt.NewNumericDate(now.Add(9 * time.Minute)),
+	}).SignedString(key)
+}
+
+type gitHubToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// createGitHubToken creates an installation token scoped to the repositories
+// and permissions of the role.
+func (b *backend) createGitHubToken(ctx context.Context, config *gitHubConfig, role *roleEntry) (*gitHubToken, error) {
+	appJWT, err := gitHubAppJWT(config)
+	if err != nil {
+		return nil, err
+	}
+
+	body := map[string]interface{}{}
+	if len(role.Repositories) > 0 {
+		body["repositories"] = role.Repositories
+	}
+	if len(role.Permissions) > 0 {
+		body["permissions"] = role.Permissions
+	}
+
+	var token gitHubToken
+	reqURL := fmt.Sprintf("%s/app/installations/%d/access_tokens", config.BaseURL, role.InstallationID)
+	if err := b.do(ctx, http.MethodPost, reqURL, gitHubHeader(appJWT), body, http.StatusCreated, &token); err != nil {
+		return nil, fmt.Errorf("failed to create GitHub installation token: %w", err)
+	}
+	return &token, nil
+}
+
+// revokeGitHubToken revokes the installation token. Expired tokens are
+// rejected by GitHub, and count as revoked.
+func (b *backend) revokeGitHubToken(ctx context.Context, baseURL, token string) error {
+	err := b.do(ctx, http.MethodDelete, baseURL+"/installation/token", gitHubHeader(token), nil, http.StatusNoContent, nil)
+	if e, ok := err.(*errStatus); ok && e.status == http.StatusUnauthorized {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to revoke GitHub installation token: %w", err)
+	}
+	return nil
+}
+
+type gitLabToken struct {
+	ID    int64  `json:"id"`
+	Token string `json:"token"`
+}
+
+// gitLabTokensURL returns the URL of the access tokens of the project or group
+// of the role.
+func gitLabTokensURL(baseURL string, role *roleEntry) string {
+	if role.Project != "" {
+		return fmt.Sprintf("%s/api/v4/projects/%s/access_tokens", baseURL, url.PathEscape(role.Project))
+	}
+	return fmt.Sprintf("%s/api/v4/groups/%s/access_tokens", baseURL, url.PathEscape(role.Group))
+}
+
+// createGitLabToken creates a project or group access token expiring the day
+// after the given time, as GitLab access tokens expire at the end of a day.
+func (b *backend) createGitLabToken(ctx context.Context, config *gitLabConfig, role *roleEntry, name string, expiration time.Time) (*gitLabToken, error) {
+	body := map[string]interface{}{
+		"name":         name,
+		"scopes":       role.Scopes,
+		"access_level": role.AccessLevel,
+		"expires_at":   expiration.UTC().AddDate(0, 0, 1).Format(time.DateOnly),
+	}
+
+	var token gitLabToken
+	header := http.Header{"Private-Token": []string{config.Token}}
+	if err := b.do(ctx, http.MethodPost, gitLabTokensURL(config.BaseURL, role), header, body, http.StatusCreated, &token); err != nil {
+		return nil, fmt.Errorf("failed to create GitLab access token: %w", err)
+	}
+	return &token, nil
+}
+
+// revokeGitLabToken revokes the access token of the given ID. Tokens already
+// gone count as revoked.
+func (b *backend) revokeGitLabToken(ctx context.Context, config *gitLabConfig, tokensURL, id string) error {
+	header := http.Header{"Private-Token": []string{config.Token}}
+	err := b.do(ctx, http.MethodDelete, tokensURL+"/"+url.PathEscape(id), header, nil, http.StatusNoContent, nil)
+	if e, ok := err.(*errStatus); ok && e.status == http.StatusNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to revoke GitLab access token: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,294 @@
+package scm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const (
+	configGitHubPath = "config/github"
+	configGitLabPath = "config/gitlab"
+
+	defaultGitHubBaseURL = "https://api.github.com"
+	defaultGitLabBaseURL = "https://gitlab.com"
+)
+
+type gitHubConfig struct {
+	AppID      int64  `json:"app_id"`
+	PrivateKey string `json:"private_key"`
+	BaseURL    string `json:"base_url"`
+}
+
+type gitLabConfig struct {
+	Token   string `json:"token"`
+	BaseURL string `json:"base_url"`
+}
+
+func pathConfigGitHub(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "config/github",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixSCM,
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"app_id": {
+				Type:        framework.TypeInt64,
+				Description: "ID of the GitHub App the installation tokens are created with.",
+			},
+			"private_key": {
+				Type:        framework.TypeString,
+				Description: "PEM-encoded private key of the GitHub App.",
+				DisplayAttrs: &framework.DisplayAttributes{
+					Sensitive: true,
+				},
+			},
+			"base_url": {
+				Type:        framework.TypeString,
+				Description: "Base URL of the GitHub API, for GitHub Enterprise Server. Defaults to https://api.github.com.",
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathConfigGitHubRead,
+				DisplayAttrs: &framework.DisplayAttributes{
+					OperationSuffix: "github-configuration",
+				},
+			},
+			logical.UpdateOperation: &framework.PathOperation{
+				Callback: b.pathConfigGitHubWrite,
+				DisplayAttrs: &framework.DisplayAttributes{
+					OperationVerb:   "configure",
+					OperationSuffix: "github",
+				},
+			},
+			logical.DeleteOperation: &framework.PathOperation{
+				Callback: b.pathConfigDelete(configGitHubPath),
+				DisplayAttrs: &framework.DisplayAttributes{
+					OperationSuffix: "github-configuration",
+				},
+			},
+		},
+
+		HelpSynopsis:    pathConfigGitHubHelpSyn,
+		HelpDescription: pathConfigGitHubHelpDesc,
+	}
+}
+
+func pathConfigGitLab(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "config/gitlab",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixSCM,
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"token": {
+				Type:        framework.TypeString,
+				Description: "GitLab token with the api scope, allowed to manage the access tokens of the projects and groups of the roles.",
+				DisplayAttrs: &framework.DisplayAttributes{
+					Sensitive: true,
+				},
+			},
+			"base_url": {
+				Type:        framework.TypeString,
+				Description: "Base URL of the GitLab instance. Defaults to https://gitlab.com.",
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathConfigGitLabRead,
+				DisplayAttrs: &framework.DisplayAttributes{
+					OperationSuffix: "gitlab-configuration",
+				},
+			},
+			logical.UpdateOperation: &framework.PathOperation{
+				Callback: b.pathConfigGitLabWrite,
+				DisplayAttrs: &framework.DisplayAttributes{
+					OperationVerb:   "configure",
+					OperationSuffix: "gitlab",
+				},
+			},
+			logical.DeleteOperation: &framework.PathOperation{
+				Callback: b.pathConfigDelete(configGitLabPath),
+				DisplayAttrs: &framework.DisplayAttributes{
+					OperationSuffix: "gitlab-configuration",
+				},
+			},
+		},
+
+		HelpSynopsis:    pathConfigGitLabHelpSyn,
+		HelpDescription: pathConfigGitLabHelpDesc,
+	}
+}
+
+func (b *backend) gitHubConfig(ctx context.Context, s logical.Storage) (*gitHubConfig, error) {
+	entry, err := s.Get(ctx, configGitHubPath)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var config gitHubConfig
+	if err := entry.DecodeJSON(&config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func (b *backend) gitLabConfig(ctx context.Context, s logical.Storage) (*gitLabConfig, error) {
+	entry, err := s.Get(ctx, configGitLabPath)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var config gitLabConfig
+	if err := entry.DecodeJSON(&config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+func (b *backend) pathConfigGitHubRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	config, err := b.gitHubConfig(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return nil, nil
+	}
+
+	// The private key is never returned
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"app_id":   config.AppID,
+			"base_url": config.BaseURL,
+		},
+	}, nil
+}
+
+func (b *backend) pathConfigGitHubWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	config, err := b.gitHubConfig(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		config = &gitHubConfig{
+			BaseURL: defaultGitHubBaseURL,
+		}
+	}
+
+	if raw, ok := d.GetOk("app_id"); ok {
+		config.AppID = raw.(int64)
+	}
+	if raw, ok := d.GetOk("private_key"); ok {
+		config.PrivateKey = raw.(string)
+	}
+	if raw, ok := d.GetOk("base_url"); ok {
+		config.BaseURL = strings.TrimSuffix(raw.(string), "/")
+	}
+
+	if config.AppID <= 0 {
+		return logical.ErrorResponse("app_id must be set"), logical.ErrInvalidRequest
+	}
+	if config.PrivateKey == "" {
+		return logical.ErrorResponse("private_key must be set"), logical.ErrInvalidRequest
+	}
+	if _, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(config.PrivateKey)); err != nil {
+		return logical.ErrorResponse(fmt.Sprintf("invalid private_key: %v", err)), logical.ErrInvalidRequest
+	}
+
+	entry, err := logical.StorageEntryJSON(configGitHubPath, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, req.Storage.Put(ctx, entry)
+}
+
+func (b *backend) pathConfigGitLabRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	config, err := b.gitLabConfig(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return nil, nil
+	}
+
+	// The token is never returned
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"base_url": config.BaseURL,
+		},
+	}, nil
+}
+
+func (b *backend) pathConfigGitLabWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	config, err := b.gitLabConfig(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		config = &gitLabConfig{
+			BaseURL: defaultGitLabBaseURL,
+		}
+	}
+
+	if raw, ok := d.GetOk("token"); ok {
+		config.Token = raw.(string)
+	}
+	if raw, ok := d.GetOk("base_url"); ok {
+		config.BaseURL = strings.TrimSuffix(raw.(string), "/")
+	}
+
+	if config.Token == "" {
+		return logical.ErrorResponse("token must be set"), logical.ErrInvalidRequest
+	}
+
+	entry, err := logical.StorageEntryJSON(configGitLabPath, config)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, req.Storage.Put(ctx, entry)
+}
+
+func (b *backend) pathConfigDelete(path string) framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+		return nil, req.Storage.Delete(ctx, path)
+	}
+}
+
+const pathConfigGitHubHelpSyn = `
+Configure the GitHub App installation tokens are created with.
+`
+
+const pathConfigGitHubHelpDesc = `
+The GitHub App must be installed on the accounts of the roles, with at least
+the permissions the roles grant: installation tokens can only be scoped down
+from the permissions of the installation.
+`
+
+const pathConfigGitLabHelpSyn = `
+Configure the GitLab token access tokens are created with.
+`
+
+const pathConfigGitLabHelpDesc = `
+The token needs the api scope, and must be allowed to manage the access
+tokens of the projects and groups of the roles, that is be owned by a user
+with at least the Maintainer role on them, or by the group itself.
+`
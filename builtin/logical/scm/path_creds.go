@@ -0,0 +1,143 @@
+package scm
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/base62"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+func pathCreds(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "creds/" + framework.GenericNameRegex("name"),
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixSCM,
+			OperationVerb:   "generate",
+			OperationSuffix: "token",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Name of the role.",
+			},
+		},
+
+		Operations: map[logical.Operation]framework.OperationHandler{
+			logical.ReadOperation: &framework.PathOperation{
+				Callback: b.pathCredsRead,
+			},
+		},
+
+		HelpSynopsis:    pathCredsHelpSyn,
+		HelpDescription: pathCredsHelpDesc,
+	}
+}
+
+func (b *backend) pathCredsRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := strings.ToLower(d.Get("name").(string))
+	role, err := b.role(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return logical.CodedErrorResponse(logical.ErrorCodeNotFound, "unknown role %q", name), logical.ErrInvalidRequest
+	}
+
+	switch role.Platform {
+	case platformGitHub:
+		return b.gitHubCreds(ctx, req, role)
+	case platformGitLab:
+		return b.gitLabCreds(ctx, req, name, role)
+	default:
+		return nil, fmt.Errorf("unsupported platform %q", role.Platform)
+	}
+}
+
+func (b *backend) gitHubCreds(ctx context.Context, req *logical.Request, role *roleEntry) (*logical.Response, error) {
+	config, err := b.gitHubConfig(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return logical.ErrorResponse("GitHub is not configured"), logical.ErrInvalidRequest
+	}
+
+	token, err := b.createGitHubToken(ctx, config, role)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := b.Secret(secretTokenType).Response(map[string]interface{}{
+		"platform":   platformGitHub,
+		"token":      token.Token,
+		"expires_at": token.ExpiresAt.Format(time.RFC3339),
+	}, map[string]interface{}{
+		"platform": platformGitHub,
+		"base_url": config.BaseURL,
+		"token":    token.Token,
+	})
+
+	// The lease cannot outlive the token
+	ttl := role.TTL
+	if untilExpiry := time.Until(token.ExpiresAt); untilExpiry < ttl {
+		ttl = untilExpiry
+	}
+	resp.Secret.TTL = ttl
+	resp.Secret.MaxTTL = ttl
+	resp.Secret.Renewable = false
+
+	return resp, nil
+}
+
+func (b *backend) gitLabCreds(ctx context.Context, req *logical.Request, roleName string, role *roleEntry) (*logical.Response, error) {
+	config, err := b.gitLabConfig(ctx, req.Storage)
+	if err != nil {
+		return nil, err
+	}
+	if config == nil {
+		return logical.ErrorResponse("GitLab is not configured"), logical.ErrInvalidRequest
+	}
+
+	suffix, err := base62.Random(8)
+	if err != nil {
+		return nil, err
+	}
+	tokenName := fmt.Sprintf("openbao-%s-%s", roleName, suffix)
+
+	token, err := b.createGitLabToken(ctx, config, role, tokenName, time.Now().Add(role.TTL))
+	if err != nil {
+		return nil, err
+	}
+
+	resp := b.Secret(secretTokenType).Response(map[string]interface{}{
+		"platform": platformGitLab,
+		"token":    token.Token,
+		"name":     tokenName,
+	}, map[string]interface{}{
+		"platform":   platformGitLab,
+		"tokens_url": gitLabTokensURL(config.BaseURL, role),
+		"token_id":   strconv.FormatInt(token.ID, 10),
+	})
+	resp.Secret.TTL = role.TTL
+	resp.Secret.MaxTTL = role.TTL
+	resp.Secret.Renewable = false
+
+	return resp, nil
+}
+
+const pathCredsHelpSyn = `
+Generate a short-lived token for a role.
+`
+
+const pathCredsHelpDesc = `
+This endpoint creates a GitHub installation token or a GitLab access token
+scoped by the role. The token is leased for the TTL of the role, cannot be
+renewed, and is revoked when its lease expires or is revoked.
+`
@@ -0,0 +1,372 @@
+package scm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const (
+	rolePrefix = "role/"
+
+	platformGitHub = "github"
+	platformGitLab = "gitlab"
+
+	defaultTokenTTL = time.Hour
+
+	// GitHub installation tokens expire after an hour
+	maxGitHubTokenTTL = time.Hour
+
+	// GitLab access tokens are revoked on lease expiry, their own expiry is a
+	// safety net
+	maxGitLabTokenTTL = 24 * time.Hour
+
+	// defaultGitLabAccessLevel is the Developer role
+	defaultGitLabAccessLevel = 30
+)
+
+// The access levels a GitLab access token can be given: Guest, Reporter,
+// Developer, Maintainer and Owner.
+var gitLabAccessLevels = []int{10, 20, 30, 40, 50}
+
+func pathRolesList(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "role/?",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixSCM,
+			OperationSuffix: "roles",
+			Navigation:      true,
+			ItemType:        "Role",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"after": {
+				Type:        framework.TypeString,
+				Description: `Optional entry to list begin listing after, not required to exist.`,
+			},
+			"limit": {
+				Type:        framework.TypeInt,
+				Description: `Optional number of entries to return; defaults to all entries.`,
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ListOperation: b.pathRoleList,
+		},
+
+		HelpSynopsis:    pathRoleHelpSyn,
+		HelpDescription: pathRoleHelpDesc,
+	}
+}
+
+func pathRoles(b *backend) *framework.Path {
+	return &framework.Path{
+		Pattern: "role/" + framework.GenericNameRegex("name"),
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixSCM,
+			OperationSuffix: "role",
+			Action:          "Create",
+			ItemType:        "Role",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Name of the role.",
+			},
+
+			"platform": {
+				Type:          framework.TypeString,
+				Description:   `Platform of the tokens: "github" or "gitlab". Cannot be changed once set.`,
+				AllowedValues: []interface{}{platformGitHub, platformGitLab},
+			},
+
+			"ttl": {
+				Type:        framework.TypeDurationSecond,
+				Description: "TTL of the tokens, at most 1h for GitHub and 24h for GitLab. Defaults to 1h.",
+			},
+
+			"installation_id": {
+				Type:        framework.TypeInt64,
+				Description: "GitHub only. ID of the installation of the GitHub App the tokens are created for.",
+			},
+
+			"repositories": {
+				Type:        framework.TypeCommaStringSlice,
+				Description: "GitHub only. Names of the repositories the tokens can access. If unset, the tokens can access all the repositories of the installation.",
+			},
+
+			"permissions": {
+				Type:        framework.TypeKVPairs,
+				Description: `GitHub only. Permissions of the tokens, such as contents=read. If unset, the tokens have all the permissions of the installation.`,
+			},
+
+			"project": {
+				Type:        framework.TypeString,
+				Description: "GitLab only. ID or full path of the project the tokens are project access tokens of. Exclusive with group.",
+			},
+
+			"group": {
+				Type:        framework.TypeString,
+				Description: "GitLab only. ID or full path of the group the tokens are group access tokens of. Exclusive with project.",
+			},
+
+			"scopes": {
+				Type:        framework.TypeCommaStringSlice,
+				Description: "GitLab only. Scopes of the tokens, such as read_repository.",
+			},
+
+			"access_level": {
+				Type:        framework.TypeInt,
+				Description: "GitLab only. Access level of the tokens: 10 (Guest), 20 (Reporter), 30 (Developer), 40 (Maintainer) or 50 (Owner). Defaults to 30.",
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.DeleteOperation: b.pathRoleDelete,
+			logical.ReadOperation:   b.pathRoleRead,
+			logical.UpdateOperation: b.pathRoleWrite,
+			logical.CreateOperation: b.pathRoleWrite,
+		},
+
+		ExistenceCheck: b.roleExistenceCheck,
+
+		HelpSynopsis:    pathRoleHelpSyn,
+		HelpDescription: pathRoleHelpDesc,
+	}
+}
+
+type roleEntry struct {
+	Platform string        `json:"platform"`
+	TTL      time.Duration `json:"ttl"`
+
+	InstallationID int64             `json:"installation_id,omitempty"`
+	Repositories   []string          `json:"repositories,omitempty"`
+	Permissions    map[string]string `json:"permissions,omitempty"`
+
+	Project     string   `json:"project,omitempty"`
+	Group       string   `json:"group,omitempty"`
+	Scopes      []string `json:"scopes,omitempty"`
+	AccessLevel int      `json:"access_level,omitempty"`
+}
+
+func (b *backend) roleExistenceCheck(ctx context.Context, req *logical.Request, d *framework.FieldData) (bool, error) {
+	role, err := b.role(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return false, err
+	}
+
+	return role != nil, nil
+}
+
+func (b *backend) role(ctx context.Context, s logical.Storage, name string) (*roleEntry, error) {
+	if name == "" {
+		return nil, errors.New("missing role name")
+	}
+
+	entry, err := s.Get(ctx, rolePrefix+strings.ToLower(name))
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var result roleEntry
+	if err := entry.DecodeJSON(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+func (b *backend) pathRoleList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	after := d.Get("after").(string)
+	limit := d.Get("limit").(int)
+	if limit <= 0 {
+		limit = -1
+	}
+
+	roles, err := req.Storage.ListPage(ctx, rolePrefix, after, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return logical.ListResponse(roles), nil
+}
+
+func (b *backend) pathRoleDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if err := req.Storage.Delete(ctx, rolePrefix+strings.ToLower(d.Get("name").(string))); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (b *backend) pathRoleRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	role, err := b.role(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, nil
+	}
+
+	data := map[string]interface{}{
+		"platform": role.Platform,
+		"ttl":      int64(role.TTL.Seconds()),
+	}
+	switch role.Platform {
+	case platformGitHub:
+		data["installation_id"] = role.InstallationID
+		data["repositories"] = role.Repositories
+		data["permissions"] = role.Permissions
+	case platformGitLab:
+		data["project"] = role.Project
+		data["group"] = role.Group
+		data["scopes"] = role.Scopes
+		data["access_level"] = role.AccessLevel
+	}
+
+	return &logical.Response{
+		Data: data,
+	}, nil
+}
+
+func (b *backend) pathRoleWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := strings.ToLower(d.Get("name").(string))
+	role, err := b.role(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+
+	platform := d.Get("platform").(string)
+	// Due to existence check, role will only be nil if it's a create operation
+	if role == nil {
+		if platform == "" {
+			return logical.ErrorResponse("platform must be set"), logical.ErrInvalidRequest
+		}
+		role = &roleEntry{
+			Platform: platform,
+			TTL:      defaultTokenTTL,
+		}
+		if platform == platformGitLab {
+			role.AccessLevel = defaultGitLabAccessLevel
+		}
+	} else if platform != "" && platform != role.Platform {
+		return logical.ErrorResponse("platform cannot be changed"), logical.ErrInvalidRequest
+	}
+
+	if raw, ok := d.GetOk("ttl"); ok {
+		role.TTL = time.Duration(raw.(int)) * time.Second
+	}
+	if role.TTL <= 0 {
+		return logical.ErrorResponse("ttl must be positive"), logical.ErrInvalidRequest
+	}
+
+	gitHubFields := []string{"installation_id", "repositories", "permissions"}
+	gitLabFields := []string{"project", "group", "scopes", "access_level"}
+
+	switch role.Platform {
+	case platformGitHub:
+		if resp := rejectFields(d, gitLabFields, platformGitHub); resp != nil {
+			return resp, logical.ErrInvalidRequest
+		}
+
+		if raw, ok := d.GetOk("installation_id"); ok {
+			role.InstallationID = raw.(int64)
+		}
+		if raw, ok := d.GetOk("repositories"); ok {
+			role.Repositories = raw.([]string)
+		}
+		if raw, ok := d.GetOk("permissions"); ok {
+			role.Permissions = raw.(map[string]string)
+		}
+
+		if role.InstallationID <= 0 {
+			return logical.ErrorResponse("installation_id must be set"), logical.ErrInvalidRequest
+		}
+		if role.TTL > maxGitHubTokenTTL {
+			return logical.ErrorResponse(fmt.Sprintf("ttl cannot exceed %s, the lifetime of GitHub installation tokens", maxGitHubTokenTTL)), logical.ErrInvalidRequest
+		}
+		for permission, access := range role.Permissions {
+			if access != "read" && access != "write" && access != "admin" {
+				return logical.ErrorResponse(fmt.Sprintf("invalid access %q for permission %q, must be read, write or admin", access, permission)), logical.ErrInvalidRequest
+			}
+		}
+
+	case platformGitLab:
+		if resp := rejectFields(d, gitHubFields, platformGitLab); resp != nil {
+			return resp, logical.ErrInvalidRequest
+		}
+
+		if raw, ok := d.GetOk("project"); ok {
+			role.Project = raw.(string)
+		}
+		if raw, ok := d.GetOk("group"); ok {
+			role.Group = raw.(string)
+		}
+		if raw, ok := d.GetOk("scopes"); ok {
+			role.Scopes = raw.([]string)
+		}
+		if raw, ok := d.GetOk("access_level"); ok {
+			role.AccessLevel = raw.(int)
+		}
+
+		if (role.Project == "") == (role.Group == "") {
+			return logical.ErrorResponse("exactly one of project and group must be set"), logical.ErrInvalidRequest
+		}
+		if len(role.Scopes) == 0 {
+			return logical.ErrorResponse("scopes must be set"), logical.ErrInvalidRequest
+		}
+		validLevel := false
+		for _, level := range gitLabAccessLevels {
+			validLevel = validLevel || level == role.AccessLevel
+		}
+		if !validLevel {
+			return logical.ErrorResponse(fmt.Sprintf("invalid access_level %d", role.AccessLevel)), logical.ErrInvalidRequest
+		}
+		if role.TTL > maxGitLabTokenTTL {
+			return logical.ErrorResponse(fmt.Sprintf("ttl cannot exceed %s", maxGitLabTokenTTL)), logical.ErrInvalidRequest
+		}
+
+	default:
+		return logical.ErrorResponse(fmt.Sprintf("unsupported platform %q", role.Platform)), logical.ErrInvalidRequest
+	}
+
+	entry, err := logical.StorageEntryJSON(rolePrefix+name, role)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, req.Storage.Put(ctx, entry)
+}
+
+// rejectFields returns an error response if any of the fields, which do not
+// apply to the platform, is set.
+func rejectFields(d *framework.FieldData, fields []string, platform string) *logical.Response {
+	for _, field := range fields {
+		if _, ok := d.GetOk(field); ok {
+			return logical.ErrorResponse(fmt.Sprintf("%s does not apply to %s roles", field, platform))
+		}
+	}
+	return nil
+}
+
+const pathRoleHelpSyn = `
+Manage the roles tokens are created for.
+`
+
+const pathRoleHelpDesc = `
+This endpoint allows you to create, read, update, and delete roles. A role
+sets the platform and scope of the tokens read from creds/<role>: the
+installation, repositories and permissions of GitHub installation tokens,
+or the project or group, scopes and access level of GitLab access tokens.
+`
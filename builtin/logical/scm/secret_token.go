@@ -0,0 +1,59 @@
+package scm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+// secretTokenType is the type of the GitHub and GitLab tokens leased by the
+// backend.
+const secretTokenType = "token"
+
+func secretToken(b *backend) *framework.Secret {
+	return &framework.Secret{
+		Type: secretTokenType,
+		Fields: map[string]*framework.FieldSchema{
+			"token": {
+				Type:        framework.TypeString,
+				Description: "GitHub installation token or GitLab access token",
+			},
+		},
+		Revoke: b.secretTokenRevoke,
+	}
+}
+
+func (b *backend) secretTokenRevoke(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	platform, _ := req.Secret.InternalData["platform"].(string)
+	switch platform {
+	case platformGitHub:
+		baseURL, _ := req.Secret.InternalData["base_url"].(string)
+		token, _ := req.Secret.InternalData["token"].(string)
+		if baseURL == "" || token == "" {
+			return nil, errors.New("secret is missing GitHub token internal data")
+		}
+		return nil, b.revokeGitHubToken(ctx, baseURL, token)
+
+	case platformGitLab:
+		tokensURL, _ := req.Secret.InternalData["tokens_url"].(string)
+		id, _ := req.Secret.InternalData["token_id"].(string)
+		if tokensURL == "" || id == "" {
+			return nil, errors.New("secret is missing GitLab token internal data")
+		}
+
+		config, err := b.gitLabConfig(ctx, req.Storage)
+		if err != nil {
+			return nil, err
+		}
+		if config == nil {
+			return nil, errors.New("GitLab is no longer configured, cannot revoke the token")
+		}
+		return nil, b.revokeGitLabToken(ctx, config, tokensURL, id)
+
+	default:
+		return nil, fmt.Errorf("unsupported platform %q", platform)
+	}
+}
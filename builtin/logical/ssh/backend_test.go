@@ -2820,3 +2820,71 @@ func TestProperAuthing(t *testing.T) {
 		t.Fatalf("Expected to have validated at least one path.")
 	}
 }
+
+func TestBackend_DefCriticalOptionsTemplatingEnabled(t *testing.T) {
+	cluster, userpassToken := getSshCaTestCluster(t, testUserName)
+	defer cluster.Cleanup()
+	client := cluster.Cores[0].Client
+	rootToken := client.Token()
+
+	// Get auth accessor for identity template.
+	auths, err := client.Sys().ListAuth()
+	if err != nil {
+		t.Fatal(err)
+	}
+	userpassAccessor := auths["userpass/"].Accessor
+
+	// Write SSH role.
+	_, err = client.Logical().Write("ssh/roles/test", map[string]interface{}{
+		"key_type":                          "ca",
+		"allow_user_certificates":           true,
+		"allowed_users":                     "tuber",
+		"default_user":                      "tuber",
+		"default_critical_options_template": true,
+		"default_critical_options": map[string]interface{}{
+			"force-command":  "/usr/bin/bastion --user {{identity.entity.aliases." + userpassAccessor + ".name}}",
+			"source-address": "10.0.0.0/8",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sshKeyID := "vault-userpass-" + testUserName + "-9bd0f01b7dfc50a13aa5e5cd11aea19276968755c8f1f9c98965d04147f30ed0"
+
+	// Issue SSH certificate with default critical options templating enabled
+	client.SetToken(userpassToken)
+	resp, err := client.Logical().Write("ssh/sign/test", map[string]interface{}{
+		"public_key": publicKey4096,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	signedKey := resp.Data["signed_key"].(string)
+	key, _ := base64.StdEncoding.DecodeString(strings.Split(signedKey, " ")[1])
+
+	parsedKey, err := ssh.ParsePublicKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	criticalOptions := map[string]string{
+		"force-command":  "/usr/bin/bastion --user " + testUserName,
+		"source-address": "10.0.0.0/8",
+	}
+
+	err = validateSSHCertificate(parsedKey.(*ssh.Certificate), sshKeyID, ssh.UserCert, []string{"tuber"}, criticalOptions, map[string]string{}, 16*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Without identity entity information, the templated critical option
+	// cannot be rendered and signing must fail rather than skip it.
+	client.SetToken(rootToken)
+	_, err = client.Logical().Write("ssh/sign/test", map[string]interface{}{
+		"public_key": publicKey4096,
+	})
+	if err == nil {
+		t.Fatal("expected an error while attempting to sign a key without identity entity information")
+	}
+}
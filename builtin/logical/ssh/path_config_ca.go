@@ -245,6 +245,14 @@ func (b *backend) pathConfigCAUpdate(ctx context.Context, req *logical.Request,
 		return nil, fmt.Errorf("failed to generate or parse the keys")
 	}
 
+	parsedPublicKey, err := parsePublicSSHKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the public key: %w", err)
+	}
+	if err := b.checkRestrictedCrypto(parsedPublicKey); err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+
 	publicKeyEntry, err := caKey(ctx, req.Storage, caPublicKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read CA public key: %w", err)
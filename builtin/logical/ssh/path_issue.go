@@ -91,7 +91,7 @@ func (b *backend) pathIssue(ctx context.Context, req *logical.Request, data *fra
 		return nil, err
 	}
 	if role == nil {
-		return logical.ErrorResponse(fmt.Sprintf("unknown role: %s", roleName)), nil
+		return logical.CodedErrorResponse(logical.ErrorCodeNotFound, "unknown role: %s", roleName), nil
 	}
 
 	if role.KeyType != "ca" {
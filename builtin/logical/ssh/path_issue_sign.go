@@ -54,6 +54,14 @@ type creationBundle struct {
 }
 
 func (b *backend) pathSignIssueCertificateHelper(ctx context.Context, req *logical.Request, data *framework.FieldData, role *sshRole, publicKey ssh.PublicKey) (*logical.Response, error) {
+	// SHA-1 signatures and DSA keys are disabled by default since OpenSSH 8.8
+	if role.AlgorithmSigner == ssh.SigAlgoRSA {
+		logical.RecordDeprecatedFeature(ctx, logical.DeprecatedKeyType, ssh.SigAlgoRSA)
+	}
+	if publicKey.Type() == ssh.KeyAlgoDSA {
+		logical.RecordDeprecatedFeature(ctx, logical.DeprecatedKeyType, ssh.KeyAlgoDSA)
+	}
+
 	// Note that these various functions always return "user errors" so we pass
 	// them as 4xx values
 	keyID, err := b.calculateKeyID(data, req, role, publicKey)
@@ -91,7 +99,7 @@ func (b *backend) pathSignIssueCertificateHelper(ctx context.Context, req *logic
 		return logical.ErrorResponse(err.Error()), nil
 	}
 
-	criticalOptions, err := b.calculateCriticalOptions(data, role)
+	criticalOptions, err := b.calculateCriticalOptions(data, req, role)
 	if err != nil {
 		return logical.ErrorResponse(err.Error()), nil
 	}
@@ -114,6 +122,13 @@ func (b *backend) pathSignIssueCertificateHelper(ctx context.Context, req *logic
 		return nil, fmt.Errorf("failed to parse stored CA private key: %w", err)
 	}
 
+	if err := b.checkRestrictedCrypto(signer.PublicKey()); err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+	if err := b.checkRestrictedCrypto(publicKey); err != nil {
+		return logical.ErrorResponse(err.Error()), nil
+	}
+
 	cBundle := creationBundle{
 		KeyID:           keyID,
 		PublicKey:       publicKey,
@@ -281,10 +296,24 @@ func (b *backend) calculateKeyID(data *framework.FieldData, req *logical.Request
 	return keyID, nil
 }
 
-func (b *backend) calculateCriticalOptions(data *framework.FieldData, role *sshRole) (map[string]string, error) {
+func (b *backend) calculateCriticalOptions(data *framework.FieldData, req *logical.Request, role *sshRole) (map[string]string, error) {
 	unparsedCriticalOptions := data.Get("critical_options").(map[string]interface{})
 	if len(unparsedCriticalOptions) == 0 {
-		return role.DefaultCriticalOptions, nil
+		if !role.DefaultCriticalOptionsTemplate {
+			return role.DefaultCriticalOptions, nil
+		}
+
+		// Critical options restrict the use of the certificate, so unlike
+		// extensions, they are never skipped: a certificate without the
+		// restriction of a templated option would grant more than intended.
+		criticalOptions, missingEntity, err := b.renderDefaultTemplates(req, role.DefaultCriticalOptions)
+		if err != nil {
+			return nil, err
+		}
+		if missingEntity {
+			return nil, fmt.Errorf("default_critical_options templating enabled with at least one critical option requiring identity templating, but the request lacked identity entity information")
+		}
+		return criticalOptions, nil
 	}
 
 	criticalOptions := convertMapToStringValue(unparsedCriticalOptions)
@@ -309,8 +338,6 @@ func (b *backend) calculateCriticalOptions(data *framework.FieldData, role *sshR
 
 func (b *backend) calculateExtensions(data *framework.FieldData, req *logical.Request, role *sshRole) (map[string]string, bool, error) {
 	unparsedExtensions := data.Get("extensions").(map[string]interface{})
-	extensions := make(map[string]string)
-
 	if len(unparsedExtensions) > 0 {
 		extensions := convertMapToStringValue(unparsedExtensions)
 		if role.AllowedExtensions == "*" {
@@ -332,35 +359,44 @@ func (b *backend) calculateExtensions(data *framework.FieldData, req *logical.Re
 		return extensions, false, nil
 	}
 
-	haveMissingEntityInfoWithTemplatedExt := false
-
-	if role.DefaultExtensionsTemplate {
-		for extensionKey, extensionValue := range role.DefaultExtensions {
-			// Look for templating markers {{ .* }}
-			matched := containsTemplateRegex.MatchString(extensionValue)
-			if matched {
-				if req.EntityID != "" {
-					// Retrieve extension value based on template + entityID from request.
-					templateExtensionValue, err := framework.PopulateIdentityTemplate(extensionValue, req.EntityID, b.System())
-					if err == nil {
-						// Template returned an extension value that we can use
-						extensions[extensionKey] = templateExtensionValue
-					} else {
-						return nil, false, fmt.Errorf("template '%s' could not be rendered -> %s", extensionValue, err)
-					}
+	if !role.DefaultExtensionsTemplate {
+		return role.DefaultExtensions, false, nil
+	}
+
+	return b.renderDefaultTemplates(req, role.DefaultExtensions)
+}
+
+// renderDefaultTemplates renders the identity templates of the given default
+// extensions or critical options with the entity of the request. Templated
+// values are skipped if the request has no entity, in which case the returned
+// boolean is true.
+func (b *backend) renderDefaultTemplates(req *logical.Request, defaults map[string]string) (map[string]string, bool, error) {
+	rendered := make(map[string]string)
+	haveMissingEntityInfoWithTemplate := false
+
+	for key, value := range defaults {
+		// Look for templating markers {{ .* }}
+		matched := containsTemplateRegex.MatchString(value)
+		if matched {
+			if req.EntityID != "" {
+				// Retrieve value based on template + entityID from request.
+				templateValue, err := framework.PopulateIdentityTemplate(value, req.EntityID, b.System())
+				if err == nil {
+					// Template returned a value that we can use
+					rendered[key] = templateValue
 				} else {
-					haveMissingEntityInfoWithTemplatedExt = true
+					return nil, false, fmt.Errorf("template '%s' could not be rendered -> %s", value, err)
 				}
 			} else {
-				// Static extension value or err template
-				extensions[extensionKey] = extensionValue
+				haveMissingEntityInfoWithTemplate = true
 			}
+		} else {
+			// Static value or err template
+			rendered[key] = value
 		}
-	} else {
-		extensions = role.DefaultExtensions
 	}
 
-	return extensions, haveMissingEntityInfoWithTemplatedExt, nil
+	return rendered, haveMissingEntityInfoWithTemplate, nil
 }
 
 func (b *backend) calculateTTL(data *framework.FieldData, role *sshRole) (time.Duration, error) {
@@ -38,35 +38,36 @@ const (
 // for both OTP and CA roles. Not all the fields are mandatory for both type.
 // Some are applicable for one and not for other. It doesn't matter.
 type sshRole struct {
-	KeyType                    string            `mapstructure:"key_type" json:"key_type"`
-	DefaultUser                string            `mapstructure:"default_user" json:"default_user"`
-	DefaultUserTemplate        bool              `mapstructure:"default_user_template" json:"default_user_template"`
-	CIDRList                   string            `mapstructure:"cidr_list" json:"cidr_list"`
-	ExcludeCIDRList            string            `mapstructure:"exclude_cidr_list" json:"exclude_cidr_list"`
-	Port                       int               `mapstructure:"port" json:"port"`
-	AllowedUsers               string            `mapstructure:"allowed_users" json:"allowed_users"`
-	AllowedUsersTemplate       bool              `mapstructure:"allowed_users_template" json:"allowed_users_template"`
-	AllowedDomains             string            `mapstructure:"allowed_domains" json:"allowed_domains"`
-	AllowedDomainsTemplate     bool              `mapstructure:"allowed_domains_template" json:"allowed_domains_template"`
-	MaxTTL                     string            `mapstructure:"max_ttl" json:"max_ttl"`
-	TTL                        string            `mapstructure:"ttl" json:"ttl"`
-	DefaultCriticalOptions     map[string]string `mapstructure:"default_critical_options" json:"default_critical_options"`
-	DefaultExtensions          map[string]string `mapstructure:"default_extensions" json:"default_extensions"`
-	DefaultExtensionsTemplate  bool              `mapstructure:"default_extensions_template" json:"default_extensions_template"`
-	AllowedCriticalOptions     string            `mapstructure:"allowed_critical_options" json:"allowed_critical_options"`
-	AllowedExtensions          string            `mapstructure:"allowed_extensions" json:"allowed_extensions"`
-	AllowUserCertificates      bool              `mapstructure:"allow_user_certificates" json:"allow_user_certificates"`
-	AllowHostCertificates      bool              `mapstructure:"allow_host_certificates" json:"allow_host_certificates"`
-	AllowBareDomains           bool              `mapstructure:"allow_bare_domains" json:"allow_bare_domains"`
-	AllowSubdomains            bool              `mapstructure:"allow_subdomains" json:"allow_subdomains"`
-	AllowUserKeyIDs            bool              `mapstructure:"allow_user_key_ids" json:"allow_user_key_ids"`
-	AllowEmptyPrincipals       bool              `mapstructure:"allow_empty_principals" json:"allow_empty_principals"`
-	KeyIDFormat                string            `mapstructure:"key_id_format" json:"key_id_format"`
-	OldAllowedUserKeyLengths   map[string]int    `mapstructure:"allowed_user_key_lengths" json:"allowed_user_key_lengths,omitempty"`
-	AllowedUserKeyTypesLengths map[string][]int  `mapstructure:"allowed_user_key_types_lengths" json:"allowed_user_key_types_lengths"`
-	AlgorithmSigner            string            `mapstructure:"algorithm_signer" json:"algorithm_signer"`
-	Version                    int               `mapstructure:"role_version" json:"role_version"`
-	NotBeforeDuration          time.Duration     `mapstructure:"not_before_duration" json:"not_before_duration"`
+	KeyType                        string            `mapstructure:"key_type" json:"key_type"`
+	DefaultUser                    string            `mapstructure:"default_user" json:"default_user"`
+	DefaultUserTemplate            bool              `mapstructure:"default_user_template" json:"default_user_template"`
+	CIDRList                       string            `mapstructure:"cidr_list" json:"cidr_list"`
+	ExcludeCIDRList                string            `mapstructure:"exclude_cidr_list" json:"exclude_cidr_list"`
+	Port                           int               `mapstructure:"port" json:"port"`
+	AllowedUsers                   string            `mapstructure:"allowed_users" json:"allowed_users"`
+	AllowedUsersTemplate           bool              `mapstructure:"allowed_users_template" json:"allowed_users_template"`
+	AllowedDomains                 string            `mapstructure:"allowed_domains" json:"allowed_domains"`
+	AllowedDomainsTemplate         bool              `mapstructure:"allowed_domains_template" json:"allowed_domains_template"`
+	MaxTTL                         string            `mapstructure:"max_ttl" json:"max_ttl"`
+	TTL                            string            `mapstructure:"ttl" json:"ttl"`
+	DefaultCriticalOptions         map[string]string `mapstructure:"default_critical_options" json:"default_critical_options"`
+	DefaultExtensions              map[string]string `mapstructure:"default_extensions" json:"default_extensions"`
+	DefaultExtensionsTemplate      bool              `mapstructure:"default_extensions_template" json:"default_extensions_template"`
+	DefaultCriticalOptionsTemplate bool              `mapstructure:"default_critical_options_template" json:"default_critical_options_template"`
+	AllowedCriticalOptions         string            `mapstructure:"allowed_critical_options" json:"allowed_critical_options"`
+	AllowedExtensions              string            `mapstructure:"allowed_extensions" json:"allowed_extensions"`
+	AllowUserCertificates          bool              `mapstructure:"allow_user_certificates" json:"allow_user_certificates"`
+	AllowHostCertificates          bool              `mapstructure:"allow_host_certificates" json:"allow_host_certificates"`
+	AllowBareDomains               bool              `mapstructure:"allow_bare_domains" json:"allow_bare_domains"`
+	AllowSubdomains                bool              `mapstructure:"allow_subdomains" json:"allow_subdomains"`
+	AllowUserKeyIDs                bool              `mapstructure:"allow_user_key_ids" json:"allow_user_key_ids"`
+	AllowEmptyPrincipals           bool              `mapstructure:"allow_empty_principals" json:"allow_empty_principals"`
+	KeyIDFormat                    string            `mapstructure:"key_id_format" json:"key_id_format"`
+	OldAllowedUserKeyLengths       map[string]int    `mapstructure:"allowed_user_key_lengths" json:"allowed_user_key_lengths,omitempty"`
+	AllowedUserKeyTypesLengths     map[string][]int  `mapstructure:"allowed_user_key_types_lengths" json:"allowed_user_key_types_lengths"`
+	AlgorithmSigner                string            `mapstructure:"algorithm_signer" json:"algorithm_signer"`
+	Version                        int               `mapstructure:"role_version" json:"role_version"`
+	NotBeforeDuration              time.Duration     `mapstructure:"not_before_duration" json:"not_before_duration"`
 }
 
 func pathListRoles(b *backend) *framework.Path {
@@ -269,6 +270,18 @@ func pathRoles(b *backend) *framework.Path {
 				by "allowed_critical_options". Defaults to none.
 				`,
 			},
+			"default_critical_options_template": {
+				Type: framework.TypeBool,
+				Description: `
+				[Not applicable for OTP type] [Optional for CA type]
+				If set, Default critical option values can be specified using identity template policies,
+				e.g. a force-command or source-address taken from entity metadata.
+				Non-templated critical option values are also permitted. Requests
+				without identity entity information are refused if a critical option
+				requires identity templating.
+				`,
+				Default: false,
+			},
 			"default_extensions": {
 				Type: framework.TypeMap,
 				Description: `
@@ -509,26 +522,27 @@ func (b *backend) createCARole(allowedUsers, defaultUser, signer string, data *f
 	ttl := time.Duration(data.Get("ttl").(int)) * time.Second
 	maxTTL := time.Duration(data.Get("max_ttl").(int)) * time.Second
 	role := &sshRole{
-		AllowedCriticalOptions:    data.Get("allowed_critical_options").(string),
-		AllowedExtensions:         data.Get("allowed_extensions").(string),
-		AllowUserCertificates:     data.Get("allow_user_certificates").(bool),
-		AllowHostCertificates:     data.Get("allow_host_certificates").(bool),
-		AllowedUsers:              allowedUsers,
-		AllowedUsersTemplate:      data.Get("allowed_users_template").(bool),
-		AllowedDomains:            data.Get("allowed_domains").(string),
-		AllowedDomainsTemplate:    data.Get("allowed_domains_template").(bool),
-		DefaultUser:               defaultUser,
-		DefaultUserTemplate:       data.Get("default_user_template").(bool),
-		AllowBareDomains:          data.Get("allow_bare_domains").(bool),
-		AllowSubdomains:           data.Get("allow_subdomains").(bool),
-		AllowUserKeyIDs:           data.Get("allow_user_key_ids").(bool),
-		AllowEmptyPrincipals:      data.Get("allow_empty_principals").(bool),
-		DefaultExtensionsTemplate: data.Get("default_extensions_template").(bool),
-		KeyIDFormat:               data.Get("key_id_format").(string),
-		KeyType:                   KeyTypeCA,
-		AlgorithmSigner:           signer,
-		Version:                   roleEntryVersion,
-		NotBeforeDuration:         time.Duration(data.Get("not_before_duration").(int)) * time.Second,
+		AllowedCriticalOptions:         data.Get("allowed_critical_options").(string),
+		AllowedExtensions:              data.Get("allowed_extensions").(string),
+		AllowUserCertificates:          data.Get("allow_user_certificates").(bool),
+		AllowHostCertificates:          data.Get("allow_host_certificates").(bool),
+		AllowedUsers:                   allowedUsers,
+		AllowedUsersTemplate:           data.Get("allowed_users_template").(bool),
+		AllowedDomains:                 data.Get("allowed_domains").(string),
+		AllowedDomainsTemplate:         data.Get("allowed_domains_template").(bool),
+		DefaultUser:                    defaultUser,
+		DefaultUserTemplate:            data.Get("default_user_template").(bool),
+		AllowBareDomains:               data.Get("allow_bare_domains").(bool),
+		AllowSubdomains:                data.Get("allow_subdomains").(bool),
+		AllowUserKeyIDs:                data.Get("allow_user_key_ids").(bool),
+		AllowEmptyPrincipals:           data.Get("allow_empty_principals").(bool),
+		DefaultExtensionsTemplate:      data.Get("default_extensions_template").(bool),
+		DefaultCriticalOptionsTemplate: data.Get("default_critical_options_template").(bool),
+		KeyIDFormat:                    data.Get("key_id_format").(string),
+		KeyType:                        KeyTypeCA,
+		AlgorithmSigner:                signer,
+		Version:                        roleEntryVersion,
+		NotBeforeDuration:              time.Duration(data.Get("not_before_duration").(int)) * time.Second,
 	}
 
 	if !role.AllowUserCertificates && !role.AllowHostCertificates {
@@ -698,30 +712,31 @@ func (b *backend) parseRole(role *sshRole) (map[string]interface{}, error) {
 		}
 
 		result = map[string]interface{}{
-			"allowed_users":               role.AllowedUsers,
-			"allowed_users_template":      role.AllowedUsersTemplate,
-			"allowed_domains":             role.AllowedDomains,
-			"allowed_domains_template":    role.AllowedDomainsTemplate,
-			"default_user":                role.DefaultUser,
-			"default_user_template":       role.DefaultUserTemplate,
-			"ttl":                         int64(ttl.Seconds()),
-			"max_ttl":                     int64(maxTTL.Seconds()),
-			"allowed_critical_options":    role.AllowedCriticalOptions,
-			"allowed_extensions":          role.AllowedExtensions,
-			"allow_user_certificates":     role.AllowUserCertificates,
-			"allow_host_certificates":     role.AllowHostCertificates,
-			"allow_bare_domains":          role.AllowBareDomains,
-			"allow_subdomains":            role.AllowSubdomains,
-			"allow_user_key_ids":          role.AllowUserKeyIDs,
-			"allow_empty_principals":      role.AllowEmptyPrincipals,
-			"key_id_format":               role.KeyIDFormat,
-			"key_type":                    role.KeyType,
-			"default_critical_options":    role.DefaultCriticalOptions,
-			"default_extensions":          role.DefaultExtensions,
-			"default_extensions_template": role.DefaultExtensionsTemplate,
-			"allowed_user_key_lengths":    role.AllowedUserKeyTypesLengths,
-			"algorithm_signer":            role.AlgorithmSigner,
-			"not_before_duration":         int64(role.NotBeforeDuration.Seconds()),
+			"allowed_users":                     role.AllowedUsers,
+			"allowed_users_template":            role.AllowedUsersTemplate,
+			"allowed_domains":                   role.AllowedDomains,
+			"allowed_domains_template":          role.AllowedDomainsTemplate,
+			"default_user":                      role.DefaultUser,
+			"default_user_template":             role.DefaultUserTemplate,
+			"ttl":                               int64(ttl.Seconds()),
+			"max_ttl":                           int64(maxTTL.Seconds()),
+			"allowed_critical_options":          role.AllowedCriticalOptions,
+			"allowed_extensions":                role.AllowedExtensions,
+			"allow_user_certificates":           role.AllowUserCertificates,
+			"allow_host_certificates":           role.AllowHostCertificates,
+			"allow_bare_domains":                role.AllowBareDomains,
+			"allow_subdomains":                  role.AllowSubdomains,
+			"allow_user_key_ids":                role.AllowUserKeyIDs,
+			"allow_empty_principals":            role.AllowEmptyPrincipals,
+			"key_id_format":                     role.KeyIDFormat,
+			"key_type":                          role.KeyType,
+			"default_critical_options":          role.DefaultCriticalOptions,
+			"default_critical_options_template": role.DefaultCriticalOptionsTemplate,
+			"default_extensions":                role.DefaultExtensions,
+			"default_extensions_template":       role.DefaultExtensionsTemplate,
+			"allowed_user_key_lengths":          role.AllowedUserKeyTypesLengths,
+			"algorithm_signer":                  role.AlgorithmSigner,
+			"not_before_duration":               int64(role.NotBeforeDuration.Seconds()),
 		}
 	case KeyTypeDynamic:
 		return nil, fmt.Errorf("dynamic key type roles are no longer supported")
@@ -104,6 +104,15 @@ func parsePublicSSHKey(key string) (ssh.PublicKey, error) {
 	return ssh.ParsePublicKey([]byte(decodedKey))
 }
 
+// checkRestrictedCrypto refuses keys of algorithms which are not approved when
+// the server runs in restricted crypto mode.
+func (b *backend) checkRestrictedCrypto(key ssh.PublicKey) error {
+	if b.Backend.RestrictedCrypto() && key.Type() == ssh.KeyAlgoED25519 {
+		return fmt.Errorf("key type %s is not allowed in restricted crypto mode", key.Type())
+	}
+	return nil
+}
+
 func convertMapToStringValue(initial map[string]interface{}) map[string]string {
 	result := map[string]string{}
 	for key, value := range initial {
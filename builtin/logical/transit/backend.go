@@ -45,9 +45,13 @@ func Backend(ctx context.Context, conf *logical.BackendConfig) (*backend, error)
 				"archive/",
 				"policy/",
 			},
+			Unauthenticated: []string{
+				"jwks",
+				"jwks/*",
+			},
 		},
 
-		Paths: []*framework.Path{
+		Paths: framework.PathAppend([]*framework.Path{
 			// Rotate/Config needs to come before Keys
 			// as the handler is greedy
 			b.pathRotate(),
@@ -62,9 +66,14 @@ func Backend(ctx context.Context, conf *logical.BackendConfig) (*backend, error)
 			b.pathBYOKExportKeys(),
 			b.pathExportKeys(),
 			b.pathKeysConfig(),
+			b.pathListSealClients(),
+			b.pathSealClients(),
+			b.pathListKeyAliases(),
+			b.pathKeyAliases(),
 			b.pathEncrypt(),
 			b.pathDecrypt(),
 			b.pathDatakey(),
+			b.pathHKDF(),
 			b.pathRandom(),
 			b.pathHash(),
 			b.pathHMAC(),
@@ -75,7 +84,7 @@ func Backend(ctx context.Context, conf *logical.BackendConfig) (*backend, error)
 			b.pathTrim(),
 			b.pathCacheConfig(),
 			b.pathConfigKeys(),
-		},
+		}, b.pathJWKS()),
 
 		Secrets:      []*framework.Secret{},
 		Invalidate:   b.invalidate,
@@ -171,6 +180,15 @@ func (b *backend) GetPolicy(ctx context.Context, polReq keysutil.PolicyRequest,
 	return p, true, nil
 }
 
+// checkRestrictedCrypto refuses keys of types which are not approved when the
+// server runs in restricted crypto mode.
+func (b *backend) checkRestrictedCrypto(keyType keysutil.KeyType) error {
+	if b.RestrictedCrypto() && !keyType.RestrictedCryptoApproved() {
+		return fmt.Errorf("key type %v is not allowed in restricted crypto mode", keyType)
+	}
+	return nil
+}
+
 func (b *backend) invalidate(ctx context.Context, key string) {
 	if b.Logger().IsDebug() {
 		b.Logger().Debug("invalidating key", "key", key)
@@ -275,6 +293,11 @@ func (b *backend) rotateIfRequired(ctx context.Context, req *logical.Request, ke
 		return nil
 	}
 
+	// Keys not approved in restricted crypto mode get no new versions.
+	if b.checkRestrictedCrypto(p.Type) != nil {
+		return nil
+	}
+
 	// Retrieve the latest version of the policy and determine if it is time to rotate.
 	latestKey := p.Keys[strconv.Itoa(p.LatestVersion)]
 	if time.Now().After(latestKey.CreationTime.Add(p.AutoRotatePeriod)) {
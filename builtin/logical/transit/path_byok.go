@@ -97,6 +97,10 @@ func (b *backend) pathPolicyBYOKExportRead(ctx context.Context, req *logical.Req
 	}
 	defer srcP.Unlock()
 
+	if srcP.SealProvider {
+		return sealProviderResponse(srcP)
+	}
+
 	if !srcP.Exportable {
 		return logical.ErrorResponse("key is not exportable"), nil
 	}
@@ -159,13 +159,17 @@ func (b *backend) pathDecryptWrite(ctx context.Context, req *logical.Request, d
 		return nil, err
 	}
 	if p == nil {
-		return logical.ErrorResponse("encryption key not found"), logical.ErrInvalidRequest
+		return logical.CodedErrorResponse(logical.ErrorCodeNotFound, "encryption key not found"), logical.ErrInvalidRequest
 	}
 	if !b.System().CachingDisabled() {
 		p.Lock(false)
 	}
 	defer p.Unlock()
 
+	if err := b.checkRestrictedCrypto(p.Type); err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
 	successesInBatch := false
 	for i, item := range batchInputItems {
 		if batchResponseItems[i].Error != "" {
@@ -123,6 +123,12 @@ Must be 0 (for latest) or a value greater than or equal
 to the min_encryption_version configured on the key.`,
 			},
 
+			"alias": {
+				Type: framework.TypeString,
+				Description: `The name of an alias of the key, whose pinned version
+is used for encryption. Cannot be given along with key_version.`,
+			},
+
 			"partial_failure_response_code": {
 				Type: framework.TypeInt,
 				Description: `
@@ -323,6 +329,16 @@ func (b *backend) pathEncryptWrite(ctx context.Context, req *logical.Request, d
 		}
 	}
 
+	if alias := d.Get("alias").(string); alias != "" {
+		for i := range batchInputItems {
+			version, resp, err := b.resolveKeyAlias(ctx, req.Storage, name, alias, batchInputItems[i].KeyVersion)
+			if resp != nil || err != nil {
+				return resp, err
+			}
+			batchInputItems[i].KeyVersion = version
+		}
+	}
+
 	batchResponseItems := make([]EncryptBatchResponseItem, len(batchInputItems))
 	contextSet := len(batchInputItems[0].Context) != 0
 
@@ -395,6 +411,9 @@ func (b *backend) pathEncryptWrite(ctx context.Context, req *logical.Request, d
 		default:
 			return logical.ErrorResponse(fmt.Sprintf("unknown key type %v", keyType)), logical.ErrInvalidRequest
 		}
+		if err := b.checkRestrictedCrypto(polReq.KeyType); err != nil {
+			return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+		}
 	} else {
 		polReq = keysutil.PolicyRequest{
 			Storage: req.Storage,
@@ -407,13 +426,17 @@ func (b *backend) pathEncryptWrite(ctx context.Context, req *logical.Request, d
 		return nil, err
 	}
 	if p == nil {
-		return logical.ErrorResponse("encryption key not found"), logical.ErrInvalidRequest
+		return logical.CodedErrorResponse(logical.ErrorCodeNotFound, "encryption key not found"), logical.ErrInvalidRequest
 	}
 	if !b.System().CachingDisabled() {
 		p.Lock(false)
 	}
 	defer p.Unlock()
 
+	if err := b.checkRestrictedCrypto(p.Type); err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
 	// Process batch request items. If encryption of any request
 	// item fails, respectively mark the error in the response
 	// collection and continue to process other items.
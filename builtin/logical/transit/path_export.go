@@ -113,6 +113,10 @@ func (b *backend) pathPolicyExportRead(ctx context.Context, req *logical.Request
 	}
 	defer p.Unlock()
 
+	if p.SealProvider {
+		return sealProviderResponse(p)
+	}
+
 	if !p.Exportable && exportType != exportTypePublicKey {
 		return logical.ErrorResponse("private key material is not exportable"), nil
 	}
@@ -0,0 +1,216 @@
+package transit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/errutil"
+	"github.com/openbao/openbao/sdk/v2/helper/keysutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"golang.org/x/crypto/hkdf"
+)
+
+// hkdfInfoPrefix separates the subkeys derived by the hkdf endpoint from
+// any other use of the HMAC key of the transit key.
+const hkdfInfoPrefix = "openbao-transit-hkdf:"
+
+func (b *backend) pathHKDF() *framework.Path {
+	return &framework.Path{
+		Pattern: "hkdf/" + framework.GenericNameRegex("name") + framework.OptionalParamRegex("plaintext"),
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixTransit,
+			OperationVerb:   "derive",
+			OperationSuffix: "subkey|subkey-with-plaintext",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "The key to derive the subkey from",
+			},
+
+			"plaintext": {
+				Type: framework.TypeString,
+				Description: `"plaintext" will return the derived subkey in both
+plaintext and ciphertext; if omitted, only the ciphertext is returned.`,
+			},
+
+			"info": {
+				Type: framework.TypeString,
+				Description: `Base64 encoded info naming the subkey to derive.
+The same info, salt and key version always derive the same subkey.`,
+			},
+
+			"salt": {
+				Type:        framework.TypeString,
+				Description: "Base64 encoded salt for the derivation. Optional.",
+			},
+
+			"context": {
+				Type:        framework.TypeString,
+				Description: "Base64 encoded context for the encryption of the subkey. Required for derived keys.",
+			},
+
+			"bits": {
+				Type: framework.TypeInt,
+				Description: `Number of bits for the subkey; currently 128, 256,
+and 512 bits are supported. Defaults to 256.`,
+				Default: 256,
+			},
+
+			"key_version": {
+				Type: framework.TypeInt,
+				Description: `The version of the key to derive the subkey from
+and encrypt it with. Must be 0 (for latest) or a value greater than or equal
+to the min_encryption_version configured on the key.`,
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.UpdateOperation: b.pathHKDFWrite,
+		},
+
+		HelpSynopsis:    pathHKDFHelpSyn,
+		HelpDescription: pathHKDFHelpDesc,
+	}
+}
+
+func (b *backend) pathHKDFWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+	ver := d.Get("key_version").(int)
+
+	plaintextAllowed := false
+	switch d.Get("plaintext").(string) {
+	case "plaintext":
+		plaintextAllowed = true
+	case "":
+	default:
+		return logical.ErrorResponse("Invalid path, must be omitted or 'plaintext'"), logical.ErrInvalidRequest
+	}
+
+	decode := func(field string) ([]byte, error) {
+		raw := d.Get(field).(string)
+		if len(raw) == 0 {
+			return nil, nil
+		}
+		value, err := base64.StdEncoding.DecodeString(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to base64-decode %s", field)
+		}
+		return value, nil
+	}
+
+	info, err := decode("info")
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+	if len(info) == 0 {
+		return logical.ErrorResponse("missing info naming the subkey"), logical.ErrInvalidRequest
+	}
+	salt, err := decode("salt")
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+	context, err := decode("context")
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	var subkey []byte
+	switch d.Get("bits").(int) {
+	case 128:
+		subkey = make([]byte, 16)
+	case 256:
+		subkey = make([]byte, 32)
+	case 512:
+		subkey = make([]byte, 64)
+	default:
+		return logical.ErrorResponse("invalid bit length"), logical.ErrInvalidRequest
+	}
+
+	// Get the policy
+	p, _, err := b.GetPolicy(ctx, keysutil.PolicyRequest{
+		Storage: req.Storage,
+		Name:    name,
+	}, b.GetRandomReader())
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return logical.CodedErrorResponse(logical.ErrorCodeNotFound, "encryption key not found"), logical.ErrInvalidRequest
+	}
+	if !b.System().CachingDisabled() {
+		p.Lock(false)
+	}
+	defer p.Unlock()
+
+	if err := b.checkRestrictedCrypto(p.Type); err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	if p.SealProvider {
+		return sealProviderResponse(p)
+	}
+
+	keyVersion := ver
+	if keyVersion == 0 {
+		keyVersion = p.LatestVersion
+	}
+	if keyVersion < p.MinEncryptionVersion {
+		return logical.ErrorResponse("cannot derive subkeys from a key version below min_encryption_version"), logical.ErrInvalidRequest
+	}
+
+	master, err := p.HMACKey(keyVersion)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	kdf := hkdf.New(sha256.New, master, salt, append([]byte(hkdfInfoPrefix), info...))
+	if _, err := io.ReadFull(kdf, subkey); err != nil {
+		return nil, err
+	}
+
+	ciphertext, err := p.EncryptWithFactory(keyVersion, context, nil, base64.StdEncoding.EncodeToString(subkey), nil)
+	if err != nil {
+		switch err.(type) {
+		case errutil.UserError:
+			return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+		default:
+			return nil, err
+		}
+	}
+	if ciphertext == "" {
+		return nil, fmt.Errorf("empty ciphertext returned")
+	}
+
+	resp := &logical.Response{
+		Data: map[string]interface{}{
+			"ciphertext":  ciphertext,
+			"key_version": keyVersion,
+		},
+	}
+
+	if plaintextAllowed {
+		resp.Data["plaintext"] = base64.StdEncoding.EncodeToString(subkey)
+	}
+
+	return resp, nil
+}
+
+const pathHKDFHelpSyn = `Derive a named subkey from a key`
+
+const pathHKDFHelpDesc = `
+This path derives a subkey from the named key using HKDF-SHA256, with the
+caller-provided info naming the subkey and an optional salt. The same info,
+salt and key version always derive the same subkey, so services can recreate
+their keys without storing derivation secrets of their own. The subkey is
+returned encrypted with the named key; call with the "plaintext" path to also
+return the (base64-encoded) subkey, which can be restricted separately in ACL
+policies. 128, 256, or 512 bits can be specified; if not specified, the
+default is 256 bits.
+`
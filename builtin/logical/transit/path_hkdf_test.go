@@ -0,0 +1,109 @@
+package transit
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+func TestTransit_HKDF(t *testing.T) {
+	b, storage := createBackendWithSysView(t)
+
+	request := func(path string, data map[string]interface{}) (*logical.Response, error) {
+		return b.HandleRequest(context.Background(), &logical.Request{
+			Storage:   storage,
+			Operation: logical.UpdateOperation,
+			Path:      path,
+			Data:      data,
+		})
+	}
+
+	resp, err := request("keys/master", nil)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+
+	info := base64.StdEncoding.EncodeToString([]byte("service-a"))
+	derive := func(path string, data map[string]interface{}) *logical.Response {
+		t.Helper()
+		resp, err := request(path, data)
+		if err != nil || resp == nil || resp.IsError() {
+			t.Fatalf("err:%v resp:%#v", err, resp)
+		}
+		return resp
+	}
+
+	// Only the wrapped subkey is returned without the plaintext path.
+	resp = derive("hkdf/master", map[string]interface{}{"info": info})
+	if _, ok := resp.Data["plaintext"]; ok {
+		t.Fatalf("plaintext returned on the wrapped path: %#v", resp.Data)
+	}
+	ciphertext := resp.Data["ciphertext"].(string)
+
+	// Derivation is deterministic.
+	resp = derive("hkdf/master/plaintext", map[string]interface{}{"info": info})
+	subkey := resp.Data["plaintext"].(string)
+	resp = derive("hkdf/master/plaintext", map[string]interface{}{"info": info})
+	if resp.Data["plaintext"] != subkey {
+		t.Fatalf("derivation is not deterministic: %q != %q", resp.Data["plaintext"], subkey)
+	}
+	raw, err := base64.StdEncoding.DecodeString(subkey)
+	if err != nil || len(raw) != 32 {
+		t.Fatalf("bad subkey %q: %v", subkey, err)
+	}
+
+	// The wrapped subkey decrypts to the same subkey.
+	resp = derive("decrypt/master", map[string]interface{}{"ciphertext": ciphertext})
+	if resp.Data["plaintext"] != subkey {
+		t.Fatalf("wrapped subkey mismatch: %q != %q", resp.Data["plaintext"], subkey)
+	}
+
+	// Different info or salt derive different subkeys.
+	for _, data := range []map[string]interface{}{
+		{"info": base64.StdEncoding.EncodeToString([]byte("service-b"))},
+		{"info": info, "salt": base64.StdEncoding.EncodeToString([]byte("salt"))},
+	} {
+		resp = derive("hkdf/master/plaintext", data)
+		if resp.Data["plaintext"] == subkey {
+			t.Fatalf("expected a different subkey for %v", data)
+		}
+	}
+
+	resp = derive("hkdf/master/plaintext", map[string]interface{}{"info": info, "bits": 512})
+	raw, _ = base64.StdEncoding.DecodeString(resp.Data["plaintext"].(string))
+	if len(raw) != 64 {
+		t.Fatalf("expected a 512-bit subkey, got %d bytes", len(raw))
+	}
+
+	// Subkeys change with the key version.
+	derive("keys/master/rotate", nil)
+	resp = derive("hkdf/master/plaintext", map[string]interface{}{"info": info})
+	if resp.Data["plaintext"] == subkey || resp.Data["key_version"] != 2 {
+		t.Fatalf("expected a new subkey from the rotated key: %#v", resp.Data)
+	}
+	resp = derive("hkdf/master/plaintext", map[string]interface{}{"info": info, "key_version": 1})
+	if resp.Data["plaintext"] != subkey {
+		t.Fatalf("expected the subkey of the first version: %#v", resp.Data)
+	}
+
+	// Invalid requests are refused.
+	for _, c := range []struct {
+		path string
+		data map[string]interface{}
+	}{
+		{"hkdf/master", map[string]interface{}{}},
+		{"hkdf/master/raw", map[string]interface{}{"info": info}},
+		{"hkdf/master", map[string]interface{}{"info": "not base64"}},
+		{"hkdf/master", map[string]interface{}{"info": info, "salt": "not base64"}},
+		{"hkdf/master", map[string]interface{}{"info": info, "bits": 100}},
+		{"hkdf/master", map[string]interface{}{"info": info, "key_version": 3}},
+		{"hkdf/missing", map[string]interface{}{"info": info}},
+	} {
+		resp, err := request(c.path, c.data)
+		if err == nil && (resp == nil || !resp.IsError()) {
+			t.Fatalf("expected %s with %v to fail, resp:%#v", c.path, c.data, resp)
+		}
+	}
+}
@@ -94,6 +94,12 @@ Must be 0 (for latest) or a value greater than or equal
 to the min_encryption_version configured on the key.`,
 			},
 
+			"alias": {
+				Type: framework.TypeString,
+				Description: `The name of an alias of the key, whose pinned version
+is used for generating the HMAC. Cannot be given along with key_version.`,
+			},
+
 			"batch_input": {
 				Type: framework.TypeSlice,
 				Description: `
@@ -114,7 +120,10 @@ Any batch output will preserve the order of the batch input.`,
 
 func (b *backend) pathHMACWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
 	name := d.Get("name").(string)
-	ver := d.Get("key_version").(int)
+	ver, resp, err := b.resolveKeyAlias(ctx, req.Storage, name, d.Get("alias").(string), d.Get("key_version").(int))
+	if resp != nil || err != nil {
+		return resp, err
+	}
 
 	algorithm := d.Get("urlalgorithm").(string)
 	if algorithm == "" {
@@ -130,13 +139,17 @@ func (b *backend) pathHMACWrite(ctx context.Context, req *logical.Request, d *fr
 		return nil, err
 	}
 	if p == nil {
-		return logical.ErrorResponse("encryption key not found"), logical.ErrInvalidRequest
+		return logical.CodedErrorResponse(logical.ErrorCodeNotFound, "encryption key not found"), logical.ErrInvalidRequest
 	}
 	if !b.System().CachingDisabled() {
 		p.Lock(false)
 	}
 	defer p.Unlock()
 
+	if p.SealProvider {
+		return sealProviderResponse(p)
+	}
+
 	switch {
 	case ver == 0:
 		// Allowed, will use latest; set explicitly here to ensure the string
@@ -210,7 +223,7 @@ func (b *backend) pathHMACWrite(ctx context.Context, req *logical.Request, d *fr
 	}
 
 	// Generate the response
-	resp := &logical.Response{}
+	resp = &logical.Response{}
 	if batchInputRaw != nil {
 		// Copy the references
 		for i := range batchInputItems {
@@ -251,13 +264,17 @@ func (b *backend) pathHMACVerify(ctx context.Context, req *logical.Request, d *f
 		return nil, err
 	}
 	if p == nil {
-		return logical.ErrorResponse("encryption key not found"), logical.ErrInvalidRequest
+		return logical.CodedErrorResponse(logical.ErrorCodeNotFound, "encryption key not found"), logical.ErrInvalidRequest
 	}
 	if !b.System().CachingDisabled() {
 		p.Lock(false)
 	}
 	defer p.Unlock()
 
+	if p.SealProvider {
+		return sealProviderResponse(p)
+	}
+
 	hashAlgorithm, ok := keysutil.HashTypeMap[algorithm]
 	if !ok {
 		return logical.ErrorResponse("unsupported algorithm %q", hashAlgorithm), nil
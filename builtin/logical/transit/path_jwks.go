@@ -0,0 +1,251 @@
+package transit
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/keysutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+func (b *backend) pathJWKS() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "jwks/?$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: operationPrefixTransit,
+				OperationVerb:   "read",
+				OperationSuffix: "jwks",
+			},
+
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation: b.pathJWKSRead,
+			},
+
+			HelpSynopsis:    pathJWKSHelpSyn,
+			HelpDescription: pathJWKSHelpDesc,
+		},
+		{
+			Pattern: "jwks/" + framework.GenericNameRegex("name"),
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: operationPrefixTransit,
+				OperationVerb:   "read",
+				OperationSuffix: "key-jwks",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the key",
+				},
+			},
+
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.ReadOperation: b.pathKeyJWKSRead,
+			},
+
+			HelpSynopsis:    pathJWKSHelpSyn,
+			HelpDescription: pathJWKSHelpDesc,
+		},
+	}
+}
+
+func (b *backend) pathJWKSRead(ctx context.Context, req *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
+	names, err := req.Storage.List(ctx, "policy/")
+	if err != nil {
+		return nil, err
+	}
+
+	jwks := &jose.JSONWebKeySet{Keys: []jose.JSONWebKey{}}
+	for _, name := range names {
+		p, _, err := b.GetPolicy(ctx, keysutil.PolicyRequest{
+			Storage: req.Storage,
+			Name:    name,
+		}, b.GetRandomReader())
+		if err != nil {
+			return nil, err
+		}
+		if p == nil {
+			continue
+		}
+
+		if !b.System().CachingDisabled() {
+			p.Lock(false)
+		}
+		var keys []jose.JSONWebKey
+		if jwksSupported(p) == nil {
+			keys, err = policyJWKs(p)
+		}
+		p.Unlock()
+		if err != nil {
+			return nil, err
+		}
+
+		jwks.Keys = append(jwks.Keys, keys...)
+	}
+
+	return jwksResponse(jwks)
+}
+
+func (b *backend) pathKeyJWKSRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+
+	p, _, err := b.GetPolicy(ctx, keysutil.PolicyRequest{
+		Storage: req.Storage,
+		Name:    name,
+	}, b.GetRandomReader())
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return nil, nil
+	}
+	if !b.System().CachingDisabled() {
+		p.Lock(false)
+	}
+	defer p.Unlock()
+
+	if err := jwksSupported(p); err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	keys, err := policyJWKs(p)
+	if err != nil {
+		return nil, err
+	}
+
+	return jwksResponse(&jose.JSONWebKeySet{Keys: keys})
+}
+
+// jwksSupported checks that the public keys of the policy can be published:
+// the key must be asymmetric and usable, and its public keys must not depend
+// on a derivation context.
+func jwksSupported(p *keysutil.Policy) error {
+	switch p.Type {
+	case keysutil.KeyType_ECDSA_P256, keysutil.KeyType_ECDSA_P384, keysutil.KeyType_ECDSA_P521, keysutil.KeyType_ED25519, keysutil.KeyType_RSA2048, keysutil.KeyType_RSA3072, keysutil.KeyType_RSA4096:
+	default:
+		return fmt.Errorf("key type %v has no public key", p.Type)
+	}
+	if p.Derived {
+		return fmt.Errorf("public keys of derived keys are not published")
+	}
+	if p.SoftDeleted {
+		return fmt.Errorf("key is soft deleted")
+	}
+	return nil
+}
+
+// policyJWKs returns the public keys of the versions of the policy which can
+// still verify signatures. Each key is identified by the name of the policy
+// and its version, as <name>:v<version>.
+func policyJWKs(p *keysutil.Policy) ([]jose.JSONWebKey, error) {
+	minVersion := p.MinDecryptionVersion
+	if minVersion < 1 {
+		minVersion = 1
+	}
+
+	var keys []jose.JSONWebKey
+	for ver := minVersion; ver <= p.LatestVersion; ver++ {
+		entry, ok := p.Keys[strconv.Itoa(ver)]
+		if !ok {
+			continue
+		}
+
+		var pub crypto.PublicKey
+		var alg string
+		switch p.Type {
+		case keysutil.KeyType_ECDSA_P256, keysutil.KeyType_ECDSA_P384, keysutil.KeyType_ECDSA_P521:
+			curve, curveAlg := elliptic.P256(), string(jose.ES256)
+			switch p.Type {
+			case keysutil.KeyType_ECDSA_P384:
+				curve, curveAlg = elliptic.P384(), string(jose.ES384)
+			case keysutil.KeyType_ECDSA_P521:
+				curve, curveAlg = elliptic.P521(), string(jose.ES512)
+			}
+			if entry.EC_X == nil || entry.EC_Y == nil {
+				continue
+			}
+			pub = &ecdsa.PublicKey{
+				Curve: curve,
+				X:     new(big.Int).Set(entry.EC_X),
+				Y:     new(big.Int).Set(entry.EC_Y),
+			}
+			alg = curveAlg
+
+		case keysutil.KeyType_ED25519:
+			var publicKey ed25519.PublicKey
+			switch {
+			case len(entry.Key) == ed25519.PrivateKeySize:
+				publicKey = ed25519.PrivateKey(entry.Key).Public().(ed25519.PublicKey)
+			case len(entry.FormattedPublicKey) > 0:
+				decoded, err := base64.StdEncoding.DecodeString(entry.FormattedPublicKey)
+				if err != nil {
+					return nil, fmt.Errorf("failed to decode public key of version %d: %w", ver, err)
+				}
+				publicKey = ed25519.PublicKey(decoded)
+			default:
+				continue
+			}
+			pub = publicKey
+			alg = string(jose.EdDSA)
+
+		default:
+			// RSA keys sign with various algorithms, so none is advertised.
+			publicKey := entry.RSAPublicKey
+			if entry.RSAKey != nil {
+				publicKey = &entry.RSAKey.PublicKey
+			}
+			if publicKey == nil {
+				continue
+			}
+			pub = publicKey
+		}
+
+		keys = append(keys, jose.JSONWebKey{
+			Key:       pub,
+			KeyID:     fmt.Sprintf("%s:v%d", p.Name, ver),
+			Algorithm: alg,
+			Use:       "sig",
+		})
+	}
+
+	return keys, nil
+}
+
+func jwksResponse(jwks *jose.JSONWebKeySet) (*logical.Response, error) {
+	data, err := json.Marshal(jwks)
+	if err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			logical.HTTPStatusCode:  200,
+			logical.HTTPRawBody:     data,
+			logical.HTTPContentType: "application/json",
+		},
+	}, nil
+}
+
+const pathJWKSHelpSyn = `Read the public keys of asymmetric keys as a JSON Web Key Set`
+
+const pathJWKSHelpDesc = `
+This path returns the public keys of asymmetric keys as a JSON Web Key Set
+(RFC 7517), for the key versions which can still verify signatures. Reading
+"jwks" returns the public keys of all the asymmetric keys of the mount, while
+"jwks/<name>" returns those of the named key. Each key is identified by the
+name of the key and its version, as "<name>:v<version>", so that verifiers
+follow key rotation. These paths do not require authentication.
+`
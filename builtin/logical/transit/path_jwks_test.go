@@ -0,0 +1,95 @@
+package transit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-jose/go-jose/v3"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+func TestTransit_JWKS(t *testing.T) {
+	b, storage := createBackendWithSysView(t)
+
+	request := func(op logical.Operation, path string, data map[string]interface{}) (*logical.Response, error) {
+		return b.HandleRequest(context.Background(), &logical.Request{
+			Storage:   storage,
+			Operation: op,
+			Path:      path,
+			Data:      data,
+		})
+	}
+	write := func(path string, data map[string]interface{}) {
+		t.Helper()
+		resp, err := request(logical.UpdateOperation, path, data)
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("err:%v resp:%#v", err, resp)
+		}
+	}
+	readJWKS := func(path string) *jose.JSONWebKeySet {
+		t.Helper()
+		resp, err := request(logical.ReadOperation, path, nil)
+		if err != nil || resp == nil || resp.IsError() {
+			t.Fatalf("err:%v resp:%#v", err, resp)
+		}
+		if resp.Data[logical.HTTPContentType] != "application/json" {
+			t.Fatalf("bad content type: %#v", resp.Data)
+		}
+		var jwks jose.JSONWebKeySet
+		if err := json.Unmarshal(resp.Data[logical.HTTPRawBody].([]byte), &jwks); err != nil {
+			t.Fatal(err)
+		}
+		return &jwks
+	}
+
+	write("keys/ec", map[string]interface{}{"type": "ecdsa-p384"})
+	write("keys/ed", map[string]interface{}{"type": "ed25519"})
+	write("keys/rsa", map[string]interface{}{"type": "rsa-2048"})
+	write("keys/aes", nil)
+	write("keys/derived", map[string]interface{}{"type": "ed25519", "derived": true})
+	write("keys/ec/rotate", nil)
+
+	jwks := readJWKS("jwks/ec")
+	if len(jwks.Keys) != 2 {
+		t.Fatalf("expected 2 keys, got %d", len(jwks.Keys))
+	}
+	for i, key := range jwks.Keys {
+		if key.KeyID != []string{"ec:v1", "ec:v2"}[i] || key.Algorithm != "ES384" || key.Use != "sig" || !key.IsPublic() {
+			t.Fatalf("bad key %d: %#v", i, key)
+		}
+	}
+
+	// Keys below the minimum decryption version are no longer published.
+	write("keys/ec/config", map[string]interface{}{"min_decryption_version": 2})
+	jwks = readJWKS("jwks/ec")
+	if len(jwks.Keys) != 1 || jwks.Keys[0].KeyID != "ec:v2" {
+		t.Fatalf("bad keys: %#v", jwks.Keys)
+	}
+
+	jwks = readJWKS("jwks/ed")
+	if len(jwks.Keys) != 1 || jwks.Keys[0].KeyID != "ed:v1" || jwks.Keys[0].Algorithm != "EdDSA" {
+		t.Fatalf("bad keys: %#v", jwks.Keys)
+	}
+
+	// The mount-wide set only holds the keys which can be published.
+	jwks = readJWKS("jwks")
+	kids := map[string]bool{}
+	for _, key := range jwks.Keys {
+		kids[key.KeyID] = true
+	}
+	if len(kids) != 3 || !kids["ec:v2"] || !kids["ed:v1"] || !kids["rsa:v1"] {
+		t.Fatalf("bad key ids: %v", kids)
+	}
+
+	for _, path := range []string{"jwks/aes", "jwks/derived"} {
+		resp, err := request(logical.ReadOperation, path, nil)
+		if err == nil && (resp == nil || !resp.IsError()) {
+			t.Fatalf("expected reading %s to fail, resp:%#v", path, resp)
+		}
+	}
+	resp, err := request(logical.ReadOperation, "jwks/missing", nil)
+	if err != nil || resp != nil {
+		t.Fatalf("expected no response for a missing key, err:%v resp:%#v", err, resp)
+	}
+}
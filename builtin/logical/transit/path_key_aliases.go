@@ -0,0 +1,253 @@
+package transit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/keysutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const keyAliasesPrefix = "key-aliases/"
+
+// keyAliasEntry pins a version of a key for the consumers referencing the
+// alias, so that they keep using it as the key is rotated.
+type keyAliasEntry struct {
+	Version      int       `json:"version"`
+	CreationTime time.Time `json:"creation_time"`
+	UpdateTime   time.Time `json:"update_time"`
+}
+
+func (b *backend) pathListKeyAliases() *framework.Path {
+	return &framework.Path{
+		Pattern: "keys/" + framework.GenericNameRegex("name") + "/aliases/?$",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixTransit,
+			OperationSuffix: "key-aliases",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Name of the key",
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ListOperation: b.pathKeyAliasesList,
+		},
+
+		HelpSynopsis:    pathKeyAliasesHelpSyn,
+		HelpDescription: pathKeyAliasesHelpDesc,
+	}
+}
+
+func (b *backend) pathKeyAliases() *framework.Path {
+	return &framework.Path{
+		Pattern: "keys/" + framework.GenericNameRegex("name") + "/aliases/" + framework.GenericNameRegex("alias"),
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixTransit,
+			OperationSuffix: "key-alias",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Name of the key",
+			},
+
+			"alias": {
+				Type:        framework.TypeString,
+				Description: "Name of the alias",
+			},
+
+			"version": {
+				Type: framework.TypeInt,
+				Description: `The version of the key the alias is pinned to. Must be
+an available version of the key.`,
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.UpdateOperation: b.pathKeyAliasWrite,
+			logical.ReadOperation:   b.pathKeyAliasRead,
+			logical.DeleteOperation: b.pathKeyAliasDelete,
+		},
+
+		HelpSynopsis:    pathKeyAliasesHelpSyn,
+		HelpDescription: pathKeyAliasesHelpDesc,
+	}
+}
+
+func (b *backend) pathKeyAliasesList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	aliases, err := req.Storage.List(ctx, keyAliasesPrefix+d.Get("name").(string)+"/")
+	if err != nil {
+		return nil, err
+	}
+
+	return logical.ListResponse(aliases), nil
+}
+
+func (b *backend) pathKeyAliasWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+	alias := d.Get("alias").(string)
+
+	p, _, err := b.GetPolicy(ctx, keysutil.PolicyRequest{
+		Storage: req.Storage,
+		Name:    name,
+	}, b.GetRandomReader())
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return logical.ErrorResponse(fmt.Sprintf("no existing key named %s could be found", name)), logical.ErrInvalidRequest
+	}
+	if !b.System().CachingDisabled() {
+		p.Lock(false)
+	}
+	defer p.Unlock()
+
+	version := d.Get("version").(int)
+	switch {
+	case version <= 0:
+		return logical.ErrorResponse("version must be positive"), logical.ErrInvalidRequest
+	case version > p.LatestVersion:
+		return logical.ErrorResponse(fmt.Sprintf("version %d of key %s does not exist", version, name)), logical.ErrInvalidRequest
+	case version < p.MinAvailableVersion:
+		return logical.ErrorResponse(fmt.Sprintf("version %d of key %s has been trimmed", version, name)), logical.ErrInvalidRequest
+	}
+
+	entry, err := b.keyAlias(ctx, req.Storage, name, alias)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if entry == nil {
+		entry = &keyAliasEntry{CreationTime: now}
+	}
+	entry.Version = version
+	entry.UpdateTime = now
+
+	storageEntry, err := logical.StorageEntryJSON(keyAliasesPrefix+name+"/"+alias, entry)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Storage.Put(ctx, storageEntry); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (b *backend) pathKeyAliasRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	entry, err := b.keyAlias(ctx, req.Storage, d.Get("name").(string), d.Get("alias").(string))
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"version":       entry.Version,
+			"creation_time": entry.CreationTime,
+			"update_time":   entry.UpdateTime,
+		},
+	}, nil
+}
+
+func (b *backend) pathKeyAliasDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if err := req.Storage.Delete(ctx, keyAliasesPrefix+d.Get("name").(string)+"/"+d.Get("alias").(string)); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (b *backend) keyAlias(ctx context.Context, s logical.Storage, name, alias string) (*keyAliasEntry, error) {
+	raw, err := s.Get(ctx, keyAliasesPrefix+name+"/"+alias)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, nil
+	}
+
+	var entry keyAliasEntry
+	if err := raw.DecodeJSON(&entry); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// resolveKeyAlias returns the key version pinned by the alias of the named
+// key, or keyVersion when no alias is given. An alias and an explicit key
+// version cannot be given together.
+func (b *backend) resolveKeyAlias(ctx context.Context, s logical.Storage, name, alias string, keyVersion int) (int, *logical.Response, error) {
+	if alias == "" {
+		return keyVersion, nil, nil
+	}
+	if keyVersion != 0 {
+		return 0, logical.ErrorResponse("key_version cannot be given along with alias"), logical.ErrInvalidRequest
+	}
+
+	entry, err := b.keyAlias(ctx, s, name, alias)
+	if err != nil {
+		return 0, nil, err
+	}
+	if entry == nil {
+		return 0, logical.CodedErrorResponse(logical.ErrorCodeNotFound, fmt.Sprintf("alias %s of key %s not found", alias, name)), logical.ErrInvalidRequest
+	}
+
+	return entry.Version, nil, nil
+}
+
+// checkKeyAliases refuses trimming the versions of the named key pinned by
+// its aliases.
+func (b *backend) checkKeyAliases(ctx context.Context, s logical.Storage, name string, minAvailableVersion int) (*logical.Response, error) {
+	aliases, err := s.List(ctx, keyAliasesPrefix+name+"/")
+	if err != nil {
+		return nil, err
+	}
+	for _, alias := range aliases {
+		entry, err := b.keyAlias(ctx, s, name, alias)
+		if err != nil {
+			return nil, err
+		}
+		if entry != nil && entry.Version < minAvailableVersion {
+			return logical.ErrorResponse(fmt.Sprintf("refusing to trim version %d of key %s, which is pinned by alias %s", entry.Version, name, alias)), logical.ErrInvalidRequest
+		}
+	}
+
+	return nil, nil
+}
+
+// deleteKeyAliases removes the aliases of the named key, once deleted.
+func (b *backend) deleteKeyAliases(ctx context.Context, s logical.Storage, name string) error {
+	aliases, err := s.List(ctx, keyAliasesPrefix+name+"/")
+	if err != nil {
+		return err
+	}
+	for _, alias := range aliases {
+		if err := s.Delete(ctx, keyAliasesPrefix+name+"/"+alias); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+const pathKeyAliasesHelpSyn = `Manage the aliases pinning versions of a key`
+
+const pathKeyAliasesHelpDesc = `
+An alias of a key is pinned to a version of the key. Encrypt, rewrap, sign
+and HMAC requests giving the alias, instead of key_version, use the pinned
+version, so that some consumers deliberately stay on an older version as the
+key is rotated, for staged migrations. Pinned versions cannot be trimmed.
+`
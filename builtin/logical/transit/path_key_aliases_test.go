@@ -0,0 +1,108 @@
+package transit
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+func TestTransit_KeyAliases(t *testing.T) {
+	b, storage := createBackendWithSysView(t)
+
+	request := func(op logical.Operation, path string, data map[string]interface{}) (*logical.Response, error) {
+		return b.HandleRequest(context.Background(), &logical.Request{
+			Storage:   storage,
+			Operation: op,
+			Path:      path,
+			Data:      data,
+		})
+	}
+	mustSucceed := func(op logical.Operation, path string, data map[string]interface{}) *logical.Response {
+		t.Helper()
+		resp, err := request(op, path, data)
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("%s %s: err:%v resp:%#v", op, path, err, resp)
+		}
+		return resp
+	}
+	mustFail := func(op logical.Operation, path string, data map[string]interface{}) {
+		t.Helper()
+		resp, err := request(op, path, data)
+		if err == nil && (resp == nil || !resp.IsError()) {
+			t.Fatalf("expected %s %s to fail, resp:%#v", op, path, resp)
+		}
+	}
+
+	plaintext := "dGhlIHF1aWNrIGJyb3duIGZveA=="
+
+	mustSucceed(logical.UpdateOperation, "keys/aes", nil)
+	mustSucceed(logical.UpdateOperation, "keys/aes/rotate", nil)
+	mustSucceed(logical.UpdateOperation, "keys/aes/rotate", nil)
+	mustSucceed(logical.UpdateOperation, "keys/ed", map[string]interface{}{"type": "ed25519"})
+	mustSucceed(logical.UpdateOperation, "keys/ed/rotate", nil)
+
+	// Aliases must be pinned to an existing version.
+	mustFail(logical.UpdateOperation, "keys/aes/aliases/legacy", nil)
+	mustFail(logical.UpdateOperation, "keys/aes/aliases/legacy", map[string]interface{}{"version": 4})
+	mustFail(logical.UpdateOperation, "keys/missing/aliases/legacy", map[string]interface{}{"version": 1})
+
+	mustSucceed(logical.UpdateOperation, "keys/aes/aliases/legacy", map[string]interface{}{"version": 1})
+	mustSucceed(logical.UpdateOperation, "keys/aes/aliases/staging", map[string]interface{}{"version": 2})
+	mustSucceed(logical.UpdateOperation, "keys/ed/aliases/legacy", map[string]interface{}{"version": 1})
+	resp := mustSucceed(logical.ListOperation, "keys/aes/aliases/", nil)
+	if !reflect.DeepEqual(resp.Data["keys"], []string{"legacy", "staging"}) {
+		t.Fatalf("bad aliases: %#v", resp.Data)
+	}
+	resp = mustSucceed(logical.ReadOperation, "keys/aes/aliases/legacy", nil)
+	if resp.Data["version"] != 1 {
+		t.Fatalf("bad alias: %#v", resp.Data)
+	}
+
+	// Requests giving an alias use its pinned version.
+	resp = mustSucceed(logical.UpdateOperation, "encrypt/aes", map[string]interface{}{"plaintext": plaintext, "alias": "legacy"})
+	ciphertext := resp.Data["ciphertext"].(string)
+	if !strings.HasPrefix(ciphertext, "vault:v1:") || resp.Data["key_version"] != 1 {
+		t.Fatalf("expected encryption with version 1: %#v", resp.Data)
+	}
+	resp = mustSucceed(logical.UpdateOperation, "decrypt/aes", map[string]interface{}{"ciphertext": ciphertext})
+	if resp.Data["plaintext"] != plaintext {
+		t.Fatalf("bad plaintext: %#v", resp.Data)
+	}
+	resp = mustSucceed(logical.UpdateOperation, "rewrap/aes", map[string]interface{}{"ciphertext": ciphertext, "alias": "staging"})
+	if !strings.HasPrefix(resp.Data["ciphertext"].(string), "vault:v2:") {
+		t.Fatalf("expected rewrapping to version 2: %#v", resp.Data)
+	}
+	resp = mustSucceed(logical.UpdateOperation, "hmac/aes", map[string]interface{}{"input": plaintext, "alias": "staging"})
+	if !strings.HasPrefix(resp.Data["hmac"].(string), "vault:v2:") {
+		t.Fatalf("expected an HMAC with version 2: %#v", resp.Data)
+	}
+	resp = mustSucceed(logical.UpdateOperation, "sign/ed", map[string]interface{}{"input": plaintext, "alias": "legacy"})
+	if !strings.HasPrefix(resp.Data["signature"].(string), "vault:v1:") {
+		t.Fatalf("expected a signature with version 1: %#v", resp.Data)
+	}
+
+	// Aliases can't be combined with key_version, and must exist.
+	mustFail(logical.UpdateOperation, "encrypt/aes", map[string]interface{}{"plaintext": plaintext, "alias": "legacy", "key_version": 2})
+	mustFail(logical.UpdateOperation, "sign/ed", map[string]interface{}{"input": plaintext, "alias": "legacy", "key_version": 1})
+	mustFail(logical.UpdateOperation, "encrypt/aes", map[string]interface{}{"plaintext": plaintext, "alias": "unknown"})
+	mustFail(logical.UpdateOperation, "hmac/aes", map[string]interface{}{"input": plaintext, "alias": "unknown"})
+
+	// Pinned versions can't be trimmed.
+	mustSucceed(logical.UpdateOperation, "keys/aes/config", map[string]interface{}{"min_decryption_version": 3, "min_encryption_version": 3})
+	mustFail(logical.UpdateOperation, "keys/aes/trim", map[string]interface{}{"min_available_version": 2})
+	mustSucceed(logical.DeleteOperation, "keys/aes/aliases/legacy", nil)
+	mustFail(logical.UpdateOperation, "keys/aes/trim", map[string]interface{}{"min_available_version": 3})
+	mustSucceed(logical.UpdateOperation, "keys/aes/trim", map[string]interface{}{"min_available_version": 2})
+	mustFail(logical.UpdateOperation, "keys/aes/aliases/legacy", map[string]interface{}{"version": 1})
+
+	// Deleting the key removes its aliases.
+	mustSucceed(logical.UpdateOperation, "keys/aes/config", map[string]interface{}{"deletion_allowed": true})
+	mustSucceed(logical.DeleteOperation, "keys/aes", nil)
+	resp = mustSucceed(logical.ListOperation, "keys/aes/aliases/", nil)
+	if _, ok := resp.Data["keys"]; ok {
+		t.Fatalf("expected the aliases to be removed: %#v", resp.Data)
+	}
+}
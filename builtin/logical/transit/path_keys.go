@@ -301,6 +301,10 @@ func (b *backend) pathPolicyWrite(ctx context.Context, req *logical.Request, d *
 		polReq.KeySize = keySize
 	}
 
+	if err := b.checkRestrictedCrypto(polReq.KeyType); err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
 	p, upserted, err := b.GetPolicy(ctx, polReq, b.GetRandomReader())
 	if err != nil {
 		return nil, err
@@ -381,6 +385,7 @@ func (b *backend) formatKeyPolicy(p *keysutil.Policy, context []byte) (*logical.
 			"auto_rotate_period":     int64(p.AutoRotatePeriod.Seconds()),
 			"imported_key":           p.Imported,
 			"soft_deleted":           p.SoftDeleted,
+			"seal_provider":          p.SealProvider,
 		},
 	}
 	if p.KeySize != 0 {
@@ -490,12 +495,20 @@ func (b *backend) formatKeyPolicy(p *keysutil.Policy, context []byte) (*logical.
 func (b *backend) pathPolicyDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
 	name := d.Get("name").(string)
 
+	if resp, err := b.checkSealClients(ctx, req.Storage, name, "delete"); resp != nil || err != nil {
+		return resp, err
+	}
+
 	// Delete does its own locking
 	err := b.lm.DeletePolicy(ctx, req.Storage, name)
 	if err != nil {
 		return logical.ErrorResponse(fmt.Sprintf("error deleting policy %s: %s", name, err)), err
 	}
 
+	if err := b.deleteKeyAliases(ctx, req.Storage, name); err != nil {
+		return nil, err
+	}
+
 	return nil, nil
 }
 
@@ -517,6 +530,10 @@ func (b *backend) pathPolicySoftDelete(ctx context.Context, req *logical.Request
 	}
 	defer p.Unlock()
 
+	if resp, err := b.checkSealClients(ctx, req.Storage, name, "soft delete"); resp != nil || err != nil {
+		return resp, err
+	}
+
 	wasDeleted := !p.SoftDeleted
 	p.SoftDeleted = true
 
@@ -6,6 +6,7 @@ package transit
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/openbao/openbao/sdk/v2/framework"
@@ -59,6 +60,13 @@ the latest version of the key is allowed.`,
 				Description: `Enables taking a backup of the named key in plaintext format. Once set, this cannot be disabled.`,
 			},
 
+			"seal_provider": {
+				Type: framework.TypeBool,
+				Description: `Dedicates the key to sealing other clusters, restricting
+it to encryption and decryption. This cannot be disabled
+while downstream clusters are registered on the key.`,
+			},
+
 			"auto_rotate_period": {
 				Type: framework.TypeDurationSecond,
 				Description: `Amount of time the key should live before
@@ -104,6 +112,7 @@ func (b *backend) pathKeysConfigWrite(ctx context.Context, req *logical.Request,
 	originalDeletionAllowed := p.DeletionAllowed
 	originalExportable := p.Exportable
 	originalAllowPlaintextBackup := p.AllowPlaintextBackup
+	originalSealProvider := p.SealProvider
 
 	defer func() {
 		if retErr != nil || (resp != nil && resp.IsError()) {
@@ -112,6 +121,7 @@ func (b *backend) pathKeysConfigWrite(ctx context.Context, req *logical.Request,
 			p.DeletionAllowed = originalDeletionAllowed
 			p.Exportable = originalExportable
 			p.AllowPlaintextBackup = originalAllowPlaintextBackup
+			p.SealProvider = originalSealProvider
 		}
 	}()
 
@@ -175,6 +185,38 @@ func (b *backend) pathKeysConfigWrite(ctx context.Context, req *logical.Request,
 		}
 	}
 
+	sealProviderRaw, ok := d.GetOk("seal_provider")
+	if ok {
+		sealProvider := sealProviderRaw.(bool)
+		if sealProvider && !p.SealProvider {
+			if !p.Type.EncryptionSupported() || !p.Type.DecryptionSupported() {
+				return logical.ErrorResponse(fmt.Sprintf("key type %v does not support encryption and decryption", p.Type)), nil
+			}
+			if p.Derived {
+				return logical.ErrorResponse("derived keys cannot be seal providers"), nil
+			}
+		}
+		if !sealProvider && p.SealProvider {
+			if resp, err := b.checkSealClients(ctx, req.Storage, name, "disable seal provider mode of"); resp != nil || err != nil {
+				return resp, err
+			}
+		}
+		if sealProvider != p.SealProvider {
+			p.SealProvider = sealProvider
+			persistNeeded = true
+		}
+	}
+
+	if p.DeletionAllowed && !originalDeletionAllowed && p.SealProvider {
+		clients, err := b.sealClients(ctx, req.Storage, name)
+		if err != nil {
+			return nil, err
+		}
+		if len(clients) > 0 {
+			warning = fmt.Sprintf("key %s seals the registered clusters %s, which will fail to unseal if it is deleted", name, strings.Join(clients, ", "))
+		}
+	}
+
 	// Add this as a guard here before persisting since we now require the min
 	// decryption version to start at 1; even if it's not explicitly set here,
 	// force the upgrade
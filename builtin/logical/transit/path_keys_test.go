@@ -433,3 +433,59 @@ func validateOpsFail(t *testing.T, b *backend, s logical.Storage, encrypt bool,
 
 	// Soft deleted keys remain updatable.
 }
+
+func TestTransit_RestrictedCrypto(t *testing.T) {
+	b, storage := createBackendWithSysView(t)
+	for name, keyType := range map[string]string{"chacha": "chacha20-poly1305", "ed": "ed25519", "aes": "aes256-gcm96"} {
+		resp, err := b.HandleRequest(context.Background(), &logical.Request{
+			Storage:   storage,
+			Operation: logical.UpdateOperation,
+			Path:      "keys/" + name,
+			Data:      map[string]interface{}{"type": keyType},
+		})
+		require.NoError(t, err)
+		require.False(t, resp.IsError())
+	}
+
+	// Existing keys of types not approved can be read but not used once the
+	// server runs in restricted crypto mode.
+	sysView := logical.TestSystemView()
+	sysView.RestrictedCryptoVal = true
+	conf := &logical.BackendConfig{StorageView: storage, System: sysView}
+	b, err := Backend(context.Background(), conf)
+	require.NoError(t, err)
+	require.NoError(t, b.Backend.Setup(context.Background(), conf))
+
+	for _, tc := range []struct {
+		path    string
+		data    map[string]interface{}
+		refused bool
+	}{
+		{path: "encrypt/chacha", data: map[string]interface{}{"plaintext": "dGVzdA=="}, refused: true},
+		{path: "sign/ed", data: map[string]interface{}{"input": "dGVzdA=="}, refused: true},
+		{path: "keys/chacha/rotate", refused: true},
+		{path: "encrypt/aes", data: map[string]interface{}{"plaintext": "dGVzdA=="}},
+		{path: "keys/aes/rotate"},
+	} {
+		resp, err := b.HandleRequest(context.Background(), &logical.Request{
+			Storage:   storage,
+			Operation: logical.UpdateOperation,
+			Path:      tc.path,
+			Data:      tc.data,
+		})
+		if tc.refused {
+			require.ErrorIs(t, err, logical.ErrInvalidRequest, tc.path)
+			require.Contains(t, resp.Error().Error(), "restricted crypto mode", tc.path)
+		} else {
+			require.NoError(t, err, tc.path)
+		}
+	}
+
+	resp, err := b.HandleRequest(context.Background(), &logical.Request{
+		Storage:   storage,
+		Operation: logical.ReadOperation,
+		Path:      "keys/chacha",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "chacha20-poly1305", resp.Data["type"])
+}
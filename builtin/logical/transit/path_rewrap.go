@@ -47,6 +47,12 @@ Must be 0 (for latest) or a value greater than or equal
 to the min_encryption_version configured on the key.`,
 			},
 
+			"alias": {
+				Type: framework.TypeString,
+				Description: `The name of an alias of the key, whose pinned version
+is used for encryption. Cannot be given along with key_version.`,
+			},
+
 			"batch_input": {
 				Type: framework.TypeSlice,
 				Description: `
@@ -92,6 +98,16 @@ func (b *backend) pathRewrapWrite(ctx context.Context, req *logical.Request, d *
 		}
 	}
 
+	if alias := d.Get("alias").(string); alias != "" {
+		for i := range batchInputItems {
+			version, resp, err := b.resolveKeyAlias(ctx, req.Storage, d.Get("name").(string), alias, batchInputItems[i].KeyVersion)
+			if resp != nil || err != nil {
+				return resp, err
+			}
+			batchInputItems[i].KeyVersion = version
+		}
+	}
+
 	batchResponseItems := make([]EncryptBatchResponseItem, len(batchInputItems))
 	contextSet := len(batchInputItems[0].Context) != 0
 
@@ -124,13 +140,17 @@ func (b *backend) pathRewrapWrite(ctx context.Context, req *logical.Request, d *
 		return nil, err
 	}
 	if p == nil {
-		return logical.ErrorResponse("encryption key not found"), logical.ErrInvalidRequest
+		return logical.CodedErrorResponse(logical.ErrorCodeNotFound, "encryption key not found"), logical.ErrInvalidRequest
 	}
 	if !b.System().CachingDisabled() {
 		p.Lock(false)
 	}
 	defer p.Unlock()
 
+	if err := b.checkRestrictedCrypto(p.Type); err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
 	for i, item := range batchInputItems {
 		if batchResponseItems[i].Error != "" {
 			continue
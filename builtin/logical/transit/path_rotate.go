@@ -56,6 +56,10 @@ func (b *backend) pathRotateWrite(ctx context.Context, req *logical.Request, d *
 	}
 	defer p.Unlock()
 
+	if err := b.checkRestrictedCrypto(p.Type); err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
 	// Rotate the policy
 	err = p.Rotate(ctx, req.Storage, b.GetRandomReader())
 	if err != nil {
@@ -0,0 +1,217 @@
+package transit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/keysutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const sealClientsPrefix = "seal-clients/"
+
+// sealClientEntry records a downstream cluster sealed by a seal provider key.
+type sealClientEntry struct {
+	Description  string    `json:"description"`
+	CreationTime time.Time `json:"creation_time"`
+}
+
+func (b *backend) pathListSealClients() *framework.Path {
+	return &framework.Path{
+		Pattern: "keys/" + framework.GenericNameRegex("name") + "/seal-clients/?$",
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixTransit,
+			OperationSuffix: "seal-clients",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Name of the key",
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.ListOperation: b.pathSealClientsList,
+		},
+
+		HelpSynopsis:    pathSealClientsHelpSyn,
+		HelpDescription: pathSealClientsHelpDesc,
+	}
+}
+
+func (b *backend) pathSealClients() *framework.Path {
+	return &framework.Path{
+		Pattern: "keys/" + framework.GenericNameRegex("name") + "/seal-clients/" + framework.GenericNameRegex("client"),
+
+		DisplayAttrs: &framework.DisplayAttributes{
+			OperationPrefix: operationPrefixTransit,
+			OperationSuffix: "seal-client",
+		},
+
+		Fields: map[string]*framework.FieldSchema{
+			"name": {
+				Type:        framework.TypeString,
+				Description: "Name of the key",
+			},
+
+			"client": {
+				Type:        framework.TypeString,
+				Description: "Name of the downstream cluster sealed by the key",
+			},
+
+			"description": {
+				Type:        framework.TypeString,
+				Description: "Description of the downstream cluster, such as its address or owner",
+			},
+		},
+
+		Callbacks: map[logical.Operation]framework.OperationFunc{
+			logical.UpdateOperation: b.pathSealClientWrite,
+			logical.ReadOperation:   b.pathSealClientRead,
+			logical.DeleteOperation: b.pathSealClientDelete,
+		},
+
+		HelpSynopsis:    pathSealClientsHelpSyn,
+		HelpDescription: pathSealClientsHelpDesc,
+	}
+}
+
+func (b *backend) pathSealClientsList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	clients, err := b.sealClients(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return nil, err
+	}
+
+	return logical.ListResponse(clients), nil
+}
+
+func (b *backend) pathSealClientWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+	client := d.Get("client").(string)
+
+	p, _, err := b.GetPolicy(ctx, keysutil.PolicyRequest{
+		Storage: req.Storage,
+		Name:    name,
+	}, b.GetRandomReader())
+	if err != nil {
+		return nil, err
+	}
+	if p == nil {
+		return logical.ErrorResponse(fmt.Sprintf("no existing key named %s could be found", name)), logical.ErrInvalidRequest
+	}
+	if !b.System().CachingDisabled() {
+		p.Lock(false)
+	}
+	defer p.Unlock()
+
+	if !p.SealProvider {
+		return logical.ErrorResponse(fmt.Sprintf("key %s is not a seal provider", name)), logical.ErrInvalidRequest
+	}
+
+	entry, err := b.sealClient(ctx, req.Storage, name, client)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		entry = &sealClientEntry{CreationTime: time.Now()}
+	}
+	if description, ok := d.GetOk("description"); ok {
+		entry.Description = description.(string)
+	}
+
+	storageEntry, err := logical.StorageEntryJSON(sealClientsPrefix+name+"/"+client, entry)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Storage.Put(ctx, storageEntry); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (b *backend) pathSealClientRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	entry, err := b.sealClient(ctx, req.Storage, d.Get("name").(string), d.Get("client").(string))
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"description":   entry.Description,
+			"creation_time": entry.CreationTime,
+		},
+	}, nil
+}
+
+func (b *backend) pathSealClientDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+	client := d.Get("client").(string)
+
+	if err := req.Storage.Delete(ctx, sealClientsPrefix+name+"/"+client); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (b *backend) sealClient(ctx context.Context, s logical.Storage, name, client string) (*sealClientEntry, error) {
+	raw, err := s.Get(ctx, sealClientsPrefix+name+"/"+client)
+	if err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		return nil, nil
+	}
+
+	var entry sealClientEntry
+	if err := raw.DecodeJSON(&entry); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// sealClients returns the downstream clusters registered as sealed by the
+// named key.
+func (b *backend) sealClients(ctx context.Context, s logical.Storage, name string) ([]string, error) {
+	return s.List(ctx, sealClientsPrefix+name+"/")
+}
+
+// checkSealClients refuses an operation which would break the downstream
+// clusters sealed by the named key.
+func (b *backend) checkSealClients(ctx context.Context, s logical.Storage, name, operation string) (*logical.Response, error) {
+	clients, err := b.sealClients(ctx, s, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(clients) > 0 {
+		return logical.ErrorResponse(fmt.Sprintf("refusing to %s key %s, which seals the registered clusters %s; deregister them first", operation, name, strings.Join(clients, ", "))), logical.ErrInvalidRequest
+	}
+
+	return nil, nil
+}
+
+// sealProviderResponse is returned for operations other than encryption and
+// decryption on seal provider keys.
+func sealProviderResponse(p *keysutil.Policy) (*logical.Response, error) {
+	return logical.ErrorResponse(fmt.Sprintf("key %s is a seal provider and only supports encryption and decryption", p.Name)), logical.ErrInvalidRequest
+}
+
+const pathSealClientsHelpSyn = `Manage the downstream clusters sealed by a seal provider key`
+
+const pathSealClientsHelpDesc = `
+This path registers the downstream clusters which use a seal provider key for
+auto-unseal. A key with registered clusters cannot be deleted, soft deleted or
+stop being a seal provider, so that it is not removed while clusters depend on
+it. Registrations are informational and do not restrict which clients may use
+the key.
+`
@@ -0,0 +1,83 @@
+package transit
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+func TestTransit_SealProvider(t *testing.T) {
+	b, storage := createBackendWithSysView(t)
+
+	request := func(op logical.Operation, path string, data map[string]interface{}) (*logical.Response, error) {
+		return b.HandleRequest(context.Background(), &logical.Request{
+			Storage:   storage,
+			Operation: op,
+			Path:      path,
+			Data:      data,
+		})
+	}
+	mustSucceed := func(op logical.Operation, path string, data map[string]interface{}) *logical.Response {
+		t.Helper()
+		resp, err := request(op, path, data)
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("%s %s: err:%v resp:%#v", op, path, err, resp)
+		}
+		return resp
+	}
+	mustFail := func(op logical.Operation, path string, data map[string]interface{}) {
+		t.Helper()
+		resp, err := request(op, path, data)
+		if err == nil && (resp == nil || !resp.IsError()) {
+			t.Fatalf("expected %s %s to fail, resp:%#v", op, path, resp)
+		}
+	}
+
+	mustSucceed(logical.UpdateOperation, "keys/unseal", nil)
+	mustSucceed(logical.UpdateOperation, "keys/signing", map[string]interface{}{"type": "ed25519"})
+
+	// Clusters can only be registered on seal provider keys, which must
+	// support encryption.
+	mustFail(logical.UpdateOperation, "keys/unseal/seal-clients/east", nil)
+	mustFail(logical.UpdateOperation, "keys/signing/config", map[string]interface{}{"seal_provider": true})
+	resp := mustSucceed(logical.UpdateOperation, "keys/unseal/config", map[string]interface{}{"seal_provider": true})
+	if resp.Data["seal_provider"] != true {
+		t.Fatalf("expected seal_provider to be set: %#v", resp.Data)
+	}
+
+	mustSucceed(logical.UpdateOperation, "keys/unseal/seal-clients/east", map[string]interface{}{"description": "https://east.example.com:8200"})
+	mustSucceed(logical.UpdateOperation, "keys/unseal/seal-clients/west", nil)
+	resp = mustSucceed(logical.ListOperation, "keys/unseal/seal-clients/", nil)
+	if !reflect.DeepEqual(resp.Data["keys"], []string{"east", "west"}) {
+		t.Fatalf("bad seal clients: %#v", resp.Data)
+	}
+	resp = mustSucceed(logical.ReadOperation, "keys/unseal/seal-clients/east", nil)
+	if resp.Data["description"] != "https://east.example.com:8200" {
+		t.Fatalf("bad seal client: %#v", resp.Data)
+	}
+
+	// Only encryption and decryption are allowed.
+	resp = mustSucceed(logical.UpdateOperation, "encrypt/unseal", map[string]interface{}{"plaintext": "dGhlIHF1aWNrIGJyb3duIGZveA=="})
+	mustSucceed(logical.UpdateOperation, "decrypt/unseal", map[string]interface{}{"ciphertext": resp.Data["ciphertext"]})
+	mustFail(logical.UpdateOperation, "datakey/plaintext/unseal", nil)
+	mustFail(logical.UpdateOperation, "hmac/unseal", map[string]interface{}{"input": "dGhlIHF1aWNrIGJyb3duIGZveA=="})
+	mustFail(logical.UpdateOperation, "hkdf/unseal", map[string]interface{}{"info": "aW5mbw=="})
+
+	// The key can't be removed while clusters depend on it, and enabling its
+	// deletion warns about them.
+	mustFail(logical.UpdateOperation, "keys/unseal/config", map[string]interface{}{"seal_provider": false})
+	resp = mustSucceed(logical.UpdateOperation, "keys/unseal/config", map[string]interface{}{"deletion_allowed": true})
+	if len(resp.Warnings) != 1 {
+		t.Fatalf("expected a warning about the registered clusters: %#v", resp)
+	}
+	mustFail(logical.DeleteOperation, "keys/unseal", nil)
+	mustFail(logical.UpdateOperation, "keys/unseal/soft-delete", nil)
+
+	mustSucceed(logical.DeleteOperation, "keys/unseal/seal-clients/east", nil)
+	mustSucceed(logical.DeleteOperation, "keys/unseal/seal-clients/west", nil)
+	mustSucceed(logical.UpdateOperation, "keys/unseal/config", map[string]interface{}{"seal_provider": false})
+	mustSucceed(logical.UpdateOperation, "hmac/unseal", map[string]interface{}{"input": "dGhlIHF1aWNrIGJyb3duIGZveA=="})
+	mustSucceed(logical.DeleteOperation, "keys/unseal", nil)
+}
@@ -141,6 +141,12 @@ Must be 0 (for latest) or a value greater than or equal
 to the min_encryption_version configured on the key.`,
 			},
 
+			"alias": {
+				Type: framework.TypeString,
+				Description: `The name of an alias of the key, whose pinned version
+is used for signing. Cannot be given along with key_version.`,
+			},
+
 			"prehashed": {
 				Type:        framework.TypeBool,
 				Description: `Set to 'true' when the input is already hashed. If the key type is 'rsa-2048', 'rsa-3072' or 'rsa-4096', then the algorithm used to hash the input should be indicated by the 'algorithm' parameter.`,
@@ -155,7 +161,12 @@ Options are 'pss' or 'pkcs1v15'. Defaults to 'pss'`,
 			"marshaling_algorithm": {
 				Type:        framework.TypeString,
 				Default:     "asn1",
-				Description: `The method by which to marshal the signature. The default is 'asn1' which is used by openssl and X.509. It can also be set to 'jws' which is used for JWT signatures; setting it to this will also cause the encoding of the signature to be url-safe base64 instead of using standard base64 encoding. Currently only valid for ECDSA P-256 key types".`,
+				Description: `The method by which to marshal the signature. The default is 'asn1' which is used by openssl and X.509. It can also be set to 'jws' which is used for JWT signatures; setting it to this will also cause the encoding of the signature to be url-safe base64 instead of using standard base64 encoding. It can also be set to 'raw' for the fixed-length concatenation of r and s in standard base64 encoding. Currently only valid for ECDSA key types".`,
+			},
+
+			"deterministic": {
+				Type:        framework.TypeBool,
+				Description: `Set to 'true' to generate ECDSA nonces deterministically as described by RFC 6979, so that signing the same input with the same key version always produces the same signature. Currently only applies to ECDSA key types.`,
 			},
 
 			"salt_length": {
@@ -265,7 +276,7 @@ Options are 'pss' or 'pkcs1v15'. Defaults to 'pss'`,
 			"marshaling_algorithm": {
 				Type:        framework.TypeString,
 				Default:     "asn1",
-				Description: `The method by which to unmarshal the signature when verifying. The default is 'asn1' which is used by openssl and X.509; can also be set to 'jws' which is used for JWT signatures in which case the signature is also expected to be url-safe base64 encoding instead of standard base64 encoding. Currently only valid for ECDSA P-256 key types".`,
+				Description: `The method by which to unmarshal the signature when verifying. The default is 'asn1' which is used by openssl and X.509; can also be set to 'jws' which is used for JWT signatures in which case the signature is also expected to be url-safe base64 encoding instead of standard base64 encoding; can also be set to 'raw' for the fixed-length concatenation of r and s in standard base64 encoding. Currently only valid for ECDSA key types".`,
 			},
 
 			"salt_length": {
@@ -322,7 +333,10 @@ func (b *backend) getSaltLength(d *framework.FieldData) (int, error) {
 
 func (b *backend) pathSignWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
 	name := d.Get("name").(string)
-	ver := d.Get("key_version").(int)
+	ver, resp, err := b.resolveKeyAlias(ctx, req.Storage, name, d.Get("alias").(string), d.Get("key_version").(int))
+	if resp != nil || err != nil {
+		return resp, err
+	}
 	hashAlgorithmStr := d.Get("urlalgorithm").(string)
 	if hashAlgorithmStr == "" {
 		hashAlgorithmStr = d.Get("hash_algorithm").(string)
@@ -347,6 +361,7 @@ func (b *backend) pathSignWrite(ctx context.Context, req *logical.Request, d *fr
 
 	prehashed := d.Get("prehashed").(bool)
 	sigAlgorithm := d.Get("signature_algorithm").(string)
+	deterministic := d.Get("deterministic").(bool)
 	saltLength, err := b.getSaltLength(d)
 	if err != nil {
 		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
@@ -368,6 +383,14 @@ func (b *backend) pathSignWrite(ctx context.Context, req *logical.Request, d *fr
 	}
 	defer p.Unlock()
 
+	if err := b.checkRestrictedCrypto(p.Type); err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	if p.SealProvider {
+		return sealProviderResponse(p)
+	}
+
 	if !p.Type.SigningSupported() {
 		return logical.ErrorResponse(fmt.Sprintf("key type %v does not support signing", p.Type)), logical.ErrInvalidRequest
 	}
@@ -438,6 +461,7 @@ func (b *backend) pathSignWrite(ctx context.Context, req *logical.Request, d *fr
 			Marshaling:    marshaling,
 			SaltLength:    saltLength,
 			SigAlgorithm:  sigAlgorithm,
+			Deterministic: deterministic,
 		})
 		if err != nil {
 			if batchInputRaw != nil {
@@ -459,7 +483,7 @@ func (b *backend) pathSignWrite(ctx context.Context, req *logical.Request, d *fr
 	}
 
 	// Generate the response
-	resp := &logical.Response{}
+	resp = &logical.Response{}
 	if batchInputRaw != nil {
 		// Copy the references
 		for i := range batchInputItems {
@@ -604,6 +628,14 @@ func (b *backend) pathVerifyWrite(ctx context.Context, req *logical.Request, d *
 	}
 	defer p.Unlock()
 
+	if err := b.checkRestrictedCrypto(p.Type); err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	if p.SealProvider {
+		return sealProviderResponse(p)
+	}
+
 	if !p.Type.SigningSupported() {
 		return logical.ErrorResponse(fmt.Sprintf("key type %v does not support verification", p.Type)), logical.ErrInvalidRequest
 	}
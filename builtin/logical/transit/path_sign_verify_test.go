@@ -292,6 +292,31 @@ func testTransit_SignVerify_ECDSA(t *testing.T, bits int) {
 	delete(req.Data, "marshaling_algorithm")
 	verifyRequest(req, true, "", sig)
 
+	// Sign with raw, verify we get fixed-length r||s and can validate
+	req.Data["marshaling_algorithm"] = "raw"
+	sig = signRequest(req, false, "")
+	verifyRequest(req, false, "", sig)
+	rawSig, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(sig, "vault:v1:"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rawSig) != 2*((bits+7)/8) {
+		t.Fatalf("bad raw signature length %d", len(rawSig))
+	}
+
+	// Deterministic signatures of the same input are identical
+	req.Data["deterministic"] = true
+	sig = signRequest(req, false, "")
+	if again := signRequest(req, false, ""); again != sig {
+		t.Fatalf("deterministic signatures differ: %s != %s", sig, again)
+	}
+	delete(req.Data, "deterministic")
+	verifyRequest(req, false, "", sig)
+	if random := signRequest(req, false, ""); random == sig {
+		t.Fatalf("random signature matches the deterministic one")
+	}
+	delete(req.Data, "marshaling_algorithm")
+
 	// Test 512 and save sig for later to ensure we can't validate once min
 	// decryption version is set
 	req.Data["hash_algorithm"] = "sha2-512"
@@ -93,6 +93,10 @@ func (b *backend) pathTrimUpdate() framework.OperationFunc {
 			return logical.ErrorResponse("minimum available version should be positive"), nil
 		}
 
+		if resp, err := b.checkKeyAliases(ctx, req.Storage, name, minAvailableVersion); resp != nil || err != nil {
+			return resp, err
+		}
+
 		// Ensure that cache doesn't get corrupted in error cases
 		p.MinAvailableVersion = minAvailableVersion
 		if err := p.Persist(ctx, req.Storage); err != nil {
@@ -41,6 +41,7 @@ import (
 	"github.com/openbao/openbao/command/agentproxyshared/auth"
 	cache "github.com/openbao/openbao/command/agentproxyshared/cache"
 	"github.com/openbao/openbao/command/agentproxyshared/sink"
+	"github.com/openbao/openbao/command/agentproxyshared/sink/dpapi"
 	"github.com/openbao/openbao/command/agentproxyshared/sink/file"
 	"github.com/openbao/openbao/command/agentproxyshared/sink/inmem"
 	"github.com/openbao/openbao/command/agentproxyshared/winsvc"
@@ -353,6 +354,24 @@ func (c *AgentCommand) Run(args []string) int {
 				}
 				config.Sink = s
 				sinks = append(sinks, config)
+			case "dpapi":
+				config := &sink.SinkConfig{
+					Logger:    c.logger.Named("sink.dpapi"),
+					Config:    sc.Config,
+					Client:    sinkClient,
+					WrapTTL:   sc.WrapTTL,
+					DHType:    sc.DHType,
+					DeriveKey: sc.DeriveKey,
+					DHPath:    sc.DHPath,
+					AAD:       sc.AAD,
+				}
+				s, err := dpapi.NewDPAPISink(config)
+				if err != nil {
+					c.UI.Error(fmt.Errorf("error creating dpapi sink: %w", err).Error())
+					return 1
+				}
+				config.Sink = s
+				sinks = append(sinks, config)
 			default:
 				c.UI.Error(fmt.Sprintf("Unknown sink type %q", sc.Type))
 				return 1
@@ -442,10 +461,12 @@ func (c *AgentCommand) Run(args []string) int {
 		// Create the lease cache proxier and set its underlying proxier to
 		// the API proxier.
 		leaseCache, err = cache.NewLeaseCache(&cache.LeaseCacheConfig{
-			Client:      proxyClient,
-			BaseContext: ctx,
-			Proxier:     apiProxy,
-			Logger:      cacheLogger.Named("leasecache"),
+			Client:             proxyClient,
+			BaseContext:        ctx,
+			Proxier:            apiProxy,
+			Logger:             cacheLogger.Named("leasecache"),
+			CacheStaticSecrets: config.Cache.CacheStaticSecrets,
+			StaticSecretTTL:    config.Cache.StaticSecretTTL,
 		})
 		if err != nil {
 			c.UI.Error(fmt.Sprintf("Error creating lease cache: %v", err))
@@ -528,6 +549,7 @@ func (c *AgentCommand) Run(args []string) int {
 		} else {
 			muxHandler = cache.ProxyHandler(ctx, apiProxyLogger, apiProxy, inmemSink, proxyVaultToken)
 		}
+		muxHandler = cache.PathRulesHandler(apiProxyLogger, muxHandler, config.APIProxy.PathRules())
 
 		// Parse 'require_request_header' listener config option, and wrap
 		// the request handler if necessary
@@ -672,6 +694,14 @@ func (c *AgentCommand) Run(args []string) int {
 			ExitAfterAuth: config.ExitAfterAuth,
 		})
 
+		// The PKI template functions set their own token, so they work on a
+		// copy of the client as well.
+		pkiClient, err := client.CloneWithHeaders()
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error cloning client for pki template functions: %v", err))
+			return 1
+		}
+
 		ts := template.NewServer(&template.ServerConfig{
 			Logger:        c.logger.Named("template.server"),
 			LogLevel:      c.logger.GetLevel(),
@@ -679,6 +709,7 @@ func (c *AgentCommand) Run(args []string) int {
 			AgentConfig:   c.config,
 			Namespace:     templateNamespace,
 			ExitAfterAuth: config.ExitAfterAuth,
+			PKIClient:     pkiClient,
 		})
 
 		es := exec.NewServer(&exec.ServerConfig{
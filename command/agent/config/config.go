@@ -26,6 +26,7 @@ import (
 
 	"github.com/openbao/openbao/api/v2"
 	"github.com/openbao/openbao/command/agentproxyshared"
+	"github.com/openbao/openbao/command/agentproxyshared/cache"
 	"github.com/openbao/openbao/helper/namespace"
 	"github.com/openbao/openbao/internalshared/configutil"
 	"github.com/openbao/openbao/sdk/v2/helper/pointerutil"
@@ -105,9 +106,24 @@ type transportDialer interface {
 
 // APIProxy contains any configuration needed for proxy mode
 type APIProxy struct {
-	UseAutoAuthTokenRaw interface{} `hcl:"use_auto_auth_token"`
-	UseAutoAuthToken    bool        `hcl:"-"`
-	ForceAutoAuthToken  bool        `hcl:"-"`
+	UseAutoAuthTokenRaw interface{}         `hcl:"use_auto_auth_token"`
+	UseAutoAuthToken    bool                `hcl:"-"`
+	ForceAutoAuthToken  bool                `hcl:"-"`
+	MountAliases        []*cache.MountAlias `hcl:"-"`
+	AllowedPaths        []string            `hcl:"allowed_paths"`
+	DeniedPaths         []string            `hcl:"denied_paths"`
+}
+
+// PathRules returns the path rules applied to proxied requests.
+func (p *APIProxy) PathRules() *cache.PathRulesConfig {
+	if p == nil {
+		return nil
+	}
+	return &cache.PathRulesConfig{
+		MountAliases: p.MountAliases,
+		AllowedPaths: p.AllowedPaths,
+		DeniedPaths:  p.DeniedPaths,
+	}
 }
 
 // Cache contains any configuration needed for Cache mode
@@ -117,6 +133,9 @@ type Cache struct {
 	ForceAutoAuthToken  bool                            `hcl:"-"`
 	Persist             *agentproxyshared.PersistConfig `hcl:"persist"`
 	InProcDialer        transportDialer                 `hcl:"-"`
+	CacheStaticSecrets  bool                            `hcl:"cache_static_secrets"`
+	StaticSecretTTLRaw  interface{}                     `hcl:"static_secret_ttl"`
+	StaticSecretTTL     time.Duration                   `hcl:"-"`
 }
 
 // AutoAuth is the configured authentication method and sinks
@@ -162,14 +181,22 @@ type TemplateConfig struct {
 	ExitOnRetryFailure       bool          `hcl:"exit_on_retry_failure"`
 	StaticSecretRenderIntRaw interface{}   `hcl:"static_secret_render_interval"`
 	StaticSecretRenderInt    time.Duration `hcl:"-"`
+	PKIRenewThreshold        float64       `hcl:"pki_renew_threshold"`
 }
 
 type ExecConfig struct {
-	Command                []string  `hcl:"command,attr" mapstructure:"command"`
-	RestartOnSecretChanges string    `hcl:"restart_on_secret_changes,optional" mapstructure:"restart_on_secret_changes"`
-	RestartStopSignal      os.Signal `hcl:"-" mapstructure:"restart_stop_signal"`
+	Command                []string      `hcl:"command,attr" mapstructure:"command"`
+	RestartOnSecretChanges string        `hcl:"restart_on_secret_changes,optional" mapstructure:"restart_on_secret_changes"`
+	RestartStopSignal      os.Signal     `hcl:"-" mapstructure:"restart_stop_signal"`
+	RestartStopTimeout     time.Duration `hcl:"-" mapstructure:"restart_stop_timeout"`
+	ChildProcessStdout     string        `hcl:"child_process_stdout,optional" mapstructure:"child_process_stdout"`
+	ChildProcessStderr     string        `hcl:"child_process_stderr,optional" mapstructure:"child_process_stderr"`
 }
 
+// DefaultExecRestartStopTimeout is how long the child process is given to
+// exit after the stop signal before it is killed.
+const DefaultExecRestartStopTimeout = 30 * time.Second
+
 func NewConfig() *Config {
 	return &Config{
 		SharedConfig: new(configutil.SharedConfig),
@@ -815,11 +842,30 @@ func parseAPIProxy(result *Config, list *ast.ObjectList) error {
 			}
 		}
 	}
+	if o, ok := item.Val.(*ast.ObjectType); ok {
+		if err := parseMountAliases(&apiProxy, o.List); err != nil {
+			return fmt.Errorf("error parsing 'mount_alias': %w", err)
+		}
+	}
+	if err := apiProxy.PathRules().Validate(); err != nil {
+		return err
+	}
 	result.APIProxy = &apiProxy
 
 	return nil
 }
 
+func parseMountAliases(apiProxy *APIProxy, list *ast.ObjectList) error {
+	for _, item := range list.Filter("mount_alias").Items {
+		var alias cache.MountAlias
+		if err := hcl.DecodeObject(&alias, item.Val); err != nil {
+			return err
+		}
+		apiProxy.MountAliases = append(apiProxy.MountAliases, &alias)
+	}
+	return nil
+}
+
 func parseCache(result *Config, list *ast.ObjectList) error {
 	name := "cache"
 
@@ -859,6 +905,17 @@ func parseCache(result *Config, list *ast.ObjectList) error {
 			}
 		}
 	}
+
+	if c.StaticSecretTTLRaw != nil {
+		if c.StaticSecretTTL, err = parseutil.ParseDurationSecond(c.StaticSecretTTLRaw); err != nil {
+			return fmt.Errorf("error parsing 'static_secret_ttl': %w", err)
+		}
+		if c.StaticSecretTTL < 0 {
+			return errors.New("'static_secret_ttl' must not be negative")
+		}
+		c.StaticSecretTTLRaw = nil
+	}
+
 	result.Cache = &c
 
 	subs, ok := item.Val.(*ast.ObjectType)
@@ -1116,6 +1173,10 @@ func parseTemplateConfig(result *Config, list *ast.ObjectList) error {
 		result.TemplateConfig.StaticSecretRenderIntRaw = nil
 	}
 
+	if threshold := result.TemplateConfig.PKIRenewThreshold; threshold < 0 || threshold >= 1 {
+		return fmt.Errorf("'pki_renew_threshold' must be between 0 and 1, got %v", threshold)
+	}
+
 	return nil
 }
 
@@ -1235,8 +1296,19 @@ func parseExec(result *Config, list *ast.ObjectList) error {
 		execConfig.RestartStopSignal = syscall.SIGTERM
 	}
 
-	if execConfig.RestartOnSecretChanges == "" {
+	switch execConfig.RestartOnSecretChanges {
+	case "":
 		execConfig.RestartOnSecretChanges = "always"
+	case "always", "never":
+	default:
+		return fmt.Errorf("invalid value for 'restart_on_secret_changes': %q, must be \"always\" or \"never\"", execConfig.RestartOnSecretChanges)
+	}
+
+	switch {
+	case execConfig.RestartStopTimeout == 0:
+		execConfig.RestartStopTimeout = DefaultExecRestartStopTimeout
+	case execConfig.RestartStopTimeout < 0:
+		return errors.New("'restart_stop_timeout' must not be negative")
 	}
 
 	result.Exec = &execConfig
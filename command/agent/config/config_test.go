@@ -986,6 +986,20 @@ func TestLoadConfigFile_AgentCache_PersistMissingType(t *testing.T) {
 	}
 }
 
+func TestLoadConfigFile_AgentCache_StaticSecrets(t *testing.T) {
+	config, err := LoadConfigFile("./test-fixtures/config-cache-static-secrets.hcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !config.Cache.CacheStaticSecrets {
+		t.Fatal("expected cache_static_secrets to be set")
+	}
+	if config.Cache.StaticSecretTTL != 5*time.Minute {
+		t.Fatalf("expected static_secret_ttl to be 5m, got %v", config.Cache.StaticSecretTTL)
+	}
+}
+
 func TestLoadConfigFile_TemplateConfig(t *testing.T) {
 	testCases := map[string]struct {
 		fixturePath            string
@@ -996,6 +1010,7 @@ func TestLoadConfigFile_TemplateConfig(t *testing.T) {
 			TemplateConfig{
 				ExitOnRetryFailure:    true,
 				StaticSecretRenderInt: 1 * time.Minute,
+				PKIRenewThreshold:     0.75,
 			},
 		},
 		"empty": {
@@ -2191,6 +2206,10 @@ func TestLoadConfigFile_EnvTemplates_ExecSimple(t *testing.T) {
 	if cfg.Exec.RestartStopSignal != syscall.SIGTERM {
 		t.Fatalf("expected cfg.Exec.RestartStopSignal to be 'syscall.SIGTERM', got '%s'", cfg.Exec.RestartStopSignal)
 	}
+
+	if cfg.Exec.RestartStopTimeout != DefaultExecRestartStopTimeout {
+		t.Fatalf("expected cfg.Exec.RestartStopTimeout to be %v, got %v", DefaultExecRestartStopTimeout, cfg.Exec.RestartStopTimeout)
+	}
 }
 
 // TestLoadConfigFile_EnvTemplates_ExecComplex validates the exec section with non-default parameters
@@ -2215,6 +2234,23 @@ func TestLoadConfigFile_EnvTemplates_ExecComplex(t *testing.T) {
 	if cfg.Exec.RestartStopSignal != syscall.SIGINT {
 		t.Fatalf("expected cfg.Exec.RestartStopSignal to be 'syscall.SIGINT', got %q", cfg.Exec.RestartStopSignal)
 	}
+
+	if cfg.Exec.RestartStopTimeout != 5*time.Second {
+		t.Fatalf("expected cfg.Exec.RestartStopTimeout to be 5s, got %v", cfg.Exec.RestartStopTimeout)
+	}
+
+	if cfg.Exec.ChildProcessStdout != "/var/log/app/stdout.log" || cfg.Exec.ChildProcessStderr != "/var/log/app/stderr.log" {
+		t.Fatalf("unexpected child process output paths: %q, %q", cfg.Exec.ChildProcessStdout, cfg.Exec.ChildProcessStderr)
+	}
+}
+
+// TestLoadConfigFile_EnvTemplates_ExecInvalidRestart ensures that an invalid
+// restart_on_secret_changes value triggers an error
+func TestLoadConfigFile_EnvTemplates_ExecInvalidRestart(t *testing.T) {
+	_, err := LoadConfigFile("./test-fixtures/bad-config-env-templates-invalid-restart.hcl")
+	if err == nil {
+		t.Fatalf("expected error")
+	}
 }
 
 // TestLoadConfigFile_Bad_EnvTemplates_MissingExec ensures that ValidateConfig
@@ -0,0 +1,21 @@
+pid_file = "./pidfile"
+
+auto_auth {
+	method {
+		type = "token_file"
+		config = {
+			token_file_path = "/home/username/.vault-token"
+		}
+	}
+}
+
+cache {
+	use_auto_auth_token  = true
+	cache_static_secrets = true
+	static_secret_ttl    = "5m"
+}
+
+listener "tcp" {
+	address     = "127.0.0.1:8300"
+	tls_disable = true
+}
@@ -33,4 +33,7 @@ exec {
   command                   = ["env"]
   restart_on_secret_changes = "never"
   restart_stop_signal       = "SIGINT"
+  restart_stop_timeout      = "5s"
+  child_process_stdout      = "/var/log/app/stdout.log"
+  child_process_stderr      = "/var/log/app/stderr.log"
 }
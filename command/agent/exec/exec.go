@@ -72,6 +72,11 @@ type Server struct {
 	// lastRenderedEnvVars is the cached value of all environment variables
 	// rendered by the templating engine; it is used for detecting changes
 	lastRenderedEnvVars []string
+
+	// childProcessStdout and childProcessStderr are the streams the child
+	// process writes to; by default the agent's own
+	childProcessStdout io.Writer
+	childProcessStderr io.Writer
 }
 
 type ProcessExitError struct {
@@ -88,6 +93,8 @@ func NewServer(cfg *ServerConfig) *Server {
 		config:             cfg,
 		childProcessState:  childProcessStateNotStarted,
 		childProcessExitCh: make(chan int),
+		childProcessStdout: os.Stdout,
+		childProcessStderr: os.Stderr,
 	}
 
 	return &server
@@ -106,6 +113,24 @@ func (s *Server) Run(ctx context.Context, incomingVaultToken chan string) error
 		return nil
 	}
 
+	execConfig := s.config.AgentConfig.Exec
+	if execConfig.ChildProcessStdout != "" {
+		f, err := openChildProcessLog(execConfig.ChildProcessStdout)
+		if err != nil {
+			return fmt.Errorf("could not open child process stdout: %w", err)
+		}
+		defer f.Close()
+		s.childProcessStdout = f
+	}
+	if execConfig.ChildProcessStderr != "" {
+		f, err := openChildProcessLog(execConfig.ChildProcessStderr)
+		if err != nil {
+			return fmt.Errorf("could not open child process stderr: %w", err)
+		}
+		defer f.Close()
+		s.childProcessStderr = f
+	}
+
 	managerConfig := ctmanager.ManagerConfig{
 		AgentConfig: s.config.AgentConfig,
 		Namespace:   s.config.Namespace,
@@ -286,17 +311,22 @@ func (s *Server) restartChildProcess(newEnvVars []string) error {
 		return fmt.Errorf("unable to parse command: %w", err)
 	}
 
+	killTimeout := s.config.AgentConfig.Exec.RestartStopTimeout
+	if killTimeout == 0 {
+		killTimeout = config.DefaultExecRestartStopTimeout
+	}
+
 	childInput := &child.NewInput{
 		Stdin:        os.Stdin,
-		Stdout:       os.Stdout,
-		Stderr:       os.Stderr,
+		Stdout:       s.childProcessStdout,
+		Stderr:       s.childProcessStderr,
 		Command:      args[0],
 		Args:         args[1:],
 		Timeout:      0, // let it run forever
 		Env:          append(os.Environ(), newEnvVars...),
 		ReloadSignal: nil, // can't reload w/ new env vars
 		KillSignal:   s.config.AgentConfig.Exec.RestartStopSignal,
-		KillTimeout:  30 * time.Second,
+		KillTimeout:  killTimeout,
 		Splay:        0,
 		Setpgid:      subshell,
 		Logger:       s.logger.StandardLogger(nil),
@@ -330,3 +360,9 @@ func (s *Server) restartChildProcess(newEnvVars []string) error {
 
 	return nil
 }
+
+// openChildProcessLog opens the file at path for appending the output of
+// the child process, creating it if necessary.
+func openChildProcessLog(path string) (*os.File, error) {
+	return os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o640)
+}
@@ -0,0 +1,365 @@
+// Package pki provides the template functions issuing certificates from the
+// PKI secrets engine. A certificate is shared by all the templates requesting
+// it with the same arguments, so that the certificate, its private key and its
+// CA chain can be rendered to separate files, and it is renewed once a
+// configured fraction of its lifetime has passed. Writing the files atomically,
+// their permissions and running a command after a renewal are left to the
+// template stanzas rendering them.
+package pki
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+
+	"github.com/openbao/openbao/api/v2"
+)
+
+const (
+	// DefaultRenewThreshold is the fraction of a certificate's lifetime after
+	// which it is renewed.
+	DefaultRenewThreshold = 0.9
+
+	minRetryBackoff = time.Second
+	maxRetryBackoff = 5 * time.Minute
+)
+
+// Certificate is the value returned by the template functions, with PEM
+// encoded fields.
+type Certificate struct {
+	Cert         string
+	Key          string
+	CA           string
+	CAChain      string
+	SerialNumber string
+	Expiration   time.Time
+}
+
+// ManagerConfig is a config struct for setting up the Manager
+type ManagerConfig struct {
+	Logger hclog.Logger
+
+	// Client is used to request certificates. Its token is replaced by
+	// SetToken, so it must not be shared.
+	Client *api.Client
+
+	// RenewThreshold is the fraction of a certificate's lifetime after which
+	// it is renewed, DefaultRenewThreshold if zero.
+	RenewThreshold float64
+}
+
+// Manager issues the certificates requested by templates and renews them
+type Manager struct {
+	logger         hclog.Logger
+	client         *api.Client
+	renewThreshold float64
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	lock  sync.Mutex
+	certs map[string]*entry
+
+	renewedCh chan struct{}
+}
+
+// entry is a certificate shared by the templates requesting it with the same
+// arguments.
+type entry struct {
+	path    string
+	data    map[string]interface{}
+	csrFile string
+
+	// lock serializes the requests for the certificate
+	lock    sync.Mutex
+	cert    *Certificate
+	leaf    *x509.Certificate
+	retryAt time.Time
+	backoff time.Duration
+	timer   *time.Timer
+}
+
+// NewManager returns a new configured manager
+func NewManager(conf *ManagerConfig) *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	threshold := conf.RenewThreshold
+	if threshold == 0 {
+		threshold = DefaultRenewThreshold
+	}
+
+	return &Manager{
+		logger:         conf.Logger,
+		client:         conf.Client,
+		renewThreshold: threshold,
+		ctx:            ctx,
+		cancel:         cancel,
+		certs:          make(map[string]*entry),
+		renewedCh:      make(chan struct{}, 1),
+	}
+}
+
+// FuncMap returns the template functions:
+//
+//	pkiIssue "pki/issue/<role>" "common_name=example.com" ...
+//	pkiSign "pki/sign/<role>" "/path/to/csr.pem" "common_name=example.com" ...
+func (m *Manager) FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"pkiIssue": m.issueFunc,
+		"pkiSign":  m.signFunc,
+	}
+}
+
+// SetToken replaces the token used for the following requests
+func (m *Manager) SetToken(token string) {
+	m.client.SetToken(token)
+}
+
+// RenewedCh receives a value once a certificate has been renewed, so that
+// the templates using it are rendered again.
+func (m *Manager) RenewedCh() <-chan struct{} {
+	return m.renewedCh
+}
+
+// Stop cancels the pending requests and renewals
+func (m *Manager) Stop() {
+	m.cancel()
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	for _, e := range m.certs {
+		e.lock.Lock()
+		if e.timer != nil {
+			e.timer.Stop()
+		}
+		e.lock.Unlock()
+	}
+}
+
+func (m *Manager) issueFunc(path string, args ...string) (*Certificate, error) {
+	data, err := parseArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	return m.certificate(&entry{path: path, data: data}, args)
+}
+
+func (m *Manager) signFunc(path, csrFile string, args ...string) (*Certificate, error) {
+	if csrFile == "" {
+		return nil, errors.New("pkiSign: missing CSR file")
+	}
+	data, err := parseArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	return m.certificate(&entry{path: path, data: data, csrFile: csrFile}, args)
+}
+
+// parseArgs parses the k=v arguments of the template functions, as the
+// secret function does.
+func parseArgs(args []string) (map[string]interface{}, error) {
+	data := make(map[string]interface{}, len(args))
+	for _, arg := range args {
+		if arg == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(arg, "=")
+		if !ok {
+			return nil, fmt.Errorf("not k=v pair %q", arg)
+		}
+		data[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	return data, nil
+}
+
+// certificate returns the certificate shared by the templates requesting e,
+// requesting it first if needed.
+func (m *Manager) certificate(e *entry, args []string) (*Certificate, error) {
+	sorted := append([]string{e.path, e.csrFile}, args...)
+	sort.Strings(sorted[2:])
+	key := strings.Join(sorted, "\x00")
+
+	m.lock.Lock()
+	if existing, ok := m.certs[key]; ok {
+		e = existing
+	} else {
+		m.certs[key] = e
+	}
+	m.lock.Unlock()
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.cert != nil {
+		return e.cert, nil
+	}
+
+	// A failed render is retried right away, so requests are throttled here.
+	if wait := time.Until(e.retryAt); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-m.ctx.Done():
+			timer.Stop()
+			return nil, m.ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if err := m.request(e); err != nil {
+		e.failed()
+		return nil, err
+	}
+
+	m.logger.Info("certificate issued", "path", e.path, "serial", e.cert.SerialNumber, "expiration", e.cert.Expiration)
+	m.scheduleRenewal(e, renewalTime(e.leaf, m.renewThreshold))
+	return e.cert, nil
+}
+
+// renew replaces the certificate of e with a new one. On failure, the
+// current certificate is kept and the renewal retried with a backoff.
+func (m *Manager) renew(e *entry) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if m.ctx.Err() != nil {
+		return
+	}
+
+	if err := m.request(e); err != nil {
+		e.failed()
+		m.logger.Error("failed to renew certificate", "path", e.path, "error", err, "backoff", e.backoff)
+		m.scheduleRenewal(e, e.retryAt)
+		return
+	}
+
+	m.logger.Info("certificate renewed", "path", e.path, "serial", e.cert.SerialNumber, "expiration", e.cert.Expiration)
+	m.scheduleRenewal(e, renewalTime(e.leaf, m.renewThreshold))
+
+	select {
+	case m.renewedCh <- struct{}{}:
+	default:
+	}
+}
+
+func (m *Manager) scheduleRenewal(e *entry, renewAt time.Time) {
+	e.timer = time.AfterFunc(time.Until(renewAt), func() {
+		m.renew(e)
+	})
+}
+
+// failed records a failed request, doubling the backoff before the next one.
+func (e *entry) failed() {
+	switch {
+	case e.backoff == 0:
+		e.backoff = minRetryBackoff
+	case e.backoff < maxRetryBackoff:
+		e.backoff = min(2*e.backoff, maxRetryBackoff)
+	}
+	e.retryAt = time.Now().Add(e.backoff)
+}
+
+// request requests a new certificate for e.
+func (m *Manager) request(e *entry) error {
+	data := make(map[string]interface{}, len(e.data)+1)
+	for k, v := range e.data {
+		data[k] = v
+	}
+	if e.csrFile != "" {
+		csr, err := os.ReadFile(e.csrFile)
+		if err != nil {
+			return fmt.Errorf("error reading CSR: %w", err)
+		}
+		data["csr"] = string(csr)
+	}
+
+	secret, err := m.client.Logical().WriteWithContext(m.ctx, e.path, data)
+	if err != nil {
+		return err
+	}
+	if secret == nil || secret.Data == nil {
+		return fmt.Errorf("no certificate returned from %q", e.path)
+	}
+
+	certPEM, _ := secret.Data["certificate"].(string)
+	if certPEM == "" {
+		return fmt.Errorf("no certificate returned from %q", e.path)
+	}
+	leaf, err := parseCertificate([]byte(certPEM))
+	if err != nil {
+		return err
+	}
+
+	keyPEM, _ := secret.Data["private_key"].(string)
+	if keyPEM == "" && e.csrFile == "" {
+		return fmt.Errorf("no private key returned from %q", e.path)
+	}
+	ca, _ := secret.Data["issuing_ca"].(string)
+
+	e.leaf = leaf
+	e.cert = &Certificate{
+		Cert:         certPEM,
+		Key:          keyPEM,
+		CA:           ca,
+		CAChain:      caChain(secret.Data),
+		SerialNumber: leaf.SerialNumber.String(),
+		Expiration:   leaf.NotAfter,
+	}
+	e.backoff = 0
+	e.retryAt = time.Time{}
+
+	return nil
+}
+
+// caChain returns the PEM encoded chain of the issuing CA, falling back to
+// the issuing CA alone for older responses without a chain.
+func caChain(data map[string]interface{}) string {
+	var chain []string
+	if raw, ok := data["ca_chain"].([]interface{}); ok {
+		for _, c := range raw {
+			if c, ok := c.(string); ok && c != "" {
+				chain = append(chain, strings.TrimSpace(c))
+			}
+		}
+	}
+	if len(chain) == 0 {
+		if ca, ok := data["issuing_ca"].(string); ok && ca != "" {
+			chain = append(chain, strings.TrimSpace(ca))
+		}
+	}
+	if len(chain) == 0 {
+		return ""
+	}
+	return strings.Join(chain, "\n") + "\n"
+}
+
+// renewalTime returns the point after which threshold of the certificate's
+// lifetime has passed, moved earlier by a random amount of up to 5% of the
+// lifetime so that agents sharing a role do not renew all at once.
+func renewalTime(leaf *x509.Certificate, threshold float64) time.Time {
+	lifetime := leaf.NotAfter.Sub(leaf.NotBefore)
+	if lifetime <= 0 {
+		return leaf.NotBefore
+	}
+
+	renewAt := leaf.NotBefore.Add(time.Duration(float64(lifetime) * threshold))
+	return renewAt.Add(-time.Duration(rand.Int63n(int64(lifetime)/20 + 1)))
+}
+
+func parseCertificate(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no PEM encoded certificate found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
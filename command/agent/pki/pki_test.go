@@ -0,0 +1,214 @@
+package pki
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"text/template"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openbao/openbao/api/v2"
+	"github.com/openbao/openbao/sdk/v2/helper/logging"
+)
+
+func testCertificate(t *testing.T, notBefore time.Time, lifetime time.Duration) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "example.com"},
+		NotBefore:    notBefore,
+		NotAfter:     notBefore.Add(lifetime),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return string(certPEM), string(keyPEM)
+}
+
+func testManager(t *testing.T, handler http.HandlerFunc, threshold float64) *Manager {
+	t.Helper()
+
+	httpServer := httptest.NewServer(handler)
+	t.Cleanup(httpServer.Close)
+
+	apiConfig := api.DefaultConfig()
+	apiConfig.Address = httpServer.URL
+	client, err := api.NewClient(apiConfig)
+	require.NoError(t, err)
+
+	m := NewManager(&ManagerConfig{
+		Logger:         logging.NewVaultLogger(hclog.Trace),
+		Client:         client,
+		RenewThreshold: threshold,
+	})
+	m.SetToken("test-token")
+	t.Cleanup(m.Stop)
+	return m
+}
+
+func render(t *testing.T, m *Manager, contents string) string {
+	t.Helper()
+
+	tmpl, err := template.New("").Funcs(m.FuncMap()).Parse(contents)
+	require.NoError(t, err)
+
+	var out strings.Builder
+	require.NoError(t, tmpl.Execute(&out, nil))
+	return out.String()
+}
+
+func TestManager_Issue(t *testing.T) {
+	certPEM, keyPEM := testCertificate(t, time.Now(), time.Hour)
+	caPEM, _ := testCertificate(t, time.Now(), time.Hour)
+
+	var requests atomic.Int32
+	m := testManager(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		require.Equal(t, "/v1/pki/issue/web", r.URL.Path)
+		require.Equal(t, "test-token", r.Header.Get(api.AuthHeaderName))
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, "web.example.com", body["common_name"])
+		require.Equal(t, "1h", body["ttl"])
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"certificate": certPEM,
+				"private_key": keyPEM,
+				"issuing_ca":  caPEM,
+				"ca_chain":    []string{caPEM},
+			},
+		})
+	}, 0)
+
+	// Templates requesting the same certificate share it, whatever the order
+	// of the arguments.
+	key := render(t, m, `{{ with pkiIssue "pki/issue/web" "common_name=web.example.com" "ttl=1h" }}{{ .Key }}{{ end }}`)
+	cert := render(t, m, `{{ with pkiIssue "pki/issue/web" "ttl=1h" "common_name=web.example.com" }}{{ .Cert }}{{ end }}`)
+	chain := render(t, m, `{{ with pkiIssue "pki/issue/web" "common_name=web.example.com" "ttl=1h" }}{{ .CAChain }}{{ end }}`)
+
+	require.Equal(t, int32(1), requests.Load())
+	require.Equal(t, keyPEM, key)
+	require.Equal(t, certPEM, cert)
+	require.Equal(t, caPEM, chain)
+}
+
+func TestManager_Sign(t *testing.T) {
+	certPEM, _ := testCertificate(t, time.Now(), time.Hour)
+
+	m := testManager(t, func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/pki/sign/client", r.URL.Path)
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		require.Equal(t, "test csr", body["csr"])
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"certificate": certPEM,
+			},
+		})
+	}, 0)
+
+	csrFile := filepath.Join(t.TempDir(), "client.csr")
+	require.NoError(t, os.WriteFile(csrFile, []byte("test csr"), 0o600))
+
+	cert := render(t, m, `{{ with pkiSign "pki/sign/client" "`+csrFile+`" }}{{ .Cert }}{{ .Key }}{{ end }}`)
+	require.Equal(t, certPEM, cert)
+}
+
+func TestManager_Renew(t *testing.T) {
+	var requests atomic.Int32
+	m := testManager(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		certPEM, keyPEM := testCertificate(t, time.Now(), 2*time.Second)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"certificate": certPEM,
+				"private_key": keyPEM,
+			},
+		})
+	}, 0.5)
+
+	contents := `{{ with pkiIssue "pki/issue/web" "common_name=web.example.com" }}{{ .SerialNumber }}{{ end }}`
+	first := render(t, m, contents)
+
+	select {
+	case <-m.RenewedCh():
+	case <-time.After(10 * time.Second):
+		t.Fatal("certificate was not renewed")
+	}
+
+	require.GreaterOrEqual(t, requests.Load(), int32(2))
+	require.NotEqual(t, first, render(t, m, contents))
+}
+
+func TestManager_Failure(t *testing.T) {
+	m := testManager(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}, 0)
+
+	tmpl, err := template.New("").Funcs(m.FuncMap()).Parse(`{{ pkiIssue "pki/issue/web" "common_name=web.example.com" }}`)
+	require.NoError(t, err)
+	require.Error(t, tmpl.Execute(&strings.Builder{}, nil))
+
+	tmpl, err = template.New("").Funcs(m.FuncMap()).Parse(`{{ pkiIssue "pki/issue/web" "common_name" }}`)
+	require.NoError(t, err)
+	require.ErrorContains(t, tmpl.Execute(&strings.Builder{}, nil), "not k=v pair")
+}
+
+func TestEntry_Failed(t *testing.T) {
+	var e entry
+	for _, expected := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second} {
+		e.failed()
+		require.Equal(t, expected, e.backoff)
+	}
+
+	for i := 0; i < 20; i++ {
+		e.failed()
+	}
+	require.Equal(t, maxRetryBackoff, e.backoff)
+	require.True(t, e.retryAt.After(time.Now()))
+}
+
+func TestRenewalTime(t *testing.T) {
+	notBefore := time.Now().Truncate(time.Second)
+	leaf := &x509.Certificate{
+		NotBefore: notBefore,
+		NotAfter:  notBefore.Add(100 * time.Hour),
+	}
+
+	for i := 0; i < 100; i++ {
+		renewAt := renewalTime(leaf, 0.9)
+		require.False(t, renewAt.After(notBefore.Add(90*time.Hour)))
+		require.False(t, renewAt.Before(notBefore.Add(85*time.Hour)))
+	}
+}
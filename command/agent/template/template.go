@@ -13,6 +13,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 
 	"go.uber.org/atomic"
 
@@ -20,8 +21,10 @@ import (
 	ctconfig "github.com/openbao/openbao-template/config"
 	"github.com/openbao/openbao-template/manager"
 
+	"github.com/openbao/openbao/api/v2"
 	"github.com/openbao/openbao/command/agent/config"
 	"github.com/openbao/openbao/command/agent/internal/ctmanager"
+	"github.com/openbao/openbao/command/agent/pki"
 	"github.com/openbao/openbao/helper/useragent"
 	"github.com/openbao/openbao/sdk/v2/helper/pointerutil"
 )
@@ -44,6 +47,11 @@ type ServerConfig struct {
 	// the same io.Writer that Vault Agent itself is using.
 	LogLevel  hclog.Level
 	LogWriter io.Writer
+
+	// PKIClient is used by the PKI template functions to request
+	// certificates. Its token is replaced by every token received, so it must
+	// not be shared. The functions are not available if it is nil.
+	PKIClient *api.Client
 }
 
 // Server manages the Consul Template Runner which renders templates
@@ -122,6 +130,31 @@ func (ts *Server) Run(ctx context.Context, incoming chan string, templates []*ct
 		return fmt.Errorf("template server failed to runner generate config: %w", runnerConfigErr)
 	}
 
+	// The PKI template functions share their certificates across templates,
+	// and signal renewals so that the templates are rendered again.
+	var pkiManager *pki.Manager
+	var pkiRenewedCh <-chan struct{}
+	if ts.config.PKIClient != nil {
+		var threshold float64
+		if ts.config.AgentConfig.TemplateConfig != nil {
+			threshold = ts.config.AgentConfig.TemplateConfig.PKIRenewThreshold
+		}
+		pkiManager = pki.NewManager(&pki.ManagerConfig{
+			Logger:         ts.logger.Named("pki"),
+			Client:         ts.config.PKIClient,
+			RenewThreshold: threshold,
+		})
+		defer pkiManager.Stop()
+		pkiRenewedCh = pkiManager.RenewedCh()
+
+		for _, tmpl := range *runnerConfig.Templates {
+			if tmpl.ExtFuncMap == nil {
+				tmpl.ExtFuncMap = make(map[string]interface{})
+			}
+			maps.Copy(tmpl.ExtFuncMap, pkiManager.FuncMap())
+		}
+	}
+
 	var err error
 	ts.runner, err = manager.NewRunner(runnerConfig, false)
 	if err != nil {
@@ -165,6 +198,9 @@ func (ts *Server) Run(ctx context.Context, incoming chan string, templates []*ct
 
 				ts.runner.Stop()
 				*latestToken = token
+				if pkiManager != nil {
+					pkiManager.SetToken(token)
+				}
 				ctv := ctconfig.Config{
 					Vault: &ctconfig.VaultConfig{
 						Token:           latestToken,
@@ -183,6 +219,23 @@ func (ts *Server) Run(ctx context.Context, incoming chan string, templates []*ct
 				go ts.runner.Start()
 			}
 
+		case <-pkiRenewedCh:
+			// Templates are only rendered again when their dependencies
+			// change, so the runner is restarted to render the new
+			// certificate. Unchanged files are not written again.
+			if ts.exitAfterAuth {
+				continue
+			}
+			ts.logger.Info("template server rendering renewed certificates")
+
+			ts.runner.Stop()
+			var runnerErr error
+			ts.runner, runnerErr = manager.NewRunner(runnerConfig, false)
+			if runnerErr != nil {
+				return fmt.Errorf("template server failed to create: %w", runnerErr)
+			}
+			go ts.runner.Start()
+
 		case err := <-ts.runner.ErrCh:
 			ts.logger.Error("template server error", "error", err.Error())
 			ts.runner.StopImmediately()
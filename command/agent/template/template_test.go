@@ -5,17 +5,27 @@ package template
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/hashicorp/go-hclog"
 	ctconfig "github.com/openbao/openbao-template/config"
+	"github.com/openbao/openbao/api/v2"
 	"github.com/openbao/openbao/command/agent/config"
 	"github.com/openbao/openbao/command/agent/internal/ctmanager"
 	"github.com/openbao/openbao/command/agentproxyshared"
@@ -447,6 +457,117 @@ func TestServerRun(t *testing.T) {
 	}
 }
 
+// TestServerRun_PKI renders a certificate and its key issued by the PKI
+// template functions to separate files, and renders them again once the
+// certificate is renewed.
+func TestServerRun_PKI(t *testing.T) {
+	var requests atomic.Int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/pki/issue/web", r.URL.Path)
+		requests.Add(1)
+
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+		require.NoError(t, err)
+		cert := &x509.Certificate{
+			SerialNumber: serial,
+			NotBefore:    time.Now(),
+			NotAfter:     time.Now().Add(4 * time.Second),
+		}
+		der, err := x509.CreateCertificate(rand.Reader, cert, cert, &key.PublicKey, key)
+		require.NoError(t, err)
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		require.NoError(t, err)
+
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{
+				"certificate": string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+				"private_key": string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})),
+			},
+		})
+	}))
+	defer ts.Close()
+
+	apiConfig := api.DefaultConfig()
+	apiConfig.Address = ts.URL
+	pkiClient, err := api.NewClient(apiConfig)
+	require.NoError(t, err)
+
+	tmpDir := t.TempDir()
+	certFile := filepath.Join(tmpDir, "tls.crt")
+	keyFile := filepath.Join(tmpDir, "tls.key")
+	templates := []*ctconfig.TemplateConfig{
+		{
+			Contents:    pointerutil.StringPtr(`{{ with pkiIssue "pki/issue/web" "common_name=web.example.com" }}{{ .Cert }}{{ end }}`),
+			Destination: pointerutil.StringPtr(certFile),
+		},
+		{
+			Contents:    pointerutil.StringPtr(`{{ with pkiIssue "pki/issue/web" "common_name=web.example.com" }}{{ .Key }}{{ end }}`),
+			Destination: pointerutil.StringPtr(keyFile),
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	server := NewServer(&ServerConfig{
+		Logger: logging.NewVaultLogger(hclog.Trace),
+		AgentConfig: &config.Config{
+			Vault: &config.Vault{
+				Address: ts.URL,
+			},
+			TemplateConfig: &config.TemplateConfig{
+				PKIRenewThreshold: 0.5,
+			},
+		},
+		LogLevel:  hclog.Trace,
+		LogWriter: hclog.DefaultOutput,
+		PKIClient: pkiClient,
+	})
+
+	templateTokenCh := make(chan string, 1)
+	templateTokenCh <- "test"
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Run(ctx, templateTokenCh, templates)
+	}()
+
+	// readPair returns the rendered certificate and key once they match.
+	readPair := func() (*x509.Certificate, bool) {
+		certPEM, err := os.ReadFile(certFile)
+		if err != nil {
+			return nil, false
+		}
+		keyPEM, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, false
+		}
+		if _, err := tls.X509KeyPair(certPEM, keyPEM); err != nil {
+			return nil, false
+		}
+		block, _ := pem.Decode(certPEM)
+		cert, err := x509.ParseCertificate(block.Bytes)
+		require.NoError(t, err)
+		return cert, true
+	}
+
+	var first *x509.Certificate
+	require.Eventually(t, func() bool {
+		var ok bool
+		first, ok = readPair()
+		return ok
+	}, 10*time.Second, 100*time.Millisecond)
+
+	require.Eventually(t, func() bool {
+		cert, ok := readPair()
+		return ok && cert.SerialNumber.Cmp(first.SerialNumber) != 0
+	}, 10*time.Second, 100*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-errCh)
+}
+
 // TestNewServerLogLevels tests that the server can be started with any log
 // level.
 func TestNewServerLogLevels(t *testing.T) {
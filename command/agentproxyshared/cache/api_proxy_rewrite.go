@@ -0,0 +1,162 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+// MountAlias exposes the mount at Target under Path to API proxy clients,
+// e.g. presenting "secret/" while the real mount is "teams/foo/kv/".
+type MountAlias struct {
+	Path   string `hcl:"path"`
+	Target string `hcl:"target"`
+}
+
+// PathRulesConfig restricts and rewrites the API surface exposed by the API
+// proxy. Rules are matched against the path requested by the client,
+// relative to /v1/ and before any alias is applied. A trailing "*" matches
+// any path with the preceding prefix.
+type PathRulesConfig struct {
+	MountAliases []*MountAlias
+
+	// AllowedPaths, if not empty, rejects all requests to other paths.
+	AllowedPaths []string
+
+	// DeniedPaths rejects requests even if they are allowed.
+	DeniedPaths []string
+}
+
+// Validate normalizes the aliases and checks the rules for errors.
+func (c *PathRulesConfig) Validate() error {
+	seen := make(map[string]struct{}, len(c.MountAliases))
+	for _, alias := range c.MountAliases {
+		if alias == nil {
+			continue
+		}
+		alias.Path = normalizeMountPath(alias.Path)
+		alias.Target = normalizeMountPath(alias.Target)
+		if alias.Path == "" || alias.Target == "" {
+			return errors.New("mount_alias requires both 'path' and 'target'")
+		}
+		if _, ok := seen[alias.Path]; ok {
+			return fmt.Errorf("duplicate mount_alias path %q", alias.Path)
+		}
+		seen[alias.Path] = struct{}{}
+	}
+
+	for _, rules := range [][]string{c.AllowedPaths, c.DeniedPaths} {
+		for _, rule := range rules {
+			if strings.Contains(strings.TrimSuffix(rule, "*"), "*") {
+				return fmt.Errorf("invalid path rule %q: '*' is only supported at the end", rule)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Empty returns true if the config neither restricts nor rewrites requests.
+func (c *PathRulesConfig) Empty() bool {
+	return c == nil || (len(c.MountAliases) == 0 && len(c.AllowedPaths) == 0 && len(c.DeniedPaths) == 0)
+}
+
+func normalizeMountPath(p string) string {
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return ""
+	}
+	return p + "/"
+}
+
+// PathRulesHandler applies the path rules of conf to requests under /v1/
+// before passing them to next. Denied requests are answered with a
+// permission denied error without being forwarded.
+func PathRulesHandler(logger hclog.Logger, next http.Handler, conf *PathRulesConfig) http.Handler {
+	if conf.Empty() {
+		return next
+	}
+
+	// Match the longest alias first so that nested aliases work as
+	// expected.
+	aliases := make([]*MountAlias, 0, len(conf.MountAliases))
+	for _, alias := range conf.MountAliases {
+		if alias != nil {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Slice(aliases, func(i, j int) bool {
+		return len(aliases[i].Path) > len(aliases[j].Path)
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/v1/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Clean the path so that rules cannot be bypassed with dot
+		// segments.
+		cleaned := path.Clean(r.URL.Path)
+		if cleaned != "/v1" && !strings.HasPrefix(cleaned, "/v1/") {
+			logical.RespondError(w, http.StatusForbidden, logical.ErrPermissionDenied)
+			return
+		}
+		reqPath := strings.TrimPrefix(strings.TrimPrefix(cleaned, "/v1"), "/")
+		if strings.HasSuffix(r.URL.Path, "/") && reqPath != "" {
+			reqPath += "/"
+		}
+
+		if !pathAllowed(reqPath, conf.AllowedPaths, conf.DeniedPaths) {
+			logger.Debug("rejecting request by path rules", "method", r.Method, "path", r.URL.Path)
+			logical.RespondError(w, http.StatusForbidden, logical.ErrPermissionDenied)
+			return
+		}
+
+		for _, alias := range aliases {
+			var rewritten string
+			switch {
+			case reqPath+"/" == alias.Path:
+				rewritten = strings.TrimSuffix(alias.Target, "/")
+			case strings.HasPrefix(reqPath, alias.Path):
+				rewritten = alias.Target + strings.TrimPrefix(reqPath, alias.Path)
+			default:
+				continue
+			}
+			logger.Trace("rewriting request path", "path", reqPath, "rewritten", rewritten)
+			reqPath = rewritten
+			break
+		}
+
+		r.URL.Path = "/v1/" + reqPath
+		r.URL.RawPath = ""
+		next.ServeHTTP(w, r)
+	})
+}
+
+func pathAllowed(reqPath string, allowed, denied []string) bool {
+	if matchesPathRule(reqPath, denied) {
+		return false
+	}
+	return len(allowed) == 0 || matchesPathRule(reqPath, allowed)
+}
+
+func matchesPathRule(reqPath string, rules []string) bool {
+	for _, rule := range rules {
+		rule = strings.TrimPrefix(rule, "/")
+		if strings.HasSuffix(rule, "*") {
+			if strings.HasPrefix(reqPath, strings.TrimSuffix(rule, "*")) {
+				return true
+			}
+		} else if reqPath == rule {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/openbao/openbao/sdk/v2/helper/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathRulesHandler(t *testing.T) {
+	conf := &PathRulesConfig{
+		MountAliases: []*MountAlias{
+			{Path: "secret", Target: "teams/foo/kv"},
+			{Path: "secret/legacy/", Target: "teams/bar/kv/"},
+		},
+		AllowedPaths: []string{"secret*", "sys/health", "auth/token/lookup-self"},
+		DeniedPaths:  []string{"secret/data/admin/*"},
+	}
+	require.NoError(t, conf.Validate())
+
+	var forwarded string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded = r.URL.Path
+	})
+	handler := PathRulesHandler(logging.NewVaultLogger(hclog.Trace), next, conf)
+
+	cases := []struct {
+		path      string
+		status    int
+		forwarded string
+	}{
+		{"/v1/secret/data/foo", http.StatusOK, "/v1/teams/foo/kv/data/foo"},
+		{"/v1/secret/", http.StatusOK, "/v1/teams/foo/kv/"},
+		{"/v1/secret", http.StatusOK, "/v1/teams/foo/kv"},
+		{"/v1/secret/legacy/app", http.StatusOK, "/v1/teams/bar/kv/app"},
+		{"/v1/sys/health", http.StatusOK, "/v1/sys/health"},
+		{"/v1/secret/data/admin/root", http.StatusForbidden, ""},
+		{"/v1/secret/../sys/mounts", http.StatusForbidden, ""},
+		{"/v1/teams/foo/kv/data/foo", http.StatusForbidden, ""},
+		{"/v1/sys/mounts", http.StatusForbidden, ""},
+		{"/agent/v1/cache-clear", http.StatusOK, "/agent/v1/cache-clear"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.path, func(t *testing.T) {
+			forwarded = ""
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://example.com"+tc.path, nil))
+			require.Equal(t, tc.status, rec.Code)
+			require.Equal(t, tc.forwarded, forwarded)
+		})
+	}
+}
+
+func TestPathRulesConfig_Validate(t *testing.T) {
+	require.Error(t, (&PathRulesConfig{MountAliases: []*MountAlias{{Path: "secret/"}}}).Validate())
+	require.Error(t, (&PathRulesConfig{MountAliases: []*MountAlias{
+		{Path: "secret/", Target: "a/"},
+		{Path: "/secret", Target: "b/"},
+	}}).Validate())
+	require.Error(t, (&PathRulesConfig{DeniedPaths: []string{"*/foo"}}).Validate())
+	require.True(t, (&PathRulesConfig{}).Empty())
+}
@@ -97,6 +97,11 @@ type LeaseCache struct {
 	// shuttingDown is used to determine if cache needs to be evicted or not
 	// when the context is cancelled
 	shuttingDown atomic.Bool
+
+	// cacheStaticSecrets enables caching of static secrets, such as KV reads,
+	// for staticSecretTTL
+	cacheStaticSecrets bool
+	staticSecretTTL    time.Duration
 }
 
 // LeaseCacheConfig is the configuration for initializing a new
@@ -107,6 +112,12 @@ type LeaseCacheConfig struct {
 	Proxier     Proxier
 	Logger      hclog.Logger
 	Storage     *cacheboltdb.BoltStorage
+
+	// CacheStaticSecrets enables caching of responses without a lease, such
+	// as KV reads. They are served from the cache for StaticSecretTTL, or
+	// until a write to the same secret is proxied.
+	CacheStaticSecrets bool
+	StaticSecretTTL    time.Duration
 }
 
 type inflightRequest struct {
@@ -148,16 +159,23 @@ func NewLeaseCache(conf *LeaseCacheConfig) (*LeaseCache, error) {
 	// Create a base context for the lease cache layer
 	baseCtxInfo := cachememdb.NewContextInfo(conf.BaseContext)
 
+	staticSecretTTL := conf.StaticSecretTTL
+	if staticSecretTTL == 0 {
+		staticSecretTTL = DefaultStaticSecretTTL
+	}
+
 	return &LeaseCache{
-		client:        conf.Client,
-		proxier:       conf.Proxier,
-		logger:        conf.Logger,
-		db:            db,
-		baseCtxInfo:   baseCtxInfo,
-		l:             &sync.RWMutex{},
-		idLocks:       locksutil.CreateLocks(),
-		inflightCache: gocache.New(gocache.NoExpiration, gocache.NoExpiration),
-		ps:            conf.Storage,
+		client:             conf.Client,
+		proxier:            conf.Proxier,
+		logger:             conf.Logger,
+		db:                 db,
+		baseCtxInfo:        baseCtxInfo,
+		l:                  &sync.RWMutex{},
+		idLocks:            locksutil.CreateLocks(),
+		inflightCache:      gocache.New(gocache.NoExpiration, gocache.NoExpiration),
+		ps:                 conf.Storage,
+		cacheStaticSecrets: conf.CacheStaticSecrets,
+		staticSecretTTL:    staticSecretTTL,
 	}, nil
 }
 
@@ -290,6 +308,13 @@ func (c *LeaseCache) Send(ctx context.Context, req *SendRequest) (*SendResponse,
 		return resp, err
 	}
 
+	if c.cacheStaticSecrets && req.Request.Method != http.MethodGet && resp.Response.StatusCode < 300 {
+		if err := c.invalidateStaticSecrets(req); err != nil {
+			c.logger.Error("failed to invalidate cached static secrets", "error", err)
+			return nil, err
+		}
+	}
+
 	// If this is a non-2xx or if the returned response does not contain JSON payload,
 	// we skip caching
 	if resp.Response.StatusCode >= 300 || resp.Response.Header.Get("Content-Type") != "application/json" {
@@ -335,6 +360,10 @@ func (c *LeaseCache) Send(ctx context.Context, req *SendRequest) (*SendResponse,
 		return resp, nil
 	}
 
+	if c.cacheStaticSecrets && isStaticSecretRequest(req, secret) {
+		return c.cacheStaticSecret(index, req, resp)
+	}
+
 	// Short-circuit if the secret is not renewable
 	tokenRenewable, err := secret.TokenIsRenewable()
 	if err != nil {
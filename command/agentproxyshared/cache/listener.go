@@ -5,6 +5,7 @@ package cache
 
 import (
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
 	"strings"
@@ -61,6 +62,15 @@ func StartListener(lnConfig *configutil.Listener) (*ListenerBundle, error) {
 			return nil, err
 		}
 
+	case "named_pipe":
+		if addr == "" {
+			return nil, errors.New("named pipe listeners require an address")
+		}
+		ln, err = namedPipeListener(addr, lnConfig.PipeSecurityDescriptor)
+		if err != nil {
+			return nil, err
+		}
+
 	default:
 		return nil, fmt.Errorf("invalid listener type: %q", lnConfig.Type)
 	}
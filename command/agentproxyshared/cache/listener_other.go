@@ -0,0 +1,12 @@
+//go:build !windows
+
+package cache
+
+import (
+	"errors"
+	"net"
+)
+
+func namedPipeListener(string, string) (net.Listener, error) {
+	return nil, errors.New("named pipe listeners are only supported on Windows")
+}
@@ -0,0 +1,19 @@
+//go:build windows
+
+package cache
+
+import (
+	"net"
+
+	winio "github.com/Microsoft/go-winio"
+)
+
+// namedPipeListener listens on the Windows named pipe at path, e.g.
+// `\\.\pipe\openbao-agent`. If securityDescriptor is empty, the default
+// descriptor is used, which grants full access to administrators, the local
+// system account and the creator owner, and read access to everyone else.
+func namedPipeListener(path, securityDescriptor string) (net.Listener, error) {
+	return winio.ListenPipe(path, &winio.PipeConfig{
+		SecurityDescriptor: securityDescriptor,
+	})
+}
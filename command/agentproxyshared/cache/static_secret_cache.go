@@ -0,0 +1,182 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/openbao/openbao/api/v2"
+	"github.com/openbao/openbao/command/agentproxyshared/cache/cachememdb"
+	"github.com/openbao/openbao/sdk/v2/helper/consts"
+	"github.com/openbao/openbao/sdk/v2/helper/locksutil"
+)
+
+// DefaultStaticSecretTTL is how long static secrets are served from the
+// cache if no TTL was configured. The server does not notify the cache of
+// changes made to secrets, so this bounds how stale a cached secret may be;
+// only writes proxied through this cache evict it earlier.
+const DefaultStaticSecretTTL = time.Minute
+
+// staticSecretIndexType is the index type of cached static secrets. These
+// are only held in memory and never written to persistent storage.
+const staticSecretIndexType = "static-secret"
+
+// kvV2Segments are the path segments of the KV version 2 API that refer to
+// the same secret as its data/ path.
+var kvV2Segments = map[string]struct{}{
+	"data":     {},
+	"metadata": {},
+	"delete":   {},
+	"undelete": {},
+	"destroy":  {},
+}
+
+// isStaticSecretRequest returns true if the response to req is a static
+// secret, such as a KV read, that may be cached.
+func isStaticSecretRequest(req *SendRequest, secret *api.Secret) bool {
+	if req.Request.Method != http.MethodGet {
+		return false
+	}
+	if req.Request.URL.Query().Get("list") != "" || req.Request.Header.Get("X-Vault-Wrap-TTL") != "" {
+		return false
+	}
+
+	// System and auth endpoints return data tied to the state of the server
+	// or the requesting token rather than stored secrets.
+	path := strings.TrimPrefix(req.Request.URL.Path, "/v1/")
+	if strings.HasPrefix(path, "sys/") || strings.HasPrefix(path, "auth/") || strings.HasPrefix(path, "identity/") {
+		return false
+	}
+
+	return secret.LeaseID == "" && secret.Auth == nil && secret.WrapInfo == nil && !secret.Renewable
+}
+
+// staticSecretKey maps a request path to the secret it refers to, so that
+// writes to any of the KV version 2 endpoints of a secret invalidate cached
+// reads of its data/ endpoint.
+func staticSecretKey(path string) string {
+	segments := strings.Split(strings.TrimPrefix(path, "/v1/"), "/")
+	for i := 1; i < len(segments); i++ {
+		if _, ok := kvV2Segments[segments[i]]; ok {
+			segments[i] = "data"
+			break
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// cacheStaticSecret stores the static secret response in index and starts
+// the goroutine that evicts it once the TTL passes or its context is
+// cancelled, e.g. through the cache-clear API or because the token that read
+// it was revoked.
+func (c *LeaseCache) cacheStaticSecret(index *cachememdb.Index, req *SendRequest, resp *SendResponse) (*SendResponse, error) {
+	var renewCtxInfo *cachememdb.ContextInfo
+	entry, err := c.db.Get(cachememdb.IndexNameToken, req.Token)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil {
+		renewCtxInfo = cachememdb.NewContextInfo(entry.RenewCtxInfo.Ctx)
+	} else {
+		renewCtxInfo = c.createCtxInfo(nil)
+	}
+
+	var respBytes bytes.Buffer
+	if err := resp.Response.Write(&respBytes); err != nil {
+		c.logger.Error("failed to serialize response", "error", err)
+		return nil, err
+	}
+
+	// Reset the response body for upper layers to read
+	if resp.Response.Body != nil {
+		resp.Response.Body.Close()
+	}
+	resp.Response.Body = io.NopCloser(bytes.NewReader(resp.ResponseBody))
+
+	index.Response = respBytes.Bytes()
+	index.RenewCtxInfo = &cachememdb.ContextInfo{
+		Ctx:        context.WithValue(renewCtxInfo.Ctx, contextIndexID, index.ID),
+		CancelFunc: renewCtxInfo.CancelFunc,
+		DoneCh:     renewCtxInfo.DoneCh,
+	}
+	index.RequestMethod = req.Request.Method
+	index.RequestToken = req.Token
+	index.RequestHeader = req.Request.Header
+	index.Type = staticSecretIndexType
+
+	c.logger.Debug("storing static secret into the cache", "path", req.Request.URL.Path)
+	if err := c.db.Set(index); err != nil {
+		c.logger.Error("failed to cache the static secret", "error", err)
+		return nil, err
+	}
+
+	go c.expireStaticSecret(index)
+
+	return resp, nil
+}
+
+func (c *LeaseCache) expireStaticSecret(index *cachememdb.Index) {
+	timer := time.NewTimer(c.staticSecretTTL)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-index.RenewCtxInfo.Ctx.Done():
+	case <-index.RenewCtxInfo.DoneCh:
+	}
+	index.RenewCtxInfo.CancelFunc()
+
+	if c.shuttingDown.Load() {
+		return
+	}
+
+	c.evictStaticSecret(index)
+}
+
+// evictStaticSecret removes index from the cache, unless it was already
+// replaced by a newer entry for the same request.
+func (c *LeaseCache) evictStaticSecret(index *cachememdb.Index) {
+	idLock := locksutil.LockForKey(c.idLocks, index.ID)
+	idLock.Lock()
+	defer idLock.Unlock()
+
+	current, err := c.db.Get(cachememdb.IndexNameID, index.ID)
+	if err != nil || current != index {
+		return
+	}
+
+	c.logger.Debug("evicting static secret from cache", "id", index.ID, "path", index.RequestPath)
+	if err := c.db.Evict(cachememdb.IndexNameID, index.ID); err != nil {
+		c.logger.Error("failed to evict static secret", "id", index.ID, "error", err)
+	}
+}
+
+// invalidateStaticSecrets evicts the cached static secrets affected by a
+// successful write request proxied through this cache. Writes made directly
+// against the server, or through another agent or proxy, are not seen here.
+func (c *LeaseCache) invalidateStaticSecrets(req *SendRequest) error {
+	namespace := req.Request.Header.Get(consts.NamespaceHeaderName)
+	if namespace == "" {
+		namespace = "root/"
+	}
+
+	indexes, err := c.db.GetByPrefix(cachememdb.IndexNameRequestPath, namespace, "/v1/")
+	if err != nil {
+		return err
+	}
+
+	key := staticSecretKey(req.Request.URL.Path)
+	for _, index := range indexes {
+		if index.Type != staticSecretIndexType || staticSecretKey(index.RequestPath) != key {
+			continue
+		}
+		c.logger.Debug("invalidating cached static secret", "path", index.RequestPath)
+		index.RenewCtxInfo.CancelFunc()
+		c.evictStaticSecret(index)
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package cache
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/openbao/openbao/api/v2"
+	"github.com/openbao/openbao/command/agentproxyshared/cache/cachememdb"
+	"github.com/openbao/openbao/sdk/v2/helper/logging"
+	"github.com/stretchr/testify/require"
+)
+
+func testNewStaticSecretLeaseCache(t *testing.T, responses []*SendResponse, ttl time.Duration) (*LeaseCache, *mockProxier) {
+	t.Helper()
+
+	client, err := api.NewClient(api.DefaultConfig())
+	require.NoError(t, err)
+
+	proxier := NewMockProxier(responses)
+	lc, err := NewLeaseCache(&LeaseCacheConfig{
+		Client:             client,
+		BaseContext:        context.Background(),
+		Proxier:            proxier,
+		Logger:             logging.NewVaultLogger(hclog.Trace).Named("cache.leasecache"),
+		CacheStaticSecrets: true,
+		StaticSecretTTL:    ttl,
+	})
+	require.NoError(t, err)
+
+	return lc, proxier
+}
+
+func isCacheHit(resp *SendResponse) bool {
+	return resp.CacheMeta != nil && resp.CacheMeta.Hit
+}
+
+func TestLeaseCache_StaticSecrets(t *testing.T) {
+	responses := []*SendResponse{
+		newTestSendResponse(http.StatusOK, `{"data": {"data": {"foo": "bar"}}}`),
+		newTestSendResponse(http.StatusOK, `{"data": {"version": 2}}`),
+		newTestSendResponse(http.StatusOK, `{"data": {"data": {"foo": "baz"}}}`),
+		newTestSendResponse(http.StatusOK, `{"data": {"data": {"foo": "qux"}}}`),
+	}
+	lc, proxier := testNewStaticSecretLeaseCache(t, responses, time.Hour)
+
+	send := func(method, path, token string) *SendResponse {
+		t.Helper()
+		resp, err := lc.Send(context.Background(), &SendRequest{
+			Token:   token,
+			Request: httptest.NewRequest(method, "http://example.com"+path, nil),
+		})
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := send(http.MethodGet, "/v1/secret/data/foo", "token")
+	require.False(t, isCacheHit(resp))
+	require.Equal(t, 1, proxier.ResponseIndex())
+
+	// The second read is served from the cache.
+	resp = send(http.MethodGet, "/v1/secret/data/foo", "token")
+	require.True(t, isCacheHit(resp))
+	require.Contains(t, string(resp.ResponseBody), "bar")
+	require.Equal(t, 1, proxier.ResponseIndex())
+
+	// A write to the secret invalidates the cached read.
+	send(http.MethodPost, "/v1/secret/data/foo", "token")
+	resp = send(http.MethodGet, "/v1/secret/data/foo", "token")
+	require.False(t, isCacheHit(resp))
+	require.Contains(t, string(resp.ResponseBody), "baz")
+	require.Equal(t, 3, proxier.ResponseIndex())
+
+	// Cached entries are not shared between tokens.
+	resp = send(http.MethodGet, "/v1/secret/data/foo", "other-token")
+	require.False(t, isCacheHit(resp))
+	require.Equal(t, 4, proxier.ResponseIndex())
+}
+
+func TestLeaseCache_StaticSecrets_TTL(t *testing.T) {
+	responses := []*SendResponse{
+		newTestSendResponse(http.StatusOK, `{"data": {"foo": "bar"}}`),
+		newTestSendResponse(http.StatusOK, `{"data": {"foo": "baz"}}`),
+	}
+	lc, proxier := testNewStaticSecretLeaseCache(t, responses, 50*time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		_, err := lc.Send(context.Background(), &SendRequest{
+			Token:   "token",
+			Request: httptest.NewRequest(http.MethodGet, "http://example.com/v1/kv/foo", nil),
+		})
+		require.NoError(t, err)
+	}
+	require.Equal(t, 1, proxier.ResponseIndex())
+
+	require.Eventually(t, func() bool {
+		index, err := lc.db.GetByPrefix(cachememdb.IndexNameRequestPath, "root/", "/v1/kv/foo")
+		return err == nil && len(index) == 0
+	}, time.Second, 10*time.Millisecond)
+
+	resp, err := lc.Send(context.Background(), &SendRequest{
+		Token:   "token",
+		Request: httptest.NewRequest(http.MethodGet, "http://example.com/v1/kv/foo", nil),
+	})
+	require.NoError(t, err)
+	require.Contains(t, string(resp.ResponseBody), "baz")
+}
+
+func TestLeaseCache_StaticSecrets_NotCacheable(t *testing.T) {
+	responses := []*SendResponse{
+		newTestSendResponse(http.StatusOK, `{"data": {"initialized": true}}`),
+		newTestSendResponse(http.StatusOK, `{"data": {"initialized": true}}`),
+		newTestSendResponse(http.StatusOK, `{"data": {"keys": ["foo"]}}`),
+		newTestSendResponse(http.StatusOK, `{"data": {"keys": ["foo"]}}`),
+	}
+	lc, proxier := testNewStaticSecretLeaseCache(t, responses, time.Hour)
+
+	for _, path := range []string{"/v1/sys/seal-status", "/v1/sys/seal-status", "/v1/kv/?list=true", "/v1/kv/?list=true"} {
+		resp, err := lc.Send(context.Background(), &SendRequest{
+			Token:   "token",
+			Request: httptest.NewRequest(http.MethodGet, "http://example.com"+path, nil),
+		})
+		require.NoError(t, err)
+		require.False(t, isCacheHit(resp))
+	}
+	require.Equal(t, 4, proxier.ResponseIndex())
+}
+
+func TestStaticSecretKey(t *testing.T) {
+	require.Equal(t, "secret/data/foo/bar", staticSecretKey("/v1/secret/metadata/foo/bar"))
+	require.Equal(t, "secret/data/foo", staticSecretKey("/v1/secret/destroy/foo"))
+	require.Equal(t, "kv/foo", staticSecretKey("/v1/kv/foo"))
+	// Only the segment following the mount is rewritten.
+	require.Equal(t, "secret/data/metadata", staticSecretKey("/v1/secret/data/metadata"))
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package dpapi
+
+const supported = false
+
+func protect([]byte, []byte, bool) ([]byte, error) {
+	panic("dpapi is only supported on Windows")
+}
+
+func unprotect([]byte, []byte) ([]byte, error) {
+	panic("dpapi is only supported on Windows")
+}
@@ -0,0 +1,110 @@
+// Package dpapi implements a sink that protects the token with the Windows
+// Data Protection API before writing it to disk, so that it can only be read
+// back by the same user account (or, with the machine scope, on the same
+// host).
+package dpapi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/openbao/openbao/command/agentproxyshared/sink"
+	"github.com/openbao/openbao/command/agentproxyshared/sink/file"
+)
+
+const (
+	// ScopeUser restricts decryption to the account that wrote the token.
+	ScopeUser = "user"
+
+	// ScopeMachine allows any account on the same host to decrypt the
+	// token; the file permissions are then the only access control.
+	ScopeMachine = "machine"
+)
+
+// dpapiSink is a Sink implementation that writes a DPAPI-protected token to
+// a file
+type dpapiSink struct {
+	file    sink.Sink
+	machine bool
+	entropy []byte
+}
+
+// NewDPAPISink creates a new DPAPI sink with the given configuration. Besides
+// the options of the file sink, it accepts a 'scope' of "user" (the default)
+// or "machine", and an optional 'entropy' string that must also be supplied
+// to decrypt the token.
+func NewDPAPISink(conf *sink.SinkConfig) (sink.Sink, error) {
+	if conf.Logger == nil {
+		return nil, errors.New("nil logger provided")
+	}
+
+	if !supported {
+		return nil, errors.New("dpapi sink is only supported on Windows")
+	}
+
+	d := &dpapiSink{}
+
+	if scopeRaw, ok := conf.Config["scope"]; ok {
+		scope, ok := scopeRaw.(string)
+		if !ok {
+			return nil, errors.New("could not parse 'scope' as string")
+		}
+		switch strings.ToLower(scope) {
+		case ScopeUser:
+		case ScopeMachine:
+			d.machine = true
+		default:
+			return nil, fmt.Errorf("invalid 'scope' %q, must be %q or %q", scope, ScopeUser, ScopeMachine)
+		}
+	}
+
+	if entropyRaw, ok := conf.Config["entropy"]; ok {
+		entropy, ok := entropyRaw.(string)
+		if !ok {
+			return nil, errors.New("could not parse 'entropy' as string")
+		}
+		d.entropy = []byte(entropy)
+	}
+
+	fileSink, err := file.NewFileSink(conf)
+	if err != nil {
+		return nil, err
+	}
+	d.file = fileSink
+
+	conf.Logger.Info("dpapi sink configured", "machine_scope", d.machine)
+
+	return d, nil
+}
+
+// WriteToken implements the Server interface and writes the protected token
+// to disk using the file sink. A blank token is passed through unprotected
+// to perform the file sink's write check.
+func (d *dpapiSink) WriteToken(token string) error {
+	if token == "" {
+		return d.file.WriteToken("")
+	}
+
+	blob, err := protect([]byte(token), d.entropy, d.machine)
+	if err != nil {
+		return fmt.Errorf("error protecting token: %w", err)
+	}
+
+	return d.file.WriteToken(string(blob))
+}
+
+// Unprotect decrypts a token written by the DPAPI sink. It must be called
+// by the same account that wrote the token unless the machine scope was
+// used.
+func Unprotect(blob, entropy []byte) (string, error) {
+	if !supported {
+		return "", errors.New("dpapi is only supported on Windows")
+	}
+
+	token, err := unprotect(blob, entropy)
+	if err != nil {
+		return "", err
+	}
+	return string(token), nil
+}
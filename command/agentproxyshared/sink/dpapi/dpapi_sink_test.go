@@ -0,0 +1,76 @@
+package dpapi
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	hclog "github.com/hashicorp/go-hclog"
+	"github.com/openbao/openbao/command/agentproxyshared/sink"
+	"github.com/openbao/openbao/sdk/v2/helper/logging"
+)
+
+func TestDPAPISink(t *testing.T) {
+	log := logging.NewVaultLogger(hclog.Trace)
+	path := filepath.Join(t.TempDir(), "token")
+
+	config := &sink.SinkConfig{
+		Logger: log.Named("sink.dpapi"),
+		Config: map[string]interface{}{
+			"path":    path,
+			"entropy": "some-entropy",
+		},
+	}
+
+	s, err := NewDPAPISink(config)
+	if runtime.GOOS != "windows" {
+		if err == nil {
+			t.Fatal("expected error on non-Windows platform")
+		}
+		return
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.WriteToken("test-token"); err != nil {
+		t.Fatal(err)
+	}
+
+	blob, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(blob) == "test-token" {
+		t.Fatal("token was written unprotected")
+	}
+
+	if _, err := Unprotect(blob, []byte("wrong-entropy")); err == nil {
+		t.Fatal("expected error unprotecting with wrong entropy")
+	}
+	token, err := Unprotect(blob, []byte("some-entropy"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "test-token" {
+		t.Fatalf("bad token: %q", token)
+	}
+}
+
+func TestDPAPISink_InvalidScope(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		t.Skip("dpapi is only supported on Windows")
+	}
+
+	config := &sink.SinkConfig{
+		Logger: logging.NewVaultLogger(hclog.Trace),
+		Config: map[string]interface{}{
+			"path":  filepath.Join(t.TempDir(), "token"),
+			"scope": "domain",
+		},
+	}
+	if _, err := NewDPAPISink(config); err == nil {
+		t.Fatal("expected error for invalid scope")
+	}
+}
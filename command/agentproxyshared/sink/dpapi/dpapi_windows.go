@@ -0,0 +1,52 @@
+//go:build windows
+
+package dpapi
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const supported = true
+
+func protect(data, entropy []byte, machine bool) ([]byte, error) {
+	flags := uint32(windows.CRYPTPROTECT_UI_FORBIDDEN)
+	if machine {
+		flags |= windows.CRYPTPROTECT_LOCAL_MACHINE
+	}
+
+	var out windows.DataBlob
+	if err := windows.CryptProtectData(newBlob(data), nil, newBlob(entropy), 0, nil, flags, &out); err != nil {
+		return nil, err
+	}
+	return takeBlob(&out), nil
+}
+
+func unprotect(data, entropy []byte) ([]byte, error) {
+	var out windows.DataBlob
+	if err := windows.CryptUnprotectData(newBlob(data), nil, newBlob(entropy), 0, nil, windows.CRYPTPROTECT_UI_FORBIDDEN, &out); err != nil {
+		return nil, err
+	}
+	return takeBlob(&out), nil
+}
+
+func newBlob(data []byte) *windows.DataBlob {
+	if len(data) == 0 {
+		return nil
+	}
+	return &windows.DataBlob{
+		Size: uint32(len(data)),
+		Data: &data[0],
+	}
+}
+
+// takeBlob copies the contents of a blob allocated by the system and frees
+// it.
+func takeBlob(blob *windows.DataBlob) []byte {
+	defer windows.LocalFree(windows.Handle(unsafe.Pointer(blob.Data)))
+
+	out := make([]byte, blob.Size)
+	copy(out, unsafe.Slice(blob.Data, blob.Size))
+	return out
+}
@@ -116,9 +116,12 @@ func (c *AuthMoveCommand) Run(args []string) int {
 		}
 		if remountStatusResp.MigrationInfo.MigrationStatus == MountMigrationStatusFailure {
 			c.UI.Error(fmt.Sprintf("Failure! Error encountered moving auth method %s to %s, with migration ID %s", source, destination, remountResp.MigrationID))
+			if remountStatusResp.MigrationInfo.Error != "" {
+				c.UI.Error(remountStatusResp.MigrationInfo.Error)
+			}
 			return 0
 		}
-		c.UI.Output(fmt.Sprintf("Waiting for terminal status in migration of auth method %s to %s, with migration ID %s", source, destination, remountResp.MigrationID))
+		c.UI.Output(fmt.Sprintf("Waiting for terminal status in migration of auth method %s to %s, with migration ID %s%s", source, destination, remountResp.MigrationID, migrationProgress(remountStatusResp.MigrationInfo)))
 		time.Sleep(10 * time.Second)
 	}
 
@@ -351,6 +351,7 @@ func TestPredict_Plugins(t *testing.T) {
 				"influxdb-database-plugin",
 				"jwt",
 				"kerberos",
+				"keymgmt",
 				"kubernetes",
 				"kv",
 				"ldap",
@@ -360,10 +361,14 @@ func TestPredict_Plugins(t *testing.T) {
 				"mysql-rds-database-plugin",
 				"oidc",
 				"openldap",
+				"pairing",
+				"peercred",
 				"pki",
 				"postgresql-database-plugin",
 				"rabbitmq",
 				"radius",
+				"scm",
+				"spiffe",
 				"ssh",
 				"totp",
 				"transit",
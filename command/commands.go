@@ -30,6 +30,8 @@ import (
 	credOIDC "github.com/openbao/openbao/builtin/credential/jwt"
 	credKerb "github.com/openbao/openbao/builtin/credential/kerberos"
 	credLdap "github.com/openbao/openbao/builtin/credential/ldap"
+	credPeerCred "github.com/openbao/openbao/builtin/credential/peercred"
+	credSPIFFE "github.com/openbao/openbao/builtin/credential/spiffe"
 	credToken "github.com/openbao/openbao/builtin/credential/token"
 	credUserpass "github.com/openbao/openbao/builtin/credential/userpass"
 
@@ -167,10 +169,12 @@ func initCommands(ui, serverCmdUi cli.Ui, runOpts *RunOptions) map[string]cli.Co
 		"kerberos": &credKerb.CLIHandler{},
 		"ldap":     &credLdap.CLIHandler{},
 		"oidc":     &credOIDC.CLIHandler{},
+		"peercred": &credPeerCred.CLIHandler{},
 		"radius": &credUserpass.CLIHandler{
 			DefaultMount: "radius",
 		},
-		"token": &credToken.CLIHandler{},
+		"spiffe": &credSPIFFE.CLIHandler{},
+		"token":  &credToken.CLIHandler{},
 		"userpass": &credUserpass.CLIHandler{
 			DefaultMount: "userpass",
 		},
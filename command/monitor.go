@@ -5,11 +5,14 @@ package command
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-secure-stdlib/strutil"
 	"github.com/mitchellh/cli"
+	"github.com/openbao/openbao/api/v2"
 	"github.com/posener/complete"
 )
 
@@ -21,8 +24,12 @@ var (
 type MonitorCommand struct {
 	*BaseCommand
 
-	logLevel  string
-	logFormat string
+	logLevel          string
+	logFormat         string
+	subsystems        []string
+	excludeSubsystems []string
+	regex             string
+	heartbeatInterval time.Duration
 
 	// ShutdownCh is used to capture interrupt signal and end streaming
 	ShutdownCh chan struct{}
@@ -41,6 +48,11 @@ Usage: bao monitor [options]
 	the server may be logging at the INFO level, but with the monitor command
 	you can set -log-level=DEBUG.
 
+	The logs can be filtered on the server, for example to leave out the debug
+	logs of the raft storage:
+
+	  $ bao monitor -log-level=debug -exclude-subsystem=storage.raft
+
 ` + c.Flags().Help()
 
 	return strings.TrimSpace(helpText)
@@ -66,6 +78,32 @@ func (c *MonitorCommand) Flags() *FlagSets {
 		Completion: complete.PredictSet("standard", "json"),
 		Usage:      "Output format of logs. Supported values are \"standard\" and \"json\".",
 	})
+	f.StringSliceVar(&StringSliceVar{
+		Name:   "subsystem",
+		Target: &c.subsystems,
+		Usage: "If passed, only stream the logs of this subsystem and of its " +
+			"sub-loggers, such as \"core\" or \"storage.raft\". This can be " +
+			"specified multiple times.",
+	})
+	f.StringSliceVar(&StringSliceVar{
+		Name:   "exclude-subsystem",
+		Target: &c.excludeSubsystems,
+		Usage: "Do not stream the logs of this subsystem and of its sub-loggers. " +
+			"This can be specified multiple times.",
+	})
+	f.StringVar(&StringVar{
+		Name:   "regex",
+		Target: &c.regex,
+		Usage:  "If passed, only stream the log messages matching this regular expression.",
+	})
+	f.DurationVar(&DurationVar{
+		Name:       "heartbeat-interval",
+		Target:     &c.heartbeatInterval,
+		Completion: complete.PredictAnything,
+		Usage: "If passed, the interval at which the server sends heartbeats. " +
+			"The command exits with an error if neither a log nor a heartbeat " +
+			"is received for three intervals. Requires -log-format=json.",
+	})
 
 	return set
 }
@@ -106,6 +144,11 @@ func (c *MonitorCommand) Run(args []string) int {
 		return 1
 	}
 
+	if c.heartbeatInterval > 0 && c.logFormat != "json" {
+		c.UI.Error("-heartbeat-interval requires -log-format=json")
+		return 1
+	}
+
 	client, err := c.Client()
 	if err != nil {
 		c.UI.Error(err.Error())
@@ -118,21 +161,58 @@ func (c *MonitorCommand) Run(args []string) int {
 	var logCh chan string
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
-	logCh, err = client.Sys().Monitor(ctx, c.logLevel, c.logFormat)
+	logCh, err = client.Sys().MonitorWithInput(ctx, &api.MonitorInput{
+		LogLevel:          c.logLevel,
+		LogFormat:         c.logFormat,
+		Subsystems:        c.subsystems,
+		ExcludeSubsystems: c.excludeSubsystems,
+		Regex:             c.regex,
+		HeartbeatInterval: c.heartbeatInterval,
+	})
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Error starting monitor: %s", err))
 		return 1
 	}
 
+	// A nil channel never fires, so the stream only times out when
+	// heartbeats are requested
+	var timeout *time.Timer
+	var timeoutCh <-chan time.Time
+	if c.heartbeatInterval > 0 {
+		timeout = time.NewTimer(3 * c.heartbeatInterval)
+		defer timeout.Stop()
+		timeoutCh = timeout.C
+	}
+
 	for {
 		select {
 		case log, ok := <-logCh:
 			if !ok {
 				return 0
 			}
+			if timeout != nil {
+				timeout.Reset(3 * c.heartbeatInterval)
+				if isHeartbeat(log) {
+					continue
+				}
+			}
 			c.UI.Info(log)
+		case <-timeoutCh:
+			c.UI.Error(fmt.Sprintf("No heartbeat received for %s, ending monitor session", 3*c.heartbeatInterval))
+			return 2
 		case <-c.ShutdownCh:
 			return 0
 		}
 	}
 }
+
+// isHeartbeat returns whether the JSON log line is a heartbeat of the server.
+func isHeartbeat(line string) bool {
+	var message struct {
+		Type string `json:"@type"`
+	}
+	if err := json.Unmarshal([]byte(line), &message); err != nil {
+		return false
+	}
+	return message.Type == "heartbeat"
+}
@@ -81,6 +81,13 @@ Usage: bao operator diagnose
 
      $ bao operator diagnose -config=/etc/vault/config.hcl -skip=listener
 
+  Output the results as JSON, e.g. to gate a CI pipeline:
+
+     $ bao operator diagnose -config=/etc/vault/config.hcl -format=json
+
+  The exit code is 0 if all checks succeeded, 2 if any check produced a
+  warning and 1 if any check failed.
+
 ` + c.Flags().Help()
 	return strings.TrimSpace(helpText)
 }
@@ -106,7 +113,11 @@ func (c *OperatorDiagnoseCommand) Flags() *FlagSets {
 	f.StringSliceVar(&StringSliceVar{
 		Name:   "skip",
 		Target: &c.flagSkips,
-		Usage:  "Skip the health checks named as arguments. May be 'listener', 'storage', or 'autounseal'.",
+		Usage: "Skip the health checks named as arguments, along with the " +
+			"checks nested under them. Names are the ones shown in the output, " +
+			"matched case-insensitively, e.g. 'Check Storage', 'Check Cluster " +
+			"Time' or 'Check Plugin Directory'. This flag can be specified " +
+			"multiple times.",
 	})
 
 	f.BoolVar(&BoolVar{
@@ -119,7 +130,7 @@ func (c *OperatorDiagnoseCommand) Flags() *FlagSets {
 	f.StringVar(&StringVar{
 		Name:   "format",
 		Target: &c.flagFormat,
-		Usage:  "The output format",
+		Usage:  "The output format. Use \"json\" for machine-readable output, e.g. in CI pipelines.",
 	})
 	return set
 }
@@ -317,13 +328,30 @@ func (c *OperatorDiagnoseCommand) offlineDiagnostics(ctx context.Context) error
 		if config.Storage.Type == storageTypeRaft {
 			path := bApi.ReadBaoVariable(raft.EnvVaultRaftPath)
 			if path == "" {
-				path, ok := config.Storage.Config["path"]
-				if !ok {
-					diagnose.SpotError(ctx, "Check Raft Folder Permissions", fmt.Errorf("Storage folder path is required."))
-				}
+				path = config.Storage.Config["path"]
+			}
+			if path == "" {
+				diagnose.SpotError(ctx, "Check Raft Folder Permissions", fmt.Errorf("Storage folder path is required."))
+			} else {
 				diagnose.RaftFileChecks(ctx, path)
+				if !c.skipEndEnd {
+					diagnose.RaftDiskLatency(ctx, path)
+				}
+			}
+
+			raftBackend := (*backend).(*raft.RaftBackend)
+			diagnose.RaftStorageQuorum(ctx, raftBackend)
+
+			if !c.skipEndEnd {
+				diagnose.Test(ctx, "Check Cluster Time", func(ctx context.Context) error {
+					leaders, err := raftBackend.JoinConfig()
+					if err != nil {
+						return fmt.Errorf("Could not parse the raft retry_join configuration: %w.", err)
+					}
+					diagnose.ClusterTimeSkew(ctx, leaders)
+					return nil
+				})
 			}
-			diagnose.RaftStorageQuorum(ctx, (*backend).(*raft.RaftBackend))
 		}
 
 		// Attempt to use storage backend
@@ -372,6 +400,15 @@ func (c *OperatorDiagnoseCommand) offlineDiagnostics(ctx context.Context) error
 		return fmt.Errorf("Diagnose could not initialize storage backend.")
 	}
 
+	diagnose.Test(ctx, "Check Plugin Directory", func(ctx context.Context) error {
+		if config.PluginDirectory == "" {
+			diagnose.Skipped(ctx, "No plugin directory configured.")
+			return nil
+		}
+		diagnose.PluginDirectoryChecks(ctx, config.PluginDirectory, config.PluginFileUid, config.PluginFilePermissions)
+		return nil
+	})
+
 	var configSR sr.ServiceRegistration
 	diagnose.Test(ctx, "Check Service Discovery", func(ctx context.Context) error {
 		if config.ServiceRegistration == nil || config.ServiceRegistration.Config == nil {
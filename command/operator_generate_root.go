@@ -40,6 +40,7 @@ type OperatorGenerateRootCommand struct {
 	flagOTP           string
 	flagPGPKey        string
 	flagNonce         string
+	flagTOTP          string
 	flagGenerateOTP   bool
 	flagRecoveryToken bool
 
@@ -183,6 +184,16 @@ func (c *OperatorGenerateRootCommand) Flags() *FlagSets {
 			"must be provided with each unseal key.",
 	})
 
+	f.StringVar(&StringVar{
+		Name:       "totp",
+		Target:     &c.flagTOTP,
+		Default:    "",
+		EnvVar:     "",
+		Completion: complete.PredictAnything,
+		Usage: "Current TOTP code of the unseal key share. This is required " +
+			"if the shares were enrolled for TOTP when rekeying.",
+	})
+
 	return set
 }
 
@@ -463,12 +474,12 @@ func (c *OperatorGenerateRootCommand) provide(client *api.Client, key string, ki
 	}
 
 	// Provide the key, this may potentially complete the update
-	fUpd := client.Sys().GenerateRootUpdate
+	fUpd := client.Sys().GenerateRootUpdateWithTOTP
 	switch kind {
 	case generateRootRecovery:
-		fUpd = client.Sys().GenerateRecoveryOperationTokenUpdate
+		fUpd = client.Sys().GenerateRecoveryOperationTokenUpdateWithTOTP
 	}
-	status, err = fUpd(key, nonce)
+	status, err = fUpd(key, c.flagTOTP, nonce)
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Error posting unseal key: %s", err))
 		return 2
@@ -18,7 +18,9 @@ var (
 )
 
 type OperatorRaftSnapshotRestoreCommand struct {
-	flagForce bool
+	flagForce       bool
+	flagSourceMount string
+	flagTargetMount string
 	*BaseCommand
 }
 
@@ -34,6 +36,11 @@ Usage: bao operator raft snapshot restore <snapshot_file>
 
 	  $ bao operator raft snapshot restore raft.snap
 
+  Restore only the secrets engine mounted at "secret/" in the snapshot, into
+  a new secrets engine mounted at "secret-restored/":
+
+	  $ bao operator raft snapshot restore -source-mount=secret -target-mount=secret-restored raft.snap
+
 ` + c.Flags().Help()
 
 	return strings.TrimSpace(helpText)
@@ -51,6 +58,20 @@ func (c *OperatorRaftSnapshotRestoreCommand) Flags() *FlagSets {
 		Usage:   "This bypasses checks ensuring the Autounseal or shamir keys are consistent with the snapshot data.",
 	})
 
+	f.StringVar(&StringVar{
+		Name:   "source-mount",
+		Target: &c.flagSourceMount,
+		Usage: "Path of a secrets engine in the snapshot. If set, only the data of " +
+			"this secrets engine is restored, into the new secrets engine given by " +
+			"-target-mount, and the rest of the cluster is left unchanged.",
+	})
+
+	f.StringVar(&StringVar{
+		Name:   "target-mount",
+		Target: &c.flagTargetMount,
+		Usage:  "Path of the new secrets engine to restore the data of -source-mount into.",
+	})
+
 	return set
 }
 
@@ -99,6 +120,26 @@ func (c *OperatorRaftSnapshotRestoreCommand) Run(args []string) int {
 		return 2
 	}
 
+	if c.flagSourceMount != "" || c.flagTargetMount != "" {
+		if c.flagSourceMount == "" || c.flagTargetMount == "" {
+			c.UI.Error("Both -source-mount and -target-mount are required to restore a single mount")
+			return 1
+		}
+		if c.flagForce {
+			c.UI.Error("-force cannot be used to restore a single mount")
+			return 1
+		}
+
+		result, err := client.Sys().RaftSnapshotLoadMount(snapReader, c.flagSourceMount, c.flagTargetMount)
+		if err != nil {
+			c.UI.Error(fmt.Sprintf("Error restoring the mount from the snapshot: %s", err))
+			return 2
+		}
+		c.UI.Output(fmt.Sprintf("Success! Restored %d entries of %q into %q (accessor: %s)",
+			result.Keys, result.SourceMount, result.TargetMount, result.Accessor))
+		return 0
+	}
+
 	err = client.Sys().RaftSnapshotRestore(snapReader, c.flagForce)
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Error installing the snapshot: %s", err))
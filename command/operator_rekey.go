@@ -40,6 +40,8 @@ type OperatorRekeyCommand struct {
 	flagStatus       bool
 	flagTarget       string
 	flagVerify       bool
+	flagTOTPShares   bool
+	flagTOTP         string
 
 	// Backup options
 	flagBackup         bool
@@ -164,6 +166,16 @@ func (c *OperatorRekeyCommand) Flags() *FlagSets {
 			"must be provided with each unseal or recovery key.",
 	})
 
+	f.StringVar(&StringVar{
+		Name:       "totp",
+		Target:     &c.flagTOTP,
+		Default:    "",
+		EnvVar:     "",
+		Completion: complete.PredictAnything,
+		Usage: "Current TOTP code of the unseal key share. This is required " +
+			"if the shares were enrolled for TOTP when rekeying.",
+	})
+
 	f.StringVar(&StringVar{
 		Name:       "target",
 		Target:     &c.flagTarget,
@@ -183,6 +195,15 @@ func (c *OperatorRekeyCommand) Flags() *FlagSets {
 			"attempt.",
 	})
 
+	f.BoolVar(&BoolVar{
+		Name:    "totp-shares",
+		Target:  &c.flagTOTPShares,
+		Default: false,
+		Usage: "Enroll a TOTP secret for each new unseal key share. The shares " +
+			"are then only accepted along with their current TOTP code. This is " +
+			"only used with -init.",
+	})
+
 	f.VarFlag(&VarFlag{
 		Name:       "pgp-keys",
 		Value:      (*pgpkeys.PubKeyFilesFlag)(&c.flagPGPKeys),
@@ -298,6 +319,7 @@ func (c *OperatorRekeyCommand) init(client *api.Client) int {
 		PGPKeys:             c.flagPGPKeys,
 		Backup:              c.flagBackup,
 		RequireVerification: c.flagVerify,
+		TOTPShares:          c.flagTOTPShares,
 	})
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Error initializing rekey: %s", err))
@@ -378,7 +400,7 @@ func (c *OperatorRekeyCommand) provide(client *api.Client, key string) int {
 			return client.Sys().RekeyStatus()
 		}
 		updateFn = func(s1 string, s2 string) (interface{}, error) {
-			return client.Sys().RekeyUpdate(s1, s2)
+			return client.Sys().RekeyUpdateWithTOTP(s1, c.flagTOTP, s2)
 		}
 		if c.flagVerify {
 			statusFn = func() (interface{}, error) {
@@ -704,6 +726,17 @@ func (c *OperatorRekeyCommand) printUnsealKeys(client *api.Client, status *api.R
 		}
 	}
 
+	if len(resp.TOTPURLs) > 0 {
+		c.UI.Output("")
+		for i, url := range resp.TOTPURLs {
+			if len(resp.PGPFingerprints) > 0 {
+				c.UI.Output(fmt.Sprintf("Key %d fingerprint: %s; TOTP URL: %s", i+1, resp.PGPFingerprints[i], url))
+			} else {
+				c.UI.Output(fmt.Sprintf("Key %d TOTP URL: %s", i+1, url))
+			}
+		}
+	}
+
 	c.UI.Output("")
 	c.UI.Output(fmt.Sprintf("Operation nonce: %s", resp.Nonce))
 
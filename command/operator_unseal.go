@@ -25,6 +25,7 @@ type OperatorUnsealCommand struct {
 
 	flagReset   bool
 	flagMigrate bool
+	flagTOTP    string
 
 	testOutput io.Writer // for tests
 }
@@ -81,6 +82,16 @@ func (c *OperatorUnsealCommand) Flags() *FlagSets {
 		Usage:      "Indicate that this share is provided with the intent that it is part of a seal migration process.",
 	})
 
+	f.StringVar(&StringVar{
+		Name:       "totp",
+		Target:     &c.flagTOTP,
+		Default:    "",
+		EnvVar:     "",
+		Completion: complete.PredictAnything,
+		Usage: "Current TOTP code of the unseal key share. This is required " +
+			"if the shares were enrolled for TOTP when rekeying.",
+	})
+
 	return set
 }
 
@@ -160,6 +171,7 @@ func (c *OperatorUnsealCommand) Run(args []string) int {
 	status, err := client.Sys().UnsealWithOptions(&api.UnsealOpts{
 		Key:     unsealKey,
 		Migrate: c.flagMigrate,
+		TOTP:    c.flagTOTP,
 	})
 	if err != nil {
 		c.UI.Error(fmt.Sprintf("Error unsealing: %s", err))
@@ -32,6 +32,7 @@ import (
 	"github.com/openbao/openbao/command/agentproxyshared/auth"
 	"github.com/openbao/openbao/command/agentproxyshared/cache"
 	"github.com/openbao/openbao/command/agentproxyshared/sink"
+	"github.com/openbao/openbao/command/agentproxyshared/sink/dpapi"
 	"github.com/openbao/openbao/command/agentproxyshared/sink/file"
 	"github.com/openbao/openbao/command/agentproxyshared/sink/inmem"
 	"github.com/openbao/openbao/command/agentproxyshared/winsvc"
@@ -325,6 +326,24 @@ func (c *ProxyCommand) Run(args []string) int {
 				}
 				config.Sink = s
 				sinks = append(sinks, config)
+			case "dpapi":
+				config := &sink.SinkConfig{
+					Logger:    c.logger.Named("sink.dpapi"),
+					Config:    sc.Config,
+					Client:    sinkClient,
+					WrapTTL:   sc.WrapTTL,
+					DHType:    sc.DHType,
+					DeriveKey: sc.DeriveKey,
+					DHPath:    sc.DHPath,
+					AAD:       sc.AAD,
+				}
+				s, err := dpapi.NewDPAPISink(config)
+				if err != nil {
+					c.UI.Error(fmt.Errorf("error creating dpapi sink: %w", err).Error())
+					return 1
+				}
+				config.Sink = s
+				sinks = append(sinks, config)
 			default:
 				c.UI.Error(fmt.Sprintf("Unknown sink type %q", sc.Type))
 				return 1
@@ -420,10 +439,12 @@ func (c *ProxyCommand) Run(args []string) int {
 		// Create the lease cache proxier and set its underlying proxier to
 		// the API proxier.
 		leaseCache, err = cache.NewLeaseCache(&cache.LeaseCacheConfig{
-			Client:      proxyClient,
-			BaseContext: ctx,
-			Proxier:     apiProxy,
-			Logger:      cacheLogger.Named("leasecache"),
+			Client:             proxyClient,
+			BaseContext:        ctx,
+			Proxier:            apiProxy,
+			Logger:             cacheLogger.Named("leasecache"),
+			CacheStaticSecrets: config.Cache.CacheStaticSecrets,
+			StaticSecretTTL:    config.Cache.StaticSecretTTL,
 		})
 		if err != nil {
 			c.UI.Error(fmt.Sprintf("Error creating lease cache: %v", err))
@@ -501,6 +522,7 @@ func (c *ProxyCommand) Run(args []string) int {
 		} else {
 			muxHandler = cache.ProxyHandler(ctx, apiProxyLogger, apiProxy, inmemSink, proxyVaultToken)
 		}
+		muxHandler = cache.PathRulesHandler(apiProxyLogger, muxHandler, config.APIProxy.PathRules())
 
 		// Parse 'require_request_header' listener config option, and wrap
 		// the request handler if necessary
@@ -21,6 +21,7 @@ import (
 	ctconfig "github.com/openbao/openbao-template/config"
 	"github.com/openbao/openbao/api/v2"
 	"github.com/openbao/openbao/command/agentproxyshared"
+	"github.com/openbao/openbao/command/agentproxyshared/cache"
 	"github.com/openbao/openbao/helper/namespace"
 	"github.com/openbao/openbao/internalshared/configutil"
 )
@@ -93,15 +94,33 @@ type transportDialer interface {
 
 // APIProxy contains any configuration needed for proxy mode
 type APIProxy struct {
-	UseAutoAuthTokenRaw interface{} `hcl:"use_auto_auth_token"`
-	UseAutoAuthToken    bool        `hcl:"-"`
-	ForceAutoAuthToken  bool        `hcl:"-"`
+	UseAutoAuthTokenRaw interface{}         `hcl:"use_auto_auth_token"`
+	UseAutoAuthToken    bool                `hcl:"-"`
+	ForceAutoAuthToken  bool                `hcl:"-"`
+	MountAliases        []*cache.MountAlias `hcl:"-"`
+	AllowedPaths        []string            `hcl:"allowed_paths"`
+	DeniedPaths         []string            `hcl:"denied_paths"`
+}
+
+// PathRules returns the path rules applied to proxied requests.
+func (p *APIProxy) PathRules() *cache.PathRulesConfig {
+	if p == nil {
+		return nil
+	}
+	return &cache.PathRulesConfig{
+		MountAliases: p.MountAliases,
+		AllowedPaths: p.AllowedPaths,
+		DeniedPaths:  p.DeniedPaths,
+	}
 }
 
 // Cache contains any configuration needed for Cache mode
 type Cache struct {
-	Persist      *agentproxyshared.PersistConfig `hcl:"persist"`
-	InProcDialer transportDialer                 `hcl:"-"`
+	Persist            *agentproxyshared.PersistConfig `hcl:"persist"`
+	InProcDialer       transportDialer                 `hcl:"-"`
+	CacheStaticSecrets bool                            `hcl:"cache_static_secrets"`
+	StaticSecretTTLRaw interface{}                     `hcl:"static_secret_ttl"`
+	StaticSecretTTL    time.Duration                   `hcl:"-"`
 }
 
 // AutoAuth is the configured authentication method and sinks
@@ -573,11 +592,30 @@ func parseAPIProxy(result *Config, list *ast.ObjectList) error {
 			}
 		}
 	}
+	if o, ok := item.Val.(*ast.ObjectType); ok {
+		if err := parseMountAliases(&apiProxy, o.List); err != nil {
+			return fmt.Errorf("error parsing 'mount_alias': %w", err)
+		}
+	}
+	if err := apiProxy.PathRules().Validate(); err != nil {
+		return err
+	}
 	result.APIProxy = &apiProxy
 
 	return nil
 }
 
+func parseMountAliases(apiProxy *APIProxy, list *ast.ObjectList) error {
+	for _, item := range list.Filter("mount_alias").Items {
+		var alias cache.MountAlias
+		if err := hcl.DecodeObject(&alias, item.Val); err != nil {
+			return err
+		}
+		apiProxy.MountAliases = append(apiProxy.MountAliases, &alias)
+	}
+	return nil
+}
+
 func parseCache(result *Config, list *ast.ObjectList) error {
 	name := "cache"
 
@@ -598,6 +636,16 @@ func parseCache(result *Config, list *ast.ObjectList) error {
 		return err
 	}
 
+	if c.StaticSecretTTLRaw != nil {
+		if c.StaticSecretTTL, err = parseutil.ParseDurationSecond(c.StaticSecretTTLRaw); err != nil {
+			return fmt.Errorf("error parsing 'static_secret_ttl': %w", err)
+		}
+		if c.StaticSecretTTL < 0 {
+			return errors.New("'static_secret_ttl' must not be negative")
+		}
+		c.StaticSecretTTLRaw = nil
+	}
+
 	result.Cache = &c
 
 	subs, ok := item.Val.(*ast.ObjectType)
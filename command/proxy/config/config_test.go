@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-test/deep"
 	"github.com/openbao/openbao/command/agentproxyshared"
+	"github.com/openbao/openbao/command/agentproxyshared/cache"
 	"github.com/openbao/openbao/internalshared/configutil"
 )
 
@@ -115,3 +116,30 @@ func TestLoadConfigFile_ProxyCache(t *testing.T) {
 		t.Fatal(diff)
 	}
 }
+
+// TestLoadConfigFile_APIProxyPathRules tests loading the mount aliases and
+// path rules of the api_proxy stanza.
+func TestLoadConfigFile_APIProxyPathRules(t *testing.T) {
+	config, err := LoadConfigFile("./test-fixtures/config-api-proxy-path-rules.hcl")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &cache.PathRulesConfig{
+		MountAliases: []*cache.MountAlias{
+			{Path: "secret/", Target: "teams/foo/kv/"},
+			{Path: "pki/", Target: "teams/foo/pki/"},
+		},
+		AllowedPaths: []string{"secret/*", "pki/issue/web", "sys/health"},
+		DeniedPaths:  []string{"secret/data/admin/*"},
+	}
+
+	if diff := deep.Equal(config.APIProxy.PathRules(), expected); diff != nil {
+		t.Fatal(diff)
+	}
+
+	_, err = LoadConfigFile("./test-fixtures/bad-config-api-proxy-path-rules.hcl")
+	if err == nil {
+		t.Fatal("expected error for wildcard in the middle of a path rule")
+	}
+}
@@ -0,0 +1,8 @@
+api_proxy {
+  allowed_paths = ["secret/*/foo"]
+}
+
+listener "tcp" {
+  address     = "127.0.0.1:8300"
+  tls_disable = true
+}
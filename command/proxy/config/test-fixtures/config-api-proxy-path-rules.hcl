@@ -0,0 +1,21 @@
+api_proxy {
+  use_auto_auth_token = true
+
+  mount_alias {
+    path   = "secret"
+    target = "/teams/foo/kv/"
+  }
+
+  mount_alias {
+    path   = "pki/"
+    target = "teams/foo/pki/"
+  }
+
+  allowed_paths = ["secret/*", "pki/issue/web", "sys/health"]
+  denied_paths  = ["secret/data/admin/*"]
+}
+
+listener "tcp" {
+  address     = "127.0.0.1:8300"
+  tls_disable = true
+}
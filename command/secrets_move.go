@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/mitchellh/cli"
+	"github.com/openbao/openbao/api/v2"
 	"github.com/posener/complete"
 )
 
@@ -121,11 +122,27 @@ func (c *SecretsMoveCommand) Run(args []string) int {
 		}
 		if remountStatusResp.MigrationInfo.MigrationStatus == MountMigrationStatusFailure {
 			c.UI.Error(fmt.Sprintf("Failure! Error encountered moving secrets engine %s to %s, with migration ID %s", source, destination, remountResp.MigrationID))
+			if remountStatusResp.MigrationInfo.Error != "" {
+				c.UI.Error(remountStatusResp.MigrationInfo.Error)
+			}
 			return 0
 		}
-		c.UI.Output(fmt.Sprintf("Waiting for terminal status in migration of secrets engine %s to %s, with migration ID %s", source, destination, remountResp.MigrationID))
+		c.UI.Output(fmt.Sprintf("Waiting for terminal status in migration of secrets engine %s to %s, with migration ID %s%s", source, destination, remountResp.MigrationID, migrationProgress(remountStatusResp.MigrationInfo)))
 		time.Sleep(10 * time.Second)
 	}
 
 	return 0
 }
+
+// migrationProgress describes the progress of a mount migration, for servers
+// reporting it.
+func migrationProgress(info *api.MountMigrationStatusInfo) string {
+	switch info.Stage {
+	case "":
+		return ""
+	case "revoking-leases":
+		return fmt.Sprintf(" (%s: %d of %d leases revoked)", info.Stage, info.LeasesRevoked, info.LeasesTotal)
+	default:
+		return fmt.Sprintf(" (%s)", info.Stage)
+	}
+}
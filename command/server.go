@@ -8,6 +8,7 @@ import (
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net"
@@ -45,6 +46,7 @@ import (
 	"github.com/openbao/openbao/helper/testhelpers/teststorage"
 	"github.com/openbao/openbao/helper/useragent"
 	vaulthttp "github.com/openbao/openbao/http"
+	"github.com/openbao/openbao/http/grpcapi"
 	"github.com/openbao/openbao/internalshared/configutil"
 	"github.com/openbao/openbao/internalshared/listenerutil"
 	"github.com/openbao/openbao/sdk/v2/helper/consts"
@@ -62,6 +64,7 @@ import (
 	"github.com/sasha-s/go-deadlock"
 	"go.uber.org/atomic"
 	"golang.org/x/net/http/httpproxy"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/grpclog"
 )
 
@@ -111,13 +114,21 @@ type ServerCommand struct {
 
 	reloadFuncsLock *sync.RWMutex
 	reloadFuncs     *map[string][]reloadutil.ReloadFunc
+	reloadLock      sync.Mutex
 	startedCh       chan (struct{}) // for tests
 	reloadedCh      chan (struct{}) // for tests
 
 	allLoggers []hclog.Logger
 
+	// listeners holds the running API listeners by listenerKey so that
+	// listeners can be added and removed on reload.
+	listeners     map[string]*runningListener
+	listenersLock sync.Mutex
+
 	flagConfigs            []string
 	flagRecovery           bool
+	flagValidateConfig     bool
+	flagConfigSchema       bool
 	flagDev                bool
 	flagDevTLS             bool
 	flagDevTLSCertDir      string
@@ -207,6 +218,20 @@ func (c *ServerCommand) Flags() *FlagSets {
 			"Using a recovery operation token, \"sys/raw\" API can be used to manipulate the storage.",
 	})
 
+	f.BoolVar(&BoolVar{
+		Name:   "validate-config",
+		Target: &c.flagValidateConfig,
+		Usage: "Validate the configuration given with -config against the " +
+			"configuration schema, reporting unknown keys, invalid values and " +
+			"incompatible stanzas, and exit without starting the server.",
+	})
+
+	f.BoolVar(&BoolVar{
+		Name:   "config-schema",
+		Target: &c.flagConfigSchema,
+		Usage:  "Print the schema of the server configuration as JSON and exit.",
+	})
+
 	f = set.NewFlagSet("Dev Options")
 
 	f.BoolVar(&BoolVar{
@@ -419,6 +444,77 @@ func (c *ServerCommand) parseConfig() (*server.Config, []configutil.ConfigError,
 	return config, configErrors, nil
 }
 
+// validateConfig checks the configuration given with -config without
+// starting the server, reporting all the problems found.
+func (c *ServerCommand) validateConfig() int {
+	if len(c.flagConfigs) == 0 {
+		c.UI.Error("Must specify at least one config path using -config")
+		return 1
+	}
+
+	var problems []string
+	var config *server.Config
+	loaded := true
+	for _, path := range c.flagConfigs {
+		schemaErrors, err := server.ValidateConfigSchema(path)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("error loading configuration from %s: %s", path, err))
+			loaded = false
+			continue
+		}
+		for _, cErr := range schemaErrors {
+			problems = append(problems, cErr.String())
+		}
+
+		// Loading fails on the first invalid value, which the schema already
+		// reported
+		current, err := server.LoadConfig(path)
+		if err != nil {
+			if len(schemaErrors) == 0 {
+				problems = append(problems, fmt.Sprintf("error loading configuration from %s: %s", path, err))
+			}
+			loaded = false
+			continue
+		}
+		if config == nil {
+			config = current
+		} else {
+			config = config.Merge(current)
+		}
+	}
+
+	// The combinations of stanzas are only meaningful once all the
+	// configuration is loaded
+	if loaded && config != nil {
+		for _, cErr := range config.ValidateStanzas() {
+			problems = append(problems, cErr.Problem)
+		}
+	}
+
+	if len(problems) > 0 {
+		c.UI.Error(fmt.Sprintf("Found %d problem(s) in the configuration:", len(problems)))
+		for _, problem := range problems {
+			c.UI.Error("  * " + problem)
+		}
+		return 1
+	}
+
+	c.UI.Output("Success! The configuration is valid.")
+	return 0
+}
+
+func (c *ServerCommand) printConfigSchema() int {
+	schema, err := json.MarshalIndent(map[string]interface{}{
+		"fields": server.ConfigSchema(),
+	}, "", "  ")
+	if err != nil {
+		c.UI.Error(fmt.Sprintf("Error encoding the configuration schema: %s", err))
+		return 1
+	}
+	c.UI.Output(string(schema))
+	return 0
+}
+
 func (c *ServerCommand) runRecoveryMode() int {
 	config, configErrors, err := c.parseConfig()
 	if err != nil {
@@ -590,6 +686,11 @@ func (c *ServerCommand) runRecoveryMode() int {
 	// Initialize the listeners
 	lns := make([]listenerutil.Listener, 0, len(config.Listeners))
 	for _, lnConfig := range config.Listeners {
+		// Recovery mode only serves the HTTP API.
+		if lnConfig.Type == "grpc" {
+			continue
+		}
+
 		ln, _, _, err := server.NewListener(lnConfig, c.logGate, c.UI)
 		if err != nil {
 			c.UI.Error(fmt.Sprintf("Error initializing listener of type %s: %s", lnConfig.Type, err))
@@ -836,9 +937,9 @@ func (c *ServerCommand) InitListeners(config *server.Config, disableClustering b
 		}
 
 		if reloadFunc != nil {
-			relSlice := (*c.reloadFuncs)["listener|"+lnConfig.Type]
+			relSlice := (*c.reloadFuncs)["listener|"+listenerKey(lnConfig)]
 			relSlice = append(relSlice, reloadFunc)
-			(*c.reloadFuncs)["listener|"+lnConfig.Type] = relSlice
+			(*c.reloadFuncs)["listener|"+listenerKey(lnConfig)] = relSlice
 		}
 
 		if !disableClustering && lnConfig.Type == "tcp" {
@@ -866,14 +967,8 @@ func (c *ServerCommand) InitListeners(config *server.Config, disableClustering b
 			props["cluster address"] = addr
 		}
 
-		if lnConfig.MaxRequestSize == 0 {
-			lnConfig.MaxRequestSize = vaulthttp.DefaultMaxRequestSize
-		}
+		setListenerDefaults(lnConfig)
 		props["max_request_size"] = fmt.Sprintf("%d", lnConfig.MaxRequestSize)
-
-		if lnConfig.MaxRequestDuration == 0 {
-			lnConfig.MaxRequestDuration = vault.DefaultMaxRequestDuration
-		}
 		props["max_request_duration"] = lnConfig.MaxRequestDuration.String()
 
 		lns = append(lns, listenerutil.Listener{
@@ -983,6 +1078,14 @@ func (c *ServerCommand) Run(args []string) int {
 		c.logWriter = os.Stdout
 	}
 
+	if c.flagConfigSchema {
+		return c.printConfigSchema()
+	}
+
+	if c.flagValidateConfig {
+		return c.validateConfig()
+	}
+
 	if c.flagRecovery {
 		return c.runRecoveryMode()
 	}
@@ -1258,6 +1361,13 @@ func (c *ServerCommand) Run(args []string) int {
 		}
 	}
 
+	// Allow reloading the configuration through the API once the core has
+	// been created.
+	var core *vault.Core
+	coreConfig.ReloadConfigFunc = func() error {
+		return c.reloadConfig(core)
+	}
+
 	// Initialize the core
 	core, newCoreError := vault.NewCore(&coreConfig)
 	if newCoreError != nil {
@@ -1311,11 +1421,18 @@ func (c *ServerCommand) Run(args []string) int {
 		return 1
 	}
 
-	// Make sure we close all listeners from this point on
+	// Make sure we close all listeners from this point on, including
+	// those added on reload
 	listenerCloseFunc := func() {
 		for _, ln := range lns {
 			ln.Listener.Close()
 		}
+
+		c.listenersLock.Lock()
+		defer c.listenersLock.Unlock()
+		for _, ln := range c.listeners {
+			ln.listener.Listener.Close()
+		}
 	}
 
 	defer c.cleanupGuard.Do(listenerCloseFunc)
@@ -1506,68 +1623,10 @@ func (c *ServerCommand) Run(args []string) int {
 			shutdownTriggered = true
 		case <-c.SighupCh:
 			c.UI.Output("==> OpenBao reload triggered")
-
-			// Notify systemd that the server is reloading config
-			c.notifySystemd(systemd.SdNotifyReloading)
-
-			// Check for new log level
-			var config *server.Config
-			var configErrors []configutil.ConfigError
-			for _, path := range c.flagConfigs {
-				current, err := server.LoadConfig(path)
-				if err != nil {
-					c.logger.Error("could not reload config", "path", path, "error", err)
-					goto RUNRELOADFUNCS
-				}
-
-				configErrors = append(configErrors, current.Validate(path)...)
-
-				if config == nil {
-					config = current
-				} else {
-					config = config.Merge(current)
-				}
-			}
-
-			// Ensure at least one config was found.
-			if config == nil {
-				c.logger.Error("no config found at reload time")
-				goto RUNRELOADFUNCS
-			}
-
-			// reporting Errors found in the config
-			for _, cErr := range configErrors {
-				c.logger.Warn(cErr.String())
-			}
-
-			core.SetConfig(config)
-
-			// reloading custom response headers to make sure we have
-			// the most up to date headers after reloading the config file
-			if err = core.ReloadCustomResponseHeaders(); err != nil {
-				c.logger.Error(err.Error())
-			}
-
-			// Setting log request with the new value in the config after reload
-			core.ReloadLogRequestsLevel()
-
-			// Reload log level for loggers
-			if config.LogLevel != "" {
-				level, err := loghelper.ParseLogLevel(config.LogLevel)
-				if err != nil {
-					c.logger.Error("unknown log level found on reload", "level", config.LogLevel)
-					goto RUNRELOADFUNCS
-				}
-				core.SetLogLevel(level)
-			}
-
-		RUNRELOADFUNCS:
-			if err := c.Reload(c.reloadFuncsLock, c.reloadFuncs, c.flagConfigs, core); err != nil {
+			if err := c.reloadConfig(core); err != nil {
 				c.UI.Error(fmt.Sprintf("Error(s) were encountered during reload: %s", err))
 			}
 
-			// Notify systemd that the server has completed reloading config
-			c.notifySystemd(systemd.SdNotifyReady)
 
 		case <-c.SigUSR2Ch:
 			logWriter := c.logger.StandardWriter(&hclog.StandardLoggerOptions{})
@@ -2232,6 +2291,187 @@ func (c *ServerCommand) Reload(lock *sync.RWMutex, reloadFuncs *map[string][]rel
 	return reloadErrors.ErrorOrNil()
 }
 
+// reloadConfig reloads the configuration files and applies the settings
+// that can be changed while the server is running. It is triggered by
+// SIGHUP and sys/config/reload/config.
+func (c *ServerCommand) reloadConfig(core *vault.Core) error {
+	c.reloadLock.Lock()
+	defer c.reloadLock.Unlock()
+
+	// Notify systemd that the server is reloading config
+	c.notifySystemd(systemd.SdNotifyReloading)
+
+	var reloadErrors *multierror.Error
+	if err := c.reloadConfigFiles(core); err != nil {
+		reloadErrors = multierror.Append(reloadErrors, err)
+	}
+
+	if err := c.Reload(c.reloadFuncsLock, c.reloadFuncs, c.flagConfigs, core); err != nil {
+		reloadErrors = multierror.Append(reloadErrors, err)
+	}
+
+	// Notify systemd that the server has completed reloading config
+	c.notifySystemd(systemd.SdNotifyReady)
+
+	return reloadErrors.ErrorOrNil()
+}
+
+func (c *ServerCommand) reloadConfigFiles(core *vault.Core) error {
+	var config *server.Config
+	var configErrors []configutil.ConfigError
+	for _, path := range c.flagConfigs {
+		current, err := server.LoadConfig(path)
+		if err != nil {
+			c.logger.Error("could not reload config", "path", path, "error", err)
+			return fmt.Errorf("could not reload config %q: %w", path, err)
+		}
+
+		configErrors = append(configErrors, current.Validate(path)...)
+
+		if config == nil {
+			config = current
+		} else {
+			config = config.Merge(current)
+		}
+	}
+
+	// Ensure at least one config was found.
+	if config == nil {
+		c.logger.Error("no config found at reload time")
+		return errors.New("no config found at reload time")
+	}
+
+	// reporting Errors found in the config
+	for _, cErr := range configErrors {
+		c.logger.Warn(cErr.String())
+	}
+
+	core.SetConfig(config)
+
+	var reloadErrors *multierror.Error
+
+	// reloading custom response headers to make sure we have
+	// the most up to date headers after reloading the config file
+	if err := core.ReloadCustomResponseHeaders(); err != nil {
+		c.logger.Error(err.Error())
+		reloadErrors = multierror.Append(reloadErrors, err)
+	}
+
+	// Setting log request with the new value in the config after reload
+	core.ReloadLogRequestsLevel()
+
+	// Apply the root token limits to future root generations
+	core.ReloadRootTokenLimits()
+
+	// Apply the lease revocation rate limit to the expiration manager
+	core.ReloadLeaseRevocationRateLimit()
+
+	// Apply the default limit of list responses
+	core.ReloadListDefaultLimit()
+
+	// Apply the retention of disabled mounts to future disable operations
+	core.ReloadDisabledMountRetention()
+
+	// Reload log level for loggers
+	if config.LogLevel != "" {
+		level, err := loghelper.ParseLogLevel(config.LogLevel)
+		if err != nil {
+			c.logger.Error("unknown log level found on reload", "level", config.LogLevel)
+			reloadErrors = multierror.Append(reloadErrors, fmt.Errorf("unknown log level %q", config.LogLevel))
+		} else {
+			core.SetLogLevel(level)
+		}
+	}
+
+	if err := configutil.ReloadTelemetry(config.Telemetry); err != nil {
+		c.logger.Error("could not reload telemetry", "error", err)
+		reloadErrors = multierror.Append(reloadErrors, fmt.Errorf("error reloading telemetry: %w", err))
+	}
+
+	if err := core.ReloadPluginDirectory(); err != nil {
+		c.logger.Error("could not reload plugin directory", "error", err)
+		reloadErrors = multierror.Append(reloadErrors, err)
+	}
+
+	if err := c.reloadListeners(config, core); err != nil {
+		reloadErrors = multierror.Append(reloadErrors, err)
+	}
+
+	return reloadErrors.ErrorOrNil()
+}
+
+// reloadListeners stops the API listeners that were removed from config and
+// starts those that were added. Listeners are matched by type and address;
+// changes to an existing listener other than its TLS certificates still
+// require a restart. Cluster listeners are not affected.
+func (c *ServerCommand) reloadListeners(config *server.Config, core *vault.Core) error {
+	configured := make(map[string]*configutil.Listener, len(config.Listeners))
+	for _, lnConfig := range config.Listeners {
+		configured[listenerKey(lnConfig)] = lnConfig
+	}
+
+	c.listenersLock.Lock()
+	var removed []*runningListener
+	for key, ln := range c.listeners {
+		if _, ok := configured[key]; !ok {
+			removed = append(removed, ln)
+			delete(c.listeners, key)
+		}
+	}
+	running := make(map[string]struct{}, len(c.listeners))
+	for key := range c.listeners {
+		running[key] = struct{}{}
+	}
+	c.listenersLock.Unlock()
+
+	for _, ln := range removed {
+		c.logger.Info("stopping removed listener", "type", ln.listener.Config.Type, "address", ln.listener.Config.Address)
+		ln.stop()
+
+		c.reloadFuncsLock.Lock()
+		delete(*c.reloadFuncs, "listener|"+listenerKey(ln.listener.Config))
+		c.reloadFuncsLock.Unlock()
+	}
+
+	var reloadErrors *multierror.Error
+	var added []listenerutil.Listener
+	for key, lnConfig := range configured {
+		if _, ok := running[key]; ok {
+			continue
+		}
+
+		if err := config2.IsValidListener(lnConfig); err != nil {
+			reloadErrors = multierror.Append(reloadErrors, err)
+			continue
+		}
+
+		ln, _, reloadFunc, err := server.NewListener(lnConfig, c.logGate, c.UI)
+		if err != nil {
+			reloadErrors = multierror.Append(reloadErrors, fmt.Errorf("error initializing listener of type %s: %w", lnConfig.Type, err))
+			continue
+		}
+
+		if reloadFunc != nil {
+			c.reloadFuncsLock.Lock()
+			(*c.reloadFuncs)["listener|"+key] = []reloadutil.ReloadFunc{reloadFunc}
+			c.reloadFuncsLock.Unlock()
+		}
+
+		setListenerDefaults(lnConfig)
+		c.logger.Info("starting added listener", "type", lnConfig.Type, "address", ln.Addr().String())
+		added = append(added, listenerutil.Listener{
+			Listener: ln,
+			Config:   lnConfig,
+		})
+	}
+
+	if err := startHttpServers(c, core, config, added); err != nil {
+		reloadErrors = multierror.Append(reloadErrors, err)
+	}
+
+	return reloadErrors.ErrorOrNil()
+}
+
 // storePidFile is used to write out our PID to a file if necessary
 func (c *ServerCommand) storePidFile(pidPath string) error {
 	// Quit fast if no pidfile
@@ -2607,6 +2847,12 @@ func createCoreConfig(c *ServerCommand, config *server.Config, backend physical.
 		Logger:                         c.logger,
 		DetectDeadlocks:                config.DetectDeadlocks,
 		ImpreciseLeaseRoleTracking:     config.ImpreciseLeaseRoleTracking,
+		RestrictedCrypto:               config.RestrictedCrypto,
+		RootTokenTTL:                   config.RootTokenTTL,
+		RootTokenNumUses:               config.RootTokenNumUses,
+		LeaseRevocationRateLimit:       config.LeaseRevocationRateLimit,
+		ListDefaultLimit:               config.ListDefaultLimit,
+		DisabledMountRetention:         config.DisabledMountRetention,
 		DisableSentinelTrace:           config.DisableSentinelTrace,
 		DisableCache:                   config.DisableCache,
 		MaxLeaseTTL:                    config.MaxLeaseTTL,
@@ -2828,6 +3074,23 @@ func startHttpServers(c *ServerCommand, core *vault.Core, config *server.Config,
 			handler = vaulthttp.WrapForwardedForHandler(handler, ln.Config)
 		}
 
+		if ln.Config.Type == "grpc" {
+			var opts []grpc.ServerOption
+			if ln.Config.MaxRequestSize > 0 {
+				opts = append(opts, grpc.MaxRecvMsgSize(int(ln.Config.MaxRequestSize)))
+			}
+			grpcServer := grpcapi.NewServer(handler, opts...)
+
+			// server config tests can exit now
+			if c.flagTestServerConfig {
+				continue
+			}
+
+			c.trackListener(ln, grpcServer.Stop)
+			go grpcServer.Serve(ln.Listener)
+			continue
+		}
+
 		// server defaults
 		server := &http.Server{
 			Handler:           handler,
@@ -2835,6 +3098,7 @@ func startHttpServers(c *ServerCommand, core *vault.Core, config *server.Config,
 			ReadTimeout:       30 * time.Second,
 			IdleTimeout:       5 * time.Minute,
 			ErrorLog:          c.logger.StandardLogger(nil),
+			ConnContext:       vaulthttp.ConnContext,
 		}
 
 		// override server defaults with config values for read/write/idle timeouts if configured
@@ -2856,11 +3120,45 @@ func startHttpServers(c *ServerCommand, core *vault.Core, config *server.Config,
 			continue
 		}
 
+		c.trackListener(ln, func() { server.Close() })
 		go server.Serve(ln.Listener)
 	}
 	return nil
 }
 
+// runningListener is an API listener and a function to stop serving it.
+type runningListener struct {
+	listener listenerutil.Listener
+	stop     func()
+}
+
+// listenerKey identifies a listener across reloads.
+func listenerKey(l *configutil.Listener) string {
+	return l.Type + "|" + l.Address
+}
+
+func (c *ServerCommand) trackListener(ln listenerutil.Listener, stop func()) {
+	c.listenersLock.Lock()
+	defer c.listenersLock.Unlock()
+
+	if c.listeners == nil {
+		c.listeners = make(map[string]*runningListener)
+	}
+	c.listeners[listenerKey(ln.Config)] = &runningListener{
+		listener: ln,
+		stop:     stop,
+	}
+}
+
+func setListenerDefaults(l *configutil.Listener) {
+	if l.MaxRequestSize == 0 {
+		l.MaxRequestSize = vaulthttp.DefaultMaxRequestSize
+	}
+	if l.MaxRequestDuration == 0 {
+		l.MaxRequestDuration = vault.DefaultMaxRequestDuration
+	}
+}
+
 func SetStorageMigration(b physical.Backend, active bool) error {
 	if !active {
 		return b.Delete(context.Background(), storageMigrationLock)
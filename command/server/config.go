@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -17,6 +18,7 @@ import (
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/go-secure-stdlib/parseutil"
+	"github.com/hashicorp/go-secure-stdlib/strutil"
 	"github.com/hashicorp/hcl"
 	"github.com/hashicorp/hcl/hcl/ast"
 	"github.com/mitchellh/mapstructure"
@@ -58,11 +60,26 @@ type Config struct {
 	EnableUI    bool        `hcl:"-"`
 	EnableUIRaw interface{} `hcl:"ui"`
 
+	UIAssets []*UIAssets `hcl:"-"`
+
+	Notifications []*Notification `hcl:"-"`
+
 	MaxLeaseTTL        time.Duration `hcl:"-"`
 	MaxLeaseTTLRaw     interface{}   `hcl:"max_lease_ttl,alias:MaxLeaseTTL"`
 	DefaultLeaseTTL    time.Duration `hcl:"-"`
 	DefaultLeaseTTLRaw interface{}   `hcl:"default_lease_ttl,alias:DefaultLeaseTTL"`
 
+	RootTokenTTL     time.Duration `hcl:"-"`
+	RootTokenTTLRaw  interface{}   `hcl:"root_token_ttl,alias:RootTokenTTL"`
+	RootTokenNumUses int           `hcl:"root_token_num_uses"`
+
+	LeaseRevocationRateLimit int `hcl:"lease_revocation_rate_limit"`
+
+	ListDefaultLimit int `hcl:"list_default_limit"`
+
+	DisabledMountRetention    time.Duration `hcl:"-"`
+	DisabledMountRetentionRaw interface{}   `hcl:"disabled_mount_retention"`
+
 	ClusterCipherSuites string `hcl:"cluster_cipher_suites"`
 
 	PluginDirectory string `hcl:"plugin_directory"`
@@ -105,6 +122,8 @@ type Config struct {
 
 	ImpreciseLeaseRoleTracking bool `hcl:"imprecise_lease_role_tracking"`
 
+	RestrictedCrypto bool `hcl:"restricted_crypto"`
+
 	EnableResponseHeaderRaftNodeID    bool        `hcl:"-"`
 	EnableResponseHeaderRaftNodeIDRaw interface{} `hcl:"enable_response_header_raft_node_id"`
 
@@ -126,6 +145,12 @@ func (c *Config) Validate(sourceFilePath string) []configutil.ConfigError {
 	for _, l := range c.Listeners {
 		results = append(results, l.Validate(sourceFilePath)...)
 	}
+	for _, u := range c.UIAssets {
+		results = append(results, u.Validate(sourceFilePath)...)
+	}
+	for _, n := range c.Notifications {
+		results = append(results, n.Validate(sourceFilePath)...)
+	}
 	results = append(results, c.validateEnt(sourceFilePath)...)
 	return results
 }
@@ -249,6 +274,70 @@ func (b *ServiceRegistration) GoString() string {
 	return fmt.Sprintf("*%#v", *b)
 }
 
+// UIAssets is an alternate set of UI assets, served instead of the built-in
+// UI to the requests for one of its hosts or namespaces.
+type UIAssets struct {
+	UnusedKeys configutil.UnusedKeyMap `hcl:",unusedKeyPositions"`
+	Name       string                  `hcl:"-"`
+
+	// Path is the directory of the assets, or a zip archive of them.
+	Path string `hcl:"path"`
+
+	// Hosts are the values of the Host header the assets are served to. A
+	// leading "*." matches any subdomain.
+	Hosts []string `hcl:"hosts"`
+
+	// Namespaces are the namespaces the assets are served to, along with
+	// their child namespaces.
+	Namespaces []string `hcl:"namespaces"`
+}
+
+func (u *UIAssets) Validate(source string) []configutil.ConfigError {
+	return configutil.ValidateUnusedFields(u.UnusedKeys, source)
+}
+
+func (u *UIAssets) GoString() string {
+	return fmt.Sprintf("*%#v", *u)
+}
+
+// NotificationEvents are the events a notification endpoint can be notified
+// of.
+var NotificationEvents = []string{
+	"seal",
+	"unseal",
+	"leadership_acquired",
+	"leadership_lost",
+	"quorum_loss",
+}
+
+// Notification is an endpoint to which the node POSTs its seal, unseal and
+// leadership events.
+type Notification struct {
+	UnusedKeys configutil.UnusedKeyMap `hcl:",unusedKeyPositions"`
+	Name       string                  `hcl:"-"`
+
+	// URL is the endpoint the events are POSTed to.
+	URL string `hcl:"url"`
+
+	// Events are the events the endpoint is notified of, all of them if
+	// empty.
+	Events []string `hcl:"events"`
+
+	// HMACKey, when set, is used to sign the body of the notifications.
+	HMACKey string `hcl:"hmac_key"`
+
+	// MaxRetries is the number of times a failed notification is retried.
+	MaxRetries *int `hcl:"max_retries"`
+}
+
+func (n *Notification) Validate(source string) []configutil.ConfigError {
+	return configutil.ValidateUnusedFields(n.UnusedKeys, source)
+}
+
+func (n *Notification) GoString() string {
+	return fmt.Sprintf("*%#v", *n)
+}
+
 func NewConfig() *Config {
 	return &Config{
 		SharedConfig: new(configutil.SharedConfig),
@@ -315,6 +404,31 @@ func (c *Config) Merge(c2 *Config) *Config {
 		result.DefaultLeaseTTL = c2.DefaultLeaseTTL
 	}
 
+	result.RootTokenTTL = c.RootTokenTTL
+	if c2.RootTokenTTL != 0 {
+		result.RootTokenTTL = c2.RootTokenTTL
+	}
+
+	result.RootTokenNumUses = c.RootTokenNumUses
+	if c2.RootTokenNumUses != 0 {
+		result.RootTokenNumUses = c2.RootTokenNumUses
+	}
+
+	result.LeaseRevocationRateLimit = c.LeaseRevocationRateLimit
+	if c2.LeaseRevocationRateLimit != 0 {
+		result.LeaseRevocationRateLimit = c2.LeaseRevocationRateLimit
+	}
+
+	result.ListDefaultLimit = c.ListDefaultLimit
+	if c2.ListDefaultLimit != 0 {
+		result.ListDefaultLimit = c2.ListDefaultLimit
+	}
+
+	result.DisabledMountRetention = c.DisabledMountRetention
+	if c2.DisabledMountRetention != 0 {
+		result.DisabledMountRetention = c2.DisabledMountRetention
+	}
+
 	result.ClusterCipherSuites = c.ClusterCipherSuites
 	if c2.ClusterCipherSuites != "" {
 		result.ClusterCipherSuites = c2.ClusterCipherSuites
@@ -325,6 +439,36 @@ func (c *Config) Merge(c2 *Config) *Config {
 		result.EnableUI = c2.EnableUI
 	}
 
+	// UI asset sets of the same name are replaced
+	result.UIAssets = append(result.UIAssets, c2.UIAssets...)
+	for _, u := range c.UIAssets {
+		replaced := false
+		for _, u2 := range c2.UIAssets {
+			if u2.Name == u.Name {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			result.UIAssets = append(result.UIAssets, u)
+		}
+	}
+
+	// Notification endpoints of the same name are replaced
+	result.Notifications = append(result.Notifications, c2.Notifications...)
+	for _, n := range c.Notifications {
+		replaced := false
+		for _, n2 := range c2.Notifications {
+			if n2.Name == n.Name {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			result.Notifications = append(result.Notifications, n)
+		}
+	}
+
 	result.EnableRawEndpoint = c.EnableRawEndpoint
 	if c2.EnableRawEndpoint {
 		result.EnableRawEndpoint = c2.EnableRawEndpoint
@@ -404,6 +548,11 @@ func (c *Config) Merge(c2 *Config) *Config {
 		result.ImpreciseLeaseRoleTracking = c2.ImpreciseLeaseRoleTracking
 	}
 
+	result.RestrictedCrypto = c.RestrictedCrypto
+	if c2.RestrictedCrypto {
+		result.RestrictedCrypto = c2.RestrictedCrypto
+	}
+
 	result.EnableResponseHeaderRaftNodeID = c.EnableResponseHeaderRaftNodeID
 	if c2.EnableResponseHeaderRaftNodeID {
 		result.EnableResponseHeaderRaftNodeID = c2.EnableResponseHeaderRaftNodeID
@@ -581,6 +730,32 @@ func ParseConfig(d, source string) (*Config, error) {
 		}
 	}
 
+	if result.RootTokenTTLRaw != nil {
+		if result.RootTokenTTL, err = parseutil.ParseDurationSecond(result.RootTokenTTLRaw); err != nil {
+			return nil, err
+		}
+		if result.RootTokenTTL < 0 {
+			return nil, errors.New("root_token_ttl must not be negative")
+		}
+	}
+	if result.RootTokenNumUses < 0 {
+		return nil, errors.New("root_token_num_uses must not be negative")
+	}
+	if result.LeaseRevocationRateLimit < 0 {
+		return nil, errors.New("lease_revocation_rate_limit must not be negative")
+	}
+	if result.ListDefaultLimit < 0 {
+		return nil, errors.New("list_default_limit must not be negative")
+	}
+	if result.DisabledMountRetentionRaw != nil {
+		if result.DisabledMountRetention, err = parseutil.ParseDurationSecond(result.DisabledMountRetentionRaw); err != nil {
+			return nil, err
+		}
+		if result.DisabledMountRetention < 0 {
+			return nil, errors.New("disabled_mount_retention must not be negative")
+		}
+	}
+
 	if result.EnableUIRaw != nil {
 		if result.EnableUI, err = parseutil.ParseBool(result.EnableUIRaw); err != nil {
 			return nil, err
@@ -723,6 +898,20 @@ func ParseConfig(d, source string) (*Config, error) {
 		}
 	}
 
+	if o := list.Filter("ui_assets"); len(o.Items) > 0 {
+		delete(result.UnusedKeys, "ui_assets")
+		if err := parseUIAssets(result, o, "ui_assets"); err != nil {
+			return nil, fmt.Errorf("error parsing 'ui_assets': %w", err)
+		}
+	}
+
+	if o := list.Filter("notification"); len(o.Items) > 0 {
+		delete(result.UnusedKeys, "notification")
+		if err := parseNotifications(result, o, "notification"); err != nil {
+			return nil, fmt.Errorf("error parsing 'notification': %w", err)
+		}
+	}
+
 	// Remove all unused keys from Config that were satisfied by SharedConfig.
 	result.UnusedKeys = configutil.UnusedFieldDifference(result.UnusedKeys, nil, append(result.FoundKeys, sharedConfig.FoundKeys...))
 	// Assign file info
@@ -752,11 +941,36 @@ func LoadConfigDir(dir string) (*Config, error) {
 		return nil, fmt.Errorf("configuration path must be a directory: %q", dir)
 	}
 
+	files, err := configDirFiles(f)
+	if err != nil {
+		return nil, err
+	}
+
+	result := NewConfig()
+	for _, f := range files {
+		config, err := LoadConfigFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("error loading %q: %w", f, err)
+		}
+
+		if result == nil {
+			result = config
+		} else {
+			result = result.Merge(config)
+		}
+	}
+
+	return result, nil
+}
+
+// configDirFiles returns the paths of the configuration files in the opened
+// directory dir.
+func configDirFiles(dir *os.File) ([]string, error) {
 	var files []string
-	err = nil
+	var err error
 	for err != io.EOF {
 		var fis []os.FileInfo
-		fis, err = f.Readdir(128)
+		fis, err = dir.Readdir(128)
 		if err != nil && err != io.EOF {
 			return nil, err
 		}
@@ -779,26 +993,10 @@ func LoadConfigDir(dir string) (*Config, error) {
 				continue
 			}
 
-			path := filepath.Join(dir, name)
-			files = append(files, path)
-		}
-	}
-
-	result := NewConfig()
-	for _, f := range files {
-		config, err := LoadConfigFile(f)
-		if err != nil {
-			return nil, fmt.Errorf("error loading %q: %w", f, err)
-		}
-
-		if result == nil {
-			result = config
-		} else {
-			result = result.Merge(config)
+			files = append(files, filepath.Join(dir.Name(), name))
 		}
 	}
-
-	return result, nil
+	return files, nil
 }
 
 // isTemporaryFile returns true or false depending on whether the
@@ -998,6 +1196,78 @@ func parseServiceRegistration(result *Config, list *ast.ObjectList, name string)
 	return nil
 }
 
+func parseUIAssets(result *Config, list *ast.ObjectList, name string) error {
+	for _, item := range list.Items {
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("%s: a name is required", name)
+		}
+		key := item.Keys[0].Token.Value().(string)
+
+		var u UIAssets
+		if err := hcl.DecodeObject(&u, item.Val); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%s.%s:", name, key))
+		}
+		u.Name = key
+
+		if u.Path == "" {
+			return fmt.Errorf("%s.%s: path is required", name, key)
+		}
+		if len(u.Hosts) == 0 && len(u.Namespaces) == 0 {
+			return fmt.Errorf("%s.%s: at least one of hosts and namespaces is required", name, key)
+		}
+		for _, existing := range result.UIAssets {
+			if existing.Name == key {
+				return fmt.Errorf("%s.%s: duplicate name", name, key)
+			}
+		}
+
+		result.UIAssets = append(result.UIAssets, &u)
+	}
+	return nil
+}
+
+func parseNotifications(result *Config, list *ast.ObjectList, name string) error {
+	for _, item := range list.Items {
+		if len(item.Keys) != 1 {
+			return fmt.Errorf("%s: a name is required", name)
+		}
+		key := item.Keys[0].Token.Value().(string)
+
+		var n Notification
+		if err := hcl.DecodeObject(&n, item.Val); err != nil {
+			return multierror.Prefix(err, fmt.Sprintf("%s.%s:", name, key))
+		}
+		n.Name = key
+
+		if n.URL == "" {
+			return fmt.Errorf("%s.%s: url is required", name, key)
+		}
+		u, err := url.Parse(n.URL)
+		if err != nil {
+			return fmt.Errorf("%s.%s: invalid url: %w", name, key, err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("%s.%s: url must be an http or https URL", name, key)
+		}
+		for _, event := range n.Events {
+			if !strutil.StrListContains(NotificationEvents, event) {
+				return fmt.Errorf("%s.%s: unknown event %q", name, key, event)
+			}
+		}
+		if n.MaxRetries != nil && *n.MaxRetries < 0 {
+			return fmt.Errorf("%s.%s: max_retries cannot be negative", name, key)
+		}
+		for _, existing := range result.Notifications {
+			if existing.Name == key {
+				return fmt.Errorf("%s.%s: duplicate name", name, key)
+			}
+		}
+
+		result.Notifications = append(result.Notifications, &n)
+	}
+	return nil
+}
+
 // Sanitized returns a copy of the config with all values that are considered
 // sensitive stripped. It also strips all `*Raw` values that are mainly
 // used for parsing.
@@ -1025,6 +1295,15 @@ func (c *Config) Sanitized() map[string]interface{} {
 		"max_lease_ttl":     c.MaxLeaseTTL / time.Second,
 		"default_lease_ttl": c.DefaultLeaseTTL / time.Second,
 
+		"root_token_ttl":      c.RootTokenTTL / time.Second,
+		"root_token_num_uses": c.RootTokenNumUses,
+
+		"lease_revocation_rate_limit": c.LeaseRevocationRateLimit,
+
+		"list_default_limit": c.ListDefaultLimit,
+
+		"disabled_mount_retention": c.DisabledMountRetention / time.Second,
+
 		"cluster_cipher_suites": c.ClusterCipherSuites,
 
 		"plugin_directory": c.PluginDirectory,
@@ -1056,6 +1335,8 @@ func (c *Config) Sanitized() map[string]interface{} {
 		"detect_deadlocks": c.DetectDeadlocks,
 
 		"imprecise_lease_role_tracking": c.ImpreciseLeaseRoleTracking,
+
+		"restricted_crypto": c.RestrictedCrypto,
 	}
 	for k, v := range sharedResult {
 		result[k] = v
@@ -1107,6 +1388,33 @@ func (c *Config) Sanitized() map[string]interface{} {
 		result["service_registration"] = sanitizedServiceRegistration
 	}
 
+	// Sanitize ui_assets stanzas
+	if len(c.UIAssets) > 0 {
+		sanitizedUIAssets := make([]interface{}, 0, len(c.UIAssets))
+		for _, u := range c.UIAssets {
+			sanitizedUIAssets = append(sanitizedUIAssets, map[string]interface{}{
+				"name":       u.Name,
+				"path":       u.Path,
+				"hosts":      u.Hosts,
+				"namespaces": u.Namespaces,
+			})
+		}
+		result["ui_assets"] = sanitizedUIAssets
+	}
+
+	// Sanitize notification stanzas, leaving out the URLs and HMAC keys which
+	// may hold credentials
+	if len(c.Notifications) > 0 {
+		sanitizedNotifications := make([]interface{}, 0, len(c.Notifications))
+		for _, n := range c.Notifications {
+			sanitizedNotifications = append(sanitizedNotifications, map[string]interface{}{
+				"name":   n.Name,
+				"events": n.Events,
+			})
+		}
+		result["notification"] = sanitizedNotifications
+	}
+
 	return result
 }
 
@@ -5,12 +5,19 @@ package server
 
 import (
 	"testing"
+
+	"github.com/openbao/openbao/internalshared/configutil"
 )
 
+var testEntropy = &configutil.Entropy{
+	Mode:   configutil.EntropyAugmentation,
+	Source: configutil.EntropySourceSeal,
+}
+
 func TestLoadConfigFile_topLevel(t *testing.T) {
-	testLoadConfigFile_topLevel(t, nil)
+	testLoadConfigFile_topLevel(t, testEntropy)
 }
 
 func TestLoadConfigFile_json2(t *testing.T) {
-	testLoadConfigFile_json2(t, nil)
+	testLoadConfigFile_json2(t, testEntropy)
 }
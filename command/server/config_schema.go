@@ -0,0 +1,362 @@
+package server
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-secure-stdlib/parseutil"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/hashicorp/hcl/hcl/token"
+	"github.com/openbao/openbao/internalshared/configutil"
+)
+
+// The types of the values of the configuration schema.
+const (
+	ConfigSchemaTypeString   = "string"
+	ConfigSchemaTypeInt      = "int"
+	ConfigSchemaTypeFloat    = "float"
+	ConfigSchemaTypeBool     = "bool"
+	ConfigSchemaTypeDuration = "duration"
+	ConfigSchemaTypeList     = "list"
+	ConfigSchemaTypeMap      = "map"
+	ConfigSchemaTypeBlock    = "block"
+	ConfigSchemaTypeAny      = "any"
+)
+
+// ConfigSchemaField describes a key of the server configuration.
+type ConfigSchemaField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+
+	// Labeled is set for the blocks taking a type label, such as
+	// storage "raft" {}.
+	Labeled bool `json:"labeled,omitempty"`
+
+	// Repeated is set for the blocks which may be given more than once.
+	Repeated bool `json:"repeated,omitempty"`
+
+	// FreeForm is set for the blocks whose keys depend on their type, and
+	// are validated by the plugin or backend they configure.
+	FreeForm bool `json:"free_form,omitempty"`
+
+	Fields []*ConfigSchemaField `json:"fields,omitempty"`
+}
+
+// ConfigSchema returns the schema of the server configuration. The keys are
+// derived from the hcl tags of the configuration structs, along with the
+// blocks the parser handles itself.
+func ConfigSchema() []*ConfigSchemaField {
+	fields := append(schemaFields(reflect.TypeOf(Config{})), schemaFields(reflect.TypeOf(configutil.SharedConfig{}))...)
+	fields = append(fields,
+		&ConfigSchemaField{Name: "storage", Type: ConfigSchemaTypeBlock, Labeled: true, FreeForm: true},
+		&ConfigSchemaField{Name: "backend", Type: ConfigSchemaTypeBlock, Labeled: true, FreeForm: true},
+		&ConfigSchemaField{Name: "ha_storage", Type: ConfigSchemaTypeBlock, Labeled: true, FreeForm: true},
+		&ConfigSchemaField{Name: "ha_backend", Type: ConfigSchemaTypeBlock, Labeled: true, FreeForm: true},
+		&ConfigSchemaField{Name: "service_registration", Type: ConfigSchemaTypeBlock, Labeled: true, FreeForm: true},
+		&ConfigSchemaField{Name: "seal", Type: ConfigSchemaTypeBlock, Labeled: true, Repeated: true, FreeForm: true},
+		&ConfigSchemaField{Name: "kms", Type: ConfigSchemaTypeBlock, Labeled: true, Repeated: true, FreeForm: true},
+		&ConfigSchemaField{Name: "hsm", Type: ConfigSchemaTypeBlock, Labeled: true, Repeated: true, FreeForm: true},
+		&ConfigSchemaField{Name: "entropy", Type: ConfigSchemaTypeBlock, Labeled: true, FreeForm: true},
+		&ConfigSchemaField{
+			Name: "listener", Type: ConfigSchemaTypeBlock, Labeled: true, Repeated: true,
+			Fields: schemaFields(reflect.TypeOf(configutil.Listener{})),
+		},
+		&ConfigSchemaField{
+			Name: "user_lockout", Type: ConfigSchemaTypeBlock, Labeled: true, Repeated: true,
+			Fields: schemaFields(reflect.TypeOf(configutil.UserLockout{})),
+		},
+		&ConfigSchemaField{
+			Name: "ui_assets", Type: ConfigSchemaTypeBlock, Labeled: true, Repeated: true,
+			Fields: schemaFields(reflect.TypeOf(UIAssets{})),
+		},
+		&ConfigSchemaField{
+			Name: "notification", Type: ConfigSchemaTypeBlock, Labeled: true, Repeated: true,
+			Fields: schemaFields(reflect.TypeOf(Notification{})),
+		},
+	)
+	return sortSchemaFields(fields)
+}
+
+// schemaFields returns the schema of the keys decoded into the fields of the
+// struct t.
+func schemaFields(t reflect.Type) []*ConfigSchemaField {
+	var fields []*ConfigSchemaField
+	seen := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("hcl"), ",")
+		if name == "" || name == "-" || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		// Raw fields are parsed into the field of the same name without the
+		// suffix, which has the actual type of the value
+		ft := f.Type
+		if ft.Kind() == reflect.Interface {
+			if parsed, ok := t.FieldByName(strings.TrimSuffix(f.Name, "Raw")); ok {
+				ft = parsed.Type
+			}
+		}
+
+		field := &ConfigSchemaField{Name: name, Type: schemaType(ft)}
+		if field.Type == ConfigSchemaTypeBlock {
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			field.Fields = schemaFields(ft)
+		}
+		fields = append(fields, field)
+	}
+	return sortSchemaFields(fields)
+}
+
+func schemaType(t reflect.Type) string {
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return ConfigSchemaTypeDuration
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return ConfigSchemaTypeBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return ConfigSchemaTypeInt
+	case reflect.Float32, reflect.Float64:
+		return ConfigSchemaTypeFloat
+	case reflect.String:
+		return ConfigSchemaTypeString
+	case reflect.Slice, reflect.Array:
+		return ConfigSchemaTypeList
+	case reflect.Map:
+		return ConfigSchemaTypeMap
+	case reflect.Struct:
+		return ConfigSchemaTypeBlock
+	case reflect.Pointer:
+		return schemaType(t.Elem())
+	default:
+		return ConfigSchemaTypeAny
+	}
+}
+
+func sortSchemaFields(fields []*ConfigSchemaField) []*ConfigSchemaField {
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].Name < fields[j].Name
+	})
+	return fields
+}
+
+// ValidateConfigSchema checks the configuration file, or directory of
+// configuration files, at path against the configuration schema. It reports
+// the unknown keys, the values of the wrong type, such as invalid durations,
+// and the blocks given more than once.
+func ValidateConfigSchema(path string) ([]configutil.ConfigError, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	files := []string{path}
+	if fi.IsDir() {
+		if files, err = configDirFiles(f); err != nil {
+			return nil, err
+		}
+	}
+
+	schema := ConfigSchema()
+	var results []configutil.ConfigError
+	for _, file := range files {
+		d, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+		obj, err := hcl.Parse(string(d))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %q: %w", file, err)
+		}
+		list, ok := obj.Node.(*ast.ObjectList)
+		if !ok {
+			return nil, fmt.Errorf("error parsing %q: file doesn't contain a root object", file)
+		}
+		results = append(results, validateSchemaList(list, schema, "", file)...)
+	}
+	return results, nil
+}
+
+func validateSchemaList(list *ast.ObjectList, schema []*ConfigSchemaField, prefix, source string) []configutil.ConfigError {
+	fields := make(map[string]*ConfigSchemaField, len(schema))
+	for _, field := range schema {
+		fields[field.Name] = field
+	}
+
+	var results []configutil.ConfigError
+	problem := func(pos token.Pos, format string, args ...interface{}) {
+		pos.Filename = source
+		results = append(results, configutil.ConfigError{
+			Problem:  fmt.Sprintf(format, args...),
+			Position: pos,
+		})
+	}
+
+	seen := make(map[string]bool)
+	for _, item := range list.Items {
+		if len(item.Keys) == 0 {
+			continue
+		}
+		key, ok := item.Keys[0].Token.Value().(string)
+		if !ok {
+			continue
+		}
+		pos := item.Keys[0].Pos()
+
+		field, ok := fields[key]
+		if !ok {
+			problem(pos, "unknown field %s%s found in configuration", prefix, key)
+			continue
+		}
+
+		if field.Type != ConfigSchemaTypeBlock {
+			if err := validateSchemaValue(field, item.Val); err != nil {
+				problem(pos, "invalid value for %s%s: %v", prefix, key, err)
+			}
+			continue
+		}
+
+		if !field.Repeated && seen[key] {
+			problem(pos, "only one %q block is permitted", prefix+key)
+		}
+		seen[key] = true
+
+		// Labeled blocks given as nested objects, as is usual in JSON, are
+		// keyed by their label
+		bodies := schemaBlockBodies(item.Val)
+		if field.Labeled && len(item.Keys) == 1 {
+			var labeled []*ast.ObjectList
+			for _, body := range bodies {
+				for _, inner := range body.Items {
+					labeled = append(labeled, schemaBlockBodies(inner.Val)...)
+				}
+			}
+			bodies = labeled
+		}
+		if bodies == nil {
+			problem(pos, "%s%s must be a block", prefix, key)
+			continue
+		}
+		if field.FreeForm {
+			continue
+		}
+		for _, body := range bodies {
+			results = append(results, validateSchemaList(body, field.Fields, prefix+key+".", source)...)
+		}
+	}
+	return results
+}
+
+// schemaBlockBodies returns the bodies of the block value node, which is
+// either an object or a list of objects.
+func schemaBlockBodies(node ast.Node) []*ast.ObjectList {
+	switch n := node.(type) {
+	case *ast.ObjectType:
+		return []*ast.ObjectList{n.List}
+	case *ast.ListType:
+		var bodies []*ast.ObjectList
+		for _, elem := range n.List {
+			if obj, ok := elem.(*ast.ObjectType); ok {
+				bodies = append(bodies, obj.List)
+			}
+		}
+		return bodies
+	default:
+		return nil
+	}
+}
+
+func validateSchemaValue(field *ConfigSchemaField, node ast.Node) error {
+	switch field.Type {
+	case ConfigSchemaTypeAny, ConfigSchemaTypeMap:
+		return nil
+	case ConfigSchemaTypeList:
+		if _, ok := node.(*ast.ObjectType); ok {
+			return fmt.Errorf("expected a list")
+		}
+		return nil
+	}
+
+	lit, ok := node.(*ast.LiteralType)
+	if !ok {
+		return fmt.Errorf("expected a %s", field.Type)
+	}
+	value := lit.Token.Value()
+
+	var err error
+	switch field.Type {
+	case ConfigSchemaTypeDuration:
+		_, err = parseutil.ParseDurationSecond(value)
+	case ConfigSchemaTypeBool:
+		_, err = parseutil.ParseBool(value)
+	case ConfigSchemaTypeInt:
+		_, err = parseutil.ParseInt(value)
+	}
+	return err
+}
+
+// ValidateStanzas checks the merged configuration for the combinations of
+// stanzas the server refuses to start with.
+func (c *Config) ValidateStanzas() []configutil.ConfigError {
+	var problems []string
+	if c.Storage == nil {
+		problems = append(problems, "a storage backend must be specified")
+	} else {
+		raftStorage := c.Storage.Type == "raft"
+		raftHAStorage := c.HAStorage != nil && c.HAStorage.Type == "raft"
+		switch {
+		case raftStorage && raftHAStorage:
+			problems = append(problems, "raft cannot be set both as 'storage' and 'ha_storage'")
+		case raftStorage && c.HAStorage != nil:
+			problems = append(problems, "'ha_storage' cannot be declared when raft is the storage type")
+		}
+		if raftStorage && c.Storage.DisableClustering {
+			problems = append(problems, "disable_clustering cannot be set to true when raft is the storage type")
+		}
+		if raftHAStorage && c.HAStorage.DisableClustering {
+			problems = append(problems, "disable_clustering cannot be set to true when raft is the HA storage type")
+		}
+		if (raftStorage || raftHAStorage) && c.ClusterAddr == "" {
+			problems = append(problems, "cluster_addr must be set when using raft storage")
+		}
+	}
+
+	if c.APIAddr != "" && c.APIAddr == c.ClusterAddr {
+		problems = append(problems, fmt.Sprintf("address %q used for both api_addr and cluster_addr", c.APIAddr))
+	}
+
+	addrs := make(map[string]int)
+	for i, l := range c.Listeners {
+		if l.Type != "tcp" {
+			continue
+		}
+		if !l.TLSDisable && (l.TLSCertFile == "" || l.TLSKeyFile == "") {
+			problems = append(problems, fmt.Sprintf("listener %d: tls_cert_file and tls_key_file must be set unless tls_disable is true", i))
+		}
+		if j, ok := addrs[l.Address]; ok && l.Address != "" {
+			problems = append(problems, fmt.Sprintf("listeners %d and %d both listen on %q", j, i, l.Address))
+		}
+		addrs[l.Address] = i
+	}
+
+	results := make([]configutil.ConfigError, 0, len(problems))
+	for _, p := range problems {
+		results = append(results, configutil.ConfigError{Problem: p})
+	}
+	return results
+}
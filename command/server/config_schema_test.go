@@ -0,0 +1,106 @@
+package server
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfigSchema(t *testing.T) {
+	problems := func(path string) []string {
+		t.Helper()
+		results, err := ValidateConfigSchema(path)
+		require.NoError(t, err)
+		var out []string
+		for _, r := range results {
+			out = append(out, r.Problem)
+		}
+		return out
+	}
+
+	// The schema agrees with the unused keys reported on load
+	require.ElementsMatch(t, []string{
+		"unknown field telemetry.bad_value found in configuration",
+		"unknown field sentinel found in configuration",
+	}, problems("./test-fixtures/config.hcl"))
+	require.Equal(t, []string{"unknown field sentinel found in configuration"}, problems("./test-fixtures/config.hcl.json"))
+	require.Empty(t, problems("./test-fixtures/config_small.hcl"))
+	require.Empty(t, problems("./test-fixtures/config_small.json"))
+	require.Empty(t, problems("./test-fixtures/config_seals.hcl"))
+	require.Equal(t, []string{"unknown field sentinel found in configuration"}, problems("./test-fixtures/config-dir"))
+
+	path := filepath.Join(t.TempDir(), "config.hcl")
+	require.NoError(t, os.WriteFile(path, []byte(`
+storage "file" {
+  path = "/tmp/a"
+}
+storage "file" {
+  path = "/tmp/b"
+}
+listener "tcp" {
+  address      = "127.0.0.1:8200"
+  tls_disable  = "maybe"
+  http_timeout = "10s"
+}
+default_lease_ttl = "10x"
+cache_size        = "lots"
+`), 0o600))
+	require.ElementsMatch(t, []string{
+		`only one "storage" block is permitted`,
+		`invalid value for listener.tls_disable: cannot parse '' as bool: strconv.ParseBool: parsing "maybe": invalid syntax`,
+		"unknown field listener.http_timeout found in configuration",
+		`invalid value for default_lease_ttl: time: unknown unit "x" in duration "10x"`,
+		`invalid value for cache_size: strconv.ParseInt: parsing "lots": invalid syntax`,
+	}, problems(path))
+}
+
+func TestConfig_ValidateStanzas(t *testing.T) {
+	validate := func(hcl string) []string {
+		t.Helper()
+		config, err := ParseConfig(hcl, "")
+		require.NoError(t, err)
+		var out []string
+		for _, r := range config.ValidateStanzas() {
+			out = append(out, r.Problem)
+		}
+		return out
+	}
+
+	require.Empty(t, validate(`
+storage "raft" {
+  path = "/tmp/raft"
+}
+listener "tcp" {
+  tls_disable = true
+}
+cluster_addr = "https://127.0.0.1:8201"
+api_addr     = "https://127.0.0.1:8200"
+`))
+
+	require.ElementsMatch(t, []string{
+		"'ha_storage' cannot be declared when raft is the storage type",
+		"cluster_addr must be set when using raft storage",
+		"listener 0: tls_cert_file and tls_key_file must be set unless tls_disable is true",
+		`listeners 0 and 1 both listen on "127.0.0.1:8200"`,
+	}, validate(`
+storage "raft" {
+  path = "/tmp/raft"
+}
+ha_storage "consul" {}
+listener "tcp" {
+  address = "127.0.0.1:8200"
+}
+listener "tcp" {
+  address     = "127.0.0.1:8200"
+  tls_disable = true
+}
+`))
+
+	require.Equal(t, []string{"a storage backend must be specified"}, validate(`
+listener "tcp" {
+  tls_disable = true
+}
+`))
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/openbao/openbao/internalshared/configutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -80,6 +81,90 @@ func TestUnknownFieldValidationHcl(t *testing.T) {
 	testUnknownFieldValidationHcl(t)
 }
 
+func TestParseUIAssets(t *testing.T) {
+	config, err := ParseConfig(`
+ui_assets "tenant-a" {
+	path       = "/opt/openbao/ui/tenant-a"
+	hosts      = ["tenant-a.example.com", "*.tenant-a.example.com"]
+	namespaces = ["tenant-a"]
+}
+ui_assets "tenant-b" {
+	path       = "/opt/openbao/ui/tenant-b.zip"
+	namespaces = ["tenant-b"]
+}
+`, "")
+	require.NoError(t, err)
+	require.Empty(t, config.Validate(""))
+	require.Len(t, config.UIAssets, 2)
+	require.Equal(t, "tenant-a", config.UIAssets[0].Name)
+	require.Equal(t, "/opt/openbao/ui/tenant-a", config.UIAssets[0].Path)
+	require.Equal(t, []string{"tenant-a.example.com", "*.tenant-a.example.com"}, config.UIAssets[0].Hosts)
+	require.Equal(t, []string{"tenant-b"}, config.UIAssets[1].Namespaces)
+
+	// Later configurations replace the asset sets of the same name.
+	merged := config.Merge(&Config{
+		SharedConfig: &configutil.SharedConfig{},
+		UIAssets:     []*UIAssets{{Name: "tenant-b", Path: "/srv/tenant-b", Namespaces: []string{"tenant-b"}}},
+	})
+	require.Len(t, merged.UIAssets, 2)
+	for _, u := range merged.UIAssets {
+		if u.Name == "tenant-b" {
+			require.Equal(t, "/srv/tenant-b", u.Path)
+		}
+	}
+
+	_, err = ParseConfig(`
+ui_assets "tenant-a" {
+	path = "/opt/openbao/ui/tenant-a"
+}
+`, "")
+	require.ErrorContains(t, err, "at least one of hosts and namespaces is required")
+}
+
+func TestParseNotifications(t *testing.T) {
+	config, err := ParseConfig(`
+notification "paging" {
+	url         = "https://paging.example.com/openbao"
+	events      = ["seal", "quorum_loss"]
+	hmac_key    = "secret"
+	max_retries = 5
+}
+notification "audit" {
+	url = "http://audit.example.com/events"
+}
+`, "")
+	require.NoError(t, err)
+	require.Empty(t, config.Validate(""))
+	require.Len(t, config.Notifications, 2)
+	require.Equal(t, "paging", config.Notifications[0].Name)
+	require.Equal(t, []string{"seal", "quorum_loss"}, config.Notifications[0].Events)
+	require.Equal(t, "secret", config.Notifications[0].HMACKey)
+	require.Equal(t, 5, *config.Notifications[0].MaxRetries)
+	require.Nil(t, config.Notifications[1].MaxRetries)
+
+	// The URLs and HMAC keys are left out of the sanitized configuration
+	sanitized := config.Sanitized()["notification"].([]interface{})
+	require.Equal(t, map[string]interface{}{
+		"name":   "paging",
+		"events": []string{"seal", "quorum_loss"},
+	}, sanitized[0])
+
+	_, err = ParseConfig(`
+notification "paging" {
+	url    = "https://paging.example.com/openbao"
+	events = ["sealed"]
+}
+`, "")
+	require.ErrorContains(t, err, `unknown event "sealed"`)
+
+	_, err = ParseConfig(`
+notification "paging" {
+	url = "paging.example.com"
+}
+`, "")
+	require.ErrorContains(t, err, "url must be an http or https URL")
+}
+
 func TestUnknownFieldValidationListenerAndStorage(t *testing.T) {
 	testUnknownFieldValidationStorageAndListener(t)
 }
@@ -74,6 +74,8 @@ func testLoadConfigFile_topLevel(t *testing.T, entropy *configutil.Entropy) {
 	disableSSCTs := true
 	expected := &Config{
 		SharedConfig: &configutil.SharedConfig{
+			Entropy: entropy,
+
 			Listeners: []*configutil.Listener{
 				{
 					Type:                  "tcp",
@@ -184,6 +186,8 @@ func testLoadConfigFile_json2(t *testing.T, entropy *configutil.Entropy) {
 
 	expected := &Config{
 		SharedConfig: &configutil.SharedConfig{
+			Entropy: entropy,
+
 			Listeners: []*configutil.Listener{
 				{
 					Type:                  "tcp",
@@ -726,11 +730,16 @@ func testConfig_Sanitized(t *testing.T) {
 				"type": "tcp",
 			},
 		},
-		"log_format":       "",
-		"log_level":        "",
-		"max_lease_ttl":    (30 * 24 * time.Hour) / time.Second,
-		"pid_file":         "./pidfile",
-		"plugin_directory": "",
+		"lease_revocation_rate_limit": 0,
+		"list_default_limit":          0,
+		"disabled_mount_retention":    0 * time.Second,
+		"log_format":                  "",
+		"log_level":                   "",
+		"max_lease_ttl":               (30 * 24 * time.Hour) / time.Second,
+		"root_token_ttl":              0 * time.Second,
+		"root_token_num_uses":         0,
+		"pid_file":                    "./pidfile",
+		"plugin_directory":            "",
 		"seals": []interface{}{
 			map[string]interface{}{
 				"disabled": false,
@@ -779,6 +788,7 @@ func testConfig_Sanitized(t *testing.T) {
 		},
 		"administrative_namespace_path": "admin/",
 		"imprecise_lease_role_tracking": false,
+		"restricted_crypto":             false,
 	}
 
 	addExpectedEntSanitizedConfig(expected, []string{"http"})
@@ -25,6 +25,9 @@ type ListenerFactory func(*configutil.Listener, io.Writer, cli.Ui) (net.Listener
 var BuiltinListeners = map[string]ListenerFactory{
 	"tcp":  tcpListenerFactory,
 	"unix": unixListenerFactory,
+	// grpc listeners are plain TCP listeners; they only differ in how the
+	// server serves requests on them.
+	"grpc": tcpListenerFactory,
 }
 
 // NewListener creates a new listener of the given type with the given
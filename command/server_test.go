@@ -14,7 +14,9 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"net"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
@@ -196,6 +198,66 @@ func TestServer_ReloadListener(t *testing.T) {
 	wg.Wait()
 }
 
+func TestServer_ReloadAddRemoveListener(t *testing.T) {
+	t.Parallel()
+
+	listenerHCL := func(ports ...int) string {
+		hcl := `backend "inmem" {}` + "\n"
+		for _, port := range ports {
+			hcl += fmt.Sprintf("listener \"tcp\" {\n  address = \"127.0.0.1:%d\"\n  tls_disable = true\n}\n", port)
+		}
+		return hcl
+	}
+
+	configPath := filepath.Join(t.TempDir(), "reload.hcl")
+	require.NoError(t, os.WriteFile(configPath, []byte(listenerHCL(8206, 8207)), 0o600))
+
+	ui, cmd := testServerCommand(t)
+
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if code := cmd.Run([]string{"-config", configPath}); code != 0 {
+			output := ui.ErrorWriter.String() + ui.OutputWriter.String()
+			t.Errorf("got a non-zero exit status: %s", output)
+		}
+	}()
+
+	select {
+	case <-cmd.startedCh:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout")
+	}
+
+	listening := func(port int) bool {
+		conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%d", port), time.Second)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+		return true
+	}
+	require.True(t, listening(8206))
+	require.True(t, listening(8207))
+	require.False(t, listening(8208))
+
+	require.NoError(t, os.WriteFile(configPath, []byte(listenerHCL(8207, 8208)), 0o600))
+	cmd.SighupCh <- struct{}{}
+	select {
+	case <-cmd.reloadedCh:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout")
+	}
+
+	require.False(t, listening(8206))
+	require.True(t, listening(8207))
+	require.True(t, listening(8208))
+
+	cmd.ShutdownCh <- struct{}{}
+	wg.Wait()
+}
+
 func TestServer(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,80 @@
+// Package agekeys encrypts data to age X25519 recipients, in the format of
+// age v1 (https://age-encryption.org/v1), so that it can be decrypted with the
+// age tools and an identity kept offline.
+package agekeys
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+const headerVersionLine = "age-encryption.org/v1"
+
+// X25519Recipient is the public key of an age X25519 identity.
+type X25519Recipient = age.X25519Recipient
+
+// X25519Identity is the private key of an age X25519 recipient.
+type X25519Identity = age.X25519Identity
+
+// ParseX25519Recipient parses an age X25519 recipient, of the form
+// age1<Bech32 data>.
+func ParseX25519Recipient(s string) (*X25519Recipient, error) {
+	return age.ParseX25519Recipient(s)
+}
+
+// GenerateX25519Identity generates a new identity.
+func GenerateX25519Identity() (*X25519Identity, error) {
+	return age.GenerateX25519Identity()
+}
+
+// ParseX25519Identity parses an age X25519 identity, of the form
+// AGE-SECRET-KEY-1<Bech32 data>.
+func ParseX25519Identity(s string) (*X25519Identity, error) {
+	return age.ParseX25519Identity(s)
+}
+
+// Encrypt returns a writer encrypting the data written to it to the
+// recipients, writing the result to dst. The writer must be closed to write
+// the last chunk of the data.
+func Encrypt(dst io.Writer, recipients ...*X25519Recipient) (io.WriteCloser, error) {
+	if len(recipients) == 0 {
+		return nil, errors.New("no recipients")
+	}
+
+	ageRecipients := make([]age.Recipient, 0, len(recipients))
+	for _, r := range recipients {
+		ageRecipients = append(ageRecipients, r)
+	}
+	return age.Encrypt(dst, ageRecipients...)
+}
+
+// Decrypt returns a reader of the data in src encrypted to the identity.
+func Decrypt(src io.Reader, identity *X25519Identity) (io.Reader, error) {
+	return age.Decrypt(src, identity)
+}
+
+// Armor returns the ASCII armored form of encrypted data, which the age
+// tools decrypt as is.
+func Armor(encrypted []byte) string {
+	var sb strings.Builder
+	w := armor.NewWriter(&sb)
+	// Writes to a strings.Builder do not fail
+	_, _ = w.Write(encrypted)
+	_ = w.Close()
+	return sb.String()
+}
+
+// Dearmor returns the encrypted data of its ASCII armored form.
+func Dearmor(armored string) ([]byte, error) {
+	return io.ReadAll(armor.NewReader(strings.NewReader(armored)))
+}
+
+// IsEncrypted returns whether data starts like encrypted data.
+func IsEncrypted(prefix []byte) bool {
+	return bytes.HasPrefix(prefix, []byte(headerVersionLine+"\n")) || bytes.HasPrefix(prefix, []byte(armor.Header))
+}
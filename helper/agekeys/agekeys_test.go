@@ -0,0 +1,158 @@
+package agekeys
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// A key pair generated by age-keygen, from the tests of the age tools.
+const (
+	testIdentity  = "AGE-SECRET-KEY-1EGTZVFFV20835NWYV6270LXYVK2VKNX2MMDKWYKLMGR48UAWX40Q2P2LM0"
+	testRecipient = "age1xmwwc06ly3ee5rytxm9mflaz2u56jjj36s0mypdrwsvlul66mv4q47ryef"
+)
+
+func TestParse(t *testing.T) {
+	identity, err := ParseX25519Identity(testIdentity)
+	require.NoError(t, err)
+	require.Equal(t, testIdentity, identity.String())
+	require.Equal(t, testRecipient, identity.Recipient().String())
+
+	recipient, err := ParseX25519Recipient(testRecipient)
+	require.NoError(t, err)
+	require.Equal(t, testRecipient, recipient.String())
+
+	for _, s := range []string{
+		"",
+		"age1",
+		testIdentity,
+		// Invalid checksum
+		testRecipient[:len(testRecipient)-1] + "q",
+		// Mixed case
+		"Age" + testRecipient[3:],
+	} {
+		_, err := ParseX25519Recipient(s)
+		require.Error(t, err, s)
+	}
+	_, err = ParseX25519Identity(testRecipient)
+	require.Error(t, err)
+}
+
+// TestVectors decrypts files of the age test suite
+// (https://c2sp.org/CCTV/age), available under the 0BSD, CC0 1.0 or Unlicense
+// licenses.
+func TestVectors(t *testing.T) {
+	for _, name := range []string{"x25519", "x25519_multiple_recipients", "x25519_bad_tag", "x25519_no_match", "armor"} {
+		t.Run(name, func(t *testing.T) {
+			f, err := os.Open(filepath.Join("testdata", name))
+			require.NoError(t, err)
+			defer f.Close()
+
+			// The vectors are made of a header, an empty line, and the file
+			br := bufio.NewReader(f)
+			header := make(map[string]string)
+			for {
+				line, err := br.ReadString('\n')
+				require.NoError(t, err)
+				line = strings.TrimSuffix(line, "\n")
+				if line == "" {
+					break
+				}
+				key, value, ok := strings.Cut(line, ": ")
+				require.True(t, ok, line)
+				header[key] = value
+			}
+			file, err := io.ReadAll(br)
+			require.NoError(t, err)
+
+			identity, err := ParseX25519Identity(header["identity"])
+			require.NoError(t, err)
+
+			if header["armored"] == "yes" {
+				require.True(t, IsEncrypted(file))
+				file, err = Dearmor(string(file))
+				require.NoError(t, err)
+			}
+			require.True(t, IsEncrypted(file))
+
+			var payload []byte
+			r, err := Decrypt(bytes.NewReader(file), identity)
+			if err == nil {
+				payload, err = io.ReadAll(r)
+			}
+
+			switch header["expect"] {
+			case "success":
+				require.NoError(t, err)
+				sum := sha256.Sum256(payload)
+				require.Equal(t, header["payload"], hex.EncodeToString(sum[:]))
+			default:
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	alice, err := GenerateX25519Identity()
+	require.NoError(t, err)
+	bob, err := ParseX25519Identity(testIdentity)
+	require.NoError(t, err)
+	eve, err := GenerateX25519Identity()
+	require.NoError(t, err)
+
+	plaintext := make([]byte, 200*1024)
+	_, err = rand.Read(plaintext)
+	require.NoError(t, err)
+
+	var encrypted bytes.Buffer
+	w, err := Encrypt(&encrypted, alice.Recipient(), bob.Recipient())
+	require.NoError(t, err)
+	_, err = w.Write(plaintext)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	for _, identity := range []*X25519Identity{alice, bob} {
+		r, err := Decrypt(bytes.NewReader(encrypted.Bytes()), identity)
+		require.NoError(t, err)
+		decrypted, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.Equal(t, plaintext, decrypted)
+	}
+
+	_, err = Decrypt(bytes.NewReader(encrypted.Bytes()), eve)
+	require.Error(t, err)
+
+	_, err = Encrypt(io.Discard)
+	require.Error(t, err)
+}
+
+func TestArmor(t *testing.T) {
+	identity, err := GenerateX25519Identity()
+	require.NoError(t, err)
+
+	var encrypted bytes.Buffer
+	w, err := Encrypt(&encrypted, identity.Recipient())
+	require.NoError(t, err)
+	_, err = w.Write(bytes.Repeat([]byte("openbao"), 100))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	require.True(t, IsEncrypted(encrypted.Bytes()))
+
+	armored := Armor(encrypted.Bytes())
+	require.True(t, IsEncrypted([]byte(armored)))
+
+	dearmored, err := Dearmor(armored)
+	require.NoError(t, err)
+	require.Equal(t, encrypted.Bytes(), dearmored)
+	require.False(t, IsEncrypted([]byte(`{"bundle":""}`)))
+}
@@ -12,13 +12,18 @@ import (
 	credKerb "github.com/openbao/openbao/builtin/credential/kerberos"
 	credKube "github.com/openbao/openbao/builtin/credential/kubernetes"
 	credLdap "github.com/openbao/openbao/builtin/credential/ldap"
+	credPeerCred "github.com/openbao/openbao/builtin/credential/peercred"
 	credRadius "github.com/openbao/openbao/builtin/credential/radius"
+	credSPIFFE "github.com/openbao/openbao/builtin/credential/spiffe"
 	credUserpass "github.com/openbao/openbao/builtin/credential/userpass"
+	logicalKeyMgmt "github.com/openbao/openbao/builtin/logical/keymgmt"
 	logicalKube "github.com/openbao/openbao/builtin/logical/kubernetes"
 	logicalKv "github.com/openbao/openbao/builtin/logical/kv"
 	logicalLDAP "github.com/openbao/openbao/builtin/logical/openldap"
+	logicalPairing "github.com/openbao/openbao/builtin/logical/pairing"
 	logicalPki "github.com/openbao/openbao/builtin/logical/pki"
 	logicalRabbit "github.com/openbao/openbao/builtin/logical/rabbitmq"
+	logicalSCM "github.com/openbao/openbao/builtin/logical/scm"
 	logicalSsh "github.com/openbao/openbao/builtin/logical/ssh"
 	logicalTotp "github.com/openbao/openbao/builtin/logical/totp"
 	logicalTransit "github.com/openbao/openbao/builtin/logical/transit"
@@ -77,7 +82,9 @@ func newRegistry() *registry {
 			"kubernetes": {Factory: credKube.Factory},
 			"ldap":       {Factory: credLdap.Factory},
 			"oidc":       {Factory: credJWT.Factory},
+			"peercred":   {Factory: credPeerCred.Factory},
 			"radius":     {Factory: credRadius.Factory},
+			"spiffe":     {Factory: credSPIFFE.Factory},
 			"userpass":   {Factory: credUserpass.Factory},
 		},
 		databasePlugins: map[string]databasePlugin{
@@ -93,12 +100,15 @@ func newRegistry() *registry {
 			"postgresql-database-plugin": {Factory: dbPostgres.New},
 		},
 		logicalBackends: map[string]logicalBackend{
+			"keymgmt":    {Factory: logicalKeyMgmt.Factory},
 			"kubernetes": {Factory: logicalKube.Factory},
 			"kv":         {Factory: logicalKv.Factory},
 			"openldap":   {Factory: logicalLDAP.Factory},
 			"ldap":       {Factory: logicalLDAP.Factory},
+			"pairing":    {Factory: logicalPairing.Factory},
 			"pki":        {Factory: logicalPki.Factory},
 			"rabbitmq":   {Factory: logicalRabbit.Factory},
+			"scm":        {Factory: logicalSCM.Factory},
 			"ssh":        {Factory: logicalSsh.Factory},
 			"totp":       {Factory: logicalTotp.Factory},
 			"transit":    {Factory: logicalTransit.Factory},
@@ -5,6 +5,7 @@ package fairshare
 
 import (
 	"container/list"
+	"context"
 	"fmt"
 	"io"
 	"math"
@@ -16,11 +17,78 @@ import (
 	uuid "github.com/hashicorp/go-uuid"
 	"github.com/openbao/openbao/helper/metricsutil"
 	"github.com/openbao/openbao/sdk/v2/helper/logging"
+	"golang.org/x/time/rate"
 )
 
+// Priority is the priority of a job. Workers are assigned to jobs of a
+// higher priority, across all queues, before jobs of a lower priority.
+// Within a priority, workers are shared fairly between queues.
+type Priority int
+
+const (
+	PriorityHigh Priority = iota
+	PriorityLow
+
+	numPriorities
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityHigh:
+		return "high"
+	case PriorityLow:
+		return "low"
+	default:
+		return fmt.Sprintf("priority-%d", int(p))
+	}
+}
+
+// jobQueue holds the pending jobs of a queue by priority
+type jobQueue struct {
+	jobs [numPriorities]*list.List
+}
+
+func newJobQueue() *jobQueue {
+	q := &jobQueue{}
+	for i := range q.jobs {
+		q.jobs[i] = list.New()
+	}
+	return q
+}
+
+// Len returns the number of pending jobs in the queue, of any priority
+func (q *jobQueue) Len() int {
+	n := 0
+	for _, l := range q.jobs {
+		n += l.Len()
+	}
+	return n
+}
+
+// pop removes and returns the next job of the highest priority, along with
+// its priority
+func (q *jobQueue) pop() (Job, Priority) {
+	for p, l := range q.jobs {
+		if e := l.Front(); e != nil {
+			return l.Remove(e).(Job), Priority(p)
+		}
+	}
+	return nil, 0
+}
+
 type JobManager struct {
 	name   string
-	queues map[string]*list.List
+	queues map[string]*jobQueue
+
+	// pendingJobs tracks the number of pending jobs per priority
+	pendingJobs [numPriorities]int
+
+	// limiter limits the rate at which jobs are dispatched to workers
+	limiter *rate.Limiter
+
+	// ctx is canceled when the job manager is stopped
+	ctx    context.Context
+	cancel context.CancelFunc
 
 	quit    chan struct{}
 	newWork chan struct{} // must be buffered
@@ -63,10 +131,14 @@ func NewJobManager(name string, numWorkers int, l log.Logger, metricSink *metric
 	}
 
 	wp := newDispatcher(fmt.Sprintf("%s-dispatcher", name), numWorkers, l)
+	ctx, cancel := context.WithCancel(context.Background())
 
 	j := JobManager{
 		name:              name,
-		queues:            make(map[string]*list.List),
+		queues:            make(map[string]*jobQueue),
+		limiter:           rate.NewLimiter(rate.Inf, 0),
+		ctx:               ctx,
+		cancel:            cancel,
 		quit:              make(chan struct{}),
 		newWork:           make(chan struct{}, 1),
 		workerPool:        wp,
@@ -96,12 +168,40 @@ func (j *JobManager) Stop() {
 	j.onceStop.Do(func() {
 		j.logger.Trace("terminating job manager...")
 		close(j.quit)
+		j.cancel()
 		j.workerPool.stop()
 	})
 }
 
-// AddJob adds a job to the given queue, creating the queue if it doesn't exist
+// SetRateLimit limits the rate at which jobs are dispatched to workers to
+// the given number of jobs per second, allowing bursts of up to burst jobs.
+// A limit of zero or less removes the limit. It is safe to call concurrently
+// with the processing of jobs.
+func (j *JobManager) SetRateLimit(limit float64, burst int) {
+	if limit <= 0 {
+		j.limiter.SetLimit(rate.Inf)
+		return
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	j.limiter.SetBurst(burst)
+	j.limiter.SetLimit(rate.Limit(limit))
+}
+
+// AddJob adds a job of high priority to the given queue, creating the queue
+// if it doesn't exist
 func (j *JobManager) AddJob(job Job, queueID string) {
+	j.AddJobWithPriority(job, queueID, PriorityHigh)
+}
+
+// AddJobWithPriority adds a job of the given priority to the given queue,
+// creating the queue if it doesn't exist
+func (j *JobManager) AddJobWithPriority(job Job, queueID string, priority Priority) {
+	if priority < 0 || priority >= numPriorities {
+		priority = PriorityLow
+	}
+
 	j.l.Lock()
 	if len(j.queues) == 0 {
 		defer func() {
@@ -116,8 +216,9 @@ func (j *JobManager) AddJob(job Job, queueID string) {
 		j.addQueue(queueID)
 	}
 
-	j.queues[queueID].PushBack(job)
+	j.queues[queueID].jobs[priority].PushBack(job)
 	j.totalJobs++
+	j.pendingJobs[priority]++
 
 	if j.metricSink != nil {
 		j.metricSink.AddSampleWithLabels([]string{j.name, "job_manager", "queue_length"}, float32(j.queues[queueID].Len()), []metrics.Label{{"queue_id", queueID}})
@@ -125,6 +226,19 @@ func (j *JobManager) AddJob(job Job, queueID string) {
 	}
 }
 
+// GetPendingJobCountsByPriority returns the number of pending jobs in the
+// job manager per priority
+func (j *JobManager) GetPendingJobCountsByPriority() map[Priority]int {
+	j.l.RLock()
+	defer j.l.RUnlock()
+
+	out := make(map[Priority]int, numPriorities)
+	for p, n := range j.pendingJobs {
+		out[Priority(p)] = n
+	}
+	return out
+}
+
 // GetCurrentJobCount returns the total number of pending jobs in the job manager
 func (j *JobManager) GetPendingJobCount() int {
 	j.l.RLock()
@@ -174,10 +288,10 @@ func (j *JobManager) getNextJob() (Job, string) {
 		return nil, ""
 	}
 
-	jobElement := j.queues[queueID].Front()
-	jobRaw := j.queues[queueID].Remove(jobElement)
+	job, priority := j.queues[queueID].pop()
 
 	j.totalJobs--
+	j.pendingJobs[priority]--
 
 	if j.metricSink != nil {
 		j.metricSink.AddSampleWithLabels([]string{j.name, "job_manager", "queue_length"}, float32(j.queues[queueID].Len()), []metrics.Label{{"queue_id", queueID}})
@@ -191,7 +305,7 @@ func (j *JobManager) getNextJob() (Job, string) {
 		j.removeLastQueueAccessed()
 	}
 
-	return jobRaw.(Job), queueID
+	return job, queueID
 }
 
 // returns the next queue to assign work from, and a bool if there is a queue
@@ -203,19 +317,26 @@ func (j *JobManager) getNextQueue() (string, bool) {
 	var canAssignWorker bool
 
 	// ensure we loop through all existing queues until we find an eligible
-	// queue, if one exists.
-	queueIdx := j.nextQueueIndex(j.lastQueueAccessed)
-	for i := 0; i < len(j.queuesIndex); i++ {
-		potentialQueueID := j.queuesIndex[queueIdx]
-
-		if !j.queueWorkersSaturated(potentialQueueID) {
-			nextQueue = potentialQueueID
-			canAssignWorker = true
-			j.lastQueueAccessed = queueIdx
-			break
+	// queue, if one exists. queues with jobs of a higher priority are
+	// considered first.
+	for p := range j.pendingJobs {
+		if j.pendingJobs[p] == 0 {
+			continue
 		}
 
-		queueIdx = j.nextQueueIndex(queueIdx)
+		queueIdx := j.nextQueueIndex(j.lastQueueAccessed)
+		for i := 0; i < len(j.queuesIndex); i++ {
+			potentialQueueID := j.queuesIndex[queueIdx]
+
+			if j.queues[potentialQueueID].jobs[p].Len() > 0 && !j.queueWorkersSaturated(potentialQueueID) {
+				nextQueue = potentialQueueID
+				canAssignWorker = true
+				j.lastQueueAccessed = queueIdx
+				return nextQueue, canAssignWorker
+			}
+
+			queueIdx = j.nextQueueIndex(queueIdx)
+		}
 	}
 
 	return nextQueue, canAssignWorker
@@ -286,6 +407,13 @@ func (j *JobManager) assignWork() {
 
 				job, queueID := j.getNextJob()
 				if job != nil {
+					// wait for the rate limit, if any. this only fails if
+					// the job manager is stopped.
+					if err := j.limiter.Wait(j.ctx); err != nil {
+						j.wg.Done()
+						return
+					}
+
 					j.workerPool.dispatch(job,
 						func() {
 							j.incrementWorkerCount(queueID)
@@ -319,7 +447,7 @@ func (j *JobManager) assignWork() {
 // note: this must be called with j.l held for write
 func (j *JobManager) addQueue(queueID string) {
 	if _, ok := j.queues[queueID]; !ok {
-		j.queues[queueID] = list.New()
+		j.queues[queueID] = newJobQueue()
 		j.queuesIndex = append(j.queuesIndex, queueID)
 	}
 
@@ -747,3 +747,88 @@ func TestFairshare_queueWorkersSaturated(t *testing.T) {
 		j.l.RUnlock()
 	}
 }
+
+func TestJobManager_priority(t *testing.T) {
+	j := NewJobManager("test-job-mgr", 18, nil, nil)
+
+	low := newDefaultTestJob(t, "low")
+	high := newDefaultTestJob(t, "high")
+	j.AddJobWithPriority(&low, "a", PriorityLow)
+	j.AddJobWithPriority(&low, "a", PriorityLow)
+	j.AddJobWithPriority(&high, "b", PriorityHigh)
+	j.AddJobWithPriority(&high, "a", PriorityHigh)
+
+	counts := j.GetPendingJobCountsByPriority()
+	if counts[PriorityHigh] != 2 || counts[PriorityLow] != 2 {
+		t.Fatalf("bad pending job counts: %#v", counts)
+	}
+
+	// jobs of high priority are processed first, round robin across queues
+	expected := []struct {
+		id      string
+		queueID string
+	}{
+		{"high", "a"},
+		{"high", "b"},
+		{"low", "a"},
+		{"low", "a"},
+	}
+	for _, e := range expected {
+		job, queueID := j.getNextJob()
+		if job == nil || job.(*testJob).id != e.id || queueID != e.queueID {
+			t.Fatalf("bad next job: expected %s from %s, got %#v from %s", e.id, e.queueID, job, queueID)
+		}
+	}
+
+	if job, _ := j.getNextJob(); job != nil {
+		t.Fatalf("expected no more jobs, got %#v", job)
+	}
+	counts = j.GetPendingJobCountsByPriority()
+	if counts[PriorityHigh] != 0 || counts[PriorityLow] != 0 {
+		t.Fatalf("bad pending job counts: %#v", counts)
+	}
+}
+
+func TestJobManager_rateLimit(t *testing.T) {
+	j := NewJobManager("test-job-mgr", 5, nil, nil)
+	j.SetRateLimit(20, 1)
+
+	var mu sync.Mutex
+	done := 0
+	numJobs := 10
+	ex := func(_ string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		done++
+		return nil
+	}
+	onFail := func(_ error) {}
+
+	start := time.Now()
+	j.Start()
+	defer j.Stop()
+	for i := 0; i < numJobs; i++ {
+		job := newTestJob(t, fmt.Sprintf("job-%d", i), ex, onFail)
+		j.AddJob(&job, "a")
+	}
+
+	timeout := time.After(5 * time.Second)
+	for {
+		mu.Lock()
+		finished := done == numJobs
+		mu.Unlock()
+		if finished {
+			break
+		}
+		select {
+		case <-timeout:
+			t.Fatal("timed out waiting for jobs")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	// 10 jobs at 20 per second, with a burst of 1, take at least 450ms
+	if elapsed := time.Since(start); elapsed < 400*time.Millisecond {
+		t.Fatalf("jobs were not rate limited, took %s", elapsed)
+	}
+}
@@ -4,7 +4,10 @@
 package monitor
 
 import (
+	"encoding/json"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	log "github.com/hashicorp/go-hclog"
@@ -24,6 +27,80 @@ type Monitor interface {
 	Stop()
 }
 
+// Filter selects the log messages streamed by a monitor. A log message is
+// streamed only if it passes all the set fields.
+type Filter struct {
+	// Subsystems, if set, restricts the stream to the messages of the named
+	// loggers and of their sub-loggers, such as "core" or "storage.raft".
+	Subsystems []string
+
+	// ExcludeSubsystems drops the messages of the named loggers and of their
+	// sub-loggers.
+	ExcludeSubsystems []string
+
+	// Regex, if set, restricts the stream to the messages matching it.
+	Regex *regexp.Regexp
+}
+
+// matches returns whether a message of the named logger passes the filter.
+func (f *Filter) matches(name, msg string) bool {
+	if len(f.Subsystems) > 0 && !matchesSubsystem(name, f.Subsystems) {
+		return false
+	}
+	if matchesSubsystem(name, f.ExcludeSubsystems) {
+		return false
+	}
+	if f.Regex != nil && !f.Regex.MatchString(msg) {
+		return false
+	}
+	return true
+}
+
+// matchesSubsystem returns whether the logger name is one of the subsystems
+// or one of their sub-loggers.
+func matchesSubsystem(name string, subsystems []string) bool {
+	for _, s := range subsystems {
+		if name == s || strings.HasPrefix(name, s+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// filterSink is a SinkAdapter only accepting the messages passing its filter.
+type filterSink struct {
+	log.SinkAdapter
+	filter *Filter
+}
+
+func (s *filterSink) Accept(name string, level log.Level, msg string, args ...interface{}) {
+	if s.filter.matches(name, msg) {
+		s.SinkAdapter.Accept(name, level, msg, args...)
+	}
+}
+
+// JSONMessage returns a log line in the format of the JSON logs of hclog,
+// used to frame the messages of the monitor itself in JSON streams.
+func JSONMessage(level log.Level, msg string) []byte {
+	line, _ := json.Marshal(map[string]interface{}{
+		"@level":     level.String(),
+		"@message":   msg,
+		"@module":    "monitor",
+		"@timestamp": time.Now().Format(log.TimeFormatJSON),
+	})
+	return append(line, '\n')
+}
+
+// HeartbeatMessage returns the heartbeat line sent periodically over JSON
+// streams, so that clients can tell an idle stream from a dead one.
+func HeartbeatMessage() []byte {
+	line, _ := json.Marshal(map[string]interface{}{
+		"@type":      "heartbeat",
+		"@timestamp": time.Now().Format(log.TimeFormatJSON),
+	})
+	return append(line, '\n')
+}
+
 // monitor implements the Monitor interface. Note that this
 // struct is not threadsafe.
 type monitor struct {
@@ -52,15 +129,25 @@ type monitor struct {
 	// This is to ensure that we don't start it again until
 	// it has been shut down.
 	started *atomic.Bool
+
+	// jsonFormat is whether the monitor streams JSON log lines, in which
+	// case its own messages are JSON framed too.
+	jsonFormat bool
 }
 
 // NewMonitor creates a new Monitor. Start must be called in order to actually start
 // streaming logs. buf is the buffer size of the channel that sends log messages.
 func NewMonitor(buf int, logger log.InterceptLogger, opts *log.LoggerOptions) (Monitor, error) {
-	return newMonitor(buf, logger, opts)
+	return newMonitor(buf, logger, opts, nil)
 }
 
-func newMonitor(buf int, logger log.InterceptLogger, opts *log.LoggerOptions) (*monitor, error) {
+// NewFilteredMonitor creates a new Monitor only streaming the log messages
+// passing filter.
+func NewFilteredMonitor(buf int, logger log.InterceptLogger, opts *log.LoggerOptions, filter *Filter) (Monitor, error) {
+	return newMonitor(buf, logger, opts, filter)
+}
+
+func newMonitor(buf int, logger log.InterceptLogger, opts *log.LoggerOptions, filter *Filter) (*monitor, error) {
 	if buf <= 0 {
 		return nil, fmt.Errorf("buf must be greater than zero")
 	}
@@ -73,11 +160,15 @@ func newMonitor(buf int, logger log.InterceptLogger, opts *log.LoggerOptions) (*
 		dropCheckInterval: 3 * time.Second,
 		droppedCount:      atomic.NewUint32(0),
 		started:           atomic.NewBool(false),
+		jsonFormat:        opts.JSONFormat,
 	}
 
 	opts.Output = sw
 	sink := log.NewSinkAdapter(opts)
 	sw.sink = sink
+	if filter != nil {
+		sw.sink = &filterSink{SinkAdapter: sink, filter: filter}
+	}
 
 	return sw, nil
 }
@@ -125,7 +216,12 @@ func (d *monitor) Start() <-chan []byte {
 				dc := d.droppedCount.Load()
 
 				if dc > 0 {
-					logMessage = []byte(fmt.Sprintf("Monitor dropped %d logs during monitor request\n", dc))
+					msg := fmt.Sprintf("Monitor dropped %d logs during monitor request", dc)
+					if d.jsonFormat {
+						logMessage = JSONMessage(log.Warn, msg)
+					} else {
+						logMessage = []byte(msg + "\n")
+					}
 					d.droppedCount.Swap(0)
 				}
 			case logMessage = <-d.logCh:
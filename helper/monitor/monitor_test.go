@@ -6,6 +6,7 @@ package monitor
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
 	"time"
@@ -112,7 +113,7 @@ func TestMonitor_DroppedMessages(t *testing.T) {
 
 	m, _ := newMonitor(5, logger, &log.LoggerOptions{
 		Level: log.Debug,
-	})
+	}, nil)
 	m.dropCheckInterval = 5 * time.Millisecond
 
 	logCh := m.Start()
@@ -138,3 +139,85 @@ func TestMonitor_DroppedMessages(t *testing.T) {
 		require.Fail(t, "expected to see warn dropped messages")
 	}
 }
+
+func TestMonitor_Filter(t *testing.T) {
+	t.Parallel()
+
+	logger := log.NewInterceptLogger(&log.LoggerOptions{
+		Name:  "core",
+		Level: log.Error,
+	})
+
+	m, _ := NewFilteredMonitor(512, logger, &log.LoggerOptions{
+		Level: log.Debug,
+	}, &Filter{
+		Subsystems:        []string{"core"},
+		ExcludeSubsystems: []string{"core.raft"},
+		Regex:             regexp.MustCompile("^keep"),
+	})
+
+	logCh := m.Start()
+	defer m.Stop()
+
+	logger.Debug("drop: message not matching the regex")
+	logger.Named("raft").Debug("keep: message of an excluded subsystem")
+	logger.ResetNamed("storage").Debug("keep: message of another subsystem")
+	logger.Named("expiration").Debug("keep: message of a sub-logger")
+
+	select {
+	case l := <-logCh:
+		require.Contains(t, string(l), "core.expiration: keep: message of a sub-logger")
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected to receive from log channel")
+	}
+
+	select {
+	case l := <-logCh:
+		t.Fatalf("unexpected log message: %s", l)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// Ensure dropped messages are JSON framed in JSON streams
+func TestMonitor_DroppedMessagesJSON(t *testing.T) {
+	t.Parallel()
+
+	logger := log.NewInterceptLogger(&log.LoggerOptions{
+		Level: log.Warn,
+	})
+
+	m, _ := newMonitor(5, logger, &log.LoggerOptions{
+		Level:      log.Debug,
+		JSONFormat: true,
+	}, nil)
+	m.dropCheckInterval = 5 * time.Millisecond
+
+	logCh := m.Start()
+	defer m.Stop()
+
+	for i := 0; i <= 100; i++ {
+		logger.Debug(fmt.Sprintf("test message %d", i))
+	}
+
+	passed := make(chan struct{})
+	go func() {
+		for recv := range logCh {
+			jsonLog := &struct {
+				Message string `json:"@message"`
+			}{}
+			if err := json.Unmarshal(recv, jsonLog); err != nil {
+				continue
+			}
+			if strings.HasPrefix(jsonLog.Message, "Monitor dropped") {
+				close(passed)
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-passed:
+	case <-time.After(2 * time.Second):
+		require.Fail(t, "expected to see warn dropped messages")
+	}
+}
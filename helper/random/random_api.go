@@ -56,8 +56,9 @@ func HandleRandomAPI(d *framework.FieldData, additionalSource io.Reader) (*logic
 	switch format {
 	case "hex":
 	case "base64":
+	case "base64url":
 	default:
-		return logical.ErrorResponse("unsupported encoding format %q; must be \"hex\" or \"base64\"", format), nil
+		return logical.ErrorResponse("unsupported encoding format %q; must be \"hex\", \"base64\" or \"base64url\"", format), nil
 	}
 
 	var randBytes []byte
@@ -95,6 +96,8 @@ func HandleRandomAPI(d *framework.FieldData, additionalSource io.Reader) (*logic
 		retStr = hex.EncodeToString(randBytes)
 	case "base64":
 		retStr = base64.StdEncoding.EncodeToString(randBytes)
+	case "base64url":
+		retStr = base64.RawURLEncoding.EncodeToString(randBytes)
 	}
 
 	// Generate the response
@@ -0,0 +1,76 @@
+package secretsimport
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+	"github.com/hashicorp/go-cleanhttp"
+)
+
+// awsSource imports the secrets of AWS Secrets Manager. Without explicit
+// credentials, the default credential chain of the SDK is used.
+type awsSource struct {
+	client *secretsmanager.SecretsManager
+}
+
+func newAWSSource(_ context.Context, config map[string]string) (Source, error) {
+	awsConfig := aws.NewConfig().WithHTTPClient(cleanhttp.DefaultClient())
+	if region := config["region"]; region != "" {
+		awsConfig = awsConfig.WithRegion(region)
+	}
+	if endpoint := config["endpoint"]; endpoint != "" {
+		awsConfig = awsConfig.WithEndpoint(endpoint)
+	}
+
+	accessKey, secretKey := config["access_key"], config["secret_key"]
+	switch {
+	case accessKey != "" && secretKey != "":
+		awsConfig = awsConfig.WithCredentials(credentials.NewStaticCredentials(accessKey, secretKey, config["session_token"]))
+	case accessKey != "" || secretKey != "":
+		return nil, fmt.Errorf("access_key and secret_key must be given together")
+	}
+
+	sess, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AWS session: %w", err)
+	}
+
+	return &awsSource{
+		client: secretsmanager.New(sess),
+	}, nil
+}
+
+func (s *awsSource) List(ctx context.Context) ([]string, error) {
+	var names []string
+	err := s.client.ListSecretsPagesWithContext(ctx, &secretsmanager.ListSecretsInput{}, func(page *secretsmanager.ListSecretsOutput, _ bool) bool {
+		for _, secret := range page.SecretList {
+			names = append(names, aws.StringValue(secret.Name))
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+	return names, nil
+}
+
+func (s *awsSource) Read(ctx context.Context, name string) (map[string]interface{}, error) {
+	out, err := s.client.GetSecretValueWithContext(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret %q: %w", name, err)
+	}
+
+	if out.SecretString != nil {
+		return secretData(aws.StringValue(out.SecretString)), nil
+	}
+	return map[string]interface{}{
+		"value": base64.StdEncoding.EncodeToString(out.SecretBinary),
+	}, nil
+}
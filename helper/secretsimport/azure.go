@@ -0,0 +1,120 @@
+package secretsimport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/hashicorp/go-cleanhttp"
+)
+
+const (
+	azureKeyVaultAPIVersion = "7.4"
+	azureKeyVaultScope      = "https://vault.azure.net/.default"
+)
+
+// azureSource imports the current versions of the secrets of an Azure Key
+// Vault, through its REST API. Without explicit client credentials, the
+// default Azure credential chain is used.
+type azureSource struct {
+	vaultURI   string
+	credential azcore.TokenCredential
+	client     *http.Client
+}
+
+func newAzureSource(_ context.Context, config map[string]string) (Source, error) {
+	vaultURI := strings.TrimSuffix(config["vault_uri"], "/")
+	if vaultURI == "" {
+		return nil, errors.New("vault_uri is required")
+	}
+
+	var credential azcore.TokenCredential
+	var err error
+	tenantID, clientID, clientSecret := config["tenant_id"], config["client_id"], config["client_secret"]
+	switch {
+	case tenantID != "" && clientID != "" && clientSecret != "":
+		credential, err = azidentity.NewClientSecretCredential(tenantID, clientID, clientSecret, nil)
+	case tenantID != "" || clientID != "" || clientSecret != "":
+		return nil, errors.New("tenant_id, client_id and client_secret must be given together")
+	default:
+		credential, err = azidentity.NewDefaultAzureCredential(nil)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Azure credential: %w", err)
+	}
+
+	return &azureSource{
+		vaultURI:   vaultURI,
+		credential: credential,
+		client:     cleanhttp.DefaultClient(),
+	}, nil
+}
+
+func (s *azureSource) get(ctx context.Context, reqURL string, out interface{}) error {
+	token, err := s.credential.GetToken(ctx, policy.TokenRequestOptions{
+		Scopes: []string{azureKeyVaultScope},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get Azure token: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.Token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, body)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (s *azureSource) List(ctx context.Context) ([]string, error) {
+	var names []string
+	next := fmt.Sprintf("%s/secrets?api-version=%s", s.vaultURI, azureKeyVaultAPIVersion)
+	for next != "" {
+		var page struct {
+			Value []struct {
+				ID string `json:"id"`
+			} `json:"value"`
+			NextLink string `json:"nextLink"`
+		}
+		if err := s.get(ctx, next, &page); err != nil {
+			return nil, fmt.Errorf("failed to list secrets: %w", err)
+		}
+		for _, secret := range page.Value {
+			// Secret IDs are returned as <vault_uri>/secrets/<name>.
+			names = append(names, path.Base(secret.ID))
+		}
+		next = page.NextLink
+	}
+	return names, nil
+}
+
+func (s *azureSource) Read(ctx context.Context, name string) (map[string]interface{}, error) {
+	var secret struct {
+		Value string `json:"value"`
+	}
+	reqURL := fmt.Sprintf("%s/secrets/%s?api-version=%s", s.vaultURI, url.PathEscape(name), azureKeyVaultAPIVersion)
+	if err := s.get(ctx, reqURL, &secret); err != nil {
+		return nil, fmt.Errorf("failed to read secret %q: %w", name, err)
+	}
+	return secretData(secret.Value), nil
+}
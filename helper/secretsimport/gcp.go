@@ -0,0 +1,77 @@
+package secretsimport
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"path"
+
+	"google.golang.org/api/option"
+	secretmanager "google.golang.org/api/secretmanager/v1"
+)
+
+// gcpSource imports the latest versions of the secrets of a GCP Secret
+// Manager project. Without explicit credentials, the application default
+// credentials are used.
+type gcpSource struct {
+	project string
+	service *secretmanager.Service
+}
+
+func newGCPSource(ctx context.Context, config map[string]string) (Source, error) {
+	project := config["project"]
+	if project == "" {
+		return nil, errors.New("project is required")
+	}
+
+	var opts []option.ClientOption
+	if creds := config["credentials"]; creds != "" {
+		opts = append(opts, option.WithCredentialsJSON([]byte(creds)))
+	}
+	if endpoint := config["endpoint"]; endpoint != "" {
+		opts = append(opts, option.WithEndpoint(endpoint))
+	}
+
+	service, err := secretmanager.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCP Secret Manager client: %w", err)
+	}
+
+	return &gcpSource{
+		project: project,
+		service: service,
+	}, nil
+}
+
+func (s *gcpSource) List(ctx context.Context) ([]string, error) {
+	var names []string
+	err := s.service.Projects.Secrets.List("projects/"+s.project).Pages(ctx, func(page *secretmanager.ListSecretsResponse) error {
+		for _, secret := range page.Secrets {
+			// Secret names are returned as projects/<project>/secrets/<name>.
+			names = append(names, path.Base(secret.Name))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+	return names, nil
+}
+
+func (s *gcpSource) Read(ctx context.Context, name string) (map[string]interface{}, error) {
+	version := fmt.Sprintf("projects/%s/secrets/%s/versions/latest", s.project, name)
+	resp, err := s.service.Projects.Secrets.Versions.Access(version).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret %q: %w", name, err)
+	}
+	if resp.Payload == nil {
+		return nil, fmt.Errorf("secret %q has no payload", name)
+	}
+
+	value, err := base64.StdEncoding.DecodeString(resp.Payload.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode secret %q: %w", name, err)
+	}
+	return secretData(string(value)), nil
+}
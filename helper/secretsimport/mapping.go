@@ -0,0 +1,85 @@
+package secretsimport
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Rule maps the secrets whose name matches a pattern to a path of the
+// destination mount.
+//
+// The pattern may contain a single "*" wildcard, matching any sequence of
+// characters. In the destination, "{{name}}" is replaced by the name of the
+// secret and "{{wildcard}}" by the part of the name matched by the wildcard.
+type Rule struct {
+	Match       string `json:"match" mapstructure:"match"`
+	Destination string `json:"destination" mapstructure:"destination"`
+}
+
+// DefaultRules imports every secret at a path named after it.
+var DefaultRules = []Rule{
+	{Match: "*", Destination: "{{name}}"},
+}
+
+// Validate checks the pattern and the destination of the rule.
+func (r *Rule) Validate() error {
+	if r.Match == "" {
+		return errors.New("match is required")
+	}
+	if strings.Count(r.Match, "*") > 1 {
+		return fmt.Errorf("match %q may contain at most one wildcard", r.Match)
+	}
+	if strings.Trim(r.Destination, "/") == "" {
+		return errors.New("destination is required")
+	}
+	if strings.Contains(r.Destination, "{{wildcard}}") && !strings.Contains(r.Match, "*") {
+		return fmt.Errorf("destination %q uses {{wildcard}} but match %q has no wildcard", r.Destination, r.Match)
+	}
+	return nil
+}
+
+// apply returns the destination of the secret of the given name, if it matches
+// the rule.
+func (r *Rule) apply(name string) (string, bool) {
+	wildcard := ""
+	prefix, suffix, hasWildcard := strings.Cut(r.Match, "*")
+	switch {
+	case !hasWildcard:
+		if name != r.Match {
+			return "", false
+		}
+	case len(name) >= len(prefix)+len(suffix) && strings.HasPrefix(name, prefix) && strings.HasSuffix(name, suffix):
+		wildcard = name[len(prefix) : len(name)-len(suffix)]
+	default:
+		return "", false
+	}
+
+	dest := strings.ReplaceAll(r.Destination, "{{name}}", name)
+	dest = strings.ReplaceAll(dest, "{{wildcard}}", wildcard)
+	return dest, true
+}
+
+// MapSecret returns the path of the destination mount the secret of the given
+// name is imported at, according to the first matching rule. It returns false
+// if no rule matches.
+func MapSecret(rules []Rule, name string) (string, bool, error) {
+	for _, rule := range rules {
+		dest, ok := rule.apply(name)
+		if !ok {
+			continue
+		}
+
+		dest = strings.Trim(dest, "/")
+		if dest == "" {
+			return "", true, fmt.Errorf("secret %q is mapped to an empty path", name)
+		}
+		for _, segment := range strings.Split(dest, "/") {
+			if segment == "" || segment == "." || segment == ".." {
+				return "", true, fmt.Errorf("secret %q is mapped to invalid path %q", name, dest)
+			}
+		}
+		return dest, true, nil
+	}
+	return "", false, nil
+}
@@ -0,0 +1,70 @@
+package secretsimport
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRule_Validate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		rule  Rule
+		valid bool
+	}{
+		"exact":              {Rule{Match: "db-password", Destination: "db/password"}, true},
+		"wildcard":           {Rule{Match: "prod/*", Destination: "prod/{{wildcard}}"}, true},
+		"no match":           {Rule{Destination: "db"}, false},
+		"no destination":     {Rule{Match: "*", Destination: "/"}, false},
+		"two wildcards":      {Rule{Match: "*/*", Destination: "{{name}}"}, false},
+		"wildcard undefined": {Rule{Match: "db", Destination: "{{wildcard}}"}, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := tc.rule.Validate()
+			if tc.valid {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestMapSecret(t *testing.T) {
+	rules := []Rule{
+		{Match: "app-*-db", Destination: "apps/{{wildcard}}/database"},
+		{Match: "prod/*", Destination: "production/{{wildcard}}"},
+		{Match: "legacy", Destination: "archive/{{name}}"},
+		{Match: "bad-*", Destination: "bad/{{wildcard}}"},
+	}
+
+	for name, tc := range map[string]struct {
+		secret  string
+		dest    string
+		matched bool
+		err     bool
+	}{
+		"wildcard in the middle": {secret: "app-billing-db", dest: "apps/billing/database", matched: true},
+		"wildcard suffix":        {secret: "prod/api/key", dest: "production/api/key", matched: true},
+		"exact":                  {secret: "legacy", dest: "archive/legacy", matched: true},
+		"no match":               {secret: "staging/api"},
+		"empty segment":          {secret: "bad-/x", matched: true, err: true},
+		"parent segment":         {secret: "bad-..", matched: true, err: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			dest, matched, err := MapSecret(rules, tc.secret)
+			require.Equal(t, tc.matched, matched)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.dest, dest)
+		})
+	}
+}
+
+func TestSecretData(t *testing.T) {
+	require.Equal(t, map[string]interface{}{"user": "admin", "port": float64(5432)}, secretData(`{"user":"admin","port":5432}`))
+	require.Equal(t, map[string]interface{}{"value": "hunter2"}, secretData("hunter2"))
+	require.Equal(t, map[string]interface{}{"value": `["a"]`}, secretData(`["a"]`))
+}
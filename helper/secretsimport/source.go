@@ -0,0 +1,74 @@
+// Package secretsimport implements the clients of the external secret stores
+// secrets can be imported from, and the rules mapping their secrets to paths
+// of a KV version 2 mount.
+package secretsimport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	SourceAWSSecretsManager = "aws-secrets-manager"
+	SourceAzureKeyVault     = "azure-key-vault"
+	SourceGCPSecretManager  = "gcp-secret-manager"
+	SourceVault             = "vault"
+)
+
+// Source is an external secret store.
+type Source interface {
+	// List returns the names of all the secrets of the store.
+	List(ctx context.Context) ([]string, error)
+
+	// Read returns the data of the secret of the given name, in the form
+	// written to a KV version 2 mount. It returns nil data if the secret has
+	// no current value, such as a deleted KV version 2 secret.
+	Read(ctx context.Context, name string) (map[string]interface{}, error)
+}
+
+type sourceFactory func(ctx context.Context, config map[string]string) (Source, error)
+
+var sourceFactories = map[string]sourceFactory{
+	SourceAWSSecretsManager: newAWSSource,
+	SourceAzureKeyVault:     newAzureSource,
+	SourceGCPSecretManager:  newGCPSource,
+	SourceVault:             newVaultSource,
+}
+
+// SourceTypes returns the supported types of sources, sorted.
+func SourceTypes() []string {
+	types := make([]string, 0, len(sourceFactories))
+	for t := range sourceFactories {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// NewSource returns a client for the source of the given type.
+func NewSource(ctx context.Context, sourceType string, config map[string]string) (Source, error) {
+	factory, ok := sourceFactories[sourceType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported source %q, must be one of %s", sourceType, strings.Join(SourceTypes(), ", "))
+	}
+	if config == nil {
+		config = map[string]string{}
+	}
+	return factory(ctx, config)
+}
+
+// secretData converts the value of a secret to the data of a KV secret. JSON
+// objects are imported as is, and other values are stored under the "value"
+// key.
+func secretData(value string) map[string]interface{} {
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &data); err == nil && data != nil {
+		return data
+	}
+	return map[string]interface{}{
+		"value": value,
+	}
+}
@@ -0,0 +1,143 @@
+package secretsimport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/openbao/openbao/api/v2"
+)
+
+// vaultSource imports the secrets of a KV mount of another OpenBao or Vault
+// cluster, recursively from an optional path of the mount.
+type vaultSource struct {
+	client    *api.Client
+	mount     string
+	path      string
+	kvVersion int
+}
+
+func newVaultSource(_ context.Context, config map[string]string) (Source, error) {
+	mount := strings.Trim(config["mount"], "/")
+	if mount == "" {
+		return nil, errors.New("mount is required")
+	}
+
+	kvVersion := 2
+	if raw := config["kv_version"]; raw != "" {
+		var err error
+		kvVersion, err = strconv.Atoi(raw)
+		if err != nil || (kvVersion != 1 && kvVersion != 2) {
+			return nil, fmt.Errorf("kv_version must be 1 or 2")
+		}
+	}
+
+	clientConfig := api.DefaultConfig()
+	if clientConfig.Error != nil {
+		return nil, clientConfig.Error
+	}
+	if address := config["address"]; address != "" {
+		clientConfig.Address = address
+	}
+
+	tlsConfig := &api.TLSConfig{
+		CACertBytes:   []byte(config["ca_cert"]),
+		TLSServerName: config["tls_server_name"],
+	}
+	if raw := config["tls_skip_verify"]; raw != "" {
+		skipVerify, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tls_skip_verify: %w", err)
+		}
+		tlsConfig.Insecure = skipVerify
+	}
+	if err := clientConfig.ConfigureTLS(tlsConfig); err != nil {
+		return nil, fmt.Errorf("failed to configure TLS: %w", err)
+	}
+
+	client, err := api.NewClient(clientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	if token := config["token"]; token != "" {
+		client.SetToken(token)
+	}
+	if ns := config["namespace"]; ns != "" {
+		client.SetNamespace(ns)
+	}
+
+	return &vaultSource{
+		client:    client,
+		mount:     mount,
+		path:      strings.Trim(config["path"], "/"),
+		kvVersion: kvVersion,
+	}, nil
+}
+
+// fullPath returns the path of the secret of the given name relative to the
+// mount.
+func (s *vaultSource) fullPath(name string) string {
+	if s.path == "" {
+		return name
+	}
+	return s.path + "/" + name
+}
+
+func (s *vaultSource) List(ctx context.Context) ([]string, error) {
+	listPrefix := s.mount + "/"
+	if s.kvVersion == 2 {
+		listPrefix += "metadata/"
+	}
+
+	var names []string
+	dirs := []string{""}
+	for len(dirs) > 0 {
+		dir := dirs[0]
+		dirs = dirs[1:]
+
+		secret, err := s.client.Logical().ListWithContext(ctx, listPrefix+s.fullPath(dir))
+		if err != nil {
+			return nil, fmt.Errorf("failed to list secrets: %w", err)
+		}
+		if secret == nil || secret.Data == nil {
+			continue
+		}
+		keys, _ := secret.Data["keys"].([]interface{})
+		for _, rawKey := range keys {
+			key, ok := rawKey.(string)
+			if !ok {
+				continue
+			}
+			if strings.HasSuffix(key, "/") {
+				dirs = append(dirs, dir+key)
+				continue
+			}
+			names = append(names, dir+key)
+		}
+	}
+	return names, nil
+}
+
+func (s *vaultSource) Read(ctx context.Context, name string) (map[string]interface{}, error) {
+	if s.kvVersion == 1 {
+		secret, err := s.client.KVv1(s.mount).Get(ctx, s.fullPath(name))
+		if err != nil {
+			if errors.Is(err, api.ErrSecretNotFound) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("failed to read secret %q: %w", name, err)
+		}
+		return secret.Data, nil
+	}
+
+	secret, err := s.client.KVv2(s.mount).Get(ctx, s.fullPath(name))
+	if err != nil {
+		if errors.Is(err, api.ErrSecretNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read secret %q: %w", name, err)
+	}
+	return secret.Data, nil
+}
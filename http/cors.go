@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/hashicorp/go-secure-stdlib/strutil"
+	"github.com/openbao/openbao/internalshared/configutil"
 	"github.com/openbao/openbao/vault"
 )
 
@@ -21,9 +22,32 @@ var allowedMethods = []string{
 	"LIST", // LIST is not an official HTTP method, but Vault supports it.
 }
 
-func wrapCORSHandler(h http.Handler, core *vault.Core) http.Handler {
+// listenerCORSConfig returns the CORS configuration of the given listener, or
+// nil if CORS is not enabled on it.
+func listenerCORSConfig(l *configutil.Listener) *vault.CORSConfig {
+	if l == nil || !l.CorsEnabled {
+		return nil
+	}
+
+	enabled := vault.CORSEnabled
+	return &vault.CORSConfig{
+		Enabled:        &enabled,
+		AllowedOrigins: l.CorsAllowedOrigins,
+		AllowedHeaders: append(append([]string{}, vault.StdAllowedHeaders...), l.CorsAllowedHeaders...),
+	}
+}
+
+func wrapCORSHandler(h http.Handler, props *vault.HandlerProperties) http.Handler {
+	core := props.Core
+	listenerConf := listenerCORSConfig(props.ListenerConfig)
+
 	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
-		corsConf := core.CORSConfig()
+		// A listener-level CORS configuration takes precedence over the
+		// cluster-wide one managed through sys/config/cors.
+		corsConf := listenerConf
+		if corsConf == nil {
+			corsConf = core.CORSConfig()
+		}
 
 		// If CORS is not enabled or if no Origin header is present (i.e. the request
 		// is from the Vault CLI. A browser will always send an Origin header), then
@@ -0,0 +1,301 @@
+// Package grpcapi exposes a subset of the HTTP API over gRPC.
+//
+// Requests received over gRPC are translated into their HTTP equivalents and
+// dispatched through the regular HTTP handler, so authentication, request
+// forwarding, quotas, auditing and response wrapping all behave exactly as
+// they do for HTTP clients. The benefit for callers is a single long-lived
+// HTTP/2 connection and, with the Stream RPC, no per-request round trip
+// setup.
+package grpcapi
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/openbao/openbao/helper/forwarding"
+	"github.com/openbao/openbao/sdk/v2/helper/consts"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	OperationRead   = "read"
+	OperationWrite  = "write"
+	OperationList   = "list"
+	OperationDelete = "delete"
+	OperationPatch  = "patch"
+)
+
+// NewServer returns a gRPC server that serves the API service by dispatching
+// every call to the given HTTP handler.
+func NewServer(handler http.Handler, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append([]grpc.ServerOption{grpc.Creds(tlsPassthroughCreds{})}, opts...)
+	s := grpc.NewServer(opts...)
+	RegisterAPIServer(s, &apiServer{handler: handler})
+	return s
+}
+
+type apiServer struct {
+	UnimplementedAPIServer
+
+	handler http.Handler
+}
+
+var _ APIServer = (*apiServer)(nil)
+
+func (s *apiServer) Read(ctx context.Context, req *Request) (*Response, error) {
+	return s.do(ctx, OperationRead, req)
+}
+
+func (s *apiServer) Write(ctx context.Context, req *Request) (*Response, error) {
+	return s.do(ctx, OperationWrite, req)
+}
+
+func (s *apiServer) List(ctx context.Context, req *Request) (*Response, error) {
+	return s.do(ctx, OperationList, req)
+}
+
+func (s *apiServer) Delete(ctx context.Context, req *Request) (*Response, error) {
+	return s.do(ctx, OperationDelete, req)
+}
+
+func (s *apiServer) Login(ctx context.Context, req *Request) (*Response, error) {
+	if !strings.HasPrefix(strings.TrimPrefix(req.Path, "/"), "auth/") {
+		return nil, status.Errorf(codes.InvalidArgument, "login path must be under auth/, got %q", req.Path)
+	}
+
+	// Login requests are unauthenticated; never forward a token supplied in
+	// the call metadata or the request headers.
+	ctx = metadata.NewIncomingContext(ctx, stripToken(ctx))
+	return s.do(ctx, OperationWrite, &Request{
+		Id:        req.Id,
+		Path:      req.Path,
+		Data:      req.Data,
+		Namespace: req.Namespace,
+		Headers:   stripTokenHeaders(req.Headers),
+	})
+}
+
+func (s *apiServer) Health(ctx context.Context, req *HealthRequest) (*Response, error) {
+	return s.do(ctx, OperationRead, &Request{
+		Path:  "sys/health",
+		Query: req.Query,
+	})
+}
+
+func (s *apiServer) Stream(stream API_StreamServer) error {
+	ctx := stream.Context()
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		resp, err := s.do(ctx, req.Operation, req)
+		if err != nil {
+			st, _ := status.FromError(err)
+			resp = &Response{
+				Id:         req.Id,
+				StatusCode: uint32(httpStatusFromCode(st.Code())),
+				Body:       []byte(fmt.Sprintf(`{"errors":[%q]}`, st.Message())),
+			}
+		}
+
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+// do translates req into an HTTP request, runs it through the HTTP handler
+// and translates the result back.
+func (s *apiServer) do(ctx context.Context, op string, req *Request) (*Response, error) {
+	httpReq, err := buildHTTPRequest(ctx, op, req)
+	if err != nil {
+		return nil, err
+	}
+
+	w := forwarding.NewRPCResponseWriter()
+	s.handler.ServeHTTP(w, httpReq)
+
+	resp := &Response{
+		Id:         req.Id,
+		StatusCode: uint32(w.StatusCode()),
+		Body:       w.Body().Bytes(),
+		Headers:    make(map[string]*forwarding.HeaderEntry, len(w.Header())),
+	}
+	for k, v := range w.Header() {
+		resp.Headers[k] = &forwarding.HeaderEntry{Values: v}
+	}
+
+	return resp, nil
+}
+
+func buildHTTPRequest(ctx context.Context, op string, req *Request) (*http.Request, error) {
+	if req == nil {
+		return nil, status.Error(codes.InvalidArgument, "missing request")
+	}
+
+	path := strings.TrimPrefix(req.Path, "/")
+	if path == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing path")
+	}
+
+	query := url.Values{}
+	for k, v := range req.Query {
+		query[k] = v.Values
+	}
+
+	var method string
+	switch strings.ToLower(op) {
+	case OperationRead:
+		method = http.MethodGet
+	case OperationWrite:
+		method = http.MethodPost
+	case OperationList:
+		method = http.MethodGet
+		query.Set("list", "true")
+	case OperationDelete:
+		method = http.MethodDelete
+	case OperationPatch:
+		method = http.MethodPatch
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported operation %q", op)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, "/v1/"+path, bytes.NewReader(req.Data))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	httpReq.URL.RawQuery = query.Encode()
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for k, v := range md {
+			// Pseudo-headers and gRPC transport headers are not meaningful
+			// to the HTTP handler.
+			if strings.HasPrefix(k, ":") || strings.HasPrefix(k, "grpc-") {
+				continue
+			}
+			for _, val := range v {
+				httpReq.Header.Add(k, val)
+			}
+		}
+	}
+	for k, v := range req.Headers {
+		httpReq.Header.Del(k)
+		for _, val := range v.Values {
+			httpReq.Header.Add(k, val)
+		}
+	}
+
+	if req.Token != "" {
+		httpReq.Header.Set(consts.AuthHeaderName, req.Token)
+	}
+	if req.Namespace != "" {
+		httpReq.Header.Set(consts.NamespaceHeaderName, req.Namespace)
+	}
+	if method == http.MethodPatch && httpReq.Header.Get("Content-Type") == "" {
+		httpReq.Header.Set("Content-Type", "application/merge-patch+json")
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		if p.Addr != nil {
+			httpReq.RemoteAddr = p.Addr.String()
+		}
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			state := info.State
+			httpReq.TLS = &state
+		}
+	}
+
+	return httpReq, nil
+}
+
+func stripToken(ctx context.Context) metadata.MD {
+	md, _ := metadata.FromIncomingContext(ctx)
+	md = md.Copy()
+	md.Delete(consts.AuthHeaderName)
+	md.Delete("authorization")
+	return md
+}
+
+func stripTokenHeaders(headers map[string]*forwarding.HeaderEntry) map[string]*forwarding.HeaderEntry {
+	stripped := make(map[string]*forwarding.HeaderEntry, len(headers))
+	for k, v := range headers {
+		switch http.CanonicalHeaderKey(k) {
+		case http.CanonicalHeaderKey(consts.AuthHeaderName), "Authorization":
+			continue
+		}
+		stripped[k] = v
+	}
+	return stripped
+}
+
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// tlsPassthroughCreds exposes the TLS connection state of connections that
+// were already wrapped in TLS by the listener, so that TLS certificate auth
+// keeps working over gRPC. Plaintext connections are passed through as-is.
+type tlsPassthroughCreds struct{}
+
+var _ credentials.TransportCredentials = tlsPassthroughCreds{}
+
+func (tlsPassthroughCreds) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	tc, ok := conn.(*tls.Conn)
+	if !ok {
+		return conn, nil, nil
+	}
+	if err := tc.Handshake(); err != nil {
+		return nil, nil, err
+	}
+	return conn, credentials.TLSInfo{
+		State: tc.ConnectionState(),
+		CommonAuthInfo: credentials.CommonAuthInfo{
+			SecurityLevel: credentials.PrivacyAndIntegrity,
+		},
+	}, nil
+}
+
+func (tlsPassthroughCreds) ClientHandshake(context.Context, string, net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return nil, nil, errors.New("tlsPassthroughCreds cannot be used by clients")
+}
+
+func (tlsPassthroughCreds) Info() credentials.ProtocolInfo {
+	return credentials.ProtocolInfo{SecurityProtocol: "tls"}
+}
+
+func (c tlsPassthroughCreds) Clone() credentials.TransportCredentials {
+	return c
+}
+
+func (tlsPassthroughCreds) OverrideServerName(string) error {
+	return nil
+}
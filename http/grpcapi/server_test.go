@@ -0,0 +1,148 @@
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/openbao/openbao/helper/forwarding"
+	vaulthttp "github.com/openbao/openbao/http"
+	"github.com/openbao/openbao/vault"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+func testClient(t *testing.T) (APIClient, string) {
+	t.Helper()
+
+	core, _, token := vault.TestCoreUnsealed(t)
+	handler := vaulthttp.Handler.Handler(&vault.HandlerProperties{Core: core})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	srv := NewServer(handler)
+	go srv.Serve(ln)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.Dial(ln.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return NewAPIClient(conn), token
+}
+
+func TestGRPCAPI_Health(t *testing.T) {
+	client, _ := testClient(t)
+
+	resp, err := client.Health(context.Background(), &HealthRequest{})
+	require.NoError(t, err)
+	require.Equal(t, uint32(200), resp.StatusCode)
+
+	var health map[string]interface{}
+	require.NoError(t, json.Unmarshal(resp.Body, &health))
+	require.Equal(t, true, health["initialized"])
+	require.Equal(t, false, health["sealed"])
+}
+
+func TestGRPCAPI_CRUD(t *testing.T) {
+	client, token := testClient(t)
+	ctx := context.Background()
+
+	resp, err := client.Write(ctx, &Request{Path: "secret/foo", Token: token, Data: []byte(`{"bar":"baz"}`)})
+	require.NoError(t, err)
+	require.Equal(t, uint32(204), resp.StatusCode)
+
+	resp, err = client.Read(ctx, &Request{Path: "secret/foo", Token: token})
+	require.NoError(t, err)
+	require.Equal(t, uint32(200), resp.StatusCode)
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body, &secret))
+	require.Equal(t, "baz", secret.Data["bar"])
+
+	// The token may also be supplied through call metadata.
+	mdCtx := metadata.AppendToOutgoingContext(ctx, "X-Vault-Token", token)
+	resp, err = client.List(mdCtx, &Request{Path: "secret/"})
+	require.NoError(t, err)
+	require.Equal(t, uint32(200), resp.StatusCode)
+	require.Contains(t, string(resp.Body), "foo")
+
+	resp, err = client.Delete(ctx, &Request{Path: "secret/foo", Token: token})
+	require.NoError(t, err)
+	require.Equal(t, uint32(204), resp.StatusCode)
+
+	resp, err = client.Read(ctx, &Request{Path: "secret/foo", Token: token})
+	require.NoError(t, err)
+	require.Equal(t, uint32(404), resp.StatusCode)
+
+	// Requests without a token are rejected by the core.
+	resp, err = client.Read(ctx, &Request{Path: "secret/foo"})
+	require.NoError(t, err)
+	require.Equal(t, uint32(403), resp.StatusCode)
+}
+
+func TestGRPCAPI_Login(t *testing.T) {
+	client, token := testClient(t)
+	ctx := context.Background()
+
+	_, err := client.Login(ctx, &Request{Path: "secret/foo"})
+	require.Error(t, err)
+
+	// Tokens are stripped from login requests, whether supplied in the call
+	// metadata or in the request headers.
+	mdCtx := metadata.AppendToOutgoingContext(ctx, "X-Vault-Token", token)
+	resp, err := client.Login(mdCtx, &Request{Path: "auth/token/lookup-self"})
+	require.NoError(t, err)
+	require.NotEqual(t, uint32(200), resp.StatusCode)
+
+	for _, header := range []string{"X-Vault-Token", "x-vault-token", "Authorization"} {
+		value := token
+		if header == "Authorization" {
+			value = "Bearer " + token
+		}
+		resp, err = client.Login(ctx, &Request{
+			Path:    "auth/token/lookup-self",
+			Headers: map[string]*forwarding.HeaderEntry{header: {Values: []string{value}}},
+		})
+		require.NoError(t, err)
+		require.NotEqual(t, uint32(200), resp.StatusCode, "token accepted from the %s header", header)
+	}
+
+	// The same headers authenticate other requests.
+	resp, err = client.Read(ctx, &Request{
+		Path:    "auth/token/lookup-self",
+		Headers: map[string]*forwarding.HeaderEntry{"X-Vault-Token": {Values: []string{token}}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint32(200), resp.StatusCode)
+}
+
+func TestGRPCAPI_Stream(t *testing.T) {
+	client, token := testClient(t)
+
+	stream, err := client.Stream(context.Background())
+	require.NoError(t, err)
+
+	reqs := []*Request{
+		{Id: "1", Operation: OperationWrite, Path: "secret/a", Token: token, Data: []byte(`{"v":"1"}`)},
+		{Id: "2", Operation: OperationRead, Path: "secret/a", Token: token},
+		{Id: "3", Operation: "bogus", Path: "secret/a", Token: token},
+	}
+	for _, req := range reqs {
+		require.NoError(t, stream.Send(req))
+	}
+	require.NoError(t, stream.CloseSend())
+
+	expected := []uint32{204, 200, 400}
+	for i, code := range expected {
+		resp, err := stream.Recv()
+		require.NoError(t, err)
+		require.Equal(t, reqs[i].Id, resp.Id)
+		require.Equal(t, code, resp.StatusCode)
+	}
+}
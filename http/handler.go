@@ -50,6 +50,10 @@ const (
 	// not to use request forwarding
 	NoRequestForwardingHeaderName = "X-Vault-No-Request-Forwarding"
 
+	// ActiveSNIHeaderName is the name of the header publishing the TLS
+	// server name which load balancers route to the active node.
+	ActiveSNIHeaderName = "X-Vault-Active-SNI"
+
 	// MFAHeaderName represents the HTTP header which carries the credentials
 	// required to perform MFA on any path.
 	MFAHeaderName = "X-Vault-MFA"
@@ -62,6 +66,27 @@ const (
 	// soft-mandatory Sentinel policies.
 	PolicyOverrideHeaderName = "X-Vault-Policy-Override"
 
+	// IdempotencyKeyHeaderName is the header set by clients retrying write
+	// requests, so that requests already handled are not handled again.
+	IdempotencyKeyHeaderName = "X-OpenBao-Idempotency-Key"
+
+	// DeleteConfirmationHeaderName is the header set by clients confirming a
+	// destructive operation on a path protected against deletion.
+	DeleteConfirmationHeaderName = "X-OpenBao-Confirm-Delete"
+
+	// DryRunHeaderName is the header set by clients wanting a write request
+	// to be validated without being persisted.
+	DryRunHeaderName = "X-OpenBao-Dry-Run"
+
+	// AcknowledgeBannersHeaderName is the header set by clients logging in
+	// with the names of the login banners the user acknowledged.
+	AcknowledgeBannersHeaderName = "X-OpenBao-Acknowledge-Banners"
+
+	// CorrelationIDHeaderName is the header carrying the correlation ID of a
+	// request, generated when not set by the client and returned in the
+	// response.
+	CorrelationIDHeaderName = "X-OpenBao-Correlation-Id"
+
 	// DefaultMaxRequestSize is the default maximum accepted request size. This
 	// is to prevent a denial of service attack where no Content-Length is
 	// provided and the server is fed ever more data until it exhausts memory.
@@ -107,6 +132,7 @@ func init() {
 	alwaysRedirectPaths.AddPaths([]string{
 		"sys/storage/raft/snapshot",
 		"sys/storage/raft/snapshot-force",
+		"sys/storage/raft/snapshot-load",
 	})
 }
 
@@ -135,6 +161,8 @@ func handler(props *vault.HandlerProperties) http.Handler {
 	mux := http.NewServeMux()
 
 	switch {
+	case props.ListenerConfig != nil && props.ListenerConfig.Role == "pki_public":
+		mux.Handle("/v1/", handleRequestForwarding(core, handleLogical(core)))
 	case props.RecoveryMode:
 		raw := vault.NewRawBackend(core)
 		strategy := vault.GenerateRecoveryTokenStrategy(props.RecoveryToken)
@@ -148,6 +176,7 @@ func handler(props *vault.HandlerProperties) http.Handler {
 
 		mux.Handle("/v1/sys/init", handleSysInit(core))
 		mux.Handle("/v1/sys/seal-status", handleSysSealStatus(core))
+		mux.Handle("/v1/sys/post-unseal-status", handleSysPostUnsealStatus(core))
 		mux.Handle("/v1/sys/seal", handleSysSeal(core))
 		mux.Handle("/v1/sys/step-down", handleRequestForwarding(core, handleSysStepDown(core)))
 		mux.Handle("/v1/sys/unseal", handleSysUnseal(core))
@@ -173,12 +202,13 @@ func handler(props *vault.HandlerProperties) http.Handler {
 		}
 		mux.Handle("/v1/sys/", handleRequestForwarding(core, handleLogical(core)))
 		mux.Handle("/v1/", handleRequestForwarding(core, handleLogical(core)))
+		mux.Handle("/.well-known/", handleWellKnownRedirect(core))
 		if core.UIEnabled() {
 			if uiBuiltIn {
-				mux.Handle("/ui/", http.StripPrefix("/ui/", gziphandler.GzipHandler(handleUIHeaders(core, handleUI(http.FileServer(&UIAssetWrapper{FileSystem: assetFS()}))))))
+				mux.Handle("/ui/", http.StripPrefix("/ui/", gziphandler.GzipHandler(handleUIAssets(core, handleUIHeaders(core, handleUI(http.FileServer(&UIAssetWrapper{FileSystem: assetFS()})))))))
 				mux.Handle("/robots.txt", gziphandler.GzipHandler(handleUIHeaders(core, handleUI(http.FileServer(&UIAssetWrapper{FileSystem: assetFS()})))))
 			} else {
-				mux.Handle("/ui/", handleUIHeaders(core, handleUIStub()))
+				mux.Handle("/ui/", http.StripPrefix("/ui/", gziphandler.GzipHandler(handleUIAssets(core, handleUIHeaders(core, handleUIStub())))))
 			}
 			mux.Handle("/ui", handleUIRedirect())
 			mux.Handle("/", handleUIRedirect())
@@ -215,10 +245,15 @@ func handler(props *vault.HandlerProperties) http.Handler {
 
 	// Wrap the handler in another handler to trigger all help paths.
 	helpWrappedHandler := wrapHelpHandler(mux, core)
-	corsWrappedHandler := wrapCORSHandler(helpWrappedHandler, core)
+	if props.ListenerConfig != nil && props.ListenerConfig.Role == "pki_public" {
+		helpWrappedHandler = wrapPublicPKIHandler(core, helpWrappedHandler)
+	}
+	corsWrappedHandler := wrapCORSHandler(helpWrappedHandler, props)
 	quotaWrappedHandler := rateLimitQuotaWrapping(corsWrappedHandler, core)
-	genericWrappedHandler := genericWrapping(core, quotaWrappedHandler, props)
-	wrappedHandler := wrapMaxRequestSizeHandler(genericWrappedHandler, props)
+	activeSNIWrappedHandler := wrapActiveSNIHandler(quotaWrappedHandler, props)
+	headerPolicyWrappedHandler := wrapRequestHeaderPolicyHandler(activeSNIWrappedHandler, props)
+	genericWrappedHandler := genericWrapping(core, headerPolicyWrappedHandler, props)
+	wrappedHandler := wrapMaxRequestSizeHandler(wrapMaxResponseSizeHandler(genericWrappedHandler, props), props)
 
 	// Wrap the handler with PrintablePathCheckHandler to check for non-printable
 	// characters in the request path.
@@ -375,6 +410,17 @@ func wrapGenericHandler(core *vault.Core, h http.Handler, props *vault.HandlerPr
 			return
 		}
 
+		// Honor the correlation ID given by the client, or generate one. It is
+		// kept in the request headers so that it follows forwarded requests.
+		correlationID, err := requestCorrelationID(r)
+		if err != nil {
+			respondError(nw, http.StatusInternalServerError, err)
+			cancelFunc()
+			return
+		}
+		r.Header.Set(CorrelationIDHeaderName, correlationID)
+		nw.Header().Set(CorrelationIDHeaderName, correlationID)
+
 		// The uuid for the request is going to be generated when a logical
 		// request is generated. But, here we generate one to be able to track
 		// in-flight requests, and use that to update the req data with clientID
@@ -406,6 +452,7 @@ func wrapGenericHandler(core *vault.Core, h http.Handler, props *vault.HandlerPr
 				ReqPath:          r.URL.Path,
 				ClientRemoteAddr: clientAddr,
 				Method:           requestMethod,
+				CorrelationID:    correlationID,
 			})
 		defer func() {
 			// Not expecting this fail, so skipping the assertion check
@@ -425,6 +472,98 @@ func wrapGenericHandler(core *vault.Core, h http.Handler, props *vault.HandlerPr
 	})
 }
 
+// correlationIDRe matches the correlation IDs given by clients which are
+// honored.
+var correlationIDRe = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// requestCorrelationID returns the correlation ID given by the client of the
+// request if it is valid, or a new one.
+func requestCorrelationID(r *http.Request) (string, error) {
+	if id := r.Header.Get(CorrelationIDHeaderName); correlationIDRe.MatchString(id) {
+		return id, nil
+	}
+
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate a correlation ID for the request: %w", err)
+	}
+	return id, nil
+}
+
+// wrapPublicPKIHandler only lets through the reads of the public endpoints of
+// PKI mounts, for listeners publishing revocation data and issuer
+// certificates without exposing the rest of the API. Tokens are stripped so
+// that they are never accepted on such listeners. Requests in namespaces
+// other than the root one are refused, so that the mount checked is the one
+// serving the request, including once forwarded to the active node.
+func wrapPublicPKIHandler(core *vault.Core, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ns, err := namespace.FromContext(r.Context())
+		if err != nil || ns.ID != namespace.RootNamespaceID || r.Header.Get(consts.NamespaceHeaderName) != "" {
+			respondError(w, http.StatusBadRequest, errors.New("namespaces are not supported on this listener"))
+			return
+		}
+
+		path := strings.TrimPrefix(r.URL.Path, "/v1/")
+		if !core.IsPublicPKIPath(r.Context(), r.Method, path) {
+			respondError(w, http.StatusNotFound, nil)
+			return
+		}
+
+		r.Header.Del(consts.AuthHeaderName)
+		r.Header.Del("Authorization")
+		h.ServeHTTP(w, r)
+	})
+}
+
+// wrapRequestHeaderPolicyHandler enforces the request header policies of the
+// listener: requests missing any of the required headers are rejected, and
+// the removed headers are stripped before the request is processed further.
+func wrapRequestHeaderPolicyHandler(h http.Handler, props *vault.HandlerProperties) http.Handler {
+	l := props.ListenerConfig
+	if l == nil || (len(l.RequiredRequestHeaders) == 0 && len(l.RemovedRequestHeaders) == 0) {
+		return h
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, header := range l.RemovedRequestHeaders {
+			r.Header.Del(header)
+		}
+
+		// CORS preflight requests cannot carry custom headers.
+		isPreflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		if !isPreflight {
+			for _, header := range l.RequiredRequestHeaders {
+				if r.Header.Get(header) == "" {
+					respondError(w, http.StatusPreconditionFailed, fmt.Errorf("missing required header %q", header))
+					return
+				}
+			}
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// wrapActiveSNIHandler publishes the TLS server name which load balancers
+// route to the active node, and makes standbys redirect the requests made to
+// it to the active node instead of forwarding them.
+func wrapActiveSNIHandler(h http.Handler, props *vault.HandlerProperties) http.Handler {
+	l := props.ListenerConfig
+	if l == nil || l.ActiveSNIName == "" {
+		return h
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(ActiveSNIHeaderName, l.ActiveSNIName)
+		if r.TLS != nil && strings.EqualFold(r.TLS.ServerName, l.ActiveSNIName) {
+			r.Header.Set(NoRequestForwardingHeaderName, "true")
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
 func WrapForwardedForHandler(h http.Handler, l *configutil.Listener) http.Handler {
 	rejectNotPresent := l.XForwardedForRejectNotPresent
 	hopSkips := l.XForwardedForHopSkips
@@ -952,6 +1091,45 @@ func requestAuth(r *http.Request, req *logical.Request) {
 	}
 }
 
+func requestIdempotencyKey(r *http.Request, req *logical.Request) error {
+	key := r.Header.Get(IdempotencyKeyHeaderName)
+	if len(key) > vault.MaxIdempotencyKeyLength {
+		return fmt.Errorf("idempotency key must be at most %d characters", vault.MaxIdempotencyKeyLength)
+	}
+
+	req.IdempotencyKey = key
+	return nil
+}
+
+func requestDeleteConfirmation(r *http.Request, req *logical.Request) {
+	req.DeleteConfirmation = r.Header.Get(DeleteConfirmationHeaderName)
+}
+
+func requestAcknowledgedBanners(r *http.Request, req *logical.Request) {
+	for _, value := range r.Header.Values(AcknowledgeBannersHeaderName) {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				req.AcknowledgedBanners = append(req.AcknowledgedBanners, name)
+			}
+		}
+	}
+}
+
+func requestDryRun(r *http.Request, req *logical.Request) error {
+	raw := r.Header.Get(DryRunHeaderName)
+	if raw == "" {
+		return nil
+	}
+
+	dryRun, err := parseutil.ParseBool(raw)
+	if err != nil {
+		return err
+	}
+
+	req.DryRun = dryRun
+	return nil
+}
+
 func requestPolicyOverride(r *http.Request, req *logical.Request) error {
 	raw := r.Header.Get(PolicyOverrideHeaderName)
 	if raw == "" {
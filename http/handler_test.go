@@ -16,11 +16,14 @@ import (
 	"net/url"
 	"reflect"
 	"runtime"
+	"strconv"
 	"strings"
 	"testing"
 
 	"github.com/go-test/deep"
 	"github.com/hashicorp/go-cleanhttp"
+	"github.com/openbao/openbao/api/v2"
+	"github.com/openbao/openbao/builtin/logical/pki"
 	"github.com/openbao/openbao/helper/namespace"
 	"github.com/openbao/openbao/helper/versions"
 	"github.com/openbao/openbao/internalshared/configutil"
@@ -198,6 +201,193 @@ func TestHandler_cors(t *testing.T) {
 	}
 }
 
+func TestHandler_ListenerCors(t *testing.T) {
+	ln, addr := TestListener(t)
+	core, _, _ := vault.TestCoreUnsealed(t)
+	TestServerWithListenerAndProperties(t, ln, addr, core, &vault.HandlerProperties{
+		Core: core,
+		ListenerConfig: &configutil.Listener{
+			Address:            addr,
+			CorsEnabled:        true,
+			CorsAllowedOrigins: []string{"https://example.com"},
+			CorsAllowedHeaders: []string{"X-Custom-Header"},
+		},
+	})
+	defer ln.Close()
+
+	// The cluster-wide CORS configuration is disabled, but the listener's
+	// configuration applies.
+	require.False(t, core.CORSConfig().IsEnabled())
+
+	req, err := http.NewRequest(http.MethodOptions, addr+"/v1/sys/seal-status", nil)
+	require.NoError(t, err)
+	req.Header.Set("Origin", "https://bad.example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+
+	client := cleanhttp.DefaultClient()
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	req.Header.Set("Origin", "https://example.com")
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com", resp.Header.Get("Access-Control-Allow-Origin"))
+	require.Equal(t, strings.Join(append(append([]string{}, vault.StdAllowedHeaders...), "X-Custom-Header"), ","), resp.Header.Get("Access-Control-Allow-Headers"))
+}
+
+func TestHandler_RequestHeaderPolicy(t *testing.T) {
+	ln, addr := TestListener(t)
+	core, _, token := vault.TestCoreUnsealed(t)
+	TestServerWithListenerAndProperties(t, ln, addr, core, &vault.HandlerProperties{
+		Core: core,
+		ListenerConfig: &configutil.Listener{
+			Address:                addr,
+			RequiredRequestHeaders: []string{"X-Request-Id"},
+			RemovedRequestHeaders:  []string{"X-Vault-Wrap-TTL"},
+		},
+	})
+	defer ln.Close()
+
+	client := cleanhttp.DefaultClient()
+
+	req, err := http.NewRequest(http.MethodGet, addr+"/v1/auth/token/lookup-self", nil)
+	require.NoError(t, err)
+	req.Header.Set(consts.AuthHeaderName, token)
+
+	resp, err := client.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+
+	// The wrapping header is stripped, so the response is not wrapped.
+	req.Header.Set("X-Request-Id", "abc")
+	req.Header.Set("X-Vault-Wrap-TTL", "5m")
+	resp, err = client.Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body map[string]interface{}
+	testResponseBody(t, resp, &body)
+	require.Nil(t, body["wrap_info"])
+	require.NotNil(t, body["data"])
+}
+
+func TestHandler_ActiveSNI(t *testing.T) {
+	var forwarding []string
+	h := wrapActiveSNIHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarding = append(forwarding, r.Header.Get(NoRequestForwardingHeaderName))
+	}), &vault.HandlerProperties{
+		ListenerConfig: &configutil.Listener{
+			ActiveSNIName: "active.vault.example.com",
+		},
+	})
+
+	for _, serverName := range []string{"Active.Vault.Example.com", "vault.example.com", ""} {
+		req := httptest.NewRequest(http.MethodGet, "https://127.0.0.1:8200/v1/secret/foo", nil)
+		if serverName != "" {
+			req.TLS = &tls.ConnectionState{ServerName: serverName}
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		require.Equal(t, "active.vault.example.com", w.Header().Get(ActiveSNIHeaderName))
+	}
+
+	// Only the requests made to the active name skip forwarding.
+	require.Equal(t, []string{"true", "", ""}, forwarding)
+}
+
+func TestHandler_PublicPKIListener(t *testing.T) {
+	core, _, token := vault.TestCoreUnsealedWithConfig(t, &vault.CoreConfig{
+		LogicalBackends: map[string]logical.Factory{
+			"pki": pki.Factory,
+		},
+	})
+
+	ctx := namespace.RootContext(nil)
+	for _, write := range []struct {
+		path string
+		data map[string]interface{}
+	}{
+		{"sys/mounts/pki", map[string]interface{}{"type": "pki"}},
+		{"pki/root/generate/internal", map[string]interface{}{"common_name": "root.example.com", "key_type": "ec"}},
+		{"pki/config/cluster", map[string]interface{}{"path": "https://pki.example.com/v1/pki"}},
+		{"pki/config/acme", map[string]interface{}{"enabled": true}},
+	} {
+		req := logical.TestRequest(t, logical.UpdateOperation, write.path)
+		req.Data = write.data
+		req.ClientToken = token
+		resp, err := core.HandleRequest(ctx, req)
+		require.NoError(t, err)
+		require.False(t, resp != nil && resp.IsError(), "error response: %#v", resp)
+	}
+
+	ln, addr := TestListener(t)
+	TestServerWithListenerAndProperties(t, ln, addr, core, &vault.HandlerProperties{
+		Core: core,
+		ListenerConfig: &configutil.Listener{
+			Address: addr,
+			Role:    "pki_public",
+		},
+	})
+	defer ln.Close()
+
+	client := cleanhttp.DefaultClient()
+	getWithNamespace := func(path, ns string) int {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, addr+path, nil)
+		require.NoError(t, err)
+		req.Header.Set(consts.AuthHeaderName, token)
+		if ns != "" {
+			req.Header.Set(consts.NamespaceHeaderName, ns)
+		}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+	get := func(path string) int {
+		t.Helper()
+		return getWithNamespace(path, "")
+	}
+
+	// The read-only public endpoints of PKI mounts are served.
+	require.Equal(t, http.StatusOK, get("/v1/pki/ca/pem"))
+	require.Equal(t, http.StatusOK, get("/v1/pki/crl/pem"))
+
+	// So is the ACME directory, but not the ACME endpoints creating accounts,
+	// orders and certificates, even though they are unauthenticated.
+	post := func(path string) int {
+		t.Helper()
+		resp, err := client.Post(addr+path, "application/jose+json", strings.NewReader("{}"))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+	require.Equal(t, http.StatusOK, get("/v1/pki/acme/directory"))
+	for _, path := range []string{
+		"/v1/pki/acme/new-account",
+		"/v1/pki/acme/new-order",
+		"/v1/pki/acme/order/1/finalize",
+		"/v1/pki/acme/revoke-cert",
+		"/v1/pki/roles/web/acme/new-account",
+		"/v1/pki/acme/directory",
+	} {
+		require.Equal(t, http.StatusNotFound, post(path), path)
+	}
+	require.Equal(t, http.StatusNotFound, get("/v1/pki/acme/new-nonce"))
+
+	// Nothing else is, even with a token.
+	require.Equal(t, http.StatusNotFound, get("/v1/pki/roles?list=true"))
+	require.Equal(t, http.StatusNotFound, get("/v1/pki/roles?help=1"))
+	require.Equal(t, http.StatusNotFound, get("/v1/auth/token/lookup-self"))
+	require.Equal(t, http.StatusNotFound, get("/v1/sys/health"))
+	require.Equal(t, http.StatusNotFound, get("/ui/"))
+
+	// Namespaces are refused, as the mount checked must be the one serving
+	// the request.
+	require.Equal(t, http.StatusBadRequest, getWithNamespace("/v1/pki/ca/pem", "ns1"))
+}
+
 func TestHandler_HostnameHeader(t *testing.T) {
 	t.Parallel()
 	testCases := []struct {
@@ -918,6 +1108,58 @@ func TestHandler_MaxRequestSize(t *testing.T) {
 	require.ErrorContains(t, err, "error parsing JSON")
 }
 
+// TestHandler_MaxResponseSize verifies that a response larger than the
+// MaxResponseSize fails with guidance for list requests
+func TestHandler_MaxResponseSize(t *testing.T) {
+	t.Parallel()
+	cluster := vault.NewTestCluster(t, &vault.CoreConfig{}, &vault.TestClusterOptions{
+		DefaultHandlerProperties: vault.HandlerProperties{
+			ListenerConfig: &configutil.Listener{
+				MaxResponseSize: 1024,
+			},
+		},
+		HandlerFunc: Handler,
+		NumCores:    1,
+	})
+	cluster.Start()
+	defer cluster.Cleanup()
+
+	client := cluster.Cores[0].Client
+	_, err := client.Logical().Write("secret/foo", map[string]interface{}{
+		"bar": strings.Repeat("a", 1025),
+	})
+	require.NoError(t, err)
+	_, err = client.Logical().Write("secret/small", map[string]interface{}{
+		"bar": "baz",
+	})
+	require.NoError(t, err)
+
+	_, err = client.Logical().Read("secret/small")
+	require.NoError(t, err)
+
+	_, err = client.Logical().Read("secret/foo")
+	require.ErrorContains(t, err, "exceeds the maximum response size of 1024 bytes")
+
+	for i := 0; i < 150; i++ {
+		_, err = client.Logical().Write("secret/list/key-"+strconv.Itoa(i), map[string]interface{}{
+			"bar": "baz",
+		})
+		require.NoError(t, err)
+	}
+	_, err = client.Logical().List("secret/list")
+	require.ErrorContains(t, err, "use the limit and after parameters")
+
+	// Listing in pages fits in the limit
+	r := client.NewRequest("LIST", "/v1/secret/list")
+	r.Params.Set("limit", "5")
+	resp, err := client.RawRequest(r)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	secret, err := api.ParseSecret(resp.Body)
+	require.NoError(t, err)
+	require.Len(t, secret.Data["keys"], 5)
+}
+
 // TestHandler_MaxRequestSize_Memory sets the max request size to 1024 bytes,
 // and creates a 1MB request. The test verifies that less than 1MB of memory is
 // allocated when the request is sent. This test shouldn't be run in parallel,
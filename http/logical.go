@@ -7,6 +7,7 @@ import (
 	"bufio"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime"
@@ -38,12 +39,29 @@ func newBufferedReader(r io.ReadCloser) *bufferedReader {
 	}
 }
 
+// countingReader counts the bytes read from a request body, so that the
+// router can enforce the maximum request size of the mount.
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
 func (b *bufferedReader) Close() error {
 	return b.rOrig.Close()
 }
 
 const MergePatchContentTypeHeader = "application/merge-patch+json"
 
+// EncryptedPayloadContentType is the content type of requests whose JSON
+// body is encrypted to the payload encryption key of the active node.
+const EncryptedPayloadContentType = "application/vnd.openbao.encrypted-payload+json"
+
 func buildLogicalRequestNoAuth(w http.ResponseWriter, r *http.Request) (*logical.Request, io.ReadCloser, int, error) {
 	ns, err := namespace.FromContext(r.Context())
 	if err != nil {
@@ -55,6 +73,7 @@ func buildLogicalRequestNoAuth(w http.ResponseWriter, r *http.Request) (*logical
 	var origBody io.ReadCloser
 	var passHTTPReq bool
 	var responseWriter http.ResponseWriter
+	var body *countingReader
 
 	// Determine the operation
 	var op logical.Operation
@@ -100,7 +119,8 @@ func buildLogicalRequestNoAuth(w http.ResponseWriter, r *http.Request) (*logical
 
 		// Buffer the request body in order to allow us to peek at the beginning
 		// without consuming it. This approach involves no copying.
-		bufferedBody := newBufferedReader(r.Body)
+		body = &countingReader{ReadCloser: r.Body}
+		bufferedBody := newBufferedReader(body)
 		r.Body = bufferedBody
 
 		// If we are uploading a snapshot or receiving an ocsp-request (which
@@ -110,6 +130,11 @@ func buildLogicalRequestNoAuth(w http.ResponseWriter, r *http.Request) (*logical
 		if path == "sys/storage/raft/snapshot" || path == "sys/storage/raft/snapshot-force" || isOcspRequest(contentType) {
 			passHTTPReq = true
 			origBody = r.Body
+		} else if path == "sys/storage/raft/snapshot-load" {
+			// The snapshot is the body, so parameters are passed in the query.
+			passHTTPReq = true
+			origBody = r.Body
+			data = parseQuery(r.URL.Query())
 		} else {
 			// Sample the first bytes to determine whether this should be parsed as
 			// a form or as JSON. The amount to look ahead (512 bytes) is arbitrary
@@ -160,6 +185,8 @@ func buildLogicalRequestNoAuth(w http.ResponseWriter, r *http.Request) (*logical
 			return nil, nil, http.StatusUnsupportedMediaType, fmt.Errorf("PATCH requires Content-Type of %s, provided %s", MergePatchContentTypeHeader, contentType)
 		}
 
+		body = &countingReader{ReadCloser: r.Body}
+		r.Body = body
 		origBody, err = parseJSONRequest(r, w, &data)
 
 		if err == io.EOF {
@@ -194,17 +221,25 @@ func buildLogicalRequestNoAuth(w http.ResponseWriter, r *http.Request) (*logical
 	}
 
 	req := &logical.Request{
-		ID:         requestId,
-		Operation:  op,
-		Path:       path,
-		Data:       data,
-		Connection: getConnection(r),
-		Headers:    r.Header,
+		ID:            requestId,
+		CorrelationID: r.Header.Get(CorrelationIDHeaderName),
+		Operation:     op,
+		Path:          path,
+		Data:          data,
+		Connection:    getConnection(r),
+		Headers:       r.Header,
 	}
 
 	if passHTTPReq {
 		req.HTTPRequest = r
 	}
+	switch {
+	case passHTTPReq && r.ContentLength > 0:
+		// The body is consumed by the backend, so rely on the declared length
+		req.RequestSize = r.ContentLength
+	case body != nil:
+		req.RequestSize = body.n
+	}
 	if responseWriter != nil {
 		req.ResponseWriter = logical.NewHTTPResponseWriter(responseWriter)
 	}
@@ -266,6 +301,16 @@ func buildLogicalRequest(core *vault.Core, w http.ResponseWriter, r *http.Reques
 		return nil, nil, status, err
 	}
 
+	// Encrypted payloads are only decrypted here, on the node handling the
+	// request: standby nodes forward the request before it is built.
+	if contentType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); contentType == EncryptedPayloadContentType && req.Operation == logical.UpdateOperation {
+		data, err := core.DecryptRequestPayload(r.Context(), r.URL.Path, req.Data)
+		if err != nil {
+			return nil, nil, http.StatusBadRequest, fmt.Errorf("error decrypting payload: %w", err)
+		}
+		req.Data = data
+	}
+
 	requestAuth(r, req)
 
 	req, err = requestWrapInfo(r, req)
@@ -283,6 +328,19 @@ func buildLogicalRequest(core *vault.Core, w http.ResponseWriter, r *http.Reques
 		return nil, nil, http.StatusBadRequest, fmt.Errorf("failed to parse %s header: %w", PolicyOverrideHeaderName, err)
 	}
 
+	err = requestIdempotencyKey(r, req)
+	if err != nil {
+		return nil, nil, http.StatusBadRequest, fmt.Errorf("failed to parse %s header: %w", IdempotencyKeyHeaderName, err)
+	}
+
+	requestDeleteConfirmation(r, req)
+	requestAcknowledgedBanners(r, req)
+
+	err = requestDryRun(r, req)
+	if err != nil {
+		return nil, nil, http.StatusBadRequest, fmt.Errorf("failed to parse %s header: %w", DryRunHeaderName, err)
+	}
+
 	return req, origBody, 0, nil
 }
 
@@ -429,6 +487,28 @@ func respondLogical(core *vault.Core, w http.ResponseWriter, r *http.Request, re
 
 	adjustResponse(core, w, req)
 
+	// Enforce the maximum response size of the listener
+	if maxResponseSize, ok := r.Context().Value(maxResponseSizeKey{}).(int64); ok && ret != nil {
+		body, err := json.Marshal(ret)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err)
+			return
+		}
+		if int64(len(body)) > maxResponseSize {
+			msg := fmt.Sprintf("response of %d bytes exceeds the maximum response size of %d bytes", len(body), maxResponseSize)
+			if req != nil && req.Operation == logical.ListOperation {
+				msg += "; use the limit and after parameters to list the keys in pages"
+			}
+			respondError(w, http.StatusBadRequest, errors.New(msg))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(append(body, '\n'))
+		return
+	}
+
 	// Respond
 	respondOk(w, ret)
 	return
@@ -558,9 +638,10 @@ func getConnection(r *http.Request) (connection *logical.Connection) {
 	}
 
 	connection = &logical.Connection{
-		RemoteAddr: remoteAddr,
-		RemotePort: remotePort,
-		ConnState:  r.TLS,
+		RemoteAddr:      remoteAddr,
+		RemotePort:      remotePort,
+		ConnState:       r.TLS,
+		PeerCredentials: peerCredentialsFromContext(r.Context()),
 	}
 	return
 }
@@ -30,11 +30,14 @@ import (
 	"github.com/openbao/openbao/sdk/v2/physical/inmem"
 
 	"github.com/go-test/deep"
+	"github.com/hashicorp/go-cleanhttp"
 	log "github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-uuid"
 
 	"github.com/openbao/openbao/audit"
 	"github.com/openbao/openbao/helper/namespace"
 	"github.com/openbao/openbao/vault"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLogical(t *testing.T) {
@@ -500,9 +503,11 @@ func TestLogical_Audit_invalidWrappingToken(t *testing.T) {
 			"token": "foo",
 		})
 		testResponseStatus(t, resp, 400)
-		body := map[string][]string{}
+		var body struct {
+			Errors []string `json:"errors"`
+		}
 		testResponseBody(t, resp, &body)
-		if body["errors"][0] != "wrapping token is not valid or does not exist" {
+		if body.Errors[0] != "wrapping token is not valid or does not exist" {
 			t.Fatal(body)
 		}
 
@@ -935,3 +940,224 @@ func TestLogical_AuditEnabled_ShouldLogPluginMetadata_Secret(t *testing.T) {
 		testBuiltinPluginMetadataAuditLog(t, auditResponse, consts.PluginTypeSecrets.String())
 	}
 }
+
+func TestLogical_CorrelationID(t *testing.T) {
+	noop := corehelpers.TestNoopAudit(t, nil)
+	c, _, root := vault.TestCoreUnsealedWithConfig(t, &vault.CoreConfig{
+		AuditBackends: map[string]audit.Factory{
+			"noop": func(ctx context.Context, config *audit.BackendConfig) (audit.Backend, error) {
+				return noop, nil
+			},
+		},
+	})
+	ln, addr := TestServer(t, c)
+	defer ln.Close()
+
+	resp := testHttpPost(t, root, addr+"/v1/sys/audit/noop", map[string]interface{}{
+		"type": "noop",
+	})
+	testResponseStatus(t, resp, 204)
+
+	client := cleanhttp.DefaultClient()
+	lookupSelf := func(correlationID string) string {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, addr+"/v1/auth/token/lookup-self", nil)
+		require.NoError(t, err)
+		req.Header.Set(consts.AuthHeaderName, root)
+		if correlationID != "" {
+			req.Header.Set(CorrelationIDHeaderName, correlationID)
+		}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		testResponseStatus(t, resp, 200)
+		return resp.Header.Get(CorrelationIDHeaderName)
+	}
+
+	// The correlation ID of the client is returned and audited
+	require.Equal(t, "client-1234", lookupSelf("client-1234"))
+	require.Equal(t, "client-1234", noop.Req[len(noop.Req)-1].CorrelationID)
+
+	// Otherwise, one is generated
+	generated := lookupSelf("")
+	_, err := uuid.ParseUUID(generated)
+	require.NoError(t, err)
+	require.Equal(t, generated, noop.Req[len(noop.Req)-1].CorrelationID)
+
+	// Invalid correlation IDs are replaced
+	replaced := lookupSelf("invalid id\twith spaces")
+	_, err = uuid.ParseUUID(replaced)
+	require.NoError(t, err)
+}
+
+func TestLogical_EncryptedPayload(t *testing.T) {
+	cluster := vault.NewTestCluster(t, nil, &vault.TestClusterOptions{
+		HandlerFunc: Handler,
+	})
+	cluster.Start()
+	defer cluster.Cleanup()
+
+	c := cluster.Cores[0].Client
+	vault.TestWaitActive(t, cluster.Cores[0].Core)
+
+	key, err := c.Sys().PayloadEncryptionKey()
+	require.NoError(t, err)
+	require.Equal(t, api.PayloadEncryptionAlgorithm, key.Algorithm)
+
+	r := c.NewRequest(http.MethodPut, "/v1/cubbyhole/foo")
+	require.NoError(t, r.SetEncryptedJSONBody(key, map[string]interface{}{"password": "hunter2"}))
+	require.NotContains(t, string(r.BodyBytes), "hunter2")
+	resp, err := c.RawRequest(r)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	secret, err := c.Logical().Read("cubbyhole/foo")
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", secret.Data["password"])
+
+	// A payload cannot be sent to another path than it was encrypted for
+	r = c.NewRequest(http.MethodPut, "/v1/cubbyhole/foo")
+	require.NoError(t, r.SetEncryptedJSONBody(key, map[string]interface{}{"password": "hunter3"}))
+	r.URL.Path = "/v1/cubbyhole/bar"
+	_, err = c.RawRequest(r)
+	var respErr *api.ResponseError
+	require.ErrorAs(t, err, &respErr)
+	require.Equal(t, http.StatusBadRequest, respErr.StatusCode)
+}
+
+func TestLogical_ErrorCode(t *testing.T) {
+	core, _, token := vault.TestCoreUnsealed(t)
+	ln, addr := TestServer(t, core)
+	defer ln.Close()
+	TestServerAuth(t, addr, token)
+
+	config := api.DefaultConfig()
+	config.Address = addr
+	client, err := api.NewClient(config)
+	require.NoError(t, err)
+	client.SetToken(token)
+
+	_, err = client.Logical().Write("sys/mounts/foo", map[string]interface{}{
+		"type": "nonexistent",
+	})
+	require.Equal(t, api.ErrorCodeInvalidRequest, api.ErrorCode(err))
+
+	_, err = client.Logical().Write("nonexistent/foo", map[string]interface{}{})
+	require.Equal(t, api.ErrorCodeUnsupportedPath, api.ErrorCode(err))
+
+	_, err = client.Logical().Delete("sys/policy/root")
+	require.Equal(t, api.ErrorCodeInvalidRequest, api.ErrorCode(err))
+
+	client.SetToken("invalid")
+	_, err = client.Logical().Read("secret/foo")
+	require.Equal(t, api.ErrorCodePermissionDenied, api.ErrorCode(err))
+
+	// The error code is returned alongside the errors
+	resp := testHttpGet(t, "invalid", addr+"/v1/secret/foo")
+	testResponseStatus(t, resp, http.StatusForbidden)
+	var body struct {
+		Errors    []string `json:"errors"`
+		ErrorCode string   `json:"error_code"`
+	}
+	testResponseBody(t, resp, &body)
+	require.Equal(t, []string{"permission denied"}, body.Errors)
+	require.Equal(t, api.ErrorCodePermissionDenied, body.ErrorCode)
+}
+
+func TestLogical_DryRun(t *testing.T) {
+	core, _, token := vault.TestCoreUnsealed(t)
+	ln, addr := TestServer(t, core)
+	defer ln.Close()
+	TestServerAuth(t, addr, token)
+
+	config := api.DefaultConfig()
+	config.Address = addr
+	client, err := api.NewClient(config)
+	require.NoError(t, err)
+	client.SetToken(token)
+
+	r := client.NewRequest(http.MethodPut, "/v1/secret/foo")
+	r.DryRun = true
+	require.NoError(t, r.SetJSONBody(map[string]interface{}{"data": "bar"}))
+	resp, err := client.RawRequest(r)
+	require.NoError(t, err)
+	secret, err := api.ParseSecret(resp.Body)
+	require.NoError(t, err)
+	require.NotEmpty(t, secret.Warnings)
+
+	secret, err = client.Logical().Read("secret/foo")
+	require.NoError(t, err)
+	require.Nil(t, secret)
+
+	req, err := http.NewRequest(http.MethodPut, addr+"/v1/secret/foo", strings.NewReader(`{"data":"bar"}`))
+	require.NoError(t, err)
+	req.Header.Set(consts.AuthHeaderName, token)
+	req.Header.Set(DryRunHeaderName, "maybe")
+	httpResp, err := cleanhttp.DefaultClient().Do(req)
+	require.NoError(t, err)
+	testResponseStatus(t, httpResp, http.StatusBadRequest)
+}
+
+func TestLogical_LoginBanners(t *testing.T) {
+	coreConfig := &vault.CoreConfig{
+		CredentialBackends: map[string]logical.Factory{
+			"userpass": credUserpass.Factory,
+		},
+	}
+	cluster := vault.NewTestCluster(t, coreConfig, &vault.TestClusterOptions{
+		HandlerFunc: Handler,
+	})
+	cluster.Start()
+	defer cluster.Cleanup()
+
+	vault.TestWaitActive(t, cluster.Cores[0].Core)
+	client := cluster.Cores[0].Client
+
+	require.NoError(t, client.Sys().EnableAuthWithOptions("userpass", &api.EnableAuthOptions{Type: "userpass"}))
+	_, err := client.Logical().Write("auth/userpass/users/alice", map[string]interface{}{"password": "secret"})
+	require.NoError(t, err)
+	_, err = client.Logical().Write("sys/config/ui/banners/terms", map[string]interface{}{
+		"message":                "Authorized use only.",
+		"require_acknowledgment": true,
+	})
+	require.NoError(t, err)
+
+	login := func(acknowledged ...string) (*api.Secret, error) {
+		t.Helper()
+		r := client.NewRequest(http.MethodPut, "/v1/auth/userpass/login/alice")
+		r.AcknowledgedBanners = acknowledged
+		require.NoError(t, r.SetJSONBody(map[string]interface{}{"password": "secret"}))
+		resp, err := client.RawRequest(r)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		return api.ParseSecret(resp.Body)
+	}
+
+	_, err = login()
+	require.Error(t, err)
+	require.Equal(t, api.ErrorCodeBannerAcknowledgmentRequired, api.ErrorCode(err))
+
+	secret, err := login("terms")
+	require.NoError(t, err)
+	require.NotEmpty(t, secret.Auth.ClientToken)
+
+	// The acknowledgment lasts for the period of the banner, until the terms
+	// change
+	_, err = login()
+	require.NoError(t, err)
+
+	_, err = client.Logical().Write("sys/config/ui/banners/terms", map[string]interface{}{
+		"message": "Authorized use only. Activity is monitored.",
+	})
+	require.NoError(t, err)
+	_, err = login()
+	require.Equal(t, api.ErrorCodeBannerAcknowledgmentRequired, api.ErrorCode(err))
+
+	// The banners are shown before logging in
+	client.ClearToken()
+	secret, err = client.Logical().Read("sys/internal/ui/banners")
+	require.NoError(t, err)
+	require.Len(t, secret.Data["banners"], 1)
+}
@@ -0,0 +1,33 @@
+package http
+
+import (
+	"context"
+	"net"
+
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+type peerCredentialsContextKey struct{}
+
+// ConnContext is used as the ConnContext of the API servers. For connections
+// accepted on a Unix domain socket it records the kernel-reported
+// credentials of the connecting process so that they can be attached to
+// logical requests and consumed by credential backends.
+func ConnContext(ctx context.Context, conn net.Conn) context.Context {
+	uc, ok := conn.(*net.UnixConn)
+	if !ok {
+		return ctx
+	}
+
+	creds, err := peerCredentials(uc)
+	if err != nil || creds == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, peerCredentialsContextKey{}, creds)
+}
+
+func peerCredentialsFromContext(ctx context.Context) *logical.PeerCredentials {
+	creds, _ := ctx.Value(peerCredentialsContextKey{}).(*logical.PeerCredentials)
+	return creds
+}
@@ -0,0 +1,34 @@
+//go:build linux
+
+package http
+
+import (
+	"net"
+
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"golang.org/x/sys/unix"
+)
+
+func peerCredentials(conn *net.UnixConn) (*logical.PeerCredentials, error) {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+
+	var ucred *unix.Ucred
+	var sockErr error
+	if err := raw.Control(func(fd uintptr) {
+		ucred, sockErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	}); err != nil {
+		return nil, err
+	}
+	if sockErr != nil {
+		return nil, sockErr
+	}
+
+	return &logical.PeerCredentials{
+		PID: ucred.Pid,
+		UID: ucred.Uid,
+		GID: ucred.Gid,
+	}, nil
+}
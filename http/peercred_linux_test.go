@@ -0,0 +1,40 @@
+//go:build linux
+
+package http
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnContext_PeerCredentials(t *testing.T) {
+	ln, err := net.Listen("unix", filepath.Join(t.TempDir(), "test.sock"))
+	require.NoError(t, err)
+	defer ln.Close()
+
+	go func() {
+		conn, err := net.Dial("unix", ln.Addr().String())
+		if err == nil {
+			defer conn.Close()
+			conn.Read(make([]byte, 1))
+		}
+	}()
+
+	conn, err := ln.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+
+	creds := peerCredentialsFromContext(ConnContext(context.Background(), conn))
+	require.NotNil(t, creds)
+	require.Equal(t, uint32(os.Getuid()), creds.UID)
+	require.Equal(t, uint32(os.Getgid()), creds.GID)
+	require.Equal(t, int32(os.Getpid()), creds.PID)
+
+	// TCP connections never carry peer credentials.
+	require.Nil(t, peerCredentialsFromContext(ConnContext(context.Background(), &net.TCPConn{})))
+}
@@ -0,0 +1,15 @@
+//go:build !linux
+
+package http
+
+import (
+	"net"
+
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+// peerCredentials is only implemented on Linux, where SO_PEERCRED is
+// available.
+func peerCredentials(*net.UnixConn) (*logical.PeerCredentials, error) {
+	return nil, nil
+}
@@ -151,6 +151,7 @@ func TestSysConfigState_Sanitized(t *testing.T) {
 				"disable_printable_check":             false,
 				"disable_sealwrap":                    false,
 				"raw_storage_endpoint":                false,
+				"restricted_crypto":                   false,
 				"detect_deadlocks":                    "",
 				"introspection_endpoint":              false,
 				"disable_sentinel_trace":              false,
@@ -158,6 +159,11 @@ func TestSysConfigState_Sanitized(t *testing.T) {
 				"log_format":                          "",
 				"log_level":                           "",
 				"max_lease_ttl":                       json.Number("0"),
+				"root_token_ttl":                      json.Number("0"),
+				"root_token_num_uses":                 json.Number("0"),
+				"lease_revocation_rate_limit":         json.Number("0"),
+				"list_default_limit":                  json.Number("0"),
+				"disabled_mount_retention":            json.Number("0"),
 				"pid_file":                            "",
 				"plugin_directory":                    "",
 				"plugin_file_uid":                     json.Number("0"),
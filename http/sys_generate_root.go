@@ -119,7 +119,7 @@ func handleSysGenerateRootAttemptPut(core *vault.Core, w http.ResponseWriter, r
 	}
 
 	// Attemptialize the generation
-	if err := core.GenerateRootInit(req.OTP, req.PGPKey, generateStrategy); err != nil {
+	if err := core.GenerateRootInitFrom(req.OTP, req.PGPKey, generateStrategy, getConnection(r).RemoteAddr); err != nil {
 		respondError(w, http.StatusBadRequest, err)
 		return
 	}
@@ -133,7 +133,7 @@ func handleSysGenerateRootAttemptPut(core *vault.Core, w http.ResponseWriter, r
 }
 
 func handleSysGenerateRootAttemptDelete(core *vault.Core, w http.ResponseWriter, r *http.Request) {
-	err := core.GenerateRootCancel()
+	err := core.GenerateRootCancelFrom(getConnection(r).RemoteAddr)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, err)
 		return
@@ -176,7 +176,7 @@ func handleSysGenerateRootUpdate(core *vault.Core, generateStrategy vault.Genera
 		defer cancel()
 
 		// Use the key to make progress on root generation
-		result, err := core.GenerateRootUpdate(ctx, key, req.Nonce, generateStrategy)
+		result, err := core.GenerateRootUpdateWithTOTP(ctx, key, req.TOTP, req.Nonce, generateStrategy, getConnection(r).RemoteAddr)
 		if err != nil {
 			respondError(w, http.StatusBadRequest, err)
 			return
@@ -221,4 +221,5 @@ type GenerateRootStatusResponse struct {
 type GenerateRootUpdateRequest struct {
 	Nonce string
 	Key   string
+	TOTP  string
 }
@@ -113,6 +113,18 @@ func getSysHealth(core *vault.Core, r *http.Request) (int, *HealthResponse, erro
 		activeCode = code
 	}
 
+	unhealthyCode := http.StatusServiceUnavailable
+	if code, found, ok := fetchStatusCode(r, "unhealthycode"); !ok {
+		return http.StatusBadRequest, nil, nil
+	} else if found {
+		unhealthyCode = code
+	}
+
+	thresholds, err := parseHealthThresholds(r)
+	if err != nil {
+		return http.StatusBadRequest, nil, err
+	}
+
 	ctx := context.Background()
 
 	// Check system status
@@ -168,11 +180,149 @@ func getSysHealth(core *vault.Core, r *http.Request) (int, *HealthResponse, erro
 		Version:                    version.GetVersion().VersionNumber(),
 		ClusterName:                clusterName,
 		ClusterID:                  clusterID,
+		RestrictedCrypto:           core.RestrictedCrypto(),
+	}
+
+	// Check the subsystems when requested, which only run on unsealed nodes
+	if thresholds.enabled && init && !sealed {
+		checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+		defer cancel()
+		body.Subsystems = checkSubsystems(core.SubsystemHealth(checkCtx), standby, thresholds)
+		if len(body.Subsystems.FailedChecks) > 0 {
+			code = unhealthyCode
+		}
 	}
 
 	return code, body, nil
 }
 
+// healthCheckTimeout bounds the subsystem checks, so that probes get an
+// answer when the storage backend hangs.
+const healthCheckTimeout = 5 * time.Second
+
+// healthThresholds are the limits past which a subsystem is reported as
+// failed. Zero values are not checked.
+type healthThresholds struct {
+	enabled bool
+
+	maxStorageLatency  time.Duration
+	maxLeases          int
+	maxRevocationQueue int
+	maxReplicationLag  time.Duration
+}
+
+func parseHealthThresholds(r *http.Request) (*healthThresholds, error) {
+	var err error
+	query := r.URL.Query()
+	thresholds := &healthThresholds{}
+
+	if raw, ok := query["subsystems"]; ok {
+		if thresholds.enabled, err = parseutil.ParseBool(raw[0]); err != nil {
+			return nil, fmt.Errorf("bad value for subsystems parameter: %w", err)
+		}
+	}
+	if raw, ok := query["maxstoragelatency"]; ok {
+		if thresholds.maxStorageLatency, err = parseutil.ParseDurationSecond(raw[0]); err != nil {
+			return nil, fmt.Errorf("bad value for maxstoragelatency parameter: %w", err)
+		}
+		thresholds.enabled = true
+	}
+	if raw, ok := query["maxleases"]; ok {
+		if thresholds.maxLeases, err = strconv.Atoi(raw[0]); err != nil {
+			return nil, fmt.Errorf("bad value for maxleases parameter: %w", err)
+		}
+		thresholds.enabled = true
+	}
+	if raw, ok := query["maxrevocationqueue"]; ok {
+		if thresholds.maxRevocationQueue, err = strconv.Atoi(raw[0]); err != nil {
+			return nil, fmt.Errorf("bad value for maxrevocationqueue parameter: %w", err)
+		}
+		thresholds.enabled = true
+	}
+	if raw, ok := query["maxreplicationlag"]; ok {
+		if thresholds.maxReplicationLag, err = parseutil.ParseDurationSecond(raw[0]); err != nil {
+			return nil, fmt.Errorf("bad value for maxreplicationlag parameter: %w", err)
+		}
+		thresholds.enabled = true
+	}
+
+	return thresholds, nil
+}
+
+// checkSubsystems reports the state of the subsystems, listing the checks
+// which failed: the storage and seal must be reachable, a standby must know
+// the active node, and the configured thresholds must not be exceeded.
+func checkSubsystems(health *vault.SubsystemHealth, standby bool, thresholds *healthThresholds) *HealthSubsystems {
+	subsystems := &HealthSubsystems{
+		Storage: &HealthStorage{
+			Healthy:   health.StorageErr == nil,
+			LatencyMs: health.StorageLatency.Milliseconds(),
+		},
+		Seal: &HealthSeal{
+			Type:    health.SealType,
+			Healthy: health.SealHealthy,
+		},
+		FailedChecks: []string{},
+	}
+	fail := func(check string) {
+		subsystems.FailedChecks = append(subsystems.FailedChecks, check)
+	}
+
+	if health.StorageErr != nil {
+		subsystems.Storage.Error = health.StorageErr.Error()
+		fail("storage")
+	} else if thresholds.maxStorageLatency > 0 && health.StorageLatency > thresholds.maxStorageLatency {
+		subsystems.Storage.Healthy = false
+		fail("storage_latency")
+	}
+
+	if !health.SealHealthy {
+		fail("seal")
+	}
+
+	if standby {
+		subsystems.Forwarding = &HealthForwarding{
+			Healthy:           health.ActiveNodeAddress != "",
+			ActiveNodeAddress: health.ActiveNodeAddress,
+		}
+		if !subsystems.Forwarding.Healthy {
+			fail("forwarding")
+		}
+	} else {
+		subsystems.Expiration = &HealthExpiration{
+			Healthy:              true,
+			Leases:               health.Leases,
+			IrrevocableLeases:    health.IrrevocableLeases,
+			RevocationQueueDepth: health.RevocationQueue,
+		}
+		if thresholds.maxLeases > 0 && health.Leases > thresholds.maxLeases {
+			subsystems.Expiration.Healthy = false
+			fail("leases")
+		}
+		if thresholds.maxRevocationQueue > 0 && health.RevocationQueue > thresholds.maxRevocationQueue {
+			subsystems.Expiration.Healthy = false
+			fail("revocation_queue")
+		}
+	}
+
+	if health.ReplicationMode != "" {
+		subsystems.Replication = &HealthReplication{
+			Healthy:   true,
+			Mode:      health.ReplicationMode,
+			LastError: health.ReplicationLastError,
+		}
+		if !health.ReplicationLastSync.IsZero() {
+			subsystems.Replication.LastSync = health.ReplicationLastSync.UTC().Format(time.RFC3339)
+		}
+		if thresholds.maxReplicationLag > 0 && time.Since(health.ReplicationLastSync) > thresholds.maxReplicationLag {
+			subsystems.Replication.Healthy = false
+			fail("replication_lag")
+		}
+	}
+
+	return subsystems
+}
+
 type HealthResponse struct {
 	Initialized                bool   `json:"initialized"`
 	Sealed                     bool   `json:"sealed"`
@@ -185,4 +335,50 @@ type HealthResponse struct {
 	ClusterName                string `json:"cluster_name,omitempty"`
 	ClusterID                  string `json:"cluster_id,omitempty"`
 	LastWAL                    uint64 `json:"last_wal,omitempty"`
+	RestrictedCrypto           bool   `json:"restricted_crypto,omitempty"`
+
+	Subsystems *HealthSubsystems `json:"subsystems,omitempty"`
+}
+
+// HealthSubsystems is the state of the subsystems of a node, returned when
+// the subsystem checks are requested.
+type HealthSubsystems struct {
+	Storage     *HealthStorage     `json:"storage"`
+	Seal        *HealthSeal        `json:"seal"`
+	Expiration  *HealthExpiration  `json:"expiration,omitempty"`
+	Forwarding  *HealthForwarding  `json:"forwarding,omitempty"`
+	Replication *HealthReplication `json:"replication,omitempty"`
+
+	// FailedChecks lists the checks which flipped the status code.
+	FailedChecks []string `json:"failed_checks"`
+}
+
+type HealthStorage struct {
+	Healthy   bool   `json:"healthy"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+type HealthSeal struct {
+	Healthy bool   `json:"healthy"`
+	Type    string `json:"type"`
+}
+
+type HealthExpiration struct {
+	Healthy              bool `json:"healthy"`
+	Leases               int  `json:"leases"`
+	IrrevocableLeases    int  `json:"irrevocable_leases"`
+	RevocationQueueDepth int  `json:"revocation_queue_depth"`
+}
+
+type HealthForwarding struct {
+	Healthy           bool   `json:"healthy"`
+	ActiveNodeAddress string `json:"active_node_address,omitempty"`
+}
+
+type HealthReplication struct {
+	Healthy   bool   `json:"healthy"`
+	Mode      string `json:"mode"`
+	LastSync  string `json:"last_sync,omitempty"`
+	LastError string `json:"last_error,omitempty"`
 }
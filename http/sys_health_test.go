@@ -4,6 +4,7 @@
 package http
 
 import (
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/url"
@@ -273,3 +274,69 @@ func TestSysHealth_head(t *testing.T) {
 		}
 	}
 }
+
+func TestSysHealth_subsystems(t *testing.T) {
+	core, _, token := vault.TestCoreUnsealed(t)
+	ln, addr := TestServer(t, core)
+	defer ln.Close()
+	TestServerAuth(t, addr, token)
+
+	// Child tokens with a TTL are tracked as leases.
+	for i := 0; i < 2; i++ {
+		resp := testHttpPost(t, token, addr+"/v1/auth/token/create", map[string]interface{}{"ttl": "1h"})
+		testResponseStatus(t, resp, 200)
+	}
+
+	// The subsystems are only checked when requested.
+	resp, err := http.Get(addr + "/v1/sys/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actual map[string]interface{}
+	testResponseStatus(t, resp, 200)
+	testResponseBody(t, resp, &actual)
+	if _, ok := actual["subsystems"]; ok {
+		t.Fatalf("unexpected subsystems: %#v", actual)
+	}
+
+	resp, err = http.Get(addr + "/v1/sys/health?subsystems=true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual = map[string]interface{}{}
+	testResponseStatus(t, resp, 200)
+	testResponseBody(t, resp, &actual)
+	subsystems := actual["subsystems"].(map[string]interface{})
+	if subsystems["storage"].(map[string]interface{})["healthy"] != true || subsystems["seal"].(map[string]interface{})["healthy"] != true {
+		t.Fatalf("bad subsystems: %#v", subsystems)
+	}
+	expiration := subsystems["expiration"].(map[string]interface{})
+	if leases, _ := expiration["leases"].(json.Number).Int64(); leases < 2 || len(subsystems["failed_checks"].([]interface{})) != 0 {
+		t.Fatalf("bad subsystems: %#v", subsystems)
+	}
+
+	// Exceeding a threshold flips the status code.
+	resp, err = http.Get(addr + "/v1/sys/health?maxleases=1&unhealthycode=299")
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual = map[string]interface{}{}
+	testResponseStatus(t, resp, 299)
+	testResponseBody(t, resp, &actual)
+	failed := actual["subsystems"].(map[string]interface{})["failed_checks"].([]interface{})
+	if len(failed) != 1 || failed[0] != "leases" {
+		t.Fatalf("bad failed checks: %#v", failed)
+	}
+
+	resp, err = http.Get(addr + "/v1/sys/health?maxstoragelatency=1m&maxrevocationqueue=1000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testResponseStatus(t, resp, 200)
+
+	resp, err = http.Get(addr + "/v1/sys/health?maxleases=many")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testResponseStatus(t, resp, 400)
+}
@@ -145,9 +145,11 @@ func TestSysInit_Put_ValidateParams(t *testing.T) {
 		"recovery_threshold": 3,
 	})
 	testResponseStatus(t, resp, http.StatusBadRequest)
-	body := map[string][]string{}
+	var body struct {
+		Errors []string `json:"errors"`
+	}
 	testResponseBody(t, resp, &body)
-	if body["errors"][0] != "parameters recovery_shares,recovery_threshold not applicable to seal type shamir" {
+	if body.Errors[0] != "parameters recovery_shares,recovery_threshold not applicable to seal type shamir" {
 		t.Fatal(body)
 	}
 }
@@ -188,9 +190,11 @@ func TestSysInit_Put_ValidateParams_AutoUnseal(t *testing.T) {
 		"recovery_threshold": 3,
 	})
 	testResponseStatus(t, resp, http.StatusBadRequest)
-	body := map[string][]string{}
+	var body struct {
+		Errors []string `json:"errors"`
+	}
 	testResponseBody(t, resp, &body)
-	if body["errors"][0] != "parameters secret_shares,secret_threshold not applicable to seal type transit" {
+	if body.Errors[0] != "parameters secret_shares,secret_threshold not applicable to seal type transit" {
 		t.Fatal(body)
 	}
 }
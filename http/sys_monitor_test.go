@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/openbao/openbao/api/v2"
 	"github.com/openbao/openbao/helper/testhelpers"
 	"github.com/openbao/openbao/vault"
 )
@@ -125,3 +126,61 @@ func TestSysMonitorStreamingLogs(t *testing.T) {
 		})
 	}
 }
+
+func TestSysMonitorFilteredStreamingLogs(t *testing.T) {
+	t.Parallel()
+	cluster := vault.NewTestCluster(t, nil, &vault.TestClusterOptions{
+		HandlerFunc: Handler,
+		NumCores:    1,
+	})
+	defer cluster.Cleanup()
+
+	client := cluster.Cores[0].Client
+
+	request := client.NewRequest("GET", "/v1/sys/monitor")
+	request.Params.Add("regex", "(")
+	_, err := client.RawRequest(request)
+	if err == nil || !strings.Contains(err.Error(), "invalid regex") {
+		t.Fatalf("expected an invalid regex error, got %v", err)
+	}
+
+	request = client.NewRequest("GET", "/v1/sys/monitor")
+	request.Params.Add("heartbeat_interval", "1s")
+	_, err = client.RawRequest(request)
+	if err == nil || !strings.Contains(err.Error(), "requires the json log format") {
+		t.Fatalf("expected a log format error, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Filter out all the logs to only receive heartbeats
+	logCh, err := client.Sys().MonitorWithInput(ctx, &api.MonitorInput{
+		LogLevel:          "debug",
+		LogFormat:         "json",
+		Regex:             "^no message matches this$",
+		HeartbeatInterval: time.Second,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	timeCh := time.After(5 * time.Second)
+	for {
+		select {
+		case log := <-logCh:
+			message := struct {
+				Type string `json:"@type"`
+			}{}
+			if err := json.Unmarshal([]byte(log), &message); err != nil {
+				t.Fatalf("Expected JSON log from channel: %s", log)
+			}
+			if message.Type != "heartbeat" {
+				t.Fatalf("Expected only heartbeats, got %s", log)
+			}
+			return
+		case <-timeCh:
+			t.Fatal("Failed to get a heartbeat after 5 seconds")
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -1744,6 +1745,58 @@ func TestSysTuneMount_listingVisibility(t *testing.T) {
 	}
 }
 
+func TestSysTuneMount_requestLimits(t *testing.T) {
+	core, _, token := vault.TestCoreUnsealed(t)
+	ln, addr := TestServer(t, core)
+	defer ln.Close()
+	TestServerAuth(t, addr, token)
+
+	// Mount-tune the request limits
+	resp := testHttpPost(t, token, addr+"/v1/sys/mounts/secret/tune", map[string]interface{}{
+		"default_request_timeout": "30s",
+		"max_request_size":        64,
+	})
+	testResponseStatus(t, resp, 204)
+
+	// Check results
+	resp = testHttpGet(t, token, addr+"/v1/sys/mounts/secret/tune")
+	testResponseStatus(t, resp, 200)
+
+	actual := map[string]interface{}{}
+	testResponseBody(t, resp, &actual)
+	data := actual["data"].(map[string]interface{})
+	if data["default_request_timeout"] != json.Number("30") || data["max_request_size"] != json.Number("64") {
+		t.Fatalf("bad: %#v", data)
+	}
+
+	// Requests within the limits are handled, larger ones are refused
+	resp = testHttpPut(t, token, addr+"/v1/secret/foo", map[string]interface{}{
+		"data": "bar",
+	})
+	testResponseStatus(t, resp, 204)
+
+	resp = testHttpPut(t, token, addr+"/v1/secret/foo", map[string]interface{}{
+		"data": strings.Repeat("a", 64),
+	})
+	testResponseStatus(t, resp, 413)
+
+	// Limits of zero are lifted
+	resp = testHttpPost(t, token, addr+"/v1/sys/mounts/secret/tune", map[string]interface{}{
+		"max_request_size": 0,
+	})
+	testResponseStatus(t, resp, 204)
+
+	resp = testHttpPut(t, token, addr+"/v1/secret/foo", map[string]interface{}{
+		"data": strings.Repeat("a", 64),
+	})
+	testResponseStatus(t, resp, 204)
+
+	resp = testHttpPost(t, token, addr+"/v1/sys/mounts/secret/tune", map[string]interface{}{
+		"max_request_size": -1,
+	})
+	testResponseStatus(t, resp, 400)
+}
+
 func TestSysTuneMount_passthroughRequestHeaders(t *testing.T) {
 	core, _, token := vault.TestCoreUnsealed(t)
 	ln, addr := TestServer(t, core)
@@ -87,6 +87,7 @@ func handleSysRekeyInitGet(ctx context.Context, core *vault.Core, recovery bool,
 		status.Progress = progress
 		status.VerificationRequired = rekeyConf.VerificationRequired
 		status.VerificationNonce = rekeyConf.VerificationNonce
+		status.TOTPShares = rekeyConf.TOTPShares
 		if rekeyConf.PGPKeys != nil && len(rekeyConf.PGPKeys) != 0 {
 			pgpFingerprints, err := pgpkeys.GetFingerprints(rekeyConf.PGPKeys, nil)
 			if err != nil {
@@ -126,6 +127,7 @@ func handleSysRekeyInitPut(ctx context.Context, core *vault.Core, recovery bool,
 		PGPKeys:              req.PGPKeys,
 		Backup:               req.Backup,
 		VerificationRequired: req.RequireVerification,
+		TOTPShares:           req.TOTPShares,
 	}, recovery)
 	if err != nil {
 		respondError(w, err.Code(), err)
@@ -184,7 +186,7 @@ func handleSysRekeyUpdate(core *vault.Core, recovery bool) http.Handler {
 		defer cancel()
 
 		// Use the key to make progress on rekey
-		result, rekeyErr := core.RekeyUpdate(ctx, key, req.Nonce, recovery)
+		result, rekeyErr := core.RekeyUpdateWithTOTP(ctx, key, req.TOTP, req.Nonce, recovery)
 		if rekeyErr != nil {
 			respondError(w, rekeyErr.Code(), rekeyErr)
 			return
@@ -199,6 +201,7 @@ func handleSysRekeyUpdate(core *vault.Core, recovery bool) http.Handler {
 			resp.PGPFingerprints = result.PGPFingerprints
 			resp.VerificationRequired = result.VerificationRequired
 			resp.VerificationNonce = result.VerificationNonce
+			resp.TOTPURLs = result.TOTPURLs
 
 			// Encode the keys
 			keys := make([]string, 0, len(result.SecretShares))
@@ -349,6 +352,7 @@ type RekeyRequest struct {
 	PGPKeys             []string `json:"pgp_keys"`
 	Backup              bool     `json:"backup"`
 	RequireVerification bool     `json:"require_verification"`
+	TOTPShares          bool     `json:"totp_shares"`
 }
 
 type RekeyStatusResponse struct {
@@ -362,11 +366,13 @@ type RekeyStatusResponse struct {
 	Backup               bool     `json:"backup"`
 	VerificationRequired bool     `json:"verification_required"`
 	VerificationNonce    string   `json:"verification_nonce,omitempty"`
+	TOTPShares           bool     `json:"totp_shares"`
 }
 
 type RekeyUpdateRequest struct {
 	Nonce string
 	Key   string
+	TOTP  string
 }
 
 type RekeyUpdateResponse struct {
@@ -378,6 +384,7 @@ type RekeyUpdateResponse struct {
 	Backup               bool     `json:"backup"`
 	VerificationRequired bool     `json:"verification_required"`
 	VerificationNonce    string   `json:"verification_nonce,omitempty"`
+	TOTPURLs             []string `json:"totp_urls,omitempty"`
 }
 
 type RekeyVerificationUpdateRequest struct {
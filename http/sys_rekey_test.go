@@ -62,6 +62,7 @@ func TestSysRekey_Init_Status(t *testing.T) {
 			"backup":                false,
 			"nonce":                 "",
 			"verification_required": false,
+			"totp_shares":           false,
 		}
 
 		if !reflect.DeepEqual(actual, expected) {
@@ -99,6 +100,7 @@ func TestSysRekey_Init_Setup(t *testing.T) {
 			"pgp_fingerprints":      interface{}(nil),
 			"backup":                false,
 			"verification_required": false,
+			"totp_shares":           false,
 		}
 
 		if actual["nonce"].(string) == "" {
@@ -125,6 +127,7 @@ func TestSysRekey_Init_Setup(t *testing.T) {
 			"pgp_fingerprints":      interface{}(nil),
 			"backup":                false,
 			"verification_required": false,
+			"totp_shares":           false,
 		}
 		if actual["nonce"].(string) == "" {
 			t.Fatalf("nonce was empty")
@@ -178,6 +181,7 @@ func TestSysRekey_Init_Cancel(t *testing.T) {
 			"backup":                false,
 			"nonce":                 "",
 			"verification_required": false,
+			"totp_shares":           false,
 		}
 		if !reflect.DeepEqual(actual, expected) {
 			t.Fatalf("\nexpected: %#v\nactual: %#v", expected, actual)
@@ -232,6 +236,7 @@ func TestSysRekey_Update(t *testing.T) {
 				"n":                     json.Number("5"),
 				"progress":              json.Number(fmt.Sprintf("%d", i+1)),
 				"verification_required": false,
+				"totp_shares":           false,
 			}
 			testResponseStatus(t, resp, 200)
 			testResponseBody(t, resp, &actual)
@@ -242,6 +247,7 @@ func TestSysRekey_Update(t *testing.T) {
 				delete(expected, "t")
 				delete(expected, "n")
 				delete(expected, "progress")
+				delete(expected, "totp_shares")
 				expected["complete"] = true
 				expected["keys"] = actual["keys"]
 				expected["keys_base64"] = actual["keys_base64"]
@@ -128,9 +128,9 @@ func handleSysUnseal(core *vault.Core) http.Handler {
 		// Attempt the unseal.  If migrate was specified, the key should correspond
 		// to the old seal.
 		if req.Migrate {
-			_, err = core.UnsealMigrate(key)
+			_, err = core.UnsealMigrateWithTOTP(key, req.TOTP)
 		} else {
-			_, err = core.Unseal(key)
+			_, err = core.UnsealWithTOTP(key, req.TOTP)
 		}
 		if err != nil {
 			switch {
@@ -174,6 +174,17 @@ func handleSysSealStatusRaw(core *vault.Core, w http.ResponseWriter, r *http.Req
 	respondOk(w, status)
 }
 
+func handleSysPostUnsealStatus(core *vault.Core) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			respondError(w, http.StatusMethodNotAllowed, nil)
+			return
+		}
+
+		respondOk(w, core.PostUnsealStatus())
+	})
+}
+
 // Note: because we didn't provide explicit tagging in the past we can't do it
 // now because if it then no longer accepts capitalized versions it could break
 // clients
@@ -181,4 +192,5 @@ type UnsealRequest struct {
 	Key     string
 	Reset   bool
 	Migrate bool
+	TOTP    string
 }
@@ -0,0 +1,185 @@
+package http
+
+import (
+	"archive/zip"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openbao/openbao/command/server"
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/helper/consts"
+	"github.com/openbao/openbao/vault"
+)
+
+// uiNamespaceCookie remembers the namespace the UI was loaded for, as the
+// requests for its assets do not carry it.
+const uiNamespaceCookie = "openbao-ui-namespace"
+
+// uiAssetsCache holds the opened zip archives of the alternate UI asset sets.
+var uiAssetsCache = &uiAssetsFileSystems{
+	archives: make(map[string]*uiAssetsArchive),
+}
+
+type uiAssetsFileSystems struct {
+	l        sync.Mutex
+	archives map[string]*uiAssetsArchive
+}
+
+// uiAssetsArchive is an opened zip archive. Its reader is only closed once
+// it was replaced by a newer version of the archive and the requests still
+// reading from it released it.
+type uiAssetsArchive struct {
+	reader   *zip.ReadCloser
+	modTime  time.Time
+	refs     int
+	replaced bool
+}
+
+// get returns the file system of the UI asset set at the given path, which is
+// either a directory or a zip archive, and a function releasing it which must
+// be called once the request is served. Archives are reopened when modified.
+func (c *uiAssetsFileSystems) get(path string) (http.FileSystem, func(), error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.IsDir() {
+		return http.Dir(path), func() {}, nil
+	}
+
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	archive, ok := c.archives[path]
+	if ok && !archive.modTime.Equal(info.ModTime()) {
+		archive.replaced = true
+		if archive.refs == 0 {
+			archive.reader.Close()
+		}
+		delete(c.archives, path)
+		ok = false
+	}
+	if !ok {
+		reader, err := zip.OpenReader(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		archive = &uiAssetsArchive{
+			reader:  reader,
+			modTime: info.ModTime(),
+		}
+		c.archives[path] = archive
+	}
+
+	archive.refs++
+	return http.FS(archive.reader), func() { c.release(archive) }, nil
+}
+
+func (c *uiAssetsFileSystems) release(archive *uiAssetsArchive) {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	archive.refs--
+	if archive.refs == 0 && archive.replaced {
+		archive.reader.Close()
+	}
+}
+
+// selectUIAssets returns the alternate UI asset set to serve the request
+// with, if any. Asset sets are selected by the Host header first, then by the
+// namespace of the request, taken from the namespace query parameter, the
+// namespace header or the namespace cookie, in that order. The most specific
+// namespace wins.
+func selectUIAssets(assets []*server.UIAssets, r *http.Request) *server.UIAssets {
+	if len(assets) == 0 {
+		return nil
+	}
+
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.ToLower(host)
+	for _, a := range assets {
+		for _, h := range a.Hosts {
+			h = strings.ToLower(h)
+			if h == host || (strings.HasPrefix(h, "*.") && strings.HasSuffix(host, h[1:])) {
+				return a
+			}
+		}
+	}
+
+	ns := uiRequestNamespace(r)
+	if ns == "" {
+		return nil
+	}
+	var selected *server.UIAssets
+	var selectedNS string
+	for _, a := range assets {
+		for _, n := range a.Namespaces {
+			n = namespace.Canonicalize(n)
+			if strings.HasPrefix(ns, n) && len(n) > len(selectedNS) {
+				selected, selectedNS = a, n
+			}
+		}
+	}
+	return selected
+}
+
+// uiRequestNamespace returns the canonical namespace the UI is requested for.
+func uiRequestNamespace(r *http.Request) string {
+	if r.URL.Query().Has("namespace") {
+		return namespace.Canonicalize(r.URL.Query().Get("namespace"))
+	}
+	if ns := r.Header.Get(consts.NamespaceHeaderName); ns != "" {
+		return namespace.Canonicalize(ns)
+	}
+	if cookie, err := r.Cookie(uiNamespaceCookie); err == nil {
+		return namespace.Canonicalize(cookie.Value)
+	}
+	return ""
+}
+
+// handleUIAssets serves the requests for which an alternate UI asset set is
+// configured from it, and the others with the given handler.
+func handleUIAssets(core *vault.Core, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		assets := core.UIAssets()
+		if len(assets) == 0 {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		// Remember the namespace the UI page is loaded for, so that its
+		// assets are served from the same set.
+		if req.URL.Query().Has("namespace") {
+			http.SetCookie(w, &http.Cookie{
+				Name:     uiNamespaceCookie,
+				Value:    uiRequestNamespace(req),
+				Path:     "/ui/",
+				HttpOnly: true,
+				Secure:   req.TLS != nil,
+				SameSite: http.SameSiteStrictMode,
+			})
+		}
+
+		selected := selectUIAssets(assets, req)
+		if selected == nil {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		fileSystem, release, err := uiAssetsCache.get(selected.Path)
+		if err != nil {
+			core.Logger().Error("failed to open UI assets", "name", selected.Name, "path", selected.Path, "error", err)
+			respondError(w, http.StatusInternalServerError, nil)
+			return
+		}
+		defer release()
+		handleUIHeaders(core, handleUI(http.FileServer(&UIAssetWrapper{FileSystem: fileSystem}))).ServeHTTP(w, req)
+	})
+}
@@ -0,0 +1,142 @@
+package http
+
+import (
+	"archive/zip"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/openbao/openbao/command/server"
+	"github.com/openbao/openbao/helper/testhelpers/corehelpers"
+	"github.com/openbao/openbao/internalshared/configutil"
+	"github.com/openbao/openbao/vault"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_UIAssets(t *testing.T) {
+	dir := t.TempDir()
+
+	tenantA := filepath.Join(dir, "tenant-a")
+	require.NoError(t, os.Mkdir(tenantA, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(tenantA, "index.html"), []byte("tenant-a"), 0o644))
+
+	tenantB := filepath.Join(dir, "tenant-b.zip")
+	f, err := os.Create(tenantB)
+	require.NoError(t, err)
+	zw := zip.NewWriter(f)
+	w, err := zw.Create("index.html")
+	require.NoError(t, err)
+	_, err = w.Write([]byte("tenant-b"))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	require.NoError(t, f.Close())
+
+	core := vault.TestCoreWithSealAndUI(t, &vault.CoreConfig{
+		EnableUI:        true,
+		BuiltinRegistry: corehelpers.NewMockBuiltinRegistry(),
+		RawConfig: &server.Config{
+			SharedConfig: &configutil.SharedConfig{},
+			UIAssets: []*server.UIAssets{
+				{Name: "tenant-a", Path: tenantA, Hosts: []string{"*.tenant-a.example.com"}},
+				{Name: "tenant-b", Path: tenantB, Namespaces: []string{"tenant-b"}},
+			},
+		},
+	})
+	ln, addr := TestServer(t, core)
+	defer ln.Close()
+
+	client := &http.Client{}
+	get := func(path string, modify func(*http.Request)) (int, string, *http.Response) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, addr+path, nil)
+		require.NoError(t, err)
+		if modify != nil {
+			modify(req)
+		}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(body), resp
+	}
+
+	// Selected by the Host header
+	status, body, _ := get("/ui/", func(r *http.Request) { r.Host = "bao.tenant-a.example.com" })
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, "tenant-a", body)
+
+	// Selected by the namespace, and remembered for the assets of the page
+	_, body, resp := get("/ui/?namespace=tenant-b/team", nil)
+	require.Equal(t, "tenant-b", body)
+	var cookie *http.Cookie
+	for _, c := range resp.Cookies() {
+		if c.Name == uiNamespaceCookie {
+			cookie = c
+		}
+	}
+	require.NotNil(t, cookie)
+	require.Equal(t, "tenant-b/team/", cookie.Value)
+
+	_, body, _ = get("/ui/assets/app.js", func(r *http.Request) { r.AddCookie(cookie) })
+	require.Equal(t, "tenant-b", body)
+
+	// Other requests get the built-in UI
+	_, body, _ = get("/ui/?namespace=tenant-c", nil)
+	require.NotEqual(t, "tenant-a", body)
+	require.NotEqual(t, "tenant-b", body)
+}
+
+func TestUIAssetsCache_Replace(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "assets.zip")
+	// Archives are replaced by renaming, leaving the opened one intact
+	writeArchive := func(content string, modTime time.Time) {
+		t.Helper()
+		f, err := os.CreateTemp(filepath.Dir(path), "assets-*.zip")
+		require.NoError(t, err)
+		zw := zip.NewWriter(f)
+		w, err := zw.Create("index.html")
+		require.NoError(t, err)
+		_, err = w.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, zw.Close())
+		require.NoError(t, f.Close())
+		require.NoError(t, os.Chtimes(f.Name(), modTime, modTime))
+		require.NoError(t, os.Rename(f.Name(), path))
+	}
+	read := func(fs http.FileSystem) string {
+		t.Helper()
+		f, err := fs.Open("/index.html")
+		require.NoError(t, err)
+		defer f.Close()
+		body, err := io.ReadAll(f)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	cache := &uiAssetsFileSystems{archives: make(map[string]*uiAssetsArchive)}
+	now := time.Now()
+	writeArchive("v1", now)
+
+	oldFS, releaseOld, err := cache.get(path)
+	require.NoError(t, err)
+	require.Equal(t, "v1", read(oldFS))
+
+	// Replacing the archive while a request is served from it does not close
+	// it under that request
+	writeArchive("v2", now.Add(time.Minute))
+	newFS, releaseNew, err := cache.get(path)
+	require.NoError(t, err)
+	require.Equal(t, "v2", read(newFS))
+	require.Equal(t, "v1", read(oldFS))
+
+	current := cache.archives[path]
+	releaseOld()
+	releaseNew()
+	require.Same(t, current, cache.archives[path])
+	require.Equal(t, 0, current.refs)
+	require.Equal(t, "v2", read(newFS))
+}
@@ -59,6 +59,21 @@ func wrapMaxRequestSizeHandler(handler http.Handler, props *vault.HandlerPropert
 	})
 }
 
+// maxResponseSizeKey is the context key of the maximum response size of the
+// listener, enforced by respondLogical.
+type maxResponseSizeKey struct{}
+
+func wrapMaxResponseSizeHandler(handler http.Handler, props *vault.HandlerProperties) http.Handler {
+	if props.ListenerConfig == nil || props.ListenerConfig.MaxResponseSize <= 0 {
+		return handler
+	}
+	maxResponseSize := props.ListenerConfig.MaxResponseSize
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(context.WithValue(r.Context(), maxResponseSizeKey{}, maxResponseSize))
+		handler.ServeHTTP(w, r)
+	})
+}
+
 func rateLimitQuotaWrapping(handler http.Handler, core *vault.Core) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ns, err := namespace.FromContext(r.Context())
@@ -83,6 +98,10 @@ func rateLimitQuotaWrapping(handler http.Handler, core *vault.Core) http.Handler
 			MountPath:     mountPath,
 			NamespacePath: ns.Path,
 			ClientAddress: parseRemoteIPAddress(r),
+			ResolveClientIdentity: func() (string, string) {
+				token, _ := getTokenFromReq(r)
+				return core.RateLimitQuotaClientIdentity(r.Context(), token)
+			},
 		}
 
 		// This checks if any role based quota is required (LCQ or RLQ).
@@ -0,0 +1,28 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/openbao/openbao/vault"
+)
+
+// handleWellKnownRedirect redirects the requests for the paths under
+// /.well-known/ claimed by a mount to the mount.
+func handleWellKnownRedirect(core *vault.Core) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		dest, err := core.WellKnownRedirectDestination(r.URL.Path)
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, err)
+			return
+		}
+		if dest == "" {
+			respondError(w, http.StatusNotFound, nil)
+			return
+		}
+
+		u := *r.URL
+		u.Path = dest
+		u.RawPath = ""
+		http.Redirect(w, r, u.RequestURI(), http.StatusTemporaryRedirect)
+	})
+}
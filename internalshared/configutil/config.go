@@ -30,6 +30,8 @@ type SharedConfig struct {
 
 	Seals []*KMS `hcl:"-"`
 
+	Entropy *Entropy `hcl:"-"`
+
 	// mlock is no longer used by OpenBao, but this is kept as a config option for
 	// compatibility's sake and to give a warning for those expecting it.
 	DisableMlockRaw interface{} `hcl:"disable_mlock"`
@@ -116,6 +118,13 @@ func ParseConfig(d string) (*SharedConfig, error) {
 		}
 	}
 
+	if o := list.Filter("entropy"); len(o.Items) > 0 {
+		result.found("entropy", "Entropy")
+		if err := parseEntropy(&result, o); err != nil {
+			return nil, fmt.Errorf("error parsing 'entropy': %w", err)
+		}
+	}
+
 	if o := list.Filter("listener"); len(o.Items) > 0 {
 		result.found("listener", "Listener")
 		if err := ParseListeners(&result, o); err != nil {
@@ -217,6 +226,14 @@ func (c *SharedConfig) Sanitized() map[string]interface{} {
 		result["seals"] = sanitizedSeals
 	}
 
+	// Sanitize entropy stanza
+	if c.Entropy != nil {
+		result["entropy"] = map[string]interface{}{
+			"source": c.Entropy.Source,
+			"path":   c.Entropy.Path,
+		}
+	}
+
 	// Sanitize telemetry stanza
 	if c.Telemetry != nil {
 		sanitizedTelemetry := map[string]interface{}{
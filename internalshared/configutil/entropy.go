@@ -0,0 +1,153 @@
+package configutil
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl"
+	"github.com/hashicorp/hcl/hcl/ast"
+	"github.com/openbao/go-kms-wrapping/entropy/v2"
+	wrapping "github.com/openbao/go-kms-wrapping/v2"
+)
+
+// EntropyMode is the way entropy of an external source is used
+type EntropyMode int
+
+const (
+	EntropyUnknown EntropyMode = iota
+	// EntropyAugmentation mixes the entropy of the external source with the
+	// entropy of the system, so that generated keys are never weaker than
+	// with the system alone.
+	EntropyAugmentation
+)
+
+const (
+	// EntropySourceSeal reads entropy from the random number generator of the
+	// seal, such as the RNG of an HSM.
+	EntropySourceSeal = "seal"
+
+	// EntropySourceFile reads entropy from a file or device, such as a
+	// hardware RNG or a pipe fed by an external generator.
+	EntropySourceFile = "file"
+)
+
+// Entropy contains Entropy configuration for the server
+type Entropy struct {
+	Mode   EntropyMode
+	Source string
+	Path   string
+}
+
+func parseEntropy(result *SharedConfig, list *ast.ObjectList) error {
+	if len(list.Items) > 1 {
+		return errors.New("only one 'entropy' block is permitted")
+	}
+	item := list.Items[0]
+
+	var raw struct {
+		Mode string `hcl:"mode"`
+		Path string `hcl:"path"`
+	}
+	if err := hcl.DecodeObject(&raw, item.Val); err != nil {
+		return multierror.Prefix(err, "entropy:")
+	}
+
+	if len(item.Keys) != 1 {
+		return errors.New("entropy source must be specified")
+	}
+	ent := &Entropy{
+		Source: strings.ToLower(item.Keys[0].Token.Value().(string)),
+		Path:   raw.Path,
+	}
+
+	switch strings.ToLower(raw.Mode) {
+	case "augmentation":
+		ent.Mode = EntropyAugmentation
+	default:
+		return multierror.Prefix(fmt.Errorf("unsupported mode %q, must be \"augmentation\"", raw.Mode), fmt.Sprintf("entropy.%s:", ent.Source))
+	}
+
+	switch ent.Source {
+	case EntropySourceSeal:
+		if ent.Path != "" {
+			return multierror.Prefix(errors.New("path is only supported by file sources"), fmt.Sprintf("entropy.%s:", ent.Source))
+		}
+	case EntropySourceFile:
+		if ent.Path == "" {
+			return multierror.Prefix(errors.New("path is required"), fmt.Sprintf("entropy.%s:", ent.Source))
+		}
+	default:
+		return fmt.Errorf("unsupported entropy source %q", ent.Source)
+	}
+
+	result.Entropy = ent
+	return nil
+}
+
+// createSecureRandomReader returns the reader used for the generation of
+// keys, augmented with the entropy of the configured external source.
+func createSecureRandomReader(conf *SharedConfig, wrapper wrapping.Wrapper) (io.Reader, error) {
+	if conf == nil || conf.Entropy == nil {
+		return rand.Reader, nil
+	}
+
+	var external io.Reader
+	switch conf.Entropy.Source {
+	case EntropySourceSeal:
+		sourcer, ok := wrapper.(entropy.Sourcer)
+		if !ok {
+			return nil, errors.New("entropy augmentation from the seal requires a seal providing entropy")
+		}
+		external = entropy.NewReader(sourcer)
+	case EntropySourceFile:
+		f, err := os.Open(conf.Entropy.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open entropy source: %w", err)
+		}
+		external = f
+	default:
+		return nil, fmt.Errorf("unsupported entropy source %q", conf.Entropy.Source)
+	}
+
+	return &augmentedReader{
+		system:   rand.Reader,
+		external: external,
+	}, nil
+}
+
+// augmentedReader mixes the entropy of an external source with the entropy of
+// the system, by XORing them. Reads fail when the external source fails, so
+// that keys are never generated without it.
+type augmentedReader struct {
+	system io.Reader
+
+	// l serializes the reads of the external source, which may be a file
+	// shared by concurrent readers
+	l        sync.Mutex
+	external io.Reader
+}
+
+func (r *augmentedReader) Read(p []byte) (int, error) {
+	if _, err := io.ReadFull(r.system, p); err != nil {
+		return 0, err
+	}
+
+	buf := make([]byte, len(p))
+	r.l.Lock()
+	_, err := io.ReadFull(r.external, buf)
+	r.l.Unlock()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read from entropy source: %w", err)
+	}
+
+	for i := range p {
+		p[i] ^= buf[i]
+	}
+	return len(p), nil
+}
@@ -0,0 +1,76 @@
+package configutil
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEntropy(t *testing.T) {
+	for name, tc := range map[string]struct {
+		config   string
+		expected *Entropy
+		err      bool
+	}{
+		"file": {
+			config:   `entropy "file" { mode = "augmentation" path = "/dev/hwrng" }`,
+			expected: &Entropy{Mode: EntropyAugmentation, Source: EntropySourceFile, Path: "/dev/hwrng"},
+		},
+		"seal": {
+			config:   `entropy "seal" { mode = "augmentation" }`,
+			expected: &Entropy{Mode: EntropyAugmentation, Source: EntropySourceSeal},
+		},
+		"no mode":           {config: `entropy "seal" {}`, err: true},
+		"unknown source":    {config: `entropy "tpm" { mode = "augmentation" }`, err: true},
+		"file without path": {config: `entropy "file" { mode = "augmentation" }`, err: true},
+		"seal with path":    {config: `entropy "seal" { mode = "augmentation" path = "/dev/hwrng" }`, err: true},
+		"two blocks": {
+			config: `entropy "seal" { mode = "augmentation" }
+entropy "file" { mode = "augmentation" path = "/dev/hwrng" }`,
+			err: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			config, err := ParseConfig(tc.config)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, config.Entropy)
+		})
+	}
+}
+
+func TestCreateSecureRandomReader(t *testing.T) {
+	reader, err := createSecureRandomReader(&SharedConfig{}, nil)
+	require.NoError(t, err)
+	require.NotNil(t, reader)
+
+	// Seals without entropy cannot augment it.
+	_, err = createSecureRandomReader(&SharedConfig{
+		Entropy: &Entropy{Mode: EntropyAugmentation, Source: EntropySourceSeal},
+	}, nil)
+	require.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "entropy")
+	require.NoError(t, os.WriteFile(path, bytes.Repeat([]byte{0xff}, 48), 0o600))
+	reader, err = createSecureRandomReader(&SharedConfig{
+		Entropy: &Entropy{Mode: EntropyAugmentation, Source: EntropySourceFile, Path: path},
+	}, nil)
+	require.NoError(t, err)
+
+	// The external entropy is mixed with the system entropy.
+	buf := make([]byte, 32)
+	n, err := reader.Read(buf)
+	require.NoError(t, err)
+	require.Equal(t, 32, n)
+	require.NotEqual(t, bytes.Repeat([]byte{0xff}, 32), buf)
+
+	// Reads fail once the external source is exhausted.
+	_, err = reader.Read(buf)
+	require.Error(t, err)
+}
@@ -5,9 +5,7 @@ package configutil
 
 import (
 	"context"
-	"crypto/rand"
 	"fmt"
-	"io"
 	"strings"
 
 	"github.com/hashicorp/errwrap"
@@ -32,18 +30,6 @@ var (
 	CreateSecureRandomReaderFunc = createSecureRandomReader
 )
 
-// Entropy contains Entropy configuration for the server
-type EntropyMode int
-
-const (
-	EntropyUnknown EntropyMode = iota
-	EntropyAugmentation
-)
-
-type Entropy struct {
-	Mode EntropyMode
-}
-
 // KMS contains KMS configuration for the server
 type KMS struct {
 	UnusedKeys []string `hcl:",unusedKeys"`
@@ -346,7 +332,3 @@ var GetTransitKMSFunc = func(kms *KMS, opts ...wrapping.Option) (wrapping.Wrappe
 	}
 	return wrapper, info, nil
 }
-
-func createSecureRandomReader(conf *SharedConfig, wrapper wrapping.Wrapper) (io.Reader, error) {
-	return rand.Reader, nil
-}
@@ -53,6 +53,8 @@ type Listener struct {
 	ClusterAddress          string        `hcl:"cluster_address"`
 	MaxRequestSize          int64         `hcl:"-"`
 	MaxRequestSizeRaw       interface{}   `hcl:"max_request_size"`
+	MaxResponseSize         int64         `hcl:"-"`
+	MaxResponseSizeRaw      interface{}   `hcl:"max_response_size"`
 	MaxRequestDuration      time.Duration `hcl:"-"`
 	MaxRequestDurationRaw   interface{}   `hcl:"max_request_duration"`
 	RequireRequestHeader    bool          `hcl:"-"`
@@ -72,6 +74,10 @@ type Listener struct {
 	TLSDisableClientCerts            bool        `hcl:"-"`
 	TLSDisableClientCertsRaw         interface{} `hcl:"tls_disable_client_certs"`
 
+	// ActiveSNIName is the TLS server name which layer-4 load balancers route
+	// to the active node. Requests made to it are never forwarded by standbys.
+	ActiveSNIName string `hcl:"active_sni_name"`
+
 	HTTPReadTimeout          time.Duration `hcl:"-"`
 	HTTPReadTimeoutRaw       interface{}   `hcl:"http_read_timeout"`
 	HTTPReadHeaderTimeout    time.Duration `hcl:"-"`
@@ -98,6 +104,10 @@ type Listener struct {
 	SocketUser  string `hcl:"socket_user"`
 	SocketGroup string `hcl:"socket_group"`
 
+	// PipeSecurityDescriptor is the SDDL security descriptor applied to
+	// Windows named pipe listeners.
+	PipeSecurityDescriptor string `hcl:"pipe_security_descriptor"`
+
 	AgentAPI *AgentAPI `hcl:"agent_api"`
 
 	ProxyAPI *ProxyAPI `hcl:"proxy_api"`
@@ -115,9 +125,19 @@ type Listener struct {
 	CorsAllowedHeaders    []string    `hcl:"-"`
 	CorsAllowedHeadersRaw []string    `hcl:"cors_allowed_headers,alias:cors_allowed_headers"`
 
+	// ignoredSettings are the problems which caused settings to be ignored
+	// rather than failing the parsing, reported by Validate.
+	ignoredSettings []ConfigError
+
 	// Custom Http response headers
 	CustomResponseHeaders    map[string]map[string]string `hcl:"-"`
 	CustomResponseHeadersRaw interface{}                  `hcl:"custom_response_headers"`
+
+	// Request header policies
+	RequiredRequestHeaders    []string `hcl:"-"`
+	RequiredRequestHeadersRaw []string `hcl:"required_request_headers"`
+	RemovedRequestHeaders     []string `hcl:"-"`
+	RemovedRequestHeadersRaw  []string `hcl:"removed_request_headers"`
 }
 
 // AgentAPI allows users to select which parts of the Agent API they want enabled.
@@ -136,7 +156,14 @@ func (l *Listener) GoString() string {
 
 func (l *Listener) Validate(path string) []ConfigError {
 	results := append(ValidateUnusedFields(l.UnusedKeys, path), ValidateUnusedFields(l.Telemetry.UnusedKeys, path)...)
-	return append(results, ValidateUnusedFields(l.Profiling.UnusedKeys, path)...)
+	results = append(results, ValidateUnusedFields(l.Profiling.UnusedKeys, path)...)
+	for _, cErr := range l.ignoredSettings {
+		if cErr.Position.Filename == "" && path != "" {
+			cErr.Position.Filename = path
+		}
+		results = append(results, cErr)
+	}
+	return results
 }
 
 func ParseListeners(result *SharedConfig, list *ast.ObjectList) error {
@@ -177,7 +204,7 @@ func ParseListeners(result *SharedConfig, list *ast.ObjectList) error {
 
 			l.Type = strings.ToLower(l.Type)
 			switch l.Type {
-			case "tcp", "unix":
+			case "tcp", "unix", "grpc", "named_pipe":
 				result.found(l.Type, l.Type)
 			default:
 				return multierror.Prefix(fmt.Errorf("unsupported listener type %q", l.Type), fmt.Sprintf("listeners.%d:", i))
@@ -195,7 +222,7 @@ func ParseListeners(result *SharedConfig, list *ast.ObjectList) error {
 			}
 
 			switch l.Role {
-			case "default", "metrics_only", "":
+			case "default", "metrics_only", "pki_public", "":
 				result.found(l.Type, l.Type)
 			default:
 				return multierror.Prefix(fmt.Errorf("unsupported listener role %q", l.Role), fmt.Sprintf("listeners.%d:", i))
@@ -212,6 +239,14 @@ func ParseListeners(result *SharedConfig, list *ast.ObjectList) error {
 				l.MaxRequestSizeRaw = nil
 			}
 
+			if l.MaxResponseSizeRaw != nil {
+				if l.MaxResponseSize, err = parseutil.ParseInt(l.MaxResponseSizeRaw); err != nil {
+					return multierror.Prefix(fmt.Errorf("error parsing max_response_size: %w", err), fmt.Sprintf("listeners.%d", i))
+				}
+
+				l.MaxResponseSizeRaw = nil
+			}
+
 			if l.MaxRequestDurationRaw != nil {
 				if l.MaxRequestDuration, err = parseutil.ParseDurationSecond(l.MaxRequestDurationRaw); err != nil {
 					return multierror.Prefix(fmt.Errorf("error parsing max_request_duration: %w", err), fmt.Sprintf("listeners.%d", i))
@@ -263,6 +298,10 @@ func ParseListeners(result *SharedConfig, list *ast.ObjectList) error {
 
 				l.TLSDisableClientCertsRaw = nil
 			}
+
+			if l.ActiveSNIName != "" && l.TLSDisable {
+				return multierror.Prefix(errors.New("active_sni_name cannot be set when tls_disable is true"), fmt.Sprintf("listeners.%d", i))
+			}
 		}
 
 		// HTTP timeouts
@@ -409,6 +448,31 @@ func ParseListeners(result *SharedConfig, list *ast.ObjectList) error {
 					l.CorsAllowedHeaders = append(l.CorsAllowedHeaders, textproto.CanonicalMIMEHeaderKey(header))
 				}
 			}
+
+			// Listeners used to ignore their CORS settings, so configurations
+			// enabling CORS without origins keep the cluster-wide behavior.
+			if l.CorsEnabled && len(l.CorsAllowedOrigins) == 0 {
+				l.CorsEnabled = false
+				l.ignoredSettings = append(l.ignoredSettings, ConfigError{
+					Problem:  "cors_enabled is ignored as cors_allowed_origins is not set, the cluster-wide CORS configuration applies",
+					Position: item.Pos(),
+				})
+			}
+		}
+
+		// Request header policies
+		{
+			for _, header := range l.RequiredRequestHeadersRaw {
+				l.RequiredRequestHeaders = append(l.RequiredRequestHeaders, textproto.CanonicalMIMEHeaderKey(header))
+			}
+
+			for _, header := range l.RemovedRequestHeadersRaw {
+				header = textproto.CanonicalMIMEHeaderKey(header)
+				if strutil.StrListContains(l.RequiredRequestHeaders, header) {
+					return multierror.Prefix(fmt.Errorf("header %q cannot be both required and removed", header), fmt.Sprintf("listeners.%d", i))
+				}
+				l.RemovedRequestHeaders = append(l.RemovedRequestHeaders, header)
+			}
 		}
 
 		// HTTP Headers
@@ -50,3 +50,55 @@ func TestParseSingleIPTemplate(t *testing.T) {
 		})
 	}
 }
+
+func TestParseListeners_RequestHeaderPolicies(t *testing.T) {
+	config, err := ParseConfig(`
+listener "tcp" {
+  address                  = "127.0.0.1:8200"
+  required_request_headers = ["x-request-id"]
+  removed_request_headers  = ["x-forwarded-host"]
+  cors_enabled             = true
+  cors_allowed_origins     = ["https://example.com"]
+}`)
+	assert.NoError(t, err)
+	assert.Len(t, config.Listeners, 1)
+	assert.Equal(t, []string{"X-Request-Id"}, config.Listeners[0].RequiredRequestHeaders)
+	assert.Equal(t, []string{"X-Forwarded-Host"}, config.Listeners[0].RemovedRequestHeaders)
+	assert.True(t, config.Listeners[0].CorsEnabled)
+
+	_, err = ParseConfig(`
+listener "tcp" {
+  required_request_headers = ["X-Request-Id"]
+  removed_request_headers  = ["x-request-id"]
+}`)
+	assert.ErrorContains(t, err, "cannot be both required and removed")
+
+	// CORS is ignored with a warning when no origins are allowed
+	config, err = ParseConfig(`
+listener "tcp" {
+  cors_enabled = true
+}`)
+	assert.NoError(t, err)
+	assert.False(t, config.Listeners[0].CorsEnabled)
+	problems := config.Listeners[0].Validate("config.hcl")
+	assert.Len(t, problems, 1)
+	assert.Contains(t, problems[0].String(), "cors_enabled is ignored")
+	assert.Equal(t, "config.hcl", problems[0].Position.Filename)
+}
+
+func TestParseListeners_ActiveSNIName(t *testing.T) {
+	config, err := ParseConfig(`
+listener "tcp" {
+  address         = "127.0.0.1:8200"
+  active_sni_name = "active.vault.example.com"
+}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "active.vault.example.com", config.Listeners[0].ActiveSNIName)
+
+	_, err = ParseConfig(`
+listener "tcp" {
+  tls_disable     = true
+  active_sni_name = "active.vault.example.com"
+}`)
+	assert.ErrorContains(t, err, "active_sni_name cannot be set")
+}
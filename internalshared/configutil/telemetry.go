@@ -413,6 +413,22 @@ func SetupTelemetry(opts *SetupTelemetryOpts) (*metrics.InmemSink, *metricsutil.
 	return inm, wrapper, prometheusEnabled, nil
 }
 
+// ReloadTelemetry applies the settings of conf that can be changed while
+// the server is running. Currently this is limited to prefix_filter.
+func ReloadTelemetry(conf *Telemetry) error {
+	if conf == nil {
+		conf = &Telemetry{}
+	}
+
+	telemetryAllowedPrefixes, telemetryBlockedPrefixes, err := parsePrefixFilter(conf.PrefixFilter)
+	if err != nil {
+		return err
+	}
+
+	metrics.UpdateFilter(telemetryAllowedPrefixes, telemetryBlockedPrefixes)
+	return nil
+}
+
 func parsePrefixFilter(prefixFilters []string) ([]string, []string, error) {
 	var telemetryAllowedPrefixes, telemetryBlockedPrefixes []string
 
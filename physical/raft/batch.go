@@ -0,0 +1,122 @@
+package raft
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	// defaultMaxBatchEntries is the default maximum number of operations
+	// committed in a single raft log by the commit batcher.
+	defaultMaxBatchEntries = 1024
+
+	// commitBatchTimeout bounds the time waited for raft to accept a batch,
+	// as the batch outlives the requests it is made of.
+	commitBatchTimeout = time.Minute
+)
+
+// commitBatcher coalesces the puts and deletes applied concurrently into a
+// single raft log, so that many small writes cost fewer raft applies. An
+// operation waits at most maxLatency for other operations to join its batch.
+type commitBatcher struct {
+	b          *RaftBackend
+	maxLatency time.Duration
+	maxEntries int
+
+	l     sync.Mutex
+	batch *commitBatch
+}
+
+// commitBatch is a batch of operations committed in a single raft log. done
+// is closed once the batch is committed, err being set if it failed.
+type commitBatch struct {
+	ops   []*LogOperation
+	size  int
+	timer *time.Timer
+	done  chan struct{}
+	err   error
+}
+
+func newCommitBatcher(b *RaftBackend, maxLatency time.Duration, maxEntries int) *commitBatcher {
+	return &commitBatcher{
+		b:          b,
+		maxLatency: maxLatency,
+		maxEntries: maxEntries,
+	}
+}
+
+// apply adds the operation to the current batch and waits for the batch to
+// be committed, or for ctx to be done. In the latter case, the operation may
+// still be committed with its batch. The caller must not hold the backend's
+// lock.
+func (c *commitBatcher) apply(ctx context.Context, op *LogOperation) error {
+	// The size of the operation as an element of LogData.Operations
+	size := protowire.SizeTag(1) + protowire.SizeBytes(proto.Size(op))
+
+	c.l.Lock()
+	// Operations too large to share an entry with the current batch start a
+	// new one; an operation too large on its own fails in applyLog.
+	if c.batch != nil && uint64(c.batch.size+size) > c.b.maxEntrySize {
+		c.dispatchLocked()
+	}
+	if c.batch == nil {
+		batch := &commitBatch{
+			done: make(chan struct{}),
+		}
+		batch.timer = time.AfterFunc(c.maxLatency, func() {
+			c.l.Lock()
+			defer c.l.Unlock()
+			if c.batch == batch {
+				c.dispatchLocked()
+			}
+		})
+		c.batch = batch
+	}
+
+	batch := c.batch
+	batch.ops = append(batch.ops, op)
+	batch.size += size
+	if len(batch.ops) >= c.maxEntries {
+		c.dispatchLocked()
+	}
+	c.l.Unlock()
+
+	select {
+	case <-batch.done:
+		return batch.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// dispatchLocked commits the current batch in the background. The caller
+// must hold the batcher's lock.
+func (c *commitBatcher) dispatchLocked() {
+	batch := c.batch
+	c.batch = nil
+	batch.timer.Stop()
+	go c.commit(batch)
+}
+
+// commit applies the operations of the batch in a single raft log.
+func (c *commitBatcher) commit(batch *commitBatch) {
+	defer close(batch.done)
+
+	metrics.AddSample([]string{"raft-storage", "batch_size"}, float32(len(batch.ops)))
+
+	command := &LogData{
+		Operations: batch.ops,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commitBatchTimeout)
+	defer cancel()
+
+	c.b.l.RLock()
+	batch.err = c.b.applyLog(ctx, command)
+	c.b.l.RUnlock()
+}
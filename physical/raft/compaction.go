@@ -0,0 +1,225 @@
+package raft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/go-secure-stdlib/parseutil"
+	"github.com/hashicorp/raft"
+	"github.com/shirou/gopsutil/v3/disk"
+)
+
+const (
+	// defaultCompactionDiskUsage is the default disk usage, in percent, above
+	// which adaptive compaction tightens the snapshot settings.
+	defaultCompactionDiskUsage = 70
+
+	// defaultCompactionMinTrailingLogs, defaultCompactionMinSnapshotThreshold
+	// and defaultCompactionMinSnapshotInterval are the default floors of the
+	// settings adjusted by adaptive compaction.
+	defaultCompactionMinTrailingLogs      = 1024
+	defaultCompactionMinSnapshotThreshold = 1024
+	defaultCompactionMinSnapshotInterval  = 10 * time.Second
+)
+
+// compactionCheckInterval is the interval between two adjustments of the
+// snapshot settings by adaptive compaction.
+var compactionCheckInterval = 10 * time.Second
+
+// compactionSettings are the raft settings driving log compaction.
+type compactionSettings struct {
+	TrailingLogs      uint64
+	SnapshotThreshold uint64
+	SnapshotInterval  time.Duration
+}
+
+// compactionTuner adjusts the compaction settings of raft to the usage of
+// the disk holding the raft data and to the growth rate of the raft log.
+// Past diskUsage, the settings are scaled down from the configured ones
+// towards their floors, reached when the disk is full; the snapshot interval
+// is also shortened so that no more than the snapshot threshold of logs
+// accumulates between two snapshot checks.
+type compactionTuner struct {
+	diskUsage            float64
+	minTrailingLogs      uint64
+	minSnapshotThreshold uint64
+	minSnapshotInterval  time.Duration
+}
+
+// parseCompactionTuner returns the compaction tuner set by the raft
+// configuration, nil unless adaptive_compaction is enabled.
+func parseCompactionTuner(conf map[string]string) (*compactionTuner, error) {
+	enabledRaw, ok := conf["adaptive_compaction"]
+	if !ok {
+		return nil, nil
+	}
+	enabled, err := strconv.ParseBool(enabledRaw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse 'adaptive_compaction': %w", err)
+	}
+	if !enabled {
+		return nil, nil
+	}
+
+	t := &compactionTuner{
+		diskUsage:            defaultCompactionDiskUsage,
+		minTrailingLogs:      defaultCompactionMinTrailingLogs,
+		minSnapshotThreshold: defaultCompactionMinSnapshotThreshold,
+		minSnapshotInterval:  defaultCompactionMinSnapshotInterval,
+	}
+
+	if usageRaw := conf["adaptive_compaction_disk_usage"]; usageRaw != "" {
+		usage, err := strconv.ParseFloat(usageRaw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse 'adaptive_compaction_disk_usage': %w", err)
+		}
+		if usage <= 0 || usage >= 100 {
+			return nil, errors.New("'adaptive_compaction_disk_usage' must be between 0 and 100")
+		}
+		t.diskUsage = usage
+	}
+
+	if trailingLogsRaw := conf["adaptive_compaction_min_trailing_logs"]; trailingLogsRaw != "" {
+		trailingLogs, err := strconv.ParseUint(trailingLogsRaw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse 'adaptive_compaction_min_trailing_logs': %w", err)
+		}
+		t.minTrailingLogs = trailingLogs
+	}
+
+	if thresholdRaw := conf["adaptive_compaction_min_snapshot_threshold"]; thresholdRaw != "" {
+		threshold, err := strconv.ParseUint(thresholdRaw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse 'adaptive_compaction_min_snapshot_threshold': %w", err)
+		}
+		if threshold < 1 {
+			return nil, errors.New("'adaptive_compaction_min_snapshot_threshold' must be at least 1")
+		}
+		t.minSnapshotThreshold = threshold
+	}
+
+	if intervalRaw := conf["adaptive_compaction_min_snapshot_interval"]; intervalRaw != "" {
+		interval, err := parseutil.ParseDurationSecond(intervalRaw)
+		if err != nil {
+			return nil, fmt.Errorf("adaptive_compaction_min_snapshot_interval does not parse as a duration: %w", err)
+		}
+		if interval <= 0 {
+			return nil, errors.New("'adaptive_compaction_min_snapshot_interval' must be positive")
+		}
+		t.minSnapshotInterval = interval
+	}
+
+	return t, nil
+}
+
+// settings returns the compaction settings for the given disk usage, in
+// percent, and log growth rate, in logs per second. The floors never exceed
+// the configured settings.
+func (t *compactionTuner) settings(configured compactionSettings, usedPercent, growthRate float64) compactionSettings {
+	var pressure float64
+	if usedPercent > t.diskUsage {
+		pressure = (usedPercent - t.diskUsage) / (100 - t.diskUsage)
+	}
+	if pressure > 1 {
+		pressure = 1
+	}
+
+	scale := func(value, floor uint64) uint64 {
+		if floor >= value {
+			return value
+		}
+		return value - uint64(float64(value-floor)*pressure)
+	}
+
+	minInterval := t.minSnapshotInterval
+	if minInterval > configured.SnapshotInterval {
+		minInterval = configured.SnapshotInterval
+	}
+
+	settings := compactionSettings{
+		TrailingLogs:      scale(configured.TrailingLogs, t.minTrailingLogs),
+		SnapshotThreshold: scale(configured.SnapshotThreshold, t.minSnapshotThreshold),
+		SnapshotInterval:  configured.SnapshotInterval - time.Duration(float64(configured.SnapshotInterval-minInterval)*pressure),
+	}
+
+	if growthRate > 0 {
+		interval := time.Duration(float64(settings.SnapshotThreshold) / growthRate * float64(time.Second))
+		if interval < minInterval {
+			interval = minInterval
+		}
+		if interval < settings.SnapshotInterval {
+			settings.SnapshotInterval = interval
+		}
+	}
+
+	return settings
+}
+
+// runCompactionTuner periodically adjusts the compaction settings of the
+// given raft instance until it is shut down.
+func (b *RaftBackend) runCompactionTuner(raftObj *raft.Raft, configured compactionSettings) {
+	ticker := time.NewTicker(compactionCheckInterval)
+	defer ticker.Stop()
+
+	current := configured
+	lastIndex := raftObj.LastIndex()
+	lastCheck := time.Now()
+
+	for range ticker.C {
+		if raftObj.State() == raft.Shutdown {
+			return
+		}
+
+		usage, err := disk.UsageWithContext(context.Background(), b.dataDir)
+		if err != nil {
+			b.logger.Warn("failed to read raft disk usage for adaptive compaction", "error", err)
+			continue
+		}
+
+		var growthRate float64
+		index := raftObj.LastIndex()
+		if elapsed := time.Since(lastCheck).Seconds(); elapsed > 0 && index > lastIndex {
+			growthRate = float64(index-lastIndex) / elapsed
+		}
+		lastIndex = index
+		lastCheck = time.Now()
+
+		metrics.SetGauge([]string{"raft-storage", "disk_usage_percent"}, float32(usage.UsedPercent))
+		metrics.SetGauge([]string{"raft-storage", "log_growth_rate"}, float32(growthRate))
+
+		settings := b.compactionTuner.settings(configured, usage.UsedPercent, growthRate)
+		if settings == current {
+			continue
+		}
+
+		if err := b.reloadCompactionSettings(raftObj, settings); err != nil {
+			b.logger.Error("failed to reload raft compaction settings", "error", err)
+			continue
+		}
+		current = settings
+
+		b.logger.Info("adjusted raft compaction settings",
+			"disk_usage_percent", usage.UsedPercent,
+			"log_growth_rate", growthRate,
+			"trailing_logs", settings.TrailingLogs,
+			"snapshot_threshold", settings.SnapshotThreshold,
+			"snapshot_interval", settings.SnapshotInterval)
+	}
+}
+
+// reloadCompactionSettings applies the compaction settings to the raft
+// instance, keeping its other reloadable settings.
+func (b *RaftBackend) reloadCompactionSettings(raftObj *raft.Raft, settings compactionSettings) error {
+	b.raftReloadLock.Lock()
+	defer b.raftReloadLock.Unlock()
+
+	newCfg := raftObj.ReloadableConfig()
+	newCfg.TrailingLogs = settings.TrailingLogs
+	newCfg.SnapshotThreshold = settings.SnapshotThreshold
+	newCfg.SnapshotInterval = settings.SnapshotInterval
+	return raftObj.ReloadConfig(newCfg)
+}
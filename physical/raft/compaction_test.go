@@ -0,0 +1,155 @@
+package raft
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestCompactionTuner_Parse(t *testing.T) {
+	tuner, err := parseCompactionTuner(map[string]string{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tuner != nil {
+		t.Fatal("expected adaptive compaction to be disabled by default")
+	}
+
+	tuner, err = parseCompactionTuner(map[string]string{
+		"adaptive_compaction":                        "true",
+		"adaptive_compaction_disk_usage":             "60",
+		"adaptive_compaction_min_trailing_logs":      "500",
+		"adaptive_compaction_min_snapshot_threshold": "200",
+		"adaptive_compaction_min_snapshot_interval":  "5s",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := compactionTuner{
+		diskUsage:            60,
+		minTrailingLogs:      500,
+		minSnapshotThreshold: 200,
+		minSnapshotInterval:  5 * time.Second,
+	}
+	if *tuner != expected {
+		t.Fatalf("bad tuner: %#v", tuner)
+	}
+
+	for _, conf := range []map[string]string{
+		{"adaptive_compaction": "yes please"},
+		{"adaptive_compaction": "true", "adaptive_compaction_disk_usage": "100"},
+		{"adaptive_compaction": "true", "adaptive_compaction_min_snapshot_threshold": "0"},
+		{"adaptive_compaction": "true", "adaptive_compaction_min_snapshot_interval": "0s"},
+	} {
+		if _, err := parseCompactionTuner(conf); err == nil {
+			t.Fatalf("expected an error for %v", conf)
+		}
+	}
+}
+
+func TestCompactionTuner_Settings(t *testing.T) {
+	tuner := &compactionTuner{
+		diskUsage:            60,
+		minTrailingLogs:      1000,
+		minSnapshotThreshold: 1000,
+		minSnapshotInterval:  10 * time.Second,
+	}
+	configured := compactionSettings{
+		TrailingLogs:      10000,
+		SnapshotThreshold: 8000,
+		SnapshotInterval:  120 * time.Second,
+	}
+
+	cases := []struct {
+		name        string
+		usedPercent float64
+		growthRate  float64
+		expected    compactionSettings
+	}{
+		{
+			name:        "below threshold",
+			usedPercent: 40,
+			expected:    configured,
+		},
+		{
+			name:        "half pressure",
+			usedPercent: 80,
+			expected: compactionSettings{
+				TrailingLogs:      5500,
+				SnapshotThreshold: 4500,
+				SnapshotInterval:  65 * time.Second,
+			},
+		},
+		{
+			name:        "full disk",
+			usedPercent: 100,
+			expected: compactionSettings{
+				TrailingLogs:      1000,
+				SnapshotThreshold: 1000,
+				SnapshotInterval:  10 * time.Second,
+			},
+		},
+		{
+			name:        "fast log growth",
+			usedPercent: 40,
+			growthRate:  400,
+			expected: compactionSettings{
+				TrailingLogs:      10000,
+				SnapshotThreshold: 8000,
+				SnapshotInterval:  20 * time.Second,
+			},
+		},
+		{
+			name:        "log growth bounded by floor",
+			usedPercent: 40,
+			growthRate:  8000,
+			expected: compactionSettings{
+				TrailingLogs:      10000,
+				SnapshotThreshold: 8000,
+				SnapshotInterval:  10 * time.Second,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := tuner.settings(configured, tc.usedPercent, tc.growthRate)
+			if actual != tc.expected {
+				t.Fatalf("expected %#v, got %#v", tc.expected, actual)
+			}
+		})
+	}
+
+	// Floors above the configured settings leave them untouched
+	low := compactionSettings{
+		TrailingLogs:      100,
+		SnapshotThreshold: 100,
+		SnapshotInterval:  5 * time.Second,
+	}
+	if actual := tuner.settings(low, 100, 0); actual != low {
+		t.Fatalf("expected %#v, got %#v", low, actual)
+	}
+}
+
+func TestCompactionTuner_Reload(t *testing.T) {
+	b, dir := GetRaft(t, true, true)
+	defer os.RemoveAll(dir)
+
+	timeouts := b.raft.ReloadableConfig()
+	settings := compactionSettings{
+		TrailingLogs:      1234,
+		SnapshotThreshold: 567,
+		SnapshotInterval:  30 * time.Second,
+	}
+	if err := b.reloadCompactionSettings(b.raft, settings); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := b.raft.ReloadableConfig()
+	if cfg.TrailingLogs != settings.TrailingLogs || cfg.SnapshotThreshold != settings.SnapshotThreshold || cfg.SnapshotInterval != settings.SnapshotInterval {
+		t.Fatalf("compaction settings not applied: %#v", cfg)
+	}
+	if cfg.HeartbeatTimeout != timeouts.HeartbeatTimeout || cfg.ElectionTimeout != timeouts.ElectionTimeout {
+		t.Fatalf("timeouts changed: %#v", cfg)
+	}
+}
@@ -141,6 +141,17 @@ type RaftBackend struct {
 	// performance.
 	maxEntrySize uint64
 
+	// commitBatcher coalesces concurrent puts and deletes into fewer raft
+	// logs. It is nil unless max_batch_latency is set.
+	commitBatcher *commitBatcher
+
+	// compactionTuner adjusts the snapshot settings of raft to the disk usage
+	// and the log growth rate. It is nil unless adaptive_compaction is set.
+	compactionTuner *compactionTuner
+
+	// raftReloadLock serializes the reloads of the raft configuration.
+	raftReloadLock sync.Mutex
+
 	// autopilot is the instance of raft-autopilot library implementation of the
 	// autopilot features. This will be instantiated in both leader and followers.
 	// However, only active node will have a "running" autopilot.
@@ -443,6 +454,33 @@ func NewRaftBackend(conf map[string]string, logger log.Logger) (physical.Backend
 		maxEntrySize = uint64(i)
 	}
 
+	var maxBatchLatency time.Duration
+	if latency := conf["max_batch_latency"]; latency != "" {
+		latency, err := parseutil.ParseDurationSecond(latency)
+		if err != nil {
+			return nil, fmt.Errorf("max_batch_latency does not parse as a duration: %w", err)
+		}
+		maxBatchLatency = latency
+	}
+
+	maxBatchEntries := defaultMaxBatchEntries
+	if maxBatchEntriesCfg := conf["max_batch_entries"]; len(maxBatchEntriesCfg) != 0 {
+		i, err := strconv.Atoi(maxBatchEntriesCfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse 'max_batch_entries': %w", err)
+		}
+		if i < 1 {
+			return nil, errors.New("'max_batch_entries' must be at least 1")
+		}
+
+		maxBatchEntries = i
+	}
+
+	compactionTuner, err := parseCompactionTuner(conf)
+	if err != nil {
+		return nil, err
+	}
+
 	var reconcileInterval time.Duration
 	if interval := conf["autopilot_reconcile_interval"]; interval != "" {
 		interval, err := parseutil.ParseDurationSecond(interval)
@@ -500,7 +538,7 @@ func NewRaftBackend(conf map[string]string, logger log.Logger) (physical.Backend
 		return nil, fmt.Errorf("setting %s to true is only valid if at least one retry_join stanza is specified", raftNonVoterConfigKey)
 	}
 
-	return &RaftBackend{
+	backend := &RaftBackend{
 		logger:                     logger,
 		fsm:                        fsm,
 		raftInitCh:                 make(chan struct{}),
@@ -518,7 +556,13 @@ func NewRaftBackend(conf map[string]string, logger log.Logger) (physical.Backend
 		nonVoter:                   nonVoter,
 		upgradeVersion:             upgradeVersion,
 		failGetInTxn:               new(uint32),
-	}, nil
+		compactionTuner:            compactionTuner,
+	}
+	if maxBatchLatency > 0 {
+		backend.commitBatcher = newCommitBatcher(backend, maxBatchLatency, maxBatchEntries)
+	}
+
+	return backend, nil
 }
 
 type snapshotStoreDelay struct {
@@ -1052,13 +1096,13 @@ func (b *RaftBackend) SetupCluster(ctx context.Context, opts SetupOpts) error {
 	close(b.raftInitCh)
 
 	reloadConfig := func() {
-		newCfg := raft.ReloadableConfig{
-			TrailingLogs:      raftConfig.TrailingLogs,
-			SnapshotInterval:  raftConfig.SnapshotInterval,
-			SnapshotThreshold: raftConfig.SnapshotThreshold,
-			HeartbeatTimeout:  raftConfig.HeartbeatTimeout / initialTimeoutMultiplier,
-			ElectionTimeout:   raftConfig.ElectionTimeout / initialTimeoutMultiplier,
-		}
+		b.raftReloadLock.Lock()
+		defer b.raftReloadLock.Unlock()
+
+		// Keep the compaction settings, which may have been adjusted since
+		newCfg := raftObj.ReloadableConfig()
+		newCfg.HeartbeatTimeout = raftConfig.HeartbeatTimeout / initialTimeoutMultiplier
+		newCfg.ElectionTimeout = raftConfig.ElectionTimeout / initialTimeoutMultiplier
 		err := raftObj.ReloadConfig(newCfg)
 		if err != nil {
 			b.logger.Error("failed to reload raft config to set lower timeouts", "error", err)
@@ -1108,6 +1152,14 @@ func (b *RaftBackend) SetupCluster(ctx context.Context, opts SetupOpts) error {
 		}
 	}
 
+	if b.compactionTuner != nil {
+		go b.runCompactionTuner(raftObj, compactionSettings{
+			TrailingLogs:      raftConfig.TrailingLogs,
+			SnapshotThreshold: raftConfig.SnapshotThreshold,
+			SnapshotInterval:  raftConfig.SnapshotInterval,
+		})
+	}
+
 	b.logger.Trace("finished setting up raft cluster")
 	return nil
 }
@@ -1183,6 +1235,20 @@ func (b *RaftBackend) Term() uint64 {
 	return indexState.Term
 }
 
+// HasLeader returns whether this node knows of a raft leader. It returns
+// true if the raft cluster is not set up on this node.
+func (b *RaftBackend) HasLeader() bool {
+	b.l.RLock()
+	defer b.l.RUnlock()
+
+	if b.raft == nil {
+		return true
+	}
+
+	addr, _ := b.raft.LeaderWithID()
+	return addr != ""
+}
+
 // RemovePeer removes the given peer ID from the raft cluster. If the node is
 // ourselves we will give up leadership.
 func (b *RaftBackend) RemovePeer(ctx context.Context, peerID string) error {
@@ -1451,17 +1517,20 @@ func (b *RaftBackend) Delete(ctx context.Context, path string) error {
 		return err
 	}
 
-	command := &LogData{
-		Operations: []*LogOperation{
-			{
-				OpType: deleteOp,
-				Key:    path,
-			},
-		},
+	op := &LogOperation{
+		OpType: deleteOp,
+		Key:    path,
 	}
 	b.permitPool.Acquire()
 	defer b.permitPool.Release()
 
+	if b.commitBatcher != nil {
+		return b.commitBatcher.apply(ctx, op)
+	}
+
+	command := &LogData{
+		Operations: []*LogOperation{op},
+	}
 	b.l.RLock()
 	err := b.applyLog(ctx, command)
 	b.l.RUnlock()
@@ -1511,19 +1580,22 @@ func (b *RaftBackend) Put(ctx context.Context, entry *physical.Entry) error {
 		return err
 	}
 
-	command := &LogData{
-		Operations: []*LogOperation{
-			{
-				OpType: putOp,
-				Key:    entry.Key,
-				Value:  entry.Value,
-			},
-		},
+	op := &LogOperation{
+		OpType: putOp,
+		Key:    entry.Key,
+		Value:  entry.Value,
 	}
 
 	b.permitPool.Acquire()
 	defer b.permitPool.Release()
 
+	if b.commitBatcher != nil {
+		return b.commitBatcher.apply(ctx, op)
+	}
+
+	command := &LogData{
+		Operations: []*LogOperation{op},
+	}
 	b.l.RLock()
 	err := b.applyLog(ctx, command)
 	b.l.RUnlock()
@@ -1639,7 +1711,9 @@ func (b *RaftBackend) Transaction(ctx context.Context, txns []*physical.TxnEntry
 
 // applyLog will take a given log command and apply it to the raft log. applyLog
 // doesn't return until the log has been applied to a quorum of servers and is
-// persisted to the local FSM. Caller should hold the backend's read lock.
+// persisted to the local FSM. The deadline of ctx, if any, bounds the time
+// waited for the log to be enqueued. Caller should hold the backend's read
+// lock.
 func (b *RaftBackend) applyLog(ctx context.Context, command *LogData) error {
 	if b.raft == nil {
 		return errors.New("raft storage is not initialized")
@@ -1660,14 +1734,22 @@ func (b *RaftBackend) applyLog(ctx context.Context, command *LogData) error {
 
 	defer metrics.AddSample([]string{"raft-storage", "entry_size"}, float32(cmdSize))
 
+	var timeout time.Duration
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+		if timeout <= 0 {
+			return context.DeadlineExceeded
+		}
+	}
+
 	var chunked bool
 	var applyFuture raft.ApplyFuture
 	switch {
 	case len(commandBytes) <= raftchunking.ChunkSize:
-		applyFuture = b.raft.Apply(commandBytes, 0)
+		applyFuture = b.raft.Apply(commandBytes, timeout)
 	default:
 		chunked = true
-		applyFuture = raftchunking.ChunkingApply(commandBytes, nil, 0, b.raft.ApplyLog)
+		applyFuture = raftchunking.ChunkingApply(commandBytes, nil, timeout, b.raft.ApplyLog)
 	}
 
 	if err := applyFuture.Error(); err != nil {
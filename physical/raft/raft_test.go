@@ -9,6 +9,7 @@ import (
 	"crypto/md5"
 	"encoding/base64"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"math/rand"
@@ -303,6 +304,66 @@ func TestRaft_Backend_LargeValue(t *testing.T) {
 	}
 }
 
+func TestRaft_Backend_CommitBatching(t *testing.T) {
+	b, dir := GetRaft(t, true, true)
+	defer os.RemoveAll(dir)
+	b.commitBatcher = newCommitBatcher(b, 50*time.Millisecond, 64)
+
+	physical.ExerciseBackend(t, b)
+
+	// Concurrent puts are coalesced into fewer raft logs
+	const count = 256
+	startIndex := b.raft.AppliedIndex()
+	errs := make(chan error, count)
+	for i := 0; i < count; i++ {
+		go func(i int) {
+			errs <- b.Put(context.Background(), &physical.Entry{
+				Key:   fmt.Sprintf("batch/%d", i),
+				Value: []byte(fmt.Sprintf("value-%d", i)),
+			})
+		}(i)
+	}
+	for i := 0; i < count; i++ {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if applied := b.raft.AppliedIndex() - startIndex; applied >= count {
+		t.Fatalf("expected puts to be batched, got %d raft logs for %d puts", applied, count)
+	}
+
+	for i := 0; i < count; i++ {
+		out, err := b.Get(context.Background(), fmt.Sprintf("batch/%d", i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out == nil || string(out.Value) != fmt.Sprintf("value-%d", i) {
+			t.Fatalf("bad value for key %d: %#v", i, out)
+		}
+	}
+
+	// A caller gives up on its batch once its context is done
+	b.commitBatcher = newCommitBatcher(b, time.Second, 64)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := b.Put(ctx, &physical.Entry{Key: "cancelled", Value: []byte("value")})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Fatalf("expected the put to return before its batch was committed, took %s", elapsed)
+	}
+
+	// An entry too large on its own fails without failing its batch
+	value := make([]byte, defaultMaxEntrySize+1)
+	rand.Read(value)
+	err = b.Put(context.Background(), &physical.Entry{Key: "large", Value: value})
+	if err == nil || !strings.Contains(err.Error(), physical.ErrValueTooLarge) {
+		t.Fatalf("expected %q, got %v", physical.ErrValueTooLarge, err)
+	}
+}
+
 // TestRaft_TransactionalBackend_GetTransactions tests that passing a slice of transactions to the
 // raft backend will populate values for any transactions that are Get operations.
 func TestRaft_TransactionalBackend_GetTransactions(t *testing.T) {
@@ -529,6 +529,30 @@ func (i *boltSnapshotInstaller) Install(filename string) error {
 	}
 }
 
+// ReadSnapshotEntries reads the storage entries of the snapshot data written
+// by WriteSnapshotToTemp, and calls fn for each entry whose key starts with
+// prefix. Values are returned as stored, so are still encrypted by the
+// barrier.
+func ReadSnapshotEntries(r io.Reader, prefix string, fn func(key string, value []byte) error) error {
+	protoReader := NewDelimitedReader(r, math.MaxInt32)
+
+	entry := new(pb.StorageEntry)
+	for {
+		if err := protoReader.ReadMsg(entry); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if !strings.HasPrefix(entry.Key, prefix) {
+			continue
+		}
+		if err := fn(entry.Key, entry.Value); err != nil {
+			return err
+		}
+	}
+}
+
 // snapshotName generates a name for the snapshot.
 func snapshotName(term, index uint64) string {
 	now := time.Now()
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	stdmysql "github.com/go-sql-driver/mysql"
+	"github.com/hashicorp/go-secure-stdlib/parseutil"
 	"github.com/hashicorp/go-secure-stdlib/strutil"
 	dbplugin "github.com/openbao/openbao/sdk/v2/database/dbplugin/v5"
 	"github.com/openbao/openbao/sdk/v2/database/helper/dbutil"
@@ -40,6 +41,7 @@ type MySQL struct {
 
 	usernameProducer        template.StringTemplate
 	defaultUsernameTemplate string
+	usernameCollisionCheck  bool
 }
 
 // New implements builtinplugins.BuiltinFactory
@@ -100,6 +102,11 @@ func (m *MySQL) Initialize(ctx context.Context, req dbplugin.InitializeRequest)
 		return dbplugin.InitializeResponse{}, fmt.Errorf("invalid username template: %w", err)
 	}
 
+	m.usernameCollisionCheck, err = parseutil.ParseBool(req.Config["username_collision_check"])
+	if err != nil {
+		return dbplugin.InitializeResponse{}, fmt.Errorf("failed to retrieve username_collision_check: %w", err)
+	}
+
 	err = m.mySQLConnectionProducer.Initialize(ctx, req.Config, req.VerifyConnection)
 	if err != nil {
 		return dbplugin.InitializeResponse{}, err
@@ -117,7 +124,7 @@ func (m *MySQL) NewUser(ctx context.Context, req dbplugin.NewUserRequest) (dbplu
 		return dbplugin.NewUserResponse{}, dbutil.ErrEmptyCreationStatement
 	}
 
-	username, err := m.usernameProducer.Generate(req.UsernameConfig)
+	username, err := m.generateUsername(ctx, req.UsernameConfig)
 	if err != nil {
 		return dbplugin.NewUserResponse{}, err
 	}
@@ -143,6 +150,28 @@ func (m *MySQL) NewUser(ctx context.Context, req dbplugin.NewUserRequest) (dbplu
 	return resp, nil
 }
 
+// generateUsername generates the username of a new user. If the collision
+// check is enabled, usernames of existing users are generated again.
+func (m *MySQL) generateUsername(ctx context.Context, metadata dbplugin.UsernameMetadata) (string, error) {
+	if !m.usernameCollisionCheck {
+		return m.usernameProducer.Generate(metadata)
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	db, err := m.getConnection(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return m.usernameProducer.GenerateUnique(metadata, func(username string) (bool, error) {
+		var exists bool
+		err := db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM mysql.user WHERE User = ?)", username).Scan(&exists)
+		return exists, err
+	})
+}
+
 func (m *MySQL) DeleteUser(ctx context.Context, req dbplugin.DeleteUserRequest) (dbplugin.DeleteUserResponse, error) {
 	// Grab the read lock
 	m.Lock()
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/go-secure-stdlib/parseutil"
 	"github.com/hashicorp/go-secure-stdlib/strutil"
 	_ "github.com/jackc/pgx/v4/stdlib"
 	"github.com/openbao/openbao/plugins/database/postgresql/scram"
@@ -80,6 +81,7 @@ type PostgreSQL struct {
 	*connutil.SQLConnectionProducer
 
 	usernameProducer       template.StringTemplate
+	usernameCollisionCheck bool
 	passwordAuthentication passwordAuthentication
 }
 
@@ -108,6 +110,11 @@ func (p *PostgreSQL) Initialize(ctx context.Context, req dbplugin.InitializeRequ
 		return dbplugin.InitializeResponse{}, fmt.Errorf("invalid username template: %w", err)
 	}
 
+	p.usernameCollisionCheck, err = parseutil.ParseBool(req.Config["username_collision_check"])
+	if err != nil {
+		return dbplugin.InitializeResponse{}, fmt.Errorf("failed to retrieve username_collision_check: %w", err)
+	}
+
 	passwordAuthenticationRaw, err := strutil.GetString(req.Config, "password_authentication")
 	if err != nil {
 		return dbplugin.InitializeResponse{}, fmt.Errorf("failed to retrieve password_authentication: %w", err)
@@ -272,6 +279,20 @@ func (p *PostgreSQL) changeUserExpiration(ctx context.Context, username string,
 	return tx.Commit()
 }
 
+// generateUsername generates the username of a new user. If the collision
+// check is enabled, usernames of existing roles are generated again.
+func (p *PostgreSQL) generateUsername(ctx context.Context, db *sql.DB, metadata dbplugin.UsernameMetadata) (string, error) {
+	if !p.usernameCollisionCheck {
+		return p.usernameProducer.Generate(metadata)
+	}
+
+	return p.usernameProducer.GenerateUnique(metadata, func(username string) (bool, error) {
+		var exists bool
+		err := db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM pg_roles WHERE rolname = $1)", username).Scan(&exists)
+		return exists, err
+	})
+}
+
 func (p *PostgreSQL) NewUser(ctx context.Context, req dbplugin.NewUserRequest) (dbplugin.NewUserResponse, error) {
 	if len(req.Statements.Commands) == 0 {
 		return dbplugin.NewUserResponse{}, dbutil.ErrEmptyCreationStatement
@@ -280,18 +301,18 @@ func (p *PostgreSQL) NewUser(ctx context.Context, req dbplugin.NewUserRequest) (
 	p.Lock()
 	defer p.Unlock()
 
-	username, err := p.usernameProducer.Generate(req.UsernameConfig)
+	db, err := p.getConnection(ctx)
 	if err != nil {
-		return dbplugin.NewUserResponse{}, err
+		return dbplugin.NewUserResponse{}, fmt.Errorf("unable to get connection: %w", err)
 	}
 
-	expirationStr := req.Expiration.Format(expirationFormat)
-
-	db, err := p.getConnection(ctx)
+	username, err := p.generateUsername(ctx, db, req.UsernameConfig)
 	if err != nil {
-		return dbplugin.NewUserResponse{}, fmt.Errorf("unable to get connection: %w", err)
+		return dbplugin.NewUserResponse{}, err
 	}
 
+	expirationStr := req.Expiration.Format(expirationFormat)
+
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		return dbplugin.NewUserResponse{}, fmt.Errorf("unable to start transaction: %w", err)
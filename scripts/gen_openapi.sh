@@ -56,17 +56,22 @@ bao auth enable "jwt"
 bao auth enable "kerberos"
 bao auth enable "kubernetes"
 bao auth enable "ldap"
+bao auth enable "peercred"
 bao auth enable "radius"
+bao auth enable "spiffe"
 bao auth enable "userpass"
 
 # Enable secrets plugins
 bao secrets enable "database"
+bao secrets enable "keymgmt"
 bao secrets enable "kubernetes"
 bao secrets enable -path="kv-v1/" -version=1 "kv"
 bao secrets enable -path="kv-v2/" -version=2 "kv"
 bao secrets enable "ldap"
+bao secrets enable "pairing"
 bao secrets enable "pki"
 bao secrets enable "rabbitmq"
+bao secrets enable "scm"
 bao secrets enable "ssh"
 bao secrets enable "totp"
 bao secrets enable "transit"
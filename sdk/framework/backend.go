@@ -228,6 +228,10 @@ func (b *Backend) HandleRequest(ctx context.Context, req *logical.Request) (*log
 	for k, v := range req.Data {
 		raw[k] = v
 		if !path.TakesArbitraryInput && path.Fields[k] == nil {
+			// The pagination of list responses is applied by the server
+			if req.Operation == logical.ListOperation && (k == "after" || k == "limit") {
+				continue
+			}
 			ignored = append(ignored, k)
 		}
 	}
@@ -243,9 +247,13 @@ func (b *Backend) HandleRequest(ctx context.Context, req *logical.Request) (*log
 	// Look up the callback for this operation, preferring the
 	// path.Operations definition if present.
 	var callback OperationFunc
+	dryRun := path.DryRun
+	deprecated := path.Deprecated
 
 	if path.Operations != nil {
 		if op, ok := path.Operations[req.Operation]; ok {
+			dryRun = dryRun || op.Properties().DryRun
+			deprecated = deprecated || op.Properties().Deprecated
 
 			// Check whether this operation should be forwarded
 			if sysView := b.System(); sysView != nil {
@@ -278,6 +286,10 @@ func (b *Backend) HandleRequest(ctx context.Context, req *logical.Request) (*log
 		return nil, logical.ErrUnsupportedOperation
 	}
 
+	if req.DryRun && !dryRun && req.Operation != logical.HelpOperation {
+		return logical.ErrorResponse("dry runs are not supported by this endpoint"), logical.ErrInvalidRequest
+	}
+
 	fd := FieldData{
 		Raw:    raw,
 		Schema: path.Fields,
@@ -288,6 +300,15 @@ func (b *Backend) HandleRequest(ctx context.Context, req *logical.Request) (*log
 		if err != nil {
 			return logical.ErrorResponse(fmt.Sprintf("Field validation failed: %s", err.Error())), nil
 		}
+
+		if deprecated {
+			logical.RecordDeprecatedFeature(ctx, logical.DeprecatedEndpoint, req.Path)
+		}
+		for k := range req.Data {
+			if schema := path.Fields[k]; schema != nil && schema.Deprecated {
+				logical.RecordDeprecatedFeature(ctx, logical.DeprecatedParameter, k)
+			}
+		}
 	}
 
 	resp, err := callback(ctx, req, &fd)
@@ -414,6 +435,17 @@ func (b *Backend) GetRandomReader() io.Reader {
 	return rand.Reader
 }
 
+// RestrictedCrypto returns whether the server runs in restricted crypto mode,
+// in which the backend must refuse to create or use keys of algorithms which
+// are not approved.
+func (b *Backend) RestrictedCrypto() bool {
+	if view, ok := b.System().(logical.RestrictedCryptoView); ok {
+		return view.RestrictedCrypto()
+	}
+
+	return false
+}
+
 // Logger can be used to get the logger. If no logger has been set,
 // the logs will be discarded.
 func (b *Backend) Logger() log.Logger {
@@ -690,8 +722,9 @@ type FieldSchema struct {
 	Default     interface{}
 	Description string
 
-	// The Required and Deprecated members are only used by openapi, and are not actually
-	// used by the framework.
+	// The Required member is only used by openapi, and is not actually used
+	// by the framework. The use of Deprecated fields in requests is reported
+	// with logical.RecordDeprecatedFeature.
 	Required   bool
 	Deprecated bool
 
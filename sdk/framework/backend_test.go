@@ -270,6 +270,129 @@ func TestBackendHandleRequest_Forwarding(t *testing.T) {
 	}
 }
 
+func TestBackendHandleRequest_DryRun(t *testing.T) {
+	callback := func(ctx context.Context, req *logical.Request, data *FieldData) (*logical.Response, error) {
+		return nil, nil
+	}
+
+	b := &Backend{
+		Paths: []*Path{
+			{
+				Pattern: "operations",
+				Operations: map[logical.Operation]OperationHandler{
+					logical.UpdateOperation: &PathOperation{
+						Callback: callback,
+						DryRun:   true,
+					},
+					logical.DeleteOperation: &PathOperation{
+						Callback: callback,
+					},
+				},
+			},
+			{
+				Pattern: "callbacks",
+				Callbacks: map[logical.Operation]OperationFunc{
+					logical.UpdateOperation: callback,
+				},
+				DryRun: true,
+			},
+			{
+				Pattern: "unsupported",
+				Callbacks: map[logical.Operation]OperationFunc{
+					logical.UpdateOperation: callback,
+				},
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		operation logical.Operation
+		path      string
+		supported bool
+	}{
+		"operation":             {logical.UpdateOperation, "operations", true},
+		"unsupported operation": {logical.DeleteOperation, "operations", false},
+		"callbacks":             {logical.UpdateOperation, "callbacks", true},
+		"unsupported path":      {logical.UpdateOperation, "unsupported", false},
+		"help":                  {logical.HelpOperation, "unsupported", true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp, err := b.HandleRequest(context.Background(), &logical.Request{
+				Operation: test.operation,
+				Path:      test.path,
+				DryRun:    true,
+			})
+			if test.supported {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, logical.ErrInvalidRequest)
+			require.True(t, resp.IsError())
+		})
+	}
+}
+
+func TestBackendHandleRequest_DeprecatedFeatures(t *testing.T) {
+	callback := func(ctx context.Context, req *logical.Request, data *FieldData) (*logical.Response, error) {
+		return nil, nil
+	}
+
+	b := &Backend{
+		Paths: []*Path{
+			{
+				Pattern: "roles/" + GenericNameRegex("name"),
+				Fields: map[string]*FieldSchema{
+					"name":     {Type: TypeString},
+					"ttl":      {Type: TypeDurationSecond},
+					"policies": {Type: TypeCommaStringSlice, Deprecated: true},
+				},
+				Operations: map[logical.Operation]OperationHandler{
+					logical.UpdateOperation: &PathOperation{Callback: callback},
+					logical.DeleteOperation: &PathOperation{Callback: callback, Deprecated: true},
+				},
+			},
+		},
+	}
+
+	var recorded []logical.DeprecatedFeature
+	ctx := logical.ContextWithDeprecationRecorder(context.Background(), func(f logical.DeprecatedFeature) {
+		recorded = append(recorded, f)
+	})
+
+	_, err := b.HandleRequest(ctx, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "roles/foo",
+		Data:      map[string]interface{}{"ttl": "1h"},
+	})
+	require.NoError(t, err)
+	require.Empty(t, recorded)
+
+	_, err = b.HandleRequest(ctx, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "roles/foo",
+		Data:      map[string]interface{}{"policies": "default"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []logical.DeprecatedFeature{{Kind: logical.DeprecatedParameter, Name: "policies"}}, recorded)
+
+	recorded = nil
+	_, err = b.HandleRequest(ctx, &logical.Request{
+		Operation: logical.DeleteOperation,
+		Path:      "roles/foo",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []logical.DeprecatedFeature{{Kind: logical.DeprecatedEndpoint, Name: "roles/foo"}}, recorded)
+
+	// Nothing is recorded without a recorder
+	_, err = b.HandleRequest(context.Background(), &logical.Request{
+		Operation: logical.DeleteOperation,
+		Path:      "roles/foo",
+	})
+	require.NoError(t, err)
+}
+
 func TestBackendHandleRequest_badwrite(t *testing.T) {
 	callback := func(ctx context.Context, req *logical.Request, data *FieldData) (*logical.Response, error) {
 		return &logical.Response{
@@ -125,6 +125,11 @@ type Path struct {
 	// be reflected in help and documentation.
 	Deprecated bool
 
+	// DryRun indicates that the create, update, patch and delete operations
+	// of this path support dry runs, see OperationProperties.DryRun. It is
+	// only needed by paths using Callbacks.
+	DryRun bool
+
 	// Help is text describing how to use this path. This will be used
 	// to auto-generate the help operation. The Path will automatically
 	// generate a parameter listing and URL structure based on the
@@ -185,6 +190,12 @@ type OperationProperties struct {
 	// Deprecated indicates that this operation should be avoided.
 	Deprecated bool
 
+	// DryRun indicates that this operation supports dry runs: when the
+	// request has DryRun set, it validates the request and reports what it
+	// would do without changing any state outside of the request storage,
+	// whose writes are discarded. Dry runs of other operations are refused.
+	DryRun bool
+
 	// The ForwardPerformance* parameters tell the router to unconditionally forward requests
 	// to this path if the processing node is a performance secondary/standby. This is generally
 	// *not* needed as there is already handling in place to automatically forward requests
@@ -297,6 +308,7 @@ type PathOperation struct {
 	Responses                   map[int][]Response
 	Unpublished                 bool
 	Deprecated                  bool
+	DryRun                      bool
 	ForwardPerformanceSecondary bool
 	ForwardPerformanceStandby   bool
 	DisplayAttrs                *DisplayAttributes
@@ -314,6 +326,7 @@ func (p *PathOperation) Properties() OperationProperties {
 		Examples:                    p.Examples,
 		Unpublished:                 p.Unpublished,
 		Deprecated:                  p.Deprecated,
+		DryRun:                      p.DryRun,
 		ForwardPerformanceSecondary: p.ForwardPerformanceSecondary,
 		ForwardPerformanceStandby:   p.ForwardPerformanceStandby,
 		DisplayAttrs:                p.DisplayAttrs,
@@ -871,9 +871,12 @@ func createCertificate(data *CreationBundle, randReader io.Reader, privateKeyGen
 	var err error
 	result := &ParsedCertBundle{}
 
-	serialNumber, err := GenerateSerialNumber()
-	if err != nil {
-		return nil, err
+	serialNumber := data.Params.SerialNumber
+	if serialNumber == nil {
+		serialNumber, err = GenerateSerialNumber()
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if err := privateKeyGenerator(data.Params.KeyType,
@@ -1175,9 +1178,12 @@ func signCertificate(data *CreationBundle, randReader io.Reader) (*ParsedCertBun
 
 	result := &ParsedCertBundle{}
 
-	serialNumber, err := GenerateSerialNumber()
-	if err != nil {
-		return nil, err
+	serialNumber := data.Params.SerialNumber
+	if serialNumber == nil {
+		serialNumber, err = GenerateSerialNumber()
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	subjKeyID, err := getSubjectKeyIDFromBundle(data)
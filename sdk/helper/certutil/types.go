@@ -66,6 +66,12 @@ const (
 	Ed25519PrivateKey PrivateKeyType = "ed25519"
 )
 
+// RestrictedCryptoApproved returns whether keys of the type may be created and
+// used when the server runs in restricted crypto mode.
+func (t PrivateKeyType) RestrictedCryptoApproved() bool {
+	return t != Ed25519PrivateKey
+}
+
 // TLSUsage controls whether the intended usage of a *tls.Config
 // returned from ParsedCertBundle.getTLSConfig is for server use,
 // client use, or both, which affects which values are set
@@ -800,6 +806,9 @@ type CreationParameters struct {
 
 	// The explicit SKID to use; especially useful for cross-signing.
 	SKID []byte
+
+	// The explicit serial number to use; a random one is generated if nil.
+	SerialNumber *big.Int
 }
 
 type CreationBundle struct {
@@ -34,6 +34,7 @@ const (
 	_                                 = iota
 	MarshalingTypeASN1 MarshalingType = iota
 	MarshalingTypeJWS
+	MarshalingTypeRaw
 )
 
 var (
@@ -79,5 +80,6 @@ var (
 	MarshalingTypeMap = map[string]MarshalingType{
 		"asn1": MarshalingTypeASN1,
 		"jws":  MarshalingTypeJWS,
+		"raw":  MarshalingTypeRaw,
 	}
 )
@@ -108,6 +108,10 @@ type SigningOptions struct {
 	Marshaling    MarshalingType
 	SaltLength    int
 	SigAlgorithm  string
+
+	// Deterministic requests ECDSA nonces generated as described by
+	// RFC 6979 instead of random ones.
+	Deterministic bool
 }
 
 type SigningResult struct {
@@ -177,6 +181,16 @@ func (kt KeyType) ImportPublicKeySupported() bool {
 	return false
 }
 
+// RestrictedCryptoApproved returns whether keys of the type may be created and
+// used when the server runs in restricted crypto mode.
+func (kt KeyType) RestrictedCryptoApproved() bool {
+	switch kt {
+	case KeyType_ChaCha20_Poly1305, KeyType_XChaCha20_Poly1305, KeyType_ED25519:
+		return false
+	}
+	return true
+}
+
 func (kt KeyType) String() string {
 	switch kt {
 	case KeyType_AES128_GCM96:
@@ -464,6 +478,10 @@ type Policy struct {
 
 	// Whether the key has been soft deleted.
 	SoftDeleted bool `json:"soft_deleted"`
+
+	// Whether the key is dedicated to sealing other clusters, in which case
+	// it may only be used for encryption and decryption.
+	SealProvider bool `json:"seal_provider"`
 }
 
 func (p *Policy) Lock(exclusive bool) {
@@ -1143,7 +1161,16 @@ func (p *Policy) SignWithOptions(ver int, context, input []byte, options *Signin
 			D: keyParams.EC_D,
 		}
 
-		r, s, err := ecdsa.Sign(rand.Reader, key, input)
+		var r, s *big.Int
+		if options.Deterministic {
+			hashFunc := HashFuncMap[hashAlgorithm]
+			if hashFunc == nil {
+				hashFunc = sha256.New
+			}
+			r, s, err = signECDSADeterministic(key, input, hashFunc)
+		} else {
+			r, s, err = ecdsa.Sign(rand.Reader, key, input)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -1159,8 +1186,8 @@ func (p *Policy) SignWithOptions(ver int, context, input []byte, options *Signin
 				return nil, err
 			}
 
-		case MarshalingTypeJWS:
-			// This is used by JWS
+		case MarshalingTypeJWS, MarshalingTypeRaw:
+			// This is used by JWS, and raw r||s signatures
 
 			// First we have to get the length of the curve in bytes. Although
 			// we only support 256 now, we'll do this in an agnostic way so we
@@ -1246,6 +1273,8 @@ func (p *Policy) SignWithOptions(ver int, context, input []byte, options *Signin
 		encoded = base64.StdEncoding.EncodeToString(sig)
 	case MarshalingTypeJWS:
 		encoded = base64.RawURLEncoding.EncodeToString(sig)
+	case MarshalingTypeRaw:
+		encoded = base64.StdEncoding.EncodeToString(sig)
 	}
 	res := &SigningResult{
 		Signature: p.getVersionPrefix(ver) + encoded,
@@ -1308,7 +1337,7 @@ func (p *Policy) VerifySignatureWithOptions(context, input []byte, sig string, o
 
 	var sigBytes []byte
 	switch marshaling {
-	case MarshalingTypeASN1:
+	case MarshalingTypeASN1, MarshalingTypeRaw:
 		sigBytes, err = base64.StdEncoding.DecodeString(splitVerSig[1])
 	case MarshalingTypeJWS:
 		sigBytes, err = base64.RawURLEncoding.DecodeString(splitVerSig[1])
@@ -1343,7 +1372,7 @@ func (p *Policy) VerifySignatureWithOptions(context, input []byte, sig string, o
 				return false, errutil.UserError{Err: "supplied signature contains extra data"}
 			}
 
-		case MarshalingTypeJWS:
+		case MarshalingTypeJWS, MarshalingTypeRaw:
 			paramLen := len(sigBytes) / 2
 			rb := sigBytes[:paramLen]
 			sb := sigBytes[paramLen:]
@@ -10,9 +10,12 @@ import (
 	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/sha256"
 	"crypto/x509"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"math/big"
 	mathrand "math/rand"
 	"reflect"
 	"strconv"
@@ -1067,3 +1070,68 @@ func isUnsupportedGoHashType(hashType HashType, err error) bool {
 
 	return false
 }
+
+func Test_SignDeterministicECDSA(t *testing.T) {
+	// Test vector from RFC 6979, Appendix A.2.5, with SHA-256 and the message
+	// "sample".
+	fromHex := func(s string) *big.Int {
+		v, ok := new(big.Int).SetString(s, 16)
+		if !ok {
+			t.Fatalf("bad hex %q", s)
+		}
+		return v
+	}
+	key := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     fromHex("60FED4BA255A9D31C961EB74C6356D68C049B8923B61FA6CE669622E60F29FB6"),
+			Y:     fromHex("7903FE1008B8BC99A41AE9E95628BC64F2F1B20C2D7E9F5177A3C294D4462299"),
+		},
+		D: fromHex("C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721"),
+	}
+	digest := sha256.Sum256([]byte("sample"))
+
+	r, s, err := signECDSADeterministic(key, digest[:], sha256.New)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Cmp(fromHex("EFD48B2AACB6A8FD1140DD9CD45E81D69D2C877B56AAF991C34D0EA84EAF3716")) != 0 {
+		t.Fatalf("bad r: %X", r)
+	}
+	if s.Cmp(fromHex("F7CB1C942D657C41D436C7A1B6E29F65F3E900DBB9AFF4064DC4AB2F843ACDA8")) != 0 {
+		t.Fatalf("bad s: %X", s)
+	}
+
+	// Signing through the policy is deterministic with raw marshaling.
+	p := &Policy{
+		Name:                 "test",
+		Type:                 KeyType_ECDSA_P256,
+		KeySize:              32,
+		LatestVersion:        1,
+		MinDecryptionVersion: 1,
+		Keys: map[string]KeyEntry{
+			"1": {EC_X: key.X, EC_Y: key.Y, EC_D: key.D},
+		},
+	}
+	options := &SigningOptions{
+		HashAlgorithm: HashTypeSHA2256,
+		Marshaling:    MarshalingTypeRaw,
+		Deterministic: true,
+	}
+	sig, err := p.SignWithOptions(0, nil, digest[:], options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(sig.Signature, "vault:v1:"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(raw) != 64 || new(big.Int).SetBytes(raw[:32]).Cmp(r) != 0 || new(big.Int).SetBytes(raw[32:]).Cmp(s) != 0 {
+		t.Fatalf("bad raw signature: %X", raw)
+	}
+
+	valid, err := p.VerifySignatureWithOptions(nil, digest[:], sig.Signature, options)
+	if err != nil || !valid {
+		t.Fatalf("signature did not verify: %v", err)
+	}
+}
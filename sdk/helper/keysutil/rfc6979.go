@@ -0,0 +1,90 @@
+package keysutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"errors"
+	"hash"
+	"math/big"
+)
+
+// signECDSADeterministic signs the digest with the nonce generated as
+// described by RFC 6979, Section 3.2, so that signing the same digest with
+// the same key always produces the same signature. The hash function is the
+// one of the HMAC_DRBG generating the nonce, normally the one of the digest.
+func signECDSADeterministic(key *ecdsa.PrivateKey, digest []byte, hashFunc func() hash.Hash) (*big.Int, *big.Int, error) {
+	curve := key.Curve
+	n := curve.Params().N
+	qlen := n.BitLen()
+	rolen := (qlen + 7) / 8
+
+	bits2int := func(b []byte) *big.Int {
+		v := new(big.Int).SetBytes(b)
+		if excess := len(b)*8 - qlen; excess > 0 {
+			v.Rsh(v, uint(excess))
+		}
+		return v
+	}
+	int2octets := func(v *big.Int) []byte {
+		out := make([]byte, rolen)
+		return v.FillBytes(out)
+	}
+	bits2octets := func(b []byte) []byte {
+		z := bits2int(b)
+		if z.Cmp(n) >= 0 {
+			z.Sub(z, n)
+		}
+		return int2octets(z)
+	}
+	mac := func(k []byte, parts ...[]byte) []byte {
+		h := hmac.New(hashFunc, k)
+		for _, part := range parts {
+			h.Write(part)
+		}
+		return h.Sum(nil)
+	}
+
+	hlen := hashFunc().Size()
+	v := make([]byte, hlen)
+	for i := range v {
+		v[i] = 0x01
+	}
+	k := make([]byte, hlen)
+
+	x := int2octets(key.D)
+	h1 := bits2octets(digest)
+	k = mac(k, v, []byte{0x00}, x, h1)
+	v = mac(k, v)
+	k = mac(k, v, []byte{0x01}, x, h1)
+	v = mac(k, v)
+
+	e := bits2int(digest)
+	for attempt := 0; attempt < 1024; attempt++ {
+		var t []byte
+		for len(t) < rolen {
+			v = mac(k, v)
+			t = append(t, v...)
+		}
+
+		nonce := bits2int(t[:rolen])
+		if nonce.Sign() > 0 && nonce.Cmp(n) < 0 {
+			rx, _ := curve.ScalarBaseMult(nonce.Bytes())
+			r := new(big.Int).Mod(rx, n)
+			if r.Sign() != 0 {
+				// s = k^-1 * (e + r*d) mod n
+				s := new(big.Int).Mul(r, key.D)
+				s.Add(s, e)
+				s.Mul(s, new(big.Int).ModInverse(nonce, n))
+				s.Mod(s, n)
+				if s.Sign() != 0 {
+					return r, s, nil
+				}
+			}
+		}
+
+		k = mac(k, v, []byte{0x00})
+		v = mac(k, v)
+	}
+
+	return nil, nil, errors.New("failed to generate a deterministic ECDSA nonce")
+}
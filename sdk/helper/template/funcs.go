@@ -5,6 +5,7 @@ package template
 
 import (
 	"crypto/sha256"
+	"encoding/base32"
 	"encoding/base64"
 	"fmt"
 	"strconv"
@@ -55,10 +56,45 @@ func truncateSHA256(maxLen int, str string) (string, error) {
 	return result, nil
 }
 
+// uniqueHashLen is the length of the hash appended by truncateUnique. Being
+// base32 encoded, it carries 40 bits of the hash.
+const uniqueHashLen = 8
+
+// truncateUnique truncates the string to the maximum length, replacing its end
+// with a base32 encoded hash of the whole string so that distinct strings
+// sharing a long prefix remain distinct once truncated.
+func truncateUnique(maxLen int, str string) (string, error) {
+	if maxLen <= uniqueHashLen {
+		return "", fmt.Errorf("max length must be > %d but was %d", uniqueHashLen, maxLen)
+	}
+
+	if len(str) <= maxLen {
+		return str, nil
+	}
+
+	sum := sha256.Sum256([]byte(str))
+	hash := encodeBase32(string(sum[:]))
+	return str[:maxLen-uniqueHashLen] + hash[:uniqueHashLen], nil
+}
+
 func hashSHA256(str string) string {
 	return fmt.Sprintf("%x", sha256.Sum256([]byte(str)))
 }
 
+func hashPrefix(length int, str string) (string, error) {
+	hash := hashSHA256(str)
+	if length <= 0 || length > len(hash) {
+		return "", fmt.Errorf("hash prefix length must be between 1 and %d but was %d", len(hash), length)
+	}
+	return hash[:length], nil
+}
+
+// encodeBase32 encodes the string as lowercase base32 without padding, which
+// is accepted in the identifiers of most systems.
+func encodeBase32(str string) string {
+	return strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte(str)))
+}
+
 func encodeBase64(str string) string {
 	return base64.StdEncoding.EncodeToString([]byte(str))
 }
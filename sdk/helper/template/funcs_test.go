@@ -187,6 +187,84 @@ func TestTruncateSHA256(t *testing.T) {
 	}
 }
 
+func TestTruncateUnique(t *testing.T) {
+	type testCase struct {
+		maxLen    int
+		input     string
+		expected  string
+		expectErr bool
+	}
+
+	tests := map[string]testCase{
+		"zero max length": {
+			maxLen:    0,
+			input:     "thisisareallylongstring",
+			expectErr: true,
+		},
+		"8 max length": {
+			maxLen:    8,
+			input:     "thisisareallylongstring",
+			expectErr: true,
+		},
+		"half max length": {
+			maxLen:   12,
+			input:    "thisisareallylongstring",
+			expected: "thisppbzx2vg",
+		},
+		"same prefix": {
+			maxLen:   12,
+			input:    "thisisareallylongstrinG",
+			expected: "thismhm4z7gh",
+		},
+		"max length one less than length": {
+			maxLen:   22,
+			input:    "thisisareallylongstring",
+			expected: "thisisareallylppbzx2vg",
+		},
+		"max length equals string length": {
+			maxLen:   23,
+			input:    "thisisareallylongstring",
+			expected: "thisisareallylongstring",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			actual, err := truncateUnique(test.maxLen, test.input)
+			if test.expectErr && err == nil {
+				t.Fatalf("err expected, got nil")
+			}
+			if !test.expectErr && err != nil {
+				t.Fatalf("no error expected, got: %s", err)
+			}
+
+			require.Equal(t, test.expected, actual)
+		})
+	}
+}
+
+func TestHashPrefix(t *testing.T) {
+	actual, err := hashPrefix(6, "foobar")
+	require.NoError(t, err)
+	require.Equal(t, "c3ab8f", actual)
+
+	actual, err = hashPrefix(64, "foobar")
+	require.NoError(t, err)
+	require.Equal(t, hashSHA256("foobar"), actual)
+
+	_, err = hashPrefix(0, "foobar")
+	require.Error(t, err)
+
+	_, err = hashPrefix(65, "foobar")
+	require.Error(t, err)
+}
+
+func TestBase32(t *testing.T) {
+	require.Equal(t, "", encodeBase32(""))
+	require.Equal(t, "mzxw6", encodeBase32("foo"))
+	require.Equal(t, "nbswy3dpeb3w64tmmq", encodeBase32("hello world"))
+}
+
 func TestSHA256(t *testing.T) {
 	type testCase struct {
 		input    string
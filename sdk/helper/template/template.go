@@ -65,14 +65,28 @@ func Function(name string, f interface{}) Opt {
 //   - Performs a string find & replace
 //     Example: {{ .DisplayName | replace - _ }}
 //
+// - truncate_unique
+//   - Truncates the previous value to the specified length. If the original length is greater than the length
+//     specified, the last 8 characters are replaced by a base32 hash of the whole value, so that values sharing a
+//     long prefix remain distinct. Must include a maximum length greater than 8.
+//     Example: {{ .RoleName | truncate_unique 20 }}
+//
 // - sha256
 //   - SHA256 hashes the previous value.
 //     Example: {{ .DisplayName | sha256 }}
 //
+// - hash_prefix
+//   - Provides the first characters of the hex encoded SHA256 hash of the previous value. Must include a length.
+//     Example: {{ .DisplayName | hash_prefix 6 }}
+//
 // - base64
 //   - base64 encodes the previous value.
 //     Example: {{ .DisplayName | base64 }}
 //
+// - base32
+//   - base32 encodes the previous value, in lowercase and without padding.
+//     Example: {{ .DisplayName | base32 }}
+//
 // - unix_time
 //   - Provides the current unix time in seconds.
 //     Example: {{ unix_time }}
@@ -102,11 +116,14 @@ func NewTemplate(opts ...Opt) (up StringTemplate, err error) {
 			"random":          base62.Random,
 			"truncate":        truncate,
 			"truncate_sha256": truncateSHA256,
+			"truncate_unique": truncateUnique,
 			"uppercase":       uppercase,
 			"lowercase":       lowercase,
 			"replace":         replace,
 			"sha256":          hashSHA256,
+			"hash_prefix":     hashPrefix,
 			"base64":          encodeBase64,
+			"base32":          encodeBase32,
 
 			"unix_time":        unixTime,
 			"unix_time_millis": unixTimeMillis,
@@ -153,3 +170,31 @@ func (up StringTemplate) Generate(data interface{}) (string, error) {
 
 	return str.String(), nil
 }
+
+// MaxUniqueAttempts is the number of times GenerateUnique generates a string
+// before giving up.
+const MaxUniqueAttempts = 10
+
+// GenerateUnique generates strings based on the provided template until the
+// exists callback reports one as unused, which lets callers avoid colliding
+// with names already present in a target system. The template must contain a
+// source of randomness, such as random or uuid, for retries to produce
+// different strings.
+func (up StringTemplate) GenerateUnique(data interface{}, exists func(string) (bool, error)) (string, error) {
+	for i := 0; i < MaxUniqueAttempts; i++ {
+		str, err := up.Generate(data)
+		if err != nil {
+			return "", err
+		}
+
+		found, err := exists(str)
+		if err != nil {
+			return "", fmt.Errorf("unable to check for collisions: %w", err)
+		}
+		if !found {
+			return str, nil
+		}
+	}
+
+	return "", fmt.Errorf("failed to generate a unique string after %d attempts", MaxUniqueAttempts)
+}
@@ -207,3 +207,37 @@ func TestBadConstructorArguments(t *testing.T) {
 		require.Equal(t, "", str)
 	})
 }
+
+func TestGenerateUnique(t *testing.T) {
+	st, err := NewTemplate(Template("{{random 8}}"))
+	require.NoError(t, err)
+
+	t.Run("retries on collisions", func(t *testing.T) {
+		var seen []string
+		str, err := st.GenerateUnique(nil, func(s string) (bool, error) {
+			seen = append(seen, s)
+			return len(seen) < 3, nil
+		})
+		require.NoError(t, err)
+		require.Len(t, seen, 3)
+		require.Equal(t, seen[2], str)
+	})
+
+	t.Run("gives up", func(t *testing.T) {
+		attempts := 0
+		str, err := st.GenerateUnique(nil, func(string) (bool, error) {
+			attempts++
+			return true, nil
+		})
+		require.Error(t, err)
+		require.Equal(t, "", str)
+		require.Equal(t, MaxUniqueAttempts, attempts)
+	})
+
+	t.Run("check error", func(t *testing.T) {
+		_, err := st.GenerateUnique(nil, func(string) (bool, error) {
+			return false, fmt.Errorf("an error!")
+		})
+		require.Error(t, err)
+	})
+}
@@ -93,6 +93,11 @@ type Auth struct {
 	// The set of CIDRs that this token can be used with
 	BoundCIDRs []*sockaddr.SockAddrMarshaler `json:"bound_cidrs"`
 
+	// BoundCertThumbprint binds the token to the client certificate with
+	// this thumbprint. It is set by the core, when the auth method is tuned
+	// to bind tokens to the client certificate of the login request.
+	BoundCertThumbprint string `json:"bound_cert_thumbprint,omitempty"`
+
 	// CreationPath is a path that the backend can return to use in the lease.
 	// This is currently only supported for the token store where roles may
 	// change the perceived path of the lease, even though they don't change
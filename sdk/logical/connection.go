@@ -18,4 +18,18 @@ type Connection struct {
 
 	// ConnState is the TLS connection state if applicable.
 	ConnState *tls.ConnectionState `sentinel:""`
+
+	// PeerCredentials holds the credentials of the process on the other end
+	// of the connection, as reported by the kernel. It is only set for
+	// requests received over a Unix domain socket on platforms supporting
+	// peer credential retrieval.
+	PeerCredentials *PeerCredentials `json:"peer_credentials,omitempty" sentinel:""`
+}
+
+// PeerCredentials are the kernel-verified credentials of a local peer
+// process.
+type PeerCredentials struct {
+	PID int32  `json:"pid"`
+	UID uint32 `json:"uid"`
+	GID uint32 `json:"gid"`
 }
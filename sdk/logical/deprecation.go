@@ -0,0 +1,36 @@
+package logical
+
+import "context"
+
+// Kinds of the deprecated features reported by backends with
+// RecordDeprecatedFeature.
+const (
+	DeprecatedParameter = "parameter"
+	DeprecatedEndpoint  = "endpoint"
+	DeprecatedKeyType   = "key_type"
+)
+
+// DeprecatedFeature is a deprecated feature used by a request, such as a
+// deprecated parameter or key type.
+type DeprecatedFeature struct {
+	Kind string
+	Name string
+}
+
+type deprecationRecorderKey struct{}
+
+// ContextWithDeprecationRecorder returns a context whose deprecated features
+// reported with RecordDeprecatedFeature are passed to record.
+func ContextWithDeprecationRecorder(ctx context.Context, record func(DeprecatedFeature)) context.Context {
+	return context.WithValue(ctx, deprecationRecorderKey{}, record)
+}
+
+// RecordDeprecatedFeature reports that the request of ctx uses a deprecated
+// feature, so that operators can find the features to migrate away from
+// before they are removed. It is a no-op when nothing records them, as for
+// external plugins.
+func RecordDeprecatedFeature(ctx context.Context, kind, name string) {
+	if record, ok := ctx.Value(deprecationRecorderKey{}).(func(DeprecatedFeature)); ok && record != nil {
+		record(DeprecatedFeature{Kind: kind, Name: name})
+	}
+}
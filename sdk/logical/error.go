@@ -54,6 +54,22 @@ var (
 	// Error indicating that the requested path used to serve a purpose in older
 	// versions, but the functionality has now been removed
 	ErrPathFunctionalityRemoved = errors.New("functionality on this path has been removed")
+
+	// ErrDeleteConfirmationRequired is returned when a destructive operation
+	// on a path protected against deletion was not confirmed.
+	ErrDeleteConfirmationRequired = errors.New("deletion confirmation required")
+
+	// ErrRequestTooLarge is returned when the body of a request exceeds the
+	// maximum request size of its mount.
+	ErrRequestTooLarge = errors.New("request too large")
+
+	// ErrRequestTimeout is returned when a request was not handled within the
+	// request timeout of its mount.
+	ErrRequestTimeout = errors.New("request timed out")
+
+	// ErrConcurrencyLimitExceeded is returned when a request is rejected as
+	// its mount is handling its maximum number of concurrent requests.
+	ErrConcurrencyLimitExceeded = errors.New("concurrency limit exceeded")
 )
 
 type HTTPCodedError interface {
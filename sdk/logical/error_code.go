@@ -0,0 +1,144 @@
+package logical
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/hashicorp/errwrap"
+	"github.com/openbao/openbao/sdk/v2/helper/consts"
+)
+
+// ErrorCode is a stable, machine-readable identifier of the kind of an
+// error. It is returned in the error_code field of API error responses,
+// alongside the human-readable errors, so that clients can branch on it
+// instead of matching error messages. Codes are never renamed or reused.
+type ErrorCode string
+
+const (
+	ErrorCodeInternal                     ErrorCode = "internal_error"
+	ErrorCodeInvalidRequest               ErrorCode = "invalid_request"
+	ErrorCodePermissionDenied             ErrorCode = "permission_denied"
+	ErrorCodeInvalidCredentials           ErrorCode = "invalid_credentials"
+	ErrorCodeNotFound                     ErrorCode = "not_found"
+	ErrorCodeUnsupportedOperation         ErrorCode = "unsupported_operation"
+	ErrorCodeUnsupportedPath              ErrorCode = "unsupported_path"
+	ErrorCodeInvalidWrappingToken         ErrorCode = "invalid_wrapping_token"
+	ErrorCodeUpstreamRateLimited          ErrorCode = "upstream_rate_limited"
+	ErrorCodeRateLimitQuotaExceeded       ErrorCode = "rate_limit_quota_exceeded"
+	ErrorCodeLeaseCountQuotaExceeded      ErrorCode = "lease_count_quota_exceeded"
+	ErrorCodeConcurrencyLimitExceeded     ErrorCode = "concurrency_limit_exceeded"
+	ErrorCodePathFunctionalityRemoved     ErrorCode = "path_functionality_removed"
+	ErrorCodeDeleteConfirmationRequired   ErrorCode = "delete_confirmation_required"
+	ErrorCodeBannerAcknowledgmentRequired ErrorCode = "banner_acknowledgment_required"
+	ErrorCodeRequestTooLarge              ErrorCode = "request_too_large"
+	ErrorCodeRequestTimeout               ErrorCode = "request_timeout"
+	ErrorCodeSealed                       ErrorCode = "sealed"
+	ErrorCodeAPILocked                    ErrorCode = "api_locked"
+	ErrorCodeUnavailable                  ErrorCode = "unavailable"
+)
+
+// errorCodes maps the errors returned by core and backends to their codes,
+// in the order they are checked.
+var errorCodes = []struct {
+	err  error
+	code ErrorCode
+}{
+	{consts.ErrSealed, ErrorCodeSealed},
+	{consts.ErrAPILocked, ErrorCodeAPILocked},
+	{ErrPermissionDenied, ErrorCodePermissionDenied},
+	{consts.ErrInvalidWrappingToken, ErrorCodeInvalidWrappingToken},
+	{ErrUnsupportedOperation, ErrorCodeUnsupportedOperation},
+	{ErrUnsupportedPath, ErrorCodeUnsupportedPath},
+	{ErrInvalidCredentials, ErrorCodeInvalidCredentials},
+	{ErrInvalidRequest, ErrorCodeInvalidRequest},
+	{ErrUpstreamRateLimited, ErrorCodeUpstreamRateLimited},
+	{ErrRateLimitQuotaExceeded, ErrorCodeRateLimitQuotaExceeded},
+	{ErrLeaseCountQuotaExceeded, ErrorCodeLeaseCountQuotaExceeded},
+	{ErrPathFunctionalityRemoved, ErrorCodePathFunctionalityRemoved},
+	{ErrRelativePath, ErrorCodeInvalidRequest},
+	{ErrDeleteConfirmationRequired, ErrorCodeDeleteConfirmationRequired},
+	{ErrRequestTooLarge, ErrorCodeRequestTooLarge},
+	{ErrRequestTimeout, ErrorCodeRequestTimeout},
+	{ErrConcurrencyLimitExceeded, ErrorCodeConcurrencyLimitExceeded},
+}
+
+// CodedErrorResponse is used to format an error response with an error code
+// more specific than the one derived from the error returned along with it.
+func CodedErrorResponse(code ErrorCode, text string, vargs ...interface{}) *Response {
+	resp := ErrorResponse(text, vargs...)
+	resp.Data["error_code"] = string(code)
+	return resp
+}
+
+type codedErr struct {
+	code ErrorCode
+	err  error
+}
+
+func (e *codedErr) Error() string {
+	return e.err.Error()
+}
+
+func (e *codedErr) Unwrap() error {
+	return e.err
+}
+
+// WithErrorCode returns err, with code as its error code.
+func WithErrorCode(code ErrorCode, err error) error {
+	if err == nil || code == "" {
+		return err
+	}
+	return &codedErr{code: code, err: err}
+}
+
+// ErrorCodeOf returns the error code of err, set by WithErrorCode or derived
+// from the well-known errors it contains. It returns an empty code if err is
+// not a known error.
+func ErrorCodeOf(err error) ErrorCode {
+	if err == nil {
+		return ""
+	}
+
+	var coded *codedErr
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+	if errwrap.ContainsType(err, new(StatusBadRequest)) {
+		return ErrorCodeInvalidRequest
+	}
+	for _, ec := range errorCodes {
+		if errors.Is(err, ec.err) || errwrap.Contains(err, ec.err.Error()) {
+			return ec.code
+		}
+	}
+	return ""
+}
+
+// ErrorCodeForStatus returns the generic error code of an HTTP status code,
+// for errors without a more specific code.
+func ErrorCodeForStatus(status int) ErrorCode {
+	switch status {
+	case http.StatusBadRequest:
+		return ErrorCodeInvalidRequest
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrorCodePermissionDenied
+	case http.StatusNotFound:
+		return ErrorCodeNotFound
+	case http.StatusMethodNotAllowed:
+		return ErrorCodeUnsupportedOperation
+	case http.StatusPreconditionFailed:
+		return ErrorCodeDeleteConfirmationRequired
+	case http.StatusRequestEntityTooLarge:
+		return ErrorCodeRequestTooLarge
+	case http.StatusTooManyRequests:
+		return ErrorCodeRateLimitQuotaExceeded
+	case http.StatusServiceUnavailable:
+		return ErrorCodeUnavailable
+	case http.StatusGatewayTimeout:
+		return ErrorCodeRequestTimeout
+	}
+	if status >= http.StatusInternalServerError {
+		return ErrorCodeInternal
+	}
+	return ""
+}
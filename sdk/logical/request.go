@@ -70,6 +70,11 @@ type Request struct {
 	// Id is the uuid associated with each request
 	ID string `json:"id" structs:"id" mapstructure:"id" sentinel:""`
 
+	// CorrelationID ties the request to the client request it originates
+	// from, across request forwarding. It is given by the client or generated
+	// by the server receiving the request.
+	CorrelationID string `json:"correlation_id" structs:"correlation_id" mapstructure:"correlation_id" sentinel:""`
+
 	// If set, the name given to the replication secondary where this request
 	// originated
 	ReplicationCluster string `json:"replication_cluster" structs:"replication_cluster" mapstructure:"replication_cluster" sentinel:""`
@@ -164,6 +169,33 @@ type Request struct {
 	// soft-mandatory Sentinel policies
 	PolicyOverride bool `json:"policy_override" structs:"policy_override" mapstructure:"policy_override"`
 
+	// DeleteConfirmed is set by the core when the client confirmed a
+	// destructive operation on a path protected against deletion. Backends
+	// protecting their own paths return ErrDeleteConfirmationRequired for
+	// such operations unless it is set.
+	DeleteConfirmed bool `json:"delete_confirmed" structs:"delete_confirmed" mapstructure:"delete_confirmed"`
+
+	// DeleteConfirmation is set by clients confirming a destructive operation
+	// on a path protected against deletion, either with the token returned
+	// when the operation was first refused or with "true"
+	DeleteConfirmation string `json:"delete_confirmation" structs:"delete_confirmation" mapstructure:"delete_confirmation" sentinel:""`
+
+	// AcknowledgedBanners holds the names of the login banners acknowledged
+	// by the client, supplied over the API as part of the
+	// X-OpenBao-Acknowledge-Banners header
+	AcknowledgedBanners []string `json:"acknowledged_banners" structs:"acknowledged_banners" mapstructure:"acknowledged_banners" sentinel:""`
+
+	// IdempotencyKey is set by clients retrying write requests, so that the
+	// response to a request already handled is returned instead of handling it
+	// again
+	IdempotencyKey string `json:"idempotency_key" structs:"idempotency_key" mapstructure:"idempotency_key" sentinel:""`
+
+	// DryRun is set by clients wanting a write request to be validated
+	// without being persisted. Backends only receive it on operations
+	// declaring support for dry runs, along with a storage discarding writes,
+	// and must not change any external or in-memory state when it is set.
+	DryRun bool `json:"dry_run" structs:"dry_run" mapstructure:"dry_run"`
+
 	// Whether the request is unauthenticated, as in, had no client token
 	// attached. Useful in some situations where the client token is not made
 	// accessible.
@@ -193,6 +225,11 @@ type Request struct {
 	// that generated this logical.Request object, such as the request body.
 	HTTPRequest *http.Request `json:"-" sentinel:""`
 
+	// RequestSize is the size in bytes of the body of the HTTP request that
+	// generated this logical.Request object, against which the router
+	// enforces the maximum request size of the mount.
+	RequestSize int64 `json:"-" sentinel:""`
+
 	// ResponseWriter if set can be used to stream a response value to the http
 	// request that generated this logical.Request object.
 	ResponseWriter *HTTPResponseWriter `json:"-" sentinel:""`
@@ -97,8 +97,19 @@ func (r *Response) AddWarning(warning string) {
 
 // IsError returns true if this response seems to indicate an error.
 func (r *Response) IsError() bool {
-	// If the response data contains only an 'error' element, or an 'error' and a 'data' element only
-	return r != nil && r.Data != nil && r.Data["error"] != nil && (len(r.Data) == 1 || (r.Data["data"] != nil && len(r.Data) == 2))
+	// If the response data contains only an 'error' element, optionally along
+	// with a 'data' and an 'error_code' element
+	if r == nil || r.Data == nil || r.Data["error"] == nil {
+		return false
+	}
+	n := 1
+	if r.Data["data"] != nil {
+		n++
+	}
+	if _, ok := r.Data["error_code"].(string); ok {
+		n++
+	}
+	return len(r.Data) == n
 }
 
 func (r *Response) Error() error {
@@ -136,11 +136,23 @@ func RespondErrorCommon(req *Request, resp *Response, err error) (int, error) {
 			statusCode = http.StatusBadRequest
 		case errwrap.Contains(err, ErrInvalidCredentials.Error()):
 			statusCode = http.StatusBadRequest
+		case errwrap.Contains(err, ErrDeleteConfirmationRequired.Error()):
+			statusCode = http.StatusPreconditionFailed
+		case errwrap.Contains(err, ErrRequestTooLarge.Error()):
+			statusCode = http.StatusRequestEntityTooLarge
+		case errwrap.Contains(err, ErrRequestTimeout.Error()):
+			statusCode = http.StatusGatewayTimeout
+		case errwrap.Contains(err, ErrConcurrencyLimitExceeded.Error()):
+			statusCode = http.StatusTooManyRequests
 		}
 	}
 
 	if resp != nil && resp.IsError() {
-		err = fmt.Errorf("%s", resp.Data["error"].(string))
+		code := ErrorCodeOf(err)
+		if respCode, ok := resp.Data["error_code"].(string); ok && respCode != "" {
+			code = ErrorCode(respCode)
+		}
+		err = WithErrorCode(code, fmt.Errorf("%s", resp.Data["error"].(string)))
 	}
 
 	return statusCode, err
@@ -184,12 +196,14 @@ func RespondError(w http.ResponseWriter, status int, err error) {
 	w.WriteHeader(status)
 
 	type ErrorResponse struct {
-		Errors []string `json:"errors"`
+		Errors    []string  `json:"errors"`
+		ErrorCode ErrorCode `json:"error_code,omitempty"`
 	}
 	resp := &ErrorResponse{Errors: make([]string, 0, 1)}
 	if err != nil {
 		resp.Errors = append(resp.Errors, err.Error())
 	}
+	resp.ErrorCode = responseErrorCode(status, err)
 
 	enc := json.NewEncoder(w)
 	enc.Encode(resp)
@@ -202,15 +216,26 @@ func RespondErrorAndData(w http.ResponseWriter, status int, data interface{}, er
 	w.WriteHeader(status)
 
 	type ErrorAndDataResponse struct {
-		Errors []string    `json:"errors"`
-		Data   interface{} `json:"data""`
+		Errors    []string    `json:"errors"`
+		ErrorCode ErrorCode   `json:"error_code,omitempty"`
+		Data      interface{} `json:"data""`
 	}
 	resp := &ErrorAndDataResponse{Errors: make([]string, 0, 1)}
 	if err != nil {
 		resp.Errors = append(resp.Errors, err.Error())
 	}
+	resp.ErrorCode = responseErrorCode(status, err)
 	resp.Data = data
 
 	enc := json.NewEncoder(w)
 	enc.Encode(resp)
 }
+
+// responseErrorCode returns the error code of err, or the generic error code
+// of status if err has none.
+func responseErrorCode(status int, err error) ErrorCode {
+	if code := ErrorCodeOf(err); code != "" {
+		return code
+	}
+	return ErrorCodeForStatus(status)
+}
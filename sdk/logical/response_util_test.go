@@ -4,7 +4,11 @@
 package logical
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 )
@@ -104,3 +108,73 @@ func TestResponseUtil_RespondErrorCommon_basic(t *testing.T) {
 		})
 	}
 }
+
+func TestResponseUtil_RespondError_errorCode(t *testing.T) {
+	testCases := []struct {
+		title        string
+		req          *Request
+		resp         *Response
+		respErr      error
+		expectedCode ErrorCode
+	}{
+		{
+			title:        "Permission denied",
+			respErr:      fmt.Errorf("missing client token: %w", ErrPermissionDenied),
+			expectedCode: ErrorCodePermissionDenied,
+		},
+		{
+			title:   "Error response",
+			respErr: ErrInvalidRequest,
+			resp: &Response{
+				Data: map[string]interface{}{
+					"error": "missing name",
+				},
+			},
+			expectedCode: ErrorCodeInvalidRequest,
+		},
+		{
+			title:        "Coded error response",
+			respErr:      ErrInvalidRequest,
+			resp:         CodedErrorResponse(ErrorCodeNotFound, "unknown role %q", "app"),
+			expectedCode: ErrorCodeNotFound,
+		},
+		{
+			title: "Read not found",
+			req: &Request{
+				Operation: ReadOperation,
+			},
+			expectedCode: ErrorCodeNotFound,
+		},
+		{
+			title:        "Unknown error",
+			respErr:      errors.New("storage failure"),
+			expectedCode: ErrorCodeInternal,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.title, func(t *testing.T) {
+			status, err := RespondErrorCommon(tc.req, tc.resp, tc.respErr)
+			w := httptest.NewRecorder()
+			RespondError(w, status, err)
+
+			var body struct {
+				Errors    []string  `json:"errors"`
+				ErrorCode ErrorCode `json:"error_code"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if body.ErrorCode != tc.expectedCode {
+				t.Fatalf("Expected (%s) error code, got (%s)", tc.expectedCode, body.ErrorCode)
+			}
+		})
+	}
+
+	if !CodedErrorResponse(ErrorCodeNotFound, "unknown role").IsError() {
+		t.Fatal("Expected a coded error response to be an error")
+	}
+	if code := ErrorCodeForStatus(http.StatusTooManyRequests); code != ErrorCodeRateLimitQuotaExceeded {
+		t.Fatalf("Expected (%s) error code, got (%s)", ErrorCodeRateLimitQuotaExceeded, code)
+	}
+}
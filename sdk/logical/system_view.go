@@ -111,6 +111,24 @@ type ExtendedSystemView interface {
 	// APILockShouldBlockRequest returns whether a namespace for the requested
 	// mount is locked and should be blocked
 	APILockShouldBlockRequest() (bool, error)
+
+	// RequestWellKnownRedirect claims the given path under /.well-known/ on
+	// the listeners for the mount, redirecting it to the given path of the
+	// mount. It fails if another mount already claimed the path.
+	RequestWellKnownRedirect(ctx context.Context, src, dest string) error
+
+	// DeregisterWellKnownRedirect releases the given path under
+	// /.well-known/ claimed by the mount. It returns whether the path was
+	// claimed.
+	DeregisterWellKnownRedirect(ctx context.Context, src string) bool
+}
+
+// RestrictedCryptoView is implemented by the system views of servers which
+// may run in restricted crypto mode. In this mode, backends refuse to create
+// or use keys of algorithms which are not approved, such as ChaCha20 and
+// Ed25519.
+type RestrictedCryptoView interface {
+	RestrictedCrypto() bool
 }
 
 type PasswordGenerator func() (password string, err error)
@@ -133,6 +151,7 @@ type StaticSystemView struct {
 	VersionString                string
 	ClusterUUID                  string
 	APILockShouldBlockRequestVal bool
+	RestrictedCryptoVal          bool
 }
 
 type noopAuditor struct{}
@@ -259,6 +278,18 @@ func (d StaticSystemView) ClusterID(ctx context.Context) (string, error) {
 	return d.ClusterUUID, nil
 }
 
+func (d StaticSystemView) RestrictedCrypto() bool {
+	return d.RestrictedCryptoVal
+}
+
 func (d StaticSystemView) APILockShouldBlockRequest() (bool, error) {
 	return d.APILockShouldBlockRequestVal, nil
 }
+
+func (d StaticSystemView) RequestWellKnownRedirect(ctx context.Context, src, dest string) error {
+	return errors.New("RequestWellKnownRedirect is not implemented in StaticSystemView")
+}
+
+func (d StaticSystemView) DeregisterWellKnownRedirect(ctx context.Context, src string) bool {
+	return false
+}
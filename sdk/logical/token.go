@@ -166,6 +166,11 @@ type TokenEntry struct {
 	// The set of CIDRs that this token can be used with
 	BoundCIDRs []*sockaddr.SockAddrMarshaler `json:"bound_cidrs" sentinel:""`
 
+	// BoundCertThumbprint is the base64url-encoded SHA-256 thumbprint of the
+	// client certificate this token can only be used with, as the
+	// "x5t#S256" confirmation method of RFC 8705.
+	BoundCertThumbprint string `json:"bound_cert_thumbprint,omitempty" mapstructure:"bound_cert_thumbprint" structs:"bound_cert_thumbprint" sentinel:""`
+
 	// NamespaceID is the identifier of the namespace to which this token is
 	// confined to. Do not return this value over the API when the token is
 	// being looked up.
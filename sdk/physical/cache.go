@@ -5,6 +5,7 @@ package physical
 
 import (
 	"context"
+	"sync"
 	"sync/atomic"
 
 	metrics "github.com/armon/go-metrics"
@@ -62,11 +63,40 @@ func cacheRefreshFromContext(ctx context.Context) bool {
 type Cache struct {
 	backend         Backend
 	lru             *lru.TwoQueueCache
+	size            int
 	locks           []*locksutil.LockEntry
 	logger          log.Logger
 	enabled         *uint32
 	cacheExceptions *pathmanager.PathManager
 	metricSink      metrics.MetricSink
+
+	// bypass holds the prefixes of the keys not cached in addition to the
+	// cache exceptions, as set by SetBypassPrefixes.
+	bypass         *pathmanager.PathManager
+	bypassLock     sync.RWMutex
+	bypassPrefixes []string
+
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+// CacheStats reports the statistics of a physical cache since its creation.
+type CacheStats struct {
+	Size           int
+	Entries        int
+	Hits           uint64
+	Misses         uint64
+	Evictions      uint64
+	BypassPrefixes []string
+}
+
+// TunableCache is a physical cache whose statistics can be read, and whose
+// size and bypass rules can be changed at runtime.
+type TunableCache interface {
+	Stats() CacheStats
+	Resize(size int)
+	SetBypassPrefixes(prefixes []string)
 }
 
 // TransactionalCache is a Cache that wraps the physical that is transactional
@@ -79,6 +109,8 @@ type TransactionalCache struct {
 var (
 	_ ToggleablePurgemonster = (*Cache)(nil)
 	_ ToggleablePurgemonster = (*TransactionalCache)(nil)
+	_ TunableCache           = (*Cache)(nil)
+	_ TunableCache           = (*TransactionalCache)(nil)
 	_ Backend                = (*Cache)(nil)
 	_ Transactional          = (*TransactionalCache)(nil)
 )
@@ -100,12 +132,14 @@ func NewCache(b Backend, size int, logger log.Logger, metricSink metrics.MetricS
 	c := &Cache{
 		backend: b,
 		lru:     cache,
+		size:    size,
 		locks:   locksutil.CreateLocks(),
 		logger:  logger,
 		// This fails safe.
 		enabled:         new(uint32),
 		cacheExceptions: pm,
 		metricSink:      metricSink,
+		bypass:          pathmanager.New(),
 	}
 	return c
 }
@@ -123,7 +157,7 @@ func (c *Cache) ShouldCache(key string) bool {
 		return false
 	}
 
-	return !c.cacheExceptions.HasPath(key)
+	return !c.cacheExceptions.HasPath(key) && !c.bypass.HasPath(key)
 }
 
 // SetEnabled is used to toggle whether the cache is on or off. It must be
@@ -147,6 +181,82 @@ func (c *Cache) Purge(ctx context.Context) {
 	c.lru.Purge()
 }
 
+// Stats returns the statistics of the cache.
+func (c *Cache) Stats() CacheStats {
+	for _, lock := range c.locks {
+		lock.RLock()
+		defer lock.RUnlock()
+	}
+
+	c.bypassLock.RLock()
+	defer c.bypassLock.RUnlock()
+
+	return CacheStats{
+		Size:           c.size,
+		Entries:        c.lru.Len(),
+		Hits:           c.hits.Load(),
+		Misses:         c.misses.Load(),
+		Evictions:      c.evictions.Load(),
+		BypassPrefixes: append([]string(nil), c.bypassPrefixes...),
+	}
+}
+
+// Resize changes the number of entries the cache holds, emptying it. If the
+// size is not positive, the default size is used.
+func (c *Cache) Resize(size int) {
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+
+	// Lock the world
+	for _, lock := range c.locks {
+		lock.Lock()
+		defer lock.Unlock()
+	}
+
+	if size == c.size {
+		return
+	}
+
+	cache, _ := lru.New2Q(size)
+	c.lru = cache
+	c.size = size
+	c.logger.Info("resized LRU cache", "size", size)
+}
+
+// SetBypassPrefixes sets the prefixes of the keys that are not cached, in
+// addition to the built-in cache exceptions, replacing the previous ones.
+// Entries cached under the new prefixes are evicted.
+func (c *Cache) SetBypassPrefixes(prefixes []string) {
+	// Lock the world
+	for _, lock := range c.locks {
+		lock.Lock()
+		defer lock.Unlock()
+	}
+
+	c.bypassLock.Lock()
+	defer c.bypassLock.Unlock()
+
+	c.bypass.RemovePaths(c.bypassPrefixes)
+	c.bypass.AddPaths(prefixes)
+	c.bypassPrefixes = append([]string(nil), prefixes...)
+
+	for _, raw := range c.lru.Keys() {
+		if key, ok := raw.(string); ok && c.bypass.HasPath(key) {
+			c.lru.Remove(key)
+		}
+	}
+}
+
+// add adds the entry to the LRU, counting the entry it evicts if the LRU is
+// full. The caller must hold the lock of the key.
+func (c *Cache) add(key string, entry *Entry) {
+	if !c.lru.Contains(key) && c.lru.Len() >= c.size {
+		c.evictions.Add(1)
+	}
+	c.lru.Add(key, entry)
+}
+
 func (c *Cache) Put(ctx context.Context, entry *Entry) error {
 	if entry != nil && !c.ShouldCache(entry.Key) {
 		return c.backend.Put(ctx, entry)
@@ -158,7 +268,7 @@ func (c *Cache) Put(ctx context.Context, entry *Entry) error {
 
 	err := c.backend.Put(ctx, entry)
 	if err == nil {
-		c.lru.Add(entry.Key, entry)
+		c.add(entry.Key, entry)
 		c.metricSink.IncrCounter([]string{"cache", "write"}, 1)
 	}
 	return err
@@ -176,6 +286,7 @@ func (c *Cache) Get(ctx context.Context, key string) (*Entry, error) {
 	// Check the LRU first
 	if !cacheRefreshFromContext(ctx) {
 		if raw, ok := c.lru.Get(key); ok {
+			c.hits.Add(1)
 			if raw == nil {
 				return nil, nil
 			}
@@ -184,6 +295,7 @@ func (c *Cache) Get(ctx context.Context, key string) (*Entry, error) {
 		}
 	}
 
+	c.misses.Add(1)
 	c.metricSink.IncrCounter([]string{"cache", "miss"}, 1)
 	// Read from the underlying backend
 	ent, err := c.backend.Get(ctx, key)
@@ -192,7 +304,7 @@ func (c *Cache) Get(ctx context.Context, key string) (*Entry, error) {
 	}
 
 	// Cache the result, even if nil
-	c.lru.Add(key, ent)
+	c.add(key, ent)
 
 	return ent, nil
 }
@@ -261,7 +373,7 @@ func (c *TransactionalCache) Transaction(ctx context.Context, txns []*TxnEntry)
 
 		switch txn.Operation {
 		case PutOperation:
-			c.lru.Add(txn.Entry.Key, txn.Entry)
+			c.add(txn.Entry.Key, txn.Entry)
 			c.metricSink.IncrCounter([]string{"cache", "write"}, 1)
 		case DeleteOperation:
 			c.lru.Remove(txn.Entry.Key)
@@ -331,3 +331,95 @@ func TestCache_Refresh(t *testing.T) {
 		t.Fatalf("expected value baz, got %s", string(r.Value))
 	}
 }
+
+func TestCache_Stats(t *testing.T) {
+	logger := logging.NewVaultLogger(log.Debug)
+
+	inm, err := NewInmem(nil, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := physical.NewCache(inm, 2, logger, &metrics.BlackholeSink{})
+	cache.SetEnabled(true)
+
+	for _, key := range []string{"foo", "bar", "baz"} {
+		err = cache.Put(context.Background(), &physical.Entry{Key: key, Value: []byte(key)})
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+	}
+
+	// Hit on the last key written, miss on the evicted one
+	if _, err := cache.Get(context.Background(), "baz"); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if _, err := cache.Get(context.Background(), "foo"); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	stats := cache.Stats()
+	if stats.Size != 2 || stats.Entries != 2 {
+		t.Fatalf("bad size: %#v", stats)
+	}
+	if stats.Hits != 1 || stats.Misses != 1 {
+		t.Fatalf("bad hits and misses: %#v", stats)
+	}
+	// Adding baz and then foo back each evicted an entry
+	if stats.Evictions != 2 {
+		t.Fatalf("bad evictions: %#v", stats)
+	}
+
+	cache.Resize(10)
+	stats = cache.Stats()
+	if stats.Size != 10 || stats.Entries != 0 {
+		t.Fatalf("bad size after resize: %#v", stats)
+	}
+}
+
+func TestCache_BypassPrefixes(t *testing.T) {
+	logger := logging.NewVaultLogger(log.Debug)
+
+	inm, err := NewInmem(nil, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := physical.NewCache(inm, 0, logger, &metrics.BlackholeSink{})
+	cache.SetEnabled(true)
+
+	for _, key := range []string{"pki/cert", "kv/secret"} {
+		err = cache.Put(context.Background(), &physical.Entry{Key: key, Value: []byte(key)})
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+	}
+
+	cache.SetBypassPrefixes([]string{"pki/"})
+	if cache.ShouldCache("pki/cert") {
+		t.Fatal("bypassed key should not be cached")
+	}
+	if !cache.ShouldCache("kv/secret") {
+		t.Fatal("key should be cached")
+	}
+
+	// The bypassed entry was evicted and is read from the backend
+	if err := inm.Delete(context.Background(), "pki/cert"); err != nil {
+		t.Fatal(err)
+	}
+	out, err := cache.Get(context.Background(), "pki/cert")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if out != nil {
+		t.Fatalf("should not have key")
+	}
+
+	stats := cache.Stats()
+	if len(stats.BypassPrefixes) != 1 || stats.BypassPrefixes[0] != "pki/" || stats.Entries != 1 {
+		t.Fatalf("bad stats: %#v", stats)
+	}
+
+	cache.SetBypassPrefixes(nil)
+	if !cache.ShouldCache("pki/cert") {
+		t.Fatal("key should be cached")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -19,6 +20,16 @@ import (
 	"github.com/openbao/openbao/sdk/v2/logical"
 )
 
+const (
+	// parameterPathSeparator separates the keys of nested parameters in
+	// allowed and denied parameters.
+	parameterPathSeparator = "."
+
+	// parameterRegexPrefix marks the allowed and denied parameter values
+	// which are regular expressions rather than globs.
+	parameterRegexPrefix = "regex:"
+)
+
 // ACL is used to wrap a set of policies to provide
 // an efficient interface for access control.
 type ACL struct {
@@ -158,6 +169,7 @@ func NewACL(ctx context.Context, policies []*Policy) (*ACL, error) {
 				existingPerms.CapabilitiesBitmap = DenyCapabilityInt
 				existingPerms.AllowedParameters = nil
 				existingPerms.DeniedParameters = nil
+				existingPerms.ParameterPatterns = nil
 				goto INSERT
 
 			default:
@@ -233,6 +245,15 @@ func NewACL(ctx context.Context, policies []*Policy) (*ACL, error) {
 				}
 			}
 
+			if len(pc.Permissions.ParameterPatterns) > 0 {
+				if existingPerms.ParameterPatterns == nil {
+					existingPerms.ParameterPatterns = make(map[string]*regexp.Regexp, len(pc.Permissions.ParameterPatterns))
+				}
+				for pattern, re := range pc.Permissions.ParameterPatterns {
+					existingPerms.ParameterPatterns[pattern] = re
+				}
+			}
+
 			if len(pc.Permissions.RequiredParameters) > 0 {
 				if len(existingPerms.RequiredParameters) == 0 {
 					existingPerms.RequiredParameters = pc.Permissions.RequiredParameters
@@ -486,11 +507,12 @@ CHECK:
 			return
 		}
 
-		for parameter, value := range req.Data {
-			// Check if parameter has been explicitly denied
-			if valueSlice, ok := permissions.DeniedParameters[strings.ToLower(parameter)]; ok {
+		for parameter, valueSlice := range permissions.DeniedParameters {
+			// Check if parameter has been explicitly denied, under any of
+			// the keys matching it case insensitively
+			for _, value := range parameterValues(req.Data, parameter) {
 				// If the value exists in denied values slice, deny
-				if valueInParameterList(value, valueSlice) {
+				if valueInParameterList(value, valueSlice, permissions.ParameterPatterns) {
 					return
 				}
 			}
@@ -510,15 +532,7 @@ CHECK:
 		}
 
 		for parameter, value := range req.Data {
-			valueSlice, ok := permissions.AllowedParameters[strings.ToLower(parameter)]
-			// Requested parameter is not in allowed list
-			if !ok && !allowedAll {
-				return
-			}
-
-			// If the value doesn't exists in the allowed values slice,
-			// deny
-			if ok && !valueInParameterList(value, valueSlice) {
+			if !parameterAllowed(permissions.AllowedParameters, permissions.ParameterPatterns, strings.ToLower(parameter), value, allowedAll) {
 				return
 			}
 		}
@@ -724,16 +738,19 @@ func (c *Core) performPolicyChecks(ctx context.Context, acl *ACL, te *logical.To
 	return ret
 }
 
-func valueInParameterList(v interface{}, list []interface{}) bool {
+func valueInParameterList(v interface{}, list []interface{}, patterns map[string]*regexp.Regexp) bool {
 	// Empty list is equivalent to the item always existing in the list
 	if len(list) == 0 {
 		return true
 	}
 
-	return valueInSlice(v, list)
+	return valueInSlice(v, list, patterns)
 }
 
-func valueInSlice(v interface{}, list []interface{}) bool {
+// valueInSlice checks whether the value matches an item of the list. Items
+// with the regex prefix are matched using the patterns compiled when parsing
+// the policy, keyed by the item without the prefix.
+func valueInSlice(v interface{}, list []interface{}, patterns map[string]*regexp.Regexp) bool {
 	for _, el := range list {
 		if el == nil || v == nil {
 			// It doesn't seem possible to set up a nil entry in the list, but it is possible
@@ -746,6 +763,13 @@ func valueInSlice(v interface{}, list []interface{}) bool {
 			item := el.(string)
 			val := v.(string)
 
+			if pattern, ok := strings.CutPrefix(item, parameterRegexPrefix); ok {
+				if re, ok := patterns[pattern]; ok && re.MatchString(val) {
+					return true
+				}
+				continue
+			}
+
 			if strutil.GlobbedStringsMatch(item, val) {
 				return true
 			}
@@ -756,3 +780,72 @@ func valueInSlice(v interface{}, list []interface{}) bool {
 
 	return false
 }
+
+// parameterValues returns the values of the parameter in the request data.
+// The parameter can be the path of a value nested in objects, with its keys
+// separated by dots, such as "data.password". Keys are case insensitive, so
+// every key matching the parameter at each level is returned, and keys
+// containing dots are matched both as is and as nested keys.
+func parameterValues(data map[string]interface{}, parameter string) []interface{} {
+	values := dataValues(data, parameter)
+
+	key, rest, nested := strings.Cut(parameter, parameterPathSeparator)
+	if !nested {
+		return values
+	}
+	for _, value := range dataValues(data, key) {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		values = append(values, parameterValues(object, rest)...)
+	}
+	return values
+}
+
+func dataValues(data map[string]interface{}, key string) []interface{} {
+	var values []interface{}
+	for k, v := range data {
+		if strings.EqualFold(k, key) {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// parameterAllowed checks the value of the parameter at the given path
+// against the allowed parameters. When only keys nested in the parameter are
+// allowed, such as "data.password", the parameter must be an object whose keys
+// are all allowed, or allowed by the "*" key at the same level, such as
+// "data.*". allowedAll is whether the "*" key applies to the parameter.
+func parameterAllowed(allowed map[string][]interface{}, patterns map[string]*regexp.Regexp, parameter string, value interface{}, allowedAll bool) bool {
+	if valueSlice, ok := allowed[parameter]; ok {
+		// If the value doesn't exists in the allowed values slice, deny
+		return valueInParameterList(value, valueSlice, patterns)
+	}
+
+	prefix := parameter + parameterPathSeparator
+	hasNested := false
+	for key := range allowed {
+		if strings.HasPrefix(key, prefix) {
+			hasNested = true
+			break
+		}
+	}
+	if !hasNested {
+		// Requested parameter is not in allowed list
+		return allowedAll
+	}
+
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	_, nestedAllowedAll := allowed[prefix+"*"]
+	for key, nestedValue := range object {
+		if !parameterAllowed(allowed, patterns, prefix+strings.ToLower(key), nestedValue, nestedAllowedAll) {
+			return false
+		}
+	}
+	return true
+}
@@ -571,6 +571,8 @@ func testACLValuePermissions(t *testing.T, ns *namespace.Namespace) {
 		{"foo/bar", []string{"deny"}, []interface{}{"bad"}, false},
 		{"foo/bar", []string{"deny"}, []interface{}{"bad glob"}, false},
 		{"foo/bar", []string{"deny"}, []interface{}{"good"}, true},
+		{"foo/bar", []string{"deny", "DENY"}, []interface{}{"bad", "good"}, false},
+		{"foo/bar", []string{"deny", "DENY"}, []interface{}{"good", "bad"}, false},
 		{"foo/bar", []string{"allow"}, []interface{}{"good"}, true},
 		{"foo/bar", []string{"deny"}, []interface{}{nil}, true},
 		{"foo/bar", []string{"allow"}, []interface{}{nil}, true},
@@ -604,6 +606,19 @@ func testACLValuePermissions(t *testing.T, ns *namespace.Namespace) {
 		{"test/star", []string{"foo"}, []interface{}{true}, true},
 		{"test/star", []string{"bar"}, []interface{}{false}, true},
 		{"test/star", []string{"bar"}, []interface{}{true}, false},
+		{"test/nested", []string{"data"}, []interface{}{map[string]interface{}{"password": "x"}}, true},
+		{"test/nested", []string{"data"}, []interface{}{map[string]interface{}{"Admin": true}}, false},
+		{"test/nested", []string{"data"}, []interface{}{map[string]interface{}{"env": "dev"}}, true},
+		{"test/nested", []string{"data"}, []interface{}{map[string]interface{}{"env": "staging-12"}}, true},
+		{"test/nested", []string{"data"}, []interface{}{map[string]interface{}{"env": "staging-x"}}, false},
+		{"test/nested", []string{"data"}, []interface{}{map[string]interface{}{"role": map[string]interface{}{"name": "ROOT"}}}, false},
+		{"test/nested", []string{"data"}, []interface{}{map[string]interface{}{"role": map[string]interface{}{"name": "reader"}}}, true},
+		{"test/nested", []string{"data"}, []interface{}{map[string]interface{}{"role": map[string]interface{}{"name": "reader"}, "ROLE": map[string]interface{}{"name": "root"}}}, false},
+		{"test/nested", []string{"data"}, []interface{}{map[string]interface{}{"role": map[string]interface{}{"name": "reader", "NAME": "root"}}}, false},
+		{"test/nested", []string{"data"}, []interface{}{"not an object"}, false},
+		{"test/nested", []string{"data", "options"}, []interface{}{map[string]interface{}{}, map[string]interface{}{"cas": 1}}, true},
+		{"test/nested", []string{"options"}, []interface{}{map[string]interface{}{"max_versions": 1}}, false},
+		{"test/nested", []string{"other"}, []interface{}{"value"}, false},
 	}
 
 	for _, tc := range tcases {
@@ -1331,4 +1346,16 @@ path "test/star" {
 	denied_parameters = {
 	}
 }
+path "test/nested" {
+	policy = "write"
+	allowed_parameters = {
+		"data.*" = []
+		"data.env" = ["dev", "regex:^staging-[0-9]+$"]
+		"options.cas" = []
+	}
+	denied_parameters = {
+		"data.admin" = []
+		"data.role.name" = ["regex:(?i)^root$"]
+	}
+}
 `
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/go-secure-stdlib/strutil"
 	"github.com/hashicorp/go-uuid"
@@ -228,14 +229,17 @@ func (c *Core) disableCredential(ctx context.Context, path string) error {
 	}
 
 	// Disable credential internally
-	if err := c.disableCredentialInternal(ctx, path, MountTableUpdateStorage); err != nil {
+	if err := c.disableCredentialInternal(ctx, path, MountTableUpdateStorage, c.deletedMountRetention()); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (c *Core) disableCredentialInternal(ctx context.Context, path string, updateStorage bool) error {
+// disableCredentialInternal disables the auth method at the given path. If
+// retention is positive, the storage of the auth method is retained for that
+// duration rather than cleared.
+func (c *Core) disableCredentialInternal(ctx context.Context, path string, updateStorage bool, retention time.Duration) error {
 	path = credentialRoutePrefix + path
 
 	ns, err := namespace.FromContext(ctx)
@@ -258,6 +262,7 @@ func (c *Core) disableCredentialInternal(ctx context.Context, path string, updat
 	// Get the backend/mount entry for this path, used to remove ignored
 	// replication prefixes
 	backend := c.router.MatchingBackend(ctx, path)
+	entry := c.router.MatchingMountEntry(ctx, path)
 
 	// Mark the entry as tainted
 	if err := c.taintCredEntry(ctx, ns.ID, path, updateStorage); err != nil {
@@ -289,6 +294,13 @@ func (c *Core) disableCredentialInternal(ctx context.Context, path string, updat
 	switch {
 	case !updateStorage:
 		// Don't attempt to clear data, replication will handle this
+	case retention > 0 && entry != nil:
+		// Keep the data so that the auth method can be undeleted, it is
+		// purged once the retention has passed
+		if err := c.retainDeletedMount(ctx, entry, retention); err != nil {
+			c.logger.Error("failed to retain storage for path being unmounted", "error", err, "path", path)
+			return err
+		}
 	default:
 		// Have writable storage, remove the whole thing
 		if err := logical.ClearViewWithLogging(ctx, view, c.logger.Named("auth.deletion").With("namespace", ns.ID, "path", path)); err != nil {
@@ -307,6 +319,10 @@ func (c *Core) disableCredentialInternal(ctx context.Context, path string, updat
 		return err
 	}
 
+	if entry != nil {
+		c.wellKnownRedirects.DeregisterMount(entry.UUID)
+	}
+
 	if c.quotaManager != nil {
 		if err := c.quotaManager.HandleBackendDisabling(ctx, ns.Path, path); err != nil {
 			c.logger.Error("failed to update quotas after disabling auth", "path", path, "error", err)
@@ -349,7 +365,10 @@ func (c *Core) removeCredEntry(ctx context.Context, path string, updateStorage b
 	return nil
 }
 
-func (c *Core) remountCredential(ctx context.Context, src, dst namespace.MountPathDetails, updateStorage bool) error {
+// remountCredential is used to remount an auth method at a new path. If
+// progress is not nil, it is called as the leases of the auth method are
+// revoked.
+func (c *Core) remountCredential(ctx context.Context, src, dst namespace.MountPathDetails, updateStorage bool, progress func(revoked, total int)) error {
 	ns, err := namespace.FromContext(ctx)
 	if err != nil {
 		return err
@@ -401,7 +420,7 @@ func (c *Core) remountCredential(ctx context.Context, src, dst namespace.MountPa
 	if c.expiration != nil {
 		revokeCtx := namespace.ContextWithNamespace(ctx, src.Namespace)
 		// Revoke all the dynamic keys
-		if err := c.expiration.RevokePrefix(revokeCtx, src.MountPath, true); err != nil {
+		if err := c.expiration.RevokePrefixWithProgress(revokeCtx, src.MountPath, progress); err != nil {
 			return err
 		}
 	}
@@ -457,7 +476,7 @@ func (c *Core) remountCredEntryForceInternal(ctx context.Context, path string, u
 		return err
 	}
 
-	if err := c.disableCredentialInternal(ctx, path, updateStorage); err != nil {
+	if err := c.disableCredentialInternal(ctx, path, updateStorage, 0); err != nil {
 		return err
 	}
 
@@ -693,33 +712,39 @@ func (c *Core) persistAuth(ctx context.Context, table *MountTable, local *bool)
 
 // setupCredentials is invoked after we've loaded the auth table to
 // initialize the credential backends and setup the router
-func (c *Core) setupCredentials(ctx context.Context) error {
+func (c *Core) setupCredentials(ctx context.Context) (retErr error) {
 	c.authLock.Lock()
 	defer c.authLock.Unlock()
 
-	var err error
-	for _, entry := range c.auth.sortEntriesByPathDepth().Entries {
-		var backend logical.Backend
+	entries := c.auth.sortEntriesByPathDepth().Entries
+	progress := c.postUnsealProgress.start(postUnsealPhaseCredentials, len(entries))
+	defer func() {
+		progress.done(retErr)
+	}()
 
-		// Create a barrier view using the UUID
-		viewPath := entry.ViewPath()
-
-		view := NewBarrierView(c.barrier, viewPath)
+	backends := c.createSetupBackends(ctx, entries, c.newCredentialBackend)
+	mounted := 0
+	defer func() {
+		if retErr != nil {
+			cleanupSetupBackends(ctx, entries[mounted:], backends)
+		}
+	}()
 
-		origViewReadOnlyErr := view.getReadOnlyErr()
+	var err error
+	for _, entry := range entries {
+		setup := backends[entry]
+		view := setup.view
+		origViewReadOnlyErr := setup.origReadOnlyErr
 
-		// Mark the view as read-only until the mounting is complete and
-		// ensure that it is reset after. This ensures that there will be no
-		// writes during the construction of the backend.
-		view.setReadOnlyErr(logical.ErrSetupReadOnly)
+		// The view is read-only until the mounting is complete, ensure that
+		// it is reset after.
 		if strutil.StrListContains(singletonMounts, entry.Type) {
 			defer view.setReadOnlyErr(origViewReadOnlyErr)
 		}
 
-		// Initialize the backend
-		sysView := c.mountEntrySysView(entry)
-
-		backend, entry.RunningSha256, err = c.newCredentialBackend(ctx, entry, sysView, view)
+		backend := setup.backend
+		entry.RunningSha256 = setup.runningSha256
+		err = setup.err
 		if err != nil {
 			c.logger.Error("failed to create credential entry", "path", entry.Path, "error", err)
 
@@ -774,6 +799,7 @@ func (c *Core) setupCredentials(ctx context.Context) error {
 			c.logger.Error("failed to mount auth entry", "path", entry.Path, "namespace", entry.Namespace(), "error", err)
 			return errLoadAuthFailed
 		}
+		mounted++
 
 		if c.logger.IsInfo() {
 			c.logger.Info("successfully mounted", "type", entry.Type, "version", entry.RunningVersion, "path", entry.Path, "namespace", entry.Namespace())
@@ -822,6 +848,7 @@ func (c *Core) setupCredentials(ctx context.Context) error {
 				postUnsealLogger.Error("failed to initialize auth backend", "error", err)
 			}
 		})
+		progress.increment()
 	}
 
 	return nil
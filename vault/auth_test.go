@@ -676,7 +676,7 @@ func TestCore_CredentialInitialize(t *testing.T) {
 func remountCredentialFromRoot(c *Core, src, dst string, updateStorage bool) error {
 	srcPathDetails := c.splitNamespaceAndMountFromPath("", src)
 	dstPathDetails := c.splitNamespaceAndMountFromPath("", dst)
-	return c.remountCredential(namespace.RootContext(nil), srcPathDetails, dstPathDetails, updateStorage)
+	return c.remountCredential(namespace.RootContext(nil), srcPathDetails, dstPathDetails, updateStorage, nil)
 }
 
 func TestCore_RemountCredential(t *testing.T) {
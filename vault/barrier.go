@@ -125,7 +125,8 @@ type SecurityBarrier interface {
 
 	// Rotate is used to create a new encryption key. All future writes
 	// should use the new key, while old values should still be decryptable.
-	Rotate(ctx context.Context, reader io.Reader) (uint32, error)
+	// The reason of the rotation is kept in the history of the keys.
+	Rotate(ctx context.Context, reader io.Reader, reason string) (uint32, error)
 
 	// CreateUpgrade creates an upgrade path key to the given term from the previous term
 	CreateUpgrade(ctx context.Context, term uint32) error
@@ -139,6 +140,10 @@ type SecurityBarrier interface {
 	// ActiveKeyInfo is used to inform details about the active key
 	ActiveKeyInfo() (*KeyInfo, error)
 
+	// KeyHistory is used to inform details about every key of the keyring,
+	// ordered by term
+	KeyHistory() ([]*KeyInfo, error)
+
 	// RotationConfig returns the auto-rotation config for the barrier key
 	RotationConfig() (KeyRotationConfig, error)
 
@@ -194,7 +199,8 @@ type BarrierEncryptor interface {
 
 // KeyInfo is used to convey information about the encryption key
 type KeyInfo struct {
-	Term        int
-	InstallTime time.Time
-	Encryptions int64
+	Term           int
+	InstallTime    time.Time
+	Encryptions    int64
+	RotationReason string
 }
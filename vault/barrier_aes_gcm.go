@@ -14,6 +14,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -36,7 +37,13 @@ const (
 	termSize = 4
 
 	autoRotateCheckInterval = 5 * time.Minute
-	legacyRotateReason      = "legacy rotation"
+
+	// Reasons of the rotations, kept in the history of the keys
+	legacyRotateReason        = "legacy rotation"
+	maxOperationsRotateReason = "reached max operations"
+	intervalRotateReason      = "rotation interval reached"
+	manualRotateReason        = "manual rotation"
+
 	// The keyring is persisted before the root key.
 	keyringTimeout = 1 * time.Second
 )
@@ -55,9 +62,11 @@ type barrierInit struct {
 
 // Validate AESGCMBarrier satisfies SecurityBarrier interface
 var (
-	_                      SecurityBarrier = &AESGCMBarrier{}
-	barrierEncryptsMetric                  = []string{"barrier", "estimated_encryptions"}
-	barrierRotationsMetric                 = []string{"barrier", "auto_rotation"}
+	_                                 SecurityBarrier = &AESGCMBarrier{}
+	barrierEncryptsMetric                             = []string{"barrier", "estimated_encryptions"}
+	barrierRotationsMetric                            = []string{"barrier", "auto_rotation"}
+	barrierOpsSinceRotationMetric                     = []string{"barrier", "operations_since_rotation"}
+	barrierSecondsSinceRotationMetric                 = []string{"barrier", "seconds_since_rotation"}
 )
 
 // AESGCMBarrier is a SecurityBarrier implementation that uses the AES
@@ -576,7 +585,7 @@ func (b *AESGCMBarrier) Seal() error {
 
 // Rotate is used to create a new encryption key. All future writes
 // should use the new key, while old values should still be decryptable.
-func (b *AESGCMBarrier) Rotate(ctx context.Context, randomSource io.Reader) (uint32, error) {
+func (b *AESGCMBarrier) Rotate(ctx context.Context, randomSource io.Reader, reason string) (uint32, error) {
 	b.l.Lock()
 	defer b.l.Unlock()
 	if b.sealed {
@@ -593,11 +602,19 @@ func (b *AESGCMBarrier) Rotate(ctx context.Context, randomSource io.Reader) (uin
 	term := b.keyring.ActiveTerm()
 	newTerm := term + 1
 
+	// Account the encryptions of the retired key, so that they are kept in
+	// the history of the keys
+	retired := *b.keyring.ActiveKey()
+	retired.Encryptions = uint64(b.encryptions())
+	keyring := b.keyring.Clone()
+	keyring.keys[term] = &retired
+
 	// Add a new encryption key
-	newKeyring, err := b.keyring.AddKey(&Key{
-		Term:    newTerm,
-		Version: 1,
-		Value:   encrypt,
+	newKeyring, err := keyring.AddKey(&Key{
+		Term:           newTerm,
+		Version:        1,
+		Value:          encrypt,
+		RotationReason: reason,
 	})
 	if err != nil {
 		return 0, fmt.Errorf("failed to add new encryption key: %w", err)
@@ -744,13 +761,43 @@ func (b *AESGCMBarrier) ActiveKeyInfo() (*KeyInfo, error) {
 
 	// Return the key info
 	info := &KeyInfo{
-		Term:        int(term),
-		InstallTime: key.InstallTime,
-		Encryptions: b.encryptions(),
+		Term:           int(term),
+		InstallTime:    key.InstallTime,
+		Encryptions:    b.encryptions(),
+		RotationReason: key.RotationReason,
 	}
 	return info, nil
 }
 
+// KeyHistory is used to inform details about every key of the keyring,
+// ordered by term
+func (b *AESGCMBarrier) KeyHistory() ([]*KeyInfo, error) {
+	b.l.RLock()
+	defer b.l.RUnlock()
+	if b.sealed {
+		return nil, ErrBarrierSealed
+	}
+
+	activeTerm := b.keyring.ActiveTerm()
+	history := make([]*KeyInfo, 0, len(b.keyring.keys))
+	for term, key := range b.keyring.keys {
+		info := &KeyInfo{
+			Term:           int(term),
+			InstallTime:    key.InstallTime,
+			Encryptions:    int64(key.Encryptions),
+			RotationReason: key.RotationReason,
+		}
+		if term == activeTerm {
+			info.Encryptions = b.encryptions()
+		}
+		history = append(history, info)
+	}
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].Term < history[j].Term
+	})
+	return history, nil
+}
+
 // Rekey is used to change the root key used to protect the keyring
 func (b *AESGCMBarrier) Rekey(ctx context.Context, key []byte) error {
 	b.l.Lock()
@@ -1212,16 +1259,21 @@ func (b *AESGCMBarrier) CheckBarrierAutoRotate(ctx context.Context) (string, err
 				return "", err
 			}
 
+			activeKey := b.keyring.ActiveKey()
+			ops := b.encryptions()
+			metrics.SetGauge(barrierOpsSinceRotationMetric, float32(ops))
+			if !activeKey.InstallTime.IsZero() {
+				metrics.SetGauge(barrierSecondsSinceRotationMetric, float32(time.Since(activeKey.InstallTime).Seconds()))
+			}
+
 			if !rc.Disabled {
-				activeKey := b.keyring.ActiveKey()
-				ops := b.encryptions()
 				switch {
 				case activeKey.Encryptions == 0 && !activeKey.InstallTime.IsZero() && time.Since(activeKey.InstallTime) > oneYear:
 					reason = legacyRotateReason
 				case ops > rc.MaxOperations:
-					reason = "reached max operations"
+					reason = maxOperationsRotateReason
 				case rc.Interval > 0 && time.Since(activeKey.InstallTime) > rc.Interval:
-					reason = "rotation interval reached"
+					reason = intervalRotateReason
 				}
 			}
 			return reason, nil
@@ -619,7 +619,7 @@ func TestAESGCMBarrier_ReloadKeyring(t *testing.T) {
 			t.Fatalf("err: %v", err)
 		}
 		b2.Unseal(context.Background(), key)
-		_, err = b2.Rotate(context.Background(), rand.Reader)
+		_, err = b2.Rotate(context.Background(), rand.Reader, "")
 		if err != nil {
 			t.Fatalf("err: %v", err)
 		}
@@ -697,6 +697,42 @@ func TestBarrier_LegacyRotate(t *testing.T) {
 	}
 }
 
+func TestBarrier_KeyHistory(t *testing.T) {
+	inm, err := inmem.NewInmem(nil, logger)
+	require.NoError(t, err)
+	b, err := NewAESGCMBarrier(inm)
+	require.NoError(t, err)
+	key, _ := b.GenerateKey(rand.Reader)
+	require.NoError(t, b.Initialize(context.Background(), key, nil, rand.Reader))
+	require.NoError(t, b.Unseal(context.Background(), key))
+
+	for i := 0; i < 3; i++ {
+		_, err = b.Encrypt(context.Background(), "foo", []byte("quick brown fox"))
+		require.NoError(t, err)
+	}
+	before := b.encryptions()
+	_, err = b.Rotate(context.Background(), rand.Reader, manualRotateReason)
+	require.NoError(t, err)
+
+	// The encryptions of retired keys and the reasons of the rotations are
+	// kept across unseals.
+	require.NoError(t, b.Seal())
+	require.NoError(t, b.Unseal(context.Background(), key))
+
+	history, err := b.KeyHistory()
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, 1, history[0].Term)
+	require.Equal(t, before, history[0].Encryptions)
+	require.Empty(t, history[0].RotationReason)
+	require.Equal(t, 2, history[1].Term)
+	require.Equal(t, manualRotateReason, history[1].RotationReason)
+
+	info, err := b.ActiveKeyInfo()
+	require.NoError(t, err)
+	require.Equal(t, history[1], info)
+}
+
 // TestBarrier_persistKeyring_Context checks that we get the right errors if
 // the context is cancelled or times-out before the first part of persistKeyring
 // is able to persist the keyring itself (i.e. we don't go on to try and persist
@@ -0,0 +1,96 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/openbao/openbao/sdk/v2/physical"
+)
+
+// cacheConfigPath is the path of the physical cache configuration, relative
+// to the config/ prefix of the system view.
+const cacheConfigPath = "cache"
+
+// CacheConfig stores the runtime configuration of the physical cache.
+type CacheConfig struct {
+	// Size overrides the cache_size of the server configuration when set.
+	Size int `json:"size,omitempty"`
+
+	// BypassPrefixes are the prefixes of the storage keys that are not
+	// cached.
+	BypassPrefixes []string `json:"bypass_prefixes,omitempty"`
+}
+
+// tunableCache returns the physical cache, if it can be tuned.
+func (c *Core) tunableCache() (physical.TunableCache, error) {
+	cache, ok := c.physicalCache.(physical.TunableCache)
+	if !ok {
+		return nil, errors.New("physical cache cannot be tuned")
+	}
+	return cache, nil
+}
+
+// readCacheConfig reads the stored cache configuration, returning an empty
+// configuration if none is stored.
+func (c *Core) readCacheConfig(ctx context.Context) (*CacheConfig, error) {
+	view := c.systemBarrierView.SubView("config/")
+
+	out, err := view.Get(ctx, cacheConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache config: %w", err)
+	}
+
+	conf := new(CacheConfig)
+	if out == nil {
+		return conf, nil
+	}
+	if err := out.DecodeJSON(conf); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+// saveCacheConfig stores the cache configuration and applies it to the
+// physical cache.
+func (c *Core) saveCacheConfig(ctx context.Context, conf *CacheConfig) error {
+	view := c.systemBarrierView.SubView("config/")
+
+	entry, err := logical.StorageEntryJSON(cacheConfigPath, conf)
+	if err != nil {
+		return fmt.Errorf("failed to create cache config entry: %w", err)
+	}
+	if err := view.Put(ctx, entry); err != nil {
+		return fmt.Errorf("failed to save cache config: %w", err)
+	}
+
+	return c.applyCacheConfig(conf)
+}
+
+// loadCacheConfig applies the stored cache configuration to the physical
+// cache.
+func (c *Core) loadCacheConfig(ctx context.Context) error {
+	conf, err := c.readCacheConfig(ctx)
+	if err != nil {
+		return err
+	}
+	return c.applyCacheConfig(conf)
+}
+
+// applyCacheConfig resizes the physical cache and sets its bypass prefixes.
+func (c *Core) applyCacheConfig(conf *CacheConfig) error {
+	cache, err := c.tunableCache()
+	if err != nil {
+		return err
+	}
+
+	size := c.cacheSize
+	if conf.Size > 0 {
+		size = conf.Size
+	}
+	cache.Resize(size)
+	cache.SetBypassPrefixes(conf.BypassPrefixes)
+
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"sort"
 
 	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/helper/strutil"
 	"github.com/openbao/openbao/sdk/v2/logical"
 )
 
@@ -18,24 +19,49 @@ func (c *Core) Capabilities(ctx context.Context, token, path string) ([]string,
 		return nil, &logical.StatusBadRequest{Err: "missing path"}
 	}
 
+	capabilities, _, err := c.CapabilitiesBatch(ctx, token, []string{path}, false)
+	if err != nil {
+		return nil, err
+	}
+	return capabilities[path], nil
+}
+
+// PolicyCapabilities are the capabilities a single policy of a token grants
+// on a path.
+type PolicyCapabilities struct {
+	Policy       string   `json:"policy" mapstructure:"policy"`
+	Namespace    string   `json:"namespace" mapstructure:"namespace"`
+	Capabilities []string `json:"capabilities" mapstructure:"capabilities"`
+}
+
+// CapabilitiesBatch is used to fetch the capabilities of the given token on
+// each of the given paths. The ACL of the token is only built once. If
+// explain is set, it also returns, for each path, the policies of the token
+// granting or denying capabilities on it.
+func (c *Core) CapabilitiesBatch(ctx context.Context, token string, paths []string, explain bool) (map[string][]string, map[string][]*PolicyCapabilities, error) {
 	if token == "" {
-		return nil, &logical.StatusBadRequest{Err: "missing token"}
+		return nil, nil, &logical.StatusBadRequest{Err: "missing token"}
+	}
+	for _, path := range paths {
+		if path == "" {
+			return nil, nil, &logical.StatusBadRequest{Err: "missing path"}
+		}
 	}
 
 	te, err := c.tokenStore.Lookup(ctx, token)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if te == nil {
-		return nil, &logical.StatusBadRequest{Err: "invalid token"}
+		return nil, nil, &logical.StatusBadRequest{Err: "invalid token"}
 	}
 
 	tokenNS, err := NamespaceByID(ctx, te.NamespaceID, c)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if tokenNS == nil {
-		return nil, namespace.ErrNoNamespace
+		return nil, nil, namespace.ErrNoNamespace
 	}
 
 	var policyCount int
@@ -45,15 +71,15 @@ func (c *Core) Capabilities(ctx context.Context, token, path string) ([]string,
 
 	entity, identityPolicies, err := c.fetchEntityAndDerivedPolicies(ctx, tokenNS, te.EntityID, te.NoIdentityPolicies)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if entity != nil && entity.Disabled {
 		c.logger.Warn("permission denied as the entity on the token is disabled")
-		return nil, logical.ErrPermissionDenied
+		return nil, nil, logical.ErrPermissionDenied
 	}
 	if te.EntityID != "" && entity == nil {
 		c.logger.Warn("permission denied as the entity on the token is invalid")
-		return nil, logical.ErrPermissionDenied
+		return nil, nil, logical.ErrPermissionDenied
 	}
 
 	for nsID, nsPolicies := range identityPolicies {
@@ -66,14 +92,26 @@ func (c *Core) Capabilities(ctx context.Context, token, path string) ([]string,
 	if te.InlinePolicy != "" {
 		inlinePolicy, err := ParseACLPolicy(tokenNS, te.InlinePolicy)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		policies = append(policies, inlinePolicy)
 		policyCount++
 	}
 
+	capabilities := make(map[string][]string, len(paths))
+	var explanations map[string][]*PolicyCapabilities
+	if explain {
+		explanations = make(map[string][]*PolicyCapabilities, len(paths))
+		for _, path := range paths {
+			explanations[path] = []*PolicyCapabilities{}
+		}
+	}
+
 	if policyCount == 0 {
-		return []string{DenyCapability}, nil
+		for _, path := range paths {
+			capabilities[path] = []string{DenyCapability}
+		}
+		return capabilities, explanations, nil
 	}
 
 	// Construct the corresponding ACL object. ACL construction should be
@@ -81,10 +119,71 @@ func (c *Core) Capabilities(ctx context.Context, token, path string) ([]string,
 	tokenCtx := namespace.ContextWithNamespace(ctx, tokenNS)
 	acl, err := c.policyStore.ACL(tokenCtx, entity, policyNames, policies...)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	for _, path := range paths {
+		pathCapabilities := acl.Capabilities(ctx, path)
+		sort.Strings(pathCapabilities)
+		capabilities[path] = pathCapabilities
+	}
+
+	if !explain {
+		return capabilities, nil, nil
+	}
+
+	// Build the ACL of each policy on its own to find out which of them
+	// grant or deny capabilities on the paths.
+	explainPolicy := func(name, nsPath string, acl *ACL) {
+		for _, path := range paths {
+			// Skip the policies without rules matching the path, which
+			// would otherwise be reported as denying it.
+			res := acl.AllowOperation(ctx, &logical.Request{
+				Path:      path,
+				Operation: logical.ListOperation,
+			}, true)
+			if !res.IsRoot && res.CapabilitiesBitmap == 0 {
+				continue
+			}
+			policyCapabilities := acl.Capabilities(ctx, path)
+			sort.Strings(policyCapabilities)
+			explanations[path] = append(explanations[path], &PolicyCapabilities{
+				Policy:       name,
+				Namespace:    nsPath,
+				Capabilities: policyCapabilities,
+			})
+		}
+	}
+
+	nsIDs := make([]string, 0, len(policyNames))
+	for nsID := range policyNames {
+		nsIDs = append(nsIDs, nsID)
+	}
+	sort.Strings(nsIDs)
+	for _, nsID := range nsIDs {
+		policyNS, err := NamespaceByID(ctx, nsID, c)
+		if err != nil {
+			return nil, nil, err
+		}
+		if policyNS == nil {
+			return nil, nil, namespace.ErrNoNamespace
+		}
+		names := strutil.RemoveDuplicates(policyNames[nsID], false)
+		for _, name := range names {
+			policyACL, err := c.policyStore.ACL(tokenCtx, entity, map[string][]string{nsID: {name}})
+			if err != nil {
+				return nil, nil, err
+			}
+			explainPolicy(name, policyNS.Path, policyACL)
+		}
+	}
+	for _, policy := range policies {
+		policyACL, err := NewACL(tokenCtx, []*Policy{policy})
+		if err != nil {
+			return nil, nil, err
+		}
+		explainPolicy("inline", tokenNS.Path, policyACL)
 	}
 
-	capabilities := acl.Capabilities(ctx, path)
-	sort.Strings(capabilities)
-	return capabilities, nil
+	return capabilities, explanations, nil
 }
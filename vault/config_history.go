@@ -0,0 +1,176 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const (
+	// policyHistorySubPath is the sub-path of the system view used to store
+	// previous versions of ACL policies.
+	policyHistorySubPath = "policy-history/"
+
+	// mountHistorySubPath is the sub-path of the system view used to store
+	// previous configurations of mounts, keyed by mount accessor.
+	mountHistorySubPath = "mount-history/"
+
+	// configHistoryMaxVersions is the number of versions kept per policy or
+	// mount. Older versions are removed.
+	configHistoryMaxVersions = 50
+)
+
+// ConfigChangeAuthor records who made a versioned configuration change.
+// Changes made internally, such as the initial version of an existing
+// policy, have no author.
+type ConfigChangeAuthor struct {
+	EntityID      string `json:"entity_id,omitempty"`
+	DisplayName   string `json:"display_name,omitempty"`
+	TokenAccessor string `json:"token_accessor,omitempty"`
+	RemoteAddr    string `json:"remote_address,omitempty"`
+}
+
+func configChangeAuthorFromRequest(req *logical.Request) *ConfigChangeAuthor {
+	if req == nil {
+		return nil
+	}
+	author := &ConfigChangeAuthor{
+		EntityID:      req.EntityID,
+		DisplayName:   req.DisplayName,
+		TokenAccessor: req.ClientTokenAccessor,
+	}
+	if req.Connection != nil {
+		author.RemoteAddr = req.Connection.RemoteAddr
+	}
+	return author
+}
+
+func (a *ConfigChangeAuthor) toMap() map[string]interface{} {
+	if a == nil {
+		return nil
+	}
+	return map[string]interface{}{
+		"entity_id":      a.EntityID,
+		"display_name":   a.DisplayName,
+		"token_accessor": a.TokenAccessor,
+		"remote_address": a.RemoteAddr,
+	}
+}
+
+// PolicyVersion is a stored version of an ACL policy.
+type PolicyVersion struct {
+	Version int                 `json:"version"`
+	Raw     string              `json:"raw,omitempty"`
+	Deleted bool                `json:"deleted,omitempty"`
+	Time    time.Time           `json:"time"`
+	Author  *ConfigChangeAuthor `json:"author,omitempty"`
+}
+
+// MountConfigVersion is a stored version of the tunable configuration of a
+// mount, in the format accepted by the tune endpoints.
+type MountConfigVersion struct {
+	Version int                    `json:"version"`
+	Path    string                 `json:"path"`
+	Config  map[string]interface{} `json:"config"`
+	Time    time.Time              `json:"time"`
+	Author  *ConfigChangeAuthor    `json:"author,omitempty"`
+}
+
+// configHistory stores numbered versions of an item under "<key>/<version>".
+type configHistory struct {
+	view *BarrierView
+}
+
+// versions returns the stored version numbers of key in ascending order.
+func (h *configHistory) versions(ctx context.Context, key string) ([]int, error) {
+	keys, err := h.view.List(ctx, key+"/")
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make([]int, 0, len(keys))
+	for _, k := range keys {
+		v, err := strconv.Atoi(k)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, v)
+	}
+	sort.Ints(versions)
+	return versions, nil
+}
+
+// get decodes the given version of key into out, returning false if it
+// does not exist.
+func (h *configHistory) get(ctx context.Context, key string, version int, out interface{}) (bool, error) {
+	entry, err := h.view.Get(ctx, fmt.Sprintf("%s/%d", key, version))
+	if err != nil {
+		return false, err
+	}
+	if entry == nil {
+		return false, nil
+	}
+	if err := entry.DecodeJSON(out); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// latest decodes the latest version of key into out, returning false if
+// there is none.
+func (h *configHistory) latest(ctx context.Context, key string, out interface{}) (bool, error) {
+	versions, err := h.versions(ctx, key)
+	if err != nil || len(versions) == 0 {
+		return false, err
+	}
+	return h.get(ctx, key, versions[len(versions)-1], out)
+}
+
+// append stores the value returned by create for the next version of key,
+// and removes the oldest versions beyond configHistoryMaxVersions.
+func (h *configHistory) append(ctx context.Context, key string, create func(version int) interface{}) error {
+	versions, err := h.versions(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	next := 1
+	if len(versions) > 0 {
+		next = versions[len(versions)-1] + 1
+	}
+
+	entry, err := logical.StorageEntryJSON(fmt.Sprintf("%s/%d", key, next), create(next))
+	if err != nil {
+		return err
+	}
+	if err := h.view.Put(ctx, entry); err != nil {
+		return err
+	}
+
+	versions = append(versions, next)
+	for len(versions) > configHistoryMaxVersions {
+		if err := h.view.Delete(ctx, fmt.Sprintf("%s/%d", key, versions[0])); err != nil {
+			return err
+		}
+		versions = versions[1:]
+	}
+	return nil
+}
+
+// clear removes all versions of key.
+func (h *configHistory) clear(ctx context.Context, key string) error {
+	versions, err := h.versions(ctx, key)
+	if err != nil {
+		return err
+	}
+	for _, v := range versions {
+		if err := h.view.Delete(ctx, fmt.Sprintf("%s/%d", key, v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
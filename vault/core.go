@@ -305,12 +305,54 @@ type Core struct {
 	// unlockInfo has the keys provided to Unseal until the threshold number of parts is available, as well as the operation nonce
 	unlockInfo *unlockInformation
 
+	// totpShareUsedCodes has the recently accepted TOTP codes of unseal key
+	// shares, which are not accepted again, guarded by totpShareLock
+	totpShareLock      sync.Mutex
+	totpShareUsedCodes map[string]time.Time
+
+	// mountActivity counts the recent audited requests to each mount
+	mountActivity mountActivityTracker
+
+	// engineUsage counts the certificates issued by PKI mounts and the
+	// operations of transit mounts, and engineUsageCancel stops storing them
+	// periodically
+	engineUsage       engineUsageTracker
+	engineUsageCancel context.CancelFunc
+
+	// deprecationUsage records the deprecated features used by the requests
+	// to each mount, stored along with the engine usage
+	deprecationUsage deprecationUsageTracker
+
+	// federationCancel stops the reports to the management cluster, and
+	// federationLock guards the time and error of the last report
+	federationCancel     context.CancelFunc
+	federationLock       sync.Mutex
+	federationLastReport time.Time
+	federationLastError  string
+
+	// federationClustersLock serializes the registrations of the clusters
+	// reporting to this management cluster
+	federationClustersLock sync.Mutex
+
+	// wellKnownRedirects holds the paths under /.well-known/ claimed by the
+	// mounts
+	wellKnownRedirects *wellKnownRedirectRegistry
+
 	// generateRootProgress holds the shares until we reach enough
 	// to verify the master key
 	generateRootConfig   *GenerateRootConfig
 	generateRootProgress [][]byte
 	generateRootLock     sync.Mutex
 
+	// generateRootCeremony records the participants of the root generation
+	// in progress, if any
+	generateRootCeremony *GenerateRootCeremony
+
+	// rootTokenTTL and rootTokenNumUses limit the root tokens created by
+	// root generation. Zero values mean no limit.
+	rootTokenTTL     time.Duration
+	rootTokenNumUses int
+
 	// These variables holds the config and shares we have until we reach
 	// enough to verify the appropriate master key. Note that the same lock is
 	// used; this isn't time-critical so this shouldn't be a problem.
@@ -330,6 +372,10 @@ type Core struct {
 	// against their migration ids
 	mountMigrationTracker *sync.Map
 
+	// secretsImports tracks past and ongoing imports of secrets from
+	// external stores against their IDs
+	secretsImports *sync.Map
+
 	// auth is loaded after unseal since it is a protected
 	// configuration
 	auth *MountTable
@@ -417,6 +463,9 @@ type Core struct {
 	// Cache stores the actual cache; we always have this but may bypass it if
 	// disabled
 	physicalCache physical.ToggleablePurgemonster
+	// cacheSize is the size of the physical cache set in the configuration,
+	// used unless overridden by the cache configuration
+	cacheSize int
 
 	// logRequestsLevel indicates at which level requests should be logged
 	logRequestsLevel *uberAtomic.Int32
@@ -427,6 +476,9 @@ type Core struct {
 	// reloadFuncsLock controls access to the funcs
 	reloadFuncsLock sync.RWMutex
 
+	// reloadConfigFunc reloads the server configuration file, as on SIGHUP
+	reloadConfigFunc func() error
+
 	// wrappingJWTKey is the key used for generating JWTs containing response
 	// wrapping information
 	wrappingJWTKey *ecdsa.PrivateKey
@@ -436,6 +488,9 @@ type Core struct {
 	//
 	// Name
 	clusterName string
+
+	// quorumMonitorStopCh stops the quorum monitor of the unsealed node
+	quorumMonitorStopCh chan struct{}
 	// ID
 	clusterID uberAtomic.String
 	// Specific cipher suites to use for clustering, if any
@@ -495,6 +550,10 @@ type Core struct {
 	// pluginDirectory is the location vault will look for plugin binaries
 	pluginDirectory string
 
+	// pluginDirectoryLock protects the plugin directory, uid and
+	// permissions, which can be changed on reload
+	pluginDirectoryLock sync.RWMutex
+
 	// pluginFileUid is the uid of the plugin files and directory
 	pluginFileUid int
 
@@ -527,6 +586,9 @@ type Core struct {
 	// Stores any funcs that should be run on successful postUnseal
 	postUnsealFuncs []func()
 
+	// postUnsealProgress tracks the phases of the post-unseal setup
+	postUnsealProgress postUnsealProgress
+
 	// Stores any funcs that should be run on successful barrier unseal in
 	// recovery mode
 	postRecoveryUnsealFuncs []func() error
@@ -559,6 +621,20 @@ type Core struct {
 	// Stop channel for raft TLS rotations
 	raftTLSRotationStopCh chan struct{}
 
+	// raftDRLock protects the DR secondary configuration, status and stop
+	// channel.
+	raftDRLock   sync.Mutex
+	raftDRConfig *raftDRSecondaryConfig
+	raftDRStatus raftDRSecondaryStatus
+	raftDRStopCh chan struct{}
+
+	// raftPerfLock protects the performance secondary configuration, status
+	// and stop channel.
+	raftPerfLock   sync.Mutex
+	raftPerfConfig *raftPerfSecondaryConfig
+	raftPerfStatus raftPerfSecondaryStatus
+	raftPerfStopCh chan struct{}
+
 	// Stores the root key for generating challenges for pending peers we are
 	// waiting to give answers. This is constant size unlike the earlier
 	// sync.Map implementation.
@@ -590,11 +666,63 @@ type Core struct {
 
 	autoRotateCancel context.CancelFunc
 
+	// rootRotationCancel stops the scheduler of root credential rotations,
+	// and rootRotationLock serializes the rotations and their registration.
+	rootRotationCancel context.CancelFunc
+	rootRotationLock   sync.Mutex
+
+	// configExportKeyLock serializes the generation of the key signing
+	// configuration bundles.
+	configExportKeyLock sync.Mutex
+
+	// payloadEncryptionKeyLock serializes the generation and rotation of
+	// the keys request payloads are encrypted to.
+	payloadEncryptionKeyLock sync.Mutex
+
+	// networkPolicies caches the network policies, and is nil until they
+	// are loaded after unseal
+	networkPolicies     map[string]*NetworkPolicy
+	networkPoliciesLock sync.RWMutex
+
+	// ttlPolicies caches the TTL policies, and is nil until they are loaded
+	// after unseal
+	ttlPolicies     map[string]*TTLPolicy
+	ttlPoliciesLock sync.RWMutex
+
+	// loginBanners caches the login banners, and is nil until they are
+	// loaded after unseal
+	loginBanners     map[string]*LoginBanner
+	loginBannersLock sync.RWMutex
+
+	// idempotentRequests holds the requests made with an idempotency key,
+	// and their responses for replay
+	idempotentRequests *cache.Cache
+
+	// deleteConfirmations holds the tokens confirming destructive operations
+	// refused on protected paths
+	deleteConfirmations *cache.Cache
+
 	updateLockedUserEntriesCancel context.CancelFunc
 
 	// number of workers to use for lease revocation in the expiration manager
 	numExpirationWorkers int
 
+	// maximum number of lease revocations per second started by the
+	// expiration manager, zero meaning no limit
+	leaseRevocationRateLimit int
+
+	// listDefaultLimit is the number of keys list responses are limited to
+	// when the request does not specify a limit, zero meaning no limit
+	listDefaultLimit *uberAtomic.Int64
+
+	// disabledMountRetention is the duration for which the storage of
+	// disabled mounts is retained, zero meaning it is deleted immediately.
+	// deletedMountsLock serializes changes to the retained mounts, and
+	// deletedMountsCancel stops their purge once the retention has passed.
+	disabledMountRetention time.Duration
+	deletedMountsLock      sync.Mutex
+	deletedMountsCancel    context.CancelFunc
+
 	IndexHeaderHMACKey uberAtomic.Value
 
 	// disableAutopilot is used to disable the autopilot subsystem in raft storage
@@ -637,6 +765,10 @@ type Core struct {
 	// If any role based quota (LCQ or RLQ) is enabled, don't track lease counts by role
 	impreciseLeaseRoleTracking bool
 
+	// restrictedCrypto makes backends refuse to create or use keys of
+	// algorithms which are not approved
+	restrictedCrypto bool
+
 	// Config value for "detect_deadlocks".
 	detectDeadlocks []string
 }
@@ -699,6 +831,28 @@ type CoreConfig struct {
 	// If any role based quota (LCQ or RLQ) is enabled, don't track lease counts by role
 	ImpreciseLeaseRoleTracking bool
 
+	// RestrictedCrypto makes backends refuse to create or use keys of
+	// algorithms which are not approved, such as ChaCha20 and Ed25519
+	RestrictedCrypto bool
+
+	// RootTokenTTL and RootTokenNumUses limit the root tokens created by
+	// root generation. Zero values mean no limit.
+	RootTokenTTL     time.Duration
+	RootTokenNumUses int
+
+	// LeaseRevocationRateLimit limits the number of lease revocations per
+	// second started on expiration. Zero means no limit.
+	LeaseRevocationRateLimit int
+
+	// ListDefaultLimit is the number of keys list responses are limited to
+	// when the request does not specify a limit. Zero means no limit.
+	ListDefaultLimit int
+
+	// DisabledMountRetention is the duration for which the storage of
+	// disabled secrets engines and auth methods is retained, during which
+	// they can be undeleted. Zero means the storage is deleted on disable.
+	DisabledMountRetention time.Duration
+
 	// Disables the trace display for Sentinel checks
 	DisableSentinelTrace bool
 
@@ -743,6 +897,10 @@ type CoreConfig struct {
 	ReloadFuncs     *map[string][]reloadutil.ReloadFunc
 	ReloadFuncsLock *sync.RWMutex
 
+	// ReloadConfigFunc is called to reload the server configuration through
+	// sys/config/reload/config.
+	ReloadConfigFunc func() error
+
 	DisablePerformanceStandby bool
 	DisableIndexing           bool
 	DisableKeyEncodingChecks  bool
@@ -907,6 +1065,7 @@ func CreateCore(conf *CoreConfig) (*Core, error) {
 		seal:                 conf.Seal,
 		stateLock:            stateLock,
 		router:               NewRouter(),
+		wellKnownRedirects:   newWellKnownRedirectRegistry(),
 		sealed:               new(uint32),
 		sealMigrationDone:    new(uint32),
 		standby:              true,
@@ -922,6 +1081,8 @@ func CreateCore(conf *CoreConfig) (*Core, error) {
 		clusterName:                    conf.ClusterName,
 		clusterNetworkLayer:            conf.ClusterNetworkLayer,
 		clusterPeerClusterAddrsCache:   cache.New(3*clusterHeartbeatInterval, time.Second),
+		idempotentRequests:             cache.New(idempotencyKeyTTL, time.Minute),
+		deleteConfirmations:            cache.New(deleteConfirmationTTL, time.Minute),
 		rawEnabled:                     conf.EnableRaw,
 		introspectionEnabled:           conf.EnableIntrospection,
 		shutdownDoneCh:                 new(atomic.Value),
@@ -954,6 +1115,7 @@ func CreateCore(conf *CoreConfig) (*Core, error) {
 		enableResponseHeaderHostname:   conf.EnableResponseHeaderHostname,
 		enableResponseHeaderRaftNodeID: conf.EnableResponseHeaderRaftNodeID,
 		mountMigrationTracker:          &sync.Map{},
+		secretsImports:                 &sync.Map{},
 		disableSSCTokens:               conf.DisableSSCTokens,
 		effectiveSDKVersion:            effectiveSDKVersion,
 		userFailedLoginInfo:            make(map[FailedLoginUser]*FailedLoginInfo),
@@ -961,7 +1123,13 @@ func CreateCore(conf *CoreConfig) (*Core, error) {
 		expirationRevokeRetryBase:      conf.ExpirationRevokeRetryBase,
 		numRollbackWorkers:             conf.NumRollbackWorkers,
 		impreciseLeaseRoleTracking:     conf.ImpreciseLeaseRoleTracking,
+		restrictedCrypto:               conf.RestrictedCrypto,
 		detectDeadlocks:                detectDeadlocks,
+		rootTokenTTL:                   conf.RootTokenTTL,
+		rootTokenNumUses:               conf.RootTokenNumUses,
+		leaseRevocationRateLimit:       conf.LeaseRevocationRateLimit,
+		listDefaultLimit:               uberAtomic.NewInt64(int64(conf.ListDefaultLimit)),
+		disabledMountRetention:         conf.DisabledMountRetention,
 	}
 
 	c.standbyStopCh.Store(make(chan struct{}))
@@ -973,6 +1141,7 @@ func CreateCore(conf *CoreConfig) (*Core, error) {
 	c.allLoggers = append(c.allLoggers, c.logger)
 
 	c.router.logger = c.logger.Named("router")
+	c.router.lazyBackendFunc = c.loadLazyMount
 	c.allLoggers = append(c.allLoggers, c.router.logger)
 
 	c.inFlightReqData = &InFlightRequests{
@@ -1072,6 +1241,7 @@ func NewCore(conf *CoreConfig) (*Core, error) {
 	c.reloadFuncs = make(map[string][]reloadutil.ReloadFunc)
 	c.reloadFuncsLock.Unlock()
 	conf.ReloadFuncs = &c.reloadFuncs
+	c.reloadConfigFunc = conf.ReloadConfigFunc
 
 	c.rollbackPeriod = conf.RollbackPeriod
 	if c.rollbackPeriod == 0 {
@@ -1391,13 +1561,27 @@ func (c *Core) ResetUnsealProcess() {
 }
 
 func (c *Core) UnsealMigrate(key []byte) (bool, error) {
-	err := c.unsealFragment(key, true)
+	err := c.unsealFragment(key, "", true)
+	return !c.Sealed(), err
+}
+
+// UnsealMigrateWithTOTP is UnsealMigrate for key shares enrolled for TOTP.
+func (c *Core) UnsealMigrateWithTOTP(key []byte, code string) (bool, error) {
+	err := c.unsealFragment(key, code, true)
 	return !c.Sealed(), err
 }
 
 // Unseal is used to provide one of the key parts to unseal the Vault.
 func (c *Core) Unseal(key []byte) (bool, error) {
-	err := c.unsealFragment(key, false)
+	err := c.unsealFragment(key, "", false)
+	return !c.Sealed(), err
+}
+
+// UnsealWithTOTP is used to provide one of the key parts to unseal the Vault
+// along with its TOTP code, which is required if the shares were enrolled
+// for TOTP when rekeying.
+func (c *Core) UnsealWithTOTP(key []byte, code string) (bool, error) {
+	err := c.unsealFragment(key, code, false)
 	return !c.Sealed(), err
 }
 
@@ -1423,7 +1607,10 @@ func (c *Core) Unseal(key []byte) (bool, error) {
 // In migration scenarios a side-effect of unsealing is that
 // the members of c.migrationInfo are populated (excluding
 // .seal, which must already be populated before unseal is called.)
-func (c *Core) unsealFragment(key []byte, migrate bool) error {
+//
+// If the shamir key shares were enrolled for TOTP, totpCode must be
+// the current TOTP code of the provided share.
+func (c *Core) unsealFragment(key []byte, totpCode string, migrate bool) error {
 	defer metrics.MeasureSince([]string{"core", "unseal"}, time.Now())
 
 	c.stateLock.Lock()
@@ -1480,6 +1667,10 @@ func (c *Core) unsealFragment(key []byte, migrate bool) error {
 		sealToUse = c.migrationInfo.seal
 	}
 
+	if err := c.verifyUnsealTOTP(ctx, sealToUse, key, totpCode); err != nil {
+		return err
+	}
+
 	newKey, err := c.recordUnsealPart(key)
 	if !newKey || err != nil {
 		return err
@@ -1888,6 +2079,12 @@ func (c *Core) unsealInternal(ctx context.Context, masterKey []byte) error {
 		c.logger.Info("vault is unsealed")
 	}
 
+	c.notify(NotificationEventUnseal)
+	if c.getRaftBackend() != nil {
+		c.quorumMonitorStopCh = make(chan struct{})
+		go c.runQuorumMonitor(c.quorumMonitorStopCh)
+	}
+
 	if c.serviceRegistration != nil {
 		if err := c.serviceRegistration.NotifySealedStateChange(false); err != nil {
 			if c.logger.IsWarn() {
@@ -2089,6 +2286,15 @@ func (c *Core) UIHeaders() (http.Header, error) {
 	return c.uiConfig.Headers(context.Background())
 }
 
+// UIAssets returns the alternate UI asset sets of the current configuration
+func (c *Core) UIAssets() []*server.UIAssets {
+	conf := c.rawConfig.Load()
+	if conf == nil {
+		return nil
+	}
+	return conf.(*server.Config).UIAssets
+}
+
 // sealInternal is an internal method used to seal the vault.  It does not do
 // any authorization checking.
 func (c *Core) sealInternal() error {
@@ -2202,6 +2408,12 @@ func (c *Core) sealInternalWithOptions(grabStateLock, keepHALock, performCleanup
 		}
 	}
 
+	if c.quorumMonitorStopCh != nil {
+		close(c.quorumMonitorStopCh)
+		c.quorumMonitorStopCh = nil
+	}
+	c.notify(NotificationEventSeal)
+
 	if c.quotaManager != nil {
 		if err := c.quotaManager.Reset(); err != nil {
 			c.logger.Error("error resetting quota manager", "error", err)
@@ -2258,12 +2470,18 @@ func (s standardUnsealStrategy) unseal(ctx context.Context, logger log.Logger, c
 	if err := c.setupMounts(ctx); err != nil {
 		return err
 	}
-	if err := c.setupPolicyStore(ctx); err != nil {
+	policiesProgress := c.postUnsealProgress.start(postUnsealPhasePolicies, 0)
+	err := c.setupPolicyStore(ctx)
+	policiesProgress.done(err)
+	if err != nil {
 		return err
 	}
 	if err := c.loadCORSConfig(ctx); err != nil {
 		return err
 	}
+	if err := c.loadCacheConfig(ctx); err != nil {
+		return err
+	}
 	if err := c.loadCredentials(ctx); err != nil {
 		return err
 	}
@@ -2285,6 +2503,10 @@ func (s standardUnsealStrategy) unseal(ctx context.Context, logger log.Logger, c
 	if err := c.setupExpiration(expireLeaseStrategyFairsharing); err != nil {
 		return err
 	}
+	c.startRootRotation()
+	c.startDeletedMountsPurge()
+	c.startEngineUsageFlush()
+	c.startFederationReports()
 	if err := c.loadAudits(ctx); err != nil {
 		return err
 	}
@@ -2338,6 +2560,7 @@ func (c *Core) postUnseal(ctx context.Context, ctxCancelFunc context.CancelFunc,
 
 	// Clear any out
 	c.postUnsealFuncs = nil
+	c.postUnsealProgress.reset()
 
 	// Create a new request context
 	c.activeContext = ctx
@@ -2394,45 +2617,63 @@ func (c *Core) postUnseal(ctx context.Context, ctxCancelFunc context.CancelFunc,
 	// been set up properly before any writes can have happened.
 	//
 	// Use a small temporary worker pool to run postUnsealFuncs in parallel
-	postUnsealFuncConcurrency := runtime.NumCPU() * 2
+	initProgress := c.postUnsealProgress.start(postUnsealPhaseBackendInitialization, len(c.postUnsealFuncs))
+	runParallel(c.postUnsealConcurrency(), len(c.postUnsealFuncs), func(i int) {
+		c.postUnsealFuncs[i]()
+		initProgress.increment()
+	})
+	initProgress.done(nil)
+
+	if api.ReadBaoVariable(EnvVaultDisableLocalAuthMountEntities) != "" {
+		c.logger.Warn("disabling entities for local auth mounts through env var", "env", EnvVaultDisableLocalAuthMountEntities)
+	}
+	c.loginMFABackend.usedCodes = cache.New(0, 30*time.Second)
+	c.logger.Info("post-unseal setup complete")
+	return nil
+}
+
+// postUnsealConcurrency returns the number of workers running the parallel
+// parts of the post-unseal setup.
+func (c *Core) postUnsealConcurrency() int {
+	concurrency := runtime.NumCPU() * 2
 	if v := api.ReadBaoVariable("BAO_POSTUNSEAL_FUNC_CONCURRENCY"); v != "" {
 		pv, err := strconv.Atoi(v)
 		if err != nil || pv < 1 {
 			c.logger.Warn("invalid value for VAULT_POSTUNSEAL_FUNC_CURRENCY, must be a positive integer", "error", err, "value", pv)
 		} else {
-			postUnsealFuncConcurrency = pv
+			concurrency = pv
 		}
 	}
-	if postUnsealFuncConcurrency <= 1 {
+	return concurrency
+}
+
+// runParallel calls fn with each index up to n, with at most concurrency
+// calls running at once.
+func runParallel(concurrency, n int, fn func(int)) {
+	if concurrency <= 1 {
 		// Out of paranoia, keep the old logic for parallism=1
-		for _, v := range c.postUnsealFuncs {
-			v()
-		}
-	} else {
-		jobs := make(chan func())
-		var wg sync.WaitGroup
-		for i := 0; i < postUnsealFuncConcurrency; i++ {
-			go func() {
-				for v := range jobs {
-					v()
-					wg.Done()
-				}
-			}()
-		}
-		for _, v := range c.postUnsealFuncs {
-			wg.Add(1)
-			jobs <- v
+		for i := 0; i < n; i++ {
+			fn(i)
 		}
-		wg.Wait()
-		close(jobs)
+		return
 	}
 
-	if api.ReadBaoVariable(EnvVaultDisableLocalAuthMountEntities) != "" {
-		c.logger.Warn("disabling entities for local auth mounts through env var", "env", EnvVaultDisableLocalAuthMountEntities)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			for v := range jobs {
+				fn(v)
+				wg.Done()
+			}
+		}()
 	}
-	c.loginMFABackend.usedCodes = cache.New(0, 30*time.Second)
-	c.logger.Info("post-unseal setup complete")
-	return nil
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		jobs <- i
+	}
+	wg.Wait()
+	close(jobs)
 }
 
 // preSeal is invoked before the barrier is sealed, allowing
@@ -2458,6 +2699,10 @@ func (c *Core) preSeal() error {
 	}
 	var result error
 
+	// Store the usage of the engines while the storage is still available
+	c.stopEngineUsageFlush()
+
+	c.stopFederationReports()
 	c.stopForwarding()
 
 	c.stopRaftActiveNode()
@@ -2480,12 +2725,21 @@ func (c *Core) preSeal() error {
 	if err := c.unloadMounts(context.Background()); err != nil {
 		result = multierror.Append(result, fmt.Errorf("error unloading mounts: %w", err))
 	}
+	c.wellKnownRedirects.Reset()
 
 	if c.autoRotateCancel != nil {
 		c.autoRotateCancel()
 		c.autoRotateCancel = nil
 	}
 
+	c.stopRootRotation()
+	c.stopDeletedMountsPurge()
+	c.resetNetworkPolicies()
+	c.resetTTLPolicies()
+	c.resetLoginBanners()
+	c.idempotentRequests.Flush()
+	c.deleteConfirmations.Flush()
+
 	if c.updateLockedUserEntriesCancel != nil {
 		c.updateLockedUserEntriesCancel()
 		c.updateLockedUserEntriesCancel = nil
@@ -3193,7 +3447,7 @@ func (c *Core) checkBarrierAutoRotate(ctx context.Context) {
 			// the replication canary
 			c.logger.Info("automatic barrier key rotation triggered", "reason", reason)
 
-			_, err := c.systemBackend.handleRotate(ctx, nil, nil)
+			err := c.systemBackend.rotateBarrierKey(ctx, reason)
 			if err != nil {
 				c.logger.Error("error automatically rotating barrier key", "error", err)
 			} else {
@@ -3457,6 +3711,7 @@ type InFlightReqData struct {
 	ReqPath          string    `json:"request_path"`
 	Method           string    `json:"request_method"`
 	ClientID         string    `json:"client_id"`
+	CorrelationID    string    `json:"correlation_id"`
 }
 
 func (c *Core) StoreInFlightReqData(reqID string, data InFlightReqData) {
@@ -3521,6 +3776,7 @@ func (c *Core) LogCompletedRequests(reqID string, statusCode int) {
 		"start_time", reqData.StartTime.Format(time.RFC3339),
 		"duration", fmt.Sprintf("%dms", time.Now().Sub(reqData.StartTime).Milliseconds()),
 		"client_id", reqData.ClientID,
+		"correlation_id", reqData.CorrelationID,
 		"client_address", reqData.ClientRemoteAddr, "status_code", statusCode, "request_path", reqData.ReqPath,
 		"request_method", reqData.Method)
 }
@@ -3540,6 +3796,56 @@ func (c *Core) ReloadLogRequestsLevel() {
 	}
 }
 
+// ReloadRootTokenLimits applies the root token TTL and number of uses of the
+// current configuration to future root generations.
+func (c *Core) ReloadRootTokenLimits() {
+	conf := c.rawConfig.Load()
+	if conf == nil {
+		return
+	}
+	c.generateRootLock.Lock()
+	defer c.generateRootLock.Unlock()
+	c.rootTokenTTL = conf.(*server.Config).RootTokenTTL
+	c.rootTokenNumUses = conf.(*server.Config).RootTokenNumUses
+}
+
+// ReloadLeaseRevocationRateLimit applies the lease revocation rate limit of
+// the current configuration to the expiration manager.
+func (c *Core) ReloadLeaseRevocationRateLimit() {
+	conf := c.rawConfig.Load()
+	if conf == nil {
+		return
+	}
+	c.metricsMutex.Lock()
+	defer c.metricsMutex.Unlock()
+	c.leaseRevocationRateLimit = conf.(*server.Config).LeaseRevocationRateLimit
+	if c.expiration != nil {
+		c.expiration.setRevocationRateLimit(c.leaseRevocationRateLimit)
+	}
+}
+
+// ReloadListDefaultLimit applies the default limit of list responses of the
+// current configuration.
+func (c *Core) ReloadListDefaultLimit() {
+	conf := c.rawConfig.Load()
+	if conf == nil {
+		return
+	}
+	c.listDefaultLimit.Store(int64(conf.(*server.Config).ListDefaultLimit))
+}
+
+// ReloadDisabledMountRetention applies the retention of disabled mounts of
+// the current configuration to future disable operations.
+func (c *Core) ReloadDisabledMountRetention() {
+	conf := c.rawConfig.Load()
+	if conf == nil {
+		return
+	}
+	c.deletedMountsLock.Lock()
+	defer c.deletedMountsLock.Unlock()
+	c.disabledMountRetention = conf.(*server.Config).DisabledMountRetention
+}
+
 func (c *Core) ReloadIntrospectionEndpointEnabled() {
 	conf := c.rawConfig.Load()
 	if conf == nil {
@@ -3550,6 +3856,42 @@ func (c *Core) ReloadIntrospectionEndpointEnabled() {
 	c.introspectionEnabled = conf.(*server.Config).EnableIntrospectionEndpoint
 }
 
+// ReloadPluginDirectory applies the plugin directory, uid and permissions of
+// the current config. Already running plugins are not affected.
+func (c *Core) ReloadPluginDirectory() error {
+	conf := c.rawConfig.Load()
+	if conf == nil {
+		return nil
+	}
+	config := conf.(*server.Config)
+
+	var dir string
+	if config.PluginDirectory != "" {
+		var err error
+		dir, err = filepath.Abs(config.PluginDirectory)
+		if err != nil {
+			return fmt.Errorf("could not verify plugin directory: %w", err)
+		}
+	}
+
+	// The plugin catalog is created on unseal while holding the read lock,
+	// so it is safe to access while holding the write lock.
+	c.pluginDirectoryLock.Lock()
+	changed := c.pluginDirectory != dir
+	c.pluginDirectory = dir
+	c.pluginFileUid = config.PluginFileUid
+	c.pluginFilePermissions = config.PluginFilePermissions
+	if c.pluginCatalog != nil {
+		c.pluginCatalog.setDirectory(dir)
+	}
+	c.pluginDirectoryLock.Unlock()
+
+	if changed {
+		c.logger.Info("plugin directory reloaded", "plugin-directory", dir)
+	}
+	return nil
+}
+
 type PeerNode struct {
 	Hostname       string    `json:"hostname"`
 	APIAddress     string    `json:"api_address"`
@@ -3586,6 +3928,9 @@ func (c *Core) CheckPluginPerms(pluginName string) (err error) {
 		}
 	}
 
+	c.pluginDirectoryLock.RLock()
+	defer c.pluginDirectoryLock.RUnlock()
+
 	if c.pluginDirectory != "" && enableFilePermissionsCheck {
 		err = osutil.OwnerPermissionsMatch(c.pluginDirectory, c.pluginFileUid, c.pluginFilePermissions)
 		if err != nil {
@@ -3672,6 +4017,38 @@ func (c *Core) ResolveRoleForQuotas(ctx context.Context, req *quotas.Request) (b
 	return c.quotaManager.QueryResolveRoleQuotas(req)
 }
 
+// RateLimitQuotaClientIdentity returns the identity entity of the given client
+// token and the accessor of the auth mount which issued the token, used by the
+// rate limit quotas grouping requests by identity. Empty values are returned
+// when the token cannot be resolved or has no entity.
+func (c *Core) RateLimitQuotaClientIdentity(ctx context.Context, token string) (string, string) {
+	if token == "" {
+		return "", ""
+	}
+
+	c.stateLock.RLock()
+	defer c.stateLock.RUnlock()
+
+	token, err := c.DecodeSSCToken(token)
+	if err != nil {
+		return "", ""
+	}
+	te, err := c.LookupToken(ctx, token)
+	if err != nil || te == nil || te.EntityID == "" {
+		return "", ""
+	}
+
+	tokenNS, err := c.NamespaceByID(ctx, te.NamespaceID)
+	if err != nil || tokenNS == nil {
+		return te.EntityID, ""
+	}
+	me := c.router.MatchingMountEntry(namespace.ContextWithNamespace(ctx, tokenNS), te.Path)
+	if me == nil {
+		return te.EntityID, ""
+	}
+	return te.EntityID, me.Accessor
+}
+
 // aliasNameFromLoginRequest will determine the aliasName from the login Request
 func (c *Core) aliasNameFromLoginRequest(ctx context.Context, req *logical.Request) (string, error) {
 	c.authLock.RLock()
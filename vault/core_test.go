@@ -3357,3 +3357,58 @@ func TestStatelock_DeadlockDetection(t *testing.T) {
 		t.Fatal("statelock doesn't have deadlock detection enabled, it should")
 	}
 }
+
+func TestCore_PostUnsealStatus(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+
+	var status *PostUnsealStatus
+	corehelpers.RetryUntil(t, 10*time.Second, func() error {
+		status = c.PostUnsealStatus()
+		if status.InProgress {
+			return fmt.Errorf("post-unseal setup still in progress")
+		}
+		return nil
+	})
+
+	phases := make(map[string]PostUnsealPhaseStatus, len(status.Phases))
+	for _, phase := range status.Phases {
+		phases[phase.Name] = phase
+	}
+	for _, name := range []string{
+		postUnsealPhaseMounts,
+		postUnsealPhasePolicies,
+		postUnsealPhaseCredentials,
+		postUnsealPhaseBackendInitialization,
+		postUnsealPhaseLeases,
+	} {
+		phase, ok := phases[name]
+		require.True(t, ok, "missing phase %q", name)
+		require.Equal(t, PostUnsealPhaseComplete, phase.Status, "phase %q", name)
+		require.Equal(t, phase.Total, phase.Completed, "phase %q", name)
+		require.NotNil(t, phase.EndTime, "phase %q", name)
+	}
+	require.NotZero(t, phases[postUnsealPhaseMounts].Total)
+	require.NotZero(t, phases[postUnsealPhaseCredentials].Total)
+}
+
+func TestRunParallel(t *testing.T) {
+	var running, maxRunning int32
+	done := make([]bool, 50)
+	runParallel(4, len(done), func(i int) {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		done[i] = true
+	})
+
+	require.LessOrEqual(t, maxRunning, int32(4))
+	for i := range done {
+		require.True(t, done[i], "index %d not run", i)
+	}
+}
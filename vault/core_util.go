@@ -26,6 +26,7 @@ func coreInit(c *Core, conf *CoreConfig) error {
 		c.physical = physical.NewCache(c.sealUnwrapper, conf.CacheSize, cacheLogger, c.MetricSink().Sink)
 	}
 	c.physicalCache = c.physical.(physical.ToggleablePurgemonster)
+	c.cacheSize = conf.CacheSize
 
 	// Wrap in encoding checks
 	if !conf.DisableKeyEncodingChecks {
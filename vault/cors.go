@@ -29,6 +29,10 @@ var StdAllowedHeaders = []string{
 	"X-Vault-Wrap-Format",
 	"X-Vault-Wrap-TTL",
 	"X-Vault-Policy-Override",
+	"X-OpenBao-Idempotency-Key",
+	"X-OpenBao-Dry-Run",
+	"X-OpenBao-Acknowledge-Banners",
+	"X-OpenBao-Correlation-Id",
 	"Authorization",
 	consts.AuthHeaderName,
 }
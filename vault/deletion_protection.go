@@ -0,0 +1,153 @@
+package vault
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	uuid "github.com/hashicorp/go-uuid"
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/patrickmn/go-cache"
+)
+
+const (
+	// deleteConfirmationTTL is the time during which a destructive operation
+	// refused on a protected path can be confirmed with the returned token.
+	deleteConfirmationTTL = 5 * time.Minute
+
+	// deleteConfirmationHeaderName is the header carrying the confirmation
+	// token, both in refused responses and in confirming requests.
+	deleteConfirmationHeaderName = "X-OpenBao-Confirm-Delete"
+)
+
+// checkDeletionProtection refuses destructive operations on mounts tuned with
+// protected=true unless the client confirmed them, either with the token
+// returned when the operation was first refused or with "true". Confirmed
+// requests are marked as such, so that backends protecting their own paths
+// let them through as well.
+func (c *Core) checkDeletionProtection(ctx context.Context, req *logical.Request) (*logical.Response, error) {
+	switch req.DeleteConfirmation {
+	case "":
+	case "true":
+		req.DeleteConfirmed = true
+	default:
+		fingerprint, err := deleteConfirmationFingerprint(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		raw, ok := c.deleteConfirmations.Get(req.DeleteConfirmation)
+		if !ok || raw.(string) != fingerprint {
+			return logical.ErrorResponse("invalid or expired deletion confirmation token"), logical.ErrInvalidRequest
+		}
+		c.deleteConfirmations.Delete(req.DeleteConfirmation)
+		req.DeleteConfirmed = true
+	}
+	if req.DeleteConfirmed {
+		return nil, nil
+	}
+
+	entry, err := c.protectedMountForRequest(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+	return logical.ErrorResponse("mount %q is protected against deletion", entry.APIPath()), logical.ErrDeleteConfirmationRequired
+}
+
+// protectedMountForRequest returns the protected mount the request would
+// delete data of, or disable, if any.
+func (c *Core) protectedMountForRequest(ctx context.Context, req *logical.Request) (*MountEntry, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var target string
+	switch {
+	case strings.HasPrefix(req.Path, "sys/mounts/"):
+		target = sanitizePath(strings.TrimPrefix(req.Path, "sys/mounts/"))
+	case strings.HasPrefix(req.Path, "sys/auth/"):
+		target = credentialRoutePrefix + sanitizePath(strings.TrimPrefix(req.Path, "sys/auth/"))
+	case strings.HasPrefix(req.Path, "sys/"):
+		return nil, nil
+	}
+	if target != "" {
+		if req.Operation != logical.DeleteOperation || c.router.MatchingMount(ctx, target) != ns.Path+target {
+			return nil, nil
+		}
+		entry := c.router.MatchingMountEntry(ctx, target)
+		if entry == nil || !entry.Config.Protected {
+			return nil, nil
+		}
+		return entry, nil
+	}
+
+	entry := c.router.MatchingMountEntry(ctx, req.Path)
+	if entry == nil || !entry.Config.Protected {
+		return nil, nil
+	}
+	switch req.Operation {
+	case logical.DeleteOperation:
+		return entry, nil
+	case logical.CreateOperation, logical.UpdateOperation:
+		// Soft deletion and destruction of KV version 2 secrets are writes
+		if entry.Type != mountTypeKV || entry.Options["version"] != "2" {
+			return nil, nil
+		}
+		relPath := strings.TrimPrefix(ns.Path+req.Path, c.router.MatchingMount(ctx, req.Path))
+		if strings.HasPrefix(relPath, "delete/") || strings.HasPrefix(relPath, "destroy/") {
+			return entry, nil
+		}
+	}
+	return nil, nil
+}
+
+// deleteConfirmationResponse issues a token confirming the refused request
+// when it is made again with the token before deleteConfirmationTTL passes,
+// and tells the client how to confirm it.
+func (c *Core) deleteConfirmationResponse(ctx context.Context, req *logical.Request, resp *logical.Response) *logical.Response {
+	if resp == nil || !resp.IsError() {
+		resp = logical.ErrorResponse(logical.ErrDeleteConfirmationRequired.Error())
+	}
+
+	fingerprint, err := deleteConfirmationFingerprint(ctx, req)
+	if err != nil {
+		return resp
+	}
+	token, err := uuid.GenerateUUID()
+	if err != nil {
+		c.logger.Warn("failed to generate a deletion confirmation token", "path", req.Path, "error", err)
+		return resp
+	}
+	c.deleteConfirmations.Set(token, fingerprint, cache.DefaultExpiration)
+
+	resp.Data["error"] = fmt.Sprintf("%s; to confirm, repeat the request within %s with the %s header set to %q",
+		resp.Error(), deleteConfirmationTTL, deleteConfirmationHeaderName, token)
+	if resp.Headers == nil {
+		resp.Headers = make(map[string][]string)
+	}
+	resp.Headers[deleteConfirmationHeaderName] = []string{token}
+	return resp
+}
+
+// deleteConfirmationFingerprint identifies the operation, path and token of a
+// request, so that confirmation tokens only confirm the refused request.
+func deleteConfirmationFingerprint(ctx context.Context, req *logical.Request) (string, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	for _, s := range []string{ns.ID, string(req.Operation), req.Path, req.ClientToken} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
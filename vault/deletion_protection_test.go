@@ -0,0 +1,73 @@
+package vault
+
+import (
+	"testing"
+
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCore_DeletionProtection(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	request := func(op logical.Operation, path, confirmation string, data map[string]interface{}) (*logical.Response, error) {
+		t.Helper()
+		req := logical.TestRequest(t, op, path)
+		req.Data = data
+		req.ClientToken = root
+		req.DeleteConfirmation = confirmation
+		return c.HandleRequest(ctx, req)
+	}
+
+	_, err := request(logical.UpdateOperation, "sys/mounts/protected", "", map[string]interface{}{
+		"type":   "kv",
+		"config": map[string]interface{}{"protected": true},
+	})
+	require.NoError(t, err)
+	resp, err := request(logical.ReadOperation, "sys/mounts/protected/tune", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, true, resp.Data["protected"])
+
+	for _, key := range []string{"a", "b"} {
+		_, err = request(logical.UpdateOperation, "protected/"+key, "", map[string]interface{}{"value": key})
+		require.NoError(t, err)
+	}
+
+	// Deletions are refused with a token confirming them.
+	resp, err = request(logical.DeleteOperation, "protected/a", "", nil)
+	require.ErrorIs(t, err, logical.ErrDeleteConfirmationRequired)
+	require.True(t, resp.IsError())
+	token := resp.Headers[deleteConfirmationHeaderName][0]
+	require.Contains(t, resp.Error().Error(), token)
+
+	// Tokens only confirm the refused request, once.
+	_, err = request(logical.DeleteOperation, "protected/b", token, nil)
+	require.ErrorIs(t, err, logical.ErrInvalidRequest)
+	_, err = request(logical.DeleteOperation, "protected/a", token, nil)
+	require.NoError(t, err)
+	_, err = request(logical.DeleteOperation, "protected/a", token, nil)
+	require.ErrorIs(t, err, logical.ErrInvalidRequest)
+
+	resp, err = request(logical.ReadOperation, "protected/a", "", nil)
+	require.NoError(t, err)
+	require.Nil(t, resp)
+
+	// Explicit confirmations need no token, and writes are not protected.
+	_, err = request(logical.DeleteOperation, "protected/b", "true", nil)
+	require.NoError(t, err)
+	_, err = request(logical.UpdateOperation, "protected/c", "", map[string]interface{}{"value": "c"})
+	require.NoError(t, err)
+
+	// The mount cannot be disabled without confirmation either.
+	_, err = request(logical.DeleteOperation, "sys/mounts/protected", "", nil)
+	require.ErrorIs(t, err, logical.ErrDeleteConfirmationRequired)
+	require.NotNil(t, c.router.MatchingMountEntry(ctx, "protected/"))
+
+	_, err = request(logical.UpdateOperation, "sys/mounts/protected/tune", "", map[string]interface{}{"protected": false})
+	require.NoError(t, err)
+	_, err = request(logical.DeleteOperation, "sys/mounts/protected", "", nil)
+	require.NoError(t, err)
+	require.Nil(t, c.router.MatchingMountEntry(ctx, "protected/"))
+}
@@ -0,0 +1,326 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/helper/versions"
+	"github.com/openbao/openbao/sdk/v2/helper/consts"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const (
+	// deprecationUsageSubPath stores the deprecated features used by the
+	// requests to each mount, one entry per mount accessor.
+	deprecationUsageSubPath = "deprecation-usage/"
+
+	// maxDeprecationUsageFeatures bounds the deprecated features tracked
+	// per mount, as endpoints are tracked by request path.
+	maxDeprecationUsageFeatures = 100
+
+	// Kinds of the deprecated features found in the mount tables.
+	deprecatedEngine      = "engine"
+	deprecatedMountOption = "mount_option"
+)
+
+// deprecationUsageMount is the usage of deprecated features by the requests
+// to a mount, keyed by kind and name.
+type deprecationUsageMount struct {
+	Path     string                      `json:"path"`
+	Type     string                      `json:"type"`
+	Features map[string]*deprecatedUsage `json:"features"`
+}
+
+// deprecatedUsage is the usage of a deprecated feature by the requests to a
+// mount. RequestPath is the path of the last request using it.
+type deprecatedUsage struct {
+	Kind        string    `json:"kind"`
+	Name        string    `json:"name"`
+	RequestPath string    `json:"request_path"`
+	Count       uint64    `json:"count"`
+	FirstSeen   time.Time `json:"first_seen"`
+	LastSeen    time.Time `json:"last_seen"`
+}
+
+// merge adds the usage of other to the mount, keeping at most
+// maxDeprecationUsageFeatures features.
+func (m *deprecationUsageMount) merge(other *deprecationUsageMount) {
+	if m.Features == nil {
+		m.Features = make(map[string]*deprecatedUsage)
+	}
+	// The latest path is kept, in case the mount was moved
+	m.Path = other.Path
+	m.Type = other.Type
+	for key, o := range other.Features {
+		u, ok := m.Features[key]
+		if !ok {
+			if len(m.Features) >= maxDeprecationUsageFeatures {
+				continue
+			}
+			copied := *o
+			m.Features[key] = &copied
+			continue
+		}
+		u.Count += o.Count
+		if o.FirstSeen.Before(u.FirstSeen) {
+			u.FirstSeen = o.FirstSeen
+		}
+		if o.LastSeen.After(u.LastSeen) {
+			u.LastSeen = o.LastSeen
+			u.RequestPath = o.RequestPath
+		}
+	}
+}
+
+// deprecationUsageTracker records the deprecated features used by the
+// requests to each mount on this node, until they are flushed to storage.
+type deprecationUsageTracker struct {
+	sync.Mutex
+	pending map[string]*deprecationUsageMount
+
+	// flushLock serializes the updates of the stored usage.
+	flushLock sync.Mutex
+}
+
+func (t *deprecationUsageTracker) record(entry *MountEntry, requestPath string, feature logical.DeprecatedFeature, now time.Time) {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.pending == nil {
+		t.pending = make(map[string]*deprecationUsageMount)
+	}
+	mount, ok := t.pending[entry.Accessor]
+	if !ok {
+		mount = &deprecationUsageMount{Features: make(map[string]*deprecatedUsage)}
+		t.pending[entry.Accessor] = mount
+	}
+	mount.Path = entry.APIPath()
+	mount.Type = entry.Type
+
+	key := feature.Kind + ":" + feature.Name
+	u, ok := mount.Features[key]
+	if !ok {
+		if len(mount.Features) >= maxDeprecationUsageFeatures {
+			return
+		}
+		u = &deprecatedUsage{Kind: feature.Kind, Name: feature.Name, FirstSeen: now}
+		mount.Features[key] = u
+	}
+	u.Count++
+	u.LastSeen = now
+	u.RequestPath = requestPath
+}
+
+// flush adds the usage recorded in memory to the stored usage. Usage that
+// could not be stored is kept in memory for the next flush.
+func (t *deprecationUsageTracker) flush(ctx context.Context, view logical.Storage) error {
+	t.flushLock.Lock()
+	defer t.flushLock.Unlock()
+
+	t.Lock()
+	pending := t.pending
+	t.pending = nil
+	t.Unlock()
+
+	for accessor, usage := range pending {
+		err := t.flushMount(ctx, view, accessor, usage)
+		if err == nil {
+			delete(pending, accessor)
+			continue
+		}
+
+		// Keep the usage which was not stored
+		t.Lock()
+		if t.pending == nil {
+			t.pending = make(map[string]*deprecationUsageMount)
+		}
+		for accessor, usage := range pending {
+			if t.pending[accessor] == nil {
+				t.pending[accessor] = &deprecationUsageMount{}
+			}
+			t.pending[accessor].merge(usage)
+		}
+		t.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (t *deprecationUsageTracker) flushMount(ctx context.Context, view logical.Storage, accessor string, usage *deprecationUsageMount) error {
+	stored, err := readDeprecationUsage(ctx, view, accessor)
+	if err != nil {
+		return err
+	}
+	if stored == nil {
+		stored = &deprecationUsageMount{}
+	}
+	stored.merge(usage)
+
+	entry, err := logical.StorageEntryJSON(accessor, stored)
+	if err != nil {
+		return fmt.Errorf("failed to encode deprecation usage: %w", err)
+	}
+	return view.Put(ctx, entry)
+}
+
+func readDeprecationUsage(ctx context.Context, view logical.Storage, accessor string) (*deprecationUsageMount, error) {
+	entry, err := view.Get(ctx, accessor)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var usage deprecationUsageMount
+	if err := entry.DecodeJSON(&usage); err != nil {
+		return nil, fmt.Errorf("failed to decode deprecation usage: %w", err)
+	}
+	return &usage, nil
+}
+
+func (c *Core) deprecationUsageView() logical.Storage {
+	return c.systemBarrierView.SubView(deprecationUsageSubPath)
+}
+
+// flushDeprecationUsage stores the deprecated features used since the last
+// flush.
+func (c *Core) flushDeprecationUsage(ctx context.Context) {
+	if err := c.deprecationUsage.flush(ctx, c.deprecationUsageView()); err != nil {
+		c.logger.Error("failed to store deprecation usage", "error", err)
+	}
+}
+
+// deprecationRecorderContext returns ctx with a recorder of the deprecated
+// features reported by the backend handling req.
+func (c *Core) deprecationRecorderContext(ctx context.Context, req *logical.Request) context.Context {
+	// The router makes the request path relative to the mount
+	path := req.Path
+	return logical.ContextWithDeprecationRecorder(ctx, func(feature logical.DeprecatedFeature) {
+		entry := c.router.MatchingMountEntry(ctx, path)
+		if entry == nil {
+			return
+		}
+		requestPath := path
+		if ns, err := namespace.FromContext(ctx); err == nil {
+			requestPath = ns.Path + path
+		}
+		c.deprecationUsage.record(entry, requestPath, feature, time.Now())
+	})
+}
+
+// DeprecationReference is a mount using a deprecated feature. The request
+// fields are only set for the features reported by requests.
+type DeprecationReference struct {
+	Mount       string     `json:"mount"`
+	Type        string     `json:"type"`
+	Accessor    string     `json:"accessor"`
+	RequestPath string     `json:"request_path,omitempty"`
+	Count       uint64     `json:"count,omitempty"`
+	FirstSeen   *time.Time `json:"first_seen,omitempty"`
+	LastSeen    *time.Time `json:"last_seen,omitempty"`
+}
+
+// Deprecation is a deprecated feature in use on the cluster, with the
+// mounts using it.
+type Deprecation struct {
+	Kind       string                  `json:"kind"`
+	Name       string                  `json:"name"`
+	Status     string                  `json:"status,omitempty"`
+	References []*DeprecationReference `json:"references"`
+}
+
+// deprecationReport lists the deprecated features in use by the mounts of ns
+// and its children: the deprecated builtin engines and mount options found
+// in the mount tables, and the deprecated parameters, endpoints and key
+// types used by the requests to the mounts which still exist.
+func (c *Core) deprecationReport(ctx context.Context, ns *namespace.Namespace) ([]*Deprecation, error) {
+	c.flushDeprecationUsage(ctx)
+
+	byKey := make(map[string]*Deprecation)
+	add := func(kind, name, status string, ref *DeprecationReference) {
+		key := kind + ":" + name
+		d, ok := byKey[key]
+		if !ok {
+			d = &Deprecation{Kind: kind, Name: name, Status: status}
+			byKey[key] = d
+		}
+		d.References = append(d.References, ref)
+	}
+
+	view := c.deprecationUsageView()
+	visit := func(table *MountTable, pluginType consts.PluginType) error {
+		if table == nil {
+			return nil
+		}
+		for _, entry := range table.Entries {
+			if entry.namespace == nil || !entry.namespace.HasParent(ns) {
+				continue
+			}
+
+			ref := func() *DeprecationReference {
+				return &DeprecationReference{Mount: entry.APIPath(), Type: entry.Type, Accessor: entry.Accessor}
+			}
+
+			if c.builtinRegistry != nil && versions.IsBuiltinVersion(entry.RunningVersion) {
+				t := entry.Type
+				if alias, ok := mountAliases[t]; ok {
+					t = alias
+				}
+				if status, ok := c.builtinRegistry.DeprecationStatus(t, pluginType); ok && status != consts.Supported {
+					add(deprecatedEngine, t, status.String(), ref())
+				}
+			}
+			if entry.Config.PluginName != "" {
+				add(deprecatedMountOption, "plugin_name", "", ref())
+			}
+
+			usage, err := readDeprecationUsage(ctx, view, entry.Accessor)
+			if err != nil {
+				return err
+			}
+			if usage == nil {
+				continue
+			}
+			for _, u := range usage.Features {
+				r := ref()
+				firstSeen, lastSeen := u.FirstSeen, u.LastSeen
+				r.RequestPath, r.Count, r.FirstSeen, r.LastSeen = u.RequestPath, u.Count, &firstSeen, &lastSeen
+				add(u.Kind, u.Name, "", r)
+			}
+		}
+		return nil
+	}
+
+	c.mountsLock.RLock()
+	err := visit(c.mounts, consts.PluginTypeSecrets)
+	c.mountsLock.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	c.authLock.RLock()
+	err = visit(c.auth, consts.PluginTypeCredential)
+	c.authLock.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	deprecations := make([]*Deprecation, 0, len(byKey))
+	for _, d := range byKey {
+		sort.Slice(d.References, func(i, j int) bool {
+			return d.References[i].Mount < d.References[j].Mount
+		})
+		deprecations = append(deprecations, d)
+	}
+	sort.Slice(deprecations, func(i, j int) bool {
+		if deprecations[i].Kind != deprecations[j].Kind {
+			return deprecations[i].Kind < deprecations[j].Kind
+		}
+		return deprecations[i].Name < deprecations[j].Name
+	})
+	return deprecations, nil
+}
@@ -0,0 +1,127 @@
+package vault
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/helper/versions"
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/consts"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeprecationUsageTracker(t *testing.T) {
+	ctx := context.Background()
+	view := new(logical.InmemStorage)
+	entry := &MountEntry{Table: mountTableType, Path: "secret/", Type: "kv", Accessor: "kv-1234", namespace: namespace.RootNamespace}
+	first := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	feature := logical.DeprecatedFeature{Kind: logical.DeprecatedParameter, Name: "lease"}
+
+	var tracker deprecationUsageTracker
+	tracker.record(entry, "secret/a", feature, first)
+	tracker.record(entry, "secret/b", feature, first.Add(time.Hour))
+	require.NoError(t, tracker.flush(ctx, view))
+	tracker.record(entry, "secret/c", feature, first.Add(2*time.Hour))
+	require.NoError(t, tracker.flush(ctx, view))
+
+	usage, err := readDeprecationUsage(ctx, view, "kv-1234")
+	require.NoError(t, err)
+	require.Equal(t, "secret/", usage.Path)
+	u := usage.Features["parameter:lease"]
+	require.Equal(t, uint64(3), u.Count)
+	require.Equal(t, "secret/c", u.RequestPath)
+	require.True(t, u.FirstSeen.Equal(first))
+	require.True(t, u.LastSeen.Equal(first.Add(2*time.Hour)))
+
+	// The features tracked per mount are bounded
+	for i := 0; i < maxDeprecationUsageFeatures+10; i++ {
+		tracker.record(entry, "secret/d", logical.DeprecatedFeature{Kind: logical.DeprecatedEndpoint, Name: string(rune('a' + i))}, first)
+	}
+	require.NoError(t, tracker.flush(ctx, view))
+	usage, err = readDeprecationUsage(ctx, view, "kv-1234")
+	require.NoError(t, err)
+	require.Len(t, usage.Features, maxDeprecationUsageFeatures)
+}
+
+func TestCore_DeprecationReport(t *testing.T) {
+	deprecatedBackend := func(ctx context.Context, conf *logical.BackendConfig) (logical.Backend, error) {
+		b := &framework.Backend{
+			BackendType: logical.TypeLogical,
+			Paths: []*framework.Path{
+				{
+					Pattern: "roles/" + framework.GenericNameRegex("name"),
+					Fields: map[string]*framework.FieldSchema{
+						"name":  {Type: framework.TypeString},
+						"lease": {Type: framework.TypeDurationSecond, Deprecated: true},
+					},
+					Operations: map[logical.Operation]framework.OperationHandler{
+						logical.UpdateOperation: &framework.PathOperation{
+							Callback: func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+								return nil, nil
+							},
+						},
+					},
+				},
+			},
+		}
+		if err := b.Setup(ctx, conf); err != nil {
+			return nil, err
+		}
+		return b, nil
+	}
+
+	c, _, root := TestCoreUnsealedWithConfig(t, &CoreConfig{
+		LogicalBackends: map[string]logical.Factory{
+			"deprecated": deprecatedBackend,
+		},
+	})
+	ctx := namespace.RootContext(nil)
+
+	require.NoError(t, c.mount(ctx, &MountEntry{Table: mountTableType, Path: "legacy/", Type: "deprecated"}))
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "legacy/roles/web")
+	req.ClientToken = root
+	req.Data = map[string]interface{}{"lease": "1h"}
+	_, err := c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+
+	// Mount table entries using a builtin pending removal and a deprecated
+	// mount option
+	c.authLock.Lock()
+	c.auth.Entries = append(c.auth.Entries, &MountEntry{
+		Table:          credentialTableType,
+		Path:           "old/",
+		Type:           "pending-removal-test-plugin",
+		Accessor:       "auth_old_1234",
+		RunningVersion: versions.GetBuiltinVersion(consts.PluginTypeCredential, "pending-removal-test-plugin"),
+		Config:         MountConfig{PluginName: "pending-removal-test-plugin"},
+		namespace:      namespace.RootNamespace,
+	})
+	c.authLock.Unlock()
+
+	req = logical.TestRequest(t, logical.ReadOperation, "sys/deprecations")
+	req.ClientToken = root
+	resp, err := c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+
+	deprecations := resp.Data["deprecations"].([]*Deprecation)
+	require.Len(t, deprecations, 3)
+
+	require.Equal(t, deprecatedEngine, deprecations[0].Kind)
+	require.Equal(t, "pending-removal-test-plugin", deprecations[0].Name)
+	require.Equal(t, "pending removal", deprecations[0].Status)
+	require.Equal(t, "auth/old/", deprecations[0].References[0].Mount)
+
+	require.Equal(t, deprecatedMountOption, deprecations[1].Kind)
+	require.Equal(t, "plugin_name", deprecations[1].Name)
+
+	require.Equal(t, logical.DeprecatedParameter, deprecations[2].Kind)
+	require.Equal(t, "lease", deprecations[2].Name)
+	ref := deprecations[2].References[0]
+	require.Equal(t, "legacy/", ref.Mount)
+	require.Equal(t, "legacy/roles/web", ref.RequestPath)
+	require.Equal(t, uint64(1), ref.Count)
+}
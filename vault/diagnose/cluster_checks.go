@@ -0,0 +1,101 @@
+package diagnose
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/openbao/openbao/physical/raft"
+)
+
+const (
+	timeSkewTestName = "Check Cluster Time"
+
+	// TimeSkewWarningThreshold is the clock difference to another node above
+	// which a warning is raised. The health endpoint reports time with second
+	// precision, so smaller differences cannot be detected reliably.
+	TimeSkewWarningThreshold = 2 * time.Second
+)
+
+// ClusterTimeSkew compares the local clock to the clocks of the nodes listed
+// in the raft retry_join configuration, using the server time reported by
+// their health endpoint. Differences in clocks cause tokens, leases and
+// certificates to expire at different times on different nodes. It returns
+// the measured skew per address.
+func ClusterTimeSkew(ctx context.Context, leaders []*raft.LeaderJoinInfo) map[string]time.Duration {
+	skews := make(map[string]time.Duration)
+	checked := 0
+	for _, leader := range leaders {
+		if leader.LeaderAPIAddr == "" {
+			continue
+		}
+		checked++
+
+		addr := leader.LeaderAPIAddr
+		skew, err := nodeTimeSkew(ctx, addr, leader.TLSConfig)
+		if err != nil {
+			SpotWarn(ctx, timeSkewTestName, fmt.Sprintf("Could not determine the time of %s: %s.", addr, err))
+			continue
+		}
+		skews[addr] = skew
+
+		if skew > TimeSkewWarningThreshold || skew < -TimeSkewWarningThreshold {
+			SpotWarn(ctx, timeSkewTestName, fmt.Sprintf("The clock of %s differs from the local clock by %s.", addr, skew),
+				Advice("Please synchronize the clocks of all nodes, e.g. using NTP."))
+			continue
+		}
+		SpotOk(ctx, timeSkewTestName, fmt.Sprintf("The clock of %s differs from the local clock by %s.", addr, skew))
+	}
+
+	if checked == 0 {
+		SpotSkipped(ctx, timeSkewTestName, "No leader_api_addr found in the raft retry_join configuration.")
+	}
+	return skews
+}
+
+// nodeTimeSkew returns how far the clock of the node at addr is ahead of
+// the local clock, assuming the response was created halfway through the
+// request.
+func nodeTimeSkew(ctx context.Context, addr string, tlsConfig *tls.Config) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	// Request a successful status code regardless of the node's state.
+	url := strings.TrimSuffix(addr, "/") + "/v1/sys/health?standbyok=true&perfstandbyok=true&sealedcode=200&uninitcode=200&drsecondarycode=200"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+	}
+	client := &http.Client{Transport: transport}
+	defer transport.CloseIdleConnections()
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	rtt := time.Since(start)
+
+	var health struct {
+		ServerTimeUTC int64 `json:"server_time_utc"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
+		return 0, fmt.Errorf("invalid health response: %w", err)
+	}
+	if health.ServerTimeUTC == 0 {
+		return 0, fmt.Errorf("health response did not contain the server time")
+	}
+
+	local := start.Add(rtt / 2)
+	return time.Unix(health.ServerTimeUTC, 0).Sub(local.Truncate(time.Second)), nil
+}
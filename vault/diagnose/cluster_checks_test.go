@@ -0,0 +1,79 @@
+package diagnose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/openbao/openbao/physical/raft"
+)
+
+// runSpotChecks runs f within a diagnose session and returns the results of
+// its spot checks.
+func runSpotChecks(t *testing.T, f func(ctx context.Context)) []*Result {
+	t.Helper()
+
+	sess := New(io.Discard)
+	ctx := Context(context.Background(), sess)
+	ctx, span := StartSpan(ctx, "test")
+	f(ctx)
+	span.End()
+
+	return sess.Finalize(ctx).Children
+}
+
+func healthServer(offset time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"initialized":true,"sealed":false,"server_time_utc":%d}`, time.Now().Add(offset).Unix())
+	}))
+}
+
+func TestClusterTimeSkew(t *testing.T) {
+	inSync := healthServer(0)
+	defer inSync.Close()
+	skewed := healthServer(time.Hour)
+	defer skewed.Close()
+
+	var skews map[string]time.Duration
+	results := runSpotChecks(t, func(ctx context.Context) {
+		skews = ClusterTimeSkew(ctx, []*raft.LeaderJoinInfo{
+			{LeaderAPIAddr: inSync.URL},
+			{LeaderAPIAddr: skewed.URL},
+			{AutoJoin: "provider=aws"},
+		})
+	})
+
+	if len(skews) != 2 {
+		t.Fatalf("expected 2 measurements, got %v", skews)
+	}
+	if skew := skews[inSync.URL]; skew > TimeSkewWarningThreshold || skew < -TimeSkewWarningThreshold {
+		t.Fatalf("unexpected skew %s", skew)
+	}
+	if skew := skews[skewed.URL]; skew < 59*time.Minute {
+		t.Fatalf("expected skew of about an hour, got %s", skew)
+	}
+
+	if len(results) != 2 || results[0].Status != OkStatus || results[1].Status != WarningStatus {
+		t.Fatalf("unexpected results: %#v", results)
+	}
+	// The spot checks share the name of the check run by operator diagnose,
+	// which is the name -skip accepts.
+	for _, result := range results {
+		if result.Name != "Check Cluster Time" {
+			t.Fatalf("unexpected result name %q", result.Name)
+		}
+	}
+}
+
+func TestClusterTimeSkew_NoAddresses(t *testing.T) {
+	results := runSpotChecks(t, func(ctx context.Context) {
+		ClusterTimeSkew(ctx, nil)
+	})
+	if len(results) != 1 || results[0].Status != SkippedStatus {
+		t.Fatalf("unexpected results: %#v", results)
+	}
+}
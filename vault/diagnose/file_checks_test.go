@@ -103,3 +103,26 @@ func TestRaftStorageQuorum(t *testing.T) {
 		t.Fatalf("two voter cluster yielded wrong error: %+s", errClusterInfo)
 	}
 }
+
+func TestRaftDiskLatency(t *testing.T) {
+	dir := t.TempDir()
+	dur, err := RaftDiskLatency(context.Background(), dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dur <= 0 {
+		t.Fatalf("expected a positive latency, got %s", dur)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected the test file to be removed, found %d entries", len(entries))
+	}
+
+	if _, err := RaftDiskLatency(context.Background(), dir+"/missing"); err == nil {
+		t.Fatal("expected an error for a missing raft path")
+	}
+}
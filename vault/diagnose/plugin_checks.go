@@ -0,0 +1,95 @@
+package diagnose
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/openbao/openbao/helper/osutil"
+)
+
+const pluginDirectoryTestName = "Check Plugin Directory"
+
+// PluginDirectoryChecks verifies that the plugin directory exists and that
+// neither it nor the plugin binaries in it can be modified by other users,
+// which would allow them to run code as the server. If uid or permissions
+// are set, as through plugin_file_uid and plugin_file_permissions, the
+// directory is checked against them as well.
+func PluginDirectoryChecks(ctx context.Context, dir string, uid int, permissions int) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		SpotError(ctx, pluginDirectoryTestName, fmt.Errorf("Error accessing the plugin directory: %w.", err))
+		return
+	}
+	if !info.IsDir() {
+		SpotError(ctx, pluginDirectoryTestName, fmt.Errorf("The plugin directory %q is not a directory.", dir))
+		return
+	}
+
+	if uid != 0 || permissions != 0 {
+		if err := osutil.OwnerPermissionsMatch(dir, uid, permissions); err != nil {
+			SpotError(ctx, pluginDirectoryTestName, fmt.Errorf("The plugin directory does not match the configured owner and permissions: %w.", err))
+		}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		SpotError(ctx, pluginDirectoryTestName, fmt.Errorf("Error listing the plugin directory: %w.", err))
+		return
+	}
+
+	if runtime.GOOS == "windows" {
+		SpotSkipped(ctx, pluginDirectoryTestName, "Diagnose cannot check plugin file permissions on Windows. Please check them manually.")
+		return
+	}
+
+	problems := 0
+	if info.Mode().Perm()&0o022 != 0 {
+		problems++
+		SpotError(ctx, pluginDirectoryTestName, fmt.Errorf("The plugin directory is writable by other users: perms are %s.", info.Mode()),
+			Advice("Other users could replace plugin binaries. Please remove group and other write permissions."))
+	}
+
+	plugins := 0
+	for _, entry := range entries {
+		path := filepath.Join(dir, entry.Name())
+		if entry.Type()&os.ModeSymlink != 0 {
+			problems++
+			SpotWarn(ctx, pluginDirectoryTestName, fmt.Sprintf("Plugin %q is a symlink, which the server refuses to run.", entry.Name()))
+			continue
+		}
+		if entry.IsDir() {
+			continue
+		}
+		plugins++
+
+		fileInfo, err := entry.Info()
+		if err != nil {
+			problems++
+			SpotError(ctx, pluginDirectoryTestName, fmt.Errorf("Error accessing plugin %q: %w.", entry.Name(), err))
+			continue
+		}
+		mode := fileInfo.Mode()
+		if mode.Perm()&0o022 != 0 {
+			problems++
+			SpotError(ctx, pluginDirectoryTestName, fmt.Errorf("Plugin %q is writable by other users: perms are %s.", entry.Name(), mode),
+				Advice("Other users could replace the plugin binary. Please remove group and other write permissions."))
+		}
+		if mode.Perm()&0o100 == 0 {
+			problems++
+			SpotWarn(ctx, pluginDirectoryTestName, fmt.Sprintf("Plugin %q is not executable by its owner: perms are %s.", entry.Name(), mode))
+		}
+		if uid != 0 || permissions != 0 {
+			if err := osutil.OwnerPermissionsMatch(path, uid, permissions); err != nil {
+				problems++
+				SpotError(ctx, pluginDirectoryTestName, fmt.Errorf("Plugin %q does not match the configured owner and permissions: %w.", entry.Name(), err))
+			}
+		}
+	}
+
+	if problems == 0 {
+		SpotOk(ctx, pluginDirectoryTestName, fmt.Sprintf("Found %d plugin binaries with safe permissions.", plugins))
+	}
+}
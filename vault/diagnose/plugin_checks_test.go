@@ -0,0 +1,49 @@
+package diagnose
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestPluginDirectoryChecks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugin permissions are not checked on windows")
+	}
+
+	dir := t.TempDir()
+	if err := os.Chmod(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	plugin := filepath.Join(dir, "openbao-plugin-secrets-foo")
+	if err := os.WriteFile(plugin, []byte("#!/bin/sh\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	results := runSpotChecks(t, func(ctx context.Context) {
+		PluginDirectoryChecks(ctx, dir, 0, 0)
+	})
+	if len(results) != 1 || results[0].Status != OkStatus {
+		t.Fatalf("unexpected results: %#v", results)
+	}
+
+	// A plugin writable by other users is an error.
+	if err := os.Chmod(plugin, 0o777); err != nil {
+		t.Fatal(err)
+	}
+	results = runSpotChecks(t, func(ctx context.Context) {
+		PluginDirectoryChecks(ctx, dir, 0, 0)
+	})
+	if len(results) != 1 || results[0].Status != ErrorStatus {
+		t.Fatalf("unexpected results: %#v", results)
+	}
+
+	results = runSpotChecks(t, func(ctx context.Context) {
+		PluginDirectoryChecks(ctx, filepath.Join(dir, "missing"), 0, 0)
+	})
+	if len(results) != 1 || results[0].Status != ErrorStatus {
+		t.Fatalf("unexpected results: %#v", results)
+	}
+}
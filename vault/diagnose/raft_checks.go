@@ -10,6 +10,7 @@ import (
 	"os"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/openbao/openbao/physical/raft"
 )
@@ -102,3 +103,49 @@ func RaftStorageQuorum(ctx context.Context, b RaftConfigurableStorageBackend) st
 	SpotOk(ctx, raftQuorumTestName, okMsg)
 	return okMsg
 }
+
+const (
+	raftDiskLatencyTestName = "Check Raft Disk Latency"
+
+	// RaftDiskLatencyWarningThreshold is the synced write latency above
+	// which raft commits, and with them leader elections, become unreliable.
+	RaftDiskLatencyWarningThreshold = 50 * time.Millisecond
+
+	raftDiskLatencySamples = 10
+)
+
+// RaftDiskLatency measures the latency of synced writes to the raft storage
+// path, which bounds how quickly raft can commit log entries. It returns the
+// slowest of several writes.
+func RaftDiskLatency(ctx context.Context, path string) (time.Duration, error) {
+	f, err := os.CreateTemp(path, "diagnose-latency-")
+	if err != nil {
+		return 0, SpotError(ctx, raftDiskLatencyTestName, fmt.Errorf("Error creating test file in the raft storage path: %w.", err))
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	buf := make([]byte, 4096)
+	var maxDuration time.Duration
+	for i := 0; i < raftDiskLatencySamples; i++ {
+		start := time.Now()
+		if _, err := f.WriteAt(buf, 0); err != nil {
+			return 0, SpotError(ctx, raftDiskLatencyTestName, fmt.Errorf("Error writing to the raft storage path: %w.", err))
+		}
+		if err := f.Sync(); err != nil {
+			return 0, SpotError(ctx, raftDiskLatencyTestName, fmt.Errorf("Error syncing writes to the raft storage path: %w.", err))
+		}
+		if d := time.Since(start); d > maxDuration {
+			maxDuration = d
+		}
+	}
+
+	if maxDuration > RaftDiskLatencyWarningThreshold {
+		SpotWarn(ctx, raftDiskLatencyTestName, fmt.Sprintf("Synced writes to the raft storage path took up to %s, more than the recommended maximum of %s.", maxDuration, RaftDiskLatencyWarningThreshold),
+			Advice("Slow disks delay raft commits and can cause leadership loss. Please use dedicated, low-latency storage for the raft path."))
+		return maxDuration, nil
+	}
+
+	SpotOk(ctx, raftDiskLatencyTestName, fmt.Sprintf("Synced writes took up to %s.", maxDuration))
+	return maxDuration, nil
+}
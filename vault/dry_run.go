@@ -0,0 +1,144 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+// checkDryRun returns an error if req cannot be handled as a dry run. Dry
+// runs apply to writes only, and cannot be logins or have their responses
+// wrapped, as both would create tokens. Whether the endpoint supports dry
+// runs is checked by the backend.
+func (c *Core) checkDryRun(ctx context.Context, req *logical.Request) error {
+	switch req.Operation {
+	case logical.CreateOperation, logical.UpdateOperation, logical.PatchOperation, logical.DeleteOperation:
+	default:
+		return errors.New("dry runs only apply to write and delete requests")
+	}
+	if c.isLoginRequest(ctx, req) {
+		return errors.New("dry runs are not supported by login requests")
+	}
+	if req.WrapInfo != nil && req.WrapInfo.TTL != 0 {
+		return errors.New("dry runs cannot be response-wrapped")
+	}
+	return nil
+}
+
+// dryRunStorage is the storage given to backends handling dry runs. Reads go
+// through to the storage of the mount, while writes are kept in memory for
+// the duration of the request and then discarded, so that backends validate
+// dry runs with the same code paths as regular requests.
+type dryRunStorage struct {
+	underlying logical.Storage
+
+	l sync.RWMutex
+	// entries holds the entries written during the request, deleted ones
+	// being nil.
+	entries map[string]*logical.StorageEntry
+}
+
+var _ logical.Storage = (*dryRunStorage)(nil)
+
+func newDryRunStorage(underlying logical.Storage) *dryRunStorage {
+	return &dryRunStorage{
+		underlying: underlying,
+		entries:    make(map[string]*logical.StorageEntry),
+	}
+}
+
+func (s *dryRunStorage) Get(ctx context.Context, key string) (*logical.StorageEntry, error) {
+	s.l.RLock()
+	entry, ok := s.entries[key]
+	s.l.RUnlock()
+	if ok {
+		if entry == nil {
+			return nil, nil
+		}
+		return copyStorageEntry(entry), nil
+	}
+	return s.underlying.Get(ctx, key)
+}
+
+func (s *dryRunStorage) Put(ctx context.Context, entry *logical.StorageEntry) error {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.entries[entry.Key] = copyStorageEntry(entry)
+	return nil
+}
+
+func (s *dryRunStorage) Delete(ctx context.Context, key string) error {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.entries[key] = nil
+	return nil
+}
+
+func (s *dryRunStorage) List(ctx context.Context, prefix string) ([]string, error) {
+	return s.ListPage(ctx, prefix, "", -1)
+}
+
+func (s *dryRunStorage) ListPage(ctx context.Context, prefix string, after string, limit int) ([]string, error) {
+	s.l.RLock()
+	defer s.l.RUnlock()
+
+	if len(s.entries) == 0 {
+		return s.underlying.ListPage(ctx, prefix, after, limit)
+	}
+
+	// The written entries may come before the end of any page of the
+	// underlying storage, so the whole prefix is listed before paginating
+	keys, err := s.underlying.List(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		merged[key] = struct{}{}
+	}
+	for key, entry := range s.entries {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		trimmed := strings.TrimPrefix(key, prefix)
+		if sep := strings.Index(trimmed, "/"); sep != -1 {
+			// Deleting an entry of a subtree does not tell whether the
+			// subtree is left empty, so it is kept
+			if entry != nil {
+				merged[trimmed[:sep+1]] = struct{}{}
+			}
+			continue
+		}
+		if entry == nil {
+			delete(merged, trimmed)
+		} else {
+			merged[trimmed] = struct{}{}
+		}
+	}
+
+	out := make([]string, 0, len(merged))
+	for key := range merged {
+		if after != "" && key <= after {
+			continue
+		}
+		out = append(out, key)
+	}
+	sort.Strings(out)
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
+
+func copyStorageEntry(entry *logical.StorageEntry) *logical.StorageEntry {
+	return &logical.StorageEntry{
+		Key:      entry.Key,
+		Value:    append([]byte(nil), entry.Value...),
+		SealWrap: entry.SealWrap,
+	}
+}
@@ -0,0 +1,96 @@
+package vault
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRunStorage(t *testing.T) {
+	ctx := context.Background()
+	underlying := new(logical.InmemStorage)
+	for _, key := range []string{"a", "c", "dir/x", "other/y"} {
+		require.NoError(t, underlying.Put(ctx, &logical.StorageEntry{Key: key, Value: []byte(key)}))
+	}
+
+	s := newDryRunStorage(underlying)
+	require.NoError(t, s.Put(ctx, &logical.StorageEntry{Key: "b", Value: []byte("new")}))
+	require.NoError(t, s.Put(ctx, &logical.StorageEntry{Key: "a", Value: []byte("updated")}))
+	require.NoError(t, s.Put(ctx, &logical.StorageEntry{Key: "new/z", Value: []byte("new")}))
+	require.NoError(t, s.Delete(ctx, "c"))
+	require.NoError(t, s.Delete(ctx, "missing/w"))
+
+	entry, err := s.Get(ctx, "a")
+	require.NoError(t, err)
+	require.Equal(t, []byte("updated"), entry.Value)
+	entry, err = s.Get(ctx, "c")
+	require.NoError(t, err)
+	require.Nil(t, entry)
+	entry, err = s.Get(ctx, "dir/x")
+	require.NoError(t, err)
+	require.Equal(t, []byte("dir/x"), entry.Value)
+
+	keys, err := s.List(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b", "dir/", "new/", "other/"}, keys)
+	keys, err = s.ListPage(ctx, "", "b", 2)
+	require.NoError(t, err)
+	require.Equal(t, []string{"dir/", "new/"}, keys)
+
+	// The underlying storage is left untouched
+	entry, err = underlying.Get(ctx, "a")
+	require.NoError(t, err)
+	require.Equal(t, []byte("a"), entry.Value)
+	keys, err = underlying.List(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "c", "dir/", "other/"}, keys)
+}
+
+func TestCore_DryRun(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	request := func(op logical.Operation, path string, dryRun bool, data map[string]interface{}) (*logical.Response, error) {
+		t.Helper()
+		req := logical.TestRequest(t, op, path)
+		req.Data = data
+		req.ClientToken = root
+		req.DryRun = dryRun
+		return c.HandleRequest(ctx, req)
+	}
+
+	_, err := request(logical.UpdateOperation, "secret/existing", false, map[string]interface{}{"foo": "bar"})
+	require.NoError(t, err)
+
+	resp, err := request(logical.UpdateOperation, "secret/new", true, map[string]interface{}{"foo": "bar"})
+	require.NoError(t, err)
+	require.NotEmpty(t, resp.Warnings)
+	resp, err = request(logical.ReadOperation, "secret/new", false, nil)
+	require.NoError(t, err)
+	require.Nil(t, resp)
+
+	_, err = request(logical.DeleteOperation, "secret/existing", true, nil)
+	require.NoError(t, err)
+	resp, err = request(logical.ReadOperation, "secret/existing", false, nil)
+	require.NoError(t, err)
+	require.Equal(t, "bar", resp.Data["foo"])
+
+	// Dry runs of reads, of endpoints not supporting them, and of wrapped
+	// requests are refused
+	_, err = request(logical.ReadOperation, "secret/existing", true, nil)
+	require.ErrorIs(t, err, logical.ErrInvalidRequest)
+	_, err = request(logical.UpdateOperation, "sys/policy/test", true, map[string]interface{}{"policy": `path "*" { capabilities = ["read"] }`})
+	require.ErrorIs(t, err, logical.ErrInvalidRequest)
+	_, err = request(logical.UpdateOperation, "auth/token/create", true, nil)
+	require.ErrorIs(t, err, logical.ErrInvalidRequest)
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "secret/new")
+	req.ClientToken = root
+	req.DryRun = true
+	req.WrapInfo = &logical.RequestWrapInfo{TTL: 60}
+	_, err = c.HandleRequest(ctx, req)
+	require.ErrorIs(t, err, logical.ErrInvalidRequest)
+}
@@ -6,6 +6,7 @@ package vault
 import (
 	"context"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/openbao/openbao/helper/identity"
@@ -42,6 +43,21 @@ type extendedSystemViewImpl struct {
 	dynamicSystemView
 }
 
+// entropySystemView is the system view of mounts with external entropy
+// access, providing the secure random reader of the core to their backends,
+// augmented with the entropy of the external source configured on the
+// server.
+type entropySystemView struct {
+	extendedSystemViewImpl
+}
+
+// GetRandom implements entropy.Sourcer
+func (e entropySystemView) GetRandom(bytes int) ([]byte, error) {
+	buf := make([]byte, bytes)
+	n, err := io.ReadFull(e.core.secureRandomReader, buf)
+	return buf[:n], err
+}
+
 func (e extendedSystemViewImpl) Auditor() logical.Auditor {
 	return genericAuditor{
 		mountType: e.mountEntry.Type,
@@ -136,6 +152,20 @@ func (e extendedSystemViewImpl) APILockShouldBlockRequest() (bool, error) {
 	return false, nil
 }
 
+func (e extendedSystemViewImpl) RequestWellKnownRedirect(ctx context.Context, src, dest string) error {
+	if e.mountEntry == nil {
+		return fmt.Errorf("no mount entry")
+	}
+	return e.core.wellKnownRedirects.TryRegister(ctx, e.core, e.mountEntry.UUID, src, dest)
+}
+
+func (e extendedSystemViewImpl) DeregisterWellKnownRedirect(ctx context.Context, src string) bool {
+	if e.mountEntry == nil {
+		return false
+	}
+	return e.core.wellKnownRedirects.DeregisterSource(e.mountEntry.UUID, src)
+}
+
 func (d dynamicSystemView) DefaultLeaseTTL() time.Duration {
 	def, _ := d.fetchTTLs()
 	return def
@@ -185,6 +215,11 @@ func (d dynamicSystemView) ReplicationState() consts.ReplicationState {
 	return state
 }
 
+// RestrictedCrypto implements logical.RestrictedCryptoView
+func (d dynamicSystemView) RestrictedCrypto() bool {
+	return d.core.restrictedCrypto
+}
+
 func (d dynamicSystemView) HasFeature(feature license.Features) bool {
 	return false
 }
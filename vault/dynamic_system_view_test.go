@@ -13,6 +13,7 @@ import (
 	"time"
 
 	log "github.com/hashicorp/go-hclog"
+	"github.com/openbao/go-kms-wrapping/entropy/v2"
 	ldapcred "github.com/openbao/openbao/builtin/credential/ldap"
 	"github.com/openbao/openbao/helper/namespace"
 	"github.com/openbao/openbao/sdk/v2/framework"
@@ -323,3 +324,25 @@ func (b fakeBarrier) Put(context.Context, *logical.StorageEntry) error {
 func (b fakeBarrier) Delete(context.Context, string) error {
 	return fmt.Errorf("not implemented")
 }
+
+func TestDynamicSystemView_ExternalEntropy(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+
+	sysView := c.mountEntrySysView(&MountEntry{Type: "transit"})
+	if _, ok := sysView.(entropy.Sourcer); ok {
+		t.Fatal("mounts without external entropy access must not be entropy sources")
+	}
+
+	sysView = c.mountEntrySysView(&MountEntry{Type: "transit", ExternalEntropyAccess: true})
+	sourcer, ok := sysView.(entropy.Sourcer)
+	if !ok {
+		t.Fatal("mounts with external entropy access must be entropy sources")
+	}
+	buf, err := sourcer.GetRandom(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(buf) != 32 {
+		t.Fatalf("bad length: %d", len(buf))
+	}
+}
@@ -0,0 +1,453 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const (
+	// engineUsageSubPath stores the daily usage of the PKI and transit
+	// mounts, one entry per day.
+	engineUsageSubPath = countersSubPath + "engine-usage/"
+
+	engineUsageDateFormat = "2006-01-02"
+
+	// engineUsageFlushInterval is how often the usage counted in memory is
+	// added to the stored daily usage.
+	engineUsageFlushInterval = time.Minute
+
+	// engineUsageRetention is how long the daily usage is kept.
+	engineUsageRetention = 400 * 24 * time.Hour
+)
+
+var (
+	// pkiIssuancePathRe matches the paths of the PKI engine issuing
+	// certificates, relative to the mount, with the role as its last group.
+	pkiIssuancePathRe = regexp.MustCompile(`^(?:issuer/[^/]+/)?(?:issue|sign|sign-verbatim)(?:/([^/]+))?$`)
+
+	// transitOperationPathRe matches the paths of the transit engine using a
+	// key, relative to the mount.
+	transitOperationPathRe = regexp.MustCompile(`^(encrypt|decrypt|rewrap|sign|verify|hmac|datakey/(?:plaintext|wrapped))/([^/]+)(?:/[^/]+)?$`)
+)
+
+// engineUsageDay is the usage of the PKI and transit mounts on a day, keyed
+// by mount accessor.
+type engineUsageDay struct {
+	Mounts map[string]*engineUsageMount `json:"mounts"`
+}
+
+// engineUsageMount is the usage of a PKI or transit mount. Roles counts the
+// certificates issued with each PKI role, Keys the operations with each
+// transit key, and Consumers the requests of each entity, or of each token
+// display name for tokens without an entity.
+type engineUsageMount struct {
+	Path      string                       `json:"path"`
+	Type      string                       `json:"type"`
+	Roles     map[string]uint64            `json:"roles,omitempty"`
+	Keys      map[string]map[string]uint64 `json:"keys,omitempty"`
+	Consumers map[string]uint64            `json:"consumers,omitempty"`
+}
+
+// merge adds the usage of other to the day.
+func (d *engineUsageDay) merge(other *engineUsageDay) {
+	if d.Mounts == nil {
+		d.Mounts = make(map[string]*engineUsageMount)
+	}
+	for accessor, o := range other.Mounts {
+		m, ok := d.Mounts[accessor]
+		if !ok {
+			m = &engineUsageMount{}
+			d.Mounts[accessor] = m
+		}
+		// The latest path is kept, in case the mount was moved
+		m.Path = o.Path
+		m.Type = o.Type
+		for role, count := range o.Roles {
+			m.addRole(role, count)
+		}
+		for key, ops := range o.Keys {
+			for op, count := range ops {
+				m.addKeyOperation(key, op, count)
+			}
+		}
+		for consumer, count := range o.Consumers {
+			m.addConsumer(consumer, count)
+		}
+	}
+}
+
+func (m *engineUsageMount) addRole(role string, count uint64) {
+	if m.Roles == nil {
+		m.Roles = make(map[string]uint64)
+	}
+	m.Roles[role] += count
+}
+
+func (m *engineUsageMount) addKeyOperation(key, op string, count uint64) {
+	if m.Keys == nil {
+		m.Keys = make(map[string]map[string]uint64)
+	}
+	if m.Keys[key] == nil {
+		m.Keys[key] = make(map[string]uint64)
+	}
+	m.Keys[key][op] += count
+}
+
+func (m *engineUsageMount) addConsumer(consumer string, count uint64) {
+	if m.Consumers == nil {
+		m.Consumers = make(map[string]uint64)
+	}
+	m.Consumers[consumer] += count
+}
+
+// engineUsageTracker counts the certificates issued by PKI mounts and the
+// operations of transit mounts on this node, until they are flushed to
+// storage.
+type engineUsageTracker struct {
+	sync.Mutex
+	pending map[string]*engineUsageDay
+
+	// flushLock serializes the updates of the stored daily usage.
+	flushLock sync.Mutex
+}
+
+// record counts a successful request to a PKI or transit mount. Other
+// requests are ignored.
+func (t *engineUsageTracker) record(entry *MountEntry, req *logical.Request, auth *logical.Auth, now time.Time) {
+	if req.Operation != logical.UpdateOperation && req.Operation != logical.CreateOperation {
+		return
+	}
+
+	var role, key, op string
+	var count uint64 = 1
+	relPath := strings.TrimPrefix(req.Path, entry.Path)
+	switch entry.Type {
+	case "pki":
+		m := pkiIssuancePathRe.FindStringSubmatch(relPath)
+		if m == nil {
+			return
+		}
+		role = m[1]
+	case "transit":
+		m := transitOperationPathRe.FindStringSubmatch(relPath)
+		if m == nil {
+			return
+		}
+		op, key = strings.TrimSuffix(strings.TrimSuffix(m[1], "/plaintext"), "/wrapped"), m[2]
+		// Batch requests count each item of the batch
+		if batch, ok := req.Data["batch_input"].([]interface{}); ok && len(batch) > 0 {
+			count = uint64(len(batch))
+		}
+	default:
+		return
+	}
+
+	consumer := ""
+	if auth != nil {
+		consumer = auth.EntityID
+		if consumer == "" {
+			consumer = auth.DisplayName
+		}
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	if t.pending == nil {
+		t.pending = make(map[string]*engineUsageDay)
+	}
+	date := now.UTC().Format(engineUsageDateFormat)
+	day, ok := t.pending[date]
+	if !ok {
+		day = &engineUsageDay{Mounts: make(map[string]*engineUsageMount)}
+		t.pending[date] = day
+	}
+	mount, ok := day.Mounts[entry.Accessor]
+	if !ok {
+		mount = &engineUsageMount{Type: entry.Type}
+		day.Mounts[entry.Accessor] = mount
+	}
+	mount.Path = entry.APIPath()
+
+	if entry.Type == "pki" {
+		mount.addRole(role, count)
+	} else {
+		mount.addKeyOperation(key, op, count)
+	}
+	mount.addConsumer(consumer, count)
+}
+
+// flush adds the usage counted in memory to the stored daily usage. Usage
+// that could not be stored is kept in memory for the next flush.
+func (t *engineUsageTracker) flush(ctx context.Context, view logical.Storage) error {
+	t.flushLock.Lock()
+	defer t.flushLock.Unlock()
+
+	t.Lock()
+	pending := t.pending
+	t.pending = nil
+	t.Unlock()
+
+	dates := make([]string, 0, len(pending))
+	for date := range pending {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	for i, date := range dates {
+		if err := t.flushDay(ctx, view, date, pending[date]); err != nil {
+			// Keep the usage which was not stored
+			t.Lock()
+			for _, date := range dates[i:] {
+				if t.pending == nil {
+					t.pending = make(map[string]*engineUsageDay)
+				}
+				if t.pending[date] == nil {
+					t.pending[date] = &engineUsageDay{}
+				}
+				t.pending[date].merge(pending[date])
+			}
+			t.Unlock()
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *engineUsageTracker) flushDay(ctx context.Context, view logical.Storage, date string, usage *engineUsageDay) error {
+	stored, err := readEngineUsageDay(ctx, view, date)
+	if err != nil {
+		return err
+	}
+	if stored == nil {
+		stored = &engineUsageDay{}
+	}
+	stored.merge(usage)
+
+	entry, err := logical.StorageEntryJSON(date, stored)
+	if err != nil {
+		return fmt.Errorf("failed to encode engine usage: %w", err)
+	}
+	return view.Put(ctx, entry)
+}
+
+// prune deletes the daily usage older than the retention.
+func (t *engineUsageTracker) prune(ctx context.Context, view logical.Storage, now time.Time) error {
+	t.flushLock.Lock()
+	defer t.flushLock.Unlock()
+
+	dates, err := view.List(ctx, "")
+	if err != nil {
+		return err
+	}
+	oldest := now.UTC().Add(-engineUsageRetention).Format(engineUsageDateFormat)
+	for _, date := range dates {
+		if date < oldest {
+			if err := view.Delete(ctx, date); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func readEngineUsageDay(ctx context.Context, view logical.Storage, date string) (*engineUsageDay, error) {
+	entry, err := view.Get(ctx, date)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var day engineUsageDay
+	if err := entry.DecodeJSON(&day); err != nil {
+		return nil, fmt.Errorf("failed to decode engine usage: %w", err)
+	}
+	return &day, nil
+}
+
+func (c *Core) engineUsageView() logical.Storage {
+	return c.systemBarrierView.SubView(engineUsageSubPath)
+}
+
+// flushEngineUsage stores the usage counted since the last flush.
+func (c *Core) flushEngineUsage(ctx context.Context) {
+	if err := c.engineUsage.flush(ctx, c.engineUsageView()); err != nil {
+		c.logger.Error("failed to store engine usage", "error", err)
+	}
+}
+
+func (c *Core) engineUsageFlushLoop(ctx context.Context) {
+	t := time.NewTicker(engineUsageFlushInterval)
+	for {
+		select {
+		case <-t.C:
+			c.stateLock.RLock()
+			c.flushEngineUsage(ctx)
+			c.flushDeprecationUsage(ctx)
+			if err := c.engineUsage.prune(ctx, c.engineUsageView(), time.Now()); err != nil {
+				c.logger.Error("failed to prune engine usage", "error", err)
+			}
+			c.stateLock.RUnlock()
+		case <-ctx.Done():
+			t.Stop()
+			return
+		}
+	}
+}
+
+// startEngineUsageFlush starts storing the usage of the PKI and transit
+// mounts, and of the deprecated features, periodically. It is only run on the active node.
+func (c *Core) startEngineUsageFlush() {
+	if c.engineUsageCancel != nil {
+		return
+	}
+
+	var ctx context.Context
+	ctx, c.engineUsageCancel = context.WithCancel(c.activeContext)
+	go c.engineUsageFlushLoop(ctx)
+}
+
+// stopEngineUsageFlush stops the periodic flush and stores the usage counted
+// since the last one.
+func (c *Core) stopEngineUsageFlush() {
+	if c.engineUsageCancel != nil {
+		c.engineUsageCancel()
+		c.engineUsageCancel = nil
+		c.flushEngineUsage(context.Background())
+		c.flushDeprecationUsage(context.Background())
+	}
+}
+
+// engineUsageReport is the usage of the PKI and transit mounts between two
+// dates, inclusive.
+type engineUsageReport struct {
+	Days         []*engineUsageReportDay              `json:"days"`
+	PKI          map[string]*engineUsageReportPKI     `json:"pki"`
+	Transit      map[string]*engineUsageReportTransit `json:"transit"`
+	TopConsumers []*engineUsageReportConsumer         `json:"top_consumers"`
+}
+
+type engineUsageReportDay struct {
+	Date              string `json:"date"`
+	PKIIssuances      uint64 `json:"pki_issuances"`
+	TransitOperations uint64 `json:"transit_operations"`
+}
+
+type engineUsageReportPKI struct {
+	Issuances uint64            `json:"issuances"`
+	Roles     map[string]uint64 `json:"roles"`
+}
+
+type engineUsageReportTransit struct {
+	Operations uint64                       `json:"operations"`
+	Keys       map[string]map[string]uint64 `json:"keys"`
+}
+
+type engineUsageReportConsumer struct {
+	Consumer          string `json:"consumer"`
+	PKIIssuances      uint64 `json:"pki_issuances"`
+	TransitOperations uint64 `json:"transit_operations"`
+	Total             uint64 `json:"total"`
+}
+
+// engineUsageReport aggregates the stored daily usage between two dates,
+// inclusive, keeping the top consumers. Mounts are keyed by path.
+func (c *Core) engineUsageReport(ctx context.Context, start, end time.Time, top int) (*engineUsageReport, error) {
+	c.flushEngineUsage(ctx)
+
+	report := &engineUsageReport{
+		Days:         []*engineUsageReportDay{},
+		PKI:          make(map[string]*engineUsageReportPKI),
+		Transit:      make(map[string]*engineUsageReportTransit),
+		TopConsumers: []*engineUsageReportConsumer{},
+	}
+	consumers := make(map[string]*engineUsageReportConsumer)
+
+	view := c.engineUsageView()
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		dayReport := &engineUsageReportDay{Date: date.Format(engineUsageDateFormat)}
+		report.Days = append(report.Days, dayReport)
+
+		day, err := readEngineUsageDay(ctx, view, dayReport.Date)
+		if err != nil {
+			return nil, err
+		}
+		if day == nil {
+			continue
+		}
+
+		for _, m := range day.Mounts {
+			var total uint64
+			switch m.Type {
+			case "pki":
+				p, ok := report.PKI[m.Path]
+				if !ok {
+					p = &engineUsageReportPKI{Roles: make(map[string]uint64)}
+					report.PKI[m.Path] = p
+				}
+				for role, count := range m.Roles {
+					p.Roles[role] += count
+					total += count
+				}
+				p.Issuances += total
+				dayReport.PKIIssuances += total
+			case "transit":
+				t, ok := report.Transit[m.Path]
+				if !ok {
+					t = &engineUsageReportTransit{Keys: make(map[string]map[string]uint64)}
+					report.Transit[m.Path] = t
+				}
+				for key, ops := range m.Keys {
+					if t.Keys[key] == nil {
+						t.Keys[key] = make(map[string]uint64)
+					}
+					for op, count := range ops {
+						t.Keys[key][op] += count
+						total += count
+					}
+				}
+				t.Operations += total
+				dayReport.TransitOperations += total
+			}
+
+			for consumer, count := range m.Consumers {
+				cr, ok := consumers[consumer]
+				if !ok {
+					cr = &engineUsageReportConsumer{Consumer: consumer}
+					consumers[consumer] = cr
+				}
+				if m.Type == "pki" {
+					cr.PKIIssuances += count
+				} else {
+					cr.TransitOperations += count
+				}
+				cr.Total += count
+			}
+		}
+	}
+
+	for _, cr := range consumers {
+		report.TopConsumers = append(report.TopConsumers, cr)
+	}
+	sort.Slice(report.TopConsumers, func(i, j int) bool {
+		a, b := report.TopConsumers[i], report.TopConsumers[j]
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		return a.Consumer < b.Consumer
+	})
+	if len(report.TopConsumers) > top {
+		report.TopConsumers = report.TopConsumers[:top]
+	}
+
+	return report, nil
+}
@@ -0,0 +1,84 @@
+package vault
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/helper/testhelpers/schema"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngineUsage(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	pki := &MountEntry{Path: "pki/", Type: "pki", Accessor: "pki_1234", namespace: namespace.RootNamespace}
+	transit := &MountEntry{Path: "transit/", Type: "transit", Accessor: "transit_1234", namespace: namespace.RootNamespace}
+	alice := &logical.Auth{EntityID: "alice"}
+	rootAuth := &logical.Auth{DisplayName: "root"}
+
+	now := time.Now()
+	yesterday := now.AddDate(0, 0, -1)
+	record := func(entry *MountEntry, path string, data map[string]interface{}, auth *logical.Auth, at time.Time) {
+		c.engineUsage.record(entry, &logical.Request{Operation: logical.UpdateOperation, Path: path, Data: data}, auth, at)
+	}
+
+	record(pki, "pki/issue/web", nil, alice, now)
+	record(pki, "pki/issuer/default/sign/web", nil, alice, yesterday)
+	record(pki, "pki/sign/internal", nil, rootAuth, now)
+	record(transit, "transit/encrypt/app", map[string]interface{}{
+		"batch_input": []interface{}{map[string]interface{}{}, map[string]interface{}{}, map[string]interface{}{}},
+	}, alice, now)
+	record(transit, "transit/sign/app/sha2-256", nil, rootAuth, now)
+	record(transit, "transit/datakey/plaintext/app", nil, rootAuth, now)
+
+	// Requests which do not use a role or a key are not counted
+	record(pki, "pki/root/generate/internal", nil, alice, now)
+	record(transit, "transit/keys/app", nil, alice, now)
+	record(transit, "transit/random/32", nil, alice, now)
+
+	req := logical.TestRequest(t, logical.ReadOperation, "sys/internal/counters/engine-usage")
+	req.ClientToken = root
+	req.Data = map[string]interface{}{
+		"start_date": yesterday.UTC().Format(engineUsageDateFormat),
+		"top":        1,
+	}
+	resp, err := c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	schema.ValidateResponse(
+		t,
+		schema.GetResponseSchema(t, c.systemBackend.Route("internal/counters/engine-usage"), req.Operation),
+		resp,
+		true,
+	)
+
+	report := resp.Data["counters"].(*engineUsageReport)
+	require.Equal(t, []*engineUsageReportDay{
+		{Date: yesterday.UTC().Format(engineUsageDateFormat), PKIIssuances: 1},
+		{Date: now.UTC().Format(engineUsageDateFormat), PKIIssuances: 2, TransitOperations: 5},
+	}, report.Days)
+	require.Equal(t, map[string]*engineUsageReportPKI{
+		"pki/": {Issuances: 3, Roles: map[string]uint64{"web": 2, "internal": 1}},
+	}, report.PKI)
+	require.Equal(t, map[string]*engineUsageReportTransit{
+		"transit/": {Operations: 5, Keys: map[string]map[string]uint64{
+			"app": {"encrypt": 3, "sign": 1, "datakey": 1},
+		}},
+	}, report.Transit)
+	require.Equal(t, []*engineUsageReportConsumer{
+		{Consumer: "alice", PKIIssuances: 2, TransitOperations: 3, Total: 5},
+	}, report.TopConsumers)
+
+	// The usage was stored, and is added to on the next flush
+	record(pki, "pki/issue/web", nil, alice, now)
+	c.flushEngineUsage(ctx)
+	day, err := readEngineUsageDay(ctx, c.engineUsageView(), now.UTC().Format(engineUsageDateFormat))
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), day.Mounts["pki_1234"].Roles["web"])
+
+	req.Data = map[string]interface{}{"start_date": "2026-13-01"}
+	_, err = c.HandleRequest(ctx, req)
+	require.ErrorIs(t, err, logical.ErrInvalidRequest)
+}
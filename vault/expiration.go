@@ -138,7 +138,10 @@ type ExpirationManager struct {
 	restoreRequestLock sync.RWMutex
 	restoreLocks       []*locksutil.LockEntry
 	restoreLoaded      sync.Map
-	quitCh             chan struct{}
+	// restoreMounts holds the *mountRestore tracking the restoration of the
+	// leases of each mount, keyed by the path of the mount
+	restoreMounts sync.Map
+	quitCh        chan struct{}
 
 	// do not hold coreStateLock in any API handler code - it is already held
 	coreStateLock     locking.RWMutex
@@ -155,6 +158,20 @@ type ExpirationManager struct {
 
 	jobManager      *fairshare.JobManager
 	revokeRetryBase time.Duration
+
+	// revocationsSucceeded and revocationsFailed count the revocation jobs
+	// executed since the revocation checkpoint was loaded.
+	revocationsSucceeded atomic.Uint64
+	revocationsFailed    atomic.Uint64
+
+	// revocationRateLimit is the number of revocations started per second,
+	// zero meaning no limit
+	revocationRateLimit atomic.Int64
+
+	// checkpointLock protects the revocation checkpoint fields below
+	checkpointLock       sync.Mutex
+	revocationCheckpoint *LeaseRevocationCheckpoint
+	lastCheckpoint       *LeaseRevocationCheckpoint
 }
 
 type ExpireLeaseStrategy func(context.Context, *ExpirationManager, string, *namespace.Namespace)
@@ -232,11 +249,15 @@ func (r *revocationJob) Execute() error {
 	err := r.m.Revoke(revokeCtx, r.leaseID)
 	r.m.coreStateLock.RUnlock()
 
+	if err == nil {
+		r.m.revocationsSucceeded.Add(1)
+	}
 	return err
 }
 
 func (r *revocationJob) OnFailure(err error) {
 	r.m.core.metricSink.IncrCounterWithLabels([]string{"expire", "lease_expiration", "error"}, 1, []metrics.Label{metricsutil.NamespaceLabel(r.ns)})
+	r.m.revocationsFailed.Add(1)
 
 	r.m.pendingLock.Lock()
 	pendingRaw, ok := r.m.pending.Load(r.leaseID)
@@ -294,7 +315,33 @@ func expireLeaseStrategyFairsharing(ctx context.Context, m *ExpirationManager, l
 		return
 	}
 
-	m.jobManager.AddJob(job, mountAccessor)
+	m.jobManager.AddJobWithPriority(job, mountAccessor, m.revocationPriority(leaseID))
+}
+
+// revocationPriority returns the priority of the revocation of the given
+// lease. Retries, and leases that already expired when they were restored,
+// are revoked with a low priority, so that a backlog of revocations does not
+// delay the revocation of leases expiring from now on.
+func (m *ExpirationManager) revocationPriority(leaseID string) fairshare.Priority {
+	if m.inRestoreMode() {
+		return fairshare.PriorityLow
+	}
+
+	m.pendingLock.RLock()
+	pendingRaw, ok := m.pending.Load(leaseID)
+	m.pendingLock.RUnlock()
+	if ok && pendingRaw.(pendingInfo).revokesAttempted > 0 {
+		return fairshare.PriorityLow
+	}
+
+	return fairshare.PriorityHigh
+}
+
+// setRevocationRateLimit limits the number of revocations started per
+// second. A limit of zero removes the limit.
+func (m *ExpirationManager) setRevocationRateLimit(limit int) {
+	m.revocationRateLimit.Store(int64(limit))
+	m.jobManager.SetRateLimit(float64(limit), limit)
 }
 
 func (r *revocationJob) revokeExponentialBackoff(attempt uint8) time.Duration {
@@ -367,6 +414,7 @@ func NewExpirationManager(c *Core, view *BarrierView, e ExpireLeaseStrategy, log
 		revokeRetryBase: c.expirationRevokeRetryBase,
 	}
 	exp.expireFunc.Store(&e)
+	exp.setRevocationRateLimit(c.leaseRevocationRateLimit)
 	if exp.revokeRetryBase == 0 {
 		exp.revokeRetryBase = revokeRetryBase
 	}
@@ -412,6 +460,10 @@ func (c *Core) setupExpiration(e ExpireLeaseStrategy) error {
 	// Link the token store to this
 	c.tokenStore.SetExpirationManager(mgr)
 
+	if err := mgr.loadRevocationCheckpoint(c.activeContext); err != nil {
+		c.logger.Warn("failed to load lease revocation checkpoint", "error", err)
+	}
+
 	// Restore the existing state
 	c.logger.Info("restoring leases")
 	errorFunc := func() {
@@ -425,6 +477,8 @@ func (c *Core) setupExpiration(e ExpireLeaseStrategy) error {
 	quit := c.expiration.quitCh
 	go func() {
 		t := time.NewTimer(24 * time.Hour)
+		checkpoint := time.NewTicker(leaseRevocationCheckpointInterval)
+		defer checkpoint.Stop()
 		for {
 			select {
 			case <-quit:
@@ -432,6 +486,10 @@ func (c *Core) setupExpiration(e ExpireLeaseStrategy) error {
 			case <-t.C:
 				c.expiration.attemptIrrevocableLeasesRevoke()
 				t.Reset(24 * time.Hour)
+			case <-checkpoint.C:
+				if err := mgr.persistRevocationCheckpoint(mgr.quitContext); err != nil {
+					mgr.logger.Warn("failed to persist lease revocation checkpoint", "error", err)
+				}
 			}
 		}
 	}()
@@ -678,24 +736,124 @@ func (m *ExpirationManager) Restore(errorFunc func()) (retErr error) {
 		}
 	}()
 
-	// Accumulate existing leases
-	m.logger.Debug("collecting leases")
-	existing, leaseCount, err := m.collectLeases()
+	// The leases are restored mount by mount, in the background. The leases
+	// of a mount are restored ahead when it is first accessed, see
+	// restoreMountLeases.
+	m.logger.Debug("collecting mounts")
+	mounts := m.restoreMountPaths()
+	progress := m.core.postUnsealProgress.start(postUnsealPhaseLeases, len(mounts)+1)
+	defer func() {
+		progress.done(retErr)
+	}()
+
+	for _, mount := range mounts {
+		if err := m.restoreMount(mount); err != nil {
+			return err
+		}
+		progress.increment()
+	}
+
+	// Restore the leases left by mounts that no longer exist
+	keys, err := m.collectUnmountedLeases(mounts)
 	if err != nil {
 		return err
 	}
-	m.logger.Debug("leases collected", "num_existing", leaseCount)
+	if err := m.restoreLeases(keys); err != nil {
+		return err
+	}
+	progress.increment()
+
+	m.restoreModeLock.Lock()
+	atomic.StoreInt32(m.restoreMode, 0)
+	m.restoreLoaded.Range(func(k, v interface{}) bool {
+		m.restoreLoaded.Delete(k)
+		return true
+	})
+	m.restoreMounts.Range(func(k, v interface{}) bool {
+		m.restoreMounts.Delete(k)
+		return true
+	})
+	m.restoreLocks = nil
+	m.restoreModeLock.Unlock()
+
+	m.logger.Info("lease restore complete")
+	return nil
+}
+
+// mountRestore tracks the restoration of the leases of a mount.
+type mountRestore struct {
+	once sync.Once
+	err  error
+}
+
+// restoreMountPaths returns the paths of the mounts that can hold leases, in
+// the order their leases are restored.
+func (m *ExpirationManager) restoreMountPaths() []string {
+	var paths []string
+
+	m.core.mountsLock.RLock()
+	for _, entry := range m.core.mounts.Entries {
+		paths = append(paths, entry.APIPathNoNamespace())
+	}
+	m.core.mountsLock.RUnlock()
+
+	m.core.authLock.RLock()
+	for _, entry := range m.core.auth.Entries {
+		paths = append(paths, entry.APIPathNoNamespace())
+	}
+	m.core.authLock.RUnlock()
+
+	sort.Strings(paths)
+	return paths
+}
+
+// restoreMountLeases restores the leases of the mount of the given path, if
+// they are not restored yet. Requests to a mount wait for its leases to be
+// restored, so that they are accounted for, e.g. by lease count quotas,
+// without waiting for the restoration of the leases of all the mounts.
+func (m *ExpirationManager) restoreMountLeases(ctx context.Context, path string) error {
+	if !m.inRestoreMode() {
+		return nil
+	}
+
+	entry := m.router.MatchingMountEntry(ctx, path)
+	if entry == nil {
+		return nil
+	}
+	return m.restoreMount(entry.APIPathNoNamespace())
+}
+
+// restoreMount restores the leases of the mount at the given path once,
+// waiting for the restoration if it is already in progress.
+func (m *ExpirationManager) restoreMount(mount string) error {
+	raw, _ := m.restoreMounts.LoadOrStore(mount, &mountRestore{})
+	restore := raw.(*mountRestore)
+	restore.once.Do(func() {
+		m.logger.Debug("restoring leases of mount", "path", mount)
+		keys, err := logical.CollectKeys(m.quitContext, m.leaseView(namespace.RootNamespace).SubView(mount))
+		if err != nil {
+			restore.err = fmt.Errorf("failed to scan for leases of mount %q: %w", mount, err)
+			return
+		}
+		for i, key := range keys {
+			keys[i] = mount + key
+		}
+		restore.err = m.restoreLeases(keys)
+	})
+	return restore.err
+}
 
-	// Make the channels used for the worker pool
-	type lease struct {
-		namespace *namespace.Namespace
-		id        string
+// restoreLeases restores the given leases, using a pool of workers.
+func (m *ExpirationManager) restoreLeases(leaseIDs []string) error {
+	if len(leaseIDs) == 0 {
+		return nil
 	}
-	broker := make(chan *lease)
+
+	broker := make(chan string)
 	quit := make(chan bool)
 	// Buffer these channels to prevent deadlocks
-	errs := make(chan error, leaseCount)
-	result := make(chan struct{}, leaseCount)
+	errs := make(chan error, len(leaseIDs))
+	result := make(chan struct{}, len(leaseIDs))
 
 	// Use a wait group
 	wg := &sync.WaitGroup{}
@@ -706,16 +864,16 @@ func (m *ExpirationManager) Restore(errorFunc func()) (retErr error) {
 		go func() {
 			defer wg.Done()
 
+			ctx := namespace.ContextWithNamespace(m.quitContext, namespace.RootNamespace)
 			for {
 				select {
-				case lease, ok := <-broker:
+				case leaseID, ok := <-broker:
 					// broker has been closed, we are done
 					if !ok {
 						return
 					}
 
-					ctx := namespace.ContextWithNamespace(m.quitContext, lease.namespace)
-					err := m.processRestore(ctx, lease.id)
+					err := m.processRestore(ctx, leaseID)
 					if err != nil {
 						errs <- err
 						continue
@@ -739,27 +897,19 @@ func (m *ExpirationManager) Restore(errorFunc func()) (retErr error) {
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-		i := 0
-		for ns := range existing {
-			for _, leaseID := range existing[ns] {
-				i++
-				if i%500 == 0 {
-					m.logger.Debug("leases loading", "progress", i)
-				}
+		for i, leaseID := range leaseIDs {
+			if (i+1)%500 == 0 {
+				m.logger.Debug("leases loading", "progress", i+1)
+			}
 
-				select {
-				case <-quit:
-					return
+			select {
+			case <-quit:
+				return
 
-				case <-m.quitCh:
-					return
+			case <-m.quitCh:
+				return
 
-				default:
-					broker <- &lease{
-						namespace: ns,
-						id:        leaseID,
-					}
-				}
+			case broker <- leaseID:
 			}
 		}
 
@@ -768,8 +918,9 @@ func (m *ExpirationManager) Restore(errorFunc func()) (retErr error) {
 	}()
 
 	// Ensure all keys on the chan are processed
+	var err error
 LOOP:
-	for i := 0; i < leaseCount; i++ {
+	for i := 0; i < len(leaseIDs); i++ {
 		select {
 		case err = <-errs:
 			// Close all go routines
@@ -777,6 +928,7 @@ LOOP:
 			break LOOP
 
 		case <-m.quitCh:
+			err = context.Canceled
 			close(quit)
 			break LOOP
 
@@ -786,21 +938,7 @@ LOOP:
 
 	// Let all go routines finish
 	wg.Wait()
-	if err != nil {
-		return err
-	}
-
-	m.restoreModeLock.Lock()
-	atomic.StoreInt32(m.restoreMode, 0)
-	m.restoreLoaded.Range(func(k, v interface{}) bool {
-		m.restoreLoaded.Delete(k)
-		return true
-	})
-	m.restoreLocks = nil
-	m.restoreModeLock.Unlock()
-
-	m.logger.Info("lease restore complete")
-	return nil
+	return err
 }
 
 // processRestore takes a lease and restores it in the expiration manager if it has
@@ -1052,7 +1190,7 @@ func (m *ExpirationManager) revokeCommon(ctx context.Context, leaseID string, fo
 func (m *ExpirationManager) RevokeForce(ctx context.Context, prefix string) error {
 	defer metrics.MeasureSince([]string{"expire", "revoke-force"}, time.Now())
 
-	return m.revokePrefixCommon(ctx, prefix, true, true)
+	return m.revokePrefixCommon(ctx, prefix, true, true, nil)
 }
 
 // RevokePrefix is used to revoke all secrets with a given prefix.
@@ -1061,7 +1199,16 @@ func (m *ExpirationManager) RevokeForce(ctx context.Context, prefix string) erro
 func (m *ExpirationManager) RevokePrefix(ctx context.Context, prefix string, sync bool) error {
 	defer metrics.MeasureSince([]string{"expire", "revoke-prefix"}, time.Now())
 
-	return m.revokePrefixCommon(ctx, prefix, false, sync)
+	return m.revokePrefixCommon(ctx, prefix, false, sync, nil)
+}
+
+// RevokePrefixWithProgress works like a synchronous RevokePrefix, calling
+// progress with the number of revoked leases out of the total after each
+// revocation.
+func (m *ExpirationManager) RevokePrefixWithProgress(ctx context.Context, prefix string, progress func(revoked, total int)) error {
+	defer metrics.MeasureSince([]string{"expire", "revoke-prefix"}, time.Now())
+
+	return m.revokePrefixCommon(ctx, prefix, false, true, progress)
 }
 
 // RevokeByToken is used to revoke all the secrets issued with a given token.
@@ -1124,7 +1271,8 @@ func (m *ExpirationManager) RevokeByToken(ctx context.Context, te *logical.Token
 // if sync == true, revoke immediately (using a single worker).
 // otherwise, mark the lease as expiring  `now` and let the expiration manager
 // queue it for revocation.
-func (m *ExpirationManager) revokePrefixCommon(ctx context.Context, prefix string, force, sync bool) error {
+// if progress is not nil, it is called after each lease is handled.
+func (m *ExpirationManager) revokePrefixCommon(ctx context.Context, prefix string, force, sync bool, progress func(revoked, total int)) error {
 	if m.inRestoreMode() {
 		m.restoreRequestLock.Lock()
 		defer m.restoreRequestLock.Unlock()
@@ -1158,6 +1306,10 @@ func (m *ExpirationManager) revokePrefixCommon(ctx context.Context, prefix strin
 		return fmt.Errorf("failed to scan for leases: %w", err)
 	}
 
+	if progress != nil {
+		progress(0, len(existing))
+	}
+
 	// Revoke all the keys
 	for idx, suffix := range existing {
 		leaseID := prefix + suffix
@@ -1172,6 +1324,9 @@ func (m *ExpirationManager) revokePrefixCommon(ctx context.Context, prefix strin
 				return fmt.Errorf("failed to revoke %q (%d / %d): %w", leaseID, idx+1, len(existing), err)
 			}
 		}
+		if progress != nil {
+			progress(idx+1, len(existing))
+		}
 	}
 
 	return nil
@@ -1236,6 +1391,24 @@ func (m *ExpirationManager) Renew(ctx context.Context, leaseID string, increment
 		return nil, nil
 	}
 
+	// TTL policies cap the lifetime of the lease, as requested by the entity
+	// of the token it was issued to
+	ttlPolicies, err := m.core.loadTTLPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(ttlPolicies) > 0 {
+		entityID := ""
+		if te, err := m.tokenStore.Lookup(ctx, le.ClientToken); err == nil && te != nil {
+			entityID = te.EntityID
+		}
+		_, policyMaxTTL, err := m.core.ttlPolicyLimits(ctx, le.namespace, le.Path, entityID)
+		if err != nil {
+			return nil, err
+		}
+		_, resp.Secret.MaxTTL = applyTTLPolicy(sysView, resp.Secret.TTL, resp.Secret.MaxTTL, 0, policyMaxTTL)
+	}
+
 	ttl, warnings, err := framework.CalculateTTL(sysView, increment, resp.Secret.TTL, 0, resp.Secret.MaxTTL, 0, le.IssueTime)
 	if err != nil {
 		return nil, err
@@ -2333,6 +2506,10 @@ func (m *ExpirationManager) emitMetrics() {
 	metrics.SetGauge([]string{"expire", "num_leases"}, float32(allLeases))
 
 	metrics.SetGauge([]string{"expire", "num_irrevocable_leases"}, float32(irrevocableLeases))
+
+	for priority, count := range m.jobManager.GetPendingJobCountsByPriority() {
+		metrics.SetGaugeWithLabels([]string{"expire", "revocation_queue", "depth"}, float32(count), []metrics.Label{{Name: "priority", Value: priority.String()}})
+	}
 	// Check if lease count is greater than the threshold
 	if allLeases > maxLeaseThreshold {
 		if atomic.LoadUint32(m.leaseCheckCounter) > 59 {
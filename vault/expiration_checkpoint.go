@@ -0,0 +1,145 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const (
+	// leaseRevocationCheckpointPath is the barrier path of the lease
+	// revocation checkpoint.
+	leaseRevocationCheckpointPath = "core/lease-revocation-checkpoint"
+
+	// leaseRevocationCheckpointInterval is the interval at which the lease
+	// revocation checkpoint is persisted, if it changed.
+	leaseRevocationCheckpointInterval = 30 * time.Second
+)
+
+// LeaseRevocationCheckpoint records the progress of the revocation of
+// expired leases. It is persisted periodically, so that the progress of a
+// revocation backlog can be followed across restarts and leadership
+// changes. The backlog itself does not need to be persisted, as it is
+// rebuilt from the lease entries when leases are restored.
+type LeaseRevocationCheckpoint struct {
+	Time time.Time `json:"time"`
+
+	// Pending is the number of revocations waiting for a worker, by
+	// priority.
+	Pending map[string]int `json:"pending"`
+
+	// Revoked and Failed are the total number of revocations that
+	// succeeded and failed.
+	Revoked uint64 `json:"revoked"`
+	Failed  uint64 `json:"failed"`
+}
+
+func (c *LeaseRevocationCheckpoint) toMap() map[string]interface{} {
+	pending := 0
+	for _, n := range c.Pending {
+		pending += n
+	}
+
+	return map[string]interface{}{
+		"time":                c.Time,
+		"pending":             pending,
+		"pending_by_priority": c.Pending,
+		"revoked":             c.Revoked,
+		"failed":              c.Failed,
+	}
+}
+
+// revocationStatus returns the current progress of lease revocations.
+func (m *ExpirationManager) revocationStatus() *LeaseRevocationCheckpoint {
+	m.checkpointLock.Lock()
+	base := m.revocationCheckpoint
+	m.checkpointLock.Unlock()
+
+	status := &LeaseRevocationCheckpoint{
+		Time:    time.Now().UTC(),
+		Pending: make(map[string]int),
+		Revoked: m.revocationsSucceeded.Load(),
+		Failed:  m.revocationsFailed.Load(),
+	}
+	if base != nil {
+		status.Revoked += base.Revoked
+		status.Failed += base.Failed
+	}
+	for priority, count := range m.jobManager.GetPendingJobCountsByPriority() {
+		status.Pending[priority.String()] = count
+	}
+	return status
+}
+
+// loadRevocationCheckpoint loads the last persisted lease revocation
+// checkpoint, from which the revocation totals are carried over.
+func (m *ExpirationManager) loadRevocationCheckpoint(ctx context.Context) error {
+	entry, err := m.core.barrier.Get(ctx, leaseRevocationCheckpointPath)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		return nil
+	}
+
+	checkpoint := new(LeaseRevocationCheckpoint)
+	if err := entry.DecodeJSON(checkpoint); err != nil {
+		return fmt.Errorf("failed to decode lease revocation checkpoint: %w", err)
+	}
+
+	pending := 0
+	for _, n := range checkpoint.Pending {
+		pending += n
+	}
+	if pending > 0 {
+		m.logger.Info("resuming lease revocation backlog", "checkpoint", checkpoint.Time, "pending", pending)
+	}
+
+	m.checkpointLock.Lock()
+	defer m.checkpointLock.Unlock()
+	m.revocationCheckpoint = checkpoint
+	m.lastCheckpoint = checkpoint
+	return nil
+}
+
+// persistRevocationCheckpoint persists the current progress of lease
+// revocations, unless it did not change since the last checkpoint.
+func (m *ExpirationManager) persistRevocationCheckpoint(ctx context.Context) error {
+	status := m.revocationStatus()
+
+	m.checkpointLock.Lock()
+	defer m.checkpointLock.Unlock()
+
+	if last := m.lastCheckpoint; last != nil && last.Revoked == status.Revoked &&
+		last.Failed == status.Failed && reflect.DeepEqual(last.Pending, status.Pending) {
+		return nil
+	}
+
+	entry, err := logical.StorageEntryJSON(leaseRevocationCheckpointPath, status)
+	if err != nil {
+		return err
+	}
+	if err := m.core.barrier.Put(ctx, entry); err != nil {
+		return err
+	}
+
+	m.lastCheckpoint = status
+	return nil
+}
+
+// revocationQueueStatus returns the current progress of lease revocations
+// along with the last persisted checkpoint.
+func (m *ExpirationManager) revocationQueueStatus() map[string]interface{} {
+	status := m.revocationStatus().toMap()
+	status["rate_limit"] = m.revocationRateLimit.Load()
+
+	m.checkpointLock.Lock()
+	defer m.checkpointLock.Unlock()
+	if m.lastCheckpoint != nil {
+		status["checkpoint"] = m.lastCheckpoint.toMap()
+	}
+	return status
+}
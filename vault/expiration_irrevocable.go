@@ -0,0 +1,149 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openbao/openbao/helper/namespace"
+)
+
+// irrevocableLeaseFilter selects irrevocable leases visible from the
+// namespace of a request.
+type irrevocableLeaseFilter struct {
+	includeChildNamespaces bool
+
+	// mountAccessor, if set, selects the leases of the given mount
+	mountAccessor string
+
+	// errorContains, if set, selects the leases whose revocation error
+	// contains the given string
+	errorContains string
+}
+
+// irrevocableLease is an irrevocable lease matching a filter, along with its
+// namespace.
+type irrevocableLease struct {
+	*leaseResponse
+	ns *namespace.Namespace
+}
+
+// matchingIrrevocableLeases returns the irrevocable leases matching the
+// given filter, sorted by increasing expire time and lease ID.
+func (m *ExpirationManager) matchingIrrevocableLeases(ctx context.Context, filter *irrevocableLeaseFilter) ([]*irrevocableLease, error) {
+	requestNS, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var matching []*irrevocableLease
+	m.irrevocable.Range(func(k, v interface{}) bool {
+		leaseID := k.(string)
+		leaseInfo := v.(*leaseEntry)
+
+		leaseNS, err := m.getNamespaceFromLeaseID(ctx, leaseID)
+		if err != nil {
+			m.logger.Warn("could not get lease namespace from ID", "error", err)
+			return true
+		}
+		if leaseNS != requestNS && !(filter.includeChildNamespaces && leaseNS.HasParent(requestNS)) {
+			return true
+		}
+
+		mountAccessor := m.getLeaseMountAccessor(ctx, leaseID)
+		if filter.mountAccessor != "" && filter.mountAccessor != mountAccessor {
+			return true
+		}
+		if filter.errorContains != "" && !strings.Contains(leaseInfo.RevokeErr, filter.errorContains) {
+			return true
+		}
+
+		matching = append(matching, &irrevocableLease{
+			leaseResponse: &leaseResponse{
+				LeaseID:    leaseID,
+				MountID:    mountAccessor,
+				ErrMsg:     leaseInfo.RevokeErr,
+				expireTime: leaseInfo.ExpireTime,
+			},
+			ns: leaseNS,
+		})
+		return true
+	})
+
+	sort.Slice(matching, func(i, j int) bool {
+		if !matching[i].expireTime.Equal(matching[j].expireTime) {
+			return matching[i].expireTime.Before(matching[j].expireTime)
+		}
+		return matching[i].LeaseID < matching[j].LeaseID
+	})
+
+	return matching, nil
+}
+
+// irrevocableLeasesByMount returns the number of irrevocable leases per
+// mount, along with the number of leases per revocation error.
+func (m *ExpirationManager) irrevocableLeasesByMount(ctx context.Context, includeChildNamespaces bool) (map[string]interface{}, error) {
+	leases, err := m.matchingIrrevocableLeases(ctx, &irrevocableLeaseFilter{
+		includeChildNamespaces: includeChildNamespaces,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	type mountSummary struct {
+		count  int
+		errors map[string]int
+	}
+	summaries := make(map[string]*mountSummary)
+	for _, lease := range leases {
+		summary, ok := summaries[lease.MountID]
+		if !ok {
+			summary = &mountSummary{errors: make(map[string]int)}
+			summaries[lease.MountID] = summary
+		}
+		summary.count++
+		summary.errors[lease.ErrMsg]++
+	}
+
+	mounts := make(map[string]interface{}, len(summaries))
+	for accessor, summary := range summaries {
+		info := map[string]interface{}{
+			"lease_count": summary.count,
+			"errors":      summary.errors,
+		}
+		if entry := m.core.router.MatchingMountByAccessor(accessor); entry != nil {
+			info["mount_path"] = entry.Namespace().Path + entry.Path
+			info["mount_type"] = entry.Type
+		}
+		mounts[accessor] = info
+	}
+
+	return map[string]interface{}{
+		"lease_count": len(leases),
+		"mounts":      mounts,
+	}, nil
+}
+
+// revokeIrrevocableLeases force revokes the given irrevocable leases. The
+// revocation is attempted against the backend once more, but the leases are
+// removed even if it fails. It returns the IDs of the leases that could not
+// be removed, along with the error.
+func (m *ExpirationManager) revokeIrrevocableLeases(ctx context.Context, leases []*irrevocableLease) map[string]string {
+	failed := make(map[string]string)
+	for _, lease := range leases {
+		leaseCtx := namespace.ContextWithNamespace(ctx, lease.ns)
+		if err := m.revokeCommon(leaseCtx, lease.LeaseID, true, false); err != nil {
+			failed[lease.LeaseID] = err.Error()
+			continue
+		}
+		m.logger.Warn("force revoked irrevocable lease", "lease_id", lease.LeaseID, "mount_accessor", lease.MountID, "revocation_error", lease.ErrMsg)
+	}
+	return failed
+}
+
+// errIrrevocableLeaseCountMismatch is returned when the confirmed number of
+// leases to force revoke does not match the number of matching leases.
+func errIrrevocableLeaseCountMismatch(confirmed, matching int) error {
+	return fmt.Errorf("confirm_count %d does not match the %d matching leases; run again with dry_run to review the leases", confirmed, matching)
+}
@@ -897,6 +897,79 @@ func TestExpiration_Restore(t *testing.T) {
 	}
 }
 
+func TestExpiration_RestoreMountLeases(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+	exp := c.expiration
+	_, barrier, _ := mockBarrier(t)
+	view := NewBarrierView(barrier, "logical/")
+	for _, path := range []string{"prod/aws/", "prod/gcp/"} {
+		meUUID, err := uuid.GenerateUUID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = exp.router.Mount(&NoopBackend{}, path, &MountEntry{Path: path, Type: "noop", UUID: meUUID, Accessor: "noop-accessor-" + meUUID, namespace: namespace.RootNamespace}, view)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	leases := make(map[string]string)
+	for _, path := range []string{"prod/aws/foo", "prod/gcp/bar"} {
+		req := &logical.Request{
+			Operation:   logical.ReadOperation,
+			Path:        path,
+			ClientToken: "foobar",
+		}
+		req.SetTokenEntry(&logical.TokenEntry{ID: "foobar", NamespaceID: "root"})
+		resp := &logical.Response{
+			Secret: &logical.Secret{
+				LeaseOptions: logical.LeaseOptions{
+					TTL: time.Hour,
+				},
+			},
+		}
+		leaseID, err := exp.Register(namespace.RootContext(nil), req, resp, "")
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		leases[path] = leaseID
+	}
+
+	if err := c.stopExpiration(); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	exp = NewExpirationManager(c, c.systemBarrierView.SubView(expirationSubPath), expireNoop, c.logger, false)
+	defer exp.Stop()
+
+	// Accessing a mount restores its leases only
+	if err := exp.restoreMountLeases(namespace.RootContext(nil), "prod/aws/foo"); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if _, ok := exp.pending.Load(leases["prod/aws/foo"]); !ok {
+		t.Fatal("expected lease of accessed mount to be restored")
+	}
+	if _, ok := exp.pending.Load(leases["prod/gcp/bar"]); ok {
+		t.Fatal("expected lease of other mount not to be restored")
+	}
+	if !exp.inRestoreMode() {
+		t.Fatal("expected restore mode")
+	}
+
+	// The background restore restores the leases of the other mounts
+	if err := exp.Restore(nil); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	for path, leaseID := range leases {
+		if _, ok := exp.pending.Load(leaseID); !ok {
+			t.Fatalf("expected lease of %q to be restored", path)
+		}
+	}
+	if exp.inRestoreMode() {
+		t.Fatal("expected restore mode to be over")
+	}
+}
+
 func TestExpiration_Register(t *testing.T) {
 	exp := mockExpiration(t)
 	req := &logical.Request{
@@ -3500,3 +3573,147 @@ func TestExpiration_listIrrevocableLeases_includeAll(t *testing.T) {
 		t.Errorf("bad lease count. expected %d, got %d", expectedNumLeases, numLeases)
 	}
 }
+
+func TestExpiration_RevocationQueue(t *testing.T) {
+	exp := mockExpiration(t)
+	ctx := namespace.RootContext(nil)
+
+	// Retries are revoked with a low priority
+	exp.pendingLock.Lock()
+	exp.pending.Store("retried", pendingInfo{revokesAttempted: 1})
+	exp.pendingLock.Unlock()
+	if p := exp.revocationPriority("retried"); p != fairshare.PriorityLow {
+		t.Fatalf("expected low priority for retried revocation, got %s", p)
+	}
+	if p := exp.revocationPriority("fresh"); p != fairshare.PriorityHigh {
+		t.Fatalf("expected high priority for fresh revocation, got %s", p)
+	}
+
+	// Revocation totals are carried over from the persisted checkpoint
+	exp.revocationsSucceeded.Add(3)
+	exp.revocationsFailed.Add(1)
+	if err := exp.persistRevocationCheckpoint(ctx); err != nil {
+		t.Fatal(err)
+	}
+	exp.revocationsSucceeded.Store(0)
+	exp.revocationsFailed.Store(0)
+	if err := exp.loadRevocationCheckpoint(ctx); err != nil {
+		t.Fatal(err)
+	}
+	status := exp.revocationStatus()
+	if status.Revoked != 3 || status.Failed != 1 {
+		t.Fatalf("bad status: %#v", status)
+	}
+
+	exp.setRevocationRateLimit(100)
+	req := logical.TestRequest(t, logical.ReadOperation, "leases/revocation-queue")
+	resp, err := exp.core.systemBackend.HandleRequest(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data["rate_limit"] != int64(100) || resp.Data["revoked"] != uint64(3) {
+		t.Fatalf("bad response: %#v", resp.Data)
+	}
+	if resp.Data["checkpoint"] == nil {
+		t.Fatalf("expected checkpoint in response: %#v", resp.Data)
+	}
+}
+
+func TestExpiration_RevokeForceIrrevocableLeases(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	backends := []*backend{
+		{
+			path: "foo/bar/1/",
+			ns:   namespace.RootNamespace,
+		},
+		{
+			path: "foo/bar/2/",
+			ns:   namespace.RootNamespace,
+		},
+	}
+	pathToMount, err := mountNoopBackends(c, backends)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		for _, backend := range backends {
+			if _, err := c.AddIrrevocableLease(ctx, backend.path); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	req := logical.TestRequest(t, logical.ReadOperation, "leases/irrevocable")
+	resp, err := c.systemBackend.HandleRequest(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data["lease_count"] != 6 {
+		t.Fatalf("bad response: %#v", resp.Data)
+	}
+	mounts := resp.Data["mounts"].(map[string]interface{})
+	mount := mounts[pathToMount["foo/bar/1/"]].(map[string]interface{})
+	if mount["lease_count"] != 3 || mount["mount_path"] != "foo/bar/1/" {
+		t.Fatalf("bad mount: %#v", mount)
+	}
+	if errs := mount["errors"].(map[string]int); errs["some error message"] != 3 {
+		t.Fatalf("bad errors: %#v", errs)
+	}
+
+	revokeForce := func(data map[string]interface{}) *logical.Response {
+		t.Helper()
+		req := logical.TestRequest(t, logical.UpdateOperation, "leases/irrevocable/revoke-force")
+		req.Data = data
+		resp, err := c.systemBackend.HandleRequest(ctx, req)
+		if err != nil && err != logical.ErrInvalidRequest {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	// Dry runs are the default
+	resp = revokeForce(map[string]interface{}{
+		"mount_accessor": pathToMount["foo/bar/1/"],
+	})
+	if resp.Data["lease_count"] != 3 || len(resp.Data["leases"].([]*leaseResponse)) != 3 {
+		t.Fatalf("bad response: %#v", resp.Data)
+	}
+
+	resp = revokeForce(map[string]interface{}{
+		"error_contains": "other error",
+	})
+	if resp.Data["lease_count"] != 0 {
+		t.Fatalf("bad response: %#v", resp.Data)
+	}
+
+	// The count of leases to revoke must be confirmed
+	for _, data := range []map[string]interface{}{
+		{"mount_accessor": pathToMount["foo/bar/1/"], "dry_run": false},
+		{"mount_accessor": pathToMount["foo/bar/1/"], "dry_run": false, "confirm_count": 6},
+	} {
+		if resp := revokeForce(data); !resp.IsError() {
+			t.Fatalf("expected error for %#v, got %#v", data, resp)
+		}
+	}
+
+	resp = revokeForce(map[string]interface{}{
+		"mount_accessor": pathToMount["foo/bar/1/"],
+		"error_contains": "some error",
+		"dry_run":        false,
+		"confirm_count":  3,
+	})
+	if resp.Data["revoked"] != 3 || len(resp.Data["failed"].(map[string]string)) != 0 {
+		t.Fatalf("bad response: %#v", resp.Data)
+	}
+
+	counts, err := c.expiration.getIrrevocableLeaseCounts(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counts["lease_count"] != 3 {
+		t.Fatalf("expected 3 remaining irrevocable leases, got %#v", counts)
+	}
+}
@@ -5,9 +5,9 @@ package vault
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/openbao/openbao/helper/namespace"
-	"github.com/openbao/openbao/sdk/v2/logical"
 )
 
 func (m *ExpirationManager) leaseView(*namespace.Namespace) *BarrierView {
@@ -18,14 +18,35 @@ func (m *ExpirationManager) tokenIndexView(*namespace.Namespace) *BarrierView {
 	return m.tokenView
 }
 
-func (m *ExpirationManager) collectLeases() (map[*namespace.Namespace][]string, int, error) {
-	leaseCount := 0
-	existing := make(map[*namespace.Namespace][]string)
-	keys, err := logical.CollectKeys(m.quitContext, m.leaseView(namespace.RootNamespace))
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to scan for leases: %w", err)
+// collectUnmountedLeases collects the leases which are not under any of the
+// given mount paths, without listing the leases of these mounts.
+func (m *ExpirationManager) collectUnmountedLeases(mounts []string) ([]string, error) {
+	skip := make(map[string]struct{}, len(mounts))
+	for _, mount := range mounts {
+		skip[mount] = struct{}{}
 	}
-	existing[namespace.RootNamespace] = keys
-	leaseCount += len(keys)
-	return existing, leaseCount, nil
+
+	view := m.leaseView(namespace.RootNamespace)
+	var keys []string
+	prefixes := []string{""}
+	for len(prefixes) > 0 {
+		prefix := prefixes[len(prefixes)-1]
+		prefixes = prefixes[:len(prefixes)-1]
+
+		children, err := view.List(m.quitContext, prefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan for leases: %w", err)
+		}
+		for _, child := range children {
+			path := prefix + child
+			if !strings.HasSuffix(child, "/") {
+				keys = append(keys, path)
+				continue
+			}
+			if _, ok := skip[path]; !ok {
+				prefixes = append(prefixes, path)
+			}
+		}
+	}
+	return keys, nil
 }
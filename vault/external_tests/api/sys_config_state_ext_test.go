@@ -0,0 +1,57 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/openbao/openbao/api/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSysConfigStateApply(t *testing.T) {
+	t.Parallel()
+
+	client, closer := testVaultServer(t)
+	defer closer()
+
+	input := &api.ConfigStateApplyInput{
+		Auth: map[string]map[string]interface{}{
+			"userpass": {"type": "userpass"},
+		},
+		Mounts: map[string]map[string]interface{}{
+			"transit": {"type": "transit", "description": "encryption"},
+		},
+		Policies: map[string]string{
+			"app": `path "transit/encrypt/app" { capabilities = ["update"] }`,
+		},
+		Resources: map[string]map[string]interface{}{
+			"auth/userpass/users/app": {
+				"password":       "secret",
+				"token_policies": []string{"app"},
+				"token_ttl":      "1h",
+			},
+			"transit/keys/app": {},
+		},
+	}
+
+	out, err := client.Sys().ApplyConfigState(input)
+	require.NoError(t, err)
+	require.Len(t, out.Changes, 5)
+
+	mounts, err := client.Sys().ListMounts()
+	require.NoError(t, err)
+	require.Equal(t, "encryption", mounts["transit/"].Description)
+
+	secret, err := client.Logical().Write("auth/userpass/login/app", map[string]interface{}{"password": "secret"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"app", "default"}, secret.Auth.Policies)
+
+	// Passwords are not returned on read, so the user is updated on every
+	// apply, while the rest of the bundle is unchanged.
+	input.DryRun = true
+	out, err = client.Sys().ApplyConfigState(input)
+	require.NoError(t, err)
+	require.True(t, out.DryRun)
+	require.Equal(t, []*api.ConfigStateChange{
+		{Kind: "resource", Path: "auth/userpass/users/app", Action: "update", Fields: []string{"password"}},
+	}, out.Changes)
+}
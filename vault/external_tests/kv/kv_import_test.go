@@ -0,0 +1,135 @@
+package kv
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/openbao/openbao/api/v2"
+	logicalKv "github.com/openbao/openbao/builtin/logical/kv"
+	vaulthttp "github.com/openbao/openbao/http"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/openbao/openbao/vault"
+	"github.com/stretchr/testify/require"
+)
+
+// TestKV_Import imports the secrets of a KV version 2 mount into another one
+// of the same cluster through sys/import, first in a dry run and then for
+// real.
+func TestKV_Import(t *testing.T) {
+	coreConfig := &vault.CoreConfig{
+		LogicalBackends: map[string]logical.Factory{
+			"kv": logicalKv.VersionedKVFactory,
+		},
+	}
+
+	cluster := vault.NewTestCluster(t, coreConfig, &vault.TestClusterOptions{
+		HandlerFunc: vaulthttp.Handler,
+	})
+	cluster.Start()
+	defer cluster.Cleanup()
+
+	c := cluster.Cores[0].Client
+	vault.TestWaitActive(t, cluster.Cores[0].Core)
+
+	for _, mount := range []string{"src", "dst"} {
+		require.NoError(t, c.Sys().Mount(mount, &api.MountInput{
+			Type: "kv-v2",
+		}))
+	}
+
+	ctx := context.Background()
+	secrets := map[string]map[string]interface{}{
+		"prod/db":     {"password": "db-secret"},
+		"prod/api":    {"key": "api-secret"},
+		"staging/api": {"key": "staging-secret"},
+	}
+	for name, data := range secrets {
+		_, err := kvRequestWithRetry(t, func() (interface{}, error) {
+			return c.KVv2("src").Put(ctx, name, data)
+		})
+		require.NoError(t, err)
+	}
+	_, err := kvRequestWithRetry(t, func() (interface{}, error) {
+		return c.KVv2("dst").Put(ctx, "existing", map[string]interface{}{"foo": "bar"})
+	})
+	require.NoError(t, err)
+
+	startImport := func(dryRun bool) string {
+		resp, err := c.Logical().Write("sys/import", map[string]interface{}{
+			"source": "vault",
+			"source_config": map[string]interface{}{
+				"address": c.Address(),
+				"token":   c.Token(),
+				"ca_cert": string(cluster.CACertPEM),
+				"mount":   "src",
+			},
+			"destination": "dst",
+			"mappings": []map[string]interface{}{
+				{"match": "prod/*", "destination": "production/{{wildcard}}"},
+			},
+			"dry_run": dryRun,
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, resp.Data["id"])
+		return resp.Data["id"].(string)
+	}
+
+	waitImport := func(id string) map[string]interface{} {
+		var status map[string]interface{}
+		require.Eventually(t, func() bool {
+			resp, err := c.Logical().Read("sys/import/" + id)
+			require.NoError(t, err)
+			require.NotNil(t, resp)
+			status = resp.Data
+			return status["status"] != vault.SecretsImportStatusRunning
+		}, 30*time.Second, 100*time.Millisecond)
+		require.Equal(t, vault.SecretsImportStatusCompleted, status["status"], "status: %#v", status)
+		return status
+	}
+
+	// A dry run only reports the plan.
+	id := startImport(true)
+	status := waitImport(id)
+	require.Equal(t, []interface{}{
+		map[string]interface{}{"source": "prod/api", "destination": "production/api"},
+		map[string]interface{}{"source": "prod/db", "destination": "production/db"},
+	}, status["plan"])
+	_, err = c.KVv2("dst").Get(ctx, "production/db")
+	require.ErrorIs(t, err, api.ErrSecretNotFound)
+
+	// A finished import can be removed.
+	resp, err := c.Logical().List("sys/import")
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{id}, resp.Data["keys"])
+	_, err = c.Logical().Delete("sys/import/" + id)
+	require.NoError(t, err)
+	resp, err = c.Logical().Read("sys/import/" + id)
+	require.NoError(t, err)
+	require.Nil(t, resp)
+
+	// The real import writes the mapped secrets and skips the others.
+	status = waitImport(startImport(false))
+	require.Equal(t, json.Number("3"), status["total"])
+	require.Equal(t, json.Number("2"), status["imported"])
+	require.Equal(t, json.Number("1"), status["skipped"])
+	require.Equal(t, json.Number("0"), status["failed"])
+
+	secret, err := c.KVv2("dst").Get(ctx, "production/db")
+	require.NoError(t, err)
+	require.Equal(t, secrets["prod/db"], secret.Data)
+	secret, err = c.KVv2("dst").Get(ctx, "production/api")
+	require.NoError(t, err)
+	require.Equal(t, secrets["prod/api"], secret.Data)
+	_, err = c.KVv2("dst").Get(ctx, "staging/api")
+	require.ErrorIs(t, err, api.ErrSecretNotFound)
+
+	// The destination must be a KV version 2 mount.
+	_, err = c.Logical().Write("sys/import", map[string]interface{}{
+		"source":        "vault",
+		"source_config": map[string]interface{}{"mount": "src"},
+		"destination":   "cubbyhole",
+	})
+	require.Error(t, err)
+}
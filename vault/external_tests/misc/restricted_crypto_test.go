@@ -0,0 +1,111 @@
+package misc
+
+import (
+	"testing"
+
+	"github.com/openbao/openbao/api/v2"
+	"github.com/openbao/openbao/builtin/logical/pki"
+	"github.com/openbao/openbao/builtin/logical/ssh"
+	"github.com/openbao/openbao/builtin/logical/transit"
+	vaulthttp "github.com/openbao/openbao/http"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/openbao/openbao/vault"
+	"github.com/stretchr/testify/require"
+)
+
+func restrictedCryptoCluster(t *testing.T, restricted bool) *api.Client {
+	coreConfig := &vault.CoreConfig{
+		LogicalBackends: map[string]logical.Factory{
+			"transit": transit.Factory,
+			"pki":     pki.Factory,
+			"ssh":     ssh.Factory,
+		},
+		RestrictedCrypto: restricted,
+	}
+	cluster := vault.NewTestCluster(t, coreConfig, &vault.TestClusterOptions{
+		HandlerFunc: vaulthttp.Handler,
+		NumCores:    1,
+	})
+	cluster.Start()
+	t.Cleanup(cluster.Cleanup)
+
+	vault.TestWaitActive(t, cluster.Cores[0].Core)
+	client := cluster.Cores[0].Client
+	for _, mount := range []string{"transit", "pki", "ssh"} {
+		require.NoError(t, client.Sys().Mount(mount, &api.MountInput{Type: mount}))
+	}
+	return client
+}
+
+func TestRestrictedCrypto_Report(t *testing.T) {
+	client := restrictedCryptoCluster(t, false)
+
+	health, err := client.Sys().Health()
+	require.NoError(t, err)
+	require.False(t, health.RestrictedCrypto)
+
+	for path, data := range map[string]map[string]interface{}{
+		"transit/keys/approved":      {"type": "aes256-gcm96"},
+		"transit/keys/chacha":        {"type": "chacha20-poly1305"},
+		"pki/keys/generate/internal": {"key_type": "ed25519", "key_name": "edkey"},
+		"pki/roles/approved":         {"key_type": "ec"},
+		"pki/roles/ed":               {"key_type": "ed25519"},
+		"ssh/config/ca":              {"key_type": "ed25519"},
+	} {
+		_, err := client.Logical().Write(path, data)
+		require.NoError(t, err, path)
+	}
+
+	resp, err := client.Logical().Read("sys/restricted-crypto/report")
+	require.NoError(t, err)
+	require.Equal(t, false, resp.Data["restricted_crypto"])
+
+	var reported []string
+	for _, raw := range resp.Data["keys"].([]interface{}) {
+		key := raw.(map[string]interface{})
+		require.NotEmpty(t, key["name"])
+		reported = append(reported, key["mount"].(string)+key["kind"].(string)+":"+key["key_type"].(string))
+	}
+	require.ElementsMatch(t, []string{
+		"transit/key:chacha20-poly1305",
+		"pki/key:ed25519",
+		"pki/role:ed25519",
+		"ssh/ca:ssh-ed25519",
+	}, reported)
+}
+
+func TestRestrictedCrypto_Enforced(t *testing.T) {
+	client := restrictedCryptoCluster(t, true)
+
+	health, err := client.Sys().Health()
+	require.NoError(t, err)
+	require.True(t, health.RestrictedCrypto)
+
+	for path, data := range map[string]map[string]interface{}{
+		"transit/keys/chacha":        {"type": "chacha20-poly1305"},
+		"transit/keys/ed":            {"type": "ed25519"},
+		"transit/encrypt/upserted":   {"type": "xchacha20-poly1305", "plaintext": "dGVzdA=="},
+		"pki/keys/generate/internal": {"key_type": "ed25519"},
+		"pki/root/generate/internal": {"key_type": "ed25519", "common_name": "example.com"},
+		"pki/roles/ed":               {"key_type": "ed25519"},
+		"ssh/config/ca":              {"key_type": "ed25519"},
+	} {
+		_, err := client.Logical().Write(path, data)
+		require.ErrorContains(t, err, "not allowed in restricted crypto mode", path)
+	}
+
+	for path, data := range map[string]map[string]interface{}{
+		"transit/keys/approved":      {"type": "aes256-gcm96"},
+		"transit/encrypt/approved":   {"plaintext": "dGVzdA=="},
+		"pki/root/generate/internal": {"key_type": "ec", "common_name": "example.com"},
+		"ssh/config/ca":              {"key_type": "ec", "key_bits": 256},
+	} {
+		_, err := client.Logical().Write(path, data)
+		require.NoError(t, err, path)
+	}
+
+	resp, err := client.Logical().Read("sys/restricted-crypto/report")
+	require.NoError(t, err)
+	require.Equal(t, true, resp.Data["restricted_crypto"])
+	require.Empty(t, resp.Data["keys"])
+}
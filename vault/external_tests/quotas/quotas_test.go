@@ -542,3 +542,84 @@ func TestQuotas_RateLimitQuota(t *testing.T) {
 		t.Fatalf("unexpected number of failed requests: %d", numFail)
 	}
 }
+
+func TestQuotas_RateLimitQuota_GroupByEntity(t *testing.T) {
+	conf, opts := teststorage.ClusterSetup(coreConfig, nil, nil)
+	opts.NoDefaultQuotas = true
+	cluster := vault.NewTestCluster(t, conf, opts)
+	cluster.Start()
+	defer cluster.Cleanup()
+
+	core := cluster.Cores[0].Core
+	client := cluster.Cores[0].Client
+
+	vault.TestWaitActive(t, core)
+
+	err := client.Sys().EnableAuthWithOptions("userpass", &api.EnableAuthOptions{
+		Type: "userpass",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both users reach the server from the same address, and are only rate
+	// limited separately because the quota groups the requests by entity.
+	var userClients []*api.Client
+	for _, user := range []string{"foo", "bar"} {
+		_, err = client.Logical().Write("auth/userpass/users/"+user, map[string]interface{}{
+			"password": "baz",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		secret, err := client.Logical().Write("auth/userpass/login/"+user, map[string]interface{}{
+			"password": "baz",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		userClient, err := client.Clone()
+		if err != nil {
+			t.Fatal(err)
+		}
+		userClient.SetToken(secret.Auth.ClientToken)
+		userClients = append(userClients, userClient)
+	}
+
+	_, err = client.Logical().Write("sys/quotas/rate-limit/rlq", map[string]interface{}{
+		"rate":     1,
+		"interval": "1m",
+		"path":     "auth/token/",
+		"group_by": "entity",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret, err := client.Logical().Read("sys/quotas/rate-limit/rlq")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if groupBy := secret.Data["group_by"]; groupBy != "entity" {
+		t.Fatalf("unexpected group_by: %v", groupBy)
+	}
+
+	for _, userClient := range userClients {
+		if _, err := userClient.Auth().Token().LookupSelf(); err != nil {
+			t.Fatalf("expected first request of the entity to be allowed: %v", err)
+		}
+	}
+	if _, err := userClients[0].Auth().Token().LookupSelf(); err == nil {
+		t.Fatal("expected second request of the entity to be rate limited")
+	}
+
+	_, err = client.Logical().Write("sys/quotas/rate-limit/rlq", map[string]interface{}{
+		"rate":     1,
+		"group_by": "token",
+	})
+	if err == nil {
+		t.Fatal("expected error for invalid group_by")
+	}
+}
@@ -0,0 +1,130 @@
+package rafttests
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/openbao/openbao/api/v2"
+	"github.com/openbao/openbao/helper/testhelpers"
+)
+
+func TestRaft_PerfSecondary(t *testing.T) {
+	t.Parallel()
+	cluster, _ := raftCluster(t, nil)
+	defer cluster.Cleanup()
+
+	primary := cluster.Cores[0].Client
+
+	if _, err := primary.Logical().Write("secret/a", map[string]interface{}{"value": "1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := primary.Sys().EnableAuthWithOptions("userpass", &api.EnableAuthOptions{Type: "userpass"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := primary.Logical().Write("auth/userpass/users/bob", map[string]interface{}{"password": "secret"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The secondary starts from a snapshot of the primary, so that both
+	// share the same keyring and unseal keys.
+	buf := new(bytes.Buffer)
+	if err := primary.Sys().RaftSnapshot(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	cluster2, _ := raftCluster(t, nil)
+	defer cluster2.Cleanup()
+
+	if err := cluster2.Cores[0].Client.Sys().RaftSnapshotRestore(buf, true); err != nil {
+		t.Fatal(err)
+	}
+	testhelpers.WaitForNCoresSealed(t, cluster2, 3)
+	cluster2.BarrierKeys = cluster.BarrierKeys
+	testhelpers.EnsureCoresUnsealed(t, cluster2)
+	secondary := testhelpers.WaitForActiveNode(t, cluster2).Client
+	secondary.SetToken(cluster.RootToken)
+
+	// Changes after the snapshot are only seen once they are replicated,
+	// including new mounts.
+	if _, err := primary.Logical().Write("secret/b", map[string]interface{}{"value": "2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := primary.Sys().Mount("kv-new", &api.MountInput{Type: "kv"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := primary.Logical().Write("kv-new/c", map[string]interface{}{"value": "3"}); err != nil {
+		t.Fatal(err)
+	}
+
+	configure := func() {
+		t.Helper()
+		_, err := secondary.Logical().Write("sys/storage/raft/perf/secondary", map[string]interface{}{
+			"primary_address": primary.Address(),
+			"token":           cluster.RootToken,
+			"ca_cert":         string(cluster.CACertPEM),
+			"paths":           "secret/,kv-new/,auth/userpass/",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	waitFor := func(path, key string, value interface{}) {
+		t.Helper()
+		deadline := time.Now().Add(30 * time.Second)
+		for {
+			secret, err := secondary.Logical().Read(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got interface{}
+			if secret != nil {
+				got = secret.Data[key]
+			}
+			if got == value {
+				return
+			}
+			if time.Now().After(deadline) {
+				status, _ := secondary.Logical().Read("sys/storage/raft/perf/status")
+				t.Fatalf("%s: expected %v, got %v; status: %#v", path, value, got, status)
+			}
+			time.Sleep(500 * time.Millisecond)
+		}
+	}
+
+	configure()
+	waitFor("secret/b", "value", "2")
+	waitFor("kv-new/c", "value", "3")
+
+	// Replicated mounts are read-only, but logins are served.
+	if _, err := secondary.Logical().Write("secret/d", map[string]interface{}{"value": "4"}); err == nil {
+		t.Fatal("expected writing to a replicated mount to fail")
+	}
+	login, err := secondary.Logical().Write("auth/userpass/login/bob", map[string]interface{}{"password": "secret"})
+	if err != nil || login == nil || login.Auth == nil {
+		t.Fatalf("failed to log in, err:%v resp:%#v", err, login)
+	}
+
+	// Removed keys are removed from the secondary.
+	if _, err := primary.Logical().Delete("secret/a"); err != nil {
+		t.Fatal(err)
+	}
+	configure()
+	waitFor("secret/a", "value", nil)
+
+	status, err := secondary.Logical().Read("sys/storage/raft/perf/status")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Data["mode"] != "secondary" || len(status.Data["mounts"].(map[string]interface{})) != 3 {
+		t.Fatalf("bad status: %#v", status.Data)
+	}
+
+	// Once replication stops, the mounts are writable again.
+	if _, err := secondary.Logical().Delete("sys/storage/raft/perf/secondary"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := secondary.Logical().Write("secret/d", map[string]interface{}{"value": "4"}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"github.com/hashicorp/go-uuid"
 	"github.com/openbao/openbao/api/v2"
 	credUserpass "github.com/openbao/openbao/builtin/credential/userpass"
+	"github.com/openbao/openbao/helper/agekeys"
 	"github.com/openbao/openbao/helper/benchhelpers"
 	"github.com/openbao/openbao/helper/namespace"
 	"github.com/openbao/openbao/helper/testhelpers"
@@ -504,6 +505,124 @@ func TestRaft_SnapshotAPI(t *testing.T) {
 	}
 }
 
+func TestRaft_SnapshotAPI_Encrypted(t *testing.T) {
+	t.Parallel()
+	cluster, _ := raftCluster(t, nil)
+	defer cluster.Cleanup()
+
+	leaderClient := cluster.Cores[0].Client
+
+	identity, err := agekeys.GenerateX25519Identity()
+	require.NoError(t, err)
+	_, err = leaderClient.Logical().Write("sys/config/backup-encryption", map[string]interface{}{
+		"recipients": identity.Recipient().String(),
+	})
+	require.NoError(t, err)
+
+	_, err = leaderClient.Logical().Write("secret/foo", map[string]interface{}{"test": "data"})
+	require.NoError(t, err)
+
+	// Snapshots are encrypted to the backup recipients
+	buf := new(bytes.Buffer)
+	require.NoError(t, leaderClient.Sys().RaftSnapshot(buf))
+	require.True(t, agekeys.IsEncrypted(buf.Bytes()))
+
+	// Encrypted snapshots are decrypted offline before being restored
+	err = leaderClient.Sys().RaftSnapshotRestore(bytes.NewReader(buf.Bytes()), false)
+	require.ErrorContains(t, err, "the backup is encrypted")
+
+	decrypted, err := agekeys.Decrypt(buf, identity)
+	require.NoError(t, err)
+	snap, err := io.ReadAll(decrypted)
+	require.NoError(t, err)
+
+	_, err = leaderClient.Logical().Delete("secret/foo")
+	require.NoError(t, err)
+	require.NoError(t, leaderClient.Sys().RaftSnapshotRestore(bytes.NewReader(snap), false))
+
+	secret, err := leaderClient.Logical().Read("secret/foo")
+	require.NoError(t, err)
+	require.NotNil(t, secret)
+
+	// Snapshots can still be taken unencrypted, such as by DR secondaries
+	buf.Reset()
+	require.NoError(t, leaderClient.Sys().RaftSnapshotWithOptions(context.Background(), buf, &api.RaftSnapshotOptions{Unencrypted: true}))
+	require.False(t, agekeys.IsEncrypted(buf.Bytes()))
+}
+
+func TestRaft_SnapshotAPI_LoadMount(t *testing.T) {
+	t.Parallel()
+	cluster, _ := raftCluster(t, nil)
+	defer cluster.Cleanup()
+
+	leaderClient := cluster.Cores[0].Client
+
+	for i := 0; i < 10; i++ {
+		_, err := leaderClient.Logical().Write(fmt.Sprintf("secret/%d", i), map[string]interface{}{
+			"test": fmt.Sprintf("data-%d", i),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	if err := leaderClient.Sys().RaftSnapshot(buf); err != nil {
+		t.Fatal(err)
+	}
+	snap := buf.Bytes()
+
+	// Lose some keys and write a new one, which the restore must not touch
+	for i := 0; i < 5; i++ {
+		if _, err := leaderClient.Logical().Delete(fmt.Sprintf("secret/%d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := leaderClient.Logical().Write("secret/new", map[string]interface{}{"test": "new"}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := leaderClient.Sys().RaftSnapshotLoadMount(bytes.NewReader(snap), "secret", "secret-restored")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.TargetMount != "secret-restored/" || result.Type != "kv" || result.Keys == 0 {
+		t.Fatalf("bad result: %#v", result)
+	}
+
+	secret, err := leaderClient.Logical().List("secret-restored/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secret.Data["keys"].([]interface{})) != 10 {
+		t.Fatalf("bad restored keys: %#v", secret.Data["keys"])
+	}
+	secret, err = leaderClient.Logical().Read("secret-restored/3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret.Data["test"] != "data-3" {
+		t.Fatalf("bad restored data: %#v", secret.Data)
+	}
+
+	secret, err = leaderClient.Logical().List("secret/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secret.Data["keys"].([]interface{})) != 6 {
+		t.Fatalf("source mount changed by the restore: %#v", secret.Data["keys"])
+	}
+
+	// The target mount must not exist, and the source mount must exist in
+	// the snapshot
+	if _, err := leaderClient.Sys().RaftSnapshotLoadMount(bytes.NewReader(snap), "secret", "secret-restored"); err == nil {
+		t.Fatal("expected error loading into an existing mount")
+	}
+	if _, err := leaderClient.Sys().RaftSnapshotLoadMount(bytes.NewReader(snap), "missing", "missing-restored"); err == nil {
+		t.Fatal("expected error loading a missing mount")
+	}
+}
+
 func TestRaft_SnapshotAPI_MidstreamFailure(t *testing.T) {
 	// defer goleak.VerifyNone(t)
 	t.Parallel()
@@ -0,0 +1,539 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/go-secure-stdlib/base62"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/openbao/openbao/version"
+	glob "github.com/ryanuber/go-glob"
+)
+
+// Federation lets clusters report their health, version and mount inventory
+// to a management cluster. The management cluster issues federation tokens,
+// which only authorize reporting, and keeps a registry of the clusters which
+// reported with them. Member clusters are configured with the address of the
+// management cluster and a federation token, and their active node reports
+// periodically.
+const (
+	federationTokenSubPath   = "federation/tokens/"
+	federationClusterSubPath = "federation/clusters/"
+	federationConfigPath     = "federation/config"
+
+	// federationTokenPrefix prefixes the federation tokens, so that they
+	// cannot be mistaken for tokens of the token store.
+	federationTokenPrefix = "fed."
+
+	// defaultFederationReportInterval is the interval between two reports of
+	// a member cluster, unless configured otherwise.
+	defaultFederationReportInterval = 5 * time.Minute
+
+	// federationStaleReports is the number of report intervals without a
+	// report after which a cluster is considered stale.
+	federationStaleReports = 3
+)
+
+var (
+	// federationReportTimeout is the timeout of a single report.
+	federationReportTimeout = 30 * time.Second
+
+	// federationCheckInterval is the interval at which the reporting loop
+	// checks whether a report is due, so that configuration changes apply
+	// without restarting it.
+	federationCheckInterval = 10 * time.Second
+)
+
+// FederationToken authorizes member clusters to report to the management
+// cluster. Only the hash of the token is stored.
+type FederationToken struct {
+	Name      string `json:"name"`
+	TokenHash string `json:"token_hash"`
+
+	// AllowedClusterNames are globs matched against the names of the clusters
+	// reporting with the token. If empty, any cluster may report with it.
+	AllowedClusterNames []string `json:"allowed_cluster_names,omitempty"`
+
+	CreationTime time.Time `json:"creation_time"`
+	// ExpireTime is zero if the token does not expire.
+	ExpireTime time.Time `json:"expire_time,omitempty"`
+}
+
+func (t *FederationToken) toMap() map[string]interface{} {
+	allowed := t.AllowedClusterNames
+	if allowed == nil {
+		allowed = []string{}
+	}
+	data := map[string]interface{}{
+		"name":                  t.Name,
+		"allowed_cluster_names": allowed,
+		"creation_time":         t.CreationTime.Format(time.RFC3339),
+		"expire_time":           "",
+	}
+	if !t.ExpireTime.IsZero() {
+		data["expire_time"] = t.ExpireTime.Format(time.RFC3339)
+	}
+	return data
+}
+
+// FederationReport is the report of a member cluster, as POSTed to the
+// sys/federation/report endpoint of the management cluster.
+type FederationReport struct {
+	ClusterID      string             `json:"cluster_id"`
+	ClusterName    string             `json:"cluster_name"`
+	Version        string             `json:"version"`
+	APIAddr        string             `json:"api_addr,omitempty"`
+	ReportInterval int64              `json:"report_interval"`
+	Health         *FederationHealth  `json:"health"`
+	Mounts         []*FederationMount `json:"mounts"`
+	AuthMounts     []*FederationMount `json:"auth_mounts"`
+}
+
+// FederationHealth is the health of a member cluster, as seen by its active
+// node.
+type FederationHealth struct {
+	Initialized bool      `json:"initialized"`
+	Sealed      bool      `json:"sealed"`
+	HAEnabled   bool      `json:"ha_enabled"`
+	StorageType string    `json:"storage_type,omitempty"`
+	RaftPeers   int       `json:"raft_peers,omitempty"`
+	ActiveTime  time.Time `json:"active_time,omitempty"`
+	ServerTime  time.Time `json:"server_time"`
+}
+
+// FederationMount is a secrets engine or auth method of a member cluster.
+type FederationMount struct {
+	Path           string `json:"path"`
+	Type           string `json:"type"`
+	Accessor       string `json:"accessor"`
+	Local          bool   `json:"local,omitempty"`
+	RunningVersion string `json:"running_plugin_version,omitempty"`
+}
+
+// FederatedCluster is a cluster registered with the management cluster,
+// with its last report.
+type FederatedCluster struct {
+	Report     *FederationReport `json:"report"`
+	TokenName  string            `json:"token_name"`
+	RemoteAddr string            `json:"remote_addr,omitempty"`
+	FirstSeen  time.Time         `json:"first_seen"`
+	LastReport time.Time         `json:"last_report"`
+}
+
+// stale returns whether the cluster missed federationStaleReports reports.
+func (f *FederatedCluster) stale(now time.Time) bool {
+	interval := time.Duration(f.Report.ReportInterval) * time.Second
+	if interval <= 0 {
+		interval = defaultFederationReportInterval
+	}
+	return now.Sub(f.LastReport) > federationStaleReports*interval
+}
+
+func (f *FederatedCluster) toMap(now time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"cluster_id":      f.Report.ClusterID,
+		"cluster_name":    f.Report.ClusterName,
+		"version":         f.Report.Version,
+		"api_addr":        f.Report.APIAddr,
+		"report_interval": f.Report.ReportInterval,
+		"health":          f.Report.Health,
+		"mounts":          f.Report.Mounts,
+		"auth_mounts":     f.Report.AuthMounts,
+		"token_name":      f.TokenName,
+		"remote_addr":     f.RemoteAddr,
+		"first_seen":      f.FirstSeen.Format(time.RFC3339),
+		"last_report":     f.LastReport.Format(time.RFC3339),
+		"stale":           f.stale(now),
+	}
+}
+
+// FederationConfig configures a member cluster to report to a management
+// cluster.
+type FederationConfig struct {
+	ManagementAddr string        `json:"management_addr"`
+	Token          string        `json:"token"`
+	ReportInterval time.Duration `json:"report_interval"`
+	// CACert is the PEM-encoded CA certificate used to verify the management
+	// cluster, instead of the system CAs.
+	CACert    string `json:"ca_cert,omitempty"`
+	TLSServer string `json:"tls_server_name,omitempty"`
+}
+
+func hashFederationToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// createFederationToken stores the federation token of the given name,
+// replacing any previous one, and returns the token.
+func (c *Core) createFederationToken(ctx context.Context, name string, allowedClusterNames []string, ttl time.Duration) (string, *FederationToken, error) {
+	random, err := base62.Random(TokenLength)
+	if err != nil {
+		return "", nil, err
+	}
+	token := federationTokenPrefix + random
+
+	entry := &FederationToken{
+		Name:                name,
+		TokenHash:           hashFederationToken(token),
+		AllowedClusterNames: allowedClusterNames,
+		CreationTime:        time.Now().UTC(),
+	}
+	if ttl > 0 {
+		entry.ExpireTime = entry.CreationTime.Add(ttl)
+	}
+
+	storageEntry, err := logical.StorageEntryJSON(federationTokenSubPath+name, entry)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := c.systemBarrierView.Put(ctx, storageEntry); err != nil {
+		return "", nil, err
+	}
+	return token, entry, nil
+}
+
+func (c *Core) federationToken(ctx context.Context, name string) (*FederationToken, error) {
+	entry, err := c.systemBarrierView.Get(ctx, federationTokenSubPath+name)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	token := new(FederationToken)
+	if err := entry.DecodeJSON(token); err != nil {
+		return nil, fmt.Errorf("failed to decode federation token %q: %w", name, err)
+	}
+	return token, nil
+}
+
+// checkFederationToken returns the federation token matching token, or an
+// error if there is none or if it does not allow clusterName to report.
+func (c *Core) checkFederationToken(ctx context.Context, token, clusterName string) (*FederationToken, error) {
+	if !strings.HasPrefix(token, federationTokenPrefix) {
+		return nil, logical.ErrPermissionDenied
+	}
+	hash := hashFederationToken(token)
+
+	names, err := c.systemBarrierView.List(ctx, federationTokenSubPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		entry, err := c.federationToken(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil || subtle.ConstantTimeCompare([]byte(entry.TokenHash), []byte(hash)) != 1 {
+			continue
+		}
+
+		if !entry.ExpireTime.IsZero() && time.Now().After(entry.ExpireTime) {
+			return nil, logical.ErrPermissionDenied
+		}
+		if len(entry.AllowedClusterNames) > 0 && !federationNameAllowed(entry.AllowedClusterNames, clusterName) {
+			return nil, logical.ErrPermissionDenied
+		}
+		return entry, nil
+	}
+	return nil, logical.ErrPermissionDenied
+}
+
+func federationNameAllowed(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if glob.Glob(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// registerFederatedCluster records the report of a member cluster. A cluster
+// is owned by the federation token it first reported with: reports for the
+// same cluster ID with any other token are refused, as are reports with a
+// token which does not allow the name the cluster is registered with.
+func (c *Core) registerFederatedCluster(ctx context.Context, report *FederationReport, token *FederationToken, remoteAddr string) error {
+	c.federationClustersLock.Lock()
+	defer c.federationClustersLock.Unlock()
+
+	now := time.Now().UTC()
+	cluster := &FederatedCluster{
+		Report:     report,
+		TokenName:  token.Name,
+		RemoteAddr: remoteAddr,
+		FirstSeen:  now,
+		LastReport: now,
+	}
+
+	existing, err := c.federatedCluster(ctx, report.ClusterID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		if existing.TokenName != token.Name {
+			return logical.ErrPermissionDenied
+		}
+		if len(token.AllowedClusterNames) > 0 && !federationNameAllowed(token.AllowedClusterNames, existing.Report.ClusterName) {
+			return logical.ErrPermissionDenied
+		}
+		cluster.FirstSeen = existing.FirstSeen
+	}
+
+	entry, err := logical.StorageEntryJSON(federationClusterSubPath+report.ClusterID, cluster)
+	if err != nil {
+		return err
+	}
+	return c.systemBarrierView.Put(ctx, entry)
+}
+
+func (c *Core) federatedCluster(ctx context.Context, id string) (*FederatedCluster, error) {
+	entry, err := c.systemBarrierView.Get(ctx, federationClusterSubPath+id)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	cluster := new(FederatedCluster)
+	if err := entry.DecodeJSON(cluster); err != nil {
+		return nil, fmt.Errorf("failed to decode federated cluster %q: %w", id, err)
+	}
+	return cluster, nil
+}
+
+func (c *Core) loadFederationConfig(ctx context.Context) (*FederationConfig, error) {
+	entry, err := c.systemBarrierView.Get(ctx, federationConfigPath)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	conf := new(FederationConfig)
+	if err := entry.DecodeJSON(conf); err != nil {
+		return nil, fmt.Errorf("failed to decode federation configuration: %w", err)
+	}
+	if conf.ReportInterval <= 0 {
+		conf.ReportInterval = defaultFederationReportInterval
+	}
+	return conf, nil
+}
+
+// federationReport builds the report of the cluster, from its active node.
+func (c *Core) federationReport(ctx context.Context, interval time.Duration) (*FederationReport, error) {
+	cluster, err := c.Cluster(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if cluster == nil || cluster.ID == "" {
+		return nil, errors.New("cluster information is not available")
+	}
+
+	report := &FederationReport{
+		ClusterID:      cluster.ID,
+		ClusterName:    cluster.Name,
+		Version:        version.GetVersion().Version,
+		APIAddr:        c.redirectAddr,
+		ReportInterval: int64(interval.Seconds()),
+		Health: &FederationHealth{
+			Initialized: true,
+			HAEnabled:   c.HAEnabled(),
+			StorageType: c.storageType,
+			ActiveTime:  c.ActiveTime(),
+			ServerTime:  time.Now().UTC(),
+		},
+	}
+	if raftBackend := c.getRaftBackend(); raftBackend != nil {
+		if raftConfig, err := raftBackend.GetConfiguration(ctx); err == nil {
+			report.Health.RaftPeers = len(raftConfig.Servers)
+		}
+	}
+
+	c.mountsLock.RLock()
+	report.Mounts = federationMounts(c.mounts)
+	c.mountsLock.RUnlock()
+	c.authLock.RLock()
+	report.AuthMounts = federationMounts(c.auth)
+	c.authLock.RUnlock()
+
+	return report, nil
+}
+
+func federationMounts(table *MountTable) []*FederationMount {
+	mounts := []*FederationMount{}
+	if table == nil {
+		return mounts
+	}
+	for _, entry := range table.Entries {
+		mounts = append(mounts, &FederationMount{
+			Path:           entry.Path,
+			Type:           entry.Type,
+			Accessor:       entry.Accessor,
+			Local:          entry.Local,
+			RunningVersion: entry.RunningVersion,
+		})
+	}
+	sort.Slice(mounts, func(i, j int) bool {
+		return mounts[i].Path < mounts[j].Path
+	})
+	return mounts
+}
+
+// postFederationReport POSTs the report to the management cluster of conf.
+func postFederationReport(ctx context.Context, conf *FederationConfig, report *FederationReport) error {
+	raw, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	data := make(map[string]interface{})
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return err
+	}
+	data["federation_token"] = conf.Token
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	client := cleanhttp.DefaultClient()
+	if conf.CACert != "" || conf.TLSServer != "" {
+		tlsConfig := &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			ServerName: conf.TLSServer,
+		}
+		if conf.CACert != "" {
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM([]byte(conf.CACert)) {
+				return errors.New("failed to parse the CA certificate of the management cluster")
+			}
+			tlsConfig.RootCAs = pool
+		}
+		client.Transport.(*http.Transport).TLSClientConfig = tlsConfig
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, federationReportTimeout)
+	defer cancel()
+	url := strings.TrimSuffix(conf.ManagementAddr, "/") + "/v1/sys/federation/report"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// federationReportLoop reports to the configured management cluster every
+// report interval, until ctx is canceled.
+func (c *Core) federationReportLoop(ctx context.Context) {
+	t := time.NewTicker(federationCheckInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return
+		}
+
+		conf, report, err := c.dueFederationReport(ctx)
+		if err != nil {
+			c.logger.Error("failed to build the federation report", "error", err)
+			continue
+		}
+		if report == nil {
+			continue
+		}
+
+		// The state lock is not held while reporting, so that a slow
+		// management cluster does not delay sealing
+		err = postFederationReport(ctx, conf, report)
+		c.recordFederationReport(err)
+		if err != nil {
+			c.logger.Warn("failed to report to the management cluster", "address", conf.ManagementAddr, "error", err)
+		}
+	}
+}
+
+// dueFederationReport returns the federation configuration and the report
+// of the cluster if a report is due, or a nil report otherwise.
+func (c *Core) dueFederationReport(ctx context.Context) (*FederationConfig, *FederationReport, error) {
+	c.stateLock.RLock()
+	defer c.stateLock.RUnlock()
+
+	// The reports may have been stopped while waiting for the lock
+	if ctx.Err() != nil {
+		return nil, nil, nil
+	}
+
+	conf, err := c.loadFederationConfig(ctx)
+	if err != nil || conf == nil || !c.federationReportDue(conf) {
+		return nil, nil, err
+	}
+	report, err := c.federationReport(ctx, conf.ReportInterval)
+	if err != nil {
+		return nil, nil, err
+	}
+	return conf, report, nil
+}
+
+func (c *Core) federationReportDue(conf *FederationConfig) bool {
+	c.federationLock.Lock()
+	defer c.federationLock.Unlock()
+	return time.Since(c.federationLastReport) >= conf.ReportInterval
+}
+
+func (c *Core) recordFederationReport(err error) {
+	c.federationLock.Lock()
+	defer c.federationLock.Unlock()
+	c.federationLastReport = time.Now()
+	c.federationLastError = ""
+	if err != nil {
+		c.federationLastError = err.Error()
+	}
+}
+
+// resetFederationReports makes the next report due immediately, as after a
+// configuration change.
+func (c *Core) resetFederationReports() {
+	c.federationLock.Lock()
+	defer c.federationLock.Unlock()
+	c.federationLastReport = time.Time{}
+	c.federationLastError = ""
+}
+
+// startFederationReports starts reporting to the configured management
+// cluster. It is only run on the active node.
+func (c *Core) startFederationReports() {
+	if c.federationCancel != nil {
+		return
+	}
+
+	var ctx context.Context
+	ctx, c.federationCancel = context.WithCancel(c.activeContext)
+	go c.federationReportLoop(ctx)
+}
+
+func (c *Core) stopFederationReports() {
+	if c.federationCancel != nil {
+		c.federationCancel()
+		c.federationCancel = nil
+	}
+	c.resetFederationReports()
+}
@@ -0,0 +1,196 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCore_FederationToken(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	token, entry, err := c.createFederationToken(ctx, "prod", []string{"prod-*"}, 0)
+	require.NoError(t, err)
+	require.Equal(t, "prod", entry.Name)
+	require.NotContains(t, entry.toMap(), "token_hash")
+
+	checked, err := c.checkFederationToken(ctx, token, "prod-eu")
+	require.NoError(t, err)
+	require.Equal(t, "prod", checked.Name)
+
+	_, err = c.checkFederationToken(ctx, token, "staging-eu")
+	require.ErrorIs(t, err, logical.ErrPermissionDenied)
+	_, err = c.checkFederationToken(ctx, token+"x", "prod-eu")
+	require.ErrorIs(t, err, logical.ErrPermissionDenied)
+	_, err = c.checkFederationToken(ctx, "", "prod-eu")
+	require.ErrorIs(t, err, logical.ErrPermissionDenied)
+
+	expired, _, err := c.createFederationToken(ctx, "expired", nil, time.Nanosecond)
+	require.NoError(t, err)
+	time.Sleep(time.Millisecond)
+	_, err = c.checkFederationToken(ctx, expired, "prod-eu")
+	require.ErrorIs(t, err, logical.ErrPermissionDenied)
+}
+
+func TestCore_FederatedClusterOwnership(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	_, members, err := c.createFederationToken(ctx, "members", []string{"member-*"}, 0)
+	require.NoError(t, err)
+	_, others, err := c.createFederationToken(ctx, "others", nil, 0)
+	require.NoError(t, err)
+
+	report := &FederationReport{ClusterID: "cluster-1", ClusterName: "member-eu", Version: "2.0.0"}
+	require.NoError(t, c.registerFederatedCluster(ctx, report, members, ""))
+	require.NoError(t, c.registerFederatedCluster(ctx, report, members, ""))
+
+	// Another token cannot take over the cluster
+	takeover := &FederationReport{ClusterID: "cluster-1", ClusterName: "member-eu", Version: "0.0.1"}
+	require.ErrorIs(t, c.registerFederatedCluster(ctx, takeover, others, ""), logical.ErrPermissionDenied)
+
+	// The token must allow the name the cluster is registered with
+	_, members, err = c.createFederationToken(ctx, "members", []string{"member-us"}, 0)
+	require.NoError(t, err)
+	renamed := &FederationReport{ClusterID: "cluster-1", ClusterName: "member-us", Version: "0.0.1"}
+	require.ErrorIs(t, c.registerFederatedCluster(ctx, renamed, members, ""), logical.ErrPermissionDenied)
+
+	cluster, err := c.federatedCluster(ctx, "cluster-1")
+	require.NoError(t, err)
+	require.Equal(t, "members", cluster.TokenName)
+	require.Equal(t, "member-eu", cluster.Report.ClusterName)
+	require.Equal(t, "2.0.0", cluster.Report.Version)
+}
+
+func TestSystemBackend_Federation(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	request := func(op logical.Operation, path string, token string, data map[string]interface{}) (*logical.Response, error) {
+		t.Helper()
+		req := logical.TestRequest(t, op, path)
+		req.Data = data
+		req.ClientToken = token
+		req.Connection = &logical.Connection{RemoteAddr: "127.0.0.1"}
+		return c.HandleRequest(ctx, req)
+	}
+
+	resp, err := request(logical.UpdateOperation, "sys/federation/tokens/members", root, map[string]interface{}{
+		"allowed_cluster_names": "member-*",
+		"ttl":                   "1h",
+	})
+	require.NoError(t, err)
+	token := resp.Data["token"].(string)
+	require.NotEmpty(t, token)
+	require.Equal(t, []string{"member-*"}, resp.Data["allowed_cluster_names"])
+
+	resp, err = request(logical.ReadOperation, "sys/federation/tokens/members", root, nil)
+	require.NoError(t, err)
+	require.NotContains(t, resp.Data, "token")
+
+	// The member cluster reports with the federation token, the management
+	// cluster being served by an HTTP server forwarding to the core
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data := make(map[string]interface{})
+		if err := json.NewDecoder(r.Body).Decode(&data); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		req := logical.TestRequest(t, logical.UpdateOperation, "sys/federation/report")
+		req.Data = data
+		req.Connection = &logical.Connection{RemoteAddr: "127.0.0.1"}
+		if _, err := c.HandleRequest(ctx, req); err != nil {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	report, err := c.federationReport(ctx, time.Minute)
+	require.NoError(t, err)
+	report.ClusterName = "member-eu"
+	require.NotEmpty(t, report.Mounts)
+	require.NotEmpty(t, report.AuthMounts)
+
+	conf := &FederationConfig{ManagementAddr: srv.URL, Token: token, ReportInterval: time.Minute}
+	require.NoError(t, postFederationReport(context.Background(), conf, report))
+
+	resp, err = request(logical.ListOperation, "sys/federation/clusters", root, nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{report.ClusterID}, resp.Data["keys"])
+	info := resp.Data["key_info"].(map[string]interface{})[report.ClusterID].(map[string]interface{})
+	require.Equal(t, "member-eu", info["cluster_name"])
+	require.Equal(t, false, info["stale"])
+
+	resp, err = request(logical.ReadOperation, "sys/federation/clusters/"+report.ClusterID, root, nil)
+	require.NoError(t, err)
+	require.Equal(t, "members", resp.Data["token_name"])
+	require.Equal(t, "127.0.0.1", resp.Data["remote_addr"])
+
+	// Clusters outside the scope of the token, and revoked tokens, are
+	// refused
+	report.ClusterName = "other"
+	require.Error(t, postFederationReport(context.Background(), conf, report))
+	report.ClusterName = "member-eu"
+	_, err = request(logical.DeleteOperation, "sys/federation/tokens/members", root, nil)
+	require.NoError(t, err)
+	require.Error(t, postFederationReport(context.Background(), conf, report))
+
+	_, err = request(logical.DeleteOperation, "sys/federation/clusters/"+report.ClusterID, root, nil)
+	require.NoError(t, err)
+	resp, err = request(logical.ReadOperation, "sys/federation/clusters/"+report.ClusterID, root, nil)
+	require.NoError(t, err)
+	require.Nil(t, resp)
+}
+
+func TestSystemBackend_FederationConfig(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	request := func(op logical.Operation, data map[string]interface{}) (*logical.Response, error) {
+		t.Helper()
+		req := logical.TestRequest(t, op, "sys/federation/config")
+		req.Data = data
+		req.ClientToken = root
+		return c.HandleRequest(ctx, req)
+	}
+
+	_, err := request(logical.UpdateOperation, map[string]interface{}{
+		"management_addr": "ftp://mgmt.example.com",
+		"token":           federationTokenPrefix + "abc",
+	})
+	require.ErrorIs(t, err, logical.ErrInvalidRequest)
+	_, err = request(logical.UpdateOperation, map[string]interface{}{
+		"management_addr": "https://mgmt.example.com:8200",
+		"token":           "s.abc",
+	})
+	require.ErrorIs(t, err, logical.ErrInvalidRequest)
+
+	_, err = request(logical.UpdateOperation, map[string]interface{}{
+		"management_addr": "https://mgmt.example.com:8200",
+		"token":           federationTokenPrefix + "abc",
+		"report_interval": "10m",
+	})
+	require.NoError(t, err)
+
+	resp, err := request(logical.ReadOperation, nil)
+	require.NoError(t, err)
+	require.Equal(t, "https://mgmt.example.com:8200", resp.Data["management_addr"])
+	require.Equal(t, int64(600), resp.Data["report_interval"])
+	require.NotContains(t, resp.Data, "token")
+
+	_, err = request(logical.DeleteOperation, nil)
+	require.NoError(t, err)
+	resp, err = request(logical.ReadOperation, nil)
+	require.NoError(t, err)
+	require.Nil(t, resp)
+}
@@ -9,6 +9,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/go-uuid"
 	"github.com/openbao/openbao/helper/pgpkeys"
@@ -45,7 +46,7 @@ func (g generateStandardRootToken) authenticate(ctx context.Context, c *Core, co
 }
 
 func (g generateStandardRootToken) generate(ctx context.Context, c *Core) (string, func(), error) {
-	te, err := c.tokenStore.rootToken(ctx)
+	te, err := c.tokenStore.rootTokenWithLimits(ctx, c.rootTokenTTL, c.rootTokenNumUses)
 	if err != nil {
 		c.logger.Error("root token generation failed", "error", err)
 		return "", nil, err
@@ -59,6 +60,12 @@ func (g generateStandardRootToken) generate(ctx context.Context, c *Core) (strin
 		c.tokenStore.revokeOrphan(ctx, te.ID)
 	}
 
+	if ceremony := c.generateRootCeremony; ceremony != nil {
+		ceremony.TokenAccessor = te.Accessor
+		ceremony.TokenTTL = te.TTL
+		ceremony.TokenNumUses = te.NumUses
+	}
+
 	return te.ExternalID, cleanupFunc, nil
 }
 
@@ -126,6 +133,13 @@ func (c *Core) GenerateRootConfiguration() (*GenerateRootConfig, error) {
 
 // GenerateRootInit is used to initialize the root generation settings
 func (c *Core) GenerateRootInit(otp, pgpKey string, strategy GenerateRootStrategy) error {
+	return c.GenerateRootInitFrom(otp, pgpKey, strategy, "")
+}
+
+// GenerateRootInitFrom initializes the root generation settings like
+// GenerateRootInit, recording remoteAddr as the initiator of the root
+// generation.
+func (c *Core) GenerateRootInitFrom(otp, pgpKey string, strategy GenerateRootStrategy, remoteAddr string) error {
 	var fingerprint string
 	switch {
 	case len(otp) > 0:
@@ -186,6 +200,22 @@ func (c *Core) GenerateRootInit(otp, pgpKey string, strategy GenerateRootStrateg
 		Strategy:       strategy,
 	}
 
+	if _, ok := strategy.(generateStandardRootToken); ok {
+		ctx := c.generateRootHistoryContext()
+		c.abandonGenerateRootCeremonies(ctx)
+
+		initiator := newGenerateRootParticipant(remoteAddr)
+		c.generateRootCeremony = &GenerateRootCeremony{
+			Nonce:          generationNonce,
+			Status:         GenerateRootCeremonyInProgress,
+			PGPFingerprint: fingerprint,
+			StartedAt:      initiator.Time,
+			Initiator:      initiator,
+			KeyHolders:     []*GenerateRootParticipant{},
+		}
+		c.recordGenerateRootCeremony(ctx, c.generateRootCeremony)
+	}
+
 	if c.logger.IsInfo() {
 		switch strategy.(type) {
 		case generateStandardRootToken:
@@ -202,6 +232,19 @@ func (c *Core) GenerateRootInit(otp, pgpKey string, strategy GenerateRootStrateg
 
 // GenerateRootUpdate is used to provide a new key part
 func (c *Core) GenerateRootUpdate(ctx context.Context, key []byte, nonce string, strategy GenerateRootStrategy) (*GenerateRootResult, error) {
+	return c.GenerateRootUpdateFrom(ctx, key, nonce, strategy, "")
+}
+
+// GenerateRootUpdateFrom provides a new key part like GenerateRootUpdate,
+// recording remoteAddr as the key holder that provided it.
+func (c *Core) GenerateRootUpdateFrom(ctx context.Context, key []byte, nonce string, strategy GenerateRootStrategy, remoteAddr string) (*GenerateRootResult, error) {
+	return c.GenerateRootUpdateWithTOTP(ctx, key, "", nonce, strategy, remoteAddr)
+}
+
+// GenerateRootUpdateWithTOTP is GenerateRootUpdateFrom for unseal key shares
+// enrolled for TOTP, which are only accepted along with their current TOTP
+// code.
+func (c *Core) GenerateRootUpdateWithTOTP(ctx context.Context, key []byte, totpCode, nonce string, strategy GenerateRootStrategy, remoteAddr string) (*GenerateRootResult, error) {
 	// Verify the key length
 	min, max := c.barrier.KeyLength()
 	max += shamir.ShareOverhead
@@ -274,15 +317,29 @@ func (c *Core) GenerateRootUpdate(ctx context.Context, key []byte, nonce string,
 		}
 	}
 
+	// Recovery keys are not enrolled for TOTP, and the check is skipped for
+	// seals other than shamir
+	if !c.seal.RecoveryKeySupported() {
+		if err := c.verifyUnsealTOTP(ctx, c.seal, key, totpCode); err != nil {
+			return nil, err
+		}
+	}
+
 	// Store this key
 	c.generateRootProgress = append(c.generateRootProgress, key)
 	progress := len(c.generateRootProgress)
 
+	ceremony := c.generateRootCeremony
+	if ceremony != nil {
+		ceremony.KeyHolders = append(ceremony.KeyHolders, newGenerateRootParticipant(remoteAddr))
+	}
+
 	// Check if we don't have enough keys to unlock
 	if len(c.generateRootProgress) < config.SecretThreshold {
 		if c.logger.IsDebug() {
 			c.logger.Debug("cannot generate root, not enough keys", "keys", progress, "threshold", config.SecretThreshold)
 		}
+		c.recordGenerateRootCeremony(ctx, ceremony)
 		return &GenerateRootResult{
 			Progress:       progress,
 			Required:       config.SecretThreshold,
@@ -299,18 +356,21 @@ func (c *Core) GenerateRootUpdate(ctx context.Context, key []byte, nonce string,
 		combinedKey, err = shamir.Combine(c.generateRootProgress)
 		c.generateRootProgress = nil
 		if err != nil {
+			c.recordGenerateRootError(ctx, ceremony, err)
 			return nil, fmt.Errorf("failed to compute root key: %w", err)
 		}
 	}
 
 	if err := strategy.authenticate(ctx, c, combinedKey); err != nil {
 		c.logger.Error("root generation aborted", "error", err.Error())
+		c.recordGenerateRootError(ctx, ceremony, err)
 		return nil, fmt.Errorf("root generation aborted: %w", err)
 	}
 
 	// Run the generate strategy
 	token, cleanupFunc, err := strategy.generate(ctx, c)
 	if err != nil {
+		c.recordGenerateRootError(ctx, ceremony, err)
 		return nil, err
 	}
 
@@ -329,6 +389,10 @@ func (c *Core) GenerateRootUpdate(ctx context.Context, key []byte, nonce string,
 
 	if err != nil {
 		cleanupFunc()
+		if ceremony != nil {
+			ceremony.TokenAccessor = ""
+		}
+		c.recordGenerateRootError(ctx, ceremony, err)
 		return nil, err
 	}
 
@@ -348,13 +412,36 @@ func (c *Core) GenerateRootUpdate(ctx context.Context, key []byte, nonce string,
 		c.logger.Info("dr operation token generation finished", "nonce", c.generateRootConfig.Nonce)
 	}
 
+	if ceremony != nil {
+		ceremony.Status = GenerateRootCeremonyCompleted
+		ceremony.EndedAt = time.Now().UTC()
+		c.recordGenerateRootCeremony(ctx, ceremony)
+	}
+
 	c.generateRootProgress = nil
 	c.generateRootConfig = nil
+	c.generateRootCeremony = nil
 	return results, nil
 }
 
+// recordGenerateRootError records an error that reset the progress of a
+// root generation. The root generation itself remains in progress.
+func (c *Core) recordGenerateRootError(ctx context.Context, ceremony *GenerateRootCeremony, err error) {
+	if ceremony == nil {
+		return
+	}
+	ceremony.LastError = err.Error()
+	c.recordGenerateRootCeremony(ctx, ceremony)
+}
+
 // GenerateRootCancel is used to cancel an in-progress root generation
 func (c *Core) GenerateRootCancel() error {
+	return c.GenerateRootCancelFrom("")
+}
+
+// GenerateRootCancelFrom cancels an in-progress root generation like
+// GenerateRootCancel, recording remoteAddr as the requester.
+func (c *Core) GenerateRootCancelFrom(remoteAddr string) error {
 	c.stateLock.RLock()
 	defer c.stateLock.RUnlock()
 	if c.Sealed() && !c.recoveryMode {
@@ -367,8 +454,16 @@ func (c *Core) GenerateRootCancel() error {
 	c.generateRootLock.Lock()
 	defer c.generateRootLock.Unlock()
 
+	if ceremony := c.generateRootCeremony; ceremony != nil {
+		ceremony.Status = GenerateRootCeremonyCancelled
+		ceremony.CancelledBy = newGenerateRootParticipant(remoteAddr)
+		ceremony.EndedAt = ceremony.CancelledBy.Time
+		c.recordGenerateRootCeremony(c.generateRootHistoryContext(), ceremony)
+	}
+
 	// Clear any progress or config
 	c.generateRootConfig = nil
 	c.generateRootProgress = nil
+	c.generateRootCeremony = nil
 	return nil
 }
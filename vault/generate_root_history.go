@@ -0,0 +1,190 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+// generateRootHistoryPrefix is the barrier prefix used to record root
+// generation ceremonies, keyed by nonce.
+const generateRootHistoryPrefix = "core/generate-root-history/"
+
+const (
+	GenerateRootCeremonyInProgress = "in_progress"
+	GenerateRootCeremonyCompleted  = "completed"
+	GenerateRootCeremonyCancelled  = "cancelled"
+	GenerateRootCeremonyAbandoned  = "abandoned"
+)
+
+// GenerateRootParticipant records a request made as part of a root
+// generation, such as providing a key share.
+type GenerateRootParticipant struct {
+	RemoteAddr string    `json:"remote_address,omitempty"`
+	Time       time.Time `json:"time"`
+}
+
+// GenerateRootCeremony records a root token generation and the requests
+// that took part in it. Only the generation of standard root tokens is
+// recorded, as recovery and DR operation tokens are generated while the
+// barrier is sealed.
+type GenerateRootCeremony struct {
+	Nonce          string                     `json:"nonce"`
+	Status         string                     `json:"status"`
+	PGPFingerprint string                     `json:"pgp_fingerprint,omitempty"`
+	StartedAt      time.Time                  `json:"started_at"`
+	EndedAt        time.Time                  `json:"ended_at"`
+	Initiator      *GenerateRootParticipant   `json:"initiator,omitempty"`
+	KeyHolders     []*GenerateRootParticipant `json:"key_holders"`
+	CancelledBy    *GenerateRootParticipant   `json:"cancelled_by,omitempty"`
+	LastError      string                     `json:"last_error,omitempty"`
+	TokenAccessor  string                     `json:"token_accessor,omitempty"`
+	TokenTTL       time.Duration              `json:"token_ttl,omitempty"`
+	TokenNumUses   int                        `json:"token_num_uses,omitempty"`
+}
+
+func (g *GenerateRootCeremony) toMap() map[string]interface{} {
+	participant := func(p *GenerateRootParticipant) map[string]interface{} {
+		if p == nil {
+			return nil
+		}
+		return map[string]interface{}{
+			"remote_address": p.RemoteAddr,
+			"time":           p.Time,
+		}
+	}
+
+	keyHolders := make([]map[string]interface{}, 0, len(g.KeyHolders))
+	for _, p := range g.KeyHolders {
+		keyHolders = append(keyHolders, participant(p))
+	}
+
+	m := map[string]interface{}{
+		"nonce":           g.Nonce,
+		"status":          g.Status,
+		"pgp_fingerprint": g.PGPFingerprint,
+		"started_at":      g.StartedAt,
+		"initiator":       participant(g.Initiator),
+		"key_holders":     keyHolders,
+		"token_accessor":  g.TokenAccessor,
+		"token_ttl":       int64(g.TokenTTL.Seconds()),
+		"token_num_uses":  g.TokenNumUses,
+	}
+	if !g.EndedAt.IsZero() {
+		m["ended_at"] = g.EndedAt
+	}
+	if g.CancelledBy != nil {
+		m["cancelled_by"] = participant(g.CancelledBy)
+	}
+	if g.LastError != "" {
+		m["last_error"] = g.LastError
+	}
+	return m
+}
+
+func newGenerateRootParticipant(remoteAddr string) *GenerateRootParticipant {
+	return &GenerateRootParticipant{
+		RemoteAddr: remoteAddr,
+		Time:       time.Now().UTC(),
+	}
+}
+
+// generateRootHistoryContext returns the context used to record ceremonies,
+// as the generate root operations are not bound to a request context.
+func (c *Core) generateRootHistoryContext() context.Context {
+	if c.activeContext != nil {
+		return c.activeContext
+	}
+	return context.Background()
+}
+
+// recordGenerateRootCeremony persists the given ceremony. Ceremonies are
+// only recorded while the barrier is unsealed. Failures are logged, as they
+// must not prevent recovering access to the cluster. The caller must hold
+// the generate root lock.
+func (c *Core) recordGenerateRootCeremony(ctx context.Context, ceremony *GenerateRootCeremony) {
+	if ceremony == nil || c.recoveryMode {
+		return
+	}
+	if sealed, err := c.barrier.Sealed(); err != nil || sealed {
+		return
+	}
+
+	entry, err := logical.StorageEntryJSON(generateRootHistoryPrefix+ceremony.Nonce, ceremony)
+	if err == nil {
+		err = c.barrier.Put(ctx, entry)
+	}
+	if err != nil {
+		c.logger.Error("failed to record root generation", "nonce", ceremony.Nonce, "error", err)
+	}
+}
+
+// abandonGenerateRootCeremonies marks recorded ceremonies that are still in
+// progress, but no longer known to this node, as abandoned. This happens if
+// the node was sealed or lost leadership during a root generation. The
+// caller must hold the generate root lock.
+func (c *Core) abandonGenerateRootCeremonies(ctx context.Context) {
+	ceremonies, err := c.listGenerateRootCeremonies(ctx)
+	if err != nil {
+		c.logger.Error("failed to list root generations", "error", err)
+		return
+	}
+
+	for _, ceremony := range ceremonies {
+		if ceremony.Status != GenerateRootCeremonyInProgress {
+			continue
+		}
+		ceremony.Status = GenerateRootCeremonyAbandoned
+		c.recordGenerateRootCeremony(ctx, ceremony)
+	}
+}
+
+// listGenerateRootCeremonies returns the recorded root generations, oldest
+// first.
+func (c *Core) listGenerateRootCeremonies(ctx context.Context) ([]*GenerateRootCeremony, error) {
+	if sealed, err := c.barrier.Sealed(); err != nil || sealed {
+		return nil, err
+	}
+
+	keys, err := c.barrier.List(ctx, generateRootHistoryPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	ceremonies := make([]*GenerateRootCeremony, 0, len(keys))
+	for _, key := range keys {
+		if strings.HasSuffix(key, "/") {
+			continue
+		}
+		entry, err := c.barrier.Get(ctx, generateRootHistoryPrefix+key)
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			continue
+		}
+
+		ceremony := new(GenerateRootCeremony)
+		if err := entry.DecodeJSON(ceremony); err != nil {
+			return nil, fmt.Errorf("failed to decode root generation %q: %w", key, err)
+		}
+		ceremonies = append(ceremonies, ceremony)
+	}
+
+	sort.SliceStable(ceremonies, func(i, j int) bool {
+		return ceremonies[i].StartedAt.Before(ceremonies[j].StartedAt)
+	})
+	return ceremonies, nil
+}
+
+// GenerateRootHistory returns the recorded root generations, oldest first.
+func (c *Core) GenerateRootHistory(ctx context.Context) ([]*GenerateRootCeremony, error) {
+	c.generateRootLock.Lock()
+	defer c.generateRootLock.Unlock()
+
+	return c.listGenerateRootCeremonies(ctx)
+}
@@ -6,11 +6,13 @@ package vault
 import (
 	"encoding/base64"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-secure-stdlib/base62"
 	"github.com/openbao/openbao/helper/namespace"
 	"github.com/openbao/openbao/helper/pgpkeys"
 	"github.com/openbao/openbao/sdk/v2/helper/xor"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCore_GenerateRoot_Lifecycle(t *testing.T) {
@@ -317,3 +319,88 @@ func testCore_GenerateRoot_Update_PGP_Common(t *testing.T, c *Core, keys [][]byt
 		t.Fatalf("bad: %#v", *te)
 	}
 }
+
+func testCore_GenerateRoot_Complete(t *testing.T, c *Core, keys [][]byte, remoteAddr string) string {
+	t.Helper()
+
+	otp, err := base62.Random(TokenPrefixLength + TokenLength)
+	require.NoError(t, err)
+	require.NoError(t, c.GenerateRootInitFrom(otp, "", GenerateStandardRootTokenStrategy, remoteAddr))
+
+	conf, err := c.GenerateRootConfiguration()
+	require.NoError(t, err)
+
+	var result *GenerateRootResult
+	for _, key := range keys {
+		result, err = c.GenerateRootUpdateFrom(namespace.RootContext(nil), key, conf.Nonce, GenerateStandardRootTokenStrategy, remoteAddr)
+		require.NoError(t, err)
+		if result.EncodedToken != "" {
+			break
+		}
+	}
+
+	tokenBytes, err := base64.RawStdEncoding.DecodeString(result.EncodedToken)
+	require.NoError(t, err)
+	tokenBytes, err = xor.XORBytes(tokenBytes, []byte(otp))
+	require.NoError(t, err)
+	return string(tokenBytes)
+}
+
+func TestCore_GenerateRoot_TokenLimits(t *testing.T) {
+	c, keys, _ := TestCoreUnsealedWithConfig(t, &CoreConfig{
+		RootTokenTTL:     time.Hour,
+		RootTokenNumUses: 3,
+	})
+
+	token := testCore_GenerateRoot_Complete(t, c, keys, "")
+
+	te, err := c.tokenStore.Lookup(namespace.RootContext(nil), token)
+	require.NoError(t, err)
+	require.NotNil(t, te)
+	require.Equal(t, []string{"root"}, te.Policies)
+	require.Equal(t, time.Hour, te.TTL)
+	require.Equal(t, time.Hour, te.ExplicitMaxTTL)
+	require.Equal(t, 3, te.NumUses)
+
+	// The token is tracked by the expiration manager so that it expires.
+	le, err := c.expiration.FetchLeaseTimesByToken(namespace.RootContext(nil), te)
+	require.NoError(t, err)
+	require.NotNil(t, le)
+	require.WithinDuration(t, time.Now().Add(time.Hour), le.ExpireTime, time.Minute)
+}
+
+func TestCore_GenerateRoot_History(t *testing.T) {
+	c, keys, _ := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	// A cancelled attempt
+	otp, err := base62.Random(TokenPrefixLength + TokenLength)
+	require.NoError(t, err)
+	require.NoError(t, c.GenerateRootInitFrom(otp, "", GenerateStandardRootTokenStrategy, "10.0.0.1"))
+	require.NoError(t, c.GenerateRootCancelFrom("10.0.0.2"))
+
+	// An attempt left in progress is abandoned by the next one
+	require.NoError(t, c.GenerateRootInitFrom(otp, "", GenerateStandardRootTokenStrategy, "10.0.0.1"))
+	c.generateRootConfig = nil
+	c.generateRootCeremony = nil
+
+	token := testCore_GenerateRoot_Complete(t, c, keys, "10.0.0.3")
+	te, err := c.tokenStore.Lookup(ctx, token)
+	require.NoError(t, err)
+
+	history, err := c.GenerateRootHistory(ctx)
+	require.NoError(t, err)
+	require.Len(t, history, 3)
+
+	require.Equal(t, GenerateRootCeremonyCancelled, history[0].Status)
+	require.Equal(t, "10.0.0.1", history[0].Initiator.RemoteAddr)
+	require.Equal(t, "10.0.0.2", history[0].CancelledBy.RemoteAddr)
+	require.False(t, history[0].EndedAt.IsZero())
+
+	require.Equal(t, GenerateRootCeremonyAbandoned, history[1].Status)
+
+	require.Equal(t, GenerateRootCeremonyCompleted, history[2].Status)
+	require.Len(t, history[2].KeyHolders, len(keys))
+	require.Equal(t, "10.0.0.3", history[2].KeyHolders[0].RemoteAddr)
+	require.Equal(t, te.Accessor, history[2].TokenAccessor)
+}
@@ -711,6 +711,7 @@ func (c *Core) waitForLeadership(newLeaderCh chan func(), manualStepDownCh, stop
 					c.logger.Warn("failed to notify standby status", "error", err)
 				}
 			}
+			c.notify(NotificationEventLeadershipLost)
 
 			// If we are stopped return, otherwise unlock the statelock
 			if stopped {
@@ -1118,6 +1119,7 @@ func (c *Core) advertiseLeader(ctx context.Context, uuid string, leaderLostCh <-
 			}
 		}
 	}
+	c.notify(NotificationEventLeadershipAcquired)
 	return nil
 }
 
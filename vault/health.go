@@ -0,0 +1,101 @@
+package vault
+
+import (
+	"context"
+	"time"
+)
+
+// healthCheckStorageKey is read to check that the storage is reachable. It
+// is never written.
+const healthCheckStorageKey = "core/health-check"
+
+// SubsystemHealth is the state of the subsystems of an unsealed node, as
+// reported by the detailed health checks.
+type SubsystemHealth struct {
+	// StorageLatency is the time taken to read from the storage backend,
+	// which failed if StorageErr is set.
+	StorageLatency time.Duration
+	StorageErr     error
+
+	SealType    string
+	SealHealthy bool
+
+	// The lease counts and revocation queue depth are only reported by the
+	// active node.
+	Leases            int
+	IrrevocableLeases int
+	RevocationQueue   int
+
+	// ActiveNodeAddress is the API address of the active node, which is
+	// empty on a standby which did not find it.
+	ActiveNodeAddress string
+
+	// ReplicationMode is dr_secondary or perf_secondary on the secondaries
+	// of raft snapshot replication, and empty otherwise.
+	ReplicationMode      string
+	ReplicationLastSync  time.Time
+	ReplicationLastError string
+}
+
+// SubsystemHealth checks the subsystems of the node for the detailed health
+// checks. It reads from the storage backend, and is bounded by ctx.
+func (c *Core) SubsystemHealth(ctx context.Context) *SubsystemHealth {
+	health := &SubsystemHealth{
+		SealType:    c.seal.BarrierType().String(),
+		SealHealthy: true,
+	}
+
+	start := time.Now()
+	_, health.StorageErr = c.underlyingPhysical.Get(ctx, healthCheckStorageKey)
+	health.StorageLatency = time.Since(start)
+
+	if autoSeal, ok := c.seal.(*autoSeal); ok {
+		health.SealHealthy = autoSeal.Healthy()
+	}
+
+	c.stateLock.RLock()
+	e := c.expiration
+	c.stateLock.RUnlock()
+	if e != nil {
+		e.pendingLock.RLock()
+		health.Leases = e.leaseCount
+		health.IrrevocableLeases = e.irrevocableLeaseCount
+		e.pendingLock.RUnlock()
+
+		for _, count := range e.jobManager.GetPendingJobCountsByPriority() {
+			health.RevocationQueue += count
+		}
+	}
+
+	if isLeader, leaderAddr, _, err := c.Leader(); err == nil && !isLeader {
+		health.ActiveNodeAddress = leaderAddr
+	}
+
+	c.raftDRLock.Lock()
+	if c.raftDRConfig != nil {
+		health.ReplicationMode = "dr_secondary"
+		health.ReplicationLastSync = c.raftDRStatus.LastSync
+		health.ReplicationLastError = c.raftDRStatus.LastError
+	}
+	c.raftDRLock.Unlock()
+
+	c.raftPerfLock.Lock()
+	if c.raftPerfConfig != nil {
+		health.ReplicationMode = "perf_secondary"
+		health.ReplicationLastError = c.raftPerfStatus.LastError
+		// The replication is as old as its least recently synced mount.
+		for _, path := range c.raftPerfConfig.Paths {
+			mount, ok := c.raftPerfStatus.Mounts[path]
+			if !ok || mount.LastSync.IsZero() {
+				health.ReplicationLastSync = time.Time{}
+				break
+			}
+			if health.ReplicationLastSync.IsZero() || mount.LastSync.Before(health.ReplicationLastSync) {
+				health.ReplicationLastSync = mount.LastSync
+			}
+		}
+	}
+	c.raftPerfLock.Unlock()
+
+	return health
+}
@@ -0,0 +1,156 @@
+package vault
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/mitchellh/copystructure"
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/patrickmn/go-cache"
+)
+
+const (
+	// idempotencyKeyTTL is the time during which the response to a request
+	// made with an idempotency key is returned to the retries of the request.
+	idempotencyKeyTTL = 10 * time.Minute
+
+	// MaxIdempotencyKeyLength is the maximum length of an idempotency key.
+	MaxIdempotencyKeyLength = 256
+)
+
+// idempotentRequest is a request made with an idempotency key, and its
+// response once it is handled.
+type idempotentRequest struct {
+	// fingerprint identifies the operation, path and data of the request, so
+	// that keys reused for other requests are refused
+	fingerprint string
+
+	done bool
+	resp *logical.Response
+}
+
+// idempotentReplayContextKey is the context key of the idempotent request a
+// retried request replays the response of.
+type idempotentReplayContextKey struct{}
+
+// handleIdempotentRequest handles a request made with an idempotency key only
+// once, returning its response to the retries of the request made with the
+// same key and token until idempotencyKeyTTL passes. Requests without a key,
+// without a token, dry runs, or requests which do not write are handled as
+// usual. Only successful responses are kept, so that failed requests can be
+// retried.
+func (c *Core) handleIdempotentRequest(ctx context.Context, req *logical.Request) (*logical.Response, error) {
+	if req.IdempotencyKey == "" || req.ClientToken == "" || req.DryRun || c.isLoginRequest(ctx, req) {
+		return c.handleCancelableRequest(ctx, req)
+	}
+	switch req.Operation {
+	case logical.CreateOperation, logical.UpdateOperation, logical.PatchOperation, logical.DeleteOperation:
+	default:
+		return c.handleCancelableRequest(ctx, req)
+	}
+
+	fingerprint, err := idempotencyFingerprint(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	key := idempotencyCacheKey(req.ClientToken, req.IdempotencyKey)
+
+	if err := c.idempotentRequests.Add(key, &idempotentRequest{fingerprint: fingerprint}, cache.DefaultExpiration); err != nil {
+		return c.replayIdempotentRequest(ctx, req, key, fingerprint)
+	}
+
+	resp, err := c.handleCancelableRequest(ctx, req)
+	if err != nil || resp.IsError() {
+		c.idempotentRequests.Delete(key)
+		return resp, err
+	}
+
+	stored, err := copystructure.Copy(resp)
+	if err != nil {
+		c.idempotentRequests.Delete(key)
+		c.logger.Warn("failed to keep the response of an idempotent request", "path", req.Path, "error", err)
+		return resp, nil
+	}
+	storedResp, _ := stored.(*logical.Response)
+	c.idempotentRequests.Set(key, &idempotentRequest{
+		fingerprint: fingerprint,
+		done:        true,
+		resp:        storedResp,
+	}, cache.DefaultExpiration)
+	return resp, nil
+}
+
+// replayIdempotentRequest returns the response to the request already made
+// with the same idempotency key and token. The retry is handled like any
+// other request up to its routing, so that it is authorized against the
+// current policies of the token and audited, and the response is then
+// replayed instead of routing the request again.
+func (c *Core) replayIdempotentRequest(ctx context.Context, req *logical.Request, key, fingerprint string) (*logical.Response, error) {
+	raw, ok := c.idempotentRequests.Get(key)
+	if !ok {
+		// The request expired or failed in between, handle it again
+		return c.handleIdempotentRequest(ctx, req)
+	}
+	previous := raw.(*idempotentRequest)
+
+	if previous.fingerprint != fingerprint {
+		return logical.ErrorResponse("idempotency key was already used for a different request"), logical.ErrInvalidRequest
+	}
+	if !previous.done {
+		return nil, logical.CodedError(http.StatusConflict, "a request with this idempotency key is in progress")
+	}
+
+	replay := &idempotentRequest{
+		fingerprint: previous.fingerprint,
+		done:        true,
+	}
+	if previous.resp != nil {
+		resp, err := copystructure.Copy(previous.resp)
+		if err != nil {
+			return nil, err
+		}
+		replay.resp = resp.(*logical.Response)
+	}
+	return c.handleCancelableRequest(context.WithValue(ctx, idempotentReplayContextKey{}, replay), req)
+}
+
+// idempotentReplay returns the response to replay when the request is the
+// retry of an idempotent request.
+func idempotentReplay(ctx context.Context) (*logical.Response, bool) {
+	replay, ok := ctx.Value(idempotentReplayContextKey{}).(*idempotentRequest)
+	if !ok {
+		return nil, false
+	}
+	return replay.resp, true
+}
+
+// idempotencyCacheKey scopes an idempotency key to the token of the request,
+// so that keys of different clients never collide.
+func idempotencyCacheKey(token, idempotencyKey string) string {
+	sum := sha256.Sum256([]byte(token + "\x00" + idempotencyKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func idempotencyFingerprint(ctx context.Context, req *logical.Request) (string, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(req.Data)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	for _, s := range []string{ns.ID, string(req.Operation), req.Path} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
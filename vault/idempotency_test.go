@@ -0,0 +1,141 @@
+package vault
+
+import (
+	"testing"
+
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/helper/testhelpers/corehelpers"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCore_IdempotentRequest(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	request := func(path, key string, data map[string]interface{}) (*logical.Response, error) {
+		t.Helper()
+		req := logical.TestRequest(t, logical.UpdateOperation, path)
+		req.Data = data
+		req.ClientToken = root
+		req.IdempotencyKey = key
+		return c.HandleRequest(ctx, req)
+	}
+	createData := func() map[string]interface{} {
+		return map[string]interface{}{"policies": []string{"default"}}
+	}
+
+	// Retries with the same key obtain the same token.
+	first, err := request("auth/token/create", "retry-1", createData())
+	require.NoError(t, err)
+	retry, err := request("auth/token/create", "retry-1", createData())
+	require.NoError(t, err)
+	require.Equal(t, first.Auth.ClientToken, retry.Auth.ClientToken)
+
+	other, err := request("auth/token/create", "retry-2", createData())
+	require.NoError(t, err)
+	require.NotEqual(t, first.Auth.ClientToken, other.Auth.ClientToken)
+
+	noKey, err := request("auth/token/create", "", createData())
+	require.NoError(t, err)
+	require.NotEqual(t, first.Auth.ClientToken, noKey.Auth.ClientToken)
+
+	// Keys cannot be reused for different requests.
+	_, err = request("auth/token/create", "retry-1", map[string]interface{}{"policies": []string{"other"}})
+	require.ErrorIs(t, err, logical.ErrInvalidRequest)
+
+	// Keys are scoped to the token of the request.
+	resp, err := request("auth/token/create", "", map[string]interface{}{"policies": []string{"root"}})
+	require.NoError(t, err)
+	req := logical.TestRequest(t, logical.UpdateOperation, "auth/token/create")
+	req.Data = createData()
+	req.ClientToken = resp.Auth.ClientToken
+	req.IdempotencyKey = "retry-1"
+	scoped, err := c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	require.NotEqual(t, first.Auth.ClientToken, scoped.Auth.ClientToken)
+
+	// Failed requests are handled again.
+	_, err = request("sys/mounts/kv", "mount", map[string]interface{}{"type": "unknown"})
+	require.Error(t, err)
+	_, err = request("sys/mounts/kv", "mount", map[string]interface{}{"type": "unknown"})
+	require.Error(t, err)
+	require.Equal(t, 3, c.idempotentRequests.ItemCount())
+
+	// The keys are forgotten on seal.
+	c.idempotentRequests.Flush()
+	again, err := request("auth/token/create", "retry-1", createData())
+	require.NoError(t, err)
+	require.NotEqual(t, first.Auth.ClientToken, again.Auth.ClientToken)
+}
+
+func TestCore_IdempotentRequest_Audited(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	var records *[][]byte
+	c.auditBackends["noop"] = corehelpers.NoopAuditFactory(&records)
+	err := c.enableAudit(ctx, &MountEntry{
+		Table: auditTableType,
+		Path:  "noop/",
+		Type:  "noop",
+	}, true)
+	require.NoError(t, err)
+
+	request := func() *logical.Response {
+		t.Helper()
+		req := logical.TestRequest(t, logical.UpdateOperation, "auth/token/create")
+		req.Data = map[string]interface{}{"policies": []string{"default"}}
+		req.ClientToken = root
+		req.IdempotencyKey = "retry"
+		resp, err := c.HandleRequest(ctx, req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	before := len(*records)
+	first := request()
+	require.Len(t, *records, before+2)
+
+	// The retry is audited like the original request.
+	retry := request()
+	require.Equal(t, first.Auth.ClientToken, retry.Auth.ClientToken)
+	require.Len(t, *records, before+4)
+}
+
+func TestCore_IdempotentRequest_Unauthorized(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	setPolicy := func(rules string) {
+		t.Helper()
+		policy, err := ParseACLPolicy(namespace.RootNamespace, rules)
+		require.NoError(t, err)
+		policy.Name = "creator"
+		require.NoError(t, c.policyStore.SetPolicy(ctx, policy))
+	}
+	setPolicy(`path "auth/token/create" { capabilities = ["update"] }`)
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "auth/token/create")
+	req.Data = map[string]interface{}{"policies": []string{"creator"}}
+	req.ClientToken = root
+	resp, err := c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	token := resp.Auth.ClientToken
+
+	request := func() (*logical.Response, error) {
+		t.Helper()
+		req := logical.TestRequest(t, logical.UpdateOperation, "auth/token/create")
+		req.Data = map[string]interface{}{"policies": []string{"creator"}}
+		req.ClientToken = token
+		req.IdempotencyKey = "retry"
+		return c.HandleRequest(ctx, req)
+	}
+	_, err = request()
+	require.NoError(t, err)
+
+	// The retry is denied once the token may no longer make the request.
+	setPolicy(`path "auth/token/lookup-self" { capabilities = ["read"] }`)
+	_, err = request()
+	require.ErrorIs(t, err, logical.ErrPermissionDenied)
+}
@@ -52,18 +52,19 @@ func (i *IdentityStore) resetDB(ctx context.Context) error {
 
 func NewIdentityStore(ctx context.Context, core *Core, config *logical.BackendConfig, logger log.Logger) (*IdentityStore, error) {
 	iStore := &IdentityStore{
-		view:          config.StorageView,
-		logger:        logger,
-		router:        core.router,
-		redirectAddr:  core.redirectAddr,
-		localNode:     core,
-		namespacer:    core,
-		metrics:       core.MetricSink(),
-		totpPersister: core,
-		groupUpdater:  core,
-		tokenStorer:   core,
-		entityCreator: core,
-		mfaBackend:    core.loginMFABackend,
+		view:           config.StorageView,
+		logger:         logger,
+		router:         core.router,
+		redirectAddr:   core.redirectAddr,
+		localNode:      core,
+		namespacer:     core,
+		metrics:        core.MetricSink(),
+		totpPersister:  core,
+		groupUpdater:   core,
+		groupRefresher: core,
+		tokenStorer:    core,
+		entityCreator:  core,
+		mfaBackend:     core.loginMFABackend,
 	}
 
 	// Create a memdb instance, which by default, operates on lower cased
@@ -105,6 +106,7 @@ func NewIdentityStore(ctx context.Context, core *Core, config *logical.BackendCo
 				"oidc/.well-known/*",
 				"oidc/provider/+/.well-known/*",
 				"oidc/provider/+/token",
+				"oidc/provider/+/introspect",
 			},
 			LocalStorage: []string{
 				localAliasesBucketsPrefix,
@@ -112,6 +114,7 @@ func NewIdentityStore(ctx context.Context, core *Core, config *logical.BackendCo
 		},
 		PeriodicFunc: func(ctx context.Context, req *logical.Request) error {
 			iStore.oidcPeriodicFunc(ctx)
+			iStore.groupRefreshPeriodicFunc(ctx)
 
 			return nil
 		},
@@ -132,9 +135,11 @@ func NewIdentityStore(ctx context.Context, core *Core, config *logical.BackendCo
 func (i *IdentityStore) paths() []*framework.Path {
 	return framework.PathAppend(
 		entityPaths(i),
+		entityMergeRulePaths(i),
 		aliasPaths(i),
 		groupAliasPaths(i),
 		groupPaths(i),
+		groupRefreshPaths(i),
 		lookupPaths(i),
 		upgradePaths(i),
 		oidcPaths(i),
@@ -1237,6 +1242,15 @@ func (i *IdentityStore) CreateOrFetchEntity(ctx context.Context, alias *logical.
 	}
 
 	if !update {
+		// The merge rules may link the new alias to an existing entity
+		entity, err = i.entityByMergeRulesInTxn(ctx, txn, alias)
+		if err != nil {
+			return nil, false, err
+		}
+		if entity != nil {
+			return i.addAliasToEntityInTxn(ctx, txn, entity, alias, mountValidationResp)
+		}
+
 		entity = new(identity.Entity)
 		err = i.sanitizeEntity(ctx, entity)
 		if err != nil {
@@ -1296,6 +1310,34 @@ func (i *IdentityStore) CreateOrFetchEntity(ctx context.Context, alias *logical.
 	return clonedEntity, entityCreated, err
 }
 
+// addAliasToEntityInTxn adds a new alias to an existing entity and commits
+// the transaction.
+func (i *IdentityStore) addAliasToEntityInTxn(ctx context.Context, txn *memdb.Txn, entity *identity.Entity, alias *logical.Alias, mountValidationResp *ValidateMountResponse) (*identity.Entity, bool, error) {
+	newAlias := &identity.Alias{
+		CanonicalID:   entity.ID,
+		Name:          alias.Name,
+		MountAccessor: alias.MountAccessor,
+		Metadata:      alias.Metadata,
+		MountPath:     mountValidationResp.MountPath,
+		MountType:     mountValidationResp.MountType,
+	}
+
+	err := i.sanitizeAlias(ctx, newAlias)
+	if err != nil {
+		return nil, false, err
+	}
+	entity.Aliases = append(entity.Aliases, newAlias)
+
+	err = i.upsertEntityInTxn(ctx, txn, entity, nil, true)
+	if err != nil {
+		return entity, false, err
+	}
+
+	txn.Commit()
+	clonedEntity, err := entity.Clone()
+	return clonedEntity, false, err
+}
+
 // changedAliasIndex searches an entity for changed alias metadata.
 //
 // If a match is found, the changed alias's index is returned. If no alias
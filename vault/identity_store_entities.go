@@ -227,6 +227,10 @@ func entityPaths(i *IdentityStore) []*framework.Path {
 					Type:        framework.TypeBool,
 					Description: "Setting this will follow the 'mine' strategy for merging MFA secrets. If there are secrets of the same type both in entities that are merged from and in entity into which all others are getting merged, secrets in the destination will be unaltered. If not set, this API will throw an error containing all the conflicts.",
 				},
+				"dry_run": {
+					Type:        framework.TypeBool,
+					Description: "Setting this will return the entity resulting from the merge, along with its groups and policies, without merging the entities.",
+				},
 			},
 			Operations: map[logical.Operation]framework.OperationHandler{
 				logical.UpdateOperation: &framework.PathOperation{
@@ -266,6 +270,8 @@ func (i *IdentityStore) pathEntityMergeID() framework.OperationFunc {
 			force = forceInterface.(bool)
 		}
 
+		dryRun := d.Get("dry_run").(bool)
+
 		// Create a MemDB transaction to merge entities
 		i.lock.Lock()
 		defer i.lock.Unlock()
@@ -278,7 +284,7 @@ func (i *IdentityStore) pathEntityMergeID() framework.OperationFunc {
 			return nil, err
 		}
 
-		userErr, intErr, aliases := i.mergeEntity(ctx, txn, toEntity, fromEntityIDs, conflictingAliasIDsToKeep, force, false, false, true, false)
+		userErr, intErr, aliases := i.mergeEntity(ctx, txn, toEntity, fromEntityIDs, conflictingAliasIDsToKeep, force, false, false, !dryRun, false)
 		if userErr != nil {
 			// Not an error due to alias clash, return like normal
 			if len(aliases) == 0 {
@@ -298,6 +304,12 @@ func (i *IdentityStore) pathEntityMergeID() framework.OperationFunc {
 			return nil, intErr
 		}
 
+		// The transaction is discarded on a dry run, after reading the
+		// resulting entity from it
+		if dryRun {
+			return i.entityMergePreviewInTxn(txn, toEntity)
+		}
+
 		// Committing the transaction *after* successfully performing storage
 		// persistence
 		txn.Commit()
@@ -306,6 +318,62 @@ func (i *IdentityStore) pathEntityMergeID() framework.OperationFunc {
 	}
 }
 
+// entityMergePreviewInTxn returns the entity resulting from a merge which
+// was not committed, along with the groups and policies it would have.
+func (i *IdentityStore) entityMergePreviewInTxn(txn *memdb.Txn, entity *identity.Entity) (*logical.Response, error) {
+	directGroups, err := i.MemDBGroupsByMemberEntityIDInTxn(txn, entity.ID, false, false)
+	if err != nil {
+		return nil, err
+	}
+
+	visited := make(map[string]bool)
+	var groups []*identity.Group
+	for _, group := range directGroups {
+		groups, err = i.collectGroupsReverseDFS(group, visited, groups)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	directGroupIDs := make([]string, 0, len(directGroups))
+	for _, group := range directGroups {
+		directGroupIDs = append(directGroupIDs, group.ID)
+	}
+	inheritedGroupIDs := []string{}
+	groupPolicies := []string{}
+	for _, group := range groups {
+		if !strutil.StrListContains(directGroupIDs, group.ID) {
+			inheritedGroupIDs = append(inheritedGroupIDs, group.ID)
+		}
+		groupPolicies = append(groupPolicies, group.Policies...)
+	}
+
+	aliases := make([]interface{}, 0, len(entity.Aliases))
+	for _, alias := range entity.Aliases {
+		aliases = append(aliases, map[string]interface{}{
+			"id":             alias.ID,
+			"name":           alias.Name,
+			"mount_accessor": alias.MountAccessor,
+			"mount_path":     alias.MountPath,
+			"mount_type":     alias.MountType,
+		})
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"id":                  entity.ID,
+			"name":                entity.Name,
+			"policies":            entity.Policies,
+			"metadata":            entity.Metadata,
+			"aliases":             aliases,
+			"merged_entity_ids":   entity.MergedEntityIDs,
+			"direct_group_ids":    directGroupIDs,
+			"inherited_group_ids": inheritedGroupIDs,
+			"group_policies":      strutil.RemoveDuplicates(groupPolicies, false),
+		},
+	}, nil
+}
+
 // handleEntityUpdateCommon is used to update an entity
 func (i *IdentityStore) handleEntityUpdateCommon() framework.OperationFunc {
 	return func(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
@@ -1161,6 +1229,24 @@ var entityHelp = map[string][2]string{
 	},
 	"entity-merge-id": {
 		"Merge two or more entities together",
+		`
+Merge the entities of from_entity_ids into to_entity_id. When dry_run is set,
+nothing is changed, and the entity resulting from the merge is returned along
+with its groups and policies.
+`,
+	},
+	"entity-merge-rule": {
+		"Configure a rule linking new aliases to existing entities",
+		`
+When an alias is created at the first login through an auth method, the merge
+rules are evaluated in order of their names. If the alias matches the aliases
+of exactly one existing entity from other auth methods, on their name or on
+the value of metadata_key, the new alias is added to that entity instead of a
+new entity being created.
+`,
+	},
+	"entity-merge-rule-list": {
+		"List the entity merge rules",
 		"",
 	},
 	"batch-delete": {
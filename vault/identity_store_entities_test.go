@@ -1363,3 +1363,87 @@ func TestIdentityStore_EntityUpdateRefusesRoot(t *testing.T) {
 
 	require.Equal(t, readEntityResp.Data, resp.Data, "expected initial and final response data to be the same")
 }
+
+func TestIdentityStore_MergeEntitiesByID_DryRun(t *testing.T) {
+	ctx := namespace.RootContext(nil)
+	is, approleAccessor, upAccessor, _ := testIdentityStoreWithAppRoleUserpassAuth(ctx, t)
+
+	entity1, _, err := is.CreateOrFetchEntity(ctx, &logical.Alias{
+		MountType:     "approle",
+		MountAccessor: approleAccessor,
+		Name:          "alias1",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entity2, _, err := is.CreateOrFetchEntity(ctx, &logical.Alias{
+		MountType:     "userpass",
+		MountAccessor: upAccessor,
+		Name:          "alias2",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := is.HandleRequest(ctx, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "group",
+		Data: map[string]interface{}{
+			"member_entity_ids": entity2.ID,
+			"policies":          "group-policy",
+		},
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+	groupID := resp.Data["id"].(string)
+
+	resp, err = is.HandleRequest(ctx, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "entity/merge",
+		Data: map[string]interface{}{
+			"to_entity_id":    entity1.ID,
+			"from_entity_ids": []string{entity2.ID},
+			"dry_run":         true,
+		},
+	})
+	if err != nil || resp == nil || resp.IsError() {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+
+	if resp.Data["id"] != entity1.ID || len(resp.Data["aliases"].([]interface{})) != 2 {
+		t.Fatalf("bad: preview: %#v", resp.Data)
+	}
+	if !reflect.DeepEqual(resp.Data["direct_group_ids"], []string{groupID}) {
+		t.Fatalf("bad: direct groups: %#v", resp.Data["direct_group_ids"])
+	}
+	if !reflect.DeepEqual(resp.Data["group_policies"], []string{"group-policy"}) {
+		t.Fatalf("bad: group policies: %#v", resp.Data["group_policies"])
+	}
+	if !reflect.DeepEqual(resp.Data["merged_entity_ids"], []string{entity2.ID}) {
+		t.Fatalf("bad: merged entities: %#v", resp.Data["merged_entity_ids"])
+	}
+
+	// Nothing was merged
+	entity, err := is.MemDBEntityByID(entity2.ID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entity == nil || len(entity.Aliases) != 1 {
+		t.Fatalf("bad: entity: %#v", entity)
+	}
+	entity, err = is.MemDBEntityByID(entity1.ID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entity.Aliases) != 1 || len(entity.MergedEntityIDs) != 0 {
+		t.Fatalf("bad: entity: %#v", entity)
+	}
+	group, err := is.MemDBGroupByID(groupID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(group.MemberEntityIDs, []string{entity2.ID}) {
+		t.Fatalf("bad: group members: %#v", group.MemberEntityIDs)
+	}
+}
@@ -0,0 +1,387 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openbao/openbao/helper/identity"
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const groupRefreshConfigPath = "group-refresh/config"
+
+// groupRefreshConfig configures the background refresh of external group
+// memberships, which otherwise only happens at login and token renewal.
+type groupRefreshConfig struct {
+	// Interval is the time between refreshes, which are disabled if zero.
+	Interval time.Duration `json:"interval"`
+
+	// RemoveOnFailure removes an entity from the external groups of an auth
+	// method when the auth method refuses to refresh its login, for example
+	// because the user was removed from the directory.
+	RemoveOnFailure bool `json:"remove_on_failure"`
+}
+
+// groupRefreshState tracks the background refreshes of external group
+// memberships on the active node.
+type groupRefreshState struct {
+	sync.Mutex
+	running bool
+	lastRun time.Time
+	groups  map[string]*externalGroupSyncStatus
+}
+
+// externalGroupSyncStatus is the outcome of the last refresh of the
+// memberships of an external group.
+type externalGroupSyncStatus struct {
+	LastSync  time.Time
+	Refreshed int
+	Failed    int
+	LastError string
+}
+
+// externalGroupRefreshFunc is called with the group aliases returned by the
+// auth method when refreshing a login of the entity, or with the error
+// refusing it.
+type externalGroupRefreshFunc func(ctx context.Context, entityID, mountAccessor string, groupAliases []*logical.Alias, err error)
+
+func groupRefreshPaths(i *IdentityStore) []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "group/refresh/config$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "group",
+				OperationSuffix: "refresh-configuration",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"interval": {
+					Type:        framework.TypeDurationSecond,
+					Description: "Time between refreshes of the external group memberships of logged in entities. Refreshes are disabled if zero.",
+				},
+				"remove_on_failure": {
+					Type:        framework.TypeBool,
+					Description: "Remove an entity from the external groups of an auth method when the auth method refuses to refresh its login.",
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: i.pathGroupRefreshConfigWrite,
+				},
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: i.pathGroupRefreshConfigRead,
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(groupHelp["group-refresh-config"][0]),
+			HelpDescription: strings.TrimSpace(groupHelp["group-refresh-config"][1]),
+		},
+		{
+			Pattern: "group/refresh/status$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "group",
+				OperationSuffix: "refresh-status",
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: i.pathGroupRefreshStatusRead,
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(groupHelp["group-refresh-status"][0]),
+			HelpDescription: strings.TrimSpace(groupHelp["group-refresh-status"][1]),
+		},
+	}
+}
+
+// checkRootNamespace refuses the requests to the refresh paths outside of the
+// root namespace, as the refresh covers the groups of all namespaces.
+func checkRootNamespace(ctx context.Context) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ns.ID != namespace.RootNamespaceID {
+		return logical.ErrorResponse("external group refresh can only be configured in the root namespace"), logical.ErrInvalidRequest
+	}
+	return nil, nil
+}
+
+func (i *IdentityStore) pathGroupRefreshConfigWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if resp, err := checkRootNamespace(ctx); resp != nil || err != nil {
+		return resp, err
+	}
+
+	config, err := i.groupRefreshConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if intervalRaw, ok := d.GetOk("interval"); ok {
+		config.Interval = time.Duration(intervalRaw.(int)) * time.Second
+	}
+	if removeOnFailureRaw, ok := d.GetOk("remove_on_failure"); ok {
+		config.RemoveOnFailure = removeOnFailureRaw.(bool)
+	}
+	if config.Interval < 0 {
+		return logical.ErrorResponse("interval cannot be negative"), nil
+	}
+	if config.Interval > 0 && config.Interval < time.Minute {
+		return logical.ErrorResponse("interval must be at least one minute"), nil
+	}
+
+	entry, err := logical.StorageEntryJSON(groupRefreshConfigPath, config)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.view.Put(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (i *IdentityStore) pathGroupRefreshConfigRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if resp, err := checkRootNamespace(ctx); resp != nil || err != nil {
+		return resp, err
+	}
+
+	config, err := i.groupRefreshConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"interval":          int64(config.Interval.Seconds()),
+			"remove_on_failure": config.RemoveOnFailure,
+		},
+	}, nil
+}
+
+func (i *IdentityStore) pathGroupRefreshStatusRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if resp, err := checkRootNamespace(ctx); resp != nil || err != nil {
+		return resp, err
+	}
+
+	i.groupRefresh.Lock()
+	defer i.groupRefresh.Unlock()
+
+	groups := make(map[string]interface{}, len(i.groupRefresh.groups))
+	for id, status := range i.groupRefresh.groups {
+		group := map[string]interface{}{
+			"last_sync":          status.LastSync.Format(time.RFC3339),
+			"refreshed_entities": status.Refreshed,
+			"failed_entities":    status.Failed,
+		}
+		if status.LastError != "" {
+			group["last_error"] = status.LastError
+		}
+		groups[id] = group
+	}
+
+	data := map[string]interface{}{
+		"running": i.groupRefresh.running,
+		"groups":  groups,
+	}
+	if !i.groupRefresh.lastRun.IsZero() {
+		data["last_run"] = i.groupRefresh.lastRun.Format(time.RFC3339)
+	}
+
+	return &logical.Response{
+		Data: data,
+	}, nil
+}
+
+func (i *IdentityStore) groupRefreshConfig(ctx context.Context) (*groupRefreshConfig, error) {
+	entry, err := i.view.Get(ctx, groupRefreshConfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &groupRefreshConfig{}
+	if entry != nil {
+		if err := entry.DecodeJSON(config); err != nil {
+			return nil, err
+		}
+	}
+
+	return config, nil
+}
+
+// groupRefreshPeriodicFunc starts a refresh of the external group memberships
+// in the background when the configured interval has passed since the last
+// one.
+func (i *IdentityStore) groupRefreshPeriodicFunc(ctx context.Context) {
+	config, err := i.groupRefreshConfig(ctx)
+	if err != nil {
+		i.logger.Error("failed to read external group refresh configuration", "error", err)
+		return
+	}
+	if config.Interval == 0 {
+		return
+	}
+
+	i.groupRefresh.Lock()
+	defer i.groupRefresh.Unlock()
+
+	if i.groupRefresh.running || time.Since(i.groupRefresh.lastRun) < config.Interval {
+		return
+	}
+	i.groupRefresh.running = true
+
+	go func() {
+		if err := i.refreshExternalGroups(context.Background(), config); err != nil {
+			i.logger.Error("failed to refresh external group memberships", "error", err)
+		}
+
+		i.groupRefresh.Lock()
+		defer i.groupRefresh.Unlock()
+		i.groupRefresh.running = false
+		i.groupRefresh.lastRun = time.Now()
+	}()
+}
+
+// refreshExternalGroups refreshes the logins of the entities through the auth
+// methods which have external groups, and updates their memberships from the
+// group aliases returned.
+func (i *IdentityStore) refreshExternalGroups(ctx context.Context, config *groupRefreshConfig) error {
+	// Find the external groups of each auth method
+	groupsByAccessor := make(map[string][]*identity.Group)
+	txn := i.db.Txn(false)
+	iter, err := txn.Get(groupsTable, "id")
+	if err != nil {
+		return err
+	}
+	for raw := iter.Next(); raw != nil; raw = iter.Next() {
+		group := raw.(*identity.Group)
+		if group.Type != groupTypeExternal || group.Alias == nil {
+			continue
+		}
+		groupsByAccessor[group.Alias.MountAccessor] = append(groupsByAccessor[group.Alias.MountAccessor], group)
+	}
+	if len(groupsByAccessor) == 0 {
+		return nil
+	}
+
+	mountAccessors := make(map[string]bool, len(groupsByAccessor))
+	for accessor := range groupsByAccessor {
+		mountAccessors[accessor] = true
+	}
+
+	statuses := make(map[string]*externalGroupSyncStatus, len(groupsByAccessor))
+	for accessor := range groupsByAccessor {
+		statuses[accessor] = &externalGroupSyncStatus{}
+	}
+
+	err = i.groupRefresher.RefreshAuthLogins(ctx, mountAccessors, func(ctx context.Context, entityID, mountAccessor string, groupAliases []*logical.Alias, refreshErr error) {
+		status := statuses[mountAccessor]
+		if refreshErr != nil {
+			status.Failed++
+			status.LastError = refreshErr.Error()
+			if !config.RemoveOnFailure {
+				return
+			}
+			groupAliases = nil
+		}
+
+		if _, err := i.refreshExternalGroupMembershipsByEntityID(ctx, entityID, groupAliases, mountAccessor); err != nil {
+			status.Failed++
+			status.LastError = err.Error()
+			return
+		}
+		if refreshErr == nil {
+			status.Refreshed++
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	i.groupRefresh.Lock()
+	defer i.groupRefresh.Unlock()
+
+	i.groupRefresh.groups = make(map[string]*externalGroupSyncStatus)
+	for accessor, groups := range groupsByAccessor {
+		status := statuses[accessor]
+		status.LastSync = now
+		for _, group := range groups {
+			i.groupRefresh.groups[group.ID] = status
+		}
+	}
+
+	return nil
+}
+
+// RefreshAuthLogins refreshes, for each entity logged in through one of the
+// given auth methods, one of its logins by calling the renewal handler of the
+// auth method. The leases are left untouched.
+func (c *Core) RefreshAuthLogins(ctx context.Context, mountAccessors map[string]bool, refreshFn externalGroupRefreshFunc) error {
+	c.stateLock.RLock()
+	m := c.expiration
+	c.stateLock.RUnlock()
+	if m == nil {
+		return errors.New("expiration manager is not set up")
+	}
+
+	var leaseIDs []string
+	if err := m.walkLeases(func(leaseID string, _ time.Time) bool {
+		if strings.HasPrefix(leaseID, credentialRoutePrefix) {
+			leaseIDs = append(leaseIDs, leaseID)
+		}
+		return true
+	}); err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	for _, leaseID := range leaseIDs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		le, err := m.loadEntry(ctx, leaseID)
+		if err != nil {
+			m.logger.Warn("failed to load lease to refresh external groups", "lease_id", leaseID, "error", err)
+			continue
+		}
+		if le == nil || le.Auth == nil || le.Auth.EntityID == "" || le.Auth.Alias == nil || le.ClientTokenType == logical.TokenTypeBatch {
+			continue
+		}
+		mountAccessor := le.Auth.Alias.MountAccessor
+		if !mountAccessors[mountAccessor] {
+			continue
+		}
+		key := le.Auth.EntityID + "/" + mountAccessor
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		nsCtx := namespace.ContextWithNamespace(ctx, le.namespace)
+		resp, err := m.renewAuthEntry(nsCtx, &logical.Request{}, le, 0)
+		switch {
+		case err != nil:
+		case resp == nil || resp.Auth == nil:
+			err = errors.New("auth method does not support refreshing logins")
+		case resp.IsError():
+			err = resp.Error()
+		}
+		if err != nil {
+			refreshFn(nsCtx, le.Auth.EntityID, mountAccessor, nil, err)
+			continue
+		}
+		refreshFn(nsCtx, le.Auth.EntityID, mountAccessor, resp.Auth.GroupAliases, nil)
+	}
+
+	return nil
+}
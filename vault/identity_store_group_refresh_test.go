@@ -0,0 +1,159 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+type testGroupRefresher struct {
+	entityID     string
+	groupAliases []*logical.Alias
+	err          error
+}
+
+func (r *testGroupRefresher) RefreshAuthLogins(ctx context.Context, mountAccessors map[string]bool, refreshFn externalGroupRefreshFunc) error {
+	for accessor := range mountAccessors {
+		refreshFn(ctx, r.entityID, accessor, r.groupAliases, r.err)
+	}
+	return nil
+}
+
+func TestIdentityStore_RefreshExternalGroups(t *testing.T) {
+	ctx := namespace.RootContext(nil)
+	is, _, upAccessor, c := testIdentityStoreWithAppRoleUserpassAuth(ctx, t)
+	storage := c.router.MatchingStorageByAPIPath(ctx, "identity/")
+
+	entity, _, err := is.CreateOrFetchEntity(ctx, &logical.Alias{
+		MountType:     "userpass",
+		MountAccessor: upAccessor,
+		Name:          "bob",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := is.HandleRequest(ctx, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "group",
+		Storage:   storage,
+		Data: map[string]interface{}{
+			"name": "admins",
+			"type": "external",
+		},
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+	groupID := resp.Data["id"].(string)
+
+	resp, err = is.HandleRequest(ctx, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "group-alias",
+		Storage:   storage,
+		Data: map[string]interface{}{
+			"name":           "admins",
+			"mount_accessor": upAccessor,
+			"canonical_id":   groupID,
+		},
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+
+	isMember := func() bool {
+		t.Helper()
+		group, err := is.MemDBGroupByID(groupID, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, id := range group.MemberEntityIDs {
+			if id == entity.ID {
+				return true
+			}
+		}
+		return false
+	}
+
+	refresher := &testGroupRefresher{
+		entityID: entity.ID,
+		groupAliases: []*logical.Alias{
+			{Name: "admins", MountAccessor: upAccessor},
+		},
+	}
+	is.groupRefresher = refresher
+
+	if err := is.refreshExternalGroups(ctx, &groupRefreshConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if !isMember() {
+		t.Fatal("expected the entity to be added to the group")
+	}
+
+	// Failures keep the memberships unless configured otherwise.
+	refresher.groupAliases = nil
+	refresher.err = errors.New("user not found")
+	if err := is.refreshExternalGroups(ctx, &groupRefreshConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if !isMember() {
+		t.Fatal("expected the entity to stay in the group")
+	}
+
+	if err := is.refreshExternalGroups(ctx, &groupRefreshConfig{RemoveOnFailure: true}); err != nil {
+		t.Fatal(err)
+	}
+	if isMember() {
+		t.Fatal("expected the entity to be removed from the group")
+	}
+
+	resp, err = is.HandleRequest(ctx, &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "group/refresh/status",
+		Storage:   storage,
+	})
+	if err != nil || resp == nil {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+	status, ok := resp.Data["groups"].(map[string]interface{})[groupID].(map[string]interface{})
+	if !ok || status["failed_entities"] != 1 || status["last_error"] != "user not found" {
+		t.Fatalf("bad status: %#v", resp.Data)
+	}
+
+	resp, err = is.HandleRequest(ctx, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "group/refresh/config",
+		Storage:   storage,
+		Data: map[string]interface{}{
+			"interval": "10s",
+		},
+	})
+	if err != nil || resp == nil || !resp.IsError() {
+		t.Fatalf("expected an error, err:%v resp:%#v", err, resp)
+	}
+
+	resp, err = is.HandleRequest(ctx, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "group/refresh/config",
+		Storage:   storage,
+		Data: map[string]interface{}{
+			"interval":          "1h",
+			"remove_on_failure": true,
+		},
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+
+	resp, err = is.HandleRequest(ctx, &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "group/refresh/config",
+		Storage:   storage,
+	})
+	if err != nil || resp == nil || resp.Data["interval"] != int64(3600) || resp.Data["remove_on_failure"] != true {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+}
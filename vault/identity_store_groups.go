@@ -618,4 +618,17 @@ var groupHelp = map[string][2]string{
 		"List all the group IDs.",
 		"",
 	},
+	"group-refresh-config": {
+		"Configure the background refresh of external group memberships.",
+		`External group memberships are otherwise only refreshed at login and
+token renewal. When an interval is set, the active node periodically refreshes
+the logins of the entities through the auth methods of external groups, using
+the renewal handlers of the auth methods, and updates their memberships from
+the groups returned. This requires the auth method to query the groups of the
+user on renewal, as done by the LDAP auth method.`,
+	},
+	"group-refresh-status": {
+		"Read the status of the last refresh of each external group.",
+		"",
+	},
 }
@@ -0,0 +1,299 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	memdb "github.com/hashicorp/go-memdb"
+	"github.com/openbao/openbao/helper/identity"
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/strutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const entityMergeRulePath = "entity-merge-rule/"
+
+// entityMergeRule links the alias created at the first login through an auth
+// method to an existing entity, instead of creating a new entity, when an
+// alias of that entity from another auth method refers to the same user.
+type entityMergeRule struct {
+	// MountAccessors are the auth methods the rule applies to when logging
+	// in. All auth methods are included if empty.
+	MountAccessors []string `json:"mount_accessors"`
+
+	// MatchMountAccessors are the auth methods whose aliases are matched.
+	// All other auth methods are included if empty.
+	MatchMountAccessors []string `json:"match_mount_accessors"`
+
+	// MetadataKey is the alias metadata compared between aliases, such as
+	// email. Alias names are compared if empty.
+	MetadataKey string `json:"metadata_key"`
+
+	CaseInsensitive bool `json:"case_insensitive"`
+}
+
+func entityMergeRulePaths(i *IdentityStore) []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "entity/merge-rule/" + framework.GenericNameRegex("name"),
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "entity",
+				OperationSuffix: "merge-rule",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the merge rule",
+				},
+				"mount_accessors": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "Accessors of the auth methods the rule applies to when logging in. Defaults to all auth methods.",
+				},
+				"match_mount_accessors": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "Accessors of the auth methods whose aliases are matched. Defaults to all other auth methods.",
+				},
+				"metadata_key": {
+					Type:        framework.TypeString,
+					Description: "Alias metadata key whose values are compared, such as email. Alias names are compared if not set.",
+				},
+				"case_insensitive": {
+					Type:        framework.TypeBool,
+					Description: "Compare the values without regard to case.",
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: i.pathEntityMergeRuleWrite,
+				},
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: i.pathEntityMergeRuleRead,
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: i.pathEntityMergeRuleDelete,
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(entityHelp["entity-merge-rule"][0]),
+			HelpDescription: strings.TrimSpace(entityHelp["entity-merge-rule"][1]),
+		},
+		{
+			Pattern: "entity/merge-rule/?$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "entity",
+				OperationSuffix: "merge-rules",
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback: i.pathEntityMergeRuleList,
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(entityHelp["entity-merge-rule-list"][0]),
+			HelpDescription: strings.TrimSpace(entityHelp["entity-merge-rule-list"][1]),
+		},
+	}
+}
+
+func (i *IdentityStore) pathEntityMergeRuleWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+
+	rule, err := i.getEntityMergeRule(ctx, req.Storage, name)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		rule = &entityMergeRule{}
+	}
+
+	if mountAccessorsRaw, ok := d.GetOk("mount_accessors"); ok {
+		rule.MountAccessors = strutil.RemoveDuplicates(mountAccessorsRaw.([]string), false)
+	}
+	if matchMountAccessorsRaw, ok := d.GetOk("match_mount_accessors"); ok {
+		rule.MatchMountAccessors = strutil.RemoveDuplicates(matchMountAccessorsRaw.([]string), false)
+	}
+	if metadataKeyRaw, ok := d.GetOk("metadata_key"); ok {
+		rule.MetadataKey = metadataKeyRaw.(string)
+	}
+	if caseInsensitiveRaw, ok := d.GetOk("case_insensitive"); ok {
+		rule.CaseInsensitive = caseInsensitiveRaw.(bool)
+	}
+
+	for _, accessor := range append(rule.MountAccessors, rule.MatchMountAccessors...) {
+		if i.router.ValidateMountByAccessor(accessor) == nil {
+			return logical.ErrorResponse("invalid mount accessor %q", accessor), nil
+		}
+	}
+
+	entry, err := logical.StorageEntryJSON(entityMergeRulePath+name, rule)
+	if err != nil {
+		return nil, err
+	}
+	if err := req.Storage.Put(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+func (i *IdentityStore) pathEntityMergeRuleRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	rule, err := i.getEntityMergeRule(ctx, req.Storage, d.Get("name").(string))
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, nil
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"mount_accessors":       rule.MountAccessors,
+			"match_mount_accessors": rule.MatchMountAccessors,
+			"metadata_key":          rule.MetadataKey,
+			"case_insensitive":      rule.CaseInsensitive,
+		},
+	}, nil
+}
+
+func (i *IdentityStore) pathEntityMergeRuleDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	return nil, req.Storage.Delete(ctx, entityMergeRulePath+d.Get("name").(string))
+}
+
+func (i *IdentityStore) pathEntityMergeRuleList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	rules, err := req.Storage.List(ctx, entityMergeRulePath)
+	if err != nil {
+		return nil, err
+	}
+	return logical.ListResponse(rules), nil
+}
+
+func (i *IdentityStore) getEntityMergeRule(ctx context.Context, s logical.Storage, name string) (*entityMergeRule, error) {
+	entry, err := s.Get(ctx, entityMergeRulePath+name)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var rule entityMergeRule
+	if err := entry.DecodeJSON(&rule); err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+// value returns the value of the alias compared by the rule.
+func (r *entityMergeRule) value(name string, metadata map[string]string) string {
+	value := name
+	if r.MetadataKey != "" {
+		value = metadata[r.MetadataKey]
+	}
+	if r.CaseInsensitive {
+		value = strings.ToLower(value)
+	}
+	return value
+}
+
+// entityByMergeRulesInTxn returns the existing entity which the new alias
+// should be added to according to the merge rules, if any. The rules are
+// evaluated in order of their names, and the first one matching the aliases
+// of exactly one entity is used.
+func (i *IdentityStore) entityByMergeRulesInTxn(ctx context.Context, txn *memdb.Txn, alias *logical.Alias) (*identity.Entity, error) {
+	// Local aliases are stored along with their entity on each cluster, so
+	// they are never linked to an existing entity.
+	if alias.Local {
+		return nil, nil
+	}
+
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s := i.router.MatchingStorageByAPIPath(ctx, ns.Path+"identity/")
+	if s == nil {
+		return nil, nil
+	}
+
+	names, err := s.List(ctx, entityMergeRulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		rule, err := i.getEntityMergeRule(ctx, s, name)
+		if err != nil {
+			return nil, err
+		}
+		if rule == nil {
+			continue
+		}
+		if len(rule.MountAccessors) > 0 && !strutil.StrListContains(rule.MountAccessors, alias.MountAccessor) {
+			continue
+		}
+		value := rule.value(alias.Name, alias.Metadata)
+		if value == "" {
+			continue
+		}
+
+		iter, err := txn.Get(entityAliasesTable, "namespace_id", ns.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch iterator for aliases in memdb: %w", err)
+		}
+		entityIDs := make(map[string]struct{})
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			existing := raw.(*identity.Alias)
+			if existing.MountAccessor == alias.MountAccessor || existing.Local {
+				continue
+			}
+			if len(rule.MatchMountAccessors) > 0 && !strutil.StrListContains(rule.MatchMountAccessors, existing.MountAccessor) {
+				continue
+			}
+			if rule.value(existing.Name, existing.Metadata) == value {
+				entityIDs[existing.CanonicalID] = struct{}{}
+			}
+		}
+
+		switch len(entityIDs) {
+		case 0:
+			continue
+		case 1:
+		default:
+			i.logger.Warn("aliases of multiple entities match the entity merge rule, not linking the new alias", "rule", name, "mount_accessor", alias.MountAccessor, "alias", alias.Name)
+			continue
+		}
+
+		for entityID := range entityIDs {
+			entity, err := i.MemDBEntityByIDInTxn(txn, entityID, true)
+			if err != nil {
+				return nil, err
+			}
+			if entity == nil {
+				continue
+			}
+			// An entity can only have one alias per auth method.
+			for _, existing := range entity.Aliases {
+				if existing.MountAccessor == alias.MountAccessor {
+					entity = nil
+					break
+				}
+			}
+			if entity == nil {
+				i.logger.Warn("matching entity already has an alias for the auth method, not linking the new alias", "rule", name, "entity_id", entityID, "mount_accessor", alias.MountAccessor, "alias", alias.Name)
+				continue
+			}
+
+			i.logger.Info("linking new alias to existing entity by merge rule", "rule", name, "entity_id", entity.ID, "mount_accessor", alias.MountAccessor, "alias", alias.Name)
+			return entity, nil
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,109 @@
+package vault
+
+import (
+	"testing"
+
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+func TestIdentityStore_EntityMergeRules(t *testing.T) {
+	ctx := namespace.RootContext(nil)
+	is, approleAccessor, upAccessor, c := testIdentityStoreWithAppRoleUserpassAuth(ctx, t)
+	storage := c.router.MatchingStorageByAPIPath(ctx, "identity/")
+
+	// Without a rule, each auth method gets its own entity.
+	upEntity, _, err := is.CreateOrFetchEntity(ctx, &logical.Alias{
+		MountType:     "userpass",
+		MountAccessor: upAccessor,
+		Name:          "bob",
+		Metadata:      map[string]string{"email": "Bob@example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entity, _, err := is.CreateOrFetchEntity(ctx, &logical.Alias{
+		MountType:     "approle",
+		MountAccessor: approleAccessor,
+		Name:          "alice",
+		Metadata:      map[string]string{"email": "alice@example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entity.ID == upEntity.ID {
+		t.Fatal("expected a new entity")
+	}
+
+	resp, err := is.HandleRequest(ctx, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "entity/merge-rule/email",
+		Storage:   storage,
+		Data: map[string]interface{}{
+			"mount_accessors":  approleAccessor,
+			"metadata_key":     "email",
+			"case_insensitive": true,
+		},
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+
+	resp, err = is.HandleRequest(ctx, &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "entity/merge-rule/email",
+		Storage:   storage,
+	})
+	if err != nil || resp == nil || resp.Data["metadata_key"] != "email" {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+
+	// A new alias with the same email is added to the existing entity.
+	entity, created, err := is.CreateOrFetchEntity(ctx, &logical.Alias{
+		MountType:     "approle",
+		MountAccessor: approleAccessor,
+		Name:          "bob-approle",
+		Metadata:      map[string]string{"email": "bob@example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created || entity.ID != upEntity.ID || len(entity.Aliases) != 2 {
+		t.Fatalf("expected the alias to be added to entity %s, got: %#v", upEntity.ID, entity)
+	}
+
+	// The rule does not apply to other auth methods.
+	entity, _, err = is.CreateOrFetchEntity(ctx, &logical.Alias{
+		MountType:     "userpass",
+		MountAccessor: upAccessor,
+		Name:          "alice",
+		Metadata:      map[string]string{"email": "alice@example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entity.Aliases) != 1 {
+		t.Fatalf("expected a new entity, got: %#v", entity)
+	}
+
+	resp, err = is.HandleRequest(ctx, &logical.Request{
+		Operation: logical.ListOperation,
+		Path:      "entity/merge-rule/",
+		Storage:   storage,
+	})
+	if err != nil || resp == nil || len(resp.Data["keys"].([]string)) != 1 {
+		t.Fatalf("err:%v resp:%#v", err, resp)
+	}
+
+	resp, err = is.HandleRequest(ctx, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "entity/merge-rule/invalid",
+		Storage:   storage,
+		Data: map[string]interface{}{
+			"mount_accessors": "auth_invalid",
+		},
+	})
+	if err != nil || resp == nil || !resp.IsError() {
+		t.Fatalf("expected an error, err:%v resp:%#v", err, resp)
+	}
+}
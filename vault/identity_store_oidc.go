@@ -61,10 +61,12 @@ type namedKey struct {
 }
 
 type role struct {
-	TokenTTL time.Duration `json:"token_ttl"`
-	Key      string        `json:"key"`
-	Template string        `json:"template"`
-	ClientID string        `json:"client_id"`
+	TokenTTL             time.Duration `json:"token_ttl"`
+	Key                  string        `json:"key"`
+	Template             string        `json:"template"`
+	ClientID             string        `json:"client_id"`
+	Audiences            []string      `json:"audiences"`
+	AllowedRequestClaims []string      `json:"allowed_request_claims"`
 }
 
 // idToken contains the required OIDC fields.
@@ -73,16 +75,17 @@ type role struct {
 // include top-level keys, but those keys may not overwrite any of the
 // required OIDC fields.
 type idToken struct {
-	Issuer          string `json:"iss"`       // api_addr or custom Issuer
-	Namespace       string `json:"namespace"` // Namespace of issuer
-	Subject         string `json:"sub"`       // Entity ID
-	Audience        string `json:"aud"`       // Role or client ID will be used here.
-	Expiry          int64  `json:"exp"`       // Expiration, as determined by the role or client.
-	IssuedAt        int64  `json:"iat"`       // Time of token creation
-	Nonce           string `json:"nonce"`     // Nonce given in OIDC authentication requests
-	AuthTime        int64  `json:"auth_time"` // AuthTime given in OIDC authentication requests
-	AccessTokenHash string `json:"at_hash"`   // Access token hash value
-	CodeHash        string `json:"c_hash"`    // Authorization code hash value
+	Issuer          string   `json:"iss"`       // api_addr or custom Issuer
+	Namespace       string   `json:"namespace"` // Namespace of issuer
+	Subject         string   `json:"sub"`       // Entity ID
+	Audience        string   `json:"aud"`       // Role or client ID will be used here.
+	ExtraAudiences  []string `json:"-"`         // Audiences added by the role, next to its client ID.
+	Expiry          int64    `json:"exp"`       // Expiration, as determined by the role or client.
+	IssuedAt        int64    `json:"iat"`       // Time of token creation
+	Nonce           string   `json:"nonce"`     // Nonce given in OIDC authentication requests
+	AuthTime        int64    `json:"auth_time"` // AuthTime given in OIDC authentication requests
+	AccessTokenHash string   `json:"at_hash"`   // Access token hash value
+	CodeHash        string   `json:"c_hash"`    // Authorization code hash value
 }
 
 // discovery contains a subset of the required elements of OIDC discovery needed
@@ -286,12 +289,28 @@ func oidcPaths(i *IdentityStore) []*framework.Path {
 					Type:        framework.TypeString,
 					Description: "Name of the role",
 				},
+				"ttl": {
+					Type:        framework.TypeDurationSecond,
+					Description: "TTL of the generated token. Defaults to, and cannot exceed, the TTL of the role.",
+				},
+				"claims": {
+					Type:        framework.TypeMap,
+					Description: "Additional top level claims of the generated token. Only the claims listed in the allowed_request_claims of the role may be set.",
+				},
 			},
-			Callbacks: map[logical.Operation]framework.OperationFunc{
-				logical.ReadOperation: i.pathOIDCGenerateToken,
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: i.pathOIDCGenerateToken,
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: i.pathOIDCGenerateToken,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationSuffix: "token-with-parameters",
+					},
+				},
 			},
 			HelpSynopsis:    "Generate an OIDC token",
-			HelpDescription: "Generate an OIDC token against a configured role. The OpenBao token used to call this path must have a corresponding entity.",
+			HelpDescription: "Generate an OIDC token against a configured role. The OpenBao token used to call this path must have a corresponding entity. The TTL and additional claims of the token may be set when writing to this path.",
 		},
 		{
 			Pattern: "oidc/role/" + framework.GenericNameRegex("name"),
@@ -322,6 +341,14 @@ func oidcPaths(i *IdentityStore) []*framework.Path {
 					Type:        framework.TypeString,
 					Description: "Optional client_id",
 				},
+				"audiences": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "Optional audiences added to the client_id in the aud claim of the generated tokens.",
+				},
+				"allowed_request_claims": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "Top level claims that may be set when generating a token. Reserved claims are not allowed.",
+				},
 			},
 			Callbacks: map[logical.Operation]framework.OperationFunc{
 				logical.UpdateOperation: i.pathOIDCCreateUpdateRole,
@@ -911,8 +938,33 @@ func (i *IdentityStore) pathOIDCGenerateToken(ctx context.Context, req *logical.
 		return nil, err
 	}
 
+	// Requested claims are merged over the template, so only the claims the
+	// role allows may be set
+	var requestClaims string
+	if claims := d.Get("claims").(map[string]interface{}); len(claims) > 0 {
+		for claim := range claims {
+			if !strutil.StrListContains(role.AllowedRequestClaims, claim) {
+				return logical.ErrorResponse("claim %q is not allowed by the role %q", claim, roleName), nil
+			}
+		}
+		encoded, err := json.Marshal(claims)
+		if err != nil {
+			return nil, err
+		}
+		requestClaims = string(encoded)
+	}
+
+	tokenTTL := role.TokenTTL
+	if ttlRaw, ok := d.GetOk("ttl"); ok {
+		ttl := time.Duration(ttlRaw.(int)) * time.Second
+		if ttl <= 0 || ttl > role.TokenTTL {
+			return logical.ErrorResponse("ttl must be positive and cannot exceed the ttl of the role"), nil
+		}
+		tokenTTL = ttl
+	}
+
 	retResp := &logical.Response{}
-	expiry := role.TokenTTL
+	expiry := tokenTTL
 	if expiry > key.VerificationTTL {
 		expiry = key.VerificationTTL
 		retResp.AddWarning(fmt.Sprintf("a role's token ttl cannot be longer "+
@@ -927,6 +979,8 @@ func (i *IdentityStore) pathOIDCGenerateToken(ctx context.Context, req *logical.
 		Audience:  role.ClientID,
 		Expiry:    now.Add(expiry).Unix(),
 		IssuedAt:  now.Unix(),
+
+		ExtraAudiences: role.Audiences,
 	}
 
 	e, err := i.MemDBEntityByID(req.EntityID, true)
@@ -958,7 +1012,11 @@ func (i *IdentityStore) pathOIDCGenerateToken(ctx context.Context, req *logical.
 		i.Logger().Warn("error populating OIDC token template", "template", role.Template, "error", err)
 	}
 
-	payload, err := idToken.generatePayload(i.Logger(), populatedTemplate)
+	templates := []string{populatedTemplate}
+	if requestClaims != "" {
+		templates = append(templates, requestClaims)
+	}
+	payload, err := idToken.generatePayload(i.Logger(), templates...)
 	if err != nil {
 		i.Logger().Warn("error populating OIDC token template", "error", err)
 	}
@@ -971,7 +1029,7 @@ func (i *IdentityStore) pathOIDCGenerateToken(ctx context.Context, req *logical.
 	retResp.Data = map[string]interface{}{
 		"token":     signedIdToken,
 		"client_id": role.ClientID,
-		"ttl":       int64(role.TokenTTL.Seconds()),
+		"ttl":       int64(tokenTTL.Seconds()),
 	}
 	return retResp, nil
 }
@@ -1022,6 +1080,11 @@ func (tok *idToken) generatePayload(logger hclog.Logger, templates ...string) ([
 		"iat":       tok.IssuedAt,
 	}
 
+	// The audience claim is an array when the token has several audiences
+	if len(tok.ExtraAudiences) > 0 {
+		output["aud"] = append([]string{tok.Audience}, tok.ExtraAudiences...)
+	}
+
 	// Copy optional claims into output
 	if len(tok.Nonce) > 0 {
 		output["nonce"] = tok.Nonce
@@ -1246,6 +1309,20 @@ func (i *IdentityStore) pathOIDCCreateUpdateRole(ctx context.Context, req *logic
 		role.ClientID = clientID.(string)
 	}
 
+	if audiences, ok := d.GetOk("audiences"); ok {
+		role.Audiences = audiences.([]string)
+	}
+
+	if allowedClaims, ok := d.GetOk("allowed_request_claims"); ok {
+		role.AllowedRequestClaims = allowedClaims.([]string)
+	}
+	for _, claim := range role.AllowedRequestClaims {
+		if strutil.StrListContains(reservedClaims, claim) {
+			return logical.ErrorResponse(`claim %q not allowed in allowed_request_claims. Restricted keys: %s`,
+				claim, strings.Join(reservedClaims, ", ")), nil
+		}
+	}
+
 	// create role path
 	if role.ClientID == "" {
 		clientID, err := base62.Random(26)
@@ -1289,6 +1366,9 @@ func (i *IdentityStore) pathOIDCReadRole(ctx context.Context, req *logical.Reque
 			"key":       role.Key,
 			"template":  role.Template,
 			"ttl":       int64(role.TokenTTL.Seconds()),
+
+			"audiences":              role.Audiences,
+			"allowed_request_claims": role.AllowedRequestClaims,
 		},
 	}, nil
 }
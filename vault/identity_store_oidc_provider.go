@@ -157,6 +157,7 @@ type providerDiscovery struct {
 	AuthorizationEndpoint string   `json:"authorization_endpoint"`
 	TokenEndpoint         string   `json:"token_endpoint"`
 	UserinfoEndpoint      string   `json:"userinfo_endpoint"`
+	IntrospectionEndpoint string   `json:"introspection_endpoint"`
 	RequestParameter      bool     `json:"request_parameter_supported"`
 	RequestURIParameter   bool     `json:"request_uri_parameter_supported"`
 	IDTokenAlgs           []string `json:"id_token_signing_alg_values_supported"`
@@ -595,6 +596,47 @@ func oidcProviderPaths(i *IdentityStore) []*framework.Path {
 			HelpSynopsis:    "Provides the OIDC Token Endpoint.",
 			HelpDescription: "The OIDC Token Endpoint allows a client to exchange its Authorization Grant for an Access Token and ID Token.",
 		},
+		{
+			Pattern: "oidc/provider/" + framework.GenericNameRegex("name") + "/introspect",
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "oidc-provider",
+				OperationVerb:   "introspect",
+			},
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the provider",
+				},
+				"token": {
+					Type:        framework.TypeString,
+					Description: "The access token to introspect.",
+					Required:    true,
+				},
+				"token_type_hint": {
+					Type:        framework.TypeString,
+					Description: "A hint about the type of the token. Only access tokens are issued by the provider, so the hint is ignored.",
+				},
+				// Clients authenticate to the introspection endpoint like to
+				// the token endpoint.
+				"client_id": {
+					Type:        framework.TypeString,
+					Description: "The ID of the requesting client.",
+				},
+				"client_secret": {
+					Type:        framework.TypeString,
+					Description: "The secret of the requesting client.",
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback:                    i.pathOIDCProviderIntrospect,
+					ForwardPerformanceStandby:   true,
+					ForwardPerformanceSecondary: false,
+				},
+			},
+			HelpSynopsis:    "Provides the OAuth 2.0 Token Introspection Endpoint.",
+			HelpDescription: "The Token Introspection Endpoint allows a confidential client to determine the state of an access token issued by the provider, as defined by RFC 7662.",
+		},
 		{
 			Pattern: "oidc/provider/" + framework.GenericNameRegex("name") + "/userinfo",
 			DisplayAttrs: &framework.DisplayAttributes{
@@ -1549,6 +1591,7 @@ func (i *IdentityStore) pathOIDCProviderDiscovery(ctx context.Context, req *logi
 		AuthorizationEndpoint: strings.Replace(p.effectiveIssuer, "/v1/", "/ui/vault/", 1) + "/authorize",
 		TokenEndpoint:         p.effectiveIssuer + "/token",
 		UserinfoEndpoint:      p.effectiveIssuer + "/userinfo",
+		IntrospectionEndpoint: p.effectiveIssuer + "/introspect",
 		IDTokenAlgs:           supportedAlgs,
 		Scopes:                scopes,
 		Claims:                []string{},
@@ -2213,6 +2256,92 @@ func tokenResponse(response map[string]interface{}, errorCode, errorDescription
 	}, nil
 }
 
+// pathOIDCProviderIntrospect describes an access token issued by the provider as an
+// OAuth 2.0 Token Introspection response. Only confidential clients allowed
+// to use the provider may introspect tokens. For details, see spec at
+// https://datatracker.ietf.org/doc/html/rfc7662
+func (i *IdentityStore) pathOIDCProviderIntrospect(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	// Get the namespace
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+
+	// Get the OIDC provider
+	name := d.Get("name").(string)
+	provider, err := i.getOIDCProvider(ctx, req.Storage, name)
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+	if provider == nil {
+		return tokenResponse(nil, ErrTokenInvalidRequest, "provider not found")
+	}
+
+	// client_secret_basic - Check for client credentials in the Authorization header
+	clientID, clientSecret, okBasicAuth := basicAuth(req)
+	if !okBasicAuth {
+		// client_secret_post - Check for client credentials in the request body
+		clientID = d.Get("client_id").(string)
+		if clientID == "" {
+			return tokenResponse(nil, ErrTokenInvalidRequest, "client_id parameter is required")
+		}
+		clientSecret = d.Get("client_secret").(string)
+	}
+	client, err := i.clientByID(ctx, req.Storage, clientID)
+	if err != nil {
+		return tokenResponse(nil, ErrTokenServerError, err.Error())
+	}
+	if client == nil {
+		i.Logger().Debug("client failed to authenticate with client not found", "client_id", clientID)
+		return tokenResponse(nil, ErrTokenInvalidClient, "client failed to authenticate")
+	}
+
+	// Public clients cannot authenticate, so they are not allowed to
+	// introspect tokens
+	if client.Type != confidential ||
+		subtle.ConstantTimeCompare([]byte(client.ClientSecret), []byte(clientSecret)) == 0 {
+		i.Logger().Debug("client failed to authenticate with invalid client secret", "client_id", clientID)
+		return tokenResponse(nil, ErrTokenInvalidClient, "client failed to authenticate")
+	}
+
+	// Validate that the client is authorized to use the provider
+	if !provider.allowedClientID(clientID) {
+		return tokenResponse(nil, ErrTokenInvalidClient, "client is not authorized to use the provider")
+	}
+
+	token := d.Get("token").(string)
+	if token == "" {
+		return tokenResponse(nil, ErrTokenInvalidRequest, "token parameter is required")
+	}
+
+	// Unknown and expired tokens, and tokens not issued by the provider are
+	// all reported as inactive
+	te, err := i.tokenStorer.LookupToken(ctx, token)
+	if err != nil || te == nil || te.Type != logical.TokenTypeBatch ||
+		te.NamespaceID != ns.ID || te.Path != "oidc/provider/"+name+"/token" {
+		return introspectionResponse(nil)
+	}
+	tokenClientID, ok := te.InternalMeta[accessTokenClientIDMeta]
+	if !ok {
+		return introspectionResponse(nil)
+	}
+
+	// The reserved "openid" scope is granted to every access token but not
+	// recorded in its metadata
+	scopes := append([]string{openIDScope}, strutil.ParseStringSlice(te.InternalMeta[accessTokenScopesMeta], scopesDelimiter)...)
+
+	return introspectionResponse(map[string]interface{}{
+		"scope":      strings.Join(scopes, " "),
+		"client_id":  tokenClientID,
+		"token_type": "Bearer",
+		"exp":        time.Unix(te.CreationTime, 0).Add(te.TTL).Unix(),
+		"iat":        te.CreationTime,
+		"sub":        te.EntityID,
+		"aud":        tokenClientID,
+		"iss":        provider.effectiveIssuer,
+	})
+}
+
 func (i *IdentityStore) pathOIDCUserInfo(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
 	// Get the namespace
 	ns, err := namespace.FromContext(ctx)
@@ -75,6 +75,71 @@ func TestOIDC_Path_OIDC_Cross_Provider_Exchange(t *testing.T) {
 	require.Equal(t, "authorization code was not issued by the provider", tokenRes.ErrorDescription)
 }
 
+func TestOIDC_Path_OIDC_Introspect(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+	s := new(logical.InmemStorage)
+
+	// Create the common OIDC configuration
+	entityID, _, _, clientID, clientSecret := setupOIDCCommon(t, c, s)
+
+	// Obtain an access token
+	var authRes struct {
+		Code string `json:"code"`
+	}
+	req := testAuthorizeReq(s, clientID)
+	req.EntityID = entityID
+	resp, err := c.identityStore.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(resp.Data["http_raw_body"].([]byte), &authRes))
+
+	var tokenRes struct {
+		AccessToken string `json:"access_token"`
+	}
+	resp, err = c.identityStore.HandleRequest(ctx, testTokenReq(s, authRes.Code, clientID, clientSecret))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(resp.Data["http_raw_body"].([]byte), &tokenRes))
+	require.NotEmpty(t, tokenRes.AccessToken)
+
+	introspect := func(token, clientSecret string) (int, map[string]interface{}) {
+		resp, err := c.identityStore.HandleRequest(ctx, &logical.Request{
+			Storage:   s,
+			Path:      "oidc/provider/test-provider/introspect",
+			Operation: logical.UpdateOperation,
+			Headers: map[string][]string{
+				"Authorization": {basicAuthHeader(clientID, clientSecret)},
+			},
+			Data: map[string]interface{}{
+				"token": token,
+			},
+		})
+		require.NoError(t, err)
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(resp.Data["http_raw_body"].([]byte), &body))
+		return resp.Data[logical.HTTPStatusCode].(int), body
+	}
+
+	// An active access token
+	status, body := introspect(tokenRes.AccessToken, clientSecret)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, true, body["active"])
+	require.Equal(t, clientID, body["client_id"])
+	require.Equal(t, entityID, body["sub"])
+	require.Equal(t, "openid", body["scope"])
+	require.Equal(t, "Bearer", body["token_type"])
+	require.NotZero(t, body["exp"])
+
+	// An unknown token
+	status, body = introspect("unknown", clientSecret)
+	require.Equal(t, http.StatusOK, status)
+	require.Equal(t, map[string]interface{}{"active": false}, body)
+
+	// A client failing to authenticate
+	status, body = introspect(tokenRes.AccessToken, "wrong-secret")
+	require.Equal(t, http.StatusUnauthorized, status)
+	require.Equal(t, ErrTokenInvalidClient, body["error"])
+}
+
 func TestOIDC_Path_OIDC_Token(t *testing.T) {
 	c, _, _ := TestCoreUnsealed(t)
 	ctx := namespace.RootContext(nil)
@@ -3633,6 +3698,7 @@ func TestOIDC_Path_OpenIDProviderConfig(t *testing.T) {
 		AuthorizationEndpoint: "/ui/vault/identity/oidc/provider/test-provider/authorize",
 		TokenEndpoint:         basePath + "/token",
 		UserinfoEndpoint:      basePath + "/userinfo",
+		IntrospectionEndpoint: basePath + "/introspect",
 		GrantTypes:            []string{"authorization_code"},
 		AuthMethods:           []string{"none", "client_secret_basic", "client_secret_post"},
 		RequestParameter:      false,
@@ -3689,6 +3755,7 @@ func TestOIDC_Path_OpenIDProviderConfig(t *testing.T) {
 		AuthorizationEndpoint: testIssuer + "/ui/vault/identity/oidc/provider/test-provider/authorize",
 		TokenEndpoint:         basePath + "/token",
 		UserinfoEndpoint:      basePath + "/userinfo",
+		IntrospectionEndpoint: basePath + "/introspect",
 		GrantTypes:            []string{"authorization_code"},
 		AuthMethods:           []string{"none", "client_secret_basic", "client_secret_post"},
 		RequestParameter:      false,
@@ -116,10 +116,12 @@ func TestOIDC_Path_OIDCRole_UpdateNoKey(t *testing.T) {
 	})
 	expectSuccess(t, resp, err)
 	expected := map[string]interface{}{
-		"key":       "test-key",
-		"ttl":       int64(120),
-		"template":  "",
-		"client_id": resp.Data["client_id"],
+		"key":                    "test-key",
+		"ttl":                    int64(120),
+		"template":               "",
+		"client_id":              resp.Data["client_id"],
+		"audiences":              []string(nil),
+		"allowed_request_claims": []string(nil),
 	}
 	if diff := deep.Equal(expected, resp.Data); diff != nil {
 		t.Fatal(diff)
@@ -170,10 +172,12 @@ func TestOIDC_Path_OIDCRole_UpdateEmptyKey(t *testing.T) {
 	})
 	expectSuccess(t, resp, err)
 	expected := map[string]interface{}{
-		"key":       "test-key",
-		"ttl":       int64(86400),
-		"template":  "",
-		"client_id": resp.Data["client_id"],
+		"key":                    "test-key",
+		"ttl":                    int64(86400),
+		"template":               "",
+		"client_id":              resp.Data["client_id"],
+		"audiences":              []string(nil),
+		"allowed_request_claims": []string(nil),
 	}
 	if diff := deep.Equal(expected, resp.Data); diff != nil {
 		t.Fatal(diff)
@@ -212,10 +216,12 @@ func TestOIDC_Path_OIDCRoleRole(t *testing.T) {
 	})
 	expectSuccess(t, resp, err)
 	expected := map[string]interface{}{
-		"key":       "test-key",
-		"ttl":       int64(86400),
-		"template":  "",
-		"client_id": resp.Data["client_id"],
+		"key":                    "test-key",
+		"ttl":                    int64(86400),
+		"template":               "",
+		"client_id":              resp.Data["client_id"],
+		"audiences":              []string(nil),
+		"allowed_request_claims": []string(nil),
 	}
 	if diff := deep.Equal(expected, resp.Data); diff != nil {
 		t.Fatal(diff)
@@ -242,10 +248,12 @@ func TestOIDC_Path_OIDCRoleRole(t *testing.T) {
 	})
 	expectSuccess(t, resp, err)
 	expected = map[string]interface{}{
-		"key":       "test-key",
-		"ttl":       int64(7200),
-		"template":  "{\"some-key\":\"some-value\"}",
-		"client_id": "my_custom_id",
+		"key":                    "test-key",
+		"ttl":                    int64(7200),
+		"template":               "{\"some-key\":\"some-value\"}",
+		"client_id":              "my_custom_id",
+		"audiences":              []string(nil),
+		"allowed_request_claims": []string(nil),
 	}
 	if diff := deep.Equal(expected, resp.Data); diff != nil {
 		t.Fatal(diff)
@@ -1022,6 +1030,114 @@ func TestOIDC_SignIDToken_NilSigningKey(t *testing.T) {
 	expectStrings(t, []string{err.Error()}, expectedStrings)
 }
 
+// TestOIDC_GenerateToken_RequestClaims tests the audiences and the claims
+// and TTL set when generating a token
+func TestOIDC_GenerateToken_RequestClaims(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+	storage := &logical.InmemStorage{}
+
+	// Create and load an entity, an entity is required to generate an ID token
+	testEntity := &identity.Entity{
+		Name:      "test-entity-name",
+		ID:        "test-entity-id",
+		BucketKey: "test-entity-bucket-key",
+	}
+	txn := c.identityStore.db.Txn(true)
+	defer txn.Abort()
+	if err := c.identityStore.upsertEntityInTxn(ctx, txn, testEntity, nil, true); err != nil {
+		t.Fatal(err)
+	}
+	txn.Commit()
+
+	resp, err := c.identityStore.HandleRequest(ctx, &logical.Request{
+		Path:      "oidc/key/test-key",
+		Operation: logical.CreateOperation,
+		Data: map[string]interface{}{
+			"allowed_client_ids": "*",
+		},
+		Storage: storage,
+	})
+	expectSuccess(t, resp, err)
+
+	// Reserved claims cannot be allowed in requests
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Path:      "oidc/role/test-role",
+		Operation: logical.CreateOperation,
+		Data: map[string]interface{}{
+			"key":                    "test-key",
+			"allowed_request_claims": "workload,sub",
+		},
+		Storage: storage,
+	})
+	expectError(t, resp, err)
+
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Path:      "oidc/role/test-role",
+		Operation: logical.CreateOperation,
+		Data: map[string]interface{}{
+			"key":                    "test-key",
+			"client_id":              "test-client",
+			"ttl":                    "1h",
+			"template":               `{"name": {{identity.entity.name}}, "workload": "unset"}`,
+			"audiences":              "service-a,service-b",
+			"allowed_request_claims": "workload",
+		},
+		Storage: storage,
+	})
+	expectSuccess(t, resp, err)
+
+	generate := func(data map[string]interface{}) (*logical.Response, error) {
+		return c.identityStore.HandleRequest(ctx, &logical.Request{
+			Path:      "oidc/token/test-role",
+			Operation: logical.UpdateOperation,
+			Data:      data,
+			Storage:   storage,
+			EntityID:  "test-entity-id",
+		})
+	}
+
+	// Claims not allowed by the role and TTLs longer than the role's are
+	// rejected
+	resp, err = generate(map[string]interface{}{
+		"claims": map[string]interface{}{"other": "value"},
+	})
+	expectError(t, resp, err)
+	resp, err = generate(map[string]interface{}{
+		"ttl": "2h",
+	})
+	expectError(t, resp, err)
+
+	resp, err = generate(map[string]interface{}{
+		"ttl":    "5m",
+		"claims": map[string]interface{}{"workload": "batch-job"},
+	})
+	expectSuccess(t, resp, err)
+	if resp.Data["ttl"] != int64(300) {
+		t.Fatalf("bad ttl: %#v", resp.Data["ttl"])
+	}
+
+	parsedToken, err := jwt.ParseSigned(resp.Data["token"].(string))
+	if err != nil {
+		t.Fatalf("error parsing token: %s", err.Error())
+	}
+	var claims map[string]interface{}
+	if err := parsedToken.UnsafeClaimsWithoutVerification(&claims); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedAudience := []interface{}{"test-client", "service-a", "service-b"}
+	if diff := deep.Equal(expectedAudience, claims["aud"]); diff != nil {
+		t.Fatal(diff)
+	}
+	if claims["name"] != "test-entity-name" || claims["workload"] != "batch-job" {
+		t.Fatalf("bad claims: %#v", claims)
+	}
+	if claims["exp"].(float64)-claims["iat"].(float64) != 300 {
+		t.Fatalf("bad expiry: %#v", claims)
+	}
+}
+
 func testNamedKey(name string) *namedKey {
 	return &namedKey{
 		name:            name,
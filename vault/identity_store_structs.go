@@ -93,16 +93,21 @@ type IdentityStore struct {
 	// operated case insensitively
 	disableLowerCasedNames bool
 
-	router        *Router
-	redirectAddr  string
-	localNode     LocalNode
-	namespacer    Namespacer
-	metrics       metricsutil.Metrics
-	totpPersister TOTPPersister
-	groupUpdater  GroupUpdater
-	tokenStorer   TokenStorer
-	entityCreator EntityCreator
-	mfaBackend    *LoginMFABackend
+	router         *Router
+	redirectAddr   string
+	localNode      LocalNode
+	namespacer     Namespacer
+	metrics        metricsutil.Metrics
+	totpPersister  TOTPPersister
+	groupUpdater   GroupUpdater
+	groupRefresher GroupRefresher
+	tokenStorer    TokenStorer
+	entityCreator  EntityCreator
+	mfaBackend     *LoginMFABackend
+
+	// groupRefresh tracks the background refreshes of external group
+	// memberships
+	groupRefresh groupRefreshState
 }
 
 type groupDiff struct {
@@ -141,6 +146,12 @@ type GroupUpdater interface {
 
 var _ GroupUpdater = &Core{}
 
+type GroupRefresher interface {
+	RefreshAuthLogins(ctx context.Context, mountAccessors map[string]bool, refreshFn externalGroupRefreshFunc) error
+}
+
+var _ GroupRefresher = &Core{}
+
 type TokenStorer interface {
 	LookupToken(context.Context, string) (*logical.TokenEntry, error)
 	CreateToken(context.Context, *logical.TokenEntry) error
@@ -58,6 +58,9 @@ type Key struct {
 	Value       []byte
 	InstallTime time.Time
 	Encryptions uint64 `json:"encryptions,omitempty"`
+
+	// RotationReason is the reason of the rotation which installed the key
+	RotationReason string `json:"rotation_reason,omitempty"`
 }
 
 type KeyRotationConfig struct {
@@ -130,12 +133,8 @@ func (k *Keyring) AddKey(key *Key) (*Keyring, error) {
 		clone.activeTerm = key.Term
 	}
 
-	// Zero out encryption estimates for previous terms
-	for term, key := range clone.keys {
-		if term != clone.activeTerm {
-			key.Encryptions = 0
-		}
-	}
+	// Encryption estimates of previous terms are kept as the history of
+	// the keys
 
 	return clone, nil
 }
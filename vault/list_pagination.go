@@ -0,0 +1,73 @@
+package vault
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/go-secure-stdlib/parseutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+// paginateListResponse limits the keys of a list response to the limit given
+// by the request, or to the default limit of the configuration, keeping only
+// the keys following the after parameter of the request, if any. When keys
+// are left out, a warning tells how to list them.
+func (c *Core) paginateListResponse(req *logical.Request, resp *logical.Response) error {
+	if resp == nil || resp.IsError() || resp.Data == nil {
+		return nil
+	}
+	keys, ok := resp.Data["keys"].([]string)
+	if !ok {
+		return nil
+	}
+
+	var after string
+	if raw, ok := req.Data["after"]; ok {
+		after, ok = raw.(string)
+		if !ok {
+			return logical.CodedError(400, "after must be a string")
+		}
+	}
+
+	limit := c.listDefaultLimit.Load()
+	if raw, ok := req.Data["limit"]; ok {
+		requested, err := parseutil.ParseInt(raw)
+		if err != nil {
+			return logical.CodedError(400, fmt.Sprintf("invalid limit: %v", err))
+		}
+		if requested <= 0 {
+			return logical.CodedError(400, "limit must be positive")
+		}
+		limit = requested
+	}
+
+	if after == "" && (limit <= 0 || int64(len(keys)) <= limit) {
+		return nil
+	}
+
+	paged := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key > after {
+			paged = append(paged, key)
+		}
+	}
+	sort.Strings(paged)
+
+	if limit > 0 && int64(len(paged)) > limit {
+		paged = paged[:limit]
+		resp.AddWarning(fmt.Sprintf("The response was limited to %d keys. To list the following keys, repeat the request with after=%q, and optionally a limit.", limit, paged[len(paged)-1]))
+	}
+
+	if keyInfo, ok := resp.Data["key_info"].(map[string]interface{}); ok && len(paged) != len(keys) {
+		pagedInfo := make(map[string]interface{}, len(paged))
+		for _, key := range paged {
+			if info, ok := keyInfo[key]; ok {
+				pagedInfo[key] = info
+			}
+		}
+		resp.Data["key_info"] = pagedInfo
+	}
+	resp.Data["keys"] = paged
+
+	return nil
+}
@@ -65,6 +65,7 @@ func LeaseSwitchedPassthroughBackend(ctx context.Context, conf *logical.BackendC
 					logical.DeleteOperation: b.handleDelete,
 					logical.ListOperation:   b.handleList,
 				},
+				DryRun: true,
 
 				ExistenceCheck: b.handleExistenceCheck,
 
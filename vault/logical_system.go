@@ -7,6 +7,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"crypto/sha512"
+	"encoding"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
@@ -17,9 +18,11 @@ import (
 	"net/http"
 	"path"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 	"unicode"
 
@@ -31,6 +34,7 @@ import (
 	"github.com/hashicorp/go-secure-stdlib/strutil"
 	semver "github.com/hashicorp/go-version"
 	"github.com/mitchellh/mapstructure"
+	"github.com/openbao/openbao/command/server"
 	"github.com/openbao/openbao/helper/hostutil"
 	"github.com/openbao/openbao/helper/identity"
 	"github.com/openbao/openbao/helper/locking"
@@ -48,12 +52,17 @@ import (
 	"github.com/openbao/openbao/sdk/v2/helper/wrapping"
 	"github.com/openbao/openbao/sdk/v2/logical"
 	"github.com/openbao/openbao/version"
+	"golang.org/x/crypto/blake2b"
 	"golang.org/x/crypto/sha3"
 )
 
 const (
 	maxBytes    = 128 * 1024
 	globalScope = "global"
+
+	// maxCapabilitiesPaths is the maximum number of paths queried in a single
+	// capabilities request.
+	maxCapabilitiesPaths = 1000
 )
 
 func systemBackendMemDBSchema() *memdb.DBSchema {
@@ -86,9 +95,32 @@ func NewSystemBackend(core *Core, logger log.Logger) *SystemBackend {
 				"raw",
 				"raw/*",
 				"rotate",
+				"rotate/roots",
+				"rotate/roots/*",
+				"import",
+				"import/*",
+				"network-policy",
+				"network-policy/*",
 				"config/cors",
+				"config/cache",
 				"config/auditing/*",
+				"config/reload/*",
+				"config/state/apply",
+				"config/export",
+				"config/import",
+				"config/backup-encryption",
+				"config/ttl-policies",
+				"config/ttl-policies/*",
+				"federation/tokens",
+				"federation/tokens/*",
+				"federation/clusters",
+				"federation/clusters/*",
+				"federation/config",
+				"payload-encryption/rotate",
 				"config/ui/headers/*",
+				"config/ui/banners",
+				"config/ui/banners/*",
+				"config/ui/banner-acknowledgments/*",
 				"plugins/catalog/*",
 				"revoke-prefix/*",
 				"revoke-force/*",
@@ -96,17 +128,23 @@ func NewSystemBackend(core *Core, logger log.Logger) *SystemBackend {
 				"leases/revoke-force/*",
 				"leases/lookup/*",
 				"leases",
+				"leases/irrevocable",
+				"leases/irrevocable/revoke-force",
 				"internal/inspect/*",
+				"generate-root/history/*",
+				"restricted-crypto/report",
 			},
 
 			Unauthenticated: []string{
 				"wrapping/lookup",
 				"wrapping/pubkey",
+				"payload-encryption/key",
 				"replication/status",
 				"internal/specs/openapi",
 				"internal/ui/mounts",
 				"internal/ui/mounts/*",
 				"internal/ui/namespaces",
+				"internal/ui/banners",
 				"storage/raft/bootstrap/challenge",
 				"storage/raft/bootstrap/answer",
 				"init",
@@ -124,6 +162,7 @@ func NewSystemBackend(core *Core, logger log.Logger) *SystemBackend {
 				"rekey-recovery-key/update",
 				"rekey-recovery-key/verify",
 				"mfa/validate",
+				"federation/report",
 			},
 
 			LocalStorage: []string{
@@ -134,6 +173,18 @@ func NewSystemBackend(core *Core, logger log.Logger) *SystemBackend {
 	}
 
 	b.Backend.Paths = append(b.Backend.Paths, b.configPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.configApplyPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.configExportPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.backupEncryptionPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.payloadEncryptionPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.ttlPolicyPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.federationPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.loginBannerPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.deprecationPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.rootRotationPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.secretsImportPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.networkPolicyPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.restrictedCryptoPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.rekeyPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.sealPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.statusPaths()...)
@@ -141,6 +192,8 @@ func NewSystemBackend(core *Core, logger log.Logger) *SystemBackend {
 	b.Backend.Paths = append(b.Backend.Paths, b.pluginsCatalogCRUDPath())
 	b.Backend.Paths = append(b.Backend.Paths, b.pluginsReloadPath())
 	b.Backend.Paths = append(b.Backend.Paths, b.auditPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.configHistoryPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.deletedMountPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.mountPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.authPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.lockedUserPaths()...)
@@ -150,6 +203,8 @@ func NewSystemBackend(core *Core, logger log.Logger) *SystemBackend {
 	b.Backend.Paths = append(b.Backend.Paths, b.toolsPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.capabilitiesPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.internalPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.internalUIDashboardPaths()...)
+	b.Backend.Paths = append(b.Backend.Paths, b.wellKnownPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.pprofPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.remountPaths()...)
 	b.Backend.Paths = append(b.Backend.Paths, b.metricsPath())
@@ -186,6 +241,10 @@ type SystemBackend struct {
 	Core   *Core
 	db     *memdb.MemDB
 	logger log.Logger
+
+	// mountHistoryLock serializes tuning so that the recorded mount
+	// configuration versions follow the order of the changes.
+	mountHistoryLock sync.Mutex
 }
 
 // handleConfigStateSanitized returns the current configuration state. The configuration
@@ -199,9 +258,29 @@ func (b *SystemBackend) handleConfigStateSanitized(ctx context.Context, req *log
 	return resp, nil
 }
 
+// handleConfigSchemaRead returns the schema of the server configuration
+func (b *SystemBackend) handleConfigSchemaRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"fields": server.ConfigSchema(),
+		},
+	}, nil
+}
+
 // handleConfigReload handles reloading specific pieces of the configuration.
 func (b *SystemBackend) handleConfigReload(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
-	return nil, logical.ErrUnsupportedPath
+	switch subsystem := data.Get("subsystem").(string); subsystem {
+	case "config":
+		if b.Core.reloadConfigFunc == nil {
+			return logical.ErrorResponse("reloading the configuration is not supported by this server"), logical.ErrInvalidRequest
+		}
+		if err := b.Core.reloadConfigFunc(); err != nil {
+			return nil, fmt.Errorf("error(s) were encountered during reload: %w", err)
+		}
+		return nil, nil
+	default:
+		return nil, logical.ErrUnsupportedPath
+	}
 }
 
 // handleCORSRead returns the current CORS configuration
@@ -241,6 +320,62 @@ func (b *SystemBackend) handleCORSDelete(ctx context.Context, req *logical.Reque
 	return nil, b.Core.corsConfig.Disable(ctx)
 }
 
+// handleCacheConfigRead returns the physical cache settings
+func (b *SystemBackend) handleCacheConfigRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	cache, err := b.Core.tunableCache()
+	if err != nil {
+		return nil, err
+	}
+	stats := cache.Stats()
+
+	bypassPrefixes := stats.BypassPrefixes
+	if bypassPrefixes == nil {
+		bypassPrefixes = []string{}
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"size":            stats.Size,
+			"bypass_prefixes": bypassPrefixes,
+		},
+	}, nil
+}
+
+// handleCacheConfigUpdate updates the given physical cache settings, and
+// applies them to the cache of this node.
+func (b *SystemBackend) handleCacheConfigUpdate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	conf, err := b.Core.readCacheConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if sizeRaw, ok := d.GetOk("size"); ok {
+		size := sizeRaw.(int)
+		if size < 0 {
+			return logical.ErrorResponse("size cannot be negative"), logical.ErrInvalidRequest
+		}
+		conf.Size = size
+	}
+
+	if prefixesRaw, ok := d.GetOk("bypass_prefixes"); ok {
+		conf.BypassPrefixes = nil
+		for _, prefix := range prefixesRaw.([]string) {
+			if prefix == "" {
+				return logical.ErrorResponse("bypass prefixes cannot be empty"), logical.ErrInvalidRequest
+			}
+			conf.BypassPrefixes = append(conf.BypassPrefixes, prefix)
+		}
+	}
+
+	return nil, b.Core.saveCacheConfig(ctx, conf)
+}
+
+// handleCacheConfigDelete resets the physical cache settings to the server
+// configuration.
+func (b *SystemBackend) handleCacheConfigDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	return nil, b.Core.saveCacheConfig(ctx, &CacheConfig{})
+}
+
 func (b *SystemBackend) handleTidyLeases(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
 	ns, err := namespace.FromContext(ctx)
 	if err != nil {
@@ -280,6 +415,69 @@ func (b *SystemBackend) handleLeaseCount(ctx context.Context, req *logical.Reque
 	}, nil
 }
 
+func (b *SystemBackend) handleLeaseRevocationQueue(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	return &logical.Response{
+		Data: b.Core.expiration.revocationQueueStatus(),
+	}, nil
+}
+
+func (b *SystemBackend) handleLeaseIrrevocableRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	resp, err := b.Core.expiration.irrevocableLeasesByMount(ctx, d.Get("include_child_namespaces").(bool))
+	if err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: resp,
+	}, nil
+}
+
+func (b *SystemBackend) handleLeaseIrrevocableRevokeForce(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	leases, err := b.Core.expiration.matchingIrrevocableLeases(ctx, &irrevocableLeaseFilter{
+		includeChildNamespaces: d.Get("include_child_namespaces").(bool),
+		mountAccessor:          d.Get("mount_accessor").(string),
+		errorContains:          d.Get("error_contains").(string),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if d.Get("dry_run").(bool) {
+		resp := &logical.Response{
+			Data: map[string]interface{}{
+				"lease_count": len(leases),
+			},
+		}
+		if len(leases) > MaxIrrevocableLeasesToReturn {
+			resp.AddWarning(MaxIrrevocableLeasesWarning)
+			leases = leases[:MaxIrrevocableLeasesToReturn]
+		}
+		matching := make([]*leaseResponse, 0, len(leases))
+		for _, lease := range leases {
+			matching = append(matching, lease.leaseResponse)
+		}
+		resp.Data["leases"] = matching
+		return resp, nil
+	}
+
+	confirmCount, ok := d.GetOk("confirm_count")
+	if !ok {
+		return logical.ErrorResponse("confirm_count is required unless dry_run is true"), logical.ErrInvalidRequest
+	}
+	if confirmCount.(int) != len(leases) {
+		return logical.ErrorResponse(errIrrevocableLeaseCountMismatch(confirmCount.(int), len(leases)).Error()), logical.ErrInvalidRequest
+	}
+
+	failed := b.Core.expiration.revokeIrrevocableLeases(ctx, leases)
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"lease_count": len(leases),
+			"revoked":     len(leases) - len(failed),
+			"failed":      failed,
+		},
+	}, nil
+}
+
 func processLimit(d *framework.FieldData) (bool, int, error) {
 	limitStr := ""
 	limitRaw, ok := d.GetOk("limit")
@@ -726,7 +924,8 @@ func (b *SystemBackend) handleCapabilitiesAccessor(ctx context.Context, req *log
 	return b.handleCapabilities(ctx, req, d)
 }
 
-// handleCapabilities returns the ACL capabilities of the token for a given path
+// handleCapabilities returns the ACL capabilities of the token for the given
+// paths
 func (b *SystemBackend) handleCapabilities(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
 	var token string
 	if strings.HasSuffix(req.Path, "capabilities-self") {
@@ -754,15 +953,27 @@ func (b *SystemBackend) handleCapabilities(ctx context.Context, req *logical.Req
 	if len(paths) == 0 {
 		return logical.ErrorResponse("paths must be supplied"), nil
 	}
+	if len(paths) > maxCapabilitiesPaths {
+		return logical.ErrorResponse("at most %d paths can be supplied", maxCapabilitiesPaths), nil
+	}
 
-	for _, path := range paths {
-		pathCap, err := b.Core.Capabilities(ctx, token, path)
-		if err != nil {
-			if !strings.HasSuffix(req.Path, "capabilities-self") && errwrap.Contains(err, logical.ErrPermissionDenied.Error()) {
-				return nil, &logical.StatusBadRequest{Err: "invalid token"}
-			}
-			return nil, err
+	explain := d.Get("explain").(bool)
+	capabilities, explanations, err := b.Core.CapabilitiesBatch(ctx, token, paths, explain)
+	if err != nil {
+		if !strings.HasSuffix(req.Path, "capabilities-self") && errwrap.Contains(err, logical.ErrPermissionDenied.Error()) {
+			return nil, &logical.StatusBadRequest{Err: "invalid token"}
 		}
+		return nil, err
+	}
+	// With explain, the capabilities are nested so that no queried path can
+	// collide with the explanations.
+	if explain {
+		ret.Data["capabilities"] = capabilities
+		ret.Data["explain"] = explanations
+		return ret, nil
+	}
+
+	for path, pathCap := range capabilities {
 		ret.Data[path] = pathCap
 	}
 
@@ -898,6 +1109,24 @@ func (b *SystemBackend) mountInfo(ctx context.Context, entry *MountEntry) map[st
 	if len(entry.Config.ListingVisibility) > 0 {
 		entryConfig["listing_visibility"] = entry.Config.ListingVisibility
 	}
+	if entry.Config.Protected {
+		entryConfig["protected"] = true
+	}
+	if entry.Config.DefaultRequestTimeout != 0 {
+		entryConfig["default_request_timeout"] = int64(entry.Config.DefaultRequestTimeout.Seconds())
+	}
+	if entry.Config.MaxRequestSize != 0 {
+		entryConfig["max_request_size"] = entry.Config.MaxRequestSize
+	}
+	if entry.Config.MaxConcurrentRequests != 0 {
+		entryConfig["max_concurrent_requests"] = entry.Config.MaxConcurrentRequests
+	}
+	if entry.Config.LazyLoad {
+		entryConfig["lazy_load"] = true
+	}
+	if entry.Config.TokenBindClientCert {
+		entryConfig["token_bind_client_cert"] = true
+	}
 	if rawVal, ok := entry.synthesizedConfigCache.Load("passthrough_request_headers"); ok {
 		entryConfig["passthrough_request_headers"] = rawVal.([]string)
 	}
@@ -1092,6 +1321,16 @@ func (b *SystemBackend) handleMount(ctx context.Context, req *logical.Request, d
 		return logical.ErrorResponse(fmt.Sprintf("invalid listing_visibility %s", apiConfig.ListingVisibility)), nil
 	}
 	config.ListingVisibility = apiConfig.ListingVisibility
+	config.Protected = apiConfig.Protected
+	config.LazyLoad = apiConfig.LazyLoad
+
+	if apiConfig.TokenBindClientCert {
+		return logical.ErrorResponse("token_bind_client_cert is only supported by auth methods"), logical.ErrInvalidRequest
+	}
+
+	if err := setRequestLimits(&config, &apiConfig); err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
 
 	if len(apiConfig.AuditNonHMACRequestKeys) > 0 {
 		config.AuditNonHMACRequestKeys = apiConfig.AuditNonHMACRequestKeys
@@ -1219,6 +1458,22 @@ func handleErrorNoReadOnlyForward(
 	}
 }
 
+// handleGenerateRootHistory lists the recorded root generation attempts
+func (b *SystemBackend) handleGenerateRootHistory(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	ceremonies, err := b.Core.GenerateRootHistory(ctx)
+	if err != nil {
+		return handleError(err)
+	}
+
+	keys := make([]string, 0, len(ceremonies))
+	keyInfo := make(map[string]interface{}, len(ceremonies))
+	for _, ceremony := range ceremonies {
+		keys = append(keys, ceremony.Nonce)
+		keyInfo[ceremony.Nonce] = ceremony.toMap()
+	}
+	return logical.ListResponseWithInfo(keys, keyInfo), nil
+}
+
 // handleUnmount is used to unmount a path
 func (b *SystemBackend) handleUnmount(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
 	path := data.Get("path").(string)
@@ -1242,12 +1497,16 @@ func (b *SystemBackend) handleUnmount(ctx context.Context, req *logical.Request,
 		return handleError(fmt.Errorf("unable to find storage for path: %q", path))
 	}
 
+	entry := b.Core.router.MatchingMountEntry(ctx, path)
+
 	// Attempt unmount
 	if err := b.Core.unmount(ctx, path); err != nil {
 		b.Backend.Logger().Error("unmount failed", "path", path, "error", err)
 		return handleError(err)
 	}
 
+	b.clearMountHistory(ctx, entry)
+
 	return nil, nil
 }
 
@@ -1358,6 +1617,11 @@ func (b *SystemBackend) handleRemount(ctx context.Context, req *logical.Request,
 		err := b.moveMount(ns, logger, migrationID, entry, fromPathDetails, toPathDetails)
 		if err != nil {
 			logger.Error("remount failed", "error", err)
+			if err := b.Core.updateMigrationInfo(migrationID, func(info *MountMigrationInfo) {
+				info.Error = err.Error()
+			}); err != nil {
+				logger.Error("Setting migration error failed", "error", err)
+			}
 			if err := b.Core.setMigrationStatus(migrationID, MigrationFailureStatus); err != nil {
 				logger.Error("Setting migration status failed", "error", err, "target_status", MigrationFailureStatus)
 			}
@@ -1369,7 +1633,7 @@ func (b *SystemBackend) handleRemount(ctx context.Context, req *logical.Request,
 			"migration_id": migrationID,
 		},
 	}
-	resp.AddWarning("Mount move has been queued. Progress will be reported in OpenBao's server log, tagged with the returned migration_id")
+	resp.AddWarning("Mount move has been queued. Progress will be reported by sys/remount/status/:migration_id and in OpenBao's server log, tagged with the returned migration_id")
 	return resp, nil
 }
 
@@ -1380,13 +1644,27 @@ func (b *SystemBackend) moveMount(ns *namespace.Namespace, logger log.Logger, mi
 	logger.Info("Starting to update the mount table and revoke leases")
 	revokeCtx := namespace.ContextWithNamespace(b.Core.activeContext, ns)
 
+	if err := b.Core.updateMigrationInfo(migrationID, func(info *MountMigrationInfo) {
+		info.Stage = MigrationStageRevokingLeases
+	}); err != nil {
+		return err
+	}
+	progress := func(revoked, total int) {
+		if err := b.Core.updateMigrationInfo(migrationID, func(info *MountMigrationInfo) {
+			info.LeasesRevoked = revoked
+			info.LeasesTotal = total
+		}); err != nil {
+			logger.Warn("Updating migration progress failed", "error", err)
+		}
+	}
+
 	var err error
 	// Attempt remount
 	switch entry.Table {
 	case credentialTableType:
-		err = b.Core.remountCredential(revokeCtx, fromPathDetails, toPathDetails, true)
+		err = b.Core.remountCredential(revokeCtx, fromPathDetails, toPathDetails, true, progress)
 	case mountTableType:
-		err = b.Core.remountSecretsEngine(revokeCtx, fromPathDetails, toPathDetails, true)
+		err = b.Core.remountSecretsEngine(revokeCtx, fromPathDetails, toPathDetails, true, progress)
 	default:
 		return fmt.Errorf("cannot remount mount of table %q", entry.Table)
 	}
@@ -1400,6 +1678,11 @@ func (b *SystemBackend) moveMount(ns *namespace.Namespace, logger log.Logger, mi
 	}
 
 	logger.Info("Updating quotas associated with the source mount")
+	if err := b.Core.updateMigrationInfo(migrationID, func(info *MountMigrationInfo) {
+		info.Stage = MigrationStageUpdatingQuotas
+	}); err != nil {
+		return err
+	}
 	// Update quotas with the new path and namespace
 	if err := b.Core.quotaManager.HandleRemount(revokeCtx, fromPathDetails, toPathDetails); err != nil {
 		return err
@@ -1444,6 +1727,33 @@ func (b *SystemBackend) handleRemountStatusCheck(ctx context.Context, req *logic
 	return resp, nil
 }
 
+// handleMountWarmUp loads the backend of a mount whose loading was deferred
+// at unseal, ahead of the first request routed to it.
+func (b *SystemBackend) handleMountWarmUp(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	path := data.Get("path").(string)
+	if path == "" {
+		return logical.ErrorResponse("missing path"), logical.ErrInvalidRequest
+	}
+	path = sanitizePath(path)
+
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify exact match of the route
+	if match := b.Core.router.MatchingMount(ctx, path); match == "" || ns.Path+path != match {
+		return logical.ErrorResponse(fmt.Sprintf("no mount at %q", path)), logical.ErrInvalidRequest
+	}
+
+	if err := b.Core.router.LoadLazyMount(ctx, path); err != nil {
+		b.Backend.Logger().Error("warm-up failed", "path", path, "error", err)
+		return handleError(err)
+	}
+
+	return nil, nil
+}
+
 // handleMountTuneRead is used to get config settings on a backend
 func (b *SystemBackend) handleMountTuneRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
 	path := data.Get("path").(string)
@@ -1505,6 +1815,30 @@ func (b *SystemBackend) handleTuneReadCommon(ctx context.Context, path string) (
 		resp.Data["listing_visibility"] = mountEntry.Config.ListingVisibility
 	}
 
+	if mountEntry.Config.Protected {
+		resp.Data["protected"] = true
+	}
+
+	if mountEntry.Config.DefaultRequestTimeout != 0 {
+		resp.Data["default_request_timeout"] = int64(mountEntry.Config.DefaultRequestTimeout.Seconds())
+	}
+
+	if mountEntry.Config.MaxRequestSize != 0 {
+		resp.Data["max_request_size"] = mountEntry.Config.MaxRequestSize
+	}
+
+	if mountEntry.Config.MaxConcurrentRequests != 0 {
+		resp.Data["max_concurrent_requests"] = mountEntry.Config.MaxConcurrentRequests
+	}
+
+	if mountEntry.Config.LazyLoad {
+		resp.Data["lazy_load"] = true
+	}
+
+	if mountEntry.Config.TokenBindClientCert {
+		resp.Data["token_bind_client_cert"] = true
+	}
+
 	if rawVal, ok := mountEntry.synthesizedConfigCache.Load("passthrough_request_headers"); ok {
 		resp.Data["passthrough_request_headers"] = rawVal.([]string)
 	}
@@ -1542,7 +1876,7 @@ func (b *SystemBackend) handleAuthTuneWrite(ctx context.Context, req *logical.Re
 		return logical.ErrorResponse("missing path"), nil
 	}
 
-	return b.handleTuneWriteCommon(ctx, "auth/"+path, data)
+	return b.handleTuneWriteWithHistory(ctx, req, "auth/"+path, data)
 }
 
 // handleMountTuneWrite is used to set config settings on a backend
@@ -1555,7 +1889,7 @@ func (b *SystemBackend) handleMountTuneWrite(ctx context.Context, req *logical.R
 	// This call will write both logical backend's configuration as well as auth methods'.
 	// Retaining this behavior for backward compatibility. If this behavior is not desired,
 	// an error can be returned if path has a prefix of "auth/".
-	return b.handleTuneWriteCommon(ctx, path, data)
+	return b.handleTuneWriteWithHistory(ctx, req, path, data)
 }
 
 // handleTuneWriteCommon is used to set config settings on a path
@@ -1881,6 +2215,147 @@ func (b *SystemBackend) handleTuneWriteCommon(ctx context.Context, path string,
 		}
 	}
 
+	if rawVal, ok := data.GetOk("protected"); ok {
+		oldVal := mountEntry.Config.Protected
+		mountEntry.Config.Protected = rawVal.(bool)
+
+		// Update the mount table
+		var err error
+		switch {
+		case strings.HasPrefix(path, "auth/"):
+			err = b.Core.persistAuth(ctx, b.Core.auth, &mountEntry.Local)
+		default:
+			err = b.Core.persistMounts(ctx, b.Core.mounts, &mountEntry.Local)
+		}
+		if err != nil {
+			mountEntry.Config.Protected = oldVal
+			return handleError(err)
+		}
+
+		if b.Core.logger.IsInfo() {
+			b.Core.logger.Info("mount tuning of protected successful", "path", path, "protected", mountEntry.Config.Protected)
+		}
+	}
+
+	if rawVal, ok := data.GetOk("default_request_timeout"); ok {
+		timeout := time.Duration(rawVal.(int)) * time.Second
+		if timeout < 0 {
+			return logical.ErrorResponse("default_request_timeout cannot be negative"), logical.ErrInvalidRequest
+		}
+
+		oldVal := mountEntry.Config.DefaultRequestTimeout
+		mountEntry.Config.DefaultRequestTimeout = timeout
+
+		// Update the mount table
+		var err error
+		switch {
+		case strings.HasPrefix(path, "auth/"):
+			err = b.Core.persistAuth(ctx, b.Core.auth, &mountEntry.Local)
+		default:
+			err = b.Core.persistMounts(ctx, b.Core.mounts, &mountEntry.Local)
+		}
+		if err != nil {
+			mountEntry.Config.DefaultRequestTimeout = oldVal
+			return handleError(err)
+		}
+
+		if b.Core.logger.IsInfo() {
+			b.Core.logger.Info("mount tuning of default_request_timeout successful", "path", path, "default_request_timeout", timeout)
+		}
+	}
+
+	if rawVal, ok := data.GetOk("max_request_size"); ok {
+		maxRequestSize := rawVal.(int64)
+		if maxRequestSize < 0 {
+			return logical.ErrorResponse("max_request_size cannot be negative"), logical.ErrInvalidRequest
+		}
+
+		oldVal := mountEntry.Config.MaxRequestSize
+		mountEntry.Config.MaxRequestSize = maxRequestSize
+
+		// Update the mount table
+		var err error
+		switch {
+		case strings.HasPrefix(path, "auth/"):
+			err = b.Core.persistAuth(ctx, b.Core.auth, &mountEntry.Local)
+		default:
+			err = b.Core.persistMounts(ctx, b.Core.mounts, &mountEntry.Local)
+		}
+		if err != nil {
+			mountEntry.Config.MaxRequestSize = oldVal
+			return handleError(err)
+		}
+
+		if b.Core.logger.IsInfo() {
+			b.Core.logger.Info("mount tuning of max_request_size successful", "path", path, "max_request_size", maxRequestSize)
+		}
+	}
+
+	if rawVal, ok := data.GetOk("max_concurrent_requests"); ok {
+		maxConcurrentRequests := rawVal.(int)
+		if maxConcurrentRequests < 0 {
+			return logical.ErrorResponse("max_concurrent_requests cannot be negative"), logical.ErrInvalidRequest
+		}
+
+		oldVal := mountEntry.Config.MaxConcurrentRequests
+		mountEntry.Config.MaxConcurrentRequests = maxConcurrentRequests
+
+		// Update the mount table
+		var err error
+		switch {
+		case strings.HasPrefix(path, "auth/"):
+			err = b.Core.persistAuth(ctx, b.Core.auth, &mountEntry.Local)
+		default:
+			err = b.Core.persistMounts(ctx, b.Core.mounts, &mountEntry.Local)
+		}
+		if err != nil {
+			mountEntry.Config.MaxConcurrentRequests = oldVal
+			return handleError(err)
+		}
+
+		if b.Core.logger.IsInfo() {
+			b.Core.logger.Info("mount tuning of max_concurrent_requests successful", "path", path, "max_concurrent_requests", maxConcurrentRequests)
+		}
+	}
+
+	if rawVal, ok := data.GetOk("lazy_load"); ok {
+		if strings.HasPrefix(path, "auth/") {
+			return logical.ErrorResponse("'lazy_load' can only be modified on secrets engines"), logical.ErrInvalidRequest
+		}
+
+		oldVal := mountEntry.Config.LazyLoad
+		mountEntry.Config.LazyLoad = rawVal.(bool)
+
+		// Update the mount table, the change is applied at the next unseal
+		if err := b.Core.persistMounts(ctx, b.Core.mounts, &mountEntry.Local); err != nil {
+			mountEntry.Config.LazyLoad = oldVal
+			return handleError(err)
+		}
+
+		if b.Core.logger.IsInfo() {
+			b.Core.logger.Info("mount tuning of lazy_load successful", "path", path, "lazy_load", mountEntry.Config.LazyLoad)
+		}
+	}
+
+	if rawVal, ok := data.GetOk("token_bind_client_cert"); ok {
+		if !strings.HasPrefix(path, "auth/") {
+			return logical.ErrorResponse("'token_bind_client_cert' can only be modified on auth mounts"), logical.ErrInvalidRequest
+		}
+
+		oldVal := mountEntry.Config.TokenBindClientCert
+		mountEntry.Config.TokenBindClientCert = rawVal.(bool)
+
+		// Update the mount table
+		if err := b.Core.persistAuth(ctx, b.Core.auth, &mountEntry.Local); err != nil {
+			mountEntry.Config.TokenBindClientCert = oldVal
+			return handleError(err)
+		}
+
+		if b.Core.logger.IsInfo() {
+			b.Core.logger.Info("mount tuning of token_bind_client_cert successful", "path", path, "token_bind_client_cert", mountEntry.Config.TokenBindClientCert)
+		}
+	}
+
 	if rawVal, ok := data.GetOk("token_type"); ok {
 		if !strings.HasPrefix(path, "auth/") {
 			return logical.ErrorResponse(fmt.Sprintf("'token_type' can only be modified on auth mounts")), logical.ErrInvalidRequest
@@ -2488,6 +2963,16 @@ func (b *SystemBackend) handleEnableAuth(ctx context.Context, req *logical.Reque
 		return logical.ErrorResponse(fmt.Sprintf("invalid listing_visibility %s", apiConfig.ListingVisibility)), nil
 	}
 	config.ListingVisibility = apiConfig.ListingVisibility
+	config.Protected = apiConfig.Protected
+
+	if apiConfig.LazyLoad {
+		return logical.ErrorResponse("lazy_load is only supported by secrets engines"), logical.ErrInvalidRequest
+	}
+	config.TokenBindClientCert = apiConfig.TokenBindClientCert
+
+	if err := setRequestLimits(&config, &apiConfig); err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
 
 	if len(apiConfig.AuditNonHMACRequestKeys) > 0 {
 		config.AuditNonHMACRequestKeys = apiConfig.AuditNonHMACRequestKeys
@@ -2624,12 +3109,16 @@ func (b *SystemBackend) handleDisableAuth(ctx context.Context, req *logical.Requ
 		return handleError(fmt.Errorf("unable to find storage for path: %q", fullPath))
 	}
 
+	entry := b.Core.router.MatchingMountEntry(ctx, fullPath)
+
 	// Attempt disable
 	if err := b.Core.disableCredential(ctx, path); err != nil {
 		b.Backend.Logger().Error("disable auth mount failed", "path", path, "error", err)
 		return handleError(err)
 	}
 
+	b.clearMountHistory(ctx, entry)
+
 	return nil, nil
 }
 
@@ -2750,7 +3239,7 @@ func (b *SystemBackend) handlePoliciesSet(policyType PolicyType) framework.Opera
 		}
 
 		// Update the policy
-		if err := b.Core.policyStore.SetPolicy(ctx, policy); err != nil {
+		if err := b.Core.policyStore.SetPolicyWithAuthor(ctx, policy, configChangeAuthorFromRequest(req)); err != nil {
 			return handleError(err)
 		}
 
@@ -2762,7 +3251,7 @@ func (b *SystemBackend) handlePoliciesDelete(policyType PolicyType) framework.Op
 	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
 		name := data.Get("name").(string)
 
-		if err := b.Core.policyStore.DeletePolicy(ctx, name, policyType); err != nil {
+		if err := b.Core.policyStore.DeletePolicyWithAuthor(ctx, name, policyType, configChangeAuthorFromRequest(req)); err != nil {
 			return handleError(err)
 		}
 		return nil, nil
@@ -3169,16 +3658,35 @@ func (b *SystemBackend) handleKeyStatus(ctx context.Context, req *logical.Reques
 		return nil, err
 	}
 
-	resp := &logical.Response{
-		Data: map[string]interface{}{
-			"term":         info.Term,
-			"install_time": info.InstallTime.Format(time.RFC3339Nano),
-			"encryptions":  info.Encryptions,
-		},
-	}
+	history, err := b.Core.barrier.KeyHistory()
+	if err != nil {
+		return nil, err
+	}
+	terms := make([]map[string]interface{}, 0, len(history))
+	for _, key := range history {
+		terms = append(terms, keyInfoData(key))
+	}
+
+	resp := &logical.Response{
+		Data: keyInfoData(info),
+	}
+	resp.Data["history"] = terms
 	return resp, nil
 }
 
+// keyInfoData returns the response data describing a barrier key
+func keyInfoData(info *KeyInfo) map[string]interface{} {
+	data := map[string]interface{}{
+		"term":         info.Term,
+		"install_time": info.InstallTime.Format(time.RFC3339Nano),
+		"encryptions":  info.Encryptions,
+	}
+	if info.RotationReason != "" {
+		data["rotation_reason"] = info.RotationReason
+	}
+	return data
+}
+
 // handleKeyRotationConfigRead returns the barrier key rotation config
 func (b *SystemBackend) handleKeyRotationConfigRead(_ context.Context, _ *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
 	// Get the key info
@@ -3250,7 +3758,7 @@ func (b *SystemBackend) handleKeyRotationConfigUpdate(ctx context.Context, req *
 
 // handleRotate is used to trigger a key rotation
 func (b *SystemBackend) handleRotate(ctx context.Context, _ *logical.Request, _ *framework.FieldData) (*logical.Response, error) {
-	if err := b.rotateBarrierKey(ctx); err != nil {
+	if err := b.rotateBarrierKey(ctx, manualRotateReason); err != nil {
 		b.Backend.Logger().Error("error handling key rotation", "error", err)
 		return handleError(err)
 	}
@@ -3500,6 +4008,28 @@ func (b *SystemBackend) handleMonitor(ctx context.Context, req *logical.Request,
 		return logical.ErrorResponse("unknown log format"), nil
 	}
 
+	filter := &monitor.Filter{
+		Subsystems:        data.Get("subsystems").([]string),
+		ExcludeSubsystems: data.Get("exclude_subsystems").([]string),
+	}
+	if rawRegex := data.Get("regex").(string); rawRegex != "" {
+		re, err := regexp.Compile(rawRegex)
+		if err != nil {
+			return logical.ErrorResponse("invalid regex: %s", err), nil
+		}
+		filter.Regex = re
+	}
+
+	isJson := b.Core.LogFormat() == "json" || lowerLogFormat == "json"
+
+	heartbeatInterval := time.Duration(data.Get("heartbeat_interval").(int)) * time.Second
+	if heartbeatInterval < 0 {
+		return logical.ErrorResponse("heartbeat_interval must not be negative"), nil
+	}
+	if heartbeatInterval > 0 && !isJson {
+		return logical.ErrorResponse("heartbeat_interval requires the json log format"), nil
+	}
+
 	flusher, ok := w.ResponseWriter.(http.Flusher)
 	if !ok {
 		// http.ResponseWriter is wrapped in wrapGenericHandler, so let's
@@ -3514,13 +4044,12 @@ func (b *SystemBackend) handleMonitor(ctx context.Context, req *logical.Request,
 		}
 	}
 
-	isJson := b.Core.LogFormat() == "json" || lf == "json"
 	logger := b.Core.Logger().(log.InterceptLogger)
 
-	mon, err := monitor.NewMonitor(512, logger, &log.LoggerOptions{
+	mon, err := monitor.NewFilteredMonitor(512, logger, &log.LoggerOptions{
 		Level:      logLevel,
 		JSONFormat: isJson,
-	})
+	}, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -3546,6 +4075,14 @@ func (b *SystemBackend) handleMonitor(ctx context.Context, req *logical.Request,
 	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
 
+	// A nil channel never fires, so heartbeats are only sent when requested
+	var heartbeatCh <-chan time.Time
+	if heartbeatInterval > 0 {
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+		heartbeatCh = heartbeat.C
+	}
+
 	// Stream logs until the connection is closed.
 	for {
 		select {
@@ -3553,14 +4090,26 @@ func (b *SystemBackend) handleMonitor(ctx context.Context, req *logical.Request,
 		// marked as sealed.
 		case <-ticker.C:
 			if b.Core.Sealed() {
+				sealedMsg := "core received sealed state change, ending monitor session"
+				if isJson {
+					_, err = w.Write(monitor.JSONMessage(log.Info, sealedMsg))
+				} else {
+					_, err = fmt.Fprint(w, sealedMsg)
+				}
 				// We still return the error, but this will be ignored upstream
 				// due to the fact that we've already sent a response by
 				// writing the header and flushing the writer above.
-				_, err = fmt.Fprint(w, "core received sealed state change, ending monitor session")
 				if err != nil {
 					return nil, fmt.Errorf("error checking seal state: %w", err)
 				}
 			}
+		case <-heartbeatCh:
+			_, err = w.Write(monitor.HeartbeatMessage())
+			if err != nil {
+				return nil, fmt.Errorf("error streaming monitor heartbeat: %w", err)
+			}
+
+			flusher.Flush()
 		case <-ctx.Done():
 			return nil, nil
 		case l := <-logCh:
@@ -3826,6 +4375,8 @@ func (b *SystemBackend) pathHashWrite(ctx context.Context, req *logical.Request,
 	if algorithm == "" {
 		algorithm = d.Get("algorithm").(string)
 	}
+	state := d.Get("state").(string)
+	final := d.Get("final").(bool)
 
 	input, err := base64.StdEncoding.DecodeString(inputB64)
 	if err != nil {
@@ -3835,8 +4386,9 @@ func (b *SystemBackend) pathHashWrite(ctx context.Context, req *logical.Request,
 	switch format {
 	case "hex":
 	case "base64":
+	case "base64url":
 	default:
-		return logical.ErrorResponse(fmt.Sprintf("unsupported encoding format %s; must be \"hex\" or \"base64\"", format)), nil
+		return logical.ErrorResponse(fmt.Sprintf("unsupported encoding format %s; must be \"hex\", \"base64\" or \"base64url\"", format)), nil
 	}
 
 	var hf hash.Hash
@@ -3857,10 +4409,45 @@ func (b *SystemBackend) pathHashWrite(ctx context.Context, req *logical.Request,
 		hf = sha3.New384()
 	case "sha3-512":
 		hf = sha3.New512()
+	case "blake2b-256":
+		hf, err = blake2b.New256(nil)
+	case "blake2b-384":
+		hf, err = blake2b.New384(nil)
+	case "blake2b-512":
+		hf, err = blake2b.New512(nil)
 	default:
 		return logical.ErrorResponse(fmt.Sprintf("unsupported algorithm %s", algorithm)), nil
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	// When hashing input in chunks, resume from the state returned for the
+	// previous chunk. The state carries the algorithm it was created with, so
+	// resuming it with another algorithm fails.
+	if state != "" {
+		stateBytes, err := base64.RawURLEncoding.DecodeString(state)
+		if err != nil {
+			return logical.ErrorResponse(fmt.Sprintf("unable to decode state: %s", err)), logical.ErrInvalidRequest
+		}
+		if err := hf.(encoding.BinaryUnmarshaler).UnmarshalBinary(stateBytes); err != nil {
+			return logical.ErrorResponse(fmt.Sprintf("invalid state for algorithm %s: %s", algorithm, err)), logical.ErrInvalidRequest
+		}
+	}
 	hf.Write(input)
+
+	if !final {
+		stateBytes, err := hf.(encoding.BinaryMarshaler).MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		return &logical.Response{
+			Data: map[string]interface{}{
+				"state": base64.RawURLEncoding.EncodeToString(stateBytes),
+			},
+		}, nil
+	}
+
 	retBytes := hf.Sum(nil)
 
 	var retStr string
@@ -3869,6 +4456,8 @@ func (b *SystemBackend) pathHashWrite(ctx context.Context, req *logical.Request,
 		retStr = hex.EncodeToString(retBytes)
 	case "base64":
 		retStr = base64.StdEncoding.EncodeToString(retBytes)
+	case "base64url":
+		retStr = base64.RawURLEncoding.EncodeToString(retBytes)
 	}
 
 	// Generate the response
@@ -4111,6 +4700,28 @@ func (b *SystemBackend) pathInternalCountersTokens(ctx context.Context, req *log
 	return resp, nil
 }
 
+func (b *SystemBackend) pathInternalCountersCache(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	cache, err := b.Core.tunableCache()
+	if err != nil {
+		return nil, err
+	}
+	stats := cache.Stats()
+
+	resp := &logical.Response{
+		Data: map[string]interface{}{
+			"counters": map[string]interface{}{
+				"size":      stats.Size,
+				"entries":   stats.Entries,
+				"hits":      stats.Hits,
+				"misses":    stats.Misses,
+				"evictions": stats.Evictions,
+			},
+		},
+	}
+
+	return resp, nil
+}
+
 func (b *SystemBackend) pathInternalCountersEntities(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
 	activeEntities, err := b.Core.countActiveEntities(ctx)
 	if err != nil {
@@ -4126,6 +4737,48 @@ func (b *SystemBackend) pathInternalCountersEntities(ctx context.Context, req *l
 	return resp, nil
 }
 
+func (b *SystemBackend) pathInternalCountersEngineUsage(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	end := time.Now().UTC().Truncate(24 * time.Hour)
+	if raw := d.Get("end_date").(string); raw != "" {
+		parsed, err := time.Parse(engineUsageDateFormat, raw)
+		if err != nil {
+			return logical.ErrorResponse("invalid end_date: %v", err), logical.ErrInvalidRequest
+		}
+		end = parsed
+	}
+	start := end.AddDate(0, 0, -29)
+	if raw := d.Get("start_date").(string); raw != "" {
+		parsed, err := time.Parse(engineUsageDateFormat, raw)
+		if err != nil {
+			return logical.ErrorResponse("invalid start_date: %v", err), logical.ErrInvalidRequest
+		}
+		start = parsed
+	}
+	if start.After(end) {
+		return logical.ErrorResponse("start_date must not be after end_date"), logical.ErrInvalidRequest
+	}
+	if end.Sub(start) > engineUsageRetention {
+		return logical.ErrorResponse("the report cannot span more than %d days", int(engineUsageRetention.Hours()/24)), logical.ErrInvalidRequest
+	}
+	top := d.Get("top").(int)
+	if top < 0 {
+		return logical.ErrorResponse("top must not be negative"), logical.ErrInvalidRequest
+	}
+
+	report, err := b.Core.engineUsageReport(ctx, start, end, top)
+	if err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"start_date": start.Format(engineUsageDateFormat),
+			"end_date":   end.Format(engineUsageDateFormat),
+			"counters":   report,
+		},
+	}, nil
+}
+
 func (b *SystemBackend) pathInternalInspectRouter(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
 	b.Core.introspectionEnabledLock.Lock()
 	defer b.Core.introspectionEnabledLock.Unlock()
@@ -4282,6 +4935,19 @@ func (b *SystemBackend) pathInternalOpenAPI(ctx context.Context, req *logical.Re
 	// each of those APIs.
 	genericMountPaths, _ := d.Get("generic_mount_paths").(bool)
 
+	// When filtering by capabilities, only the operations the token is able
+	// to perform are kept in the document.
+	var filterACL *ACL
+	if filter, _ := d.Get("filter_by_capabilities").(bool); filter {
+		if req.ClientToken == "" {
+			return logical.ErrorResponse("a token is required to filter by capabilities"), logical.ErrInvalidRequest
+		}
+		filterACL, _, _, _, err = b.Core.fetchACLTokenEntryAndEntity(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	procMountGroup := func(group, mountPrefix string) error {
 		for mount, entry := range resp.Data[group].(map[string]interface{}) {
 
@@ -4354,6 +5020,10 @@ func (b *SystemBackend) pathInternalOpenAPI(ctx context.Context, req *logical.Re
 			for path, obj := range backendDoc.Paths {
 				path := strings.TrimPrefix(path, "/")
 
+				if filterACL != nil && !filterOASPathItem(ctx, filterACL, mountPrefix+mount+path, obj) {
+					continue
+				}
+
 				// Add tags to all of the operations if necessary
 				if tag != "" {
 					for _, op := range []*framework.OASOperation{obj.Get, obj.Post, obj.Delete} {
@@ -4419,6 +5089,77 @@ func (b *SystemBackend) pathInternalOpenAPI(ctx context.Context, req *logical.Re
 	return resp, nil
 }
 
+// openAPIPathParameterRe matches the parameters of OpenAPI paths, e.g.
+// "data/{path}".
+var openAPIPathParameterRe = regexp.MustCompile(`{\w+}`)
+
+// filterOASPathItem removes the operations of the OpenAPI path item of the
+// given path which the ACL does not allow. It returns false if none is left.
+func filterOASPathItem(ctx context.Context, acl *ACL, path string, obj *framework.OASPathItem) bool {
+	if obj.Unauthenticated {
+		return true
+	}
+
+	capabilities := openAPIPathCapabilities(ctx, acl, path)
+	if capabilities&DenyCapabilityInt > 0 || (obj.Sudo && capabilities&SudoCapabilityInt == 0) {
+		return false
+	}
+
+	if capabilities&(ReadCapabilityInt|ListCapabilityInt) == 0 {
+		obj.Get = nil
+	}
+	if capabilities&(CreateCapabilityInt|UpdateCapabilityInt) == 0 {
+		obj.Post = nil
+	}
+	if capabilities&DeleteCapabilityInt == 0 {
+		obj.Delete = nil
+	}
+
+	return obj.Get != nil || obj.Post != nil || obj.Delete != nil
+}
+
+// openAPIPathCapabilities returns the capabilities the ACL grants on the
+// given OpenAPI path. Path parameters may take any value, so the capabilities
+// granted on any path under the part of the path preceding the first
+// parameter are included as well.
+func openAPIPathCapabilities(ctx context.Context, acl *ACL, path string) uint32 {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return 0
+	}
+
+	idx := strings.Index(path, "{")
+	if idx == -1 {
+		res := acl.AllowOperation(ctx, &logical.Request{
+			Path:      path,
+			Operation: logical.ListOperation,
+		}, true)
+		if res.IsRoot {
+			return ^uint32(0) &^ DenyCapabilityInt
+		}
+		return res.CapabilitiesBitmap
+	}
+
+	// Substitute the parameters to check the rules matching any value
+	concrete := openAPIPathParameterRe.ReplaceAllString(path, "_")
+	capabilities := openAPIPathCapabilities(ctx, acl, concrete)
+	if capabilities&DenyCapabilityInt > 0 {
+		capabilities = 0
+	}
+
+	walkFn := func(s string, v interface{}) bool {
+		if perms, ok := v.(*ACLPermissions); ok && perms.CapabilitiesBitmap&DenyCapabilityInt == 0 {
+			capabilities |= perms.CapabilitiesBitmap
+		}
+		return false
+	}
+	prefix := ns.Path + path[:idx]
+	acl.exactRules.WalkPrefix(prefix, walkFn)
+	acl.prefixRules.WalkPrefix(prefix, walkFn)
+
+	return capabilities
+}
+
 type SealStatusResponse struct {
 	Type         string   `json:"type"`
 	Initialized  bool     `json:"initialized"`
@@ -4587,13 +5328,13 @@ func (b *SystemBackend) handleLeaderStatus(ctx context.Context, req *logical.Req
 	return httpResp, nil
 }
 
-func (b *SystemBackend) rotateBarrierKey(ctx context.Context) error {
+func (b *SystemBackend) rotateBarrierKey(ctx context.Context, reason string) error {
 	// Rotate to the new term
-	newTerm, err := b.Core.barrier.Rotate(ctx, b.Core.secureRandomReader)
+	newTerm, err := b.Core.barrier.Rotate(ctx, b.Core.secureRandomReader, reason)
 	if err != nil {
 		return errwrap.Wrap(errors.New("failed to create new encryption key"), err)
 	}
-	b.Backend.Logger().Info("installed new encryption key")
+	b.Backend.Logger().Info("installed new encryption key", "term", newTerm, "reason", reason)
 
 	// In HA mode, we need to an upgrade path for the standby instances
 	if b.Core.ha != nil && b.Core.KeyRotateGracePeriod() > 0 {
@@ -4862,6 +5603,34 @@ func sanitizePath(path string) string {
 	return path
 }
 
+// setRequestLimits sets the request timeout, the maximum request size and
+// the maximum number of concurrent requests of the mount configuration from
+// the API configuration.
+func setRequestLimits(config *MountConfig, apiConfig *APIMountConfig) error {
+	if apiConfig.DefaultRequestTimeout != "" {
+		timeout, err := parseutil.ParseDurationSecond(apiConfig.DefaultRequestTimeout)
+		if err != nil {
+			return fmt.Errorf("unable to parse default_request_timeout of %s: %w", apiConfig.DefaultRequestTimeout, err)
+		}
+		if timeout < 0 {
+			return fmt.Errorf("default_request_timeout cannot be negative")
+		}
+		config.DefaultRequestTimeout = timeout
+	}
+
+	if apiConfig.MaxRequestSize < 0 {
+		return fmt.Errorf("max_request_size cannot be negative")
+	}
+	config.MaxRequestSize = apiConfig.MaxRequestSize
+
+	if apiConfig.MaxConcurrentRequests < 0 {
+		return fmt.Errorf("max_concurrent_requests cannot be negative")
+	}
+	config.MaxConcurrentRequests = apiConfig.MaxConcurrentRequests
+
+	return nil
+}
+
 func checkListingVisibility(visibility ListingVisibilityType) error {
 	switch visibility {
 	case ListingVisibilityDefault:
@@ -4894,6 +5663,135 @@ The path responds to the following HTTP methods.
         Sets the license for the server
 	`,
 	},
+	"config/reload": {
+		"The subsystem to reload. Only \"config\" is supported, which reloads the server configuration file as on SIGHUP.",
+		"",
+	},
+	"config/state/apply": {
+		"Reconcile mounts, auth methods, policies and resources with a declarative bundle.",
+		`
+The bundle lists the desired secrets engines, auth methods and ACL policies
+of the namespace, and data to write to other API paths, such as roles. Items
+that differ from the bundle are created or updated. With prune set, mounts,
+auth methods and policies that are not listed in their section of the bundle
+are removed. With dry_run set, the changes are only returned.
+		`,
+	},
+	"config/backup-encryption": {
+		"Configures the recipients backups are encrypted to.",
+		`
+Configures the age X25519 recipients raft snapshots read from
+sys/storage/raft/snapshot and bundles read from sys/config/export are
+encrypted to. The identities of the recipients are kept offline: encrypted
+backups are decrypted with the age tools before being restored or imported.
+		`,
+	},
+	"config/ttl-policies": {
+		"List the TTL policies.",
+		"",
+	},
+	"config/ttl-policies-name": {
+		"Manage a TTL policy.",
+		`
+TTL policies assign a default and a maximum TTL to the leases and login tokens
+issued on the paths matching their glob, optionally only to the members of
+some identity groups. They override the TTLs of the mounts serving these
+paths. When several policies apply, the shortest TTLs win. The maximum TTL of
+a policy also caps renewals, and never raises the maximum TTL of the mount.
+		`,
+	},
+	"federation/tokens": {
+		"List the federation tokens.",
+		"",
+	},
+	"federation/tokens-name": {
+		"Manage a federation token.",
+		`
+Federation tokens let member clusters report to this cluster, the management
+cluster, on the federation/report endpoint. They grant no other access, and can
+be restricted to the clusters whose names match some globs. The secret of a
+token is only returned when the token is created.
+		`,
+	},
+	"federation/report": {
+		"Report the state of a member cluster.",
+		`
+Member clusters configured with sys/federation/config periodically send their
+health, version, secrets engines and auth methods to this endpoint, with a
+federation token issued by this cluster.
+		`,
+	},
+	"federation/clusters": {
+		"List the clusters reporting to this cluster.",
+		"",
+	},
+	"federation/clusters-id": {
+		"Read or remove a cluster reporting to this cluster.",
+		`
+A cluster is stale when it missed three of its reports in a row. Removing a
+cluster only lasts until its next report; revoke its federation token to stop
+it from reporting.
+		`,
+	},
+	"federation/config": {
+		"Configure the management cluster this cluster reports to.",
+		`
+When configured, the active node reports the health, version, secrets engines
+and auth methods of the cluster to the management cluster at each interval,
+using a federation token issued by the management cluster.
+		`,
+	},
+	"config/export": {
+		"Export the logical configuration of the namespace as a signed bundle.",
+		`
+The bundle lists the secrets engines, auth methods and ACL policies of the
+namespace, and the configuration and roles of the built-in secrets engines and
+auth methods, without secret data. Credentials that are not returned when
+reading the configuration, such as passwords and keys, are not exported. The
+bundle is signed with a key generated for the cluster on first export.
+		`,
+	},
+	"config/import": {
+		"Apply a signed configuration bundle to the namespace.",
+		`
+Verifies the signature of a bundle returned by sys/config/export and applies
+it as sys/config/state/apply would, without pruning. Bundles of another
+cluster are verified with the public key returned along them. With dry_run
+set, the changes are only returned.
+		`,
+	},
+	"config/cache": {
+		"Configures or returns the current configuration of the physical cache.",
+		`
+This path responds to the following HTTP methods.
+
+    GET /
+        Returns the configuration of the physical cache.
+
+    POST /
+        Sets the size of the physical cache and the prefixes of the storage keys it does not cache.
+
+    DELETE /
+        Resets the configuration of the physical cache to the server configuration.
+		`,
+	},
+	"payload-encryption/key": {
+		"Return the public key request payloads can be encrypted to.",
+		`
+Clients encrypt the JSON body of a request to this key and send it with the
+application/vnd.openbao.encrypted-payload+json content type, so that load
+balancers, proxies and standby nodes forwarding the request never see it in
+plaintext. The key is generated on first use.
+		`,
+	},
+	"payload-encryption/rotate": {
+		"Rotate the key request payloads are encrypted to.",
+		`
+Generates a new key request payloads are encrypted to. The previous key keeps
+decrypting payloads until the next rotation, so that clients have time to
+fetch the new key.
+		`,
+	},
 	"config/cors": {
 		"Configures or returns the current configuration of CORS settings.",
 		`
@@ -4920,6 +5818,38 @@ This path responds to the following HTTP methods.
         Sets the current group_policy_application_mode to either 'within_namespace_hierarchy' or 'any'.
         `,
 	},
+	"deprecations": {
+		"List the deprecated features in use, with the mounts using them.",
+		`
+The report lists the deprecated builtin secrets engines and auth methods
+mounted, the deprecated mount options set, and the deprecated parameters,
+endpoints and key types used by the requests to the mounts since they were
+first recorded. Features used by requests are recorded by the builtin plugins
+only, and stored periodically by the active node.
+		`,
+	},
+	"config/ui/banners": {
+		"List the login banners.",
+		"",
+	},
+	"config/ui/banners-name": {
+		"Manage a login banner.",
+		`
+Login banners are shown to users before they log in, such as terms of use.
+Banners requiring acknowledgment are enforced on the logins to the auth methods
+of their types, interactive ones by default: the user must acknowledge the
+banner with the X-OpenBao-Acknowledge-Banners header on the first login of each
+acknowledgment period, and again whenever its title or message changes.
+		`,
+	},
+	"config/ui/banner-acknowledgments": {
+		"Read the acknowledgments of the login banners by an entity.",
+		"",
+	},
+	"internal-ui-banners": {
+		"Return the login banners to show before logging in.",
+		"",
+	},
 	"config/ui/headers": {
 		"Configures response headers that should be returned from the UI.",
 		`
@@ -4956,6 +5886,16 @@ This path responds to the following HTTP methods.
 		Returns health information about OpenBao.
 		`,
 	},
+	"generate-root-history": {
+		"List the recorded root generation attempts.",
+		`
+Lists the root generation attempts recorded by this cluster, keyed by nonce and
+oldest first. Each attempt includes its status, the remote address and time of
+the request that started it and of every provided key share, and the accessor,
+TTL and number of uses of the generated root token.
+		`,
+	},
+
 	"generate-root": {
 		"Reads, generates, or deletes a root token regeneration process.",
 		`
@@ -5134,6 +6074,13 @@ the auth path.`,
 the mount.`,
 	},
 
+	"mount_warm_up": {
+		"Load the backend of a secrets engine whose loading was deferred at unseal.",
+		`Create and initialize the backend of a mount enabled with 'lazy_load'
+ahead of the first request routed to it. This does nothing when the backend is
+already loaded.`,
+	},
+
 	"unlock_user": {
 		"Unlock the locked user with given mount_accessor and alias_identifier.",
 		`
@@ -5335,6 +6282,43 @@ or delete a policy.
 		"",
 	},
 
+	"policy-versions": {
+		"Read the recorded versions of an ACL policy.",
+		`
+Every change to an ACL policy is recorded as a new version, along with the
+time of the change and the entity, display name, token accessor and remote
+address of its author. Deletions are recorded as versions without a policy.
+Up to 50 versions are kept per policy.
+		`,
+	},
+
+	"policy-rollback": {
+		"Restore a recorded version of an ACL policy.",
+		`
+Sets the policy to the contents of the given version. The restored policy is
+recorded as a new version.
+		`,
+	},
+
+	"mount-versions": {
+		"Read the recorded configuration versions of a mount.",
+		`
+Every change made by tuning a mount is recorded as a new version of its
+tunable configuration, along with the time of the change and its author. The
+history is removed when the mount is disabled. Up to 50 versions are kept per
+mount.
+		`,
+	},
+
+	"mount-rollback": {
+		"Restore a recorded configuration version of a mount.",
+		`
+Tunes the mount to the configuration of the given version. The restored
+configuration is recorded as a new version. KV version upgrades are not
+rolled back.
+		`,
+	},
+
 	"policy-enforcement-level": {
 		`The enforcement level to apply to the policy.`,
 		"",
@@ -5403,7 +6387,8 @@ Enable a new audit backend or disable an existing backend.
 	"key-status": {
 		"Provides information about the backend encryption key.",
 		`
-		Provides the current backend encryption key term and installation time.
+		Provides the current backend encryption key term, installation time and
+		estimated encryptions, along with the history of the rotations of the key.
 		`,
 	},
 
@@ -5435,6 +6420,98 @@ Enable a new audit backend or disable an existing backend.
 		`,
 	},
 
+	"rotate-roots": {
+		"List the root credentials registered with the rotation scheduler.",
+		"",
+	},
+	"rotate-roots-name": {
+		"Register a root credential with the rotation scheduler.",
+		`
+		Registers the root credential of a secrets engine or auth method, such
+		as a database connection or an LDAP bind account, with the rotation
+		scheduler. The credential is rotated by writing to the rotate endpoint
+		of the engine, at the given interval and within the optional daily
+		rotation window. The outcome of each rotation is recorded, and sent to
+		the optional notification URLs.
+		`,
+	},
+	"rotate-roots-rotate": {
+		"Rotate a registered root credential now.",
+		"",
+	},
+
+	"import": {
+		"Import secrets from an external store into a KV version 2 mount.",
+		`
+		Starts importing the secrets of AWS Secrets Manager, Azure Key Vault,
+		GCP Secret Manager or the KV mount of another cluster into a KV
+		version 2 mount, in the background. Mapping rules select the secrets
+		to import and the paths they are written at. In a dry run, the
+		secrets are only listed and mapped, and the resulting plan is
+		reported in the status of the import. Listing returns the IDs of the
+		imports.
+		`,
+	},
+	"import-id": {
+		"Read the progress of an import of secrets, or cancel it.",
+		`
+		Reads the status and progress of an import of secrets. Deleting a
+		running import cancels it, and deleting a finished import removes its
+		status. Imports are tracked in memory and are not retained across
+		restarts or leadership changes.
+		`,
+	},
+
+	"mount-undelete": {
+		"Enable again a disabled mount, with its data.",
+		`
+		When a retention of disabled mounts is configured, the data of disabled
+		secrets engines and auth methods is retained until the retention has
+		passed. This endpoint enables again the most recently disabled mount at
+		the given path, with the same type, configuration, accessor and data.
+		Leases of the mount were revoked when it was disabled and are not
+		restored.
+		`,
+	},
+	"deleted-mounts": {
+		"List the disabled mounts whose data is retained.",
+		"",
+	},
+	"deleted-mounts-uuid": {
+		"Read or purge a disabled mount whose data is retained.",
+		`
+		Reads a disabled secrets engine or auth method whose data is retained,
+		or deletes its data immediately rather than once the retention has
+		passed.
+		`,
+	},
+
+	"restricted-crypto-report": {
+		"Report the keys which are not approved in restricted crypto mode.",
+		`
+		Lists the keys of the transit, PKI and SSH secrets engines of the
+		namespace and its children whose algorithms are not approved in
+		restricted crypto mode, such as ChaCha20 and Ed25519. Such keys can no
+		longer be used once the server runs in this mode, and should be
+		replaced beforehand.
+		`,
+	},
+
+	"network-policy": {
+		"List the network policies.",
+		"",
+	},
+	"network-policy-name": {
+		"Manage a network policy.",
+		`
+		Network policies restrict the source addresses, days of the week and
+		times of day from which the tokens they apply to can be used. A network
+		policy applies to the tokens carrying any of its policies, and to the
+		tokens issued by any of its auth methods. It is enforced on login and
+		on each request, independently of the bound CIDRs of the token.
+		`,
+	},
+
 	"rekey_backup": {
 		"Allows fetching or deleting the backup of the rotated unseal keys.",
 		"",
@@ -5442,8 +6519,9 @@ Enable a new audit backend or disable an existing backend.
 
 	"capabilities": {
 		"Fetches the capabilities of the given token on the given path.",
-		`Returns the capabilities of the given token on the path.
-		The path will be searched for a path match in all the policies associated with the token.`,
+		`Returns the capabilities of the given token on the paths.
+		The paths will be searched for a path match in all the policies associated with the token.
+		With explain, the policies granting or denying capabilities on each path are also returned.`,
 	},
 
 	"capabilities_self": {
@@ -5455,7 +6533,8 @@ Enable a new audit backend or disable an existing backend.
 	"capabilities_accessor": {
 		"Fetches the capabilities of the token associated with the given token, on the given path.",
 		`When there is no access to the token, token accessor can be used to fetch the token's capabilities
-		on a given path.`,
+		on a given path.
+		With explain, the policies granting or denying capabilities on each path are also returned.`,
 	},
 
 	"tidy_leases": {
@@ -5604,7 +6683,11 @@ This path responds to the following HTTP methods.
 	},
 	"hash": {
 		"Generate a hash sum for input data",
-		"Generates a hash sum of the given algorithm against the given input data.",
+		`Generates a hash sum of the given algorithm against the given input data.
+
+Input larger than the request size limit can be hashed in chunks: each chunk
+but the last is sent with "final" set to false, and the "state" returned for
+it is sent along with the next chunk.`,
 	},
 	"random": {
 		"Generate random bytes",
@@ -5614,6 +6697,30 @@ This path responds to the following HTTP methods.
 		"Determines the visibility of the mount in the UI-specific listing endpoint. Accepted value are 'unauth' and 'hidden', with the empty default ('') behaving like 'hidden'.",
 		"",
 	},
+	"protected": {
+		"If true, deleting data of the mount and disabling it require a confirmation, given with the X-OpenBao-Confirm-Delete header.",
+		"",
+	},
+	"default_request_timeout": {
+		"The time after which requests to the mount are interrupted. Zero means no timeout.",
+		"",
+	},
+	"max_request_size": {
+		"The maximum size in bytes of the body of requests to the mount. Zero means no limit beyond the one of the listener.",
+		"",
+	},
+	"max_concurrent_requests": {
+		"The maximum number of requests to the mount handled at once, further requests being rejected. Zero means no limit.",
+		"",
+	},
+	"token_bind_client_cert": {
+		"If true, the tokens issued by logins to the auth method can only be used on connections presenting the client certificate of the login request.",
+		"",
+	},
+	"lazy_load": {
+		"If true, the backend of the secrets engine is not created at unseal but by the first request routed to it, or by a warm-up of the mount.",
+		"",
+	},
 	"passthrough_request_headers": {
 		"A list of headers to whitelist and pass from the request to the plugin.",
 		"",
@@ -5648,6 +6755,33 @@ This path responds to the following HTTP methods.
 		"Information about a token's resultant ACL. Internal API; its location, inputs, and outputs may change.",
 		"",
 	},
+	"internal-ui-dashboard-mounts": {
+		"Summary of the mounts visible to the token, with their lease and alias counts. Internal API; its location, inputs, and outputs may change.",
+		"",
+	},
+	"internal-ui-dashboard-activity": {
+		"Requests to the mounts visible to the token in the last hour, as audited by this node. Internal API; its location, inputs, and outputs may change.",
+		"",
+	},
+	"internal-ui-dashboard-pki-issuers": {
+		"PKI issuers of the mounts visible to the token which have expired or expire soon. Internal API; its location, inputs, and outputs may change.",
+		"",
+	},
+	"well-known": {
+		"List the paths under /.well-known/ claimed by the mounts.",
+		`
+Mounts may claim paths under /.well-known/ on the listeners, for protocols
+requiring well-known locations. The requests for these paths are redirected to
+the mount which claimed them.
+		`,
+	},
+	"well-known-label": {
+		"Read the registration of a path under /.well-known/.",
+		`
+Returns the mount which claimed the path, along with its current path and the
+path of the mount the requests are redirected to.
+		`,
+	},
 	"metrics": {
 		"Export the metrics aggregated for telemetry purpose.",
 		"",
@@ -5668,10 +6802,24 @@ This path responds to the following HTTP methods.
 		"Count of active tokens in this OpenBao cluster.",
 		"Count of active tokens in this OpenBao cluster.",
 	},
+	"internal-counters-cache": {
+		"Statistics of the physical cache of this OpenBao node.",
+		"Statistics of the physical cache of this OpenBao node: its size, the number of entries it holds, and its hits, misses and evictions since the node started.",
+	},
 	"internal-counters-entities": {
 		"Count of active entities in this OpenBao cluster.",
 		"Count of active entities in this OpenBao cluster.",
 	},
+	"internal-counters-engine-usage": {
+		"Daily usage of the PKI and transit mounts in this OpenBao cluster.",
+		`
+Returns the certificates issued with each role of the PKI mounts, the
+operations with each key of the transit mounts, and the entities using them
+the most, between start_date and end_date. Usage is aggregated per UTC day and
+kept for 400 days. Entities are identified by their ID, or by the display name
+of their token for tokens without an entity.
+		`,
+	},
 	"internal-inspect-router": {
 		"Information on the entries in each of the trees in the router. Inspectable trees are uuid, accessor, storage, and root.",
 		`
@@ -5690,6 +6838,25 @@ This path responds to the following HTTP methods.
 		"Count of leases associated with this OpenBao cluster",
 		"Count of leases associated with this OpenBao cluster",
 	},
+	"revocation-queue-leases": {
+		"Progress of the revocation of expired leases",
+		`Returns the number of lease revocations waiting for a worker, by
+priority, the number of revocations that succeeded and failed, the
+configured revocation rate limit and the last persisted checkpoint.`,
+	},
+	"irrevocable-leases": {
+		"Irrevocable leases grouped by mount",
+		`Requires sudo capability. Returns the number of irrevocable leases of
+each mount, along with the number of leases per revocation error.`,
+	},
+	"revoke-force-irrevocable-leases": {
+		"Force revoke irrevocable leases",
+		`Requires sudo capability. Removes the irrevocable leases matching the
+given mount accessor and error filters, even if the revocation against the
+backend fails again. By default, the matching leases are only returned. To
+revoke them, dry_run must be set to false and confirm_count must be set to
+the number of matching leases.`,
+	},
 	"list-leases": {
 		"List leases associated with this OpenBao cluster",
 		"Requires sudo capability. List leases associated with this OpenBao cluster",
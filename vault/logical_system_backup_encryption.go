@@ -0,0 +1,189 @@
+package vault
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/openbao/openbao/helper/agekeys"
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+// backupEncryptionConfigPath is the path in the system view of the
+// recipients backups are encrypted to.
+const backupEncryptionConfigPath = "config/backup-encryption"
+
+// backupEncryptionConfig holds the age X25519 recipients raft snapshots and
+// configuration exports are encrypted to. Their identities are kept offline,
+// so reading the backups requires more than access to the backup storage.
+type backupEncryptionConfig struct {
+	Recipients []string `json:"recipients"`
+}
+
+func (b *SystemBackend) backupEncryptionPaths() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "config/backup-encryption$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "backup-encryption",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"recipients": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "age X25519 recipients raft snapshots and configuration exports are encrypted to.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleBackupEncryptionRead,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb:   "read",
+						OperationSuffix: "configuration",
+					},
+					Summary: "Read the recipients backups are encrypted to.",
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"recipients": {
+									Type:     framework.TypeStringSlice,
+									Required: true,
+								},
+							},
+						}},
+					},
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleBackupEncryptionWrite,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "configure",
+					},
+					Summary: "Configure the recipients backups are encrypted to.",
+					Responses: map[int][]framework.Response{
+						http.StatusNoContent: {{
+							Description: "OK",
+						}},
+					},
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.handleBackupEncryptionDelete,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb:   "delete",
+						OperationSuffix: "configuration",
+					},
+					Summary: "Stop encrypting backups.",
+					Responses: map[int][]framework.Response{
+						http.StatusNoContent: {{
+							Description: "OK",
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["config/backup-encryption"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["config/backup-encryption"][1]),
+		},
+	}
+}
+
+func (b *SystemBackend) handleBackupEncryptionRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	config, err := b.Core.loadBackupEncryptionConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	recipients := []string{}
+	if config != nil {
+		recipients = config.Recipients
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"recipients": recipients,
+		},
+	}, nil
+}
+
+func (b *SystemBackend) handleBackupEncryptionWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	recipients := d.Get("recipients").([]string)
+	if len(recipients) == 0 {
+		return logical.ErrorResponse("at least one recipient is required"), logical.ErrInvalidRequest
+	}
+	for _, recipient := range recipients {
+		if _, err := agekeys.ParseX25519Recipient(recipient); err != nil {
+			return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+		}
+	}
+
+	entry, err := logical.StorageEntryJSON(backupEncryptionConfigPath, &backupEncryptionConfig{Recipients: recipients})
+	if err != nil {
+		return nil, err
+	}
+	if err := b.Core.systemBarrierView.Put(ctx, entry); err != nil {
+		return nil, fmt.Errorf("failed to save backup encryption config: %w", err)
+	}
+	return nil, nil
+}
+
+func (b *SystemBackend) handleBackupEncryptionDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if err := b.Core.systemBarrierView.Delete(ctx, backupEncryptionConfigPath); err != nil {
+		return nil, fmt.Errorf("failed to delete backup encryption config: %w", err)
+	}
+	return nil, nil
+}
+
+func (c *Core) loadBackupEncryptionConfig(ctx context.Context) (*backupEncryptionConfig, error) {
+	entry, err := c.systemBarrierView.Get(ctx, backupEncryptionConfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup encryption config: %w", err)
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var config backupEncryptionConfig
+	if err := entry.DecodeJSON(&config); err != nil {
+		return nil, fmt.Errorf("failed to decode backup encryption config: %w", err)
+	}
+	return &config, nil
+}
+
+// backupEncryptionRecipients returns the recipients backups are encrypted
+// to, or none if backups are not encrypted.
+func (c *Core) backupEncryptionRecipients(ctx context.Context) ([]*agekeys.X25519Recipient, error) {
+	config, err := c.loadBackupEncryptionConfig(ctx)
+	if err != nil || config == nil {
+		return nil, err
+	}
+
+	recipients := make([]*agekeys.X25519Recipient, 0, len(config.Recipients))
+	for _, raw := range config.Recipients {
+		recipient, err := agekeys.ParseX25519Recipient(raw)
+		if err != nil {
+			return nil, err
+		}
+		recipients = append(recipients, recipient)
+	}
+	return recipients, nil
+}
+
+// encryptedBackupReader returns a reader of body, or an error if body is an
+// encrypted backup, which must be decrypted offline before being restored.
+func encryptedBackupReader(body io.ReadCloser) (io.ReadCloser, error) {
+	br := bufio.NewReader(body)
+	prefix, _ := br.Peek(64)
+	if agekeys.IsEncrypted(prefix) {
+		return nil, errors.New("the backup is encrypted; decrypt it with a backup identity before restoring it")
+	}
+	return struct {
+		io.Reader
+		io.Closer
+	}{br, body}, nil
+}
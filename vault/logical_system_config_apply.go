@@ -0,0 +1,558 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-secure-stdlib/parseutil"
+	"github.com/hashicorp/go-secure-stdlib/strutil"
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const (
+	configApplyActionCreate = "create"
+	configApplyActionUpdate = "update"
+	configApplyActionDelete = "delete"
+
+	configApplyKindMount    = "mount"
+	configApplyKindAuth     = "auth"
+	configApplyKindPolicy   = "policy"
+	configApplyKindResource = "resource"
+)
+
+// configApplyMountKeys are the keys supported in the mounts and auth
+// sections of a bundle.
+var configApplyMountKeys = []string{
+	"type", "description", "options", "config", "local", "seal_wrap",
+	"external_entropy_access", "plugin_version",
+}
+
+// configApplyChange is a single change needed to reconcile the current
+// state with a bundle.
+type configApplyChange struct {
+	Kind   string
+	Path   string
+	Action string
+	Fields []string
+
+	// request performs the change.
+	request *logical.Request
+}
+
+func (c *configApplyChange) toMap() map[string]interface{} {
+	m := map[string]interface{}{
+		"kind":   c.Kind,
+		"path":   c.Path,
+		"action": c.Action,
+	}
+	if len(c.Fields) > 0 {
+		m["fields"] = c.Fields
+	}
+	return m
+}
+
+func (b *SystemBackend) configApplyPaths() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "config/state/apply$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "configuration-state",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"mounts": {
+					Type:        framework.TypeMap,
+					Description: "Secrets engines keyed by path. Each value takes the parameters of sys/mounts.",
+				},
+				"auth": {
+					Type:        framework.TypeMap,
+					Description: "Auth methods keyed by path. Each value takes the parameters of sys/auth.",
+				},
+				"policies": {
+					Type:        framework.TypeMap,
+					Description: "ACL policies keyed by name.",
+				},
+				"resources": {
+					Type:        framework.TypeMap,
+					Description: "Data to write keyed by API path, such as auth/approle/role/app. A null value deletes the path.",
+				},
+				"prune": {
+					Type:        framework.TypeBool,
+					Description: "If set, mounts, auth methods and policies that are not listed in their section of the bundle are removed. Sections missing from the bundle are not pruned.",
+				},
+				"dry_run": {
+					Type:        framework.TypeBool,
+					Description: "If set, the changes are returned without being applied.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleConfigStateApply,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "apply",
+					},
+					Summary: "Reconcile mounts, auth methods, policies and resources with a declarative bundle.",
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"changes": {
+									Type:     framework.TypeSlice,
+									Required: true,
+								},
+								"dry_run": {
+									Type:     framework.TypeBool,
+									Required: true,
+								},
+							},
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["config/state/apply"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["config/state/apply"][1]),
+		},
+	}
+}
+
+// handleConfigStateApply computes the changes needed to reconcile the
+// current namespace with the given bundle and applies them unless dry_run
+// is set. Changes are applied through the regular sys and backend
+// handlers, in the order policies, auth methods, mounts, resources and
+// then removals.
+func (b *SystemBackend) handleConfigStateApply(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	dryRun := data.Get("dry_run").(bool)
+	prune := data.Get("prune").(bool)
+
+	var changes []*configApplyChange
+
+	policyChanges, err := b.configApplyPolicies(ctx, req, data.Get("policies").(map[string]interface{}), prune)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	authChanges, err := b.configApplyMounts(ctx, req, configApplyKindAuth, data.Get("auth").(map[string]interface{}), prune)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	mountChanges, err := b.configApplyMounts(ctx, req, configApplyKindMount, data.Get("mounts").(map[string]interface{}), prune)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	resourceChanges, err := b.configApplyResources(ctx, req, data.Get("resources").(map[string]interface{}))
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	// Create and update everything first, so that resources can refer to
+	// new mounts and policies, then remove in reverse order.
+	var removals []*configApplyChange
+	for _, group := range [][]*configApplyChange{policyChanges, authChanges, mountChanges, resourceChanges} {
+		for _, change := range group {
+			if change.Action == configApplyActionDelete {
+				removals = append(removals, change)
+			} else {
+				changes = append(changes, change)
+			}
+		}
+	}
+	for i := len(removals) - 1; i >= 0; i-- {
+		changes = append(changes, removals[i])
+	}
+
+	applied := make([]interface{}, 0, len(changes))
+	for _, change := range changes {
+		if !dryRun {
+			if err := b.configApplyChange(ctx, change); err != nil {
+				resp := logical.ErrorResponse("error applying %s of %s %q: %s", change.Action, change.Kind, change.Path, err)
+				resp.Data["changes"] = applied
+				return resp, logical.ErrInvalidRequest
+			}
+		}
+		applied = append(applied, change.toMap())
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"changes": applied,
+			"dry_run": dryRun,
+		},
+	}, nil
+}
+
+func (b *SystemBackend) configApplyChange(ctx context.Context, change *configApplyChange) error {
+	var resp *logical.Response
+	var err error
+	if change.Kind == configApplyKindResource {
+		resp, err = b.Core.router.Route(ctx, change.request)
+	} else {
+		resp, err = b.HandleRequest(ctx, change.request)
+	}
+	if err != nil {
+		return err
+	}
+	if resp != nil && resp.IsError() {
+		return resp.Error()
+	}
+	return nil
+}
+
+// configApplyRequest creates a request for a change, carrying over the
+// identity of the original request.
+func configApplyRequest(req *logical.Request, op logical.Operation, path string, data map[string]interface{}) *logical.Request {
+	return &logical.Request{
+		Operation:   op,
+		Path:        path,
+		Data:        data,
+		Storage:     req.Storage,
+		Connection:  req.Connection,
+		ClientToken: req.ClientToken,
+		EntityID:    req.EntityID,
+	}
+}
+
+func (b *SystemBackend) configApplyPolicies(ctx context.Context, req *logical.Request, desired map[string]interface{}, prune bool) ([]*configApplyChange, error) {
+	// Sections missing from the bundle are left alone, even when pruning.
+	if desired == nil {
+		return nil, nil
+	}
+
+	existing, err := b.Core.policyStore.ListPolicies(ctx, PolicyTypeACL)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []*configApplyChange
+	wanted := make(map[string]struct{}, len(desired))
+	for _, name := range sortedKeys(desired) {
+		raw, ok := desired[name].(string)
+		if !ok {
+			return nil, fmt.Errorf("policy %q must be a string", name)
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		wanted[name] = struct{}{}
+
+		policy, err := b.Core.policyStore.GetPolicy(ctx, name, PolicyTypeACL)
+		if err != nil {
+			return nil, err
+		}
+
+		action := configApplyActionCreate
+		if policy != nil {
+			if strings.TrimSpace(policy.Raw) == strings.TrimSpace(raw) {
+				continue
+			}
+			action = configApplyActionUpdate
+		}
+		changes = append(changes, &configApplyChange{
+			Kind:    configApplyKindPolicy,
+			Path:    name,
+			Action:  action,
+			request: configApplyRequest(req, logical.UpdateOperation, "policies/acl/"+name, map[string]interface{}{"policy": raw}),
+		})
+	}
+
+	if prune {
+		for _, name := range existing {
+			if _, ok := wanted[name]; ok || name == "root" || name == "default" {
+				continue
+			}
+			changes = append(changes, &configApplyChange{
+				Kind:    configApplyKindPolicy,
+				Path:    name,
+				Action:  configApplyActionDelete,
+				request: configApplyRequest(req, logical.DeleteOperation, "policies/acl/"+name, nil),
+			})
+		}
+	}
+
+	return changes, nil
+}
+
+func (b *SystemBackend) configApplyMounts(ctx context.Context, req *logical.Request, kind string, desired map[string]interface{}, prune bool) ([]*configApplyChange, error) {
+	// Sections missing from the bundle are left alone, even when pruning.
+	if desired == nil {
+		return nil, nil
+	}
+
+	table, apiPrefix := b.handleMountTable, "mounts/"
+	if kind == configApplyKindAuth {
+		table, apiPrefix = b.handleAuthTable, "auth/"
+	}
+	resp, err := table(ctx, req, nil)
+	if err != nil {
+		return nil, err
+	}
+	existing := resp.Data
+
+	var changes []*configApplyChange
+	wanted := make(map[string]struct{}, len(desired))
+	for _, path := range sortedKeys(desired) {
+		spec, ok := desired[path].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s %q must be an object", kind, path)
+		}
+		for key := range spec {
+			if !strutil.StrListContains(configApplyMountKeys, key) {
+				return nil, fmt.Errorf("%s %q: unsupported parameter %q", kind, path, key)
+			}
+		}
+
+		path = sanitizePath(path)
+		wanted[path] = struct{}{}
+
+		current, ok := existing[path].(map[string]interface{})
+		if !ok {
+			if _, ok := spec["type"].(string); !ok {
+				return nil, fmt.Errorf("%s %q: missing type", kind, path)
+			}
+			changes = append(changes, &configApplyChange{
+				Kind:    kind,
+				Path:    path,
+				Action:  configApplyActionCreate,
+				request: configApplyRequest(req, logical.UpdateOperation, apiPrefix+path, spec),
+			})
+			continue
+		}
+
+		fields, tune, err := configApplyMountDiff(kind, path, current, spec)
+		if err != nil {
+			return nil, err
+		}
+		if len(fields) == 0 {
+			continue
+		}
+		changes = append(changes, &configApplyChange{
+			Kind:    kind,
+			Path:    path,
+			Action:  configApplyActionUpdate,
+			Fields:  fields,
+			request: configApplyRequest(req, logical.UpdateOperation, apiPrefix+path+"tune", tune),
+		})
+	}
+
+	if prune {
+		for _, path := range sortedKeys(existing) {
+			if _, ok := wanted[path]; ok {
+				continue
+			}
+			info := existing[path].(map[string]interface{})
+			if strutil.StrListContains(singletonMounts, info["type"].(string)) {
+				continue
+			}
+			changes = append(changes, &configApplyChange{
+				Kind:    kind,
+				Path:    path,
+				Action:  configApplyActionDelete,
+				request: configApplyRequest(req, logical.DeleteOperation, apiPrefix+path, nil),
+			})
+		}
+	}
+
+	return changes, nil
+}
+
+// configApplyMountDiff returns the names of the fields of an existing mount
+// that differ from spec, and the tune request data to update them.
+func configApplyMountDiff(kind, path string, current, spec map[string]interface{}) ([]string, map[string]interface{}, error) {
+	if typ, ok := spec["type"].(string); ok && typ != current["type"] {
+		return nil, nil, fmt.Errorf("%s %q: changing the type from %q to %q requires removing it first", kind, path, current["type"], typ)
+	}
+	for _, key := range []string{"local", "seal_wrap", "external_entropy_access"} {
+		if raw, ok := spec[key]; ok {
+			want, err := parseutil.ParseBool(raw)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s %q: invalid %s: %w", kind, path, key, err)
+			}
+			if want != current[key] {
+				return nil, nil, fmt.Errorf("%s %q: changing %s requires removing it first", kind, path, key)
+			}
+		}
+	}
+
+	var fields []string
+	tune := make(map[string]interface{})
+
+	if raw, ok := spec["description"]; ok && fmt.Sprint(raw) != current["description"] {
+		fields = append(fields, "description")
+		tune["description"] = raw
+	}
+
+	if raw, ok := spec["plugin_version"]; ok && fmt.Sprint(raw) != current["plugin_version"] {
+		fields = append(fields, "plugin_version")
+		tune["plugin_version"] = raw
+	}
+
+	if raw, ok := spec["options"]; ok {
+		want, err := configApplyStringMap(raw)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s %q: invalid options: %w", kind, path, err)
+		}
+		have, _ := current["options"].(map[string]string)
+		for k, v := range want {
+			if have[k] != v {
+				fields = append(fields, "options")
+				tune["options"] = want
+				break
+			}
+		}
+	}
+
+	if raw, ok := spec["config"]; ok {
+		want, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, nil, fmt.Errorf("%s %q: config must be an object", kind, path)
+		}
+		have, _ := current["config"].(map[string]interface{})
+		for _, key := range sortedKeys(want) {
+			equal, err := configApplyConfigEqual(key, want[key], have[key])
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s %q: invalid config %s: %w", kind, path, key, err)
+			}
+			if !equal {
+				fields = append(fields, "config."+key)
+				tune[key] = want[key]
+			}
+		}
+	}
+
+	return fields, tune, nil
+}
+
+// configApplyConfigEqual compares a mount config value from a bundle with
+// the value reported by the mount table.
+func configApplyConfigEqual(key string, want, have interface{}) (bool, error) {
+	switch key {
+	case "default_lease_ttl", "max_lease_ttl":
+		ttl, err := parseutil.ParseDurationSecond(want)
+		if err != nil {
+			return false, err
+		}
+		return have == int64(ttl.Seconds()), nil
+	case "audit_non_hmac_request_keys", "audit_non_hmac_response_keys",
+		"passthrough_request_headers", "allowed_response_headers", "allowed_managed_keys":
+		values, err := parseutil.ParseCommaStringSlice(want)
+		if err != nil {
+			return false, err
+		}
+		current, _ := have.([]string)
+		return strutil.EquivalentSlices(values, current), nil
+	case "force_no_cache":
+		value, err := parseutil.ParseBool(want)
+		if err != nil {
+			return false, err
+		}
+		return have == value, nil
+	default:
+		return fmt.Sprint(want) == fmt.Sprint(have), nil
+	}
+}
+
+func configApplyStringMap(raw interface{}) (map[string]string, error) {
+	switch m := raw.(type) {
+	case map[string]string:
+		return m, nil
+	case map[string]interface{}:
+		result := make(map[string]string, len(m))
+		for k, v := range m {
+			result[k] = fmt.Sprint(v)
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("expected an object, got %T", raw)
+	}
+}
+
+func (b *SystemBackend) configApplyResources(ctx context.Context, req *logical.Request, desired map[string]interface{}) ([]*configApplyChange, error) {
+	var changes []*configApplyChange
+	for _, path := range sortedKeys(desired) {
+		apiPath := strings.Trim(path, "/")
+		if apiPath == "" || strings.HasPrefix(apiPath, "sys/") || strings.Contains(apiPath, "..") {
+			return nil, fmt.Errorf("invalid resource path %q", path)
+		}
+
+		var want map[string]interface{}
+		if desired[path] != nil {
+			var ok bool
+			want, ok = desired[path].(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("resource %q must be an object or null", path)
+			}
+		}
+
+		// The mount of a resource may not exist yet during a dry run, in
+		// which case the resource is created.
+		readReq := configApplyRequest(req, logical.ReadOperation, apiPath, nil)
+		current, err := b.Core.router.Route(ctx, readReq)
+		exists := err == nil && current != nil && !current.IsError() && current.Data != nil
+
+		change := &configApplyChange{
+			Kind: configApplyKindResource,
+			Path: apiPath,
+		}
+		switch {
+		case want == nil && !exists:
+			continue
+		case want == nil:
+			change.Action = configApplyActionDelete
+			change.request = configApplyRequest(req, logical.DeleteOperation, apiPath, nil)
+		case !exists:
+			change.Action = configApplyActionCreate
+			change.request = configApplyRequest(req, logical.UpdateOperation, apiPath, want)
+		default:
+			for _, key := range sortedKeys(want) {
+				if !configApplyValueEqual(want[key], current.Data[key]) {
+					change.Fields = append(change.Fields, key)
+				}
+			}
+			if len(change.Fields) == 0 {
+				continue
+			}
+			change.Action = configApplyActionUpdate
+			change.request = configApplyRequest(req, logical.UpdateOperation, apiPath, want)
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// configApplyValueEqual loosely compares a value from a bundle with the
+// value read from a backend, which may use a different type for the same
+// value, such as a duration in seconds instead of a duration string.
+func configApplyValueEqual(want, have interface{}) bool {
+	if fmt.Sprint(want) == fmt.Sprint(have) {
+		return true
+	}
+
+	if wantDur, err := parseutil.ParseDurationSecond(want); err == nil {
+		if haveDur, err := parseutil.ParseDurationSecond(have); err == nil {
+			return wantDur == haveDur
+		}
+	}
+
+	if wantSlice, err := parseutil.ParseCommaStringSlice(want); err == nil {
+		if haveSlice, err := parseutil.ParseCommaStringSlice(have); err == nil {
+			return strutil.EquivalentSlices(wantSlice, haveSlice)
+		}
+	}
+
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,120 @@
+package vault
+
+import (
+	"testing"
+
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemBackend_ConfigStateApply(t *testing.T) {
+	c, b, _ := testCoreSystemBackend(t)
+	ctx := namespace.RootContext(nil)
+
+	apply := func(data map[string]interface{}) []interface{} {
+		t.Helper()
+		req := logical.TestRequest(t, logical.UpdateOperation, "config/state/apply")
+		req.Data = data
+		resp, err := b.HandleRequest(ctx, req)
+		require.NoError(t, err)
+		require.False(t, resp.IsError(), "unexpected error: %#v", resp.Data)
+		return resp.Data["changes"].([]interface{})
+	}
+
+	bundle := map[string]interface{}{
+		"policies": map[string]interface{}{
+			"app": `path "apps/*" { capabilities = ["read"] }`,
+		},
+		"auth": map[string]interface{}{
+			"noop": map[string]interface{}{"type": "noop"},
+		},
+		"mounts": map[string]interface{}{
+			"apps": map[string]interface{}{
+				"type":        "kv",
+				"description": "application secrets",
+				"config":      map[string]interface{}{"default_lease_ttl": "1h"},
+			},
+		},
+		"resources": map[string]interface{}{
+			"apps/foo": map[string]interface{}{"value": "bar"},
+		},
+	}
+	created := []interface{}{
+		map[string]interface{}{"kind": "policy", "path": "app", "action": "create"},
+		map[string]interface{}{"kind": "auth", "path": "noop/", "action": "create"},
+		map[string]interface{}{"kind": "mount", "path": "apps/", "action": "create"},
+		map[string]interface{}{"kind": "resource", "path": "apps/foo", "action": "create"},
+	}
+
+	// A dry run reports the changes without applying them.
+	bundle["dry_run"] = true
+	require.Equal(t, created, apply(bundle))
+	require.Nil(t, c.router.MatchingMountEntry(ctx, "apps/"))
+
+	delete(bundle, "dry_run")
+	require.Equal(t, created, apply(bundle))
+
+	entry := c.router.MatchingMountEntry(ctx, "apps/")
+	require.NotNil(t, entry)
+	require.Equal(t, "application secrets", entry.Description)
+	require.Equal(t, "1h0m0s", entry.Config.DefaultLeaseTTL.String())
+	require.NotNil(t, c.router.MatchingMountEntry(ctx, "auth/noop/"))
+	policy, err := c.policyStore.GetPolicy(ctx, "app", PolicyTypeACL)
+	require.NoError(t, err)
+	require.NotNil(t, policy)
+
+	// Applying the same bundle again is a no-op.
+	require.Empty(t, apply(bundle))
+
+	// Changes are reported by field, and pruning removes what is not in
+	// the bundle while keeping the built-in mounts.
+	bundle["mounts"].(map[string]interface{})["apps"].(map[string]interface{})["description"] = "apps"
+	bundle["resources"] = map[string]interface{}{
+		"apps/foo": map[string]interface{}{"value": "baz"},
+	}
+	bundle["prune"] = true
+	require.Equal(t, []interface{}{
+		map[string]interface{}{"kind": "mount", "path": "apps/", "action": "update", "fields": []string{"description"}},
+		map[string]interface{}{"kind": "resource", "path": "apps/foo", "action": "update", "fields": []string{"value"}},
+		map[string]interface{}{"kind": "mount", "path": "secret/", "action": "delete"},
+	}, apply(bundle))
+
+	require.Equal(t, "apps", c.router.MatchingMountEntry(ctx, "apps/").Description)
+	require.Nil(t, c.router.MatchingMountEntry(ctx, "secret/"))
+	require.NotNil(t, c.router.MatchingMountEntry(ctx, "cubbyhole/"))
+
+	// A null resource is deleted.
+	bundle["resources"] = map[string]interface{}{"apps/foo": nil}
+	require.Equal(t, []interface{}{
+		map[string]interface{}{"kind": "resource", "path": "apps/foo", "action": "delete"},
+	}, apply(bundle))
+}
+
+func TestSystemBackend_ConfigStateApply_Invalid(t *testing.T) {
+	_, b, _ := testCoreSystemBackend(t)
+	ctx := namespace.RootContext(nil)
+
+	for name, data := range map[string]map[string]interface{}{
+		"type change": {
+			"mounts": map[string]interface{}{"secret": map[string]interface{}{"type": "noop"}},
+		},
+		"missing type": {
+			"mounts": map[string]interface{}{"new": map[string]interface{}{}},
+		},
+		"unknown parameter": {
+			"mounts": map[string]interface{}{"new": map[string]interface{}{"type": "kv", "bogus": true}},
+		},
+		"sys resource": {
+			"resources": map[string]interface{}{"sys/mounts/foo": map[string]interface{}{"type": "kv"}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			req := logical.TestRequest(t, logical.UpdateOperation, "config/state/apply")
+			req.Data = data
+			resp, err := b.HandleRequest(ctx, req)
+			require.ErrorIs(t, err, logical.ErrInvalidRequest)
+			require.True(t, resp.IsError())
+		})
+	}
+}
@@ -0,0 +1,538 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-secure-stdlib/strutil"
+	"github.com/openbao/openbao/helper/agekeys"
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/jsonutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const (
+	// configExportSigningKeyPath is the path in the system view of the key
+	// signing configuration bundles.
+	configExportSigningKeyPath = "config-export/signing-key"
+
+	// configExportBundleVersion is the version of the format of the
+	// configuration bundles.
+	configExportBundleVersion = 1
+)
+
+// configExportPath is an API path of a secrets engine or auth method holding
+// configuration, relative to its mount. If list is set, the path is listed
+// and each of its entries is exported. The overrides are added to the
+// exported data, such as to skip checks requiring credentials on import.
+type configExportPath struct {
+	path      string
+	list      bool
+	overrides map[string]interface{}
+}
+
+// configExportMountPaths are the configuration paths exported for each type
+// of secrets engine. Only paths whose read responses omit credentials are
+// listed; credentials must be written again after a bundle is imported.
+var configExportMountPaths = map[string][]configExportPath{
+	"database": {
+		{path: "config/", list: true, overrides: map[string]interface{}{"verify_connection": false}},
+		{path: "roles/", list: true},
+		{path: "static-roles/", list: true},
+	},
+	"kubernetes": {
+		{path: "config"},
+		{path: "roles/", list: true},
+	},
+	"ldap": {
+		{path: "config"},
+		{path: "role/", list: true},
+		{path: "static-role/", list: true},
+		{path: "library/", list: true},
+	},
+	"openldap": {
+		{path: "config"},
+		{path: "role/", list: true},
+		{path: "static-role/", list: true},
+		{path: "library/", list: true},
+	},
+	"pki": {
+		{path: "config/urls"},
+		{path: "config/crl"},
+		{path: "config/cluster"},
+		{path: "config/auto-tidy"},
+		{path: "roles/", list: true},
+	},
+	"rabbitmq": {
+		{path: "roles/", list: true},
+		{path: "static-roles/", list: true},
+	},
+	"ssh": {
+		{path: "roles/", list: true},
+	},
+}
+
+// configExportAuthPaths are the configuration paths exported for each type
+// of auth method.
+var configExportAuthPaths = map[string][]configExportPath{
+	"approle": {
+		{path: "role/", list: true},
+	},
+	"cert": {
+		{path: "config"},
+		{path: "certs/", list: true},
+	},
+	"jwt": {
+		{path: "config"},
+		{path: "role/", list: true},
+	},
+	"kerberos": {
+		{path: "groups/", list: true},
+	},
+	"kubernetes": {
+		{path: "config"},
+		{path: "role/", list: true},
+	},
+	"ldap": {
+		{path: "config"},
+		{path: "groups/", list: true},
+		{path: "users/", list: true},
+	},
+	"oidc": {
+		{path: "config"},
+		{path: "role/", list: true},
+	},
+	"radius": {
+		{path: "config"},
+		{path: "users/", list: true},
+	},
+	"spiffe": {
+		{path: "trust-domain/", list: true},
+		{path: "role/", list: true},
+	},
+}
+
+// configExportBundle is the logical configuration of a namespace, in the
+// format accepted by sys/config/state/apply.
+type configExportBundle struct {
+	Version     int                    `json:"version"`
+	CreatedTime time.Time              `json:"created_time"`
+	Mounts      map[string]interface{} `json:"mounts"`
+	Auth        map[string]interface{} `json:"auth"`
+	Policies    map[string]interface{} `json:"policies"`
+	Resources   map[string]interface{} `json:"resources"`
+}
+
+type configExportSigningKey struct {
+	PrivateKey []byte `json:"private_key"`
+}
+
+func (b *SystemBackend) configExportPaths() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "config/export$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "configuration",
+				OperationVerb:   "export",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"encrypt": {
+					Type:        framework.TypeBool,
+					Default:     true,
+					Description: "Whether to encrypt the bundle to the recipients of sys/config/backup-encryption, if any.",
+					Query:       true,
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleConfigExport,
+					Summary:  "Export the mounts, auth methods, roles and policies of the namespace as a signed bundle.",
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"bundle": {
+									Type: framework.TypeString,
+								},
+								"signature": {
+									Type: framework.TypeString,
+								},
+								"public_key": {
+									Type: framework.TypeString,
+								},
+								"encrypted_bundle": {
+									Type: framework.TypeString,
+								},
+							},
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["config/export"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["config/export"][1]),
+		},
+
+		{
+			Pattern: "config/import$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "configuration",
+				OperationVerb:   "import",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"bundle": {
+					Type:        framework.TypeString,
+					Description: "Bundle returned by sys/config/export.",
+					Required:    true,
+				},
+				"signature": {
+					Type:        framework.TypeString,
+					Description: "Signature of the bundle returned by sys/config/export.",
+					Required:    true,
+				},
+				"public_key": {
+					Type:        framework.TypeString,
+					Description: "Public key of the cluster the bundle was exported from. Defaults to the key of this cluster.",
+				},
+				"dry_run": {
+					Type:        framework.TypeBool,
+					Description: "If set, the changes are returned without being applied.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleConfigImport,
+					Summary:  "Verify a signed configuration bundle and apply it to the namespace.",
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"changes": {
+									Type:     framework.TypeSlice,
+									Required: true,
+								},
+								"dry_run": {
+									Type:     framework.TypeBool,
+									Required: true,
+								},
+							},
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["config/import"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["config/import"][1]),
+		},
+	}
+}
+
+func (b *SystemBackend) handleConfigExport(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	bundle, err := b.configExportBundle(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(bundle)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := b.Core.configExportSigningKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	exported := map[string]interface{}{
+		"bundle":     string(raw),
+		"signature":  base64.StdEncoding.EncodeToString(ed25519.Sign(key, raw)),
+		"public_key": base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey)),
+	}
+
+	var recipients []*agekeys.X25519Recipient
+	if data.Get("encrypt").(bool) {
+		recipients, err = b.Core.backupEncryptionRecipients(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(recipients) == 0 {
+		return &logical.Response{Data: exported}, nil
+	}
+
+	// The signed bundle is encrypted as the JSON body of sys/config/import
+	plaintext, err := json.Marshal(exported)
+	if err != nil {
+		return nil, err
+	}
+	var encrypted bytes.Buffer
+	w, err := agekeys.Encrypt(&encrypted, recipients...)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"encrypted_bundle": agekeys.Armor(encrypted.Bytes()),
+		},
+	}, nil
+}
+
+func (b *SystemBackend) handleConfigImport(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	raw := data.Get("bundle").(string)
+	if raw == "" {
+		return logical.ErrorResponse("bundle is required"), logical.ErrInvalidRequest
+	}
+	signature, err := base64.StdEncoding.DecodeString(data.Get("signature").(string))
+	if err != nil || len(signature) == 0 {
+		return logical.ErrorResponse("signature is required and must be base64 encoded"), logical.ErrInvalidRequest
+	}
+
+	var publicKey ed25519.PublicKey
+	if encoded := data.Get("public_key").(string); encoded != "" {
+		publicKey, err = base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(publicKey) != ed25519.PublicKeySize {
+			return logical.ErrorResponse("invalid public_key"), logical.ErrInvalidRequest
+		}
+	} else {
+		key, err := b.Core.configExportSigningKey(ctx)
+		if err != nil {
+			return nil, err
+		}
+		publicKey = key.Public().(ed25519.PublicKey)
+	}
+	if !ed25519.Verify(publicKey, []byte(raw), signature) {
+		return logical.ErrorResponse("invalid bundle signature"), logical.ErrPermissionDenied
+	}
+
+	var bundle configExportBundle
+	if err := jsonutil.DecodeJSON([]byte(raw), &bundle); err != nil {
+		return logical.ErrorResponse("invalid bundle: %s", err), logical.ErrInvalidRequest
+	}
+	if bundle.Version != configExportBundleVersion {
+		return logical.ErrorResponse("unsupported bundle version %d", bundle.Version), logical.ErrInvalidRequest
+	}
+
+	// The bundle is applied through sys/config/state/apply, without pruning,
+	// so that the configuration of the namespace is only added to.
+	applyReq := configApplyRequest(req, logical.UpdateOperation, "config/state/apply", map[string]interface{}{
+		"mounts":    bundle.Mounts,
+		"auth":      bundle.Auth,
+		"policies":  bundle.Policies,
+		"resources": bundle.Resources,
+		"dry_run":   data.Get("dry_run").(bool),
+	})
+	return b.HandleRequest(ctx, applyReq)
+}
+
+// configExportBundle collects the mounts, auth methods, ACL policies and
+// backend configuration of the namespace of the request.
+func (b *SystemBackend) configExportBundle(ctx context.Context, req *logical.Request) (*configExportBundle, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &configExportBundle{
+		Version:     configExportBundleVersion,
+		CreatedTime: time.Now().UTC(),
+		Mounts:      make(map[string]interface{}),
+		Auth:        make(map[string]interface{}),
+		Policies:    make(map[string]interface{}),
+		Resources:   make(map[string]interface{}),
+	}
+
+	var mountEntries, authEntries []*MountEntry
+	b.Core.mountsLock.RLock()
+	for _, entry := range b.Core.mounts.Entries {
+		if entry.Namespace().ID == ns.ID && !strutil.StrListContains(singletonMounts, entry.Type) {
+			mountEntries = append(mountEntries, entry)
+		}
+	}
+	b.Core.mountsLock.RUnlock()
+
+	b.Core.authLock.RLock()
+	for _, entry := range b.Core.auth.Entries {
+		if entry.Namespace().ID == ns.ID && !strutil.StrListContains(singletonMounts, entry.Type) {
+			authEntries = append(authEntries, entry)
+		}
+	}
+	b.Core.authLock.RUnlock()
+
+	for _, entry := range mountEntries {
+		bundle.Mounts[entry.Path] = configExportMountSpec(entry)
+		if err := b.configExportResources(ctx, req, bundle, entry.Path, configExportMountPaths[entry.Type]); err != nil {
+			return nil, err
+		}
+	}
+	for _, entry := range authEntries {
+		bundle.Auth[entry.Path] = configExportMountSpec(entry)
+		if err := b.configExportResources(ctx, req, bundle, credentialRoutePrefix+entry.Path, configExportAuthPaths[entry.Type]); err != nil {
+			return nil, err
+		}
+	}
+
+	names, err := b.Core.policyStore.ListPolicies(ctx, PolicyTypeACL)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if name == "root" {
+			continue
+		}
+		policy, err := b.Core.policyStore.GetPolicy(ctx, name, PolicyTypeACL)
+		if err != nil {
+			return nil, err
+		}
+		if policy != nil {
+			bundle.Policies[name] = policy.Raw
+		}
+	}
+
+	return bundle, nil
+}
+
+// configExportMountSpec returns the parameters re-creating a mount through
+// sys/mounts or sys/auth.
+func configExportMountSpec(entry *MountEntry) map[string]interface{} {
+	config := map[string]interface{}{
+		"force_no_cache": entry.Config.ForceNoCache,
+	}
+	if entry.Config.DefaultLeaseTTL != 0 {
+		config["default_lease_ttl"] = entry.Config.DefaultLeaseTTL.String()
+	}
+	if entry.Config.MaxLeaseTTL != 0 {
+		config["max_lease_ttl"] = entry.Config.MaxLeaseTTL.String()
+	}
+	if entry.Config.ListingVisibility != "" {
+		config["listing_visibility"] = string(entry.Config.ListingVisibility)
+	}
+	for key, value := range map[string][]string{
+		"audit_non_hmac_request_keys":  entry.Config.AuditNonHMACRequestKeys,
+		"audit_non_hmac_response_keys": entry.Config.AuditNonHMACResponseKeys,
+		"passthrough_request_headers":  entry.Config.PassthroughRequestHeaders,
+		"allowed_response_headers":     entry.Config.AllowedResponseHeaders,
+		"allowed_managed_keys":         entry.Config.AllowedManagedKeys,
+	} {
+		if len(value) > 0 {
+			config[key] = value
+		}
+	}
+
+	spec := map[string]interface{}{
+		"type":                    entry.Type,
+		"description":             entry.Description,
+		"local":                   entry.Local,
+		"seal_wrap":               entry.SealWrap,
+		"external_entropy_access": entry.ExternalEntropyAccess,
+		"config":                  config,
+	}
+	if len(entry.Options) > 0 {
+		spec["options"] = entry.Options
+	}
+	if entry.Version != "" {
+		spec["plugin_version"] = entry.Version
+	}
+	return spec
+}
+
+// configExportResources reads the configuration paths of a mount into the
+// resources of the bundle.
+func (b *SystemBackend) configExportResources(ctx context.Context, req *logical.Request, bundle *configExportBundle, prefix string, paths []configExportPath) error {
+	for _, p := range paths {
+		if !p.list {
+			if err := b.configExportResource(ctx, req, bundle, prefix+p.path, p.overrides); err != nil {
+				return err
+			}
+			continue
+		}
+
+		resp, err := b.Core.router.Route(ctx, configApplyRequest(req, logical.ListOperation, prefix+p.path, nil))
+		if err != nil && !errors.Is(err, logical.ErrUnsupportedPath) {
+			return fmt.Errorf("failed to list %q: %w", prefix+p.path, err)
+		}
+		if resp == nil || resp.IsError() {
+			continue
+		}
+		keys, _ := resp.Data["keys"].([]string)
+		for _, key := range keys {
+			if strings.HasSuffix(key, "/") {
+				continue
+			}
+			if err := b.configExportResource(ctx, req, bundle, prefix+p.path+key, p.overrides); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (b *SystemBackend) configExportResource(ctx context.Context, req *logical.Request, bundle *configExportBundle, path string, overrides map[string]interface{}) error {
+	resp, err := b.Core.router.Route(ctx, configApplyRequest(req, logical.ReadOperation, path, nil))
+	if err != nil && !errors.Is(err, logical.ErrUnsupportedPath) {
+		return fmt.Errorf("failed to read %q: %w", path, err)
+	}
+	if resp == nil || resp.IsError() || len(resp.Data) == 0 {
+		return nil
+	}
+	for key, value := range overrides {
+		resp.Data[key] = value
+	}
+	bundle.Resources[path] = resp.Data
+	return nil
+}
+
+// configExportSigningKey returns the key signing configuration bundles,
+// generating it on first use.
+func (c *Core) configExportSigningKey(ctx context.Context) (ed25519.PrivateKey, error) {
+	c.configExportKeyLock.Lock()
+	defer c.configExportKeyLock.Unlock()
+
+	entry, err := c.systemBarrierView.Get(ctx, configExportSigningKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	if entry != nil {
+		var key configExportSigningKey
+		if err := entry.DecodeJSON(&key); err != nil {
+			return nil, err
+		}
+		return ed25519.PrivateKey(key.PrivateKey), nil
+	}
+
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	entry, err = logical.StorageEntryJSON(configExportSigningKeyPath, &configExportSigningKey{
+		PrivateKey: privateKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := c.systemBarrierView.Put(ctx, entry); err != nil {
+		return nil, err
+	}
+	return privateKey, nil
+}
@@ -0,0 +1,162 @@
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/openbao/openbao/helper/agekeys"
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemBackend_ConfigExportImport(t *testing.T) {
+	// Export the entries of KV mounts as resources, to exercise the export
+	// of backend configuration without the built-in plugins.
+	configExportMountPaths["kv"] = []configExportPath{{path: "", list: true}}
+	defer delete(configExportMountPaths, "kv")
+
+	src, srcBackend, _ := testCoreSystemBackend(t)
+	dst, dstBackend, _ := testCoreSystemBackend(t)
+	ctx := namespace.RootContext(nil)
+
+	request := func(b logical.Backend, op logical.Operation, path string, data map[string]interface{}) *logical.Response {
+		t.Helper()
+		req := logical.TestRequest(t, op, path)
+		req.Data = data
+		resp, err := b.HandleRequest(ctx, req)
+		require.NoError(t, err)
+		if resp != nil {
+			require.False(t, resp.IsError(), "unexpected error: %#v", resp.Data)
+		}
+		return resp
+	}
+
+	request(srcBackend, logical.UpdateOperation, "mounts/apps", map[string]interface{}{
+		"type":        "kv",
+		"description": "application secrets",
+		"config":      map[string]interface{}{"default_lease_ttl": "1h"},
+	})
+	request(srcBackend, logical.UpdateOperation, "policies/acl/app", map[string]interface{}{
+		"policy": `path "apps/*" { capabilities = ["read"] }`,
+	})
+	_, err := src.router.Route(ctx, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "apps/settings",
+		Data:      map[string]interface{}{"region": "eu"},
+	})
+	require.NoError(t, err)
+
+	export := request(srcBackend, logical.ReadOperation, "config/export", nil).Data
+	var bundle map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(export["bundle"].(string)), &bundle))
+	require.Contains(t, bundle["mounts"], "apps/")
+	require.NotContains(t, bundle["mounts"], "sys/")
+	require.Contains(t, bundle["policies"], "app")
+	require.NotContains(t, bundle["policies"], "root")
+	require.Equal(t, map[string]interface{}{"apps/settings": map[string]interface{}{"region": "eu"}}, bundle["resources"])
+
+	// The signing key is kept across exports.
+	require.Equal(t, export["public_key"], request(srcBackend, logical.ReadOperation, "config/export", nil).Data["public_key"])
+
+	// Bundles of another cluster are verified with its public key.
+	importData := map[string]interface{}{
+		"bundle":    export["bundle"],
+		"signature": export["signature"],
+	}
+	req := logical.TestRequest(t, logical.UpdateOperation, "config/import")
+	req.Data = importData
+	resp, err := dstBackend.HandleRequest(ctx, req)
+	require.ErrorIs(t, err, logical.ErrPermissionDenied)
+	require.True(t, resp.IsError())
+
+	importData["public_key"] = export["public_key"]
+	importData["dry_run"] = true
+	changes := request(dstBackend, logical.UpdateOperation, "config/import", importData).Data["changes"]
+	require.Contains(t, changes, map[string]interface{}{"kind": "mount", "path": "apps/", "action": "create"})
+	require.Contains(t, changes, map[string]interface{}{"kind": "policy", "path": "app", "action": "create"})
+	require.Contains(t, changes, map[string]interface{}{"kind": "resource", "path": "apps/settings", "action": "create"})
+
+	delete(importData, "dry_run")
+	request(dstBackend, logical.UpdateOperation, "config/import", importData)
+
+	entry := dst.router.MatchingMountEntry(ctx, "apps/")
+	require.NotNil(t, entry)
+	require.Equal(t, "application secrets", entry.Description)
+	require.Equal(t, "1h0m0s", entry.Config.DefaultLeaseTTL.String())
+	policy, err := dst.policyStore.GetPolicy(ctx, "app", PolicyTypeACL)
+	require.NoError(t, err)
+	require.NotNil(t, policy)
+	settings, err := dst.router.Route(ctx, &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "apps/settings",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "eu", settings.Data["region"])
+
+	// Importing the same bundle again is a no-op.
+	require.Empty(t, request(dstBackend, logical.UpdateOperation, "config/import", importData).Data["changes"])
+
+	// Tampered bundles are rejected.
+	importData["bundle"] = export["bundle"].(string) + " "
+	req = logical.TestRequest(t, logical.UpdateOperation, "config/import")
+	req.Data = importData
+	_, err = dstBackend.HandleRequest(ctx, req)
+	require.ErrorIs(t, err, logical.ErrPermissionDenied)
+}
+
+func TestSystemBackend_ConfigExportEncrypted(t *testing.T) {
+	_, b, _ := testCoreSystemBackend(t)
+	ctx := namespace.RootContext(nil)
+
+	request := func(op logical.Operation, path string, data map[string]interface{}) (*logical.Response, error) {
+		t.Helper()
+		req := logical.TestRequest(t, op, path)
+		req.Data = data
+		return b.HandleRequest(ctx, req)
+	}
+
+	identity, err := agekeys.GenerateX25519Identity()
+	require.NoError(t, err)
+
+	resp, err := request(logical.UpdateOperation, "config/backup-encryption", map[string]interface{}{
+		"recipients": []string{"age1invalid"},
+	})
+	require.ErrorIs(t, err, logical.ErrInvalidRequest)
+	require.True(t, resp.IsError())
+
+	_, err = request(logical.UpdateOperation, "config/backup-encryption", map[string]interface{}{
+		"recipients": identity.Recipient().String(),
+	})
+	require.NoError(t, err)
+	resp, err = request(logical.ReadOperation, "config/backup-encryption", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{identity.Recipient().String()}, resp.Data["recipients"])
+
+	// The signed bundle is only readable with the backup identity
+	resp, err = request(logical.ReadOperation, "config/export", nil)
+	require.NoError(t, err)
+	require.NotContains(t, resp.Data, "bundle")
+	encrypted, err := agekeys.Dearmor(resp.Data["encrypted_bundle"].(string))
+	require.NoError(t, err)
+	decrypted, err := agekeys.Decrypt(bytes.NewReader(encrypted), identity)
+	require.NoError(t, err)
+	var exported map[string]interface{}
+	require.NoError(t, json.NewDecoder(decrypted).Decode(&exported))
+	require.Contains(t, exported, "bundle")
+	require.Contains(t, exported, "signature")
+
+	_, err = request(logical.UpdateOperation, "config/import", exported)
+	require.NoError(t, err)
+
+	resp, err = request(logical.ReadOperation, "config/export", map[string]interface{}{"encrypt": false})
+	require.NoError(t, err)
+	require.Contains(t, resp.Data, "bundle")
+
+	_, err = request(logical.DeleteOperation, "config/backup-encryption", nil)
+	require.NoError(t, err)
+	resp, err = request(logical.ReadOperation, "config/export", nil)
+	require.NoError(t, err)
+	require.Contains(t, resp.Data, "bundle")
+}
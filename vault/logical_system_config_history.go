@@ -0,0 +1,666 @@
+package vault
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openbao/openbao/helper/locking"
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+var configHistoryListResponse = map[int][]framework.Response{
+	http.StatusOK: {{
+		Description: "OK",
+		Fields: map[string]*framework.FieldSchema{
+			"keys": {
+				Type:     framework.TypeCommaStringSlice,
+				Required: true,
+			},
+			"key_info": {
+				Type:     framework.TypeMap,
+				Required: true,
+			},
+		},
+	}},
+}
+
+func (b *SystemBackend) configHistoryPaths() []*framework.Path {
+	paths := []*framework.Path{
+		{
+			Pattern: "policies/acl/(?P<name>.+)/versions/?$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "policies",
+				OperationSuffix: "acl-policy-versions",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["policy-name"][0]),
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback:  b.handlePolicyVersionsList,
+					Responses: configHistoryListResponse,
+					Summary:   "List the recorded versions of the named ACL policy.",
+				},
+				logical.ReadOperation: &framework.PathOperation{
+					Callback:  b.handlePolicyVersionsList,
+					Responses: configHistoryListResponse,
+					Summary:   "List the recorded versions of the named ACL policy.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["policy-versions"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["policy-versions"][1]),
+		},
+		{
+			Pattern: "policies/acl/(?P<name>.+)/versions/(?P<version>\\d+)$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "policies",
+				OperationVerb:   "read",
+				OperationSuffix: "acl-policy-version",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["policy-name"][0]),
+				},
+				"version": {
+					Type:        framework.TypeInt,
+					Description: "The version to read.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handlePolicyVersionRead,
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"name":    {Type: framework.TypeString, Required: true},
+								"version": {Type: framework.TypeInt, Required: true},
+								"policy":  {Type: framework.TypeString, Required: false},
+								"deleted": {Type: framework.TypeBool, Required: true},
+								"time":    {Type: framework.TypeTime, Required: true},
+								"author":  {Type: framework.TypeMap, Required: false},
+							},
+						}},
+					},
+					Summary: "Retrieve a recorded version of the named ACL policy.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["policy-versions"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["policy-versions"][1]),
+		},
+		{
+			Pattern: "policies/acl/(?P<name>.+)/rollback$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "policies",
+				OperationVerb:   "rollback",
+				OperationSuffix: "acl-policy",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["policy-name"][0]),
+				},
+				"version": {
+					Type:        framework.TypeInt,
+					Description: "The version to restore.",
+					Required:    true,
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handlePolicyRollback,
+					Responses: map[int][]framework.Response{
+						http.StatusNoContent: {{
+							Description: "OK",
+						}},
+					},
+					Summary: "Restore a recorded version of the named ACL policy.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["policy-rollback"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["policy-rollback"][1]),
+		},
+	}
+
+	paths = append(paths, b.mountHistoryPaths("mounts", "mounts", false)...)
+	paths = append(paths, b.mountHistoryPaths("auth", "auth", true)...)
+	return paths
+}
+
+// mountHistoryPaths returns the versions and rollback paths of secrets
+// engines or, if credential is set, of auth methods.
+func (b *SystemBackend) mountHistoryPaths(prefix, operationPrefix string, credential bool) []*framework.Path {
+	pathField := &framework.FieldSchema{
+		Type:        framework.TypeString,
+		Description: "The path of the mount.",
+	}
+
+	return []*framework.Path{
+		{
+			Pattern: prefix + "/(?P<path>.+?)/versions/?$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: operationPrefix,
+				OperationSuffix: "configuration-versions",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"path": pathField,
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback:  b.handleMountVersionsList(credential),
+					Responses: configHistoryListResponse,
+					Summary:   "List the recorded configuration versions of the mount.",
+				},
+				logical.ReadOperation: &framework.PathOperation{
+					Callback:  b.handleMountVersionsList(credential),
+					Responses: configHistoryListResponse,
+					Summary:   "List the recorded configuration versions of the mount.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["mount-versions"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["mount-versions"][1]),
+		},
+		{
+			Pattern: prefix + "/(?P<path>.+?)/versions/(?P<version>\\d+)$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: operationPrefix,
+				OperationVerb:   "read",
+				OperationSuffix: "configuration-version",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"path": pathField,
+				"version": {
+					Type:        framework.TypeInt,
+					Description: "The version to read.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleMountVersionRead(credential),
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"path":    {Type: framework.TypeString, Required: true},
+								"version": {Type: framework.TypeInt, Required: true},
+								"config":  {Type: framework.TypeMap, Required: true},
+								"time":    {Type: framework.TypeTime, Required: true},
+								"author":  {Type: framework.TypeMap, Required: false},
+							},
+						}},
+					},
+					Summary: "Retrieve a recorded configuration version of the mount.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["mount-versions"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["mount-versions"][1]),
+		},
+		{
+			Pattern: prefix + "/(?P<path>.+?)/rollback$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: operationPrefix,
+				OperationVerb:   "rollback",
+				OperationSuffix: "configuration",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"path": pathField,
+				"version": {
+					Type:        framework.TypeInt,
+					Description: "The version to restore.",
+					Required:    true,
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleMountRollback(credential),
+					Responses: map[int][]framework.Response{
+						http.StatusNoContent: {{
+							Description: "OK",
+						}},
+					},
+					Summary: "Restore a recorded configuration version of the mount.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["mount-rollback"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["mount-rollback"][1]),
+		},
+	}
+}
+
+func (b *SystemBackend) handlePolicyVersionsList(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	name := data.Get("name").(string)
+
+	versions, err := b.Core.policyStore.PolicyVersions(ctx, name)
+	if err != nil {
+		return handleError(err)
+	}
+	if len(versions) == 0 {
+		return nil, nil
+	}
+
+	keys := make([]string, 0, len(versions))
+	keyInfo := make(map[string]interface{}, len(versions))
+	for _, v := range versions {
+		key := strconv.Itoa(v.Version)
+		keys = append(keys, key)
+		keyInfo[key] = map[string]interface{}{
+			"deleted": v.Deleted,
+			"time":    v.Time,
+			"author":  v.Author.toMap(),
+		}
+	}
+	return logical.ListResponseWithInfo(keys, keyInfo), nil
+}
+
+func (b *SystemBackend) handlePolicyVersionRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	name := data.Get("name").(string)
+
+	v, err := b.Core.policyStore.PolicyVersion(ctx, name, data.Get("version").(int))
+	if err != nil {
+		return handleError(err)
+	}
+	if v == nil {
+		return nil, nil
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"name":    b.Core.policyStore.sanitizeName(name),
+			"version": v.Version,
+			"policy":  v.Raw,
+			"deleted": v.Deleted,
+			"time":    v.Time,
+			"author":  v.Author.toMap(),
+		},
+	}, nil
+}
+
+// handlePolicyRollback restores a previous version of an ACL policy. The
+// restored policy is recorded as a new version.
+func (b *SystemBackend) handlePolicyRollback(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name := b.Core.policyStore.sanitizeName(data.Get("name").(string))
+	version := data.Get("version").(int)
+	if version <= 0 {
+		return logical.ErrorResponse("a positive version must be provided"), logical.ErrInvalidRequest
+	}
+
+	v, err := b.Core.policyStore.PolicyVersion(ctx, name, version)
+	if err != nil {
+		return handleError(err)
+	}
+	if v == nil {
+		return logical.ErrorResponse("version %d of policy %q not found", version, name), logical.ErrInvalidRequest
+	}
+	if v.Deleted {
+		return logical.ErrorResponse("version %d of policy %q is a deletion", version, name), logical.ErrInvalidRequest
+	}
+
+	p, err := ParseACLPolicy(ns, v.Raw)
+	if err != nil {
+		return handleError(err)
+	}
+	policy := &Policy{
+		Name:      name,
+		Type:      PolicyTypeACL,
+		Raw:       v.Raw,
+		Paths:     p.Paths,
+		Templated: p.Templated,
+		namespace: ns,
+	}
+
+	if err := b.Core.policyStore.SetPolicyWithAuthor(ctx, policy, configChangeAuthorFromRequest(req)); err != nil {
+		return handleError(err)
+	}
+	return nil, nil
+}
+
+func (b *SystemBackend) mountHistory() *configHistory {
+	return &configHistory{view: b.Core.systemBarrierView.SubView(mountHistorySubPath)}
+}
+
+// mountHistoryEntry returns the API path and entry of the mount named in
+// the request, or a nil entry if no mount exists at exactly that path.
+func (b *SystemBackend) mountHistoryEntry(ctx context.Context, data *framework.FieldData, credential bool) (string, *MountEntry, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+
+	path := sanitizePath(data.Get("path").(string))
+	if credential {
+		path = credentialRoutePrefix + path
+	}
+
+	match := b.Core.router.MatchingMount(ctx, path)
+	if match == "" || ns.Path+path != match {
+		return path, nil, nil
+	}
+	return path, b.Core.router.MatchingMountEntry(ctx, path), nil
+}
+
+func (b *SystemBackend) handleMountVersionsList(credential bool) framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		_, entry, err := b.mountHistoryEntry(ctx, data, credential)
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			return nil, nil
+		}
+
+		h := b.mountHistory()
+		versions, err := h.versions(ctx, entry.Accessor)
+		if err != nil {
+			return handleError(err)
+		}
+		if len(versions) == 0 {
+			return nil, nil
+		}
+
+		keys := make([]string, 0, len(versions))
+		keyInfo := make(map[string]interface{}, len(versions))
+		var previous *MountConfigVersion
+		for _, n := range versions {
+			v := new(MountConfigVersion)
+			ok, err := h.get(ctx, entry.Accessor, n, v)
+			if err != nil {
+				return handleError(err)
+			}
+			if !ok {
+				continue
+			}
+
+			info := map[string]interface{}{
+				"path":   v.Path,
+				"time":   v.Time,
+				"author": v.Author.toMap(),
+			}
+			if previous != nil {
+				info["changed"] = mountConfigChanges(previous.Config, v.Config)
+			}
+			previous = v
+
+			key := strconv.Itoa(v.Version)
+			keys = append(keys, key)
+			keyInfo[key] = info
+		}
+		return logical.ListResponseWithInfo(keys, keyInfo), nil
+	}
+}
+
+func (b *SystemBackend) handleMountVersionRead(credential bool) framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		_, entry, err := b.mountHistoryEntry(ctx, data, credential)
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			return nil, nil
+		}
+
+		v := new(MountConfigVersion)
+		ok, err := b.mountHistory().get(ctx, entry.Accessor, data.Get("version").(int), v)
+		if err != nil {
+			return handleError(err)
+		}
+		if !ok {
+			return nil, nil
+		}
+
+		return &logical.Response{
+			Data: map[string]interface{}{
+				"path":    v.Path,
+				"version": v.Version,
+				"config":  v.Config,
+				"time":    v.Time,
+				"author":  v.Author.toMap(),
+			},
+		}, nil
+	}
+}
+
+// handleMountRollback restores a previous configuration of a mount by
+// tuning it to the recorded values. The restored configuration is recorded
+// as a new version.
+func (b *SystemBackend) handleMountRollback(credential bool) framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		path, entry, err := b.mountHistoryEntry(ctx, data, credential)
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			return logical.ErrorResponse("no mount found at %q", path), logical.ErrInvalidRequest
+		}
+
+		version := data.Get("version").(int)
+		if version <= 0 {
+			return logical.ErrorResponse("a positive version must be provided"), logical.ErrInvalidRequest
+		}
+
+		v := new(MountConfigVersion)
+		ok, err := b.mountHistory().get(ctx, entry.Accessor, version, v)
+		if err != nil {
+			return handleError(err)
+		}
+		if !ok {
+			return logical.ErrorResponse("version %d of mount %q not found", version, path), logical.ErrInvalidRequest
+		}
+
+		tuneData := make(map[string]interface{}, len(v.Config))
+		for k, val := range v.Config {
+			tuneData[k] = val
+		}
+
+		// Options are merged when tuning, so options added since the
+		// version are removed explicitly. The KV version option cannot be
+		// downgraded and is left as is.
+		options := map[string]interface{}{}
+		if recorded, ok := v.Config["options"].(map[string]interface{}); ok {
+			for k, val := range recorded {
+				options[k] = val
+			}
+		}
+		current := b.mountConfigSnapshot(path, entry)
+		for k := range current["options"].(map[string]string) {
+			if _, ok := options[k]; !ok {
+				options[k] = ""
+			}
+		}
+		delete(options, "version")
+		tuneData["options"] = options
+
+		tuneReq := &logical.Request{
+			Operation:           logical.UpdateOperation,
+			Path:                "mounts/" + path + "tune",
+			Data:                tuneData,
+			EntityID:            req.EntityID,
+			DisplayName:         req.DisplayName,
+			ClientTokenAccessor: req.ClientTokenAccessor,
+			Connection:          req.Connection,
+		}
+		resp, err := b.HandleRequest(ctx, tuneReq)
+		if err != nil || resp.IsError() {
+			return resp, err
+		}
+		return resp, nil
+	}
+}
+
+// handleTuneWriteWithHistory tunes a mount and records the resulting
+// configuration in the mount history.
+func (b *SystemBackend) handleTuneWriteWithHistory(ctx context.Context, req *logical.Request, path string, data *framework.FieldData) (*logical.Response, error) {
+	path = sanitizePath(path)
+
+	b.mountHistoryLock.Lock()
+	defer b.mountHistoryLock.Unlock()
+
+	entry := b.Core.router.MatchingMountEntry(ctx, path)
+	if entry == nil {
+		return b.handleTuneWriteCommon(ctx, path, data)
+	}
+
+	before := b.mountConfigSnapshot(path, entry)
+	resp, err := b.handleTuneWriteCommon(ctx, path, data)
+	if err != nil || resp.IsError() {
+		return resp, err
+	}
+	after := b.mountConfigSnapshot(path, entry)
+
+	b.recordMountVersion(ctx, path, entry, before, after, configChangeAuthorFromRequest(req))
+	return resp, nil
+}
+
+// recordMountVersion records after as a new configuration version of the
+// mount if it differs from before. If no history exists yet, before is
+// recorded first so that the change can be rolled back. Failures are only
+// logged, as the change itself has already been persisted.
+func (b *SystemBackend) recordMountVersion(ctx context.Context, path string, entry *MountEntry, before, after map[string]interface{}, author *ConfigChangeAuthor) {
+	if reflect.DeepEqual(before, after) {
+		return
+	}
+
+	h := b.mountHistory()
+	now := time.Now().UTC()
+	err := func() error {
+		versions, err := h.versions(ctx, entry.Accessor)
+		if err != nil {
+			return err
+		}
+		if len(versions) == 0 {
+			err := h.append(ctx, entry.Accessor, func(v int) interface{} {
+				return &MountConfigVersion{Version: v, Path: path, Config: before, Time: now}
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return h.append(ctx, entry.Accessor, func(v int) interface{} {
+			return &MountConfigVersion{Version: v, Path: path, Config: after, Time: now, Author: author}
+		})
+	}()
+	if err != nil {
+		b.Backend.Logger().Error("failed to record mount configuration version", "path", path, "error", err)
+	}
+}
+
+// clearMountHistory removes the configuration history of a mount that has
+// been disabled.
+func (b *SystemBackend) clearMountHistory(ctx context.Context, entry *MountEntry) {
+	if entry == nil {
+		return
+	}
+	if err := b.mountHistory().clear(ctx, entry.Accessor); err != nil {
+		b.Backend.Logger().Error("failed to remove mount configuration history", "path", entry.Path, "error", err)
+	}
+}
+
+// mountConfigSnapshot returns the tunable configuration of a mount in the
+// format accepted by the tune endpoints.
+func (b *SystemBackend) mountConfigSnapshot(path string, entry *MountEntry) map[string]interface{} {
+	var lock *locking.DeadlockRWMutex
+	switch {
+	case strings.HasPrefix(path, credentialRoutePrefix):
+		lock = &b.Core.authLock
+	default:
+		lock = &b.Core.mountsLock
+	}
+	lock.RLock()
+	defer lock.RUnlock()
+
+	ttl := func(d time.Duration) string {
+		if d == 0 {
+			return "system"
+		}
+		return strconv.FormatInt(int64(d.Seconds()), 10)
+	}
+	list := func(l []string) []string {
+		if l == nil {
+			return []string{}
+		}
+		return append([]string(nil), l...)
+	}
+
+	options := make(map[string]string, len(entry.Options))
+	for k, v := range entry.Options {
+		options[k] = v
+	}
+
+	config := map[string]interface{}{
+		"description":                  entry.Description,
+		"default_lease_ttl":            ttl(entry.Config.DefaultLeaseTTL),
+		"max_lease_ttl":                ttl(entry.Config.MaxLeaseTTL),
+		"listing_visibility":           string(entry.Config.ListingVisibility),
+		"audit_non_hmac_request_keys":  list(entry.Config.AuditNonHMACRequestKeys),
+		"audit_non_hmac_response_keys": list(entry.Config.AuditNonHMACResponseKeys),
+		"passthrough_request_headers":  list(entry.Config.PassthroughRequestHeaders),
+		"allowed_response_headers":     list(entry.Config.AllowedResponseHeaders),
+		"allowed_managed_keys":         list(entry.Config.AllowedManagedKeys),
+		"options":                      options,
+	}
+	if entry.Version != "" {
+		config["plugin_version"] = entry.Version
+	}
+	if entry.Table == credentialTableType && entry.Type != mountTypeToken && entry.Type != mountTypeNSToken {
+		config["token_type"] = entry.Config.TokenType.String()
+	}
+	return config
+}
+
+// mountConfigChanges returns the sorted keys whose values differ between
+// two recorded configurations.
+func mountConfigChanges(before, after map[string]interface{}) []string {
+	changed := []string{}
+	for k, v := range after {
+		if !reflect.DeepEqual(before[k], v) {
+			changed = append(changed, k)
+		}
+	}
+	for k := range before {
+		if _, ok := after[k]; !ok {
+			changed = append(changed, k)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
@@ -0,0 +1,145 @@
+package vault
+
+import (
+	"testing"
+
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemBackend_PolicyVersions(t *testing.T) {
+	c, b, _ := testCoreSystemBackend(t)
+	ctx := namespace.RootContext(nil)
+
+	request := func(op logical.Operation, path string, data map[string]interface{}) *logical.Response {
+		t.Helper()
+		req := logical.TestRequest(t, op, path)
+		req.Data = data
+		req.EntityID = "entity-1"
+		req.DisplayName = "root"
+		resp, err := b.HandleRequest(ctx, req)
+		require.NoError(t, err)
+		require.False(t, resp.IsError(), "unexpected error: %#v", resp)
+		return resp
+	}
+
+	// A policy that existed before history was kept gets its previous
+	// contents recorded as the first version.
+	v1 := `path "secret/a" { capabilities = ["read"] }`
+	v2 := `path "secret/b" { capabilities = ["read"] }`
+	p, err := ParseACLPolicy(namespace.RootNamespace, v1)
+	require.NoError(t, err)
+	p.Name = "app"
+	require.NoError(t, c.policyStore.setPolicyInternal(ctx, p))
+
+	request(logical.UpdateOperation, "policies/acl/app", map[string]interface{}{"policy": v2})
+	request(logical.DeleteOperation, "policies/acl/app", nil)
+
+	resp := request(logical.ListOperation, "policies/acl/app/versions/", nil)
+	require.Equal(t, []string{"1", "2", "3"}, resp.Data["keys"])
+	keyInfo := resp.Data["key_info"].(map[string]interface{})
+	require.Nil(t, keyInfo["1"].(map[string]interface{})["author"])
+	require.Equal(t, "entity-1", keyInfo["2"].(map[string]interface{})["author"].(map[string]interface{})["entity_id"])
+	require.Equal(t, true, keyInfo["3"].(map[string]interface{})["deleted"])
+
+	resp = request(logical.ReadOperation, "policies/acl/app/versions/2", nil)
+	require.Equal(t, v2, resp.Data["policy"])
+
+	// Rolling back restores the policy and records a new version.
+	request(logical.UpdateOperation, "policies/acl/app/rollback", map[string]interface{}{"version": 1})
+	policy, err := c.policyStore.GetPolicy(ctx, "app", PolicyTypeACL)
+	require.NoError(t, err)
+	require.Equal(t, v1, policy.Raw)
+
+	resp = request(logical.ReadOperation, "policies/acl/app/versions", nil)
+	require.Equal(t, []string{"1", "2", "3", "4"}, resp.Data["keys"])
+
+	// Deletions cannot be restored.
+	req := logical.TestRequest(t, logical.UpdateOperation, "policies/acl/app/rollback")
+	req.Data = map[string]interface{}{"version": 3}
+	resp, err = b.HandleRequest(ctx, req)
+	require.ErrorIs(t, err, logical.ErrInvalidRequest)
+	require.True(t, resp.IsError())
+}
+
+func TestSystemBackend_PolicyVersions_Max(t *testing.T) {
+	c, _, _ := testCoreSystemBackend(t)
+	ctx := namespace.RootContext(nil)
+
+	for i := 0; i < configHistoryMaxVersions+5; i++ {
+		p, err := ParseACLPolicy(namespace.RootNamespace, `path "secret/*" { capabilities = ["read"] }`)
+		require.NoError(t, err)
+		p.Name = "app"
+		require.NoError(t, c.policyStore.SetPolicy(ctx, p))
+	}
+
+	versions, err := c.policyStore.PolicyVersions(ctx, "app")
+	require.NoError(t, err)
+	require.Len(t, versions, configHistoryMaxVersions)
+	require.Equal(t, 6, versions[0].Version)
+}
+
+func TestSystemBackend_MountVersions(t *testing.T) {
+	c, b, _ := testCoreSystemBackend(t)
+	ctx := namespace.RootContext(nil)
+
+	request := func(op logical.Operation, path string, data map[string]interface{}) *logical.Response {
+		t.Helper()
+		req := logical.TestRequest(t, op, path)
+		req.Data = data
+		req.DisplayName = "root"
+		resp, err := b.HandleRequest(ctx, req)
+		require.NoError(t, err)
+		require.False(t, resp.IsError(), "unexpected error: %#v", resp)
+		return resp
+	}
+
+	request(logical.UpdateOperation, "mounts/secret/tune", map[string]interface{}{
+		"description":       "first",
+		"default_lease_ttl": "1h",
+	})
+	request(logical.UpdateOperation, "mounts/secret/tune", map[string]interface{}{
+		"description": "second",
+		"options":     map[string]interface{}{"foo": "bar"},
+	})
+
+	// A tune that does not change anything is not recorded.
+	request(logical.UpdateOperation, "mounts/secret/tune", map[string]interface{}{
+		"description": "second",
+	})
+
+	resp := request(logical.ListOperation, "mounts/secret/versions/", nil)
+	require.Equal(t, []string{"1", "2", "3"}, resp.Data["keys"])
+	keyInfo := resp.Data["key_info"].(map[string]interface{})
+	require.Equal(t, []string{"default_lease_ttl", "description"}, keyInfo["2"].(map[string]interface{})["changed"])
+	require.Equal(t, []string{"description", "options"}, keyInfo["3"].(map[string]interface{})["changed"])
+	require.Equal(t, "root", keyInfo["3"].(map[string]interface{})["author"].(map[string]interface{})["display_name"])
+
+	resp = request(logical.ReadOperation, "mounts/secret/versions/2", nil)
+	require.Equal(t, "first", resp.Data["config"].(map[string]interface{})["description"])
+
+	// Rolling back restores the configuration, including removing options
+	// added since.
+	request(logical.UpdateOperation, "mounts/secret/rollback", map[string]interface{}{"version": 1})
+	entry := c.router.MatchingMountEntry(ctx, "secret/")
+	require.Equal(t, "key/value secret storage", entry.Description)
+	require.Zero(t, entry.Config.DefaultLeaseTTL)
+	require.NotContains(t, entry.Options, "foo")
+
+	resp = request(logical.ListOperation, "mounts/secret/versions/", nil)
+	require.Equal(t, []string{"1", "2", "3", "4"}, resp.Data["keys"])
+
+	// Auth methods are versioned separately and the history is removed
+	// when they are disabled.
+	request(logical.UpdateOperation, "auth/noop", map[string]interface{}{"type": "noop"})
+	request(logical.UpdateOperation, "auth/noop/tune", map[string]interface{}{"token_type": "batch"})
+	resp = request(logical.ReadOperation, "auth/noop/versions", nil)
+	require.Equal(t, []string{"1", "2"}, resp.Data["keys"])
+
+	accessor := c.router.MatchingMountEntry(ctx, "auth/noop/").Accessor
+	request(logical.DeleteOperation, "auth/noop", nil)
+	versions, err := c.systemBackend.mountHistory().versions(ctx, accessor)
+	require.NoError(t, err)
+	require.Empty(t, versions)
+}
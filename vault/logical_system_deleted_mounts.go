@@ -0,0 +1,225 @@
+package vault
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+// deletedMountPaths are registered before the mount and auth paths, which
+// would otherwise match the undelete endpoints.
+func (b *SystemBackend) deletedMountPaths() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "mounts/(?P<path>.+?)/undelete$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "mounts",
+				OperationVerb:   "undelete",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"path": {
+					Type:        framework.TypeString,
+					Description: "The path of the disabled secrets engine.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleUndeleteMount(mountTableType),
+					Summary:  "Enable again the most recently disabled secrets engine at the given path, with its data.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["mount-undelete"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["mount-undelete"][1]),
+		},
+
+		{
+			Pattern: "auth/(?P<path>.+?)/undelete$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "auth",
+				OperationVerb:   "undelete",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"path": {
+					Type:        framework.TypeString,
+					Description: "The path of the disabled auth method, relative to auth/.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleUndeleteMount(credentialTableType),
+					Summary:  "Enable again the most recently disabled auth method at the given path, with its data.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["mount-undelete"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["mount-undelete"][1]),
+		},
+
+		{
+			Pattern: "deleted-mounts/?$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "deleted-mounts",
+				OperationVerb:   "list",
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback: b.handleDeletedMountsList,
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"keys": {
+									Type:     framework.TypeStringSlice,
+									Required: true,
+								},
+								"key_info": {
+									Type: framework.TypeMap,
+								},
+							},
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["deleted-mounts"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["deleted-mounts"][1]),
+		},
+
+		{
+			Pattern: "deleted-mounts/(?P<uuid>[^/]+)$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "deleted-mounts",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"uuid": {
+					Type:        framework.TypeString,
+					Description: "The UUID of the disabled secrets engine or auth method.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleDeletedMountRead,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "read",
+					},
+					Summary: "Read a disabled secrets engine or auth method whose data is retained.",
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.handleDeletedMountPurge,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "purge",
+					},
+					Summary: "Delete the retained data of a disabled secrets engine or auth method now.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["deleted-mounts-uuid"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["deleted-mounts-uuid"][1]),
+		},
+	}
+}
+
+func (b *SystemBackend) handleUndeleteMount(table string) framework.OperationFunc {
+	return func(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+		path := data.Get("path").(string)
+		if path == "" {
+			return logical.ErrorResponse("path is required"), logical.ErrInvalidRequest
+		}
+		path = sanitizePath(path)
+
+		entry, err := b.Core.undeleteMount(ctx, table, path)
+		if err != nil {
+			b.Backend.Logger().Error("undelete failed", "path", path, "table", table, "error", err)
+			return handleError(err)
+		}
+
+		return &logical.Response{
+			Data: map[string]interface{}{
+				"uuid":     entry.UUID,
+				"type":     entry.Type,
+				"accessor": entry.Accessor,
+			},
+		}, nil
+	}
+}
+
+func (b *SystemBackend) handleDeletedMountsList(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := b.Core.deletedMounts(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(deleted))
+	keyInfo := make(map[string]interface{}, len(deleted))
+	for _, d := range deleted {
+		keys = append(keys, d.Entry.UUID)
+		keyInfo[d.Entry.UUID] = d.toMap()
+	}
+	return logical.ListResponseWithInfo(keys, keyInfo), nil
+}
+
+// deletedMountInNamespace returns the retained mount with the given UUID, if
+// it belongs to the namespace of the request.
+func (b *SystemBackend) deletedMountInNamespace(ctx context.Context, uuid string) (*DeletedMount, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := b.Core.deletedMount(ctx, uuid)
+	if err != nil || deleted == nil || deleted.Entry.NamespaceID != ns.ID {
+		return nil, err
+	}
+	return deleted, nil
+}
+
+func (b *SystemBackend) handleDeletedMountRead(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	deleted, err := b.deletedMountInNamespace(ctx, data.Get("uuid").(string))
+	if err != nil {
+		return nil, err
+	}
+	if deleted == nil {
+		return nil, nil
+	}
+	return &logical.Response{
+		Data: deleted.toMap(),
+	}, nil
+}
+
+func (b *SystemBackend) handleDeletedMountPurge(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	b.Core.deletedMountsLock.Lock()
+	defer b.Core.deletedMountsLock.Unlock()
+
+	deleted, err := b.deletedMountInNamespace(ctx, data.Get("uuid").(string))
+	if err != nil {
+		return nil, err
+	}
+	if deleted == nil {
+		return nil, nil
+	}
+	if err := b.Core.purgeDeletedMount(ctx, deleted); err != nil {
+		return handleError(err)
+	}
+	return nil, nil
+}
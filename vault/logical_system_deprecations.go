@@ -0,0 +1,50 @@
+package vault
+
+import (
+	"context"
+	"strings"
+
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+func (b *SystemBackend) deprecationPaths() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "deprecations$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "deprecations",
+				OperationVerb:   "read",
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleDeprecationsRead,
+					Summary:  "List the deprecated features in use, with the mounts using them.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["deprecations"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["deprecations"][1]),
+		},
+	}
+}
+
+func (b *SystemBackend) handleDeprecationsRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deprecations, err := b.Core.deprecationReport(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"deprecations": deprecations,
+		},
+	}, nil
+}
@@ -0,0 +1,485 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+func (b *SystemBackend) federationPaths() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "federation/tokens/?$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "federation-tokens",
+				OperationVerb:   "list",
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback: b.handleFederationTokenList,
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["federation/tokens"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["federation/tokens"][1]),
+		},
+
+		{
+			Pattern: "federation/tokens/" + framework.GenericNameRegex("name") + "$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "federation-tokens",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the federation token.",
+				},
+				"allowed_cluster_names": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "Globs matched against the names of the clusters reporting with the token. If empty, any cluster may report with it.",
+				},
+				"ttl": {
+					Type:        framework.TypeDurationSecond,
+					Description: "Lifetime of the token. If zero, the token does not expire.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleFederationTokenRead,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "read",
+					},
+					Summary: "Read a federation token, without its secret.",
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleFederationTokenWrite,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "create",
+					},
+					Summary: "Create a federation token, replacing any token of the same name.",
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.handleFederationTokenDelete,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "revoke",
+					},
+					Summary: "Revoke a federation token.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["federation/tokens-name"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["federation/tokens-name"][1]),
+		},
+
+		{
+			Pattern: "federation/report$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "federation",
+				OperationVerb:   "report",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"federation_token": {
+					Type:        framework.TypeString,
+					Description: "Federation token issued by the management cluster.",
+				},
+				"cluster_id": {
+					Type:        framework.TypeString,
+					Description: "ID of the reporting cluster.",
+				},
+				"cluster_name": {
+					Type:        framework.TypeString,
+					Description: "Name of the reporting cluster.",
+				},
+				"version": {
+					Type:        framework.TypeString,
+					Description: "Version of the reporting cluster.",
+				},
+				"api_addr": {
+					Type:        framework.TypeString,
+					Description: "API address of the reporting cluster.",
+				},
+				"report_interval": {
+					Type:        framework.TypeInt,
+					Description: "Interval between two reports of the cluster, in seconds.",
+				},
+				"health": {
+					Type:        framework.TypeMap,
+					Description: "Health of the reporting cluster.",
+				},
+				"mounts": {
+					Type:        framework.TypeSlice,
+					Description: "Secrets engines of the reporting cluster.",
+				},
+				"auth_mounts": {
+					Type:        framework.TypeSlice,
+					Description: "Auth methods of the reporting cluster.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleFederationReport,
+					Summary:  "Report the health, version and mounts of a member cluster.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["federation/report"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["federation/report"][1]),
+		},
+
+		{
+			Pattern: "federation/clusters/?$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "federation-clusters",
+				OperationVerb:   "list",
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback: b.handleFederationClusterList,
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["federation/clusters"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["federation/clusters"][1]),
+		},
+
+		{
+			Pattern: "federation/clusters/" + framework.GenericNameRegex("cluster_id") + "$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "federation-clusters",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"cluster_id": {
+					Type:        framework.TypeString,
+					Description: "ID of the cluster.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleFederationClusterRead,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "read",
+					},
+					Summary: "Read the last report of a cluster.",
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.handleFederationClusterDelete,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "delete",
+					},
+					Summary: "Remove a cluster from the registry, until it reports again.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["federation/clusters-id"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["federation/clusters-id"][1]),
+		},
+
+		{
+			Pattern: "federation/config$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "federation",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"management_addr": {
+					Type:        framework.TypeString,
+					Description: "API address of the management cluster, such as https://bao-mgmt.example.com:8200.",
+				},
+				"token": {
+					Type:        framework.TypeString,
+					Description: "Federation token issued by the management cluster.",
+				},
+				"report_interval": {
+					Type:        framework.TypeDurationSecond,
+					Default:     int(defaultFederationReportInterval.Seconds()),
+					Description: "Interval between two reports.",
+				},
+				"ca_cert": {
+					Type:        framework.TypeString,
+					Description: "PEM-encoded CA certificate used to verify the management cluster, instead of the system CAs.",
+				},
+				"tls_server_name": {
+					Type:        framework.TypeString,
+					Description: "Server name used to verify the certificate of the management cluster.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleFederationConfigRead,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb:   "read",
+						OperationSuffix: "configuration",
+					},
+					Summary: "Read the management cluster this cluster reports to.",
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleFederationConfigWrite,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb:   "configure",
+						OperationSuffix: "configuration",
+					},
+					Summary: "Configure the management cluster this cluster reports to.",
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.handleFederationConfigDelete,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb:   "delete",
+						OperationSuffix: "configuration",
+					},
+					Summary: "Stop reporting to the management cluster.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["federation/config"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["federation/config"][1]),
+		},
+	}
+}
+
+func (b *SystemBackend) handleFederationTokenList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	names, err := b.Core.systemBarrierView.List(ctx, federationTokenSubPath)
+	if err != nil {
+		return nil, err
+	}
+	return logical.ListResponse(names), nil
+}
+
+func (b *SystemBackend) handleFederationTokenRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	token, err := b.Core.federationToken(ctx, d.Get("name").(string))
+	if err != nil || token == nil {
+		return nil, err
+	}
+	return &logical.Response{
+		Data: token.toMap(),
+	}, nil
+}
+
+func (b *SystemBackend) handleFederationTokenWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ttl := time.Duration(d.Get("ttl").(int)) * time.Second
+	if ttl < 0 {
+		return logical.ErrorResponse("ttl cannot be negative"), logical.ErrInvalidRequest
+	}
+
+	token, entry, err := b.Core.createFederationToken(ctx, d.Get("name").(string), d.Get("allowed_cluster_names").([]string), ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	data := entry.toMap()
+	data["token"] = token
+	return &logical.Response{
+		Data: data,
+	}, nil
+}
+
+func (b *SystemBackend) handleFederationTokenDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if err := b.Core.systemBarrierView.Delete(ctx, federationTokenSubPath+d.Get("name").(string)); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (b *SystemBackend) handleFederationReport(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	report, err := parseFederationReport(d)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	token, err := b.Core.checkFederationToken(ctx, d.Get("federation_token").(string), report.ClusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	var remoteAddr string
+	if req.Connection != nil {
+		remoteAddr = req.Connection.RemoteAddr
+	}
+	if err := b.Core.registerFederatedCluster(ctx, report, token, remoteAddr); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+// parseFederationReport decodes the report of a member cluster from the
+// fields of the request.
+func parseFederationReport(d *framework.FieldData) (*FederationReport, error) {
+	raw, err := json.Marshal(map[string]interface{}{
+		"cluster_id":      d.Get("cluster_id"),
+		"cluster_name":    d.Get("cluster_name"),
+		"version":         d.Get("version"),
+		"api_addr":        d.Get("api_addr"),
+		"report_interval": d.Get("report_interval"),
+		"health":          d.Get("health"),
+		"mounts":          d.Get("mounts"),
+		"auth_mounts":     d.Get("auth_mounts"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := new(FederationReport)
+	if err := json.Unmarshal(raw, report); err != nil {
+		return nil, err
+	}
+	switch {
+	case report.ClusterID == "":
+		return nil, errors.New("cluster_id is required")
+	case report.ClusterName == "":
+		return nil, errors.New("cluster_name is required")
+	case report.Version == "":
+		return nil, errors.New("version is required")
+	case strings.Contains(report.ClusterID, "/"):
+		return nil, errors.New("invalid cluster_id")
+	}
+	return report, nil
+}
+
+func (b *SystemBackend) handleFederationClusterList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ids, err := b.Core.systemBarrierView.List(ctx, federationClusterSubPath)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(ids)
+
+	now := time.Now()
+	keyInfo := make(map[string]interface{}, len(ids))
+	for _, id := range ids {
+		cluster, err := b.Core.federatedCluster(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if cluster == nil {
+			continue
+		}
+		keyInfo[id] = map[string]interface{}{
+			"cluster_name": cluster.Report.ClusterName,
+			"version":      cluster.Report.Version,
+			"last_report":  cluster.LastReport.Format(time.RFC3339),
+			"stale":        cluster.stale(now),
+		}
+	}
+	return logical.ListResponseWithInfo(ids, keyInfo), nil
+}
+
+func (b *SystemBackend) handleFederationClusterRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	cluster, err := b.Core.federatedCluster(ctx, d.Get("cluster_id").(string))
+	if err != nil || cluster == nil {
+		return nil, err
+	}
+	return &logical.Response{
+		Data: cluster.toMap(time.Now()),
+	}, nil
+}
+
+func (b *SystemBackend) handleFederationClusterDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if err := b.Core.systemBarrierView.Delete(ctx, federationClusterSubPath+d.Get("cluster_id").(string)); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (b *SystemBackend) handleFederationConfigRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	conf, err := b.Core.loadFederationConfig(ctx)
+	if err != nil || conf == nil {
+		return nil, err
+	}
+
+	b.Core.federationLock.Lock()
+	lastReport, lastError := b.Core.federationLastReport, b.Core.federationLastError
+	b.Core.federationLock.Unlock()
+
+	data := map[string]interface{}{
+		"management_addr":   conf.ManagementAddr,
+		"report_interval":   int64(conf.ReportInterval.Seconds()),
+		"ca_cert":           conf.CACert,
+		"tls_server_name":   conf.TLSServer,
+		"last_report":       "",
+		"last_report_error": lastError,
+	}
+	if !lastReport.IsZero() {
+		data["last_report"] = lastReport.UTC().Format(time.RFC3339)
+	}
+	return &logical.Response{
+		Data: data,
+	}, nil
+}
+
+func (b *SystemBackend) handleFederationConfigWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	conf, err := b.Core.loadFederationConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if conf == nil {
+		conf = &FederationConfig{ReportInterval: defaultFederationReportInterval}
+	}
+
+	if addrRaw, ok := d.GetOk("management_addr"); ok {
+		conf.ManagementAddr = addrRaw.(string)
+	}
+	if tokenRaw, ok := d.GetOk("token"); ok {
+		conf.Token = tokenRaw.(string)
+	}
+	if intervalRaw, ok := d.GetOk("report_interval"); ok {
+		conf.ReportInterval = time.Duration(intervalRaw.(int)) * time.Second
+	}
+	if caCertRaw, ok := d.GetOk("ca_cert"); ok {
+		conf.CACert = caCertRaw.(string)
+	}
+	if serverNameRaw, ok := d.GetOk("tls_server_name"); ok {
+		conf.TLSServer = serverNameRaw.(string)
+	}
+
+	addr, err := url.Parse(conf.ManagementAddr)
+	switch {
+	case conf.ManagementAddr == "":
+		return logical.ErrorResponse("management_addr is required"), logical.ErrInvalidRequest
+	case err != nil || (addr.Scheme != "http" && addr.Scheme != "https") || addr.Host == "":
+		return logical.ErrorResponse("management_addr must be an http or https URL"), logical.ErrInvalidRequest
+	case !strings.HasPrefix(conf.Token, federationTokenPrefix):
+		return logical.ErrorResponse("token must be a federation token issued by the management cluster"), logical.ErrInvalidRequest
+	case conf.ReportInterval < time.Minute:
+		return logical.ErrorResponse("report_interval must be at least one minute"), logical.ErrInvalidRequest
+	}
+
+	entry, err := logical.StorageEntryJSON(federationConfigPath, conf)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.Core.systemBarrierView.Put(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	// Report with the new configuration without waiting for the interval
+	b.Core.resetFederationReports()
+	return nil, nil
+}
+
+func (b *SystemBackend) handleFederationConfigDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if err := b.Core.systemBarrierView.Delete(ctx, federationConfigPath); err != nil {
+		return nil, err
+	}
+	b.Core.resetFederationReports()
+	return nil, nil
+}
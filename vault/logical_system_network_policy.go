@@ -0,0 +1,226 @@
+package vault
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-secure-stdlib/parseutil"
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/policyutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+func (b *SystemBackend) networkPolicyPaths() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "network-policy/?$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "network-policies",
+				OperationVerb:   "list",
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback: b.handleNetworkPolicyList,
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"keys": {
+									Type:     framework.TypeStringSlice,
+									Required: true,
+								},
+							},
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["network-policy"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["network-policy"][1]),
+		},
+
+		{
+			Pattern: "network-policy/" + framework.GenericNameRegex("name") + "$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "network-policies",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the network policy.",
+				},
+				"cidrs": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "CIDR blocks from which requests are allowed. If empty, requests are allowed from any address.",
+				},
+				"days": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "Days of the week on which requests are allowed, such as monday or mon. If empty, requests are allowed on any day.",
+				},
+				"window_start": {
+					Type:        framework.TypeString,
+					Description: "Start of the daily window in which requests are allowed, as HH:MM.",
+				},
+				"window_end": {
+					Type:        framework.TypeString,
+					Description: "End of the daily window in which requests are allowed, as HH:MM. The window spans midnight if it is before window_start.",
+				},
+				"timezone": {
+					Type:        framework.TypeString,
+					Description: "IANA time zone in which days and windows are evaluated, such as Europe/Paris. Defaults to UTC.",
+				},
+				"policies": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "The network policy applies to tokens carrying any of these policies.",
+				},
+				"mount_accessors": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "The network policy applies to tokens issued by any of these auth methods.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleNetworkPolicyRead,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "read",
+					},
+					Summary: "Read a network policy.",
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleNetworkPolicyWrite,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "write",
+					},
+					Summary: "Create or update a network policy.",
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.handleNetworkPolicyDelete,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "delete",
+					},
+					Summary: "Delete a network policy.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["network-policy-name"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["network-policy-name"][1]),
+		},
+	}
+}
+
+func (b *SystemBackend) handleNetworkPolicyList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	policies, err := b.Core.loadNetworkPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(policies))
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return logical.ListResponse(names), nil
+}
+
+func (b *SystemBackend) handleNetworkPolicyRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	policies, err := b.Core.loadNetworkPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, ok := policies[d.Get("name").(string)]
+	if !ok {
+		return nil, nil
+	}
+	return &logical.Response{
+		Data: policy.toMap(),
+	}, nil
+}
+
+func (b *SystemBackend) handleNetworkPolicyWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+
+	policies, err := b.Core.loadNetworkPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Start from a copy of the existing policy, as the cached one is in use
+	policy := &NetworkPolicy{Name: name}
+	if existing, ok := policies[name]; ok {
+		*policy = *existing
+	}
+
+	if cidrsRaw, ok := d.GetOk("cidrs"); ok {
+		policy.CIDRs, err = parseutil.ParseAddrs(cidrsRaw.([]string))
+		if err != nil {
+			return logical.ErrorResponse("invalid cidrs: %s", err), logical.ErrInvalidRequest
+		}
+	}
+	if daysRaw, ok := d.GetOk("days"); ok {
+		policy.Days = nil
+		for _, s := range daysRaw.([]string) {
+			day, err := parseNetworkPolicyDay(s)
+			if err != nil {
+				return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+			}
+			policy.Days = append(policy.Days, day)
+		}
+	}
+	if windowStartRaw, ok := d.GetOk("window_start"); ok {
+		policy.WindowStart = windowStartRaw.(string)
+	}
+	if windowEndRaw, ok := d.GetOk("window_end"); ok {
+		policy.WindowEnd = windowEndRaw.(string)
+	}
+	if timezoneRaw, ok := d.GetOk("timezone"); ok {
+		policy.Timezone = timezoneRaw.(string)
+	}
+	if policiesRaw, ok := d.GetOk("policies"); ok {
+		policy.Policies = policyutil.SanitizePolicies(policiesRaw.([]string), policyutil.DoNotAddDefaultPolicy)
+	}
+	if accessorsRaw, ok := d.GetOk("mount_accessors"); ok {
+		policy.MountAccessors = accessorsRaw.([]string)
+		for _, accessor := range policy.MountAccessors {
+			entry := b.Core.router.MatchingMountByAccessor(accessor)
+			if entry == nil || entry.Table != credentialTableType {
+				return logical.ErrorResponse("no auth method found with accessor %q", accessor), logical.ErrInvalidRequest
+			}
+		}
+	}
+
+	if err := policy.init(); err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	// Warn if the policy would deny the token configuring it
+	resp := &logical.Response{}
+	if te := req.TokenEntry(); te != nil && b.Core.networkPolicyApplies(policy, te.Policies, te.Path) {
+		if !policy.allows(networkPolicyAddr(req.Connection), time.Now()) {
+			resp.AddWarning("This network policy applies to the token used to write it, and denies its current requests.")
+		}
+	}
+
+	if err := b.Core.putNetworkPolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+	if len(resp.Warnings) == 0 {
+		return nil, nil
+	}
+	return resp, nil
+}
+
+func (b *SystemBackend) handleNetworkPolicyDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if err := b.Core.deleteNetworkPolicy(ctx, d.Get("name").(string)); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
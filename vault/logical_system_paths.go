@@ -252,6 +252,104 @@ func (b *SystemBackend) configPaths() []*framework.Path {
 			HelpSynopsis:    strings.TrimSpace(sysHelp["config/ui/headers"][1]),
 		},
 
+		{
+			Pattern: "config/cache$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "cache",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"size": {
+					Type:        framework.TypeInt,
+					Description: "The number of entries held by the physical cache. If not set, the cache_size of the server configuration is used.",
+				},
+				"bypass_prefixes": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "A comma-separated string or array of strings indicating the prefixes of the storage keys that are not cached.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleCacheConfigRead,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationSuffix: "configuration",
+					},
+					Summary: "Return the current physical cache settings.",
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"size": {
+									Type:     framework.TypeInt,
+									Required: true,
+								},
+								"bypass_prefixes": {
+									Type:     framework.TypeCommaStringSlice,
+									Required: true,
+								},
+							},
+						}},
+					},
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleCacheConfigUpdate,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "configure",
+					},
+					Summary: "Configure the physical cache settings.",
+					Responses: map[int][]framework.Response{
+						http.StatusNoContent: {{
+							Description: "OK",
+						}},
+					},
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.handleCacheConfigDelete,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb:   "delete",
+						OperationSuffix: "configuration",
+					},
+					Summary: "Reset the physical cache settings to the server configuration.",
+					Responses: map[int][]framework.Response{
+						http.StatusNoContent: {{
+							Description: "OK",
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["config/cache"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["config/cache"][1]),
+		},
+
+		{
+			Pattern: "config/schema$",
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleConfigSchemaRead,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb:   "read",
+						OperationSuffix: "configuration-schema",
+					},
+					Summary:     "Return the schema of the OpenBao server configuration.",
+					Description: "The schema lists the keys and blocks accepted in the server configuration files, with the type of their values. It is the schema the configuration is checked against by the server -validate-config flag.",
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"fields": {
+									Type:     framework.TypeSlice,
+									Required: true,
+								},
+							},
+						}},
+					},
+				},
+			},
+		},
+
 		{
 			Pattern: "generate-root(/attempt)?$",
 
@@ -466,6 +564,38 @@ func (b *SystemBackend) configPaths() []*framework.Path {
 			HelpSynopsis:    strings.TrimSpace(sysHelp["generate-root"][0]),
 			HelpDescription: strings.TrimSpace(sysHelp["generate-root"][1]),
 		},
+		{
+			Pattern: "generate-root/history/?$",
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback: b.handleGenerateRootHistory,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationPrefix: "root-token-generation",
+						OperationVerb:   "list",
+						OperationSuffix: "history",
+					},
+					Summary: "List the recorded root generation attempts and their participants.",
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"keys": {
+									Type:     framework.TypeCommaStringSlice,
+									Required: true,
+								},
+								"key_info": {
+									Type:     framework.TypeMap,
+									Required: true,
+								},
+							},
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["generate-root-history"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["generate-root-history"][1]),
+		},
 		{
 			Pattern: "decode-token$",
 			Fields: map[string]*framework.FieldSchema{
@@ -765,6 +895,9 @@ func (b *SystemBackend) rekeyPaths() []*framework.Path {
 		"pgp_fingerprints": {
 			Type: framework.TypeCommaStringSlice,
 		},
+		"totp_shares": {
+			Type: framework.TypeBool,
+		},
 	}
 
 	return []*framework.Path{
@@ -796,6 +929,10 @@ func (b *SystemBackend) rekeyPaths() []*framework.Path {
 					Type:        framework.TypeBool,
 					Description: "Turns on verification functionality",
 				},
+				"totp_shares": {
+					Type:        framework.TypeBool,
+					Description: "Specifies whether a TOTP secret is enrolled for each new unseal key share. The shares are then only accepted during unseal along with their current TOTP code.",
+				},
 			},
 
 			Operations: map[logical.Operation]framework.OperationHandler{
@@ -1014,6 +1151,9 @@ func (b *SystemBackend) rekeyPaths() []*framework.Path {
 								"pgp_fingerprints": {
 									Type: framework.TypeCommaStringSlice,
 								},
+								"totp_urls": {
+									Type: framework.TypeCommaStringSlice,
+								},
 							},
 						}},
 					},
@@ -1168,6 +1308,10 @@ func (b *SystemBackend) rekeyPaths() []*framework.Path {
 					Type:        framework.TypeBool,
 					Description: "Specifies if previously-provided unseal keys are discarded and the unseal process is reset.",
 				},
+				"totp": {
+					Type:        framework.TypeString,
+					Description: "Specifies the current TOTP code of the unseal key share. This is required if the shares were enrolled for TOTP when rekeying.",
+				},
 			},
 
 			Operations: map[logical.Operation]framework.OperationHandler{
@@ -2090,6 +2234,13 @@ func (b *SystemBackend) toolsPaths() []*framework.Path {
 			* sha2-256
 			* sha2-384
 			* sha2-512
+			* sha3-224
+			* sha3-256
+			* sha3-384
+			* sha3-512
+			* blake2b-256
+			* blake2b-384
+			* blake2b-512
 
 			Defaults to "sha2-256".`,
 				},
@@ -2102,7 +2253,18 @@ func (b *SystemBackend) toolsPaths() []*framework.Path {
 				"format": {
 					Type:        framework.TypeString,
 					Default:     "hex",
-					Description: `Encoding format to use. Can be "hex" or "base64". Defaults to "hex".`,
+					Description: `Encoding format to use. Can be "hex", "base64" or "base64url". Defaults to "hex".`,
+				},
+
+				"state": {
+					Type:        framework.TypeString,
+					Description: "The state returned for the previous chunk of the input, when hashing the input in chunks.",
+				},
+
+				"final": {
+					Type:        framework.TypeBool,
+					Default:     true,
+					Description: `Whether the input is the last chunk of the input. If false, the state to send along with the next chunk is returned instead of the sum. Defaults to true.`,
 				},
 			},
 
@@ -2114,8 +2276,12 @@ func (b *SystemBackend) toolsPaths() []*framework.Path {
 							Description: "OK",
 							Fields: map[string]*framework.FieldSchema{
 								"sum": {
-									Type:     framework.TypeString,
-									Required: true,
+									Type:        framework.TypeString,
+									Description: "The hash sum, returned for the last chunk of the input",
+								},
+								"state": {
+									Type:        framework.TypeString,
+									Description: "The state to send along with the next chunk of the input, returned when final is false",
 								},
 							},
 						}},
@@ -2150,7 +2316,7 @@ func (b *SystemBackend) toolsPaths() []*framework.Path {
 				"format": {
 					Type:        framework.TypeString,
 					Default:     "base64",
-					Description: `Encoding format to use. Can be "hex" or "base64". Defaults to "base64".`,
+					Description: `Encoding format to use. Can be "hex", "base64" or "base64url". Defaults to "base64".`,
 				},
 
 				"source": {
@@ -2204,6 +2370,12 @@ func (b *SystemBackend) internalPaths() []*framework.Path {
 					Query:       true,
 					Default:     false,
 				},
+				"filter_by_capabilities": {
+					Type:        framework.TypeBool,
+					Description: "Only include the operations the requesting token is able to perform",
+					Query:       true,
+					Default:     false,
+				},
 			},
 
 			Operations: map[logical.Operation]framework.OperationHandler{
@@ -2487,6 +2659,33 @@ func (b *SystemBackend) internalPaths() []*framework.Path {
 			HelpSynopsis:    strings.TrimSpace(sysHelp["internal-counters-tokens"][0]),
 			HelpDescription: strings.TrimSpace(sysHelp["internal-counters-tokens"][1]),
 		},
+		{
+			Pattern: "internal/counters/cache",
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "internal",
+				OperationVerb:   "count",
+				OperationSuffix: "cache",
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.pathInternalCountersCache,
+					Summary:  "Backwards compatibility is not guaranteed for this API",
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"counters": {
+									Type:     framework.TypeMap,
+									Required: true,
+								},
+							},
+						}},
+					},
+				},
+			},
+			HelpSynopsis:    strings.TrimSpace(sysHelp["internal-counters-cache"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["internal-counters-cache"][1]),
+		},
 		{
 			Pattern: "internal/counters/entities",
 			DisplayAttrs: &framework.DisplayAttributes{
@@ -2514,6 +2713,59 @@ func (b *SystemBackend) internalPaths() []*framework.Path {
 			HelpSynopsis:    strings.TrimSpace(sysHelp["internal-counters-entities"][0]),
 			HelpDescription: strings.TrimSpace(sysHelp["internal-counters-entities"][1]),
 		},
+		{
+			Pattern: "internal/counters/engine-usage",
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "internal",
+				OperationVerb:   "count",
+				OperationSuffix: "engine-usage",
+			},
+			Fields: map[string]*framework.FieldSchema{
+				"start_date": {
+					Type:        framework.TypeString,
+					Description: "First day of the report, as YYYY-MM-DD in UTC. Defaults to 29 days before end_date.",
+					Query:       true,
+				},
+				"end_date": {
+					Type:        framework.TypeString,
+					Description: "Last day of the report, as YYYY-MM-DD in UTC. Defaults to today.",
+					Query:       true,
+				},
+				"top": {
+					Type:        framework.TypeInt,
+					Default:     10,
+					Description: "Number of top consumers to return.",
+					Query:       true,
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.pathInternalCountersEngineUsage,
+					Summary:  "Backwards compatibility is not guaranteed for this API",
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"start_date": {
+									Type:     framework.TypeString,
+									Required: true,
+								},
+								"end_date": {
+									Type:     framework.TypeString,
+									Required: true,
+								},
+								"counters": {
+									Type:     framework.TypeMap,
+									Required: true,
+								},
+							},
+						}},
+					},
+				},
+			},
+			HelpSynopsis:    strings.TrimSpace(sysHelp["internal-counters-engine-usage"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["internal-counters-engine-usage"][1]),
+		},
 	}
 }
 
@@ -2568,6 +2820,10 @@ func (b *SystemBackend) capabilitiesPaths() []*framework.Path {
 					Type:        framework.TypeCommaStringSlice,
 					Description: "Paths on which capabilities are being queried.",
 				},
+				"explain": {
+					Type:        framework.TypeBool,
+					Description: "Also return the policies granting or denying capabilities on each path.",
+				},
 			},
 
 			Operations: map[logical.Operation]framework.OperationHandler{
@@ -2609,6 +2865,10 @@ func (b *SystemBackend) capabilitiesPaths() []*framework.Path {
 					Type:        framework.TypeCommaStringSlice,
 					Description: "Paths on which capabilities are being queried.",
 				},
+				"explain": {
+					Type:        framework.TypeBool,
+					Description: "Also return the policies granting or denying capabilities on each path.",
+				},
 			},
 
 			Operations: map[logical.Operation]framework.OperationHandler{
@@ -2650,6 +2910,10 @@ func (b *SystemBackend) capabilitiesPaths() []*framework.Path {
 					Type:        framework.TypeCommaStringSlice,
 					Description: "Paths on which capabilities are being queried.",
 				},
+				"explain": {
+					Type:        framework.TypeBool,
+					Description: "Also return the policies granting or denying capabilities on each path.",
+				},
 			},
 
 			Operations: map[logical.Operation]framework.OperationHandler{
@@ -2998,6 +3262,182 @@ func (b *SystemBackend) leasePaths() []*framework.Path {
 			HelpDescription: strings.TrimSpace(sysHelp["count-leases"][1]),
 		},
 
+		{
+			Pattern: "leases/revocation-queue$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "leases",
+				OperationVerb:   "read",
+				OperationSuffix: "revocation-queue",
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleLeaseRevocationQueue,
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"pending": {
+									Type:        framework.TypeInt,
+									Description: "Number of revocations waiting for a worker",
+									Required:    true,
+								},
+								"pending_by_priority": {
+									Type:        framework.TypeMap,
+									Description: "Number of revocations waiting for a worker, by priority",
+									Required:    true,
+								},
+								"revoked": {
+									Type:        framework.TypeInt64,
+									Description: "Number of revocations that succeeded",
+									Required:    true,
+								},
+								"failed": {
+									Type:        framework.TypeInt64,
+									Description: "Number of revocations that failed",
+									Required:    true,
+								},
+								"rate_limit": {
+									Type:        framework.TypeInt64,
+									Description: "Maximum number of revocations started per second, zero meaning no limit",
+									Required:    true,
+								},
+								"time": {
+									Type:        framework.TypeTime,
+									Description: "Time of the status",
+									Required:    true,
+								},
+								"checkpoint": {
+									Type:        framework.TypeMap,
+									Description: "Last persisted checkpoint",
+									Required:    false,
+								},
+							},
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["revocation-queue-leases"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["revocation-queue-leases"][1]),
+		},
+
+		{
+			Pattern: "leases/irrevocable$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "leases",
+				OperationVerb:   "read",
+				OperationSuffix: "irrevocable",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"include_child_namespaces": {
+					Type:        framework.TypeBool,
+					Default:     false,
+					Description: "Set true if you want irrevocable leases of this namespace and its children.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleLeaseIrrevocableRead,
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"lease_count": {
+									Type:        framework.TypeInt,
+									Description: "Number of irrevocable leases",
+									Required:    true,
+								},
+								"mounts": {
+									Type:        framework.TypeMap,
+									Description: "Number of irrevocable leases and revocation errors, by mount accessor",
+									Required:    true,
+								},
+							},
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["irrevocable-leases"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["irrevocable-leases"][1]),
+		},
+
+		{
+			Pattern: "leases/irrevocable/revoke-force$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "leases",
+				OperationVerb:   "force-revoke",
+				OperationSuffix: "irrevocable",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"mount_accessor": {
+					Type:        framework.TypeString,
+					Description: "Only revoke the irrevocable leases of the mount with this accessor.",
+				},
+				"error_contains": {
+					Type:        framework.TypeString,
+					Description: "Only revoke the irrevocable leases whose revocation error contains this string.",
+				},
+				"include_child_namespaces": {
+					Type:        framework.TypeBool,
+					Default:     false,
+					Description: "Set true to also revoke the irrevocable leases of the child namespaces.",
+				},
+				"dry_run": {
+					Type:        framework.TypeBool,
+					Default:     true,
+					Description: "If true, the matching leases are returned without being revoked.",
+				},
+				"confirm_count": {
+					Type:        framework.TypeInt,
+					Description: "Number of leases expected to be revoked, as returned by a dry run. Required unless dry_run is true.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleLeaseIrrevocableRevokeForce,
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"lease_count": {
+									Type:        framework.TypeInt,
+									Description: "Number of matching leases",
+									Required:    true,
+								},
+								"leases": {
+									Type:        framework.TypeSlice,
+									Description: "Matching leases, only returned on dry runs",
+									Required:    false,
+								},
+								"revoked": {
+									Type:        framework.TypeInt,
+									Description: "Number of revoked leases",
+									Required:    false,
+								},
+								"failed": {
+									Type:        framework.TypeMap,
+									Description: "Errors of the leases that could not be revoked, by lease ID",
+									Required:    false,
+								},
+							},
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["revoke-force-irrevocable-leases"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["revoke-force-irrevocable-leases"][1]),
+		},
+
 		{
 			Pattern: "leases$",
 
@@ -3186,6 +3626,26 @@ func (b *SystemBackend) monitorPath() *framework.Path {
 				Query:       true,
 				Default:     "standard",
 			},
+			"subsystems": {
+				Type:        framework.TypeCommaStringSlice,
+				Description: "If set, only stream the logs of these subsystems and of their sub-loggers, such as \"core\" or \"storage.raft\".",
+				Query:       true,
+			},
+			"exclude_subsystems": {
+				Type:        framework.TypeCommaStringSlice,
+				Description: "Do not stream the logs of these subsystems and of their sub-loggers.",
+				Query:       true,
+			},
+			"regex": {
+				Type:        framework.TypeString,
+				Description: "If set, only stream the log messages matching this regular expression.",
+				Query:       true,
+			},
+			"heartbeat_interval": {
+				Type:        framework.TypeDurationSecond,
+				Description: "If set, the interval at which a heartbeat line is sent over the stream. Requires the \"json\" log format.",
+				Query:       true,
+			},
 		},
 		Operations: map[logical.Operation]framework.OperationHandler{
 			logical.ReadOperation: &framework.PathOperation{
@@ -3345,6 +3805,22 @@ func (b *SystemBackend) authPaths() []*framework.Path {
 					Type:        framework.TypeString,
 					Description: strings.TrimSpace(sysHelp["listing_visibility"][0]),
 				},
+				"protected": {
+					Type:        framework.TypeBool,
+					Description: strings.TrimSpace(sysHelp["protected"][0]),
+				},
+				"default_request_timeout": {
+					Type:        framework.TypeDurationSecond,
+					Description: strings.TrimSpace(sysHelp["default_request_timeout"][0]),
+				},
+				"max_request_size": {
+					Type:        framework.TypeInt64,
+					Description: strings.TrimSpace(sysHelp["max_request_size"][0]),
+				},
+				"max_concurrent_requests": {
+					Type:        framework.TypeInt,
+					Description: strings.TrimSpace(sysHelp["max_concurrent_requests"][0]),
+				},
 				"passthrough_request_headers": {
 					Type:        framework.TypeCommaStringSlice,
 					Description: strings.TrimSpace(sysHelp["passthrough_request_headers"][0]),
@@ -3357,6 +3833,10 @@ func (b *SystemBackend) authPaths() []*framework.Path {
 					Type:        framework.TypeString,
 					Description: strings.TrimSpace(sysHelp["token_type"][0]),
 				},
+				"token_bind_client_cert": {
+					Type:        framework.TypeBool,
+					Description: strings.TrimSpace(sysHelp["token_bind_client_cert"][0]),
+				},
 				"user_lockout_config": {
 					Type:        framework.TypeMap,
 					Description: strings.TrimSpace(sysHelp["tune_user_lockout_config"][0]),
@@ -3403,6 +3883,10 @@ func (b *SystemBackend) authPaths() []*framework.Path {
 									Type:     framework.TypeString,
 									Required: false,
 								},
+								"token_bind_client_cert": {
+									Type:     framework.TypeBool,
+									Required: false,
+								},
 								"audit_non_hmac_request_keys": {
 									Type:     framework.TypeCommaStringSlice,
 									Required: false,
@@ -3415,6 +3899,22 @@ func (b *SystemBackend) authPaths() []*framework.Path {
 									Type:     framework.TypeString,
 									Required: false,
 								},
+								"protected": {
+									Type:     framework.TypeBool,
+									Required: false,
+								},
+								"default_request_timeout": {
+									Type:     framework.TypeInt,
+									Required: false,
+								},
+								"max_request_size": {
+									Type:     framework.TypeInt64,
+									Required: false,
+								},
+								"max_concurrent_requests": {
+									Type:     framework.TypeInt,
+									Required: false,
+								},
 								"passthrough_request_headers": {
 									Type:     framework.TypeCommaStringSlice,
 									Required: false,
@@ -4193,6 +4693,26 @@ func (b *SystemBackend) mountPaths() []*framework.Path {
 					Type:        framework.TypeString,
 					Description: strings.TrimSpace(sysHelp["listing_visibility"][0]),
 				},
+				"protected": {
+					Type:        framework.TypeBool,
+					Description: strings.TrimSpace(sysHelp["protected"][0]),
+				},
+				"default_request_timeout": {
+					Type:        framework.TypeDurationSecond,
+					Description: strings.TrimSpace(sysHelp["default_request_timeout"][0]),
+				},
+				"max_request_size": {
+					Type:        framework.TypeInt64,
+					Description: strings.TrimSpace(sysHelp["max_request_size"][0]),
+				},
+				"max_concurrent_requests": {
+					Type:        framework.TypeInt,
+					Description: strings.TrimSpace(sysHelp["max_concurrent_requests"][0]),
+				},
+				"lazy_load": {
+					Type:        framework.TypeBool,
+					Description: strings.TrimSpace(sysHelp["lazy_load"][0]),
+				},
 				"passthrough_request_headers": {
 					Type:        framework.TypeCommaStringSlice,
 					Description: strings.TrimSpace(sysHelp["passthrough_request_headers"][0]),
@@ -4205,6 +4725,10 @@ func (b *SystemBackend) mountPaths() []*framework.Path {
 					Type:        framework.TypeString,
 					Description: strings.TrimSpace(sysHelp["token_type"][0]),
 				},
+				"token_bind_client_cert": {
+					Type:        framework.TypeBool,
+					Description: strings.TrimSpace(sysHelp["token_bind_client_cert"][0]),
+				},
 				"allowed_managed_keys": {
 					Type:        framework.TypeCommaStringSlice,
 					Description: strings.TrimSpace(sysHelp["tune_allowed_managed_keys"][0]),
@@ -4254,6 +4778,11 @@ func (b *SystemBackend) mountPaths() []*framework.Path {
 									Description: strings.TrimSpace(sysHelp["token_type"][0]),
 									Required:    false,
 								},
+								"token_bind_client_cert": {
+									Type:        framework.TypeBool,
+									Description: strings.TrimSpace(sysHelp["token_bind_client_cert"][0]),
+									Required:    false,
+								},
 								"allowed_managed_keys": {
 									Type:        framework.TypeCommaStringSlice,
 									Description: strings.TrimSpace(sysHelp["tune_allowed_managed_keys"][0]),
@@ -4290,6 +4819,26 @@ func (b *SystemBackend) mountPaths() []*framework.Path {
 									Type:     framework.TypeString,
 									Required: false,
 								},
+								"protected": {
+									Type:     framework.TypeBool,
+									Required: false,
+								},
+								"default_request_timeout": {
+									Type:     framework.TypeInt,
+									Required: false,
+								},
+								"max_request_size": {
+									Type:     framework.TypeInt64,
+									Required: false,
+								},
+								"max_concurrent_requests": {
+									Type:     framework.TypeInt,
+									Required: false,
+								},
+								"lazy_load": {
+									Type:     framework.TypeBool,
+									Required: false,
+								},
 								"passthrough_request_headers": {
 									Type:     framework.TypeCommaStringSlice,
 									Required: false,
@@ -4332,6 +4881,36 @@ func (b *SystemBackend) mountPaths() []*framework.Path {
 			HelpDescription: strings.TrimSpace(sysHelp["mount_tune"][1]),
 		},
 
+		{
+			Pattern: "mounts/(?P<path>.+?)/warm-up$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "mounts",
+				OperationVerb:   "warm-up",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"path": {
+					Type:        framework.TypeString,
+					Description: strings.TrimSpace(sysHelp["mount_path"][0]),
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleMountWarmUp,
+					Responses: map[int][]framework.Response{
+						http.StatusNoContent: {{
+							Description: "OK",
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["mount_warm_up"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["mount_warm_up"][1]),
+		},
+
 		{
 			Pattern: "mounts/(?P<path>.+?)",
 
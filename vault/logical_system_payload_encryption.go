@@ -0,0 +1,345 @@
+package vault
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/mitchellh/mapstructure"
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/jsonutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// payloadEncryptionKeysPath is the path in the system view of the keys
+	// request payloads are encrypted to.
+	payloadEncryptionKeysPath = "payload-encryption/keys"
+
+	// PayloadEncryptionAlgorithm names the scheme request payloads are
+	// encrypted with: an ephemeral X25519 key agreement with the published
+	// key, HKDF-SHA256 key derivation and AES-256-GCM.
+	PayloadEncryptionAlgorithm = "x25519-hkdf-sha256-aes256gcm"
+
+	// payloadEncryptionInfo is the HKDF info string of the scheme.
+	payloadEncryptionInfo = "openbao payload encryption"
+
+	// payloadEncryptionMaxKeys is the number of keys kept, the current key
+	// and the previous ones, so that payloads encrypted to a key published
+	// before a rotation can still be decrypted.
+	payloadEncryptionMaxKeys = 2
+)
+
+// payloadEncryptionKey is a key request payloads are encrypted to.
+type payloadEncryptionKey struct {
+	ID           string    `json:"id"`
+	PrivateKey   []byte    `json:"private_key"`
+	CreationTime time.Time `json:"creation_time"`
+}
+
+// payloadEncryptionKeyring holds the keys request payloads are encrypted
+// to, the current key first.
+type payloadEncryptionKeyring struct {
+	Keys []*payloadEncryptionKey `json:"keys"`
+}
+
+// payloadEnvelope is the body of a request whose payload is encrypted.
+type payloadEnvelope struct {
+	KeyID              string `mapstructure:"key_id"`
+	EphemeralPublicKey string `mapstructure:"ephemeral_public_key"`
+	Nonce              string `mapstructure:"nonce"`
+	Ciphertext         string `mapstructure:"ciphertext"`
+}
+
+func (b *SystemBackend) payloadEncryptionPaths() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "payload-encryption/key$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "payload-encryption",
+				OperationVerb:   "read",
+				OperationSuffix: "key",
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handlePayloadEncryptionKeyRead,
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"key_id": {
+									Type:     framework.TypeString,
+									Required: true,
+								},
+								"algorithm": {
+									Type:     framework.TypeString,
+									Required: true,
+								},
+								"public_key": {
+									Type:     framework.TypeString,
+									Required: true,
+								},
+								"creation_time": {
+									Type:     framework.TypeTime,
+									Required: true,
+								},
+							},
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["payload-encryption/key"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["payload-encryption/key"][1]),
+		},
+
+		{
+			Pattern: "payload-encryption/rotate$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "payload-encryption",
+				OperationVerb:   "rotate",
+				OperationSuffix: "key",
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handlePayloadEncryptionRotate,
+					Summary:  "Rotate the key request payloads are encrypted to.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["payload-encryption/rotate"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["payload-encryption/rotate"][1]),
+		},
+	}
+}
+
+func (b *SystemBackend) handlePayloadEncryptionKeyRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	keyring, err := b.Core.payloadEncryptionKeyring(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return payloadEncryptionKeyResponse(keyring.Keys[0])
+}
+
+func (b *SystemBackend) handlePayloadEncryptionRotate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	key, err := b.Core.rotatePayloadEncryptionKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b.Core.logger.Info("rotated payload encryption key", "key_id", key.ID)
+	return payloadEncryptionKeyResponse(key)
+}
+
+func payloadEncryptionKeyResponse(key *payloadEncryptionKey) (*logical.Response, error) {
+	privateKey, err := ecdh.X25519().NewPrivateKey(key.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"key_id":        key.ID,
+			"algorithm":     PayloadEncryptionAlgorithm,
+			"public_key":    base64.StdEncoding.EncodeToString(privateKey.PublicKey().Bytes()),
+			"creation_time": key.CreationTime,
+		},
+	}, nil
+}
+
+// payloadEncryptionKeyring returns the keys request payloads are encrypted
+// to, generating the first key on first use.
+func (c *Core) payloadEncryptionKeyring(ctx context.Context) (*payloadEncryptionKeyring, error) {
+	keyring, err := c.loadPayloadEncryptionKeyring(ctx)
+	if err != nil || keyring != nil {
+		return keyring, err
+	}
+
+	c.payloadEncryptionKeyLock.Lock()
+	defer c.payloadEncryptionKeyLock.Unlock()
+
+	// Another request may have generated the key in the meantime
+	keyring, err = c.loadPayloadEncryptionKeyring(ctx)
+	if err != nil || keyring != nil {
+		return keyring, err
+	}
+
+	keyring = &payloadEncryptionKeyring{}
+	if _, err := c.addPayloadEncryptionKey(ctx, keyring); err != nil {
+		return nil, err
+	}
+	return keyring, nil
+}
+
+// rotatePayloadEncryptionKey generates a new current key, keeping the
+// previous key to decrypt the payloads encrypted to it before the rotation.
+func (c *Core) rotatePayloadEncryptionKey(ctx context.Context) (*payloadEncryptionKey, error) {
+	c.payloadEncryptionKeyLock.Lock()
+	defer c.payloadEncryptionKeyLock.Unlock()
+
+	keyring, err := c.loadPayloadEncryptionKeyring(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if keyring == nil {
+		keyring = &payloadEncryptionKeyring{}
+	}
+	return c.addPayloadEncryptionKey(ctx, keyring)
+}
+
+func (c *Core) loadPayloadEncryptionKeyring(ctx context.Context) (*payloadEncryptionKeyring, error) {
+	entry, err := c.systemBarrierView.Get(ctx, payloadEncryptionKeysPath)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var keyring payloadEncryptionKeyring
+	if err := entry.DecodeJSON(&keyring); err != nil {
+		return nil, err
+	}
+	if len(keyring.Keys) == 0 {
+		return nil, nil
+	}
+	return &keyring, nil
+}
+
+// addPayloadEncryptionKey generates a key, makes it the current key of the
+// keyring and persists the keyring. The lock must be held.
+func (c *Core) addPayloadEncryptionKey(ctx context.Context, keyring *payloadEncryptionKeyring) (*payloadEncryptionKey, error) {
+	privateKey, err := ecdh.X25519().GenerateKey(c.secureRandomReader)
+	if err != nil {
+		return nil, err
+	}
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	key := &payloadEncryptionKey{
+		ID:           id,
+		PrivateKey:   privateKey.Bytes(),
+		CreationTime: time.Now().UTC(),
+	}
+	keyring.Keys = append([]*payloadEncryptionKey{key}, keyring.Keys...)
+	if len(keyring.Keys) > payloadEncryptionMaxKeys {
+		keyring.Keys = keyring.Keys[:payloadEncryptionMaxKeys]
+	}
+
+	entry, err := logical.StorageEntryJSON(payloadEncryptionKeysPath, keyring)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.systemBarrierView.Put(ctx, entry); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// DecryptRequestPayload decrypts the data of a request sent as an encrypted
+// payload envelope, returning the request data. The request path is the
+// additional data of the encryption, so a payload cannot be replayed to
+// another path.
+func (c *Core) DecryptRequestPayload(ctx context.Context, path string, data map[string]interface{}) (map[string]interface{}, error) {
+	var envelope payloadEnvelope
+	if err := mapstructure.Decode(data, &envelope); err != nil {
+		return nil, fmt.Errorf("invalid payload envelope: %w", err)
+	}
+	if envelope.KeyID == "" || envelope.EphemeralPublicKey == "" || envelope.Nonce == "" || envelope.Ciphertext == "" {
+		return nil, errors.New("invalid payload envelope: key_id, ephemeral_public_key, nonce and ciphertext are required")
+	}
+
+	keyring, err := c.loadPayloadEncryptionKeyring(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var key *payloadEncryptionKey
+	if keyring != nil {
+		for _, k := range keyring.Keys {
+			if k.ID == envelope.KeyID {
+				key = k
+				break
+			}
+		}
+	}
+	if key == nil {
+		return nil, fmt.Errorf("unknown payload encryption key %q", envelope.KeyID)
+	}
+
+	ephemeralPublicKey, err := base64.StdEncoding.DecodeString(envelope.EphemeralPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ephemeral public key: %w", err)
+	}
+	nonce, err := base64.StdEncoding.DecodeString(envelope.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("invalid nonce: %w", err)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(envelope.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ciphertext: %w", err)
+	}
+
+	aead, err := payloadEncryptionAEAD(key.PrivateKey, ephemeralPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, errors.New("invalid nonce: wrong size")
+	}
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(path))
+	if err != nil {
+		return nil, errors.New("failed to decrypt payload")
+	}
+
+	var out map[string]interface{}
+	if err := jsonutil.DecodeJSON(plaintext, &out); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to parse decrypted payload: %w", err)
+	}
+	return out, nil
+}
+
+// payloadEncryptionAEAD derives the cipher of a payload from the key
+// agreement of the published key with the ephemeral key of the client.
+func payloadEncryptionAEAD(privateKeyBytes, ephemeralPublicKeyBytes []byte) (cipher.AEAD, error) {
+	privateKey, err := ecdh.X25519().NewPrivateKey(privateKeyBytes)
+	if err != nil {
+		return nil, err
+	}
+	ephemeralPublicKey, err := ecdh.X25519().NewPublicKey(ephemeralPublicKeyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ephemeral public key: %w", err)
+	}
+	shared, err := privateKey.ECDH(ephemeralPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ephemeral public key: %w", err)
+	}
+
+	// Both public keys salt the derivation, binding the cipher key to this
+	// exchange
+	salt := append(append([]byte{}, ephemeralPublicKeyBytes...), privateKey.PublicKey().Bytes()...)
+	cipherKey := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, shared, salt, []byte(payloadEncryptionInfo)), cipherKey); err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cipherKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
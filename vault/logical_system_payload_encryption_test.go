@@ -0,0 +1,108 @@
+package vault
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"testing"
+
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/hkdf"
+)
+
+// newPayloadEnvelope encrypts a payload to the published key, as clients
+// do. It is used by tests.
+func newPayloadEnvelope(keyID string, publicKeyBytes []byte, path string, payload []byte) (map[string]interface{}, error) {
+	publicKey, err := ecdh.X25519().NewPublicKey(publicKeyBytes)
+	if err != nil {
+		return nil, err
+	}
+	ephemeralKey, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := ephemeralKey.ECDH(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := append(append([]byte{}, ephemeralKey.PublicKey().Bytes()...), publicKeyBytes...)
+	cipherKey := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, shared, salt, []byte(payloadEncryptionInfo)), cipherKey); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(cipherKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"key_id":               keyID,
+		"ephemeral_public_key": base64.StdEncoding.EncodeToString(ephemeralKey.PublicKey().Bytes()),
+		"nonce":                base64.StdEncoding.EncodeToString(nonce),
+		"ciphertext":           base64.StdEncoding.EncodeToString(aead.Seal(nil, nonce, payload, []byte(path))),
+	}, nil
+}
+
+func TestSystemBackend_PayloadEncryption(t *testing.T) {
+	c, b, _ := testCoreSystemBackend(t)
+	ctx := namespace.RootContext(nil)
+
+	readKey := func(op logical.Operation, path string) (string, []byte) {
+		t.Helper()
+		resp, err := b.HandleRequest(ctx, logical.TestRequest(t, op, path))
+		require.NoError(t, err)
+		require.Equal(t, PayloadEncryptionAlgorithm, resp.Data["algorithm"])
+		publicKey, err := base64.StdEncoding.DecodeString(resp.Data["public_key"].(string))
+		require.NoError(t, err)
+		return resp.Data["key_id"].(string), publicKey
+	}
+
+	// The key is generated on first read and stable afterwards
+	keyID, publicKey := readKey(logical.ReadOperation, "payload-encryption/key")
+	sameID, _ := readKey(logical.ReadOperation, "payload-encryption/key")
+	require.Equal(t, keyID, sameID)
+
+	payload := []byte(`{"password":"hunter2","ttl":"1h"}`)
+	envelope, err := newPayloadEnvelope(keyID, publicKey, "/v1/secret/app", payload)
+	require.NoError(t, err)
+
+	data, err := c.DecryptRequestPayload(ctx, "/v1/secret/app", envelope)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"password": "hunter2", "ttl": "1h"}, data)
+
+	// The payload is bound to its path
+	_, err = c.DecryptRequestPayload(ctx, "/v1/secret/other", envelope)
+	require.Error(t, err)
+
+	_, err = c.DecryptRequestPayload(ctx, "/v1/secret/app", map[string]interface{}{"key_id": keyID})
+	require.Error(t, err)
+
+	// Payloads encrypted to the previous key are decrypted after a rotation,
+	// but not after a second one
+	newID, _ := readKey(logical.UpdateOperation, "payload-encryption/rotate")
+	require.NotEqual(t, keyID, newID)
+	currentID, _ := readKey(logical.ReadOperation, "payload-encryption/key")
+	require.Equal(t, newID, currentID)
+
+	_, err = c.DecryptRequestPayload(ctx, "/v1/secret/app", envelope)
+	require.NoError(t, err)
+
+	readKey(logical.UpdateOperation, "payload-encryption/rotate")
+	_, err = c.DecryptRequestPayload(ctx, "/v1/secret/app", envelope)
+	require.ErrorContains(t, err, "unknown payload encryption key")
+}
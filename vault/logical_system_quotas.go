@@ -149,6 +149,15 @@ The 'rate' must be positive.`,
 					Description: `If set, when a client reaches a rate limit threshold, the client will be prohibited
 from any further requests until after the 'block_interval' has elapsed.`,
 				},
+				"group_by": {
+					Type: framework.TypeString,
+					Description: `How requests are attributed to clients, each client being rate limited
+separately. One of 'ip' (the default), 'entity' or 'alias'. With 'entity' and
+'alias', requests are grouped by the identity entity of their token, or by its
+entity alias, falling back to the client IP address for requests without an
+entity.`,
+					AllowedValues: []interface{}{quotas.GroupByIP, quotas.GroupByEntity, quotas.GroupByAlias},
+				},
 			},
 			Operations: map[logical.Operation]framework.OperationHandler{
 				logical.UpdateOperation: &framework.PathOperation{
@@ -199,6 +208,10 @@ from any further requests until after the 'block_interval' has elapsed.`,
 									Type:     framework.TypeInt,
 									Required: true,
 								},
+								"group_by": {
+									Type:     framework.TypeString,
+									Required: true,
+								},
 							},
 						}},
 					},
@@ -300,6 +313,14 @@ func (b *SystemBackend) handleRateLimitQuotasUpdate() framework.OperationFunc {
 			return logical.ErrorResponse("'block' is invalid"), nil
 		}
 
+		groupBy := d.Get("group_by").(string)
+		if !quotas.ValidGroupBy(groupBy) {
+			return logical.ErrorResponse("'group_by' must be one of %q, %q or %q", quotas.GroupByIP, quotas.GroupByEntity, quotas.GroupByAlias), nil
+		}
+		if groupBy == quotas.GroupByIP {
+			groupBy = ""
+		}
+
 		mountPath := sanitizePath(d.Get("path").(string))
 		ns := namespace.RootNamespace
 		if ns.ID != namespace.RootNamespaceID {
@@ -356,7 +377,9 @@ func (b *SystemBackend) handleRateLimitQuotasUpdate() framework.OperationFunc {
 
 		switch {
 		case quota == nil:
-			quota = quotas.NewRateLimitQuota(name, ns.Path, mountPath, pathSuffix, role, rate, interval, blockInterval)
+			rlq := quotas.NewRateLimitQuota(name, ns.Path, mountPath, pathSuffix, role, rate, interval, blockInterval)
+			rlq.GroupBy = groupBy
+			quota = rlq
 		default:
 			// Re-inserting the already indexed object in memdb might cause problems.
 			// So, clone the object. See https://github.com/hashicorp/go-memdb/issues/76.
@@ -368,6 +391,7 @@ func (b *SystemBackend) handleRateLimitQuotasUpdate() framework.OperationFunc {
 			rlq.Rate = rate
 			rlq.Interval = interval
 			rlq.BlockInterval = blockInterval
+			rlq.GroupBy = groupBy
 			quota = rlq
 		}
 
@@ -416,6 +440,10 @@ func (b *SystemBackend) handleRateLimitQuotasRead() framework.OperationFunc {
 			"rate":           rlq.Rate,
 			"interval":       int(rlq.Interval.Seconds()),
 			"block_interval": int(rlq.BlockInterval.Seconds()),
+			"group_by":       quotas.GroupByIP,
+		}
+		if rlq.GroupBy != "" {
+			data["group_by"] = rlq.GroupBy
 		}
 
 		return &logical.Response{
@@ -452,7 +480,7 @@ mount.`,
 		`A rate limit quota will enforce API rate limiting in a specified interval. A
 rate limit quota can be created at the root level or defined on a namespace or
 mount by specifying a 'path'. The rate limiter is applied to each unique client
-IP address.`,
+IP address, or, depending on 'group_by', to each identity entity or entity alias.`,
 	},
 	"rate-limit-list": {
 		"Lists the names of all the rate limit quotas.",
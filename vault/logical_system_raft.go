@@ -16,11 +16,12 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/mitchellh/mapstructure"
 	wrapping "github.com/openbao/go-kms-wrapping/v2"
+	"github.com/openbao/openbao/helper/agekeys"
 	"github.com/openbao/openbao/helper/namespace"
 	"github.com/openbao/openbao/physical/raft"
 	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/strutil"
 	"github.com/openbao/openbao/sdk/v2/logical"
-	"github.com/openbao/openbao/sdk/v2/physical"
 	"golang.org/x/crypto/hkdf"
 )
 
@@ -116,6 +117,16 @@ func (b *SystemBackend) raftStoragePaths() []*framework.Path {
 		},
 		{
 			Pattern: "storage/raft/snapshot",
+
+			Fields: map[string]*framework.FieldSchema{
+				"encrypt": {
+					Type:        framework.TypeBool,
+					Default:     true,
+					Description: "Whether to encrypt the snapshot to the recipients of sys/config/backup-encryption, if any. Only used when taking a snapshot.",
+					Query:       true,
+				},
+			},
+
 			Operations: map[logical.Operation]framework.OperationHandler{
 				logical.ReadOperation: &framework.PathOperation{
 					Callback: b.handleStorageRaftSnapshotRead(),
@@ -142,6 +153,37 @@ func (b *SystemBackend) raftStoragePaths() []*framework.Path {
 			HelpSynopsis:    strings.TrimSpace(sysRaftHelp["raft-snapshot-force"][0]),
 			HelpDescription: strings.TrimSpace(sysRaftHelp["raft-snapshot-force"][1]),
 		},
+		{
+			Pattern: "storage/raft/snapshot-load",
+
+			Fields: map[string]*framework.FieldSchema{
+				"scope": {
+					Type:        framework.TypeString,
+					Description: "Scope of the data to load from the snapshot. Only mount is supported.",
+					Query:       true,
+				},
+				"source_mount": {
+					Type:        framework.TypeString,
+					Description: "Path of the secrets engine in the snapshot to load the data of.",
+					Query:       true,
+				},
+				"target_mount": {
+					Type:        framework.TypeString,
+					Description: "Path of the new secrets engine to load the data into. It must not be in use.",
+					Query:       true,
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleStorageRaftSnapshotLoad,
+					Summary:  "Restores the data of a single secrets engine from the provided snapshot into a new mount.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysRaftHelp["raft-snapshot-load"][0]),
+			HelpDescription: strings.TrimSpace(sysRaftHelp["raft-snapshot-load"][1]),
+		},
 		{
 			Pattern: "storage/raft/autopilot/state",
 			Operations: map[logical.Operation]framework.OperationHandler{
@@ -200,6 +242,120 @@ func (b *SystemBackend) raftStoragePaths() []*framework.Path {
 			HelpSynopsis:    strings.TrimSpace(sysRaftHelp["raft-autopilot-configuration"][0]),
 			HelpDescription: strings.TrimSpace(sysRaftHelp["raft-autopilot-configuration"][1]),
 		},
+		{
+			Pattern: "storage/raft/dr/secondary",
+			Fields: map[string]*framework.FieldSchema{
+				"primary_address": {
+					Type:        framework.TypeString,
+					Description: "API address of the primary cluster to replicate.",
+				},
+				"token": {
+					Type:        framework.TypeString,
+					Description: "Token used to read snapshots from the primary cluster.",
+				},
+				"ca_cert": {
+					Type:        framework.TypeString,
+					Description: "PEM-encoded CA certificate used to verify the primary cluster's TLS certificate.",
+				},
+				"interval": {
+					Type:        framework.TypeDurationSecond,
+					Description: "Interval at which snapshots are fetched from the primary cluster. Defaults to 1 minute.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleStorageRaftDRSecondaryRead,
+					Summary:  "Returns the configuration of this DR secondary.",
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleStorageRaftDRSecondaryUpdate,
+					Summary:  "Makes this cluster a DR secondary replicating the storage of the primary cluster.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysRaftHelp["raft-dr-secondary"][0]),
+			HelpDescription: strings.TrimSpace(sysRaftHelp["raft-dr-secondary"][1]),
+		},
+		{
+			Pattern: "storage/raft/dr/secondary/promote",
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleStorageRaftDRSecondaryPromote,
+					Summary:  "Stops replicating from the primary cluster and serves requests from the replicated storage.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysRaftHelp["raft-dr-secondary-promote"][0]),
+			HelpDescription: strings.TrimSpace(sysRaftHelp["raft-dr-secondary-promote"][1]),
+		},
+		{
+			Pattern: "storage/raft/dr/status",
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleStorageRaftDRStatus,
+					Summary:  "Returns the replication status of this cluster.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysRaftHelp["raft-dr-status"][0]),
+			HelpDescription: strings.TrimSpace(sysRaftHelp["raft-dr-status"][1]),
+		},
+		{
+			Pattern: "storage/raft/perf/secondary",
+			Fields: map[string]*framework.FieldSchema{
+				"primary_address": {
+					Type:        framework.TypeString,
+					Description: "API address of the primary cluster to replicate.",
+				},
+				"token": {
+					Type:        framework.TypeString,
+					Description: "Token used to read snapshots from the primary cluster.",
+				},
+				"ca_cert": {
+					Type:        framework.TypeString,
+					Description: "PEM-encoded CA certificate used to verify the primary cluster's TLS certificate.",
+				},
+				"interval": {
+					Type:        framework.TypeDurationSecond,
+					Description: "Interval at which snapshots are fetched from the primary cluster. Defaults to 1 minute.",
+				},
+				"paths": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "Mounts to replicate from the primary cluster, such as secret/ or auth/userpass/.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleStorageRaftPerfSecondaryRead,
+					Summary:  "Returns the configuration of this performance secondary.",
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleStorageRaftPerfSecondaryUpdate,
+					Summary:  "Makes this cluster serve read-only copies of selected mounts of the primary cluster.",
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.handleStorageRaftPerfSecondaryDelete,
+					Summary:  "Stops replicating mounts from the primary cluster.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysRaftHelp["raft-perf-secondary"][0]),
+			HelpDescription: strings.TrimSpace(sysRaftHelp["raft-perf-secondary"][1]),
+		},
+		{
+			Pattern: "storage/raft/perf/status",
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleStorageRaftPerfStatus,
+					Summary:  "Returns the status of the replicated mounts.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysRaftHelp["raft-perf-status"][0]),
+			HelpDescription: strings.TrimSpace(sysRaftHelp["raft-perf-status"][1]),
+		},
 	}
 }
 
@@ -412,10 +568,33 @@ func (b *SystemBackend) handleStorageRaftSnapshotRead() framework.OperationFunc
 			return nil, errors.New("no writer for request")
 		}
 
-		err := raftStorage.SnapshotHTTP(req.ResponseWriter, b.Core.seal.GetAccess())
+		var recipients []*agekeys.X25519Recipient
+		if d.Get("encrypt").(bool) {
+			var err error
+			recipients, err = b.Core.backupEncryptionRecipients(ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if len(recipients) == 0 {
+			if err := raftStorage.SnapshotHTTP(req.ResponseWriter, b.Core.seal.GetAccess()); err != nil {
+				return nil, err
+			}
+			return nil, nil
+		}
+
+		req.ResponseWriter.Header().Add("Content-Disposition", "attachment")
+		req.ResponseWriter.Header().Add("Content-Type", "application/octet-stream")
+		encrypted, err := agekeys.Encrypt(req.ResponseWriter, recipients...)
 		if err != nil {
 			return nil, err
 		}
+		if err := raftStorage.Snapshot(encrypted, b.Core.seal.GetAccess()); err != nil {
+			return nil, err
+		}
+		if err := encrypted.Close(); err != nil {
+			return nil, err
+		}
 
 		return nil, nil
 	}
@@ -565,6 +744,10 @@ func (b *SystemBackend) handleStorageRaftSnapshotWrite(force bool) framework.Ope
 		if !ok {
 			return nil, errors.New("no reader for request")
 		}
+		body, err := encryptedBackupReader(body)
+		if err != nil {
+			return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+		}
 
 		access := b.Core.seal.GetAccess()
 		if force {
@@ -592,83 +775,333 @@ func (b *SystemBackend) handleStorageRaftSnapshotWrite(force bool) framework.Ope
 
 		// We want to do this in a go routine so we can upgrade the lock and
 		// allow the client to disconnect.
-		go func() (retErr error) {
+		go func() {
 			// Cleanup the temp file
 			defer cleanup()
 
-			// Grab statelock
-			l := newLockGrabber(b.Core.stateLock.Lock, b.Core.stateLock.Unlock, b.Core.standbyStopCh.Load().(chan struct{}))
-			go l.grab()
-			if stopped := l.lockOrStop(); stopped {
-				b.Core.logger.Error("not applying snapshot; shutting down")
-				return
-			}
-			defer b.Core.stateLock.Unlock()
-
-			// If we failed to restore the snapshot we should seal this node as
-			// it's in an unknown state
-			defer func() {
-				if retErr != nil {
-					if err := b.Core.sealInternalWithOptions(false, false, true); err != nil {
-						b.Core.logger.Error("failed to seal node", "error", err)
-					}
-				}
-			}()
-
-			ctx, ctxCancel := context.WithCancel(namespace.RootContext(nil))
-
-			// We are calling the callback function synchronously here while we
-			// have the lock. So set it to nil and restore the callback when we
-			// finish.
-			raftStorage.SetRestoreCallback(nil)
-			defer raftStorage.SetRestoreCallback(b.Core.raftSnapshotRestoreCallback(true, true))
-
-			// Do a preSeal to clear vault's in-memory caches and shut down any
-			// systems that might be holding the encryption access.
-			b.Core.logger.Info("shutting down prior to restoring snapshot")
-			if err := b.Core.preSeal(); err != nil {
-				b.Core.logger.Error("raft snapshot restore failed preSeal", "error", err)
-				return err
-			}
+			b.Core.applyRaftSnapshot(raftStorage, metadata, snapFile, nil)
+		}()
 
-			b.Core.logger.Info("applying snapshot")
-			if err := raftStorage.RestoreSnapshot(ctx, metadata, snapFile); err != nil {
-				b.Core.logger.Error("error while restoring raft snapshot", "error", err)
-				return err
-			}
+		return nil, nil
+	}
+}
 
-			// Run invalidation logic synchronously here
-			callback := b.Core.raftSnapshotRestoreCallback(false, false)
-			if err := callback(ctx); err != nil {
-				return err
-			}
+func (b *SystemBackend) handleStorageRaftSnapshotLoad(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	raftStorage, ok := b.Core.underlyingPhysical.(*raft.RaftBackend)
+	if !ok {
+		return logical.ErrorResponse("raft storage is not in use"), logical.ErrInvalidRequest
+	}
 
-			{
-				// If the snapshot was taken while another node was leader we
-				// need to reset the leader information to this node.
-				if err := b.Core.underlyingPhysical.Put(ctx, &physical.Entry{
-					Key:   CoreLockPath,
-					Value: []byte(b.Core.leaderUUID),
-				}); err != nil {
-					b.Core.logger.Error("cluster setup failed", "error", err)
-					return err
-				}
-				// re-advertise our cluster information
-				if err := b.Core.advertiseLeader(ctx, b.Core.leaderUUID, nil); err != nil {
-					b.Core.logger.Error("cluster setup failed", "error", err)
-					return err
-				}
-			}
-			if err := b.Core.postUnseal(ctx, ctxCancel, standardUnsealStrategy{}); err != nil {
-				b.Core.logger.Error("raft snapshot restore failed postUnseal", "error", err)
-				return err
-			}
+	if scope := d.Get("scope").(string); scope != "mount" {
+		return logical.ErrorResponse("unsupported scope %q, only mount is supported", scope), logical.ErrInvalidRequest
+	}
+	sourceMount := d.Get("source_mount").(string)
+	targetMount := d.Get("target_mount").(string)
+	if sourceMount == "" || targetMount == "" {
+		return logical.ErrorResponse("source_mount and target_mount are required"), logical.ErrInvalidRequest
+	}
+	sourceMount = sanitizePath(sourceMount)
+	targetMount = sanitizePath(targetMount)
 
-			return nil
-		}()
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ns.ID != namespace.RootNamespaceID {
+		return logical.ErrorResponse("mounts can only be loaded from a snapshot in the root namespace"), logical.ErrInvalidRequest
+	}
+
+	body, ok := logical.ContextOriginalBodyValue(ctx)
+	if !ok {
+		return nil, errors.New("no reader for request")
+	}
+	body, err = encryptedBackupReader(body)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	snapFile, cleanup, _, err := raftStorage.WriteSnapshotToTemp(body, b.Core.seal.GetAccess())
+	if err != nil {
+		if strings.Contains(err.Error(), "failed to open the sealed hashes") {
+			return logical.ErrorResponse("could not verify hash file, the snapshot was likely taken by a different cluster"), logical.ErrInvalidRequest
+		}
+		b.Core.logger.Error("raft snapshot load: failed to write snapshot", "error", err)
+		return nil, err
+	}
+	defer cleanup()
+
+	entry, keys, err := b.Core.loadMountFromSnapshot(ctx, snapFile, sourceMount, targetMount)
+	if err != nil {
+		return handleError(err)
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"source_mount": sourceMount,
+			"target_mount": entry.Path,
+			"type":         entry.Type,
+			"accessor":     entry.Accessor,
+			"keys":         keys,
+		},
+	}, nil
+}
+
+func (b *SystemBackend) handleStorageRaftDRSecondaryRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	b.Core.raftDRLock.Lock()
+	defer b.Core.raftDRLock.Unlock()
+
+	config := b.Core.raftDRConfig
+	if config == nil {
+		return nil, nil
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"primary_address": config.PrimaryAddress,
+			"ca_cert":         config.CACert,
+			"interval":        int64(config.Interval.Seconds()),
+		},
+	}, nil
+}
+
+func (b *SystemBackend) handleStorageRaftDRSecondaryUpdate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if _, ok := b.Core.underlyingPhysical.(*raft.RaftBackend); !ok {
+		return logical.ErrorResponse("raft storage is not in use"), logical.ErrInvalidRequest
+	}
+
+	config := &raftDRSecondaryConfig{
+		Interval: raftDRDefaultInterval,
+	}
+	b.Core.raftDRLock.Lock()
+	if b.Core.raftDRConfig != nil {
+		*config = *b.Core.raftDRConfig
+	}
+	b.Core.raftDRLock.Unlock()
+
+	if primaryAddress, ok := d.GetOk("primary_address"); ok {
+		config.PrimaryAddress = primaryAddress.(string)
+	}
+	if token, ok := d.GetOk("token"); ok {
+		config.Token = token.(string)
+	}
+	if caCert, ok := d.GetOk("ca_cert"); ok {
+		config.CACert = caCert.(string)
+	}
+	if interval, ok := d.GetOk("interval"); ok {
+		config.Interval = time.Duration(interval.(int)) * time.Second
+	}
+
+	switch {
+	case config.PrimaryAddress == "":
+		return logical.ErrorResponse("primary_address is required"), logical.ErrInvalidRequest
+	case config.Token == "":
+		return logical.ErrorResponse("token is required"), logical.ErrInvalidRequest
+	case config.Interval < raftDRMinInterval:
+		return logical.ErrorResponse(fmt.Sprintf("interval must be at least %s", raftDRMinInterval)), logical.ErrInvalidRequest
+	}
+	if _, err := raftDRPrimaryClient(config); err != nil {
+		return logical.ErrorResponse(fmt.Sprintf("invalid primary configuration: %s", err)), logical.ErrInvalidRequest
+	}
+
+	if err := b.Core.persistRaftDRSecondaryConfig(ctx, config); err != nil {
+		return nil, err
+	}
+
+	b.Core.raftDRLock.Lock()
+	defer b.Core.raftDRLock.Unlock()
+
+	b.Core.raftDRConfig = config
+	b.Core.startRaftDRSecondaryLocked(true)
+
+	return nil, nil
+}
+
+func (b *SystemBackend) handleStorageRaftDRSecondaryPromote(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	b.Core.raftDRLock.Lock()
+	defer b.Core.raftDRLock.Unlock()
+
+	if b.Core.raftDRConfig == nil {
+		return logical.ErrorResponse("cluster is not a DR secondary"), logical.ErrInvalidRequest
+	}
+
+	if err := b.Core.barrier.Delete(ctx, raftDRSecondaryConfigPath); err != nil {
+		return nil, err
+	}
+	b.Core.stopRaftDRSecondaryLocked()
+	b.Core.raftDRConfig = nil
+
+	return nil, nil
+}
+
+func (b *SystemBackend) handleStorageRaftDRStatus(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	b.Core.raftDRLock.Lock()
+	defer b.Core.raftDRLock.Unlock()
+
+	status := b.Core.raftDRStatus
+	data := map[string]interface{}{
+		"mode":       "primary",
+		"last_index": status.LastIndex,
+		"last_error": status.LastError,
+	}
+	if b.Core.raftDRConfig != nil {
+		data["mode"] = "secondary"
+		data["primary_address"] = b.Core.raftDRConfig.PrimaryAddress
+	}
+	if !status.LastSync.IsZero() {
+		data["last_sync"] = status.LastSync.Format(time.RFC3339)
+	}
+	if !status.LastAttempt.IsZero() {
+		data["last_attempt"] = status.LastAttempt.Format(time.RFC3339)
+	}
+
+	return &logical.Response{
+		Data: data,
+	}, nil
+}
 
+func (b *SystemBackend) handleStorageRaftPerfSecondaryRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	b.Core.raftPerfLock.Lock()
+	defer b.Core.raftPerfLock.Unlock()
+
+	config := b.Core.raftPerfConfig
+	if config == nil {
 		return nil, nil
 	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"primary_address": config.PrimaryAddress,
+			"ca_cert":         config.CACert,
+			"interval":        int64(config.Interval.Seconds()),
+			"paths":           config.Paths,
+		},
+	}, nil
+}
+
+func (b *SystemBackend) handleStorageRaftPerfSecondaryUpdate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if _, ok := b.Core.underlyingPhysical.(*raft.RaftBackend); !ok {
+		return logical.ErrorResponse("raft storage is not in use"), logical.ErrInvalidRequest
+	}
+	if b.Core.IsRaftDRSecondary() {
+		return logical.ErrorResponse("cluster is a DR secondary"), logical.ErrInvalidRequest
+	}
+
+	config := &raftPerfSecondaryConfig{
+		raftDRSecondaryConfig: raftDRSecondaryConfig{
+			Interval: raftDRDefaultInterval,
+		},
+	}
+	b.Core.raftPerfLock.Lock()
+	if b.Core.raftPerfConfig != nil {
+		*config = *b.Core.raftPerfConfig
+	}
+	b.Core.raftPerfLock.Unlock()
+
+	if primaryAddress, ok := d.GetOk("primary_address"); ok {
+		config.PrimaryAddress = primaryAddress.(string)
+	}
+	if token, ok := d.GetOk("token"); ok {
+		config.Token = token.(string)
+	}
+	if caCert, ok := d.GetOk("ca_cert"); ok {
+		config.CACert = caCert.(string)
+	}
+	if interval, ok := d.GetOk("interval"); ok {
+		config.Interval = time.Duration(interval.(int)) * time.Second
+	}
+	if paths, ok := d.GetOk("paths"); ok {
+		config.Paths = nil
+		for _, path := range paths.([]string) {
+			path = sanitizePath(path)
+			if path == credentialRoutePrefix+mountTypeToken+"/" || path == credentialRoutePrefix || strutil.StrListContains(protectedMounts, path) {
+				return logical.ErrorResponse(fmt.Sprintf("cannot replicate %q", path)), logical.ErrInvalidRequest
+			}
+			config.Paths = strutil.AppendIfMissing(config.Paths, path)
+		}
+	}
+
+	switch {
+	case config.PrimaryAddress == "":
+		return logical.ErrorResponse("primary_address is required"), logical.ErrInvalidRequest
+	case config.Token == "":
+		return logical.ErrorResponse("token is required"), logical.ErrInvalidRequest
+	case len(config.Paths) == 0:
+		return logical.ErrorResponse("paths is required"), logical.ErrInvalidRequest
+	case config.Interval < raftDRMinInterval:
+		return logical.ErrorResponse(fmt.Sprintf("interval must be at least %s", raftDRMinInterval)), logical.ErrInvalidRequest
+	}
+	if _, err := raftDRPrimaryClient(&config.raftDRSecondaryConfig); err != nil {
+		return logical.ErrorResponse(fmt.Sprintf("invalid primary configuration: %s", err)), logical.ErrInvalidRequest
+	}
+
+	entry, err := logical.StorageEntryJSON(raftPerfSecondaryConfigPath, config)
+	if err != nil {
+		return nil, err
+	}
+	if err := b.Core.barrier.Put(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	b.Core.raftPerfLock.Lock()
+	defer b.Core.raftPerfLock.Unlock()
+
+	// Sync the mounts again even if the primary did not change, as the
+	// replicated paths may have.
+	b.Core.raftPerfConfig = config
+	b.Core.raftPerfStatus = raftPerfSecondaryStatus{}
+	b.Core.startRaftPerfSecondaryLocked()
+
+	return nil, nil
+}
+
+func (b *SystemBackend) handleStorageRaftPerfSecondaryDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	b.Core.raftPerfLock.Lock()
+	defer b.Core.raftPerfLock.Unlock()
+
+	if err := b.Core.barrier.Delete(ctx, raftPerfSecondaryConfigPath); err != nil {
+		return nil, err
+	}
+	b.Core.stopRaftPerfSecondaryLocked()
+	b.Core.raftPerfConfig = nil
+	b.Core.raftPerfStatus = raftPerfSecondaryStatus{}
+
+	return nil, nil
+}
+
+func (b *SystemBackend) handleStorageRaftPerfStatus(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	b.Core.raftPerfLock.Lock()
+	defer b.Core.raftPerfLock.Unlock()
+
+	status := b.Core.raftPerfStatus
+	data := map[string]interface{}{
+		"mode":       "primary",
+		"last_index": status.LastIndex,
+		"last_error": status.LastError,
+	}
+	if b.Core.raftPerfConfig != nil {
+		data["mode"] = "secondary"
+		data["primary_address"] = b.Core.raftPerfConfig.PrimaryAddress
+	}
+	if !status.LastAttempt.IsZero() {
+		data["last_attempt"] = status.LastAttempt.Format(time.RFC3339)
+	}
+
+	mounts := make(map[string]interface{}, len(status.Mounts))
+	for path, mount := range status.Mounts {
+		info := map[string]interface{}{
+			"keys":       mount.Keys,
+			"last_error": mount.LastError,
+		}
+		if !mount.LastSync.IsZero() {
+			info["last_sync"] = mount.LastSync.Format(time.RFC3339)
+		}
+		mounts[path] = info
+	}
+	data["mounts"] = mounts
+
+	return &logical.Response{
+		Data: data,
+	}, nil
 }
 
 var sysRaftHelp = map[string][2]string{
@@ -696,6 +1129,13 @@ var sysRaftHelp = map[string][2]string{
 		"Force restore a raft cluster snapshot",
 		"",
 	},
+	"raft-snapshot-load": {
+		"Restores a single secrets engine from a raft cluster snapshot.",
+		`Loads the data of the secrets engine mounted at source_mount in the
+provided snapshot into a new secrets engine mounted at target_mount, with the
+same type and configuration. The rest of the cluster is left unchanged. The
+snapshot must have been taken by this cluster.`,
+	},
 	"raft-autopilot-state": {
 		"Returns the state of the raft cluster under integrated storage as seen by autopilot.",
 		"",
@@ -704,4 +1144,31 @@ var sysRaftHelp = map[string][2]string{
 		"Returns autopilot configuration.",
 		"",
 	},
+	"raft-dr-secondary": {
+		"Configures this cluster as a DR secondary of a primary cluster.",
+		`A DR secondary periodically fetches a snapshot of the primary cluster's
+storage and restores it, so that it can take over when the primary is lost.
+Both clusters must share the same seal. Until it is promoted, the secondary
+only serves the sys/storage/raft/dr/ endpoints.`,
+	},
+	"raft-dr-secondary-promote": {
+		"Promotes this DR secondary, so that it serves requests from the storage replicated last.",
+		"",
+	},
+	"raft-dr-status": {
+		"Returns the DR replication status of this cluster.",
+		"",
+	},
+	"raft-perf-secondary": {
+		"Configures the mounts this cluster replicates from a primary cluster.",
+		`A performance secondary periodically fetches a snapshot of the primary
+cluster's storage and copies the data of the selected mounts, which it serves
+read-only. Logins are allowed on replicated auth methods. Both clusters must
+share the same seal and keyring. The rest of the secondary's storage, such as
+its tokens and policies, is its own.`,
+	},
+	"raft-perf-status": {
+		"Returns the status of the mounts replicated from the primary cluster.",
+		"",
+	},
 }
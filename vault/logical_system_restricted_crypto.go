@@ -0,0 +1,74 @@
+package vault
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+func (b *SystemBackend) restrictedCryptoPaths() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "restricted-crypto/report$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "restricted-crypto",
+				OperationVerb:   "report",
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleRestrictedCryptoReport,
+					Summary:  "Report the keys of secrets engines which are not approved in restricted crypto mode.",
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"restricted_crypto": {
+									Type:     framework.TypeBool,
+									Required: true,
+								},
+								"keys": {
+									Type:     framework.TypeSlice,
+									Required: true,
+								},
+							},
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["restricted-crypto-report"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["restricted-crypto-report"][1]),
+		},
+	}
+}
+
+func (b *SystemBackend) handleRestrictedCryptoReport(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	keys, warnings, err := b.Core.restrictedCryptoReport(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reported := make([]map[string]interface{}, 0, len(keys))
+	for _, key := range keys {
+		reported = append(reported, map[string]interface{}{
+			"mount":      key.Mount,
+			"mount_type": key.MountType,
+			"kind":       key.Kind,
+			"name":       key.Name,
+			"key_type":   key.KeyType,
+		})
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"restricted_crypto": b.Core.RestrictedCrypto(),
+			"keys":              reported,
+		},
+		Warnings: warnings,
+	}, nil
+}
@@ -0,0 +1,249 @@
+package vault
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+func (b *SystemBackend) rootRotationPaths() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "rotate/roots/?$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "root-rotations",
+				OperationVerb:   "list",
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback: b.handleRootRotationList,
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"keys": {
+									Type:     framework.TypeStringSlice,
+									Required: true,
+								},
+							},
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["rotate-roots"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["rotate-roots"][1]),
+		},
+
+		{
+			Pattern: "rotate/roots/" + framework.GenericNameRegex("name") + "/rotate$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "root-rotations",
+				OperationVerb:   "rotate",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the root credential registration.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleRootRotationRotate,
+					Summary:  "Rotate a registered root credential now, regardless of its schedule.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["rotate-roots-rotate"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["rotate-roots-rotate"][1]),
+		},
+
+		{
+			Pattern: "rotate/roots/" + framework.GenericNameRegex("name") + "$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "root-rotations",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the root credential registration.",
+				},
+				"path": {
+					Type:        framework.TypeString,
+					Description: "API path of the rotate endpoint of the secrets engine or auth method, such as database/rotate-root/my-db. Required when registering.",
+				},
+				"interval": {
+					Type:        framework.TypeDurationSecond,
+					Description: "Minimum time between two scheduled rotations. If zero, the credential is only rotated on demand.",
+				},
+				"window_start": {
+					Type:        framework.TypeString,
+					Description: "Start of the daily rotation window, as HH:MM in UTC.",
+				},
+				"window_duration": {
+					Type:        framework.TypeDurationSecond,
+					Description: "Duration of the daily rotation window, at most 24 hours. If zero, scheduled rotations may happen at any time.",
+				},
+				"notify_urls": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "URLs receiving a POST request with the outcome of each rotation.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleRootRotationRead,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "read",
+					},
+					Summary: "Read a root credential registration and its rotation status.",
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleRootRotationWrite,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "register",
+					},
+					Summary: "Register a root credential with the rotation scheduler, or update its registration.",
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.handleRootRotationDelete,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "unregister",
+					},
+					Summary: "Remove a root credential from the rotation scheduler.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["rotate-roots-name"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["rotate-roots-name"][1]),
+		},
+	}
+}
+
+func (b *SystemBackend) handleRootRotationList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	names, err := b.Core.rootRotationView().List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	return logical.ListResponse(names), nil
+}
+
+func (b *SystemBackend) handleRootRotationRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	rotation, err := b.Core.rootRotation(ctx, d.Get("name").(string))
+	if err != nil {
+		return nil, err
+	}
+	if rotation == nil {
+		return nil, nil
+	}
+	return &logical.Response{
+		Data: rotation.toMap(),
+	}, nil
+}
+
+func (b *SystemBackend) handleRootRotationWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+
+	b.Core.rootRotationLock.Lock()
+	defer b.Core.rootRotationLock.Unlock()
+
+	rotation, err := b.Core.rootRotation(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if rotation == nil {
+		rotation = &RootRotation{
+			Name:        name,
+			CreatedTime: time.Now().UTC(),
+		}
+	}
+
+	if pathRaw, ok := d.GetOk("path"); ok {
+		rotation.Path = strings.Trim(pathRaw.(string), "/")
+	}
+	if rotation.Path == "" {
+		return logical.ErrorResponse("path is required"), logical.ErrInvalidRequest
+	}
+	if err := b.Core.validateRootRotationPath(ctx, rotation.Path); err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	if intervalRaw, ok := d.GetOk("interval"); ok {
+		rotation.Interval = time.Duration(intervalRaw.(int)) * time.Second
+	}
+	if rotation.Interval < 0 {
+		return logical.ErrorResponse("interval must not be negative"), logical.ErrInvalidRequest
+	}
+
+	if windowStartRaw, ok := d.GetOk("window_start"); ok {
+		rotation.WindowStart, err = parseRootRotationWindowStart(windowStartRaw.(string))
+		if err != nil {
+			return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+		}
+	}
+	if windowDurationRaw, ok := d.GetOk("window_duration"); ok {
+		rotation.WindowDuration = time.Duration(windowDurationRaw.(int)) * time.Second
+	}
+	if rotation.WindowDuration < 0 || rotation.WindowDuration > 24*time.Hour {
+		return logical.ErrorResponse("window_duration must be between 0 and 24 hours"), logical.ErrInvalidRequest
+	}
+
+	if notifyRaw, ok := d.GetOk("notify_urls"); ok {
+		rotation.NotifyURLs = notifyRaw.([]string)
+	}
+	for _, u := range rotation.NotifyURLs {
+		parsed, err := url.Parse(u)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return logical.ErrorResponse("invalid notification URL %q", u), logical.ErrInvalidRequest
+		}
+	}
+
+	if err := b.Core.putRootRotation(ctx, rotation); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (b *SystemBackend) handleRootRotationDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	b.Core.rootRotationLock.Lock()
+	defer b.Core.rootRotationLock.Unlock()
+
+	if err := b.Core.rootRotationView().Delete(ctx, d.Get("name").(string)); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (b *SystemBackend) handleRootRotationRotate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+
+	b.Core.rootRotationLock.Lock()
+	defer b.Core.rootRotationLock.Unlock()
+
+	rotation, err := b.Core.rootRotation(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if rotation == nil {
+		return logical.ErrorResponse("no root credential registered as %q", name), logical.ErrInvalidRequest
+	}
+
+	if err := b.Core.rotateRoot(ctx, rotation); err != nil {
+		return logical.ErrorResponse("failed to rotate root credential %q: %s", name, err), nil
+	}
+	return &logical.Response{
+		Data: rotation.toMap(),
+	}, nil
+}
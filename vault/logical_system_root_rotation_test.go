@@ -0,0 +1,145 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSystemBackend_RootRotation(t *testing.T) {
+	c, b, root := testCoreSystemBackend(t)
+	ctx := namespace.RootContext(nil)
+
+	var fail atomic.Bool
+	noop := &NoopBackend{
+		RequestHandler: func(ctx context.Context, req *logical.Request) (*logical.Response, error) {
+			if fail.Load() {
+				return nil, errors.New("connection refused")
+			}
+			return nil, nil
+		},
+	}
+	c.logicalBackends["noop"] = func(context.Context, *logical.BackendConfig) (logical.Backend, error) {
+		return noop, nil
+	}
+	req := logical.TestRequest(t, logical.UpdateOperation, "sys/mounts/db")
+	req.Data["type"] = "noop"
+	req.ClientToken = root
+	_, err := c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+
+	notifications := make(chan map[string]interface{}, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		notifications <- body
+	}))
+	defer server.Close()
+
+	request := func(op logical.Operation, path string, data map[string]interface{}) *logical.Response {
+		t.Helper()
+		req := logical.TestRequest(t, op, path)
+		req.Data = data
+		resp, err := b.HandleRequest(ctx, req)
+		if err != nil && err != logical.ErrInvalidRequest {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	// Paths must belong to a mount.
+	resp := request(logical.UpdateOperation, "rotate/roots/db", map[string]interface{}{
+		"path": "missing/rotate-root/my-db",
+	})
+	require.True(t, resp.IsError())
+
+	resp = request(logical.UpdateOperation, "rotate/roots/db", map[string]interface{}{
+		"path":            "db/rotate-root/my-db",
+		"interval":        "24h",
+		"window_start":    "02:00",
+		"window_duration": "2h",
+		"notify_urls":     server.URL,
+	})
+	require.Nil(t, resp)
+
+	resp = request(logical.ListOperation, "rotate/roots", nil)
+	require.Equal(t, []string{"db"}, resp.Data["keys"])
+
+	resp = request(logical.ReadOperation, "rotate/roots/db", nil)
+	require.Equal(t, "02:00", resp.Data["window_start"])
+	require.Equal(t, int64(86400), resp.Data["interval"])
+	next := resp.Data["next_rotation"].(time.Time)
+	require.Contains(t, []int{2, 3}, next.Hour())
+
+	// Rotations on demand ignore the schedule.
+	resp = request(logical.UpdateOperation, "rotate/roots/db/rotate", nil)
+	require.False(t, resp.IsError())
+	require.Equal(t, uint64(1), resp.Data["rotations"])
+	require.Equal(t, "rotate-root/my-db", noop.Paths[len(noop.Paths)-1])
+	require.Equal(t, "success", (<-notifications)["status"])
+
+	fail.Store(true)
+	resp = request(logical.UpdateOperation, "rotate/roots/db/rotate", nil)
+	require.True(t, resp.IsError())
+	resp = request(logical.ReadOperation, "rotate/roots/db", nil)
+	require.Equal(t, 1, resp.Data["consecutive_failures"])
+	require.Contains(t, resp.Data["last_error"], "connection refused")
+	notification := <-notifications
+	require.Equal(t, "failure", notification["status"])
+	require.Contains(t, notification["error"], "connection refused")
+
+	// Scheduled rotations only happen once due and within the window.
+	fail.Store(false)
+	rotation, err := c.rootRotation(ctx, "db")
+	require.NoError(t, err)
+	rotation.LastRotation = time.Now().Add(-48 * time.Hour)
+	rotation.LastAttempt = time.Now().Add(-time.Hour)
+	require.NoError(t, c.putRootRotation(ctx, rotation))
+
+	day := time.Now().UTC().Add(24 * time.Hour)
+	c.checkRootRotations(ctx, time.Date(day.Year(), day.Month(), day.Day(), 5, 0, 0, 0, time.UTC))
+	rotation, err = c.rootRotation(ctx, "db")
+	require.NoError(t, err)
+	require.Equal(t, 1, rotation.ConsecutiveFailures)
+
+	c.checkRootRotations(ctx, time.Date(day.Year(), day.Month(), day.Day(), 3, 0, 0, 0, time.UTC))
+	rotation, err = c.rootRotation(ctx, "db")
+	require.NoError(t, err)
+	require.Equal(t, 0, rotation.ConsecutiveFailures)
+	require.Equal(t, uint64(2), rotation.Rotations)
+	require.Equal(t, "success", (<-notifications)["status"])
+
+	request(logical.DeleteOperation, "rotate/roots/db", nil)
+	resp = request(logical.ReadOperation, "rotate/roots/db", nil)
+	require.Nil(t, resp)
+}
+
+func TestRootRotation_NextWindow(t *testing.T) {
+	r := &RootRotation{
+		WindowStart:    23 * 60,
+		WindowDuration: 2 * time.Hour,
+	}
+
+	at := func(day, hour int) time.Time {
+		return time.Date(2024, 6, day, hour, 30, 0, 0, time.UTC)
+	}
+
+	// Windows may span midnight.
+	require.Equal(t, at(2, 0), r.nextWindow(at(2, 0)))
+	require.Equal(t, time.Date(2024, 6, 2, 23, 0, 0, 0, time.UTC), r.nextWindow(at(2, 1)))
+	require.Equal(t, at(2, 23), r.nextWindow(at(2, 23)))
+	require.True(t, r.inWindow(at(2, 23)))
+	require.False(t, r.inWindow(at(2, 12)))
+
+	r.WindowDuration = 0
+	require.Equal(t, at(2, 12), r.nextWindow(at(2, 12)))
+}
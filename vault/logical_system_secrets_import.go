@@ -0,0 +1,207 @@
+package vault
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+	"github.com/openbao/openbao/helper/secretsimport"
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+func (b *SystemBackend) secretsImportPaths() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "import/?$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "secrets-import",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"source": {
+					Type:          framework.TypeString,
+					Description:   "Type of the external store to import secrets from.",
+					AllowedValues: []interface{}{secretsimport.SourceAWSSecretsManager, secretsimport.SourceAzureKeyVault, secretsimport.SourceGCPSecretManager, secretsimport.SourceVault},
+					Required:      true,
+				},
+				"source_config": {
+					Type:        framework.TypeKVPairs,
+					Description: "Configuration of the client of the external store, such as its address and credentials.",
+				},
+				"destination": {
+					Type:        framework.TypeString,
+					Description: "Path of the KV version 2 mount to import the secrets into.",
+					Required:    true,
+				},
+				"mappings": {
+					Type:        framework.TypeSlice,
+					Description: `Rules mapping the secrets to paths of the destination mount, each with a "match" pattern and a "destination". The first matching rule applies, and secrets matching no rule are skipped. By default, every secret is imported at a path named after it.`,
+				},
+				"dry_run": {
+					Type:        framework.TypeBool,
+					Description: "If true, only list and map the secrets of the store, reporting the resulting plan without writing any secret.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleSecretsImportStart,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "start",
+					},
+					Summary: "Start importing secrets from an external store into a KV version 2 mount.",
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"id": {
+									Type:     framework.TypeString,
+									Required: true,
+								},
+							},
+						}},
+					},
+				},
+				logical.ListOperation: &framework.PathOperation{
+					Callback: b.handleSecretsImportList,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "list",
+					},
+					Summary: "List the imports of secrets.",
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"keys": {
+									Type:     framework.TypeStringSlice,
+									Required: true,
+								},
+							},
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["import"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["import"][1]),
+		},
+
+		{
+			Pattern: "import/(?P<id>.+)",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "secrets-import",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"id": {
+					Type:        framework.TypeString,
+					Description: "ID of the import.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleSecretsImportRead,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "read-status",
+					},
+					Summary: "Read the status and progress of an import of secrets.",
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.handleSecretsImportDelete,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "cancel",
+					},
+					Summary: "Cancel a running import of secrets, or remove the status of a finished one.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["import-id"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["import-id"][1]),
+		},
+	}
+}
+
+func (b *SystemBackend) handleSecretsImportStart(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	importReq := &SecretsImportRequest{
+		SourceType:   d.Get("source").(string),
+		SourceConfig: d.Get("source_config").(map[string]string),
+		Destination:  d.Get("destination").(string),
+		DryRun:       d.Get("dry_run").(bool),
+	}
+	if importReq.SourceType == "" {
+		return logical.ErrorResponse("source is required"), logical.ErrInvalidRequest
+	}
+	if importReq.Destination == "" {
+		return logical.ErrorResponse("destination is required"), logical.ErrInvalidRequest
+	}
+	if mappingsRaw, ok := d.GetOk("mappings"); ok {
+		if err := mapstructure.Decode(mappingsRaw, &importReq.Rules); err != nil {
+			return logical.ErrorResponse("invalid mappings: %s", err), logical.ErrInvalidRequest
+		}
+	}
+
+	id, err := b.Core.startSecretsImport(ctx, importReq)
+	if err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"id": id,
+		},
+	}, nil
+}
+
+func (b *SystemBackend) handleSecretsImportList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	return logical.ListResponse(b.Core.listSecretsImports()), nil
+}
+
+func (b *SystemBackend) handleSecretsImportRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	job := b.Core.secretsImportJob(d.Get("id").(string))
+	if job == nil {
+		return nil, nil
+	}
+	info := job.snapshot()
+
+	data := map[string]interface{}{
+		"id":          info.ID,
+		"source":      info.Source,
+		"destination": info.Destination,
+		"dry_run":     info.DryRun,
+		"status":      info.Status,
+		"start_time":  info.StartTime,
+		"total":       info.Total,
+		"imported":    info.Imported,
+		"skipped":     info.Skipped,
+		"failed":      info.Failed,
+		"errors":      info.Errors,
+	}
+	if !info.EndTime.IsZero() {
+		data["end_time"] = info.EndTime
+	}
+	if info.Error != "" {
+		data["error"] = info.Error
+	}
+	if info.DryRun {
+		plan := make([]map[string]interface{}, 0, len(info.Plan))
+		for _, entry := range info.Plan {
+			plan = append(plan, map[string]interface{}{
+				"source":      entry.Source,
+				"destination": entry.Destination,
+			})
+		}
+		data["plan"] = plan
+	}
+	return &logical.Response{
+		Data: data,
+	}, nil
+}
+
+func (b *SystemBackend) handleSecretsImportDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	b.Core.deleteSecretsImport(d.Get("id").(string))
+	return nil, nil
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -36,6 +37,7 @@ import (
 	"github.com/openbao/openbao/sdk/v2/helper/pluginutil"
 	"github.com/openbao/openbao/sdk/v2/helper/testhelpers/schema"
 	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/openbao/openbao/sdk/v2/physical"
 	"github.com/openbao/openbao/version"
 )
 
@@ -48,9 +50,32 @@ func TestSystemBackend_RootPaths(t *testing.T) {
 		"raw",
 		"raw/*",
 		"rotate",
+		"rotate/roots",
+		"rotate/roots/*",
+		"import",
+		"import/*",
+		"network-policy",
+		"network-policy/*",
 		"config/cors",
+		"config/cache",
 		"config/auditing/*",
+		"config/reload/*",
+		"config/state/apply",
+		"config/export",
+		"config/import",
+		"config/backup-encryption",
+		"config/ttl-policies",
+		"config/ttl-policies/*",
+		"federation/tokens",
+		"federation/tokens/*",
+		"federation/clusters",
+		"federation/clusters/*",
+		"federation/config",
+		"payload-encryption/rotate",
 		"config/ui/headers/*",
+		"config/ui/banners",
+		"config/ui/banners/*",
+		"config/ui/banner-acknowledgments/*",
 		"plugins/catalog/*",
 		"revoke-prefix/*",
 		"revoke-force/*",
@@ -58,7 +83,11 @@ func TestSystemBackend_RootPaths(t *testing.T) {
 		"leases/revoke-force/*",
 		"leases/lookup/*",
 		"leases",
+		"leases/irrevocable",
+		"leases/irrevocable/revoke-force",
 		"internal/inspect/*",
+		"generate-root/history/*",
+		"restricted-crypto/report",
 	}
 
 	b := testSystemBackend(t)
@@ -173,6 +202,75 @@ func TestSystemConfigCORS(t *testing.T) {
 	}
 }
 
+func TestSystemConfigCache(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "sys/config/cache")
+	req.ClientToken = root
+	req.Data["size"] = 1000
+	req.Data["bypass_prefixes"] = "logical/pki/"
+	resp, err := c.HandleRequest(ctx, req)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\n err: %v", resp, err)
+	}
+
+	expected := map[string]interface{}{
+		"size":            1000,
+		"bypass_prefixes": []string{"logical/pki/"},
+	}
+	req = logical.TestRequest(t, logical.ReadOperation, "sys/config/cache")
+	req.ClientToken = root
+	resp, err = c.HandleRequest(ctx, req)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\n err: %v", resp, err)
+	}
+	if diff := deep.Equal(resp.Data, expected); diff != nil {
+		t.Fatal(diff)
+	}
+	schema.ValidateResponse(
+		t,
+		schema.GetResponseSchema(t, c.systemBackend.Route("config/cache"), req.Operation),
+		resp,
+		true,
+	)
+
+	// The configuration is applied again on unseal
+	c.physicalCache.(physical.TunableCache).Resize(0)
+	if err := c.loadCacheConfig(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	req = logical.TestRequest(t, logical.ReadOperation, "sys/internal/counters/cache")
+	req.ClientToken = root
+	resp, err = c.HandleRequest(ctx, req)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\n err: %v", resp, err)
+	}
+	schema.ValidateResponse(
+		t,
+		schema.GetResponseSchema(t, c.systemBackend.Route("internal/counters/cache"), req.Operation),
+		resp,
+		true,
+	)
+	counters := resp.Data["counters"].(map[string]interface{})
+	if counters["size"] != 1000 {
+		t.Fatalf("bad: %#v", counters)
+	}
+
+	req = logical.TestRequest(t, logical.DeleteOperation, "sys/config/cache")
+	req.ClientToken = root
+	resp, err = c.HandleRequest(ctx, req)
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\n err: %v", resp, err)
+	}
+
+	stats := c.physicalCache.(physical.TunableCache).Stats()
+	if stats.Size != physical.DefaultCacheSize || len(stats.BypassPrefixes) != 0 {
+		t.Fatalf("bad: %#v", stats)
+	}
+}
+
 func TestSystemBackend_mounts(t *testing.T) {
 	b := testSystemBackend(t)
 	req := logical.TestRequest(t, logical.ReadOperation, "mounts")
@@ -804,6 +902,105 @@ func TestSystemBackend_CapabilitiesAccessor_BC(t *testing.T) {
 	}
 }
 
+func TestSystemBackend_CapabilitiesBatchExplain(t *testing.T) {
+	core, b, rootToken := testCoreSystemBackend(t)
+
+	policy, _ := ParseACLPolicy(namespace.RootNamespace, capabilitiesPolicy)
+	err := core.policyStore.SetPolicy(namespace.RootContext(nil), policy)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	policy, _ = ParseACLPolicy(namespace.RootNamespace, `
+name = "deny-bar"
+path "bar/baz" {
+	capabilities = ["deny"]
+}
+path "foo/bar" {
+	capabilities = ["read"]
+}
+`)
+	err = core.policyStore.SetPolicy(namespace.RootContext(nil), policy)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	testMakeServiceTokenViaBackend(t, core.tokenStore, rootToken, "tokenid", "", []string{"test", "deny-bar"})
+	te, err := core.tokenStore.Lookup(namespace.RootContext(nil), "tokenid")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "capabilities-accessor")
+	req.Data["accessor"] = te.Accessor
+	req.Data["paths"] = []string{"foo/bar", "bar/baz", "other"}
+	req.Data["explain"] = true
+	resp, err := b.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"capabilities": map[string][]string{
+			"foo/bar": {"read"},
+			"bar/baz": {"deny"},
+			"other":   {"deny"},
+		},
+		"explain": map[string][]*PolicyCapabilities{
+			"foo/bar": {
+				{Policy: "deny-bar", Capabilities: []string{"read"}},
+				{Policy: "test", Capabilities: []string{"create", "sudo", "update"}},
+			},
+			"bar/baz": {
+				{Policy: "deny-bar", Capabilities: []string{"deny"}},
+				{Policy: "test", Capabilities: []string{"delete", "read", "update"}},
+			},
+			"other": {},
+		},
+	}
+	if diff := deep.Equal(resp.Data, expected); diff != nil {
+		t.Fatal(diff)
+	}
+
+	// Paths named like the response keys do not collide with them.
+	req = logical.TestRequest(t, logical.UpdateOperation, "capabilities")
+	req.Data["token"] = "tokenid"
+	req.Data["paths"] = []string{"explain", "capabilities", "foo/bar"}
+	req.Data["explain"] = true
+	resp, err = b.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	expected = map[string]interface{}{
+		"capabilities": map[string][]string{
+			"explain":      {"deny"},
+			"capabilities": {"deny"},
+			"foo/bar":      {"read"},
+		},
+		"explain": map[string][]*PolicyCapabilities{
+			"explain":      {},
+			"capabilities": {},
+			"foo/bar": {
+				{Policy: "deny-bar", Capabilities: []string{"read"}},
+				{Policy: "test", Capabilities: []string{"create", "sudo", "update"}},
+			},
+		},
+	}
+	if diff := deep.Equal(resp.Data, expected); diff != nil {
+		t.Fatal(diff)
+	}
+
+	// Accessors are only accepted by capabilities-accessor, so that the
+	// policies guarding it cannot be bypassed.
+	req = logical.TestRequest(t, logical.UpdateOperation, "capabilities")
+	req.Data["accessor"] = te.Accessor
+	req.Data["paths"] = "foo/bar"
+	resp, err = b.HandleRequest(namespace.RootContext(nil), req)
+	if err == nil && (resp == nil || !resp.IsError()) {
+		t.Fatalf("expected an error, resp:%#v", resp)
+	}
+}
+
 func TestSystemBackend_remount_auth(t *testing.T) {
 	err := AddTestCredentialBackend("userpass", credUserpass.Factory)
 	if err != nil {
@@ -1012,6 +1209,66 @@ func TestSystemBackend_remount(t *testing.T) {
 	})
 }
 
+func TestSystemBackend_remount_progress(t *testing.T) {
+	core, b, root := testCoreSystemBackend(t)
+	ctx := namespace.RootContext(nil)
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "secret/foo")
+	req.Data["foo"] = "bar"
+	req.ClientToken = root
+	if _, err := core.HandleRequest(ctx, req); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Create three leases on the mount being moved
+	for i := 0; i < 3; i++ {
+		req = logical.TestRequest(t, logical.ReadOperation, "secret/foo")
+		req.ClientToken = root
+		if err := core.PopulateTokenEntry(ctx, req); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		resp, err := core.HandleRequest(ctx, req)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if resp == nil || resp.Secret == nil || resp.Secret.LeaseID == "" {
+			t.Fatalf("bad: %#v", resp)
+		}
+	}
+
+	req = logical.TestRequest(t, logical.UpdateOperation, "remount")
+	req.Data["from"] = "secret"
+	req.Data["to"] = "foo"
+	resp, err := b.HandleRequest(ctx, req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	var migrationInfo *MountMigrationInfo
+	corehelpers.RetryUntil(t, 5*time.Second, func() error {
+		req = logical.TestRequest(t, logical.ReadOperation, fmt.Sprintf("remount/status/%s", resp.Data["migration_id"]))
+		statusResp, err := b.HandleRequest(ctx, req)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		migrationInfo = statusResp.Data["migration_info"].(*MountMigrationInfo)
+		if migrationInfo.MigrationStatus != MigrationSuccessStatus.String() {
+			return fmt.Errorf("Expected migration status to be successful, got %q", migrationInfo.MigrationStatus)
+		}
+		return nil
+	})
+
+	if migrationInfo.Stage != MigrationStageCompleted {
+		t.Fatalf("expected stage %q, got %q", MigrationStageCompleted, migrationInfo.Stage)
+	}
+	if migrationInfo.LeasesTotal != 3 || migrationInfo.LeasesRevoked != 3 {
+		t.Fatalf("expected 3 of 3 leases revoked, got %d of %d", migrationInfo.LeasesRevoked, migrationInfo.LeasesTotal)
+	}
+	if migrationInfo.EndTime == nil || migrationInfo.EndTime.Before(migrationInfo.StartTime) {
+		t.Fatalf("bad: end time %s before start time %s", migrationInfo.EndTime, migrationInfo.StartTime)
+	}
+}
+
 func TestSystemBackend_remount_destinationInUse(t *testing.T) {
 	c, b, _ := testCoreSystemBackend(t)
 
@@ -3177,11 +3434,17 @@ func TestSystemBackend_keyStatus(t *testing.T) {
 		t.Fatalf("err: %v", err)
 	}
 
+	history := resp.Data["history"].([]map[string]interface{})
+	if len(history) != 1 || history[0]["term"] != 1 {
+		t.Fatalf("bad history: %#v", history)
+	}
+
 	exp := map[string]interface{}{
 		"term": 1,
 	}
 	delete(resp.Data, "install_time")
 	delete(resp.Data, "encryptions")
+	delete(resp.Data, "history")
 	if !reflect.DeepEqual(resp.Data, exp) {
 		t.Fatalf("got: %#v expect: %#v", resp.Data, exp)
 	}
@@ -3266,11 +3529,21 @@ func TestSystemBackend_rotate(t *testing.T) {
 		t.Fatalf("err: %v", err)
 	}
 
+	history := resp.Data["history"].([]map[string]interface{})
+	if len(history) != 2 || history[0]["term"] != 1 || history[1]["term"] != 2 {
+		t.Fatalf("bad history: %#v", history)
+	}
+	if history[1]["rotation_reason"] != manualRotateReason {
+		t.Fatalf("bad history: %#v", history)
+	}
+
 	exp := map[string]interface{}{
-		"term": 2,
+		"term":            2,
+		"rotation_reason": manualRotateReason,
 	}
 	delete(resp.Data, "install_time")
 	delete(resp.Data, "encryptions")
+	delete(resp.Data, "history")
 	if !reflect.DeepEqual(resp.Data, exp) {
 		t.Fatalf("got: %#v expect: %#v", resp.Data, exp)
 	}
@@ -3288,6 +3561,39 @@ func testSystemBackendRaw(t *testing.T) logical.Backend {
 	return c.systemBackend
 }
 
+func TestSystemBackend_ConfigReload(t *testing.T) {
+	var reloads int
+	var reloadErr error
+	core, _, _ := TestCoreUnsealedWithConfig(t, &CoreConfig{
+		ReloadConfigFunc: func() error {
+			reloads++
+			return reloadErr
+		},
+	})
+	b := core.systemBackend
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "config/reload/config")
+	resp, err := b.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil || resp != nil {
+		t.Fatalf("unexpected response: %#v, err: %v", resp, err)
+	}
+	if reloads != 1 {
+		t.Fatalf("expected one reload, got %d", reloads)
+	}
+
+	reloadErr = errors.New("bad listener")
+	_, err = b.HandleRequest(namespace.RootContext(nil), req)
+	if err == nil || !strings.Contains(err.Error(), "bad listener") {
+		t.Fatalf("expected reload error, got: %v", err)
+	}
+
+	req = logical.TestRequest(t, logical.UpdateOperation, "config/reload/license")
+	_, err = b.HandleRequest(namespace.RootContext(nil), req)
+	if err != logical.ErrUnsupportedPath {
+		t.Fatalf("expected unsupported path, got: %v", err)
+	}
+}
+
 func testCoreSystemBackend(t *testing.T) (*Core, logical.Backend, string) {
 	t.Helper()
 	c, _, root := TestCoreUnsealed(t)
@@ -3619,7 +3925,66 @@ func TestSystemBackend_ToolsHash(t *testing.T) {
 	req.Data["algorithm"] = "sha3-512"
 	doRequest(req, false, "f7cac5ad830422a5408b36a60a60620687be180765a3e2895bc3bdbd857c9e08246c83064d4e3612f0cb927f3ead208413ab98624bf7b0617af0f03f62080976")
 
-	// Test bad input/format/algorithm
+	// Test BLAKE2b
+	req.Data["algorithm"] = "blake2b-256"
+	doRequest(req, false, "9ad992086a8d14adf5d8516c38785029661251102a64ebcff25310731fe1857d")
+
+	req.Data["algorithm"] = "blake2b-384"
+	doRequest(req, false, "88516cadaa75e8d2753e5d0b3c22f8d844ef8e9f5af7d489145d2c85bc6fc0eaf20ef91cf4cf29d1ea636d0cfcbf8fb2")
+
+	req.Data["algorithm"] = "blake2b-512"
+	doRequest(req, false, "29003e690ce77aec2d74dc601ea2ac7bdd38d6a4ad494f517cc18949643f497d0ecbacca0974933c0c15412cb366edc789c6612f588a415bd7a57e934b45651a")
+
+	// Test returning as base64url
+	req.Data["algorithm"] = "sha2-512"
+	req.Data["format"] = "base64url"
+	doRequest(req, false, "2dOA8puXrWodkumH2D-loCZTMB4QBt0rzVGvpZqRR-nK7a-JUhq8DwtoKtzUf7USuDQ8g0oy8yb-m-8AVCzohw")
+
+	// Test hashing the input in chunks
+	for algorithm, expected := range map[string]string{
+		"sha2-512":    "d9d380f29b97ad6a1d92e987d83fa5a02653301e1006dd2bcd51afa59a9147e9caedaf89521abc0f0b682adcd47fb512b8343c834a32f326fe9bef00542ce887",
+		"sha3-256":    "e4bd866ec3fa52df3b7842aa97b448bc859a7606cefcdad1715847f4b82a6c93",
+		"blake2b-256": "9ad992086a8d14adf5d8516c38785029661251102a64ebcff25310731fe1857d",
+	} {
+		chunkReq := logical.TestRequest(t, logical.UpdateOperation, "tools/hash/"+algorithm)
+		chunkReq.Data = map[string]interface{}{
+			"input": "dGhlIHF1aWNrIA==",
+			"final": false,
+		}
+		resp, err := b.HandleRequest(namespace.RootContext(nil), chunkReq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		schema.ValidateResponse(
+			t,
+			schema.GetResponseSchema(t, b.(*SystemBackend).Route(chunkReq.Path), chunkReq.Operation),
+			resp,
+			true,
+		)
+		if _, ok := resp.Data["sum"]; ok {
+			t.Fatalf("unexpected sum for a chunk: %#v", resp.Data)
+		}
+		state, ok := resp.Data["state"].(string)
+		if !ok || state == "" {
+			t.Fatalf("no state found in returned data: %#v", resp.Data)
+		}
+
+		chunkReq.Data = map[string]interface{}{
+			"input": "YnJvd24gZm94",
+			"state": state,
+		}
+		doRequest(chunkReq, false, expected)
+
+		// A state cannot be resumed with another algorithm
+		chunkReq.Path = "tools/hash/sha2-384"
+		doRequest(chunkReq, true, "")
+	}
+
+	// Test bad input/format/algorithm/state
+	req.Data["state"] = "foobar"
+	doRequest(req, true, "")
+
+	delete(req.Data, "state")
 	req.Data["format"] = "base92"
 	doRequest(req, true, "")
 
@@ -3683,6 +4048,8 @@ func TestSystemBackend_ToolsRandom(t *testing.T) {
 			switch format {
 			case "base64":
 				outputBytes, err = base64.StdEncoding.DecodeString(outputStr)
+			case "base64url":
+				outputBytes, err = base64.RawURLEncoding.DecodeString(outputStr)
 			case "hex":
 				outputBytes, err = hex.DecodeString(outputStr)
 			default:
@@ -3717,6 +4084,9 @@ func TestSystemBackend_ToolsRandom(t *testing.T) {
 	req.Data["format"] = "hex"
 	doRequest(req, false, "hex", 24)
 
+	req.Data["format"] = "base64url"
+	doRequest(req, false, "base64url", 24)
+
 	// Test bad input/format
 	req.Path = "tools/random"
 	req.Data["format"] = "base92"
@@ -4150,6 +4520,77 @@ func TestSystemBackend_OpenAPI(t *testing.T) {
 	}
 }
 
+func TestSystemBackend_OpenAPIFilterByCapabilities(t *testing.T) {
+	core, b, rootToken := testCoreSystemBackend(t)
+
+	policy, _ := ParseACLPolicy(namespace.RootNamespace, `
+name = "openapi"
+path "identity/group/id/*" {
+	capabilities = ["read"]
+}
+path "sys/policy" {
+	capabilities = ["read"]
+}
+`)
+	if err := core.policyStore.SetPolicy(namespace.RootContext(nil), policy); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	testMakeServiceTokenViaBackend(t, core.tokenStore, rootToken, "tokenid", "", []string{"openapi"})
+
+	// A token is required to filter the document
+	req := logical.TestRequest(t, logical.ReadOperation, "internal/specs/openapi")
+	req.Data["filter_by_capabilities"] = true
+	resp, err := b.HandleRequest(namespace.RootContext(nil), req)
+	if err != logical.ErrInvalidRequest {
+		t.Fatalf("expected invalid request error, got: %v, resp: %#v", err, resp)
+	}
+
+	req = logical.TestRequest(t, logical.ReadOperation, "internal/specs/openapi")
+	req.Data["filter_by_capabilities"] = true
+	req.ClientToken = "tokenid"
+	resp, err = b.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	var oapi map[string]interface{}
+	if err := jsonutil.DecodeJSON(resp.Data["http_raw_body"].([]byte), &oapi); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	doc, err := framework.NewOASDocumentFromMap(oapi)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pathSamples := []struct {
+		path   string
+		get    bool
+		post   bool
+		delete bool
+	}{
+		{path: "/identity/group/id/{id}", get: true},
+		{path: "/sys/policy", get: true},
+		{path: "/cubbyhole/{path}", get: true, post: true, delete: true},
+		{path: "/sys/health", get: true},
+	}
+	for _, sample := range pathSamples {
+		item := doc.Paths[sample.path]
+		if item == nil {
+			t.Fatalf("didn't find expected path %q", sample.path)
+		}
+		if (item.Get != nil) != sample.get || (item.Post != nil) != sample.post || (item.Delete != nil) != sample.delete {
+			t.Fatalf("path: %s; unexpected operations: get: %t, post: %t, delete: %t",
+				sample.path, item.Get != nil, item.Post != nil, item.Delete != nil)
+		}
+	}
+
+	for _, path := range []string{"/sys/mounts", "/sys/policy/{name}", "/auth/token/create-orphan"} {
+		if doc.Paths[path] != nil {
+			t.Fatalf("expected path %q to be filtered out", path)
+		}
+	}
+}
+
 func TestSystemBackend_PathWildcardPreflight(t *testing.T) {
 	core, b, _ := testCoreSystemBackend(t)
 
@@ -0,0 +1,176 @@
+package vault
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+func (b *SystemBackend) ttlPolicyPaths() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "config/ttl-policies/?$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "ttl-policies",
+				OperationVerb:   "list",
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback: b.handleTTLPolicyList,
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"keys": {
+									Type:     framework.TypeStringSlice,
+									Required: true,
+								},
+							},
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["config/ttl-policies"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["config/ttl-policies"][1]),
+		},
+
+		{
+			Pattern: "config/ttl-policies/" + framework.GenericNameRegex("name") + "$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "ttl-policies",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the TTL policy.",
+				},
+				"path": {
+					Type:        framework.TypeString,
+					Description: "Glob matched against the request path, prefixed by the path of its namespace, such as database/creds/* or auth/oidc/login*.",
+				},
+				"groups": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "Names or IDs of the identity groups the TTL policy applies to. If empty, it applies to any requester.",
+				},
+				"ttl": {
+					Type:        framework.TypeDurationSecond,
+					Description: "TTL replacing the default TTL of the mount.",
+				},
+				"max_ttl": {
+					Type:        framework.TypeDurationSecond,
+					Description: "Maximum TTL of the leases and tokens, renewals included. It cannot raise the maximum TTL of the mount.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleTTLPolicyRead,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "read",
+					},
+					Summary: "Read a TTL policy.",
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleTTLPolicyWrite,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "write",
+					},
+					Summary: "Create or update a TTL policy.",
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.handleTTLPolicyDelete,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "delete",
+					},
+					Summary: "Delete a TTL policy.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["config/ttl-policies-name"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["config/ttl-policies-name"][1]),
+		},
+	}
+}
+
+func (b *SystemBackend) handleTTLPolicyList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	policies, err := b.Core.loadTTLPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(policies))
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return logical.ListResponse(names), nil
+}
+
+func (b *SystemBackend) handleTTLPolicyRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	policies, err := b.Core.loadTTLPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, ok := policies[d.Get("name").(string)]
+	if !ok {
+		return nil, nil
+	}
+	return &logical.Response{
+		Data: policy.toMap(),
+	}, nil
+}
+
+func (b *SystemBackend) handleTTLPolicyWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+
+	policies, err := b.Core.loadTTLPolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Start from a copy of the existing policy, as the cached one is in use
+	policy := &TTLPolicy{Name: name}
+	if existing, ok := policies[name]; ok {
+		*policy = *existing
+	}
+
+	if pathRaw, ok := d.GetOk("path"); ok {
+		policy.Path = strings.TrimPrefix(pathRaw.(string), "/")
+	}
+	if groupsRaw, ok := d.GetOk("groups"); ok {
+		policy.Groups = groupsRaw.([]string)
+	}
+	if ttlRaw, ok := d.GetOk("ttl"); ok {
+		policy.TTL = time.Duration(ttlRaw.(int)) * time.Second
+	}
+	if maxTTLRaw, ok := d.GetOk("max_ttl"); ok {
+		policy.MaxTTL = time.Duration(maxTTLRaw.(int)) * time.Second
+	}
+
+	if err := policy.validate(); err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	if err := b.Core.putTTLPolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (b *SystemBackend) handleTTLPolicyDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if err := b.Core.deleteTTLPolicy(ctx, d.Get("name").(string)); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
@@ -0,0 +1,295 @@
+package vault
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+func (b *SystemBackend) loginBannerPaths() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "config/ui/banners/?$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "ui-banners",
+				OperationVerb:   "list",
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback: b.handleLoginBannerList,
+					Responses: map[int][]framework.Response{
+						http.StatusOK: {{
+							Description: "OK",
+							Fields: map[string]*framework.FieldSchema{
+								"keys": {
+									Type:     framework.TypeStringSlice,
+									Required: true,
+								},
+							},
+						}},
+					},
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["config/ui/banners"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["config/ui/banners"][1]),
+		},
+
+		{
+			Pattern: "config/ui/banners/" + framework.GenericNameRegex("name") + "$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "ui-banners",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"name": {
+					Type:        framework.TypeString,
+					Description: "Name of the login banner.",
+				},
+				"title": {
+					Type:        framework.TypeString,
+					Description: "Title of the login banner.",
+				},
+				"message": {
+					Type:        framework.TypeString,
+					Description: "Message of the login banner, such as terms of use.",
+				},
+				"require_acknowledgment": {
+					Type:        framework.TypeBool,
+					Description: "If true, users must acknowledge the banner to log in to the auth methods of auth_method_types.",
+				},
+				"acknowledgment_period": {
+					Type:        framework.TypeDurationSecond,
+					Default:     int(defaultLoginBannerAckPeriod.Seconds()),
+					Description: "Period after which users must acknowledge the banner again. If zero, users acknowledge each version of the banner once.",
+				},
+				"auth_method_types": {
+					Type:        framework.TypeCommaStringSlice,
+					Default:     defaultLoginBannerAuthTypes,
+					Description: "Types of the auth methods the acknowledgment is required for. Defaults to the interactive auth methods.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleLoginBannerRead,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "read",
+					},
+					Summary: "Read a login banner.",
+				},
+				logical.UpdateOperation: &framework.PathOperation{
+					Callback: b.handleLoginBannerWrite,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "write",
+					},
+					Summary: "Create or update a login banner.",
+				},
+				logical.DeleteOperation: &framework.PathOperation{
+					Callback: b.handleLoginBannerDelete,
+					DisplayAttrs: &framework.DisplayAttributes{
+						OperationVerb: "delete",
+					},
+					Summary: "Delete a login banner.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["config/ui/banners-name"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["config/ui/banners-name"][1]),
+		},
+
+		{
+			Pattern: "config/ui/banner-acknowledgments/" + framework.GenericNameRegex("entity_id") + "$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "ui-banners",
+				OperationVerb:   "read",
+				OperationSuffix: "acknowledgments",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"entity_id": {
+					Type:        framework.TypeString,
+					Description: "ID of the entity.",
+				},
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleLoginBannerAcksRead,
+					Summary:  "Read the acknowledgments of the login banners by an entity.",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["config/ui/banner-acknowledgments"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["config/ui/banner-acknowledgments"][1]),
+		},
+
+		{
+			Pattern: "internal/ui/banners$",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "internal-ui",
+				OperationVerb:   "list",
+				OperationSuffix: "banners",
+			},
+
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleInternalUIBannersRead,
+					Summary:  "Backwards compatibility is not guaranteed for this API",
+				},
+			},
+
+			HelpSynopsis:    strings.TrimSpace(sysHelp["internal-ui-banners"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["internal-ui-banners"][1]),
+		},
+	}
+}
+
+func (b *SystemBackend) handleLoginBannerList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	banners, err := b.Core.loadLoginBanners(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(banners))
+	for name := range banners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return logical.ListResponse(names), nil
+}
+
+func (b *SystemBackend) handleLoginBannerRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	banners, err := b.Core.loadLoginBanners(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	banner, ok := banners[d.Get("name").(string)]
+	if !ok {
+		return nil, nil
+	}
+	return &logical.Response{
+		Data: banner.toMap(),
+	}, nil
+}
+
+func (b *SystemBackend) handleLoginBannerWrite(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	name := d.Get("name").(string)
+
+	banners, err := b.Core.loadLoginBanners(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Start from a copy of the existing banner, as the cached one is in use
+	banner := &LoginBanner{
+		Name:            name,
+		AckPeriod:       time.Duration(d.Get("acknowledgment_period").(int)) * time.Second,
+		AuthMethodTypes: d.Get("auth_method_types").([]string),
+	}
+	existing, ok := banners[name]
+	if ok {
+		*banner = *existing
+	}
+
+	if titleRaw, ok := d.GetOk("title"); ok {
+		banner.Title = titleRaw.(string)
+	}
+	if messageRaw, ok := d.GetOk("message"); ok {
+		banner.Message = messageRaw.(string)
+	}
+	if requireAckRaw, ok := d.GetOk("require_acknowledgment"); ok {
+		banner.RequireAck = requireAckRaw.(bool)
+	}
+	if periodRaw, ok := d.GetOk("acknowledgment_period"); ok {
+		banner.AckPeriod = time.Duration(periodRaw.(int)) * time.Second
+	}
+	if typesRaw, ok := d.GetOk("auth_method_types"); ok {
+		banner.AuthMethodTypes = typesRaw.([]string)
+	}
+	if err := banner.validate(); err != nil {
+		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+	}
+
+	// Changing the terms requires users to acknowledge them again
+	if existing == nil || existing.Title != banner.Title || existing.Message != banner.Message {
+		banner.Version++
+	}
+
+	if err := b.Core.putLoginBanner(ctx, banner); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (b *SystemBackend) handleLoginBannerDelete(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if err := b.Core.deleteLoginBanner(ctx, d.Get("name").(string)); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
+
+func (b *SystemBackend) handleLoginBannerAcksRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	acks, err := b.Core.loginBannerAcks(ctx, d.Get("entity_id").(string))
+	if err != nil {
+		return nil, err
+	}
+	if len(acks) == 0 {
+		return nil, nil
+	}
+
+	data := make(map[string]interface{}, len(acks))
+	for name, ack := range acks {
+		data[name] = map[string]interface{}{
+			"version": ack.Version,
+			"time":    ack.Time.UTC().Format(time.RFC3339),
+		}
+	}
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"acknowledgments": data,
+		},
+	}, nil
+}
+
+func (b *SystemBackend) handleInternalUIBannersRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	banners, err := b.Core.loadLoginBanners(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(banners))
+	for name := range banners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := make([]map[string]interface{}, 0, len(names))
+	for _, name := range names {
+		banner := banners[name]
+		out = append(out, map[string]interface{}{
+			"name":                   banner.Name,
+			"title":                  banner.Title,
+			"message":                banner.Message,
+			"version":                banner.Version,
+			"require_acknowledgment": banner.RequireAck,
+			"auth_method_types":      banner.AuthMethodTypes,
+		})
+	}
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"banners": out,
+		},
+	}, nil
+}
@@ -0,0 +1,323 @@
+package vault
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openbao/openbao/helper/identity"
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/helper/strutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+// defaultDashboardIssuerExpiry is the default window in which PKI issuers are
+// reported as expiring by the UI dashboard.
+const defaultDashboardIssuerExpiry = 30 * 24 * time.Hour
+
+func (b *SystemBackend) internalUIDashboardPaths() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "internal/ui/dashboard/mounts",
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "internal-ui",
+				OperationVerb:   "read",
+				OperationSuffix: "dashboard-mounts",
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.pathInternalUIDashboardMountsRead,
+					Summary:  "Backwards compatibility is not guaranteed for this API",
+				},
+			},
+			HelpSynopsis:    strings.TrimSpace(sysHelp["internal-ui-dashboard-mounts"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["internal-ui-dashboard-mounts"][1]),
+		},
+		{
+			Pattern: "internal/ui/dashboard/activity",
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "internal-ui",
+				OperationVerb:   "read",
+				OperationSuffix: "dashboard-activity",
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.pathInternalUIDashboardActivityRead,
+					Summary:  "Backwards compatibility is not guaranteed for this API",
+				},
+			},
+			HelpSynopsis:    strings.TrimSpace(sysHelp["internal-ui-dashboard-activity"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["internal-ui-dashboard-activity"][1]),
+		},
+		{
+			Pattern: "internal/ui/dashboard/pki-issuers",
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "internal-ui",
+				OperationVerb:   "read",
+				OperationSuffix: "dashboard-pki-issuers",
+			},
+			Fields: map[string]*framework.FieldSchema{
+				"expiring_within": {
+					Type:        framework.TypeDurationSecond,
+					Description: "Window in which issuers are reported as expiring. Defaults to 30 days.",
+					Query:       true,
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.pathInternalUIDashboardPKIIssuersRead,
+					Summary:  "Backwards compatibility is not guaranteed for this API",
+				},
+			},
+			HelpSynopsis:    strings.TrimSpace(sysHelp["internal-ui-dashboard-pki-issuers"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["internal-ui-dashboard-pki-issuers"][1]),
+		},
+	}
+}
+
+// dashboardMount is a mount of the namespace of the request visible to its
+// token, along with its path relative to the namespace.
+type dashboardMount struct {
+	entry   *MountEntry
+	apiPath string
+}
+
+// dashboardMounts returns the secret and auth mounts of the namespace of the
+// request which the token of the request has access to, along with its ACL.
+func (b *SystemBackend) dashboardMounts(ctx context.Context, req *logical.Request) ([]*dashboardMount, *ACL, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	acl, te, entity, _, err := b.Core.fetchACLTokenEntryAndEntity(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if entity != nil && entity.Disabled {
+		b.logger.Warn("permission denied as the entity on the token is disabled")
+		return nil, nil, logical.ErrPermissionDenied
+	}
+	if te != nil && te.EntityID != "" && entity == nil {
+		b.logger.Warn("permission denied as the entity on the token is invalid")
+		return nil, nil, logical.ErrPermissionDenied
+	}
+
+	var mounts []*dashboardMount
+
+	b.Core.mountsLock.RLock()
+	for _, entry := range b.Core.mounts.Entries {
+		if ns.ID == entry.NamespaceID && hasMountAccess(ctx, acl, entry.Namespace().Path+entry.Path) {
+			mounts = append(mounts, &dashboardMount{entry: entry, apiPath: entry.Path})
+		}
+	}
+	b.Core.mountsLock.RUnlock()
+
+	b.Core.authLock.RLock()
+	for _, entry := range b.Core.auth.Entries {
+		if ns.ID == entry.NamespaceID && hasMountAccess(ctx, acl, entry.Namespace().Path+entry.Table+"/"+entry.Path) {
+			mounts = append(mounts, &dashboardMount{entry: entry, apiPath: credentialRoutePrefix + entry.Path})
+		}
+	}
+	b.Core.authLock.RUnlock()
+
+	return mounts, acl, nil
+}
+
+func (b *SystemBackend) pathInternalUIDashboardMountsRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mounts, _, err := b.dashboardMounts(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Count the leases of the namespace by mount. The leases of auth mounts
+	// are the tokens issued by their logins.
+	leaseCounts := make(map[string]int)
+	b.Core.stateLock.RLock()
+	expiration := b.Core.expiration
+	b.Core.stateLock.RUnlock()
+	if expiration != nil {
+		err := expiration.walkLeases(func(leaseID string, _ time.Time) bool {
+			if _, nsID := namespace.SplitIDFromString(leaseID); nsID != "" && nsID != ns.ID {
+				return true
+			} else if nsID == "" && ns.ID != namespace.RootNamespaceID {
+				return true
+			}
+			if entry := b.Core.router.MatchingMountEntry(ctx, leaseID); entry != nil {
+				leaseCounts[entry.Accessor]++
+			}
+			return true
+		})
+		if err != nil {
+			return nil, suppressRestoreModeError(err)
+		}
+	}
+
+	secretMounts := make(map[string]interface{})
+	authMounts := make(map[string]interface{})
+	txn := b.Core.identityStore.db.Txn(false)
+	for _, mount := range mounts {
+		info := map[string]interface{}{
+			"type":        mount.entry.Type,
+			"description": mount.entry.Description,
+			"accessor":    mount.entry.Accessor,
+			"local":       mount.entry.Local,
+			"lease_count": leaseCounts[mount.entry.Accessor],
+		}
+		if mount.entry.Table != credentialTableType {
+			secretMounts[mount.entry.Path] = info
+			continue
+		}
+
+		iter, err := txn.Get(entityAliasesTable, "factors_prefix", mount.entry.Accessor)
+		if err != nil {
+			return nil, err
+		}
+		var aliasCount int
+		for raw := iter.Next(); raw != nil; raw = iter.Next() {
+			if raw.(*identity.Alias).MountAccessor == mount.entry.Accessor {
+				aliasCount++
+			}
+		}
+		info["entity_alias_count"] = aliasCount
+		authMounts[mount.entry.Path] = info
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"secret": secretMounts,
+			"auth":   authMounts,
+		},
+	}, nil
+}
+
+func (b *SystemBackend) pathInternalUIDashboardActivityRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	mounts, _, err := b.dashboardMounts(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	activity := make(map[string]interface{}, len(mounts))
+	for _, mount := range mounts {
+		requests, errors, lastRequest := b.Core.mountActivity.recent(mount.entry.Accessor, now)
+		info := map[string]interface{}{
+			"type":     mount.entry.Type,
+			"accessor": mount.entry.Accessor,
+			"requests": requests,
+			"errors":   errors,
+		}
+		if !lastRequest.IsZero() {
+			info["last_request_time"] = lastRequest.Format(time.RFC3339)
+		}
+		activity[mount.apiPath] = info
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"window_seconds": int64((mountActivityBuckets * mountActivityBucket).Seconds()),
+			"mounts":         activity,
+		},
+	}, nil
+}
+
+func (b *SystemBackend) pathInternalUIDashboardPKIIssuersRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	expiringWithin := defaultDashboardIssuerExpiry
+	if expiringWithinRaw, ok := d.GetOk("expiring_within"); ok {
+		expiringWithin = time.Duration(expiringWithinRaw.(int)) * time.Second
+	}
+
+	mounts, acl, err := b.dashboardMounts(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	type expiringIssuer struct {
+		notAfter time.Time
+		info     map[string]interface{}
+	}
+
+	now := time.Now()
+	var expiring []*expiringIssuer
+	for _, mount := range mounts {
+		if mount.entry.Type != "pki" {
+			continue
+		}
+
+		// Only report the issuers of the mounts whose issuers the token can
+		// read.
+		capabilities := acl.Capabilities(ctx, mount.entry.Namespace().Path+mount.entry.Path+"issuer/")
+		if !strutil.StrListContains(capabilities, RootCapability) && !strutil.StrListContains(capabilities, ReadCapability) {
+			continue
+		}
+
+		listResp, err := b.Core.router.Route(ctx, &logical.Request{
+			Operation: logical.ListOperation,
+			Path:      mount.entry.Path + "issuers",
+		})
+		if err != nil || listResp == nil || listResp.IsError() {
+			b.logger.Warn("failed to list PKI issuers for the UI dashboard", "mount", mount.entry.Path, "error", err)
+			continue
+		}
+		issuerIDs, _ := listResp.Data["keys"].([]string)
+
+		for _, issuerID := range issuerIDs {
+			issuerResp, err := b.Core.router.Route(ctx, &logical.Request{
+				Operation: logical.ReadOperation,
+				Path:      mount.entry.Path + "issuer/" + issuerID,
+			})
+			if err != nil || issuerResp == nil || issuerResp.IsError() {
+				b.logger.Warn("failed to read PKI issuer for the UI dashboard", "mount", mount.entry.Path, "issuer_id", issuerID, "error", err)
+				continue
+			}
+
+			certPEM, _ := issuerResp.Data["certificate"].(string)
+			block, _ := pem.Decode([]byte(certPEM))
+			if block == nil {
+				continue
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				continue
+			}
+			if cert.NotAfter.Sub(now) > expiringWithin {
+				continue
+			}
+
+			expiring = append(expiring, &expiringIssuer{
+				notAfter: cert.NotAfter,
+				info: map[string]interface{}{
+					"mount":       mount.entry.Path,
+					"issuer_id":   issuerID,
+					"issuer_name": issuerResp.Data["issuer_name"],
+					"common_name": cert.Subject.CommonName,
+					"not_after":   cert.NotAfter.UTC().Format(time.RFC3339),
+					"expired":     now.After(cert.NotAfter),
+				},
+			})
+		}
+	}
+
+	sort.Slice(expiring, func(i, j int) bool {
+		return expiring[i].notAfter.Before(expiring[j].notAfter)
+	})
+	issuers := make([]map[string]interface{}, 0, len(expiring))
+	for _, issuer := range expiring {
+		issuers = append(issuers, issuer.info)
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			"issuers": issuers,
+		},
+	}, nil
+}
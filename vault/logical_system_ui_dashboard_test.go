@@ -0,0 +1,67 @@
+package vault
+
+import (
+	"testing"
+
+	logicalPki "github.com/openbao/openbao/builtin/logical/pki"
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+func TestSystemBackend_InternalUIDashboard(t *testing.T) {
+	AddTestLogicalBackend("pki", logicalPki.Factory)
+
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	request := func(op logical.Operation, path string, data map[string]interface{}) *logical.Response {
+		t.Helper()
+		req := logical.TestRequest(t, op, path)
+		req.ClientToken = root
+		req.Data = data
+		resp, err := c.HandleRequest(ctx, req)
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("%s: err:%v resp:%#v", path, err, resp)
+		}
+		return resp
+	}
+
+	request(logical.UpdateOperation, "sys/mounts/pki", map[string]interface{}{"type": "pki"})
+	request(logical.UpdateOperation, "pki/root/generate/internal", map[string]interface{}{
+		"common_name": "example.com",
+		"ttl":         "24h",
+	})
+	// Failed requests are counted as errors.
+	req := logical.TestRequest(t, logical.ReadOperation, "pki/issuer/missing")
+	req.ClientToken = root
+	if resp, err := c.HandleRequest(ctx, req); err == nil && (resp == nil || !resp.IsError()) {
+		t.Fatalf("expected an error, resp:%#v", resp)
+	}
+
+	resp := request(logical.ReadOperation, "sys/internal/ui/dashboard/mounts", nil)
+	pkiMount, ok := resp.Data["secret"].(map[string]interface{})["pki/"].(map[string]interface{})
+	if !ok || pkiMount["type"] != "pki" {
+		t.Fatalf("bad mounts: %#v", resp.Data)
+	}
+	tokenMount, ok := resp.Data["auth"].(map[string]interface{})["token/"].(map[string]interface{})
+	if !ok || tokenMount["entity_alias_count"] != 0 {
+		t.Fatalf("bad mounts: %#v", resp.Data)
+	}
+
+	resp = request(logical.ReadOperation, "sys/internal/ui/dashboard/activity", nil)
+	pkiActivity, ok := resp.Data["mounts"].(map[string]interface{})["pki/"].(map[string]interface{})
+	if !ok || pkiActivity["requests"] != uint64(2) || pkiActivity["errors"] != uint64(1) || pkiActivity["last_request_time"] == nil {
+		t.Fatalf("bad activity: %#v", resp.Data)
+	}
+
+	resp = request(logical.ReadOperation, "sys/internal/ui/dashboard/pki-issuers", nil)
+	issuers := resp.Data["issuers"].([]map[string]interface{})
+	if len(issuers) != 1 || issuers[0]["common_name"] != "example.com" || issuers[0]["expired"] != false {
+		t.Fatalf("bad issuers: %#v", resp.Data)
+	}
+
+	resp = request(logical.ReadOperation, "sys/internal/ui/dashboard/pki-issuers", map[string]interface{}{"expiring_within": "1h"})
+	if issuers := resp.Data["issuers"].([]map[string]interface{}); len(issuers) != 0 {
+		t.Fatalf("bad issuers: %#v", resp.Data)
+	}
+}
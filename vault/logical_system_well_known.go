@@ -0,0 +1,111 @@
+package vault
+
+import (
+	"context"
+	"strings"
+
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+func (b *SystemBackend) wellKnownPaths() []*framework.Path {
+	return []*framework.Path{
+		{
+			Pattern: "well-known/?$",
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "well-known",
+				OperationVerb:   "list",
+				OperationSuffix: "labels",
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ListOperation: &framework.PathOperation{
+					Callback: b.handleWellKnownList,
+					Summary:  "List the paths under /.well-known/ claimed by the mounts.",
+				},
+			},
+			HelpSynopsis:    strings.TrimSpace(sysHelp["well-known"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["well-known"][1]),
+		},
+		{
+			Pattern: "well-known/(?P<label>.+)",
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: "well-known",
+				OperationVerb:   "read",
+				OperationSuffix: "label",
+			},
+			Fields: map[string]*framework.FieldSchema{
+				"label": {
+					Type:        framework.TypeString,
+					Description: "The path under /.well-known/ claimed by a mount.",
+				},
+			},
+			Operations: map[logical.Operation]framework.OperationHandler{
+				logical.ReadOperation: &framework.PathOperation{
+					Callback: b.handleWellKnownRead,
+					Summary:  "Read the registration of a path under /.well-known/.",
+				},
+			},
+			HelpSynopsis:    strings.TrimSpace(sysHelp["well-known-label"][0]),
+			HelpDescription: strings.TrimSpace(sysHelp["well-known-label"][1]),
+		},
+	}
+}
+
+// checkWellKnownNamespace refuses the requests outside of the root namespace,
+// as the paths under /.well-known/ are shared by all the namespaces.
+func checkWellKnownNamespace(ctx context.Context) (*logical.Response, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if ns.ID != namespace.RootNamespaceID {
+		return logical.ErrorResponse("well-known registrations can only be read from the root namespace"), logical.ErrInvalidRequest
+	}
+	return nil, nil
+}
+
+func (b *SystemBackend) handleWellKnownList(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if resp, err := checkWellKnownNamespace(ctx); resp != nil || err != nil {
+		return resp, err
+	}
+
+	labels := b.Core.wellKnownRedirects.List()
+	keyInfo := make(map[string]interface{}, len(labels))
+	for _, label := range labels {
+		redirect := b.Core.wellKnownRedirects.Get(label)
+		if redirect == nil {
+			continue
+		}
+		keyInfo[label] = wellKnownRedirectInfo(label, redirect)
+	}
+
+	return logical.ListResponseWithInfo(labels, keyInfo), nil
+}
+
+func (b *SystemBackend) handleWellKnownRead(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if resp, err := checkWellKnownNamespace(ctx); resp != nil || err != nil {
+		return resp, err
+	}
+
+	label := d.Get("label").(string)
+	redirect := b.Core.wellKnownRedirects.Get(label)
+	if redirect == nil {
+		return nil, nil
+	}
+
+	return &logical.Response{
+		Data: wellKnownRedirectInfo(strings.Trim(label, "/"), redirect),
+	}, nil
+}
+
+func wellKnownRedirectInfo(label string, redirect *wellKnownRedirect) map[string]interface{} {
+	// The mount path is left empty if the mount is being removed
+	mountPath, _ := redirect.MountPath()
+	return map[string]interface{}{
+		"label":      label,
+		"mount_uuid": redirect.mountUUID,
+		"mount_path": mountPath,
+		"prefix":     redirect.prefix,
+	}
+}
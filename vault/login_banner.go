@@ -0,0 +1,269 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-secure-stdlib/strutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const (
+	// loginBannerSubPath is the sub-path of the system view used to store
+	// the login banners.
+	loginBannerSubPath = "config/ui/banners/"
+
+	// loginBannerAckSubPath is the sub-path of the system view used to store
+	// the acknowledgments of the login banners, by entity ID.
+	loginBannerAckSubPath = "ui-banner-acknowledgments/"
+
+	defaultLoginBannerAckPeriod = 24 * time.Hour
+)
+
+// defaultLoginBannerAuthTypes are the interactive auth methods login banners
+// apply to by default.
+var defaultLoginBannerAuthTypes = []string{"userpass", "ldap", "jwt", "oidc", "radius", "kerberos"}
+
+// LoginBanner is a message shown to users before they log in. Banners
+// requiring acknowledgment are enforced on the logins to the auth methods of
+// AuthMethodTypes: the user must acknowledge the current version of the
+// banner once per AckPeriod.
+type LoginBanner struct {
+	Name    string `json:"name"`
+	Title   string `json:"title,omitempty"`
+	Message string `json:"message"`
+
+	// Version is incremented each time the title or message changes, so
+	// that users acknowledge the new terms.
+	Version int `json:"version"`
+
+	RequireAck      bool          `json:"require_acknowledgment"`
+	AckPeriod       time.Duration `json:"acknowledgment_period,omitempty"`
+	AuthMethodTypes []string      `json:"auth_method_types"`
+}
+
+func (b *LoginBanner) validate() error {
+	switch {
+	case b.Message == "":
+		return errors.New("message is required")
+	case b.AckPeriod < 0:
+		return errors.New("acknowledgment_period cannot be negative")
+	case b.RequireAck && len(b.AuthMethodTypes) == 0:
+		return errors.New("auth_method_types cannot be empty when acknowledgment is required")
+	}
+	return nil
+}
+
+// appliesTo returns whether the acknowledgment of the banner is required to
+// log in to an auth method of the given type.
+func (b *LoginBanner) appliesTo(authType string) bool {
+	return b.RequireAck && strutil.StrListContains(b.AuthMethodTypes, authType)
+}
+
+func (b *LoginBanner) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"name":                   b.Name,
+		"title":                  b.Title,
+		"message":                b.Message,
+		"version":                b.Version,
+		"require_acknowledgment": b.RequireAck,
+		"acknowledgment_period":  int64(b.AckPeriod.Seconds()),
+		"auth_method_types":      b.AuthMethodTypes,
+	}
+}
+
+// LoginBannerAck is the acknowledgment of a version of a login banner by an
+// entity.
+type LoginBannerAck struct {
+	Version int       `json:"version"`
+	Time    time.Time `json:"time"`
+}
+
+func (c *Core) loginBannerView() *BarrierView {
+	return c.systemBarrierView.SubView(loginBannerSubPath)
+}
+
+// loadLoginBanners returns the login banners, loading them from storage on
+// first use after unseal.
+func (c *Core) loadLoginBanners(ctx context.Context) (map[string]*LoginBanner, error) {
+	c.loginBannersLock.RLock()
+	banners := c.loginBanners
+	c.loginBannersLock.RUnlock()
+	if banners != nil {
+		return banners, nil
+	}
+
+	c.loginBannersLock.Lock()
+	defer c.loginBannersLock.Unlock()
+	if c.loginBanners != nil {
+		return c.loginBanners, nil
+	}
+
+	view := c.loginBannerView()
+	names, err := view.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	banners = make(map[string]*LoginBanner, len(names))
+	for _, name := range names {
+		entry, err := view.Get(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			continue
+		}
+		banner := new(LoginBanner)
+		if err := entry.DecodeJSON(banner); err != nil {
+			return nil, fmt.Errorf("failed to decode login banner %q: %w", name, err)
+		}
+		banners[name] = banner
+	}
+
+	c.loginBanners = banners
+	return banners, nil
+}
+
+// putLoginBanner stores the given login banner, which must have been
+// validated.
+func (c *Core) putLoginBanner(ctx context.Context, banner *LoginBanner) error {
+	entry, err := logical.StorageEntryJSON(banner.Name, banner)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.loadLoginBanners(ctx); err != nil {
+		return err
+	}
+
+	c.loginBannersLock.Lock()
+	defer c.loginBannersLock.Unlock()
+	if err := c.loginBannerView().Put(ctx, entry); err != nil {
+		return err
+	}
+	if c.loginBanners == nil {
+		// The cache was reset, the banners are loaded again on next use
+		return nil
+	}
+	banners := make(map[string]*LoginBanner, len(c.loginBanners)+1)
+	for name, b := range c.loginBanners {
+		banners[name] = b
+	}
+	banners[banner.Name] = banner
+	c.loginBanners = banners
+	return nil
+}
+
+func (c *Core) deleteLoginBanner(ctx context.Context, name string) error {
+	if _, err := c.loadLoginBanners(ctx); err != nil {
+		return err
+	}
+
+	c.loginBannersLock.Lock()
+	defer c.loginBannersLock.Unlock()
+	if err := c.loginBannerView().Delete(ctx, name); err != nil {
+		return err
+	}
+	if c.loginBanners == nil {
+		return nil
+	}
+	banners := make(map[string]*LoginBanner, len(c.loginBanners))
+	for n, b := range c.loginBanners {
+		if n != name {
+			banners[n] = b
+		}
+	}
+	c.loginBanners = banners
+	return nil
+}
+
+// resetLoginBanners drops the cached login banners, so that they are loaded
+// from storage on next use.
+func (c *Core) resetLoginBanners() {
+	c.loginBannersLock.Lock()
+	defer c.loginBannersLock.Unlock()
+	c.loginBanners = nil
+}
+
+// loginBannerAcks returns the acknowledgments of the login banners by the
+// given entity, by banner name.
+func (c *Core) loginBannerAcks(ctx context.Context, entityID string) (map[string]*LoginBannerAck, error) {
+	acks := make(map[string]*LoginBannerAck)
+	entry, err := c.systemBarrierView.Get(ctx, loginBannerAckSubPath+entityID)
+	if err != nil || entry == nil {
+		return acks, err
+	}
+	if err := entry.DecodeJSON(&acks); err != nil {
+		return nil, fmt.Errorf("failed to decode the login banner acknowledgments of entity %q: %w", entityID, err)
+	}
+	return acks, nil
+}
+
+// checkLoginBanners enforces the acknowledgment of the login banners on a
+// login to an auth method of the given type. The banners not acknowledged by
+// entityID in their period must be in acknowledged, the banners acknowledged
+// with the request, in which case their acknowledgment is recorded. Logins
+// without an entity must acknowledge the banners every time. It returns the
+// names of the banners left to acknowledge, if any.
+func (c *Core) checkLoginBanners(ctx context.Context, authType, entityID string, acknowledged []string) ([]string, error) {
+	banners, err := c.loadLoginBanners(ctx)
+	if err != nil || len(banners) == 0 {
+		return nil, err
+	}
+
+	var acks map[string]*LoginBannerAck
+	now := time.Now()
+	var missing []string
+	changed := false
+	for name, banner := range banners {
+		if !banner.appliesTo(authType) {
+			continue
+		}
+		if acks == nil && entityID != "" {
+			acks, err = c.loginBannerAcks(ctx, entityID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		ack := acks[name]
+		if ack != nil && ack.Version == banner.Version && (banner.AckPeriod == 0 || now.Sub(ack.Time) < banner.AckPeriod) {
+			continue
+		}
+		if !strutil.StrListContains(acknowledged, name) {
+			missing = append(missing, name)
+			continue
+		}
+		if acks != nil {
+			acks[name] = &LoginBannerAck{Version: banner.Version, Time: now}
+			changed = true
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return missing, nil
+	}
+
+	if changed {
+		entry, err := logical.StorageEntryJSON(loginBannerAckSubPath+entityID, acks)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.systemBarrierView.Put(ctx, entry); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// loginBannerError is the error response of logins refused because some
+// login banners were not acknowledged.
+func loginBannerError(missing []string) *logical.Response {
+	return logical.CodedErrorResponse(logical.ErrorCodeBannerAcknowledgmentRequired,
+		"the login banners %s must be acknowledged with the X-OpenBao-Acknowledge-Banners header", strings.Join(missing, ", "))
+}
@@ -0,0 +1,128 @@
+package vault
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCore_CheckLoginBanners(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	require.NoError(t, c.putLoginBanner(ctx, &LoginBanner{
+		Name:            "terms",
+		Message:         "Authorized use only.",
+		Version:         1,
+		RequireAck:      true,
+		AckPeriod:       time.Hour,
+		AuthMethodTypes: []string{"userpass"},
+	}))
+	require.NoError(t, c.putLoginBanner(ctx, &LoginBanner{
+		Name:    "notice",
+		Message: "Maintenance on Sunday.",
+		Version: 1,
+	}))
+
+	// Other auth methods, and banners not requiring acknowledgment, are not
+	// enforced
+	missing, err := c.checkLoginBanners(ctx, "approle", "entity", nil)
+	require.NoError(t, err)
+	require.Empty(t, missing)
+
+	missing, err = c.checkLoginBanners(ctx, "userpass", "entity", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"terms"}, missing)
+
+	missing, err = c.checkLoginBanners(ctx, "userpass", "entity", []string{"terms"})
+	require.NoError(t, err)
+	require.Empty(t, missing)
+	missing, err = c.checkLoginBanners(ctx, "userpass", "entity", nil)
+	require.NoError(t, err)
+	require.Empty(t, missing)
+
+	// Logins without an entity acknowledge the banners every time
+	missing, err = c.checkLoginBanners(ctx, "userpass", "", []string{"terms"})
+	require.NoError(t, err)
+	require.Empty(t, missing)
+	missing, err = c.checkLoginBanners(ctx, "userpass", "", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"terms"}, missing)
+
+	// Acknowledgments expire at the end of the period
+	acks, err := c.loginBannerAcks(ctx, "entity")
+	require.NoError(t, err)
+	acks["terms"].Time = time.Now().Add(-2 * time.Hour)
+	entry, err := logical.StorageEntryJSON(loginBannerAckSubPath+"entity", acks)
+	require.NoError(t, err)
+	require.NoError(t, c.systemBarrierView.Put(ctx, entry))
+	missing, err = c.checkLoginBanners(ctx, "userpass", "entity", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"terms"}, missing)
+}
+
+func TestSystemBackend_LoginBanners(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	request := func(op logical.Operation, path string, data map[string]interface{}) (*logical.Response, error) {
+		t.Helper()
+		req := logical.TestRequest(t, op, path)
+		req.Data = data
+		req.ClientToken = root
+		return c.HandleRequest(ctx, req)
+	}
+
+	_, err := request(logical.UpdateOperation, "sys/config/ui/banners/terms", map[string]interface{}{
+		"require_acknowledgment": true,
+	})
+	require.ErrorIs(t, err, logical.ErrInvalidRequest)
+
+	_, err = request(logical.UpdateOperation, "sys/config/ui/banners/terms", map[string]interface{}{
+		"title":                  "Terms of use",
+		"message":                "Authorized use only.",
+		"require_acknowledgment": true,
+	})
+	require.NoError(t, err)
+
+	resp, err := request(logical.ReadOperation, "sys/config/ui/banners/terms", nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, resp.Data["version"])
+	require.Equal(t, int64(defaultLoginBannerAckPeriod.Seconds()), resp.Data["acknowledgment_period"])
+	require.Equal(t, defaultLoginBannerAuthTypes, resp.Data["auth_method_types"])
+
+	// Only changes of the terms require a new acknowledgment
+	_, err = request(logical.UpdateOperation, "sys/config/ui/banners/terms", map[string]interface{}{
+		"acknowledgment_period": "720h",
+	})
+	require.NoError(t, err)
+	resp, err = request(logical.ReadOperation, "sys/config/ui/banners/terms", nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, resp.Data["version"])
+	_, err = request(logical.UpdateOperation, "sys/config/ui/banners/terms", map[string]interface{}{
+		"message": "Authorized use only. Activity is monitored.",
+	})
+	require.NoError(t, err)
+	resp, err = request(logical.ReadOperation, "sys/config/ui/banners/terms", nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, resp.Data["version"])
+
+	resp, err = request(logical.ListOperation, "sys/config/ui/banners", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"terms"}, resp.Data["keys"])
+
+	_, err = c.checkLoginBanners(ctx, "userpass", "entity", []string{"terms"})
+	require.NoError(t, err)
+	resp, err = request(logical.ReadOperation, "sys/config/ui/banner-acknowledgments/entity", nil)
+	require.NoError(t, err)
+	require.Contains(t, resp.Data["acknowledgments"], "terms")
+
+	_, err = request(logical.DeleteOperation, "sys/config/ui/banners/terms", nil)
+	require.NoError(t, err)
+	resp, err = request(logical.ReadOperation, "sys/config/ui/banners/terms", nil)
+	require.NoError(t, err)
+	require.Nil(t, resp)
+}
@@ -162,10 +162,28 @@ func (m MountMigrationStatus) String() string {
 	return "unknown"
 }
 
+// Stages of a mount migration, reported along with its status
+const (
+	MigrationStageQueued         = "queued"
+	MigrationStageRevokingLeases = "revoking-leases"
+	MigrationStageUpdatingQuotas = "updating-quotas"
+	MigrationStageCompleted      = "completed"
+)
+
 type MountMigrationInfo struct {
 	SourceMount     string `json:"source_mount"`
 	TargetMount     string `json:"target_mount"`
 	MigrationStatus string `json:"status"`
+
+	// Stage is the current stage of the migration, and the lease counts
+	// report the progress of the revocation of the leases of the source
+	// mount. EndTime is only set once the migration reaches a final status.
+	Stage         string     `json:"stage"`
+	LeasesTotal   int        `json:"leases_total"`
+	LeasesRevoked int        `json:"leases_revoked"`
+	StartTime     time.Time  `json:"start_time"`
+	EndTime       *time.Time `json:"end_time,omitempty"`
+	Error         string     `json:"error,omitempty"`
 }
 
 // tableMetrics is responsible for setting gauge metrics for
@@ -365,6 +383,28 @@ type MountConfig struct {
 	AllowedManagedKeys        []string              `json:"allowed_managed_keys,omitempty" mapstructure:"allowed_managed_keys"`
 	UserLockoutConfig         *UserLockoutConfig    `json:"user_lockout_config,omitempty" mapstructure:"user_lockout_config"`
 
+	// Protected requires a confirmation for destructive operations on the
+	// mount, and for disabling it.
+	Protected bool `json:"protected,omitempty" structs:"protected" mapstructure:"protected"`
+
+	// DefaultRequestTimeout bounds the time spent handling a request to the
+	// mount, and MaxRequestSize the size of the body of the request. The
+	// router enforces them when they are set.
+	DefaultRequestTimeout time.Duration `json:"default_request_timeout,omitempty" structs:"default_request_timeout" mapstructure:"default_request_timeout"`
+	MaxRequestSize        int64         `json:"max_request_size,omitempty" structs:"max_request_size" mapstructure:"max_request_size"`
+
+	// MaxConcurrentRequests bounds the number of requests to the mount
+	// handled at once; the router rejects the requests beyond it.
+	MaxConcurrentRequests int `json:"max_concurrent_requests,omitempty" structs:"max_concurrent_requests" mapstructure:"max_concurrent_requests"`
+
+	// LazyLoad defers the creation of the backend of a secrets engine from
+	// the unseal to the first request routed to it.
+	LazyLoad bool `json:"lazy_load,omitempty" structs:"lazy_load" mapstructure:"lazy_load"`
+
+	// TokenBindClientCert binds the tokens issued by logins to an auth method
+	// to the client certificate the login request was made with.
+	TokenBindClientCert bool `json:"token_bind_client_cert,omitempty" structs:"token_bind_client_cert" mapstructure:"token_bind_client_cert"`
+
 	// PluginName is the name of the plugin registered in the catalog.
 	//
 	// Deprecated: MountEntry.Type should be used instead for Vault 1.0.0 and beyond.
@@ -399,6 +439,12 @@ type APIMountConfig struct {
 	AllowedManagedKeys        []string              `json:"allowed_managed_keys,omitempty" mapstructure:"allowed_managed_keys"`
 	UserLockoutConfig         *UserLockoutConfig    `json:"user_lockout_config,omitempty" mapstructure:"user_lockout_config"`
 	PluginVersion             string                `json:"plugin_version,omitempty" mapstructure:"plugin_version"`
+	Protected                 bool                  `json:"protected,omitempty" structs:"protected" mapstructure:"protected"`
+	DefaultRequestTimeout     string                `json:"default_request_timeout,omitempty" structs:"default_request_timeout" mapstructure:"default_request_timeout"`
+	MaxRequestSize            int64                 `json:"max_request_size,omitempty" structs:"max_request_size" mapstructure:"max_request_size"`
+	MaxConcurrentRequests     int                   `json:"max_concurrent_requests,omitempty" structs:"max_concurrent_requests" mapstructure:"max_concurrent_requests"`
+	LazyLoad                  bool                  `json:"lazy_load,omitempty" structs:"lazy_load" mapstructure:"lazy_load"`
+	TokenBindClientCert       bool                  `json:"token_bind_client_cert,omitempty" structs:"token_bind_client_cert" mapstructure:"token_bind_client_cert"`
 
 	// PluginName is the name of the plugin registered in the catalog.
 	//
@@ -431,6 +477,12 @@ func (e *MountEntry) IsExternalPlugin() bool {
 	return e.RunningSha256 != ""
 }
 
+// loadsLazily returns whether the creation of the backend of the mount is
+// deferred from the unseal to the first request routed to it.
+func (e *MountEntry) loadsLazily() bool {
+	return e.Table == mountTableType && e.Config.LazyLoad && !strutil.StrListContains(singletonMounts, e.Type)
+}
+
 // MountClass returns the mount class based on Accessor and Path
 func (e *MountEntry) MountClass() string {
 	if e.Accessor == "" || strings.HasPrefix(e.Path, fmt.Sprintf("%s/", mountPathSystem)) {
@@ -775,14 +827,16 @@ func (c *Core) unmount(ctx context.Context, path string) error {
 	}
 
 	// Unmount mount internally
-	if err := c.unmountInternal(ctx, path, MountTableUpdateStorage); err != nil {
+	if err := c.unmountInternal(ctx, path, MountTableUpdateStorage, c.deletedMountRetention()); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (c *Core) unmountInternal(ctx context.Context, path string, updateStorage bool) error {
+// unmountInternal unmounts the given path. If retention is positive, the
+// storage of the mount is retained for that duration rather than cleared.
+func (c *Core) unmountInternal(ctx context.Context, path string, updateStorage bool, retention time.Duration) error {
 	ns, err := namespace.FromContext(ctx)
 	if err != nil {
 		return err
@@ -794,12 +848,19 @@ func (c *Core) unmountInternal(ctx context.Context, path string, updateStorage b
 		return fmt.Errorf("no matching mount")
 	}
 
+	// Load the backend if it was deferred at unseal, so that its leases are
+	// revoked
+	if err := c.router.LoadLazyMount(ctx, path); err != nil {
+		return err
+	}
+
 	// Get the view for this backend
 	view := c.router.MatchingStorageByAPIPath(ctx, path)
 
 	// Get the backend/mount entry for this path, used to remove ignored
 	// replication prefixes
 	backend := c.router.MatchingBackend(ctx, path)
+	entry := c.router.MatchingMountEntry(ctx, path)
 
 	// Mark the entry as tainted
 	if err := c.taintMountEntry(ctx, ns.ID, path, updateStorage, true); err != nil {
@@ -839,6 +900,13 @@ func (c *Core) unmountInternal(ctx context.Context, path string, updateStorage b
 	switch {
 	case !updateStorage:
 		// Don't attempt to clear data, replication will handle this
+	case retention > 0 && entry != nil:
+		// Keep the data so that the mount can be undeleted, it is purged
+		// once the retention has passed
+		if err := c.retainDeletedMount(ctx, entry, retention); err != nil {
+			c.logger.Error("failed to retain storage for path being unmounted", "error", err, "path", path)
+			return err
+		}
 	default:
 		// Have writable storage, remove the whole thing
 		if err := logical.ClearViewWithLogging(ctx, view, c.logger.Named("secrets.deletion").With("namespace", ns.ID, "path", path)); err != nil {
@@ -858,6 +926,10 @@ func (c *Core) unmountInternal(ctx context.Context, path string, updateStorage b
 		return err
 	}
 
+	if entry != nil {
+		c.wellKnownRedirects.DeregisterMount(entry.UUID)
+	}
+
 	if c.quotaManager != nil {
 		if err := c.quotaManager.HandleBackendDisabling(ctx, ns.Path, path); err != nil {
 			c.logger.Error("failed to update quotas after disabling mount", "path", path, "error", err)
@@ -997,7 +1069,7 @@ func (c *Core) remountForceInternal(ctx context.Context, path string, updateStor
 		return err
 	}
 
-	if err := c.unmountInternal(ctx, path, updateStorage); err != nil {
+	if err := c.unmountInternal(ctx, path, updateStorage, 0); err != nil {
 		return err
 	}
 
@@ -1017,11 +1089,12 @@ func (c *Core) remountSecretsEngineCurrentNamespace(ctx context.Context, src, ds
 
 	srcPathDetails := c.splitNamespaceAndMountFromPath(ns.Path, src)
 	dstPathDetails := c.splitNamespaceAndMountFromPath(ns.Path, dst)
-	return c.remountSecretsEngine(ctx, srcPathDetails, dstPathDetails, updateStorage)
+	return c.remountSecretsEngine(ctx, srcPathDetails, dstPathDetails, updateStorage, nil)
 }
 
-// remountSecretsEngine is used to remount a path at a new mount point.
-func (c *Core) remountSecretsEngine(ctx context.Context, src, dst namespace.MountPathDetails, updateStorage bool) error {
+// remountSecretsEngine is used to remount a path at a new mount point. If
+// progress is not nil, it is called as the leases of the mount are revoked.
+func (c *Core) remountSecretsEngine(ctx context.Context, src, dst namespace.MountPathDetails, updateStorage bool, progress func(revoked, total int)) error {
 	ns, err := namespace.FromContext(ctx)
 	if err != nil {
 		return err
@@ -1075,7 +1148,7 @@ func (c *Core) remountSecretsEngine(ctx context.Context, src, dst namespace.Moun
 
 	revokeCtx := namespace.ContextWithNamespace(ctx, src.Namespace)
 	// Revoke all the dynamic keys
-	if err := c.expiration.RevokePrefix(revokeCtx, src.MountPath, true); err != nil {
+	if err := c.expiration.RevokePrefixWithProgress(revokeCtx, src.MountPath, progress); err != nil {
 		return err
 	}
 
@@ -1382,32 +1455,60 @@ func (c *Core) persistMounts(ctx context.Context, table *MountTable, local *bool
 
 // setupMounts is invoked after we've loaded the mount table to
 // initialize the logical backends and setup the router
-func (c *Core) setupMounts(ctx context.Context) error {
+func (c *Core) setupMounts(ctx context.Context) (retErr error) {
 	c.mountsLock.Lock()
 	defer c.mountsLock.Unlock()
 
+	entries := c.mounts.sortEntriesByPathDepth().Entries
+	progress := c.postUnsealProgress.start(postUnsealPhaseMounts, len(entries))
+	defer func() {
+		progress.done(retErr)
+	}()
+
+	backends := c.createSetupBackends(ctx, entries, c.newLogicalBackend)
+	mounted := 0
+	defer func() {
+		if retErr != nil {
+			cleanupSetupBackends(ctx, entries[mounted:], backends)
+		}
+	}()
+
 	var err error
-	for _, entry := range c.mounts.sortEntriesByPathDepth().Entries {
-		// Initialize the backend, special casing for system
-		barrierPath := entry.ViewPath()
+	for _, entry := range entries {
+		setup := backends[entry]
+		view := setup.view
+		origReadOnlyErr := setup.origReadOnlyErr
+
+		// Defer the creation of the backend to the first request routed to
+		// the mount
+		if entry.loadsLazily() {
+			err = c.router.MountLazy(entry.Path, entry, view)
+			if err != nil {
+				c.logger.Error("failed to mount entry", "path", entry.Path, "error", err)
+				return errLoadMountsFailed
+			}
 
-		// Create a barrier storage view using the UUID
-		view := NewBarrierView(c.barrier, barrierPath)
+			if c.logger.IsInfo() {
+				c.logger.Info("deferred loading of mount", "type", entry.Type, "path", entry.Path, "namespace", entry.Namespace())
+			}
 
-		origReadOnlyErr := view.getReadOnlyErr()
+			mounted++
 
-		// Mark the view as read-only until the mounting is complete and
-		// ensure that it is reset after. This ensures that there will be no
-		// writes during the construction of the backend.
-		view.setReadOnlyErr(logical.ErrSetupReadOnly)
+			// Ensure the cache is populated, don't need the result
+			NamespaceByID(ctx, entry.NamespaceID, c)
+			progress.increment()
+			continue
+		}
+
+		// The view is read-only until the mounting is complete, ensure that
+		// it is reset after.
 		if strutil.StrListContains(singletonMounts, entry.Type) {
 			defer view.setReadOnlyErr(origReadOnlyErr)
 		}
 
-		var backend logical.Backend
-		// Create the new backend
-		sysView := c.mountEntrySysView(entry)
-		backend, entry.RunningSha256, err = c.newLogicalBackend(ctx, entry, sysView, view)
+		backend := setup.backend
+		entry.RunningSha256 = setup.runningSha256
+		err = setup.err
 		if err != nil {
 			c.logger.Error("failed to create mount entry", "path", entry.Path, "error", err)
 
@@ -1466,6 +1567,7 @@ func (c *Core) setupMounts(ctx context.Context) error {
 			c.logger.Error("failed to mount entry", "path", entry.Path, "error", err)
 			return errLoadMountsFailed
 		}
+		mounted++
 
 		// Bind locally
 		localEntry := entry
@@ -1500,6 +1602,87 @@ func (c *Core) setupMounts(ctx context.Context) error {
 
 		// Ensure the cache is populated, don't need the result
 		NamespaceByID(ctx, entry.NamespaceID, c)
+		progress.increment()
+	}
+	return nil
+}
+
+// setupBackend is the backend of a mount created ahead of its setup, along
+// with its storage view.
+type setupBackend struct {
+	view            *BarrierView
+	origReadOnlyErr error
+	backend         logical.Backend
+	runningSha256   string
+	err             error
+}
+
+// createSetupBackends creates the backends of the entries ahead of mounting
+// them, except for the ones loaded lazily. The singleton backends are created
+// first and in order as other backends may depend on them, the others are
+// created in parallel as creating plugin backends dominates the setup.
+//
+// The views are read-only until the mounting is complete. This ensures that
+// there will be no writes during the construction of the backends.
+func (c *Core) createSetupBackends(ctx context.Context, entries []*MountEntry, factory func(context.Context, *MountEntry, logical.SystemView, logical.Storage) (logical.Backend, string, error)) map[*MountEntry]*setupBackend {
+	backends := make(map[*MountEntry]*setupBackend, len(entries))
+	create := func(entry *MountEntry) {
+		setup := backends[entry]
+		sysView := c.mountEntrySysView(entry)
+		setup.backend, setup.runningSha256, setup.err = factory(ctx, entry, sysView, setup.view)
+	}
+
+	var parallel []*MountEntry
+	for _, entry := range entries {
+		// Create a barrier storage view using the UUID
+		view := NewBarrierView(c.barrier, entry.ViewPath())
+		backends[entry] = &setupBackend{
+			view:            view,
+			origReadOnlyErr: view.getReadOnlyErr(),
+		}
+		if entry.loadsLazily() {
+			continue
+		}
+
+		view.setReadOnlyErr(logical.ErrSetupReadOnly)
+		if strutil.StrListContains(singletonMounts, entry.Type) {
+			create(entry)
+			continue
+		}
+		parallel = append(parallel, entry)
+	}
+
+	runParallel(c.postUnsealConcurrency(), len(parallel), func(i int) {
+		create(parallel[i])
+	})
+	return backends
+}
+
+// cleanupSetupBackends cleans up the backends created ahead for the entries,
+// when the setup fails before mounting them.
+func cleanupSetupBackends(ctx context.Context, entries []*MountEntry, backends map[*MountEntry]*setupBackend) {
+	for _, entry := range entries {
+		if backend := backends[entry].backend; backend != nil {
+			backend.Cleanup(ctx)
+		}
+	}
+}
+
+// loadLazyMount creates and initializes the backend of a mount whose loading
+// was deferred at unseal.
+func (c *Core) loadLazyMount(ctx context.Context, entry *MountEntry) error {
+	c.mountsLock.RLock()
+	defer c.mountsLock.RUnlock()
+
+	// The backend outlives the request loading it, so it is created with the
+	// active context.
+	ctx = namespace.ContextWithNamespace(c.activeContext, entry.Namespace())
+	if err := c.reloadBackendCommon(ctx, entry, false); err != nil {
+		return err
+	}
+
+	if c.logger.IsInfo() {
+		c.logger.Info("successfully loaded lazy mount", "type", entry.Type, "version", entry.RunningVersion, "path", entry.Path, "namespace", entry.Namespace())
 	}
 	return nil
 }
@@ -1780,18 +1963,35 @@ func (c *Core) createMigrationStatus(from, to namespace.MountPathDetails) (strin
 		SourceMount:     from.Namespace.Path + from.MountPath,
 		TargetMount:     to.Namespace.Path + to.MountPath,
 		MigrationStatus: MigrationInProgressStatus.String(),
+		Stage:           MigrationStageQueued,
+		StartTime:       time.Now().UTC(),
 	}
 	c.mountMigrationTracker.Store(migrationID, migrationInfo)
 	return migrationID, nil
 }
 
 func (c *Core) setMigrationStatus(migrationID string, migrationStatus MountMigrationStatus) error {
+	return c.updateMigrationInfo(migrationID, func(migrationInfo *MountMigrationInfo) {
+		migrationInfo.MigrationStatus = migrationStatus.String()
+		if migrationStatus != MigrationInProgressStatus {
+			endTime := time.Now().UTC()
+			migrationInfo.EndTime = &endTime
+		}
+		if migrationStatus == MigrationSuccessStatus {
+			migrationInfo.Stage = MigrationStageCompleted
+		}
+	})
+}
+
+// updateMigrationInfo applies the given update to the tracked information of
+// a migration.
+func (c *Core) updateMigrationInfo(migrationID string, update func(*MountMigrationInfo)) error {
 	migrationInfoRaw, ok := c.mountMigrationTracker.Load(migrationID)
 	if !ok {
 		return fmt.Errorf("Migration Tracker entry missing for ID %s", migrationID)
 	}
 	migrationInfo := migrationInfoRaw.(MountMigrationInfo)
-	migrationInfo.MigrationStatus = migrationStatus.String()
+	update(&migrationInfo)
 	c.mountMigrationTracker.Store(migrationID, migrationInfo)
 	return nil
 }
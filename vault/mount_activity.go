@@ -0,0 +1,81 @@
+package vault
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// mountActivityBucket is the granularity of the recent activity of
+	// mounts, and mountActivityBuckets the number of buckets kept.
+	mountActivityBucket  = time.Minute
+	mountActivityBuckets = 60
+)
+
+// mountActivityTracker counts the audited requests to each mount on this
+// node, to report their recent activity without reading the audit logs.
+type mountActivityTracker struct {
+	sync.Mutex
+	mounts map[string]*mountActivity
+}
+
+// mountActivity is the recent activity of a mount. Requests are counted in
+// buckets of mountActivityBucket, indexed by their time modulo the number of
+// buckets.
+type mountActivity struct {
+	lastRequest time.Time
+	buckets     [mountActivityBuckets]mountActivityCounts
+}
+
+type mountActivityCounts struct {
+	start    int64
+	requests uint64
+	errors   uint64
+}
+
+// record counts an audited request to the mount with the given accessor.
+func (t *mountActivityTracker) record(accessor string, failed bool, now time.Time) {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.mounts == nil {
+		t.mounts = make(map[string]*mountActivity)
+	}
+	activity, ok := t.mounts[accessor]
+	if !ok {
+		activity = &mountActivity{}
+		t.mounts[accessor] = activity
+	}
+
+	start := now.Truncate(mountActivityBucket).Unix()
+	bucket := &activity.buckets[(start/int64(mountActivityBucket.Seconds()))%mountActivityBuckets]
+	if bucket.start != start {
+		*bucket = mountActivityCounts{start: start}
+	}
+	bucket.requests++
+	if failed {
+		bucket.errors++
+	}
+	activity.lastRequest = now
+}
+
+// recent returns the number of requests and of failed requests to the mount
+// with the given accessor in the last hour, and the time of its last request.
+func (t *mountActivityTracker) recent(accessor string, now time.Time) (requests, errors uint64, lastRequest time.Time) {
+	t.Lock()
+	defer t.Unlock()
+
+	activity, ok := t.mounts[accessor]
+	if !ok {
+		return 0, 0, time.Time{}
+	}
+
+	oldest := now.Truncate(mountActivityBucket).Add(-(mountActivityBuckets - 1) * mountActivityBucket).Unix()
+	for _, bucket := range activity.buckets {
+		if bucket.start >= oldest {
+			requests += bucket.requests
+			errors += bucket.errors
+		}
+	}
+	return requests, errors, activity.lastRequest
+}
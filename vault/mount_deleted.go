@@ -0,0 +1,254 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const (
+	// deletedMountsSubPath is the sub-path of the system view used to store
+	// the entries of the disabled mounts whose storage is retained.
+	deletedMountsSubPath = "deleted-mounts/"
+
+	// deletedMountsPurgeInterval is the interval at which the storage of
+	// disabled mounts is purged once their retention has passed.
+	deletedMountsPurgeInterval = 10 * time.Minute
+)
+
+// DeletedMount is a disabled secrets engine or auth method whose storage is
+// retained until PurgeTime, during which it can be undeleted. Its leases are
+// revoked when it is disabled.
+type DeletedMount struct {
+	Entry       *MountEntry `json:"entry"`
+	DeletedTime time.Time   `json:"deleted_time"`
+	PurgeTime   time.Time   `json:"purge_time"`
+}
+
+func (d *DeletedMount) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"uuid":         d.Entry.UUID,
+		"path":         d.Entry.Path,
+		"table":        d.Entry.Table,
+		"type":         d.Entry.Type,
+		"accessor":     d.Entry.Accessor,
+		"description":  d.Entry.Description,
+		"deleted_time": d.DeletedTime,
+		"purge_time":   d.PurgeTime,
+	}
+}
+
+func (c *Core) deletedMountsView() *BarrierView {
+	return c.systemBarrierView.SubView(deletedMountsSubPath)
+}
+
+// deletedMountRetention returns the duration for which the storage of
+// mounts disabled now is retained.
+func (c *Core) deletedMountRetention() time.Duration {
+	c.deletedMountsLock.Lock()
+	defer c.deletedMountsLock.Unlock()
+	return c.disabledMountRetention
+}
+
+func (c *Core) deletedMount(ctx context.Context, uuid string) (*DeletedMount, error) {
+	entry, err := c.deletedMountsView().Get(ctx, uuid)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	deleted := new(DeletedMount)
+	if err := entry.DecodeJSON(deleted); err != nil {
+		return nil, fmt.Errorf("failed to decode deleted mount %q: %w", uuid, err)
+	}
+	return deleted, nil
+}
+
+// deletedMounts returns the retained mounts of the given namespace, or of
+// all namespaces if ns is nil, sorted by decreasing deletion time.
+func (c *Core) deletedMounts(ctx context.Context, ns *namespace.Namespace) ([]*DeletedMount, error) {
+	uuids, err := c.deletedMountsView().List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*DeletedMount
+	for _, uuid := range uuids {
+		deleted, err := c.deletedMount(ctx, uuid)
+		if err != nil {
+			return nil, err
+		}
+		if deleted == nil || (ns != nil && deleted.Entry.NamespaceID != ns.ID) {
+			continue
+		}
+		result = append(result, deleted)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].DeletedTime.After(result[j].DeletedTime)
+	})
+	return result, nil
+}
+
+// retainDeletedMount records the entry of a mount being disabled, so that its
+// storage is kept until the retention passes.
+func (c *Core) retainDeletedMount(ctx context.Context, entry *MountEntry, retention time.Duration) error {
+	entry, err := entry.Clone()
+	if err != nil {
+		return err
+	}
+	entry.Tainted = false
+	entry.MountState = ""
+
+	now := time.Now().UTC()
+	deleted := &DeletedMount{
+		Entry:       entry,
+		DeletedTime: now,
+		PurgeTime:   now.Add(retention),
+	}
+
+	storageEntry, err := logical.StorageEntryJSON(entry.UUID, deleted)
+	if err != nil {
+		return err
+	}
+
+	c.deletedMountsLock.Lock()
+	defer c.deletedMountsLock.Unlock()
+	return c.deletedMountsView().Put(ctx, storageEntry)
+}
+
+// undeleteMount mounts again the most recently disabled mount at the given
+// path of the namespace of the request, with its storage, UUID and accessor.
+// table is either mountTableType or credentialTableType, in which case path
+// is relative to auth/.
+func (c *Core) undeleteMount(ctx context.Context, table, path string) (*MountEntry, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+
+	c.deletedMountsLock.Lock()
+	defer c.deletedMountsLock.Unlock()
+
+	deleted, err := c.deletedMounts(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	var entry *MountEntry
+	for _, d := range deleted {
+		if d.Entry.Table == table && d.Entry.Path == path {
+			entry = d.Entry
+			break
+		}
+	}
+	if entry == nil {
+		return nil, logical.CodedError(404, fmt.Sprintf("no deleted mount found at %q", path))
+	}
+
+	switch table {
+	case credentialTableType:
+		err = c.enableCredentialInternal(ctx, entry, MountTableUpdateStorage)
+	default:
+		err = c.mountInternal(ctx, entry, MountTableUpdateStorage)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.deletedMountsView().Delete(ctx, entry.UUID); err != nil {
+		return nil, fmt.Errorf("mount was undeleted but its deletion record could not be removed: %w", err)
+	}
+
+	c.logger.Info("undeleted mount", "path", path, "table", table, "type", entry.Type, "namespace", ns.Path)
+	return entry, nil
+}
+
+// purgeDeletedMount clears the retained storage of a disabled mount and
+// removes its record. It must be called with deletedMountsLock held.
+func (c *Core) purgeDeletedMount(ctx context.Context, deleted *DeletedMount) error {
+	view := NewBarrierView(c.barrier, deleted.Entry.ViewPath())
+	loggerName := "secrets.deletion"
+	if deleted.Entry.Table == credentialTableType {
+		loggerName = "auth.deletion"
+	}
+	logger := c.logger.Named(loggerName).With("namespace", deleted.Entry.NamespaceID, "path", deleted.Entry.Path)
+	if err := logical.ClearViewWithLogging(ctx, view, logger); err != nil {
+		return fmt.Errorf("failed to clear the storage of deleted mount %q: %w", deleted.Entry.UUID, err)
+	}
+	if err := c.deletedMountsView().Delete(ctx, deleted.Entry.UUID); err != nil {
+		return err
+	}
+
+	c.logger.Info("purged deleted mount", "path", deleted.Entry.Path, "table", deleted.Entry.Table, "uuid", deleted.Entry.UUID)
+	return nil
+}
+
+// purgeDeletedMounts purges the disabled mounts whose retention has passed at
+// the given time.
+func (c *Core) purgeDeletedMounts(ctx context.Context, now time.Time) {
+	c.deletedMountsLock.Lock()
+	defer c.deletedMountsLock.Unlock()
+
+	deleted, err := c.deletedMounts(ctx, nil)
+	if err != nil {
+		c.logger.Error("failed to list deleted mounts", "error", err)
+		return
+	}
+
+	for _, d := range deleted {
+		if ctx.Err() != nil {
+			return
+		}
+		if d.PurgeTime.After(now) {
+			continue
+		}
+		if err := c.purgeDeletedMount(ctx, d); err != nil {
+			c.logger.Error("failed to purge deleted mount", "uuid", d.Entry.UUID, "error", err)
+		}
+	}
+}
+
+func (c *Core) deletedMountsPurgeLoop(ctx context.Context) {
+	t := time.NewTicker(deletedMountsPurgeInterval)
+	for {
+		select {
+		case <-t.C:
+			c.stateLock.RLock()
+			c.purgeDeletedMounts(ctx, time.Now().UTC())
+			c.stateLock.RUnlock()
+		case <-ctx.Done():
+			t.Stop()
+			return
+		}
+	}
+}
+
+// startDeletedMountsPurge starts purging the storage of disabled mounts once
+// their retention has passed. It is only run on the active node.
+func (c *Core) startDeletedMountsPurge() {
+	if c.deletedMountsCancel != nil {
+		return
+	}
+
+	var ctx context.Context
+	ctx, c.deletedMountsCancel = context.WithCancel(c.activeContext)
+	go c.deletedMountsPurgeLoop(ctx)
+}
+
+func (c *Core) stopDeletedMountsPurge() {
+	if c.deletedMountsCancel != nil {
+		c.deletedMountsCancel()
+		c.deletedMountsCancel = nil
+	}
+}
@@ -0,0 +1,123 @@
+package vault
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCore_DisabledMountRetention(t *testing.T) {
+	c, _, root := TestCoreUnsealedWithConfig(t, &CoreConfig{
+		DisabledMountRetention: time.Hour,
+	})
+	ctx := namespace.RootContext(nil)
+
+	request := func(op logical.Operation, path string, data map[string]interface{}) (*logical.Response, error) {
+		t.Helper()
+		req := logical.TestRequest(t, op, path)
+		req.Data = data
+		req.ClientToken = root
+		return c.HandleRequest(ctx, req)
+	}
+
+	_, err := request(logical.UpdateOperation, "sys/mounts/secret2", map[string]interface{}{"type": "kv"})
+	require.NoError(t, err)
+	_, err = request(logical.UpdateOperation, "secret2/foo", map[string]interface{}{"bar": "baz"})
+	require.NoError(t, err)
+	mount := c.router.MatchingMountEntry(ctx, "secret2/")
+
+	_, err = request(logical.UpdateOperation, "sys/auth/noop", map[string]interface{}{"type": "noop"})
+	require.NoError(t, err)
+	auth := c.router.MatchingMountEntry(ctx, "auth/noop/")
+	authView := NewBarrierView(c.barrier, auth.ViewPath())
+	require.NoError(t, authView.Put(ctx, &logical.StorageEntry{Key: "user", Value: []byte("data")}))
+
+	_, err = request(logical.DeleteOperation, "sys/mounts/secret2", nil)
+	require.NoError(t, err)
+	_, err = request(logical.DeleteOperation, "sys/auth/noop", nil)
+	require.NoError(t, err)
+	require.Nil(t, c.router.MatchingMountEntry(ctx, "secret2/"))
+
+	resp, err := request(logical.ListOperation, "sys/deleted-mounts", nil)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{mount.UUID, auth.UUID}, resp.Data["keys"])
+	info := resp.Data["key_info"].(map[string]interface{})[mount.UUID].(map[string]interface{})
+	require.Equal(t, "secret2/", info["path"])
+	require.Equal(t, "kv", info["type"])
+
+	resp, err = request(logical.UpdateOperation, "sys/mounts/secret2/undelete", nil)
+	require.NoError(t, err)
+	require.Equal(t, mount.UUID, resp.Data["uuid"])
+	require.Equal(t, mount.Accessor, resp.Data["accessor"])
+	resp, err = request(logical.ReadOperation, "secret2/foo", nil)
+	require.NoError(t, err)
+	require.Equal(t, "baz", resp.Data["bar"])
+
+	resp, err = request(logical.UpdateOperation, "sys/auth/noop/undelete", nil)
+	require.NoError(t, err)
+	require.Equal(t, auth.Accessor, resp.Data["accessor"])
+	entry, err := authView.Get(ctx, "user")
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+
+	// Only disabled mounts can be undeleted.
+	_, err = request(logical.UpdateOperation, "sys/mounts/secret2/undelete", nil)
+	require.Error(t, err)
+	resp, err = request(logical.ListOperation, "sys/deleted-mounts", nil)
+	require.NoError(t, err)
+	require.Nil(t, resp.Data["keys"])
+
+	// The data is purged once the retention has passed.
+	_, err = request(logical.DeleteOperation, "sys/auth/noop", nil)
+	require.NoError(t, err)
+	c.purgeDeletedMounts(ctx, time.Now().Add(30*time.Minute))
+	entry, err = authView.Get(ctx, "user")
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+
+	c.purgeDeletedMounts(ctx, time.Now().Add(2*time.Hour))
+	entry, err = authView.Get(ctx, "user")
+	require.NoError(t, err)
+	require.Nil(t, entry)
+	_, err = request(logical.UpdateOperation, "sys/auth/noop/undelete", nil)
+	require.Error(t, err)
+}
+
+func TestCore_DisabledMountRetention_Purge(t *testing.T) {
+	c, _, root := TestCoreUnsealedWithConfig(t, &CoreConfig{
+		DisabledMountRetention: time.Hour,
+	})
+	ctx := namespace.RootContext(nil)
+
+	require.NoError(t, c.mount(ctx, &MountEntry{
+		Table: mountTableType,
+		Path:  "secret2/",
+		Type:  "kv",
+	}))
+	mount := c.router.MatchingMountEntry(ctx, "secret2/")
+	view := NewBarrierView(c.barrier, mount.ViewPath())
+	require.NoError(t, view.Put(context.Background(), &logical.StorageEntry{Key: "foo", Value: []byte("bar")}))
+	require.NoError(t, c.unmount(ctx, "secret2/"))
+
+	req := logical.TestRequest(t, logical.ReadOperation, "sys/deleted-mounts/"+mount.UUID)
+	req.ClientToken = root
+	resp, err := c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, "secret2/", resp.Data["path"])
+
+	req = logical.TestRequest(t, logical.DeleteOperation, "sys/deleted-mounts/"+mount.UUID)
+	req.ClientToken = root
+	_, err = c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+
+	keys, err := logical.CollectKeys(ctx, view)
+	require.NoError(t, err)
+	require.Empty(t, keys)
+	deleted, err := c.deletedMount(ctx, mount.UUID)
+	require.NoError(t, err)
+	require.Nil(t, deleted)
+}
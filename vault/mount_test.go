@@ -6,6 +6,7 @@ package vault
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"reflect"
 	"strings"
 	"testing"
@@ -208,13 +209,109 @@ func TestCore_Mount_secrets_builtin_RunningVersion(t *testing.T) {
 		t.Fatalf("missing mount")
 	}
 
-	raw, _ := c.router.root.Get(match)
+	re, _ := c.router.routeEntry(match)
 	// we override the running version of builtins
-	if !versions.IsBuiltinVersion(raw.(*routeEntry).mountEntry.RunningVersion) {
-		t.Errorf("Expected mount to have builtin version but got %s", raw.(*routeEntry).mountEntry.RunningVersion)
+	if !versions.IsBuiltinVersion(re.mountEntry.RunningVersion) {
+		t.Errorf("Expected mount to have builtin version but got %s", re.mountEntry.RunningVersion)
 	}
 }
 
+func TestCore_Mount_LazyLoad(t *testing.T) {
+	c, keys, _ := TestCoreUnsealed(t)
+	noopFactory := func(context.Context, *logical.BackendConfig) (logical.Backend, error) {
+		return &NoopBackend{Login: []string{"login"}}, nil
+	}
+	c.logicalBackends["noop"] = noopFactory
+	for path, mountType := range map[string]string{"foo": "kv", "bar": "kv", "baz": "noop", "qux": "kv"} {
+		me := &MountEntry{
+			Table:  mountTableType,
+			Path:   path,
+			Type:   mountType,
+			Config: MountConfig{LazyLoad: true},
+		}
+		if err := c.mount(namespace.RootContext(nil), me); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+	}
+
+	inmemSink := metrics.NewInmemSink(1000000*time.Hour, 2000000*time.Hour)
+	conf := &CoreConfig{
+		Physical:        c.physical,
+		LogicalBackends: map[string]logical.Factory{"noop": noopFactory},
+		BuiltinRegistry: corehelpers.NewMockBuiltinRegistry(),
+		MetricSink:      metricsutil.NewClusterMetricSink("test-cluster", inmemSink),
+		MetricsHelper:   metricsutil.NewMetricsHelper(inmemSink, false),
+	}
+	c2, err := NewCore(conf)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	defer c2.Shutdown()
+	for i, key := range keys {
+		unseal, err := TestCoreUnseal(c2, key)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		if i+1 == len(keys) && !unseal {
+			t.Fatalf("should be unsealed")
+		}
+	}
+
+	// The backends are not created at unseal
+	ctx := namespace.RootContext(nil)
+	for _, path := range []string{"foo/", "bar/", "baz/", "qux/"} {
+		re, ok := c2.router.routeEntry(path)
+		if !ok {
+			t.Fatalf("missing mount %q", path)
+		}
+		if !re.lazy.Load() || c2.router.MatchingBackend(ctx, path) != nil {
+			t.Fatalf("expected backend of %q not to be loaded", path)
+		}
+	}
+
+	// The first request routed to the mount loads the backend
+	req := &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      "foo/test",
+	}
+	if _, err := c2.router.Route(ctx, req); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if c2.router.MatchingBackend(ctx, "foo/") == nil {
+		t.Fatalf("expected backend of foo/ to be loaded")
+	}
+
+	// The backend can also be loaded ahead of the requests
+	if err := c2.router.LoadLazyMount(ctx, "bar/"); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	re, _ := c2.router.routeEntry("bar/")
+	if re.lazy.Load() || c2.router.MatchingBackend(ctx, "bar/") == nil {
+		t.Fatalf("expected backend of bar/ to be loaded")
+	}
+	if !versions.IsBuiltinVersion(re.mountEntry.RunningVersion) {
+		t.Errorf("Expected mount to have builtin version but got %s", re.mountEntry.RunningVersion)
+	}
+
+	// Checking for unauthenticated paths loads the backend, as they are only
+	// known once it is loaded
+	if !c2.router.LoginPath(ctx, "baz/login") {
+		t.Fatalf("expected baz/login to be a login path")
+	}
+	if c2.router.IsLazyMount(ctx, "baz/") {
+		t.Fatalf("expected backend of baz/ to be loaded")
+	}
+
+	// The rollback loads the backend, so that its periodic function runs
+	c2.rollback.triggerRollbacks()
+	corehelpers.RetryUntil(t, 5*time.Second, func() error {
+		if c2.router.IsLazyMount(ctx, "qux/") {
+			return errors.New("backend of qux/ not loaded")
+		}
+		return nil
+	})
+}
+
 // TestCore_Mount_kv_generic tests that we can successfully mount kv using the
 // kv alias "generic"
 func TestCore_Mount_kv_generic(t *testing.T) {
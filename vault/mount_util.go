@@ -39,6 +39,10 @@ func (c *Core) mountEntrySysView(entry *MountEntry) extendedSystemView {
 		},
 	}
 
+	if entry.ExternalEntropyAccess {
+		return entropySystemView{esi}
+	}
+
 	// Due to complexity in the ACME interface, only return it when we
 	// are a PKI plugin that needs it.
 	if entry.Type != "pki" {
@@ -0,0 +1,338 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-secure-stdlib/strutil"
+	sockaddr "github.com/hashicorp/go-sockaddr"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+// networkPolicySubPath is the sub-path of the system view used to store the
+// network policies.
+const networkPolicySubPath = "network-policy/"
+
+// NetworkPolicy restricts the source addresses and the times at which the
+// tokens it applies to can be used. It applies to the tokens carrying any of
+// Policies, and to the tokens issued by the auth methods of MountAccessors.
+// The conditions are enforced on login and on each request.
+type NetworkPolicy struct {
+	Name string `json:"name"`
+
+	// CIDRs are the source addresses allowed. If empty, any address is
+	// allowed.
+	CIDRs []*sockaddr.SockAddrMarshaler `json:"cidrs,omitempty"`
+
+	// Days are the days of the week allowed. If empty, any day is allowed.
+	Days []time.Weekday `json:"days,omitempty"`
+
+	// WindowStart and WindowEnd restrict the time of day allowed, as HH:MM.
+	// The window spans midnight if WindowEnd is before WindowStart. If
+	// empty, any time of day is allowed.
+	WindowStart string `json:"window_start,omitempty"`
+	WindowEnd   string `json:"window_end,omitempty"`
+
+	// Timezone is the IANA time zone in which days and times of day are
+	// evaluated, UTC if empty.
+	Timezone string `json:"timezone,omitempty"`
+
+	Policies       []string `json:"policies,omitempty"`
+	MountAccessors []string `json:"mount_accessors,omitempty"`
+
+	location    *time.Location
+	windowStart int
+	windowEnd   int
+
+	// invalid is set for stored policies that fail to initialize, which
+	// then deny all requests of the tokens they apply to
+	invalid bool
+}
+
+// init validates the conditions of the policy and parses them for
+// evaluation.
+func (p *NetworkPolicy) init() error {
+	if len(p.Policies) == 0 && len(p.MountAccessors) == 0 {
+		return fmt.Errorf("at least one of policies or mount_accessors is required")
+	}
+	if strutil.StrListContains(p.Policies, "root") {
+		return fmt.Errorf("network policies cannot apply to the root policy")
+	}
+	if (p.WindowStart == "") != (p.WindowEnd == "") {
+		return fmt.Errorf("window_start and window_end must be set together")
+	}
+	if len(p.CIDRs) == 0 && len(p.Days) == 0 && p.WindowStart == "" {
+		return fmt.Errorf("at least one of cidrs, days or window_start and window_end is required")
+	}
+
+	var err error
+	if p.location, err = time.LoadLocation(p.Timezone); err != nil {
+		return fmt.Errorf("invalid timezone %q: %w", p.Timezone, err)
+	}
+	if p.WindowStart != "" {
+		if p.windowStart, err = parseNetworkPolicyTime(p.WindowStart); err != nil {
+			return err
+		}
+		if p.windowEnd, err = parseNetworkPolicyTime(p.WindowEnd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// allows returns whether a request from addr at the given time satisfies the
+// conditions of the policy. addr may be nil if the source address of the
+// request is unknown.
+func (p *NetworkPolicy) allows(addr sockaddr.SockAddr, now time.Time) bool {
+	if p.invalid {
+		return false
+	}
+
+	if len(p.CIDRs) > 0 {
+		if addr == nil {
+			return false
+		}
+		var allowed bool
+		for _, cidr := range p.CIDRs {
+			if cidr.Contains(addr) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+
+	local := now.In(p.location)
+	if len(p.Days) > 0 {
+		var allowed bool
+		for _, day := range p.Days {
+			if local.Weekday() == day {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+
+	if p.WindowStart != "" {
+		minutes := local.Hour()*60 + local.Minute()
+		if p.windowStart <= p.windowEnd {
+			return minutes >= p.windowStart && minutes < p.windowEnd
+		}
+		return minutes >= p.windowStart || minutes < p.windowEnd
+	}
+	return true
+}
+
+func (p *NetworkPolicy) toMap() map[string]interface{} {
+	cidrs := make([]string, 0, len(p.CIDRs))
+	for _, cidr := range p.CIDRs {
+		cidrs = append(cidrs, cidr.String())
+	}
+	days := make([]string, 0, len(p.Days))
+	for _, day := range p.Days {
+		days = append(days, strings.ToLower(day.String()))
+	}
+
+	return map[string]interface{}{
+		"name":            p.Name,
+		"cidrs":           cidrs,
+		"days":            days,
+		"window_start":    p.WindowStart,
+		"window_end":      p.WindowEnd,
+		"timezone":        p.Timezone,
+		"policies":        p.Policies,
+		"mount_accessors": p.MountAccessors,
+	}
+}
+
+// parseNetworkPolicyTime parses a time of day of the form HH:MM into a number
+// of minutes after midnight.
+func parseNetworkPolicyTime(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q, expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// parseNetworkPolicyDay parses the name of a day of the week, in full or
+// abbreviated to three letters.
+func parseNetworkPolicyDay(s string) (time.Weekday, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		name := strings.ToLower(day.String())
+		if s == name || s == name[:3] {
+			return day, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid day of the week %q", s)
+}
+
+// networkPolicyAddr returns the source address of a request, or nil if it is
+// unknown, in which case it is only allowed by policies without CIDRs.
+func networkPolicyAddr(conn *logical.Connection) sockaddr.SockAddr {
+	if conn == nil || conn.RemoteAddr == "" {
+		return nil
+	}
+	addr, err := sockaddr.NewSockAddr(conn.RemoteAddr)
+	if err != nil {
+		return nil
+	}
+	return addr
+}
+
+func (c *Core) networkPolicyView() *BarrierView {
+	return c.systemBarrierView.SubView(networkPolicySubPath)
+}
+
+// loadNetworkPolicies returns the network policies, loading them from storage
+// on first use after unseal.
+func (c *Core) loadNetworkPolicies(ctx context.Context) (map[string]*NetworkPolicy, error) {
+	c.networkPoliciesLock.RLock()
+	policies := c.networkPolicies
+	c.networkPoliciesLock.RUnlock()
+	if policies != nil {
+		return policies, nil
+	}
+
+	c.networkPoliciesLock.Lock()
+	defer c.networkPoliciesLock.Unlock()
+	if c.networkPolicies != nil {
+		return c.networkPolicies, nil
+	}
+
+	view := c.networkPolicyView()
+	names, err := view.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	policies = make(map[string]*NetworkPolicy, len(names))
+	for _, name := range names {
+		entry, err := view.Get(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			continue
+		}
+		policy := new(NetworkPolicy)
+		if err := entry.DecodeJSON(policy); err != nil {
+			return nil, fmt.Errorf("failed to decode network policy %q: %w", name, err)
+		}
+		if err := policy.init(); err != nil {
+			c.logger.Error("invalid network policy, denying all requests it applies to", "name", name, "error", err)
+			policy.invalid = true
+		}
+		policies[name] = policy
+	}
+
+	c.networkPolicies = policies
+	return policies, nil
+}
+
+// putNetworkPolicy stores the given network policy, which must have been
+// initialized, and applies it to future requests.
+func (c *Core) putNetworkPolicy(ctx context.Context, policy *NetworkPolicy) error {
+	entry, err := logical.StorageEntryJSON(policy.Name, policy)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.loadNetworkPolicies(ctx); err != nil {
+		return err
+	}
+
+	c.networkPoliciesLock.Lock()
+	defer c.networkPoliciesLock.Unlock()
+	if err := c.networkPolicyView().Put(ctx, entry); err != nil {
+		return err
+	}
+	if c.networkPolicies == nil {
+		// The cache was reset, the policies are loaded again on next use
+		return nil
+	}
+	policies := make(map[string]*NetworkPolicy, len(c.networkPolicies)+1)
+	for name, p := range c.networkPolicies {
+		policies[name] = p
+	}
+	policies[policy.Name] = policy
+	c.networkPolicies = policies
+	return nil
+}
+
+func (c *Core) deleteNetworkPolicy(ctx context.Context, name string) error {
+	if _, err := c.loadNetworkPolicies(ctx); err != nil {
+		return err
+	}
+
+	c.networkPoliciesLock.Lock()
+	defer c.networkPoliciesLock.Unlock()
+	if err := c.networkPolicyView().Delete(ctx, name); err != nil {
+		return err
+	}
+	if c.networkPolicies == nil {
+		return nil
+	}
+	policies := make(map[string]*NetworkPolicy, len(c.networkPolicies))
+	for n, p := range c.networkPolicies {
+		if n != name {
+			policies[n] = p
+		}
+	}
+	c.networkPolicies = policies
+	return nil
+}
+
+// resetNetworkPolicies drops the cached network policies, so that they are
+// loaded from storage on next use.
+func (c *Core) resetNetworkPolicies() {
+	c.networkPoliciesLock.Lock()
+	defer c.networkPoliciesLock.Unlock()
+	c.networkPolicies = nil
+}
+
+// checkNetworkPolicies evaluates the network policies applying to a token
+// with the given policies, issued through the given path, for a request from
+// conn. It returns the name of the first policy denying the request, if any.
+func (c *Core) checkNetworkPolicies(ctx context.Context, conn *logical.Connection, policies []string, path string, now time.Time) (string, error) {
+	networkPolicies, err := c.loadNetworkPolicies(ctx)
+	if err != nil || len(networkPolicies) == 0 {
+		return "", err
+	}
+
+	addr := networkPolicyAddr(conn)
+	for name, policy := range networkPolicies {
+		if !c.networkPolicyApplies(policy, policies, path) {
+			continue
+		}
+		if !policy.allows(addr, now) {
+			return name, nil
+		}
+	}
+	return "", nil
+}
+
+// networkPolicyApplies returns whether the network policy applies to a token
+// with the given policies, issued through the given path.
+func (c *Core) networkPolicyApplies(policy *NetworkPolicy, policies []string, path string) bool {
+	for _, p := range policy.Policies {
+		if strutil.StrListContains(policies, p) {
+			return true
+		}
+	}
+	for _, accessor := range policy.MountAccessors {
+		entry := c.router.MatchingMountByAccessor(accessor)
+		if entry != nil && strings.HasPrefix(path, entry.APIPath()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,205 @@
+package vault
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-secure-stdlib/parseutil"
+	credUserpass "github.com/openbao/openbao/builtin/credential/userpass"
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkPolicy_Allows(t *testing.T) {
+	cidrs, err := parseutil.ParseAddrs([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+
+	policy := &NetworkPolicy{
+		Name:        "business-hours",
+		CIDRs:       cidrs,
+		Days:        []time.Weekday{time.Monday, time.Tuesday},
+		WindowStart: "09:00",
+		WindowEnd:   "18:00",
+		Timezone:    "Europe/Paris",
+		Policies:    []string{"contractor"},
+	}
+	require.NoError(t, policy.init())
+
+	inside := networkPolicyAddr(&logical.Connection{RemoteAddr: "10.1.2.3"})
+	outside := networkPolicyAddr(&logical.Connection{RemoteAddr: "192.168.1.1"})
+
+	// Monday 2024-01-15 10:00 in Paris is 09:00 UTC.
+	monday := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	require.True(t, policy.allows(inside, monday))
+	require.False(t, policy.allows(outside, monday))
+	require.False(t, policy.allows(nil, monday))
+	require.False(t, policy.allows(inside, monday.Add(9*time.Hour)))
+	require.False(t, policy.allows(inside, monday.Add(-2*time.Hour)))
+	require.False(t, policy.allows(inside, monday.AddDate(0, 0, 2)))
+
+	// Windows ending before they start span midnight.
+	night := &NetworkPolicy{
+		Name:        "night",
+		WindowStart: "22:00",
+		WindowEnd:   "06:00",
+		Policies:    []string{"batch"},
+	}
+	require.NoError(t, night.init())
+	require.True(t, night.allows(nil, time.Date(2024, 1, 15, 23, 0, 0, 0, time.UTC)))
+	require.True(t, night.allows(nil, time.Date(2024, 1, 15, 5, 59, 0, 0, time.UTC)))
+	require.False(t, night.allows(nil, time.Date(2024, 1, 15, 6, 0, 0, 0, time.UTC)))
+	require.False(t, night.allows(nil, time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)))
+}
+
+func TestNetworkPolicy_Validation(t *testing.T) {
+	for name, policy := range map[string]*NetworkPolicy{
+		"no target":      {WindowStart: "09:00", WindowEnd: "18:00"},
+		"root":           {Policies: []string{"root"}, WindowStart: "09:00", WindowEnd: "18:00"},
+		"no condition":   {Policies: []string{"contractor"}},
+		"half window":    {Policies: []string{"contractor"}, WindowStart: "09:00"},
+		"invalid window": {Policies: []string{"contractor"}, WindowStart: "9am", WindowEnd: "18:00"},
+		"invalid zone":   {Policies: []string{"contractor"}, Days: []time.Weekday{time.Monday}, Timezone: "Mars/Olympus"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.Error(t, policy.init())
+		})
+	}
+
+	day, err := parseNetworkPolicyDay("Wed")
+	require.NoError(t, err)
+	require.Equal(t, time.Wednesday, day)
+	_, err = parseNetworkPolicyDay("someday")
+	require.Error(t, err)
+}
+
+func TestSystemBackend_NetworkPolicy(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	request := func(op logical.Operation, path, token, remoteAddr string, data map[string]interface{}) (*logical.Response, error) {
+		t.Helper()
+		req := logical.TestRequest(t, op, path)
+		req.Data = data
+		req.ClientToken = token
+		req.Connection = &logical.Connection{RemoteAddr: remoteAddr}
+		return c.HandleRequest(ctx, req)
+	}
+
+	_, err := request(logical.UpdateOperation, "sys/policy/contractor", root, "127.0.0.1", map[string]interface{}{
+		"policy": `path "secret/*" { capabilities = ["read"] }`,
+	})
+	require.NoError(t, err)
+	resp, err := request(logical.UpdateOperation, "auth/token/create", root, "127.0.0.1", map[string]interface{}{
+		"policies": []string{"contractor"},
+	})
+	require.NoError(t, err)
+	token := resp.Auth.ClientToken
+
+	_, err = request(logical.UpdateOperation, "sys/network-policy/office", root, "127.0.0.1", map[string]interface{}{
+		"cidrs":    "10.0.0.0/8",
+		"policies": "contractor",
+	})
+	require.NoError(t, err)
+
+	resp, err = request(logical.ReadOperation, "sys/network-policy/office", root, "127.0.0.1", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.0.0/8"}, resp.Data["cidrs"])
+	require.Equal(t, []string{"contractor"}, resp.Data["policies"])
+
+	resp, err = request(logical.ListOperation, "sys/network-policy", root, "127.0.0.1", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"office"}, resp.Data["keys"])
+
+	// The token is only usable from the office network, the root token is
+	// not restricted.
+	_, err = request(logical.ReadOperation, "auth/token/lookup-self", token, "127.0.0.1", nil)
+	require.ErrorIs(t, err, logical.ErrPermissionDenied)
+	_, err = request(logical.ReadOperation, "auth/token/lookup-self", token, "10.1.2.3", nil)
+	require.NoError(t, err)
+
+	_, err = request(logical.UpdateOperation, "sys/network-policy/invalid", root, "127.0.0.1", map[string]interface{}{
+		"days":     "monday",
+		"timezone": "Mars/Olympus",
+		"policies": "contractor",
+	})
+	require.Error(t, err)
+	_, err = request(logical.UpdateOperation, "sys/network-policy/invalid", root, "127.0.0.1", map[string]interface{}{
+		"days":            "monday",
+		"mount_accessors": "unknown",
+	})
+	require.Error(t, err)
+
+	_, err = request(logical.DeleteOperation, "sys/network-policy/office", root, "127.0.0.1", nil)
+	require.NoError(t, err)
+	_, err = request(logical.ReadOperation, "auth/token/lookup-self", token, "127.0.0.1", nil)
+	require.NoError(t, err)
+}
+
+func TestSystemBackend_NetworkPolicy_IdentityPolicies(t *testing.T) {
+	err := AddTestCredentialBackend("userpass", credUserpass.Factory)
+	require.NoError(t, err)
+	defer ClearTestCredentialBackends()
+
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	request := func(op logical.Operation, path, token, remoteAddr string, data map[string]interface{}) (*logical.Response, error) {
+		t.Helper()
+		req := logical.TestRequest(t, op, path)
+		req.Data = data
+		req.ClientToken = token
+		req.Connection = &logical.Connection{RemoteAddr: remoteAddr}
+		return c.HandleRequest(ctx, req)
+	}
+
+	_, err = request(logical.UpdateOperation, "sys/auth/userpass", root, "127.0.0.1", map[string]interface{}{
+		"type": "userpass",
+	})
+	require.NoError(t, err)
+	_, err = request(logical.UpdateOperation, "auth/userpass/users/contractor", root, "127.0.0.1", map[string]interface{}{
+		"password": "secret",
+	})
+	require.NoError(t, err)
+
+	// The contractor policy is only granted through a group of the entity of
+	// the user.
+	resp, err := request(logical.UpdateOperation, "identity/entity", root, "127.0.0.1", map[string]interface{}{
+		"name": "contractor",
+	})
+	require.NoError(t, err)
+	entityID := resp.Data["id"].(string)
+	_, err = request(logical.UpdateOperation, "identity/entity-alias", root, "127.0.0.1", map[string]interface{}{
+		"name":           "contractor",
+		"canonical_id":   entityID,
+		"mount_accessor": c.router.MatchingMountEntry(ctx, "auth/userpass/").Accessor,
+	})
+	require.NoError(t, err)
+	_, err = request(logical.UpdateOperation, "identity/group", root, "127.0.0.1", map[string]interface{}{
+		"name":              "contractors",
+		"policies":          "contractor",
+		"member_entity_ids": entityID,
+	})
+	require.NoError(t, err)
+
+	_, err = request(logical.UpdateOperation, "sys/network-policy/office", root, "127.0.0.1", map[string]interface{}{
+		"cidrs":    "10.0.0.0/8",
+		"policies": "contractor",
+	})
+	require.NoError(t, err)
+
+	// Logins are only allowed from the office network
+	login := map[string]interface{}{"password": "secret"}
+	_, err = request(logical.UpdateOperation, "auth/userpass/login/contractor", "", "127.0.0.1", login)
+	require.ErrorIs(t, err, logical.ErrPermissionDenied)
+	resp, err = request(logical.UpdateOperation, "auth/userpass/login/contractor", "", "10.1.2.3", login)
+	require.NoError(t, err)
+	token := resp.Auth.ClientToken
+	require.NotContains(t, resp.Auth.TokenPolicies, "contractor")
+
+	// And so is the use of the token
+	_, err = request(logical.ReadOperation, "auth/token/lookup-self", token, "127.0.0.1", nil)
+	require.ErrorIs(t, err, logical.ErrPermissionDenied)
+	_, err = request(logical.ReadOperation, "auth/token/lookup-self", token, "10.1.2.3", nil)
+	require.NoError(t, err)
+}
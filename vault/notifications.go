@@ -0,0 +1,199 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/armon/go-metrics"
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/go-secure-stdlib/strutil"
+	"github.com/openbao/openbao/command/server"
+)
+
+// The events POSTed to the endpoints of the notification stanzas of the
+// server configuration.
+const (
+	NotificationEventSeal               = "seal"
+	NotificationEventUnseal             = "unseal"
+	NotificationEventLeadershipAcquired = "leadership_acquired"
+	NotificationEventLeadershipLost     = "leadership_lost"
+	NotificationEventQuorumLoss         = "quorum_loss"
+)
+
+const (
+	// NotificationSignatureHeader holds the hex-encoded HMAC-SHA256 of the
+	// body of a notification, for the endpoints configured with a key.
+	NotificationSignatureHeader = "X-OpenBao-Signature"
+
+	// defaultNotificationMaxRetries is the number of times a failed
+	// notification is retried, unless configured otherwise.
+	defaultNotificationMaxRetries = 3
+)
+
+var (
+	// notificationTimeout is the timeout of a single notification attempt.
+	notificationTimeout = 10 * time.Second
+
+	// notificationRetryBackoff is the delay before the first retry of a
+	// failed notification, doubled on each further retry.
+	notificationRetryBackoff = time.Second
+
+	// quorumCheckInterval is the interval between two checks of the raft
+	// leader by the quorum monitor.
+	quorumCheckInterval = 5 * time.Second
+
+	// quorumLossTimeout is how long the node has to see no raft leader
+	// before notifying a quorum loss.
+	quorumLossTimeout = 30 * time.Second
+)
+
+// Notification is the JSON body POSTed to the notification endpoints.
+type Notification struct {
+	Event       string    `json:"event"`
+	Time        time.Time `json:"time"`
+	ClusterName string    `json:"cluster_name,omitempty"`
+	APIAddr     string    `json:"api_addr,omitempty"`
+	NodeID      string    `json:"node_id,omitempty"`
+}
+
+// notifications returns the notification endpoints of the current
+// configuration
+func (c *Core) notifications() []*server.Notification {
+	conf := c.rawConfig.Load()
+	if conf == nil {
+		return nil
+	}
+	return conf.(*server.Config).Notifications
+}
+
+// notify POSTs the event to the notification endpoints subscribed to it, in
+// the background. Failed notifications are retried, and then logged.
+func (c *Core) notify(event string) {
+	var endpoints []*server.Notification
+	for _, n := range c.notifications() {
+		if len(n.Events) == 0 || strutil.StrListContains(n.Events, event) {
+			endpoints = append(endpoints, n)
+		}
+	}
+	if len(endpoints) == 0 {
+		return
+	}
+
+	notification := &Notification{
+		Event:       event,
+		Time:        time.Now().UTC(),
+		ClusterName: c.clusterName,
+		APIAddr:     c.redirectAddr,
+	}
+	if raftBackend := c.getRaftBackend(); raftBackend != nil {
+		notification.NodeID = raftBackend.NodeID()
+	}
+	body, err := json.Marshal(notification)
+	if err != nil {
+		c.logger.Error("failed to encode notification", "event", event, "error", err)
+		return
+	}
+
+	for _, endpoint := range endpoints {
+		go c.sendNotification(endpoint, event, body)
+	}
+}
+
+// sendNotification POSTs the notification body to the endpoint, retrying
+// with an exponential backoff on failure.
+func (c *Core) sendNotification(endpoint *server.Notification, event string, body []byte) {
+	maxRetries := defaultNotificationMaxRetries
+	if endpoint.MaxRetries != nil {
+		maxRetries = *endpoint.MaxRetries
+	}
+
+	backoff := notificationRetryBackoff
+	var err error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		err = c.postNotification(endpoint, event, body)
+		if err == nil {
+			metrics.IncrCounterWithLabels([]string{"core", "notification", "sent"}, 1, []metrics.Label{{Name: "event", Value: event}})
+			return
+		}
+		c.logger.Debug("failed to send notification", "notification", endpoint.Name, "event", event, "attempt", attempt+1, "error", err)
+	}
+
+	metrics.IncrCounterWithLabels([]string{"core", "notification", "failed"}, 1, []metrics.Label{{Name: "event", Value: event}})
+	c.logger.Error("failed to send notification", "notification", endpoint.Name, "event", event, "error", err)
+}
+
+// postNotification makes a single attempt at POSTing the notification body
+// to the endpoint.
+func (c *Core) postNotification(endpoint *server.Notification, event string, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), notificationTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-OpenBao-Event", event)
+	if endpoint.HMACKey != "" {
+		mac := hmac.New(sha256.New, []byte(endpoint.HMACKey))
+		mac.Write(body)
+		req.Header.Set(NotificationSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := cleanhttp.DefaultClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// runQuorumMonitor notifies a quorum loss once the node has seen no raft
+// leader for quorumLossTimeout, until stopCh is closed. A new loss is only
+// notified after a leader was seen again.
+func (c *Core) runQuorumMonitor(stopCh chan struct{}) {
+	ticker := time.NewTicker(quorumCheckInterval)
+	defer ticker.Stop()
+
+	var noLeaderSince time.Time
+	notified := false
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+		}
+
+		raftBackend := c.getRaftBackend()
+		if raftBackend == nil || raftBackend.HasLeader() {
+			noLeaderSince = time.Time{}
+			notified = false
+			continue
+		}
+
+		if noLeaderSince.IsZero() {
+			noLeaderSince = time.Now()
+		}
+		if !notified && time.Since(noLeaderSince) >= quorumLossTimeout {
+			c.logger.Warn("no raft leader, quorum lost", "since", noLeaderSince)
+			c.notify(NotificationEventQuorumLoss)
+			notified = true
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package vault
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/openbao/openbao/command/server"
+	"github.com/openbao/openbao/internalshared/configutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCore_Notify(t *testing.T) {
+	oldBackoff := notificationRetryBackoff
+	notificationRetryBackoff = 10 * time.Millisecond
+	defer func() { notificationRetryBackoff = oldBackoff }()
+
+	type received struct {
+		notification Notification
+		signature    string
+	}
+	receivedCh := make(chan received, 10)
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Fail the first attempt to exercise the retries
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var n Notification
+		require.NoError(t, json.Unmarshal(body, &n))
+
+		mac := hmac.New(sha256.New, []byte("hmac-key"))
+		mac.Write(body)
+		require.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), r.Header.Get(NotificationSignatureHeader))
+		require.Equal(t, n.Event, r.Header.Get("X-OpenBao-Event"))
+
+		receivedCh <- received{notification: n, signature: r.Header.Get(NotificationSignatureHeader)}
+	}))
+	defer srv.Close()
+
+	c, _, _ := TestCoreUnsealed(t)
+	c.SetConfig(&server.Config{
+		SharedConfig: &configutil.SharedConfig{},
+		Notifications: []*server.Notification{
+			{
+				Name:    "paging",
+				URL:     srv.URL,
+				Events:  []string{NotificationEventSeal, NotificationEventQuorumLoss},
+				HMACKey: "hmac-key",
+			},
+		},
+	})
+
+	// Events the endpoint is not subscribed to are not sent
+	c.notify(NotificationEventUnseal)
+	c.notify(NotificationEventSeal)
+
+	select {
+	case r := <-receivedCh:
+		require.Equal(t, NotificationEventSeal, r.notification.Event)
+		require.NotEmpty(t, r.signature)
+	case <-time.After(5 * time.Second):
+		t.Fatal("notification not received")
+	}
+	require.Equal(t, int32(2), attempts.Load())
+
+	select {
+	case r := <-receivedCh:
+		t.Fatalf("unexpected notification: %#v", r.notification)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
@@ -154,7 +154,7 @@ func wrapFactoryCheckPerms(core *Core, f logical.Factory) logical.Factory {
 			return nil, fmt.Errorf("failed to find %s in plugin catalog", pluginDescription)
 		}
 
-		command, err := filepath.Rel(core.pluginCatalog.directory, plugin.Command)
+		command, err := filepath.Rel(core.pluginCatalog.Directory(), plugin.Command)
 		if err != nil {
 			return nil, fmt.Errorf("failed to compute plugin command: %w", err)
 		}
@@ -167,6 +167,9 @@ func wrapFactoryCheckPerms(core *Core, f logical.Factory) logical.Factory {
 }
 
 func (c *Core) setupPluginCatalog(ctx context.Context) error {
+	c.pluginDirectoryLock.RLock()
+	defer c.pluginDirectoryLock.RUnlock()
+
 	c.pluginCatalog = &PluginCatalog{
 		builtinRegistry: c.builtinRegistry,
 		catalogView:     NewBarrierView(c.barrier, pluginCatalogPath),
@@ -867,13 +870,22 @@ func (c *PluginCatalog) get(ctx context.Context, name string, pluginType consts.
 	return nil, nil
 }
 
+// Directory returns the directory external plugins are run from.
+func (c *PluginCatalog) Directory() string {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.directory
+}
+
+func (c *PluginCatalog) setDirectory(dir string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.directory = dir
+}
+
 // Set registers a new external plugin with the catalog, or updates an existing
 // external plugin. It takes the name, command and SHA256 of the plugin.
 func (c *PluginCatalog) Set(ctx context.Context, name string, pluginType consts.PluginType, version string, command string, args []string, env []string, sha256 []byte) error {
-	if c.directory == "" {
-		return ErrDirectoryNotConfigured
-	}
-
 	switch {
 	case strings.Contains(name, ".."):
 		fallthrough
@@ -884,6 +896,10 @@ func (c *PluginCatalog) Set(ctx context.Context, name string, pluginType consts.
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
+	if c.directory == "" {
+		return ErrDirectoryNotConfigured
+	}
+
 	_, err := c.setInternal(ctx, name, pluginType, version, command, args, env, sha256)
 	return err
 }
@@ -139,13 +139,11 @@ func (c *Core) reloadBackendCommon(ctx context.Context, entry *MountEntry, isAut
 	}
 
 	// Fast-path out if the backend doesn't exist
-	raw, ok := c.router.root.Get(entry.Namespace().Path + path)
+	re, ok := c.router.routeEntry(entry.Namespace().Path + path)
 	if !ok {
 		return nil
 	}
 
-	re := raw.(*routeEntry)
-
 	// Grab the lock, this allows requests to drain before we cleanup the
 	// client.
 	re.l.Lock()
@@ -6,6 +6,7 @@ package vault
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
@@ -133,6 +134,7 @@ type ACLPermissions struct {
 	MaxWrappingTTL      time.Duration
 	AllowedParameters   map[string][]interface{}
 	DeniedParameters    map[string][]interface{}
+	ParameterPatterns   map[string]*regexp.Regexp
 	RequiredParameters  []string
 	MFAMethods          []string
 	GrantingPoliciesMap map[uint32][]logical.PolicyInfo
@@ -170,6 +172,15 @@ func (p *ACLPermissions) Clone() (*ACLPermissions, error) {
 		ret.DeniedParameters = clonedDenied.(map[string][]interface{})
 	}
 
+	// Compiled regular expressions are safe for concurrent use, so they are
+	// shared rather than copied
+	if p.ParameterPatterns != nil {
+		ret.ParameterPatterns = make(map[string]*regexp.Regexp, len(p.ParameterPatterns))
+		for pattern, re := range p.ParameterPatterns {
+			ret.ParameterPatterns[pattern] = re
+		}
+	}
+
 	switch {
 	case p.MFAMethods == nil:
 	case len(p.MFAMethods) == 0:
@@ -394,6 +405,9 @@ func parsePaths(result *Policy, list *ast.ObjectList, performTemplating bool, en
 		if pc.AllowedParametersHCL != nil {
 			pc.Permissions.AllowedParameters = make(map[string][]interface{}, len(pc.AllowedParametersHCL))
 			for k, v := range pc.AllowedParametersHCL {
+				if err := compileParameterPatterns(pc.Permissions, v); err != nil {
+					return fmt.Errorf("path %q: allowed parameter %q: %w", key, k, err)
+				}
 				pc.Permissions.AllowedParameters[strings.ToLower(k)] = v
 			}
 		}
@@ -401,6 +415,9 @@ func parsePaths(result *Policy, list *ast.ObjectList, performTemplating bool, en
 			pc.Permissions.DeniedParameters = make(map[string][]interface{}, len(pc.DeniedParametersHCL))
 
 			for k, v := range pc.DeniedParametersHCL {
+				if err := compileParameterPatterns(pc.Permissions, v); err != nil {
+					return fmt.Errorf("path %q: denied parameter %q: %w", key, k, err)
+				}
 				pc.Permissions.DeniedParameters[strings.ToLower(k)] = v
 			}
 		}
@@ -438,3 +455,31 @@ func parsePaths(result *Policy, list *ast.ObjectList, performTemplating bool, en
 	result.Paths = paths
 	return nil
 }
+
+// compileParameterPatterns compiles the regular expressions among the values
+// of an allowed or denied parameter, storing them in the permissions so that
+// they are not compiled again when checking requests.
+func compileParameterPatterns(perms *ACLPermissions, values []interface{}) error {
+	for _, value := range values {
+		item, ok := value.(string)
+		if !ok {
+			continue
+		}
+		pattern, ok := strings.CutPrefix(item, parameterRegexPrefix)
+		if !ok {
+			continue
+		}
+		if _, ok := perms.ParameterPatterns[pattern]; ok {
+			continue
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid regular expression %q: %w", pattern, err)
+		}
+		if perms.ParameterPatterns == nil {
+			perms.ParameterPatterns = make(map[string]*regexp.Regexp)
+		}
+		perms.ParameterPatterns[pattern] = re
+	}
+	return nil
+}
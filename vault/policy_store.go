@@ -152,6 +152,9 @@ type PolicyStore struct {
 	core    *Core
 	aclView *BarrierView
 
+	// history stores previous versions of ACL policies
+	history *configHistory
+
 	tokenPoliciesLRU *lru.TwoQueueCache
 	egpLRU           *lru.TwoQueueCache
 
@@ -180,6 +183,7 @@ type PolicyEntry struct {
 func NewPolicyStore(ctx context.Context, core *Core, baseView *BarrierView, system logical.SystemView, logger log.Logger) (*PolicyStore, error) {
 	ps := &PolicyStore{
 		aclView:    baseView.SubView(policyACLSubPath),
+		history:    &configHistory{view: baseView.SubView(policyHistorySubPath)},
 		modifyLock: new(sync.RWMutex),
 		logger:     logger,
 		core:       core,
@@ -286,6 +290,12 @@ func (ps *PolicyStore) invalidate(ctx context.Context, name string, policyType P
 
 // SetPolicy is used to create or update the given policy
 func (ps *PolicyStore) SetPolicy(ctx context.Context, p *Policy) error {
+	return ps.SetPolicyWithAuthor(ctx, p, nil)
+}
+
+// SetPolicyWithAuthor creates or updates the given policy and records the
+// new version, along with its author, in the policy history.
+func (ps *PolicyStore) SetPolicyWithAuthor(ctx context.Context, p *Policy, author *ConfigChangeAuthor) error {
 	defer metrics.MeasureSince([]string{"policy", "set_policy"}, time.Now())
 	if p == nil {
 		return fmt.Errorf("nil policy passed in for storage")
@@ -299,13 +309,35 @@ func (ps *PolicyStore) SetPolicy(ctx context.Context, p *Policy) error {
 		return fmt.Errorf("cannot update %q policy", p.Name)
 	}
 
-	return ps.setPolicyInternal(ctx, p)
+	ps.modifyLock.Lock()
+	defer ps.modifyLock.Unlock()
+
+	previous, err := ps.readPolicyEntry(ctx, p.Name, p.Type)
+	if err != nil {
+		return err
+	}
+
+	if err := ps.setPolicyLocked(ctx, p); err != nil {
+		return err
+	}
+
+	if p.Type == PolicyTypeACL {
+		ps.recordPolicyVersion(ctx, p.Name, previous, &PolicyVersion{
+			Raw:    p.Raw,
+			Author: author,
+		})
+	}
+	return nil
 }
 
 func (ps *PolicyStore) setPolicyInternal(ctx context.Context, p *Policy) error {
 	ps.modifyLock.Lock()
 	defer ps.modifyLock.Unlock()
 
+	return ps.setPolicyLocked(ctx, p)
+}
+
+func (ps *PolicyStore) setPolicyLocked(ctx context.Context, p *Policy) error {
 	// Get the appropriate view based on policy type and namespace
 	view := ps.getBarrierView(p.namespace, p.Type)
 	if view == nil {
@@ -539,7 +571,17 @@ func (ps *PolicyStore) ListPolicies(ctx context.Context, policyType PolicyType)
 
 // DeletePolicy is used to delete the named policy
 func (ps *PolicyStore) DeletePolicy(ctx context.Context, name string, policyType PolicyType) error {
-	return ps.switchedDeletePolicy(ctx, name, policyType, true, false)
+	return ps.DeletePolicyWithAuthor(ctx, name, policyType, nil)
+}
+
+// DeletePolicyWithAuthor deletes the named policy and records the deletion,
+// along with its author, in the policy history. Previous versions are kept
+// so that the policy can be restored.
+func (ps *PolicyStore) DeletePolicyWithAuthor(ctx context.Context, name string, policyType PolicyType, author *ConfigChangeAuthor) error {
+	if author == nil {
+		author = &ConfigChangeAuthor{}
+	}
+	return ps.switchedDeletePolicyWithHistory(ctx, name, policyType, true, false, author)
 }
 
 // deletePolicyForce is used to delete the named policy and force it even if
@@ -551,6 +593,12 @@ func (ps *PolicyStore) deletePolicyForce(ctx context.Context, name string, polic
 }
 
 func (ps *PolicyStore) switchedDeletePolicy(ctx context.Context, name string, policyType PolicyType, physicalDeletion, force bool) error {
+	return ps.switchedDeletePolicyWithHistory(ctx, name, policyType, physicalDeletion, force, nil)
+}
+
+// switchedDeletePolicyWithHistory deletes a policy like switchedDeletePolicy
+// and, if author is set, records the deletion in the policy history.
+func (ps *PolicyStore) switchedDeletePolicyWithHistory(ctx context.Context, name string, policyType PolicyType, physicalDeletion, force bool, author *ConfigChangeAuthor) error {
 	defer metrics.MeasureSince([]string{"policy", "delete_policy"}, time.Now())
 
 	ns, err := namespace.FromContext(ctx)
@@ -585,10 +633,25 @@ func (ps *PolicyStore) switchedDeletePolicy(ctx context.Context, name string, po
 		}
 
 		if physicalDeletion {
+			var previous *PolicyEntry
+			if author != nil {
+				previous, err = ps.readPolicyEntry(ctx, name, policyType)
+				if err != nil {
+					return err
+				}
+			}
+
 			err := view.Delete(ctx, name)
 			if err != nil {
 				return fmt.Errorf("failed to delete policy: %w", err)
 			}
+
+			if previous != nil {
+				ps.recordPolicyVersion(ctx, name, previous, &PolicyVersion{
+					Deleted: true,
+					Author:  author,
+				})
+			}
 		}
 
 		if ps.tokenPoliciesLRU != nil {
@@ -602,6 +665,112 @@ func (ps *PolicyStore) switchedDeletePolicy(ctx context.Context, name string, po
 	return nil
 }
 
+// readPolicyEntry returns the stored entry of a policy, or nil if it does
+// not exist. The caller must hold the modify lock.
+func (ps *PolicyStore) readPolicyEntry(ctx context.Context, name string, policyType PolicyType) (*PolicyEntry, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	view := ps.getBarrierView(ns, policyType)
+	if view == nil {
+		return nil, fmt.Errorf("unable to get the barrier subview for policy type %q", policyType)
+	}
+
+	out, err := view.Get(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy: %w", err)
+	}
+	if out == nil {
+		return nil, nil
+	}
+
+	entry := new(PolicyEntry)
+	if err := out.DecodeJSON(entry); err != nil {
+		return nil, fmt.Errorf("failed to parse policy: %w", err)
+	}
+	return entry, nil
+}
+
+// recordPolicyVersion appends version to the history of the named policy.
+// If the policy existed before history was kept, its previous contents are
+// recorded first so that the change can be rolled back. Failures are only
+// logged, as the change itself has already been persisted. The caller must
+// hold the modify lock.
+func (ps *PolicyStore) recordPolicyVersion(ctx context.Context, name string, previous *PolicyEntry, version *PolicyVersion) {
+	if ps.history == nil {
+		return
+	}
+
+	now := time.Now().UTC()
+	err := func() error {
+		versions, err := ps.history.versions(ctx, name)
+		if err != nil {
+			return err
+		}
+		if len(versions) == 0 && previous != nil {
+			err := ps.history.append(ctx, name, func(v int) interface{} {
+				return &PolicyVersion{Version: v, Raw: previous.Raw, Time: now}
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return ps.history.append(ctx, name, func(v int) interface{} {
+			version.Version = v
+			version.Time = now
+			return version
+		})
+	}()
+	if err != nil {
+		ps.logger.Error("failed to record policy version", "name", name, "error", err)
+	}
+}
+
+// PolicyVersions returns the recorded versions of the named ACL policy in
+// ascending order. The policy does not need to exist anymore.
+func (ps *PolicyStore) PolicyVersions(ctx context.Context, name string) ([]*PolicyVersion, error) {
+	name = ps.sanitizeName(name)
+
+	ps.modifyLock.RLock()
+	defer ps.modifyLock.RUnlock()
+
+	versions, err := ps.history.versions(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*PolicyVersion, 0, len(versions))
+	for _, v := range versions {
+		version := new(PolicyVersion)
+		ok, err := ps.history.get(ctx, name, v, version)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result = append(result, version)
+		}
+	}
+	return result, nil
+}
+
+// PolicyVersion returns the given recorded version of the named ACL policy,
+// or nil if it does not exist.
+func (ps *PolicyStore) PolicyVersion(ctx context.Context, name string, version int) (*PolicyVersion, error) {
+	name = ps.sanitizeName(name)
+
+	ps.modifyLock.RLock()
+	defer ps.modifyLock.RUnlock()
+
+	out := new(PolicyVersion)
+	ok, err := ps.history.get(ctx, name, version, out)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ACL is used to return an ACL which is built using the
 // named policies and pre-fetched policies if given.
 func (ps *PolicyStore) ACL(ctx context.Context, entity *identity.Entity, policyNames map[string][]string, additionalPolicies ...*Policy) (*ACL, error) {
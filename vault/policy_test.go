@@ -420,6 +420,24 @@ path "/" {
 	}
 }
 
+func TestPolicy_ParseBadParameterPattern(t *testing.T) {
+	_, err := ParseACLPolicy(namespace.RootNamespace, strings.TrimSpace(`
+path "/" {
+	capabilities = ["update"]
+	allowed_parameters = {
+		"data.env" = ["regex:[a-"]
+	}
+}
+`))
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+
+	if !strings.Contains(err.Error(), `path "/": allowed parameter "data.env": invalid regular expression "[a-"`) {
+		t.Errorf("bad error: %s", err)
+	}
+}
+
 func TestPolicy_ParseBadSegmentWildcard(t *testing.T) {
 	_, err := ParseACLPolicy(namespace.RootNamespace, strings.TrimSpace(`
 path "foo/+*" {
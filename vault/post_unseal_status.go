@@ -0,0 +1,132 @@
+package vault
+
+import (
+	"sync"
+	"time"
+)
+
+// The phases of the post-unseal setup reported by the post-unseal status.
+const (
+	postUnsealPhaseMounts                = "mounts"
+	postUnsealPhasePolicies              = "policies"
+	postUnsealPhaseCredentials           = "credentials"
+	postUnsealPhaseBackendInitialization = "backend_initialization"
+	postUnsealPhaseLeases                = "leases"
+)
+
+const (
+	PostUnsealPhaseRunning  = "running"
+	PostUnsealPhaseComplete = "complete"
+	PostUnsealPhaseFailed   = "failed"
+)
+
+// PostUnsealStatus reports the progress of the post-unseal setup of the
+// node, phase by phase.
+type PostUnsealStatus struct {
+	InProgress bool                    `json:"in_progress"`
+	Phases     []PostUnsealPhaseStatus `json:"phases"`
+}
+
+// PostUnsealPhaseStatus reports the progress of a phase of the post-unseal
+// setup. Total and Completed count the items handled by the phase, such as
+// mounts or leases.
+type PostUnsealPhaseStatus struct {
+	Name      string     `json:"name"`
+	Status    string     `json:"status"`
+	Total     int        `json:"total"`
+	Completed int        `json:"completed"`
+	StartTime time.Time  `json:"start_time"`
+	EndTime   *time.Time `json:"end_time,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// postUnsealProgress tracks the phases of the current post-unseal setup.
+type postUnsealProgress struct {
+	l      sync.Mutex
+	phases []*postUnsealPhase
+}
+
+// postUnsealPhase tracks the progress of a phase. The phases of a previous
+// setup can still be updated by the goroutines they were handed to, without
+// being reported anymore.
+type postUnsealPhase struct {
+	progress *postUnsealProgress
+	status   PostUnsealPhaseStatus
+}
+
+// reset forgets the phases of the previous post-unseal setup.
+func (p *postUnsealProgress) reset() {
+	p.l.Lock()
+	defer p.l.Unlock()
+	p.phases = nil
+}
+
+// start starts tracking a phase handling the given number of items.
+func (p *postUnsealProgress) start(name string, total int) *postUnsealPhase {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	phase := &postUnsealPhase{
+		progress: p,
+		status: PostUnsealPhaseStatus{
+			Name:      name,
+			Status:    PostUnsealPhaseRunning,
+			Total:     total,
+			StartTime: time.Now().UTC(),
+		},
+	}
+	p.phases = append(p.phases, phase)
+	return phase
+}
+
+// status returns a snapshot of the progress of the post-unseal setup.
+func (p *postUnsealProgress) status() *PostUnsealStatus {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	status := &PostUnsealStatus{
+		Phases: make([]PostUnsealPhaseStatus, 0, len(p.phases)),
+	}
+	for _, phase := range p.phases {
+		if phase.status.Status == PostUnsealPhaseRunning {
+			status.InProgress = true
+		}
+		status.Phases = append(status.Phases, phase.status)
+	}
+	return status
+}
+
+// setTotal sets the number of items handled by the phase, once known.
+func (ph *postUnsealPhase) setTotal(total int) {
+	ph.progress.l.Lock()
+	defer ph.progress.l.Unlock()
+	ph.status.Total = total
+}
+
+// increment records that an item of the phase was handled.
+func (ph *postUnsealPhase) increment() {
+	ph.progress.l.Lock()
+	defer ph.progress.l.Unlock()
+	ph.status.Completed++
+}
+
+// done records the end of the phase, failed if err is set.
+func (ph *postUnsealPhase) done(err error) {
+	ph.progress.l.Lock()
+	defer ph.progress.l.Unlock()
+
+	now := time.Now().UTC()
+	ph.status.EndTime = &now
+	ph.status.Status = PostUnsealPhaseComplete
+	if err != nil {
+		ph.status.Status = PostUnsealPhaseFailed
+		ph.status.Error = err.Error()
+	}
+}
+
+// PostUnsealStatus returns the progress of the post-unseal setup of the
+// node. It is available while the node is sealed, so that operators can
+// follow a failover.
+func (c *Core) PostUnsealStatus() *PostUnsealStatus {
+	return c.postUnsealProgress.status()
+}
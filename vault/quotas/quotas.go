@@ -267,6 +267,12 @@ type Request struct {
 	// ClientAddress is client unique addressable string (e.g. IP address). It can
 	// be empty if the quota type does not need it.
 	ClientAddress string
+
+	// ResolveClientIdentity, if set, returns the identity entity of the client
+	// token of the request and the accessor of the auth mount which issued
+	// the token. It is only called by quotas grouping requests by identity,
+	// as it requires looking up the token.
+	ResolveClientIdentity func() (entityID, mountAccessor string)
 }
 
 // NewManager creates and initializes a new quota manager to hold all the quota
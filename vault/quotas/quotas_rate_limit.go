@@ -35,6 +35,32 @@ const (
 	EnvVaultEnableRateLimitAuditLogging = "VAULT_ENABLE_RATE_LIMIT_AUDIT_LOGGING"
 )
 
+const (
+	// GroupByIP groups the requests by the address of the client.
+	GroupByIP = "ip"
+
+	// GroupByEntity groups the requests by the identity entity of the client
+	// token, falling back to the address of the client for requests without a
+	// token or whose token has no entity.
+	GroupByEntity = "entity"
+
+	// GroupByAlias groups the requests by the identity entity of the client
+	// token and the auth mount the token was issued by, i.e. by entity alias,
+	// falling back to the address of the client like GroupByEntity.
+	GroupByAlias = "alias"
+)
+
+// ValidGroupBy returns whether the given value is a supported way of grouping
+// the requests of a rate limit quota. An empty value groups by IP address.
+func ValidGroupBy(groupBy string) bool {
+	switch groupBy {
+	case "", GroupByIP, GroupByEntity, GroupByAlias:
+		return true
+	default:
+		return false
+	}
+}
+
 // Ensure that RateLimitQuota implements the Quota interface
 var _ Quota = (*RateLimitQuota)(nil)
 
@@ -75,6 +101,11 @@ type RateLimitQuota struct {
 	// reaches the rate limit.
 	BlockInterval time.Duration `json:"block_interval"`
 
+	// GroupBy defines how requests are attributed to clients, each client
+	// having its own rate limiter. It is one of GroupByIP, GroupByEntity or
+	// GroupByAlias; an empty value groups by IP address.
+	GroupBy string `json:"group_by,omitempty"`
+
 	lock                *sync.RWMutex
 	store               limiter.Store
 	logger              log.Logger
@@ -125,6 +156,7 @@ func (q *RateLimitQuota) Clone() Quota {
 		BlockInterval: q.BlockInterval,
 		Rate:          q.Rate,
 		Interval:      q.Interval,
+		GroupBy:       q.GroupBy,
 	}
 	return rlq
 }
@@ -158,6 +190,10 @@ func (rlq *RateLimitQuota) initialize(logger log.Logger, ms *metricsutil.Cluster
 		return fmt.Errorf("invalid block interval: %v", rlq.BlockInterval)
 	}
 
+	if !ValidGroupBy(rlq.GroupBy) {
+		return fmt.Errorf("invalid group by: %q", rlq.GroupBy)
+	}
+
 	if logger != nil {
 		rlq.logger = logger
 	}
@@ -275,10 +311,32 @@ func (rlq *RateLimitQuota) QuotaName() string {
 	return rlq.Name
 }
 
+// clientKey returns the key identifying the client of the request, according
+// to how the quota groups the requests. Requests which cannot be attributed to
+// an identity are grouped by client address.
+func (rlq *RateLimitQuota) clientKey(req *Request) string {
+	if rlq.GroupBy != GroupByEntity && rlq.GroupBy != GroupByAlias {
+		return req.ClientAddress
+	}
+	if req.ResolveClientIdentity == nil {
+		return req.ClientAddress
+	}
+
+	entityID, mountAccessor := req.ResolveClientIdentity()
+	switch {
+	case entityID == "":
+		return req.ClientAddress
+	case rlq.GroupBy == GroupByAlias && mountAccessor != "":
+		return "alias:" + entityID + ":" + mountAccessor
+	default:
+		return "entity:" + entityID
+	}
+}
+
 // allow decides if the request is allowed by the quota. An error will be
 // returned if the request ID or address is empty. If the path is exempt, the
 // quota will not be evaluated. Otherwise, the client rate limiter is retrieved
-// by address and the rate limit quota is checked against that limiter.
+// by client key and the rate limit quota is checked against that limiter.
 func (rlq *RateLimitQuota) allow(ctx context.Context, req *Request) (Response, error) {
 	resp := Response{
 		Headers: make(map[string]string),
@@ -288,6 +346,8 @@ func (rlq *RateLimitQuota) allow(ctx context.Context, req *Request) (Response, e
 		return resp, fmt.Errorf("missing request client address in quota request")
 	}
 
+	client := rlq.clientKey(req)
+
 	var retryAfter string
 
 	defer func() {
@@ -302,11 +362,11 @@ func (rlq *RateLimitQuota) allow(ctx context.Context, req *Request) (Response, e
 	// of purging blocked clients may not yield a false negative. In other words,
 	// a client may no longer be considered blocked whereas the purging interval
 	// has yet to run.
-	if v, ok := rlq.blockedClients.Load(req.ClientAddress); ok {
+	if v, ok := rlq.blockedClients.Load(client); ok {
 		blockedAt := v.(time.Time)
 		if time.Since(blockedAt) >= rlq.BlockInterval {
 			// allow the request and remove the blocked client
-			rlq.blockedClients.Delete(req.ClientAddress)
+			rlq.blockedClients.Delete(client)
 		} else {
 			// deny the request and return early
 			resp.Allowed = false
@@ -315,7 +375,7 @@ func (rlq *RateLimitQuota) allow(ctx context.Context, req *Request) (Response, e
 		}
 	}
 
-	limit, remaining, reset, allow, err := rlq.store.Take(ctx, req.ClientAddress)
+	limit, remaining, reset, allow, err := rlq.store.Take(ctx, client)
 	if err != nil {
 		return resp, err
 	}
@@ -331,7 +391,7 @@ func (rlq *RateLimitQuota) allow(ctx context.Context, req *Request) (Response, e
 	if !resp.Allowed && rlq.purgeBlocked {
 		blockedAt := time.Now()
 		retryAfter = strconv.Itoa(int(time.Until(blockedAt.Add(rlq.BlockInterval)).Seconds()))
-		rlq.blockedClients.Store(req.ClientAddress, blockedAt)
+		rlq.blockedClients.Store(client, blockedAt)
 	}
 
 	return resp, nil
@@ -227,3 +227,49 @@ func TestRateLimitQuota_Update(t *testing.T) {
 
 	require.Nil(t, quota.close(context.Background()))
 }
+
+func TestRateLimitQuota_Allow_GroupBy(t *testing.T) {
+	identity := func(entityID, mountAccessor string) func() (string, string) {
+		return func() (string, string) {
+			return entityID, mountAccessor
+		}
+	}
+
+	testCases := []struct {
+		groupBy string
+		req     *Request
+		key     string
+	}{
+		{"", &Request{ClientAddress: "10.0.0.1", ResolveClientIdentity: identity("e1", "a1")}, "10.0.0.1"},
+		{GroupByIP, &Request{ClientAddress: "10.0.0.1", ResolveClientIdentity: identity("e1", "a1")}, "10.0.0.1"},
+		{GroupByEntity, &Request{ClientAddress: "10.0.0.1", ResolveClientIdentity: identity("e1", "a1")}, "entity:e1"},
+		{GroupByEntity, &Request{ClientAddress: "10.0.0.1", ResolveClientIdentity: identity("", "")}, "10.0.0.1"},
+		{GroupByEntity, &Request{ClientAddress: "10.0.0.1"}, "10.0.0.1"},
+		{GroupByAlias, &Request{ClientAddress: "10.0.0.1", ResolveClientIdentity: identity("e1", "a1")}, "alias:e1:a1"},
+		{GroupByAlias, &Request{ClientAddress: "10.0.0.1", ResolveClientIdentity: identity("e1", "")}, "entity:e1"},
+	}
+
+	for _, tc := range testCases {
+		rlq := &RateLimitQuota{GroupBy: tc.groupBy}
+		require.Equal(t, tc.key, rlq.clientKey(tc.req), "group by %q", tc.groupBy)
+	}
+
+	// Clients behind the same address are rate limited separately when grouped
+	// by entity.
+	rlq := NewRateLimitQuota("test-rate-limiter", "qa", "/foo/bar", "", "", 1, time.Minute, 0)
+	rlq.GroupBy = GroupByEntity
+	require.NoError(t, rlq.initialize(logging.NewVaultLogger(log.Trace), metricsutil.BlackholeSink()))
+	defer rlq.close(context.Background())
+
+	for _, entityID := range []string{"e1", "e2"} {
+		resp, err := rlq.allow(context.Background(), &Request{ClientAddress: "10.0.0.1", ResolveClientIdentity: identity(entityID, "")})
+		require.NoError(t, err)
+		require.True(t, resp.Allowed, "entity %s", entityID)
+	}
+	resp, err := rlq.allow(context.Background(), &Request{ClientAddress: "10.0.0.1", ResolveClientIdentity: identity("e1", "")})
+	require.NoError(t, err)
+	require.False(t, resp.Allowed)
+
+	rlq.GroupBy = "token"
+	require.Error(t, rlq.initialize(nil, nil))
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
@@ -22,12 +23,15 @@ import (
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-secure-stdlib/tlsutil"
 	"github.com/hashicorp/go-uuid"
+	raftlib "github.com/hashicorp/raft"
 	"github.com/mitchellh/mapstructure"
 	wrapping "github.com/openbao/go-kms-wrapping/v2"
 	"github.com/openbao/openbao/api/v2"
+	"github.com/openbao/openbao/helper/namespace"
 	"github.com/openbao/openbao/physical/raft"
 	"github.com/openbao/openbao/sdk/v2/helper/jsonutil"
 	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/openbao/openbao/sdk/v2/physical"
 	"github.com/openbao/openbao/vault/seal"
 	"golang.org/x/net/http2"
 )
@@ -196,6 +200,13 @@ func (c *Core) setupRaftActiveNode(ctx context.Context) error {
 		return err
 	}
 
+	if err := c.startRaftDRSecondary(ctx); err != nil {
+		return err
+	}
+	if err := c.startRaftPerfSecondary(ctx); err != nil {
+		return err
+	}
+
 	return c.startPeriodicRaftTLSRotate(ctx)
 }
 
@@ -211,6 +222,8 @@ func (c *Core) stopRaftActiveNode() {
 		raftBackend.StopAutopilot()
 	}
 
+	c.stopRaftDRSecondary()
+	c.stopRaftPerfSecondary()
 	c.stopPeriodicRaftTLSRotate()
 }
 
@@ -628,6 +641,93 @@ func (c *Core) checkRaftTLSKeyUpgrades(ctx context.Context) error {
 	return nil
 }
 
+// applyRaftSnapshot replaces the raft storage with the snapshot, sealing the
+// node if it fails. afterRestore, if set, is called once the snapshot is
+// restored, before the node is set up again.
+func (c *Core) applyRaftSnapshot(raftStorage *raft.RaftBackend, metadata raftlib.SnapshotMeta, snapFile io.Reader, afterRestore func(context.Context) error) (retErr error) {
+	// Grab statelock
+	l := newLockGrabber(c.stateLock.Lock, c.stateLock.Unlock, c.standbyStopCh.Load().(chan struct{}))
+	go l.grab()
+	if stopped := l.lockOrStop(); stopped {
+		c.logger.Error("not applying snapshot; shutting down")
+		return errors.New("not applying snapshot; shutting down")
+	}
+	defer c.stateLock.Unlock()
+
+	// If we failed to restore the snapshot we should seal this node as
+	// it's in an unknown state
+	defer func() {
+		if retErr != nil {
+			if err := c.sealInternalWithOptions(false, false, true); err != nil {
+				c.logger.Error("failed to seal node", "error", err)
+			}
+		}
+	}()
+
+	ctx, ctxCancel := context.WithCancel(namespace.RootContext(nil))
+	defer func() {
+		// On success the context is handed over to postUnseal
+		if retErr != nil {
+			ctxCancel()
+		}
+	}()
+
+	// We are calling the callback function synchronously here while we
+	// have the lock. So set it to nil and restore the callback when we
+	// finish.
+	raftStorage.SetRestoreCallback(nil)
+	defer raftStorage.SetRestoreCallback(c.raftSnapshotRestoreCallback(true, true))
+
+	// Do a preSeal to clear vault's in-memory caches and shut down any
+	// systems that might be holding the encryption access.
+	c.logger.Info("shutting down prior to restoring snapshot")
+	if err := c.preSeal(); err != nil {
+		c.logger.Error("raft snapshot restore failed preSeal", "error", err)
+		return err
+	}
+
+	c.logger.Info("applying snapshot")
+	if err := raftStorage.RestoreSnapshot(ctx, metadata, snapFile); err != nil {
+		c.logger.Error("error while restoring raft snapshot", "error", err)
+		return err
+	}
+
+	// Run invalidation logic synchronously here
+	callback := c.raftSnapshotRestoreCallback(false, false)
+	if err := callback(ctx); err != nil {
+		return err
+	}
+
+	{
+		// If the snapshot was taken while another node was leader we
+		// need to reset the leader information to this node.
+		if err := c.underlyingPhysical.Put(ctx, &physical.Entry{
+			Key:   CoreLockPath,
+			Value: []byte(c.leaderUUID),
+		}); err != nil {
+			c.logger.Error("cluster setup failed", "error", err)
+			return err
+		}
+		// re-advertise our cluster information
+		if err := c.advertiseLeader(ctx, c.leaderUUID, nil); err != nil {
+			c.logger.Error("cluster setup failed", "error", err)
+			return err
+		}
+	}
+	if afterRestore != nil {
+		if err := afterRestore(ctx); err != nil {
+			c.logger.Error("raft snapshot restore failed", "error", err)
+			return err
+		}
+	}
+	if err := c.postUnseal(ctx, ctxCancel, standardUnsealStrategy{}); err != nil {
+		c.logger.Error("raft snapshot restore failed postUnseal", "error", err)
+		return err
+	}
+
+	return nil
+}
+
 // handleSnapshotRestore is for the raft backend to hook back into core after a
 // snapshot is restored so we can clear the necessary caches and handle changing
 // keyrings or root keys
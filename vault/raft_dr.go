@@ -0,0 +1,306 @@
+package vault
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-retryablehttp"
+	raftlib "github.com/hashicorp/raft"
+	"github.com/openbao/openbao/api/v2"
+	"github.com/openbao/openbao/physical/raft"
+	"github.com/openbao/openbao/sdk/v2/helper/jsonutil"
+	"github.com/openbao/openbao/sdk/v2/helper/tlsutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"golang.org/x/net/http2"
+)
+
+const (
+	// raftDRSecondaryConfigPath holds the configuration of a DR secondary.
+	// As it is replaced along with the rest of the storage by every snapshot
+	// from the primary, it is written again after each restore.
+	raftDRSecondaryConfigPath = "core/raft/dr-secondary"
+
+	raftDRDefaultInterval = time.Minute
+	raftDRMinInterval     = 10 * time.Second
+
+	// raftDRPathPrefix is the only path served by a DR secondary.
+	raftDRPathPrefix = "sys/storage/raft/dr/"
+)
+
+// raftDRSecondaryConfig is the configuration of a cluster replicating the
+// storage of a primary cluster through raft snapshots.
+type raftDRSecondaryConfig struct {
+	PrimaryAddress string        `json:"primary_address"`
+	Token          string        `json:"token"`
+	CACert         string        `json:"ca_cert"`
+	Interval       time.Duration `json:"interval"`
+}
+
+// raftDRSecondaryStatus tracks the snapshots applied by a DR secondary.
+type raftDRSecondaryStatus struct {
+	LastSync    time.Time
+	LastAttempt time.Time
+	LastIndex   uint64
+	LastError   string
+}
+
+// IsRaftDRSecondary returns whether the cluster is a DR secondary, which only
+// serves the DR endpoints until it is promoted.
+func (c *Core) IsRaftDRSecondary() bool {
+	c.raftDRLock.Lock()
+	defer c.raftDRLock.Unlock()
+
+	return c.raftDRConfig != nil
+}
+
+func (c *Core) loadRaftDRSecondaryConfig(ctx context.Context) (*raftDRSecondaryConfig, error) {
+	entry, err := c.barrier.Get(ctx, raftDRSecondaryConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var config raftDRSecondaryConfig
+	if err := jsonutil.DecodeJSON(entry.Value, &config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+func (c *Core) persistRaftDRSecondaryConfig(ctx context.Context, config *raftDRSecondaryConfig) error {
+	entry, err := logical.StorageEntryJSON(raftDRSecondaryConfigPath, config)
+	if err != nil {
+		return err
+	}
+
+	return c.barrier.Put(ctx, entry)
+}
+
+// startRaftDRSecondary resumes replication from the primary if the cluster
+// is a DR secondary.
+func (c *Core) startRaftDRSecondary(ctx context.Context) error {
+	if _, ok := c.underlyingPhysical.(*raft.RaftBackend); !ok {
+		return nil
+	}
+
+	config, err := c.loadRaftDRSecondaryConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.raftDRLock.Lock()
+	defer c.raftDRLock.Unlock()
+
+	c.raftDRConfig = config
+	if config != nil {
+		c.startRaftDRSecondaryLocked(false)
+	}
+
+	return nil
+}
+
+// startRaftDRSecondaryLocked starts fetching snapshots from the primary. The
+// first one is fetched after an interval, unless immediate is set.
+func (c *Core) startRaftDRSecondaryLocked(immediate bool) {
+	c.stopRaftDRSecondaryLocked()
+
+	stopCh := make(chan struct{})
+	c.raftDRStopCh = stopCh
+
+	logger := c.logger.Named("raft-dr")
+	go c.raftDRSecondaryLoop(logger, c.raftDRConfig, stopCh, immediate)
+}
+
+func (c *Core) stopRaftDRSecondary() {
+	c.raftDRLock.Lock()
+	defer c.raftDRLock.Unlock()
+
+	c.stopRaftDRSecondaryLocked()
+}
+
+func (c *Core) stopRaftDRSecondaryLocked() {
+	if c.raftDRStopCh != nil {
+		close(c.raftDRStopCh)
+	}
+	c.raftDRStopCh = nil
+}
+
+func (c *Core) raftDRSecondaryLoop(logger hclog.Logger, config *raftDRSecondaryConfig, stopCh chan struct{}, immediate bool) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	wait := config.Interval
+	if immediate {
+		wait = 0
+	}
+
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-time.After(wait):
+		}
+		wait = config.Interval
+
+		applying, err := c.raftDRSecondarySync(ctx, logger, config)
+		if err != nil {
+			select {
+			case <-stopCh:
+				return
+			default:
+			}
+			logger.Error("failed to replicate from the primary", "error", err)
+		}
+		if applying {
+			// The node is set up again once the snapshot is applied,
+			// which starts a new loop.
+			return
+		}
+	}
+}
+
+// raftDRSecondarySync fetches a snapshot from the primary and starts applying
+// it if the primary storage changed since the last one.
+func (c *Core) raftDRSecondarySync(ctx context.Context, logger hclog.Logger, config *raftDRSecondaryConfig) (applying bool, retErr error) {
+	raftStorage, ok := c.underlyingPhysical.(*raft.RaftBackend)
+	if !ok {
+		return false, errors.New("raft storage is not in use")
+	}
+
+	defer func() {
+		c.raftDRLock.Lock()
+		defer c.raftDRLock.Unlock()
+
+		c.raftDRStatus.LastAttempt = time.Now()
+		c.raftDRStatus.LastError = ""
+		if retErr != nil {
+			c.raftDRStatus.LastError = retErr.Error()
+		}
+	}()
+
+	snapFile, cleanup, metadata, err := c.fetchRaftPrimarySnapshot(ctx, raftStorage, config)
+	if err != nil {
+		return false, err
+	}
+
+	c.raftDRLock.Lock()
+	unchanged := metadata.Index == c.raftDRStatus.LastIndex
+	c.raftDRLock.Unlock()
+	if unchanged {
+		cleanup()
+		logger.Debug("primary storage is unchanged", "index", metadata.Index)
+		return false, nil
+	}
+
+	logger.Info("applying snapshot from the primary", "index", metadata.Index)
+	go func() {
+		defer cleanup()
+
+		c.applyRaftSnapshot(raftStorage, metadata, snapFile, func(ctx context.Context) error {
+			c.raftDRLock.Lock()
+			defer c.raftDRLock.Unlock()
+
+			// The cluster may have been promoted while the snapshot was
+			// fetched.
+			if c.raftDRConfig != nil {
+				if err := c.persistRaftDRSecondaryConfig(ctx, c.raftDRConfig); err != nil {
+					return err
+				}
+			}
+
+			c.raftDRStatus.LastSync = time.Now()
+			c.raftDRStatus.LastIndex = metadata.Index
+			return nil
+		})
+	}()
+
+	return true, nil
+}
+
+// fetchRaftPrimarySnapshot writes a snapshot of the primary cluster to a
+// temporary file, after verifying that it was sealed with the same seal.
+func (c *Core) fetchRaftPrimarySnapshot(ctx context.Context, raftStorage *raft.RaftBackend, config *raftDRSecondaryConfig) (*os.File, func(), raftlib.SnapshotMeta, error) {
+	client, err := raftDRPrimaryClient(config)
+	if err != nil {
+		return nil, nil, raftlib.SnapshotMeta{}, err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(client.Sys().RaftSnapshotWithOptions(ctx, pw, &api.RaftSnapshotOptions{Unencrypted: true}))
+	}()
+	defer pr.Close()
+
+	snapFile, cleanup, metadata, err := raftStorage.WriteSnapshotToTemp(pr, c.seal.GetAccess())
+	if err != nil {
+		if strings.Contains(err.Error(), "failed to open the sealed hashes") {
+			return nil, nil, raftlib.SnapshotMeta{}, errors.New("could not verify the snapshot; the primary must use the same seal as this cluster")
+		}
+		return nil, nil, raftlib.SnapshotMeta{}, err
+	}
+
+	return snapFile, cleanup, metadata, nil
+}
+
+// raftDRPrimaryClient returns a client for the primary cluster, configured
+// from the DR configuration only: unlike api.DefaultConfig, the BAO_*
+// environment variables of the server are not read.
+func raftDRPrimaryClient(config *raftDRSecondaryConfig) (*api.Client, error) {
+	transport := cleanhttp.DefaultPooledTransport()
+	transport.TLSHandshakeTimeout = 10 * time.Second
+	transport.TLSClientConfig = &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+	if config.CACert != "" {
+		tlsConfig, err := tlsutil.ClientTLSConfig([]byte(config.CACert), nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create TLS config: %w", err)
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+	if err := http2.ConfigureTransport(transport); err != nil {
+		return nil, fmt.Errorf("failed to configure TLS: %w", err)
+	}
+
+	client, err := api.NewClient(&api.Config{
+		Address: config.PrimaryAddress,
+		HttpClient: &http.Client{
+			Transport: transport,
+			// Redirects are handled by the API client
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		Timeout:    60 * time.Second,
+		MaxRetries: 2,
+		Backoff:    retryablehttp.LinearJitterBackoff,
+	})
+	if err != nil {
+		return nil, err
+	}
+	client.SetToken(config.Token)
+	// NewClient still picks up BAO_NAMESPACE, while the primary must be
+	// queried in its root namespace.
+	client.ClearNamespace()
+
+	return client, nil
+}
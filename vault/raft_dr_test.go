@@ -0,0 +1,126 @@
+package vault
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/framework"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+func TestCore_RaftDRSecondary(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	request := func(op logical.Operation, path string, data map[string]interface{}) (*logical.Response, error) {
+		return c.HandleRequest(ctx, &logical.Request{
+			Operation:   op,
+			Path:        path,
+			Data:        data,
+			ClientToken: root,
+		})
+	}
+
+	// The DR paths are only registered with raft storage, so their handlers
+	// are called directly.
+	b := c.systemBackend
+	handle := func(op logical.Operation, path string, data map[string]interface{}) (*logical.Response, error) {
+		req := &logical.Request{Operation: op, Path: path, Data: data}
+		for _, p := range b.raftStoragePaths() {
+			if p.Pattern == path {
+				return p.Operations[op].Handler()(ctx, req, &framework.FieldData{Raw: data, Schema: p.Fields})
+			}
+		}
+		t.Fatalf("no path %s", path)
+		return nil, nil
+	}
+
+	// Only raft storage can be replicated.
+	resp, err := handle(logical.UpdateOperation, "storage/raft/dr/secondary", map[string]interface{}{
+		"primary_address": "https://127.0.0.1:8200",
+		"token":           "primary",
+	})
+	if err == nil || resp == nil || !resp.IsError() {
+		t.Fatalf("expected an error, resp:%#v", resp)
+	}
+
+	resp, err = handle(logical.ReadOperation, "storage/raft/dr/status", nil)
+	if err != nil || resp == nil || resp.Data["mode"] != "primary" {
+		t.Fatalf("bad status, err:%v resp:%#v", err, resp)
+	}
+
+	// A secondary only serves the DR endpoints until it is promoted.
+	c.raftDRLock.Lock()
+	c.raftDRConfig = &raftDRSecondaryConfig{
+		PrimaryAddress: "https://127.0.0.1:8200",
+		Token:          "primary",
+		Interval:       raftDRDefaultInterval,
+	}
+	c.raftDRLock.Unlock()
+
+	if _, err := request(logical.ReadOperation, "sys/mounts", nil); err == nil {
+		t.Fatal("expected requests to be refused on a secondary")
+	}
+	resp, err = handle(logical.ReadOperation, "storage/raft/dr/secondary", nil)
+	if err != nil || resp == nil || resp.Data["primary_address"] != "https://127.0.0.1:8200" {
+		t.Fatalf("bad config, err:%v resp:%#v", err, resp)
+	}
+	if _, ok := resp.Data["token"]; ok {
+		t.Fatal("token was returned")
+	}
+	resp, err = handle(logical.ReadOperation, "storage/raft/dr/status", nil)
+	if err != nil || resp == nil || resp.Data["mode"] != "secondary" {
+		t.Fatalf("bad status, err:%v resp:%#v", err, resp)
+	}
+
+	if _, err := handle(logical.UpdateOperation, "storage/raft/dr/secondary/promote", nil); err != nil {
+		t.Fatal(err)
+	}
+	if c.IsRaftDRSecondary() {
+		t.Fatal("cluster is still a secondary")
+	}
+	if _, err := request(logical.ReadOperation, "sys/mounts", nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := handle(logical.UpdateOperation, "storage/raft/dr/secondary/promote", nil); err == nil {
+		t.Fatal("expected promoting a primary to fail")
+	}
+}
+
+func TestRaftDRPrimaryClient(t *testing.T) {
+	var namespaces []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "primary-token", r.Header.Get("X-Vault-Token"))
+		namespaces = append(namespaces, r.Header.Get("X-Vault-Namespace"))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// The environment of the server is ignored.
+	t.Setenv("BAO_ADDR", "https://127.0.0.1:1")
+	t.Setenv("BAO_TOKEN", "env-token")
+	t.Setenv("BAO_NAMESPACE", "ns1")
+	t.Setenv("BAO_SKIP_VERIFY", "true")
+
+	config := &raftDRSecondaryConfig{
+		PrimaryAddress: server.URL,
+		Token:          "primary-token",
+	}
+	client, err := raftDRPrimaryClient(config)
+	require.NoError(t, err)
+	require.Equal(t, server.URL, client.Address())
+	_, err = client.Logical().Read("sys/health")
+	require.ErrorContains(t, err, "certificate")
+
+	config.CACert = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	client, err = raftDRPrimaryClient(config)
+	require.NoError(t, err)
+	_, err = client.Logical().Read("sys/health")
+	require.NoError(t, err)
+	require.Equal(t, []string{""}, namespaces)
+}
@@ -0,0 +1,356 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	uuid "github.com/hashicorp/go-uuid"
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/physical/raft"
+	"github.com/openbao/openbao/sdk/v2/helper/jsonutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+// raftPerfSecondaryConfigPath holds the configuration of a performance
+// secondary. Unlike a DR secondary, only the replicated mounts are replaced,
+// so it is kept across syncs.
+const raftPerfSecondaryConfigPath = "core/raft/perf-secondary"
+
+// raftPerfSecondaryConfig is the configuration of a cluster serving read-only
+// copies of selected mounts of a primary cluster, refreshed from its raft
+// snapshots.
+type raftPerfSecondaryConfig struct {
+	raftDRSecondaryConfig
+
+	// Paths are the replicated mounts, such as secret/ or auth/userpass/.
+	Paths []string `json:"paths"`
+}
+
+// raftPerfSecondaryStatus tracks the syncs of a performance secondary.
+type raftPerfSecondaryStatus struct {
+	LastAttempt time.Time
+	LastIndex   uint64
+	LastError   string
+	Mounts      map[string]*raftPerfMountStatus
+}
+
+type raftPerfMountStatus struct {
+	LastSync  time.Time
+	Keys      int
+	LastError string
+}
+
+// raftPerfReplicatedPath returns the replicated mount serving path, if any.
+func (c *Core) raftPerfReplicatedPath(ctx context.Context, path string) string {
+	c.raftPerfLock.Lock()
+	config := c.raftPerfConfig
+	c.raftPerfLock.Unlock()
+	if config == nil {
+		return ""
+	}
+
+	ns, err := namespace.FromContext(ctx)
+	if err != nil || ns.ID != namespace.RootNamespaceID {
+		return ""
+	}
+
+	mount := c.router.MatchingMount(ctx, path)
+	for _, p := range config.Paths {
+		if p == mount {
+			return p
+		}
+	}
+	return ""
+}
+
+func (c *Core) loadRaftPerfSecondaryConfig(ctx context.Context) (*raftPerfSecondaryConfig, error) {
+	entry, err := c.barrier.Get(ctx, raftPerfSecondaryConfigPath)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	var config raftPerfSecondaryConfig
+	if err := jsonutil.DecodeJSON(entry.Value, &config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// startRaftPerfSecondary resumes the syncs from the primary if the cluster is
+// a performance secondary.
+func (c *Core) startRaftPerfSecondary(ctx context.Context) error {
+	if _, ok := c.underlyingPhysical.(*raft.RaftBackend); !ok {
+		return nil
+	}
+
+	config, err := c.loadRaftPerfSecondaryConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.raftPerfLock.Lock()
+	defer c.raftPerfLock.Unlock()
+
+	c.raftPerfConfig = config
+	if config != nil {
+		c.startRaftPerfSecondaryLocked()
+	}
+
+	return nil
+}
+
+// startRaftPerfSecondaryLocked starts syncing the replicated mounts from the
+// primary, starting immediately.
+func (c *Core) startRaftPerfSecondaryLocked() {
+	c.stopRaftPerfSecondaryLocked()
+
+	stopCh := make(chan struct{})
+	c.raftPerfStopCh = stopCh
+
+	logger := c.logger.Named("raft-perf")
+	go c.raftPerfSecondaryLoop(logger, c.raftPerfConfig, stopCh)
+}
+
+func (c *Core) stopRaftPerfSecondary() {
+	c.raftPerfLock.Lock()
+	defer c.raftPerfLock.Unlock()
+
+	c.stopRaftPerfSecondaryLocked()
+}
+
+func (c *Core) stopRaftPerfSecondaryLocked() {
+	if c.raftPerfStopCh != nil {
+		close(c.raftPerfStopCh)
+	}
+	c.raftPerfStopCh = nil
+}
+
+func (c *Core) raftPerfSecondaryLoop(logger hclog.Logger, config *raftPerfSecondaryConfig, stopCh chan struct{}) {
+	ctx, cancel := context.WithCancel(namespace.RootContext(nil))
+	defer cancel()
+	go func() {
+		select {
+		case <-stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	var wait time.Duration
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-time.After(wait):
+		}
+		wait = config.Interval
+
+		if err := c.raftPerfSecondarySync(ctx, logger, config); err != nil {
+			select {
+			case <-stopCh:
+				return
+			default:
+			}
+			logger.Error("failed to sync from the primary", "error", err)
+		}
+	}
+}
+
+// raftPerfSecondarySync fetches a snapshot from the primary and, if its
+// storage changed since the last sync, copies the data of the replicated
+// mounts.
+func (c *Core) raftPerfSecondarySync(ctx context.Context, logger hclog.Logger, config *raftPerfSecondaryConfig) (retErr error) {
+	raftStorage, ok := c.underlyingPhysical.(*raft.RaftBackend)
+	if !ok {
+		return errors.New("raft storage is not in use")
+	}
+
+	mounts := make(map[string]*raftPerfMountStatus, len(config.Paths))
+	defer func() {
+		c.raftPerfLock.Lock()
+		defer c.raftPerfLock.Unlock()
+
+		c.raftPerfStatus.LastAttempt = time.Now()
+		c.raftPerfStatus.LastError = ""
+		if retErr != nil {
+			c.raftPerfStatus.LastError = retErr.Error()
+		}
+		if c.raftPerfStatus.Mounts == nil {
+			c.raftPerfStatus.Mounts = make(map[string]*raftPerfMountStatus)
+		}
+		for path, status := range mounts {
+			c.raftPerfStatus.Mounts[path] = status
+		}
+	}()
+
+	snapFile, cleanup, metadata, err := c.fetchRaftPrimarySnapshot(ctx, raftStorage, &config.raftDRSecondaryConfig)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	c.raftPerfLock.Lock()
+	unchanged := metadata.Index == c.raftPerfStatus.LastIndex
+	c.raftPerfLock.Unlock()
+	if unchanged {
+		logger.Debug("primary storage is unchanged", "index", metadata.Index)
+		return nil
+	}
+
+	// Hold the state lock so that the mounts are not torn down while they
+	// are synced.
+	c.stateLock.RLock()
+	defer c.stateLock.RUnlock()
+	if c.Sealed() || c.standby {
+		return errors.New("not syncing; node is no longer active")
+	}
+
+	var failed bool
+	for _, path := range config.Paths {
+		status := &raftPerfMountStatus{}
+		mounts[path] = status
+
+		if _, err := snapFile.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		keys, err := c.syncMountFromSnapshot(ctx, snapFile, path)
+		if err != nil {
+			logger.Error("failed to sync mount", "path", path, "error", err)
+			status.LastError = err.Error()
+			failed = true
+			continue
+		}
+		status.LastSync = time.Now()
+		status.Keys = keys
+	}
+	if failed {
+		return errors.New("failed to sync some mounts")
+	}
+
+	c.raftPerfLock.Lock()
+	c.raftPerfStatus.LastIndex = metadata.Index
+	c.raftPerfLock.Unlock()
+
+	return nil
+}
+
+// syncMountFromSnapshot makes the data of the mount at path match the data of
+// the mount at the same path in the given snapshot data, creating the mount
+// if needed. It returns the number of storage entries of the mount.
+func (c *Core) syncMountFromSnapshot(ctx context.Context, snap io.ReadSeeker, path string) (int, error) {
+	table, mountPath, barrierPrefix := mountTableType, path, backendBarrierPrefix
+	if strings.HasPrefix(path, credentialRoutePrefix) {
+		table, mountPath, barrierPrefix = credentialTableType, strings.TrimPrefix(path, credentialRoutePrefix), credentialBarrierPrefix
+	}
+
+	source, err := c.snapshotMountEntry(ctx, snap, table, mountPath)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := snap.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	target := c.router.MatchingMountEntry(ctx, path)
+	if target != nil && (target.Table != table || target.Path != mountPath) {
+		return 0, fmt.Errorf("existing mount at %q", target.Path)
+	}
+	if target != nil && target.Type != source.Type {
+		return 0, fmt.Errorf("existing mount at %q is of type %q instead of %q", path, target.Type, source.Type)
+	}
+
+	created := target == nil
+	if created {
+		targetUUID, err := uuid.GenerateUUID()
+		if err != nil {
+			return 0, err
+		}
+		target = &MountEntry{
+			Table:       table,
+			Path:        mountPath,
+			Type:        source.Type,
+			Description: source.Description,
+			UUID:        targetUUID,
+			Config:      source.Config,
+			Options:     source.Options,
+			Local:       true,
+			SealWrap:    source.SealWrap,
+			Version:     source.Version,
+		}
+	}
+
+	// Entries are updated in place and stale ones removed afterwards, so
+	// that readers never see the mount empty.
+	sourcePrefix := barrierPrefix + source.UUID + "/"
+	targetView := NewBarrierView(c.barrier, barrierPrefix+target.UUID+"/")
+	seen := make(map[string]struct{})
+	var changed bool
+	err = raft.ReadSnapshotEntries(snap, sourcePrefix, func(key string, value []byte) error {
+		plaintext, err := c.barrier.Decrypt(ctx, key, value)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt %q, the primary may have rotated its keyring: %w", key, err)
+		}
+		key = strings.TrimPrefix(key, sourcePrefix)
+		seen[key] = struct{}{}
+
+		existing, err := targetView.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		if existing != nil && bytes.Equal(existing.Value, plaintext) {
+			return nil
+		}
+		changed = true
+		return targetView.Put(ctx, &logical.StorageEntry{
+			Key:   key,
+			Value: plaintext,
+		})
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	existing, err := logical.CollectKeys(ctx, targetView)
+	if err != nil {
+		return 0, err
+	}
+	for _, key := range existing {
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		changed = true
+		if err := targetView.Delete(ctx, key); err != nil {
+			return 0, err
+		}
+	}
+
+	switch {
+	case created && table == credentialTableType:
+		err = c.enableCredential(ctx, target)
+	case created:
+		err = c.mount(ctx, target)
+	case changed:
+		// The backend is reloaded so that it does not serve cached data.
+		err = c.reloadBackendCommon(ctx, target, table == credentialTableType)
+	}
+	if err != nil {
+		if created {
+			if clearErr := logical.ClearView(ctx, targetView); clearErr != nil {
+				err = errors.Join(err, fmt.Errorf("failed to clean up synced data: %w", clearErr))
+			}
+		}
+		return 0, err
+	}
+
+	return len(seen), nil
+}
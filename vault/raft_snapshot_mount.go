@@ -0,0 +1,130 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	uuid "github.com/hashicorp/go-uuid"
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/physical/raft"
+	"github.com/openbao/openbao/sdk/v2/helper/strutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+// snapshotMountEntry returns the entry of the secrets engine, or of the auth
+// method if table is credentialTableType, mounted at path in the root
+// namespace, as recorded in the mount tables of the given snapshot data.
+func (c *Core) snapshotMountEntry(ctx context.Context, snap io.Reader, table, path string) (*MountEntry, error) {
+	tableKeys := []string{coreMountConfigPath, coreLocalMountConfigPath}
+	if table == credentialTableType {
+		tableKeys = []string{coreAuthConfigPath, coreLocalAuthConfigPath}
+	}
+
+	var source *MountEntry
+	err := raft.ReadSnapshotEntries(snap, "core/", func(key string, value []byte) error {
+		if !strutil.StrListContains(tableKeys, key) {
+			return nil
+		}
+
+		raw, err := c.barrier.Decrypt(ctx, key, value)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt the mount table of the snapshot, it may have been taken with a different keyring: %w", err)
+		}
+		table, err := c.decodeMountTable(ctx, raw)
+		if err != nil {
+			return fmt.Errorf("failed to decode the mount table of the snapshot: %w", err)
+		}
+
+		for _, entry := range table.Entries {
+			if entry.Path == path && entry.NamespaceID == namespace.RootNamespaceID {
+				source = entry
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		if table == credentialTableType {
+			return nil, fmt.Errorf("no auth method mounted at %q in the snapshot", path)
+		}
+		return nil, fmt.Errorf("no secrets engine mounted at %q in the snapshot", path)
+	}
+	return source, nil
+}
+
+// loadMountFromSnapshot restores the data of the secrets engine mounted at
+// sourcePath in the given snapshot data into a new mount at targetPath, with
+// the same type and configuration. It returns the new mount entry along with
+// the number of restored storage entries.
+func (c *Core) loadMountFromSnapshot(ctx context.Context, snap io.ReadSeeker, sourcePath, targetPath string) (*MountEntry, int, error) {
+	if !strings.HasSuffix(sourcePath, "/") {
+		sourcePath += "/"
+	}
+	if !strings.HasSuffix(targetPath, "/") {
+		targetPath += "/"
+	}
+
+	if conflict := c.router.MountConflict(ctx, targetPath); conflict != "" {
+		return nil, 0, fmt.Errorf("existing mount at %q", conflict)
+	}
+
+	source, err := c.snapshotMountEntry(ctx, snap, mountTableType, sourcePath)
+	if err != nil {
+		return nil, 0, err
+	}
+	if _, err := snap.Seek(0, io.SeekStart); err != nil {
+		return nil, 0, err
+	}
+
+	targetUUID, err := uuid.GenerateUUID()
+	if err != nil {
+		return nil, 0, err
+	}
+	target := &MountEntry{
+		Table:                 mountTableType,
+		Path:                  targetPath,
+		Type:                  source.Type,
+		Description:           source.Description,
+		UUID:                  targetUUID,
+		Config:                source.Config,
+		Options:               source.Options,
+		Local:                 source.Local,
+		SealWrap:              source.SealWrap,
+		ExternalEntropyAccess: source.ExternalEntropyAccess,
+		Version:               source.Version,
+	}
+
+	// The data is written before the mount is created, so that the backend
+	// sees it when it is initialized.
+	sourcePrefix := backendBarrierPrefix + source.UUID + "/"
+	targetView := NewBarrierView(c.barrier, backendBarrierPrefix+targetUUID+"/")
+	var keys int
+	err = raft.ReadSnapshotEntries(snap, sourcePrefix, func(key string, value []byte) error {
+		plaintext, err := c.barrier.Decrypt(ctx, key, value)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt %q, the snapshot may have been taken with a different keyring: %w", key, err)
+		}
+		keys++
+		return targetView.Put(ctx, &logical.StorageEntry{
+			Key:   strings.TrimPrefix(key, sourcePrefix),
+			Value: plaintext,
+		})
+	})
+	if err == nil {
+		err = c.mount(ctx, target)
+	}
+	if err != nil {
+		if clearErr := logical.ClearView(ctx, targetView); clearErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to clean up restored data: %w", clearErr))
+		}
+		return nil, 0, err
+	}
+
+	c.logger.Info("restored mount from snapshot", "source", sourcePath, "target", targetPath, "type", target.Type, "keys", keys)
+	return target, keys, nil
+}
@@ -9,6 +9,7 @@ import (
 	"crypto/subtle"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -45,6 +46,7 @@ type RekeyResult struct {
 	RecoveryKey          bool
 	VerificationRequired bool
 	VerificationNonce    string
+	TOTPURLs             []string
 }
 
 type RekeyVerifyResult struct {
@@ -195,6 +197,9 @@ func (c *Core) BarrierRekeyInit(config *SealConfig) logical.HTTPCodedError {
 		if config.Backup {
 			return logical.CodedError(http.StatusBadRequest, "key backup not supported when using stored keys")
 		}
+		if config.TOTPShares {
+			return logical.CodedError(http.StatusBadRequest, "TOTP shares not supported when using stored keys")
+		}
 	}
 
 	if c.seal.RecoveryKeySupported() {
@@ -249,6 +254,9 @@ func (c *Core) RecoveryRekeyInit(config *SealConfig) logical.HTTPCodedError {
 	if config.StoredShares > 0 {
 		return logical.CodedError(http.StatusBadRequest, "stored shares not supported by recovery key")
 	}
+	if config.TOTPShares {
+		return logical.CodedError(http.StatusBadRequest, "TOTP shares not supported by recovery key")
+	}
 
 	// Check if the seal configuration is valid
 	if err := config.Validate(); err != nil {
@@ -296,10 +304,16 @@ func (c *Core) RecoveryRekeyInit(config *SealConfig) logical.HTTPCodedError {
 
 // RekeyUpdate is used to provide a new key part for the barrier or recovery key.
 func (c *Core) RekeyUpdate(ctx context.Context, key []byte, nonce string, recovery bool) (*RekeyResult, logical.HTTPCodedError) {
+	return c.RekeyUpdateWithTOTP(ctx, key, "", nonce, recovery)
+}
+
+// RekeyUpdateWithTOTP is RekeyUpdate for unseal key shares enrolled for TOTP,
+// which are only accepted along with their current TOTP code.
+func (c *Core) RekeyUpdateWithTOTP(ctx context.Context, key []byte, totpCode, nonce string, recovery bool) (*RekeyResult, logical.HTTPCodedError) {
 	if recovery {
 		return c.RecoveryRekeyUpdate(ctx, key, nonce)
 	}
-	return c.BarrierRekeyUpdate(ctx, key, nonce)
+	return c.barrierRekeyUpdate(ctx, key, totpCode, nonce)
 }
 
 // BarrierRekeyUpdate is used to provide a new key part. Barrier rekey can be done
@@ -308,6 +322,12 @@ func (c *Core) RekeyUpdate(ctx context.Context, key []byte, nonce string, recove
 //
 // N.B.: If recovery keys are used to rekey, the new barrier key shares are not returned.
 func (c *Core) BarrierRekeyUpdate(ctx context.Context, key []byte, nonce string) (*RekeyResult, logical.HTTPCodedError) {
+	return c.barrierRekeyUpdate(ctx, key, "", nonce)
+}
+
+// barrierRekeyUpdate is BarrierRekeyUpdate, with the TOTP code required if
+// the unseal key shares were enrolled for TOTP.
+func (c *Core) barrierRekeyUpdate(ctx context.Context, key []byte, totpCode, nonce string) (*RekeyResult, logical.HTTPCodedError) {
 	// Ensure we are already unsealed
 	c.stateLock.RLock()
 	defer c.stateLock.RUnlock()
@@ -369,6 +389,16 @@ func (c *Core) BarrierRekeyUpdate(ctx context.Context, key []byte, nonce string)
 		}
 	}
 
+	if !useRecovery {
+		if err := c.verifyUnsealTOTP(ctx, c.seal, key, totpCode); err != nil {
+			var invalidKey *ErrInvalidKey
+			if errors.As(err, &invalidKey) {
+				return nil, logical.CodedError(http.StatusBadRequest, err.Error())
+			}
+			return nil, logical.CodedError(http.StatusInternalServerError, err.Error())
+		}
+	}
+
 	// Store this key
 	c.barrierRekeyConfig.RekeyProgress = append(c.barrierRekeyConfig.RekeyProgress, key)
 
@@ -453,6 +483,18 @@ func (c *Core) BarrierRekeyUpdate(ctx context.Context, key []byte, nonce string)
 		}
 	}
 
+	// Enroll the new shares for TOTP before they are encrypted. A rekey
+	// without TOTP shares removes any existing enrollments, as they belong
+	// to the old shares.
+	c.barrierRekeyConfig.TOTPEnrollments = nil
+	if c.barrierRekeyConfig.TOTPShares {
+		c.barrierRekeyConfig.TOTPEnrollments, results.TOTPURLs, err = enrollTOTPShares(c.secureRandomReader, results.SecretShares)
+		if err != nil {
+			c.logger.Error("failed to enroll shares for TOTP", "error", err)
+			return nil, logical.CodedError(http.StatusInternalServerError, fmt.Errorf("failed to enroll shares for TOTP: %w", err).Error())
+		}
+	}
+
 	// If PGP keys are passed in, encrypt shares with corresponding PGP keys.
 	if len(c.barrierRekeyConfig.PGPKeys) > 0 {
 		hexEncodedShares := make([][]byte, len(results.SecretShares))
@@ -464,6 +506,15 @@ func (c *Core) BarrierRekeyUpdate(ctx context.Context, key []byte, nonce string)
 			return nil, logical.CodedError(http.StatusInternalServerError, fmt.Errorf("failed to encrypt shares: %w", err).Error())
 		}
 
+		// The TOTP secrets are the second factor of the shares, so they are
+		// encrypted to the same keys
+		if len(results.TOTPURLs) > 0 {
+			results.TOTPURLs, err = encryptTOTPURLs(results.TOTPURLs, c.barrierRekeyConfig.PGPKeys)
+			if err != nil {
+				return nil, logical.CodedError(http.StatusInternalServerError, fmt.Errorf("failed to encrypt TOTP URLs: %w", err).Error())
+			}
+		}
+
 		// If backup is enabled, store backup info in vault.coreBarrierUnsealKeysBackupPath
 		if c.barrierRekeyConfig.Backup {
 			backupInfo := map[string][]string{}
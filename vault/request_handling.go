@@ -6,9 +6,12 @@ package vault
 import (
 	"context"
 	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -262,6 +265,28 @@ func (c *Core) fetchACLTokenEntryAndEntity(ctx context.Context, req *logical.Req
 		}
 	}
 
+	// Tokens bound to a client certificate are only usable on connections
+	// presenting the same certificate
+	if te.BoundCertThumbprint != "" {
+		thumbprint := clientCertThumbprint(req.Connection)
+		if subtle.ConstantTimeCompare([]byte(thumbprint), []byte(te.BoundCertThumbprint)) != 1 {
+			if c.logger.IsDebug() {
+				c.logger.Debug("token used without its bound client certificate", "path", req.Path)
+			}
+			return nil, nil, nil, nil, logical.ErrPermissionDenied
+		}
+	}
+
+	// Tokens exchanged for a subset of paths are only usable on those paths
+	if allowedPaths := te.InternalMeta[tokenExchangeAllowedPathsInternalMeta]; allowedPaths != "" {
+		if !tokenPathAllowed(strings.Split(allowedPaths, ","), req.Path) {
+			if c.logger.IsDebug() {
+				c.logger.Debug("token used outside of its allowed paths", "path", req.Path)
+			}
+			return nil, nil, nil, nil, logical.ErrPermissionDenied
+		}
+	}
+
 	policyNames := make(map[string][]string)
 	// Add tokens policies
 	policyNames[te.NamespaceID] = append(policyNames[te.NamespaceID], te.Policies...)
@@ -285,6 +310,24 @@ func (c *Core) fetchACLTokenEntryAndEntity(ctx context.Context, req *logical.Req
 		policyNames[nsID] = policyutil.SanitizePolicies(append(policyNames[nsID], nsPolicies...), false)
 	}
 
+	// Network policies bind the tokens they apply to, at each request,
+	// including through the policies derived from their entity
+	var allPolicies []string
+	for _, nsPolicies := range policyNames {
+		allPolicies = append(allPolicies, nsPolicies...)
+	}
+	denied, err := c.checkNetworkPolicies(ctx, req.Connection, allPolicies, te.Path, time.Now())
+	if err != nil {
+		c.logger.Error("failed to evaluate network policies", "error", err)
+		return nil, nil, nil, nil, ErrInternalError
+	}
+	if denied != "" {
+		if c.logger.IsDebug() {
+			c.logger.Debug("request denied by network policy", "network_policy", denied, "path", req.Path)
+		}
+		return nil, nil, nil, nil, logical.ErrPermissionDenied
+	}
+
 	// Attach token's namespace information to the context. Wrapping tokens by
 	// should be able to be used anywhere, so we also special case behavior.
 	var tokenCtx context.Context
@@ -323,6 +366,17 @@ func (c *Core) fetchACLTokenEntryAndEntity(ctx context.Context, req *logical.Req
 	return acl, te, entity, identityPolicies, nil
 }
 
+// clientCertThumbprint returns the base64url-encoded SHA-256 thumbprint of the
+// client certificate presented on the connection, as defined by RFC 8705, or
+// an empty string if no certificate was presented.
+func clientCertThumbprint(conn *logical.Connection) string {
+	if conn == nil || conn.ConnState == nil || len(conn.ConnState.PeerCertificates) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256(conn.ConnState.PeerCertificates[0].Raw)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
 func (c *Core) CheckToken(ctx context.Context, req *logical.Request, unauth bool) (*logical.Auth, *logical.TokenEntry, error) {
 	defer metrics.MeasureSince([]string{"core", "check_token"}, time.Now())
 
@@ -526,7 +580,7 @@ func (c *Core) switchedLockHandleRequest(httpCtx context.Context, req *logical.R
 	if ok {
 		ctx = logical.CreateContextOriginalBody(ctx, body)
 	}
-	resp, err = c.handleCancelableRequest(ctx, req)
+	resp, err = c.handleIdempotentRequest(ctx, req)
 	req.SetTokenEntry(nil)
 	cancel()
 	return resp, err
@@ -546,10 +600,32 @@ func (c *Core) handleCancelableRequest(ctx context.Context, req *logical.Request
 		return logical.ErrorResponse("cannot write to a path ending in '/'"), nil
 	}
 
+	// A DR secondary only serves the endpoints to manage and promote it, as
+	// its storage is replaced by the primary's.
+	if c.IsRaftDRSecondary() && !strings.HasPrefix(req.Path, raftDRPathPrefix) {
+		return logical.ErrorResponse("cluster is a DR secondary; promote it to serve requests"), logical.ErrInvalidRequest
+	}
+
 	// MountPoint will not always be set at this point, so we ensure the req contains it
 	// as it is depended on by some functionality (e.g. quotas)
 	req.MountPoint = c.router.MatchingMount(ctx, req.Path)
 
+	// The leases of the mount may still be restoring after the unseal
+	if c.expiration != nil {
+		if err := c.expiration.restoreMountLeases(ctx, req.Path); err != nil {
+			c.logger.Error("failed to restore leases of mount", "path", req.MountPoint, "error", err)
+			return nil, ErrInternalError
+		}
+	}
+
+	// Mounts replicated from the primary on a performance secondary are
+	// read-only, apart from logins.
+	if req.Operation != logical.ReadOperation && req.Operation != logical.ListOperation && req.Operation != logical.HelpOperation {
+		if path := c.raftPerfReplicatedPath(ctx, req.Path); path != "" && !c.isLoginRequest(ctx, req) {
+			return logical.ErrorResponse(fmt.Sprintf("%s is replicated from the primary cluster and is read-only", path)), logical.ErrReadOnly
+		}
+	}
+
 	err = c.PopulateTokenEntry(ctx, req)
 	if err != nil {
 		return nil, err
@@ -690,6 +766,12 @@ func (c *Core) handleCancelableRequest(ctx context.Context, req *logical.Request
 		return nil, logical.CodedError(403, "namespaces feature not enabled")
 	}
 
+	if req.DryRun {
+		if err := c.checkDryRun(ctx, req); err != nil {
+			return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
+		}
+	}
+
 	var auth *logical.Auth
 	if c.isLoginRequest(ctx, req) {
 		resp, auth, err = c.handleLoginRequest(ctx, req)
@@ -697,6 +779,13 @@ func (c *Core) handleCancelableRequest(ctx context.Context, req *logical.Request
 		resp, auth, err = c.handleRequest(ctx, req)
 	}
 
+	if req.DryRun && err == nil && !resp.IsError() {
+		if resp == nil {
+			resp = &logical.Response{}
+		}
+		resp.AddWarning("dry run: the request was validated but no changes were persisted")
+	}
+
 	if err == nil && c.requestResponseCallback != nil {
 		c.requestResponseCallback(c.router.MatchingBackend(ctx, req.Path), req, resp)
 	}
@@ -731,6 +820,13 @@ func (c *Core) handleCancelableRequest(ctx context.Context, req *logical.Request
 		}
 	}
 
+	// Apply the pagination of list responses
+	if err == nil && req.Operation == logical.ListOperation {
+		if pageErr := c.paginateListResponse(req, resp); pageErr != nil {
+			return nil, pageErr
+		}
+	}
+
 	// We are wrapping if there is anything to wrap (not a nil response) and a
 	// TTL was specified for the token. Errors on a call should be returned to
 	// the caller, so wrapping is turned off if an error is hit and the error
@@ -814,19 +910,101 @@ func (c *Core) handleCancelableRequest(ctx context.Context, req *logical.Request
 		c.logger.Error("failed to audit response", "request_path", req.Path, "error", auditErr)
 		return nil, ErrInternalError
 	}
+	if entry != nil {
+		failed := err != nil || (auditResp != nil && auditResp.IsError())
+		c.mountActivity.record(entry.Accessor, failed, time.Now())
+		if !failed && !req.DryRun {
+			c.engineUsage.record(entry, req, auth, time.Now())
+		}
+	}
 
 	return
 }
 
 func (c *Core) doRouting(ctx context.Context, req *logical.Request) (*logical.Response, error) {
 	// If we're replicating and we get a read-only error from a backend, need to forward to primary
-	return c.router.Route(ctx, req)
+	return c.router.Route(c.deprecationRecorderContext(ctx, req), req)
 }
 
 func (c *Core) isLoginRequest(ctx context.Context, req *logical.Request) bool {
 	return c.router.LoginPath(ctx, req.Path)
 }
 
+// publicPKIPaths are the read-only endpoints of PKI mounts served by
+// IsPublicPKIPath, relative to the mount. A "+" segment matches any single
+// segment and a trailing "*" any suffix, in patterns without "+". ACME endpoints other than the
+// directory are left out, as they create accounts, orders and certificates.
+var publicPKIPaths = []string{
+	"ca",
+	"ca/pem",
+	"ca_chain",
+	"cert/*",
+	"crl",
+	"crl/pem",
+	"crl/delta",
+	"crl/delta/pem",
+	"issuer/+/crl",
+	"issuer/+/crl/der",
+	"issuer/+/crl/pem",
+	"issuer/+/crl/delta",
+	"issuer/+/crl/delta/der",
+	"issuer/+/crl/delta/pem",
+	"issuer/+/der",
+	"issuer/+/json",
+	"issuer/+/pem",
+	"issuers/",
+	"ocsp",
+	"ocsp/*",
+	"acme/directory",
+	"issuer/+/acme/directory",
+	"roles/+/acme/directory",
+	"issuer/+/roles/+/acme/directory",
+}
+
+// IsPublicPKIPath checks if the given request is a read of one of the public
+// endpoints of a PKI mount, such as its CRLs, OCSP responder, issuer
+// certificates and ACME directory. Only GET and HEAD requests are allowed,
+// along with POST requests to the OCSP responder.
+func (c *Core) IsPublicPKIPath(ctx context.Context, method, path string) bool {
+	entry := c.router.MatchingMountEntry(ctx, path)
+	if entry == nil || entry.Type != "pki" {
+		return false
+	}
+	path = strings.TrimPrefix(path, c.router.MatchingMount(ctx, path))
+
+	for _, pattern := range publicPKIPaths {
+		if !matchPublicPKIPath(pattern, path) {
+			continue
+		}
+		switch method {
+		case http.MethodGet, http.MethodHead:
+			return true
+		case http.MethodPost:
+			return pattern == "ocsp"
+		}
+		return false
+	}
+	return false
+}
+
+func matchPublicPKIPath(pattern, path string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(path, prefix)
+	}
+
+	patternParts := strings.Split(pattern, "/")
+	pathParts := strings.Split(path, "/")
+	if len(patternParts) != len(pathParts) {
+		return false
+	}
+	for i, part := range patternParts {
+		if part != "+" && part != pathParts[i] {
+			return false
+		}
+	}
+	return true
+}
+
 func (c *Core) handleRequest(ctx context.Context, req *logical.Request) (retResp *logical.Response, retAuth *logical.Auth, retErr error) {
 	defer metrics.MeasureSince([]string{"core", "handle_request"}, time.Now())
 
@@ -960,8 +1138,22 @@ func (c *Core) handleRequest(ctx context.Context, req *logical.Request) (retResp
 		return nil, auth, retErr
 	}
 
-	// Route the request
-	resp, routeErr := c.doRouting(ctx, req)
+	// Retries of idempotent requests, once authorized and audited, get the
+	// response of the original request instead of being routed again
+	if replayed, ok := idempotentReplay(ctx); ok {
+		c.logger.Debug("replaying the response of an idempotent request", "path", req.Path)
+		return replayed, auth, nil
+	}
+
+	// Route the request, unless it deletes data protected against deletion
+	// without confirmation
+	resp, routeErr := c.checkDeletionProtection(ctx, req)
+	if resp == nil && routeErr == nil {
+		resp, routeErr = c.doRouting(ctx, req)
+	}
+	if errors.Is(routeErr, logical.ErrDeleteConfirmationRequired) {
+		resp = c.deleteConfirmationResponse(ctx, req, resp)
+	}
 	if resp != nil {
 
 		// If wrapping is used, use the shortest between the request and response
@@ -1007,8 +1199,9 @@ func (c *Core) handleRequest(ctx context.Context, req *logical.Request) (retResp
 	}
 
 	// If there is a secret, we must register it with the expiration manager.
-	// We exclude renewal of a lease, since it does not need to be re-registered
-	if resp != nil && resp.Secret != nil && !strings.HasPrefix(req.Path, "sys/renew") &&
+	// We exclude renewal of a lease, since it does not need to be re-registered,
+	// and dry runs, which do not persist anything
+	if resp != nil && resp.Secret != nil && !req.DryRun && !strings.HasPrefix(req.Path, "sys/renew") &&
 		!strings.HasPrefix(req.Path, "sys/leases/renew") {
 		// KV mounts should return the TTL but not register
 		// for a lease as this provides a massive slowdown
@@ -1058,6 +1251,13 @@ func (c *Core) handleRequest(ctx context.Context, req *logical.Request) (retResp
 				return nil, nil, ErrInternalError
 			}
 
+			policyTTL, policyMaxTTL, err := c.ttlPolicyLimits(ctx, ns, req.Path, req.EntityID)
+			if err != nil {
+				c.logger.Error("failed to evaluate TTL policies", "request_path", req.Path, "error", err)
+				return nil, nil, ErrInternalError
+			}
+			resp.Secret.TTL, resp.Secret.MaxTTL = applyTTLPolicy(sysView, resp.Secret.TTL, resp.Secret.MaxTTL, policyTTL, policyMaxTTL)
+
 			ttl, warnings, err := framework.CalculateTTL(sysView, 0, resp.Secret.TTL, 0, resp.Secret.MaxTTL, 0, time.Time{})
 			if err != nil {
 				return nil, nil, err
@@ -1423,6 +1623,33 @@ func (c *Core) handleLoginRequest(ctx context.Context, req *logical.Request) (re
 		// Determine the source of the login
 		source := c.router.MatchingMount(ctx, req.Path)
 
+		// Bind the token to the client certificate of the login request if
+		// the auth method requires it. This is done before login MFA so the
+		// binding is kept in the cached auth.
+		if mEntry != nil && mEntry.Config.TokenBindClientCert {
+			if auth.TokenType == logical.TokenTypeBatch {
+				return logical.ErrorResponse("batch tokens cannot be bound to a client certificate"), nil, logical.ErrInvalidRequest
+			}
+			thumbprint := clientCertThumbprint(req.Connection)
+			if thumbprint == "" {
+				return logical.ErrorResponse("a client certificate is required to log in to this auth method"), nil, logical.ErrPermissionDenied
+			}
+			auth.BoundCertThumbprint = thumbprint
+		}
+
+		// Login banners are enforced before login MFA, so that they also
+		// apply to two-phase MFA logins
+		if mEntry != nil {
+			missing, err := c.checkLoginBanners(ctx, mEntry.Type, auth.EntityID, req.AcknowledgedBanners)
+			if err != nil {
+				c.logger.Error("failed to check the acknowledgment of the login banners", "error", err)
+				return nil, nil, ErrInternalError
+			}
+			if len(missing) > 0 {
+				return loginBannerError(missing), nil, logical.ErrPermissionDenied
+			}
+		}
+
 		// Login MFA
 		entity, _, err := c.fetchEntityAndDerivedPolicies(ctx, ns, auth.EntityID, true)
 		if err != nil {
@@ -1528,6 +1755,26 @@ func (c *Core) handleLoginRequest(ctx context.Context, req *logical.Request) (re
 			role = c.DetermineRoleFromLoginRequest(ctx, req.MountPoint, req.Data)
 		}
 
+		// Network policies are enforced on login, before the token is issued,
+		// including through the policies derived from the entity
+		tokenPolicies := policyutil.SanitizePolicies(auth.Policies, !auth.NoDefaultPolicy)
+		_, identityPolicies, err := c.fetchEntityAndDerivedPolicies(ctx, ns, auth.EntityID, false)
+		if err != nil {
+			return nil, nil, ErrInternalError
+		}
+		for _, nsPolicies := range identityPolicies {
+			tokenPolicies = append(tokenPolicies, nsPolicies...)
+		}
+		denied, err := c.checkNetworkPolicies(ctx, req.Connection, tokenPolicies, req.Path, time.Now())
+		if err != nil {
+			c.logger.Error("failed to evaluate network policies", "error", err)
+			return nil, nil, ErrInternalError
+		}
+		if denied != "" {
+			c.logger.Warn("login denied by network policy", "network_policy", denied, "path", req.Path)
+			return nil, nil, logical.ErrPermissionDenied
+		}
+
 		_, respTokenCreate, errCreateToken := c.LoginCreateToken(ctx, ns, req.Path, source, role, resp)
 		if errCreateToken != nil {
 			return respTokenCreate, nil, errCreateToken
@@ -1594,6 +1841,15 @@ func (c *Core) LoginCreateToken(ctx context.Context, ns *namespace.Namespace, re
 		return false, nil, ErrInternalError
 	}
 
+	// The maximum TTL of TTL policies is set as the explicit maximum TTL of
+	// the token, so that it also caps its renewals.
+	policyTTL, policyMaxTTL, err := c.ttlPolicyLimits(ctx, ns, reqPath, auth.EntityID)
+	if err != nil {
+		c.logger.Error("failed to evaluate TTL policies", "request_path", reqPath, "error", err)
+		return false, nil, ErrInternalError
+	}
+	auth.TTL, auth.ExplicitMaxTTL = applyTTLPolicy(sysView, auth.TTL, auth.ExplicitMaxTTL, policyTTL, policyMaxTTL)
+
 	tokenTTL, warnings, err := framework.CalculateTTL(sysView, 0, auth.TTL, auth.Period, auth.MaxTTL, auth.ExplicitMaxTTL, time.Time{})
 	if err != nil {
 		return false, nil, err
@@ -1937,19 +2193,20 @@ func (c *Core) RegisterAuth(ctx context.Context, tokenTTL time.Duration, path st
 		return err
 	}
 	te := logical.TokenEntry{
-		Path:           path,
-		Meta:           auth.Metadata,
-		DisplayName:    auth.DisplayName,
-		CreationTime:   time.Now().Unix(),
-		TTL:            tokenTTL,
-		NumUses:        auth.NumUses,
-		EntityID:       auth.EntityID,
-		BoundCIDRs:     auth.BoundCIDRs,
-		Policies:       auth.TokenPolicies,
-		NamespaceID:    ns.ID,
-		ExplicitMaxTTL: auth.ExplicitMaxTTL,
-		Period:         auth.Period,
-		Type:           auth.TokenType,
+		Path:                path,
+		Meta:                auth.Metadata,
+		DisplayName:         auth.DisplayName,
+		CreationTime:        time.Now().Unix(),
+		TTL:                 tokenTTL,
+		NumUses:             auth.NumUses,
+		EntityID:            auth.EntityID,
+		BoundCIDRs:          auth.BoundCIDRs,
+		Policies:            auth.TokenPolicies,
+		BoundCertThumbprint: auth.BoundCertThumbprint,
+		NamespaceID:         ns.ID,
+		ExplicitMaxTTL:      auth.ExplicitMaxTTL,
+		Period:              auth.Period,
+		Type:                auth.TokenType,
 	}
 
 	if te.TTL == 0 && (len(te.Policies) != 1 || te.Policies[0] != "root") {
@@ -4,6 +4,9 @@
 package vault
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -473,3 +476,238 @@ func TestRequestHandling_SecretLeaseMetric(t *testing.T) {
 		},
 	)
 }
+
+func TestRequestHandling_ListPagination(t *testing.T) {
+	core, _, root := TestCoreUnsealedWithConfig(t, &CoreConfig{
+		ListDefaultLimit: 2,
+	})
+	ctx := namespace.RootContext(nil)
+
+	for _, key := range []string{"e", "d", "c", "b", "a"} {
+		req := logical.TestRequest(t, logical.UpdateOperation, "secret/"+key)
+		req.Data["value"] = key
+		req.ClientToken = root
+		if _, err := core.HandleRequest(ctx, req); err != nil {
+			t.Fatalf("err: %v", err)
+		}
+	}
+
+	list := func(data map[string]interface{}) *logical.Response {
+		t.Helper()
+		req := logical.TestRequest(t, logical.ListOperation, "secret/")
+		req.Data = data
+		req.ClientToken = root
+		resp, err := core.HandleRequest(ctx, req)
+		if err != nil {
+			t.Fatalf("err: %v", err)
+		}
+		return resp
+	}
+
+	// The default limit applies
+	resp := list(nil)
+	if diff := deep.Equal(resp.Data["keys"], []string{"a", "b"}); diff != nil {
+		t.Fatal(diff)
+	}
+	if len(resp.Warnings) != 1 || !strings.Contains(resp.Warnings[0], `after="b"`) {
+		t.Fatalf("expected a continuation warning, got: %v", resp.Warnings)
+	}
+
+	// Continue after the last key
+	resp = list(map[string]interface{}{"after": "b"})
+	if diff := deep.Equal(resp.Data["keys"], []string{"c", "d"}); diff != nil {
+		t.Fatal(diff)
+	}
+
+	// The last page has no warning
+	resp = list(map[string]interface{}{"after": "d"})
+	if diff := deep.Equal(resp.Data["keys"], []string{"e"}); diff != nil {
+		t.Fatal(diff)
+	}
+	if len(resp.Warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", resp.Warnings)
+	}
+
+	// The limit of the request overrides the default one
+	resp = list(map[string]interface{}{"limit": "10"})
+	if diff := deep.Equal(resp.Data["keys"], []string{"a", "b", "c", "d", "e"}); diff != nil {
+		t.Fatal(diff)
+	}
+	if len(resp.Warnings) != 0 {
+		t.Fatalf("unexpected warnings: %v", resp.Warnings)
+	}
+
+	// Invalid limits are refused
+	req := logical.TestRequest(t, logical.ListOperation, "secret/")
+	req.Data["limit"] = "many"
+	req.ClientToken = root
+	if _, err := core.HandleRequest(ctx, req); err == nil || !strings.Contains(err.Error(), "invalid limit") {
+		t.Fatalf("expected an invalid limit error, got: %v", err)
+	}
+	for _, limit := range []string{"0", "-1"} {
+		req := logical.TestRequest(t, logical.ListOperation, "secret/")
+		req.Data["limit"] = limit
+		req.ClientToken = root
+		if _, err := core.HandleRequest(ctx, req); err == nil || !strings.Contains(err.Error(), "limit must be positive") {
+			t.Fatalf("expected a non-positive limit error for %q, got: %v", limit, err)
+		}
+	}
+}
+
+func TestRequestHandling_BindClientCert(t *testing.T) {
+	core, _, root := TestCoreUnsealed(t)
+
+	if err := core.loadMounts(namespace.RootContext(nil)); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	core.credentialBackends["userpass"] = credUserpass.Factory
+
+	connWithCert := func(raw string) *logical.Connection {
+		return &logical.Connection{
+			ConnState: &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{{Raw: []byte(raw)}},
+			},
+		}
+	}
+
+	// Setup mount binding tokens to client certificates
+	req := &logical.Request{
+		Path:        "sys/auth/userpass",
+		ClientToken: root,
+		Operation:   logical.UpdateOperation,
+		Data: map[string]interface{}{
+			"type": "userpass",
+			"config": map[string]interface{}{
+				"token_bind_client_cert": true,
+			},
+		},
+		Connection: &logical.Connection{},
+	}
+	resp, err := core.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if resp != nil {
+		t.Fatalf("bad: %#v", resp)
+	}
+
+	// Create a policy allowing to create child tokens, and user
+	req.Path = "sys/policy/create-token"
+	req.Data = map[string]interface{}{
+		"policy": `path "auth/token/create" { capabilities = ["update"] }`,
+	}
+	resp, err = core.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	req.Path = "auth/userpass/users/test"
+	req.Data = map[string]interface{}{
+		"password": "foo",
+		"policies": "default,create-token",
+	}
+	resp, err = core.HandleRequest(namespace.RootContext(nil), req)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if resp != nil {
+		t.Fatalf("bad: %#v", resp)
+	}
+
+	login := func(conn *logical.Connection) (*logical.Response, error) {
+		return core.HandleRequest(namespace.RootContext(nil), &logical.Request{
+			Path:      "auth/userpass/login/test",
+			Operation: logical.UpdateOperation,
+			Data: map[string]interface{}{
+				"password": "foo",
+			},
+			Connection: conn,
+		})
+	}
+	lookupSelf := func(token string, conn *logical.Connection) (*logical.Response, error) {
+		return core.HandleRequest(namespace.RootContext(nil), &logical.Request{
+			Path:        "auth/token/lookup-self",
+			Operation:   logical.ReadOperation,
+			ClientToken: token,
+			Connection:  conn,
+		})
+	}
+
+	// Logging in requires a client certificate
+	if _, err := login(&logical.Connection{}); err == nil {
+		t.Fatal("expected login without a client certificate to fail")
+	}
+
+	resp, err = login(connWithCert("cert-a"))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if resp == nil || resp.Auth == nil {
+		t.Fatalf("bad: %v", resp)
+	}
+	token := resp.Auth.ClientToken
+
+	resp, err = lookupSelf(token, connWithCert("cert-a"))
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if resp.Data["bound_cert_thumbprint"] != clientCertThumbprint(connWithCert("cert-a")) {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+
+	// The token cannot be used with another certificate or without one
+	for _, conn := range []*logical.Connection{connWithCert("cert-b"), {}} {
+		_, err = lookupSelf(token, conn)
+		if !errors.Is(err, logical.ErrPermissionDenied) {
+			t.Fatalf("expected permission denied, got: %v", err)
+		}
+	}
+
+	// Child tokens are bound to the certificate of their parent
+	resp, err = core.HandleRequest(namespace.RootContext(nil), &logical.Request{
+		Path:        "auth/token/create",
+		Operation:   logical.UpdateOperation,
+		ClientToken: token,
+		Connection:  connWithCert("cert-a"),
+	})
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if _, err = lookupSelf(resp.Auth.ClientToken, connWithCert("cert-b")); !errors.Is(err, logical.ErrPermissionDenied) {
+		t.Fatalf("expected permission denied, got: %v", err)
+	}
+
+	// Tokens can be bound to the certificate of the creation request
+	createReq := &logical.Request{
+		Path:        "auth/token/create",
+		Operation:   logical.UpdateOperation,
+		ClientToken: root,
+		Data: map[string]interface{}{
+			"policies":         "default",
+			"bind_client_cert": true,
+		},
+		Connection: &logical.Connection{},
+	}
+	if _, err = core.HandleRequest(namespace.RootContext(nil), createReq); err == nil {
+		t.Fatal("expected binding a token without a client certificate to fail")
+	}
+
+	createReq.Connection = connWithCert("cert-b")
+	resp, err = core.HandleRequest(namespace.RootContext(nil), createReq)
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if _, err = lookupSelf(resp.Auth.ClientToken, connWithCert("cert-b")); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if _, err = lookupSelf(resp.Auth.ClientToken, connWithCert("cert-a")); !errors.Is(err, logical.ErrPermissionDenied) {
+		t.Fatalf("expected permission denied, got: %v", err)
+	}
+
+	// Batch tokens cannot be bound
+	createReq.Data["type"] = "batch"
+	if _, err = core.HandleRequest(namespace.RootContext(nil), createReq); err == nil {
+		t.Fatal("expected binding a batch token to fail")
+	}
+}
@@ -0,0 +1,200 @@
+package vault
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/helper/certutil"
+	"github.com/openbao/openbao/sdk/v2/helper/keysutil"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"golang.org/x/crypto/ssh"
+)
+
+// RestrictedCrypto returns whether the server runs in restricted crypto mode,
+// in which backends refuse to create or use keys of algorithms which are not
+// approved.
+func (c *Core) RestrictedCrypto() bool {
+	return c.restrictedCrypto
+}
+
+// restrictedCryptoKey is a key of a secrets engine which is not approved in
+// restricted crypto mode.
+type restrictedCryptoKey struct {
+	Mount     string
+	MountType string
+	Kind      string
+	Name      string
+	KeyType   string
+}
+
+// restrictedCryptoReport walks the transit, PKI and SSH mounts of the
+// namespace and its children, and returns their keys which are not approved in
+// restricted crypto mode. Mounts which could not be walked are reported as
+// warnings.
+func (c *Core) restrictedCryptoReport(ctx context.Context) ([]*restrictedCryptoKey, []string, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var entries []*MountEntry
+	c.mountsLock.RLock()
+	if c.mounts != nil {
+		for _, entry := range c.mounts.Entries {
+			if entry.Tainted || (entry.namespace != ns && !entry.namespace.HasParent(ns)) {
+				continue
+			}
+			switch entry.Type {
+			case "transit", "pki", "ssh":
+				entries = append(entries, entry)
+			}
+		}
+	}
+	c.mountsLock.RUnlock()
+
+	// Requests are routed with full paths, as the mounts may be in child
+	// namespaces.
+	ctx = namespace.RootContext(ctx)
+
+	var keys []*restrictedCryptoKey
+	var warnings []string
+	for _, entry := range entries {
+		mount := strings.TrimPrefix(entry.namespace.Path+entry.Path, ns.Path)
+
+		var found []*restrictedCryptoKey
+		switch entry.Type {
+		case "transit":
+			found, err = c.restrictedCryptoTransitKeys(ctx, entry)
+		case "pki":
+			found, err = c.restrictedCryptoPKIKeys(ctx, entry)
+		case "ssh":
+			found, err = c.restrictedCryptoSSHKeys(ctx, entry)
+		}
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("failed to check the keys of mount %q: %v", mount, err))
+			continue
+		}
+
+		for _, key := range found {
+			key.Mount = mount
+			key.MountType = entry.Type
+		}
+		keys = append(keys, found...)
+	}
+
+	sort.SliceStable(keys, func(i, j int) bool {
+		return keys[i].Mount < keys[j].Mount
+	})
+	return keys, warnings, nil
+}
+
+func (c *Core) restrictedCryptoTransitKeys(ctx context.Context, entry *MountEntry) ([]*restrictedCryptoKey, error) {
+	prefix := entry.namespace.Path + entry.Path
+	names, err := c.restrictedCryptoList(ctx, prefix+"keys/")
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []*restrictedCryptoKey
+	for _, name := range names {
+		data, err := c.restrictedCryptoRead(ctx, prefix+"keys/"+name)
+		if err != nil {
+			return nil, err
+		}
+		keyType, _ := data["type"].(string)
+		for _, kt := range []keysutil.KeyType{keysutil.KeyType_ChaCha20_Poly1305, keysutil.KeyType_XChaCha20_Poly1305, keysutil.KeyType_ED25519} {
+			if keyType == kt.String() {
+				keys = append(keys, &restrictedCryptoKey{Kind: "key", Name: name, KeyType: keyType})
+			}
+		}
+	}
+	return keys, nil
+}
+
+func (c *Core) restrictedCryptoPKIKeys(ctx context.Context, entry *MountEntry) ([]*restrictedCryptoKey, error) {
+	prefix := entry.namespace.Path + entry.Path
+
+	var keys []*restrictedCryptoKey
+	for _, kind := range []struct {
+		name, list, read string
+	}{
+		{"key", "keys/", "key/"},
+		{"role", "roles/", "roles/"},
+	} {
+		names, err := c.restrictedCryptoList(ctx, prefix+kind.list)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			data, err := c.restrictedCryptoRead(ctx, prefix+kind.read+name)
+			if err != nil {
+				return nil, err
+			}
+			keyType := fmt.Sprint(data["key_type"])
+			if !certutil.PrivateKeyType(keyType).RestrictedCryptoApproved() {
+				keys = append(keys, &restrictedCryptoKey{Kind: kind.name, Name: name, KeyType: keyType})
+			}
+		}
+	}
+	return keys, nil
+}
+
+func (c *Core) restrictedCryptoSSHKeys(ctx context.Context, entry *MountEntry) ([]*restrictedCryptoKey, error) {
+	data, err := c.restrictedCryptoRead(ctx, entry.namespace.Path+entry.Path+"config/ca")
+	if err != nil {
+		return nil, err
+	}
+	raw, _ := data["public_key"].(string)
+	if raw == "" {
+		return nil, nil
+	}
+
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA public key: %w", err)
+	}
+	if publicKey.Type() != ssh.KeyAlgoED25519 {
+		return nil, nil
+	}
+	return []*restrictedCryptoKey{{Kind: "ca", Name: "ca", KeyType: publicKey.Type()}}, nil
+}
+
+func (c *Core) restrictedCryptoList(ctx context.Context, path string) ([]string, error) {
+	resp, err := c.router.Route(ctx, &logical.Request{
+		Operation: logical.ListOperation,
+		Path:      path,
+	})
+	if resp != nil && resp.IsError() {
+		return nil, resp.Error()
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, nil
+	}
+	keys, _ := resp.Data["keys"].([]string)
+	return keys, nil
+}
+
+func (c *Core) restrictedCryptoRead(ctx context.Context, path string) (map[string]interface{}, error) {
+	resp, err := c.router.Route(ctx, &logical.Request{
+		Operation: logical.ReadOperation,
+		Path:      path,
+	})
+	// Error responses, such as for keys deleted since they were listed or
+	// for SSH mounts without CA, have no keys to report.
+	if resp != nil && resp.IsError() {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, nil
+	}
+	return resp.Data, nil
+}
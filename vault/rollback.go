@@ -177,10 +177,12 @@ func (m *RollbackManager) triggerRollbacks() {
 			path = credentialRoutePrefix + path
 		}
 
-		// When the mount is filtered, the backend will be nil
+		// When the mount is filtered, the backend will be nil. The backend of
+		// a lazy mount is loaded by its rollback, so that its periodic
+		// function keeps running.
 		ctx := namespace.ContextWithNamespace(m.quitContext, e.namespace)
 		backend := m.router.MatchingBackend(ctx, path)
-		if backend == nil {
+		if backend == nil && !m.router.IsLazyMount(ctx, path) {
 			continue
 		}
 		fullPath := e.namespace.Path + path
@@ -0,0 +1,343 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	"github.com/hashicorp/go-cleanhttp"
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const (
+	// rootRotationSubPath is the sub-path of the system view used to store
+	// the registered root credential rotations.
+	rootRotationSubPath = "root-rotation/"
+
+	// rootRotationCheckInterval is the interval at which registered root
+	// credential rotations are checked and rotated if due.
+	rootRotationCheckInterval = time.Minute
+
+	// rootRotationRetryInterval is the minimum time between a failed
+	// scheduled rotation and the next attempt.
+	rootRotationRetryInterval = 10 * time.Minute
+
+	// rootRotationNotifyTimeout is the timeout of the requests notifying the
+	// outcome of a rotation.
+	rootRotationNotifyTimeout = 10 * time.Second
+
+	rootRotationStatusSuccess = "success"
+	rootRotationStatusFailure = "failure"
+)
+
+var (
+	rootRotationSuccessMetric = []string{"core", "root_rotation", "success"}
+	rootRotationFailureMetric = []string{"core", "root_rotation", "failure"}
+)
+
+// RootRotation is the registration of the root credential of a secrets
+// engine or auth method with the root rotation scheduler. The credential is
+// rotated by writing to Path, the rotate endpoint exposed by the engine, such
+// as database/rotate-root/my-db.
+type RootRotation struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+
+	// Interval is the minimum time between two rotations. If zero, the
+	// credential is only rotated on demand.
+	Interval time.Duration `json:"interval"`
+
+	// WindowStart and WindowDuration restrict scheduled rotations to a
+	// daily window, starting at WindowStart minutes after midnight UTC. If
+	// WindowDuration is zero, rotations may happen at any time.
+	WindowStart    int           `json:"window_start"`
+	WindowDuration time.Duration `json:"window_duration"`
+
+	// NotifyURLs receive a POST request with the outcome of each rotation.
+	NotifyURLs []string `json:"notify_urls,omitempty"`
+
+	CreatedTime         time.Time `json:"created_time"`
+	LastAttempt         time.Time `json:"last_attempt"`
+	LastRotation        time.Time `json:"last_rotation"`
+	LastError           string    `json:"last_error,omitempty"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	Rotations           uint64    `json:"rotations"`
+	Failures            uint64    `json:"failures"`
+}
+
+// inWindow returns whether t is within the rotation window.
+func (r *RootRotation) inWindow(t time.Time) bool {
+	return r.nextWindow(t).Equal(t)
+}
+
+// nextWindow returns the earliest time, from t onwards, that is within the
+// rotation window.
+func (r *RootRotation) nextWindow(t time.Time) time.Time {
+	if r.WindowDuration <= 0 {
+		return t
+	}
+
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Add(time.Duration(r.WindowStart) * time.Minute)
+	switch {
+	case t.Before(start):
+		// The window of the previous day may still be open.
+		if t.Before(start.AddDate(0, 0, -1).Add(r.WindowDuration)) {
+			return t
+		}
+		return start
+	case t.Before(start.Add(r.WindowDuration)):
+		return t
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// nextRotation returns the time of the next scheduled rotation, or the zero
+// time if the credential is only rotated on demand.
+func (r *RootRotation) nextRotation() time.Time {
+	if r.Interval <= 0 {
+		return time.Time{}
+	}
+
+	base := r.LastRotation
+	if base.IsZero() {
+		base = r.CreatedTime
+	}
+	due := base.Add(r.Interval)
+	if r.LastError != "" {
+		if retry := r.LastAttempt.Add(rootRotationRetryInterval); retry.After(due) {
+			due = retry
+		}
+	}
+	return r.nextWindow(due)
+}
+
+func (r *RootRotation) toMap() map[string]interface{} {
+	window := ""
+	if r.WindowDuration > 0 {
+		window = fmt.Sprintf("%02d:%02d", r.WindowStart/60, r.WindowStart%60)
+	}
+
+	m := map[string]interface{}{
+		"name":                 r.Name,
+		"path":                 r.Path,
+		"interval":             int64(r.Interval.Seconds()),
+		"window_start":         window,
+		"window_duration":      int64(r.WindowDuration.Seconds()),
+		"notify_urls":          r.NotifyURLs,
+		"created_time":         r.CreatedTime,
+		"last_attempt":         r.LastAttempt,
+		"last_rotation":        r.LastRotation,
+		"last_error":           r.LastError,
+		"consecutive_failures": r.ConsecutiveFailures,
+		"rotations":            r.Rotations,
+		"failures":             r.Failures,
+	}
+	if next := r.nextRotation(); !next.IsZero() {
+		m["next_rotation"] = next
+	}
+	return m
+}
+
+// parseRootRotationWindowStart parses a window start of the form HH:MM into
+// a number of minutes after midnight.
+func parseRootRotationWindowStart(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid window_start %q, expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func (c *Core) rootRotationView() *BarrierView {
+	return c.systemBarrierView.SubView(rootRotationSubPath)
+}
+
+func (c *Core) rootRotation(ctx context.Context, name string) (*RootRotation, error) {
+	entry, err := c.rootRotationView().Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		return nil, nil
+	}
+
+	rotation := new(RootRotation)
+	if err := entry.DecodeJSON(rotation); err != nil {
+		return nil, fmt.Errorf("failed to decode root rotation %q: %w", name, err)
+	}
+	return rotation, nil
+}
+
+func (c *Core) putRootRotation(ctx context.Context, rotation *RootRotation) error {
+	entry, err := logical.StorageEntryJSON(rotation.Name, rotation)
+	if err != nil {
+		return err
+	}
+	return c.rootRotationView().Put(ctx, entry)
+}
+
+// rotateRoot rotates the root credential of the given registration by
+// writing to its rotate endpoint, and records the outcome. It must be called
+// with rootRotationLock held.
+func (c *Core) rotateRoot(ctx context.Context, rotation *RootRotation) error {
+	now := time.Now().UTC()
+	req := &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      rotation.Path,
+		Data:      map[string]interface{}{},
+	}
+	resp, err := c.router.Route(namespace.ContextWithNamespace(ctx, namespace.RootNamespace), req)
+	if err == nil && resp != nil && resp.IsError() {
+		err = resp.Error()
+	}
+
+	rotation.LastAttempt = now
+	status := rootRotationStatusSuccess
+	if err != nil {
+		status = rootRotationStatusFailure
+		rotation.LastError = err.Error()
+		rotation.ConsecutiveFailures++
+		rotation.Failures++
+		metrics.IncrCounterWithLabels(rootRotationFailureMetric, 1, []metrics.Label{{Name: "name", Value: rotation.Name}})
+		c.logger.Error("root credential rotation failed", "name", rotation.Name, "path", rotation.Path, "error", err)
+	} else {
+		rotation.LastRotation = now
+		rotation.LastError = ""
+		rotation.ConsecutiveFailures = 0
+		rotation.Rotations++
+		metrics.IncrCounterWithLabels(rootRotationSuccessMetric, 1, []metrics.Label{{Name: "name", Value: rotation.Name}})
+		c.logger.Info("rotated root credential", "name", rotation.Name, "path", rotation.Path)
+	}
+
+	if perr := c.putRootRotation(ctx, rotation); perr != nil {
+		return errors.Join(err, fmt.Errorf("failed to persist root rotation status: %w", perr))
+	}
+
+	if len(rotation.NotifyURLs) > 0 {
+		go c.notifyRootRotation(rotation, status, now)
+	}
+
+	return err
+}
+
+// notifyRootRotation sends the outcome of a rotation to the notification
+// URLs of its registration.
+func (c *Core) notifyRootRotation(rotation *RootRotation, status string, t time.Time) {
+	body, err := json.Marshal(map[string]interface{}{
+		"name":                 rotation.Name,
+		"path":                 rotation.Path,
+		"status":               status,
+		"error":                rotation.LastError,
+		"time":                 t,
+		"consecutive_failures": rotation.ConsecutiveFailures,
+	})
+	if err != nil {
+		c.logger.Error("failed to encode root rotation notification", "name", rotation.Name, "error", err)
+		return
+	}
+
+	client := cleanhttp.DefaultClient()
+	client.Timeout = rootRotationNotifyTimeout
+	for _, url := range rotation.NotifyURLs {
+		resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			c.logger.Warn("failed to send root rotation notification", "name", rotation.Name, "url", url, "error", err)
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= http.StatusBadRequest {
+			c.logger.Warn("root rotation notification was rejected", "name", rotation.Name, "url", url, "status", resp.StatusCode)
+		}
+	}
+}
+
+// checkRootRotations rotates the registered root credentials that are due at
+// the given time.
+func (c *Core) checkRootRotations(ctx context.Context, now time.Time) {
+	c.rootRotationLock.Lock()
+	defer c.rootRotationLock.Unlock()
+
+	names, err := c.rootRotationView().List(ctx, "")
+	if err != nil {
+		c.logger.Error("failed to list root rotations", "error", err)
+		return
+	}
+
+	for _, name := range names {
+		if ctx.Err() != nil {
+			return
+		}
+
+		rotation, err := c.rootRotation(ctx, name)
+		if err != nil {
+			c.logger.Error("failed to load root rotation", "name", name, "error", err)
+			continue
+		}
+		if rotation == nil {
+			continue
+		}
+
+		next := rotation.nextRotation()
+		if next.IsZero() || next.After(now) || !rotation.inWindow(now) {
+			continue
+		}
+
+		// Errors are recorded and logged by rotateRoot.
+		_ = c.rotateRoot(ctx, rotation)
+	}
+}
+
+func (c *Core) rootRotationLoop(ctx context.Context) {
+	t := time.NewTicker(rootRotationCheckInterval)
+	for {
+		select {
+		case <-t.C:
+			c.stateLock.RLock()
+			c.checkRootRotations(ctx, time.Now().UTC())
+			c.stateLock.RUnlock()
+		case <-ctx.Done():
+			t.Stop()
+			return
+		}
+	}
+}
+
+// startRootRotation starts the scheduler of root credential rotations. It is
+// only run on the active node.
+func (c *Core) startRootRotation() {
+	if c.rootRotationCancel != nil {
+		return
+	}
+
+	var ctx context.Context
+	ctx, c.rootRotationCancel = context.WithCancel(c.activeContext)
+	go c.rootRotationLoop(ctx)
+}
+
+func (c *Core) stopRootRotation() {
+	if c.rootRotationCancel != nil {
+		c.rootRotationCancel()
+		c.rootRotationCancel = nil
+	}
+}
+
+// validateRootRotationPath checks that path refers to a mounted secrets
+// engine or auth method.
+func (c *Core) validateRootRotationPath(ctx context.Context, path string) error {
+	if strings.HasPrefix(path, "sys/") || strings.HasPrefix(path, "identity/") || strings.HasPrefix(path, "cubbyhole/") {
+		return fmt.Errorf("path %q is not the rotate endpoint of a secrets engine or auth method", path)
+	}
+	if c.router.MatchingMount(namespace.ContextWithNamespace(ctx, namespace.RootNamespace), path) == "" {
+		return fmt.Errorf("no mount found for path %q", path)
+	}
+	return nil
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/armon/go-metrics"
 	"github.com/armon/go-radix"
 	"github.com/hashicorp/go-hclog"
+	iradix "github.com/hashicorp/go-immutable-radix"
 	"github.com/hashicorp/go-secure-stdlib/strutil"
 	"github.com/openbao/openbao/helper/namespace"
 	"github.com/openbao/openbao/sdk/v2/helper/consts"
@@ -31,41 +32,121 @@ var wcAdjacentNonSlashRegEx = regexp.MustCompile(`\+[^/]|[^/]\+`).MatchString
 
 // Router is used to do prefix based routing of a request to a logical backend
 type Router struct {
-	l                  sync.RWMutex
-	root               *radix.Tree
-	mountUUIDCache     *radix.Tree
-	mountAccessorCache *radix.Tree
+	// l serializes the changes to the routing table. Requests resolve their
+	// paths against the current table without taking it.
+	l     sync.Mutex
+	table atomic.Pointer[routingTable]
+
 	tokenStoreSaltFunc func(context.Context) (*salt.Salt, error)
-	// storagePrefix maps the prefix used for storage (ala the BarrierView)
-	// to the backend. This is used to map a key back into the backend that owns it.
-	// For example, logical/uuid1/foobar -> secrets/ (kv backend) + foobar
-	storagePrefix *radix.Tree
-	logger        hclog.Logger
+	// lazyBackendFunc creates and initializes the backend of a mount whose
+	// loading was deferred at unseal.
+	lazyBackendFunc func(context.Context, *MountEntry) error
+	logger          hclog.Logger
 }
 
 // NewRouter returns a new router
 func NewRouter() *Router {
 	r := &Router{
-		root:               radix.New(),
-		storagePrefix:      radix.New(),
-		mountUUIDCache:     radix.New(),
-		mountAccessorCache: radix.New(),
 		// this will get replaced in production with a real logger but it's useful to have a default in place for tests
 		logger: hclog.NewNullLogger(),
 	}
+	r.table.Store(newRoutingTable())
 	return r
 }
 
+// routingTable is an immutable snapshot of the routes of the router. Changes
+// to the routes build a new table replacing the previous one, so that the
+// many concurrent path resolutions of requests never contend on a lock.
+type routingTable struct {
+	// root maps the mount prefixes, including the namespace path, to their
+	// routeMount.
+	root               *iradix.Tree
+	mountUUIDCache     *iradix.Tree
+	mountAccessorCache *iradix.Tree
+	// storagePrefix maps the prefix used for storage (ala the BarrierView)
+	// to the backend. This is used to map a key back into the backend that owns it.
+	// For example, logical/uuid1/foobar -> secrets/ (kv backend) + foobar
+	storagePrefix *iradix.Tree
+}
+
+func newRoutingTable() *routingTable {
+	return &routingTable{
+		root:               iradix.New(),
+		storagePrefix:      iradix.New(),
+		mountUUIDCache:     iradix.New(),
+		mountAccessorCache: iradix.New(),
+	}
+}
+
+// routeMount is the value of the root tree of the routing table. It keeps
+// the mount prefix so that resolving a path does not allocate it.
+type routeMount struct {
+	prefix string
+	entry  *routeEntry
+}
+
+func (m *routeMount) Deserialize() map[string]interface{} {
+	return m.entry.Deserialize()
+}
+
+// longestPrefix returns the route entry of the mount with the longest prefix
+// of the path, and this prefix.
+func (t *routingTable) longestPrefix(path string) (string, *routeEntry, bool) {
+	_, raw, ok := t.root.Root().LongestPrefix([]byte(path))
+	if !ok {
+		return "", nil, false
+	}
+	m := raw.(*routeMount)
+	return m.prefix, m.entry, true
+}
+
+// get returns the route entry of the mount with the given prefix.
+func (t *routingTable) get(prefix string) (*routeEntry, bool) {
+	raw, ok := t.root.Get([]byte(prefix))
+	if !ok {
+		return nil, false
+	}
+	return raw.(*routeMount).entry, true
+}
+
+// matchingRouteEntry returns the route entry of the mount owning the API path
+// or the storage path.
+func (t *routingTable) matchingRouteEntry(path string, apiPath bool) (*routeEntry, bool) {
+	if apiPath {
+		_, re, ok := t.longestPrefix(path)
+		return re, ok
+	}
+	_, raw, ok := t.storagePrefix.Root().LongestPrefix([]byte(path))
+	if !ok {
+		return nil, false
+	}
+	return raw.(*routeEntry), true
+}
+
+// routeEntry returns the route entry of the mount with the given prefix,
+// including the namespace path.
+func (r *Router) routeEntry(prefix string) (*routeEntry, bool) {
+	return r.table.Load().get(prefix)
+}
+
 // routeEntry is used to represent a mount point in the router
 type routeEntry struct {
-	tainted       bool
+	tainted atomic.Bool
+	// lazy is set while the backend of the mount has not been loaded yet, it
+	// is loaded by the first request routed to the mount. lazyLock ensures
+	// the backend is loaded once.
+	lazy          atomic.Bool
+	lazyLock      sync.Mutex
 	backend       logical.Backend
 	mountEntry    *MountEntry
 	storageView   logical.Storage
 	storagePrefix string
 	rootPaths     atomic.Value
 	loginPaths    atomic.Value
-	l             sync.RWMutex
+	// inFlight counts the requests being handled by the backend, bounded by
+	// the maximum number of concurrent requests of the mount.
+	inFlight atomic.Int64
+	l        sync.RWMutex
 }
 
 type wildcardPath struct {
@@ -91,33 +172,30 @@ type ValidateMountResponse struct {
 func (r *Router) reset() {
 	r.l.Lock()
 	defer r.l.Unlock()
-	r.root = radix.New()
-	r.storagePrefix = radix.New()
-	r.mountUUIDCache = radix.New()
-	r.mountAccessorCache = radix.New()
+	r.table.Store(newRoutingTable())
 }
 
 func (r *Router) GetRecords(tag string) ([]map[string]interface{}, error) {
-	r.l.RLock()
-	defer r.l.RUnlock()
+	table := r.table.Load()
 	var data []map[string]interface{}
-	var tree *radix.Tree
+	var tree *iradix.Tree
 	switch tag {
 	case "root":
-		tree = r.root
+		tree = table.root
 	case "uuid":
-		tree = r.mountUUIDCache
+		tree = table.mountUUIDCache
 	case "accessor":
-		tree = r.mountAccessorCache
+		tree = table.mountAccessorCache
 	case "storage":
-		tree = r.storagePrefix
+		tree = table.storagePrefix
 	default:
 		return nil, logical.ErrUnsupportedPath
 	}
-	for _, v := range tree.ToMap() {
+	tree.Root().Walk(func(_ []byte, v interface{}) bool {
 		info := v.(Deserializable).Deserialize()
 		data = append(data, info)
-	}
+		return false
+	})
 	return data, nil
 }
 
@@ -125,7 +203,8 @@ func (entry *routeEntry) Deserialize() map[string]interface{} {
 	entry.l.RLock()
 	defer entry.l.RUnlock()
 	ret := map[string]interface{}{
-		"tainted":        entry.tainted,
+		"tainted":        entry.tainted.Load(),
+		"lazy":           entry.lazy.Load(),
 		"storage_prefix": entry.storagePrefix,
 	}
 	for k, v := range entry.mountEntry.Deserialize() {
@@ -167,6 +246,16 @@ func (re *routeEntry) SaltID(id string) string {
 // Mount is used to expose a logical backend at a given prefix, using a unique salt,
 // and the barrier view for that path.
 func (r *Router) Mount(backend logical.Backend, prefix string, mountEntry *MountEntry, storageView *BarrierView) error {
+	return r.mountInternal(backend, prefix, mountEntry, storageView, false)
+}
+
+// MountLazy is used to expose a logical backend at a given prefix without
+// creating it. The backend is loaded by the first request routed to it.
+func (r *Router) MountLazy(prefix string, mountEntry *MountEntry, storageView *BarrierView) error {
+	return r.mountInternal(nil, prefix, mountEntry, storageView, true)
+}
+
+func (r *Router) mountInternal(backend logical.Backend, prefix string, mountEntry *MountEntry, storageView *BarrierView, lazy bool) error {
 	r.l.Lock()
 	defer r.l.Unlock()
 
@@ -174,7 +263,8 @@ func (r *Router) Mount(backend logical.Backend, prefix string, mountEntry *Mount
 	prefix = mountEntry.Namespace().Path + prefix
 
 	// Check if this is a nested mount
-	if existing, _, ok := r.root.LongestPrefix(prefix); ok && existing != "" {
+	table := r.table.Load()
+	if existing, _, ok := table.longestPrefix(prefix); ok && existing != "" {
 		return fmt.Errorf("cannot mount under existing mount %q", existing)
 	}
 
@@ -189,12 +279,13 @@ func (r *Router) Mount(backend logical.Backend, prefix string, mountEntry *Mount
 
 	// Create a mount entry
 	re := &routeEntry{
-		tainted:       mountEntry.Tainted,
 		backend:       backend,
 		mountEntry:    mountEntry,
 		storagePrefix: storageView.Prefix(),
 		storageView:   storageView,
 	}
+	re.tainted.Store(mountEntry.Tainted)
+	re.lazy.Store(lazy)
 	re.rootPaths.Store(pathsToRadix(paths.Root))
 	loginPathsEntry, err := parseUnauthenticatedPaths(paths.Unauthenticated)
 	if err != nil {
@@ -213,10 +304,12 @@ func (r *Router) Mount(backend logical.Backend, prefix string, mountEntry *Mount
 		return fmt.Errorf("missing mount accessor; mount_path: %q, mount_type: %q", re.mountEntry.Path, re.mountEntry.Type)
 	}
 
-	r.root.Insert(prefix, re)
-	r.storagePrefix.Insert(re.storagePrefix, re)
-	r.mountUUIDCache.Insert(re.mountEntry.UUID, re.mountEntry)
-	r.mountAccessorCache.Insert(re.mountEntry.Accessor, re.mountEntry)
+	updated := *table
+	updated.root, _, _ = table.root.Insert([]byte(prefix), &routeMount{prefix: prefix, entry: re})
+	updated.storagePrefix, _, _ = table.storagePrefix.Insert([]byte(re.storagePrefix), re)
+	updated.mountUUIDCache, _, _ = table.mountUUIDCache.Insert([]byte(re.mountEntry.UUID), re.mountEntry)
+	updated.mountAccessorCache, _, _ = table.mountAccessorCache.Insert([]byte(re.mountEntry.Accessor), re.mountEntry)
+	r.table.Store(&updated)
 
 	return nil
 }
@@ -233,22 +326,24 @@ func (r *Router) Unmount(ctx context.Context, prefix string) error {
 	defer r.l.Unlock()
 
 	// Fast-path out if the backend doesn't exist
-	raw, ok := r.root.Get(prefix)
+	table := r.table.Load()
+	re, ok := table.get(prefix)
 	if !ok {
 		return nil
 	}
 
 	// Call backend's Cleanup routine
-	re := raw.(*routeEntry)
 	if re.backend != nil {
 		re.backend.Cleanup(ctx)
 	}
 
 	// Purge from the radix trees
-	r.root.Delete(prefix)
-	r.storagePrefix.Delete(re.storagePrefix)
-	r.mountUUIDCache.Delete(re.mountEntry.UUID)
-	r.mountAccessorCache.Delete(re.mountEntry.Accessor)
+	updated := *table
+	updated.root, _, _ = table.root.Delete([]byte(prefix))
+	updated.storagePrefix, _, _ = table.storagePrefix.Delete([]byte(re.storagePrefix))
+	updated.mountUUIDCache, _, _ = table.mountUUIDCache.Delete([]byte(re.mountEntry.UUID))
+	updated.mountAccessorCache, _, _ = table.mountAccessorCache.Delete([]byte(re.mountEntry.Accessor))
+	r.table.Store(&updated)
 
 	return nil
 }
@@ -266,14 +361,17 @@ func (r *Router) Remount(ctx context.Context, src, dst string) error {
 	defer r.l.Unlock()
 
 	// Check for existing mount
-	raw, ok := r.root.Get(src)
+	table := r.table.Load()
+	re, ok := table.get(src)
 	if !ok {
 		return fmt.Errorf("no mount at %q", src)
 	}
 
 	// Update the mount point
-	r.root.Delete(src)
-	r.root.Insert(dst, raw)
+	updated := *table
+	updated.root, _, _ = table.root.Delete([]byte(src))
+	updated.root, _, _ = updated.root.Insert([]byte(dst), &routeMount{prefix: dst, entry: re})
+	r.table.Store(&updated)
 	return nil
 }
 
@@ -286,11 +384,8 @@ func (r *Router) Taint(ctx context.Context, path string) error {
 	}
 	path = ns.Path + path
 
-	r.l.Lock()
-	defer r.l.Unlock()
-	_, raw, ok := r.root.LongestPrefix(path)
-	if ok {
-		raw.(*routeEntry).tainted = true
+	if _, re, ok := r.table.Load().longestPrefix(path); ok {
+		re.tainted.Store(true)
 	}
 	return nil
 }
@@ -303,12 +398,64 @@ func (r *Router) Untaint(ctx context.Context, path string) error {
 	}
 	path = ns.Path + path
 
-	r.l.Lock()
-	defer r.l.Unlock()
-	_, raw, ok := r.root.LongestPrefix(path)
-	if ok {
-		raw.(*routeEntry).tainted = false
+	if _, re, ok := r.table.Load().longestPrefix(path); ok {
+		re.tainted.Store(false)
+	}
+	return nil
+}
+
+// LoadLazyMount loads the backend of the mount at the given path if its
+// loading was deferred, and does nothing otherwise.
+func (r *Router) LoadLazyMount(ctx context.Context, path string) error {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	re, ok := r.routeEntry(ns.Path + path)
+	if !ok {
+		return fmt.Errorf("no mount at %q", path)
+	}
+	return r.loadLazyBackend(ctx, re)
+}
+
+// IsLazyMount returns whether the backend of the mount at the given path is
+// yet to be loaded.
+func (r *Router) IsLazyMount(ctx context.Context, path string) bool {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return false
+	}
+
+	_, re, ok := r.table.Load().longestPrefix(ns.Path + path)
+	if !ok {
+		return false
+	}
+	return re.lazy.Load()
+}
+
+// loadLazyBackend loads the backend of the route entry if it is not loaded
+// yet.
+func (r *Router) loadLazyBackend(ctx context.Context, re *routeEntry) error {
+	if !re.lazy.Load() {
+		return nil
+	}
+
+	re.lazyLock.Lock()
+	defer re.lazyLock.Unlock()
+
+	// Check again as a concurrent request may have loaded the backend
+	if !re.lazy.Load() {
+		return nil
+	}
+	if r.lazyBackendFunc == nil {
+		return fmt.Errorf("unable to load lazy mount %q", re.mountEntry.Path)
 	}
+	if err := r.lazyBackendFunc(ctx, re.mountEntry); err != nil {
+		return err
+	}
+
+	re.lazy.Store(false)
 	return nil
 }
 
@@ -317,15 +464,11 @@ func (r *Router) MatchingMountByUUID(mountID string) *MountEntry {
 		return nil
 	}
 
-	r.l.RLock()
-
-	_, raw, ok := r.mountUUIDCache.LongestPrefix(mountID)
+	_, raw, ok := r.table.Load().mountUUIDCache.Root().LongestPrefix([]byte(mountID))
 	if !ok {
-		r.l.RUnlock()
 		return nil
 	}
 
-	r.l.RUnlock()
 	return raw.(*MountEntry)
 }
 
@@ -335,34 +478,27 @@ func (r *Router) MatchingMountByAccessor(mountAccessor string) *MountEntry {
 		return nil
 	}
 
-	r.l.RLock()
-
-	_, raw, ok := r.mountAccessorCache.LongestPrefix(mountAccessor)
+	_, raw, ok := r.table.Load().mountAccessorCache.Root().LongestPrefix([]byte(mountAccessor))
 	if !ok {
-		r.l.RUnlock()
 		return nil
 	}
 
-	r.l.RUnlock()
 	return raw.(*MountEntry)
 }
 
 // MatchingMount returns the mount prefix that would be used for a path
 func (r *Router) MatchingMount(ctx context.Context, path string) string {
-	r.l.RLock()
-	mount := r.matchingMountInternal(ctx, path)
-	r.l.RUnlock()
-	return mount
+	return r.matchingMountInternal(ctx, r.table.Load(), path)
 }
 
-func (r *Router) matchingMountInternal(ctx context.Context, path string) string {
+func (r *Router) matchingMountInternal(ctx context.Context, table *routingTable, path string) string {
 	ns, err := namespace.FromContext(ctx)
 	if err != nil {
 		return ""
 	}
 	path = ns.Path + path
 
-	mount, _, ok := r.root.LongestPrefix(path)
+	mount, _, ok := table.longestPrefix(path)
 	if !ok {
 		return ""
 	}
@@ -370,7 +506,7 @@ func (r *Router) matchingMountInternal(ctx context.Context, path string) string
 }
 
 // matchingPrefixInternal returns a mount prefix that a path may be a part of
-func (r *Router) matchingPrefixInternal(ctx context.Context, path string) string {
+func (r *Router) matchingPrefixInternal(ctx context.Context, table *routingTable, path string) string {
 	ns, err := namespace.FromContext(ctx)
 	if err != nil {
 		return ""
@@ -378,25 +514,24 @@ func (r *Router) matchingPrefixInternal(ctx context.Context, path string) string
 	path = ns.Path + path
 
 	var existing string
-	fn := func(existingPath string, v interface{}) bool {
-		if strings.HasPrefix(existingPath, path) {
-			existing = existingPath
+	fn := func(existingPath []byte, v interface{}) bool {
+		if strings.HasPrefix(string(existingPath), path) {
+			existing = string(existingPath)
 			return true
 		}
 		return false
 	}
-	r.root.WalkPrefix(path, fn)
+	table.root.Root().WalkPrefix([]byte(path), fn)
 	return existing
 }
 
 // MountConflict determines if there are potential path conflicts
 func (r *Router) MountConflict(ctx context.Context, path string) string {
-	r.l.RLock()
-	defer r.l.RUnlock()
-	if exactMatch := r.matchingMountInternal(ctx, path); exactMatch != "" {
+	table := r.table.Load()
+	if exactMatch := r.matchingMountInternal(ctx, table, path); exactMatch != "" {
 		return exactMatch
 	}
-	if prefixMatch := r.matchingPrefixInternal(ctx, path); prefixMatch != "" {
+	if prefixMatch := r.matchingPrefixInternal(ctx, table, path); prefixMatch != "" {
 		return prefixMatch
 	}
 	return ""
@@ -419,19 +554,11 @@ func (r *Router) matchingStorage(ctx context.Context, path string, apiPath bool)
 	}
 	path = ns.Path + path
 
-	var raw interface{}
-	var ok bool
-	r.l.RLock()
-	if apiPath {
-		_, raw, ok = r.root.LongestPrefix(path)
-	} else {
-		_, raw, ok = r.storagePrefix.LongestPrefix(path)
-	}
-	r.l.RUnlock()
+	re, ok := r.table.Load().matchingRouteEntry(path, apiPath)
 	if !ok {
 		return nil
 	}
-	return raw.(*routeEntry).storageView
+	return re.storageView
 }
 
 // MatchingMountEntry returns the MountEntry used for a path
@@ -442,13 +569,11 @@ func (r *Router) MatchingMountEntry(ctx context.Context, path string) *MountEntr
 	}
 	path = ns.Path + path
 
-	r.l.RLock()
-	_, raw, ok := r.root.LongestPrefix(path)
-	r.l.RUnlock()
+	_, re, ok := r.table.Load().longestPrefix(path)
 	if !ok {
 		return nil
 	}
-	return raw.(*routeEntry).mountEntry
+	return re.mountEntry
 }
 
 // MatchingBackend returns the backend used for a path
@@ -459,14 +584,11 @@ func (r *Router) MatchingBackend(ctx context.Context, path string) logical.Backe
 	}
 	path = ns.Path + path
 
-	r.l.RLock()
-	_, raw, ok := r.root.LongestPrefix(path)
-	r.l.RUnlock()
+	_, re, ok := r.table.Load().longestPrefix(path)
 	if !ok {
 		return nil
 	}
 
-	re := raw.(*routeEntry)
 	re.l.RLock()
 	defer re.l.RUnlock()
 
@@ -481,13 +603,18 @@ func (r *Router) MatchingSystemView(ctx context.Context, path string) logical.Sy
 	}
 	path = ns.Path + path
 
-	r.l.RLock()
-	_, raw, ok := r.root.LongestPrefix(path)
-	r.l.RUnlock()
-	if !ok || raw.(*routeEntry).backend == nil {
+	mount, re, ok := r.table.Load().longestPrefix(path)
+	if !ok {
 		return nil
 	}
-	return raw.(*routeEntry).backend.System()
+	if err := r.loadLazyBackend(ctx, re); err != nil {
+		r.logger.Error("failed to load lazy mount", "path", mount, "error", err)
+		return nil
+	}
+	if re.backend == nil {
+		return nil
+	}
+	return re.backend.System()
 }
 
 func (r *Router) MatchingMountByAPIPath(ctx context.Context, path string) string {
@@ -527,21 +654,12 @@ func (r *Router) MatchingAPIPrefixByStoragePath(ctx context.Context, path string
 }
 
 func (r *Router) matchingMountEntryByPath(ctx context.Context, path string, apiPath bool) (*MountEntry, string, bool) {
-	var raw interface{}
-	var ok bool
-	r.l.RLock()
-	if apiPath {
-		_, raw, ok = r.root.LongestPrefix(path)
-	} else {
-		_, raw, ok = r.storagePrefix.LongestPrefix(path)
-	}
-	r.l.RUnlock()
+	re, ok := r.table.Load().matchingRouteEntry(path, apiPath)
 	if !ok {
 		return nil, "", false
 	}
 
 	// Extract the mount path and storage prefix
-	re := raw.(*routeEntry)
 	prefix := re.storagePrefix
 
 	return re.mountEntry, prefix, true
@@ -566,16 +684,15 @@ func (r *Router) routeCommon(ctx context.Context, req *logical.Request, existenc
 	}
 
 	// Find the mount point
-	r.l.RLock()
+	table := r.table.Load()
 	adjustedPath := req.Path
-	mount, raw, ok := r.root.LongestPrefix(ns.Path + adjustedPath)
+	mount, re, ok := table.longestPrefix(ns.Path + adjustedPath)
 	if !ok && !strings.HasSuffix(adjustedPath, "/") {
 		// Re-check for a backend by appending a slash. This lets "foo" mean
 		// "foo/" at the root level which is almost always what we want.
 		adjustedPath += "/"
-		mount, raw, ok = r.root.LongestPrefix(ns.Path + adjustedPath)
+		mount, re, ok = table.longestPrefix(ns.Path + adjustedPath)
 	}
-	r.l.RUnlock()
 	if !ok {
 		return logical.ErrorResponse(fmt.Sprintf("no handler for route %q. route entry not found.", req.Path)), false, false, logical.ErrUnsupportedPath
 	}
@@ -586,7 +703,12 @@ func (r *Router) routeCommon(ctx context.Context, req *logical.Request, existenc
 			strings.ReplaceAll(mount, "/", "-"),
 		}, time.Now())
 	}
-	re := raw.(*routeEntry)
+
+	// Load the backend of the mount if it was deferred at unseal
+	if err := r.loadLazyBackend(ctx, re); err != nil {
+		r.logger.Error("failed to load lazy mount", "path", mount, "error", err)
+		return logical.ErrorResponse(fmt.Sprintf("no handler for route %q. failed to load backend.", req.Path)), false, false, logical.ErrUnsupportedPath
+	}
 
 	// Grab a read lock on the route entry, this protects against the backend
 	// being reloaded during a request. The exception is a renew request on the
@@ -605,7 +727,7 @@ func (r *Router) routeCommon(ctx context.Context, req *logical.Request, existenc
 
 	// If the path is tainted, we reject any operation except for
 	// Rollback and Revoke
-	if re.tainted {
+	if re.tainted.Load() {
 		switch req.Operation {
 		case logical.RevokeOperation, logical.RollbackOperation:
 		default:
@@ -613,6 +735,29 @@ func (r *Router) routeCommon(ctx context.Context, req *logical.Request, existenc
 		}
 	}
 
+	// Reject requests larger than the maximum request size of the mount. The
+	// existence check is left to run, the request being rejected right after.
+	if maxRequestSize := re.mountEntry.Config.MaxRequestSize; !existenceCheck && maxRequestSize > 0 && req.RequestSize > maxRequestSize {
+		return logical.ErrorResponse(fmt.Sprintf("request of %d bytes exceeds the maximum request size of %d bytes of route %q", req.RequestSize, maxRequestSize, req.Path)), false, false, logical.ErrRequestTooLarge
+	}
+
+	// Reject requests beyond the maximum number of concurrent requests of the
+	// mount, so that a burst of requests to a mount cannot tie up the workers
+	// handling requests to other mounts.
+	if maxConcurrent := int64(re.mountEntry.Config.MaxConcurrentRequests); !existenceCheck && maxConcurrent > 0 {
+		if re.inFlight.Add(1) > maxConcurrent {
+			re.inFlight.Add(-1)
+			return logical.ErrorResponse(fmt.Sprintf("route %q is handling its maximum of %d concurrent requests", req.Path, maxConcurrent)), false, false, logical.ErrConcurrencyLimitExceeded
+		}
+		defer re.inFlight.Add(-1)
+	}
+
+	// Dry runs rely on the backend not changing any state outside of its
+	// storage, which cannot be enforced on external plugins
+	if req.DryRun && re.mountEntry.IsExternalPlugin() {
+		return logical.ErrorResponse("dry runs are not supported by external plugins"), false, false, logical.ErrInvalidRequest
+	}
+
 	// Adjust the path to exclude the routing prefix
 	originalPath := req.Path
 	req.Path = strings.TrimPrefix(ns.Path+req.Path, mount)
@@ -627,8 +772,12 @@ func (r *Router) routeCommon(ctx context.Context, req *logical.Request, existenc
 		req.Path = ""
 	}
 
-	// Attach the storage view for the request
+	// Attach the storage view for the request, discarding the writes of dry
+	// runs
 	req.Storage = re.storageView
+	if req.DryRun {
+		req.Storage = newDryRunStorage(re.storageView)
+	}
 
 	originalEntityID := req.EntityID
 
@@ -767,12 +916,26 @@ func (r *Router) routeCommon(ctx context.Context, req *logical.Request, existenc
 		req.SetTokenEntry(reqTokenEntry)
 	}()
 
+	// Bound the time the backend spends handling the request to the request
+	// timeout of the mount, so that slow backends do not tie up the workers
+	// handling requests to other mounts.
+	backendCtx := ctx
+	requestTimeout := re.mountEntry.Config.DefaultRequestTimeout
+	if requestTimeout > 0 {
+		var cancel context.CancelFunc
+		backendCtx, cancel = context.WithTimeout(ctx, requestTimeout)
+		defer cancel()
+	}
+
 	// Invoke the backend
 	if existenceCheck {
-		ok, exists, err := re.backend.HandleExistenceCheck(ctx, req)
+		ok, exists, err := re.backend.HandleExistenceCheck(backendCtx, req)
 		return nil, ok, exists, err
 	} else {
-		resp, err := re.backend.HandleRequest(ctx, req)
+		resp, err := re.backend.HandleRequest(backendCtx, req)
+		if backendCtx.Err() != nil && ctx.Err() == nil && (err != nil || (resp != nil && resp.IsError())) {
+			return logical.ErrorResponse(fmt.Sprintf("request to route %q exceeded the request timeout of %s", originalPath, requestTimeout)), false, false, logical.ErrRequestTimeout
+		}
 		if resp != nil {
 			if len(allowedResponseHeaders) > 0 {
 				resp.Headers = filteredHeaders(resp.Headers, allowedResponseHeaders, nil)
@@ -841,13 +1004,16 @@ func (r *Router) RootPath(ctx context.Context, path string) bool {
 
 	adjustedPath := ns.Path + path
 
-	r.l.RLock()
-	mount, raw, ok := r.root.LongestPrefix(adjustedPath)
-	r.l.RUnlock()
+	mount, re, ok := r.table.Load().longestPrefix(adjustedPath)
 	if !ok {
 		return false
 	}
-	re := raw.(*routeEntry)
+
+	// The root paths are only known once the backend is loaded
+	if err := r.loadLazyBackend(ctx, re); err != nil {
+		r.logger.Error("failed to load lazy mount", "path", mount, "error", err)
+		return false
+	}
 
 	// Trim to get remaining path
 	remain := strings.TrimPrefix(adjustedPath, mount)
@@ -882,13 +1048,16 @@ func (r *Router) LoginPath(ctx context.Context, path string) bool {
 
 	adjustedPath := ns.Path + path
 
-	r.l.RLock()
-	mount, raw, ok := r.root.LongestPrefix(adjustedPath)
-	r.l.RUnlock()
+	mount, re, ok := r.table.Load().longestPrefix(adjustedPath)
 	if !ok {
 		return false
 	}
-	re := raw.(*routeEntry)
+
+	// The login paths are only known once the backend is loaded
+	if err := r.loadLazyBackend(ctx, re); err != nil {
+		r.logger.Error("failed to load lazy mount", "path", mount, "error", err)
+		return false
+	}
 
 	// Trim to get remaining path
 	remain := strings.TrimPrefix(adjustedPath, mount)
@@ -4,13 +4,17 @@
 package vault
 
 import (
+	"context"
+	"fmt"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-uuid"
 	"github.com/openbao/openbao/helper/namespace"
 	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/stretchr/testify/require"
 )
 
 func TestRouter_Mount(t *testing.T) {
@@ -631,3 +635,205 @@ func TestParseUnauthenticatedPaths_Error(t *testing.T) {
 		}
 	}
 }
+
+func TestRouter_RequestLimits(t *testing.T) {
+	r := NewRouter()
+	_, barrier, _ := mockBarrier(t)
+	view := NewBarrierView(barrier, "logical/")
+
+	meUUID, err := uuid.GenerateUUID()
+	require.NoError(t, err)
+
+	mountEntry := &MountEntry{
+		Path:        "limited/",
+		UUID:        meUUID,
+		Accessor:    "limitedaccessor",
+		NamespaceID: namespace.RootNamespaceID,
+		namespace:   namespace.RootNamespace,
+		Config: MountConfig{
+			DefaultRequestTimeout: 50 * time.Millisecond,
+			MaxRequestSize:        10,
+		},
+	}
+
+	n := &NoopBackend{
+		RequestHandler: func(ctx context.Context, req *logical.Request) (*logical.Response, error) {
+			if req.Path != "slow" {
+				return nil, nil
+			}
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+	require.NoError(t, r.Mount(n, "limited/", mountEntry, view))
+
+	ctx := namespace.RootContext(nil)
+
+	req := &logical.Request{Path: "limited/fast", RequestSize: 10}
+	_, err = r.Route(ctx, req)
+	require.NoError(t, err)
+
+	req = &logical.Request{Path: "limited/fast", RequestSize: 11}
+	resp, err := r.Route(ctx, req)
+	require.ErrorIs(t, err, logical.ErrRequestTooLarge)
+	require.True(t, resp.IsError())
+
+	req = &logical.Request{Path: "limited/slow"}
+	resp, err = r.Route(ctx, req)
+	require.ErrorIs(t, err, logical.ErrRequestTimeout)
+	require.True(t, resp.IsError())
+
+	// Requests too large never reach the backend.
+	require.Equal(t, []string{"fast", "slow"}, n.Paths)
+}
+
+func TestRouter_MaxConcurrentRequests(t *testing.T) {
+	r := NewRouter()
+	_, barrier, _ := mockBarrier(t)
+	view := NewBarrierView(barrier, "logical/")
+
+	meUUID, err := uuid.GenerateUUID()
+	require.NoError(t, err)
+
+	mountEntry := &MountEntry{
+		Path:        "limited/",
+		UUID:        meUUID,
+		Accessor:    "limitedaccessor",
+		NamespaceID: namespace.RootNamespaceID,
+		namespace:   namespace.RootNamespace,
+		Config: MountConfig{
+			MaxConcurrentRequests: 2,
+		},
+	}
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	n := &NoopBackend{
+		RequestHandler: func(ctx context.Context, req *logical.Request) (*logical.Response, error) {
+			if req.Path == "blocking" {
+				started <- struct{}{}
+				<-release
+			}
+			return nil, nil
+		},
+	}
+	require.NoError(t, r.Mount(n, "limited/", mountEntry, view))
+
+	ctx := namespace.RootContext(nil)
+
+	// Fill up the mount with blocking requests
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := r.Route(ctx, &logical.Request{Path: "limited/blocking"})
+			errs <- err
+		}()
+		<-started
+	}
+
+	resp, err := r.Route(ctx, &logical.Request{Path: "limited/other"})
+	require.ErrorIs(t, err, logical.ErrConcurrencyLimitExceeded)
+	require.True(t, resp.IsError())
+
+	close(release)
+	for i := 0; i < 2; i++ {
+		require.NoError(t, <-errs)
+	}
+
+	// The mount accepts requests again once the blocking ones are done
+	_, err = r.Route(ctx, &logical.Request{Path: "limited/other"})
+	require.NoError(t, err)
+}
+
+// benchmarkRouter returns a router with mounts spread across namespaces,
+// along with the namespaced contexts and paths to resolve.
+func benchmarkRouter(b *testing.B, namespaces, mountsPerNamespace int) (*Router, []context.Context, []string) {
+	r := NewRouter()
+	_, barrier, _ := mockBarrier(b)
+
+	var ctxs []context.Context
+	var paths []string
+	n := &NoopBackend{}
+	for i := 0; i < namespaces; i++ {
+		ns := &namespace.Namespace{
+			ID:   fmt.Sprintf("ns%d", i),
+			Path: fmt.Sprintf("ns%d/", i),
+		}
+		if i == 0 {
+			ns = namespace.RootNamespace
+		}
+		for j := 0; j < mountsPerNamespace; j++ {
+			meUUID, err := uuid.GenerateUUID()
+			if err != nil {
+				b.Fatal(err)
+			}
+			path := fmt.Sprintf("secret%d/", j)
+			mountEntry := &MountEntry{
+				Path:        path,
+				UUID:        meUUID,
+				Accessor:    "kv_" + meUUID,
+				NamespaceID: ns.ID,
+				namespace:   ns,
+			}
+			view := NewBarrierView(barrier, "logical/"+meUUID+"/")
+			if err := r.Mount(n, path, mountEntry, view); err != nil {
+				b.Fatal(err)
+			}
+			ctxs = append(ctxs, namespace.ContextWithNamespace(context.Background(), ns))
+			paths = append(paths, path+"data/foo")
+		}
+	}
+	return r, ctxs, paths
+}
+
+func BenchmarkRouter_MatchingMount(b *testing.B) {
+	r, ctxs, paths := benchmarkRouter(b, 400, 8)
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			idx := i % len(paths)
+			if r.MatchingMount(ctxs[idx], paths[idx]) == "" {
+				b.Fatalf("missing mount for %q", paths[idx])
+			}
+			i++
+		}
+	})
+}
+
+func BenchmarkRouter_MatchingMount_Churn(b *testing.B) {
+	r, ctxs, paths := benchmarkRouter(b, 400, 8)
+	_, barrier, _ := mockBarrier(b)
+	view := NewBarrierView(barrier, "logical/churn/")
+
+	// Keep mounting and unmounting alongside the lookups, as when the mount
+	// tables of namespaces are changing.
+	ctx, cancel := context.WithCancel(namespace.RootContext(nil))
+	defer cancel()
+	go func() {
+		mountEntry := &MountEntry{
+			Path:        "churn/",
+			UUID:        "churn",
+			Accessor:    "kv_churn",
+			NamespaceID: namespace.RootNamespaceID,
+			namespace:   namespace.RootNamespace,
+		}
+		for ctx.Err() == nil {
+			r.Mount(&NoopBackend{}, "churn/", mountEntry, view)
+			r.Unmount(ctx, "churn/")
+		}
+	}()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			idx := i % len(paths)
+			if r.MatchingMount(ctxs[idx], paths[idx]) == "" {
+				b.Fatalf("missing mount for %q", paths[idx])
+			}
+			i++
+		}
+	})
+}
@@ -352,6 +352,15 @@ type SealConfig struct {
 
 	// Stores the progress of the verification operation (key shares)
 	VerificationProgress [][]byte `json:"-"`
+
+	// TOTPShares requests, when rekeying, that a TOTP secret is enrolled for
+	// each new share. It is omitted from JSON as only the enrollments are
+	// persisted.
+	TOTPShares bool `json:"-"`
+
+	// TOTPEnrollments holds the TOTP enrollment of each share. If set, shares
+	// are only accepted during unseal along with their current TOTP code.
+	TOTPEnrollments []*TOTPShareEnrollment `json:"totp_enrollments,omitempty" mapstructure:"-"`
 }
 
 // Validate is used to sanity check the seal configuration
@@ -392,7 +401,7 @@ func (s *SealConfig) Validate() error {
 			}
 		}
 	}
-	return nil
+	return s.validateTOTPShares()
 }
 
 func (s *SealConfig) Clone() *SealConfig {
@@ -405,6 +414,7 @@ func (s *SealConfig) Clone() *SealConfig {
 		StoredShares:         s.StoredShares,
 		VerificationRequired: s.VerificationRequired,
 		VerificationNonce:    s.VerificationNonce,
+		TOTPShares:           s.TOTPShares,
 	}
 	if len(s.PGPKeys) > 0 {
 		ret.PGPKeys = make([]string, len(s.PGPKeys))
@@ -414,6 +424,10 @@ func (s *SealConfig) Clone() *SealConfig {
 		ret.VerificationKey = make([]byte, len(s.VerificationKey))
 		copy(ret.VerificationKey, s.VerificationKey)
 	}
+	if len(s.TOTPEnrollments) > 0 {
+		ret.TOTPEnrollments = make([]*TOTPShareEnrollment, len(s.TOTPEnrollments))
+		copy(ret.TOTPEnrollments, s.TOTPEnrollments)
+	}
 	return ret
 }
 
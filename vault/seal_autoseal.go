@@ -48,6 +48,8 @@ type autoSeal struct {
 
 	hcLock          sync.Mutex
 	healthCheckStop chan struct{}
+	// unhealthy is set while the health check fails.
+	unhealthy atomic.Bool
 }
 
 // Ensure we are implementing the Seal interface
@@ -564,6 +566,7 @@ func (d *autoSeal) StartHealthCheck() {
 				healthCheck.Reset(sealHealthTestIntervalUnhealthy)
 			}
 			lastTestOk = false
+			d.unhealthy.Store(true)
 			d.core.MetricSink().SetGauge(autoSealUnavailableDuration, float32(time.Since(lastSeenOk).Milliseconds()))
 		}
 		for {
@@ -602,6 +605,7 @@ func (d *autoSeal) StartHealthCheck() {
 								}
 								lastTestOk = true
 								lastSeenOk = t
+								d.unhealthy.Store(false)
 								d.core.MetricSink().SetGauge(autoSealUnavailableDuration, 0)
 							}
 						}()
@@ -612,6 +616,12 @@ func (d *autoSeal) StartHealthCheck() {
 	}()
 }
 
+// Healthy returns whether the last health check of the auto-unseal backend
+// succeeded.
+func (d *autoSeal) Healthy() bool {
+	return !d.unhealthy.Load()
+}
+
 func (d *autoSeal) StopHealthCheck() {
 	d.hcLock.Lock()
 	defer d.hcLock.Unlock()
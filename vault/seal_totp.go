@@ -0,0 +1,193 @@
+package vault
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	wrapping "github.com/openbao/go-kms-wrapping/v2"
+	"github.com/openbao/openbao/helper/pgpkeys"
+	otplib "github.com/pquerna/otp"
+	totplib "github.com/pquerna/otp/totp"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	totpShareIssuer = "OpenBao"
+	totpShareInfo   = "openbao-totp-share"
+	totpSharePeriod = 30
+	totpShareSkew   = 1
+)
+
+// TOTPShareEnrollment is the TOTP enrollment of an unseal key share. The
+// secret is encrypted with a key derived from the share, so that it can only
+// be recovered by the shareholder, and so that the enrollment of a share can
+// be found without storing anything that identifies it.
+type TOTPShareEnrollment struct {
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// totpShareAEAD returns the cipher protecting the TOTP secret of a share.
+func totpShareAEAD(share, salt []byte) (cipher.AEAD, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, share, salt, []byte(totpShareInfo)), key); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptTOTPURLs encrypts each TOTP URL to the PGP key of the matching share,
+// so that only its shareholder can read it. The encrypted URLs are base64
+// encoded.
+func encryptTOTPURLs(urls []string, pgpKeys []string) ([]string, error) {
+	plaintexts := make([][]byte, len(urls))
+	for i, url := range urls {
+		plaintexts[i] = []byte(url)
+	}
+	_, ciphertexts, err := pgpkeys.EncryptShares(plaintexts, pgpKeys)
+	if err != nil {
+		return nil, err
+	}
+	encrypted := make([]string, len(ciphertexts))
+	for i, ciphertext := range ciphertexts {
+		encrypted[i] = base64.StdEncoding.EncodeToString(ciphertext)
+	}
+	return encrypted, nil
+}
+
+// enrollTOTPShares generates a TOTP secret for each of the given shares. It
+// returns the enrollments, which are stored in the seal configuration, and
+// the otpauth URLs to hand to the shareholders, in the order of the shares.
+func enrollTOTPShares(rand io.Reader, shares [][]byte) ([]*TOTPShareEnrollment, []string, error) {
+	enrollments := make([]*TOTPShareEnrollment, 0, len(shares))
+	urls := make([]string, 0, len(shares))
+	for i, share := range shares {
+		key, err := totplib.Generate(totplib.GenerateOpts{
+			Issuer:      totpShareIssuer,
+			AccountName: "unseal-key-" + strconv.Itoa(i+1),
+			Period:      totpSharePeriod,
+			Rand:        rand,
+		})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate TOTP secret: %w", err)
+		}
+
+		enrollment := &TOTPShareEnrollment{
+			Salt: make([]byte, 32),
+		}
+		if _, err := io.ReadFull(rand, enrollment.Salt); err != nil {
+			return nil, nil, err
+		}
+		aead, err := totpShareAEAD(share, enrollment.Salt)
+		if err != nil {
+			return nil, nil, err
+		}
+		enrollment.Nonce = make([]byte, aead.NonceSize())
+		if _, err := io.ReadFull(rand, enrollment.Nonce); err != nil {
+			return nil, nil, err
+		}
+		enrollment.Ciphertext = aead.Seal(nil, enrollment.Nonce, []byte(key.Secret()), nil)
+
+		enrollments = append(enrollments, enrollment)
+		urls = append(urls, key.URL())
+	}
+
+	return enrollments, urls, nil
+}
+
+// verifyTOTPShare checks that the code is the current TOTP code of the share.
+// Codes are only accepted once; used tracks the codes accepted so far.
+func verifyTOTPShare(enrollments []*TOTPShareEnrollment, share []byte, code string, used map[string]time.Time, now time.Time) error {
+	var secret []byte
+	var index int
+	for i, enrollment := range enrollments {
+		aead, err := totpShareAEAD(share, enrollment.Salt)
+		if err != nil {
+			return err
+		}
+		if plaintext, err := aead.Open(nil, enrollment.Nonce, enrollment.Ciphertext, nil); err == nil {
+			secret, index = plaintext, i
+			break
+		}
+	}
+	if secret == nil {
+		return &ErrInvalidKey{"key share is not enrolled for TOTP"}
+	}
+	if code == "" {
+		return &ErrInvalidKey{"a TOTP code is required with this key share"}
+	}
+
+	valid, err := totplib.ValidateCustom(code, string(secret), now, totplib.ValidateOpts{
+		Period:    totpSharePeriod,
+		Skew:      totpShareSkew,
+		Digits:    otplib.DigitsSix,
+		Algorithm: otplib.AlgorithmSHA1,
+	})
+	if err != nil || !valid {
+		return &ErrInvalidKey{"invalid TOTP code"}
+	}
+
+	// A code stays valid for the whole skew window, which bounds how long it
+	// needs to be remembered.
+	for k, at := range used {
+		if now.Sub(at) > (2*totpShareSkew+1)*totpSharePeriod*time.Second {
+			delete(used, k)
+		}
+	}
+	usedKey := strconv.Itoa(index) + ":" + code
+	if _, ok := used[usedKey]; ok {
+		return &ErrInvalidKey{"TOTP code has already been used"}
+	}
+	used[usedKey] = now
+
+	return nil
+}
+
+// verifyUnsealTOTP checks the TOTP code provided with an unseal key share, if
+// the shares of the seal were enrolled for TOTP. It applies to every use of
+// the shares: unseal, root token generation and rekey.
+func (c *Core) verifyUnsealTOTP(ctx context.Context, sealToUse Seal, key []byte, code string) error {
+	if sealToUse.BarrierType() != wrapping.WrapperTypeShamir {
+		return nil
+	}
+	config, err := sealToUse.BarrierConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if config == nil || len(config.TOTPEnrollments) == 0 {
+		return nil
+	}
+
+	c.totpShareLock.Lock()
+	defer c.totpShareLock.Unlock()
+	if c.totpShareUsedCodes == nil {
+		c.totpShareUsedCodes = make(map[string]time.Time)
+	}
+	return verifyTOTPShare(config.TOTPEnrollments, key, code, c.totpShareUsedCodes, time.Now())
+}
+
+// validateTOTPShares checks the TOTP options of a seal configuration.
+func (s *SealConfig) validateTOTPShares() error {
+	if len(s.TOTPEnrollments) > 0 && len(s.TOTPEnrollments) != s.SecretShares {
+		return errors.New("count mismatch between number of TOTP enrollments and number of shares")
+	}
+	for _, enrollment := range s.TOTPEnrollments {
+		if enrollment == nil || len(enrollment.Salt) == 0 || len(enrollment.Nonce) == 0 || len(enrollment.Ciphertext) == 0 {
+			return errors.New("invalid TOTP enrollment")
+		}
+	}
+	return nil
+}
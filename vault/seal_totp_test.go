@@ -0,0 +1,204 @@
+package vault
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-secure-stdlib/base62"
+	"github.com/openbao/openbao/helper/pgpkeys"
+	totplib "github.com/pquerna/otp/totp"
+)
+
+func TestCore_Rekey_TOTPShares(t *testing.T) {
+	c, keys, root := TestCoreUnsealed(t)
+	ctx := context.Background()
+
+	if err := c.RekeyInit(&SealConfig{
+		SecretShares:    5,
+		SecretThreshold: 3,
+		TOTPShares:      true,
+	}, false); err != nil {
+		t.Fatal(err)
+	}
+	rkconf, err := c.RekeyConfig(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result *RekeyResult
+	for _, key := range keys {
+		result, err = c.RekeyUpdate(ctx, key, rkconf.Nonce, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result != nil {
+			break
+		}
+	}
+	if result == nil || len(result.TOTPURLs) != len(result.SecretShares) {
+		t.Fatalf("bad result: %#v", result)
+	}
+
+	secrets := make([]string, len(result.TOTPURLs))
+	for i, u := range result.TOTPURLs {
+		parsed, err := url.Parse(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		secrets[i] = parsed.Query().Get("secret")
+	}
+
+	if err := c.Seal(root); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	code := func(i int) string {
+		t.Helper()
+		code, err := totplib.GenerateCode(secrets[i], now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+
+	// Shares are refused without their code, or with the code of another
+	// share.
+	if _, err := c.Unseal(result.SecretShares[0]); err == nil {
+		t.Fatal("expected an error without a TOTP code")
+	}
+	if _, err := c.UnsealWithTOTP(result.SecretShares[0], code(1)); err == nil {
+		t.Fatal("expected an error with the TOTP code of another share")
+	}
+
+	if _, err := c.UnsealWithTOTP(result.SecretShares[0], code(0)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.UnsealWithTOTP(result.SecretShares[1], code(1)); err != nil {
+		t.Fatal(err)
+	}
+
+	// Codes cannot be reused.
+	c.ResetUnsealProcess()
+	if _, err := c.UnsealWithTOTP(result.SecretShares[0], code(0)); err == nil {
+		t.Fatal("expected an error when reusing a TOTP code")
+	}
+	if _, err := c.UnsealWithTOTP(result.SecretShares[2], code(2)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.UnsealWithTOTP(result.SecretShares[3], code(3)); err != nil {
+		t.Fatal(err)
+	}
+	unsealed, unsealErr := c.UnsealWithTOTP(result.SecretShares[4], code(4))
+	if unsealErr != nil {
+		t.Fatal(unsealErr)
+	}
+	if !unsealed {
+		t.Fatal("should be unsealed")
+	}
+
+	// The shares also require their code to generate a root token or rekey,
+	// using codes of the next period as those of this one were used.
+	later := now.Add(totpSharePeriod * time.Second)
+	laterCode := func(i int) string {
+		t.Helper()
+		code, err := totplib.GenerateCode(secrets[i], later)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return code
+	}
+
+	otp, otpErr := base62.Random(TokenPrefixLength + TokenLength)
+	if otpErr != nil {
+		t.Fatal(otpErr)
+	}
+	if err := c.GenerateRootInit(otp, "", GenerateStandardRootTokenStrategy); err != nil {
+		t.Fatal(err)
+	}
+	genConf, genErr := c.GenerateRootConfiguration()
+	if genErr != nil {
+		t.Fatal(genErr)
+	}
+	if _, err := c.GenerateRootUpdate(ctx, result.SecretShares[0], genConf.Nonce, GenerateStandardRootTokenStrategy); err == nil {
+		t.Fatal("expected an error generating a root token without a TOTP code")
+	}
+	genResult, genErr := c.GenerateRootUpdateWithTOTP(ctx, result.SecretShares[0], laterCode(0), genConf.Nonce, GenerateStandardRootTokenStrategy, "")
+	if genErr != nil {
+		t.Fatal(genErr)
+	}
+	if genResult.Progress != 1 {
+		t.Fatalf("bad progress: %d", genResult.Progress)
+	}
+	if err := c.GenerateRootCancel(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.RekeyInit(&SealConfig{SecretShares: 1, SecretThreshold: 1}, false); err != nil {
+		t.Fatal(err)
+	}
+	rkconf, err = c.RekeyConfig(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.RekeyUpdate(ctx, result.SecretShares[1], rkconf.Nonce, false); err == nil {
+		t.Fatal("expected an error rekeying without a TOTP code")
+	}
+	if _, err := c.RekeyUpdateWithTOTP(ctx, result.SecretShares[1], laterCode(1), rkconf.Nonce, false); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCore_Rekey_TOTPSharesPGP(t *testing.T) {
+	c, keys, _ := TestCoreUnsealed(t)
+	ctx := context.Background()
+
+	pubKeys := []string{pgpkeys.TestPubKey1, pgpkeys.TestPubKey2, pgpkeys.TestPubKey3}
+	privKeys := []string{pgpkeys.TestPrivKey1, pgpkeys.TestPrivKey2, pgpkeys.TestPrivKey3}
+	if err := c.RekeyInit(&SealConfig{
+		SecretShares:    3,
+		SecretThreshold: 2,
+		PGPKeys:         pubKeys,
+		TOTPShares:      true,
+	}, false); err != nil {
+		t.Fatal(err)
+	}
+	rkconf, err := c.RekeyConfig(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result *RekeyResult
+	for _, key := range keys {
+		result, err = c.RekeyUpdate(ctx, key, rkconf.Nonce, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result != nil {
+			break
+		}
+	}
+	if result == nil || len(result.TOTPURLs) != len(pubKeys) {
+		t.Fatalf("bad result: %#v", result)
+	}
+
+	// Each TOTP URL is only readable with the key its share was encrypted to
+	for i, encrypted := range result.TOTPURLs {
+		if strings.HasPrefix(encrypted, "otpauth://") {
+			t.Fatalf("TOTP URL %d is not encrypted", i)
+		}
+		plaintext, err := pgpkeys.DecryptBytes(encrypted, privKeys[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(plaintext.String(), "otpauth://totp/") {
+			t.Fatalf("bad TOTP URL %d: %q", i, plaintext.String())
+		}
+		if _, err := pgpkeys.DecryptBytes(encrypted, privKeys[(i+1)%len(privKeys)]); err == nil {
+			t.Fatalf("TOTP URL %d decrypted with the key of another share", i)
+		}
+	}
+}
@@ -0,0 +1,299 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	metrics "github.com/armon/go-metrics"
+	uuid "github.com/hashicorp/go-uuid"
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/helper/secretsimport"
+	"github.com/openbao/openbao/sdk/v2/logical"
+)
+
+const (
+	SecretsImportStatusRunning   = "running"
+	SecretsImportStatusCompleted = "completed"
+	SecretsImportStatusFailed    = "failed"
+	SecretsImportStatusCanceled  = "canceled"
+
+	// secretsImportMaxErrors is the maximum number of per-secret errors
+	// recorded in the status of an import.
+	secretsImportMaxErrors = 100
+)
+
+var (
+	secretsImportImportedMetric = []string{"core", "secrets_import", "imported"}
+	secretsImportFailedMetric   = []string{"core", "secrets_import", "failed"}
+)
+
+// SecretsImportPlanEntry is a secret of the source and the path of the
+// destination mount it is, or would be in a dry run, imported at.
+type SecretsImportPlanEntry struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+}
+
+// SecretsImportInfo is the progress of an import of secrets from an external
+// store into a KV version 2 mount.
+type SecretsImportInfo struct {
+	ID          string    `json:"id"`
+	Source      string    `json:"source"`
+	Destination string    `json:"destination"`
+	DryRun      bool      `json:"dry_run"`
+	Status      string    `json:"status"`
+	StartTime   time.Time `json:"start_time"`
+	EndTime     time.Time `json:"end_time"`
+
+	// Total is the number of secrets listed in the source, and Imported,
+	// Skipped and Failed the number of them processed so far. Secrets are
+	// skipped if no mapping rule matches them or they have no current value.
+	Total    int `json:"total"`
+	Imported int `json:"imported"`
+	Skipped  int `json:"skipped"`
+	Failed   int `json:"failed"`
+
+	// Error is the error that stopped the import, and Errors the first
+	// errors of individual secrets.
+	Error  string   `json:"error,omitempty"`
+	Errors []string `json:"errors,omitempty"`
+
+	// Plan lists the secrets that would be imported, in a dry run.
+	Plan []SecretsImportPlanEntry `json:"plan,omitempty"`
+}
+
+// secretsImportJob is an import tracked in the secretsImports map of the
+// core.
+type secretsImportJob struct {
+	lock   sync.RWMutex
+	info   SecretsImportInfo
+	cancel context.CancelFunc
+}
+
+// snapshot returns a copy of the progress of the import.
+func (j *secretsImportJob) snapshot() SecretsImportInfo {
+	j.lock.RLock()
+	defer j.lock.RUnlock()
+
+	info := j.info
+	info.Errors = append([]string(nil), j.info.Errors...)
+	info.Plan = append([]SecretsImportPlanEntry(nil), j.info.Plan...)
+	return info
+}
+
+func (j *secretsImportJob) update(f func(info *SecretsImportInfo)) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	f(&j.info)
+}
+
+func (j *secretsImportJob) recordFailure(name string, err error) {
+	j.update(func(info *SecretsImportInfo) {
+		info.Failed++
+		if len(info.Errors) < secretsImportMaxErrors {
+			info.Errors = append(info.Errors, fmt.Sprintf("%s: %s", name, err))
+		}
+	})
+}
+
+// SecretsImportRequest is the request of an import of secrets.
+type SecretsImportRequest struct {
+	SourceType   string
+	SourceConfig map[string]string
+	Destination  string
+	Rules        []secretsimport.Rule
+	DryRun       bool
+}
+
+// startSecretsImport validates the request and starts importing secrets in
+// the background, returning the ID the progress of the import is tracked
+// under.
+func (c *Core) startSecretsImport(ctx context.Context, req *SecretsImportRequest) (string, error) {
+	ns, err := namespace.FromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if len(req.Rules) == 0 {
+		req.Rules = secretsimport.DefaultRules
+	}
+	for i := range req.Rules {
+		if err := req.Rules[i].Validate(); err != nil {
+			return "", fmt.Errorf("invalid mapping %d: %w", i, err)
+		}
+	}
+
+	destination := sanitizePath(req.Destination)
+	entry := c.router.MatchingMountEntry(ctx, destination)
+	if entry == nil || entry.Path != destination {
+		return "", fmt.Errorf("no mount found at %q", destination)
+	}
+	if (entry.Type != "kv" && entry.Type != "generic") || entry.Options["version"] != "2" {
+		return "", fmt.Errorf("mount %q is not a KV version 2 mount", destination)
+	}
+
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		return "", fmt.Errorf("error generating import ID: %w", err)
+	}
+
+	// The import outlives the request, so the client of the source is
+	// created with the context of the import rather than of the request.
+	jobCtx, cancel := context.WithCancel(namespace.ContextWithNamespace(c.activeContext, ns))
+	source, err := secretsimport.NewSource(jobCtx, req.SourceType, req.SourceConfig)
+	if err != nil {
+		cancel()
+		return "", err
+	}
+
+	job := &secretsImportJob{
+		info: SecretsImportInfo{
+			ID:          id,
+			Source:      req.SourceType,
+			Destination: destination,
+			DryRun:      req.DryRun,
+			Status:      SecretsImportStatusRunning,
+			StartTime:   time.Now().UTC(),
+		},
+		cancel: cancel,
+	}
+	c.secretsImports.Store(id, job)
+
+	go func() {
+		defer cancel()
+		c.runSecretsImport(jobCtx, job, source, destination, req.Rules)
+	}()
+
+	return id, nil
+}
+
+// runSecretsImport imports the secrets of the source into the destination
+// mount, recording its progress in the job.
+func (c *Core) runSecretsImport(ctx context.Context, job *secretsImportJob, source secretsimport.Source, destination string, rules []secretsimport.Rule) {
+	logger := c.logger.Named("secrets-import").With("id", job.info.ID, "source", job.info.Source, "destination", destination)
+	logger.Info("starting secrets import", "dry_run", job.info.DryRun)
+
+	finish := func(status string, err error) {
+		job.update(func(info *SecretsImportInfo) {
+			info.Status = status
+			info.EndTime = time.Now().UTC()
+			if err != nil {
+				info.Error = err.Error()
+			}
+		})
+		info := job.snapshot()
+		logger.Info("finished secrets import", "status", status, "imported", info.Imported, "skipped", info.Skipped, "failed", info.Failed, "error", err)
+	}
+
+	names, err := source.List(ctx)
+	if err != nil {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			finish(SecretsImportStatusCanceled, nil)
+			return
+		}
+		finish(SecretsImportStatusFailed, err)
+		return
+	}
+	sort.Strings(names)
+	job.update(func(info *SecretsImportInfo) {
+		info.Total = len(names)
+	})
+
+	for _, name := range names {
+		if ctx.Err() != nil {
+			finish(SecretsImportStatusCanceled, nil)
+			return
+		}
+
+		dest, ok, err := secretsimport.MapSecret(rules, name)
+		if err != nil {
+			job.recordFailure(name, err)
+			continue
+		}
+		if !ok {
+			job.update(func(info *SecretsImportInfo) { info.Skipped++ })
+			continue
+		}
+
+		if job.info.DryRun {
+			job.update(func(info *SecretsImportInfo) {
+				info.Imported++
+				info.Plan = append(info.Plan, SecretsImportPlanEntry{Source: name, Destination: dest})
+			})
+			continue
+		}
+
+		data, err := source.Read(ctx, name)
+		if err != nil {
+			job.recordFailure(name, err)
+			metrics.IncrCounter(secretsImportFailedMetric, 1)
+			continue
+		}
+		if data == nil {
+			job.update(func(info *SecretsImportInfo) { info.Skipped++ })
+			continue
+		}
+
+		resp, err := c.router.Route(ctx, &logical.Request{
+			Operation: logical.UpdateOperation,
+			Path:      destination + "data/" + dest,
+			Data: map[string]interface{}{
+				"data": data,
+			},
+		})
+		if err == nil && resp != nil && resp.IsError() {
+			err = resp.Error()
+		}
+		if err != nil {
+			job.recordFailure(name, fmt.Errorf("failed to write %q: %w", dest, err))
+			metrics.IncrCounter(secretsImportFailedMetric, 1)
+			continue
+		}
+
+		job.update(func(info *SecretsImportInfo) { info.Imported++ })
+		metrics.IncrCounter(secretsImportImportedMetric, 1)
+	}
+
+	finish(SecretsImportStatusCompleted, nil)
+}
+
+// secretsImportJob returns the import of the given ID, or nil if it is not
+// tracked.
+func (c *Core) secretsImportJob(id string) *secretsImportJob {
+	raw, ok := c.secretsImports.Load(id)
+	if !ok {
+		return nil
+	}
+	return raw.(*secretsImportJob)
+}
+
+// listSecretsImports returns the IDs of the tracked imports, sorted.
+func (c *Core) listSecretsImports() []string {
+	var ids []string
+	c.secretsImports.Range(func(key, _ interface{}) bool {
+		ids = append(ids, key.(string))
+		return true
+	})
+	sort.Strings(ids)
+	return ids
+}
+
+// deleteSecretsImport cancels the import of the given ID if it is running,
+// and stops tracking it otherwise. It returns false if the import is not
+// tracked.
+func (c *Core) deleteSecretsImport(id string) bool {
+	job := c.secretsImportJob(id)
+	if job == nil {
+		return false
+	}
+	if job.snapshot().Status == SecretsImportStatusRunning {
+		job.cancel()
+		return true
+	}
+	c.secretsImports.Delete(id)
+	return true
+}
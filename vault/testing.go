@@ -228,6 +228,13 @@ func TestCoreWithSealAndUINoCleanup(t testing.T, opts *CoreConfig) *Core {
 	conf.DetectDeadlocks = opts.DetectDeadlocks
 	conf.AdministrativeNamespacePath = opts.AdministrativeNamespacePath
 	conf.ImpreciseLeaseRoleTracking = opts.ImpreciseLeaseRoleTracking
+	conf.RestrictedCrypto = opts.RestrictedCrypto
+	conf.RootTokenTTL = opts.RootTokenTTL
+	conf.RootTokenNumUses = opts.RootTokenNumUses
+	conf.LeaseRevocationRateLimit = opts.LeaseRevocationRateLimit
+	conf.ListDefaultLimit = opts.ListDefaultLimit
+	conf.DisabledMountRetention = opts.DisabledMountRetention
+	conf.ReloadConfigFunc = opts.ReloadConfigFunc
 
 	if opts.Logger != nil {
 		conf.Logger = opts.Logger
@@ -1537,6 +1544,12 @@ func NewTestCluster(t testing.T, base *CoreConfig, opts *TestClusterOptions) *Te
 		coreConfig.AdministrativeNamespacePath = base.AdministrativeNamespacePath
 		coreConfig.ServiceRegistration = base.ServiceRegistration
 		coreConfig.ImpreciseLeaseRoleTracking = base.ImpreciseLeaseRoleTracking
+		coreConfig.RestrictedCrypto = base.RestrictedCrypto
+		coreConfig.RootTokenTTL = base.RootTokenTTL
+		coreConfig.RootTokenNumUses = base.RootTokenNumUses
+		coreConfig.LeaseRevocationRateLimit = base.LeaseRevocationRateLimit
+		coreConfig.ListDefaultLimit = base.ListDefaultLimit
+		coreConfig.DisabledMountRetention = base.DisabledMountRetention
 
 		if base.BuiltinRegistry != nil {
 			coreConfig.BuiltinRegistry = base.BuiltinRegistry
@@ -103,6 +103,10 @@ var (
 	// pathSuffixSanitize is used to ensure a path suffix in a role is valid.
 	pathSuffixSanitize = regexp.MustCompile("\\w[\\w-.]+\\w")
 
+	// batchTokenPrefixVersion is the version from which batch tokens use the
+	// current batch token prefix.
+	batchTokenPrefixVersion = version.Must(version.NewVersion("1.10.0"))
+
 	destroyCubbyhole = func(ctx context.Context, ts *TokenStore, te *logical.TokenEntry) error {
 		if ts.cubbyholeBackend == nil {
 			// Should only ever happen in testing
@@ -136,6 +140,29 @@ var (
 	}
 )
 
+const (
+	// tokenExchangeTokenType is the only token type supported by the token
+	// exchange endpoint, see RFC 8693 section 3.
+	tokenExchangeTokenType = "urn:ietf:params:oauth:token-type:access_token"
+
+	// tokenExchangeSubjectAccessorMeta is the metadata key recording the
+	// accessor of the token a token was exchanged from.
+	tokenExchangeSubjectAccessorMeta = "exchange_subject_accessor"
+
+	// tokenExchangeAllowedPathsInternalMeta is the internal metadata key
+	// marking exchanged tokens and their children, holding the
+	// comma-separated path patterns they are restricted to, if any.
+	tokenExchangeAllowedPathsInternalMeta = "exchange_allowed_paths"
+)
+
+// tokenExchangeRestriction holds the restrictions of a token created by a
+// token exchange.
+type tokenExchangeRestriction struct {
+	// allowedPaths are the path patterns the token is restricted to. If
+	// empty, the token is only restricted by its policies.
+	allowedPaths []string
+}
+
 func (ts *TokenStore) paths() []*framework.Path {
 	commonFieldsForCreate := map[string]*framework.FieldSchema{
 		"display_name": {
@@ -192,6 +219,10 @@ func (ts *TokenStore) paths() []*framework.Path {
 			Type:        framework.TypeBool,
 			Description: "Create the token with no parent",
 		},
+		"bind_client_cert": {
+			Type:        framework.TypeBool,
+			Description: "Bind the token to the client certificate of the request, so it can only be used on connections presenting the same certificate",
+		},
 		"policies": {
 			Type:        framework.TypeStringSlice,
 			Description: "List of policies for the token",
@@ -299,6 +330,57 @@ func (ts *TokenStore) paths() []*framework.Path {
 			HelpDescription: strings.TrimSpace(tokenCreateHelp),
 		},
 
+		{
+			Pattern: "exchange$",
+
+			Fields: map[string]*framework.FieldSchema{
+				"subject_token": {
+					Type:        framework.TypeString,
+					Description: "Token to exchange. If set, it must be the token used to authenticate the request.",
+				},
+				"subject_token_type": {
+					Type:        framework.TypeString,
+					Description: "Type of the subject token. Only access tokens are supported.",
+				},
+				"requested_token_type": {
+					Type:        framework.TypeString,
+					Description: "Type of the requested token. Only access tokens are supported.",
+				},
+				"scope": {
+					Type:        framework.TypeString,
+					Description: "Space-separated list of policies for the token, as an alternative to 'policies'.",
+				},
+				"policies": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "List of policies for the token. These must be a subset of the policies of the subject token.",
+				},
+				"ttl": {
+					Type:        framework.TypeDurationSecond,
+					Description: "Time to live for the token. This cannot exceed the remaining TTL of the subject token, which is used by default.",
+				},
+				"num_uses": {
+					Type:        framework.TypeInt,
+					Description: "Max number of uses for the token.",
+				},
+				"paths": {
+					Type:        framework.TypeCommaStringSlice,
+					Description: "List of paths the token is restricted to, which may end with a * wildcard. These must be within the paths the subject token is restricted to, if any.",
+				},
+			},
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: operationPrefixToken,
+				OperationVerb:   "exchange",
+			},
+
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.UpdateOperation: ts.handleExchange,
+			},
+
+			HelpSynopsis:    strings.TrimSpace(tokenExchangeHelp),
+			HelpDescription: strings.TrimSpace(tokenExchangeHelpDesc),
+		},
+
 		{
 			Pattern: "lookup",
 
@@ -330,6 +412,33 @@ func (ts *TokenStore) paths() []*framework.Path {
 			HelpDescription: strings.TrimSpace(tokenLookupHelp),
 		},
 
+		{
+			Pattern: "introspect",
+
+			DisplayAttrs: &framework.DisplayAttributes{
+				OperationPrefix: operationPrefixToken,
+				OperationVerb:   "introspect",
+			},
+
+			Fields: map[string]*framework.FieldSchema{
+				"token": {
+					Type:        framework.TypeString,
+					Description: "Token to introspect",
+				},
+				"token_type_hint": {
+					Type:        framework.TypeString,
+					Description: "Hint about the type of the token, accepted for compatibility and ignored",
+				},
+			},
+
+			Callbacks: map[logical.Operation]framework.OperationFunc{
+				logical.UpdateOperation: ts.handleIntrospect,
+			},
+
+			HelpSynopsis:    strings.TrimSpace(tokenIntrospectHelp),
+			HelpDescription: strings.TrimSpace(tokenIntrospectHelpDesc),
+		},
+
 		{
 			Pattern: "lookup-accessor",
 
@@ -931,18 +1040,48 @@ func (ts *TokenStore) SaltID(ctx context.Context, id string) (string, error) {
 
 // rootToken is used to generate a new token with root privileges and no parent
 func (ts *TokenStore) rootToken(ctx context.Context) (*logical.TokenEntry, error) {
+	return ts.rootTokenWithLimits(ctx, 0, 0)
+}
+
+// rootTokenWithLimits is used to generate a new root token which expires
+// after ttl and can be used numUses times. Zero values mean no limit.
+func (ts *TokenStore) rootTokenWithLimits(ctx context.Context, ttl time.Duration, numUses int) (*logical.TokenEntry, error) {
 	ctx = namespace.ContextWithNamespace(ctx, namespace.RootNamespace)
 	te := &logical.TokenEntry{
-		Policies:     []string{"root"},
-		Path:         "auth/token/root",
-		DisplayName:  "root",
-		CreationTime: time.Now().Unix(),
-		NamespaceID:  namespace.RootNamespaceID,
-		Type:         logical.TokenTypeService,
+		Policies:       []string{"root"},
+		Path:           "auth/token/root",
+		DisplayName:    "root",
+		CreationTime:   time.Now().Unix(),
+		NamespaceID:    namespace.RootNamespaceID,
+		Type:           logical.TokenTypeService,
+		TTL:            ttl,
+		ExplicitMaxTTL: ttl,
+		NumUses:        numUses,
 	}
 	if err := ts.create(ctx, te); err != nil {
 		return nil, err
 	}
+
+	if ttl > 0 {
+		auth := &logical.Auth{
+			ClientToken:   te.ID,
+			Accessor:      te.Accessor,
+			DisplayName:   te.DisplayName,
+			Policies:      te.Policies,
+			TokenPolicies: te.Policies,
+			TokenType:     te.Type,
+			NumUses:       te.NumUses,
+			LeaseOptions: logical.LeaseOptions{
+				TTL:       ttl,
+				Renewable: false,
+			},
+			ExplicitMaxTTL: ttl,
+		}
+		if err := ts.expiration.RegisterAuth(ctx, te, auth, ""); err != nil {
+			ts.revokeOrphan(ctx, te.ID)
+			return nil, fmt.Errorf("failed to register root token lease: %w", err)
+		}
+	}
 	return te, nil
 }
 
@@ -953,7 +1092,7 @@ func (ts *TokenStore) tokenStoreAccessorList(ctx context.Context, req *logical.R
 	}
 	nsID := ns.ID
 
-	entries, err := ts.accessorView(ns).List(ctx, "")
+	entries, err := listTokenIndex(ctx, ts.accessorView(ns), "")
 	if err != nil {
 		return nil, err
 	}
@@ -1029,7 +1168,7 @@ func (ts *TokenStore) createAccessor(ctx context.Context, entry *logical.TokenEn
 		return fmt.Errorf("failed to marshal accessor index entry: %w", err)
 	}
 
-	le := &logical.StorageEntry{Key: saltID, Value: aEntryBytes}
+	le := &logical.StorageEntry{Key: tokenIndexShard(saltID) + saltID, Value: aEntryBytes}
 	if err := ts.accessorView(tokenNS).Put(ctx, le); err != nil {
 		return fmt.Errorf("failed to persist accessor index entry: %w", err)
 	}
@@ -1186,20 +1325,14 @@ func (ts *TokenStore) create(ctx context.Context, entry *logical.TokenEntry) err
 		}
 
 		var newestVersion *version.Version
-		var oneTen *version.Version
-
 		if ver != "" {
 			newestVersion, err = version.NewVersion(ver)
 			if err != nil {
 				return err
 			}
-			oneTen, err = version.NewVersion("1.10.0")
-			if err != nil {
-				return err
-			}
 		}
 
-		if ts.core.DisableSSCTokens() || (newestVersion != nil && newestVersion.LessThan(oneTen)) {
+		if ts.core.DisableSSCTokens() || (newestVersion != nil && newestVersion.LessThan(batchTokenPrefixVersion)) {
 			entry.ID = consts.LegacyBatchTokenPrefix + bEntry
 		} else {
 			entry.ID = consts.BatchTokenPrefix + bEntry
@@ -1346,7 +1479,7 @@ func (ts *TokenStore) storeCommon(ctx context.Context, entry *logical.TokenEntry
 				return err
 			}
 
-			path := parentSaltedID + "/" + saltedID
+			path := parentSaltedID + "/" + tokenIndexShard(saltedID) + saltedID
 			if tokenNS.ID != namespace.RootNamespaceID {
 				path = fmt.Sprintf("%s.%s", path, tokenNS.ID)
 			}
@@ -1846,13 +1979,19 @@ func (ts *TokenStore) revokeInternal(ctx context.Context, saltedID string, skipO
 			return err
 		}
 
-		path := parentSaltedID + "/" + saltedID
+		child := saltedID
 		if tokenNS.ID != namespace.RootNamespaceID {
-			path = fmt.Sprintf("%s.%s", path, tokenNS.ID)
+			child = fmt.Sprintf("%s.%s", child, tokenNS.ID)
 		}
 
-		if err = ts.parentView(parentNS).Delete(ctx, path); err != nil {
-			return fmt.Errorf("failed to delete entry: %w", err)
+		// The entry may have been written unsharded by an older version
+		for _, path := range []string{
+			parentSaltedID + "/" + tokenIndexShard(saltedID) + child,
+			parentSaltedID + "/" + child,
+		} {
+			if err = ts.parentView(parentNS).Delete(ctx, path); err != nil {
+				return fmt.Errorf("failed to delete entry: %w", err)
+			}
 		}
 	}
 
@@ -1863,8 +2002,11 @@ func (ts *TokenStore) revokeInternal(ctx context.Context, saltedID string, skipO
 			return err
 		}
 
-		if err = ts.accessorView(tokenNS).Delete(ctx, accessorSaltedID); err != nil {
-			return fmt.Errorf("failed to delete entry: %w", err)
+		// The entry may have been written unsharded by an older version
+		for _, key := range []string{tokenIndexShard(accessorSaltedID) + accessorSaltedID, accessorSaltedID} {
+			if err = ts.accessorView(tokenNS).Delete(ctx, key); err != nil {
+				return fmt.Errorf("failed to delete entry: %w", err)
+			}
 		}
 	}
 
@@ -1876,14 +2018,14 @@ func (ts *TokenStore) revokeInternal(ctx context.Context, saltedID string, skipO
 		// revokeTreeInternal to avoid unnecessary view.List operations. Since
 		// the deletion occurs in a DFS fashion we don't need to perform a delete
 		// on child prefixes as there will be none (as saltedID entry is a leaf node).
-		children, err := ts.parentView(tokenNS).List(ctx, saltedID+"/")
+		children, err := listTokenIndex(ctx, ts.parentView(tokenNS), saltedID+"/")
 		if err != nil {
 			return fmt.Errorf("failed to scan for children: %w", err)
 		}
-		for _, child := range children {
-			var childNSID string
+		for _, childKey := range children {
+			childKey = saltedID + "/" + childKey
+			child, childNSID := namespace.SplitIDFromString(tokenIndexKeyID(childKey))
 			childCtx := revokeCtx
-			child, childNSID = namespace.SplitIDFromString(child)
 			if childNSID != "" {
 				childNS, err := NamespaceByID(ctx, childNSID, ts.core)
 				if err != nil {
@@ -1902,7 +2044,7 @@ func (ts *TokenStore) revokeInternal(ctx context.Context, saltedID string, skipO
 			}
 			if entry == nil {
 				// Seems it's already revoked, so nothing to do here except delete the index
-				err = ts.parentView(tokenNS).Delete(ctx, child)
+				err = ts.parentView(tokenNS).Delete(ctx, childKey)
 				if err != nil {
 					return fmt.Errorf("failed to delete child entry: %w", err)
 				}
@@ -1922,9 +2064,9 @@ func (ts *TokenStore) revokeInternal(ctx context.Context, saltedID string, skipO
 
 			// Delete the the child storage entry after we update the token entry Since
 			// paths are not deeply nested (i.e. they are simply
-			// parenPrefix/<parentID>/<childID>), we can simply call view.Delete instead
-			// of logical.ClearView
-			err = ts.parentView(tokenNS).Delete(ctx, child)
+			// parenPrefix/<parentID>/<shard>/<childID>), we can simply call
+			// view.Delete instead of logical.ClearView
+			err = ts.parentView(tokenNS).Delete(ctx, childKey)
 			if err != nil {
 				return fmt.Errorf("failed to delete child entry: %w", err)
 			}
@@ -2007,7 +2149,7 @@ func (ts *TokenStore) revokeTreeInternal(ctx context.Context, id string) error {
 		}
 
 		path := saltedID + "/"
-		childrenRaw, err := ts.parentView(saltedNS).List(saltedCtx, path)
+		childrenRaw, err := listTokenIndex(saltedCtx, ts.parentView(saltedNS), path)
 		if err != nil {
 			return fmt.Errorf("failed to scan for children: %w", err)
 		}
@@ -2015,11 +2157,12 @@ func (ts *TokenStore) revokeTreeInternal(ctx context.Context, id string) error {
 		// Filter the child list to remove any items that have ever been in the dfs stack.
 		// This is a robustness check, as a parent/child cycle can lead to an OOM crash.
 		children := make([]string, 0, len(childrenRaw))
-		for _, child := range childrenRaw {
+		for _, childKey := range childrenRaw {
+			child := tokenIndexKeyID(childKey)
 			if _, seen := seenIDs[child]; !seen {
 				children = append(children, child)
 			} else {
-				if err = ts.parentView(saltedNS).Delete(saltedCtx, path+child); err != nil {
+				if err = ts.parentView(saltedNS).Delete(saltedCtx, path+childKey); err != nil {
 					return fmt.Errorf("failed to delete entry: %w", err)
 				}
 
@@ -2063,7 +2206,7 @@ func (ts *TokenStore) handleCreateAgainstRole(ctx context.Context, req *logical.
 		return logical.ErrorResponse(fmt.Sprintf("unknown role %s", name)), nil
 	}
 
-	return ts.handleCreateCommon(ctx, req, d, false, roleEntry)
+	return ts.handleCreateCommon(ctx, req, d, false, roleEntry, nil)
 }
 
 func (ts *TokenStore) lookupByAccessor(ctx context.Context, id string, salted, tainted bool) (*accessorEntry, error) {
@@ -2102,9 +2245,22 @@ func (ts *TokenStore) lookupByAccessor(ctx context.Context, id string, salted, t
 		}
 	}
 
-	entry, err := ts.accessorView(ns).Get(ctx, lookupID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read index using accessor: %w", err)
+	// Salted IDs listed from the index are keys of sharded entries, or of
+	// unsharded entries written by older versions
+	keys := []string{lookupID}
+	if !strings.Contains(lookupID, "/") {
+		keys = []string{tokenIndexShard(lookupID) + lookupID, lookupID}
+	}
+
+	var entry *logical.StorageEntry
+	for _, key := range keys {
+		entry, err = ts.accessorView(ns).Get(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read index using accessor: %w", err)
+		}
+		if entry != nil {
+			break
+		}
 	}
 	if entry == nil {
 		return nil, nil
@@ -2164,7 +2320,7 @@ func (ts *TokenStore) handleTidy(ctx context.Context, req *logical.Request, data
 			quitCtx := namespace.ContextWithNamespace(ts.quitContext, ns)
 
 			// List out all the accessors
-			saltedAccessorList, err := ts.accessorView(ns).List(quitCtx, "")
+			saltedAccessorList, err := listTokenIndex(quitCtx, ts.accessorView(ns), "")
 			if err != nil {
 				return fmt.Errorf("failed to fetch accessor index entries: %w", err)
 			}
@@ -2195,7 +2351,7 @@ func (ts *TokenStore) handleTidy(ctx context.Context, req *logical.Request, data
 				countParentEntries++
 
 				// Get the children
-				children, err := ts.parentView(ns).List(quitCtx, parent)
+				children, err := listTokenIndex(quitCtx, ts.parentView(ns), parent)
 				if err != nil {
 					tidyErrors = multierror.Append(tidyErrors, fmt.Errorf("failed to read secondary index: %w", err))
 					continue
@@ -2211,7 +2367,8 @@ func (ts *TokenStore) handleTidy(ctx context.Context, req *logical.Request, data
 				}
 
 				var deletedChildrenCount int64
-				for index, child := range children {
+				for index, childKey := range children {
+					child := tokenIndexKeyID(childKey)
 					countParentList++
 					if countParentList%500 == 0 {
 						percentComplete := float64(index) / float64(len(children)) * 100
@@ -2240,7 +2397,7 @@ func (ts *TokenStore) handleTidy(ctx context.Context, req *logical.Request, data
 					// Otherwise, if the entry doesn't exist, or if the parent doesn't exist go
 					// on with the delete on the secondary index
 					if te == nil || exists == nil {
-						index := parent + child
+						index := parent + childKey
 						ts.logger.Debug("deleting invalid secondary index", "index", index)
 						err = ts.parentView(ns).Delete(quitCtx, index)
 						if err != nil {
@@ -2564,17 +2721,159 @@ func (ts *TokenStore) handleUpdateRevokeAccessor(ctx context.Context, req *logic
 // handleCreate handles the auth/token/create path for creation of new orphan
 // tokens
 func (ts *TokenStore) handleCreateOrphan(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
-	return ts.handleCreateCommon(ctx, req, d, true, nil)
+	return ts.handleCreateCommon(ctx, req, d, true, nil, nil)
 }
 
 // handleCreate handles the auth/token/create path for creation of new non-orphan
 // tokens
 func (ts *TokenStore) handleCreate(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
-	return ts.handleCreateCommon(ctx, req, d, false, nil)
+	return ts.handleCreateCommon(ctx, req, d, false, nil, nil)
+}
+
+// handleExchange handles the auth/token/exchange path, which exchanges the
+// client token for a child token with a subset of its policies and a TTL
+// that does not exceed its own, in the spirit of OAuth 2.0 Token Exchange
+// (RFC 8693).
+func (ts *TokenStore) handleExchange(ctx context.Context, req *logical.Request, d *framework.FieldData) (*logical.Response, error) {
+	if subject := d.Get("subject_token").(string); subject != "" && subject != req.ClientToken {
+		return logical.ErrorResponse("subject_token must be the token used to authenticate the request"), logical.ErrInvalidRequest
+	}
+	for _, field := range []string{"subject_token_type", "requested_token_type"} {
+		if t := d.Get(field).(string); t != "" && t != tokenExchangeTokenType {
+			return logical.ErrorResponse("unsupported %s %q", field, t), logical.ErrInvalidRequest
+		}
+	}
+
+	subject, err := ts.Lookup(ctx, req.ClientToken)
+	if err != nil {
+		return nil, fmt.Errorf("subject token lookup failed: %w", err)
+	}
+	if subject == nil {
+		return logical.ErrorResponse("subject token lookup failed: no token found"), logical.ErrInvalidRequest
+	}
+
+	policies := d.Get("policies").([]string)
+	policies = append(policies, strings.Fields(d.Get("scope").(string))...)
+	if len(policies) == 0 {
+		return logical.ErrorResponse("at least one policy must be requested using 'policies' or 'scope'"), logical.ErrInvalidRequest
+	}
+
+	// Unlike token creation, sudo privileges do not allow requesting
+	// policies that the subject token does not have. Only root tokens may
+	// exchange for arbitrary policies.
+	subjectPolicies := policyutil.SanitizePolicies(subject.Policies, policyutil.DoNotAddDefaultPolicy)
+	policies = policyutil.SanitizePolicies(policies, policyutil.DoNotAddDefaultPolicy)
+	if !strutil.StrListContains(subjectPolicies, "root") && !strutil.StrListSubset(subjectPolicies, policies) {
+		return logical.ErrorResponse("requested policies must be a subset of the subject token policies"), logical.ErrInvalidRequest
+	}
+
+	// Exchanged tokens can only be restricted further
+	var allowedPaths []string
+	for _, path := range d.Get("paths").([]string) {
+		path = strings.TrimPrefix(path, "/")
+		if path == "" || strings.Contains(strings.TrimSuffix(path, "*"), "*") {
+			return logical.ErrorResponse("invalid path %q: paths may only end with a * wildcard", path), logical.ErrInvalidRequest
+		}
+		allowedPaths = append(allowedPaths, path)
+	}
+	if subjectPaths := subject.InternalMeta[tokenExchangeAllowedPathsInternalMeta]; subjectPaths != "" {
+		subjectAllowedPaths := strings.Split(subjectPaths, ",")
+		if len(allowedPaths) == 0 {
+			allowedPaths = subjectAllowedPaths
+		}
+		for _, path := range allowedPaths {
+			if !tokenPathPatternAllowed(subjectAllowedPaths, path) {
+				return logical.ErrorResponse("requested paths must be within the paths of the subject token"), logical.ErrInvalidRequest
+			}
+		}
+	}
+
+	ttl := time.Duration(d.Get("ttl").(int)) * time.Second
+	if subject.TTL > 0 {
+		times, err := ts.expiration.FetchLeaseTimesByToken(ctx, subject)
+		if err != nil {
+			return nil, err
+		}
+		if times != nil && !times.ExpireTime.IsZero() {
+			remaining := time.Until(times.ExpireTime).Truncate(time.Second)
+			switch {
+			case remaining <= 0:
+				return logical.ErrorResponse("subject token has expired"), logical.ErrInvalidRequest
+			case ttl == 0:
+				ttl = remaining
+			case ttl > remaining:
+				return logical.ErrorResponse("requested ttl exceeds the remaining ttl of the subject token"), logical.ErrInvalidRequest
+			}
+		}
+	}
+
+	createData := &framework.FieldData{
+		Raw: map[string]interface{}{
+			"policies":          policies,
+			"no_default_policy": !strutil.StrListContains(policies, "default"),
+			"renewable":         false,
+			"num_uses":          d.Get("num_uses").(int),
+			"display_name":      "exchange",
+			"meta": map[string]interface{}{
+				tokenExchangeSubjectAccessorMeta: subject.Accessor,
+			},
+		},
+		Schema: ts.Backend.Route("create").Fields,
+	}
+	if ttl > 0 {
+		createData.Raw["ttl"] = ttl.String()
+		createData.Raw["explicit_max_ttl"] = ttl.String()
+	}
+
+	resp, err := ts.handleCreateCommon(ctx, req, createData, false, nil, &tokenExchangeRestriction{allowedPaths: allowedPaths})
+	if err != nil || resp == nil || resp.IsError() || resp.Auth == nil {
+		return resp, err
+	}
+
+	resp.Data = map[string]interface{}{
+		"issued_token_type": tokenExchangeTokenType,
+		"token_type":        "N_A",
+		"scope":             strings.Join(resp.Auth.Policies, " "),
+		"expires_in":        int64(resp.Auth.TTL.Seconds()),
+	}
+	if len(allowedPaths) > 0 {
+		resp.Data["paths"] = allowedPaths
+	}
+	return resp, nil
+}
+
+// tokenPathAllowed returns whether the request path matches one of the path
+// patterns, which may end with a * wildcard.
+func tokenPathAllowed(patterns []string, path string) bool {
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+		} else if path == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenPathPatternAllowed returns whether all the paths matching the pattern
+// match one of the path patterns.
+func tokenPathPatternAllowed(patterns []string, pattern string) bool {
+	prefix, ok := strings.CutSuffix(pattern, "*")
+	if !ok {
+		return tokenPathAllowed(patterns, pattern)
+	}
+	for _, p := range patterns {
+		if pp, ok := strings.CutSuffix(p, "*"); ok && strings.HasPrefix(prefix, pp) {
+			return true
+		}
+	}
+	return false
 }
 
 // handleCreateCommon handles the auth/token/create path for creation of new tokens
-func (ts *TokenStore) handleCreateCommon(ctx context.Context, req *logical.Request, d *framework.FieldData, orphan bool, role *tsRoleEntry) (*logical.Response, error) {
+func (ts *TokenStore) handleCreateCommon(ctx context.Context, req *logical.Request, d *framework.FieldData, orphan bool, role *tsRoleEntry, exchange *tokenExchangeRestriction) (*logical.Response, error) {
 	// Read the parent policy
 	parent, err := ts.Lookup(ctx, req.ClientToken)
 	if err != nil {
@@ -3011,6 +3310,34 @@ func (ts *TokenStore) handleCreateCommon(ctx context.Context, req *logical.Reque
 		if role == nil {
 			te.BoundCIDRs = parent.BoundCIDRs
 		}
+
+		// Children of a token bound to a client certificate are bound to
+		// the same certificate
+		te.BoundCertThumbprint = parent.BoundCertThumbprint
+	}
+
+	// Exchanged tokens don't inherit the identity policies of their entity,
+	// which would defeat the downscoping, and may be restricted to a subset
+	// of paths. Tokens created by exchanged tokens keep these restrictions.
+	allowedPaths, exchanged := parent.InternalMeta[tokenExchangeAllowedPathsInternalMeta]
+	if exchange != nil {
+		allowedPaths, exchanged = strings.Join(exchange.allowedPaths, ","), true
+	}
+	if exchanged {
+		te.NoIdentityPolicies = true
+		te.InternalMeta = map[string]string{
+			tokenExchangeAllowedPathsInternalMeta: allowedPaths,
+		}
+	}
+
+	if d.Get("bind_client_cert").(bool) {
+		te.BoundCertThumbprint = clientCertThumbprint(req.Connection)
+		if te.BoundCertThumbprint == "" {
+			return logical.ErrorResponse("a client certificate is required to bind the token to it"), logical.ErrInvalidRequest
+		}
+	}
+	if te.BoundCertThumbprint != "" && te.Type == logical.TokenTypeBatch {
+		return logical.ErrorResponse("batch tokens cannot be bound to a client certificate"), logical.ErrInvalidRequest
 	}
 
 	var explicitMaxTTLToUse time.Duration
@@ -3347,6 +3674,10 @@ func (ts *TokenStore) handleLookup(ctx context.Context, req *logical.Request, da
 		resp.Data["bound_cidrs"] = out.BoundCIDRs
 	}
 
+	if out.BoundCertThumbprint != "" {
+		resp.Data["bound_cert_thumbprint"] = out.BoundCertThumbprint
+	}
+
 	tokenNS, err := NamespaceByID(ctx, out.NamespaceID, ts.core)
 	if err != nil {
 		return logical.ErrorResponse(err.Error()), logical.ErrInvalidRequest
@@ -3393,6 +3724,75 @@ func (ts *TokenStore) handleLookup(ctx context.Context, req *logical.Request, da
 	return resp, nil
 }
 
+// handleIntrospect handles the auth/token/introspect path, describing a
+// token as an OAuth 2.0 Token Introspection (RFC 7662) response. Unknown,
+// malformed and expired tokens are reported as inactive rather than failing.
+func (ts *TokenStore) handleIntrospect(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
+	id := data.Get("token").(string)
+	if id == "" {
+		return logical.ErrorResponse("missing token"), logical.ErrInvalidRequest
+	}
+
+	lock := locksutil.LockForKey(ts.tokenLocks, id)
+	lock.RLock()
+	defer lock.RUnlock()
+
+	out, err := ts.lookupInternal(ctx, id, false, false)
+	if err != nil || out == nil {
+		return introspectionResponse(nil)
+	}
+
+	claims := map[string]interface{}{
+		"scope":      strings.Join(out.Policies, " "),
+		"token_type": "Bearer",
+		"username":   out.DisplayName,
+		"iat":        out.CreationTime,
+		"jti":        out.Accessor,
+	}
+	if out.EntityID != "" {
+		claims["sub"] = out.EntityID
+	}
+
+	leaseTimes, err := ts.expiration.FetchLeaseTimesByToken(ctx, out)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case leaseTimes != nil && !leaseTimes.ExpireTime.IsZero():
+		claims["exp"] = leaseTimes.ExpireTime.Unix()
+	case out.Type == logical.TokenTypeBatch && out.TTL != 0:
+		claims["exp"] = time.Unix(out.CreationTime, 0).Add(out.TTL).Unix()
+	}
+
+	return introspectionResponse(claims)
+}
+
+// introspectionResponse returns an OAuth 2.0 Token Introspection response
+// with the given claims, or for an inactive token if claims is nil. For
+// details, see https://datatracker.ietf.org/doc/html/rfc7662#section-2.2
+func introspectionResponse(claims map[string]interface{}) (*logical.Response, error) {
+	response := map[string]interface{}{
+		"active": claims != nil,
+	}
+	for k, v := range claims {
+		response[k] = v
+	}
+
+	body, err := json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &logical.Response{
+		Data: map[string]interface{}{
+			logical.HTTPStatusCode:         http.StatusOK,
+			logical.HTTPRawBody:            body,
+			logical.HTTPContentType:        "application/json",
+			logical.HTTPCacheControlHeader: "no-store",
+		},
+	}, nil
+}
+
 func (ts *TokenStore) handleRenewSelf(ctx context.Context, req *logical.Request, data *framework.FieldData) (*logical.Response, error) {
 	data.Raw["token"] = req.ClientToken
 	return ts.handleRenew(ctx, req, data)
@@ -4142,13 +4542,24 @@ it.
 Client tokens are used to identify a client and to allow OpenBao to associate policies and ACLs
 which are enforced on every request. This backend also allows for generating sub-tokens as well
 as revocation of tokens. The tokens are renewable if associated with a lease.`
-	tokenCreateHelp          = `The token create path is used to create new tokens.`
-	tokenCreateOrphanHelp    = `The token create path is used to create new orphan tokens.`
-	tokenCreateRoleHelp      = `This token create path is used to create new tokens adhering to the given role.`
-	tokenListRolesHelp       = `This endpoint lists configured roles.`
-	tokenLookupAccessorHelp  = `This endpoint will lookup a token associated with the given accessor and its properties. Response will not contain the token ID.`
-	tokenRenewAccessorHelp   = `This endpoint will renew a token associated with the given accessor and its properties. Response will not contain the token ID.`
-	tokenLookupHelp          = `This endpoint will lookup a token and its properties.`
+	tokenExchangeHelp     = `This endpoint exchanges the token used to call it for a child token with reduced privileges.`
+	tokenExchangeHelpDesc = `This endpoint exchanges the token used to call it for a child token,
+in the spirit of OAuth 2.0 Token Exchange (RFC 8693). The new token is
+limited to a subset of the policies of the calling token and cannot outlive
+it. The new token is not renewable, and the accessor of the calling token is
+recorded in its metadata.`
+	tokenCreateHelp         = `The token create path is used to create new tokens.`
+	tokenCreateOrphanHelp   = `The token create path is used to create new orphan tokens.`
+	tokenCreateRoleHelp     = `This token create path is used to create new tokens adhering to the given role.`
+	tokenListRolesHelp      = `This endpoint lists configured roles.`
+	tokenLookupAccessorHelp = `This endpoint will lookup a token associated with the given accessor and its properties. Response will not contain the token ID.`
+	tokenRenewAccessorHelp  = `This endpoint will renew a token associated with the given accessor and its properties. Response will not contain the token ID.`
+	tokenLookupHelp         = `This endpoint will lookup a token and its properties.`
+	tokenIntrospectHelp     = `This endpoint describes a token in the OAuth 2.0 Token Introspection format.`
+	tokenIntrospectHelpDesc = `This endpoint describes the given token as an OAuth 2.0 Token Introspection
+(RFC 7662) response, so that third-party gateways can validate tokens with a
+standard call. Unknown, malformed and expired tokens are reported with an
+"active" value of false.`
 	tokenPathRolesHelp       = `This endpoint allows creating, reading, and deleting roles.`
 	tokenRevokeAccessorHelp  = `This endpoint will delete the token associated with the accessor and all of its child tokens.`
 	tokenRevokeHelp          = `This endpoint will delete the given token and all of its child tokens.`
@@ -7,6 +7,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"path"
 	"reflect"
 	"sort"
@@ -814,8 +815,13 @@ func TestTokenStore_HandleRequest_ListAccessors(t *testing.T) {
 		if err != nil {
 			t.Fatal(err)
 		}
+		// Move the entry back to the unsharded layout of older versions
+		view := ts.accessorView(namespace.RootNamespace)
+		if err := view.Delete(namespace.RootContext(nil), tokenIndexShard(saltID)+saltID); err != nil {
+			t.Fatalf("failed to delete accessor index entry: %v", err)
+		}
 		le := &logical.StorageEntry{Key: saltID, Value: []byte(aEntry.TokenID)}
-		if err := ts.accessorView(namespace.RootNamespace).Put(namespace.RootContext(nil), le); err != nil {
+		if err := view.Put(namespace.RootContext(nil), le); err != nil {
 			t.Fatalf("failed to persist accessor index entry: %v", err)
 		}
 	}
@@ -1397,6 +1403,91 @@ func TestTokenStore_Revoke_Orphan(t *testing.T) {
 	deepEqualTokenEntries(t, out, ent2)
 }
 
+func TestTokenStore_IndexSharding(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+	ts := c.tokenStore
+	ctx := namespace.RootContext(nil)
+
+	ent := &logical.TokenEntry{
+		NamespaceID: namespace.RootNamespaceID,
+		Path:        "test",
+		Policies:    []string{"dev", "ops"},
+		TTL:         time.Hour,
+	}
+	testMakeTokenDirectly(t, ts, ent)
+
+	var children []*logical.TokenEntry
+	for i := 0; i < 2; i++ {
+		child := &logical.TokenEntry{
+			NamespaceID: namespace.RootNamespaceID,
+			Parent:      ent.ID,
+			TTL:         time.Hour,
+		}
+		testMakeTokenDirectly(t, ts, child)
+		children = append(children, child)
+	}
+
+	saltedAccessor, err := ts.SaltID(ctx, ent.Accessor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := ts.accessorView(namespace.RootNamespace).Get(ctx, tokenIndexShard(saltedAccessor)+saltedAccessor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out == nil {
+		t.Fatal("expected a sharded accessor index entry")
+	}
+
+	saltedParent, err := ts.SaltID(ctx, ent.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saltedChild, err := ts.SaltID(ctx, children[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shardedKey := saltedParent + "/" + tokenIndexShard(saltedChild) + saltedChild
+	out, err = ts.parentView(namespace.RootNamespace).Get(ctx, shardedKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out == nil {
+		t.Fatal("expected a sharded parent index entry")
+	}
+
+	// Move the parent index entry of the first child to the unsharded layout
+	// of older versions, it must still be revoked along with its parent
+	if err := ts.parentView(namespace.RootNamespace).Delete(ctx, shardedKey); err != nil {
+		t.Fatal(err)
+	}
+	le := &logical.StorageEntry{Key: saltedParent + "/" + saltedChild}
+	if err := ts.parentView(namespace.RootNamespace).Put(ctx, le); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ts.revokeTreeInternal(ctx, saltedParent); err != nil {
+		t.Fatal(err)
+	}
+	for _, child := range children {
+		out, err := ts.Lookup(ctx, child.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out != nil {
+			t.Fatalf("expected child token to be revoked: %#v", out)
+		}
+	}
+
+	keys, err := ts.parentView(namespace.RootNamespace).List(ctx, saltedParent+"/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 0 {
+		t.Fatalf("expected the parent index to be cleaned up, got %v", keys)
+	}
+}
+
 // This was the original function name, and now it just calls
 // the non recursive version for a variety of depths.
 func TestTokenStore_RevokeTree(t *testing.T) {
@@ -1948,6 +2039,265 @@ func TestTokenStore_HandleRequest_CreateToken_NonRoot_InvalidSubset(t *testing.T
 	}
 }
 
+func TestTokenStore_HandleRequest_Exchange(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ts := c.tokenStore
+	testMakeServiceTokenViaBackend(t, ts, root, "client", "1h", []string{"foo", "bar"})
+	subject, err := ts.Lookup(namespace.RootContext(nil), "client")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := logical.TestRequest(t, logical.UpdateOperation, "exchange")
+	req.ClientToken = "client"
+	req.Data["subject_token"] = "client"
+	req.Data["subject_token_type"] = tokenExchangeTokenType
+	req.Data["scope"] = "foo"
+	resp := testMakeTokenViaRequest(t, ts, req)
+	if resp.IsError() {
+		t.Fatalf("bad: %#v", resp)
+	}
+	if resp.Data["scope"] != "foo" || resp.Data["issued_token_type"] != tokenExchangeTokenType {
+		t.Fatalf("bad: %#v", resp.Data)
+	}
+	if resp.Auth.TTL <= 0 || resp.Auth.TTL > time.Hour {
+		t.Fatalf("bad: ttl %v", resp.Auth.TTL)
+	}
+
+	te, err := ts.Lookup(namespace.RootContext(nil), resp.Auth.ClientToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if te.Parent != "client" || te.Meta[tokenExchangeSubjectAccessorMeta] != subject.Accessor {
+		t.Fatalf("bad: %#v", te)
+	}
+	if !reflect.DeepEqual(te.Policies, []string{"foo"}) || te.ExplicitMaxTTL != resp.Auth.TTL {
+		t.Fatalf("bad: %#v", te)
+	}
+
+	// The exchanged token cannot outlive the subject token.
+	req = logical.TestRequest(t, logical.UpdateOperation, "exchange")
+	req.ClientToken = "client"
+	req.Data["policies"] = []string{"foo"}
+	req.Data["ttl"] = "2h"
+	resp, err = ts.HandleRequest(namespace.RootContext(nil), req)
+	if err != logical.ErrInvalidRequest {
+		t.Fatalf("err: %v resp: %#v", err, resp)
+	}
+
+	// Policies must be a subset of the subject token policies.
+	req = logical.TestRequest(t, logical.UpdateOperation, "exchange")
+	req.ClientToken = "client"
+	req.Data["policies"] = []string{"foo", "baz"}
+	resp, err = ts.HandleRequest(namespace.RootContext(nil), req)
+	if err != logical.ErrInvalidRequest {
+		t.Fatalf("err: %v resp: %#v", err, resp)
+	}
+
+	// The subject token must be the calling token.
+	req = logical.TestRequest(t, logical.UpdateOperation, "exchange")
+	req.ClientToken = "client"
+	req.Data["subject_token"] = root
+	req.Data["policies"] = []string{"foo"}
+	resp, err = ts.HandleRequest(namespace.RootContext(nil), req)
+	if err != logical.ErrInvalidRequest {
+		t.Fatalf("err: %v resp: %#v", err, resp)
+	}
+}
+
+func TestTokenStore_HandleRequest_Exchange_IdentityPolicies(t *testing.T) {
+	ctx := namespace.RootContext(nil)
+	i, _, c := testIdentityStoreWithAppRoleAuth(ctx, t)
+
+	for _, p := range []string{`
+name = "tokenpolicy"
+path "secret/token" {
+	capabilities = ["read"]
+}
+`, `
+name = "grouppolicy"
+path "secret/group" {
+	capabilities = ["read"]
+}
+`} {
+		policy, _ := ParseACLPolicy(namespace.RootNamespace, p)
+		if err := c.policyStore.SetPolicy(ctx, policy); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp, err := i.HandleRequest(ctx, &logical.Request{
+		Path:      "entity",
+		Operation: logical.UpdateOperation,
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %#v", resp, err)
+	}
+	entityID := resp.Data["id"].(string)
+	resp, err = i.HandleRequest(ctx, &logical.Request{
+		Path:      "group",
+		Operation: logical.UpdateOperation,
+		Data: map[string]interface{}{
+			"member_entity_ids": []string{entityID},
+			"policies":          "grouppolicy",
+		},
+	})
+	if err != nil || (resp != nil && resp.IsError()) {
+		t.Fatalf("bad: resp: %#v\nerr: %#v", resp, err)
+	}
+
+	testMakeTokenDirectly(t, c.tokenStore, &logical.TokenEntry{
+		ID:       "subject",
+		Path:     "auth/token/create",
+		Policies: []string{"tokenpolicy"},
+		EntityID: entityID,
+		TTL:      time.Hour,
+	})
+	capabilities, err := c.Capabilities(ctx, "subject", "secret/group")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(capabilities, []string{"read"}) {
+		t.Fatalf("bad: %v", capabilities)
+	}
+
+	// The exchanged token keeps the entity of the subject token, but not the
+	// policies of its groups.
+	req := logical.TestRequest(t, logical.UpdateOperation, "exchange")
+	req.ClientToken = "subject"
+	req.Data["policies"] = []string{"tokenpolicy"}
+	resp = testMakeTokenViaRequest(t, c.tokenStore, req)
+	if resp.IsError() {
+		t.Fatalf("bad: %#v", resp)
+	}
+	exchanged := resp.Auth.ClientToken
+	te, err := c.tokenStore.Lookup(ctx, exchanged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if te.EntityID != entityID || !te.NoIdentityPolicies {
+		t.Fatalf("bad: %#v", te)
+	}
+	capabilities, err = c.Capabilities(ctx, exchanged, "secret/group")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(capabilities, []string{DenyCapability}) {
+		t.Fatalf("bad: %v", capabilities)
+	}
+	capabilities, err = c.Capabilities(ctx, exchanged, "secret/token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(capabilities, []string{"read"}) {
+		t.Fatalf("bad: %v", capabilities)
+	}
+}
+
+func TestTokenStore_HandleRequest_Exchange_Paths(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ts := c.tokenStore
+	ctx := namespace.RootContext(nil)
+
+	policy, _ := ParseACLPolicy(namespace.RootNamespace, `
+path "secret/*" {
+	capabilities = ["read"]
+}
+path "auth/token/create" {
+	capabilities = ["update"]
+}
+path "auth/token/exchange" {
+	capabilities = ["update"]
+}
+`)
+	policy.Name = "secretpolicy"
+	if err := c.policyStore.SetPolicy(ctx, policy); err != nil {
+		t.Fatal(err)
+	}
+	testMakeServiceTokenViaBackend(t, ts, root, "client", "1h", []string{"secretpolicy"})
+
+	checkPath := func(token, path string) error {
+		t.Helper()
+		_, _, err := c.CheckToken(ctx, &logical.Request{
+			Operation:   logical.ReadOperation,
+			Path:        path,
+			ClientToken: token,
+		}, false)
+		return err
+	}
+
+	// Paths may only end with a wildcard.
+	req := logical.TestRequest(t, logical.UpdateOperation, "exchange")
+	req.ClientToken = "client"
+	req.Data["policies"] = []string{"secretpolicy"}
+	req.Data["paths"] = []string{"secret/*/config"}
+	resp, err := ts.HandleRequest(ctx, req)
+	if err != logical.ErrInvalidRequest {
+		t.Fatalf("err: %v resp: %#v", err, resp)
+	}
+
+	req = logical.TestRequest(t, logical.UpdateOperation, "exchange")
+	req.ClientToken = "client"
+	req.Data["policies"] = []string{"secretpolicy"}
+	req.Data["paths"] = []string{"secret/app/*", "auth/token/create", "auth/token/exchange"}
+	resp = testMakeTokenViaRequest(t, ts, req)
+	if resp.IsError() {
+		t.Fatalf("bad: %#v", resp)
+	}
+	exchanged := resp.Auth.ClientToken
+
+	if err := checkPath(exchanged, "secret/app/db"); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if err := checkPath(exchanged, "secret/other"); err != logical.ErrPermissionDenied {
+		t.Fatalf("err: %v", err)
+	}
+	if err := checkPath("client", "secret/other"); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Tokens created by the exchanged token are bound to the same paths.
+	req = logical.TestRequest(t, logical.UpdateOperation, "create")
+	req.ClientToken = exchanged
+	req.Data["policies"] = []string{"secretpolicy"}
+	resp = testMakeTokenViaRequest(t, ts, req)
+	if resp.IsError() {
+		t.Fatalf("bad: %#v", resp)
+	}
+	child := resp.Auth.ClientToken
+	if err := checkPath(child, "secret/app/db"); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if err := checkPath(child, "secret/other"); err != logical.ErrPermissionDenied {
+		t.Fatalf("err: %v", err)
+	}
+
+	// Exchanging the exchanged token can only restrict its paths further.
+	req = logical.TestRequest(t, logical.UpdateOperation, "exchange")
+	req.ClientToken = exchanged
+	req.Data["policies"] = []string{"secretpolicy"}
+	req.Data["paths"] = []string{"secret/*"}
+	resp, err = ts.HandleRequest(ctx, req)
+	if err != logical.ErrInvalidRequest {
+		t.Fatalf("err: %v resp: %#v", err, resp)
+	}
+
+	req = logical.TestRequest(t, logical.UpdateOperation, "exchange")
+	req.ClientToken = exchanged
+	req.Data["policies"] = []string{"secretpolicy"}
+	req.Data["paths"] = []string{"secret/app/db"}
+	resp = testMakeTokenViaRequest(t, ts, req)
+	if resp.IsError() {
+		t.Fatalf("bad: %#v", resp)
+	}
+	if err := checkPath(resp.Auth.ClientToken, "secret/app/db"); err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if err := checkPath(resp.Auth.ClientToken, "secret/app/other"); err != logical.ErrPermissionDenied {
+		t.Fatalf("err: %v", err)
+	}
+}
+
 func TestTokenStore_HandleRequest_CreateToken_NonRoot_RootChild(t *testing.T) {
 	core, _, root := TestCoreUnsealed(t)
 	ts := core.tokenStore
@@ -2420,6 +2770,86 @@ func TestTokenStore_HandleRequest_Lookup(t *testing.T) {
 	})
 }
 
+func TestTokenStore_HandleRequest_Introspect(t *testing.T) {
+	t.Run("service_token", func(t *testing.T) {
+		testTokenStoreHandleRequestIntrospect(t, false)
+	})
+
+	t.Run("batch_token", func(t *testing.T) {
+		testTokenStoreHandleRequestIntrospect(t, true)
+	})
+}
+
+func testTokenStoreHandleRequestIntrospect(t *testing.T, batch bool) {
+	c, _, root := TestCoreUnsealed(t)
+	ts := c.tokenStore
+
+	outAuth := new(logical.Auth)
+	testMakeTokenViaCore(t, c, root, "client", "3600s", "", []string{"foo"}, batch, outAuth)
+	token := "client"
+	if batch {
+		token = outAuth.ClientToken
+	}
+
+	introspect := func(token string) map[string]interface{} {
+		req := logical.TestRequest(t, logical.UpdateOperation, "introspect")
+		req.Data = map[string]interface{}{
+			"token": token,
+		}
+		resp, err := ts.HandleRequest(namespace.RootContext(nil), req)
+		if err != nil || (resp != nil && resp.IsError()) {
+			t.Fatalf("err: %v\nresp: %#v", err, resp)
+		}
+		if resp.Data[logical.HTTPStatusCode] != http.StatusOK {
+			t.Fatalf("bad: %#v", resp)
+		}
+		var body map[string]interface{}
+		if err := json.Unmarshal(resp.Data[logical.HTTPRawBody].([]byte), &body); err != nil {
+			t.Fatal(err)
+		}
+		return body
+	}
+
+	body := introspect(token)
+	if body["active"] != true {
+		t.Fatalf("expected an active token: %#v", body)
+	}
+	if body["scope"] != "default foo" {
+		t.Fatalf("bad scope: %#v", body)
+	}
+	if body["jti"] != outAuth.Accessor {
+		t.Fatalf("bad jti: %#v", body)
+	}
+	if body["token_type"] != "Bearer" {
+		t.Fatalf("bad token_type: %#v", body)
+	}
+	exp, ok := body["exp"].(float64)
+	if !ok || exp < float64(time.Now().Add(3500*time.Second).Unix()) {
+		t.Fatalf("bad exp: %#v", body)
+	}
+
+	// Unknown tokens are inactive
+	body = introspect("unknown")
+	if !reflect.DeepEqual(body, map[string]interface{}{"active": false}) {
+		t.Fatalf("expected an inactive token: %#v", body)
+	}
+
+	// Revoked tokens are inactive
+	if !batch {
+		req := logical.TestRequest(t, logical.UpdateOperation, "revoke")
+		req.Data = map[string]interface{}{
+			"token": token,
+		}
+		if _, err := ts.HandleRequest(namespace.RootContext(nil), req); err != nil {
+			t.Fatal(err)
+		}
+		body = introspect(token)
+		if body["active"] != false {
+			t.Fatalf("expected an inactive token: %#v", body)
+		}
+	}
+}
+
 func testTokenStoreHandleRequestLookup(t *testing.T, batch, periodic bool) {
 	c, _, root := TestCoreUnsealed(t)
 	ts := c.tokenStore
@@ -4,6 +4,9 @@
 package vault
 
 import (
+	"context"
+	"strings"
+
 	"github.com/openbao/openbao/helper/namespace"
 )
 
@@ -26,3 +29,46 @@ func (ts *TokenStore) parentView(ns *namespace.Namespace) *BarrierView {
 func (ts *TokenStore) rolesView(ns *namespace.Namespace) *BarrierView {
 	return ts.rolesBarrierView
 }
+
+// tokenIndexShard returns the shard of the accessor and parent indexes
+// holding the entry of the given salted ID. Spreading the entries over shards
+// avoids hot index prefixes when tokens are created in bulk.
+func tokenIndexShard(saltedID string) string {
+	if len(saltedID) < 2 {
+		return ""
+	}
+	return saltedID[len(saltedID)-2:] + "/"
+}
+
+// tokenIndexKeyID returns the salted ID of the entry of the accessor or
+// parent index stored at the given key.
+func tokenIndexKeyID(key string) string {
+	return key[strings.LastIndex(key, "/")+1:]
+}
+
+// listTokenIndex lists the keys of the entries of the accessor or parent
+// index under the given prefix, relative to the prefix. The entries written
+// by older versions are not sharded, and are listed along with the others.
+func listTokenIndex(ctx context.Context, view *BarrierView, prefix string) ([]string, error) {
+	entries, err := view.List(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry, "/") {
+			keys = append(keys, entry)
+			continue
+		}
+
+		shard, err := view.List(ctx, prefix+entry)
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range shard {
+			keys = append(keys, entry+key)
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,267 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-secure-stdlib/strutil"
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	glob "github.com/ryanuber/go-glob"
+)
+
+// ttlPolicySubPath is the sub-path of the system view used to store the TTL
+// policies.
+const ttlPolicySubPath = "config/ttl-policies/"
+
+// TTLPolicy assigns a default and a maximum TTL to the leases and login
+// tokens issued on the paths matching Path, to the entities member of any of
+// Groups. It overrides the TTLs of the mounts serving these paths, so that
+// credentials issued to people can be shorter-lived than those issued to
+// machines by the same mounts.
+type TTLPolicy struct {
+	Name string `json:"name"`
+
+	// Path is a glob matched against the request path, prefixed by the
+	// path of its namespace, such as database/creds/* or auth/oidc/login*.
+	Path string `json:"path"`
+
+	// Groups are the names or IDs of identity groups. If empty, the policy
+	// applies to any requester.
+	Groups []string `json:"groups,omitempty"`
+
+	// TTL replaces the default TTL of the mount. It is used when the backend
+	// does not set a TTL of its own, or sets the default TTL of its mount.
+	TTL time.Duration `json:"ttl,omitempty"`
+
+	// MaxTTL caps the lifetime of the leases and tokens, renewals included.
+	// It can only lower the maximum TTL of the mount.
+	MaxTTL time.Duration `json:"max_ttl,omitempty"`
+}
+
+func (p *TTLPolicy) validate() error {
+	switch {
+	case p.Path == "":
+		return errors.New("path is required")
+	case p.TTL <= 0 && p.MaxTTL <= 0:
+		return errors.New("at least one of ttl and max_ttl is required")
+	case p.TTL < 0 || p.MaxTTL < 0:
+		return errors.New("ttl and max_ttl cannot be negative")
+	case p.MaxTTL > 0 && p.TTL > p.MaxTTL:
+		return errors.New("ttl cannot be greater than max_ttl")
+	}
+	return nil
+}
+
+func (p *TTLPolicy) toMap() map[string]interface{} {
+	groups := p.Groups
+	if groups == nil {
+		groups = []string{}
+	}
+	return map[string]interface{}{
+		"name":    p.Name,
+		"path":    p.Path,
+		"groups":  groups,
+		"ttl":     int64(p.TTL.Seconds()),
+		"max_ttl": int64(p.MaxTTL.Seconds()),
+	}
+}
+
+func (c *Core) ttlPolicyView() *BarrierView {
+	return c.systemBarrierView.SubView(ttlPolicySubPath)
+}
+
+// loadTTLPolicies returns the TTL policies, loading them from storage on
+// first use after unseal.
+func (c *Core) loadTTLPolicies(ctx context.Context) (map[string]*TTLPolicy, error) {
+	c.ttlPoliciesLock.RLock()
+	policies := c.ttlPolicies
+	c.ttlPoliciesLock.RUnlock()
+	if policies != nil {
+		return policies, nil
+	}
+
+	c.ttlPoliciesLock.Lock()
+	defer c.ttlPoliciesLock.Unlock()
+	if c.ttlPolicies != nil {
+		return c.ttlPolicies, nil
+	}
+
+	view := c.ttlPolicyView()
+	names, err := view.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	policies = make(map[string]*TTLPolicy, len(names))
+	for _, name := range names {
+		entry, err := view.Get(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if entry == nil {
+			continue
+		}
+		policy := new(TTLPolicy)
+		if err := entry.DecodeJSON(policy); err != nil {
+			return nil, fmt.Errorf("failed to decode TTL policy %q: %w", name, err)
+		}
+		policies[name] = policy
+	}
+
+	c.ttlPolicies = policies
+	return policies, nil
+}
+
+// putTTLPolicy stores the given TTL policy, which must have been validated,
+// and applies it to future leases and tokens.
+func (c *Core) putTTLPolicy(ctx context.Context, policy *TTLPolicy) error {
+	entry, err := logical.StorageEntryJSON(policy.Name, policy)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.loadTTLPolicies(ctx); err != nil {
+		return err
+	}
+
+	c.ttlPoliciesLock.Lock()
+	defer c.ttlPoliciesLock.Unlock()
+	if err := c.ttlPolicyView().Put(ctx, entry); err != nil {
+		return err
+	}
+	if c.ttlPolicies == nil {
+		// The cache was reset, the policies are loaded again on next use
+		return nil
+	}
+	policies := make(map[string]*TTLPolicy, len(c.ttlPolicies)+1)
+	for name, p := range c.ttlPolicies {
+		policies[name] = p
+	}
+	policies[policy.Name] = policy
+	c.ttlPolicies = policies
+	return nil
+}
+
+func (c *Core) deleteTTLPolicy(ctx context.Context, name string) error {
+	if _, err := c.loadTTLPolicies(ctx); err != nil {
+		return err
+	}
+
+	c.ttlPoliciesLock.Lock()
+	defer c.ttlPoliciesLock.Unlock()
+	if err := c.ttlPolicyView().Delete(ctx, name); err != nil {
+		return err
+	}
+	if c.ttlPolicies == nil {
+		return nil
+	}
+	policies := make(map[string]*TTLPolicy, len(c.ttlPolicies))
+	for n, p := range c.ttlPolicies {
+		if n != name {
+			policies[n] = p
+		}
+	}
+	c.ttlPolicies = policies
+	return nil
+}
+
+// resetTTLPolicies drops the cached TTL policies, so that they are loaded
+// from storage on next use.
+func (c *Core) resetTTLPolicies() {
+	c.ttlPoliciesLock.Lock()
+	defer c.ttlPoliciesLock.Unlock()
+	c.ttlPolicies = nil
+}
+
+// ttlPolicyLimits returns the TTL and maximum TTL the TTL policies assign to
+// the given request path of ns, as requested by entityID. When several
+// policies apply, the shortest TTLs win. Zero values mean that no policy
+// sets them.
+func (c *Core) ttlPolicyLimits(ctx context.Context, ns *namespace.Namespace, reqPath, entityID string) (time.Duration, time.Duration, error) {
+	policies, err := c.loadTTLPolicies(ctx)
+	if err != nil || len(policies) == 0 {
+		return 0, 0, err
+	}
+
+	fullPath := ns.Path + reqPath
+
+	// The groups of the entity are only looked up if a policy needs them
+	var groups []string
+	groupsLoaded := false
+
+	var ttl, maxTTL time.Duration
+	for _, policy := range policies {
+		if !glob.Glob(policy.Path, fullPath) {
+			continue
+		}
+		if len(policy.Groups) > 0 {
+			if entityID == "" {
+				continue
+			}
+			if !groupsLoaded {
+				groups, err = c.ttlPolicyEntityGroups(entityID)
+				if err != nil {
+					return 0, 0, err
+				}
+				groupsLoaded = true
+			}
+			if !ttlPolicyGroupsMatch(policy.Groups, groups) {
+				continue
+			}
+		}
+
+		if policy.TTL > 0 && (ttl == 0 || policy.TTL < ttl) {
+			ttl = policy.TTL
+		}
+		if policy.MaxTTL > 0 && (maxTTL == 0 || policy.MaxTTL < maxTTL) {
+			maxTTL = policy.MaxTTL
+		}
+	}
+	if maxTTL > 0 && ttl > maxTTL {
+		ttl = maxTTL
+	}
+	return ttl, maxTTL, nil
+}
+
+// ttlPolicyEntityGroups returns the names and IDs of the groups entityID is
+// a direct or inherited member of.
+func (c *Core) ttlPolicyEntityGroups(entityID string) ([]string, error) {
+	if c.identityStore == nil {
+		return nil, nil
+	}
+	direct, inherited, err := c.identityStore.groupsByEntityID(entityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up the groups of entity %q: %w", entityID, err)
+	}
+
+	groups := make([]string, 0, 2*(len(direct)+len(inherited)))
+	for _, group := range append(direct, inherited...) {
+		groups = append(groups, group.Name, group.ID)
+	}
+	return groups, nil
+}
+
+func ttlPolicyGroupsMatch(policyGroups, groups []string) bool {
+	for _, group := range policyGroups {
+		if strutil.StrListContains(groups, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyTTLPolicy returns ttl and maxTTL, as set by a backend, overridden by
+// the TTLs of the matching TTL policies. A TTL equal to the default of the
+// mount is considered unset by the backend.
+func applyTTLPolicy(sysView logical.SystemView, ttl, maxTTL, policyTTL, policyMaxTTL time.Duration) (time.Duration, time.Duration) {
+	if policyTTL > 0 && (ttl == 0 || ttl == sysView.DefaultLeaseTTL()) {
+		ttl = policyTTL
+	}
+	if policyMaxTTL > 0 && (maxTTL == 0 || maxTTL > policyMaxTTL) {
+		maxTTL = policyMaxTTL
+	}
+	return ttl, maxTTL
+}
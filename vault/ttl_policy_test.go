@@ -0,0 +1,169 @@
+package vault
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTTLPolicy_Validation(t *testing.T) {
+	for name, policy := range map[string]*TTLPolicy{
+		"no path":       {TTL: time.Hour},
+		"no ttl":        {Path: "secret/*"},
+		"negative":      {Path: "secret/*", TTL: -time.Hour},
+		"ttl above max": {Path: "secret/*", TTL: 2 * time.Hour, MaxTTL: time.Hour},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.Error(t, policy.validate())
+		})
+	}
+	require.NoError(t, (&TTLPolicy{Path: "secret/*", MaxTTL: time.Hour}).validate())
+}
+
+func TestTTLPolicy_Limits(t *testing.T) {
+	c, _, _ := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	resp, err := c.identityStore.HandleRequest(ctx, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "entity",
+		Data: map[string]interface{}{
+			"name": "alice",
+		},
+	})
+	require.NoError(t, err)
+	entityID := resp.Data["id"].(string)
+	resp, err = c.identityStore.HandleRequest(ctx, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "group",
+		Data: map[string]interface{}{
+			"name":              "humans",
+			"member_entity_ids": []string{entityID},
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, resp.IsError())
+
+	require.NoError(t, c.putTTLPolicy(ctx, &TTLPolicy{Name: "all", Path: "database/creds/*", TTL: 4 * time.Hour, MaxTTL: 24 * time.Hour}))
+	require.NoError(t, c.putTTLPolicy(ctx, &TTLPolicy{Name: "humans", Path: "database/creds/*", Groups: []string{"humans"}, TTL: time.Hour, MaxTTL: 8 * time.Hour}))
+
+	ttl, maxTTL, err := c.ttlPolicyLimits(ctx, namespace.RootNamespace, "database/creds/app", "")
+	require.NoError(t, err)
+	require.Equal(t, 4*time.Hour, ttl)
+	require.Equal(t, 24*time.Hour, maxTTL)
+
+	// The shortest TTLs of the policies applying to the entity win
+	ttl, maxTTL, err = c.ttlPolicyLimits(ctx, namespace.RootNamespace, "database/creds/app", entityID)
+	require.NoError(t, err)
+	require.Equal(t, time.Hour, ttl)
+	require.Equal(t, 8*time.Hour, maxTTL)
+
+	ttl, maxTTL, err = c.ttlPolicyLimits(ctx, namespace.RootNamespace, "pki/issue/app", entityID)
+	require.NoError(t, err)
+	require.Zero(t, ttl)
+	require.Zero(t, maxTTL)
+
+	// Policies are loaded again from storage after a reset
+	c.resetTTLPolicies()
+	policies, err := c.loadTTLPolicies(ctx)
+	require.NoError(t, err)
+	require.Len(t, policies, 2)
+}
+
+func TestSystemBackend_TTLPolicies(t *testing.T) {
+	noop := &NoopBackend{
+		Login: []string{"login"},
+		Response: &logical.Response{
+			Auth: &logical.Auth{
+				Policies:    []string{"default"},
+				DisplayName: "alice",
+			},
+		},
+		BackendType: logical.TypeCredential,
+	}
+	c, _, root := TestCoreUnsealed(t)
+	c.credentialBackends["noop"] = func(ctx context.Context, conf *logical.BackendConfig) (logical.Backend, error) {
+		return noop, nil
+	}
+	ctx := namespace.RootContext(nil)
+
+	request := func(op logical.Operation, path string, data map[string]interface{}) (*logical.Response, error) {
+		t.Helper()
+		req := logical.TestRequest(t, op, path)
+		req.Data = data
+		req.ClientToken = root
+		return c.HandleRequest(ctx, req)
+	}
+
+	_, err := request(logical.UpdateOperation, "sys/auth/people", map[string]interface{}{"type": "noop"})
+	require.NoError(t, err)
+
+	_, err = request(logical.UpdateOperation, "sys/config/ttl-policies/leases", map[string]interface{}{
+		"path":    "secret/*",
+		"max_ttl": "10m",
+	})
+	require.NoError(t, err)
+	_, err = request(logical.UpdateOperation, "sys/config/ttl-policies/logins", map[string]interface{}{
+		"path":    "auth/people/login",
+		"ttl":     "5m",
+		"max_ttl": "15m",
+	})
+	require.NoError(t, err)
+
+	resp, err := request(logical.ReadOperation, "sys/config/ttl-policies/logins", nil)
+	require.NoError(t, err)
+	require.Equal(t, "auth/people/login", resp.Data["path"])
+	require.Equal(t, int64(300), resp.Data["ttl"])
+	require.Equal(t, int64(900), resp.Data["max_ttl"])
+
+	resp, err = request(logical.ListOperation, "sys/config/ttl-policies", nil)
+	require.NoError(t, err)
+	require.Equal(t, []string{"leases", "logins"}, resp.Data["keys"])
+
+	_, err = request(logical.UpdateOperation, "sys/config/ttl-policies/invalid", map[string]interface{}{
+		"path":    "secret/*",
+		"ttl":     "1h",
+		"max_ttl": "10m",
+	})
+	require.Error(t, err)
+
+	// The lease is capped to the maximum TTL of the policy
+	_, err = request(logical.UpdateOperation, "secret/test", map[string]interface{}{
+		"foo":   "bar",
+		"lease": "1h",
+	})
+	require.NoError(t, err)
+	resp, err = request(logical.ReadOperation, "secret/test", nil)
+	require.NoError(t, err)
+	require.Equal(t, 10*time.Minute, resp.Secret.TTL)
+
+	// The token gets the TTL of the policy, and its maximum TTL as an
+	// explicit maximum TTL
+	resp, err = c.HandleRequest(ctx, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "auth/people/login",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 5*time.Minute, resp.Auth.TTL)
+	require.Equal(t, 15*time.Minute, resp.Auth.ExplicitMaxTTL)
+
+	_, err = request(logical.DeleteOperation, "sys/config/ttl-policies/logins", nil)
+	require.NoError(t, err)
+	noop.Response = &logical.Response{
+		Auth: &logical.Auth{
+			Policies:    []string{"default"},
+			DisplayName: "alice",
+		},
+	}
+	resp, err = c.HandleRequest(ctx, &logical.Request{
+		Operation: logical.UpdateOperation,
+		Path:      "auth/people/login",
+	})
+	require.NoError(t, err)
+	require.Greater(t, resp.Auth.TTL, 15*time.Minute)
+	require.Zero(t, resp.Auth.ExplicitMaxTTL)
+}
@@ -0,0 +1,228 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/armon/go-radix"
+)
+
+// wellKnownPrefix is the path of the listeners under which the well-known
+// redirects are served.
+const wellKnownPrefix = "/.well-known/"
+
+var errWellKnownConflict = errors.New("well-known path conflicts with an existing registration")
+
+// wellKnownRedirect is a path under /.well-known/ claimed by a mount, which is
+// redirected to the given prefix of the mount.
+type wellKnownRedirect struct {
+	c *Core
+
+	// mountUUID identifies the mount which claimed the path, so that the
+	// redirect follows the mount when it is moved.
+	mountUUID string
+	prefix    string
+}
+
+// wellKnownRedirectRegistry holds the paths under /.well-known/ claimed by
+// the mounts. Registrations are not persisted: backends register their paths
+// again when they are initialized.
+type wellKnownRedirectRegistry struct {
+	lock  sync.RWMutex
+	paths *radix.Tree
+}
+
+func newWellKnownRedirectRegistry() *wellKnownRedirectRegistry {
+	return &wellKnownRedirectRegistry{
+		paths: radix.New(),
+	}
+}
+
+// TryRegister claims the given path under /.well-known/ for the mount of the
+// given UUID, redirecting it to the given prefix of the mount. Registering
+// again a path claimed by the same mount updates it. It fails if the path
+// overlaps with a path claimed by another mount.
+func (r *wellKnownRedirectRegistry) TryRegister(ctx context.Context, core *Core, mountUUID, src, dest string) error {
+	src = strings.Trim(src, "/")
+	if src == "" {
+		return errors.New("a well-known path is required")
+	}
+	if strings.Contains(src, "..") {
+		return errors.New("well-known path may not contain '..'")
+	}
+	if mountUUID == "" {
+		return errors.New("a mount is required")
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	// Refuse paths that are a prefix of, or prefixed by, a path of another
+	// mount, as requests could not be attributed to one of them.
+	conflict := false
+	checkFn := func(s string, v interface{}) bool {
+		if v.(*wellKnownRedirect).mountUUID != mountUUID && pathsOverlap(s, src) {
+			conflict = true
+			return true
+		}
+		return false
+	}
+	r.paths.WalkPath(src, checkFn)
+	if !conflict {
+		r.paths.WalkPrefix(src, checkFn)
+	}
+	if conflict {
+		return fmt.Errorf("%w: %q", errWellKnownConflict, src)
+	}
+
+	r.paths.Insert(src, &wellKnownRedirect{
+		c:         core,
+		mountUUID: mountUUID,
+		prefix:    strings.TrimPrefix(dest, "/"),
+	})
+	return nil
+}
+
+// pathsOverlap returns whether one of the paths is the other one or one of
+// its parents.
+func pathsOverlap(a, b string) bool {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	return a == b || strings.HasPrefix(b, a+"/")
+}
+
+// Find returns the registration matching the given path under /.well-known/,
+// along with the remaining part of the path.
+func (r *wellKnownRedirectRegistry) Find(path string) (*wellKnownRedirect, string) {
+	path = strings.TrimPrefix(path, "/")
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	var match string
+	var redirect *wellKnownRedirect
+	r.paths.WalkPath(path, func(s string, v interface{}) bool {
+		if pathsOverlap(s, path) {
+			match, redirect = s, v.(*wellKnownRedirect)
+		}
+		return false
+	})
+	if redirect == nil {
+		return nil, ""
+	}
+	return redirect, strings.TrimPrefix(path[len(match):], "/")
+}
+
+// Get returns the registration of the given path, if any.
+func (r *wellKnownRedirectRegistry) Get(src string) *wellKnownRedirect {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	v, ok := r.paths.Get(strings.Trim(src, "/"))
+	if !ok {
+		return nil
+	}
+	return v.(*wellKnownRedirect)
+}
+
+// List returns the registered paths, sorted.
+func (r *wellKnownRedirectRegistry) List() []string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	paths := make([]string, 0, r.paths.Len())
+	r.paths.Walk(func(s string, _ interface{}) bool {
+		paths = append(paths, s)
+		return false
+	})
+	sort.Strings(paths)
+	return paths
+}
+
+// DeregisterSource removes the registration of the given path if it was made
+// by the mount of the given UUID.
+func (r *wellKnownRedirectRegistry) DeregisterSource(mountUUID, src string) bool {
+	src = strings.Trim(src, "/")
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	v, ok := r.paths.Get(src)
+	if !ok || v.(*wellKnownRedirect).mountUUID != mountUUID {
+		return false
+	}
+	r.paths.Delete(src)
+	return true
+}
+
+// DeregisterMount removes all the registrations of the mount of the given
+// UUID.
+func (r *wellKnownRedirectRegistry) DeregisterMount(mountUUID string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var paths []string
+	r.paths.Walk(func(s string, v interface{}) bool {
+		if v.(*wellKnownRedirect).mountUUID == mountUUID {
+			paths = append(paths, s)
+		}
+		return false
+	})
+	for _, s := range paths {
+		r.paths.Delete(s)
+	}
+}
+
+// Reset removes all the registrations.
+func (r *wellKnownRedirectRegistry) Reset() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.paths = radix.New()
+}
+
+// MountPath returns the current API path of the mount which claimed the
+// path, including its namespace.
+func (w *wellKnownRedirect) MountPath() (string, error) {
+	entry := w.c.router.MatchingMountByUUID(w.mountUUID)
+	if entry == nil {
+		return "", fmt.Errorf("cannot find mount %q", w.mountUUID)
+	}
+
+	mountPath := entry.Namespace().Path
+	if entry.Table == credentialTableType {
+		mountPath += credentialRoutePrefix
+	}
+	return mountPath + entry.Path, nil
+}
+
+// Destination returns the API path to redirect the requests for the given
+// remaining part of the well-known path to.
+func (w *wellKnownRedirect) Destination(remaining string) (string, error) {
+	mountPath, err := w.MountPath()
+	if err != nil {
+		return "", err
+	}
+
+	dest := "/v1/" + mountPath + w.prefix
+	if remaining != "" {
+		dest = strings.TrimSuffix(dest, "/") + "/" + remaining
+	}
+	return dest, nil
+}
+
+// WellKnownRedirectDestination returns the API path to redirect the requests
+// for the given path under /.well-known/ to, or an empty string if no mount
+// claimed the path.
+func (c *Core) WellKnownRedirectDestination(path string) (string, error) {
+	redirect, remaining := c.wellKnownRedirects.Find(strings.TrimPrefix(path, wellKnownPrefix))
+	if redirect == nil {
+		return "", nil
+	}
+	return redirect.Destination(remaining)
+}
@@ -0,0 +1,81 @@
+package vault
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/openbao/openbao/helper/namespace"
+	"github.com/openbao/openbao/sdk/v2/logical"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWellKnownRedirects(t *testing.T) {
+	c, _, root := TestCoreUnsealed(t)
+	ctx := namespace.RootContext(nil)
+
+	require.NoError(t, c.mount(ctx, &MountEntry{
+		Table: mountTableType,
+		Path:  "foo/",
+		Type:  "kv",
+	}))
+	require.NoError(t, c.enableCredential(ctx, &MountEntry{
+		Table: credentialTableType,
+		Path:  "bar/",
+		Type:  "noop",
+	}))
+
+	fooView := c.router.MatchingSystemView(ctx, "foo/").(logical.ExtendedSystemView)
+	barView := c.router.MatchingSystemView(ctx, "auth/bar/").(logical.ExtendedSystemView)
+
+	require.NoError(t, fooView.RequestWellKnownRedirect(ctx, "est", "est/"))
+	require.NoError(t, barView.RequestWellKnownRedirect(ctx, "/openid-configuration/", "config"))
+
+	// Registering again from the same mount updates the registration
+	require.NoError(t, fooView.RequestWellKnownRedirect(ctx, "est", "default/est/"))
+
+	// Overlapping paths of other mounts are refused
+	for _, src := range []string{"est", "est/arbitrary", "openid-configuration/keys"} {
+		err := c.wellKnownRedirects.TryRegister(ctx, c, "other-uuid", src, "")
+		require.True(t, errors.Is(err, errWellKnownConflict), "src: %s, err: %v", src, err)
+	}
+	require.NoError(t, c.wellKnownRedirects.TryRegister(ctx, c, "other-uuid", "estimate", ""))
+	require.True(t, c.wellKnownRedirects.DeregisterSource("other-uuid", "estimate"))
+
+	for path, expected := range map[string]string{
+		"/.well-known/est":                       "/v1/foo/default/est/",
+		"/.well-known/est/cacerts":               "/v1/foo/default/est/cacerts",
+		"/.well-known/openid-configuration":      "/v1/auth/bar/config",
+		"/.well-known/openid-configuration/keys": "/v1/auth/bar/config/keys",
+		"/.well-known/estimate":                  "",
+		"/.well-known/other":                     "",
+	} {
+		dest, err := c.WellKnownRedirectDestination(path)
+		require.NoError(t, err)
+		require.Equal(t, expected, dest, "path: %s", path)
+	}
+
+	// The registrations are listed under sys/well-known
+	req := logical.TestRequest(t, logical.ListOperation, "sys/well-known")
+	req.ClientToken = root
+	resp, err := c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, []string{"est", "openid-configuration"}, resp.Data["keys"])
+
+	req = logical.TestRequest(t, logical.ReadOperation, "sys/well-known/est")
+	req.ClientToken = root
+	resp, err = c.HandleRequest(ctx, req)
+	require.NoError(t, err)
+	require.Equal(t, "foo/", resp.Data["mount_path"])
+	require.Equal(t, "default/est/", resp.Data["prefix"])
+
+	// A mount may only release its own paths
+	require.False(t, fooView.DeregisterWellKnownRedirect(ctx, "openid-configuration"))
+	require.True(t, fooView.DeregisterWellKnownRedirect(ctx, "est"))
+	dest, err := c.WellKnownRedirectDestination("/.well-known/est")
+	require.NoError(t, err)
+	require.Empty(t, dest)
+
+	// The paths of a mount are released when it is removed
+	require.NoError(t, c.disableCredential(ctx, "bar/"))
+	require.Empty(t, c.wellKnownRedirects.List())
+}
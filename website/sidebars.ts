@@ -86,6 +86,7 @@ const sidebars: SidebarsConfig = {
                     listener: [
                         "configuration/listener/index",
                         "configuration/listener/unix",
+                        "configuration/listener/grpc",
                         "configuration/listener/tcp",
                     ],
                     seal: [
@@ -108,6 +109,8 @@ const sidebars: SidebarsConfig = {
                         "configuration/storage/raft",
                     ],
                 },
+                "configuration/entropy-augmentation",
+                "configuration/notification",
                 "configuration/telemetry",
                 "configuration/ui",
                 "configuration/user-lockout",
@@ -258,6 +261,7 @@ const sidebars: SidebarsConfig = {
                             Sinks: [
                                 "agent-and-proxy/autoauth/sinks/index",
                                 "agent-and-proxy/autoauth/sinks/file",
+                                "agent-and-proxy/autoauth/sinks/dpapi",
                             ],
                         },
                     ],
@@ -293,6 +297,7 @@ const sidebars: SidebarsConfig = {
                         },
                         "agent-and-proxy/agent/generate-config/index",
                         "agent-and-proxy/agent/process-supervisor",
+                        "agent-and-proxy/agent/pki",
                         "agent-and-proxy/agent/template",
                         "agent-and-proxy/agent/winsvc",
                         "agent-and-proxy/agent/versions",
@@ -322,8 +327,10 @@ const sidebars: SidebarsConfig = {
                         "secrets/kv/kv-v2",
                     ],
                 },
+                "secrets/keymgmt",
                 "secrets/kubernetes",
                 "secrets/ldap",
+                "secrets/pairing",
                 {
                     "PKI (Certificates)": [
                         "secrets/pki/index",
@@ -336,6 +343,7 @@ const sidebars: SidebarsConfig = {
                     ],
                 },
                 "secrets/rabbitmq",
+                "secrets/scm",
                 {
                     SSH: [
                         "secrets/ssh/index",
@@ -377,10 +385,12 @@ const sidebars: SidebarsConfig = {
                 "auth/kerberos",
                 "auth/kubernetes",
                 "auth/ldap",
+                "auth/peercred",
                 {
                     "Login MFA": ["auth/login-mfa/index", "auth/login-mfa/faq"],
                 },
                 "auth/radius",
+                "auth/spiffe",
                 "auth/cert",
                 "auth/token",
                 "auth/userpass",
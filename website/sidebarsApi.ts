@@ -53,10 +53,13 @@ const sidebars: SidebarsConfig = {
             "secret/kv/kv-v2",
           ],
         },
+        "secret/keymgmt",
         "secret/kubernetes",
         "secret/ldap",
+        "secret/pairing",
         "secret/pki",
         "secret/rabbitmq",
+        "secret/scm",
         "secret/ssh",
         "secret/totp",
         "secret/transit",
@@ -82,15 +85,26 @@ const sidebars: SidebarsConfig = {
         "system/capabilities-accessor",
         "system/capabilities-self",
         "system/config-auditing",
+        "system/config-backup-encryption",
+        "system/config-cache",
         "system/config-cors",
+        "system/config-export",
+        "system/config-reload",
+        "system/config-schema",
         "system/config-state",
+        "system/config-ttl-policies",
         "system/config-ui",
+        "system/config-ui-banners",
         "system/decode-token",
+        "system/deleted-mounts",
+        "system/deprecations",
+        "system/federation",
         "system/generate-recovery-token",
         "system/generate-root",
         "system/health",
         "system/host-info",
         "system/in-flight-req",
+        "system/import",
         "system/init",
         "system/internal-counters",
         {
@@ -101,6 +115,7 @@ const sidebars: SidebarsConfig = {
         },
         "system/internal-specs-openapi",
         "system/internal-ui-feature",
+        "system/internal-ui-dashboard",
         "system/internal-ui-mounts",
         "system/internal-ui-namespaces",
         "system/internal-ui-resultant-acl",
@@ -114,11 +129,14 @@ const sidebars: SidebarsConfig = {
         "system/monitor",
         "system/mounts",
         "system/namespaces",
+        "system/network-policy",
+        "system/payload-encryption",
         "system/plugins-reload-backend",
         "system/plugins-catalog",
         "system/policy",
         "system/policies",
         "system/policies-password",
+        "system/post-unseal-status",
         "system/pprof",
         "system/quotas-config",
         "system/rate-limit-quotas",
@@ -126,8 +144,10 @@ const sidebars: SidebarsConfig = {
         "system/rekey",
         "system/rekey-recovery-key",
         "system/remount",
+        "system/restricted-crypto",
         "system/rotate",
         "system/rotate-config",
+        "system/rotate-roots",
         "system/seal",
         "system/seal-status",
         "system/step-down",
@@ -142,6 +162,7 @@ const sidebars: SidebarsConfig = {
         "system/unseal",
         "system/user-lockout",
         "system/version-history",
+        "system/well-known",
         "system/wrapping-lookup",
         "system/wrapping-rewrap",
         "system/wrapping-unwrap",